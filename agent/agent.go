@@ -1626,7 +1626,10 @@ func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(co
 			closing := a.closing
 			if !closing {
 				a.network = network
-				a.statsReporter = newStatsReporter(a.logger, network, a, a.statsReportInterval)
+				// The HTTP API is optional, so test clients need not
+				// implement it.
+				fallback, _ := a.client.(statsPoster)
+				a.statsReporter = newStatsReporter(a.logger, network, a, fallback, a.statsReportInterval)
 			}
 			a.closeMutex.Unlock()
 			if closing {
@@ -3,6 +3,7 @@ package agent_test
 import (
 	"bufio"
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -3651,6 +3652,55 @@ func TestAgent_WriteVSCodeConfigs(t *testing.T) {
 	}, testutil.WaitShort, testutil.IntervalFast)
 }
 
+func TestAgent_TopProcesses(t *testing.T) {
+	t.Parallel()
+
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+	t.Run("Memory", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := conn.TopProcesses(ctx, codersdk.WorkspaceAgentTopProcessesRequest{
+			SortBy: codersdk.WorkspaceAgentProcessSortByMemory,
+			Limit:  3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentProcessSortByMemory, resp.SortBy)
+		// At the very least the test process itself is running.
+		require.NotEmpty(t, resp.Processes)
+		require.LessOrEqual(t, len(resp.Processes), 3)
+		require.True(t, slices.IsSortedFunc(resp.Processes, func(a, b codersdk.WorkspaceAgentProcess) int {
+			return cmp.Compare(b.MemoryBytes, a.MemoryBytes)
+		}), "processes should be sorted by memory descending")
+	})
+
+	t.Run("DefaultCPU", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := conn.TopProcesses(ctx, codersdk.WorkspaceAgentTopProcessesRequest{})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentProcessSortByCPU, resp.SortBy)
+		require.LessOrEqual(t, len(resp.Processes), codersdk.WorkspaceAgentTopProcessesDefaultLimit)
+		require.True(t, slices.IsSortedFunc(resp.Processes, func(a, b codersdk.WorkspaceAgentProcess) int {
+			return cmp.Compare(b.CPUPercent, a.CPUPercent)
+		}), "processes should be sorted by CPU descending")
+	})
+
+	t.Run("InvalidSortBy", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := conn.TopProcesses(ctx, codersdk.WorkspaceAgentTopProcessesRequest{
+			SortBy: "disk",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+}
+
 func TestAgent_DebugServer(t *testing.T) {
 	t.Parallel()
 
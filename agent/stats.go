@@ -11,6 +11,7 @@ import (
 
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
 
 const maxConns = 2048
@@ -27,10 +28,21 @@ type statsDest interface {
 	UpdateStats(ctx context.Context, req *proto.UpdateStatsRequest) (*proto.UpdateStatsResponse, error)
 }
 
+// statsPoster reports stats over HTTP, for when the Agent API connection
+// breaks, e.g. because a proxy doesn't allow long-lived connections.
+type statsPoster interface {
+	PostStats(ctx context.Context, req agentsdk.PostStatsRequest) (agentsdk.StatsResponse, error)
+}
+
+// maxBufferedStats is the number of stats kept while they can't be reported.
+// It matches the number of stats coderd accepts at once.
+const maxBufferedStats = 100
+
 // statsReporter is a subcomponent of the agent that handles registering the stats callback on the
 // networkStatsSource (tailnet.Conn in prod), handling the callback, calling back to the
 // statsCollector (agent in prod) to collect additional stats, then sending the update to the
-// statsDest (agent API in prod)
+// statsDest (agent API in prod). Stats that fail to be sent are reported to the statsPoster
+// (the HTTP API in prod) instead, if any.
 type statsReporter struct {
 	*sync.Cond
 	networkStats map[netlogtype.Connection]netlogtype.Counts
@@ -39,18 +51,25 @@ type statsReporter struct {
 
 	source    networkStatsSource
 	collector statsCollector
+	fallback  statsPoster
 	logger    slog.Logger
+
+	// buffered are the stats that failed to be reported to both the statsDest
+	// and the statsPoster. It is only used by reportLoop.
+	buffered []agentsdk.TimestampedStats
 }
 
 // DefaultStatsReportInterval matches coderd.Options.AgentStatsRefreshInterval.
 const DefaultStatsReportInterval = 5 * time.Minute
 
-func newStatsReporter(logger slog.Logger, source networkStatsSource, collector statsCollector, interval time.Duration) *statsReporter {
+// newStatsReporter creates a statsReporter. fallback may be nil.
+func newStatsReporter(logger slog.Logger, source networkStatsSource, collector statsCollector, fallback statsPoster, interval time.Duration) *statsReporter {
 	s := &statsReporter{
 		Cond:         sync.NewCond(&sync.Mutex{}),
 		logger:       logger,
 		source:       source,
 		collector:    collector,
+		fallback:     fallback,
 		lastInterval: interval,
 	}
 	// Install the callback immediately so traffic is tracked before
@@ -133,10 +152,22 @@ func (s *statsReporter) reportLocked(
 	s.L.Unlock()
 	defer s.L.Lock()
 	stats := s.collector.Collect(ctx, networkStats)
+	collectedAt := time.Now()
 	resp, err := dest.UpdateStats(ctx, &proto.UpdateStatsRequest{Stats: stats})
 	if err != nil {
+		// The connection is reestablished by the caller, but the stats would
+		// be lost meanwhile.
+		s.post(ctx, agentsdk.TimestampedStats{
+			CollectedAt: collectedAt,
+			Stats:       agentsdk.StatsFromProto(stats),
+		})
 		return err
 	}
+	// Stats that could not be reported earlier are retried once the server
+	// is reachable again.
+	if len(s.buffered) > 0 {
+		s.post(ctx)
+	}
 	interval := resp.GetReportInterval().AsDuration()
 	if interval != s.lastInterval {
 		s.logger.Info(ctx, "new stats report interval", slog.F("interval", interval))
@@ -145,3 +176,29 @@ func (s *statsReporter) reportLocked(
 	}
 	return nil
 }
+
+// post reports the stats, along with the stats that failed to be reported
+// before, to the fallback. Stats that fail to be reported are kept until the
+// next attempt, up to maxBufferedStats.
+func (s *statsReporter) post(ctx context.Context, stats ...agentsdk.TimestampedStats) {
+	if s.fallback == nil {
+		return
+	}
+	s.buffered = append(s.buffered, stats...)
+	if n := len(s.buffered) - maxBufferedStats; n > 0 {
+		s.buffered = s.buffered[n:]
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	resp, err := s.fallback.PostStats(ctx, agentsdk.PostStatsRequest{Stats: s.buffered})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to report stats over HTTP, retrying with the next report",
+			slog.F("count", len(s.buffered)), slog.Error(err))
+		return
+	}
+	if resp.Dropped > 0 {
+		s.logger.Warn(ctx, "server dropped stale stats", slog.F("count", resp.Dropped))
+	}
+	s.buffered = nil
+}
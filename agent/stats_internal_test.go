@@ -8,11 +8,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"tailscale.com/types/ipproto"
 	"tailscale.com/types/netlogtype"
 
 	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -23,7 +25,7 @@ func TestStatsReporter(t *testing.T) {
 	fSource := newFakeNetworkStatsSource(ctx, t)
 	fCollector := newFakeCollector(t)
 	fDest := newFakeStatsDest()
-	uut := newStatsReporter(logger, fSource, fCollector, DefaultStatsReportInterval)
+	uut := newStatsReporter(logger, fSource, fCollector, nil, DefaultStatsReportInterval)
 
 	_ = testutil.TryReceive(ctx, t, fSource.period) // drain construction-time install
 
@@ -135,6 +137,84 @@ func TestStatsReporter(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStatsReporterFallback(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := testutil.Logger(t)
+	fSource := newFakeNetworkStatsSource(ctx, t)
+	fCollector := newFakeCollector(t)
+	fDest := newFakeStatsDest()
+	fPoster := newFakeStatsPoster()
+	uut := newStatsReporter(logger, fSource, fCollector, fPoster, DefaultStatsReportInterval)
+
+	_ = testutil.TryReceive(ctx, t, fSource.period) // drain construction-time install
+
+	netStats := map[netlogtype.Connection]netlogtype.Counts{
+		{
+			Proto: ipproto.TCP,
+			Src:   netip.MustParseAddrPort("192.168.1.33:4887"),
+			Dst:   netip.MustParseAddrPort("192.168.2.99:9999"),
+		}: {
+			TxPackets: 22,
+			TxBytes:   23,
+			RxPackets: 24,
+			RxBytes:   25,
+		},
+	}
+
+	loopErr := make(chan error, 1)
+	go func() {
+		loopErr <- uut.reportLoop(ctx, fDest)
+	}()
+	_ = testutil.TryReceive(ctx, t, fDest.reqs)
+	testutil.RequireSend(ctx, t, fDest.resps, &proto.UpdateStatsResponse{ReportInterval: durationpb.New(DefaultStatsReportInterval)})
+
+	// the connection breaks while reporting
+	fSource.callback(time.Now(), time.Now(), netStats, nil)
+	_ = testutil.TryReceive(ctx, t, fCollector.calls)
+	lost := &proto.Stats{SessionCountSsh: 3}
+	testutil.RequireSend(ctx, t, fCollector.stats, lost)
+	_ = testutil.TryReceive(ctx, t, fDest.reqs)
+	testutil.RequireSend(ctx, t, fDest.errs, xerrors.New("connection lost"))
+
+	// the stats are posted instead, which fails too
+	post := testutil.TryReceive(ctx, t, fPoster.reqs)
+	require.Len(t, post.Stats, 1)
+	require.NotZero(t, post.Stats[0].CollectedAt)
+	require.Equal(t, agentsdk.StatsFromProto(lost), post.Stats[0].Stats)
+	testutil.RequireSend(ctx, t, fPoster.errs, xerrors.New("bad gateway"))
+
+	err := testutil.TryReceive(ctx, t, loopErr)
+	require.ErrorContains(t, err, "connection lost")
+
+	// the connection is reestablished
+	loopCtx, loopCancel := context.WithCancel(ctx)
+	go func() {
+		loopErr <- uut.reportLoop(loopCtx, fDest)
+	}()
+	_ = testutil.TryReceive(ctx, t, fDest.reqs)
+	testutil.RequireSend(ctx, t, fDest.resps, &proto.UpdateStatsResponse{ReportInterval: durationpb.New(DefaultStatsReportInterval)})
+
+	fSource.callback(time.Now(), time.Now(), netStats, nil)
+	_ = testutil.TryReceive(ctx, t, fCollector.calls)
+	stats := &proto.Stats{SessionCountSsh: 4}
+	testutil.RequireSend(ctx, t, fCollector.stats, stats)
+	update := testutil.TryReceive(ctx, t, fDest.reqs)
+	require.Equal(t, stats, update.Stats)
+	testutil.RequireSend(ctx, t, fDest.resps, &proto.UpdateStatsResponse{ReportInterval: durationpb.New(DefaultStatsReportInterval)})
+
+	// the stats that were lost are posted again
+	post = testutil.TryReceive(ctx, t, fPoster.reqs)
+	require.Len(t, post.Stats, 1)
+	require.Equal(t, agentsdk.StatsFromProto(lost), post.Stats[0].Stats)
+	testutil.RequireSend(ctx, t, fPoster.errs, nil)
+
+	loopCancel()
+	err = testutil.TryReceive(ctx, t, loopErr)
+	require.NoError(t, err)
+	require.Empty(t, uut.buffered)
+}
+
 type fakeNetworkStatsSource struct {
 	sync.Mutex
 	ctx      context.Context
@@ -198,6 +278,7 @@ func newFakeCollector(t testing.TB) *fakeCollector {
 type fakeStatsDest struct {
 	reqs  chan *proto.UpdateStatsRequest
 	resps chan *proto.UpdateStatsResponse
+	errs  chan error
 }
 
 func (f *fakeStatsDest) UpdateStats(ctx context.Context, req *proto.UpdateStatsRequest) (*proto.UpdateStatsResponse, error) {
@@ -212,6 +293,8 @@ func (f *fakeStatsDest) UpdateStats(ctx context.Context, req *proto.UpdateStatsR
 		return nil, ctx.Err()
 	case resp := <-f.resps:
 		return resp, nil
+	case err := <-f.errs:
+		return nil, err
 	}
 }
 
@@ -219,5 +302,33 @@ func newFakeStatsDest() *fakeStatsDest {
 	return &fakeStatsDest{
 		reqs:  make(chan *proto.UpdateStatsRequest),
 		resps: make(chan *proto.UpdateStatsResponse),
+		errs:  make(chan error),
+	}
+}
+
+type fakeStatsPoster struct {
+	reqs chan agentsdk.PostStatsRequest
+	errs chan error
+}
+
+func (f *fakeStatsPoster) PostStats(ctx context.Context, req agentsdk.PostStatsRequest) (agentsdk.StatsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return agentsdk.StatsResponse{}, ctx.Err()
+	case f.reqs <- req:
+		// OK
+	}
+	select {
+	case <-ctx.Done():
+		return agentsdk.StatsResponse{}, ctx.Err()
+	case err := <-f.errs:
+		return agentsdk.StatsResponse{}, err
+	}
+}
+
+func newFakeStatsPoster() *fakeStatsPoster {
+	return &fakeStatsPoster{
+		reqs: make(chan agentsdk.PostStatsRequest),
+		errs: make(chan error),
 	}
 }
@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// topProcessesSampleInterval is the time between the two CPU time samples
+// used to compute the CPU usage of each process.
+const topProcessesSampleInterval = 500 * time.Millisecond
+
+func (a *agent) handleTopProcesses(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	sortBy := httpapi.ParseCustom(p, vals, codersdk.WorkspaceAgentProcessSortByCPU, "sort_by", httpapi.ParseEnum[codersdk.WorkspaceAgentProcessSortBy])
+	limit := p.PositiveInt32(vals, codersdk.WorkspaceAgentTopProcessesDefaultLimit, "limit")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if limit == 0 {
+		limit = codersdk.WorkspaceAgentTopProcessesDefaultLimit
+	}
+	limit = min(limit, codersdk.WorkspaceAgentTopProcessesMaxLimit)
+
+	processes, err := topProcesses(ctx, sortBy, int(limit))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to list processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentTopProcessesResponse{
+		SortBy:    sortBy,
+		Processes: processes,
+	})
+}
+
+type processSample struct {
+	proc     *process.Process
+	cpuTime  float64
+	rss      uint64
+	cpuUsage float64
+}
+
+// topProcesses samples the CPU time of every process twice and returns the
+// processes using the most of the requested resource. Processes that exit or
+// can't be inspected while sampling are skipped.
+func topProcesses(ctx context.Context, sortBy codersdk.WorkspaceAgentProcessSortBy, limit int) ([]codersdk.WorkspaceAgentProcess, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("list processes: %w", err)
+	}
+
+	samples := make([]processSample, 0, len(procs))
+	for _, proc := range procs {
+		times, err := proc.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, processSample{
+			proc:    proc,
+			cpuTime: times.User + times.System,
+		})
+	}
+
+	start := time.Now()
+	t := time.NewTimer(topProcessesSampleInterval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.C:
+	}
+	elapsed := time.Since(start).Seconds()
+
+	measured := samples[:0]
+	for _, s := range samples {
+		times, err := s.proc.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		memInfo, err := s.proc.MemoryInfoWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		s.cpuUsage = max(times.User+times.System-s.cpuTime, 0) / elapsed * 100
+		s.rss = memInfo.RSS
+		measured = append(measured, s)
+	}
+
+	slices.SortFunc(measured, func(a, b processSample) int {
+		if sortBy == codersdk.WorkspaceAgentProcessSortByMemory {
+			return cmp.Compare(b.rss, a.rss)
+		}
+		return cmp.Compare(b.cpuUsage, a.cpuUsage)
+	})
+	if len(measured) > limit {
+		measured = measured[:limit]
+	}
+
+	// Total memory is only used to compute percentages, so a failure here
+	// shouldn't fail the whole request.
+	var totalMemory uint64
+	if vm, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		totalMemory = vm.Total
+	}
+
+	// Names and usernames are only looked up for the processes we return,
+	// since resolving them is comparatively expensive.
+	result := make([]codersdk.WorkspaceAgentProcess, 0, len(measured))
+	for _, s := range measured {
+		name, err := s.proc.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		username, _ := s.proc.UsernameWithContext(ctx)
+		var memoryPercent float32
+		if totalMemory > 0 {
+			memoryPercent = float32(float64(s.rss) / float64(totalMemory) * 100)
+		}
+		result = append(result, codersdk.WorkspaceAgentProcess{
+			PID:           s.proc.Pid,
+			Name:          name,
+			Username:      username,
+			CPUPercent:    s.cpuUsage,
+			MemoryBytes:   s.rss,
+			MemoryPercent: memoryPercent,
+		})
+	}
+	return result, nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"net/http"
 	"net/url"
@@ -28,17 +29,22 @@ import (
 	"cdr.dev/slog/v3/sloggers/sloghuman"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/scaletest/agentconn"
+	"github.com/coder/coder/v2/scaletest/annotate"
 	"github.com/coder/coder/v2/scaletest/autostart"
 	"github.com/coder/coder/v2/scaletest/createusers"
 	"github.com/coder/coder/v2/scaletest/createworkspaces"
 	"github.com/coder/coder/v2/scaletest/dashboard"
 	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadprofile"
 	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/plugin"
 	"github.com/coder/coder/v2/scaletest/prebuilds"
+	"github.com/coder/coder/v2/scaletest/quota"
 	"github.com/coder/coder/v2/scaletest/reconnectingpty"
 	"github.com/coder/coder/v2/scaletest/workspacebuild"
 	"github.com/coder/coder/v2/scaletest/workspacetraffic"
@@ -59,13 +65,26 @@ func (r *RootCmd) scaletestCmd() *serpent.Command {
 		},
 		Children: []*serpent.Command{
 			r.scaletestCleanup(),
+			r.scaletestCompare(),
+			r.scaletestScenario(),
+			r.scaletestRecordLoadProfile(),
 			r.scaletestDashboard(),
+			r.scaletestAPIReplay(),
+			r.scaletestAuditLog(),
 			r.scaletestDynamicParameters(),
 			r.scaletestCreateWorkspaces(),
 			r.scaletestWorkspaceUpdates(),
 			r.scaletestWorkspaceTraffic(),
+			r.scaletestWorkspacePortForward(),
+			r.scaletestSSHCLI(),
+			r.scaletestWorkspaceChurn(),
+			r.scaletestDevcontainers(),
+			r.scaletestTemplateChurn(),
+			r.scaletestCoordinationChurn(),
+			r.scaletestAppHealth(),
 			r.scaletestAutostart(),
 			r.scaletestNotifications(),
+			r.scaletestNotificationDelivery(),
 			r.scaletestTaskStatus(),
 			r.scaletestSMTP(),
 			r.scaletestPrebuilds(),
@@ -74,6 +93,38 @@ func (r *RootCmd) scaletestCmd() *serpent.Command {
 			r.scaletestLLMMock(),
 		},
 	}
+	for _, w := range plugin.Workloads() {
+		cmd.Children = append(cmd.Children, r.scaletestPluginWorkload(w))
+	}
+
+	var prefix string
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "prefix",
+			Env:         "CODER_SCALETEST_PREFIX",
+			Description: "Prefix of the names of the users, workspaces and tokens that scaletests create, and that cleanup deletes. Scaletests with different prefixes can run against the same deployment concurrently, as long as neither prefix is a prefix of the other.",
+			Default:     loadtestutil.ScaleTestPrefix,
+			Value:       serpent.StringOf(&prefix),
+		},
+	}
+	// Make every scaletest command name and find its resources by the prefix.
+	prefixMiddleware := func(next serpent.HandlerFunc) serpent.HandlerFunc {
+		return func(inv *serpent.Invocation) error {
+			if err := loadtestutil.ValidatePrefix(prefix); err != nil {
+				return xerrors.Errorf("invalid --prefix: %w", err)
+			}
+			return next(inv.WithContext(loadtestutil.WithPrefix(inv.Context(), prefix)))
+		}
+	}
+	for _, child := range cmd.Children {
+		child.Walk(func(c *serpent.Command) {
+			if c.Middleware == nil {
+				c.Middleware = prefixMiddleware
+			} else {
+				c.Middleware = serpent.Chain(c.Middleware, prefixMiddleware)
+			}
+		})
+	}
 
 	return cmd
 }
@@ -149,48 +200,123 @@ func (s *scaletestTracingFlags) provider(ctx context.Context) (trace.TracerProvi
 type concurrencyFlags struct {
 	cleanup     bool
 	concurrency int64
+	rateLimit   float64
+	// targetP95 and targetInterval configure a latency target, for run
+	// strategies only.
+	targetP95      time.Duration
+	targetInterval time.Duration
+	// strategy is the name of an execution strategy registered by a plugin,
+	// for run strategies only.
+	strategy string
 }
 
 func (c *concurrencyFlags) attach(opts *serpent.OptionSet) {
 	concurrencyLong, concurrencyEnv, concurrencyDescription := "concurrency", "CODER_SCALETEST_CONCURRENCY", "Number of concurrent jobs to run. 0 means unlimited."
+	rateLimitLong, rateLimitEnv, rateLimitDescription := "rate-limit", "CODER_SCALETEST_RATE_LIMIT", "Maximum number of jobs to start per second. 0 means unlimited."
 	if c.cleanup {
 		concurrencyLong, concurrencyEnv, concurrencyDescription = "cleanup-"+concurrencyLong, "CODER_SCALETEST_CLEANUP_CONCURRENCY", strings.ReplaceAll(concurrencyDescription, "jobs", "cleanup jobs")
+		rateLimitLong, rateLimitEnv, rateLimitDescription = "cleanup-"+rateLimitLong, "CODER_SCALETEST_CLEANUP_RATE_LIMIT", strings.ReplaceAll(rateLimitDescription, "jobs", "cleanup jobs")
+	}
+
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        concurrencyLong,
+			Env:         concurrencyEnv,
+			Description: concurrencyDescription,
+			Default:     "1",
+			Value:       serpent.Int64Of(&c.concurrency),
+		},
+		serpent.Option{
+			Flag:        rateLimitLong,
+			Env:         rateLimitEnv,
+			Description: rateLimitDescription,
+			Default:     "0",
+			Value:       serpent.Float64Of(&c.rateLimit),
+		},
+	)
+	if c.cleanup {
+		return
 	}
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "target-p95-latency",
+			Env:         "CODER_SCALETEST_TARGET_P95_LATENCY",
+			Description: "Adjust the number of concurrent jobs automatically to find the maximum load at which the 95th percentile job duration stays under this target, and report it as the capacity in the results. --concurrency caps the number of concurrent jobs, so raise it above its default of 1 or set it to 0 for no cap. 0 disables the latency target.",
+			Default:     "0s",
+			Value:       serpent.DurationOf(&c.targetP95),
+		},
+		serpent.Option{
+			Flag:        "target-latency-interval",
+			Env:         "CODER_SCALETEST_TARGET_LATENCY_INTERVAL",
+			Description: "Interval at which the number of concurrent jobs is adjusted when --target-p95-latency is set. Jobs should finish well within the interval.",
+			Default:     "30s",
+			Value:       serpent.DurationOf(&c.targetInterval),
+		},
+	)
 
+	// The flag only exists in builds that include strategy plugins.
+	registered := plugin.Strategies()
+	if len(registered) == 0 {
+		return
+	}
+	names := make([]string, 0, len(registered))
+	descriptions := make([]string, 0, len(registered))
+	for _, s := range registered {
+		names = append(names, s.Name)
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", s.Name, s.Description))
+	}
 	*opts = append(*opts, serpent.Option{
-		Flag:        concurrencyLong,
-		Env:         concurrencyEnv,
-		Description: concurrencyDescription,
-		Default:     "1",
-		Value:       serpent.Int64Of(&c.concurrency),
+		Flag:        "strategy",
+		Env:         "CODER_SCALETEST_STRATEGY",
+		Description: "Run jobs with an execution strategy contributed by a plugin instead of the built-in strategy picked by --concurrency and --target-p95-latency. Available strategies: " + strings.Join(descriptions, ", ") + ".",
+		Value:       serpent.EnumOf(&c.strategy, names...),
 	})
 }
 
 func (c *concurrencyFlags) toStrategy() harness.ExecutionStrategy {
-	switch c.concurrency {
-	case 1:
-		return harness.LinearExecutionStrategy{}
-	case 0:
-		return harness.ConcurrentExecutionStrategy{}
+	var strategy harness.ExecutionStrategy
+	pluginStrategy, isPlugin := plugin.LookupStrategy(c.strategy)
+	switch {
+	case isPlugin:
+		strategy = pluginStrategy.New(int(c.concurrency))
+	case c.targetP95 > 0:
+		strategy = harness.LatencyTargetExecutionStrategy{
+			Target:   c.targetP95,
+			Interval: c.targetInterval,
+			MaxLimit: int(c.concurrency),
+		}
+	case c.concurrency == 1:
+		strategy = harness.LinearExecutionStrategy{}
+	case c.concurrency == 0:
+		strategy = harness.ConcurrentExecutionStrategy{}
 	default:
-		return harness.ParallelExecutionStrategy{
+		strategy = harness.ParallelExecutionStrategy{
 			Limit: int(c.concurrency),
 		}
 	}
+
+	var interval time.Duration
+	if c.rateLimit > 0 {
+		interval = time.Duration(float64(time.Second) / c.rateLimit)
+	}
+	return harness.Compose(strategy, harness.WithRateLimit(interval))
 }
 
 type timeoutFlags struct {
 	cleanup       bool
 	timeout       time.Duration
 	timeoutPerJob time.Duration
+	abandonAfter  time.Duration
 }
 
 func (t *timeoutFlags) attach(opts *serpent.OptionSet) {
-	timeoutLong, timeoutEnv, timeoutDescription := "timeout", "CODER_SCALETEST_TIMEOUT", "Timeout for the entire test run. 0 means unlimited."
+	timeoutLong, timeoutEnv, timeoutDescription := "timeout", "CODER_SCALETEST_TIMEOUT", "Timeout for the entire test run, independent of the per-job timeout. Jobs still in progress when it's reached are reported as killed by the test timeout. 0 means unlimited."
 	jobTimeoutLong, jobTimeoutEnv, jobTimeoutDescription := "job-timeout", "CODER_SCALETEST_JOB_TIMEOUT", "Timeout per job. Jobs may take longer to complete under higher concurrency limits."
+	abandonLong, abandonEnv, abandonDescription := "job-abandon-after", "CODER_SCALETEST_JOB_ABANDON_AFTER", "How long to wait for a job to stop after its timeout before abandoning it and marking it as timed out. 0 waits indefinitely."
 	if t.cleanup {
 		timeoutLong, timeoutEnv, timeoutDescription = "cleanup-"+timeoutLong, "CODER_SCALETEST_CLEANUP_TIMEOUT", strings.ReplaceAll(timeoutDescription, "test", "cleanup")
 		jobTimeoutLong, jobTimeoutEnv, jobTimeoutDescription = "cleanup-"+jobTimeoutLong, "CODER_SCALETEST_CLEANUP_JOB_TIMEOUT", strings.ReplaceAll(jobTimeoutDescription, "jobs", "cleanup jobs")
+		abandonLong, abandonEnv, abandonDescription = "cleanup-"+abandonLong, "CODER_SCALETEST_CLEANUP_JOB_ABANDON_AFTER", strings.ReplaceAll(abandonDescription, "job", "cleanup job")
 	}
 
 	*opts = append(
@@ -209,22 +335,26 @@ func (t *timeoutFlags) attach(opts *serpent.OptionSet) {
 			Default:     "5m",
 			Value:       serpent.DurationOf(&t.timeoutPerJob),
 		},
+		serpent.Option{
+			Flag:        abandonLong,
+			Env:         abandonEnv,
+			Description: abandonDescription,
+			Default:     "0s",
+			Value:       serpent.DurationOf(&t.abandonAfter),
+		},
 	)
 }
 
 func (t *timeoutFlags) wrapStrategy(strategy harness.ExecutionStrategy) harness.ExecutionStrategy {
-	if t.timeoutPerJob > 0 {
-		return harness.TimeoutExecutionStrategyWrapper{
-			Timeout: t.timeoutPerJob,
-			Inner:   strategy,
-		}
-	}
-	return strategy
+	return harness.Compose(strategy, harness.WithTimeout(t.timeoutPerJob, t.abandonAfter))
 }
 
+// toContext applies the overall timeout to ctx. It's enforced independently of
+// the per-job timeout of wrapStrategy, and jobs still in progress when it's
+// reached are reported as killed by the test timeout rather than timed out.
 func (t *timeoutFlags) toContext(ctx context.Context) (context.Context, context.CancelFunc) {
 	if t.timeout > 0 {
-		return context.WithTimeout(ctx, t.timeout)
+		return context.WithTimeoutCause(ctx, t.timeout, harness.ErrPhaseTimeout)
 	}
 
 	return context.WithCancel(ctx)
@@ -233,6 +363,7 @@ func (t *timeoutFlags) toContext(ctx context.Context) (context.Context, context.
 type scaletestStrategyFlags struct {
 	concurrencyFlags
 	timeoutFlags
+	dryRun bool
 }
 
 func newScaletestCleanupStrategy() *scaletestStrategyFlags {
@@ -251,11 +382,47 @@ func (s *scaletestStrategyFlags) toStrategy() harness.ExecutionStrategy {
 	return s.timeoutFlags.wrapStrategy(s.concurrencyFlags.toStrategy())
 }
 
+// attachDryRun attaches the --cleanup-dry-run flag. Commands that attach it must
+// clean up using runCleanup.
+func (s *scaletestStrategyFlags) attachDryRun(opts *serpent.OptionSet) {
+	*opts = append(*opts, serpent.Option{
+		Flag:        "cleanup-dry-run",
+		Env:         "CODER_SCALETEST_CLEANUP_DRY_RUN",
+		Description: "List the resources that cleanup would delete without deleting them.",
+		Value:       serpent.BoolOf(&s.dryRun),
+	})
+}
+
+// runCleanup cleans up the test harness, or only lists the resources that
+// would be deleted if --cleanup-dry-run is set.
+func (s *scaletestStrategyFlags) runCleanup(ctx context.Context, th *harness.TestHarness, w io.Writer) error {
+	cleanupCtx, cleanupCancel := s.toContext(ctx)
+	defer cleanupCancel()
+
+	if s.dryRun {
+		_, _ = fmt.Fprintln(w, "\nCleanup dry run, the following resources would be deleted:")
+		th.CleanupPlan(cleanupCtx).PrintText(w)
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(w, "\nCleaning up...")
+	err := th.Cleanup(cleanupCtx)
+	if err != nil {
+		return xerrors.Errorf("cleanup tests: %w", err)
+	}
+	return nil
+}
+
 type scaleTestOutputFormat string
 
 const (
-	scaleTestOutputFormatText scaleTestOutputFormat = "text"
-	scaleTestOutputFormatJSON scaleTestOutputFormat = "json"
+	scaleTestOutputFormatText  scaleTestOutputFormat = "text"
+	scaleTestOutputFormatJSON  scaleTestOutputFormat = "json"
+	scaleTestOutputFormatCSV   scaleTestOutputFormat = "csv"
+	scaleTestOutputFormatJUnit scaleTestOutputFormat = "junit"
+	// scaleTestOutputFormatPrometheus pushes a summary of the results to the
+	// Prometheus Pushgateway at the URL in the path of the output.
+	scaleTestOutputFormatPrometheus scaleTestOutputFormat = "prometheus"
 	// TODO: html format
 )
 
@@ -267,6 +434,15 @@ type scaleTestOutput struct {
 }
 
 func (o *scaleTestOutput) write(res harness.Results, stdout io.Writer) error {
+	// Outputs are also written after the test was interrupted, so they don't
+	// use the context of the command.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if o.format == scaleTestOutputFormatPrometheus {
+		return harness.PrometheusPushReporter{URL: o.path}.Report(ctx, res)
+	}
+
 	var (
 		w = stdout
 		c io.Closer
@@ -279,13 +455,21 @@ func (o *scaleTestOutput) write(res harness.Results, stdout io.Writer) error {
 		w, c = f, f
 	}
 
+	var reporter harness.Reporter
 	switch o.format {
 	case scaleTestOutputFormatText:
-		res.PrintText(w)
+		reporter = harness.TextReporter{W: w}
 	case scaleTestOutputFormatJSON:
-		err := json.NewEncoder(w).Encode(res)
+		reporter = harness.JSONReporter{W: w}
+	case scaleTestOutputFormatCSV:
+		reporter = harness.CSVReporter{W: w}
+	case scaleTestOutputFormatJUnit:
+		reporter = harness.JUnitReporter{W: w}
+	}
+	if reporter != nil {
+		err := reporter.Report(ctx, res)
 		if err != nil {
-			return xerrors.Errorf("encode JSON: %w", err)
+			return err
 		}
 	}
 
@@ -313,7 +497,7 @@ func (s *scaletestOutputFlags) attach(opts *serpent.OptionSet) {
 	*opts = append(*opts, serpent.Option{
 		Flag:        "output",
 		Env:         "CODER_SCALETEST_OUTPUTS",
-		Description: `Output format specs in the format "<format>[:<path>]". Not specifying a path will default to stdout. Available formats: text, json.`,
+		Description: `Output format specs in the format "<format>[:<path>]". Not specifying a path will default to stdout. Available formats: text, json, csv, junit, prometheus. The path of the prometheus format is the URL of a Prometheus Pushgateway to push a summary of the results to, e.g. "prometheus:http://pushgateway:9091".`,
 		Default:     "text",
 		Value:       serpent.StringArrayOf(&s.outputSpecs),
 	})
@@ -323,8 +507,11 @@ func (s *scaletestOutputFlags) parse() ([]scaleTestOutput, error) {
 	var stdoutFormat scaleTestOutputFormat
 
 	validFormats := map[scaleTestOutputFormat]struct{}{
-		scaleTestOutputFormatText: {},
-		scaleTestOutputFormatJSON: {},
+		scaleTestOutputFormatText:       {},
+		scaleTestOutputFormatJSON:       {},
+		scaleTestOutputFormatCSV:        {},
+		scaleTestOutputFormatJUnit:      {},
+		scaleTestOutputFormatPrometheus: {},
 	}
 
 	var out []scaleTestOutput
@@ -336,6 +523,9 @@ func (s *scaletestOutputFlags) parse() ([]scaleTestOutput, error) {
 		}
 
 		if len(parts) == 1 {
+			if format == scaleTestOutputFormatPrometheus {
+				return nil, xerrors.Errorf("output flag %d: the prometheus format requires the URL of a Pushgateway", i)
+			}
 			if stdoutFormat != "" {
 				return nil, xerrors.Errorf("multiple output flags specified for stdout")
 			}
@@ -367,6 +557,932 @@ func (s *scaletestOutputFlags) parse() ([]scaleTestOutput, error) {
 	return out, nil
 }
 
+type scaletestRetryFlags struct {
+	retries    int64
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func (s *scaletestRetryFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "retries",
+			Env:         "CODER_SCALETEST_RETRIES",
+			Description: "Number of times to retry a failed run. Runs are only marked as failed once all retries are exhausted.",
+			Default:     "0",
+			Value:       serpent.Int64Of(&s.retries),
+		},
+		serpent.Option{
+			Flag:        "retry-backoff",
+			Env:         "CODER_SCALETEST_RETRY_BACKOFF",
+			Description: "Delay before the first retry of a failed run. The delay doubles on every subsequent retry.",
+			Default:     "5s",
+			Value:       serpent.DurationOf(&s.backoff),
+		},
+		serpent.Option{
+			Flag:        "retry-max-backoff",
+			Env:         "CODER_SCALETEST_RETRY_MAX_BACKOFF",
+			Description: "Maximum delay between retries of a failed run.",
+			Default:     "1m",
+			Value:       serpent.DurationOf(&s.maxBackoff),
+		},
+	)
+}
+
+func (s *scaletestRetryFlags) option() harness.Option {
+	return harness.WithRetryPolicy(harness.RetryPolicy{
+		Retries:    int(s.retries),
+		Backoff:    s.backoff,
+		MaxBackoff: s.maxBackoff,
+	})
+}
+
+type scaletestWarmupFlags struct {
+	runs     int64
+	duration time.Duration
+}
+
+func (s *scaletestWarmupFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "warmup-runs",
+			Env:         "CODER_SCALETEST_WARMUP_RUNS",
+			Description: "Number of runs, in the order they are started, to exclude from the results summary as warmup.",
+			Default:     "0",
+			Value:       serpent.Int64Of(&s.runs),
+		},
+		serpent.Option{
+			Flag:        "warmup-duration",
+			Env:         "CODER_SCALETEST_WARMUP_DURATION",
+			Description: "Runs started within this duration of the start of the test are excluded from the results summary as warmup.",
+			Default:     "0s",
+			Value:       serpent.DurationOf(&s.duration),
+		},
+	)
+}
+
+func (s *scaletestWarmupFlags) option() harness.Option {
+	return harness.WithWarmup(harness.Warmup{
+		Runs:     int(s.runs),
+		Duration: s.duration,
+	})
+}
+
+// scaletestInterruptHandler implements two-stage interrupt handling for
+// scaletest commands. Until a test harness is attached, an interrupt cancels
+// the context straight away. Once attached, the first interrupt drains the
+// harness so that no new runs are started, and the second interrupt cancels the
+// context and with it all in-flight runs. Either way the harness finishes, so
+// the partial results can still be written.
+type scaletestInterruptHandler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sigs   chan os.Signal
+	w      io.Writer
+
+	mut         sync.Mutex
+	th          *harness.TestHarness
+	interrupted bool
+}
+
+func newScaletestInterruptHandler(ctx context.Context, w io.Writer) *scaletestInterruptHandler {
+	ctx, cancel := context.WithCancel(ctx)
+	h := &scaletestInterruptHandler{
+		ctx:    ctx,
+		cancel: cancel,
+		sigs:   make(chan os.Signal, 1),
+		w:      w,
+	}
+	signal.Notify(h.sigs, StopSignals...)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.sigs:
+				h.handle()
+			}
+		}
+	}()
+	return h
+}
+
+func (h *scaletestInterruptHandler) handle() {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	if h.th == nil || h.interrupted {
+		if h.th != nil {
+			_, _ = fmt.Fprintln(h.w, "\nInterrupted again, canceling in-flight runs...")
+		}
+		h.cancel()
+		return
+	}
+
+	h.interrupted = true
+	h.th.Drain()
+	_, _ = fmt.Fprintln(h.w, "\nInterrupted, waiting for in-flight runs to finish. Interrupt again to cancel them.")
+}
+
+// attach enables draining the given harness on the first interrupt.
+func (h *scaletestInterruptHandler) attach(th *harness.TestHarness) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.th = th
+}
+
+// wasInterrupted returns whether the attached harness has been interrupted.
+func (h *scaletestInterruptHandler) wasInterrupted() bool {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	return h.interrupted
+}
+
+// stop restores the default signal behavior and cancels the context.
+func (h *scaletestInterruptHandler) stop() {
+	signal.Stop(h.sigs)
+	h.cancel()
+}
+
+type scaletestSeedFlags struct {
+	seed int64
+}
+
+func (s *scaletestSeedFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts, serpent.Option{
+		Flag:        "seed",
+		Env:         "CODER_SCALETEST_SEED",
+		Description: "Seed for the random number generators used by the test, e.g. for run order and generated names. Use the seed reported in the results of a previous test to reproduce it. 0 picks a random seed.",
+		Default:     "0",
+		Value:       serpent.Int64Of(&s.seed),
+	})
+}
+
+func (s *scaletestSeedFlags) option() harness.Option {
+	return harness.WithSeed(s.seed)
+}
+
+type scaletestResourceUsageFlags struct {
+	interval time.Duration
+}
+
+func (s *scaletestResourceUsageFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts, serpent.Option{
+		Flag:        "resource-usage-interval",
+		Env:         "CODER_SCALETEST_RESOURCE_USAGE_INTERVAL",
+		Description: "Interval at which the CPU, memory and file descriptor usage of this process is sampled and included in the results, to tell whether the load generator rather than the deployment was the bottleneck. 0 disables sampling.",
+		Default:     "5s",
+		Value:       serpent.DurationOf(&s.interval),
+	})
+}
+
+func (s *scaletestResourceUsageFlags) option() harness.Option {
+	return harness.WithResourceUsage(s.interval)
+}
+
+type scaletestAnnotationFlags struct {
+	grafanaURL      url.URL
+	grafanaAPIToken string
+	webhookURL      url.URL
+	tags            []string
+}
+
+func (s *scaletestAnnotationFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "grafana-url",
+			Env:         "CODER_SCALETEST_GRAFANA_URL",
+			Description: "URL of a Grafana instance to post annotations marking the run, warmup, chaos and cleanup phases of the test to, so that they are visible on the dashboards of the deployment.",
+			Value:       serpent.URLOf(&s.grafanaURL),
+		},
+		serpent.Option{
+			Flag:        "grafana-api-token",
+			Env:         "CODER_SCALETEST_GRAFANA_API_TOKEN",
+			Description: "Grafana service account token with permission to write annotations.",
+			Value:       serpent.StringOf(&s.grafanaAPIToken),
+		},
+		serpent.Option{
+			Flag:        "annotation-webhook-url",
+			Env:         "CODER_SCALETEST_ANNOTATION_WEBHOOK_URL",
+			Description: "URL to post the annotations marking the phases of the test to as JSON, for monitoring systems other than Grafana.",
+			Value:       serpent.URLOf(&s.webhookURL),
+		},
+		serpent.Option{
+			Flag:        "annotation-tag",
+			Env:         "CODER_SCALETEST_ANNOTATION_TAGS",
+			Description: "Additional tags to add to every annotation, e.g. to distinguish tests against different deployments.",
+			Value:       serpent.StringArrayOf(&s.tags),
+		},
+	)
+}
+
+// option returns a harness option posting annotations to the configured
+// destinations. Failures to post annotations are written to w but never fail
+// the test.
+func (s *scaletestAnnotationFlags) option(w io.Writer) harness.Option {
+	var annotators annotate.Multi
+	if s.grafanaURL.String() != "" {
+		grafanaURL := s.grafanaURL
+		annotators = append(annotators, annotate.LogErrors(&annotate.Grafana{
+			URL:   &grafanaURL,
+			Token: s.grafanaAPIToken,
+			Tags:  s.tags,
+		}, w))
+	}
+	if s.webhookURL.String() != "" {
+		annotators = append(annotators, annotate.LogErrors(&annotate.Webhook{
+			URL:  s.webhookURL.String(),
+			Tags: s.tags,
+		}, w))
+	}
+	if len(annotators) == 0 {
+		return func(*harness.TestHarness) {}
+	}
+	return harness.WithAnnotator(annotators)
+}
+
+type scaletestCircuitBreakerFlags struct {
+	breaker harness.CircuitBreaker
+	window  int64
+}
+
+func (s *scaletestCircuitBreakerFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "abort-error-rate",
+			Env:         "CODER_SCALETEST_ABORT_ERROR_RATE",
+			Description: "Stop starting new runs and abort the test once the fraction of failed runs (between 0 and 1) among the last --abort-error-rate-window runs exceeds this value. Runs in progress finish and cleanup still happens. 0 disables the circuit breaker.",
+			Default:     "0",
+			Value:       serpent.Float64Of(&s.breaker.MaxErrorRate),
+		},
+		serpent.Option{
+			Flag:        "abort-error-rate-window",
+			Env:         "CODER_SCALETEST_ABORT_ERROR_RATE_WINDOW",
+			Description: "Number of most recently finished runs the error rate of --abort-error-rate is computed over.",
+			Default:     "100",
+			Value:       serpent.Int64Of(&s.window),
+		},
+	)
+}
+
+func (s *scaletestCircuitBreakerFlags) enabled() bool {
+	return s.breaker.MaxErrorRate > 0
+}
+
+func (s *scaletestCircuitBreakerFlags) validate() error {
+	if !s.enabled() {
+		return nil
+	}
+	s.breaker.Window = int(s.window)
+	if err := s.breaker.Validate(); err != nil {
+		return xerrors.Errorf("invalid circuit breaker: %w", err)
+	}
+	return nil
+}
+
+func (s *scaletestCircuitBreakerFlags) option() harness.Option {
+	if !s.enabled() {
+		return func(*harness.TestHarness) {}
+	}
+	return harness.WithCircuitBreaker(s.breaker)
+}
+
+// check returns an error if the circuit breaker aborted the test.
+func (*scaletestCircuitBreakerFlags) check(res harness.Results) error {
+	if res.CircuitBreaker == nil {
+		return nil
+	}
+	return xerrors.Errorf("load test aborted, %s", res.CircuitBreaker)
+}
+
+type scaletestUploadFlags struct {
+	enabled bool
+	name    string
+}
+
+func (s *scaletestUploadFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "upload-results",
+			Env:         "CODER_SCALETEST_UPLOAD_RESULTS",
+			Description: "Store a summary of the results in the deployment under test, so that the results of past tests can be queried through the API. Requires permission to update the deployment config.",
+			Value:       serpent.BoolOf(&s.enabled),
+		},
+		serpent.Option{
+			Flag:        "upload-results-name",
+			Env:         "CODER_SCALETEST_UPLOAD_RESULTS_NAME",
+			Description: "Name to store the results under with --upload-results. Defaults to the name of the scaletest command.",
+			Value:       serpent.StringOf(&s.name),
+		},
+	)
+}
+
+// upload stores the summary of the results in the deployment, if enabled.
+func (s *scaletestUploadFlags) upload(ctx context.Context, client *codersdk.Client, inv *serpent.Invocation, res harness.Results) error {
+	if !s.enabled {
+		return nil
+	}
+	name := s.name
+	if name == "" {
+		name = inv.Command.Name()
+	}
+	uploaded, err := harness.UploadResults(ctx, client, name, res)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(inv.Stderr, "Uploaded results as %s\n", uploaded.ID)
+	return nil
+}
+
+// scaletestTelemetryTimeout is how long reporting scaletest telemetry may
+// take.
+const scaletestTelemetryTimeout = 10 * time.Second
+
+type scaletestTelemetryFlags struct {
+	enabled bool
+	url     url.URL
+}
+
+func (s *scaletestTelemetryFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "report-telemetry",
+			Env:         "CODER_SCALETEST_REPORT_TELEMETRY",
+			Description: "Report an anonymized summary of the outcome of the test to Coder, to help tune the sizing guidance. It contains the size class of the deployment by number of users, its version, the throughput and the error rate of the test, but no identifiers of the deployment or its users.",
+			Value:       serpent.BoolOf(&s.enabled),
+		},
+		serpent.Option{
+			Flag:        "telemetry-url",
+			Env:         "CODER_SCALETEST_TELEMETRY_URL",
+			Description: "URL to report scaletest telemetry to.",
+			Default:     "https://telemetry.coder.com",
+			Value:       serpent.URLOf(&s.url),
+			Hidden:      true,
+		},
+	)
+}
+
+// report reports an anonymized summary of the results, if enabled. Reporting is
+// best effort, failures are written to stderr but never fail the test.
+func (s *scaletestTelemetryFlags) report(ctx context.Context, client *codersdk.Client, inv *serpent.Invocation, res harness.Results) {
+	if !s.enabled {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, scaletestTelemetryTimeout)
+	defer cancel()
+
+	err := func() error {
+		buildInfo, err := client.BuildInfo(ctx)
+		if err != nil {
+			return xerrors.Errorf("get build info: %w", err)
+		}
+		users, err := client.Users(ctx, codersdk.UsersRequest{Pagination: codersdk.Pagination{Limit: 1}})
+		if err != nil {
+			return xerrors.Errorf("count users: %w", err)
+		}
+
+		run := telemetry.ScaletestRun{
+			ID:           uuid.New(),
+			CreatedAt:    time.Now(),
+			Command:      inv.Command.Name(),
+			CoderVersion: buildInfo.Version,
+			SizeClass:    telemetry.ScaletestSizeClass(users.Count),
+			TotalRuns:    res.TotalRuns,
+			TotalFail:    res.TotalFail,
+			ElapsedSec:   time.Duration(res.Elapsed).Seconds(),
+		}
+		if run.ElapsedSec > 0 {
+			run.Throughput = float64(res.TotalPass) / run.ElapsedSec
+		}
+		if res.TotalRuns > 0 {
+			run.ErrorRate = float64(res.TotalFail) / float64(res.TotalRuns)
+		}
+		return telemetry.Submit(ctx, http.DefaultClient, &s.url, &telemetry.Snapshot{
+			ScaletestRuns: []telemetry.ScaletestRun{run},
+		})
+	}()
+	if err != nil {
+		_, _ = fmt.Fprintf(inv.Stderr, "Failed to report scaletest telemetry: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintln(inv.Stderr, "Reported scaletest telemetry, thank you!")
+}
+
+type scaletestSnapshotFlags struct {
+	interval time.Duration
+	path     string
+}
+
+func (s *scaletestSnapshotFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "snapshot-interval",
+			Env:         "CODER_SCALETEST_SNAPSHOT_INTERVAL",
+			Description: "Print a summary of the runs that completed during every interval of the test to stderr, e.g. to spot latency creeping up during soak tests. The summaries are also included in the results. 0 disables snapshots.",
+			Default:     "0s",
+			Value:       serpent.DurationOf(&s.interval),
+		},
+		serpent.Option{
+			Flag:        "snapshot-file",
+			Env:         "CODER_SCALETEST_SNAPSHOT_FILE",
+			Description: "Path to append every snapshot to as a line of JSON as soon as it is taken. Requires --snapshot-interval.",
+			Value:       serpent.StringOf(&s.path),
+		},
+	)
+}
+
+func (s *scaletestSnapshotFlags) option(w io.Writer) harness.Option {
+	if s.interval <= 0 {
+		return func(*harness.TestHarness) {}
+	}
+	return harness.WithSnapshots(s.interval, func(snapshot harness.Snapshot) {
+		_, _ = fmt.Fprintf(w, "Snapshot %d (%s): %d runs, %d failed, %d in flight\n", snapshot.Index, time.Duration(snapshot.Elapsed).Round(time.Second), snapshot.TotalRuns, snapshot.TotalFail, snapshot.InFlight)
+		if s.path == "" {
+			return
+		}
+		if err := appendSnapshot(s.path, snapshot); err != nil {
+			_, _ = fmt.Fprintf(w, "Failed to write snapshot to %q: %v\n", s.path, err)
+		}
+	})
+}
+
+type scaletestProgressFlags struct {
+	enabled  bool
+	interval time.Duration
+}
+
+func (s *scaletestProgressFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "progress",
+			Env:         "CODER_SCALETEST_PROGRESS",
+			Description: "Show the progress of the test while it's running, along with the error rate and latency percentiles of the most recently completed runs. If stdout is a terminal, a live progress bar is drawn to it, otherwise a progress line is printed to stderr at every --progress-interval.",
+			Default:     "true",
+			Value:       serpent.BoolOf(&s.enabled),
+		},
+		serpent.Option{
+			Flag:        "progress-interval",
+			Env:         "CODER_SCALETEST_PROGRESS_INTERVAL",
+			Description: "Interval between progress lines when stdout is not a terminal.",
+			Default:     "30s",
+			Value:       serpent.DurationOf(&s.interval),
+		},
+	)
+}
+
+func (s *scaletestProgressFlags) option(inv *serpent.Invocation) harness.Option {
+	if !s.enabled {
+		return func(*harness.TestHarness) {}
+	}
+	if isTTYOut(inv) {
+		return harness.WithProgress(inv.Stdout, harness.ProgressConfig{TTY: true})
+	}
+	return harness.WithProgress(inv.Stderr, harness.ProgressConfig{Interval: s.interval})
+}
+
+type scaletestKeepFailuresFlags struct {
+	noCleanupFailures bool
+}
+
+func (s *scaletestKeepFailuresFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "no-cleanup-failures",
+			Env:         "CODER_SCALETEST_NO_CLEANUP_FAILURES",
+			Description: "Do not clean up the resources of failed runs, so that they can be inspected after the test. The resources of passed runs are still cleaned up. The retained resources are listed in the results. You can cleanup manually using coder scaletest cleanup.",
+			Value:       serpent.BoolOf(&s.noCleanupFailures),
+		},
+	)
+}
+
+func (s *scaletestKeepFailuresFlags) option() harness.Option {
+	if !s.noCleanupFailures {
+		return func(*harness.TestHarness) {}
+	}
+	return harness.WithRetainFailedRuns()
+}
+
+type scaletestRerunFlags struct {
+	path     string
+	previous *harness.Results
+}
+
+func (s *scaletestRerunFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "rerun-failed",
+			Env:         "CODER_SCALETEST_RERUN_FAILED",
+			Description: "Path to the JSON results of a previous test to rerun only the failed and skipped runs of, with the same configuration and seed. Pass the same flags as for the previous test. The outputs contain the previous results with the results of the reruns merged in.",
+			Value:       serpent.StringOf(&s.path),
+		},
+	)
+}
+
+// load reads the previous results, if set.
+func (s *scaletestRerunFlags) load() error {
+	if s.path == "" {
+		return nil
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return xerrors.Errorf("open previous results: %w", err)
+	}
+	defer f.Close()
+	previous, err := harness.ReadResults(f)
+	if err != nil {
+		return xerrors.Errorf("read previous results %q: %w", s.path, err)
+	}
+	s.previous = &previous
+	return nil
+}
+
+func (s *scaletestRerunFlags) option() harness.Option {
+	if s.previous == nil {
+		return func(*harness.TestHarness) {}
+	}
+	return harness.WithRerunFailed(*s.previous)
+}
+
+// merge merges the results of the reruns into the previous results, if the
+// failed runs of previous results were rerun.
+func (s *scaletestRerunFlags) merge(res harness.Results) (harness.Results, error) {
+	if s.previous == nil {
+		return res, nil
+	}
+	merged, err := harness.MergeRerun(*s.previous, res)
+	if err != nil {
+		return harness.Results{}, xerrors.Errorf("merge rerun results: %w", err)
+	}
+	return merged, nil
+}
+
+type scaletestRetentionFlags struct {
+	passed int64
+	failed int64
+}
+
+func (s *scaletestRetentionFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "retain-passed-runs",
+			Env:         "CODER_SCALETEST_RETAIN_PASSED_RUNS",
+			Description: "Number of passed runs whose logs and individual results are kept in memory and included in the outputs. Totals and summaries still cover every run. Limiting this keeps the memory usage of tests with very many runs bounded. -1 keeps every run.",
+			Default:     "-1",
+			Value:       serpent.Int64Of(&s.passed),
+		},
+		serpent.Option{
+			Flag:        "retain-failed-runs",
+			Env:         "CODER_SCALETEST_RETAIN_FAILED_RUNS",
+			Description: "Number of failed runs whose logs and individual results are kept in memory and included in the outputs. -1 keeps every run.",
+			Default:     "-1",
+			Value:       serpent.Int64Of(&s.failed),
+		},
+	)
+}
+
+func (s *scaletestRetentionFlags) option() harness.Option {
+	if s.passed < 0 && s.failed < 0 {
+		return func(*harness.TestHarness) {}
+	}
+	limit := func(n int64) int {
+		if n < 0 {
+			return math.MaxInt
+		}
+		return int(n)
+	}
+	return harness.WithResultRetention(harness.ResultRetention{
+		Passed: limit(s.passed),
+		Failed: limit(s.failed),
+	})
+}
+
+type scaletestLoadProfileFlags struct {
+	path        string
+	compression float64
+	schedule    []time.Duration
+}
+
+// attach attaches the load profile flags. runs describes what a run replays
+// for every occurrence of the metric of the profile.
+func (s *scaletestLoadProfileFlags) attach(opts *serpent.OptionSet, runs string) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "load-profile",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE",
+			Description: fmt.Sprintf("Path to a load profile written by record-load-profile to replay. Starts %s at the time it happened in the profile, compressed by --load-profile-compression. Runs start late if the concurrency is limited, so use it with --concurrency 0.", runs),
+			Value:       serpent.StringOf(&s.path),
+		},
+		serpent.Option{
+			Flag:        "load-profile-compression",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE_COMPRESSION",
+			Description: "Factor the load profile is compressed in time by, e.g. 1440 replays a day in a minute.",
+			Default:     "1",
+			Value:       serpent.Float64Of(&s.compression),
+		},
+	)
+}
+
+func (s *scaletestLoadProfileFlags) enabled() bool {
+	return s.path != ""
+}
+
+// load reads the load profile, if set, and schedules a run for every occurrence
+// of the given metric. It returns the number of scheduled runs.
+func (s *scaletestLoadProfileFlags) load(m loadprofile.Metric) (int, error) {
+	if !s.enabled() {
+		return 0, nil
+	}
+	if !(s.compression > 0) {
+		return 0, xerrors.New("--load-profile-compression must be greater than 0")
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return 0, xerrors.Errorf("open load profile: %w", err)
+	}
+	defer f.Close()
+	profile, err := loadprofile.Parse(f)
+	if err != nil {
+		return 0, xerrors.Errorf("parse load profile: %w", err)
+	}
+	s.schedule = profile.Schedule(m, s.compression)
+	return len(s.schedule), nil
+}
+
+func (s *scaletestLoadProfileFlags) wrapStrategy(strategy harness.ExecutionStrategy) harness.ExecutionStrategy {
+	if !s.enabled() {
+		return strategy
+	}
+	return loadprofile.ExecutionStrategyWrapper{
+		Schedule: s.schedule,
+		Inner:    strategy,
+	}
+}
+
+// appendSnapshot appends the snapshot to the file at the given path as a line
+// of JSON.
+func appendSnapshot(path string, snapshot harness.Snapshot) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return xerrors.Errorf("open snapshot file: %w", err)
+	}
+	defer f.Close()
+	err = json.NewEncoder(f).Encode(snapshot)
+	if err != nil {
+		return xerrors.Errorf("write snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+type scaletestOrganizationFlags struct {
+	organizations []string
+}
+
+func (s *scaletestOrganizationFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts, serpent.Option{
+		Flag:        "organization",
+		Env:         "CODER_SCALETEST_ORGANIZATIONS",
+		Description: "Organization to create users and workspaces in, by name or ID, optionally followed by a colon and a relative weight, e.g. \"eng:3\". Repeat to spread runs across multiple organizations in proportion to their weights, e.g. to test organization scoped RBAC and provisioner routing. Each organization must have a copy of the template. Defaults to the first organization of the logged in user.",
+		Value:       serpent.StringArrayOf(&s.organizations),
+	})
+}
+
+// parse resolves the organizations and their weights. It returns nil if no
+// organizations were given.
+func (s *scaletestOrganizationFlags) parse(ctx context.Context, client *codersdk.Client) ([]harness.Weighted[codersdk.Organization], error) {
+	organizations := make([]harness.Weighted[codersdk.Organization], 0, len(s.organizations))
+	for _, raw := range s.organizations {
+		name, weight := raw, 1
+		if i := strings.LastIndex(raw, ":"); i >= 0 {
+			w, err := strconv.Atoi(raw[i+1:])
+			if err != nil || w <= 0 {
+				return nil, xerrors.Errorf("invalid --organization %q, weight must be a positive integer", raw)
+			}
+			name, weight = raw[:i], w
+		}
+		org, err := client.OrganizationByName(ctx, name)
+		if err != nil {
+			return nil, xerrors.Errorf("get organization %q: %w", name, err)
+		}
+		for _, o := range organizations {
+			if o.Value.ID == org.ID {
+				return nil, xerrors.Errorf("organization %q given more than once", name)
+			}
+		}
+		organizations = append(organizations, harness.Weighted[codersdk.Organization]{
+			Value:  org,
+			Weight: weight,
+		})
+	}
+	return organizations, nil
+}
+
+type scaletestLogStreamFlags struct {
+	enabled bool
+	dir     string
+}
+
+func (s *scaletestLogStreamFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "stream-logs",
+			Env:         "CODER_SCALETEST_STREAM_LOGS",
+			Description: "Stream the logs of every run to stderr while the test is running, prefixed with the ID of the run.",
+			Value:       serpent.BoolOf(&s.enabled),
+		},
+		serpent.Option{
+			Flag:        "log-dir",
+			Env:         "CODER_SCALETEST_LOG_DIR",
+			Description: "Directory to write the logs of every run to as JSON lines, one file per run named after its full ID.",
+			Value:       serpent.StringOf(&s.dir),
+		},
+	)
+}
+
+func (s *scaletestLogStreamFlags) option(w io.Writer) harness.Option {
+	return func(h *harness.TestHarness) {
+		if s.enabled {
+			harness.WithLogStream(w)(h)
+		}
+		if s.dir != "" {
+			harness.WithLogDir(s.dir)(h)
+		}
+	}
+}
+
+type scaletestArtifactFlags struct {
+	dir     string
+	archive string
+}
+
+func (s *scaletestArtifactFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "artifact-dir",
+			Env:         "CODER_SCALETEST_ARTIFACT_DIR",
+			Description: "Directory to collect per-run artifacts in, such as the logs of failed runs. Each run gets its own subdirectory named after its full ID.",
+			Value:       serpent.StringOf(&s.dir),
+		},
+		serpent.Option{
+			Flag:        "artifact-archive",
+			Env:         "CODER_SCALETEST_ARTIFACT_ARCHIVE",
+			Description: "Path to write a gzipped tarball of the artifact directory to after the test. Requires --artifact-dir.",
+			Value:       serpent.StringOf(&s.archive),
+		},
+	)
+}
+
+func (s *scaletestArtifactFlags) validate() error {
+	if s.archive != "" && s.dir == "" {
+		return xerrors.New("--artifact-archive requires --artifact-dir to be set")
+	}
+	return nil
+}
+
+func (s *scaletestArtifactFlags) option() harness.Option {
+	return harness.WithArtifactDir(s.dir)
+}
+
+// writeArchive writes the artifact archive, if requested, once the harness has
+// finished.
+func (s *scaletestArtifactFlags) writeArchive(th *harness.TestHarness) error {
+	if s.archive == "" {
+		return nil
+	}
+
+	f, err := os.Create(s.archive)
+	if err != nil {
+		return xerrors.Errorf("create artifact archive %q: %w", s.archive, err)
+	}
+	defer f.Close()
+	err = th.WriteArtifactArchive(f)
+	if err != nil {
+		return xerrors.Errorf("write artifact archive %q: %w", s.archive, err)
+	}
+	return f.Close()
+}
+
+type scaletestProfileFlags struct {
+	interval time.Duration
+	duration time.Duration
+	profiles []string
+	dir      string
+}
+
+func (s *scaletestProfileFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "profile-interval",
+			Env:         "CODER_SCALETEST_PROFILE_INTERVAL",
+			Description: "Interval at which pprof profiles are captured from the debug endpoints of coderd while the test runs. Every capture is named after the load phase it was captured in and annotated. Requires the owner role. 0 disables profiling.",
+			Default:     "0",
+			Value:       serpent.DurationOf(&s.interval),
+		},
+		serpent.Option{
+			Flag:        "profile-duration",
+			Env:         "CODER_SCALETEST_PROFILE_DURATION",
+			Description: "How long the CPU and trace profiles of every capture run for. 0 uses the server default.",
+			Default:     "0",
+			Value:       serpent.DurationOf(&s.duration),
+		},
+		serpent.Option{
+			Flag:        "profile-type",
+			Env:         "CODER_SCALETEST_PROFILE_TYPES",
+			Description: "Types of profiles to capture, e.g. cpu, heap or goroutine. Defaults to the server default.",
+			Value:       serpent.StringArrayOf(&s.profiles),
+		},
+		serpent.Option{
+			Flag:        "profile-dir",
+			Env:         "CODER_SCALETEST_PROFILE_DIR",
+			Description: "Directory to write the captured profiles to. Defaults to the profiles directory of --artifact-dir.",
+			Value:       serpent.StringOf(&s.dir),
+		},
+	)
+}
+
+func (s *scaletestProfileFlags) validate(artifactDir string) error {
+	if s.interval < 0 {
+		return xerrors.New("--profile-interval must not be negative")
+	}
+	if s.duration > codersdk.DebugProfileDurationMax {
+		return xerrors.Errorf("--profile-duration must be at most %s", codersdk.DebugProfileDurationMax)
+	}
+	if s.interval > 0 && s.dir == "" && artifactDir == "" {
+		return xerrors.New("--profile-interval requires --profile-dir or --artifact-dir to be set")
+	}
+	return nil
+}
+
+// option returns a harness option capturing profiles from the deployment the
+// client is connected to.
+func (s *scaletestProfileFlags) option(client *codersdk.Client) harness.Option {
+	if s.interval <= 0 {
+		return func(*harness.TestHarness) {}
+	}
+	opts := codersdk.DebugProfileOptions{
+		Duration: s.duration,
+		Profiles: s.profiles,
+	}
+	return harness.WithProfiles(harness.ProfileConfig{
+		Profiler: harness.ProfilerFunc(func(ctx context.Context, w io.Writer) error {
+			body, err := client.DebugCollectProfile(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer body.Close()
+			_, err = io.Copy(w, body)
+			return err
+		}),
+		Interval:  s.interval,
+		Dir:       s.dir,
+		Extension: ".tar.gz",
+	})
+}
+
+type scaletestAssertionFlags struct {
+	assertions harness.Assertions
+}
+
+func (s *scaletestAssertionFlags) attach(opts *serpent.OptionSet) {
+	*opts = append(*opts,
+		serpent.Option{
+			Flag:        "assert-max-error-rate",
+			Env:         "CODER_SCALETEST_ASSERT_MAX_ERROR_RATE",
+			Description: "Fail the test if the fraction of failed runs (between 0 and 1) exceeds this value. 0 disables the assertion.",
+			Default:     "0",
+			Value:       serpent.Float64Of(&s.assertions.MaxErrorRate),
+		},
+		serpent.Option{
+			Flag:        "assert-max-p95-duration",
+			Env:         "CODER_SCALETEST_ASSERT_MAX_P95_DURATION",
+			Description: "Fail the test if the 95th percentile run duration exceeds this value. 0 disables the assertion.",
+			Default:     "0s",
+			Value:       serpent.DurationOf(&s.assertions.MaxP95Duration),
+		},
+		serpent.Option{
+			Flag:        "assert-min-throughput",
+			Env:         "CODER_SCALETEST_ASSERT_MIN_THROUGHPUT",
+			Description: "Fail the test if fewer successful runs per second than this value were completed. 0 disables the assertion.",
+			Default:     "0",
+			Value:       serpent.Float64Of(&s.assertions.MinThroughput),
+		},
+	)
+}
+
+// check evaluates the assertions against the results, printing a summary of
+// any violations to w and returning an error if there were any.
+func (s *scaletestAssertionFlags) check(res harness.Results, w io.Writer) error {
+	violations := s.assertions.Evaluate(res)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(w, "\nAssertion violations:")
+	for _, v := range violations {
+		_, _ = fmt.Fprintf(w, "\t%s\n", v)
+	}
+	return xerrors.Errorf("load test failed, %d assertion(s) violated", len(violations))
+}
+
 type scaletestPrometheusFlags struct {
 	Address string
 	Wait    time.Duration
@@ -525,6 +1641,27 @@ func (r *userCleanupRunner) Run(ctx context.Context, _ string, _ io.Writer) erro
 	return nil
 }
 
+// tokenCleanupRunner deletes a single scaletest API token.
+type tokenCleanupRunner struct {
+	client *codersdk.Client
+	token  codersdk.APIKeyWithOwner
+}
+
+var _ harness.Runnable = &tokenCleanupRunner{}
+
+// Run implements Runnable.
+func (r *tokenCleanupRunner) Run(ctx context.Context, _ string, _ io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	err := r.client.DeleteAPIKey(ctx, r.token.UserID.String(), r.token.ID)
+	if err != nil {
+		return xerrors.Errorf("delete token %q of user %q: %w", r.token.TokenName, r.token.Username, err)
+	}
+
+	return nil
+}
+
 // prebuildTemplateCleanupRunner deletes a single scaletest prebuilds template.
 // All prebuild workspaces must be deleted before this runs.
 type prebuildTemplateCleanupRunner struct {
@@ -608,12 +1745,17 @@ func getScaletestPrebuildsTemplates(ctx context.Context, client *codersdk.Client
 }
 
 func (r *RootCmd) scaletestCleanup() *serpent.Command {
-	var template string
+	var (
+		template string
+		dryRun   bool
+	)
 	cleanupStrategy := newScaletestCleanupStrategy()
 	cmd := &serpent.Command{
 		Use:   "cleanup",
-		Short: "Cleanup scaletest workspaces, then cleanup scaletest users.",
-		Long:  "The strategy flags will apply to each stage of the cleanup process.",
+		Short: "Cleanup scaletest workspaces, then cleanup scaletest tokens and users.",
+		Long: "Resources are discovered by their scaletest name prefix on the deployment rather than from the state of a previous test, " +
+			"so resources left behind by aborted or crashed tests are cleaned up too. " +
+			"The strategy flags will apply to each stage of the cleanup process.",
 		Handler: func(inv *serpent.Invocation) error {
 			client, err := r.InitClient(inv)
 			if err != nil {
@@ -643,6 +1785,10 @@ func (r *RootCmd) scaletestCleanup() *serpent.Command {
 				}
 			}
 
+			if dryRun {
+				return scaletestCleanupDryRun(ctx, client, template, inv.Stdout)
+			}
+
 			cliui.Infof(inv.Stdout, "Pausing prebuilds reconciler...")
 			setPrebuild := func(val bool) error {
 				return client.PutPrebuildsSettings(ctx, codersdk.PrebuildsSettings{ReconciliationPaused: val})
@@ -755,6 +1901,42 @@ func (r *RootCmd) scaletestCleanup() *serpent.Command {
 				}
 			}
 
+			cliui.Infof(inv.Stdout, "Fetching scaletest tokens...")
+			tokens, err := getScaletestTokens(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			cliui.Errorf(inv.Stderr, "Found %d scaletest tokens\n", len(tokens))
+			if len(tokens) != 0 {
+				cliui.Infof(inv.Stdout, "Deleting scaletest tokens...")
+				harness := harness.NewTestHarness(cleanupStrategy.toStrategy(), harness.ConcurrentExecutionStrategy{})
+
+				for i, t := range tokens {
+					const testName = "cleanup-tokens"
+					r := &tokenCleanupRunner{
+						client: client,
+						token:  t,
+					}
+					harness.AddRun(testName, strconv.Itoa(i), r)
+				}
+
+				ctx, cancel := cleanupStrategy.toContext(ctx)
+				defer cancel()
+				err := harness.Run(ctx)
+				if err != nil {
+					return xerrors.Errorf("run test harness to delete tokens (harness failure, not a test failure): %w", err)
+				}
+
+				cliui.Infof(inv.Stdout, "Done deleting scaletest tokens:")
+				res := harness.Results()
+				res.PrintText(inv.Stderr)
+
+				if res.TotalFail > 0 {
+					return xerrors.Errorf("failed to delete scaletest tokens")
+				}
+			}
+
 			cliui.Infof(inv.Stdout, "Fetching scaletest users...")
 			users, err := getScaletestUsers(ctx, client)
 			if err != nil {
@@ -802,10 +1984,78 @@ func (r *RootCmd) scaletestCleanup() *serpent.Command {
 			Description: "Name or ID of the template. Only delete workspaces created from the given template.",
 			Value:       serpent.StringOf(&template),
 		},
+		{
+			Flag:        "dry-run",
+			Env:         "CODER_SCALETEST_CLEANUP_DRY_RUN",
+			Description: "List the scaletest resources found on the deployment without deleting them.",
+			Value:       serpent.BoolOf(&dryRun),
+		},
+	}
+
+	cleanupStrategy.attach(&cmd.Options)
+	return cmd
+}
+
+// scaletestCleanupDryRun lists the resources that the cleanup command would
+// delete.
+func scaletestCleanupDryRun(ctx context.Context, client *codersdk.Client, template string, w io.Writer) error {
+	printResources := func(kind string, names []string) {
+		_, _ = fmt.Fprintf(w, "\nFound %d scaletest %s:\n", len(names), kind)
+		for _, name := range names {
+			_, _ = fmt.Fprintf(w, "\t%s\n", name)
+		}
+	}
+
+	prebuildWorkspaces, err := getScaletestPrebuildWorkspaces(ctx, client, template)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(prebuildWorkspaces))
+	for _, ws := range prebuildWorkspaces {
+		names = append(names, ws.OwnerName+"/"+ws.Name)
+	}
+	printResources("prebuild workspaces", names)
+
+	prebuildTemplates, err := getScaletestPrebuildsTemplates(ctx, client, template)
+	if err != nil {
+		return err
+	}
+	names = make([]string, 0, len(prebuildTemplates))
+	for _, t := range prebuildTemplates {
+		names = append(names, t.Name)
+	}
+	printResources("prebuilds templates", names)
+
+	workspaces, _, err := getScaletestWorkspaces(ctx, client, "", template)
+	if err != nil {
+		return err
+	}
+	names = make([]string, 0, len(workspaces))
+	for _, ws := range workspaces {
+		names = append(names, ws.OwnerName+"/"+ws.Name)
+	}
+	printResources("workspaces", names)
+
+	tokens, err := getScaletestTokens(ctx, client)
+	if err != nil {
+		return err
+	}
+	names = make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		names = append(names, t.Username+"/"+t.TokenName)
+	}
+	printResources("tokens", names)
+
+	users, err := getScaletestUsers(ctx, client)
+	if err != nil {
+		return err
+	}
+	names = make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Username)
 	}
-
-	cleanupStrategy.attach(&cmd.Options)
-	return cmd
+	printResources("users", names)
+	return nil
 }
 
 func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
@@ -815,9 +2065,7 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 		maxFailures int64
 		template    string
 
-		noCleanup bool
-		// TODO: implement this flag
-		// noCleanupFailures bool
+		noCleanup       bool
 		noWaitForAgents bool
 
 		runCommand       string
@@ -836,12 +2084,34 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 
 		useHostUser bool
 
+		respectQuota bool
+		quotaCost    int64
+
 		parameterFlags workspaceParameterFlags
 
-		tracingFlags    = &scaletestTracingFlags{}
-		strategy        = &scaletestStrategyFlags{}
-		cleanupStrategy = newScaletestCleanupStrategy()
-		output          = &scaletestOutputFlags{}
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		retryFlags          = &scaletestRetryFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		organizationFlags   = &scaletestOrganizationFlags{}
+		loadProfileFlags    = &scaletestLoadProfileFlags{}
 	)
 
 	cmd := &serpent.Command{
@@ -861,6 +2131,17 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 				return err
 			}
 
+			if loadProfileFlags.enabled() {
+				builds, err := loadProfileFlags.load(loadprofile.MetricBuilds)
+				if err != nil {
+					return err
+				}
+				if builds == 0 {
+					return xerrors.New("load profile contains no workspace builds")
+				}
+				count = int64(builds)
+				_, _ = fmt.Fprintf(inv.Stderr, "Replaying %d workspace builds from the load profile\n", builds)
+			}
 			if count <= 0 {
 				return xerrors.Errorf("--count is required and must be greater than 0")
 			}
@@ -868,13 +2149,38 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 			if err != nil {
 				return xerrors.Errorf("could not parse --output flags")
 			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			if respectQuota && !useHostUser {
+				return xerrors.Errorf("--respect-workspace-quota requires --use-host-login, since the quota of users created by the test can't be known in advance")
+			}
+			if retryFlags.retries > 0 && noCleanup {
+				return xerrors.Errorf("--retries can't be used with --no-cleanup, since failed attempts must be cleaned up before they are retried")
+			}
 
 			if template == "" {
 				return xerrors.Errorf("--template is required")
 			}
-			tpl, err := parseTemplate(ctx, client, me.OrganizationIDs, template)
+			organizations, err := organizationFlags.parse(ctx, client)
 			if err != nil {
-				return xerrors.Errorf("parse template: %w", err)
+				return err
+			}
+			if respectQuota && len(organizations) > 1 {
+				return xerrors.Errorf("--respect-workspace-quota doesn't support multiple --organization flags")
 			}
 
 			cliRichParameters, err := asWorkspaceBuildParameters(parameterFlags.richParameters)
@@ -882,20 +2188,66 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 				return xerrors.Errorf("can't parse given parameter values: %w", err)
 			}
 
-			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
-				Action:            WorkspaceCreate,
-				TemplateVersionID: tpl.ActiveVersionID,
-				NewWorkspaceName:  "scaletest-N", // TODO: the scaletest runner will pass in a different name here. Does this matter?
-				Owner:             codersdk.Me,
+			// Each organization has its own copy of the template.
+			type target struct {
+				organization   codersdk.Organization
+				template       codersdk.Template
+				richParameters []codersdk.WorkspaceBuildParameter
+			}
+			var targetWeights []harness.Weighted[target]
+			prepTarget := func(organization codersdk.Organization, organizationIDs []uuid.UUID, weight int) error {
+				tpl, err := parseTemplate(ctx, client, organizationIDs, template)
+				if err != nil {
+					return xerrors.Errorf("parse template: %w", err)
+				}
+				if organization.ID != uuid.Nil && tpl.OrganizationID != organization.ID {
+					return xerrors.Errorf("template %q is not in organization %q", template, organization.Name)
+				}
 
-				RichParameterFile: parameterFlags.richParameterFile,
-				RichParameters:    cliRichParameters,
-			})
+				richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
+					Action:            WorkspaceCreate,
+					TemplateVersionID: tpl.ActiveVersionID,
+					NewWorkspaceName:  "scaletest-N", // TODO: the scaletest runner will pass in a different name here. Does this matter?
+					Owner:             codersdk.Me,
+
+					RichParameterFile: parameterFlags.richParameterFile,
+					RichParameters:    cliRichParameters,
+				})
+				if err != nil {
+					return xerrors.Errorf("prepare build: %w", err)
+				}
+				if organization.ID == uuid.Nil {
+					// TODO: configurable org
+					organization.ID = me.OrganizationIDs[0]
+				}
+				targetWeights = append(targetWeights, harness.Weighted[target]{
+					Value: target{
+						organization:   organization,
+						template:       tpl,
+						richParameters: richParameters,
+					},
+					Weight: weight,
+				})
+				return nil
+			}
+			if len(organizations) == 0 {
+				err = prepTarget(codersdk.Organization{}, me.OrganizationIDs, 1)
+				if err != nil {
+					return err
+				}
+			}
+			for _, o := range organizations {
+				err = prepTarget(o.Value, []uuid.UUID{o.Value.ID}, o.Weight)
+				if err != nil {
+					return xerrors.Errorf("organization %q: %w", o.Value.Name, err)
+				}
+			}
+			targets, err := harness.NewDistribution(targetWeights...)
 			if err != nil {
-				return xerrors.Errorf("prepare build: %w", err)
+				return xerrors.Errorf("distribute runs across organizations: %w", err)
 			}
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
@@ -907,24 +2259,33 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
 				}
 			}()
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
-			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy())
+			runStrategy := loadProfileFlags.wrapStrategy(strategy.toStrategy())
+			if respectQuota {
+				runStrategy = quota.ExecutionStrategyWrapper{
+					Client:         client,
+					OrganizationID: targetWeights[0].Value.organization.ID,
+					User:           me.ID.String(),
+					Cost:           int(quotaCost),
+					Inner:          runStrategy,
+				}
+			}
+			th := harness.NewTestHarness(runStrategy, cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), retryFlags.option(), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
 			for i := 0; i < int(count); i++ {
 				const name = "workspacebuild"
 				id := strconv.Itoa(i)
+				target := targets.Next()
 
 				config := createworkspaces.Config{
 					User: createworkspaces.UserConfig{
-						// TODO: configurable org
-						OrganizationID: me.OrganizationIDs[0],
+						OrganizationID: target.organization.ID,
 					},
 					Workspace: workspacebuild.Config{
-						OrganizationID: me.OrganizationIDs[0],
+						OrganizationID: target.organization.ID,
 						// UserID is set by the test automatically.
 						Request: codersdk.CreateWorkspaceRequest{
-							TemplateID:          tpl.ID,
-							RichParameterValues: richParameters,
+							TemplateID:          target.template.ID,
+							RichParameterValues: target.richParameters,
 						},
 						NoWaitForAgents: noWaitForAgents,
 						Retry:           int(retry),
@@ -980,46 +2341,67 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = createworkspaces.NewRunner(runnerClient, config)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: fmt.Sprintf("%s/%s", name, id),
-						runner:   runner,
-					}
-				}
 
-				th.AddRun(name, id, runner)
+				run := th.AddRun(name, id, runner)
+				if len(organizations) > 1 {
+					run.SetTag("organization", target.organization.Name)
+				}
 			}
 
-			// TODO: live progress output
 			_, _ = fmt.Fprintln(inv.Stderr, "Running load test...")
-			testCtx, testCancel := strategy.toContext(ctx)
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr)
+			defer interrupts.stop()
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(interrupts.ctx)
 			defer testCancel()
 			err = th.Run(testCtx)
 			if err != nil {
 				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
 			}
+			// Restore the default interrupt behavior for cleanup.
+			interrupts.stop()
 
-			res := th.Results()
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
 			for _, o := range outputs {
 				err = o.write(res, inv.Stdout)
 				if err != nil {
 					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
 				}
 			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
 
-			_, _ = fmt.Fprintln(inv.Stderr, "\nCleaning up...")
-			cleanupCtx, cleanupCancel := cleanupStrategy.toContext(ctx)
-			defer cleanupCancel()
-			err = th.Cleanup(cleanupCtx)
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
 			if err != nil {
-				return xerrors.Errorf("cleanup tests: %w", err)
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
 			}
 
 			if res.TotalFail > int(maxFailures) {
 				return xerrors.Errorf("load test failed, %d runs failed (max allowed: %d)", res.TotalFail, maxFailures)
 			}
 
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	}
@@ -1132,6 +2514,20 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 			Description: "Use the user logged in on the host machine, instead of creating users.",
 			Value:       serpent.BoolOf(&useHostUser),
 		},
+		{
+			Flag:        "respect-workspace-quota",
+			Env:         "CODER_SCALETEST_RESPECT_WORKSPACE_QUOTA",
+			Default:     "false",
+			Description: "Only start runs while the workspace quota of the host user has enough credits left, and skip the remaining runs instead of failing them once it's exhausted. Requires --use-host-login.",
+			Value:       serpent.BoolOf(&respectQuota),
+		},
+		{
+			Flag:        "workspace-quota-cost",
+			Env:         "CODER_SCALETEST_WORKSPACE_QUOTA_COST",
+			Default:     "1",
+			Description: "Number of quota credits each workspace consumes, i.e. the daily cost of the template, for --respect-workspace-quota.",
+			Value:       serpent.Int64Of(&quotaCost),
+		},
 		{
 			Flag:        "max-failures",
 			Env:         "CODER_SCALETEST_MAX_FAILURES",
@@ -1145,7 +2541,27 @@ func (r *RootCmd) scaletestCreateWorkspaces() *serpent.Command {
 	tracingFlags.attach(&cmd.Options)
 	strategy.attach(&cmd.Options)
 	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
 	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	retryFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	organizationFlags.attach(&cmd.Options)
+	loadProfileFlags.attach(&cmd.Options, "a workspace for every workspace start build, overriding --count,")
 	return cmd
 }
 
@@ -1162,10 +2578,26 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 		parameterFlags workspaceParameterFlags
 		tracingFlags   = &scaletestTracingFlags{}
 		// This test requires unlimited concurrency
-		timeoutStrategy = &timeoutFlags{}
-		cleanupStrategy = newScaletestCleanupStrategy()
-		output          = &scaletestOutputFlags{}
-		prometheusFlags = &scaletestPrometheusFlags{}
+		timeoutStrategy     = &timeoutFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		prometheusFlags     = &scaletestPrometheusFlags{}
 	)
 
 	cmd := &serpent.Command{
@@ -1178,9 +2610,9 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 				return err
 			}
 
-			notifyCtx, stop := signal.NotifyContext(ctx, StopSignals...) // Checked later.
-			defer stop()
-			ctx = notifyCtx
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
 
 			me, err := RequireAdmin(ctx, client)
 			if err != nil {
@@ -1219,6 +2651,21 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 			if err != nil {
 				return xerrors.Errorf("could not parse --output flags")
 			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
 
 			tpl, err := parseTemplate(ctx, client, me.OrganizationIDs, template)
 			if err != nil {
@@ -1242,11 +2689,10 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 				return xerrors.Errorf("prepare build: %w", err)
 			}
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			reg := prometheus.NewRegistry()
 			metrics := workspaceupdates.NewMetrics(reg)
@@ -1322,7 +2768,7 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 				configs = append(configs, config)
 			}
 
-			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy())
+			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
 			for i, config := range configs {
 				name := fmt.Sprintf("workspaceupdates-%dw", config.WorkspaceCount)
 				id := strconv.Itoa(i)
@@ -1334,18 +2780,12 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = workspaceupdates.NewRunner(runnerClient, config)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: fmt.Sprintf("%s/%s", name, id),
-						runner:   runner,
-					}
-				}
 
 				th.AddRun(name, id, runner)
 			}
 
 			_, _ = fmt.Fprintln(inv.Stderr, "Running workspace updates scaletest...")
+			interrupts.attach(th)
 			testCtx, testCancel := timeoutStrategy.toContext(ctx)
 			defer testCancel()
 			err = th.Run(testCtx)
@@ -1353,33 +2793,54 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
 			}
 
-			// If the command was interrupted, skip stats.
-			if notifyCtx.Err() != nil {
-				return notifyCtx.Err()
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
 			}
-
-			res := th.Results()
 			for _, o := range outputs {
 				err = o.write(res, inv.Stdout)
 				if err != nil {
 					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
 				}
 			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 
 			if !noCleanup {
-				_, _ = fmt.Fprintln(inv.Stderr, "\nCleaning up...")
-				cleanupCtx, cleanupCancel := cleanupStrategy.toContext(ctx)
-				defer cleanupCancel()
-				err = th.Cleanup(cleanupCtx)
+				err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
 				if err != nil {
-					return xerrors.Errorf("cleanup tests: %w", err)
+					return err
 				}
 			}
 
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
 			if res.TotalFail > 0 {
 				return xerrors.New("load test failed, see above for more details")
 			}
 
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	}
@@ -1441,7 +2902,24 @@ func (r *RootCmd) scaletestWorkspaceUpdates() *serpent.Command {
 	tracingFlags.attach(&cmd.Options)
 	timeoutStrategy.attach(&cmd.Options)
 	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
 	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
 	prometheusFlags.attach(&cmd.Options)
 	return cmd
 }
@@ -1454,13 +2932,33 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 		disableDirect     bool
 		app               string
 		workspaceProxyURL string
-
-		targetFlags     = &workspaceTargetFlags{}
-		tracingFlags    = &scaletestTracingFlags{}
-		strategy        = &scaletestStrategyFlags{}
-		cleanupStrategy = newScaletestCleanupStrategy()
-		output          = &scaletestOutputFlags{}
-		prometheusFlags = &scaletestPrometheusFlags{}
+		verify            bool
+		verifyTimeout     time.Duration
+
+		targetFlags         = &workspaceTargetFlags{}
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		retryFlags          = &scaletestRetryFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		prometheusFlags     = &scaletestPrometheusFlags{}
+		loadProfileFlags    = &scaletestLoadProfileFlags{}
 	)
 
 	cmd := &serpent.Command{
@@ -1474,9 +2972,9 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 
 			ctx := inv.Context()
 
-			notifyCtx, stop := signal.NotifyContext(ctx, StopSignals...) // Checked later.
-			defer stop()
-			ctx = notifyCtx
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
 
 			me, err := RequireAdmin(ctx, client)
 			if err != nil {
@@ -1500,7 +2998,7 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 				return xerrors.Errorf("get app host: %w", err)
 			}
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
@@ -1515,14 +3013,36 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 				_, _ = fmt.Fprintf(inv.Stderr, "Waiting %s for prometheus metrics to be scraped\n", prometheusFlags.Wait)
 				<-time.After(prometheusFlags.Wait)
 			}()
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			outputs, err := output.parse()
 			if err != nil {
 				return xerrors.Errorf("could not parse --output flags")
 			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			sessions, err := loadProfileFlags.load(loadprofile.MetricActiveUsers)
+			if err != nil {
+				return err
+			}
+			if sessions > len(workspaces) {
+				_, _ = fmt.Fprintf(inv.Stderr, "Warning: the load profile has %d active users but only %d workspaces are targeted, the remaining active users are not replayed\n", sessions, len(workspaces))
+			}
 
-			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy())
+			th := harness.NewTestHarness(loadProfileFlags.wrapStrategy(strategy.toStrategy()), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), retryFlags.option(), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
 			for idx, ws := range workspaces {
 				var (
 					agent codersdk.WorkspaceAgent
@@ -1580,6 +3100,8 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 					DisableDirect: disableDirect,
 					Echo:          ssh,
 					App:           appConfig,
+					Verify:        verify,
+					VerifyTimeout: verifyTimeout,
 				}
 
 				if webClient != nil {
@@ -1596,18 +3118,13 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = workspacetraffic.NewRunner(runnerClient, config)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: fmt.Sprintf("%s/%s", name, id),
-						runner:   runner,
-					}
-				}
 
-				th.AddRun(name, id, runner)
+				run := th.AddRun(name, id, runner)
+				run.SetTag("template", ws.TemplateName)
 			}
 
 			_, _ = fmt.Fprintln(inv.Stderr, "Running load test...")
+			interrupts.attach(th)
 			testCtx, testCancel := strategy.toContext(ctx)
 			defer testCancel()
 			err = th.Run(testCtx)
@@ -1615,23 +3132,42 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
 			}
 
-			// If the command was interrupted, skip stats.
-			if notifyCtx.Err() != nil {
-				return notifyCtx.Err()
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
 			}
-
-			res := th.Results()
 			for _, o := range outputs {
 				err = o.write(res, inv.Stdout)
 				if err != nil {
 					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
 				}
 			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
 
 			if res.TotalFail > 0 {
 				return xerrors.New("load test failed, see above for more details")
 			}
 
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	}
@@ -1679,6 +3215,19 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 			Description: "URL for workspace proxy to send web traffic to.",
 			Value:       serpent.StringOf(&workspaceProxyURL),
 		},
+		{
+			Flag:        "verify-reachability",
+			Env:         "CODER_SCALETEST_WORKSPACE_TRAFFIC_VERIFY_REACHABILITY",
+			Description: "Wait for the agent of each workspace to be connected and ready, and for its apps to be healthy, before generating traffic. The time it takes is reported separately from the traffic generation in the results.",
+			Value:       serpent.BoolOf(&verify),
+		},
+		{
+			Flag:        "verify-timeout",
+			Env:         "CODER_SCALETEST_WORKSPACE_TRAFFIC_VERIFY_TIMEOUT",
+			Default:     "5m",
+			Description: "How long to wait for the agent of each workspace to be reachable with --verify-reachability.",
+			Value:       serpent.DurationOf(&verifyTimeout),
+		},
 	}
 
 	targetFlags.attach(&cmd.Options)
@@ -1686,23 +3235,58 @@ func (r *RootCmd) scaletestWorkspaceTraffic() *serpent.Command {
 	strategy.attach(&cmd.Options)
 	cleanupStrategy.attach(&cmd.Options)
 	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	retryFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
 	prometheusFlags.attach(&cmd.Options)
+	loadProfileFlags.attach(&cmd.Options, "traffic to a workspace for every active user, skipping the remaining workspaces,")
 
 	return cmd
 }
 
 func (r *RootCmd) scaletestDashboard() *serpent.Command {
 	var (
-		interval        time.Duration
-		jitter          time.Duration
-		headless        bool
-		randSeed        int64
-		targetUsers     string
-		tracingFlags    = &scaletestTracingFlags{}
-		strategy        = &scaletestStrategyFlags{}
-		cleanupStrategy = newScaletestCleanupStrategy()
-		output          = &scaletestOutputFlags{}
-		prometheusFlags = &scaletestPrometheusFlags{}
+		interval            time.Duration
+		jitter              time.Duration
+		headless            bool
+		randSeed            int64
+		targetUsers         string
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		retryFlags          = &scaletestRetryFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		prometheusFlags     = &scaletestPrometheusFlags{}
 	)
 
 	cmd := &serpent.Command{
@@ -1733,11 +3317,25 @@ func (r *RootCmd) scaletestDashboard() *serpent.Command {
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 			outputs, err := output.parse()
 			if err != nil {
 				return xerrors.Errorf("could not parse --output flags")
 			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
 			reg := prometheus.NewRegistry()
 			prometheusSrvClose := ServeHandler(ctx, logger, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), prometheusFlags.Address, "prometheus")
 			defer prometheusSrvClose()
@@ -1756,7 +3354,7 @@ func (r *RootCmd) scaletestDashboard() *serpent.Command {
 
 			metrics := dashboard.NewMetrics(reg)
 
-			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy())
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), retryFlags.option(), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
 
 			users, err := getScaletestUsers(ctx, client)
 			if err != nil {
@@ -1777,7 +3375,7 @@ func (r *RootCmd) scaletestDashboard() *serpent.Command {
 				userTokResp, err := client.CreateToken(ctx, usr.ID.String(), codersdk.CreateTokenRequest{
 					Lifetime:  30 * 24 * time.Hour,
 					Scope:     "",
-					TokenName: fmt.Sprintf("scaletest-%d", time.Now().Unix()),
+					TokenName: fmt.Sprintf("%s-%d", loadtestutil.Prefix(ctx), time.Now().Unix()),
 				})
 				if err != nil {
 					return xerrors.Errorf("create token for user: %w", err)
@@ -1816,36 +3414,56 @@ func (r *RootCmd) scaletestDashboard() *serpent.Command {
 					return err
 				}
 				var runner harness.Runnable = dashboard.NewRunner(userClient, metrics, config)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: name,
-						runner:   runner,
-					}
-				}
 				th.AddRun("dashboard", name, runner)
 			}
 
 			_, _ = fmt.Fprintln(inv.Stderr, "Running load test...")
-			testCtx, testCancel := strategy.toContext(ctx)
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr)
+			defer interrupts.stop()
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(interrupts.ctx)
 			defer testCancel()
 			err = th.Run(testCtx)
 			if err != nil {
 				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
 			}
 
-			res := th.Results()
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
 			for _, o := range outputs {
 				err = o.write(res, inv.Stdout)
 				if err != nil {
 					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
 				}
 			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
 
 			if res.TotalFail > 0 {
 				return xerrors.New("load test failed, see above for more details")
 			}
 
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	}
@@ -1891,6 +3509,23 @@ func (r *RootCmd) scaletestDashboard() *serpent.Command {
 	strategy.attach(&cmd.Options)
 	cleanupStrategy.attach(&cmd.Options)
 	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	retryFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
 	prometheusFlags.attach(&cmd.Options)
 
 	return cmd
@@ -1906,6 +3541,7 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 		workspaceJobTimeout   time.Duration
 		autostartBuildTimeout time.Duration
 		autostartDelay        time.Duration
+		scheduleSpread        time.Duration
 		template              string
 		noCleanup             bool
 
@@ -1938,6 +3574,12 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 			if workspaceCount <= 0 {
 				return xerrors.Errorf("--workspace-count must be greater than zero")
 			}
+			if scheduleSpread < 0 {
+				return xerrors.Errorf("--schedule-spread must not be negative")
+			}
+			// Autostart schedules have minute granularity, so workspaces are
+			// assigned to one of the minutes in the spread window.
+			scheduleSlots := max(int64(scheduleSpread/time.Minute), 1)
 
 			outputs, err := output.parse()
 			if err != nil {
@@ -1966,11 +3608,10 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 				return xerrors.Errorf("prepare build: %w", err)
 			}
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			setupBarrier := new(sync.WaitGroup)
 			setupBarrier.Add(int(workspaceCount))
@@ -1989,7 +3630,7 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 			resultSink := make(chan autostart.RunResult, workspaceCount)
 			for i := range workspaceCount {
 				id := strconv.Itoa(int(i))
-				workspaceNames = append(workspaceNames, loadtestutil.GenerateDeterministicWorkspaceName(id))
+				workspaceNames = append(workspaceNames, loadtestutil.GenerateDeterministicWorkspaceName(ctx, id))
 			}
 			dispatcher := autostart.NewWorkspaceDispatcher(workspaceNames)
 
@@ -2003,9 +3644,13 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 			// close all workspace channels when the build updates channel closes.
 			dispatcher.Start(ctx, decoder.Chan())
 
-			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy())
+			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider))
 			for workspaceName, buildUpdatesChannel := range dispatcher.Channels {
-				id := strings.TrimPrefix(workspaceName, loadtestutil.ScaleTestPrefix+"-")
+				id := strings.TrimPrefix(workspaceName, loadtestutil.Prefix(ctx)+"-")
+				index, err := strconv.ParseInt(id, 10, 64)
+				if err != nil {
+					return xerrors.Errorf("parse workspace index from %q: %w", workspaceName, err)
+				}
 
 				config := autostart.Config{
 					User: createusers.Config{
@@ -2023,6 +3668,7 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 					WorkspaceJobTimeout:   workspaceJobTimeout,
 					AutostartBuildTimeout: autostartBuildTimeout,
 					AutostartDelay:        autostartDelay,
+					ScheduleOffset:        time.Duration(index%scheduleSlots) * time.Minute,
 					SetupBarrier:          setupBarrier,
 					BuildUpdates:          buildUpdatesChannel,
 					ResultSink:            resultSink,
@@ -2037,13 +3683,6 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = autostart.NewRunner(runnerClient, config)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: fmt.Sprintf("%s/%s", autostartTestName, id),
-						runner:   runner,
-					}
-				}
 				th.AddRun(autostartTestName, id, runner)
 			}
 
@@ -2086,12 +3725,9 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 			}
 
 			if !noCleanup {
-				_, _ = fmt.Fprintln(inv.Stderr, "\nCleaning up...")
-				cleanupCtx, cleanupCancel := cleanupStrategy.toContext(context.Background())
-				defer cleanupCancel()
-				err = th.Cleanup(cleanupCtx)
+				err = cleanupStrategy.runCleanup(context.Background(), th, inv.Stderr)
 				if err != nil {
-					return xerrors.Errorf("cleanup tests: %w", err)
+					return err
 				}
 				_, _ = fmt.Fprintln(inv.Stderr, "Cleanup complete")
 			} else {
@@ -2132,6 +3768,13 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 			Description: "How long after all the workspaces have been stopped to schedule them to be started again.",
 			Value:       serpent.DurationOf(&autostartDelay),
 		},
+		{
+			Flag:        "schedule-spread",
+			Env:         "CODER_SCALETEST_AUTOSTART_SCHEDULE_SPREAD",
+			Default:     "0s",
+			Description: "Spread the autostart schedules of the workspaces evenly across this many minutes, so that they overlap rather than all falling on the same minute. Zero schedules every workspace for the same minute.",
+			Value:       serpent.DurationOf(&scheduleSpread),
+		},
 		{
 			Flag:          "template",
 			FlagShorthand: "t",
@@ -2153,70 +3796,10 @@ func (r *RootCmd) scaletestAutostart() *serpent.Command {
 	output.attach(&cmd.Options)
 	timeoutStrategy.attach(&cmd.Options)
 	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
 	return cmd
 }
 
-type runnableTraceWrapper struct {
-	tracer   trace.Tracer
-	spanName string
-	runner   harness.Runnable
-
-	span trace.Span
-}
-
-var (
-	_ harness.Runnable    = &runnableTraceWrapper{}
-	_ harness.Cleanable   = &runnableTraceWrapper{}
-	_ harness.Collectable = &runnableTraceWrapper{}
-)
-
-func (r *runnableTraceWrapper) Run(ctx context.Context, id string, logs io.Writer) error {
-	ctx, span := r.tracer.Start(ctx, r.spanName, trace.WithNewRoot())
-	defer span.End()
-	r.span = span
-
-	traceID := "unknown trace ID"
-	spanID := "unknown span ID"
-	if span.SpanContext().HasTraceID() {
-		traceID = span.SpanContext().TraceID().String()
-	}
-	if span.SpanContext().HasSpanID() {
-		spanID = span.SpanContext().SpanID().String()
-	}
-	_, _ = fmt.Fprintf(logs, "Trace ID: %s\n", traceID)
-	_, _ = fmt.Fprintf(logs, "Span ID: %s\n\n", spanID)
-
-	// Make a separate span for the run itself so the sub-spans are grouped
-	// neatly. The cleanup span is also a child of the above span so this is
-	// important for readability.
-	ctx2, span2 := r.tracer.Start(ctx, r.spanName+" run")
-	defer span2.End()
-	return r.runner.Run(ctx2, id, logs)
-}
-
-func (r *runnableTraceWrapper) Cleanup(ctx context.Context, id string, logs io.Writer) error {
-	c, ok := r.runner.(harness.Cleanable)
-	if !ok {
-		return nil
-	}
-
-	if r.span != nil {
-		ctx = trace.ContextWithSpanContext(ctx, r.span.SpanContext())
-	}
-	ctx, span := r.tracer.Start(ctx, r.spanName+" cleanup")
-	defer span.End()
-
-	return c.Cleanup(ctx, id, logs)
-}
-
-func (r *runnableTraceWrapper) GetMetrics() map[string]any {
-	c, ok := r.runner.(harness.Collectable)
-	if !ok {
-		return nil
-	}
-	return c.GetMetrics()
-}
-
 func getScaletestWorkspaces(ctx context.Context, client *codersdk.Client, owner, template string) ([]codersdk.Workspace, int, error) {
 	var (
 		pageNumber = 0
@@ -2238,7 +3821,7 @@ func getScaletestWorkspaces(ctx context.Context, client *codersdk.Client, owner,
 
 	for {
 		page, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
-			Name:     "scaletest-",
+			Name:     loadtestutil.Prefix(ctx) + "-",
 			Template: template,
 			Owner:    owner,
 			Offset:   pageNumber * limit,
@@ -2255,7 +3838,7 @@ func getScaletestWorkspaces(ctx context.Context, client *codersdk.Client, owner,
 
 		pageWorkspaces := make([]codersdk.Workspace, 0, len(page.Workspaces))
 		for _, w := range page.Workspaces {
-			if !loadtestutil.IsScaleTestWorkspace(w.Name, w.OwnerName) {
+			if !loadtestutil.IsScaleTestWorkspace(ctx, w.Name, w.OwnerName) {
 				continue
 			}
 			if noOwnerAccess && w.OwnerID != me.ID {
@@ -2278,7 +3861,7 @@ func getScaletestUsers(ctx context.Context, client *codersdk.Client) ([]codersdk
 
 	for {
 		page, err := client.Users(ctx, codersdk.UsersRequest{
-			Search: "scaletest-",
+			Search: loadtestutil.Prefix(ctx) + "-",
 			Pagination: codersdk.Pagination{
 				Offset: pageNumber * limit,
 				Limit:  limit,
@@ -2295,7 +3878,7 @@ func getScaletestUsers(ctx context.Context, client *codersdk.Client) ([]codersdk
 
 		pageUsers := make([]codersdk.User, 0, len(page.Users))
 		for _, u := range page.Users {
-			if loadtestutil.IsScaleTestUser(u.Username, u.Email) {
+			if loadtestutil.IsScaleTestUser(ctx, u.Username, u.Email) {
 				pageUsers = append(pageUsers, u)
 			}
 		}
@@ -2305,6 +3888,26 @@ func getScaletestUsers(ctx context.Context, client *codersdk.Client) ([]codersdk
 	return users, nil
 }
 
+// getScaletestTokens returns the API tokens of every user that were created for
+// scale testing, or that are owned by scaletest users.
+func getScaletestTokens(ctx context.Context, client *codersdk.Client) ([]codersdk.APIKeyWithOwner, error) {
+	keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{
+		IncludeAll:     true,
+		IncludeExpired: true,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("fetch tokens: %w", err)
+	}
+
+	tokens := make([]codersdk.APIKeyWithOwner, 0, len(keys))
+	for _, k := range keys {
+		if loadtestutil.IsScaleTestToken(ctx, k.TokenName, k.Username) {
+			tokens = append(tokens, k)
+		}
+	}
+	return tokens, nil
+}
+
 func parseTemplate(ctx context.Context, client *codersdk.Client, organizationIDs []uuid.UUID, template string) (tpl codersdk.Template, err error) {
 	if id, err := uuid.Parse(template); err == nil && id != uuid.Nil {
 		tpl, err = client.Template(ctx, id)
@@ -0,0 +1,436 @@
+//go:build !slim
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/apireplay"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/serpent"
+)
+
+const apiReplayTestName = "api-replay"
+
+// Variants of the API replay test in A/B mode, stored in the run tag
+// apiReplayVariantTag.
+const (
+	apiReplayVariantTag       = "variant"
+	apiReplayVariantBaseline  = "baseline"
+	apiReplayVariantCandidate = "candidate"
+)
+
+// apiReplayVariant is a variant of the replayed traffic. The zero value
+// replays the routes against the deployment of the client.
+type apiReplayVariant struct {
+	name   string
+	routes []apireplay.Route
+	// url overrides the URL of the deployment, if set.
+	url *url.URL
+}
+
+func (r *RootCmd) scaletestAPIReplay() *serpent.Command {
+	var (
+		harFile             string
+		routesFile          string
+		interval            time.Duration
+		jitter              time.Duration
+		targetUsers         string
+		abCandidateURL      url.URL
+		abRewrites          []string
+		abReport            string
+		abThresholds        harness.CompareThresholds
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		prometheusFlags     = &scaletestPrometheusFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "api-replay",
+		Short: "Replay recorded API traffic against coderd as the scaletest users.",
+		Long: `Each scaletest user repeatedly requests API routes picked at random according to their weights, to simulate the read-heavy load of the dashboard without a browser.
+The routes are taken from a HAR recording, e.g. exported from the browser's developer tools while using the dashboard, or from a JSON list of routes in the format [{"method": "GET", "path": "/api/v2/workspaces", "weight": 5}]. Only GET and HEAD requests to the API are replayed.
+With --ab-candidate-url or --ab-candidate-rewrite, every user replays the same traffic against a baseline and a candidate variant simultaneously, e.g. a stable and an experimental route, and a side-by-side comparison of both variants is printed after the results.`,
+		Handler: func(inv *serpent.Invocation) error {
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			if (harFile == "") == (routesFile == "") {
+				return xerrors.Errorf("exactly one of --har or --routes must be set")
+			}
+			targetUserStart, targetUserEnd, err := parseTargetRange("users", targetUsers)
+			if err != nil {
+				return xerrors.Errorf("parse target users: %w", err)
+			}
+
+			routes, err := readAPIReplayRoutes(harFile, routesFile)
+			if err != nil {
+				return err
+			}
+			if len(routes) == 0 {
+				return xerrors.New("no API routes to replay were found")
+			}
+			variants := []apiReplayVariant{{routes: routes}}
+			abMode := abCandidateURL.String() != "" || len(abRewrites) > 0
+			if abMode {
+				if err := abThresholds.Validate(); err != nil {
+					return xerrors.Errorf("invalid A/B thresholds: %w", err)
+				}
+				rewrites := make([]apireplay.PathRewrite, 0, len(abRewrites))
+				for _, s := range abRewrites {
+					rewrite, err := apireplay.ParsePathRewrite(s)
+					if err != nil {
+						return xerrors.Errorf("parse --ab-candidate-rewrite: %w", err)
+					}
+					rewrites = append(rewrites, rewrite)
+				}
+				candidate := apiReplayVariant{
+					name:   apiReplayVariantCandidate,
+					routes: apireplay.RewriteRoutes(routes, rewrites),
+				}
+				if abCandidateURL.String() != "" {
+					candidate.url = &abCandidateURL
+				}
+				variants = []apiReplayVariant{{name: apiReplayVariantBaseline, routes: routes}, candidate}
+			}
+
+			ctx := inv.Context()
+			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+			reg := prometheus.NewRegistry()
+			prometheusSrvClose := ServeHandler(ctx, inv.Logger, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), prometheusFlags.Address, "prometheus")
+			defer prometheusSrvClose()
+
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+				// Wait for prometheus metrics to be scraped
+				_, _ = fmt.Fprintf(inv.Stderr, "Waiting %s for prometheus metrics to be scraped\n", prometheusFlags.Wait)
+				<-time.After(prometheusFlags.Wait)
+			}()
+
+			metrics := apireplay.NewMetrics(reg)
+
+			// Replaying only reads, so there is nothing to clean up.
+			th := harness.NewTestHarness(strategy.toStrategy(), harness.ConcurrentExecutionStrategy{}, harness.WithTracerProvider(tracerProvider), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+
+			users, err := getScaletestUsers(ctx, client)
+			if err != nil {
+				return xerrors.Errorf("get scaletest users")
+			}
+			if targetUserEnd == 0 {
+				targetUserEnd = len(users)
+			}
+
+			for idx, usr := range users {
+				if idx < targetUserStart || idx >= targetUserEnd {
+					continue
+				}
+
+				userTokResp, err := client.CreateToken(ctx, usr.ID.String(), codersdk.CreateTokenRequest{
+					Lifetime:  30 * 24 * time.Hour,
+					Scope:     "",
+					TokenName: fmt.Sprintf("%s-%d", loadtestutil.Prefix(ctx), time.Now().Unix()),
+				})
+				if err != nil {
+					return xerrors.Errorf("create token for user: %w", err)
+				}
+
+				for _, variant := range variants {
+					// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+					// requests being unbalanced among Coder instances.
+					userClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+					if err != nil {
+						return xerrors.Errorf("create runner client: %w", err)
+					}
+					codersdk.WithSessionToken(userTokResp.Key)(userClient)
+					userClient.Trace = tracingEnabled
+					if variant.url != nil {
+						userClient.URL = variant.url
+					}
+
+					config := apireplay.Config{
+						Routes:   variant.routes,
+						Interval: interval,
+						Jitter:   jitter,
+						Metrics:  metrics,
+					}
+					if variant.name != "" {
+						config.Metrics = apireplay.WithRoutePrefix(metrics, variant.name+": ")
+					}
+					if err := config.Validate(); err != nil {
+						return xerrors.Errorf("validate config: %w", err)
+					}
+					var runner harness.Runnable = apireplay.NewRunner(userClient, config)
+					id := usr.Username
+					if variant.name != "" {
+						id += "/" + variant.name
+					}
+					run := th.AddRun(apiReplayTestName, id, runner)
+					if variant.name != "" {
+						run.SetTag(apiReplayVariantTag, variant.name)
+					}
+				}
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Replaying %d API routes...\n", len(routes))
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr)
+			defer interrupts.stop()
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(interrupts.ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if abMode {
+				err = compareAPIReplayVariants(inv.Stderr, res, abThresholds, abReport)
+				if err != nil {
+					return err
+				}
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = []serpent.Option{
+		{
+			Flag:        "har",
+			Env:         "CODER_SCALETEST_API_REPLAY_HAR",
+			Description: "Path to a HAR recording to take the API routes to replay from. Routes are weighted by how often they were requested.",
+			Value:       serpent.StringOf(&harFile),
+		},
+		{
+			Flag:        "routes",
+			Env:         "CODER_SCALETEST_API_REPLAY_ROUTES",
+			Description: "Path to a JSON list of API routes to replay, with their weights.",
+			Value:       serpent.StringOf(&routesFile),
+		},
+		{
+			Flag:        "target-users",
+			Env:         "CODER_SCALETEST_API_REPLAY_TARGET_USERS",
+			Description: "Target a specific range of users in the format [START]:[END] (exclusive). Example: 0:10 will target the 10 first alphabetically sorted users (0-9).",
+			Value:       serpent.StringOf(&targetUsers),
+		},
+		{
+			Flag:        "ab-candidate-url",
+			Env:         "CODER_SCALETEST_API_REPLAY_AB_CANDIDATE_URL",
+			Description: "Replay the traffic against this URL as the candidate variant of an A/B test, in addition to the deployment itself as the baseline. The candidate must share the database of the deployment, e.g. a replica running a different version, so that the tokens of the users are valid.",
+			Value:       serpent.URLOf(&abCandidateURL),
+		},
+		{
+			Flag:        "ab-candidate-rewrite",
+			Env:         "CODER_SCALETEST_API_REPLAY_AB_CANDIDATE_REWRITES",
+			Description: "Rewrite the paths of the routes of the candidate variant of an A/B test starting with OLD to start with NEW instead, in the format OLD=NEW. E.g. /api/v2/workspaces=/api/experimental/workspaces compares an experimental route to the stable one.",
+			Value:       serpent.StringArrayOf(&abRewrites),
+		},
+		{
+			Flag:        "ab-report",
+			Env:         "CODER_SCALETEST_API_REPLAY_AB_REPORT",
+			Description: "Path to write the comparison of the variants of an A/B test to as JSON.",
+			Value:       serpent.StringOf(&abReport),
+		},
+		{
+			Flag:        "ab-max-throughput-decrease",
+			Env:         "CODER_SCALETEST_API_REPLAY_AB_MAX_THROUGHPUT_DECREASE",
+			Description: "Report a regression of the candidate variant of an A/B test if the number of successful runs per second decreased by more than this percentage of the baseline. 0 disables the threshold.",
+			Default:     "10",
+			Value:       serpent.Float64Of(&abThresholds.MaxThroughputDecrease),
+		},
+		{
+			Flag:        "ab-max-error-rate-increase",
+			Env:         "CODER_SCALETEST_API_REPLAY_AB_MAX_ERROR_RATE_INCREASE",
+			Description: "Report a regression of the candidate variant of an A/B test if the percentage of failed runs increased by more than this many percentage points. 0 disables the threshold.",
+			Default:     "1",
+			Value:       serpent.Float64Of(&abThresholds.MaxErrorRateIncrease),
+		},
+		{
+			Flag:        "ab-max-latency-increase",
+			Env:         "CODER_SCALETEST_API_REPLAY_AB_MAX_LATENCY_INCREASE",
+			Description: "Report a regression of the candidate variant of an A/B test if any latency percentile increased by more than this percentage of the baseline. 0 disables the threshold.",
+			Default:     "20",
+			Value:       serpent.Float64Of(&abThresholds.MaxLatencyIncrease),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_API_REPLAY_INTERVAL",
+			Default:     "1s",
+			Description: "Interval between requests of each user.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "jitter",
+			Env:         "CODER_SCALETEST_API_REPLAY_JITTER",
+			Default:     "500ms",
+			Description: "Jitter between requests of each user.",
+			Value:       serpent.DurationOf(&jitter),
+		},
+	}
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	prometheusFlags.attach(&cmd.Options)
+
+	return cmd
+}
+
+// readAPIReplayRoutes reads the routes to replay from either a HAR recording or
+// a JSON list of routes.
+func readAPIReplayRoutes(harFile, routesFile string) ([]apireplay.Route, error) {
+	path, parse := harFile, apireplay.ParseHAR
+	if routesFile != "" {
+		path, parse = routesFile, apireplay.ParseRoutes
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	routes, err := parse(f)
+	if err != nil {
+		return nil, xerrors.Errorf("read routes from %q: %w", path, err)
+	}
+	return routes, nil
+}
+
+// compareAPIReplayVariants prints a side-by-side comparison of the baseline and
+// candidate variants of an A/B test, writes it to reportPath as JSON if set, and
+// returns an error if the candidate regressed.
+func compareAPIReplayVariants(w io.Writer, res harness.Results, thresholds harness.CompareThresholds, reportPath string) error {
+	comparison, err := harness.CompareVariants(res, apiReplayVariantTag, apiReplayVariantBaseline, apiReplayVariantCandidate, thresholds)
+	if err != nil {
+		return xerrors.Errorf("compare variants: %w", err)
+	}
+	_, _ = fmt.Fprintf(w, "\nA/B comparison (%s vs. %s):\n\n", apiReplayVariantBaseline, apiReplayVariantCandidate)
+	comparison.PrintText(w)
+
+	if reportPath != "" {
+		f, err := os.Create(reportPath)
+		if err != nil {
+			return xerrors.Errorf("create A/B report: %w", err)
+		}
+		defer f.Close()
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(comparison); err != nil {
+			return xerrors.Errorf("write A/B report: %w", err)
+		}
+	}
+
+	if regressions := comparison.Regressions(); len(regressions) > 0 {
+		return xerrors.Errorf("candidate variant regressed, %d threshold(s) exceeded", len(regressions))
+	}
+	return nil
+}
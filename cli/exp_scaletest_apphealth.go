@@ -0,0 +1,299 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/apphealth"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/serpent"
+)
+
+const appHealthTestName = "app-health"
+
+func (r *RootCmd) scaletestAppHealth() *serpent.Command {
+	var (
+		count                     int64
+		apps                      int64
+		reports                   int64
+		interval                  time.Duration
+		flipRatio                 float64
+		templateVersionJobTimeout time.Duration
+		workspaceBuildTimeout     time.Duration
+		propagationTimeout        time.Duration
+		provisionerType           string
+		provisionerTags           []string
+
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "app-health",
+		Short: "Load test app health reporting by acting as an agent with many health checked apps",
+		Long: `Each runner creates a template with an external agent that defines a number of coder_apps with health checks and builds a workspace from it. It then acts as the agent, first reporting every app as healthy and then repeatedly flipping the health of a fraction of the apps.
+The time coderd takes to accept every report and the time until the new health of all apps is visible on the workspace are reported as runner metrics, along with the number of app health updates written per second.`,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if count <= 0 {
+				return xerrors.Errorf("--count must be greater than 0")
+			}
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			tags, err := ParseProvisionerTags(provisionerTags)
+			if err != nil {
+				return err
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for i := range count {
+				id := strconv.Itoa(int(i))
+				config := apphealth.Config{
+					OrganizationID:            me.OrganizationIDs[0],
+					ProvisionerType:           codersdk.ProvisionerType(provisionerType),
+					ProvisionerTags:           tags,
+					Apps:                      int(apps),
+					Reports:                   int(reports),
+					Interval:                  interval,
+					FlipRatio:                 flipRatio,
+					TemplateVersionJobTimeout: templateVersionJobTimeout,
+					WorkspaceBuildTimeout:     workspaceBuildTimeout,
+					PropagationTimeout:        propagationTimeout,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+				var runner harness.Runnable = apphealth.NewRunner(runnerClient, config)
+				th.AddRun(appHealthTestName, id, runner)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running app health scaletest...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_COUNT",
+			Default:       "1",
+			Description:   "Number of workspaces to create, each with its own template and agent reporting app health.",
+			Value:         serpent.Int64Of(&count),
+		},
+		{
+			Flag:        "apps",
+			Env:         "CODER_SCALETEST_APP_HEALTH_APPS",
+			Default:     "50",
+			Description: "Number of apps with health checks defined on each workspace agent.",
+			Value:       serpent.Int64Of(&apps),
+		},
+		{
+			Flag:        "reports",
+			Env:         "CODER_SCALETEST_APP_HEALTH_REPORTS",
+			Default:     "20",
+			Description: "Number of app health reports each agent sends after initially reporting all apps as healthy.",
+			Value:       serpent.Int64Of(&reports),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_APP_HEALTH_INTERVAL",
+			Default:     "5s",
+			Description: "Time to wait between app health reports.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "flip-ratio",
+			Env:         "CODER_SCALETEST_APP_HEALTH_FLIP_RATIO",
+			Default:     "0.1",
+			Description: "Fraction of apps, between 0 and 1, whose health changes with every report. At least one app changes per report.",
+			Value:       serpent.Float64Of(&flipRatio),
+		},
+		{
+			Flag:        "template-version-job-timeout",
+			Env:         "CODER_SCALETEST_APP_HEALTH_TEMPLATE_VERSION_JOB_TIMEOUT",
+			Default:     "5m",
+			Description: "Timeout for template version import jobs.",
+			Value:       serpent.DurationOf(&templateVersionJobTimeout),
+		},
+		{
+			Flag:        "workspace-build-timeout",
+			Env:         "CODER_SCALETEST_APP_HEALTH_WORKSPACE_BUILD_TIMEOUT",
+			Default:     "10m",
+			Description: "Timeout for workspace builds.",
+			Value:       serpent.DurationOf(&workspaceBuildTimeout),
+		},
+		{
+			Flag:        "propagation-timeout",
+			Env:         "CODER_SCALETEST_APP_HEALTH_PROPAGATION_TIMEOUT",
+			Default:     "30s",
+			Description: "Timeout for a report to be visible on the workspace through the API.",
+			Value:       serpent.DurationOf(&propagationTimeout),
+		},
+		{
+			Flag:        "provisioner-type",
+			Env:         "CODER_SCALETEST_APP_HEALTH_PROVISIONER_TYPE",
+			Default:     string(codersdk.ProvisionerTypeTerraform),
+			Description: "Provisioner to import the template version and build the workspace with. The echo provisioner skips Terraform entirely.",
+			Value:       serpent.EnumOf(&provisionerType, string(codersdk.ProvisionerTypeTerraform), string(codersdk.ProvisionerTypeEcho)),
+		},
+		{
+			Flag:        "provisioner-tag",
+			Description: "Specify a set of tags to target provisioner daemons.",
+			Value:       serpent.StringArrayOf(&provisionerTags),
+		},
+	}
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,272 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/auditlog"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/serpent"
+)
+
+const auditLogTestName = "audit-log"
+
+// defaultAuditLogFilters resemble the queries of a compliance dashboard: the
+// unfiltered log, and the logs narrowed down by resource type and action.
+var defaultAuditLogFilters = []string{
+	"",
+	"resource_type:workspace",
+	"resource_type:workspace_build",
+	"resource_type:template action:write",
+	"action:login",
+	"action:delete",
+}
+
+func (r *RootCmd) scaletestAuditLog() *serpent.Command {
+	var (
+		count               int64
+		filters             []string
+		pageSize            int64
+		pages               int64
+		interval            time.Duration
+		jitter              time.Duration
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		prometheusFlags     = &scaletestPrometheusFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "audit-log",
+		Short: "Generate paginated, filtered audit log queries against coderd.",
+		Long: `Each runner repeatedly picks one of the filters at random and walks the pages of the matching audit logs, to simulate the load of a compliance dashboard.
+This can be used to validate audit log retention and indexing choices on a deployment with a realistic amount of audit logs. The time taken by every page is reported as runner metrics.`,
+		Handler: func(inv *serpent.Invocation) error {
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			if count <= 0 {
+				return xerrors.Errorf("--count must be greater than 0")
+			}
+			if len(filters) == 0 {
+				filters = defaultAuditLogFilters
+			}
+
+			ctx := inv.Context()
+			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+			reg := prometheus.NewRegistry()
+			prometheusSrvClose := ServeHandler(ctx, inv.Logger, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), prometheusFlags.Address, "prometheus")
+			defer prometheusSrvClose()
+
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+				// Wait for prometheus metrics to be scraped
+				_, _ = fmt.Fprintf(inv.Stderr, "Waiting %s for prometheus metrics to be scraped\n", prometheusFlags.Wait)
+				<-time.After(prometheusFlags.Wait)
+			}()
+
+			metrics := auditlog.NewMetrics(reg)
+
+			// Querying the audit log only reads, so there is nothing to clean up.
+			th := harness.NewTestHarness(strategy.toStrategy(), harness.ConcurrentExecutionStrategy{}, harness.WithTracerProvider(tracerProvider), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+
+			if _, err := RequireAdmin(ctx, client); err != nil {
+				return err
+			}
+			client.Trace = tracingEnabled
+
+			for i := range count {
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+
+				config := auditlog.Config{
+					Filters:  filters,
+					PageSize: int(pageSize),
+					Pages:    int(pages),
+					Interval: interval,
+					Jitter:   jitter,
+					Metrics:  metrics,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+				var runner harness.Runnable = auditlog.NewRunner(runnerClient, config)
+				th.AddRun(auditLogTestName, strconv.Itoa(int(i)), runner)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Querying audit logs with %d filters...\n", len(filters))
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr)
+			defer interrupts.stop()
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(interrupts.ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = []serpent.Option{
+		{
+			Flag:          "count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_AUDIT_LOG_COUNT",
+			Default:       "10",
+			Description:   "Number of concurrent audit log readers.",
+			Value:         serpent.Int64Of(&count),
+		},
+		{
+			Flag:        "filter",
+			Env:         "CODER_SCALETEST_AUDIT_LOG_FILTERS",
+			Description: "Audit log search query to run, e.g. \"resource_type:workspace action:create\". Can be specified multiple times. Defaults to a mix of unfiltered, resource type and action queries.",
+			Value:       serpent.StringArrayOf(&filters),
+		},
+		{
+			Flag:        "page-size",
+			Env:         "CODER_SCALETEST_AUDIT_LOG_PAGE_SIZE",
+			Default:     "25",
+			Description: fmt.Sprintf("Number of audit logs per page, at most %d.", auditlog.MaxPageSize),
+			Value:       serpent.Int64Of(&pageSize),
+		},
+		{
+			Flag:        "pages",
+			Env:         "CODER_SCALETEST_AUDIT_LOG_PAGES",
+			Default:     "5",
+			Description: "Maximum number of pages walked per query.",
+			Value:       serpent.Int64Of(&pages),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_AUDIT_LOG_INTERVAL",
+			Default:     "1s",
+			Description: "Interval between queries of each reader.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "jitter",
+			Env:         "CODER_SCALETEST_AUDIT_LOG_JITTER",
+			Default:     "500ms",
+			Description: "Jitter between queries of each reader.",
+			Value:       serpent.DurationOf(&jitter),
+		},
+	}
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	prometheusFlags.attach(&cmd.Options)
+
+	return cmd
+}
@@ -105,8 +105,6 @@ func (r *RootCmd) scaletestChat() *serpent.Command {
 				prometheusSrvClose()
 			}()
 
-			tracer := tracerProvider.Tracer(scaletestTracerName)
-
 			var turnStartReadyWaitGroup *sync.WaitGroup
 			var startTurnsChan chan struct{}
 			if turnStartDelay > 0 && turns > 1 {
@@ -117,6 +115,7 @@ func (r *RootCmd) scaletestChat() *serpent.Command {
 			chatHarness := harness.NewTestHarness(
 				timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}),
 				cleanupStrategy.toStrategy(),
+				harness.WithTracerProvider(tracerProvider),
 			)
 			for workspaceIndex, targetWorkspace := range workspaces {
 				for chatIndex := int64(0); chatIndex < chatsPerWorkspace; chatIndex++ {
@@ -144,13 +143,6 @@ func (r *RootCmd) scaletestChat() *serpent.Command {
 						return xerrors.Errorf("duplicate client for workspace %d chat %d: %w", workspaceIndex, chatIndex, err)
 					}
 					var runner harness.Runnable = chat.NewRunner(runnerClient, cfg)
-					if tracingEnabled {
-						runner = &runnableTraceWrapper{
-							tracer:   tracer,
-							runner:   runner,
-							spanName: fmt.Sprintf("chat/workspace-%d-chat-%d", workspaceIndex, chatIndex),
-						}
-					}
 					chatHarness.AddRun("chat", fmt.Sprintf("workspace-%d-chat-%d", workspaceIndex, chatIndex), runner)
 				}
 			}
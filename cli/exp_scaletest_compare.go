@@ -0,0 +1,102 @@
+//go:build !slim
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/serpent"
+)
+
+func (*RootCmd) scaletestCompare() *serpent.Command {
+	var (
+		format     string
+		thresholds harness.CompareThresholds
+	)
+
+	cmd := &serpent.Command{
+		Use:   "compare <baseline.json> <candidate.json>",
+		Short: "Compare the results of two scaletests and detect regressions",
+		Long: `Compares the throughput, error rate and latency percentiles of a candidate test to a baseline test, using the JSON results written with --output json.
+Exits with a non-zero status if any change exceeds its regression threshold.`,
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if err := thresholds.Validate(); err != nil {
+				return xerrors.Errorf("invalid thresholds: %w", err)
+			}
+
+			baseline, err := readScaletestResults(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("read baseline: %w", err)
+			}
+			candidate, err := readScaletestResults(inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("read candidate: %w", err)
+			}
+
+			comparison := harness.Compare(baseline, candidate, thresholds)
+			switch format {
+			case "json":
+				enc := json.NewEncoder(inv.Stdout)
+				enc.SetIndent("", "\t")
+				if err := enc.Encode(comparison); err != nil {
+					return xerrors.Errorf("encode comparison: %w", err)
+				}
+			default:
+				comparison.PrintText(inv.Stdout)
+			}
+
+			if regressions := comparison.Regressions(); len(regressions) > 0 {
+				return xerrors.Errorf("candidate regressed, %d threshold(s) exceeded", len(regressions))
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "output",
+			Env:         "CODER_SCALETEST_COMPARE_OUTPUT",
+			Description: "Output format of the comparison.",
+			Default:     "text",
+			Value:       serpent.EnumOf(&format, "text", "json"),
+		},
+		{
+			Flag:        "max-throughput-decrease",
+			Env:         "CODER_SCALETEST_COMPARE_MAX_THROUGHPUT_DECREASE",
+			Description: "Report a regression if the number of successful runs per second decreased by more than this percentage of the baseline. 0 disables the threshold.",
+			Default:     "10",
+			Value:       serpent.Float64Of(&thresholds.MaxThroughputDecrease),
+		},
+		{
+			Flag:        "max-error-rate-increase",
+			Env:         "CODER_SCALETEST_COMPARE_MAX_ERROR_RATE_INCREASE",
+			Description: "Report a regression if the percentage of failed runs increased by more than this many percentage points. 0 disables the threshold.",
+			Default:     "1",
+			Value:       serpent.Float64Of(&thresholds.MaxErrorRateIncrease),
+		},
+		{
+			Flag:        "max-latency-increase",
+			Env:         "CODER_SCALETEST_COMPARE_MAX_LATENCY_INCREASE",
+			Description: "Report a regression if any latency percentile of any test increased by more than this percentage of the baseline. 0 disables the threshold.",
+			Default:     "20",
+			Value:       serpent.Float64Of(&thresholds.MaxLatencyIncrease),
+		},
+	}
+	return cmd
+}
+
+func readScaletestResults(path string) (harness.Results, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return harness.Results{}, xerrors.Errorf("open %q: %w", path, err)
+	}
+	defer f.Close()
+	return harness.ReadResults(f)
+}
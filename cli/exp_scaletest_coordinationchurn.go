@@ -0,0 +1,292 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/coordinationchurn"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+	"github.com/coder/serpent"
+)
+
+const coordinationChurnTestName = "coordination-churn"
+
+func (r *RootCmd) scaletestCoordinationChurn() *serpent.Command {
+	var (
+		count               int64
+		template            string
+		clients             int64
+		cycles              int64
+		interval            time.Duration
+		coordinationTimeout time.Duration
+
+		parameterFlags      workspaceParameterFlags
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "coordination-churn",
+		Short: "Stress the tailnet coordinator by repeatedly connecting and disconnecting agent and client coordinator sessions",
+		Long: `Each runner creates a workspace from a template with an external agent, then repeatedly connects a coordinator session as the agent and a number of client coordinator sessions to it, and disconnects them again. No traffic is sent between the peers.
+The time for each client to receive the agent's node, for the agent to receive the nodes of all clients and for the agent to learn that all clients disconnected are reported as runner metrics.`,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if count <= 0 {
+				return xerrors.Errorf("--count must be greater than 0")
+			}
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			tpl, err := parseTemplate(ctx, client, me.OrganizationIDs, template)
+			if err != nil {
+				return xerrors.Errorf("parse template: %w", err)
+			}
+
+			cliRichParameters, err := asWorkspaceBuildParameters(parameterFlags.richParameters)
+			if err != nil {
+				return xerrors.Errorf("can't parse given parameter values: %w", err)
+			}
+
+			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
+				Action:            WorkspaceCreate,
+				TemplateVersionID: tpl.ActiveVersionID,
+				Owner:             codersdk.Me,
+
+				RichParameterFile: parameterFlags.richParameterFile,
+				RichParameters:    cliRichParameters,
+			})
+			if err != nil {
+				return xerrors.Errorf("prepare build: %w", err)
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for i := range count {
+				id := strconv.Itoa(int(i))
+				config := coordinationchurn.Config{
+					Workspace: workspacebuild.Config{
+						OrganizationID: me.OrganizationIDs[0],
+						UserID:         codersdk.Me,
+						Request: codersdk.CreateWorkspaceRequest{
+							TemplateID:          tpl.ID,
+							RichParameterValues: richParameters,
+						},
+					},
+					Clients:             int(clients),
+					Cycles:              int(cycles),
+					Interval:            interval,
+					CoordinationTimeout: coordinationTimeout,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+				var runner harness.Runnable = coordinationchurn.NewRunner(runnerClient, config)
+				th.AddRun(coordinationChurnTestName, id, runner)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running coordination churn scaletest...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_COUNT",
+			Default:       "1",
+			Description:   "Number of workspaces to create, each with its own agent to churn coordinator sessions against.",
+			Value:         serpent.Int64Of(&count),
+		},
+		{
+			Flag:          "template",
+			FlagShorthand: "t",
+			Env:           "CODER_SCALETEST_TEMPLATE",
+			Description:   "Required: Name or ID of the template to use for workspaces. The template must define a coder_external_agent.",
+			Value:         serpent.StringOf(&template),
+			Required:      true,
+		},
+		{
+			Flag:        "clients",
+			Env:         "CODER_SCALETEST_COORDINATION_CHURN_CLIENTS",
+			Default:     "10",
+			Description: "Number of client coordinator sessions connected to each agent per cycle.",
+			Value:       serpent.Int64Of(&clients),
+		},
+		{
+			Flag:        "cycles",
+			Env:         "CODER_SCALETEST_COORDINATION_CHURN_CYCLES",
+			Default:     "10",
+			Description: "Number of times each runner connects and disconnects its agent and client coordinator sessions.",
+			Value:       serpent.Int64Of(&cycles),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_COORDINATION_CHURN_INTERVAL",
+			Default:     "0s",
+			Description: "Time to wait between cycles.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "coordination-timeout",
+			Env:         "CODER_SCALETEST_COORDINATION_CHURN_COORDINATION_TIMEOUT",
+			Default:     "1m",
+			Description: "Maximum time a single cycle may take to coordinate all sessions and propagate their disconnection.",
+			Value:       serpent.DurationOf(&coordinationTimeout),
+		},
+	}
+
+	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,283 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/devcontainer"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+	"github.com/coder/serpent"
+)
+
+const devcontainerTestName = "devcontainers"
+
+func (r *RootCmd) scaletestDevcontainers() *serpent.Command {
+	var (
+		count               int64
+		template            string
+		devcontainers       int64
+		devcontainerTimeout time.Duration
+		pollInterval        time.Duration
+
+		parameterFlags      workspaceParameterFlags
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "devcontainers",
+		Short: "Create workspaces from a devcontainer template and wait for their devcontainers to start",
+		Long: `Each runner creates a workspace from the template and waits for the configured number of devcontainers to be cloned, built and started, and for their sub-agents to be ready.
+The time until the workspace agents connected, and the time after that until each devcontainer was running and its sub-agent was ready, are reported as runner metrics.`,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if count <= 0 {
+				return xerrors.Errorf("--count must be greater than 0")
+			}
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			tpl, err := parseTemplate(ctx, client, me.OrganizationIDs, template)
+			if err != nil {
+				return xerrors.Errorf("parse template: %w", err)
+			}
+
+			cliRichParameters, err := asWorkspaceBuildParameters(parameterFlags.richParameters)
+			if err != nil {
+				return xerrors.Errorf("can't parse given parameter values: %w", err)
+			}
+
+			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
+				Action:            WorkspaceCreate,
+				TemplateVersionID: tpl.ActiveVersionID,
+				Owner:             codersdk.Me,
+
+				RichParameterFile: parameterFlags.richParameterFile,
+				RichParameters:    cliRichParameters,
+			})
+			if err != nil {
+				return xerrors.Errorf("prepare build: %w", err)
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for i := range count {
+				id := strconv.Itoa(int(i))
+				config := devcontainer.Config{
+					Workspace: workspacebuild.Config{
+						OrganizationID: me.OrganizationIDs[0],
+						UserID:         codersdk.Me,
+						Request: codersdk.CreateWorkspaceRequest{
+							TemplateID:          tpl.ID,
+							RichParameterValues: richParameters,
+						},
+					},
+					Devcontainers:       int(devcontainers),
+					DevcontainerTimeout: devcontainerTimeout,
+					PollInterval:        pollInterval,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+				var runner harness.Runnable = devcontainer.NewRunner(runnerClient, config)
+				th.AddRun(devcontainerTestName, id, runner)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running devcontainer scaletest...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_COUNT",
+			Default:       "1",
+			Description:   "Number of workspaces to create.",
+			Value:         serpent.Int64Of(&count),
+		},
+		{
+			Flag:          "template",
+			FlagShorthand: "t",
+			Env:           "CODER_SCALETEST_TEMPLATE",
+			Description:   "Required: Name or ID of the template to use for workspaces.",
+			Value:         serpent.StringOf(&template),
+			Required:      true,
+		},
+		{
+			Flag:        "devcontainers",
+			Env:         "CODER_SCALETEST_DEVCONTAINERS",
+			Default:     "1",
+			Description: "Number of devcontainers each workspace is expected to start.",
+			Value:       serpent.Int64Of(&devcontainers),
+		},
+		{
+			Flag:        "devcontainer-timeout",
+			Env:         "CODER_SCALETEST_DEVCONTAINER_TIMEOUT",
+			Default:     "15m",
+			Description: "Maximum time to wait for the devcontainers of a workspace to be running and their sub-agents ready, after the workspace agents connected.",
+			Value:       serpent.DurationOf(&devcontainerTimeout),
+		},
+		{
+			Flag:        "devcontainer-poll-interval",
+			Env:         "CODER_SCALETEST_DEVCONTAINER_POLL_INTERVAL",
+			Default:     "1s",
+			Description: "How often to poll the status of the devcontainers.",
+			Value:       serpent.DurationOf(&pollInterval),
+		},
+	}
+
+	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	return cmd
+}
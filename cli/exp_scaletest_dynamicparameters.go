@@ -77,7 +77,7 @@ func (r *RootCmd) scaletestDynamicParameters() *serpent.Command {
 			prometheusSrvClose := ServeHandler(ctx, logger, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), prometheusFlags.Address, "prometheus")
 			defer prometheusSrvClose()
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
@@ -92,7 +92,6 @@ func (r *RootCmd) scaletestDynamicParameters() *serpent.Command {
 				_, _ = fmt.Fprintf(inv.Stderr, "Waiting %s for prometheus metrics to be scraped\n", prometheusFlags.Wait)
 				<-time.After(prometheusFlags.Wait)
 			}()
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			partitions, err := dynamicparameters.SetupPartitions(ctx, client, org.ID, templateName, tags, numEvals, logger)
 			if err != nil {
@@ -102,7 +101,9 @@ func (r *RootCmd) scaletestDynamicParameters() *serpent.Command {
 			th := harness.NewTestHarness(
 				timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}),
 				// there is no cleanup since it's just a connection that we sever.
-				nil)
+				nil,
+				harness.WithTracerProvider(tracerProvider),
+			)
 
 			for i, part := range partitions {
 				for j := range part.ConcurrentEvaluations {
@@ -118,13 +119,6 @@ func (r *RootCmd) scaletestDynamicParameters() *serpent.Command {
 						return xerrors.Errorf("create runner client: %w", err)
 					}
 					var runner harness.Runnable = dynamicparameters.NewRunner(runnerClient, cfg)
-					if tracingEnabled {
-						runner = &runnableTraceWrapper{
-							tracer:   tracer,
-							spanName: fmt.Sprintf("%s/%d/%d", dynamicParametersTestName, i, j),
-							runner:   runner,
-						}
-					}
 					th.AddRun(dynamicParametersTestName, fmt.Sprintf("%d/%d", j, i), runner)
 				}
 			}
@@ -0,0 +1,113 @@
+//go:build !slim
+
+package cli
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/loadprofile"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) scaletestRecordLoadProfile() *serpent.Command {
+	var (
+		start       string
+		end         string
+		interval    string
+		templateIDs []string
+		outputPath  string
+	)
+
+	cmd := &serpent.Command{
+		Use:   "record-load-profile",
+		Short: "Record the load of a deployment from its historical stats",
+		Long: `Records the number of active users and workspace start builds of a deployment over time into a load profile, which create-workspaces and workspace-traffic can replay against another deployment with --load-profile.
+Active users are taken from the template insights. Builds are taken from the build history of existing workspaces, so builds of deleted workspaces are not counted.`,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			cfg := loadprofile.RecordConfig{
+				Interval: codersdk.InsightsReportInterval(interval),
+			}
+			cfg.Start, err = time.Parse(time.RFC3339, start)
+			if err != nil {
+				return xerrors.Errorf("parse --start: %w", err)
+			}
+			cfg.End, err = time.Parse(time.RFC3339, end)
+			if err != nil {
+				return xerrors.Errorf("parse --end: %w", err)
+			}
+			for _, id := range templateIDs {
+				templateID, err := uuid.Parse(id)
+				if err != nil {
+					return xerrors.Errorf("parse template ID %q: %w", id, err)
+				}
+				cfg.TemplateIDs = append(cfg.TemplateIDs, templateID)
+			}
+
+			profile, err := loadprofile.Record(ctx, client, cfg)
+			if err != nil {
+				return xerrors.Errorf("record load profile: %w", err)
+			}
+
+			var w io.Writer = inv.Stdout
+			if outputPath != "-" {
+				f, err := os.Create(outputPath)
+				if err != nil {
+					return xerrors.Errorf("create output file: %w", err)
+				}
+				defer f.Close()
+				w = f
+			}
+			return profile.Write(w)
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "start",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE_START",
+			Description: "Start of the recorded stats in RFC 3339 format, at the start of an hour.",
+			Required:    true,
+			Value:       serpent.StringOf(&start),
+		},
+		{
+			Flag:        "end",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE_END",
+			Description: "End of the recorded stats in RFC 3339 format, at the start of an hour.",
+			Required:    true,
+			Value:       serpent.StringOf(&end),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE_INTERVAL",
+			Description: "Interval of the samples of the load profile.",
+			Default:     string(codersdk.InsightsReportIntervalDay),
+			Value:       serpent.EnumOf(&interval, string(codersdk.InsightsReportIntervalDay), string(codersdk.InsightsReportIntervalWeek)),
+		},
+		{
+			Flag:        "template-id",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE_TEMPLATE_IDS",
+			Description: "Only record the stats of workspaces of the given templates.",
+			Value:       serpent.StringArrayOf(&templateIDs),
+		},
+		{
+			Flag:        "output",
+			Env:         "CODER_SCALETEST_LOAD_PROFILE_OUTPUT",
+			Description: `Path to write the load profile to as JSON, or "-" for stdout.`,
+			Default:     "-",
+			Value:       serpent.StringOf(&outputPath),
+		},
+	}
+	return cmd
+}
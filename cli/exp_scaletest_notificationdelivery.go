@@ -0,0 +1,314 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/createusers"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/notificationdelivery"
+	"github.com/coder/coder/v2/scaletest/notifications"
+	"github.com/coder/serpent"
+)
+
+const notificationDeliveryTestName = "notification-delivery"
+
+func (r *RootCmd) scaletestNotificationDelivery() *serpent.Command {
+	var (
+		userCount            int64
+		notificationCount    int64
+		interval             time.Duration
+		dialTimeout          time.Duration
+		deliveryTimeout      time.Duration
+		webhookListenAddress string
+		smtpAPIURL           string
+		smtpRequestTimeout   time.Duration
+
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+		prometheusFlags     = &scaletestPrometheusFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "notification-delivery",
+		Short: "Measure notification delivery latency by sending large volumes of notifications.",
+		Long: `Each runner creates a user that sends itself a number of custom notifications, and measures the time from sending each notification until it was delivered.
+Notifications are always expected in the user's inbox. To also measure webhook dispatch, point the deployment's webhook endpoint (CODER_NOTIFICATIONS_WEBHOOK_ENDPOINT) at --webhook-listen-address. To also measure SMTP dispatch, point the deployment's smarthost at the SMTP mock and set --smtp-api-url.`,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if userCount <= 0 {
+				return xerrors.Errorf("--user-count must be greater than 0")
+			}
+			if smtpAPIURL != "" && !strings.HasPrefix(smtpAPIURL, "http://") && !strings.HasPrefix(smtpAPIURL, "https://") {
+				return xerrors.Errorf("--smtp-api-url must start with http:// or https://")
+			}
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+
+			reg := prometheus.NewRegistry()
+			metrics := notifications.NewMetrics(reg)
+			prometheusSrvClose := ServeHandler(ctx, inv.Logger, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), prometheusFlags.Address, "prometheus")
+			defer prometheusSrvClose()
+
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+				// Wait for prometheus metrics to be scraped
+				_, _ = fmt.Fprintf(inv.Stderr, "Waiting %s for prometheus metrics to be scraped\n", prometheusFlags.Wait)
+				<-time.After(prometheusFlags.Wait)
+			}()
+
+			var webhook *notificationdelivery.WebhookReceiver
+			if webhookListenAddress != "" {
+				webhook = notificationdelivery.NewWebhookReceiver()
+				webhookSrvClose := ServeHandler(ctx, inv.Logger, webhook, webhookListenAddress, "webhook")
+				defer webhookSrvClose()
+			}
+
+			smtpHTTPClient := &http.Client{
+				Transport: &http.Transport{
+					MaxConnsPerHost:     512,
+					MaxIdleConnsPerHost: 512,
+					IdleConnTimeout:     60 * time.Second,
+				},
+			}
+
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for i := range userCount {
+				id := strconv.Itoa(int(i))
+				config := notificationdelivery.Config{
+					User: createusers.Config{
+						OrganizationID: me.OrganizationIDs[0],
+					},
+					Notifications:      int(notificationCount),
+					Interval:           interval,
+					DialTimeout:        dialTimeout,
+					DeliveryTimeout:    deliveryTimeout,
+					Webhook:            webhook,
+					SMTPApiURL:         smtpAPIURL,
+					SMTPRequestTimeout: smtpRequestTimeout,
+					SMTPHttpClient:     smtpHTTPClient,
+					Metrics:            metrics,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+				var runner harness.Runnable = notificationdelivery.NewRunner(runnerClient, config)
+				th.AddRun(notificationDeliveryTestName, id, runner)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running notification delivery scaletest...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "user-count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_NOTIFICATION_DELIVERY_USER_COUNT",
+			Default:       "10",
+			Description:   "Number of users to create, each sending notifications to themselves.",
+			Value:         serpent.Int64Of(&userCount),
+		},
+		{
+			Flag:        "notifications",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_NOTIFICATIONS",
+			Default:     "10",
+			Description: "Number of notifications each user sends.",
+			Value:       serpent.Int64Of(&notificationCount),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_INTERVAL",
+			Default:     "0s",
+			Description: "Time each user waits between sending notifications.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "dial-timeout",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_DIAL_TIMEOUT",
+			Default:     "1m",
+			Description: "Timeout for dialing the inbox notification websocket endpoint.",
+			Value:       serpent.DurationOf(&dialTimeout),
+		},
+		{
+			Flag:        "delivery-timeout",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_TIMEOUT",
+			Default:     "10m",
+			Description: "How long to wait for all notifications of a user to be delivered after the last one was sent.",
+			Value:       serpent.DurationOf(&deliveryTimeout),
+		},
+		{
+			Flag:        "webhook-listen-address",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_WEBHOOK_LISTEN_ADDRESS",
+			Description: "Address to receive notifications dispatched by the webhook method on, e.g. \"0.0.0.0:8090\". If set, every notification is expected to be delivered by webhook.",
+			Value:       serpent.StringOf(&webhookListenAddress),
+		},
+		{
+			Flag:        "smtp-api-url",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_SMTP_API_URL",
+			Description: "SMTP mock HTTP API address. If set, every notification is expected to be delivered by email.",
+			Value:       serpent.StringOf(&smtpAPIURL),
+		},
+		{
+			Flag:        "smtp-request-timeout",
+			Env:         "CODER_SCALETEST_NOTIFICATION_DELIVERY_SMTP_REQUEST_TIMEOUT",
+			Default:     "1m",
+			Description: "Timeout for SMTP mock API requests.",
+			Value:       serpent.DurationOf(&smtpRequestTimeout),
+		},
+	}
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	prometheusFlags.attach(&cmd.Options)
+	return cmd
+}
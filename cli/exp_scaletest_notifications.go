@@ -94,11 +94,10 @@ func (r *RootCmd) scaletestNotifications() *serpent.Command {
 				return xerrors.Errorf("could not parse --output flags")
 			}
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			reg := prometheus.NewRegistry()
 			metrics := notifications.NewMetrics(reg)
@@ -192,7 +191,7 @@ func (r *RootCmd) scaletestNotifications() *serpent.Command {
 				triggerTimes,
 			)
 
-			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy())
+			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider))
 
 			for i, config := range configs {
 				id := strconv.Itoa(i)
@@ -204,13 +203,6 @@ func (r *RootCmd) scaletestNotifications() *serpent.Command {
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = notifications.NewRunner(runnerClient, config)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: name,
-						runner:   runner,
-					}
-				}
 
 				th.AddRun(name, id, runner)
 			}
@@ -0,0 +1,240 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/plugin"
+	"github.com/coder/serpent"
+)
+
+// scaletestPluginWorkload returns the scaletest command of a workload
+// registered by a plugin.
+func (r *RootCmd) scaletestPluginWorkload(w plugin.Workload) *serpent.Command {
+	var (
+		count    int64
+		duration time.Duration
+
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		retryFlags          = &scaletestRetryFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   w.Name,
+		Short: w.Short,
+		Long:  w.Long,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			if duration <= 0 && count <= 0 {
+				return xerrors.Errorf("--count must be greater than 0")
+			}
+			if duration > 0 && strategy.concurrency <= 0 {
+				return xerrors.Errorf("--duration requires --concurrency to be greater than 0")
+			}
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+			if w.Validate != nil {
+				if err := w.Validate(); err != nil {
+					return xerrors.Errorf("validate %s: %w", w.Name, err)
+				}
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			newRunner := func(id int) (harness.Runnable, error) {
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return nil, xerrors.Errorf("create runner client: %w", err)
+				}
+				return w.NewRunner(ctx, runnerClient, id)
+			}
+
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), retryFlags.option(), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			if duration <= 0 {
+				for i := range int(count) {
+					runner, err := newRunner(i)
+					if err != nil {
+						return xerrors.Errorf("create runner %d: %w", i, err)
+					}
+					th.AddRun(w.Name, strconv.Itoa(i), runner)
+				}
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Running %s scaletest...\n", w.Name)
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			if duration > 0 {
+				err = th.RunFor(testCtx, harness.DurationTest{
+					TestName:    w.Name,
+					Duration:    duration,
+					Concurrency: int(strategy.concurrency),
+					RunTimeout:  strategy.timeoutPerJob,
+					NewRunner:   newRunner,
+				})
+			} else {
+				err = th.Run(testCtx)
+			}
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_COUNT",
+			Default:       "1",
+			Description:   "Number of runners to run.",
+			Value:         serpent.Int64Of(&count),
+		},
+		{
+			Flag:        "duration",
+			Env:         "CODER_SCALETEST_DURATION",
+			Default:     "0s",
+			Description: "Run the test for this long instead of a fixed number of runs, starting a new run whenever one finishes so that --concurrency runs are in progress at all times. --count is ignored when set. The duration must be shorter than --timeout.",
+			Value:       serpent.DurationOf(&duration),
+		},
+	}
+	cmd.Options = append(cmd.Options, w.Options...)
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	retryFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,284 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/portforward"
+	"github.com/coder/serpent"
+)
+
+const portForwardTestName = "workspace-port-forward"
+
+func (r *RootCmd) scaletestWorkspacePortForward() *serpent.Command {
+	var (
+		port          int64
+		connections   int64
+		pattern       string
+		bytesPerTick  int64
+		tickInterval  time.Duration
+		disableDirect bool
+
+		targetFlags         = &workspaceTargetFlags{}
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		retryFlags          = &scaletestRetryFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "workspace-port-forward",
+		Short: "Generate traffic through port-forwarded connections to scaletest workspaces",
+		Long: `Each runner opens a tunnel to a workspace agent the same way "coder port-forward" does, forwards connections to a port inside the workspace and pushes traffic through them.
+A service that echoes back everything it receives must be listening on the port in every workspace, e.g. "socat TCP-LISTEN:8080,fork EXEC:cat".`,
+		Handler: func(inv *serpent.Invocation) error {
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			ctx := inv.Context()
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if port <= 0 || port > 65535 {
+				return xerrors.Errorf("--port must be between 1 and 65535")
+			}
+
+			workspaces, err := targetFlags.getTargetedWorkspaces(ctx, client, me.OrganizationIDs, inv.Stdout)
+			if err != nil {
+				return err
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			// Port forwarding doesn't create any resources, so there is
+			// nothing to clean up.
+			th := harness.NewTestHarness(strategy.toStrategy(), harness.ConcurrentExecutionStrategy{}, harness.WithTracerProvider(tracerProvider), retryFlags.option(), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for idx, ws := range workspaces {
+				var (
+					agent codersdk.WorkspaceAgent
+					id    = strconv.Itoa(idx)
+				)
+
+				for _, res := range ws.LatestBuild.Resources {
+					if len(res.Agents) == 0 {
+						continue
+					}
+					agent = res.Agents[0]
+				}
+
+				if agent.ID == uuid.Nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "WARN: skipping workspace %s: no agent\n", ws.Name)
+					continue
+				}
+
+				config := portforward.Config{
+					AgentID:       agent.ID,
+					WorkspaceName: ws.Name,
+					AgentName:     agent.Name,
+					Port:          uint16(port), //nolint:gosec // Validated above.
+					Connections:   int(connections),
+					DisableDirect: disableDirect,
+					Pattern:       portforward.Pattern(pattern),
+					BytesPerTick:  bytesPerTick,
+					TickInterval:  tickInterval,
+					Duration:      strategy.timeout,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+				var runner harness.Runnable = portforward.NewRunner(runnerClient, config)
+
+				run := th.AddRun(portForwardTestName, id, runner)
+				run.SetTag("template", ws.TemplateName)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running load test...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "port",
+			Env:         "CODER_SCALETEST_PORT_FORWARD_PORT",
+			Description: "Required: Port inside the workspaces to forward connections to. A service that echoes back what it receives must be listening on it.",
+			Value:       serpent.Int64Of(&port),
+			Required:    true,
+		},
+		{
+			Flag:        "connections",
+			Env:         "CODER_SCALETEST_PORT_FORWARD_CONNECTIONS",
+			Default:     "1",
+			Description: "Number of concurrent connections to forward to each workspace.",
+			Value:       serpent.Int64Of(&connections),
+		},
+		{
+			Flag:        "pattern",
+			Env:         "CODER_SCALETEST_PORT_FORWARD_PATTERN",
+			Default:     string(portforward.PatternStream),
+			Description: "Traffic pattern to send through each connection. \"stream\" writes every tick without waiting for the echo, \"ping-pong\" waits for the echo and records the round trip time.",
+			Value:       serpent.EnumOf(&pattern, string(portforward.PatternStream), string(portforward.PatternPingPong)),
+		},
+		{
+			Flag:        "bytes-per-tick",
+			Env:         "CODER_SCALETEST_PORT_FORWARD_BYTES_PER_TICK",
+			Default:     "1024",
+			Description: "How much traffic to send through each connection per tick.",
+			Value:       serpent.Int64Of(&bytesPerTick),
+		},
+		{
+			Flag:        "tick-interval",
+			Env:         "CODER_SCALETEST_PORT_FORWARD_TICK_INTERVAL",
+			Default:     "100ms",
+			Description: "How often to send traffic.",
+			Value:       serpent.DurationOf(&tickInterval),
+		},
+		{
+			Flag:        "disable-direct",
+			Env:         "CODER_SCALETEST_PORT_FORWARD_DISABLE_DIRECT_CONNECTIONS",
+			Default:     "false",
+			Description: "Disable direct connections to workspaces, relaying all traffic through DERP.",
+			Value:       serpent.BoolOf(&disableDirect),
+		},
+	}
+
+	targetFlags.attach(&cmd.Options)
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	retryFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+
+	return cmd
+}
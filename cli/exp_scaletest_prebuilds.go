@@ -72,11 +72,10 @@ func (r *RootCmd) scaletestPrebuilds() *serpent.Command {
 				return xerrors.Errorf("parse output flags: %w", err)
 			}
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			reg := prometheus.NewRegistry()
 			metrics := prebuilds.NewMetrics(reg)
@@ -110,7 +109,7 @@ func (r *RootCmd) scaletestPrebuilds() *serpent.Command {
 			deletionBarrier := new(sync.WaitGroup)
 			deletionBarrier.Add(int(numTemplates))
 
-			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy())
+			th := harness.NewTestHarness(timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider))
 
 			tags, err := ParseProvisionerTags(provisionerTags)
 			if err != nil {
@@ -145,13 +144,6 @@ func (r *RootCmd) scaletestPrebuilds() *serpent.Command {
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = prebuilds.NewRunner(runnerClient, cfg)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: fmt.Sprintf("prebuilds/%s", id),
-						runner:   runner,
-					}
-				}
 
 				th.AddRun("prebuilds", id, runner)
 			}
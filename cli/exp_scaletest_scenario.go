@@ -0,0 +1,342 @@
+//go:build !slim
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/scenario"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) scaletestScenario() *serpent.Command {
+	var dryRun bool
+
+	cmd := &serpent.Command{
+		Use:   "scenario <file>",
+		Short: "Run a multi-phase scaletest described by a scenario file",
+		Long: `Runs the phases of a YAML or JSON scenario file one after the other. Each phase runs a mix of scaletest commands concurrently, with the strategy, duration and SLOs of the phase.
+The output of every command is prefixed with its phase and workload name. Exits with a non-zero status if any command failed.`,
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			f, err := os.Open(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("open scenario: %w", err)
+			}
+			s, err := scenario.Parse(f)
+			_ = f.Close()
+			if err != nil {
+				return xerrors.Errorf("parse scenario: %w", err)
+			}
+			if err := validateScaletestScenario(inv.Command.Parent, s); err != nil {
+				return xerrors.Errorf("validate scenario: %w", err)
+			}
+
+			binPath, err := os.Executable()
+			if err != nil {
+				return xerrors.Errorf("get executable path: %w", err)
+			}
+			runner := &scaletestScenarioRunner{
+				scenario: s,
+				binPath:  binPath,
+				// Skip the name of the binary.
+				parentArgs: strings.Fields(inv.Command.Parent.FullName())[1:],
+				environ:    r.scaletestScenarioEnviron(ctx, inv),
+				stdout:     inv.Stdout,
+				stderr:     inv.Stderr,
+			}
+
+			if dryRun {
+				for _, p := range s.Phases {
+					_, _ = fmt.Fprintf(inv.Stdout, "Phase %q:\n", p.Name)
+					for _, w := range p.Workloads {
+						args, err := runner.args(p, w)
+						if err != nil {
+							return err
+						}
+						_, _ = fmt.Fprintf(inv.Stdout, "\t%s\n", strings.Join(args, " "))
+					}
+				}
+				return nil
+			}
+
+			// Interrupts also reach the commands, which drain themselves, so
+			// the scenario only stops starting new phases.
+			notifyCtx, stop := inv.SignalNotifyContext(ctx, StopSignals...)
+			defer stop()
+
+			var results []scaletestScenarioResult
+			for i, p := range s.Phases {
+				if notifyCtx.Err() != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nScenario was interrupted, skipping %d phase(s)\n", len(s.Phases)-i)
+					break
+				}
+				_, _ = fmt.Fprintf(inv.Stderr, "\nRunning phase %q (%d/%d)...\n", p.Name, i+1, len(s.Phases))
+				phaseResults := runner.runPhase(ctx, p)
+				results = append(results, phaseResults...)
+				if !p.ContinueOnFailure && slices.ContainsFunc(phaseResults, scaletestScenarioResult.failed) {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nPhase %q failed, skipping %d phase(s)\n", p.Name, len(s.Phases)-i-1)
+					break
+				}
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, "\nScenario results:")
+			failed := 0
+			for _, res := range results {
+				_, _ = fmt.Fprintf(inv.Stdout, "\t%s\n", res)
+				if res.failed() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return xerrors.Errorf("scenario failed, %d workload(s) failed", failed)
+			}
+			if notifyCtx.Err() != nil {
+				return xerrors.New("scenario was interrupted, results are partial")
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "dry-run",
+			Env:         "CODER_SCALETEST_SCENARIO_DRY_RUN",
+			Description: "Validate the scenario and print the command of every workload without running them.",
+			Value:       serpent.BoolOf(&dryRun),
+		},
+	}
+	return cmd
+}
+
+// validateScaletestScenario checks that the commands and flags of every
+// workload of the scenario exist, so typos are caught before the first phase
+// starts.
+func validateScaletestScenario(parent *serpent.Command, s scenario.Scenario) error {
+	for _, p := range s.Phases {
+		for _, w := range p.Workloads {
+			var cmd *serpent.Command
+			for _, c := range parent.Children {
+				if c.Name() == w.Command {
+					cmd = c
+					break
+				}
+			}
+			if cmd == nil || cmd.Name() == "scenario" {
+				return xerrors.Errorf("phase %q: workload %q: unknown scaletest command %q", p.Name, w.Name, w.Command)
+			}
+
+			names, err := p.FlagNames(w)
+			if err != nil {
+				return xerrors.Errorf("phase %q: workload %q: %w", p.Name, w.Name, err)
+			}
+			for _, name := range names {
+				if !scaletestCommandHasFlag(cmd, name) {
+					return xerrors.Errorf("phase %q: workload %q: command %q has no flag %q", p.Name, w.Name, w.Command, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// scaletestCommandHasFlag returns whether the command or any of its parents
+// has the given flag.
+func scaletestCommandHasFlag(cmd *serpent.Command, name string) bool {
+	for c := cmd; c != nil; c = c.Parent {
+		for _, o := range c.Options {
+			if o.Flag == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scaletestScenarioEnviron returns the environment of the commands of a
+// scenario. Global options set with flags, and the scaletest prefix, are passed
+// on as environment variables.
+func (r *RootCmd) scaletestScenarioEnviron(ctx context.Context, inv *serpent.Invocation) []string {
+	environ := inv.Environ.ToOS()
+	if r.clientURL != nil && r.clientURL.String() != "" {
+		environ = append(environ, envURL+"="+r.clientURL.String())
+	}
+	if r.token != "" {
+		environ = append(environ, envSessionToken+"="+r.token)
+	}
+	if r.globalConfig != "" {
+		environ = append(environ, "CODER_CONFIG_DIR="+r.globalConfig)
+	}
+	return append(environ, "CODER_SCALETEST_PREFIX="+loadtestutil.Prefix(ctx))
+}
+
+type scaletestScenarioRunner struct {
+	scenario   scenario.Scenario
+	binPath    string
+	parentArgs []string
+	environ    []string
+	stdout     io.Writer
+	stderr     io.Writer
+	// mu serializes the output of the commands.
+	mu sync.Mutex
+}
+
+// args returns the arguments of the command of the given workload, including
+// the outputs of its results if the scenario has an output directory.
+func (r *scaletestScenarioRunner) args(p scenario.Phase, w scenario.Workload) ([]string, error) {
+	args, err := p.Args(w)
+	if err != nil {
+		return nil, xerrors.Errorf("phase %q: workload %q: %w", p.Name, w.Name, err)
+	}
+	args = append(slices.Clone(r.parentArgs), args...)
+	if _, ok := w.Flags["output"]; !ok && r.scenario.OutputDir != "" {
+		path := filepath.Join(r.scenario.OutputDir, p.Name, w.Name+".json")
+		args = append(args, "--output=text", "--output=json:"+path)
+	}
+	return args, nil
+}
+
+// runPhase runs every workload of the phase concurrently and waits for them to
+// finish.
+func (r *scaletestScenarioRunner) runPhase(ctx context.Context, p scenario.Phase) []scaletestScenarioResult {
+	phaseCtx, cancel := ctx, context.CancelFunc(func() {})
+	if p.Duration > 0 {
+		phaseCtx, cancel = context.WithTimeout(ctx, p.Duration)
+	}
+	defer cancel()
+
+	results := make([]scaletestScenarioResult, len(p.Workloads))
+	var wg sync.WaitGroup
+	for i, w := range p.Workloads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := r.runWorkload(phaseCtx, p, w)
+			results[i] = scaletestScenarioResult{
+				phase:    p.Name,
+				workload: w.Name,
+				duration: time.Since(start),
+				err:      err,
+				// Commands are interrupted once the phase duration has
+				// elapsed, which doesn't make them fail.
+				interrupted: err != nil && phaseCtx.Err() != nil && ctx.Err() == nil,
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *scaletestScenarioRunner) runWorkload(ctx context.Context, p scenario.Phase, w scenario.Workload) error {
+	args, err := r.args(p, w)
+	if err != nil {
+		return err
+	}
+	if r.scenario.OutputDir != "" {
+		if err := os.MkdirAll(filepath.Join(r.scenario.OutputDir, p.Name), 0o755); err != nil {
+			return xerrors.Errorf("create output directory: %w", err)
+		}
+	}
+
+	prefix := fmt.Sprintf("[%s/%s] ", p.Name, w.Name)
+	stdout := &scaletestScenarioWriter{mu: &r.mu, w: r.stdout, prefix: prefix}
+	stderr := &scaletestScenarioWriter{mu: &r.mu, w: r.stderr, prefix: prefix}
+	defer stdout.flush()
+	defer stderr.flush()
+
+	//nolint:gosec // The command is the binary that is running.
+	cmd := exec.CommandContext(ctx, r.binPath, args...)
+	cmd.Env = append(slices.Clone(r.environ), r.scenario.Environ(w)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Interrupt rather than kill the command, so it drains its runs and
+	// cleans up.
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	if err := cmd.Run(); err != nil {
+		return xerrors.Errorf("run %q: %w", w.Command, err)
+	}
+	return nil
+}
+
+type scaletestScenarioResult struct {
+	phase       string
+	workload    string
+	duration    time.Duration
+	err         error
+	interrupted bool
+}
+
+func (r scaletestScenarioResult) failed() bool {
+	return r.err != nil && !r.interrupted
+}
+
+func (r scaletestScenarioResult) String() string {
+	status := "pass"
+	switch {
+	case r.interrupted:
+		status = "stopped after phase duration"
+	case r.err != nil:
+		status = fmt.Sprintf("fail: %v", r.err)
+	}
+	return fmt.Sprintf("%s/%s: %s (%s)", r.phase, r.workload, status, r.duration.Round(time.Millisecond))
+}
+
+// scaletestScenarioWriter prefixes every line written to it before writing it
+// to w. Lines of different writers sharing mu are not interleaved.
+type scaletestScenarioWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *scaletestScenarioWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes the last line if it doesn't end with a newline.
+func (w *scaletestScenarioWriter) flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *scaletestScenarioWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, _ = io.WriteString(w.w, w.prefix)
+	_, _ = w.w.Write(line)
+}
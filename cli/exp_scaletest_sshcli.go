@@ -0,0 +1,242 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/sshcli"
+	"github.com/coder/serpent"
+)
+
+const sshCLITestName = "ssh-cli"
+
+func (r *RootCmd) scaletestSSHCLI() *serpent.Command {
+	var (
+		binaryPath    string
+		command       string
+		iterations    int64
+		interval      time.Duration
+		disableDirect bool
+
+		targetFlags         = &workspaceTargetFlags{}
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		retryFlags          = &scaletestRetryFlags{}
+		warmupFlags         = &scaletestWarmupFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "ssh-cli",
+		Short: "Measure the time to shell of the real `coder ssh` command against scaletest workspaces",
+		Long: `Each runner runs "coder ssh <workspace> <command>" as a subprocess, so the time to shell includes CLI startup, authentication, coordinator negotiation and establishing the DERP or direct connection, not only the in-process SDK path.
+The time until the command prints its first output and the total session time are reported as runner metrics.`,
+		Handler: func(inv *serpent.Invocation) error {
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			ctx := inv.Context()
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			workspaces, err := targetFlags.getTargetedWorkspaces(ctx, client, me.OrganizationIDs, inv.Stdout)
+			if err != nil {
+				return err
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			// Connecting over SSH doesn't create any resources, so there is
+			// nothing to clean up.
+			th := harness.NewTestHarness(strategy.toStrategy(), harness.ConcurrentExecutionStrategy{}, harness.WithTracerProvider(tracerProvider), retryFlags.option(), warmupFlags.option(), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for idx, ws := range workspaces {
+				config := sshcli.Config{
+					BinaryPath:    binaryPath,
+					URL:           client.URL,
+					SessionToken:  client.SessionToken(),
+					Workspace:     ws.OwnerName + "/" + ws.Name,
+					Command:       command,
+					Iterations:    int(iterations),
+					Interval:      interval,
+					DisableDirect: disableDirect,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				var runner harness.Runnable = sshcli.NewRunner(config)
+				run := th.AddRun(sshCLITestName, strconv.Itoa(idx), runner)
+				run.SetTag("template", ws.TemplateName)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running load test...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "coder-binary",
+			Env:         "CODER_SCALETEST_SSH_CLI_BINARY",
+			Description: "Path to the coder binary to run. Defaults to the current executable.",
+			Value:       serpent.StringOf(&binaryPath),
+		},
+		{
+			Flag:        "command",
+			Env:         "CODER_SCALETEST_SSH_CLI_COMMAND",
+			Default:     "echo ok",
+			Description: "Command to run in each workspace. It must print something, as the time until the first output is the time to shell.",
+			Value:       serpent.StringOf(&command),
+		},
+		{
+			Flag:        "iterations",
+			Env:         "CODER_SCALETEST_SSH_CLI_ITERATIONS",
+			Default:     "1",
+			Description: "Number of times to run coder ssh against each workspace.",
+			Value:       serpent.Int64Of(&iterations),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_SSH_CLI_INTERVAL",
+			Default:     "0s",
+			Description: "Time to wait between iterations.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "disable-direct",
+			Env:         "CODER_SCALETEST_SSH_CLI_DISABLE_DIRECT_CONNECTIONS",
+			Default:     "false",
+			Description: "Disable direct connections to workspaces, relaying all traffic through DERP.",
+			Value:       serpent.BoolOf(&disableDirect),
+		},
+	}
+
+	targetFlags.attach(&cmd.Options)
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	retryFlags.attach(&cmd.Options)
+	warmupFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+
+	return cmd
+}
@@ -96,7 +96,7 @@ After all runners connect, it waits for the baseline duration before triggering
 			prometheusSrvClose := ServeHandler(ctx, logger, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), prometheusFlags.Address, "prometheus")
 			defer prometheusSrvClose()
 
-			tracerProvider, closeTracing, tracingEnabled, err := tracingFlags.provider(ctx)
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
 			if err != nil {
 				return xerrors.Errorf("create tracer provider: %w", err)
 			}
@@ -111,7 +111,6 @@ After all runners connect, it waits for the baseline duration before triggering
 				_, _ = fmt.Fprintf(inv.Stderr, "Waiting %s for prometheus metrics to be scraped\n", prometheusFlags.Wait)
 				<-time.After(prometheusFlags.Wait)
 			}()
-			tracer := tracerProvider.Tracer(scaletestTracerName)
 
 			// Setup shared resources for coordination
 			connectedWaitGroup := &sync.WaitGroup{}
@@ -122,6 +121,7 @@ After all runners connect, it waits for the baseline duration before triggering
 			th := harness.NewTestHarness(
 				timeoutStrategy.wrapStrategy(harness.ConcurrentExecutionStrategy{}),
 				cleanupStrategy.toStrategy(),
+				harness.WithTracerProvider(tracerProvider),
 			)
 
 			// Create runners
@@ -150,13 +150,6 @@ After all runners connect, it waits for the baseline duration before triggering
 					return xerrors.Errorf("create runner client: %w", err)
 				}
 				var runner harness.Runnable = taskstatus.NewRunner(runnerClient, cfg)
-				if tracingEnabled {
-					runner = &runnableTraceWrapper{
-						tracer:   tracer,
-						spanName: fmt.Sprintf("%s/%d", taskStatusTestName, i),
-						runner:   runner,
-					}
-				}
 				th.AddRun(taskStatusTestName, workspaceName, runner)
 			}
 
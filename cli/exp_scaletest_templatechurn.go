@@ -0,0 +1,281 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/templatechurn"
+	"github.com/coder/serpent"
+)
+
+const templateChurnTestName = "template-churn"
+
+func (r *RootCmd) scaletestTemplateChurn() *serpent.Command {
+	var (
+		count                     int64
+		workspaces                int64
+		versions                  int64
+		interval                  time.Duration
+		templateVersionJobTimeout time.Duration
+		workspaceBuildTimeout     time.Duration
+		provisionerType           string
+		provisionerTags           []string
+
+		tracingFlags        = &scaletestTracingFlags{}
+		strategy            = &scaletestStrategyFlags{}
+		cleanupStrategy     = newScaletestCleanupStrategy()
+		output              = &scaletestOutputFlags{}
+		assertionFlags      = &scaletestAssertionFlags{}
+		artifactFlags       = &scaletestArtifactFlags{}
+		logStreamFlags      = &scaletestLogStreamFlags{}
+		seedFlags           = &scaletestSeedFlags{}
+		resourceUsageFlags  = &scaletestResourceUsageFlags{}
+		profileFlags        = &scaletestProfileFlags{}
+		annotationFlags     = &scaletestAnnotationFlags{}
+		circuitBreakerFlags = &scaletestCircuitBreakerFlags{}
+		uploadFlags         = &scaletestUploadFlags{}
+		telemetryFlags      = &scaletestTelemetryFlags{}
+		snapshotFlags       = &scaletestSnapshotFlags{}
+		progressFlags       = &scaletestProgressFlags{}
+		keepFailuresFlags   = &scaletestKeepFailuresFlags{}
+		rerunFlags          = &scaletestRerunFlags{}
+		retentionFlags      = &scaletestRetentionFlags{}
+	)
+
+	cmd := &serpent.Command{
+		Use:   "template-churn",
+		Short: "Load test the template import pipeline by repeatedly pushing template versions and updating workspaces to them",
+		Long: `Each runner creates a template and a number of workspaces from it, then repeatedly pushes a new template version, promotes it and updates every workspace to it.
+The time every import job spends queued waiting for a provisioner and the time it takes to run are reported as runner metrics, along with the time it takes for an update to fan out to all workspaces.`,
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			interrupts := newScaletestInterruptHandler(ctx, inv.Stderr) // Checked later.
+			defer interrupts.stop()
+			ctx = interrupts.ctx
+
+			me, err := RequireAdmin(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			if count <= 0 {
+				return xerrors.Errorf("--count must be greater than 0")
+			}
+
+			outputs, err := output.parse()
+			if err != nil {
+				return xerrors.Errorf("could not parse --output flags")
+			}
+			if err := assertionFlags.assertions.Validate(); err != nil {
+				return xerrors.Errorf("invalid assertions: %w", err)
+			}
+			if err := circuitBreakerFlags.validate(); err != nil {
+				return err
+			}
+			if err := rerunFlags.load(); err != nil {
+				return err
+			}
+			if err := artifactFlags.validate(); err != nil {
+				return err
+			}
+			if err := profileFlags.validate(artifactFlags.dir); err != nil {
+				return err
+			}
+
+			tags, err := ParseProvisionerTags(provisionerTags)
+			if err != nil {
+				return err
+			}
+
+			tracerProvider, closeTracing, _, err := tracingFlags.provider(ctx)
+			if err != nil {
+				return xerrors.Errorf("create tracer provider: %w", err)
+			}
+			defer func() {
+				// Allow time for traces to flush even if command context is
+				// canceled. This is a no-op if tracing is not enabled.
+				_, _ = fmt.Fprintln(inv.Stderr, "\nUploading traces...")
+				if err := closeTracing(ctx); err != nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "\nError uploading traces: %+v\n", err)
+				}
+			}()
+
+			th := harness.NewTestHarness(strategy.toStrategy(), cleanupStrategy.toStrategy(), harness.WithTracerProvider(tracerProvider), artifactFlags.option(), logStreamFlags.option(inv.Stderr), seedFlags.option(), resourceUsageFlags.option(), profileFlags.option(client), annotationFlags.option(inv.Stderr), circuitBreakerFlags.option(), snapshotFlags.option(inv.Stderr), progressFlags.option(inv), keepFailuresFlags.option(), rerunFlags.option(), retentionFlags.option())
+			for i := range count {
+				id := strconv.Itoa(int(i))
+				config := templatechurn.Config{
+					OrganizationID:            me.OrganizationIDs[0],
+					ProvisionerType:           codersdk.ProvisionerType(provisionerType),
+					ProvisionerTags:           tags,
+					Workspaces:                int(workspaces),
+					Versions:                  int(versions),
+					Interval:                  interval,
+					TemplateVersionJobTimeout: templateVersionJobTimeout,
+					WorkspaceBuildTimeout:     workspaceBuildTimeout,
+				}
+				if err := config.Validate(); err != nil {
+					return xerrors.Errorf("validate config: %w", err)
+				}
+
+				// use an independent client for each Runner, so they don't reuse TCP connections. This can lead to
+				// requests being unbalanced among Coder instances.
+				runnerClient, err := loadtestutil.DupClientCopyingHeaders(client, BypassHeader)
+				if err != nil {
+					return xerrors.Errorf("create runner client: %w", err)
+				}
+				var runner harness.Runnable = templatechurn.NewRunner(runnerClient, config)
+				th.AddRun(templateChurnTestName, id, runner)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Running template churn scaletest...")
+			interrupts.attach(th)
+			testCtx, testCancel := strategy.toContext(ctx)
+			defer testCancel()
+			err = th.Run(testCtx)
+			if err != nil {
+				return xerrors.Errorf("run test harness (harness failure, not a test failure): %w", err)
+			}
+
+			res, err := rerunFlags.merge(th.Results())
+			if err != nil {
+				return err
+			}
+			for _, o := range outputs {
+				err = o.write(res, inv.Stdout)
+				if err != nil {
+					return xerrors.Errorf("write output %q to %q: %w", o.format, o.path, err)
+				}
+			}
+			err = artifactFlags.writeArchive(th)
+			if err != nil {
+				return err
+			}
+
+			// If the in-flight runs were canceled, skip cleanup.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			err = cleanupStrategy.runCleanup(ctx, th, inv.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if err := uploadFlags.upload(ctx, client, inv, res); err != nil {
+				return err
+			}
+			telemetryFlags.report(ctx, client, inv, res)
+
+			if err := circuitBreakerFlags.check(res); err != nil {
+				return err
+			}
+
+			if interrupts.wasInterrupted() {
+				return xerrors.New("load test was interrupted, results are partial")
+			}
+
+			if res.TotalFail > 0 {
+				return xerrors.New("load test failed, see above for more details")
+			}
+
+			if err := assertionFlags.check(res, inv.Stderr); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "count",
+			FlagShorthand: "c",
+			Env:           "CODER_SCALETEST_COUNT",
+			Default:       "1",
+			Description:   "Number of templates to churn concurrently.",
+			Value:         serpent.Int64Of(&count),
+		},
+		{
+			Flag:        "workspaces",
+			Env:         "CODER_SCALETEST_TEMPLATE_CHURN_WORKSPACES",
+			Default:     "10",
+			Description: "Number of workspaces to create from each template and update to every new template version.",
+			Value:       serpent.Int64Of(&workspaces),
+		},
+		{
+			Flag:        "versions",
+			Env:         "CODER_SCALETEST_TEMPLATE_CHURN_VERSIONS",
+			Default:     "5",
+			Description: "Number of template versions to push to each template.",
+			Value:       serpent.Int64Of(&versions),
+		},
+		{
+			Flag:        "interval",
+			Env:         "CODER_SCALETEST_TEMPLATE_CHURN_INTERVAL",
+			Default:     "0s",
+			Description: "Time to wait between pushing template versions.",
+			Value:       serpent.DurationOf(&interval),
+		},
+		{
+			Flag:        "template-version-job-timeout",
+			Env:         "CODER_SCALETEST_TEMPLATE_CHURN_TEMPLATE_VERSION_JOB_TIMEOUT",
+			Default:     "5m",
+			Description: "Timeout for template version import jobs.",
+			Value:       serpent.DurationOf(&templateVersionJobTimeout),
+		},
+		{
+			Flag:        "workspace-build-timeout",
+			Env:         "CODER_SCALETEST_TEMPLATE_CHURN_WORKSPACE_BUILD_TIMEOUT",
+			Default:     "10m",
+			Description: "Timeout for all workspaces of a template to be created, and to be updated to each new template version.",
+			Value:       serpent.DurationOf(&workspaceBuildTimeout),
+		},
+		{
+			Flag:        "provisioner-type",
+			Env:         "CODER_SCALETEST_TEMPLATE_CHURN_PROVISIONER_TYPE",
+			Default:     string(codersdk.ProvisionerTypeTerraform),
+			Description: "Provisioner to import template versions with. The echo provisioner measures the overhead of coderd without running Terraform.",
+			Value:       serpent.EnumOf(&provisionerType, string(codersdk.ProvisionerTypeTerraform), string(codersdk.ProvisionerTypeEcho)),
+		},
+		{
+			Flag:        "provisioner-tag",
+			Description: "Specify a set of tags to target provisioner daemons.",
+			Value:       serpent.StringArrayOf(&provisionerTags),
+		},
+	}
+
+	tracingFlags.attach(&cmd.Options)
+	strategy.attach(&cmd.Options)
+	cleanupStrategy.attach(&cmd.Options)
+	cleanupStrategy.attachDryRun(&cmd.Options)
+	output.attach(&cmd.Options)
+	assertionFlags.attach(&cmd.Options)
+	artifactFlags.attach(&cmd.Options)
+	logStreamFlags.attach(&cmd.Options)
+	seedFlags.attach(&cmd.Options)
+	resourceUsageFlags.attach(&cmd.Options)
+	profileFlags.attach(&cmd.Options)
+	annotationFlags.attach(&cmd.Options)
+	circuitBreakerFlags.attach(&cmd.Options)
+	uploadFlags.attach(&cmd.Options)
+	telemetryFlags.attach(&cmd.Options)
+	snapshotFlags.attach(&cmd.Options)
+	progressFlags.attach(&cmd.Options)
+	keepFailuresFlags.attach(&cmd.Options)
+	rerunFlags.attach(&cmd.Options)
+	retentionFlags.attach(&cmd.Options)
+	return cmd
+}
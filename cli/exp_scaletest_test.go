@@ -1,15 +1,21 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog/v3/sloggers/slogtest"
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/scaletest/harness"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -254,3 +260,124 @@ func TestScaleTestDashboard(t *testing.T) {
 		require.ErrorContains(t, err, "invalid target users \"0:0\": start and end cannot be equal")
 	})
 }
+
+func TestScaleTestCompare(t *testing.T) {
+	t.Parallel()
+
+	writeResults := func(t *testing.T, pass, fail int, p95 time.Duration) string {
+		t.Helper()
+
+		res := harness.Results{
+			SchemaVersion: harness.ResultsSchemaVersion,
+			TotalRuns:     pass + fail,
+			TotalPass:     pass,
+			TotalFail:     fail,
+			Elapsed:       httpapi.Duration(10 * time.Second),
+			Latency: map[string]harness.LatencySummary{
+				"test": {P95: httpapi.Duration(p95)},
+			},
+		}
+		path := filepath.Join(t.TempDir(), "results.json")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		defer f.Close()
+		require.NoError(t, res.WriteJSON(f))
+		return path
+	}
+
+	t.Run("NoRegression", func(t *testing.T) {
+		t.Parallel()
+
+		baseline := writeResults(t, 100, 0, time.Second)
+		candidate := writeResults(t, 98, 0, time.Second)
+		inv, _ := clitest.New(t, "exp", "scaletest", "compare", baseline, candidate)
+		out := &bytes.Buffer{}
+		inv.Stdout = out
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "No regressions.")
+	})
+
+	t.Run("Regression", func(t *testing.T) {
+		t.Parallel()
+
+		baseline := writeResults(t, 100, 0, time.Second)
+		candidate := writeResults(t, 100, 0, 2*time.Second)
+		inv, _ := clitest.New(t, "exp", "scaletest", "compare", "--output", "json", baseline, candidate)
+		out := &bytes.Buffer{}
+		inv.Stdout = out
+		err := inv.Run()
+		require.ErrorContains(t, err, "candidate regressed, 1 threshold(s) exceeded")
+
+		var comparison harness.Comparison
+		require.NoError(t, json.Unmarshal(out.Bytes(), &comparison))
+		require.Len(t, comparison.Regressions(), 1)
+		require.Equal(t, "latency p95 (test)", comparison.Regressions()[0].Metric)
+	})
+}
+
+func TestScaleTestScenario(t *testing.T) {
+	t.Parallel()
+
+	writeScenario := func(t *testing.T, scenario string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "scenario.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(scenario), 0o600))
+		return path
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeScenario(t, `
+phases:
+  - name: churn
+    strategy:
+      concurrency: 5
+    slo:
+      max_error_rate: 0.01
+    workloads:
+      - command: workspace-churn
+        flags:
+          count: 20
+`)
+		inv, _ := clitest.New(t, "exp", "scaletest", "scenario", "--dry-run", path)
+		out := &bytes.Buffer{}
+		inv.Stdout = out
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `Phase "churn":`)
+		require.Contains(t, out.String(), "exp scaletest workspace-churn --assert-max-error-rate=0.01 --concurrency=5 --count=20")
+	})
+
+	t.Run("UnknownFlag", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeScenario(t, `
+phases:
+  - name: churn
+    workloads:
+      - command: workspace-churn
+        flags:
+          cuont: 20
+`)
+		inv, _ := clitest.New(t, "exp", "scaletest", "scenario", "--dry-run", path)
+		err := inv.Run()
+		require.ErrorContains(t, err, `command "workspace-churn" has no flag "cuont"`)
+	})
+
+	t.Run("UnknownCommand", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeScenario(t, `
+phases:
+  - name: nested
+    workloads:
+      - command: scenario
+`)
+		inv, _ := clitest.New(t, "exp", "scaletest", "scenario", path)
+		err := inv.Run()
+		require.ErrorContains(t, err, `unknown scaletest command "scenario"`)
+	})
+}
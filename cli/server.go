@@ -79,6 +79,7 @@ import (
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/idlereaper"
 	"github.com/coder/coder/v2/coderd/jobreaper"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/reports"
@@ -96,9 +97,11 @@ import (
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	stringutil "github.com/coder/coder/v2/coderd/util/strings"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/webpush"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/coderd/workspacestats"
+	"github.com/coder/coder/v2/coderd/workspacestats/anomaly"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/coderd/x/nats"
 	"github.com/coder/coder/v2/codersdk"
@@ -1035,10 +1038,31 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.SwaggerEndpoint = vals.Swagger.Enable.Value()
 			}
 
-			batcher, closeBatcher, err := workspacestats.NewBatcher(ctx,
+			batcherOpts := []workspacestats.BatcherOption{
 				workspacestats.BatcherWithLogger(options.Logger.Named("batchstats")),
 				workspacestats.BatcherWithStore(options.Database),
-			)
+			}
+			if vals.Prometheus.RemoteWrite.URL.String() != "" {
+				metrics := make([]codersdk.WorkspaceAgentStatsMetric, 0, len(vals.Prometheus.RemoteWrite.Metrics.Value()))
+				for _, m := range vals.Prometheus.RemoteWrite.Metrics.Value() {
+					metrics = append(metrics, codersdk.WorkspaceAgentStatsMetric(m))
+				}
+				exporter, err := workspacestats.NewRemoteWriteExporter(workspacestats.RemoteWriteOptions{
+					Logger:         options.Logger.Named("remotewrite"),
+					URL:            vals.Prometheus.RemoteWrite.URL.String(),
+					BearerToken:    vals.Prometheus.RemoteWrite.BearerToken.Value(),
+					Metrics:        metrics,
+					RelabelConfigs: vals.Prometheus.RemoteWrite.RelabelConfigs.Value,
+				})
+				if err != nil {
+					return xerrors.Errorf("create prometheus remote write exporter: %w", err)
+				}
+				// Deferred before the batcher is closed so the final flush
+				// is still pushed.
+				defer exporter.Close()
+				batcherOpts = append(batcherOpts, workspacestats.BatcherWithExporter(exporter))
+			}
+			batcher, closeBatcher, err := workspacestats.NewBatcher(ctx, batcherOpts...)
 			if err != nil {
 				return xerrors.Errorf("failed to create agent stats batcher: %w", err)
 			}
@@ -1079,7 +1103,8 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			notificationsManager.Run(dbauthz.AsNotifier(ctx))
 
 			// Run report generator to distribute periodic reports.
-			notificationReportGenerator := reports.NewReportGenerator(ctx, logger.Named("notifications.report_generator"), options.Database, options.NotificationsEnqueuer, quartz.NewReal())
+			notificationReportGenerator := reports.NewReportGenerator(ctx, logger.Named("notifications.report_generator"), options.Database, options.NotificationsEnqueuer, quartz.NewReal(),
+				reports.WithUnusedWorkspaceNotifications(vals.Notifications.UnusedWorkspaceAfter.Value(), vals.Notifications.UnusedWorkspaceNotifyAdmins.Value()))
 			defer notificationReportGenerator.Close()
 
 			// We use a separate coderAPICloser so the Enterprise API
@@ -1223,6 +1248,10 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			purger := dbpurge.New(ctx, logger.Named("dbpurge"), options.Database, options.DeploymentValues, options.PrometheusRegistry)
 			defer purger.Close()
 
+			// Delivers workspace events to outbound webhooks.
+			webhookDispatcher := webhooks.NewDispatcher(ctx, logger.Named("webhooks"), options.Database)
+			defer webhookDispatcher.Close()
+
 			// Updates workspace usage
 			tracker := workspacestats.NewTracker(options.Database,
 				workspacestats.TrackerWithLogger(logger.Named("workspace_usage_tracker")),
@@ -1301,6 +1330,20 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			jobReaper.Start()
 			defer jobReaper.Close()
 
+			idleReaperTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
+			defer idleReaperTicker.Stop()
+			idleReaper := idlereaper.New(ctx, options.Database, options.Pubsub, coderAPI.FileCache, coderAPI.BuildUsageChecker, logger.Named("idlereaper"), idleReaperTicker.C)
+			idleReaper.Start()
+			defer idleReaper.Close()
+
+			if sensitivity := codersdk.AnomalyDetectionSensitivity(vals.StatsCollection.AnomalyDetection.Sensitivity); sensitivity != codersdk.AnomalyDetectionSensitivityOff {
+				anomalyDetector, err := anomaly.New(ctx, logger.Named("anomaly_detector"), options.Database, &coderAPI.Auditor, options.NotificationsEnqueuer, quartz.NewReal(), sensitivity)
+				if err != nil {
+					return xerrors.Errorf("create anomaly detector: %w", err)
+				}
+				defer anomalyDetector.Close()
+			}
+
 			waitForProvisionerJobs := false
 			// Currently there is no way to ask the server to shut
 			// itself down, so any exit signal will result in a non-zero
@@ -29,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/portsharing"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
@@ -80,6 +81,7 @@ type Options struct {
 	Clock                 quartz.Clock
 	Database              database.Store
 	NotificationsEnqueuer notifications.Enqueuer
+	WebhookEnqueuer       webhooks.Enqueuer
 	Pubsub                pubsub.Pubsub
 	// ContextDirtyMarker is the chatd-backed hydrate/dirty fan-out invoked
 	// from PushContextState. Nil when chatd is disabled.
@@ -88,6 +90,7 @@ type Options struct {
 	DerpMapFn                         func() *tailcfg.DERPMap
 	TailnetCoordinator                *atomic.Pointer[tailnet.Coordinator]
 	StatsReporter                     *workspacestats.Reporter
+	StatsLeases                       *workspacestats.StatsLeases
 	MetadataBatcher                   *metadatabatcher.Batcher
 	AppearanceFetcher                 *atomic.Pointer[appearance.Fetcher]
 	PublishWorkspaceUpdateFn          func(ctx context.Context, userID uuid.UUID, event wspubsub.WorkspaceEvent)
@@ -148,6 +151,7 @@ func New(opts Options, workspace database.Workspace, agent database.WorkspaceAge
 		Clock:                 opts.Clock,
 		Database:              opts.Database,
 		NotificationsEnqueuer: opts.NotificationsEnqueuer,
+		WebhookEnqueuer:       opts.WebhookEnqueuer,
 		Debounce:              30 * time.Minute,
 
 		Config: resourcesmonitor.Config{
@@ -168,6 +172,7 @@ func New(opts Options, workspace database.Workspace, agent database.WorkspaceAge
 		Database:                  opts.Database,
 		Log:                       opts.Log,
 		StatsReporter:             opts.StatsReporter,
+		StatsLeases:               opts.StatsLeases,
 		AgentStatsRefreshInterval: opts.AgentStatsRefreshInterval,
 		Experiments:               opts.Experiments,
 	}
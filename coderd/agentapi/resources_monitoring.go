@@ -18,6 +18,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/quartz"
 )
 
@@ -29,6 +31,9 @@ type ResourcesMonitoringAPI struct {
 	Clock                 quartz.Clock
 	Database              database.Store
 	NotificationsEnqueuer notifications.Enqueuer
+	// WebhookEnqueuer is optional, threshold events are not delivered to
+	// webhooks if it is nil.
+	WebhookEnqueuer webhooks.Enqueuer
 
 	Debounce time.Duration
 	Config   resourcesmonitor.Config
@@ -180,11 +185,17 @@ func (a *ResourcesMonitoringAPI) monitorMemory(ctx context.Context, datapoints [
 		return xerrors.Errorf("notify workspace OOM: %w", err)
 	}
 
+	a.enqueueThresholdWebhook(ctx, workspace, []codersdk.WebhookResourceThreshold{{
+		Resource:         "memory",
+		ThresholdPercent: a.memoryMonitor.Threshold,
+	}})
+
 	return nil
 }
 
 func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint) error {
 	outOfDiskVolumes := make([]map[string]any, 0)
+	thresholds := make([]codersdk.WebhookResourceThreshold, 0)
 
 	for i, monitor := range a.volumeMonitors {
 		if !monitor.Enabled {
@@ -217,6 +228,11 @@ func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints
 				"path":      monitor.Path,
 				"threshold": fmt.Sprintf("%d%%", monitor.Threshold),
 			})
+			thresholds = append(thresholds, codersdk.WebhookResourceThreshold{
+				Resource:         "volume",
+				Path:             monitor.Path,
+				ThresholdPercent: monitor.Threshold,
+			})
 		}
 
 		//nolint:gocritic // We need to be able to update the resource monitor here.
@@ -273,5 +289,25 @@ func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints
 		return xerrors.Errorf("notify workspace OOD: %w", err)
 	}
 
+	a.enqueueThresholdWebhook(ctx, workspace, thresholds)
+
 	return nil
 }
+
+// enqueueThresholdWebhook delivers the resources of the workspace that
+// exceeded their threshold to the webhooks subscribed to them. Failing to
+// enqueue the delivery doesn't fail the push of the datapoints.
+func (a *ResourcesMonitoringAPI) enqueueThresholdWebhook(ctx context.Context, workspace database.Workspace, thresholds []codersdk.WebhookResourceThreshold) {
+	if a.WebhookEnqueuer == nil {
+		return
+	}
+	err := a.WebhookEnqueuer.Enqueue(ctx, codersdk.WebhookPayload{
+		Event:      codersdk.WebhookEventWorkspaceResourceThresholdExceeded,
+		Timestamp:  dbtime.Time(a.Clock.Now()),
+		Workspace:  webhooks.Workspace(workspace),
+		Thresholds: thresholds,
+	})
+	if err != nil {
+		a.Log.Warn(ctx, "failed to enqueue resource threshold webhook", slog.Error(err))
+	}
+}
@@ -2,6 +2,8 @@ package agentapi
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,16 +19,22 @@ import (
 )
 
 type StatsAPI struct {
-	AgentID                   uuid.UUID
-	AgentName                 string
-	Workspace                 *CachedWorkspaceFields
-	Database                  database.Store
-	Log                       slog.Logger
-	StatsReporter             *workspacestats.Reporter
+	AgentID       uuid.UUID
+	AgentName     string
+	Workspace     *CachedWorkspaceFields
+	Database      database.Store
+	Log           slog.Logger
+	StatsReporter *workspacestats.Reporter
+	// StatsLeases deduplicates the stats of agents connected to several
+	// replicas. Optional.
+	StatsLeases               *workspacestats.StatsLeases
 	AgentStatsRefreshInterval time.Duration
 	Experiments               codersdk.Experiments
 
 	TimeNowFn func() time.Time // defaults to dbtime.Now()
+
+	leaseOnce sync.Once
+	lease     *workspacestats.StatsLease
 }
 
 func (a *StatsAPI) now() time.Time {
@@ -40,20 +48,25 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 	res := &agentproto.UpdateStatsResponse{
 		ReportInterval: durationpb.New(a.AgentStatsRefreshInterval),
 	}
+	// The agent looks for the report interval as soon as it connects, which
+	// revokes the stats lease of its previous connections.
+	if a.StatsLeases != nil {
+		a.leaseOnce.Do(func() {
+			a.lease = a.StatsLeases.Acquire(ctx, a.AgentID)
+		})
+	}
 	// An empty stat means it's just looking for the report interval.
 	if req.Stats == nil {
 		return res, nil
 	}
+	now := a.now()
+	if a.lease != nil && !a.lease.Admit(ctx) {
+		return res, nil
+	}
 
-	// If cache is empty (prebuild or invalid), fall back to DB
-	var ws database.WorkspaceIdentity
-	var ok bool
-	if ws, ok = a.Workspace.AsWorkspaceIdentity(); !ok {
-		w, err := a.Database.GetWorkspaceByAgentID(ctx, a.AgentID)
-		if err != nil {
-			return nil, xerrors.Errorf("get workspace by agent ID %q: %w", a.AgentID, err)
-		}
-		ws = database.WorkspaceIdentityFromWorkspace(w)
+	ws, err := a.workspace(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	a.Log.Debug(ctx, "read stats report",
@@ -62,19 +75,10 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 		slog.F("payload", req),
 	)
 
-	if a.Experiments.Enabled(codersdk.ExperimentWorkspaceUsage) {
-		// while the experiment is enabled we will not report
-		// session stats from the agent. This is because it is
-		// being handled by the CLI and the postWorkspaceUsage route.
-		req.Stats.SessionCountSsh = 0
-		req.Stats.SessionCountJetbrains = 0
-		req.Stats.SessionCountVscode = 0
-		req.Stats.SessionCountReconnectingPty = 0
-	}
-
-	err := a.StatsReporter.ReportAgentStats(
+	a.dropSessionCounts(req.Stats)
+	err = a.StatsReporter.ReportAgentStats(
 		ctx,
-		a.now(),
+		now,
 		ws,
 		a.AgentID,
 		a.AgentName,
@@ -87,3 +91,65 @@ func (a *StatsAPI) UpdateStats(ctx context.Context, req *agentproto.UpdateStatsR
 
 	return res, nil
 }
+
+// ReportStatsBatch reports stats the agent collected at different times. It
+// backs the HTTP endpoint for agents behind proxies that break the connection
+// to the Agent API, which buffer their stats until they can be delivered.
+func (a *StatsAPI) ReportStatsBatch(ctx context.Context, reports []workspacestats.TimestampedStats) error {
+	if a.StatsLeases != nil {
+		reports = slices.DeleteFunc(reports, func(report workspacestats.TimestampedStats) bool {
+			return !a.StatsLeases.Admit(ctx, a.AgentID, report.CollectedAt)
+		})
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+	ws, err := a.workspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.Log.Debug(ctx, "read stats batch",
+		slog.F("workspace_id", ws.ID),
+		slog.F("count", len(reports)),
+	)
+
+	for _, report := range reports {
+		a.dropSessionCounts(report.Stats)
+	}
+	err = a.StatsReporter.ReportAgentStatsBatch(ctx, ws, a.AgentID, a.AgentName, reports, false)
+	if err != nil {
+		return xerrors.Errorf("report agent stats batch: %w", err)
+	}
+	// The stats are only recorded as ingested once the batcher, which keeps
+	// them until they are inserted, has them. Otherwise a retry of a report
+	// that failed would be dropped.
+	if a.StatsLeases != nil {
+		a.StatsLeases.Ingested(ctx, a.AgentID, reports[len(reports)-1].CollectedAt)
+	}
+	return nil
+}
+
+func (a *StatsAPI) workspace(ctx context.Context) (database.WorkspaceIdentity, error) {
+	// If cache is empty (prebuild or invalid), fall back to DB
+	if ws, ok := a.Workspace.AsWorkspaceIdentity(); ok {
+		return ws, nil
+	}
+	w, err := a.Database.GetWorkspaceByAgentID(ctx, a.AgentID)
+	if err != nil {
+		return database.WorkspaceIdentity{}, xerrors.Errorf("get workspace by agent ID %q: %w", a.AgentID, err)
+	}
+	return database.WorkspaceIdentityFromWorkspace(w), nil
+}
+
+func (a *StatsAPI) dropSessionCounts(stats *agentproto.Stats) {
+	if a.Experiments.Enabled(codersdk.ExperimentWorkspaceUsage) {
+		// while the experiment is enabled we will not report
+		// session stats from the agent. This is because it is
+		// being handled by the CLI and the postWorkspaceUsage route.
+		stats.SessionCountSsh = 0
+		stats.SessionCountJetbrains = 0
+		stats.SessionCountVscode = 0
+		stats.SessionCountReconnectingPty = 0
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 func TestUpdateStats(t *testing.T) {
@@ -75,7 +76,9 @@ func TestUpdateStats(t *testing.T) {
 
 			templateScheduleStore = schedule.MockTemplateScheduleStore{
 				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
-					panic("should not be called")
+					// Only the dormancy activity signal is needed, as the
+					// workspace has no autostart schedule.
+					return schedule.TemplateScheduleOptions{}, nil
 				},
 				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
 					panic("not implemented")
@@ -398,6 +401,175 @@ func TestUpdateStats(t *testing.T) {
 		require.True(t, updateAgentMetricsFnCalled)
 	})
 
+	t.Run("DormancyActivitySignal", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			now = dbtime.Now()
+			dbM = dbmock.NewMockStore(gomock.NewController(t))
+			ps  = pubsub.NewInMemory()
+
+			templateScheduleStore = schedule.MockTemplateScheduleStore{
+				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+					return schedule.TemplateScheduleOptions{
+						DormancyActivitySignal: codersdk.DormancyActivitySignalSession,
+					}, nil
+				},
+				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
+					panic("not implemented")
+				},
+			}
+			tickCh  = make(chan time.Time)
+			flushCh = make(chan int, 1)
+			wut     = workspacestats.NewTracker(dbM,
+				workspacestats.TrackerWithTickFlush(tickCh, flushCh),
+			)
+
+			// An idle connection left open, without sessions.
+			req = &agentproto.UpdateStatsRequest{
+				Stats: &agentproto.Stats{
+					ConnectionsByProto: map[string]int64{
+						"tcp": 1,
+					},
+					ConnectionCount: 1,
+					RxBytes:         200,
+					TxBytes:         200,
+				},
+			}
+		)
+		api := agentapi.StatsAPI{
+			AgentID:   agent.ID,
+			AgentName: agent.Name,
+			Workspace: &workspaceAsCacheFields,
+			Database:  dbM,
+			StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+				Database:               dbM,
+				Pubsub:                 ps,
+				StatsBatcher:           &workspacestatstest.StatsBatcher{},
+				UsageTracker:           wut,
+				TemplateScheduleStore:  templateScheduleStorePtr(templateScheduleStore),
+				DormancyActivitySignal: codersdk.DormancyActivitySignalConnection,
+			}),
+			AgentStatsRefreshInterval: 10 * time.Second,
+			TimeNowFn: func() time.Time {
+				return now
+			},
+		}
+		defer wut.Close()
+
+		// The deadline is still bumped because of the connection.
+		dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
+			WorkspaceID:   workspace.ID,
+			NextAutostart: time.Time{}.UTC(),
+		}).Return(nil)
+
+		// Workspace last used at is not bumped, as the template only counts
+		// sessions towards dormancy.
+		_, err := api.UpdateStats(context.Background(), req)
+		require.NoError(t, err)
+
+		tickCh <- now
+		count := <-flushCh
+		require.Equal(t, 0, count, "expected no workspace to be flushed")
+	})
+
+	t.Run("DormancyActivitySignalTraffic", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name    string
+			rxBytes int64
+			txBytes int64
+			bumped  bool
+		}{
+			{name: "BelowThreshold", rxBytes: 32 * 1024, txBytes: 32*1024 - 1, bumped: false},
+			{name: "AtThreshold", rxBytes: 32 * 1024, txBytes: 32 * 1024, bumped: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				var (
+					now      = dbtime.Now()
+					dbM      = dbmock.NewMockStore(gomock.NewController(t))
+					getCalls atomic.Int64
+
+					templateScheduleStore = schedule.MockTemplateScheduleStore{
+						GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+							getCalls.Add(1)
+							return schedule.TemplateScheduleOptions{
+								DormancyActivitySignal: codersdk.DormancyActivitySignalTraffic,
+							}, nil
+						},
+						SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
+							panic("not implemented")
+						},
+					}
+					tickCh  = make(chan time.Time)
+					flushCh = make(chan int, 1)
+					wut     = workspacestats.NewTracker(dbM,
+						workspacestats.TrackerWithTickFlush(tickCh, flushCh),
+					)
+
+					req = &agentproto.UpdateStatsRequest{
+						Stats: &agentproto.Stats{
+							ConnectionsByProto: map[string]int64{
+								"tcp": 1,
+							},
+							ConnectionCount: 1,
+							RxBytes:         tc.rxBytes,
+							TxBytes:         tc.txBytes,
+						},
+					}
+				)
+				api := agentapi.StatsAPI{
+					AgentID:   agent.ID,
+					AgentName: agent.Name,
+					Workspace: &workspaceAsCacheFields,
+					Database:  dbM,
+					StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+						Database:               dbM,
+						Pubsub:                 pubsub.NewInMemory(),
+						StatsBatcher:           &workspacestatstest.StatsBatcher{},
+						UsageTracker:           wut,
+						TemplateScheduleStore:  templateScheduleStorePtr(templateScheduleStore),
+						DormancyActivitySignal: codersdk.DormancyActivitySignalConnection,
+					}),
+					AgentStatsRefreshInterval: 10 * time.Second,
+					TimeNowFn: func() time.Time {
+						return now
+					},
+				}
+				defer wut.Close()
+
+				dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
+					WorkspaceID:   workspace.ID,
+					NextAutostart: time.Time{}.UTC(),
+				}).Return(nil).Times(2)
+				if tc.bumped {
+					dbM.EXPECT().BatchUpdateWorkspaceLastUsedAt(gomock.Any(), database.BatchUpdateWorkspaceLastUsedAtParams{
+						IDs:        []uuid.UUID{workspace.ID},
+						LastUsedAt: now,
+					}).Return(nil)
+				}
+
+				for range 2 {
+					_, err := api.UpdateStats(context.Background(), req)
+					require.NoError(t, err)
+				}
+				// The signal of the template is cached.
+				require.EqualValues(t, 1, getCalls.Load())
+
+				tickCh <- now
+				count := <-flushCh
+				if tc.bumped {
+					require.Equal(t, 1, count, "expected one flush with one id")
+				} else {
+					require.Equal(t, 0, count, "expected no workspace to be flushed")
+				}
+			})
+		}
+	})
+
 	t.Run("WorkspaceUsageExperiment", func(t *testing.T) {
 		t.Parallel()
 
@@ -408,7 +580,8 @@ func TestUpdateStats(t *testing.T) {
 
 			templateScheduleStore = schedule.MockTemplateScheduleStore{
 				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
-					t.Fatal("getfn should not be called")
+					// Only the dormancy activity signal is needed, as the
+					// workspace has no autostart schedule.
 					return schedule.TemplateScheduleOptions{}, nil
 				},
 				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
@@ -548,7 +721,9 @@ func TestUpdateStats(t *testing.T) {
 
 			templateScheduleStore = schedule.MockTemplateScheduleStore{
 				GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
-					panic("should not be called")
+					// Only the dormancy activity signal is needed, as the
+					// workspace has no autostart schedule.
+					return schedule.TemplateScheduleOptions{}, nil
 				},
 				SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
 					panic("not implemented")
@@ -667,6 +842,85 @@ func TestUpdateStats(t *testing.T) {
 	})
 }
 
+func TestReportStatsBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitShort)
+		ps      = pubsub.NewInMemory()
+		clk     = quartz.NewMock(t)
+		batcher = &workspacestatstest.StatsBatcher{}
+		agentID = uuid.New()
+		ws      = database.Workspace{
+			ID:         uuid.New(),
+			OwnerID:    uuid.New(),
+			TemplateID: uuid.New(),
+		}
+		reports = func() []workspacestats.TimestampedStats {
+			// Without connections, the stats don't bump the activity of the
+			// workspace.
+			return []workspacestats.TimestampedStats{
+				{CollectedAt: clk.Now().Add(-2 * time.Minute), Stats: &agentproto.Stats{TxBytes: 1}},
+				{CollectedAt: clk.Now().Add(-time.Minute), Stats: &agentproto.Stats{TxBytes: 2}},
+			}
+		}
+	)
+	// newReplica returns the stats API of the agent on a new replica, that
+	// shares the pubsub with the other replicas.
+	newReplica := func(dbM database.Store, cached *agentapi.CachedWorkspaceFields) *agentapi.StatsAPI {
+		leases, err := workspacestats.NewStatsLeases(testutil.Logger(t), ps, uuid.New(), clk, time.Minute)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = leases.Close() })
+		return &agentapi.StatsAPI{
+			AgentID:   agentID,
+			Workspace: cached,
+			Database:  dbM,
+			Log:       testutil.Logger(t),
+			StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+				Database:     dbM,
+				Pubsub:       ps,
+				StatsBatcher: batcher,
+			}),
+			StatsLeases: leases,
+		}
+	}
+	cached := &agentapi.CachedWorkspaceFields{}
+	cached.UpdateValues(ws)
+
+	// Given: a replica that fails to look up the workspace of the agent.
+	dbM := dbmock.NewMockStore(gomock.NewController(t))
+	dbM.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agentID).Return(database.Workspace{}, sql.ErrConnDone)
+	failing := newReplica(dbM, &agentapi.CachedWorkspaceFields{})
+	replica1 := newReplica(dbM, cached)
+	replica2 := newReplica(dbM, cached)
+
+	// When: the agent posts a batch that fails.
+	require.Error(t, failing.ReportStatsBatch(ctx, reports()))
+
+	// Then: its retry on another replica is not dropped.
+	require.NoError(t, replica1.ReportStatsBatch(ctx, reports()))
+	batcher.Mu.Lock()
+	require.EqualValues(t, 2, batcher.Called)
+	batcher.Mu.Unlock()
+
+	// When: the agent posts the same batch to another replica.
+	require.NoError(t, replica2.ReportStatsBatch(ctx, reports()))
+
+	// Then: it is dropped.
+	batcher.Mu.Lock()
+	require.EqualValues(t, 2, batcher.Called)
+	batcher.Mu.Unlock()
+
+	// When: the agent posts newer stats to the other replica.
+	clk.Advance(time.Minute)
+	require.NoError(t, replica2.ReportStatsBatch(ctx, reports()[1:]))
+
+	// Then: they are ingested.
+	batcher.Mu.Lock()
+	require.EqualValues(t, 3, batcher.Called)
+	batcher.Mu.Unlock()
+}
+
 func templateScheduleStorePtr(store schedule.TemplateScheduleStore) *atomic.Pointer[schedule.TemplateScheduleStore] {
 	var ptr atomic.Pointer[schedule.TemplateScheduleStore]
 	ptr.Store(&store)
@@ -2548,6 +2548,56 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/deployment/purge-status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get database purge status",
+                "operationId": "get-database-purge-status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DatabasePurgeStatus"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/deployment/rate-limits": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get API rate limit consumption",
+                "operationId": "get-api-rate-limit-consumption",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.APIRateLimitConsumption"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/deployment/ssh": {
             "get": {
                 "produces": [
@@ -2598,6 +2648,56 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/deployment/stats/watch": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Watch deployment stats",
+                "operationId": "watch-deployment-stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/deployment/stats/watch-ws": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Watch deployment stats via WebSockets",
+                "operationId": "watch-deployment-stats-via-websockets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ServerSentEvent"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/derp-map": {
             "get": {
                 "tags": [
@@ -3267,6 +3367,140 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/groups/{group}/usage-quotas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get group usage quotas",
+                "operationId": "get-group-usage-quotas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.GroupUsageQuota"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/groups/{group}/usage-quotas/{metric}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Upsert group usage quota",
+                "operationId": "upsert-group-usage-quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "connection_hours",
+                            "active_hours"
+                        ],
+                        "type": "string",
+                        "description": "Usage quota metric",
+                        "name": "metric",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Upsert group usage quota request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpsertGroupUsageQuotaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GroupUsageQuota"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Delete group usage quota",
+                "operationId": "delete-group-usage-quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "connection_hours",
+                            "active_hours"
+                        ],
+                        "type": "string",
+                        "description": "Usage quota metric",
+                        "name": "metric",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/init-script/{os}/{arch}": {
             "get": {
                 "produces": [
@@ -3300,7 +3534,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v2/insights/daus": {
+        "/api/v2/insights/access-methods": {
             "get": {
                 "produces": [
                     "application/json"
@@ -3308,22 +3542,68 @@ const docTemplate = `{
                 "tags": [
                     "Insights"
                 ],
-                "summary": "Get deployment DAUs",
-                "operationId": "get-deployment-daus",
+                "summary": "Get insights about access method usage",
+                "operationId": "get-insights-about-access-method-usage",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Time-zone offset (e.g. -2)",
-                        "name": "tz_offset",
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Template IDs",
+                        "name": "template_ids",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "month",
+                            "week",
+                            "day"
+                        ],
+                        "type": "string",
+                        "description": "Interval, defaults to day",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone name to bucket intervals in (e.g. America/St_Johns), defaults to the UTC offset of the start time",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                            "$ref": "#/definitions/codersdk.AccessMethodInsightsResponse"
                         }
                     }
                 },
@@ -3334,7 +3614,7 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/insights/templates": {
+        "/api/v2/insights/client-region-latency": {
             "get": {
                 "produces": [
                     "application/json"
@@ -3342,8 +3622,8 @@ const docTemplate = `{
                 "tags": [
                     "Insights"
                 ],
-                "summary": "Get insights about templates",
-                "operationId": "get-insights-about-templates",
+                "summary": "Get connection latency insights by client region",
+                "operationId": "get-connection-latency-insights-by-client-region",
                 "parameters": [
                     {
                         "type": "string",
@@ -3360,9 +3640,364 @@ const docTemplate = `{
                         "name": "end_time",
                         "in": "query",
                         "required": true
-                    },
-                    {
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ClientRegionLatencyInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/costs": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about workspace costs",
+                "operationId": "get-insights-about-workspace-costs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "workspace",
+                            "user",
+                            "organization"
+                        ],
+                        "type": "string",
+                        "description": "Group costs by",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CostInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/daus": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get deployment DAUs",
+                "operationId": "get-deployment-daus",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Time-zone offset (e.g. -2)",
+                        "name": "tz_offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/deployment": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about the deployment",
+                "operationId": "get-insights-about-the-deployment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DeploymentInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/prebuilds": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about prebuilt workspaces",
+                "operationId": "get-insights-about-prebuilt-workspaces",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Template IDs",
+                        "name": "template_ids",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PrebuildInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/region-latency": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get connection latency insights by DERP region",
+                "operationId": "get-connection-latency-insights-by-derp-region",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.RegionLatencyInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/template-comparison": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights comparing templates",
+                "operationId": "get-insights-comparing-templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Template IDs",
+                        "name": "template_ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateComparisonInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/templates": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about templates",
+                "operationId": "get-insights-about-templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
                         "enum": [
+                            "month",
                             "week",
                             "day"
                         ],
@@ -3372,6 +4007,159 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone name to bucket intervals in (e.g. America/St_Johns), defaults to the UTC offset of the start time",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Template IDs",
+                        "name": "template_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "active_users",
+                                "apps_usage",
+                                "parameters_usage"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Fields of the report to include, all of them by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/usage-heatmap": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about hour-of-week usage",
+                "operationId": "get-insights-about-hour-of-week-usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Template IDs",
+                        "name": "template_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone name to bucket hours in (e.g. America/St_Johns), defaults to the UTC offset of the start time",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UsageHeatmapInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/insights/user-activity": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about user activity",
+                "operationId": "get-insights-about-user-activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "array",
                         "items": {
@@ -3381,13 +4169,23 @@ const docTemplate = `{
                         "description": "Template IDs",
                         "name": "template_ids",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateInsightsResponse"
+                            "$ref": "#/definitions/codersdk.UserActivityInsightsResponse"
                         }
                     }
                 },
@@ -3398,7 +4196,7 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/insights/user-activity": {
+        "/api/v2/insights/user-bandwidth": {
             "get": {
                 "produces": [
                     "application/json"
@@ -3406,8 +4204,8 @@ const docTemplate = `{
                 "tags": [
                     "Insights"
                 ],
-                "summary": "Get insights about user activity",
-                "operationId": "get-insights-about-user-activity",
+                "summary": "Get insights about user bandwidth",
+                "operationId": "get-insights-about-user-bandwidth",
                 "parameters": [
                     {
                         "type": "string",
@@ -3434,13 +4232,35 @@ const docTemplate = `{
                         "description": "Template IDs",
                         "name": "template_ids",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UserActivityInsightsResponse"
+                            "$ref": "#/definitions/codersdk.UserBandwidthInsightsResponse"
                         }
                     }
                 },
@@ -3487,6 +4307,16 @@ const docTemplate = `{
                         "description": "Template IDs",
                         "name": "template_ids",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3543,6 +4373,53 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/insights/workspace-health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about workspace health",
+                "operationId": "get-insights-about-workspace-health",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Template IDs",
+                        "name": "template_ids",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format, csv can also be requested with an Accept: text/csv header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceHealthInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/licenses": {
             "get": {
                 "produces": [
@@ -3633,6 +4510,31 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/licenses/seat-usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get license seat usage",
+                "operationId": "get-license-seat-usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LicenseSeatUsage"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/licenses/{id}": {
             "delete": {
                 "produces": [
@@ -4744,6 +5646,57 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/organizations/{organization}/insights": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Insights"
+                ],
+                "summary": "Get insights about an organization",
+                "operationId": "get-insights-about-an-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OrganizationInsightsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/organizations/{organization}/members": {
             "get": {
                 "produces": [
@@ -5127,6 +6080,48 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/organizations/{organization}/members/{user}/usage-quota": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get usage quota by user",
+                "operationId": "get-usage-quota-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UsageQuota"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/organizations/{organization}/members/{user}/workspace-quota": {
             "get": {
                 "produces": [
@@ -6609,11 +7604,98 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PrebuildsSettings"
+                            "$ref": "#/definitions/codersdk.PrebuildsSettings"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/provisionerkeys/{provisionerkey}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Fetch provisioner key details",
+                "operationId": "fetch-provisioner-key-details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provisioner Key",
+                        "name": "provisionerkey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ProvisionerKey"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderProvisionerKey": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/regions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WorkspaceProxies"
+                ],
+                "summary": "Get site-wide regions for workspace connections",
+                "operationId": "get-site-wide-regions-for-workspace-connections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/replicas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get active replicas",
+                "operationId": "get-active-replicas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Replica"
+                            }
                         }
-                    },
-                    "304": {
-                        "description": "Not Modified"
                     }
                 },
                 "security": [
@@ -6623,55 +7705,81 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/provisionerkeys/{provisionerkey}": {
+        "/api/v2/scaletest/results": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "General"
                 ],
-                "summary": "Fetch provisioner key details",
-                "operationId": "fetch-provisioner-key-details",
+                "summary": "List scaletest results",
+                "operationId": "list-scaletest-results",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provisioner Key",
-                        "name": "provisionerkey",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by test name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerKey"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ScaletestResult"
+                            }
                         }
                     }
                 },
                 "security": [
                     {
-                        "CoderProvisionerKey": []
+                        "CoderSessionToken": []
                     }
                 ]
-            }
-        },
-        "/api/v2/regions": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "WorkspaceProxies"
+                    "General"
+                ],
+                "summary": "Upload scaletest result",
+                "operationId": "upload-scaletest-result",
+                "parameters": [
+                    {
+                        "description": "Scaletest result",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UploadScaletestResultRequest"
+                        }
+                    }
                 ],
-                "summary": "Get site-wide regions for workspace connections",
-                "operationId": "get-site-wide-regions-for-workspace-connections",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
+                            "$ref": "#/definitions/codersdk.ScaletestResult"
                         }
                     }
                 },
@@ -6682,24 +7790,31 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/replicas": {
+        "/api/v2/scaletest/results/{scaletestresult}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "General"
+                ],
+                "summary": "Get scaletest result by ID",
+                "operationId": "get-scaletest-result-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Scaletest result ID",
+                        "name": "scaletestresult",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get active replicas",
-                "operationId": "get-active-replicas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Replica"
-                            }
+                            "$ref": "#/definitions/codersdk.ScaletestResult"
                         }
                     }
                 },
@@ -7616,8 +8731,170 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Update template settings by ID",
-                "operationId": "update-template-settings-by-id",
+                "summary": "Update template settings by ID",
+                "operationId": "update-template-settings-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch template settings request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateMeta"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Template"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/templates/{template}/acl": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get template ACLs",
+                "operationId": "get-template-acls",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateACL"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Update template ACL",
+                "operationId": "update-template-acl",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update template ACL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/templates/{template}/acl/available": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get template available acl users/groups",
+                "operationId": "get-template-available-acl-usersgroups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ACLAvailable"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/templates/{template}/daus": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template DAUs by ID",
+                "operationId": "get-template-daus-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -7626,22 +8903,13 @@ const docTemplate = `{
                         "name": "template",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Patch template settings request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateTemplateMeta"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.DAUsResponse"
                         }
                     }
                 },
@@ -7652,16 +8920,16 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/templates/{template}/acl": {
+        "/api/v2/templates/{template}/idle-policy": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get template ACLs",
-                "operationId": "get-template-acls",
+                "summary": "Get template idle policy",
+                "operationId": "get-template-idle-policy",
                 "parameters": [
                     {
                         "type": "string",
@@ -7676,7 +8944,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateACL"
+                            "$ref": "#/definitions/codersdk.TemplateIdlePolicy"
                         }
                     }
                 },
@@ -7686,7 +8954,7 @@ const docTemplate = `{
                     }
                 ]
             },
-            "patch": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -7694,10 +8962,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Update template ACL",
-                "operationId": "update-template-acl",
+                "summary": "Upsert template idle policy",
+                "operationId": "upsert-template-idle-policy",
                 "parameters": [
                     {
                         "type": "string",
@@ -7708,12 +8976,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Update template ACL request",
+                        "description": "Upsert template idle policy request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                            "$ref": "#/definitions/codersdk.UpsertTemplateIdlePolicyRequest"
                         }
                     }
                 ],
@@ -7721,7 +8989,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.TemplateIdlePolicy"
                         }
                     }
                 },
@@ -7730,18 +8998,13 @@ const docTemplate = `{
                         "CoderSessionToken": []
                     }
                 ]
-            }
-        },
-        "/api/v2/templates/{template}/acl/available": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get template available acl users/groups",
-                "operationId": "get-template-available-acl-usersgroups",
+                "summary": "Delete template idle policy",
+                "operationId": "delete-template-idle-policy",
                 "parameters": [
                     {
                         "type": "string",
@@ -7753,14 +9016,8 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ACLAvailable"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 },
                 "security": [
@@ -7770,7 +9027,7 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/templates/{template}/daus": {
+        "/api/v2/templates/{template}/idle-workspaces": {
             "get": {
                 "produces": [
                     "application/json"
@@ -7778,8 +9035,8 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template DAUs by ID",
-                "operationId": "get-template-daus-by-id",
+                "summary": "Get idle workspaces by template",
+                "operationId": "get-idle-workspaces-by-template",
                 "parameters": [
                     {
                         "type": "string",
@@ -7794,7 +9051,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.IdleWorkspace"
+                            }
                         }
                     }
                 },
@@ -10995,57 +12255,253 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "204": {
+                        "description": "No Content"
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/api/v2/users/{user}/workspace/{workspacename}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace metadata by user and workspace name",
+                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build by user, workspace name, and build number",
+                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "number",
+                        "description": "Build number",
+                        "name": "buildnumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/users/{user}/workspaces": {
+            "post": {
+                "description": "Create a new workspace using a template. The request must\nspecify either the Template ID or the Template Version ID,\nnot both. If the Template ID is specified, the active version\nof the template will be used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Create user workspace",
+                "operationId": "create-user-workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username, UUID, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "List webhooks",
+                "operationId": "list-webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Webhook"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Create webhook",
+                "operationId": "create-webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Webhook"
+                        }
                     }
                 },
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
-                ],
-                "x-apidocgen": {
-                    "skip": true
-                }
+                ]
             }
         },
-        "/api/v2/users/{user}/workspace/{workspacename}": {
+        "/api/v2/webhooks/{webhook}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Notifications"
                 ],
-                "summary": "Get workspace metadata by user and workspace name",
-                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "summary": "Get webhook by ID",
+                "operationId": "get-webhook-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.Webhook"
                         }
                     }
                 },
@@ -11054,48 +12510,26 @@ const docTemplate = `{
                         "CoderSessionToken": []
                     }
                 ]
-            }
-        },
-        "/api/v2/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
-                    "Builds"
+                    "Notifications"
                 ],
-                "summary": "Get workspace build by user, workspace name, and build number",
-                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "summary": "Delete webhook",
+                "operationId": "delete-webhook",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "number",
-                        "description": "Build number",
-                        "name": "buildnumber",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 },
                 "security": [
@@ -11105,43 +12539,46 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/users/{user}/workspaces": {
-            "post": {
-                "description": "Create a new workspace using a template. The request must\nspecify either the Template ID or the Template Version ID,\nnot both. If the Template ID is specified, the active version\nof the template will be used.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v2/webhooks/{webhook}/deliveries": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Notifications"
                 ],
-                "summary": "Create user workspace",
-                "operationId": "create-user-workspace",
+                "summary": "List webhook deliveries",
+                "operationId": "list-webhook-deliveries",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Username, UUID, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Create workspace request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
-                        }
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WebhookDelivery"
+                            }
                         }
                     }
                 },
@@ -11630,6 +13067,45 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v2/workspaceagents/me/stats": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent stats batch",
+                "operationId": "submit-workspace-agent-stats-batch",
+                "parameters": [
+                    {
+                        "description": "Stats batch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostStatsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/workspaceagents/me/tasks/{task}/log-snapshot": {
             "post": {
                 "consumes": [
@@ -12072,7 +13548,130 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v2/workspaceagents/{workspaceagent}/startup-logs": {
+        "/api/v2/workspaceagents/{workspaceagent}/startup-logs": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Get logs by workspace agent",
+                "operationId": "removed-get-logs-by-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disable compression for WebSocket connection",
+                        "name": "no_compression",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/workspaceagents/{workspaceagent}/stats/timeseries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent stats time series",
+                "operationId": "get-workspace-agent-stats-time-series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time, defaults to 24 hours before end time",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time, defaults to now",
+                        "name": "end_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size as a duration, e.g. 5m",
+                        "name": "bucket_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of metrics, defaults to all metrics",
+                        "name": "metrics",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentStatsTimeSeries"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/workspaceagents/{workspaceagent}/top-processes": {
             "get": {
                 "produces": [
                     "application/json"
@@ -12080,8 +13679,8 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Removed: Get logs by workspace agent",
-                "operationId": "removed-get-logs-by-workspace-agent",
+                "summary": "Get top processes for workspace agent",
+                "operationId": "get-top-processes-for-workspace-agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -12092,27 +13691,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
+                        "enum": [
+                            "cpu",
+                            "memory"
+                        ],
+                        "type": "string",
+                        "description": "Resource to sort processes by",
+                        "name": "sort_by",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Disable compression for WebSocket connection",
-                        "name": "no_compression",
+                        "description": "Number of processes to return",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -12120,10 +13711,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentTopProcessesResponse"
                         }
                     }
                 },
@@ -13725,6 +15313,97 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v2/workspaces/{workspace}/rightsizing": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace resource right-sizing recommendations",
+                "operationId": "get-workspace-resource-right-sizing-recommendations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days of usage to base the recommendations on, defaults to 14",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceRightsizing"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
+        "/api/v2/workspaces/{workspace}/timeline": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace timeline by ID",
+                "operationId": "get-workspace-timeline-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceTimelineEvent"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ]
+            }
+        },
         "/api/v2/workspaces/{workspace}/timings": {
             "get": {
                 "produces": [
@@ -14487,6 +16166,65 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.AgentMetric": {
+            "type": "object",
+            "required": [
+                "name",
+                "type",
+                "value"
+            ],
+            "properties": {
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.AgentMetricLabel"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "counter",
+                        "gauge"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/agentsdk.AgentMetricType"
+                        }
+                    ]
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "agentsdk.AgentMetricLabel": {
+            "type": "object",
+            "required": [
+                "name",
+                "value"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.AgentMetricType": {
+            "type": "string",
+            "enum": [
+                "counter",
+                "gauge"
+            ],
+            "x-enum-varnames": [
+                "AgentMetricTypeCounter",
+                "AgentMetricTypeGauge"
+            ]
+        },
         "agentsdk.AuthenticateResponse": {
             "type": "object",
             "properties": {
@@ -14633,6 +16371,17 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostStatsRequest": {
+            "type": "object",
+            "properties": {
+                "stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.TimestampedStats"
+                    }
+                }
+            }
+        },
         "agentsdk.ReinitializationEvent": {
             "type": "object",
             "properties": {
@@ -14658,6 +16407,91 @@ const docTemplate = `{
                 "ReinitializeReasonPrebuildClaimed"
             ]
         },
+        "agentsdk.Stats": {
+            "type": "object",
+            "properties": {
+                "connection_count": {
+                    "description": "ConnectionCount is the number of connections received by an agent.",
+                    "type": "integer"
+                },
+                "connection_median_latency_ms": {
+                    "description": "ConnectionMedianLatencyMS is the median latency of all connections in milliseconds.",
+                    "type": "number"
+                },
+                "connections_by_proto": {
+                    "description": "ConnectionsByProto is a count of connections by protocol.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "metrics": {
+                    "description": "Metrics collected by the agent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.AgentMetric"
+                    }
+                },
+                "rx_bytes": {
+                    "description": "RxBytes is the number of received bytes.",
+                    "type": "integer"
+                },
+                "rx_packets": {
+                    "description": "RxPackets is the number of received packets.",
+                    "type": "integer"
+                },
+                "session_count_jetbrains": {
+                    "description": "SessionCountJetBrains is the number of connections received by an agent\nthat are from our JetBrains extension.",
+                    "type": "integer"
+                },
+                "session_count_reconnecting_pty": {
+                    "description": "SessionCountReconnectingPTY is the number of connections received by an agent\nthat are from the reconnecting web terminal.",
+                    "type": "integer"
+                },
+                "session_count_ssh": {
+                    "description": "SessionCountSSH is the number of connections received by an agent\nthat are normal, non-tagged SSH sessions.",
+                    "type": "integer"
+                },
+                "session_count_vscode": {
+                    "description": "SessionCountVSCode is the number of connections received by an agent\nthat are from our VS Code extension.",
+                    "type": "integer"
+                },
+                "tx_bytes": {
+                    "description": "TxBytes is the number of transmitted bytes.",
+                    "type": "integer"
+                },
+                "tx_packets": {
+                    "description": "TxPackets is the number of transmitted bytes.",
+                    "type": "integer"
+                }
+            }
+        },
+        "agentsdk.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "Dropped is the number of stats in a PostStatsRequest that were not\nrecorded because they were collected too long ago.",
+                    "type": "integer"
+                },
+                "report_interval": {
+                    "description": "ReportInterval is the duration after which the agent should send stats\nagain.",
+                    "type": "integer"
+                }
+            }
+        },
+        "agentsdk.TimestampedStats": {
+            "type": "object",
+            "properties": {
+                "collected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "stats": {
+                    "$ref": "#/definitions/agentsdk.Stats"
+                }
+            }
+        },
         "coderd.cspViolation": {
             "type": "object",
             "properties": {
@@ -15851,6 +17685,136 @@ const docTemplate = `{
                 "APIKeyScopeWorkspaceProxyUpdate"
             ]
         },
+        "codersdk.APIRateLimitConsumption": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Limit is the number of requests a user may send to an endpoint per\nwindow. Zero means the API rate limit is disabled.",
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.APIRateLimitUserConsumption"
+                    }
+                },
+                "window_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.APIRateLimitUserConsumption": {
+            "type": "object",
+            "properties": {
+                "consumption": {
+                    "description": "Consumption is WindowRequests as a fraction of the limit. Values above\n1 mean requests are being rejected.",
+                    "type": "number"
+                },
+                "last_request_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "limited_requests": {
+                    "type": "integer"
+                },
+                "total_requests": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "window_requests": {
+                    "description": "WindowRequests is the number of requests the user sent to their\nbusiest endpoint in the current window.",
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.AccessMethodInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "interval": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.InsightsReportInterval"
+                        }
+                    ],
+                    "example": "week"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateAccessMethodInsight"
+                    }
+                }
+            }
+        },
+        "codersdk.AccessMethodInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.AccessMethodInsightsReport"
+                }
+            }
+        },
+        "codersdk.AccessMethodIntervalReport": {
+            "type": "object",
+            "properties": {
+                "access_methods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AccessMethodUsage"
+                    }
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.AccessMethodUsage": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "Visual Studio Code"
+                },
+                "seconds": {
+                    "type": "integer",
+                    "example": 80500
+                },
+                "share": {
+                    "type": "number",
+                    "example": 0.65
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "vscode"
+                }
+            }
+        },
         "codersdk.AddLicenseRequest": {
             "type": "object",
             "required": [
@@ -15952,6 +17916,14 @@ const docTemplate = `{
                 "AgentSubsystemExectrace"
             ]
         },
+        "codersdk.AnomalyDetectionConfig": {
+            "type": "object",
+            "properties": {
+                "sensitivity": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AppHostResponse": {
             "type": "object",
             "properties": {
@@ -16170,6 +18142,31 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AuditLogExportConfig": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "description": "Bucket is the S3 bucket the audit logs are exported to. The export is\ndisabled when empty.",
+                    "type": "string"
+                },
+                "endpoint": {
+                    "description": "Endpoint is the endpoint of an S3 compatible service, e.g. Google\nCloud Storage.",
+                    "type": "string"
+                },
+                "include_stats": {
+                    "description": "IncludeStats also exports the template usage stats.",
+                    "type": "boolean"
+                },
+                "prefix": {
+                    "description": "Prefix is prepended to the keys of the exported objects.",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "Region is the region of the bucket.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AuditLogResponse": {
             "type": "object",
             "properties": {
@@ -17576,6 +19573,68 @@ const docTemplate = `{
                 "ChatWatchEventKindContextDirty"
             ]
         },
+        "codersdk.ClientGeographyConfig": {
+            "type": "object",
+            "properties": {
+                "asn_header": {
+                    "type": "string"
+                },
+                "region_header": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ClientRegionLatencyInsight": {
+            "type": "object",
+            "properties": {
+                "agents": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "asn": {
+                    "type": "integer",
+                    "example": 3320
+                },
+                "latency_ms": {
+                    "$ref": "#/definitions/codersdk.ConnectionLatencyQuantiles"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "DE"
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "codersdk.ClientRegionLatencyInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ClientRegionLatencyInsight"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.ClientRegionLatencyInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.ClientRegionLatencyInsightsReport"
+                }
+            }
+        },
         "codersdk.ConnectionLatency": {
             "type": "object",
             "properties": {
@@ -17589,6 +19648,27 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ConnectionLatencyQuantiles": {
+            "type": "object",
+            "properties": {
+                "p50": {
+                    "type": "number",
+                    "example": 31.312
+                },
+                "p90": {
+                    "type": "number",
+                    "example": 84.127
+                },
+                "p95": {
+                    "type": "number",
+                    "example": 119.832
+                },
+                "p99": {
+                    "type": "number",
+                    "example": 203.511
+                }
+            }
+        },
         "codersdk.ConnectionLog": {
             "type": "object",
             "properties": {
@@ -17745,6 +19825,82 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CostInsight": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "number",
+                    "example": 24
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "running_seconds": {
+                    "type": "integer",
+                    "example": 86400
+                }
+            }
+        },
+        "codersdk.CostInsightsGroupBy": {
+            "type": "string",
+            "enum": [
+                "workspace",
+                "user",
+                "organization"
+            ],
+            "x-enum-varnames": [
+                "CostInsightsGroupByWorkspace",
+                "CostInsightsGroupByUser",
+                "CostInsightsGroupByOrganization"
+            ]
+        },
+        "codersdk.CostInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.CostInsight"
+                    }
+                },
+                "group_by": {
+                    "enum": [
+                        "workspace",
+                        "user",
+                        "organization"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.CostInsightsGroupBy"
+                        }
+                    ]
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "total_cost": {
+                    "type": "number",
+                    "example": 184.5
+                }
+            }
+        },
+        "codersdk.CostInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.CostInsightsReport"
+                }
+            }
+        },
         "codersdk.CreateAIGatewayKeyRequest": {
             "type": "object",
             "required": [
@@ -18439,6 +20595,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateWorkspaceBuildReason": {
             "type": "string",
             "enum": [
@@ -18785,11 +20963,42 @@ const docTemplate = `{
                 "allow_all_cors": {
                     "type": "boolean"
                 },
-                "allow_path_app_sharing": {
+                "allow_path_app_sharing": {
+                    "type": "boolean"
+                },
+                "allow_path_app_site_owner_access": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.DatabasePurgeStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "records_purged": {
+                    "description": "RecordsPurged is the number of records deleted by the run, keyed by\nrecord type.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "success": {
                     "type": "boolean"
                 },
-                "allow_path_app_site_owner_access": {
-                    "type": "boolean"
+                "workspace_agent_stats_backlog_ms": {
+                    "description": "WorkspaceAgentStatsBacklogMillis is how far the oldest raw workspace\nagent stat is past its retention period. Stats are purged in batches,\nso this shrinks to zero over multiple runs once the purge catches up.",
+                    "type": "integer"
                 }
             }
         },
@@ -18824,6 +21033,36 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DeploymentBandwidthInsights": {
+            "type": "object",
+            "properties": {
+                "rx_bytes": {
+                    "type": "integer",
+                    "example": 1048576
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "tx_bytes": {
+                    "type": "integer",
+                    "example": 524288
+                }
+            }
+        },
+        "codersdk.DeploymentBuildInsights": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
         "codersdk.DeploymentConfig": {
             "type": "object",
             "properties": {
@@ -18838,6 +21077,40 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DeploymentInsightsReport": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "bandwidth": {
+                    "$ref": "#/definitions/codersdk.DeploymentBandwidthInsights"
+                },
+                "builds": {
+                    "$ref": "#/definitions/codersdk.DeploymentBuildInsights"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "latency_ms": {
+                    "$ref": "#/definitions/codersdk.ConnectionLatency"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.DeploymentInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.DeploymentInsightsReport"
+                }
+            }
+        },
         "codersdk.DeploymentStats": {
             "type": "object",
             "properties": {
@@ -18896,6 +21169,9 @@ const docTemplate = `{
                 "allow_workspace_renames": {
                     "type": "boolean"
                 },
+                "audit_log_export": {
+                    "$ref": "#/definitions/codersdk.AuditLogExportConfig"
+                },
                 "autobuild_poll_interval": {
                     "type": "integer"
                 },
@@ -18938,6 +21214,9 @@ const docTemplate = `{
                 "docs_url": {
                     "$ref": "#/definitions/serpent.URL"
                 },
+                "dormancy_activity_signal": {
+                    "type": "string"
+                },
                 "enable_authz_recording": {
                     "type": "boolean"
                 },
@@ -18981,6 +21260,9 @@ const docTemplate = `{
                 "job_hang_detector_interval": {
                     "type": "integer"
                 },
+                "license_seat_usage_window": {
+                    "type": "integer"
+                },
                 "logging": {
                     "$ref": "#/definitions/codersdk.LoggingConfig"
                 },
@@ -19151,6 +21433,19 @@ const docTemplate = `{
                 "DisplayAppSSH"
             ]
         },
+        "codersdk.DormancyActivitySignal": {
+            "type": "string",
+            "enum": [
+                "connection",
+                "session",
+                "traffic"
+            ],
+            "x-enum-varnames": [
+                "DormancyActivitySignalConnection",
+                "DormancyActivitySignalSession",
+                "DormancyActivitySignalTraffic"
+            ]
+        },
         "codersdk.DynamicParametersRequest": {
             "type": "object",
             "properties": {
@@ -19821,6 +22116,48 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.GroupUsageQuota": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "report",
+                        "block_start"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UsageQuotaAction"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "group_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "metric": {
+                    "enum": [
+                        "connection_hours",
+                        "active_hours"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UsageQuotaMetric"
+                        }
+                    ]
+                },
+                "monthly_hours": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.HTTPCookieConfig": {
             "type": "object",
             "properties": {
@@ -19863,6 +22200,60 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.IdleWorkspace": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "flag",
+                        "stop"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.IdleWorkspaceAction"
+                        }
+                    ]
+                },
+                "flagged_at": {
+                    "description": "FlaggedAt is set once the reaper has flagged the workspace since it was\nlast active.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "last_active_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "owner_name": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.IdleWorkspaceAction": {
+            "type": "string",
+            "enum": [
+                "flag",
+                "stop"
+            ],
+            "x-enum-varnames": [
+                "IdleWorkspaceActionFlag",
+                "IdleWorkspaceActionStop"
+            ]
+        },
         "codersdk.InboxNotification": {
             "type": "object",
             "properties": {
@@ -19924,11 +22315,13 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "day",
-                "week"
+                "week",
+                "month"
             ],
             "x-enum-varnames": [
                 "InsightsReportIntervalDay",
-                "InsightsReportIntervalWeek"
+                "InsightsReportIntervalWeek",
+                "InsightsReportIntervalMonth"
             ]
         },
         "codersdk.InvalidatePresetsResponse": {
@@ -20013,6 +22406,31 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.LicenseSeatUsage": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "description": "ActiveUsers is the number of active users in the deployment.",
+                    "type": "integer"
+                },
+                "limit": {
+                    "description": "Limit is the user limit of the license, if there is one.",
+                    "type": "integer"
+                },
+                "seats": {
+                    "description": "Seats is the number of seats counted against the user limit.",
+                    "type": "integer"
+                },
+                "usage_users": {
+                    "description": "UsageUsers is the number of active users that used a workspace within\nthe usage window. It is only set when the usage window is non-zero.",
+                    "type": "integer"
+                },
+                "usage_window_ms": {
+                    "description": "UsageWindowMillis is the window of workspace usage used to count seats.\nZero means every active user consumes a seat.",
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.LinkConfig": {
             "type": "object",
             "properties": {
@@ -20328,6 +22746,14 @@ const docTemplate = `{
                     "description": "The notifications system buffers message updates in memory to ease pressure on the database.\nThis option controls how often it synchronizes its state with the database. The shorter this value the\nlower the change of state inconsistency in a non-graceful shutdown - but it also increases load on the\ndatabase. It is recommended to keep this option at its default value.",
                     "type": "integer"
                 },
+                "unused_workspace_after": {
+                    "description": "How long a workspace must go unused before its owner is notified. A value of 0 disables the notification.",
+                    "type": "integer"
+                },
+                "unused_workspace_notify_admins": {
+                    "description": "Whether template admins are notified about unused workspaces in their organizations as well.",
+                    "type": "boolean"
+                },
                 "webhook": {
                     "description": "Webhook settings.",
                     "allOf": [
@@ -21156,6 +23582,47 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OrganizationInsightsReport": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "bandwidth": {
+                    "$ref": "#/definitions/codersdk.DeploymentBandwidthInsights"
+                },
+                "builds": {
+                    "$ref": "#/definitions/codersdk.DeploymentBuildInsights"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "latency_ms": {
+                    "$ref": "#/definitions/codersdk.ConnectionLatency"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspaces": {
+                    "$ref": "#/definitions/codersdk.OrganizationWorkspaceInsights"
+                }
+            }
+        },
+        "codersdk.OrganizationInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.OrganizationInsightsReport"
+                }
+            }
+        },
         "codersdk.OrganizationMember": {
             "type": "object",
             "properties": {
@@ -21284,6 +23751,19 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OrganizationWorkspaceInsights": {
+            "type": "object",
+            "properties": {
+                "running": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
         "codersdk.PaginatedMembersResponse": {
             "type": "object",
             "properties": {
@@ -21571,34 +24051,127 @@ const docTemplate = `{
                 "callback_url": {
                     "type": "string"
                 },
-                "icon": {
-                    "type": "string"
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.PostWorkspaceUsageRequest": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "app_name": {
+                    "$ref": "#/definitions/codersdk.UsageAppName"
+                }
+            }
+        },
+        "codersdk.PprofConfig": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/serpent.HostPort"
+                },
+                "enable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.PrebuildInsightsReport": {
+            "type": "object",
+            "properties": {
+                "claims": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "hit_rate": {
+                    "type": "number",
+                    "example": 0.84
+                },
+                "misses": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "presets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.PrebuildPresetInsight"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "name": {
-                    "type": "string"
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
                 }
             }
         },
-        "codersdk.PostWorkspaceUsageRequest": {
+        "codersdk.PrebuildInsightsResponse": {
             "type": "object",
             "properties": {
-                "agent_id": {
-                    "type": "string",
-                    "format": "uuid"
-                },
-                "app_name": {
-                    "$ref": "#/definitions/codersdk.UsageAppName"
+                "report": {
+                    "$ref": "#/definitions/codersdk.PrebuildInsightsReport"
                 }
             }
         },
-        "codersdk.PprofConfig": {
+        "codersdk.PrebuildPresetInsight": {
             "type": "object",
             "properties": {
-                "address": {
-                    "$ref": "#/definitions/serpent.HostPort"
+                "claim_latency_seconds_50": {
+                    "type": "number",
+                    "example": 12.5
                 },
-                "enable": {
-                    "type": "boolean"
+                "claim_latency_seconds_95": {
+                    "type": "number",
+                    "example": 30
+                },
+                "claims": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "hit_rate": {
+                    "type": "number",
+                    "example": 0.84
+                },
+                "idle_seconds_50": {
+                    "type": "number",
+                    "example": 3600
+                },
+                "idle_seconds_95": {
+                    "type": "number",
+                    "example": 43200
+                },
+                "misses": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "prebuilds_created": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "preset_name": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_name": {
+                    "type": "string"
                 }
             }
         },
@@ -21809,6 +24382,83 @@ const docTemplate = `{
                 },
                 "enable": {
                     "type": "boolean"
+                },
+                "remote_write": {
+                    "$ref": "#/definitions/codersdk.PrometheusRemoteWriteConfig"
+                }
+            }
+        },
+        "codersdk.PrometheusRelabelAction": {
+            "type": "string",
+            "enum": [
+                "replace",
+                "keep",
+                "drop",
+                "labeldrop",
+                "labelkeep"
+            ],
+            "x-enum-varnames": [
+                "PrometheusRelabelActionReplace",
+                "PrometheusRelabelActionKeep",
+                "PrometheusRelabelActionDrop",
+                "PrometheusRelabelActionLabelDrop",
+                "PrometheusRelabelActionLabelKeep"
+            ]
+        },
+        "codersdk.PrometheusRelabelConfig": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "replace",
+                        "keep",
+                        "drop",
+                        "labeldrop",
+                        "labelkeep"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.PrometheusRelabelAction"
+                        }
+                    ]
+                },
+                "regex": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "string"
+                },
+                "separator": {
+                    "type": "string"
+                },
+                "source_labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "target_label": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.PrometheusRemoteWriteConfig": {
+            "type": "object",
+            "properties": {
+                "bearer_token": {
+                    "type": "string"
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "relabel_configs": {
+                    "$ref": "#/definitions/serpent.Struct-array_codersdk_PrometheusRelabelConfig"
+                },
+                "url": {
+                    "$ref": "#/definitions/serpent.URL"
                 }
             }
         },
@@ -22579,6 +25229,53 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.RegionLatencyInsight": {
+            "type": "object",
+            "properties": {
+                "agents": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "latency_ms": {
+                    "$ref": "#/definitions/codersdk.ConnectionLatencyQuantiles"
+                },
+                "region_id": {
+                    "type": "integer",
+                    "example": 999
+                },
+                "region_name": {
+                    "type": "string",
+                    "example": "Coder"
+                }
+            }
+        },
+        "codersdk.RegionLatencyInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.RegionLatencyInsight"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.RegionLatencyInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.RegionLatencyInsightsReport"
+                }
+            }
+        },
         "codersdk.RegionsResponse-codersdk_Region": {
             "type": "object",
             "properties": {
@@ -22693,7 +25390,8 @@ const docTemplate = `{
                 "user_ai_budget_override",
                 "chat",
                 "user_secret",
-                "user_skill"
+                "user_skill",
+                "webhook"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -22730,7 +25428,8 @@ const docTemplate = `{
                 "ResourceTypeUserAIBudgetOverride",
                 "ResourceTypeChat",
                 "ResourceTypeUserSecret",
-                "ResourceTypeUserSkill"
+                "ResourceTypeUserSkill",
+                "ResourceTypeWebhook"
             ]
         },
         "codersdk.Response": {
@@ -22783,6 +25482,10 @@ const docTemplate = `{
                 "workspace_agent_logs": {
                     "description": "WorkspaceAgentLogs controls how long workspace agent logs are retained.\nLogs are deleted if the agent hasn't connected within this period.\nLogs from the latest build are always retained regardless of age.\nDefaults to 7 days to preserve existing behavior.",
                     "type": "integer"
+                },
+                "workspace_agent_stats": {
+                    "description": "WorkspaceAgentStats controls how long raw workspace agent stats are\nretained. Stats are only deleted once they have been rolled up into\ntemplate usage stats. Defaults to 1 day to preserve existing behavior.",
+                    "type": "integer"
                 }
             }
         },
@@ -22881,6 +25584,44 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ScaletestResult": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "description": "CreatedBy is the user that uploaded the result.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "elapsed_ms": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "description": "Name is the name of the test, e.g. the scaletest command that was run.",
+                    "type": "string"
+                },
+                "summary": {
+                    "description": "Summary is the result of the scaletest harness without the results of\nindividual runs. Its format is defined by the scaletest harness.",
+                    "type": "object"
+                },
+                "total_fail": {
+                    "type": "integer"
+                },
+                "total_pass": {
+                    "type": "integer"
+                },
+                "total_runs": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.ServerSentEvent": {
             "type": "object",
             "properties": {
@@ -23020,6 +25761,12 @@ const docTemplate = `{
         "codersdk.StatsCollectionConfig": {
             "type": "object",
             "properties": {
+                "anomaly_detection": {
+                    "$ref": "#/definitions/codersdk.AnomalyDetectionConfig"
+                },
+                "client_geography": {
+                    "$ref": "#/definitions/codersdk.ClientGeographyConfig"
+                },
                 "usage_stats": {
                     "$ref": "#/definitions/codersdk.UsageStatsConfig"
                 }
@@ -23429,6 +26176,20 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "dormancy_activity_signal": {
+                    "description": "DormancyActivitySignal is the agent activity that keeps workspaces of\nthe template from going dormant. Empty if the deployment default is\nused.",
+                    "enum": [
+                        "",
+                        "connection",
+                        "session",
+                        "traffic"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.DormancyActivitySignal"
+                        }
+                    ]
+                },
                 "failure_ttl_ms": {
                     "description": "FailureTTLMillis, TimeTilDormantMillis, and TimeTilDormantAutoDeleteMillis are enterprise-only. Their\nvalues are used if your license is entitled to use the advanced\ntemplate scheduling feature.",
                     "type": "integer"
@@ -23502,6 +26263,30 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateAccessMethodInsight": {
+            "type": "object",
+            "properties": {
+                "access_methods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AccessMethodUsage"
+                    }
+                },
+                "intervals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AccessMethodIntervalReport"
+                    }
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.TemplateAppUsage": {
             "type": "object",
             "properties": {
@@ -23598,6 +26383,23 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateBandwidth": {
+            "type": "object",
+            "properties": {
+                "rx_bytes": {
+                    "type": "integer",
+                    "example": 4182593536
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "tx_bytes": {
+                    "type": "integer",
+                    "example": 817889280
+                }
+            }
+        },
         "codersdk.TemplateBuildTimeStats": {
             "type": "object",
             "additionalProperties": {
@@ -23831,6 +26633,77 @@ const docTemplate = `{
                 "TemplateBuilderVariableTypeBool"
             ]
         },
+        "codersdk.TemplateComparison": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer",
+                    "example": 18
+                },
+                "build_success_rate": {
+                    "description": "BuildSuccessRate is the share of completed builds that didn't fail,\nbetween 0 and 1. Canceled builds are not counted as failures.",
+                    "type": "number",
+                    "example": 0.967
+                },
+                "failed_builds": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "median_build_seconds": {
+                    "description": "MedianBuildSeconds is the median duration of successful workspace\nstarts.",
+                    "type": "number",
+                    "example": 48.5
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_display_name": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_name": {
+                    "type": "string"
+                },
+                "total_builds": {
+                    "type": "integer",
+                    "example": 212
+                },
+                "workspace_connection_latency_ms": {
+                    "$ref": "#/definitions/codersdk.ConnectionLatency"
+                }
+            }
+        },
+        "codersdk.TemplateComparisonInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateComparison"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateComparisonInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.TemplateComparisonInsightsReport"
+                }
+            }
+        },
         "codersdk.TemplateExample": {
             "type": "object",
             "properties": {
@@ -23917,6 +26790,37 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateIdlePolicy": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "flag",
+                        "stop"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.IdleWorkspaceAction"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "idle_timeout_ms": {
+                    "type": "integer"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.TemplateInsightsIntervalReport": {
             "type": "object",
             "properties": {
@@ -24741,6 +27645,20 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "dormancy_activity_signal": {
+                    "description": "DormancyActivitySignal overrides the deployment wide agent activity\nthat keeps workspaces of the template from going dormant. Pass an\nempty string to use the deployment default. This is an enterprise\nfeature, like TimeTilDormantMillis.",
+                    "enum": [
+                        "",
+                        "connection",
+                        "session",
+                        "traffic"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.DormancyActivitySignal"
+                        }
+                    ]
+                },
                 "failure_ttl_ms": {
                     "type": "integer"
                 },
@@ -25036,6 +27954,37 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UploadScaletestResultRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "summary"
+            ],
+            "properties": {
+                "elapsed_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "object"
+                },
+                "total_fail": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "total_pass": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "total_runs": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "codersdk.UpsertGroupAIBudgetRequest": {
             "type": "object",
             "properties": {
@@ -25045,6 +27994,47 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpsertGroupUsageQuotaRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action defaults to \"report\" when empty.",
+                    "enum": [
+                        "report",
+                        "block_start"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UsageQuotaAction"
+                        }
+                    ]
+                },
+                "monthly_hours": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "codersdk.UpsertTemplateIdlePolicyRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action defaults to \"flag\" when empty.",
+                    "enum": [
+                        "flag",
+                        "stop"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.IdleWorkspaceAction"
+                        }
+                    ]
+                },
+                "idle_timeout_ms": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.UpsertUserAIBudgetOverrideRequest": {
             "type": "object",
             "required": [
@@ -25112,6 +28102,56 @@ const docTemplate = `{
                 "UsageAppNameSSH"
             ]
         },
+        "codersdk.UsageHeatmapInsightsReport": {
+            "type": "object",
+            "properties": {
+                "avg_active_users": {
+                    "description": "AvgActiveUsers is the average number of active users in each hour of\nthe week across the time range.",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number",
+                            "format": "float64"
+                        }
+                    }
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "max_active_users": {
+                    "description": "MaxActiveUsers is the highest number of active users seen in each\nhour of the week.",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int64"
+                        }
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "codersdk.UsageHeatmapInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.UsageHeatmapInsightsReport"
+                }
+            }
+        },
         "codersdk.UsagePeriod": {
             "type": "object",
             "properties": {
@@ -25129,11 +28169,93 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UsageQuota": {
+            "type": "object",
+            "properties": {
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.UsageQuotaStatus"
+                    }
+                },
+                "period_end": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "period_start": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.UsageQuotaAction": {
+            "type": "string",
+            "enum": [
+                "report",
+                "block_start"
+            ],
+            "x-enum-varnames": [
+                "UsageQuotaActionReport",
+                "UsageQuotaActionBlockStart"
+            ]
+        },
+        "codersdk.UsageQuotaMetric": {
+            "type": "string",
+            "enum": [
+                "connection_hours",
+                "active_hours"
+            ],
+            "x-enum-varnames": [
+                "UsageQuotaMetricConnectionHours",
+                "UsageQuotaMetricActiveHours"
+            ]
+        },
+        "codersdk.UsageQuotaStatus": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is the strictest action across the user's groups. It is omitted\nwhen the metric is not limited.",
+                    "enum": [
+                        "report",
+                        "block_start"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UsageQuotaAction"
+                        }
+                    ]
+                },
+                "allowance_hours": {
+                    "description": "AllowanceHours is the sum of the allowances of the user's groups. It is\nomitted when none of their groups limit the metric.",
+                    "type": "integer"
+                },
+                "consumed_hours": {
+                    "type": "number"
+                },
+                "exceeded": {
+                    "type": "boolean"
+                },
+                "metric": {
+                    "enum": [
+                        "connection_hours",
+                        "active_hours"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UsageQuotaMetric"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.UsageStatsConfig": {
             "type": "object",
             "properties": {
                 "enable": {
                     "type": "boolean"
+                },
+                "insights_cache_ttl": {
+                    "type": "integer"
                 }
             }
         },
@@ -25301,26 +28423,88 @@ const docTemplate = `{
                 }
             }
         },
-        "codersdk.UserAppearanceSettings": {
+        "codersdk.UserAppearanceSettings": {
+            "type": "object",
+            "properties": {
+                "terminal_font": {
+                    "$ref": "#/definitions/codersdk.TerminalFontName"
+                },
+                "theme_dark": {
+                    "description": "Ignored when ThemeMode is \"single\"",
+                    "type": "string"
+                },
+                "theme_light": {
+                    "description": "Ignored when ThemeMode is \"single\"",
+                    "type": "string"
+                },
+                "theme_mode": {
+                    "$ref": "#/definitions/codersdk.ThemeMode"
+                },
+                "theme_preference": {
+                    "description": "ThemePreference is the legacy single-field appearance setting. In\n\"single\" mode it mirrors the active theme. In \"sync\" mode modern\nclients normally mirror the active OS slot, but older clients can\nupdate only this field, so it may diverge from ThemeLight or\nThemeDark until a modern client saves the full appearance state\nagain.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UserBandwidth": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "rx_bytes": {
+                    "type": "integer",
+                    "example": 4182593536
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateBandwidth"
+                    }
+                },
+                "tx_bytes": {
+                    "type": "integer",
+                    "example": 817889280
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UserBandwidthInsightsReport": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the total number of users with bandwidth in the time range,\nacross all pages.",
+                    "type": "integer",
+                    "example": 42
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.UserBandwidth"
+                    }
+                }
+            }
+        },
+        "codersdk.UserBandwidthInsightsResponse": {
             "type": "object",
             "properties": {
-                "terminal_font": {
-                    "$ref": "#/definitions/codersdk.TerminalFontName"
-                },
-                "theme_dark": {
-                    "description": "Ignored when ThemeMode is \"single\"",
-                    "type": "string"
-                },
-                "theme_light": {
-                    "description": "Ignored when ThemeMode is \"single\"",
-                    "type": "string"
-                },
-                "theme_mode": {
-                    "$ref": "#/definitions/codersdk.ThemeMode"
-                },
-                "theme_preference": {
-                    "description": "ThemePreference is the legacy single-field appearance setting. In\n\"single\" mode it mirrors the active theme. In \"sync\" mode modern\nclients normally mirror the active OS slot, but older clients can\nupdate only this field, so it may diverge from ThemeLight or\nThemeDark until a modern client saves the full appearance state\nagain.",
-                    "type": "string"
+                "report": {
+                    "$ref": "#/definitions/codersdk.UserBandwidthInsightsReport"
                 }
             }
         },
@@ -25626,6 +28810,234 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "secret": {
+                    "description": "Secret is used to sign the deliveries of the webhook. It is only\nreturned when the webhook is created.",
+                    "type": "string"
+                },
+                "template_id": {
+                    "description": "TemplateID restricts the webhook to workspaces of the template. Events\nof all workspaces are delivered if it is not set.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "description": "Error is the reason the last attempt failed, if it did.",
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/codersdk.WebhookEvent"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "payload": {
+                    "$ref": "#/definitions/codersdk.WebhookPayload"
+                },
+                "response_status_code": {
+                    "description": "ResponseStatusCode is the status code returned by the last attempt, if\nthe endpoint responded.",
+                    "type": "integer"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WebhookDeliveryStatus"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "webhook_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "WebhookDeliveryStatusPending",
+                "WebhookDeliveryStatusSucceeded",
+                "WebhookDeliveryStatusFailed"
+            ]
+        },
+        "codersdk.WebhookEvent": {
+            "type": "string",
+            "enum": [
+                "workspace_build_succeeded",
+                "workspace_build_failed",
+                "workspace_resource_threshold_exceeded"
+            ],
+            "x-enum-varnames": [
+                "WebhookEventWorkspaceBuildSucceeded",
+                "WebhookEventWorkspaceBuildFailed",
+                "WebhookEventWorkspaceResourceThresholdExceeded"
+            ]
+        },
+        "codersdk.WebhookPayload": {
+            "type": "object",
+            "properties": {
+                "build": {
+                    "description": "Build is set for workspace build events.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WebhookWorkspaceBuild"
+                        }
+                    ]
+                },
+                "event": {
+                    "$ref": "#/definitions/codersdk.WebhookEvent"
+                },
+                "thresholds": {
+                    "description": "Thresholds is set for resource threshold events, and lists the\nresources of the workspace that exceeded their threshold.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookResourceThreshold"
+                    }
+                },
+                "timestamp": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspace": {
+                    "$ref": "#/definitions/codersdk.WebhookWorkspace"
+                }
+            }
+        },
+        "codersdk.WebhookResourceThreshold": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "description": "Path is the path of the volume, for volume thresholds.",
+                    "type": "string"
+                },
+                "resource": {
+                    "description": "Resource is either \"memory\" or \"volume\".",
+                    "type": "string",
+                    "enum": [
+                        "memory",
+                        "volume"
+                    ]
+                },
+                "threshold_percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WebhookWorkspace": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "owner_name": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebhookWorkspaceBuild": {
+            "type": "object",
+            "properties": {
+                "build_number": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "reason": {
+                    "$ref": "#/definitions/codersdk.BuildReason"
+                },
+                "status": {
+                    "$ref": "#/definitions/codersdk.ProvisionerJobStatus"
+                },
+                "transition": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.WebpushSubscription": {
             "type": "object",
             "properties": {
@@ -26346,6 +29758,42 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentProcess": {
+            "type": "object",
+            "properties": {
+                "cpu_percent": {
+                    "description": "CPUPercent is the CPU usage of the process, sampled over a short\ninterval. It can exceed 100 for processes using multiple cores.",
+                    "type": "number"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "memory_percent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "Username is empty if the owner of the process can't be resolved.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentProcessSortBy": {
+            "type": "string",
+            "enum": [
+                "cpu",
+                "memory"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentProcessSortByCPU",
+                "WorkspaceAgentProcessSortByMemory"
+            ]
+        },
         "codersdk.WorkspaceAgentRepoChanges": {
             "type": "object",
             "properties": {
@@ -26435,6 +29883,81 @@ const docTemplate = `{
                 "WorkspaceAgentStartupScriptBehaviorNonBlocking"
             ]
         },
+        "codersdk.WorkspaceAgentStatsBucket": {
+            "type": "object",
+            "properties": {
+                "reports": {
+                    "description": "Reports is the number of stats reports the agent sent within the\nbucket. Buckets without reports have all values set to zero.",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "values": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentStatsMetric": {
+            "type": "string",
+            "enum": [
+                "rx_bytes",
+                "tx_bytes",
+                "rx_packets",
+                "tx_packets",
+                "connection_count",
+                "connection_median_latency_ms",
+                "session_count_vscode",
+                "session_count_jetbrains",
+                "session_count_reconnecting_pty",
+                "session_count_ssh"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentStatsMetricRxBytes",
+                "WorkspaceAgentStatsMetricTxBytes",
+                "WorkspaceAgentStatsMetricRxPackets",
+                "WorkspaceAgentStatsMetricTxPackets",
+                "WorkspaceAgentStatsMetricConnectionCount",
+                "WorkspaceAgentStatsMetricConnectionMedianLatencyMS",
+                "WorkspaceAgentStatsMetricSessionCountVSCode",
+                "WorkspaceAgentStatsMetricSessionCountJetBrains",
+                "WorkspaceAgentStatsMetricSessionCountReconnectingPTY",
+                "WorkspaceAgentStatsMetricSessionCountSSH"
+            ]
+        },
+        "codersdk.WorkspaceAgentStatsTimeSeries": {
+            "type": "object",
+            "properties": {
+                "bucket_size_ms": {
+                    "type": "integer"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentStatsBucket"
+                    }
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentStatsMetric"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentStatus": {
             "type": "string",
             "enum": [
@@ -26450,6 +29973,29 @@ const docTemplate = `{
                 "WorkspaceAgentTimeout"
             ]
         },
+        "codersdk.WorkspaceAgentTopProcessesResponse": {
+            "type": "object",
+            "properties": {
+                "processes": {
+                    "description": "Processes are ordered by the resource they are sorted by, highest\nfirst.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentProcess"
+                    }
+                },
+                "sort_by": {
+                    "enum": [
+                        "cpu",
+                        "memory"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentProcessSortBy"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.WorkspaceApp": {
             "type": "object",
             "properties": {
@@ -26913,6 +30459,178 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceHealthApps": {
+            "type": "object",
+            "properties": {
+                "healthy": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "initializing": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "unhealthy": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthBuilds": {
+            "type": "object",
+            "properties": {
+                "builds": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "failed_builds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "latest_build_failed": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthConnectivity": {
+            "type": "object",
+            "properties": {
+                "agents_connected": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "agents_total": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthInsight": {
+            "type": "object",
+            "properties": {
+                "apps": {
+                    "$ref": "#/definitions/codersdk.WorkspaceHealthApps"
+                },
+                "builds": {
+                    "$ref": "#/definitions/codersdk.WorkspaceHealthBuilds"
+                },
+                "connectivity": {
+                    "$ref": "#/definitions/codersdk.WorkspaceHealthConnectivity"
+                },
+                "latency": {
+                    "$ref": "#/definitions/codersdk.WorkspaceHealthLatency"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "owner_username": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 85
+                },
+                "status": {
+                    "enum": [
+                        "healthy",
+                        "degraded",
+                        "unhealthy"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceHealthStatus"
+                        }
+                    ]
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_name": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthInsightsReport": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "workspaces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceHealthInsight"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.WorkspaceHealthInsightsReport"
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthLatency": {
+            "type": "object",
+            "properties": {
+                "baseline_latency_ms": {
+                    "type": "number",
+                    "example": 22
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 25
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "codersdk.WorkspaceHealthStatus": {
+            "type": "string",
+            "enum": [
+                "healthy",
+                "degraded",
+                "unhealthy"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceHealthStatusHealthy",
+                "WorkspaceHealthStatusDegraded",
+                "WorkspaceHealthStatusUnhealthy"
+            ]
+        },
         "codersdk.WorkspaceProxy": {
             "type": "object",
             "properties": {
@@ -27071,6 +30789,88 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceRightsizing": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "description": "Cost is the estimated cost of running the workspace during the period,\nin the same unit as the daily cost of template resources.",
+                    "type": "number",
+                    "example": 24
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "potential_savings": {
+                    "description": "PotentialSavings is the part of the cost that would have been saved by\napplying the recommendations, assuming the cost is split evenly between\nCPU and memory.",
+                    "type": "number",
+                    "example": 6
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceRightsizingRecommendation"
+                    }
+                },
+                "running_seconds": {
+                    "description": "RunningSeconds is how long the workspace was running during the period.",
+                    "type": "integer",
+                    "example": 86400
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.WorkspaceRightsizingRecommendation": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current is the latest total of the resource reported by the agent.",
+                    "type": "number"
+                },
+                "recommended": {
+                    "description": "Recommended is the 95th percentile of the usage with headroom, rounded\nup. It is higher than the current total if the agent is undersized.",
+                    "type": "number"
+                },
+                "resource": {
+                    "enum": [
+                        "cpu",
+                        "memory"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceRightsizingResource"
+                        }
+                    ]
+                },
+                "samples": {
+                    "description": "Samples is the number of usage samples the recommendation is based on.",
+                    "type": "integer"
+                },
+                "used_max": {
+                    "type": "number"
+                },
+                "used_p95": {
+                    "type": "number"
+                }
+            }
+        },
+        "codersdk.WorkspaceRightsizingResource": {
+            "type": "string",
+            "enum": [
+                "cpu",
+                "memory"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceRightsizingResourceCPU",
+                "WorkspaceRightsizingResourceMemory"
+            ]
+        },
         "codersdk.WorkspaceRole": {
             "type": "string",
             "enum": [
@@ -27137,6 +30937,68 @@ const docTemplate = `{
                 "WorkspaceStatusDeleted"
             ]
         },
+        "codersdk.WorkspaceTimelineEvent": {
+            "type": "object",
+            "properties": {
+                "build_number": {
+                    "description": "BuildNumber is the number of the workspace build the event belongs to,\nif any.",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "EndTime is set for events that span a period of time once it's over,\ne.g. for completed builds and closed connections.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "description": "ID is the ID of the record the event is derived from, i.e. the\nworkspace build, connection log, workspace agent or audit log.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "type": {
+                    "enum": [
+                        "build",
+                        "connection",
+                        "app_usage",
+                        "agent_lifecycle",
+                        "audit"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTimelineEventType"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "UserID is the user that caused the event, if known.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceTimelineEventType": {
+            "type": "string",
+            "enum": [
+                "build",
+                "connection",
+                "app_usage",
+                "agent_lifecycle",
+                "audit"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceTimelineEventTypeBuild",
+                "WorkspaceTimelineEventTypeConnection",
+                "WorkspaceTimelineEventTypeAppUsage",
+                "WorkspaceTimelineEventTypeAgentLifecycle",
+                "WorkspaceTimelineEventTypeAudit"
+            ]
+        },
         "codersdk.WorkspaceTransition": {
             "type": "string",
             "enum": [
@@ -28093,6 +31955,17 @@ const docTemplate = `{
                 }
             }
         },
+        "serpent.Struct-array_codersdk_PrometheusRelabelConfig": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.PrometheusRelabelConfig"
+                    }
+                }
+            }
+        },
         "serpent.URL": {
             "type": "object",
             "properties": {
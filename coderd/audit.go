@@ -513,6 +513,14 @@ func (api *API) auditLogIsResourceDeleted(ctx context.Context, alog database.Get
 			api.Logger.Error(ctx, "unable to fetch user secret", slog.Error(err))
 		}
 		return false
+	case database.ResourceTypeWebhook:
+		_, err := api.Database.GetWebhookByID(ctx, alog.AuditLog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch webhook", slog.Error(err))
+		}
+		return false
 	default:
 		return false
 	}
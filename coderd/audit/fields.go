@@ -10,8 +10,9 @@ import (
 type BackgroundSubsystem string
 
 const (
-	BackgroundSubsystemDormancy        BackgroundSubsystem = "dormancy"
-	BackgroundSubsystemChatAutoArchive BackgroundSubsystem = "chat_auto_archive"
+	BackgroundSubsystemDormancy         BackgroundSubsystem = "dormancy"
+	BackgroundSubsystemChatAutoArchive  BackgroundSubsystem = "chat_auto_archive"
+	BackgroundSubsystemAnomalyDetection BackgroundSubsystem = "anomaly_detection"
 )
 
 func BackgroundTaskFields(subsystem BackgroundSubsystem) map[string]string {
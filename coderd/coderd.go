@@ -71,6 +71,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/httpmw/loggermw"
 	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/insightscache"
 	"github.com/coder/coder/v2/coderd/metricscache"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauth2provider"
@@ -90,6 +91,7 @@ import (
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/usage"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/webpush"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
@@ -294,6 +296,9 @@ type Options struct {
 	BoundaryUsageTracker *boundaryusage.Tracker
 	// NotificationsEnqueuer handles enqueueing notifications for delivery by SMTP, webhook, etc.
 	NotificationsEnqueuer notifications.Enqueuer
+	// WebhookEnqueuer enqueues workspace events for delivery to outbound
+	// webhooks.
+	WebhookEnqueuer webhooks.Enqueuer
 
 	// IDPSync holds all configured values for syncing external IDP users into Coder.
 	IDPSync idpsync.IDPSync
@@ -510,7 +515,7 @@ func New(options *Options) *API {
 	}
 
 	if options.DatabaseRolluper == nil {
-		options.DatabaseRolluper = dbrollup.New(options.Logger.Named("dbrollup"), options.Database)
+		options.DatabaseRolluper = dbrollup.New(options.Logger.Named("dbrollup"), options.Database, dbrollup.WithPubsub(options.Pubsub))
 	}
 
 	if options.WorkspaceUsageTracker == nil {
@@ -522,6 +527,9 @@ func New(options *Options) *API {
 	if options.NotificationsEnqueuer == nil {
 		options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
 	}
+	if options.WebhookEnqueuer == nil {
+		options.WebhookEnqueuer = webhooks.NewStoreEnqueuer(options.Database)
+	}
 
 	r := chi.NewRouter()
 	// We add this middleware early, to make sure that authorization checks made
@@ -899,7 +907,29 @@ func New(options *Options) *API {
 		UpdateAgentMetricsFn:   options.UpdateAgentMetrics,
 		AppStatBatchSize:       workspaceapps.DefaultStatsDBReporterBatchSize,
 		DisableDatabaseInserts: !options.DeploymentValues.StatsCollection.UsageStats.Enable.Value(),
+		DormancyActivitySignal: codersdk.DormancyActivitySignal(options.DeploymentValues.DormancyActivitySignal),
 	})
+	// The previous connection of an agent can take its stats lease back once
+	// the newer one has not reported stats for a couple of report intervals.
+	api.statsLeases, err = workspacestats.NewStatsLeases(
+		options.Logger.Named("stats_leases"),
+		options.Pubsub,
+		api.ID,
+		options.Clock,
+		2*options.AgentStatsRefreshInterval,
+	)
+	if err != nil {
+		api.Logger.Fatal(context.Background(), "failed to initialize stats leases", slog.Error(err))
+	}
+	api.insightsCache, err = insightscache.New(
+		options.Logger.Named("insights_cache"),
+		options.Pubsub,
+		options.Clock,
+		options.DeploymentValues.StatsCollection.UsageStats.InsightsCacheTTL.Value(),
+	)
+	if err != nil {
+		api.Logger.Fatal(context.Background(), "failed to initialize insights cache", slog.Error(err))
+	}
 
 	// Initialize the metadata batcher for batching agent metadata updates.
 	batcherOpts := []metadatabatcher.Option{
@@ -994,7 +1024,9 @@ func New(options *Options) *API {
 
 	// API rate limit middleware. The counter is local and not shared between
 	// replicas or instances of this middleware.
-	apiRateLimiter := httpmw.RateLimit(options.APIRateLimit, time.Minute)
+	api.rateLimitTracker = httpmw.NewRateLimitTracker(options.APIRateLimit, time.Minute, options.Clock)
+	options.PrometheusRegistry.MustRegister(api.rateLimitTracker)
+	apiRateLimiter := httpmw.RateLimit(options.APIRateLimit, time.Minute, httpmw.WithRateLimitTracker(api.rateLimitTracker))
 
 	// Register DERP on expvar HTTP handler, which we serve below in the router, c.f. expvar.Handler()
 	expDERPOnce.Do(func() {
@@ -1430,6 +1462,10 @@ func New(options *Options) *API {
 			r.Use(apiKeyMiddleware)
 			r.Get("/config", api.deploymentValues)
 			r.Get("/stats", api.deploymentStats)
+			r.Get("/stats/watch", api.watchDeploymentStatsSSE)
+			r.Get("/stats/watch-ws", api.watchDeploymentStatsWS)
+			r.Get("/purge-status", api.databasePurgeStatus)
+			r.Get("/rate-limits", api.apiRateLimitConsumption)
 			r.Get("/ssh", api.sshConfig)
 		})
 		r.Route("/experiments", func(r chi.Router) {
@@ -1499,6 +1535,7 @@ func New(options *Options) *API {
 					httpmw.ExtractOrganizationParam(options.Database),
 				)
 				r.Get("/", api.organization)
+				r.Get("/insights", api.insightsOrganization)
 				r.Post("/templateversions", api.postTemplateVersionsByOrganization)
 				r.Route("/templates", func(r chi.Router) {
 					r.Post("/", api.postTemplateByOrganization)
@@ -1568,6 +1605,12 @@ func New(options *Options) *API {
 				r.Get("/", api.template)
 				r.Delete("/", api.deleteTemplate)
 				r.Patch("/", api.patchTemplateMeta)
+				r.Route("/idle-policy", func(r chi.Router) {
+					r.Get("/", api.templateIdlePolicy)
+					r.Put("/", api.putTemplateIdlePolicy)
+					r.Delete("/", api.deleteTemplateIdlePolicy)
+				})
+				r.Get("/idle-workspaces", api.templateIdleWorkspaces)
 				r.Route("/versions", func(r chi.Router) {
 					r.Post("/archive", api.postArchiveTemplateVersions)
 					r.Get("/", api.templateVersionsByTemplate)
@@ -1780,6 +1823,7 @@ func New(options *Options) *API {
 				})
 				r.Patch("/logs", api.patchWorkspaceAgentLogs)
 				r.Patch("/app-status", api.patchWorkspaceAgentAppStatus)
+				r.Post("/stats", api.postWorkspaceAgentStats)
 				// Deprecated: Required to support legacy agents
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
 				r.Get("/external-auth", api.workspaceAgentsExternalAuth)
@@ -1811,6 +1855,8 @@ func New(options *Options) *API {
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/top-processes", api.workspaceAgentTopProcesses)
+				r.Get("/stats/timeseries", api.workspaceAgentStatsTimeSeries)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/containers", api.workspaceAgentListContainers)
 				r.Get("/containers/watch", api.watchWorkspaceAgentContainers)
@@ -1857,6 +1903,8 @@ func New(options *Options) *API {
 					r.Delete("/", api.deleteWorkspaceAgentPortShare)
 				})
 				r.Get("/timings", api.workspaceTimings)
+				r.Get("/timeline", api.workspaceTimeline)
+				r.Get("/rightsizing", api.workspaceRightsizing)
 				r.Route("/acl", func(r chi.Router) {
 					r.Get("/", api.workspaceACL)
 					r.Patch("/", api.patchWorkspaceACL)
@@ -1922,8 +1970,34 @@ func New(options *Options) *API {
 				r.Get("/user-activity", api.insightsUserActivity)
 				r.Get("/user-latency", api.insightsUserLatency)
 				r.Get("/templates", api.insightsTemplates)
+				r.Get("/deployment", api.insightsDeployment)
+				r.Get("/usage-heatmap", api.insightsUsageHeatmap)
+				r.Get("/template-comparison", api.insightsTemplateComparison)
+				r.Get("/access-methods", api.insightsAccessMethods)
 			})
 			r.Get("/user-status-counts", api.insightsUserStatusCounts)
+			r.Get("/region-latency", api.insightsRegionLatency)
+			r.Get("/client-region-latency", api.insightsClientRegionLatency)
+			r.Get("/user-bandwidth", api.insightsUserBandwidth)
+			r.Get("/costs", api.insightsCosts)
+			r.Get("/workspace-health", api.insightsWorkspaceHealth)
+			r.Get("/prebuilds", api.insightsPrebuilds)
+		})
+		r.Route("/scaletest/results", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.scaletestResults)
+			r.Post("/", api.postScaletestResult)
+			r.Get("/{scaletestresult}", api.scaletestResult)
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Get("/", api.webhook)
+				r.Delete("/", api.deleteWebhook)
+				r.Get("/deliveries", api.webhookDeliveries)
+			})
 		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
@@ -2254,6 +2328,8 @@ type API struct {
 	derpCloseFunc      func()
 
 	metricsCache          *metricscache.Cache
+	insightsCache         *insightscache.Cache
+	rateLimitTracker      *httpmw.RateLimitTracker
 	updateChecker         *updatecheck.Checker
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
@@ -2268,6 +2344,7 @@ type API struct {
 	healthCheckProgress healthcheck.Progress
 
 	statsReporter            *workspacestats.Reporter
+	statsLeases              *workspacestats.StatsLeases
 	metadataBatcher          *metadatabatcher.Batcher
 	lifecycleMetrics         *agentapi.LifecycleMetrics
 	workspaceAgentRPCMetrics *WorkspaceAgentRPCMetrics
@@ -2334,6 +2411,7 @@ func (api *API) Close() error {
 		api.Logger.Warn(api.ctx, "close chat processor", slog.Error(err))
 	}
 	api.metricsCache.Close()
+	api.insightsCache.Close()
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
@@ -2349,6 +2427,7 @@ func (api *API) Close() error {
 		_ = (*coordinator).Close()
 	}
 	_ = api.statsReporter.Close()
+	_ = api.statsLeases.Close()
 	if api.metadataBatcher != nil {
 		api.metadataBatcher.Close()
 	}
@@ -2496,6 +2575,7 @@ func (api *API) CreateInMemoryTaggedProvisionerDaemon(dialCtx context.Context, n
 			OIDCConfig:          api.OIDCConfig,
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
 			AISeatTracker:       api.AISeatTracker,
+			WebhookEnqueuer:     api.WebhookEnqueuer,
 			Clock:               api.Clock,
 			HeartbeatFn:         options.heartbeatFn,
 		},
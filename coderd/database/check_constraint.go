@@ -39,6 +39,7 @@ const (
 	CheckUsersUsernameMinLength                        CheckConstraint = "users_username_min_length"                            // users
 	CheckOrganizationIDNotZero                         CheckConstraint = "organization_id_not_zero"                             // custom_roles
 	CheckGroupAIBudgetsSpendLimitMicrosCheck           CheckConstraint = "group_ai_budgets_spend_limit_micros_check"            // group_ai_budgets
+	CheckGroupUsageQuotasMonthlyHoursCheck             CheckConstraint = "group_usage_quotas_monthly_hours_check"               // group_usage_quotas
 	CheckGroupsChatSpendLimitMicrosCheck               CheckConstraint = "groups_chat_spend_limit_micros_check"                 // groups
 	CheckMcpServerConfigsAuthTypeCheck                 CheckConstraint = "mcp_server_configs_auth_type_check"                   // mcp_server_configs
 	CheckMcpServerConfigsAvailabilityCheck             CheckConstraint = "mcp_server_configs_availability_check"                // mcp_server_configs
@@ -50,7 +51,9 @@ const (
 	CheckGroupAclIsObject                              CheckConstraint = "group_acl_is_object"                                  // workspaces
 	CheckUserAclIsObject                               CheckConstraint = "user_acl_is_object"                                   // workspaces
 	CheckTelemetryLockEventTypeConstraint              CheckConstraint = "telemetry_lock_event_type_constraint"                 // telemetry_locks
+	CheckTemplateIdlePoliciesIdleTimeoutMsCheck        CheckConstraint = "template_idle_policies_idle_timeout_ms_check"         // template_idle_policies
 	CheckValidationMonotonicOrder                      CheckConstraint = "validation_monotonic_order"                           // template_version_parameters
+	CheckTemplatesDormancyActivitySignalCheck          CheckConstraint = "templates_dormancy_activity_signal_check"             // templates
 	CheckUsageEventTypeCheck                           CheckConstraint = "usage_event_type_check"                               // usage_events
 	CheckUserAIBudgetOverridesSpendLimitMicrosCheck    CheckConstraint = "user_ai_budget_overrides_spend_limit_micros_check"    // user_ai_budget_overrides
 	CheckUserAIProviderKeysAPIKeyCheck                 CheckConstraint = "user_ai_provider_keys_api_key_check"                  // user_ai_provider_keys
@@ -1430,6 +1430,40 @@ func GroupAIBudget(b database.GroupAIBudget) codersdk.GroupAIBudget {
 	}
 }
 
+func GroupUsageQuota(q database.GroupUsageQuota) codersdk.GroupUsageQuota {
+	return codersdk.GroupUsageQuota{
+		GroupID:      q.GroupID,
+		Metric:       codersdk.UsageQuotaMetric(q.Metric),
+		MonthlyHours: q.MonthlyHours,
+		Action:       codersdk.UsageQuotaAction(q.Action),
+		CreatedAt:    q.CreatedAt,
+		UpdatedAt:    q.UpdatedAt,
+	}
+}
+
+func TemplateIdlePolicy(p database.TemplateIdlePolicy) codersdk.TemplateIdlePolicy {
+	return codersdk.TemplateIdlePolicy{
+		TemplateID:        p.TemplateID,
+		IdleTimeoutMillis: p.IdleTimeoutMs,
+		Action:            codersdk.IdleWorkspaceAction(p.Action),
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
+
+func IdleWorkspace(row database.GetIdleWorkspacesRow) codersdk.IdleWorkspace {
+	return codersdk.IdleWorkspace{
+		WorkspaceID:   row.WorkspaceID,
+		WorkspaceName: row.WorkspaceName,
+		OwnerID:       row.OwnerID,
+		OwnerName:     row.OwnerUsername,
+		TemplateID:    row.TemplateID,
+		LastActiveAt:  row.LastActiveAt,
+		Action:        codersdk.IdleWorkspaceAction(row.Action),
+		FlaggedAt:     nullTimePtr(row.FlaggedAt),
+	}
+}
+
 func UserAIBudgetOverride(o database.UserAIBudgetOverride) codersdk.UserAIBudgetOverride {
 	return codersdk.UserAIBudgetOverride{
 		UserID:           o.UserID,
@@ -2109,3 +2143,60 @@ func UserSkillMetadataList(rows []database.ListUserSkillMetadataByUserIDRow) []c
 	}
 	return metadata
 }
+
+// ScaletestResult converts a database ScaletestResult to an SDK ScaletestResult.
+func ScaletestResult(result database.ScaletestResult) codersdk.ScaletestResult {
+	return codersdk.ScaletestResult{
+		ID:        result.ID,
+		CreatedAt: result.CreatedAt,
+		CreatedBy: result.CreatedBy,
+		Name:      result.Name,
+		TotalRuns: int(result.TotalRuns),
+		TotalPass: int(result.TotalPass),
+		TotalFail: int(result.TotalFail),
+		ElapsedMS: result.ElapsedMs,
+		Summary:   result.Summary,
+	}
+}
+
+// Webhook converts a webhook without its secret, which is only returned when
+// the webhook is created.
+func Webhook(webhook database.Webhook) codersdk.Webhook {
+	w := codersdk.Webhook{
+		ID:        webhook.ID,
+		CreatedAt: webhook.CreatedAt,
+		CreatedBy: webhook.CreatedBy,
+		URL:       webhook.Url,
+		Events:    make([]codersdk.WebhookEvent, 0, len(webhook.Events)),
+	}
+	if webhook.TemplateID.Valid {
+		w.TemplateID = &webhook.TemplateID.UUID
+	}
+	for _, event := range webhook.Events {
+		w.Events = append(w.Events, codersdk.WebhookEvent(event))
+	}
+	return w
+}
+
+func WebhookDelivery(delivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	d := codersdk.WebhookDelivery{
+		ID:        delivery.ID,
+		WebhookID: delivery.WebhookID,
+		CreatedAt: delivery.CreatedAt,
+		UpdatedAt: delivery.UpdatedAt,
+		Event:     codersdk.WebhookEvent(delivery.Event),
+		Status:    codersdk.WebhookDeliveryStatus(delivery.Status),
+		Attempts:  delivery.Attempts,
+		Error:     delivery.Error.String,
+	}
+	// Payloads are marshaled from codersdk.WebhookPayload when deliveries are
+	// enqueued, so they always unmarshal.
+	_ = json.Unmarshal(delivery.Payload, &d.Payload)
+	if delivery.Status == database.WebhookDeliveryStatusPending {
+		d.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.ResponseStatusCode.Valid {
+		d.ResponseStatusCode = &delivery.ResponseStatusCode.Int32
+	}
+	return d
+}
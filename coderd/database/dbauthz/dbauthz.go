@@ -670,7 +670,9 @@ var (
 				Identifier:  rbac.RoleIdentifier{Name: "dbpurge"},
 				DisplayName: "DB Purge Daemon",
 				Site: rbac.Permissions(map[string][]policy.Action{
-					rbac.ResourceSystem.Type:               {policy.ActionDelete},
+					// Read and update are needed to track the purge
+					// backlog and record the status of each run.
+					rbac.ResourceSystem.Type:               {policy.ActionRead, policy.ActionUpdate, policy.ActionDelete},
 					rbac.ResourceNotificationMessage.Type:  {policy.ActionDelete},
 					rbac.ResourceApiKey.Type:               {policy.ActionDelete},
 					rbac.ResourceAibridgeInterception.Type: {policy.ActionDelete},
@@ -1678,6 +1680,13 @@ func (q *querier) AcquireStaleChatDiffStatuses(ctx context.Context, limitVal int
 	return q.db.AcquireStaleChatDiffStatuses(ctx, limitVal)
 }
 
+func (q *querier) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.AcquireWebhookDeliveriesRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	fetch := func(ctx context.Context, arg database.ActivityBumpWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
@@ -2214,6 +2223,18 @@ func (q *querier) DeleteGroupMemberFromGroup(ctx context.Context, arg database.D
 	return update(q.log, q.auth, fetch, q.db.DeleteGroupMemberFromGroup)(ctx, arg)
 }
 
+func (q *querier) DeleteGroupUsageQuota(ctx context.Context, arg database.DeleteGroupUsageQuotaParams) (database.GroupUsageQuota, error) {
+	// Removing a group's usage quota counts as updating the group.
+	group, err := q.db.GetGroupByID(ctx, arg.GroupID)
+	if err != nil {
+		return database.GroupUsageQuota{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, group); err != nil {
+		return database.GroupUsageQuota{}, err
+	}
+	return q.db.DeleteGroupUsageQuota(ctx, arg)
+}
+
 func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	err := deleteQ(q.log, q.auth, q.db.GetLicenseByID, func(ctx context.Context, id int32) error {
 		_, err := q.db.DeleteLicense(ctx, id)
@@ -2374,6 +2395,13 @@ func (q *querier) DeleteOldTelemetryLocks(ctx context.Context, beforeTime time.T
 	return q.db.DeleteOldTelemetryLocks(ctx, beforeTime)
 }
 
+func (q *querier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWebhookDeliveries(ctx)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -2381,11 +2409,25 @@ func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold tim
 	return q.db.DeleteOldWorkspaceAgentLogs(ctx, threshold)
 }
 
-func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+func (q *querier) DeleteOldWorkspaceAgentResourceSamples(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
 	}
-	return q.db.DeleteOldWorkspaceAgentStats(ctx)
+	return q.db.DeleteOldWorkspaceAgentResourceSamples(ctx)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldWorkspaceAgentStats(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldWorkspaceClientNetworkStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldWorkspaceClientNetworkStats(ctx, beforeTime)
 }
 
 func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
@@ -2465,6 +2507,18 @@ func (q *querier) DeleteTask(ctx context.Context, arg database.DeleteTaskParams)
 	return q.db.DeleteTask(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateIdlePolicy, error) {
+	// Removing a template's idle policy counts as updating the template.
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return database.TemplateIdlePolicy{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateIdlePolicy{}, err
+	}
+	return q.db.DeleteTemplateIdlePolicy(ctx, templateID)
+}
+
 func (q *querier) DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	// Removing a user's AI budget override affects both the user (clearing
 	// their per-user spend cap) and the group it was attributed to.
@@ -2536,6 +2590,13 @@ func (q *querier) DeleteUserSkillByUserIDAndName(ctx context.Context, arg databa
 	return q.db.DeleteUserSkillByUserIDAndName(ctx, arg)
 }
 
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return err
+	}
+	return q.db.DeleteWebhookByID(ctx, id)
+}
+
 func (q *querier) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return err
@@ -2918,6 +2979,20 @@ func (q *querier) GetApplicationName(ctx context.Context) (string, error) {
 	return q.db.GetApplicationName(ctx)
 }
 
+func (q *querier) GetAuditLogExport(ctx context.Context, dataset string) (database.AuditLogExport, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.AuditLogExport{}, err
+	}
+	return q.db.GetAuditLogExport(ctx, dataset)
+}
+
+func (q *querier) GetAuditLogsForExport(ctx context.Context, arg database.GetAuditLogsForExportParams) ([]database.AuditLog, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsForExport(ctx, arg)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// Shortcut if the user is an owner. The SQL filter is noticeable,
 	// and this is an easy win for owners. Which is the common case.
@@ -3614,6 +3689,13 @@ func (q *querier) GetDatabaseNow(ctx context.Context) (time.Time, error) {
 	return q.db.GetDatabaseNow(ctx)
 }
 
+func (q *querier) GetDatabasePurgeStatus(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return "", err
+	}
+	return q.db.GetDatabasePurgeStatus(ctx)
+}
+
 func (q *querier) GetDefaultChatModelConfig(ctx context.Context) (database.ChatModelConfig, error) {
 	// Reading the default model config is needed for chat creation.
 	// TODO(CODAGT-161): scope this check when org context is available.
@@ -3643,6 +3725,13 @@ func (q *querier) GetDeploymentID(ctx context.Context) (string, error) {
 	return q.db.GetDeploymentID(ctx)
 }
 
+func (q *querier) GetDeploymentInsights(ctx context.Context, arg database.GetDeploymentInsightsParams) (database.GetDeploymentInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return database.GetDeploymentInsightsRow{}, err
+	}
+	return q.db.GetDeploymentInsights(ctx, arg)
+}
+
 func (q *querier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
 	return q.db.GetDeploymentWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -3818,6 +3907,18 @@ func (q *querier) GetGroupMembersCountByGroupIDs(ctx context.Context, arg databa
 	return q.db.GetGroupMembersCountByGroupIDs(ctx, arg)
 }
 
+func (q *querier) GetGroupUsageQuotas(ctx context.Context, groupID uuid.UUID) ([]database.GroupUsageQuota, error) {
+	// Reading a group's usage quotas requires read on the parent group.
+	group, err := q.db.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, group); err != nil {
+		return nil, err
+	}
+	return q.db.GetGroupUsageQuotas(ctx, groupID)
+}
+
 func (q *querier) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err == nil {
 		// Optimize this query for system users as it is used in telemetry.
@@ -3842,6 +3943,25 @@ func (q *querier) GetHighestGroupAIBudgetByUser(ctx context.Context, userID uuid
 	return q.db.GetHighestGroupAIBudgetByUser(ctx, userID)
 }
 
+func (q *querier) GetIdleWorkspaces(ctx context.Context, arg database.GetIdleWorkspacesParams) ([]database.GetIdleWorkspacesRow, error) {
+	// Template admins may list the idle workspaces of their template. Listing
+	// across all templates is reserved for the reaper.
+	if arg.TemplateID != uuid.Nil {
+		template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+			return nil, err
+		}
+		return q.db.GetIdleWorkspaces(ctx, arg)
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetIdleWorkspaces(ctx, arg)
+}
+
 func (q *querier) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
 	return fetchWithAction(q.log, q.auth, policy.ActionRead, q.db.GetInboxNotificationByID)(ctx, id)
 }
@@ -4109,6 +4229,13 @@ func (q *querier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid
 	return q.db.GetOAuth2ProviderAppsByUserID(ctx, userID)
 }
 
+func (q *querier) GetOldestWorkspaceAgentStatCreatedAt(ctx context.Context) (time.Time, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return time.Time{}, err
+	}
+	return q.db.GetOldestWorkspaceAgentStatCreatedAt(ctx)
+}
+
 func (q *querier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationByID)(ctx, id)
 }
@@ -4123,6 +4250,15 @@ func (q *querier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetOrganizationIDsByMemberIDs)(ctx, ids)
 }
 
+func (q *querier) GetOrganizationInsights(ctx context.Context, arg database.GetOrganizationInsightsParams) (database.GetOrganizationInsightsRow, error) {
+	// Viewing insights of all templates in the organization implies viewing
+	// the insights of the organization.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return database.GetOrganizationInsightsRow{}, err
+	}
+	return q.db.GetOrganizationInsights(ctx, arg)
+}
+
 func (q *querier) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	// Can read org members
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceOrganizationMember.InOrg(organizationID)); err != nil {
@@ -4191,6 +4327,13 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPrebuildInsights(ctx context.Context, arg database.GetPrebuildInsightsParams) ([]database.GetPrebuildInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetPrebuildInsights(ctx, arg)
+}
+
 func (q *querier) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	// GetPrebuildMetrics returns metrics related to prebuilt workspaces,
 	// such as the number of created and failed prebuilt workspaces.
@@ -4447,6 +4590,20 @@ func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, err
 	return q.db.GetRuntimeConfig(ctx, key)
 }
 
+func (q *querier) GetScaletestResultByID(ctx context.Context, id uuid.UUID) (database.ScaletestResult, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return database.ScaletestResult{}, err
+	}
+	return q.db.GetScaletestResultByID(ctx, id)
+}
+
+func (q *querier) GetScaletestResults(ctx context.Context, arg database.GetScaletestResultsParams) ([]database.ScaletestResult, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetScaletestResults(ctx, arg)
+}
+
 func (q *querier) GetStaleChats(ctx context.Context, staleThreshold time.Time) ([]database.Chat, error) {
 	// GetStaleChats is a system-level operation used by the chat processor for recovery.
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceChat); err != nil {
@@ -4523,6 +4680,13 @@ func (q *querier) GetTelemetryTaskEvents(ctx context.Context, arg database.GetTe
 	return q.db.GetTelemetryTaskEvents(ctx, arg)
 }
 
+func (q *querier) GetTemplateAccessMethodInsights(ctx context.Context, arg database.GetTemplateAccessMethodInsightsParams) ([]database.GetTemplateAccessMethodInsightsRow, error) {
+	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateAccessMethodInsights(ctx, arg)
+}
+
 func (q *querier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -4554,6 +4718,40 @@ func (q *querier) GetTemplateByOrganizationAndName(ctx context.Context, arg data
 	return fetch(q.log, q.auth, q.db.GetTemplateByOrganizationAndName)(ctx, arg)
 }
 
+func (q *querier) GetTemplateComparisonInsights(ctx context.Context, arg database.GetTemplateComparisonInsightsParams) ([]database.GetTemplateComparisonInsightsRow, error) {
+	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
+		for _, templateID := range arg.TemplateIDs {
+			template, err := q.db.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+				return nil, err
+			}
+		}
+		if len(arg.TemplateIDs) == 0 {
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q.db.GetTemplateComparisonInsights(ctx, arg)
+}
+
+func (q *querier) GetTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateIdlePolicy, error) {
+	// Reading a template's idle policy requires read on the template.
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return database.TemplateIdlePolicy{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionRead, template); err != nil {
+		return database.TemplateIdlePolicy{}, err
+	}
+	return q.db.GetTemplateIdlePolicy(ctx, templateID)
+}
+
 func (q *querier) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return database.GetTemplateInsightsRow{}, err
@@ -4631,6 +4829,28 @@ func (q *querier) GetTemplateRankingSignalsByOwnerID(ctx context.Context, arg da
 	return q.db.GetTemplateRankingSignalsByOwnerID(ctx, arg)
 }
 
+func (q *querier) GetTemplateUsageHeatmap(ctx context.Context, arg database.GetTemplateUsageHeatmapParams) ([]database.GetTemplateUsageHeatmapRow, error) {
+	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
+	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
+		for _, templateID := range arg.TemplateIDs {
+			template, err := q.db.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, template); err != nil {
+				return nil, err
+			}
+		}
+		if len(arg.TemplateIDs) == 0 {
+			if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate.All()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q.db.GetTemplateUsageHeatmap(ctx, arg)
+}
+
 func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	if err := q.authorizeTemplateInsights(ctx, arg.TemplateIDs); err != nil {
 		return nil, err
@@ -4831,6 +5051,27 @@ func (q *querier) GetUnexpiredLicenses(ctx context.Context) ([]database.License,
 	return q.db.GetUnexpiredLicenses(ctx)
 }
 
+func (q *querier) GetUnusedWorkspaces(ctx context.Context, unusedSince time.Time) ([]database.GetUnusedWorkspacesRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUnusedWorkspaces(ctx, unusedSince)
+}
+
+func (q *querier) GetUsageQuotaAllowancesForUser(ctx context.Context, arg database.GetUsageQuotaAllowancesForUserParams) ([]database.GetUsageQuotaAllowancesForUserRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetUsageQuotaAllowancesForUser(ctx, arg)
+}
+
+func (q *querier) GetUsageQuotaConsumedForUser(ctx context.Context, arg database.GetUsageQuotaConsumedForUserParams) (database.GetUsageQuotaConsumedForUserRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.GetUsageQuotaConsumedForUserRow{}, err
+	}
+	return q.db.GetUsageQuotaConsumedForUser(ctx, arg)
+}
+
 func (q *querier) GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	if _, err := q.GetUserByID(ctx, userID); err != nil { // AuthZ check
 		return database.UserAIBudgetOverride{}, err
@@ -4918,6 +5159,13 @@ func (q *querier) GetUserAppearanceSettings(ctx context.Context, userID uuid.UUI
 	return q.db.GetUserAppearanceSettings(ctx, userID)
 }
 
+func (q *querier) GetUserBandwidthInsights(ctx context.Context, arg database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserBandwidthInsights(ctx, arg)
+}
+
 func (q *querier) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	return fetch(q.log, q.auth, q.db.GetUserByEmailOrUsername)(ctx, arg)
 }
@@ -4996,6 +5244,13 @@ func (q *querier) GetUserCount(ctx context.Context, includeSystem bool) (int64,
 	return q.db.GetUserCount(ctx, includeSystem)
 }
 
+func (q *querier) GetUserCountWithUsageSince(ctx context.Context, since time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.GetUserCountWithUsageSince(ctx, since)
+}
+
 func (q *querier) GetUserGroupSpendLimit(ctx context.Context, arg database.GetUserGroupSpendLimitParams) (int64, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceChat.WithOwner(arg.UserID.String())); err != nil {
 		return 0, err
@@ -5161,6 +5416,27 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.GetWebhookByID(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentConfig); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhooks(ctx)
+}
+
 func (q *querier) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWebpushSubscription.WithOwner(userID.String())); err != nil {
 		return nil, err
@@ -5186,6 +5462,13 @@ func (q *querier) GetWorkspaceACLByID(ctx context.Context, id uuid.UUID) (databa
 	return q.db.GetWorkspaceACLByID(ctx, id)
 }
 
+func (q *querier) GetWorkspaceActivityStats(ctx context.Context, arg database.GetWorkspaceActivityStatsParams) ([]database.GetWorkspaceActivityStatsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceActivityStats(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentAndWorkspaceByIDRow, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceAgentAndWorkspaceByID)(ctx, id)
 }
@@ -5212,6 +5495,20 @@ func (q *querier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (data
 	return q.db.GetWorkspaceAgentByID(ctx, id)
 }
 
+func (q *querier) GetWorkspaceAgentConnectionLatencyByClientRegion(ctx context.Context, arg database.GetWorkspaceAgentConnectionLatencyByClientRegionParams) ([]database.GetWorkspaceAgentConnectionLatencyByClientRegionRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentConnectionLatencyByClientRegion(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentConnectionLatencyByRegion(ctx context.Context, arg database.GetWorkspaceAgentConnectionLatencyByRegionParams) ([]database.GetWorkspaceAgentConnectionLatencyByRegionRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentConnectionLatencyByRegion(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, workspaceAgentID)
 	if err != nil {
@@ -5220,6 +5517,13 @@ func (q *querier) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, w
 	return q.db.GetWorkspaceAgentDevcontainersByAgentID(ctx, workspaceAgentID)
 }
 
+func (q *querier) GetWorkspaceAgentIDsWithConnectionLatency(ctx context.Context, arg database.GetWorkspaceAgentIDsWithConnectionLatencyParams) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentIDsWithConnectionLatency(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, id)
 	if err != nil {
@@ -5271,6 +5575,13 @@ func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.G
 	return q.db.GetWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentResourceUsage(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageParams) ([]database.GetWorkspaceAgentResourceUsageRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentResourceUsage(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -5293,6 +5604,20 @@ func (q *querier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAf
 	return q.db.GetWorkspaceAgentStatsAndLabels(ctx, createdAfter)
 }
 
+func (q *querier) GetWorkspaceAgentStatsTimeSeries(ctx context.Context, arg database.GetWorkspaceAgentStatsTimeSeriesParams) ([]database.GetWorkspaceAgentStatsTimeSeriesRow, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentStatsTimeSeries(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	return q.db.GetWorkspaceAgentUsageStats(ctx, createdAt)
 }
@@ -5528,6 +5853,27 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceCost(ctx context.Context, arg database.GetWorkspaceCostParams) (database.GetWorkspaceCostRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return database.GetWorkspaceCostRow{}, err
+	}
+	return q.db.GetWorkspaceCost(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceCostInsights(ctx context.Context, arg database.GetWorkspaceCostInsightsParams) ([]database.GetWorkspaceCostInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCostInsights(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceHealthInsights(ctx context.Context, arg database.GetWorkspaceHealthInsightsParams) ([]database.GetWorkspaceHealthInsightsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceDeploymentStats); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceHealthInsights(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -5656,6 +6002,13 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceStatBaselines(ctx context.Context, workspaceIds []uuid.UUID) ([]database.WorkspaceStatBaseline, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceStatBaselines(ctx, workspaceIds)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIDs []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -6150,6 +6503,15 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertScaletestResult(ctx context.Context, arg database.InsertScaletestResultParams) (database.ScaletestResult, error) {
+	// Scaletest results describe the capacity of the deployment, so storing
+	// them requires the same permission as updating its health information.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return database.ScaletestResult{}, err
+	}
+	return q.db.InsertScaletestResult(ctx, arg)
+}
+
 func (q *querier) InsertTask(ctx context.Context, arg database.InsertTaskParams) (database.TaskTable, error) {
 	// Ensure the actor can access the specified template version (and thus its template).
 	if _, err := q.GetTemplateVersionByID(ctx, arg.TemplateVersionID); err != nil {
@@ -6284,6 +6646,20 @@ func (q *querier) InsertVolumeResourceMonitor(ctx context.Context, arg database.
 	return q.db.InsertVolumeResourceMonitor(ctx, arg)
 }
 
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.InsertWebhook(ctx, arg)
+}
+
+func (q *querier) InsertWebhookDeliveries(ctx context.Context, arg database.InsertWebhookDeliveriesParams) (int64, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.InsertWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWebpushSubscription.WithOwner(arg.UserID.String())); err != nil {
 		return database.WebpushSubscription{}, err
@@ -6353,6 +6729,13 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentResourceSamples(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceAgentResourceSamples(ctx)
+}
+
 func (q *querier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceAgentScriptTiming{}, err
@@ -6454,6 +6837,13 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceClientNetworkStat(ctx context.Context, arg database.InsertWorkspaceClientNetworkStatParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceClientNetworkStat(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceModule{}, err
@@ -7371,6 +7761,13 @@ func (q *querier) UpdateEncryptedUserAIProviderKey(ctx context.Context, arg data
 	return q.db.UpdateEncryptedUserAIProviderKey(ctx, arg)
 }
 
+func (q *querier) UpdateEncryptedWebhookSecret(ctx context.Context, arg database.UpdateEncryptedWebhookSecretParams) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.UpdateEncryptedWebhookSecret(ctx, arg)
+}
+
 func (q *querier) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		return q.db.GetExternalAuthLink(ctx, database.GetExternalAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -8201,6 +8598,13 @@ func (q *querier) UpdateVolumeResourceMonitor(ctx context.Context, arg database.
 	return q.db.UpdateVolumeResourceMonitor(ctx, arg)
 }
 
+func (q *querier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookDeliveryByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 		w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -8527,6 +8931,13 @@ func (q *querier) UpsertApplicationName(ctx context.Context, value string) error
 	return q.db.UpsertApplicationName(ctx, value)
 }
 
+func (q *querier) UpsertAuditLogExport(ctx context.Context, arg database.UpsertAuditLogExportParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertAuditLogExport(ctx, arg)
+}
+
 func (q *querier) UpsertBoundaryUsageStats(ctx context.Context, arg database.UpsertBoundaryUsageStatsParams) (bool, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceBoundaryUsage); err != nil {
 		return false, err
@@ -8704,6 +9115,13 @@ func (q *querier) UpsertChatWorkspaceTTL(ctx context.Context, workspaceTtl strin
 	return q.db.UpsertChatWorkspaceTTL(ctx, workspaceTtl)
 }
 
+func (q *querier) UpsertDatabasePurgeStatus(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertDatabasePurgeStatus(ctx, value)
+}
+
 func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -8723,6 +9141,18 @@ func (q *querier) UpsertGroupAIBudget(ctx context.Context, arg database.UpsertGr
 	return q.db.UpsertGroupAIBudget(ctx, arg)
 }
 
+func (q *querier) UpsertGroupUsageQuota(ctx context.Context, arg database.UpsertGroupUsageQuotaParams) (database.GroupUsageQuota, error) {
+	// Setting a group's usage quota counts as updating the group.
+	group, err := q.db.GetGroupByID(ctx, arg.GroupID)
+	if err != nil {
+		return database.GroupUsageQuota{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, group); err != nil {
+		return database.GroupUsageQuota{}, err
+	}
+	return q.db.UpsertGroupUsageQuota(ctx, arg)
+}
+
 func (q *querier) UpsertHealthSettings(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceDeploymentConfig); err != nil {
 		return err
@@ -8851,6 +9281,17 @@ func (q *querier) UpsertTelemetryItem(ctx context.Context, arg database.UpsertTe
 	return q.db.UpsertTelemetryItem(ctx, arg)
 }
 
+func (q *querier) UpsertTemplateIdlePolicy(ctx context.Context, arg database.UpsertTemplateIdlePolicyParams) (database.TemplateIdlePolicy, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateIdlePolicy{}, err
+	}
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, template); err != nil {
+		return database.TemplateIdlePolicy{}, err
+	}
+	return q.db.UpsertTemplateIdlePolicy(ctx, arg)
+}
+
 func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -8970,6 +9411,34 @@ func (q *querier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg databa
 	return q.db.UpsertWorkspaceAppAuditSession(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceCosts(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceCosts(ctx)
+}
+
+func (q *querier) UpsertWorkspaceIdleFlag(ctx context.Context, arg database.UpsertWorkspaceIdleFlagParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceIdleFlag(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceStatBaseline(ctx context.Context, arg database.UpsertWorkspaceStatBaselineParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceStatBaseline(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceUnusedNotification(ctx context.Context, arg database.UpsertWorkspaceUnusedNotificationParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceUnusedNotification(ctx, arg)
+}
+
 func (q *querier) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceUsageEvent); err != nil {
 		return false, err
@@ -9014,6 +9483,13 @@ func (q *querier) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) ([]da
 	return q.db.GetTemplateGroupRoles(ctx, id)
 }
 
+func (q *querier) GetTemplateUsageStatsForExport(ctx context.Context, arg database.GetTemplateUsageStatsForExportParams) ([]database.TemplateUsageStat, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateUsageStatsForExport(ctx, arg)
+}
+
 func (q *querier) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
 	// An actor is authorized to query template user roles if they are authorized to update the template.
 	template, err := q.db.GetTemplateByID(ctx, id)
@@ -438,6 +438,20 @@ func (s *MethodTestSuite) TestAuditLogs() {
 		dbm.EXPECT().DeleteOldAuditLogs(gomock.Any(), database.DeleteOldAuditLogsParams{}).Return(int64(0), nil).AnyTimes()
 		check.Args(database.DeleteOldAuditLogsParams{}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("GetAuditLogsForExport", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetAuditLogsForExportParams{}
+		dbm.EXPECT().GetAuditLogsForExport(gomock.Any(), arg).Return([]database.AuditLog{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceAuditLog, policy.ActionRead).Returns([]database.AuditLog{})
+	}))
+	s.Run("GetAuditLogExport", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetAuditLogExport(gomock.Any(), "audit_logs").Return(database.AuditLogExport{}, nil).AnyTimes()
+		check.Args("audit_logs").Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(database.AuditLogExport{})
+	}))
+	s.Run("UpsertAuditLogExport", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertAuditLogExportParams{Dataset: "audit_logs"}
+		dbm.EXPECT().UpsertAuditLogExport(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestBoundaryLogs() {
@@ -2901,11 +2915,26 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().GetTemplateParameterInsights(gomock.Any(), arg).Return([]database.GetTemplateParameterInsightsRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
+	s.Run("GetTemplateAccessMethodInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetTemplateAccessMethodInsightsParams{Tz: "UTC", IntervalDays: 1, StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetTemplateAccessMethodInsights(gomock.Any(), arg).Return([]database.GetTemplateAccessMethodInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
 	s.Run("GetTemplateInsightsByInterval", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
-		arg := database.GetTemplateInsightsByIntervalParams{IntervalDays: 7, StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		arg := database.GetTemplateInsightsByIntervalParams{Tz: "UTC", IntervalDays: 7, StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
 		dbm.EXPECT().GetTemplateInsightsByInterval(gomock.Any(), arg).Return([]database.GetTemplateInsightsByIntervalRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
 	}))
+	s.Run("GetTemplateUsageHeatmap", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetTemplateUsageHeatmapParams{Tz: "UTC", StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetTemplateUsageHeatmap(gomock.Any(), arg).Return([]database.GetTemplateUsageHeatmapRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
+	s.Run("GetTemplateComparisonInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetTemplateComparisonInsightsParams{StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetTemplateComparisonInsights(gomock.Any(), arg).Return([]database.GetTemplateComparisonInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights)
+	}))
 	s.Run("GetTemplateInsightsByTemplate", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.GetTemplateInsightsByTemplateParams{}
 		dbm.EXPECT().GetTemplateInsightsByTemplate(gomock.Any(), arg).Return([]database.GetTemplateInsightsByTemplateRow{}, nil).AnyTimes()
@@ -2931,10 +2960,19 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().GetTemplateUsageStats(gomock.Any(), arg).Return([]database.TemplateUsageStat{}, nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceTemplate, policy.ActionViewInsights).Returns([]database.TemplateUsageStat{})
 	}))
+	s.Run("GetTemplateUsageStatsForExport", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetTemplateUsageStatsForExportParams{}
+		dbm.EXPECT().GetTemplateUsageStatsForExport(gomock.Any(), arg).Return([]database.TemplateUsageStat{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.TemplateUsageStat{})
+	}))
 	s.Run("UpsertTemplateUsageStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		dbm.EXPECT().UpsertTemplateUsageStats(gomock.Any()).Return(nil).AnyTimes()
 		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
 	}))
+	s.Run("UpsertWorkspaceCosts", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().UpsertWorkspaceCosts(gomock.Any()).Return(nil).AnyTimes()
+		check.Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 	s.Run("UpdatePresetsLastInvalidatedAt", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		t1 := testutil.Fake(s.T(), faker, database.Template{})
 		arg := database.UpdatePresetsLastInvalidatedAtParams{LastInvalidatedAt: sql.NullTime{Valid: true, Time: dbtime.Now()}, TemplateID: t1.ID}
@@ -2942,6 +2980,70 @@ func (s *MethodTestSuite) TestTemplate() {
 		dbm.EXPECT().UpdatePresetsLastInvalidatedAt(gomock.Any(), arg).Return([]database.UpdatePresetsLastInvalidatedAtRow{}, nil).AnyTimes()
 		check.Args(arg).Asserts(t1, policy.ActionUpdate)
 	}))
+	s.Run("GetTemplateIdlePolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		t1 := testutil.Fake(s.T(), faker, database.Template{})
+		p := testutil.Fake(s.T(), faker, database.TemplateIdlePolicy{TemplateID: t1.ID, Action: database.IdleWorkspaceActionFlag})
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), t1.ID).Return(t1, nil).AnyTimes()
+		dbm.EXPECT().GetTemplateIdlePolicy(gomock.Any(), t1.ID).Return(p, nil).AnyTimes()
+		check.Args(t1.ID).Asserts(t1, policy.ActionRead).Returns(p)
+	}))
+	s.Run("UpsertTemplateIdlePolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		t1 := testutil.Fake(s.T(), faker, database.Template{})
+		p := testutil.Fake(s.T(), faker, database.TemplateIdlePolicy{TemplateID: t1.ID, Action: database.IdleWorkspaceActionStop})
+		arg := database.UpsertTemplateIdlePolicyParams{TemplateID: t1.ID, IdleTimeoutMs: p.IdleTimeoutMs, Action: p.Action}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), t1.ID).Return(t1, nil).AnyTimes()
+		dbm.EXPECT().UpsertTemplateIdlePolicy(gomock.Any(), arg).Return(p, nil).AnyTimes()
+		check.Args(arg).Asserts(t1, policy.ActionUpdate).Returns(p)
+	}))
+	s.Run("DeleteTemplateIdlePolicy", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		t1 := testutil.Fake(s.T(), faker, database.Template{})
+		p := testutil.Fake(s.T(), faker, database.TemplateIdlePolicy{TemplateID: t1.ID, Action: database.IdleWorkspaceActionFlag})
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), t1.ID).Return(t1, nil).AnyTimes()
+		dbm.EXPECT().DeleteTemplateIdlePolicy(gomock.Any(), t1.ID).Return(p, nil).AnyTimes()
+		check.Args(t1.ID).Asserts(t1, policy.ActionUpdate).Returns(p)
+	}))
+	s.Run("Template/GetIdleWorkspaces", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		t1 := testutil.Fake(s.T(), faker, database.Template{})
+		arg := database.GetIdleWorkspacesParams{Now: dbtime.Now(), TemplateID: t1.ID}
+		dbm.EXPECT().GetTemplateByID(gomock.Any(), t1.ID).Return(t1, nil).AnyTimes()
+		dbm.EXPECT().GetIdleWorkspaces(gomock.Any(), arg).Return([]database.GetIdleWorkspacesRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(t1, policy.ActionUpdate).Returns([]database.GetIdleWorkspacesRow{})
+	}))
+	s.Run("System/GetIdleWorkspaces", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetIdleWorkspacesParams{Now: dbtime.Now()}
+		dbm.EXPECT().GetIdleWorkspaces(gomock.Any(), arg).Return([]database.GetIdleWorkspacesRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.GetIdleWorkspacesRow{})
+	}))
+	s.Run("UpsertWorkspaceIdleFlag", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceIdleFlagParams{WorkspaceID: uuid.New(), FlaggedAt: dbtime.Now()}
+		dbm.EXPECT().UpsertWorkspaceIdleFlag(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetUnusedWorkspaces", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		unusedSince := dbtime.Now()
+		dbm.EXPECT().GetUnusedWorkspaces(gomock.Any(), unusedSince).Return([]database.GetUnusedWorkspacesRow{}, nil).AnyTimes()
+		check.Args(unusedSince).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.GetUnusedWorkspacesRow{})
+	}))
+	s.Run("UpsertWorkspaceUnusedNotification", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceUnusedNotificationParams{WorkspaceID: uuid.New(), NotifiedAt: dbtime.Now()}
+		dbm.EXPECT().UpsertWorkspaceUnusedNotification(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceActivityStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceActivityStatsParams{StartTime: dbtime.Now().Add(-time.Hour), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceActivityStats(gomock.Any(), arg).Return([]database.GetWorkspaceActivityStatsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.GetWorkspaceActivityStatsRow{})
+	}))
+	s.Run("GetWorkspaceStatBaselines", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		ids := []uuid.UUID{uuid.New()}
+		dbm.EXPECT().GetWorkspaceStatBaselines(gomock.Any(), ids).Return([]database.WorkspaceStatBaseline{}, nil).AnyTimes()
+		check.Args(ids).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.WorkspaceStatBaseline{})
+	}))
+	s.Run("UpsertWorkspaceStatBaseline", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpsertWorkspaceStatBaselineParams{WorkspaceID: uuid.New(), Metric: "tx_bytes", UpdatedAt: dbtime.Now()}
+		dbm.EXPECT().UpsertWorkspaceStatBaseline(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestUser() {
@@ -2972,6 +3074,20 @@ func (s *MethodTestSuite) TestUser() {
 		dbm.EXPECT().GetQuotaConsumedForUser(gomock.Any(), arg).Return(int64(0), nil).AnyTimes()
 		check.Args(arg).Asserts(u, policy.ActionRead).Returns(int64(0))
 	}))
+	s.Run("GetUsageQuotaAllowancesForUser", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.GetUsageQuotaAllowancesForUserParams{UserID: u.ID, OrganizationID: uuid.New()}
+		rows := []database.GetUsageQuotaAllowancesForUserRow{}
+		dbm.EXPECT().GetUsageQuotaAllowancesForUser(gomock.Any(), arg).Return(rows, nil).AnyTimes()
+		check.Args(arg).Asserts(u, policy.ActionRead).Returns(rows)
+	}))
+	s.Run("GetUsageQuotaConsumedForUser", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		u := testutil.Fake(s.T(), faker, database.User{})
+		arg := database.GetUsageQuotaConsumedForUserParams{UserID: u.ID, OrganizationID: uuid.New(), StartTime: dbtime.Now()}
+		row := database.GetUsageQuotaConsumedForUserRow{ConnectionMins: 90, ActiveMins: 60}
+		dbm.EXPECT().GetUsageQuotaConsumedForUser(gomock.Any(), arg).Return(row, nil).AnyTimes()
+		check.Args(arg).Asserts(u, policy.ActionRead).Returns(row)
+	}))
 	s.Run("GetUserAISeatStates", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		a := testutil.Fake(s.T(), faker, database.User{})
 		b := testutil.Fake(s.T(), faker, database.User{})
@@ -3709,6 +3825,20 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().GetWorkspaceAgentMetadata(gomock.Any(), arg).Return([]database.WorkspaceAgentMetadatum{dt}, nil).AnyTimes()
 		check.Args(arg).Asserts(w, policy.ActionRead).Returns([]database.WorkspaceAgentMetadatum{dt})
 	}))
+	s.Run("GetWorkspaceAgentStatsTimeSeries", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		w := testutil.Fake(s.T(), faker, database.Workspace{})
+		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
+		arg := database.GetWorkspaceAgentStatsTimeSeriesParams{
+			StartTime:     dbtime.Now().Add(-time.Hour),
+			EndTime:       dbtime.Now(),
+			BucketSeconds: 60,
+			AgentID:       agt.ID,
+		}
+		row := database.GetWorkspaceAgentStatsTimeSeriesRow{BucketStart: arg.StartTime}
+		dbm.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agt.ID).Return(w, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceAgentStatsTimeSeries(gomock.Any(), arg).Return([]database.GetWorkspaceAgentStatsTimeSeriesRow{row}, nil).AnyTimes()
+		check.Args(arg).Asserts(w, policy.ActionRead).Returns([]database.GetWorkspaceAgentStatsTimeSeriesRow{row})
+	}))
 	s.Run("BatchUpdateWorkspaceAgentMetadata", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
 		arg := database.BatchUpdateWorkspaceAgentMetadataParams{
@@ -3882,6 +4012,20 @@ func (s *MethodTestSuite) TestWorkspace() {
 		dbm.EXPECT().GetWorkspaceBuildsByWorkspaceID(gomock.Any(), arg).Return([]database.WorkspaceBuild{b1}, nil).AnyTimes()
 		check.Args(arg).Asserts(ws, policy.ActionRead).Returns([]database.WorkspaceBuild{b1})
 	}))
+	s.Run("GetWorkspaceAgentResourceUsage", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.GetWorkspaceAgentResourceUsageParams{WorkspaceID: ws.ID, StartTime: dbtime.Now().Add(-time.Hour), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceAgentResourceUsage(gomock.Any(), arg).Return([]database.GetWorkspaceAgentResourceUsageRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionRead).Returns([]database.GetWorkspaceAgentResourceUsageRow{})
+	}))
+	s.Run("GetWorkspaceCost", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		ws := testutil.Fake(s.T(), faker, database.Workspace{})
+		arg := database.GetWorkspaceCostParams{WorkspaceID: ws.ID, StartTime: dbtime.Now().Add(-time.Hour), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceByID(gomock.Any(), ws.ID).Return(ws, nil).AnyTimes()
+		dbm.EXPECT().GetWorkspaceCost(gomock.Any(), arg).Return(database.GetWorkspaceCostRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(ws, policy.ActionRead).Returns(database.GetWorkspaceCostRow{})
+	}))
 	s.Run("GetWorkspaceByAgentID", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		ws := testutil.Fake(s.T(), faker, database.Workspace{})
 		agt := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
@@ -4865,6 +5009,11 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetUserCount(gomock.Any(), false).Return(int64(0), nil).AnyTimes()
 		check.Args(false).Asserts(rbac.ResourceUser, policy.ActionRead).Returns(int64(0))
 	}))
+	s.Run("GetUserCountWithUsageSince", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		since := dbtime.Now().Add(-time.Hour)
+		dbm.EXPECT().GetUserCountWithUsageSince(gomock.Any(), since).Return(int64(0), nil).AnyTimes()
+		check.Args(since).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(int64(0))
+	}))
 	s.Run("GetTemplates", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		dbm.EXPECT().GetTemplates(gomock.Any()).Return([]database.Template{}, nil).AnyTimes()
 		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
@@ -4889,6 +5038,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetLastUpdateCheck(gomock.Any()).Return("value", nil).AnyTimes()
 		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("UpsertDatabasePurgeStatus", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().UpsertDatabasePurgeStatus(gomock.Any(), "value").Return(nil).AnyTimes()
+		check.Args("value").Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("GetDatabasePurgeStatus", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetDatabasePurgeStatus(gomock.Any()).Return("value", nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceBuildsCreatedAfter", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		ts := dbtime.Now()
 		dbm.EXPECT().GetWorkspaceBuildsCreatedAfter(gomock.Any(), ts).Return([]database.WorkspaceBuild{}, nil).AnyTimes()
@@ -4932,9 +5089,23 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().GetWorkspaceResourceMetadataCreatedAfter(gomock.Any(), ts).Return([]database.WorkspaceResourceMetadatum{}, nil).AnyTimes()
 		check.Args(ts).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("DeleteOldWorkspaceAgentResourceSamples", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().DeleteOldWorkspaceAgentResourceSamples(gomock.Any()).Return(nil).AnyTimes()
+		check.Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("DeleteOldWorkspaceAgentStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
-		dbm.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any()).Return(nil).AnyTimes()
-		check.Args().Asserts(rbac.ResourceSystem, policy.ActionDelete)
+		t := dbtime.Now()
+		dbm.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any(), t).Return(int64(0), nil).AnyTimes()
+		check.Args(t).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceClientNetworkStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		t := dbtime.Now()
+		dbm.EXPECT().DeleteOldWorkspaceClientNetworkStats(gomock.Any(), t).Return(int64(0), nil).AnyTimes()
+		check.Args(t).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetOldestWorkspaceAgentStatCreatedAt", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().GetOldestWorkspaceAgentStatCreatedAt(gomock.Any()).Return(dbtime.Now(), nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		ts := dbtime.Now()
@@ -5145,6 +5316,10 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().DeleteOldWorkspaceAgentLogs(gomock.Any(), t).Return(int64(0), nil).AnyTimes()
 		check.Args(t).Asserts(rbac.ResourceSystem, policy.ActionDelete)
 	}))
+	s.Run("InsertWorkspaceAgentResourceSamples", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		dbm.EXPECT().InsertWorkspaceAgentResourceSamples(gomock.Any()).Return(nil).AnyTimes()
+		check.Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceAgentStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		arg := database.InsertWorkspaceAgentStatsParams{}
 		dbm.EXPECT().InsertWorkspaceAgentStats(gomock.Any(), arg).Return(xerrors.New("any error")).AnyTimes()
@@ -5155,6 +5330,11 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().InsertWorkspaceAppStats(gomock.Any(), arg).Return(nil).AnyTimes()
 		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceClientNetworkStat", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertWorkspaceClientNetworkStatParams{}
+		dbm.EXPECT().InsertWorkspaceClientNetworkStat(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("UpsertWorkspaceAppAuditSession", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		u := testutil.Fake(s.T(), faker, database.User{})
 		agent := testutil.Fake(s.T(), faker, database.WorkspaceAgent{})
@@ -5222,6 +5402,59 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		dbm.EXPECT().UpsertNotificationsSettings(gomock.Any(), "foo").Return(nil).AnyTimes()
 		check.Args("foo").Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
 	}))
+	s.Run("GetDeploymentInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetDeploymentInsightsParams{StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetDeploymentInsights(gomock.Any(), arg).Return(database.GetDeploymentInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentIDsWithConnectionLatency", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentIDsWithConnectionLatencyParams{StartTime: dbtime.Now().Add(-time.Hour), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceAgentIDsWithConnectionLatency(gomock.Any(), arg).Return([]uuid.UUID{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentConnectionLatencyByRegion", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentConnectionLatencyByRegionParams{
+			AgentIDs:  []uuid.UUID{uuid.New()},
+			RegionIDs: []int32{1},
+			StartTime: dbtime.Now().Add(-time.Hour),
+			EndTime:   dbtime.Now(),
+		}
+		dbm.EXPECT().GetWorkspaceAgentConnectionLatencyByRegion(gomock.Any(), arg).Return([]database.GetWorkspaceAgentConnectionLatencyByRegionRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentConnectionLatencyByClientRegion", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceAgentConnectionLatencyByClientRegionParams{
+			StartTime: dbtime.Now().Add(-time.Hour),
+			EndTime:   dbtime.Now(),
+		}
+		dbm.EXPECT().GetWorkspaceAgentConnectionLatencyByClientRegion(gomock.Any(), arg).Return([]database.GetWorkspaceAgentConnectionLatencyByClientRegionRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetUserBandwidthInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetUserBandwidthInsightsParams{StartTime: dbtime.Now().Add(-time.Hour), EndTime: dbtime.Now(), LimitOpt: 10}
+		dbm.EXPECT().GetUserBandwidthInsights(gomock.Any(), arg).Return([]database.GetUserBandwidthInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceCostInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceCostInsightsParams{GroupBy: "workspace", StartTime: dbtime.Now().Add(-time.Hour), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetWorkspaceCostInsights(gomock.Any(), arg).Return([]database.GetWorkspaceCostInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetPrebuildInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetPrebuildInsightsParams{StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetPrebuildInsights(gomock.Any(), arg).Return([]database.GetPrebuildInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceHealthInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWorkspaceHealthInsightsParams{BuildsSince: dbtime.Now().Add(-time.Hour * 24 * 7), LatencySince: dbtime.Now().Add(-time.Hour), BaselineSince: dbtime.Now().Add(-time.Hour * 24 * 7)}
+		dbm.EXPECT().GetWorkspaceHealthInsights(gomock.Any(), arg).Return([]database.GetWorkspaceHealthInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+	s.Run("GetOrganizationInsights", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetOrganizationInsightsParams{OrganizationID: uuid.New(), StartTime: dbtime.Now().Add(-time.Hour * 24 * 7), EndTime: dbtime.Now()}
+		dbm.EXPECT().GetOrganizationInsights(gomock.Any(), arg).Return(database.GetOrganizationInsightsRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceTemplate.InOrg(arg.OrganizationID), policy.ActionViewInsights)
+	}))
 	s.Run("GetDeploymentWorkspaceAgentStats", s.Mocked(func(dbm *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
 		t := time.Time{}
 		dbm.EXPECT().GetDeploymentWorkspaceAgentStats(gomock.Any(), t).Return(database.GetDeploymentWorkspaceAgentStatsRow{}, nil).AnyTimes()
@@ -6766,6 +6999,32 @@ func (s *MethodTestSuite) TestAIBridge() {
 		check.Args(g.ID).Asserts(g, policy.ActionUpdate).Returns(b)
 	}))
 
+	s.Run("GetGroupUsageQuotas", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		g := testutil.Fake(s.T(), faker, database.Group{})
+		q := testutil.Fake(s.T(), faker, database.GroupUsageQuota{GroupID: g.ID, Metric: database.UsageQuotaMetricConnectionHours, Action: database.UsageQuotaActionReport})
+		dbm.EXPECT().GetGroupByID(gomock.Any(), g.ID).Return(g, nil).AnyTimes()
+		dbm.EXPECT().GetGroupUsageQuotas(gomock.Any(), g.ID).Return([]database.GroupUsageQuota{q}, nil).AnyTimes()
+		check.Args(g.ID).Asserts(g, policy.ActionRead).Returns([]database.GroupUsageQuota{q})
+	}))
+
+	s.Run("UpsertGroupUsageQuota", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		g := testutil.Fake(s.T(), faker, database.Group{})
+		q := testutil.Fake(s.T(), faker, database.GroupUsageQuota{GroupID: g.ID, Metric: database.UsageQuotaMetricActiveHours, Action: database.UsageQuotaActionBlockStart})
+		arg := database.UpsertGroupUsageQuotaParams{GroupID: g.ID, Metric: q.Metric, MonthlyHours: q.MonthlyHours, Action: q.Action}
+		dbm.EXPECT().GetGroupByID(gomock.Any(), g.ID).Return(g, nil).AnyTimes()
+		dbm.EXPECT().UpsertGroupUsageQuota(gomock.Any(), arg).Return(q, nil).AnyTimes()
+		check.Args(arg).Asserts(g, policy.ActionUpdate).Returns(q)
+	}))
+
+	s.Run("DeleteGroupUsageQuota", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		g := testutil.Fake(s.T(), faker, database.Group{})
+		q := testutil.Fake(s.T(), faker, database.GroupUsageQuota{GroupID: g.ID, Metric: database.UsageQuotaMetricConnectionHours, Action: database.UsageQuotaActionReport})
+		arg := database.DeleteGroupUsageQuotaParams{GroupID: g.ID, Metric: q.Metric}
+		dbm.EXPECT().GetGroupByID(gomock.Any(), g.ID).Return(g, nil).AnyTimes()
+		dbm.EXPECT().DeleteGroupUsageQuota(gomock.Any(), arg).Return(q, nil).AnyTimes()
+		check.Args(arg).Asserts(g, policy.ActionUpdate).Returns(q)
+	}))
+
 	s.Run("GetUserAIBudgetOverride", s.Mocked(func(dbm *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
 		user := testutil.Fake(s.T(), faker, database.User{})
 		override := testutil.Fake(s.T(), faker, database.UserAIBudgetOverride{UserID: user.ID})
@@ -6987,6 +7246,89 @@ func (s *MethodTestSuite) TestTelemetry() {
 	}))
 }
 
+func (s *MethodTestSuite) TestScaletestResults() {
+	s.Run("InsertScaletestResult", s.Mocked(func(db *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		res := testutil.Fake(s.T(), faker, database.ScaletestResult{})
+		arg := database.InsertScaletestResultParams{ID: res.ID, CreatedBy: res.CreatedBy, Name: res.Name}
+		db.EXPECT().InsertScaletestResult(gomock.Any(), arg).Return(res, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate).Returns(res)
+	}))
+
+	s.Run("GetScaletestResultByID", s.Mocked(func(db *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		res := testutil.Fake(s.T(), faker, database.ScaletestResult{})
+		db.EXPECT().GetScaletestResultByID(gomock.Any(), res.ID).Return(res, nil).AnyTimes()
+		check.Args(res.ID).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead).Returns(res)
+	}))
+
+	s.Run("GetScaletestResults", s.Mocked(func(db *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		arg := database.GetScaletestResultsParams{Name: "create-workspaces", LimitOpt: 10}
+		db.EXPECT().GetScaletestResults(gomock.Any(), arg).Return([]database.ScaletestResult{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentStats, policy.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestWebhooks() {
+	s.Run("InsertWebhook", s.Mocked(func(db *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		webhook := testutil.Fake(s.T(), faker, database.Webhook{})
+		arg := database.InsertWebhookParams{ID: webhook.ID, CreatedBy: webhook.CreatedBy, Url: webhook.Url, Secret: webhook.Secret}
+		db.EXPECT().InsertWebhook(gomock.Any(), arg).Return(webhook, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate).Returns(webhook)
+	}))
+
+	s.Run("GetWebhooks", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		db.EXPECT().GetWebhooks(gomock.Any()).Return([]database.Webhook{}, nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead)
+	}))
+
+	s.Run("GetWebhookByID", s.Mocked(func(db *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		webhook := testutil.Fake(s.T(), faker, database.Webhook{})
+		db.EXPECT().GetWebhookByID(gomock.Any(), webhook.ID).Return(webhook, nil).AnyTimes()
+		check.Args(webhook.ID).Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead).Returns(webhook)
+	}))
+
+	s.Run("DeleteWebhookByID", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		id := uuid.New()
+		db.EXPECT().DeleteWebhookByID(gomock.Any(), id).Return(nil).AnyTimes()
+		check.Args(id).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate)
+	}))
+
+	s.Run("UpdateEncryptedWebhookSecret", s.Mocked(func(db *dbmock.MockStore, faker *gofakeit.Faker, check *expects) {
+		webhook := testutil.Fake(s.T(), faker, database.Webhook{})
+		arg := database.UpdateEncryptedWebhookSecretParams{ID: webhook.ID, Secret: "encrypted-secret"}
+		db.EXPECT().UpdateEncryptedWebhookSecret(gomock.Any(), arg).Return(webhook, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentConfig, policy.ActionUpdate).Returns(webhook)
+	}))
+
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.GetWebhookDeliveriesByWebhookIDParams{WebhookID: uuid.New(), LimitOpt: 10}
+		db.EXPECT().GetWebhookDeliveriesByWebhookID(gomock.Any(), arg).Return([]database.WebhookDelivery{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceDeploymentConfig, policy.ActionRead)
+	}))
+
+	s.Run("InsertWebhookDeliveries", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.InsertWebhookDeliveriesParams{Event: "workspace_build_succeeded", TemplateID: uuid.New()}
+		db.EXPECT().InsertWebhookDeliveries(gomock.Any(), arg).Return(int64(1), nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+
+	s.Run("AcquireWebhookDeliveries", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.AcquireWebhookDeliveriesParams{LimitOpt: 10}
+		db.EXPECT().AcquireWebhookDeliveries(gomock.Any(), arg).Return([]database.AcquireWebhookDeliveriesRow{}, nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+
+	s.Run("UpdateWebhookDeliveryByID", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		arg := database.UpdateWebhookDeliveryByIDParams{ID: uuid.New(), Status: database.WebhookDeliveryStatusSucceeded}
+		db.EXPECT().UpdateWebhookDeliveryByID(gomock.Any(), arg).Return(nil).AnyTimes()
+		check.Args(arg).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+
+	s.Run("DeleteOldWebhookDeliveries", s.Mocked(func(db *dbmock.MockStore, _ *gofakeit.Faker, check *expects) {
+		db.EXPECT().DeleteOldWebhookDeliveries(gomock.Any()).Return(nil).AnyTimes()
+		check.Args().Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+}
+
 func TestGetLatestWorkspaceBuildByWorkspaceID_FastPath(t *testing.T) {
 	t.Parallel()
 
@@ -1723,6 +1723,30 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 	}
 }
 
+func WorkspaceClientNetworkStat(t testing.TB, db database.Store, orig database.WorkspaceClientNetworkStat) database.WorkspaceClientNetworkStat {
+	params := database.InsertWorkspaceClientNetworkStatParams{
+		ID:           takeFirst(orig.ID, uuid.New()),
+		CreatedAt:    takeFirst(orig.CreatedAt, dbtime.Now()),
+		UserID:       takeFirst(orig.UserID, uuid.New()),
+		WorkspaceID:  takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:      takeFirst(orig.AgentID, uuid.New()),
+		ClientRegion: orig.ClientRegion,
+		ClientAsn:    orig.ClientAsn,
+	}
+	err := db.InsertWorkspaceClientNetworkStat(genCtx, params)
+	require.NoError(t, err, "insert workspace client network stat")
+
+	return database.WorkspaceClientNetworkStat{
+		ID:           params.ID,
+		CreatedAt:    params.CreatedAt,
+		UserID:       params.UserID,
+		WorkspaceID:  params.WorkspaceID,
+		AgentID:      params.AgentID,
+		ClientRegion: params.ClientRegion,
+		ClientAsn:    params.ClientAsn,
+	}
+}
+
 func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
 	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
 		ID:                      takeFirst(seed.ID, uuid.New()),
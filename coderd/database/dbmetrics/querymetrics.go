@@ -95,6 +95,14 @@ func (m queryMetricsStore) InTx(f func(database.Store) error, options *database.
 	return m.dbMetrics.InTx(f, options)
 }
 
+func (m queryMetricsStore) DeleteOldWorkspaceClientNetworkStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldWorkspaceClientNetworkStats(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceClientNetworkStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWorkspaceClientNetworkStats").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.UpdateOrganizationDeletedByID(ctx, database.UpdateOrganizationDeletedByIDParams{
@@ -146,6 +154,14 @@ func (m queryMetricsStore) AcquireStaleChatDiffStatuses(ctx context.Context, lim
 	return r0, r1
 }
 
+func (m queryMetricsStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.AcquireWebhookDeliveriesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireWebhookDeliveries").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "AcquireWebhookDeliveries").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	start := time.Now()
 	r0 := m.s.ActivityBumpWorkspace(ctx, arg)
@@ -634,6 +650,14 @@ func (m queryMetricsStore) DeleteGroupMemberFromGroup(ctx context.Context, arg d
 	return r0
 }
 
+func (m queryMetricsStore) DeleteGroupUsageQuota(ctx context.Context, arg database.DeleteGroupUsageQuotaParams) (database.GroupUsageQuota, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteGroupUsageQuota(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteGroupUsageQuota").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteGroupUsageQuota").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteLicense(ctx, id)
@@ -802,6 +826,14 @@ func (m queryMetricsStore) DeleteOldTelemetryLocks(ctx context.Context, periodEn
 	return r0
 }
 
+func (m queryMetricsStore) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWebhookDeliveries(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWebhookDeliveries").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWebhookDeliveries").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteOldWorkspaceAgentLogs(ctx, threshold)
@@ -810,12 +842,20 @@ func (m queryMetricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, thre
 	return r0, r1
 }
 
-func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+func (m queryMetricsStore) DeleteOldWorkspaceAgentResourceSamples(ctx context.Context) error {
 	start := time.Now()
-	r0 := m.s.DeleteOldWorkspaceAgentStats(ctx)
+	r0 := m.s.DeleteOldWorkspaceAgentResourceSamples(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentResourceSamples").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWorkspaceAgentResourceSamples").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOldWorkspaceAgentStats(ctx, beforeTime)
 	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentStats").Observe(time.Since(start).Seconds())
 	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteOldWorkspaceAgentStats").Inc()
-	return r0
+	return r0, r1
 }
 
 func (m queryMetricsStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
@@ -890,6 +930,14 @@ func (m queryMetricsStore) DeleteTask(ctx context.Context, arg database.DeleteTa
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateIdlePolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteTemplateIdlePolicy(ctx, templateID)
+	m.queryLatencies.WithLabelValues("DeleteTemplateIdlePolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteTemplateIdlePolicy").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteUserAIBudgetOverride(ctx, userID)
@@ -938,6 +986,14 @@ func (m queryMetricsStore) DeleteUserSkillByUserIDAndName(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebhookByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "DeleteWebhookByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx, arg)
@@ -1338,6 +1394,22 @@ func (m queryMetricsStore) GetApplicationName(ctx context.Context) (string, erro
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetAuditLogExport(ctx context.Context, dataset string) (database.AuditLogExport, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogExport(ctx, dataset)
+	m.queryLatencies.WithLabelValues("GetAuditLogExport").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetAuditLogExport").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogsForExport(ctx context.Context, arg database.GetAuditLogsForExportParams) ([]database.AuditLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogsForExport(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogsForExport").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetAuditLogsForExport").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAuditLogsOffset(ctx, arg)
@@ -1938,6 +2010,14 @@ func (m queryMetricsStore) GetDatabaseNow(ctx context.Context) (time.Time, error
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetDatabasePurgeStatus(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetDatabasePurgeStatus(ctx)
+	m.queryLatencies.WithLabelValues("GetDatabasePurgeStatus").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetDatabasePurgeStatus").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetDefaultChatModelConfig(ctx context.Context) (database.ChatModelConfig, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDefaultChatModelConfig(ctx)
@@ -1970,6 +2050,14 @@ func (m queryMetricsStore) GetDeploymentID(ctx context.Context) (string, error)
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetDeploymentInsights(ctx context.Context, arg database.GetDeploymentInsightsParams) (database.GetDeploymentInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetDeploymentInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetDeploymentInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetDeploymentInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDeploymentWorkspaceAgentStats(ctx, createdAt)
@@ -2170,6 +2258,14 @@ func (m queryMetricsStore) GetGroupMembersCountByGroupIDs(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetGroupUsageQuotas(ctx context.Context, groupID uuid.UUID) ([]database.GroupUsageQuota, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupUsageQuotas(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetGroupUsageQuotas").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetGroupUsageQuotas").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetGroups(ctx, arg)
@@ -2194,6 +2290,14 @@ func (m queryMetricsStore) GetHighestGroupAIBudgetByUser(ctx context.Context, us
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetIdleWorkspaces(ctx context.Context, arg database.GetIdleWorkspacesParams) ([]database.GetIdleWorkspacesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetIdleWorkspaces(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetIdleWorkspaces").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetIdleWorkspaces").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetInboxNotificationByID(ctx, id)
@@ -2490,6 +2594,14 @@ func (m queryMetricsStore) GetOAuth2ProviderAppsByUserID(ctx context.Context, us
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOldestWorkspaceAgentStatCreatedAt(ctx context.Context) (time.Time, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOldestWorkspaceAgentStatCreatedAt(ctx)
+	m.queryLatencies.WithLabelValues("GetOldestWorkspaceAgentStatCreatedAt").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetOldestWorkspaceAgentStatCreatedAt").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOrganizationByID(ctx, id)
@@ -2514,6 +2626,14 @@ func (m queryMetricsStore) GetOrganizationIDsByMemberIDs(ctx context.Context, id
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetOrganizationInsights(ctx context.Context, arg database.GetOrganizationInsightsParams) (database.GetOrganizationInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetOrganizationInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetOrganizationInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOrganizationResourceCountByID(ctx, organizationID)
@@ -2554,6 +2674,14 @@ func (m queryMetricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetPrebuildInsights(ctx context.Context, arg database.GetPrebuildInsightsParams) ([]database.GetPrebuildInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPrebuildInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPrebuildInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetPrebuildInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetPrebuildMetrics(ctx)
@@ -2810,6 +2938,22 @@ func (m queryMetricsStore) GetRuntimeConfig(ctx context.Context, key string) (st
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetScaletestResultByID(ctx context.Context, id uuid.UUID) (database.ScaletestResult, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetScaletestResultByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetScaletestResultByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetScaletestResultByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetScaletestResults(ctx context.Context, arg database.GetScaletestResultsParams) ([]database.ScaletestResult, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetScaletestResults(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetScaletestResults").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetScaletestResults").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetStaleChats(ctx context.Context, staleThreshold time.Time) ([]database.Chat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetStaleChats(ctx, staleThreshold)
@@ -2898,6 +3042,14 @@ func (m queryMetricsStore) GetTelemetryTaskEvents(ctx context.Context, createdAf
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateAccessMethodInsights(ctx context.Context, arg database.GetTemplateAccessMethodInsightsParams) ([]database.GetTemplateAccessMethodInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAccessMethodInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateAccessMethodInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateAccessMethodInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
@@ -2938,6 +3090,22 @@ func (m queryMetricsStore) GetTemplateByOrganizationAndName(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateComparisonInsights(ctx context.Context, arg database.GetTemplateComparisonInsightsParams) ([]database.GetTemplateComparisonInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateComparisonInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateComparisonInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateComparisonInsights").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateIdlePolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateIdlePolicy(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateIdlePolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateIdlePolicy").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsights(ctx, arg)
@@ -2986,6 +3154,14 @@ func (m queryMetricsStore) GetTemplateRankingSignalsByOwnerID(ctx context.Contex
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateUsageHeatmap(ctx context.Context, arg database.GetTemplateUsageHeatmapParams) ([]database.GetTemplateUsageHeatmapRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateUsageHeatmap(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateUsageHeatmap").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateUsageHeatmap").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
@@ -3106,6 +3282,30 @@ func (m queryMetricsStore) GetUnexpiredLicenses(ctx context.Context) ([]database
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUnusedWorkspaces(ctx context.Context, unusedSince time.Time) ([]database.GetUnusedWorkspacesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnusedWorkspaces(ctx, unusedSince)
+	m.queryLatencies.WithLabelValues("GetUnusedWorkspaces").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUnusedWorkspaces").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUsageQuotaAllowancesForUser(ctx context.Context, arg database.GetUsageQuotaAllowancesForUserParams) ([]database.GetUsageQuotaAllowancesForUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUsageQuotaAllowancesForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUsageQuotaAllowancesForUser").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUsageQuotaAllowancesForUser").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetUsageQuotaConsumedForUser(ctx context.Context, arg database.GetUsageQuotaConsumedForUserParams) (database.GetUsageQuotaConsumedForUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUsageQuotaConsumedForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUsageQuotaConsumedForUser").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUsageQuotaConsumedForUser").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserAIBudgetOverride(ctx, userID)
@@ -3170,6 +3370,14 @@ func (m queryMetricsStore) GetUserAppearanceSettings(ctx context.Context, userID
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserBandwidthInsights(ctx context.Context, arg database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserBandwidthInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserBandwidthInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserBandwidthInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserByEmailOrUsername(ctx, arg)
@@ -3242,6 +3450,14 @@ func (m queryMetricsStore) GetUserCount(ctx context.Context, includeSystem bool)
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetUserCountWithUsageSince(ctx context.Context, since time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserCountWithUsageSince(ctx, since)
+	m.queryLatencies.WithLabelValues("GetUserCountWithUsageSince").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetUserCountWithUsageSince").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserGroupSpendLimit(ctx context.Context, userID database.GetUserGroupSpendLimitParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserGroupSpendLimit(ctx, userID)
@@ -3378,6 +3594,30 @@ func (m queryMetricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWebhookByID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveriesByWebhookID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWebhookDeliveriesByWebhookID").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooks(ctx)
+	m.queryLatencies.WithLabelValues("GetWebhooks").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWebhooks").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWebpushSubscriptionsByUserID(ctx, userID)
@@ -3402,6 +3642,14 @@ func (m queryMetricsStore) GetWorkspaceACLByID(ctx context.Context, id uuid.UUID
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceActivityStats(ctx context.Context, arg database.GetWorkspaceActivityStatsParams) ([]database.GetWorkspaceActivityStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceActivityStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceActivityStats").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceActivityStats").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentAndWorkspaceByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentAndWorkspaceByID(ctx, id)
@@ -3418,6 +3666,22 @@ func (m queryMetricsStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UU
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentConnectionLatencyByClientRegion(ctx context.Context, arg database.GetWorkspaceAgentConnectionLatencyByClientRegionParams) ([]database.GetWorkspaceAgentConnectionLatencyByClientRegionRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentConnectionLatencyByClientRegion(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentConnectionLatencyByClientRegion").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentConnectionLatencyByClientRegion").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceAgentConnectionLatencyByRegion(ctx context.Context, arg database.GetWorkspaceAgentConnectionLatencyByRegionParams) ([]database.GetWorkspaceAgentConnectionLatencyByRegionRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentConnectionLatencyByRegion(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentConnectionLatencyByRegion").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentConnectionLatencyByRegion").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentDevcontainersByAgentID(ctx, workspaceAgentID)
@@ -3426,6 +3690,14 @@ func (m queryMetricsStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.C
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentIDsWithConnectionLatency(ctx context.Context, arg database.GetWorkspaceAgentIDsWithConnectionLatencyParams) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentIDsWithConnectionLatency(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentIDsWithConnectionLatency").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentIDsWithConnectionLatency").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLifecycleStateByID(ctx, id)
@@ -3466,6 +3738,14 @@ func (m queryMetricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg d
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentResourceUsage(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageParams) ([]database.GetWorkspaceAgentResourceUsageRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentResourceUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentResourceUsage").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentResourceUsage").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentScriptTimingsByBuildID(ctx, id)
@@ -3498,6 +3778,14 @@ func (m queryMetricsStore) GetWorkspaceAgentStatsAndLabels(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentStatsTimeSeries(ctx context.Context, arg database.GetWorkspaceAgentStatsTimeSeriesParams) ([]database.GetWorkspaceAgentStatsTimeSeriesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentStatsTimeSeries(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentStatsTimeSeries").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceAgentStatsTimeSeries").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentUsageStats(ctx, createdAt)
@@ -3730,6 +4018,30 @@ func (m queryMetricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, wor
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceCost(ctx context.Context, arg database.GetWorkspaceCostParams) (database.GetWorkspaceCostRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCost(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCost").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceCost").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceCostInsights(ctx context.Context, arg database.GetWorkspaceCostInsightsParams) ([]database.GetWorkspaceCostInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCostInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCostInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceCostInsights").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceHealthInsights(ctx context.Context, arg database.GetWorkspaceHealthInsightsParams) ([]database.GetWorkspaceHealthInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceHealthInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceHealthInsights").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceHealthInsights").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesByJobID(ctx, jobID)
@@ -3826,6 +4138,14 @@ func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetWorkspaceStatBaselines(ctx context.Context, workspaceIds []uuid.UUID) ([]database.WorkspaceStatBaseline, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceStatBaselines(ctx, workspaceIds)
+	m.queryLatencies.WithLabelValues("GetWorkspaceStatBaselines").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetWorkspaceStatBaselines").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
@@ -4298,6 +4618,14 @@ func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.Inser
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertScaletestResult(ctx context.Context, arg database.InsertScaletestResultParams) (database.ScaletestResult, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertScaletestResult(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertScaletestResult").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertScaletestResult").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertTask(ctx context.Context, arg database.InsertTaskParams) (database.TaskTable, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertTask(ctx, arg)
@@ -4418,6 +4746,22 @@ func (m queryMetricsStore) InsertVolumeResourceMonitor(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhook").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWebhook").Inc()
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWebhookDeliveries(ctx context.Context, arg database.InsertWebhookDeliveriesParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhookDeliveries").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWebhookDeliveries").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWebpushSubscription(ctx, arg)
@@ -4474,6 +4818,14 @@ func (m queryMetricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentResourceSamples(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceAgentResourceSamples(ctx)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentResourceSamples").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceAgentResourceSamples").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScriptTimings(ctx, arg)
@@ -4530,6 +4882,14 @@ func (m queryMetricsStore) InsertWorkspaceBuildParameters(ctx context.Context, a
 	return r0
 }
 
+func (m queryMetricsStore) InsertWorkspaceClientNetworkStat(ctx context.Context, arg database.InsertWorkspaceClientNetworkStatParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceClientNetworkStat(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceClientNetworkStat").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "InsertWorkspaceClientNetworkStat").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceModule(ctx, arg)
@@ -5266,6 +5626,14 @@ func (m queryMetricsStore) UpdateEncryptedUserAIProviderKey(ctx context.Context,
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpdateEncryptedWebhookSecret(ctx context.Context, arg database.UpdateEncryptedWebhookSecretParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateEncryptedWebhookSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateEncryptedWebhookSecret").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateEncryptedWebhookSecret").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateExternalAuthLink(ctx, arg)
@@ -5826,6 +6194,14 @@ func (m queryMetricsStore) UpdateVolumeResourceMonitor(ctx context.Context, arg
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWebhookDeliveryByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpdateWebhookDeliveryByID").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateWorkspace(ctx, arg)
@@ -6058,6 +6434,14 @@ func (m queryMetricsStore) UpsertApplicationName(ctx context.Context, value stri
 	return r0
 }
 
+func (m queryMetricsStore) UpsertAuditLogExport(ctx context.Context, arg database.UpsertAuditLogExportParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertAuditLogExport(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertAuditLogExport").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertAuditLogExport").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpsertBoundaryUsageStats(ctx context.Context, arg database.UpsertBoundaryUsageStatsParams) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertBoundaryUsageStats(ctx, arg)
@@ -6242,6 +6626,14 @@ func (m queryMetricsStore) UpsertChatWorkspaceTTL(ctx context.Context, workspace
 	return r0
 }
 
+func (m queryMetricsStore) UpsertDatabasePurgeStatus(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertDatabasePurgeStatus(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertDatabasePurgeStatus").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertDatabasePurgeStatus").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertDefaultProxy(ctx, arg)
@@ -6258,6 +6650,14 @@ func (m queryMetricsStore) UpsertGroupAIBudget(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertGroupUsageQuota(ctx context.Context, arg database.UpsertGroupUsageQuotaParams) (database.GroupUsageQuota, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertGroupUsageQuota(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertGroupUsageQuota").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertGroupUsageQuota").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertHealthSettings(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertHealthSettings(ctx, value)
@@ -6386,6 +6786,14 @@ func (m queryMetricsStore) UpsertTelemetryItem(ctx context.Context, arg database
 	return r0
 }
 
+func (m queryMetricsStore) UpsertTemplateIdlePolicy(ctx context.Context, arg database.UpsertTemplateIdlePolicyParams) (database.TemplateIdlePolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateIdlePolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateIdlePolicy").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertTemplateIdlePolicy").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.UpsertTemplateUsageStats(ctx)
@@ -6474,6 +6882,38 @@ func (m queryMetricsStore) UpsertWorkspaceAppAuditSession(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceCosts(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceCosts(ctx)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceCosts").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceCosts").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceIdleFlag(ctx context.Context, arg database.UpsertWorkspaceIdleFlagParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceIdleFlag(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceIdleFlag").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceIdleFlag").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceStatBaseline(ctx context.Context, arg database.UpsertWorkspaceStatBaselineParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceStatBaseline(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceStatBaseline").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceStatBaseline").Inc()
+	return r0
+}
+
+func (m queryMetricsStore) UpsertWorkspaceUnusedNotification(ctx context.Context, arg database.UpsertWorkspaceUnusedNotificationParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspaceUnusedNotification(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceUnusedNotification").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "UpsertWorkspaceUnusedNotification").Inc()
+	return r0
+}
+
 func (m queryMetricsStore) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
 	start := time.Now()
 	r0, r1 := m.s.UsageEventExistsByID(ctx, id)
@@ -6514,6 +6954,14 @@ func (m queryMetricsStore) GetTemplateGroupRoles(ctx context.Context, id uuid.UU
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetTemplateUsageStatsForExport(ctx context.Context, arg database.GetTemplateUsageStatsForExportParams) ([]database.TemplateUsageStat, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateUsageStatsForExport(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateUsageStatsForExport").Observe(time.Since(start).Seconds())
+	m.queryCounts.WithLabelValues(httpmw.ExtractHTTPRoute(ctx), httpmw.ExtractHTTPMethod(ctx), "GetTemplateUsageStatsForExport").Inc()
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateUserRoles(ctx, id)
@@ -120,6 +120,21 @@ func (mr *MockStoreMockRecorder) AcquireStaleChatDiffStatuses(ctx, limitVal any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireStaleChatDiffStatuses", reflect.TypeOf((*MockStore)(nil).AcquireStaleChatDiffStatuses), ctx, limitVal)
 }
 
+// AcquireWebhookDeliveries mocks base method.
+func (m *MockStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.AcquireWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]database.AcquireWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireWebhookDeliveries indicates an expected call of AcquireWebhookDeliveries.
+func (mr *MockStoreMockRecorder) AcquireWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).AcquireWebhookDeliveries), ctx, arg)
+}
+
 // ActivityBumpWorkspace mocks base method.
 func (m *MockStore) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	m.ctrl.T.Helper()
@@ -1049,6 +1064,21 @@ func (mr *MockStoreMockRecorder) DeleteGroupMemberFromGroup(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupMemberFromGroup", reflect.TypeOf((*MockStore)(nil).DeleteGroupMemberFromGroup), ctx, arg)
 }
 
+// DeleteGroupUsageQuota mocks base method.
+func (m *MockStore) DeleteGroupUsageQuota(ctx context.Context, arg database.DeleteGroupUsageQuotaParams) (database.GroupUsageQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupUsageQuota", ctx, arg)
+	ret0, _ := ret[0].(database.GroupUsageQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteGroupUsageQuota indicates an expected call of DeleteGroupUsageQuota.
+func (mr *MockStoreMockRecorder) DeleteGroupUsageQuota(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupUsageQuota", reflect.TypeOf((*MockStore)(nil).DeleteGroupUsageQuota), ctx, arg)
+}
+
 // DeleteLicense mocks base method.
 func (m *MockStore) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	m.ctrl.T.Helper()
@@ -1352,6 +1382,20 @@ func (mr *MockStoreMockRecorder) DeleteOldTelemetryLocks(ctx, periodEndingAtBefo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldTelemetryLocks", reflect.TypeOf((*MockStore)(nil).DeleteOldTelemetryLocks), ctx, periodEndingAtBefore)
 }
 
+// DeleteOldWebhookDeliveries mocks base method.
+func (m *MockStore) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWebhookDeliveries", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWebhookDeliveries indicates an expected call of DeleteOldWebhookDeliveries.
+func (mr *MockStoreMockRecorder) DeleteOldWebhookDeliveries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).DeleteOldWebhookDeliveries), ctx)
+}
+
 // DeleteOldWorkspaceAgentLogs mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1367,18 +1411,48 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogs(ctx, threshold any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentLogs), ctx, threshold)
 }
 
-// DeleteOldWorkspaceAgentStats mocks base method.
-func (m *MockStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+// DeleteOldWorkspaceAgentResourceSamples mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentResourceSamples(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentStats", ctx)
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentResourceSamples", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteOldWorkspaceAgentResourceSamples indicates an expected call of DeleteOldWorkspaceAgentResourceSamples.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentResourceSamples(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentResourceSamples", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentResourceSamples), ctx)
+}
+
+// DeleteOldWorkspaceAgentStats mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentStats", ctx, beforeTime)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteOldWorkspaceAgentStats indicates an expected call of DeleteOldWorkspaceAgentStats.
-func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(ctx any) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(ctx, beforeTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), ctx, beforeTime)
+}
+
+// DeleteOldWorkspaceClientNetworkStats mocks base method.
+func (m *MockStore) DeleteOldWorkspaceClientNetworkStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceClientNetworkStats", ctx, beforeTime)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOldWorkspaceClientNetworkStats indicates an expected call of DeleteOldWorkspaceClientNetworkStats.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceClientNetworkStats(ctx, beforeTime any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceClientNetworkStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceClientNetworkStats), ctx, beforeTime)
 }
 
 // DeleteOrganizationMember mocks base method.
@@ -1511,6 +1585,21 @@ func (mr *MockStoreMockRecorder) DeleteTask(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockStore)(nil).DeleteTask), ctx, arg)
 }
 
+// DeleteTemplateIdlePolicy mocks base method.
+func (m *MockStore) DeleteTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateIdlePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateIdlePolicy", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateIdlePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTemplateIdlePolicy indicates an expected call of DeleteTemplateIdlePolicy.
+func (mr *MockStoreMockRecorder) DeleteTemplateIdlePolicy(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateIdlePolicy", reflect.TypeOf((*MockStore)(nil).DeleteTemplateIdlePolicy), ctx, templateID)
+}
+
 // DeleteUserAIBudgetOverride mocks base method.
 func (m *MockStore) DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	m.ctrl.T.Helper()
@@ -1598,6 +1687,20 @@ func (mr *MockStoreMockRecorder) DeleteUserSkillByUserIDAndName(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSkillByUserIDAndName", reflect.TypeOf((*MockStore)(nil).DeleteUserSkillByUserIDAndName), ctx, arg)
 }
 
+// DeleteWebhookByID mocks base method.
+func (m *MockStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookByID indicates an expected call of DeleteWebhookByID.
+func (mr *MockStoreMockRecorder) DeleteWebhookByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookByID", reflect.TypeOf((*MockStore)(nil).DeleteWebhookByID), ctx, id)
+}
+
 // DeleteWebpushSubscriptionByUserIDAndEndpoint mocks base method.
 func (m *MockStore) DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg database.DeleteWebpushSubscriptionByUserIDAndEndpointParams) error {
 	m.ctrl.T.Helper()
@@ -2337,6 +2440,36 @@ func (mr *MockStoreMockRecorder) GetApplicationName(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationName", reflect.TypeOf((*MockStore)(nil).GetApplicationName), ctx)
 }
 
+// GetAuditLogExport mocks base method.
+func (m *MockStore) GetAuditLogExport(ctx context.Context, dataset string) (database.AuditLogExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogExport", ctx, dataset)
+	ret0, _ := ret[0].(database.AuditLogExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogExport indicates an expected call of GetAuditLogExport.
+func (mr *MockStoreMockRecorder) GetAuditLogExport(ctx, dataset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogExport", reflect.TypeOf((*MockStore)(nil).GetAuditLogExport), ctx, dataset)
+}
+
+// GetAuditLogsForExport mocks base method.
+func (m *MockStore) GetAuditLogsForExport(ctx context.Context, arg database.GetAuditLogsForExportParams) ([]database.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsForExport", ctx, arg)
+	ret0, _ := ret[0].([]database.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsForExport indicates an expected call of GetAuditLogsForExport.
+func (mr *MockStoreMockRecorder) GetAuditLogsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsForExport", reflect.TypeOf((*MockStore)(nil).GetAuditLogsForExport), ctx, arg)
+}
+
 // GetAuditLogsOffset mocks base method.
 func (m *MockStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	m.ctrl.T.Helper()
@@ -3582,6 +3715,21 @@ func (mr *MockStoreMockRecorder) GetDatabaseNow(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDatabaseNow", reflect.TypeOf((*MockStore)(nil).GetDatabaseNow), ctx)
 }
 
+// GetDatabasePurgeStatus mocks base method.
+func (m *MockStore) GetDatabasePurgeStatus(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDatabasePurgeStatus", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDatabasePurgeStatus indicates an expected call of GetDatabasePurgeStatus.
+func (mr *MockStoreMockRecorder) GetDatabasePurgeStatus(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDatabasePurgeStatus", reflect.TypeOf((*MockStore)(nil).GetDatabasePurgeStatus), ctx)
+}
+
 // GetDefaultChatModelConfig mocks base method.
 func (m *MockStore) GetDefaultChatModelConfig(ctx context.Context) (database.ChatModelConfig, error) {
 	m.ctrl.T.Helper()
@@ -3642,6 +3790,21 @@ func (mr *MockStoreMockRecorder) GetDeploymentID(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentID", reflect.TypeOf((*MockStore)(nil).GetDeploymentID), ctx)
 }
 
+// GetDeploymentInsights mocks base method.
+func (m *MockStore) GetDeploymentInsights(ctx context.Context, arg database.GetDeploymentInsightsParams) (database.GetDeploymentInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeploymentInsights", ctx, arg)
+	ret0, _ := ret[0].(database.GetDeploymentInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeploymentInsights indicates an expected call of GetDeploymentInsights.
+func (mr *MockStoreMockRecorder) GetDeploymentInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentInsights", reflect.TypeOf((*MockStore)(nil).GetDeploymentInsights), ctx, arg)
+}
+
 // GetDeploymentWorkspaceAgentStats mocks base method.
 func (m *MockStore) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -4017,6 +4180,21 @@ func (mr *MockStoreMockRecorder) GetGroupMembersCountByGroupIDs(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembersCountByGroupIDs", reflect.TypeOf((*MockStore)(nil).GetGroupMembersCountByGroupIDs), ctx, arg)
 }
 
+// GetGroupUsageQuotas mocks base method.
+func (m *MockStore) GetGroupUsageQuotas(ctx context.Context, groupID uuid.UUID) ([]database.GroupUsageQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupUsageQuotas", ctx, groupID)
+	ret0, _ := ret[0].([]database.GroupUsageQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupUsageQuotas indicates an expected call of GetGroupUsageQuotas.
+func (mr *MockStoreMockRecorder) GetGroupUsageQuotas(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupUsageQuotas", reflect.TypeOf((*MockStore)(nil).GetGroupUsageQuotas), ctx, groupID)
+}
+
 // GetGroups mocks base method.
 func (m *MockStore) GetGroups(ctx context.Context, arg database.GetGroupsParams) ([]database.GetGroupsRow, error) {
 	m.ctrl.T.Helper()
@@ -4062,6 +4240,21 @@ func (mr *MockStoreMockRecorder) GetHighestGroupAIBudgetByUser(ctx, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestGroupAIBudgetByUser", reflect.TypeOf((*MockStore)(nil).GetHighestGroupAIBudgetByUser), ctx, userID)
 }
 
+// GetIdleWorkspaces mocks base method.
+func (m *MockStore) GetIdleWorkspaces(ctx context.Context, arg database.GetIdleWorkspacesParams) ([]database.GetIdleWorkspacesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdleWorkspaces", ctx, arg)
+	ret0, _ := ret[0].([]database.GetIdleWorkspacesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdleWorkspaces indicates an expected call of GetIdleWorkspaces.
+func (mr *MockStoreMockRecorder) GetIdleWorkspaces(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdleWorkspaces", reflect.TypeOf((*MockStore)(nil).GetIdleWorkspaces), ctx, arg)
+}
+
 // GetInboxNotificationByID mocks base method.
 func (m *MockStore) GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (database.InboxNotification, error) {
 	m.ctrl.T.Helper()
@@ -4617,6 +4810,21 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppsByUserID(ctx, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppsByUserID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppsByUserID), ctx, userID)
 }
 
+// GetOldestWorkspaceAgentStatCreatedAt mocks base method.
+func (m *MockStore) GetOldestWorkspaceAgentStatCreatedAt(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldestWorkspaceAgentStatCreatedAt", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestWorkspaceAgentStatCreatedAt indicates an expected call of GetOldestWorkspaceAgentStatCreatedAt.
+func (mr *MockStoreMockRecorder) GetOldestWorkspaceAgentStatCreatedAt(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldestWorkspaceAgentStatCreatedAt", reflect.TypeOf((*MockStore)(nil).GetOldestWorkspaceAgentStatCreatedAt), ctx)
+}
+
 // GetOrganizationByID mocks base method.
 func (m *MockStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -4662,6 +4870,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationIDsByMemberIDs(ctx, ids any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationIDsByMemberIDs", reflect.TypeOf((*MockStore)(nil).GetOrganizationIDsByMemberIDs), ctx, ids)
 }
 
+// GetOrganizationInsights mocks base method.
+func (m *MockStore) GetOrganizationInsights(ctx context.Context, arg database.GetOrganizationInsightsParams) (database.GetOrganizationInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationInsights", ctx, arg)
+	ret0, _ := ret[0].(database.GetOrganizationInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationInsights indicates an expected call of GetOrganizationInsights.
+func (mr *MockStoreMockRecorder) GetOrganizationInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationInsights", reflect.TypeOf((*MockStore)(nil).GetOrganizationInsights), ctx, arg)
+}
+
 // GetOrganizationResourceCountByID mocks base method.
 func (m *MockStore) GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (database.GetOrganizationResourceCountByIDRow, error) {
 	m.ctrl.T.Helper()
@@ -4737,6 +4960,21 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(ctx, jobID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), ctx, jobID)
 }
 
+// GetPrebuildInsights mocks base method.
+func (m *MockStore) GetPrebuildInsights(ctx context.Context, arg database.GetPrebuildInsightsParams) ([]database.GetPrebuildInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrebuildInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetPrebuildInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrebuildInsights indicates an expected call of GetPrebuildInsights.
+func (mr *MockStoreMockRecorder) GetPrebuildInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrebuildInsights", reflect.TypeOf((*MockStore)(nil).GetPrebuildInsights), ctx, arg)
+}
+
 // GetPrebuildMetrics mocks base method.
 func (m *MockStore) GetPrebuildMetrics(ctx context.Context) ([]database.GetPrebuildMetricsRow, error) {
 	m.ctrl.T.Helper()
@@ -5217,6 +5455,36 @@ func (mr *MockStoreMockRecorder) GetRuntimeConfig(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), ctx, key)
 }
 
+// GetScaletestResultByID mocks base method.
+func (m *MockStore) GetScaletestResultByID(ctx context.Context, id uuid.UUID) (database.ScaletestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScaletestResultByID", ctx, id)
+	ret0, _ := ret[0].(database.ScaletestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScaletestResultByID indicates an expected call of GetScaletestResultByID.
+func (mr *MockStoreMockRecorder) GetScaletestResultByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScaletestResultByID", reflect.TypeOf((*MockStore)(nil).GetScaletestResultByID), ctx, id)
+}
+
+// GetScaletestResults mocks base method.
+func (m *MockStore) GetScaletestResults(ctx context.Context, arg database.GetScaletestResultsParams) ([]database.ScaletestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScaletestResults", ctx, arg)
+	ret0, _ := ret[0].([]database.ScaletestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScaletestResults indicates an expected call of GetScaletestResults.
+func (mr *MockStoreMockRecorder) GetScaletestResults(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScaletestResults", reflect.TypeOf((*MockStore)(nil).GetScaletestResults), ctx, arg)
+}
+
 // GetStaleChats mocks base method.
 func (m *MockStore) GetStaleChats(ctx context.Context, staleThreshold time.Time) ([]database.Chat, error) {
 	m.ctrl.T.Helper()
@@ -5382,6 +5650,21 @@ func (mr *MockStoreMockRecorder) GetTelemetryTaskEvents(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTelemetryTaskEvents", reflect.TypeOf((*MockStore)(nil).GetTelemetryTaskEvents), ctx, arg)
 }
 
+// GetTemplateAccessMethodInsights mocks base method.
+func (m *MockStore) GetTemplateAccessMethodInsights(ctx context.Context, arg database.GetTemplateAccessMethodInsightsParams) ([]database.GetTemplateAccessMethodInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAccessMethodInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateAccessMethodInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAccessMethodInsights indicates an expected call of GetTemplateAccessMethodInsights.
+func (mr *MockStoreMockRecorder) GetTemplateAccessMethodInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAccessMethodInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateAccessMethodInsights), ctx, arg)
+}
+
 // GetTemplateAppInsights mocks base method.
 func (m *MockStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -5457,6 +5740,21 @@ func (mr *MockStoreMockRecorder) GetTemplateByOrganizationAndName(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateByOrganizationAndName", reflect.TypeOf((*MockStore)(nil).GetTemplateByOrganizationAndName), ctx, arg)
 }
 
+// GetTemplateComparisonInsights mocks base method.
+func (m *MockStore) GetTemplateComparisonInsights(ctx context.Context, arg database.GetTemplateComparisonInsightsParams) ([]database.GetTemplateComparisonInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateComparisonInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateComparisonInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateComparisonInsights indicates an expected call of GetTemplateComparisonInsights.
+func (mr *MockStoreMockRecorder) GetTemplateComparisonInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateComparisonInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateComparisonInsights), ctx, arg)
+}
+
 // GetTemplateGroupRoles mocks base method.
 func (m *MockStore) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateGroup, error) {
 	m.ctrl.T.Helper()
@@ -5472,6 +5770,21 @@ func (mr *MockStoreMockRecorder) GetTemplateGroupRoles(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateGroupRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateGroupRoles), ctx, id)
 }
 
+// GetTemplateIdlePolicy mocks base method.
+func (m *MockStore) GetTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (database.TemplateIdlePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateIdlePolicy", ctx, templateID)
+	ret0, _ := ret[0].(database.TemplateIdlePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateIdlePolicy indicates an expected call of GetTemplateIdlePolicy.
+func (mr *MockStoreMockRecorder) GetTemplateIdlePolicy(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateIdlePolicy", reflect.TypeOf((*MockStore)(nil).GetTemplateIdlePolicy), ctx, templateID)
+}
+
 // GetTemplateInsights mocks base method.
 func (m *MockStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -5562,6 +5875,21 @@ func (mr *MockStoreMockRecorder) GetTemplateRankingSignalsByOwnerID(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateRankingSignalsByOwnerID", reflect.TypeOf((*MockStore)(nil).GetTemplateRankingSignalsByOwnerID), ctx, arg)
 }
 
+// GetTemplateUsageHeatmap mocks base method.
+func (m *MockStore) GetTemplateUsageHeatmap(ctx context.Context, arg database.GetTemplateUsageHeatmapParams) ([]database.GetTemplateUsageHeatmapRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateUsageHeatmap", ctx, arg)
+	ret0, _ := ret[0].([]database.GetTemplateUsageHeatmapRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateUsageHeatmap indicates an expected call of GetTemplateUsageHeatmap.
+func (mr *MockStoreMockRecorder) GetTemplateUsageHeatmap(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUsageHeatmap", reflect.TypeOf((*MockStore)(nil).GetTemplateUsageHeatmap), ctx, arg)
+}
+
 // GetTemplateUsageStats mocks base method.
 func (m *MockStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	m.ctrl.T.Helper()
@@ -5577,6 +5905,21 @@ func (mr *MockStoreMockRecorder) GetTemplateUsageStats(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).GetTemplateUsageStats), ctx, arg)
 }
 
+// GetTemplateUsageStatsForExport mocks base method.
+func (m *MockStore) GetTemplateUsageStatsForExport(ctx context.Context, arg database.GetTemplateUsageStatsForExportParams) ([]database.TemplateUsageStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateUsageStatsForExport", ctx, arg)
+	ret0, _ := ret[0].([]database.TemplateUsageStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateUsageStatsForExport indicates an expected call of GetTemplateUsageStatsForExport.
+func (mr *MockStoreMockRecorder) GetTemplateUsageStatsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUsageStatsForExport", reflect.TypeOf((*MockStore)(nil).GetTemplateUsageStatsForExport), ctx, arg)
+}
+
 // GetTemplateUserRoles mocks base method.
 func (m *MockStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
 	m.ctrl.T.Helper()
@@ -5802,6 +6145,51 @@ func (mr *MockStoreMockRecorder) GetUnexpiredLicenses(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnexpiredLicenses", reflect.TypeOf((*MockStore)(nil).GetUnexpiredLicenses), ctx)
 }
 
+// GetUnusedWorkspaces mocks base method.
+func (m *MockStore) GetUnusedWorkspaces(ctx context.Context, unusedSince time.Time) ([]database.GetUnusedWorkspacesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnusedWorkspaces", ctx, unusedSince)
+	ret0, _ := ret[0].([]database.GetUnusedWorkspacesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnusedWorkspaces indicates an expected call of GetUnusedWorkspaces.
+func (mr *MockStoreMockRecorder) GetUnusedWorkspaces(ctx, unusedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedWorkspaces", reflect.TypeOf((*MockStore)(nil).GetUnusedWorkspaces), ctx, unusedSince)
+}
+
+// GetUsageQuotaAllowancesForUser mocks base method.
+func (m *MockStore) GetUsageQuotaAllowancesForUser(ctx context.Context, arg database.GetUsageQuotaAllowancesForUserParams) ([]database.GetUsageQuotaAllowancesForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsageQuotaAllowancesForUser", ctx, arg)
+	ret0, _ := ret[0].([]database.GetUsageQuotaAllowancesForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsageQuotaAllowancesForUser indicates an expected call of GetUsageQuotaAllowancesForUser.
+func (mr *MockStoreMockRecorder) GetUsageQuotaAllowancesForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsageQuotaAllowancesForUser", reflect.TypeOf((*MockStore)(nil).GetUsageQuotaAllowancesForUser), ctx, arg)
+}
+
+// GetUsageQuotaConsumedForUser mocks base method.
+func (m *MockStore) GetUsageQuotaConsumedForUser(ctx context.Context, arg database.GetUsageQuotaConsumedForUserParams) (database.GetUsageQuotaConsumedForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsageQuotaConsumedForUser", ctx, arg)
+	ret0, _ := ret[0].(database.GetUsageQuotaConsumedForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsageQuotaConsumedForUser indicates an expected call of GetUsageQuotaConsumedForUser.
+func (mr *MockStoreMockRecorder) GetUsageQuotaConsumedForUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsageQuotaConsumedForUser", reflect.TypeOf((*MockStore)(nil).GetUsageQuotaConsumedForUser), ctx, arg)
+}
+
 // GetUserAIBudgetOverride mocks base method.
 func (m *MockStore) GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (database.UserAIBudgetOverride, error) {
 	m.ctrl.T.Helper()
@@ -5922,6 +6310,21 @@ func (mr *MockStoreMockRecorder) GetUserAppearanceSettings(ctx, userID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAppearanceSettings", reflect.TypeOf((*MockStore)(nil).GetUserAppearanceSettings), ctx, userID)
 }
 
+// GetUserBandwidthInsights mocks base method.
+func (m *MockStore) GetUserBandwidthInsights(ctx context.Context, arg database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBandwidthInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetUserBandwidthInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBandwidthInsights indicates an expected call of GetUserBandwidthInsights.
+func (mr *MockStoreMockRecorder) GetUserBandwidthInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBandwidthInsights", reflect.TypeOf((*MockStore)(nil).GetUserBandwidthInsights), ctx, arg)
+}
+
 // GetUserByEmailOrUsername mocks base method.
 func (m *MockStore) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -6057,6 +6460,21 @@ func (mr *MockStoreMockRecorder) GetUserCount(ctx, includeSystem any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCount", reflect.TypeOf((*MockStore)(nil).GetUserCount), ctx, includeSystem)
 }
 
+// GetUserCountWithUsageSince mocks base method.
+func (m *MockStore) GetUserCountWithUsageSince(ctx context.Context, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCountWithUsageSince", ctx, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCountWithUsageSince indicates an expected call of GetUserCountWithUsageSince.
+func (mr *MockStoreMockRecorder) GetUserCountWithUsageSince(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCountWithUsageSince", reflect.TypeOf((*MockStore)(nil).GetUserCountWithUsageSince), ctx, since)
+}
+
 // GetUserGroupSpendLimit mocks base method.
 func (m *MockStore) GetUserGroupSpendLimit(ctx context.Context, arg database.GetUserGroupSpendLimitParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -6312,6 +6730,51 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), ctx, ids)
 }
 
+// GetWebhookByID mocks base method.
+func (m *MockStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", ctx, id)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockStoreMockRecorder) GetWebhookByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockStore)(nil).GetWebhookByID), ctx, id)
+}
+
+// GetWebhookDeliveriesByWebhookID mocks base method.
+func (m *MockStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesByWebhookID", ctx, arg)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesByWebhookID indicates an expected call of GetWebhookDeliveriesByWebhookID.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveriesByWebhookID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesByWebhookID", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveriesByWebhookID), ctx, arg)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStoreMockRecorder) GetWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStore)(nil).GetWebhooks), ctx)
+}
+
 // GetWebpushSubscriptionsByUserID mocks base method.
 func (m *MockStore) GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -6357,6 +6820,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceACLByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceACLByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceACLByID), ctx, id)
 }
 
+// GetWorkspaceActivityStats mocks base method.
+func (m *MockStore) GetWorkspaceActivityStats(ctx context.Context, arg database.GetWorkspaceActivityStatsParams) ([]database.GetWorkspaceActivityStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceActivityStats", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceActivityStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceActivityStats indicates an expected call of GetWorkspaceActivityStats.
+func (mr *MockStoreMockRecorder) GetWorkspaceActivityStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceActivityStats", reflect.TypeOf((*MockStore)(nil).GetWorkspaceActivityStats), ctx, arg)
+}
+
 // GetWorkspaceAgentAndWorkspaceByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentAndWorkspaceByIDRow, error) {
 	m.ctrl.T.Helper()
@@ -6387,6 +6865,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentByID), ctx, id)
 }
 
+// GetWorkspaceAgentConnectionLatencyByClientRegion mocks base method.
+func (m *MockStore) GetWorkspaceAgentConnectionLatencyByClientRegion(ctx context.Context, arg database.GetWorkspaceAgentConnectionLatencyByClientRegionParams) ([]database.GetWorkspaceAgentConnectionLatencyByClientRegionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentConnectionLatencyByClientRegion", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentConnectionLatencyByClientRegionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentConnectionLatencyByClientRegion indicates an expected call of GetWorkspaceAgentConnectionLatencyByClientRegion.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentConnectionLatencyByClientRegion(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentConnectionLatencyByClientRegion", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentConnectionLatencyByClientRegion), ctx, arg)
+}
+
+// GetWorkspaceAgentConnectionLatencyByRegion mocks base method.
+func (m *MockStore) GetWorkspaceAgentConnectionLatencyByRegion(ctx context.Context, arg database.GetWorkspaceAgentConnectionLatencyByRegionParams) ([]database.GetWorkspaceAgentConnectionLatencyByRegionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentConnectionLatencyByRegion", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentConnectionLatencyByRegionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentConnectionLatencyByRegion indicates an expected call of GetWorkspaceAgentConnectionLatencyByRegion.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentConnectionLatencyByRegion(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentConnectionLatencyByRegion", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentConnectionLatencyByRegion), ctx, arg)
+}
+
 // GetWorkspaceAgentDevcontainersByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentDevcontainer, error) {
 	m.ctrl.T.Helper()
@@ -6402,6 +6910,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentDevcontainersByAgentID(ctx, wo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentDevcontainersByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentDevcontainersByAgentID), ctx, workspaceAgentID)
 }
 
+// GetWorkspaceAgentIDsWithConnectionLatency mocks base method.
+func (m *MockStore) GetWorkspaceAgentIDsWithConnectionLatency(ctx context.Context, arg database.GetWorkspaceAgentIDsWithConnectionLatencyParams) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentIDsWithConnectionLatency", ctx, arg)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentIDsWithConnectionLatency indicates an expected call of GetWorkspaceAgentIDsWithConnectionLatency.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentIDsWithConnectionLatency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentIDsWithConnectionLatency", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentIDsWithConnectionLatency), ctx, arg)
+}
+
 // GetWorkspaceAgentLifecycleStateByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	m.ctrl.T.Helper()
@@ -6477,6 +7000,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortShare(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortShare), ctx, arg)
 }
 
+// GetWorkspaceAgentResourceUsage mocks base method.
+func (m *MockStore) GetWorkspaceAgentResourceUsage(ctx context.Context, arg database.GetWorkspaceAgentResourceUsageParams) ([]database.GetWorkspaceAgentResourceUsageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentResourceUsage", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentResourceUsageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentResourceUsage indicates an expected call of GetWorkspaceAgentResourceUsage.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentResourceUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentResourceUsage", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentResourceUsage), ctx, arg)
+}
+
 // GetWorkspaceAgentScriptTimingsByBuildID mocks base method.
 func (m *MockStore) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]database.GetWorkspaceAgentScriptTimingsByBuildIDRow, error) {
 	m.ctrl.T.Helper()
@@ -6537,6 +7075,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentStatsAndLabels(ctx, createdAt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentStatsAndLabels", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentStatsAndLabels), ctx, createdAt)
 }
 
+// GetWorkspaceAgentStatsTimeSeries mocks base method.
+func (m *MockStore) GetWorkspaceAgentStatsTimeSeries(ctx context.Context, arg database.GetWorkspaceAgentStatsTimeSeriesParams) ([]database.GetWorkspaceAgentStatsTimeSeriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentStatsTimeSeries", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentStatsTimeSeriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentStatsTimeSeries indicates an expected call of GetWorkspaceAgentStatsTimeSeries.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentStatsTimeSeries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentStatsTimeSeries", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentStatsTimeSeries), ctx, arg)
+}
+
 // GetWorkspaceAgentUsageStats mocks base method.
 func (m *MockStore) GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentUsageStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -6972,6 +7525,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(ctx, workspaceAppI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), ctx, workspaceAppID)
 }
 
+// GetWorkspaceCost mocks base method.
+func (m *MockStore) GetWorkspaceCost(ctx context.Context, arg database.GetWorkspaceCostParams) (database.GetWorkspaceCostRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCost", ctx, arg)
+	ret0, _ := ret[0].(database.GetWorkspaceCostRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCost indicates an expected call of GetWorkspaceCost.
+func (mr *MockStoreMockRecorder) GetWorkspaceCost(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCost", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCost), ctx, arg)
+}
+
+// GetWorkspaceCostInsights mocks base method.
+func (m *MockStore) GetWorkspaceCostInsights(ctx context.Context, arg database.GetWorkspaceCostInsightsParams) ([]database.GetWorkspaceCostInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCostInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceCostInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCostInsights indicates an expected call of GetWorkspaceCostInsights.
+func (mr *MockStoreMockRecorder) GetWorkspaceCostInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCostInsights", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCostInsights), ctx, arg)
+}
+
+// GetWorkspaceHealthInsights mocks base method.
+func (m *MockStore) GetWorkspaceHealthInsights(ctx context.Context, arg database.GetWorkspaceHealthInsightsParams) ([]database.GetWorkspaceHealthInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceHealthInsights", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceHealthInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceHealthInsights indicates an expected call of GetWorkspaceHealthInsights.
+func (mr *MockStoreMockRecorder) GetWorkspaceHealthInsights(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceHealthInsights", reflect.TypeOf((*MockStore)(nil).GetWorkspaceHealthInsights), ctx, arg)
+}
+
 // GetWorkspaceModulesByJobID mocks base method.
 func (m *MockStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -7152,6 +7750,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(ctx, createdA
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceStatBaselines mocks base method.
+func (m *MockStore) GetWorkspaceStatBaselines(ctx context.Context, workspaceIds []uuid.UUID) ([]database.WorkspaceStatBaseline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceStatBaselines", ctx, workspaceIds)
+	ret0, _ := ret[0].([]database.WorkspaceStatBaseline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceStatBaselines indicates an expected call of GetWorkspaceStatBaselines.
+func (mr *MockStoreMockRecorder) GetWorkspaceStatBaselines(ctx, workspaceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceStatBaselines", reflect.TypeOf((*MockStore)(nil).GetWorkspaceStatBaselines), ctx, workspaceIds)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -8045,6 +8658,21 @@ func (mr *MockStoreMockRecorder) InsertReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), ctx, arg)
 }
 
+// InsertScaletestResult mocks base method.
+func (m *MockStore) InsertScaletestResult(ctx context.Context, arg database.InsertScaletestResultParams) (database.ScaletestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertScaletestResult", ctx, arg)
+	ret0, _ := ret[0].(database.ScaletestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertScaletestResult indicates an expected call of InsertScaletestResult.
+func (mr *MockStoreMockRecorder) InsertScaletestResult(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertScaletestResult", reflect.TypeOf((*MockStore)(nil).InsertScaletestResult), ctx, arg)
+}
+
 // InsertTask mocks base method.
 func (m *MockStore) InsertTask(ctx context.Context, arg database.InsertTaskParams) (database.TaskTable, error) {
 	m.ctrl.T.Helper()
@@ -8264,6 +8892,36 @@ func (mr *MockStoreMockRecorder) InsertVolumeResourceMonitor(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVolumeResourceMonitor", reflect.TypeOf((*MockStore)(nil).InsertVolumeResourceMonitor), ctx, arg)
 }
 
+// InsertWebhook mocks base method.
+func (m *MockStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockStoreMockRecorder) InsertWebhook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockStore)(nil).InsertWebhook), ctx, arg)
+}
+
+// InsertWebhookDeliveries mocks base method.
+func (m *MockStore) InsertWebhookDeliveries(ctx context.Context, arg database.InsertWebhookDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookDeliveries indicates an expected call of InsertWebhookDeliveries.
+func (mr *MockStoreMockRecorder) InsertWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).InsertWebhookDeliveries), ctx, arg)
+}
+
 // InsertWebpushSubscription mocks base method.
 func (m *MockStore) InsertWebpushSubscription(ctx context.Context, arg database.InsertWebpushSubscriptionParams) (database.WebpushSubscription, error) {
 	m.ctrl.T.Helper()
@@ -8368,6 +9026,20 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), ctx, arg)
 }
 
+// InsertWorkspaceAgentResourceSamples mocks base method.
+func (m *MockStore) InsertWorkspaceAgentResourceSamples(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentResourceSamples", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceAgentResourceSamples indicates an expected call of InsertWorkspaceAgentResourceSamples.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentResourceSamples(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentResourceSamples", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentResourceSamples), ctx)
+}
+
 // InsertWorkspaceAgentScriptTimings mocks base method.
 func (m *MockStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	m.ctrl.T.Helper()
@@ -8469,6 +9141,20 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), ctx, arg)
 }
 
+// InsertWorkspaceClientNetworkStat mocks base method.
+func (m *MockStore) InsertWorkspaceClientNetworkStat(ctx context.Context, arg database.InsertWorkspaceClientNetworkStatParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceClientNetworkStat", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceClientNetworkStat indicates an expected call of InsertWorkspaceClientNetworkStat.
+func (mr *MockStoreMockRecorder) InsertWorkspaceClientNetworkStat(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceClientNetworkStat", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceClientNetworkStat), ctx, arg)
+}
+
 // InsertWorkspaceModule mocks base method.
 func (m *MockStore) InsertWorkspaceModule(ctx context.Context, arg database.InsertWorkspaceModuleParams) (database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -9919,6 +10605,21 @@ func (mr *MockStoreMockRecorder) UpdateEncryptedUserAIProviderKey(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedUserAIProviderKey", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedUserAIProviderKey), ctx, arg)
 }
 
+// UpdateEncryptedWebhookSecret mocks base method.
+func (m *MockStore) UpdateEncryptedWebhookSecret(ctx context.Context, arg database.UpdateEncryptedWebhookSecretParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEncryptedWebhookSecret", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEncryptedWebhookSecret indicates an expected call of UpdateEncryptedWebhookSecret.
+func (mr *MockStoreMockRecorder) UpdateEncryptedWebhookSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEncryptedWebhookSecret", reflect.TypeOf((*MockStore)(nil).UpdateEncryptedWebhookSecret), ctx, arg)
+}
+
 // UpdateExternalAuthLink mocks base method.
 func (m *MockStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -10940,6 +11641,20 @@ func (mr *MockStoreMockRecorder) UpdateVolumeResourceMonitor(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolumeResourceMonitor", reflect.TypeOf((*MockStore)(nil).UpdateVolumeResourceMonitor), ctx, arg)
 }
 
+// UpdateWebhookDeliveryByID mocks base method.
+func (m *MockStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDeliveryByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDeliveryByID indicates an expected call of UpdateWebhookDeliveryByID.
+func (mr *MockStoreMockRecorder) UpdateWebhookDeliveryByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDeliveryByID", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDeliveryByID), ctx, arg)
+}
+
 // UpdateWorkspace mocks base method.
 func (m *MockStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.WorkspaceTable, error) {
 	m.ctrl.T.Helper()
@@ -11351,6 +12066,20 @@ func (mr *MockStoreMockRecorder) UpsertApplicationName(ctx, value any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertApplicationName", reflect.TypeOf((*MockStore)(nil).UpsertApplicationName), ctx, value)
 }
 
+// UpsertAuditLogExport mocks base method.
+func (m *MockStore) UpsertAuditLogExport(ctx context.Context, arg database.UpsertAuditLogExportParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAuditLogExport", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertAuditLogExport indicates an expected call of UpsertAuditLogExport.
+func (mr *MockStoreMockRecorder) UpsertAuditLogExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAuditLogExport", reflect.TypeOf((*MockStore)(nil).UpsertAuditLogExport), ctx, arg)
+}
+
 // UpsertBoundaryUsageStats mocks base method.
 func (m *MockStore) UpsertBoundaryUsageStats(ctx context.Context, arg database.UpsertBoundaryUsageStatsParams) (bool, error) {
 	m.ctrl.T.Helper()
@@ -11679,6 +12408,20 @@ func (mr *MockStoreMockRecorder) UpsertChatWorkspaceTTL(ctx, workspaceTtl any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertChatWorkspaceTTL", reflect.TypeOf((*MockStore)(nil).UpsertChatWorkspaceTTL), ctx, workspaceTtl)
 }
 
+// UpsertDatabasePurgeStatus mocks base method.
+func (m *MockStore) UpsertDatabasePurgeStatus(ctx context.Context, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDatabasePurgeStatus", ctx, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertDatabasePurgeStatus indicates an expected call of UpsertDatabasePurgeStatus.
+func (mr *MockStoreMockRecorder) UpsertDatabasePurgeStatus(ctx, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDatabasePurgeStatus", reflect.TypeOf((*MockStore)(nil).UpsertDatabasePurgeStatus), ctx, value)
+}
+
 // UpsertDefaultProxy mocks base method.
 func (m *MockStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	m.ctrl.T.Helper()
@@ -11708,6 +12451,21 @@ func (mr *MockStoreMockRecorder) UpsertGroupAIBudget(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertGroupAIBudget", reflect.TypeOf((*MockStore)(nil).UpsertGroupAIBudget), ctx, arg)
 }
 
+// UpsertGroupUsageQuota mocks base method.
+func (m *MockStore) UpsertGroupUsageQuota(ctx context.Context, arg database.UpsertGroupUsageQuotaParams) (database.GroupUsageQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertGroupUsageQuota", ctx, arg)
+	ret0, _ := ret[0].(database.GroupUsageQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertGroupUsageQuota indicates an expected call of UpsertGroupUsageQuota.
+func (mr *MockStoreMockRecorder) UpsertGroupUsageQuota(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertGroupUsageQuota", reflect.TypeOf((*MockStore)(nil).UpsertGroupUsageQuota), ctx, arg)
+}
+
 // UpsertHealthSettings mocks base method.
 func (m *MockStore) UpsertHealthSettings(ctx context.Context, value string) error {
 	m.ctrl.T.Helper()
@@ -11938,6 +12696,21 @@ func (mr *MockStoreMockRecorder) UpsertTelemetryItem(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTelemetryItem", reflect.TypeOf((*MockStore)(nil).UpsertTelemetryItem), ctx, arg)
 }
 
+// UpsertTemplateIdlePolicy mocks base method.
+func (m *MockStore) UpsertTemplateIdlePolicy(ctx context.Context, arg database.UpsertTemplateIdlePolicyParams) (database.TemplateIdlePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateIdlePolicy", ctx, arg)
+	ret0, _ := ret[0].(database.TemplateIdlePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateIdlePolicy indicates an expected call of UpsertTemplateIdlePolicy.
+func (mr *MockStoreMockRecorder) UpsertTemplateIdlePolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateIdlePolicy", reflect.TypeOf((*MockStore)(nil).UpsertTemplateIdlePolicy), ctx, arg)
+}
+
 // UpsertTemplateUsageStats mocks base method.
 func (m *MockStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -12099,6 +12872,62 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAppAuditSession(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAppAuditSession", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAppAuditSession), ctx, arg)
 }
 
+// UpsertWorkspaceCosts mocks base method.
+func (m *MockStore) UpsertWorkspaceCosts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceCosts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceCosts indicates an expected call of UpsertWorkspaceCosts.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceCosts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceCosts", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceCosts), ctx)
+}
+
+// UpsertWorkspaceIdleFlag mocks base method.
+func (m *MockStore) UpsertWorkspaceIdleFlag(ctx context.Context, arg database.UpsertWorkspaceIdleFlagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceIdleFlag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceIdleFlag indicates an expected call of UpsertWorkspaceIdleFlag.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceIdleFlag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceIdleFlag", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceIdleFlag), ctx, arg)
+}
+
+// UpsertWorkspaceStatBaseline mocks base method.
+func (m *MockStore) UpsertWorkspaceStatBaseline(ctx context.Context, arg database.UpsertWorkspaceStatBaselineParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceStatBaseline", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceStatBaseline indicates an expected call of UpsertWorkspaceStatBaseline.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceStatBaseline(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceStatBaseline", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceStatBaseline), ctx, arg)
+}
+
+// UpsertWorkspaceUnusedNotification mocks base method.
+func (m *MockStore) UpsertWorkspaceUnusedNotification(ctx context.Context, arg database.UpsertWorkspaceUnusedNotificationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceUnusedNotification", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceUnusedNotification indicates an expected call of UpsertWorkspaceUnusedNotification.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceUnusedNotification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceUnusedNotification", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceUnusedNotification), ctx, arg)
+}
+
 // UsageEventExistsByID mocks base method.
 func (m *MockStore) UsageEventExistsByID(ctx context.Context, id string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -2,6 +2,7 @@ package dbpurge
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"time"
@@ -82,14 +83,23 @@ func New(ctx context.Context, logger slog.Logger, db database.Store, vals *coder
 	}, []string{"record_type"})
 	reg.MustRegister(recordsPurged)
 
+	workspaceAgentStatsBacklog := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "dbpurge",
+		Name:      "workspace_agent_stats_backlog_seconds",
+		Help:      "How far the oldest raw workspace agent stat is past its retention period, in seconds.",
+	})
+	reg.MustRegister(workspaceAgentStatsBacklog)
+
 	inst := &instance{
-		cancel:            cancelFunc,
-		closed:            closed,
-		logger:            logger,
-		vals:              vals,
-		clk:               quartz.NewReal(),
-		iterationDuration: iterationDuration,
-		recordsPurged:     recordsPurged,
+		cancel:                     cancelFunc,
+		closed:                     closed,
+		logger:                     logger,
+		vals:                       vals,
+		clk:                        quartz.NewReal(),
+		iterationDuration:          iterationDuration,
+		recordsPurged:              recordsPurged,
+		workspaceAgentStatsBacklog: workspaceAgentStatsBacklog,
 	}
 	for _, opt := range opts {
 		opt(inst)
@@ -151,6 +161,14 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 
 	chatConfigErr := errors.Join(chatRetentionErr, chatDebugRetentionErr)
 
+	var (
+		// ran is false when another replica holds the purge lock, in
+		// which case that replica records the status instead.
+		ran           bool
+		recordsPurged map[string]int64
+		statsBacklog  time.Duration
+	)
+
 	// Start a transaction to grab advisory lock, we don't want to run
 	// multiple purges at the same time (multiple replicas).
 	err := db.InTx(func(tx database.Store) error {
@@ -164,6 +182,7 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 			i.logger.Debug(ctx, "unable to acquire lock for purging old database entries, skipping")
 			return nil
 		}
+		ran = true
 
 		var purgedWorkspaceAgentLogs int64
 		workspaceAgentLogsRetention := i.vals.Retention.WorkspaceAgentLogs.Value()
@@ -174,8 +193,27 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 				return xerrors.Errorf("failed to delete old workspace agent logs: %w", err)
 			}
 		}
-		if err := tx.DeleteOldWorkspaceAgentStats(ctx); err != nil {
-			return xerrors.Errorf("failed to delete old workspace agent stats: %w", err)
+		var purgedWorkspaceAgentStats, purgedWorkspaceClientNetworkStats int64
+		workspaceAgentStatsRetention := i.vals.Retention.WorkspaceAgentStats.Value()
+		if workspaceAgentStatsRetention > 0 {
+			deleteWorkspaceAgentStatsBefore := start.Add(-workspaceAgentStatsRetention)
+			purgedWorkspaceAgentStats, err = tx.DeleteOldWorkspaceAgentStats(ctx, deleteWorkspaceAgentStatsBefore)
+			if err != nil {
+				return xerrors.Errorf("failed to delete old workspace agent stats: %w", err)
+			}
+			// Stats are deleted in batches, so report how much is left
+			// to purge.
+			oldest, err := tx.GetOldestWorkspaceAgentStatCreatedAt(ctx)
+			if err != nil {
+				return xerrors.Errorf("failed to get oldest workspace agent stat: %w", err)
+			}
+			statsBacklog = max(deleteWorkspaceAgentStatsBefore.Sub(oldest), 0)
+			// Client network stats are only used to attribute agent stats,
+			// so they are retained for as long.
+			purgedWorkspaceClientNetworkStats, err = tx.DeleteOldWorkspaceClientNetworkStats(ctx, deleteWorkspaceAgentStatsBefore)
+			if err != nil {
+				return xerrors.Errorf("failed to delete old workspace client network stats: %w", err)
+			}
 		}
 		if err := tx.DeleteOldProvisionerDaemons(ctx); err != nil {
 			return xerrors.Errorf("failed to delete old provisioner daemons: %w", err)
@@ -183,6 +221,12 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 		if err := tx.DeleteOldNotificationMessages(ctx); err != nil {
 			return xerrors.Errorf("failed to delete old notification messages: %w", err)
 		}
+		if err := tx.DeleteOldWebhookDeliveries(ctx); err != nil {
+			return xerrors.Errorf("failed to delete old webhook deliveries: %w", err)
+		}
+		if err := tx.DeleteOldWorkspaceAgentResourceSamples(ctx); err != nil {
+			return xerrors.Errorf("failed to delete old workspace agent resource samples: %w", err)
+		}
 		if err := tx.ExpirePrebuildsAPIKeys(ctx, dbtime.Time(start)); err != nil {
 			return xerrors.Errorf("failed to expire prebuilds user api keys: %w", err)
 		}
@@ -298,6 +342,8 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 
 		i.logger.Debug(ctx, "purged old database entries",
 			slog.F("workspace_agent_logs", purgedWorkspaceAgentLogs),
+			slog.F("workspace_agent_stats", purgedWorkspaceAgentStats),
+			slog.F("workspace_client_network_stats", purgedWorkspaceClientNetworkStats),
 			slog.F("expired_api_keys", expiredAPIKeys),
 			slog.F("aibridge_records", purgedAIBridgeRecords),
 			slog.F("connection_logs", purgedConnectionLogs),
@@ -310,17 +356,27 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 			slog.F("duration", i.clk.Since(start)),
 		)
 
+		recordsPurged = map[string]int64{
+			"workspace_agent_logs":           purgedWorkspaceAgentLogs,
+			"workspace_agent_stats":          purgedWorkspaceAgentStats,
+			"workspace_client_network_stats": purgedWorkspaceClientNetworkStats,
+			"expired_api_keys":               expiredAPIKeys,
+			"aibridge_records":               purgedAIBridgeRecords,
+			"connection_logs":                purgedConnectionLogs,
+			"audit_logs":                     purgedAuditLogs,
+			"boundary_logs":                  purgedBoundaryLogs,
+			"boundary_sessions":              purgedBoundarySessions,
+			"chats":                          purgedChats,
+			"chat_debug_runs":                purgedChatDebugRuns,
+			"chat_files":                     purgedChatFiles,
+		}
 		if i.recordsPurged != nil {
-			i.recordsPurged.WithLabelValues("workspace_agent_logs").Add(float64(purgedWorkspaceAgentLogs))
-			i.recordsPurged.WithLabelValues("expired_api_keys").Add(float64(expiredAPIKeys))
-			i.recordsPurged.WithLabelValues("aibridge_records").Add(float64(purgedAIBridgeRecords))
-			i.recordsPurged.WithLabelValues("connection_logs").Add(float64(purgedConnectionLogs))
-			i.recordsPurged.WithLabelValues("audit_logs").Add(float64(purgedAuditLogs))
-			i.recordsPurged.WithLabelValues("boundary_logs").Add(float64(purgedBoundaryLogs))
-			i.recordsPurged.WithLabelValues("boundary_sessions").Add(float64(purgedBoundarySessions))
-			i.recordsPurged.WithLabelValues("chats").Add(float64(purgedChats))
-			i.recordsPurged.WithLabelValues("chat_debug_runs").Add(float64(purgedChatDebugRuns))
-			i.recordsPurged.WithLabelValues("chat_files").Add(float64(purgedChatFiles))
+			for recordType, purged := range recordsPurged {
+				i.recordsPurged.WithLabelValues(recordType).Add(float64(purged))
+			}
+		}
+		if i.workspaceAgentStatsBacklog != nil {
+			i.workspaceAgentStatsBacklog.Set(statsBacklog.Seconds())
 		}
 
 		// chatConfigErr is returned after the tx, so do not record this
@@ -332,6 +388,13 @@ func (i *instance) purgeTick(ctx context.Context, db database.Store, start time.
 
 		return nil
 	}, database.DefaultTXOptions().WithID("db_purge"))
+	if err != nil {
+		// Records deleted by a failed iteration were rolled back.
+		recordsPurged = nil
+	}
+	if ran || err != nil {
+		i.recordStatus(ctx, db, start, recordsPurged, statsBacklog, errors.Join(err, chatConfigErr))
+	}
 	if err != nil {
 		return err
 	}
@@ -353,6 +416,35 @@ type instance struct {
 	clk               quartz.Clock
 	iterationDuration *prometheus.HistogramVec
 	recordsPurged     *prometheus.CounterVec
+	// workspaceAgentStatsBacklog tracks the progress of the batched
+	// workspace agent stats purge.
+	workspaceAgentStatsBacklog prometheus.Gauge
+}
+
+// recordStatus stores the outcome of a purge iteration so that operators can
+// inspect it through the API. Failing to store it does not fail the purge.
+func (i *instance) recordStatus(ctx context.Context, db database.Store, start time.Time, recordsPurged map[string]int64, statsBacklog time.Duration, purgeErr error) {
+	status := codersdk.DatabasePurgeStatus{
+		StartedAt:                        start,
+		FinishedAt:                       start.Add(i.clk.Since(start)),
+		Success:                          purgeErr == nil,
+		RecordsPurged:                    recordsPurged,
+		WorkspaceAgentStatsBacklogMillis: statsBacklog.Milliseconds(),
+	}
+	if purgeErr != nil {
+		status.Error = purgeErr.Error()
+	}
+	if status.RecordsPurged == nil {
+		status.RecordsPurged = map[string]int64{}
+	}
+	raw, err := json.Marshal(status)
+	if err != nil {
+		i.logger.Error(ctx, "failed to marshal database purge status", slog.Error(err))
+		return
+	}
+	if err := db.UpsertDatabasePurgeStatus(ctx, string(raw)); err != nil {
+		i.logger.Error(ctx, "failed to store database purge status", slog.Error(err))
+	}
 }
 
 func (i *instance) Close() error {
@@ -200,6 +200,12 @@ func TestMetrics(t *testing.T) {
 		mDB.EXPECT().InTx(gomock.Any(), database.DefaultTXOptions().WithID("db_purge")).
 			Return(xerrors.New("simulated database error")).
 			MinTimes(1)
+		var status codersdk.DatabasePurgeStatus
+		mDB.EXPECT().UpsertDatabasePurgeStatus(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, value string) error {
+				return json.Unmarshal([]byte(value), &status)
+			}).
+			MinTimes(1)
 
 		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
 
@@ -218,6 +224,10 @@ func TestMetrics(t *testing.T) {
 			"success": "true",
 		})
 		require.Nil(t, successHist, "should not have success=true metric on failure")
+
+		require.False(t, status.Success)
+		require.Contains(t, status.Error, "simulated database error")
+		require.Empty(t, status.RecordsPurged)
 	})
 
 	// A failed retention read must not block unrelated or chat debug
@@ -242,9 +252,12 @@ func TestMetrics(t *testing.T) {
 		mDB.EXPECT().GetChatDebugRetentionDays(gomock.Any(), codersdk.DefaultChatDebugRetentionDays).
 			Return(int32(7), nil).AnyTimes()
 		mDB.EXPECT().TryAcquireLock(gomock.Any(), int64(database.LockIDDBPurge)).Return(true, nil).AnyTimes()
-		mDB.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+		mDB.EXPECT().UpsertDatabasePurgeStatus(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldProvisionerDaemons(gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldNotificationMessages(gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteOldWebhookDeliveries(gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteOldWorkspaceAgentResourceSamples(gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().ExpirePrebuildsAPIKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldTelemetryLocks(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldAuditLogConnectionEvents(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
@@ -292,9 +305,12 @@ func TestMetrics(t *testing.T) {
 			Return(int32(0), xerrors.New("simulated chat debug retention read error")).
 			MinTimes(1)
 		mDB.EXPECT().TryAcquireLock(gomock.Any(), int64(database.LockIDDBPurge)).Return(true, nil).AnyTimes()
-		mDB.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteOldWorkspaceAgentStats(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+		mDB.EXPECT().UpsertDatabasePurgeStatus(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldProvisionerDaemons(gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldNotificationMessages(gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteOldWebhookDeliveries(gomock.Any()).Return(nil).AnyTimes()
+		mDB.EXPECT().DeleteOldWorkspaceAgentResourceSamples(gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().ExpirePrebuildsAPIKeys(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldTelemetryLocks(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mDB.EXPECT().DeleteOldAuditLogConnectionEvents(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
@@ -399,7 +415,12 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 	})
 
 	// when
-	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, prometheus.NewRegistry(), dbpurge.WithClock(clk))
+	vals := &codersdk.DeploymentValues{
+		Retention: codersdk.RetentionConfig{
+			WorkspaceAgentStats: serpent.Duration(24 * time.Hour),
+		},
+	}
+	closer := dbpurge.New(ctx, logger, db, vals, prometheus.NewRegistry(), dbpurge.WithClock(clk))
 	defer closer.Close()
 
 	// then
@@ -424,7 +445,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 
 	// Start a new purger to immediately trigger delete after rollup.
 	_ = closer.Close()
-	closer = dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{}, prometheus.NewRegistry(), dbpurge.WithClock(clk))
+	closer = dbpurge.New(ctx, logger, db, vals, prometheus.NewRegistry(), dbpurge.WithClock(clk))
 	defer closer.Close()
 
 	// then
@@ -440,6 +461,93 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 	}, testutil.WaitShort, testutil.IntervalFast, "it should delete old stats after rollup: %v", stats)
 }
 
+//nolint:paralleltest // It uses LockIDDBPurge.
+func TestDeleteOldWorkspaceAgentStatsRetention(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	reg := prometheus.NewRegistry()
+	clk := quartz.NewMock(t)
+	now := dbtime.Now()
+	clk.Set(now).MustWait(ctx)
+
+	db, _ := dbtestutil.NewDB(t)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	// Without template usage stats, stats older than ~6 months are
+	// considered rolled up. Only the retention period keeps them around.
+	oldest := dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:                 now.AddDate(0, 0, -200).Add(-6 * time.Hour),
+		ConnectionCount:           1,
+		ConnectionMedianLatencyMS: 1,
+		RxBytes:                   1111,
+		SessionCountSSH:           1,
+	})
+	// Past the retention period, but outside of the first 4 hour batch.
+	old := dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:                 now.AddDate(0, 0, -200),
+		ConnectionCount:           1,
+		ConnectionMedianLatencyMS: 1,
+		RxBytes:                   2222,
+		SessionCountSSH:           1,
+	})
+	// Rolled up, but within the retention period.
+	retained := dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:                 now.AddDate(0, 0, -185),
+		ConnectionCount:           1,
+		ConnectionMedianLatencyMS: 1,
+		RxBytes:                   3333,
+		SessionCountSSH:           1,
+	})
+	// Client network stats are retained as long as agent stats.
+	_ = dbgen.WorkspaceClientNetworkStat(t, db, database.WorkspaceClientNetworkStat{
+		CreatedAt:    now.AddDate(0, 0, -200),
+		ClientRegion: "DE",
+	})
+	_ = dbgen.WorkspaceClientNetworkStat(t, db, database.WorkspaceClientNetworkStat{
+		CreatedAt:    now.AddDate(0, 0, -185),
+		ClientRegion: "US",
+	})
+
+	done := awaitDoTick(ctx, t, clk)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{
+		Retention: codersdk.RetentionConfig{
+			WorkspaceAgentStats: serpent.Duration(190 * 24 * time.Hour),
+		},
+	}, reg, dbpurge.WithClock(clk))
+	defer closer.Close()
+	testutil.TryReceive(ctx, t, done)
+
+	stats, err := db.GetWorkspaceAgentStats(ctx, now.AddDate(0, 0, -210))
+	require.NoError(t, err)
+	require.False(t, containsWorkspaceAgentStat(stats, oldest), "oldest stat should be deleted")
+	require.True(t, containsWorkspaceAgentStat(stats, old), "old stat should be left for the next batch")
+	require.True(t, containsWorkspaceAgentStat(stats, retained), "stat within retention should be kept")
+
+	purged := promhelp.CounterValue(t, reg, "coderd_dbpurge_records_purged_total", prometheus.Labels{
+		"record_type": "workspace_agent_stats",
+	})
+	require.Equal(t, 1, purged)
+	purged = promhelp.CounterValue(t, reg, "coderd_dbpurge_records_purged_total", prometheus.Labels{
+		"record_type": "workspace_client_network_stats",
+	})
+	require.Equal(t, 1, purged)
+
+	// The remaining stat past the retention period is reported as backlog.
+	wantBacklog := 10 * 24 * time.Hour
+	backlog := promhelp.GaugeValue(t, reg, "coderd_dbpurge_workspace_agent_stats_backlog_seconds", nil)
+	require.InDelta(t, wantBacklog.Seconds(), backlog, time.Minute.Seconds())
+
+	raw, err := db.GetDatabasePurgeStatus(ctx)
+	require.NoError(t, err)
+	var status codersdk.DatabasePurgeStatus
+	require.NoError(t, json.Unmarshal([]byte(raw), &status))
+	require.True(t, status.Success)
+	require.Empty(t, status.Error)
+	require.Equal(t, int64(1), status.RecordsPurged["workspace_agent_stats"])
+	require.InDelta(t, wantBacklog.Milliseconds(), status.WorkspaceAgentStatsBacklogMillis, float64(time.Minute.Milliseconds()))
+}
+
 func containsWorkspaceAgentStat(stats []database.GetWorkspaceAgentStatsRow, needle database.WorkspaceAgentStat) bool {
 	return slices.ContainsFunc(stats, func(s database.GetWorkspaceAgentStatsRow) bool {
 		return s.WorkspaceRxBytes == needle.RxBytes
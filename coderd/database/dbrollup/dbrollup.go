@@ -2,6 +2,7 @@ package dbrollup
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
 )
 
 const (
@@ -17,11 +19,17 @@ const (
 	// Rollups will be synchronized with the clock so that
 	// they happen 13:00, 13:05, 13:10, etc.
 	DefaultInterval = 5 * time.Minute
+
+	// EventChannel is the pubsub channel rollup events are published on,
+	// by the replica that performed the rollup.
+	EventChannel = "db_rollup"
 )
 
 type Event struct {
-	Init               bool `json:"-"`
-	TemplateUsageStats bool `json:"template_usage_stats"`
+	Init                          bool `json:"-"`
+	TemplateUsageStats            bool `json:"template_usage_stats"`
+	WorkspaceCosts                bool `json:"workspace_costs"`
+	WorkspaceAgentResourceSamples bool `json:"workspace_agent_resource_samples"`
 }
 
 type Rolluper struct {
@@ -31,6 +39,7 @@ type Rolluper struct {
 	logger   slog.Logger
 	interval time.Duration
 	event    chan<- Event
+	ps       pubsub.Pubsub
 }
 
 type Option func(*Rolluper)
@@ -42,6 +51,14 @@ func WithInterval(interval time.Duration) Option {
 	}
 }
 
+// WithPubsub publishes an Event on EventChannel after every rollup, e.g. to
+// invalidate caches of the rolled up data on all replicas.
+func WithPubsub(ps pubsub.Pubsub) Option {
+	return func(r *Rolluper) {
+		r.ps = ps
+	}
+}
+
 // WithEventChannel sets the event channel to use for rollup events.
 //
 // This is only used for testing.
@@ -57,8 +74,10 @@ func WithEventChannel(ch chan<- Event) Option {
 // New creates a new DB rollup service that periodically runs rollup queries.
 // It is the caller's responsibility to call Close on the returned instance.
 //
-// This is for e.g. generating insights data (template_usage_stats) from
-// raw data (workspace_agent_stats, workspace_app_stats).
+// This is for e.g. generating insights data (template_usage_stats) and cost
+// estimates (workspace_costs) from raw data (workspace_agent_stats,
+// workspace_app_stats), and sampling the resource usage of agents
+// (workspace_agent_resource_samples) from their metadata.
 func New(logger slog.Logger, db database.Store, opts ...Option) *Rolluper {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -106,7 +125,17 @@ func (r *Rolluper) start(ctx context.Context) {
 				}
 
 				ev.TemplateUsageStats = true
-				return tx.UpsertTemplateUsageStats(ctx)
+				if err := tx.UpsertTemplateUsageStats(ctx); err != nil {
+					return err
+				}
+
+				ev.WorkspaceCosts = true
+				if err := tx.UpsertWorkspaceCosts(ctx); err != nil {
+					return err
+				}
+
+				ev.WorkspaceAgentResourceSamples = true
+				return tx.InsertWorkspaceAgentResourceSamples(ctx)
 			}, database.DefaultTXOptions().WithID("db_rollup"))
 		})
 
@@ -128,6 +157,15 @@ func (r *Rolluper) start(ctx context.Context) {
 			slog.F("event", ev),
 		)
 
+		if r.ps != nil && ev.TemplateUsageStats {
+			msg, err := json.Marshal(ev)
+			if err != nil {
+				r.logger.Error(ctx, "failed to marshal rollup event", slog.Error(err))
+			} else if err := r.ps.Publish(EventChannel, msg); err != nil {
+				r.logger.Warn(ctx, "failed to publish rollup event", slog.Error(err))
+			}
+		}
+
 		// For testing.
 		if r.event != nil {
 			select {
@@ -256,3 +256,154 @@ func TestRollupTemplateUsageStats(t *testing.T) {
 		},
 	}, stats[0])
 }
+
+func TestRollupWorkspaceCosts(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	twoHoursAgo := dbtime.Now().Add(-2 * time.Hour).Truncate(time.Hour).UTC()
+
+	var (
+		org   = dbgen.Organization(t, db, database.Organization{})
+		user  = dbgen.User(t, db, database.User{Name: "user1"})
+		tpl   = dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+		ver   = dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: user.ID})
+		ws    = dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: org.ID, TemplateID: tpl.ID, OwnerID: user.ID})
+		job   = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{OrganizationID: org.ID})
+		build = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       ws.ID,
+			JobID:             job.ID,
+			TemplateVersionID: ver.ID,
+			CreatedAt:         twoHoursAgo.Add(-time.Minute),
+			// One credit per running minute.
+			DailyCost: 1440,
+		})
+		res   = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID})
+		agent = dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: res.ID})
+	)
+
+	// Stats reported every 30 seconds for 3 minutes, the workspace was
+	// running for 3 minutes.
+	for i := range 6 {
+		_ = dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+			TemplateID:  tpl.ID,
+			WorkspaceID: ws.ID,
+			AgentID:     agent.ID,
+			UserID:      user.ID,
+			CreatedAt:   twoHoursAgo.Add(time.Duration(i) * 30 * time.Second),
+		})
+	}
+
+	events := make(chan dbrollup.Event, 1)
+	rolluper := dbrollup.New(logger, db, dbrollup.WithInterval(250*time.Millisecond), dbrollup.WithEventChannel(events))
+	defer rolluper.Close()
+
+	<-events // Deplete init event, resume operation.
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for rollup to occur")
+	case ev := <-events:
+		require.True(t, ev.WorkspaceCosts, "expected workspace costs to be rolled up")
+	}
+
+	for _, groupBy := range []string{"workspace", "user", "organization"} {
+		rows, err := db.GetWorkspaceCostInsights(ctx, database.GetWorkspaceCostInsightsParams{
+			GroupBy:   groupBy,
+			StartTime: twoHoursAgo,
+			EndTime:   twoHoursAgo.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 1, groupBy)
+		require.EqualValues(t, 3*60, rows[0].RunningSeconds, groupBy)
+		require.InDelta(t, 3, rows[0].Cost, 0.0001, groupBy)
+	}
+
+	rows, err := db.GetWorkspaceCostInsights(ctx, database.GetWorkspaceCostInsightsParams{
+		GroupBy:   "user",
+		StartTime: twoHoursAgo,
+		EndTime:   twoHoursAgo.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.ID, rows[0].ID)
+	require.Equal(t, user.Username, rows[0].Name)
+}
+
+func TestRollupWorkspaceAgentResourceSamples(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t, dbtestutil.WithDumpOnFailure())
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	var (
+		org   = dbgen.Organization(t, db, database.Organization{})
+		user  = dbgen.User(t, db, database.User{})
+		tpl   = dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+		ver   = dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true}, CreatedBy: user.ID})
+		ws    = dbgen.Workspace(t, db, database.WorkspaceTable{OrganizationID: org.ID, TemplateID: tpl.ID, OwnerID: user.ID})
+		job   = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{OrganizationID: org.ID})
+		build = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: job.ID, TemplateVersionID: ver.ID})
+		res   = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: build.JobID})
+		agent = dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: res.ID, Name: "main"})
+	)
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	collectedAt := dbtime.Now().Add(-time.Minute)
+	metadata := database.BatchUpdateWorkspaceAgentMetadataParams{}
+	for _, md := range []struct {
+		key, script, value string
+	}{
+		{"cpu", "coder stat cpu", "1.5/4 cores (38%)"},
+		{"mem", "coder stat mem", "2.5/8 GiB (31%)"},
+		// Host usage and other stats are not sampled.
+		{"host_cpu", "coder stat cpu --host", "12/16 cores (75%)"},
+		{"disk", "coder stat disk --path $HOME", "10/50 GiB (20%)"},
+	} {
+		err := db.InsertWorkspaceAgentMetadata(ctx, database.InsertWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: agent.ID,
+			DisplayName:      md.key,
+			Key:              md.key,
+			Script:           md.script,
+			Timeout:          1,
+			Interval:         10,
+		})
+		require.NoError(t, err)
+		metadata.WorkspaceAgentID = append(metadata.WorkspaceAgentID, agent.ID)
+		metadata.Key = append(metadata.Key, md.key)
+		metadata.Value = append(metadata.Value, md.value)
+		metadata.Error = append(metadata.Error, "")
+		metadata.CollectedAt = append(metadata.CollectedAt, collectedAt)
+	}
+	require.NoError(t, db.BatchUpdateWorkspaceAgentMetadata(ctx, metadata))
+
+	events := make(chan dbrollup.Event, 1)
+	rolluper := dbrollup.New(logger, db, dbrollup.WithInterval(250*time.Millisecond), dbrollup.WithEventChannel(events))
+	defer rolluper.Close()
+
+	<-events // Deplete init event, resume operation.
+
+	// Wait for two rollups, the unchanged metadata must only be sampled once.
+	for range 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for rollup to occur")
+		case ev := <-events:
+			require.True(t, ev.WorkspaceAgentResourceSamples, "expected workspace agent resource samples to be rolled up")
+		}
+	}
+
+	rows, err := db.GetWorkspaceAgentResourceUsage(ctx, database.GetWorkspaceAgentResourceUsageParams{
+		WorkspaceID: ws.ID,
+		StartTime:   collectedAt.Add(-time.Hour),
+		EndTime:     collectedAt.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.GetWorkspaceAgentResourceUsageRow{
+		{AgentName: "main", Resource: "cpu", Samples: 1, UsedP95: 1.5, UsedMax: 1.5, Total: 4},
+		{AgentName: "main", Resource: "memory", Samples: 1, UsedP95: 2.5 * (1 << 30), UsedMax: 2.5 * (1 << 30), Total: 8 << 30},
+	}, rows)
+}
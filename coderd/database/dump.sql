@@ -389,6 +389,11 @@ CREATE TYPE group_source AS ENUM (
     'oidc'
 );
 
+CREATE TYPE idle_workspace_action AS ENUM (
+    'flag',
+    'stop'
+);
+
 CREATE TYPE inbox_notification_read_status AS ENUM (
     'all',
     'unread',
@@ -571,7 +576,8 @@ CREATE TYPE resource_type AS ENUM (
     'group_ai_budget',
     'user_skill',
     'ai_gateway_key',
-    'user_ai_budget_override'
+    'user_ai_budget_override',
+    'webhook'
 );
 
 CREATE TYPE shareable_workspace_owners AS ENUM (
@@ -603,6 +609,16 @@ CREATE TYPE task_status AS ENUM (
     'error'
 );
 
+CREATE TYPE usage_quota_action AS ENUM (
+    'report',
+    'block_start'
+);
+
+CREATE TYPE usage_quota_metric AS ENUM (
+    'connection_hours',
+    'active_hours'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -611,6 +627,12 @@ CREATE TYPE user_status AS ENUM (
 
 COMMENT ON TYPE user_status IS 'Defines the users status: active, dormant, or suspended.';
 
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'pending',
+    'succeeded',
+    'failed'
+);
+
 CREATE TYPE workspace_agent_context_body_kind AS ENUM (
     'instruction_file',
     'skill',
@@ -1650,6 +1672,14 @@ CREATE TABLE api_keys (
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+CREATE TABLE audit_log_exports (
+    dataset text NOT NULL,
+    exported_until timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE audit_log_exports IS 'Tracks up to when each dataset was exported to object storage by the audit log exporter.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -2280,6 +2310,20 @@ CREATE TABLE group_members (
     group_id uuid NOT NULL
 );
 
+CREATE TABLE group_usage_quotas (
+    group_id uuid NOT NULL,
+    metric usage_quota_metric NOT NULL,
+    monthly_hours integer NOT NULL,
+    action usage_quota_action DEFAULT 'report'::usage_quota_action NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT group_usage_quotas_monthly_hours_check CHECK ((monthly_hours >= 0))
+);
+
+COMMENT ON TABLE group_usage_quotas IS 'Monthly usage allowance granted to each member of the group. A member''s allowance for a metric is the sum across their groups. No row in any of their groups means the metric is not limited.';
+
+COMMENT ON COLUMN group_usage_quotas.action IS 'What happens when a member exceeds their allowance. The strictest action across their groups applies.';
+
 CREATE TABLE groups (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -2793,6 +2837,24 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE scaletest_results (
+    id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    created_by uuid NOT NULL,
+    name text NOT NULL,
+    total_runs integer NOT NULL,
+    total_pass integer NOT NULL,
+    total_fail integer NOT NULL,
+    elapsed_ms bigint NOT NULL,
+    summary jsonb NOT NULL
+);
+
+COMMENT ON TABLE scaletest_results IS 'Summaries of scaletest results uploaded by the scaletest CLI, so that the capacity of a deployment can be tracked over time.';
+
+COMMENT ON COLUMN scaletest_results.name IS 'Name of the test, defaults to the scaletest command that was run.';
+
+COMMENT ON COLUMN scaletest_results.summary IS 'The scaletest harness results without the results of individual runs.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value text NOT NULL
@@ -3096,6 +3158,19 @@ COMMENT ON COLUMN telemetry_locks.event_type IS 'The type of event that was sent
 
 COMMENT ON COLUMN telemetry_locks.period_ending_at IS 'The heartbeat period end timestamp.';
 
+CREATE TABLE template_idle_policies (
+    template_id uuid NOT NULL,
+    idle_timeout_ms bigint NOT NULL,
+    action idle_workspace_action DEFAULT 'flag'::idle_workspace_action NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT template_idle_policies_idle_timeout_ms_check CHECK ((idle_timeout_ms > 0))
+);
+
+COMMENT ON TABLE template_idle_policies IS 'Configures the idle workspace reaper for workspaces of the template. Templates without a row are never reaped.';
+
+COMMENT ON COLUMN template_idle_policies.idle_timeout_ms IS 'Time without sessions after which a running workspace is idle.';
+
 CREATE TABLE template_usage_stats (
     start_time timestamp with time zone NOT NULL,
     end_time timestamp with time zone NOT NULL,
@@ -3344,7 +3419,9 @@ CREATE TABLE templates (
     max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
     use_classic_parameter_flow boolean DEFAULT false NOT NULL,
     cors_behavior cors_behavior DEFAULT 'simple'::cors_behavior NOT NULL,
-    disable_module_cache boolean DEFAULT false NOT NULL
+    disable_module_cache boolean DEFAULT false NOT NULL,
+    dormancy_activity_signal text DEFAULT ''::text NOT NULL,
+    CONSTRAINT templates_dormancy_activity_signal_check CHECK ((dormancy_activity_signal = ANY (ARRAY[''::text, 'connection'::text, 'session'::text, 'traffic'::text])))
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -3367,6 +3444,8 @@ COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the tem
 
 COMMENT ON COLUMN templates.use_classic_parameter_flow IS 'Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.';
 
+COMMENT ON COLUMN templates.dormancy_activity_signal IS 'The agent activity that counts as usage of workspaces of the template, and keeps them from going dormant. Empty to use the deployment default.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -3399,6 +3478,7 @@ CREATE VIEW template_with_names AS
     templates.use_classic_parameter_flow,
     templates.cors_behavior,
     templates.disable_module_cache,
+    templates.dormancy_activity_signal,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(visible_users.name, ''::text) AS created_by_name,
@@ -3542,6 +3622,47 @@ CREATE TABLE user_status_changes (
 
 COMMENT ON TABLE user_status_changes IS 'Tracks the history of user status changes';
 
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    status webhook_delivery_status DEFAULT 'pending'::webhook_delivery_status NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone NOT NULL,
+    response_status_code integer,
+    error text
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Log of the deliveries of workspace events to webhooks.';
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When a pending delivery is attempted next. It is pushed forward while an attempt is in flight, so that other replicas do not attempt it concurrently.';
+
+COMMENT ON COLUMN webhook_deliveries.response_status_code IS 'HTTP status code of the response to the last attempt, if any.';
+
+COMMENT ON COLUMN webhook_deliveries.error IS 'Error of the last attempt, if it failed.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    created_by uuid NOT NULL,
+    template_id uuid,
+    url text NOT NULL,
+    secret text NOT NULL,
+    secret_key_id text,
+    events text[] NOT NULL
+);
+
+COMMENT ON TABLE webhooks IS 'Endpoints that workspace events are delivered to.';
+
+COMMENT ON COLUMN webhooks.template_id IS 'Only events of workspaces of this template are delivered. NULL means events of all workspaces are delivered.';
+
+COMMENT ON COLUMN webhooks.secret IS 'Key used to sign the HMAC-SHA256 signature of deliveries. Encrypted at rest via dbcrypt when secret_key_id is set.';
+
+COMMENT ON COLUMN webhooks.secret_key_id IS 'The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted.';
+
 CREATE TABLE webpush_subscriptions (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     user_id uuid NOT NULL,
@@ -3675,6 +3796,21 @@ CREATE TABLE workspace_agent_port_share (
     protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL
 );
 
+CREATE TABLE workspace_agent_resource_samples (
+    agent_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    resource text NOT NULL,
+    collected_at timestamp with time zone NOT NULL,
+    used double precision NOT NULL,
+    total double precision NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_resource_samples IS 'CPU and memory usage of workspace agents, sampled by the rollup from the "coder stat cpu" and "coder stat mem" agent metadata. Used to recommend right-sized workspace resources.';
+
+COMMENT ON COLUMN workspace_agent_resource_samples.resource IS 'Either cpu, in cores, or memory, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_resource_samples.collected_at IS 'When the agent collected the metadata value the sample was taken from.';
+
 CREATE TABLE workspace_agent_script_timings (
     script_id uuid NOT NULL,
     started_at timestamp with time zone NOT NULL,
@@ -3863,6 +3999,50 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_client_network_stats (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    user_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    client_region text DEFAULT ''::text NOT NULL,
+    client_asn bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_client_network_stats IS 'Records the coarse network location of clients connecting to workspace agents. Only recorded when the deployment is configured with the request headers to derive it from, and never includes the client IP.';
+
+COMMENT ON COLUMN workspace_client_network_stats.client_region IS 'Region of the client, e.g. a country code, or empty if unknown.';
+
+COMMENT ON COLUMN workspace_client_network_stats.client_asn IS 'Autonomous system number of the network of the client, or 0 if unknown.';
+
+CREATE TABLE workspace_costs (
+    start_time timestamp with time zone NOT NULL,
+    workspace_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    running_mins smallint NOT NULL,
+    daily_cost integer NOT NULL,
+    cost double precision NOT NULL
+);
+
+COMMENT ON TABLE workspace_costs IS 'Records the estimated cost of running workspaces, aggregated per hour. A workspace is considered running during every minute it reported agent stats.';
+
+COMMENT ON COLUMN workspace_costs.start_time IS 'Start time of the hour the cost was accrued in.';
+
+COMMENT ON COLUMN workspace_costs.running_mins IS 'Minutes the workspace was running during the hour.';
+
+COMMENT ON COLUMN workspace_costs.daily_cost IS 'Daily cost of the workspace resources at the end of the hour.';
+
+COMMENT ON COLUMN workspace_costs.cost IS 'Cost accrued during the hour, in the same unit as daily_cost.';
+
+CREATE TABLE workspace_idle_flags (
+    workspace_id uuid NOT NULL,
+    flagged_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_idle_flags IS 'Records when the idle workspace reaper last flagged a workspace. A flag older than the last activity of the workspace is stale.';
+
 CREATE VIEW workspace_latest_builds AS
  SELECT latest_build.id,
     latest_build.workspace_id,
@@ -4020,6 +4200,29 @@ CREATE SEQUENCE workspace_resource_metadata_id_seq
 
 ALTER SEQUENCE workspace_resource_metadata_id_seq OWNED BY workspace_resource_metadata.id;
 
+CREATE TABLE workspace_stat_baselines (
+    workspace_id uuid NOT NULL,
+    metric text NOT NULL,
+    samples integer DEFAULT 0 NOT NULL,
+    mean double precision DEFAULT 0 NOT NULL,
+    variance double precision DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    flagged_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_stat_baselines IS 'The usual hourly activity of a workspace, kept as exponentially weighted moving statistics by the anomaly detector.';
+
+COMMENT ON COLUMN workspace_stat_baselines.updated_at IS 'The end of the last hour folded into the baseline.';
+
+COMMENT ON COLUMN workspace_stat_baselines.flagged_at IS 'When the metric of the workspace was last flagged as anomalous.';
+
+CREATE TABLE workspace_unused_notifications (
+    workspace_id uuid NOT NULL,
+    notified_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_unused_notifications IS 'Records when the owner of a workspace was last notified that it is unused. A notification older than the last use of the workspace is stale.';
+
 CREATE VIEW workspaces_expanded AS
  SELECT workspaces.id,
     workspaces.created_at,
@@ -4116,6 +4319,9 @@ ALTER TABLE ONLY aibridge_user_prompts
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY audit_log_exports
+    ADD CONSTRAINT audit_log_exports_pkey PRIMARY KEY (dataset);
+
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
@@ -4203,6 +4409,9 @@ ALTER TABLE ONLY group_ai_budgets
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
 
+ALTER TABLE ONLY group_usage_quotas
+    ADD CONSTRAINT group_usage_quotas_pkey PRIMARY KEY (group_id, metric);
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
 
@@ -4299,6 +4508,9 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY scaletest_results
+    ADD CONSTRAINT scaletest_results_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -4326,6 +4538,9 @@ ALTER TABLE ONLY telemetry_items
 ALTER TABLE ONLY telemetry_locks
     ADD CONSTRAINT telemetry_locks_pkey PRIMARY KEY (event_type, period_ending_at);
 
+ALTER TABLE ONLY template_idle_policies
+    ADD CONSTRAINT template_idle_policies_pkey PRIMARY KEY (template_id);
+
 ALTER TABLE ONLY template_usage_stats
     ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
 
@@ -4395,6 +4610,12 @@ ALTER TABLE ONLY user_status_changes
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_pkey PRIMARY KEY (id);
 
@@ -4419,6 +4640,9 @@ ALTER TABLE ONLY workspace_agent_metadata
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 
+ALTER TABLE ONLY workspace_agent_resource_samples
+    ADD CONSTRAINT workspace_agent_resource_samples_pkey PRIMARY KEY (agent_id, resource, collected_at);
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 
@@ -4467,6 +4691,15 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_client_network_stats
+    ADD CONSTRAINT workspace_client_network_stats_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_costs
+    ADD CONSTRAINT workspace_costs_pkey PRIMARY KEY (start_time, workspace_id);
+
+ALTER TABLE ONLY workspace_idle_flags
+    ADD CONSTRAINT workspace_idle_flags_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
@@ -4482,6 +4715,12 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_stat_baselines
+    ADD CONSTRAINT workspace_stat_baselines_pkey PRIMARY KEY (workspace_id, metric);
+
+ALTER TABLE ONLY workspace_unused_notifications
+    ADD CONSTRAINT workspace_unused_notifications_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
@@ -4675,6 +4914,8 @@ COMMENT ON INDEX idx_provisioner_daemons_org_name_owner_key IS 'Allow unique pro
 
 CREATE INDEX idx_provisioner_jobs_status ON provisioner_jobs USING btree (job_status);
 
+CREATE INDEX idx_scaletest_results_created_at ON scaletest_results USING btree (created_at DESC);
+
 CREATE INDEX idx_tailnet_peers_coordinator ON tailnet_peers USING btree (coordinator_id);
 
 CREATE INDEX idx_tailnet_tunnels_dst_id ON tailnet_tunnels USING hash (dst_id);
@@ -4703,6 +4944,10 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE ((deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries USING btree (next_attempt_at) WHERE (status = 'pending'::webhook_delivery_status);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id_created_at ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
 CREATE INDEX idx_workspace_app_statuses_workspace_id_created_at ON workspace_app_statuses USING btree (workspace_id, created_at DESC);
 
 CREATE INDEX idx_workspace_builds_initiator_id ON workspace_builds USING btree (initiator_id);
@@ -4761,6 +5006,10 @@ CREATE INDEX workspace_agent_devcontainers_workspace_agent_id ON workspace_agent
 
 COMMENT ON INDEX workspace_agent_devcontainers_workspace_agent_id IS 'Workspace agent foreign key and query index';
 
+CREATE INDEX workspace_agent_resource_samples_collected_at_idx ON workspace_agent_resource_samples USING btree (collected_at);
+
+CREATE INDEX workspace_agent_resource_samples_workspace_id_collected_at_idx ON workspace_agent_resource_samples USING btree (workspace_id, collected_at);
+
 CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_scripts_workspace_agent_id_idx IS 'Foreign key support index for faster lookups';
@@ -4785,6 +5034,14 @@ CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING b
 
 CREATE INDEX workspace_app_statuses_app_id_idx ON workspace_app_statuses USING btree (app_id, created_at DESC);
 
+CREATE INDEX workspace_client_network_stats_agent_id_created_at_idx ON workspace_client_network_stats USING btree (agent_id, created_at DESC);
+
+CREATE INDEX workspace_client_network_stats_created_at_idx ON workspace_client_network_stats USING btree (created_at);
+
+CREATE INDEX workspace_costs_start_time_idx ON workspace_costs USING btree (start_time DESC);
+
+COMMENT ON INDEX workspace_costs_start_time_idx IS 'Index for querying MAX(start_time).';
+
 CREATE INDEX workspace_modules_created_at_idx ON workspace_modules USING btree (created_at);
 
 CREATE INDEX workspace_next_start_at_idx ON workspaces USING btree (next_start_at) WHERE (deleted = false);
@@ -5055,6 +5312,9 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY group_usage_quotas
+    ADD CONSTRAINT group_usage_quotas_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -5151,6 +5411,9 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY scaletest_results
+    ADD CONSTRAINT scaletest_results_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY tailnet_peers
     ADD CONSTRAINT tailnet_peers_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -5181,6 +5444,9 @@ ALTER TABLE ONLY tasks
 ALTER TABLE ONLY tasks
     ADD CONSTRAINT tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_idle_policies
+    ADD CONSTRAINT template_idle_policies_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -5262,6 +5528,18 @@ ALTER TABLE ONLY user_skills
 ALTER TABLE ONLY user_status_changes
     ADD CONSTRAINT user_status_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY webpush_subscriptions
     ADD CONSTRAINT webpush_subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
@@ -5346,6 +5624,9 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_idle_flags
+    ADD CONSTRAINT workspace_idle_flags_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_modules
     ADD CONSTRAINT workspace_modules_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -5355,6 +5636,12 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_stat_baselines
+    ADD CONSTRAINT workspace_stat_baselines_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_unused_notifications
+    ADD CONSTRAINT workspace_unused_notifications_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
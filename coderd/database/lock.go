@@ -16,6 +16,8 @@ const (
 	LockIDReconcileSystemRoles
 	LockIDBoundaryUsageStats
 	LockIDAIProvidersEnvSeed
+	LockIDAnomalyDetector
+	LockIDAuditLogExport
 )
 
 // GenLockID generates a unique and consistent lock ID from a given string.
@@ -0,0 +1,17 @@
+CREATE TABLE scaletest_results (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    total_runs INTEGER NOT NULL,
+    total_pass INTEGER NOT NULL,
+    total_fail INTEGER NOT NULL,
+    elapsed_ms BIGINT NOT NULL,
+    summary JSONB NOT NULL
+);
+
+CREATE INDEX idx_scaletest_results_created_at ON scaletest_results USING btree (created_at DESC);
+
+COMMENT ON TABLE scaletest_results IS 'Summaries of scaletest results uploaded by the scaletest CLI, so that the capacity of a deployment can be tracked over time.';
+COMMENT ON COLUMN scaletest_results.name IS 'Name of the test, defaults to the scaletest command that was run.';
+COMMENT ON COLUMN scaletest_results.summary IS 'The scaletest harness results without the results of individual runs.';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TYPE IF EXISTS webhook_delivery_status;
+-- Postgres does not support removing enum values, so the 'webhook'
+-- resource_type value is left in place.
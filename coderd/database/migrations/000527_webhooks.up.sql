@@ -0,0 +1,46 @@
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'pending',
+    'succeeded',
+    'failed'
+);
+
+CREATE TABLE webhooks (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    template_id UUID REFERENCES templates(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    secret_key_id TEXT REFERENCES dbcrypt_keys(active_key_digest),
+    events TEXT[] NOT NULL
+);
+
+COMMENT ON TABLE webhooks IS 'Endpoints that workspace events are delivered to.';
+COMMENT ON COLUMN webhooks.template_id IS 'Only events of workspaces of this template are delivered. NULL means events of all workspaces are delivered.';
+COMMENT ON COLUMN webhooks.secret IS 'Key used to sign the HMAC-SHA256 signature of deliveries. Encrypted at rest via dbcrypt when secret_key_id is set.';
+COMMENT ON COLUMN webhooks.secret_key_id IS 'The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted.';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'webhook';
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    event TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status webhook_delivery_status NOT NULL DEFAULT 'pending'::webhook_delivery_status,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    response_status_code INTEGER,
+    error TEXT
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id_created_at ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries USING btree (next_attempt_at) WHERE (status = 'pending'::webhook_delivery_status);
+
+COMMENT ON TABLE webhook_deliveries IS 'Log of the deliveries of workspace events to webhooks.';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When a pending delivery is attempted next. It is pushed forward while an attempt is in flight, so that other replicas do not attempt it concurrently.';
+COMMENT ON COLUMN webhook_deliveries.response_status_code IS 'HTTP status code of the response to the last attempt, if any.';
+COMMENT ON COLUMN webhook_deliveries.error IS 'Error of the last attempt, if it failed.';
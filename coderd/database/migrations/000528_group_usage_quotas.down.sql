@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS group_usage_quotas;
+DROP TYPE IF EXISTS usage_quota_action;
+DROP TYPE IF EXISTS usage_quota_metric;
@@ -0,0 +1,23 @@
+CREATE TYPE usage_quota_metric AS ENUM (
+    'connection_hours',
+    'active_hours'
+);
+
+CREATE TYPE usage_quota_action AS ENUM (
+    'report',
+    'block_start'
+);
+
+CREATE TABLE group_usage_quotas (
+    group_id      UUID               NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    metric        usage_quota_metric NOT NULL,
+    -- Hours each member may consume per calendar month (UTC).
+    monthly_hours INTEGER            NOT NULL CHECK (monthly_hours >= 0),
+    action        usage_quota_action NOT NULL DEFAULT 'report'::usage_quota_action,
+    created_at    TIMESTAMPTZ        NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ        NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, metric)
+);
+
+COMMENT ON TABLE group_usage_quotas IS 'Monthly usage allowance granted to each member of the group. A member''s allowance for a metric is the sum across their groups. No row in any of their groups means the metric is not limited.';
+COMMENT ON COLUMN group_usage_quotas.action IS 'What happens when a member exceeds their allowance. The strictest action across their groups applies.';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_idle_flags;
+DROP TABLE IF EXISTS template_idle_policies;
+DROP TYPE IF EXISTS idle_workspace_action;
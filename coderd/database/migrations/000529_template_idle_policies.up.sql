@@ -0,0 +1,22 @@
+CREATE TYPE idle_workspace_action AS ENUM (
+    'flag',
+    'stop'
+);
+
+CREATE TABLE template_idle_policies (
+    template_id     UUID                  NOT NULL PRIMARY KEY REFERENCES templates(id) ON DELETE CASCADE,
+    -- Time without sessions after which a running workspace is idle.
+    idle_timeout_ms BIGINT                NOT NULL CHECK (idle_timeout_ms > 0),
+    action          idle_workspace_action NOT NULL DEFAULT 'flag'::idle_workspace_action,
+    created_at      TIMESTAMPTZ           NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ           NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE template_idle_policies IS 'Configures the idle workspace reaper for workspaces of the template. Templates without a row are never reaped.';
+
+CREATE TABLE workspace_idle_flags (
+    workspace_id UUID        NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    flagged_at   TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON TABLE workspace_idle_flags IS 'Records when the idle workspace reaper last flagged a workspace. A flag older than the last activity of the workspace is stale.';
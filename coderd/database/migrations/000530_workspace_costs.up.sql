@@ -0,0 +1,25 @@
+CREATE TABLE workspace_costs (
+    start_time      TIMESTAMPTZ      NOT NULL,
+    workspace_id    UUID             NOT NULL,
+    owner_id        UUID             NOT NULL,
+    organization_id UUID             NOT NULL,
+    template_id     UUID             NOT NULL,
+    running_mins    SMALLINT         NOT NULL,
+    daily_cost      INTEGER          NOT NULL,
+    cost            DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (start_time, workspace_id)
+);
+
+COMMENT ON TABLE workspace_costs IS 'Records the estimated cost of running workspaces, aggregated per hour. A workspace is considered running during every minute it reported agent stats.';
+
+COMMENT ON COLUMN workspace_costs.start_time IS 'Start time of the hour the cost was accrued in.';
+
+COMMENT ON COLUMN workspace_costs.running_mins IS 'Minutes the workspace was running during the hour.';
+
+COMMENT ON COLUMN workspace_costs.daily_cost IS 'Daily cost of the workspace resources at the end of the hour.';
+
+COMMENT ON COLUMN workspace_costs.cost IS 'Cost accrued during the hour, in the same unit as daily_cost.';
+
+CREATE INDEX workspace_costs_start_time_idx ON workspace_costs USING btree (start_time DESC);
+
+COMMENT ON INDEX workspace_costs_start_time_idx IS 'Index for querying MAX(start_time).';
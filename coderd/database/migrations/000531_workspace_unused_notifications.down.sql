@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = '501fc4a3-2c42-4a27-896d-4c14c1cac708';
+
+DROP TABLE IF EXISTS workspace_unused_notifications;
@@ -0,0 +1,33 @@
+CREATE TABLE workspace_unused_notifications (
+    workspace_id UUID        NOT NULL PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    notified_at  TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON TABLE workspace_unused_notifications IS 'Records when the owner of a workspace was last notified that it is unused. A notification older than the last use of the workspace is stale.';
+
+INSERT INTO notification_templates (
+	id,
+	name,
+	title_template,
+	body_template,
+	actions,
+	"group",
+	method,
+	kind,
+	enabled_by_default
+) VALUES (
+	'501fc4a3-2c42-4a27-896d-4c14c1cac708',
+	'Workspace Unused',
+	E'Workspace "{{.Labels.name}}" is unused',
+	E'{{if eq .Labels.owner .UserUsername}}Your workspace **{{.Labels.name}}**{{else}}The workspace **{{.Labels.name}}** owned by **{{.Labels.owner}}**{{end}} was last used {{.Labels.lastUsed}}.\n\n{{if .Labels.timeTilDormant}}If it remains unused, it will be marked as [**dormant**](https://coder.com/docs/admin/templates/managing-templates/schedule#dormancy-threshold) {{.Labels.timeTilDormant}}.\n\n{{end}}Use the workspace to keep it active, or delete it if it is no longer needed.',
+	'[
+		{
+			"label": "View workspace",
+			"url": "{{base_url}}/@{{.Labels.owner}}/{{.Labels.name}}"
+		}
+	]'::jsonb,
+	'Workspace Events',
+	NULL,
+	'system'::notification_template_kind,
+	true
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS workspace_client_network_stats;
@@ -0,0 +1,19 @@
+CREATE TABLE workspace_client_network_stats (
+    id            UUID        NOT NULL PRIMARY KEY,
+    created_at    TIMESTAMPTZ NOT NULL,
+    user_id       UUID        NOT NULL,
+    workspace_id  UUID        NOT NULL,
+    agent_id      UUID        NOT NULL,
+    client_region TEXT        NOT NULL DEFAULT '',
+    client_asn    BIGINT      NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE workspace_client_network_stats IS 'Records the coarse network location of clients connecting to workspace agents. Only recorded when the deployment is configured with the request headers to derive it from, and never includes the client IP.';
+
+COMMENT ON COLUMN workspace_client_network_stats.client_region IS 'Region of the client, e.g. a country code, or empty if unknown.';
+
+COMMENT ON COLUMN workspace_client_network_stats.client_asn IS 'Autonomous system number of the network of the client, or 0 if unknown.';
+
+CREATE INDEX workspace_client_network_stats_agent_id_created_at_idx ON workspace_client_network_stats USING btree (agent_id, created_at DESC);
+
+CREATE INDEX workspace_client_network_stats_created_at_idx ON workspace_client_network_stats USING btree (created_at);
@@ -0,0 +1,3 @@
+DELETE FROM notification_templates WHERE id = 'c89dc1e6-4d01-4bba-8eed-c18d31e5249b';
+
+DROP TABLE IF EXISTS workspace_stat_baselines;
@@ -0,0 +1,43 @@
+CREATE TABLE workspace_stat_baselines (
+    workspace_id UUID             NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    metric       TEXT             NOT NULL,
+    samples      INTEGER          NOT NULL DEFAULT 0,
+    mean         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    variance     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMPTZ      NOT NULL,
+    flagged_at   TIMESTAMPTZ,
+    PRIMARY KEY (workspace_id, metric)
+);
+
+COMMENT ON TABLE workspace_stat_baselines IS 'The usual hourly activity of a workspace, kept as exponentially weighted moving statistics by the anomaly detector.';
+
+COMMENT ON COLUMN workspace_stat_baselines.updated_at IS 'The end of the last hour folded into the baseline.';
+
+COMMENT ON COLUMN workspace_stat_baselines.flagged_at IS 'When the metric of the workspace was last flagged as anomalous.';
+
+INSERT INTO notification_templates (
+	id,
+	name,
+	title_template,
+	body_template,
+	actions,
+	"group",
+	method,
+	kind,
+	enabled_by_default
+) VALUES (
+	'c89dc1e6-4d01-4bba-8eed-c18d31e5249b',
+	'Workspace Anomalous Activity',
+	E'Unusual activity in workspace "{{.Labels.name}}"',
+	E'The workspace **{{.Labels.name}}** owned by **{{.Labels.owner}}** had unusually high {{.Labels.metric}} in the last hour: **{{.Labels.observed}}**, compared to an hourly average of {{.Labels.usual}}.\n\nReview the activity of the workspace if this is not expected.',
+	'[
+		{
+			"label": "View workspace",
+			"url": "{{base_url}}/@{{.Labels.owner}}/{{.Labels.name}}"
+		}
+	]'::jsonb,
+	'Workspace Events',
+	NULL,
+	'system'::notification_template_kind,
+	true
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS audit_log_exports;
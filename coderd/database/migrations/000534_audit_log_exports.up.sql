@@ -0,0 +1,7 @@
+CREATE TABLE audit_log_exports (
+    dataset        TEXT        NOT NULL PRIMARY KEY,
+    exported_until TIMESTAMPTZ NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL
+);
+
+COMMENT ON TABLE audit_log_exports IS 'Tracks up to when each dataset was exported to object storage by the audit log exporter.';
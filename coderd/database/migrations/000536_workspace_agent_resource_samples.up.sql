@@ -0,0 +1,19 @@
+CREATE TABLE workspace_agent_resource_samples (
+    agent_id     UUID             NOT NULL,
+    workspace_id UUID             NOT NULL,
+    resource     TEXT             NOT NULL,
+    collected_at TIMESTAMPTZ      NOT NULL,
+    used         DOUBLE PRECISION NOT NULL,
+    total        DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (agent_id, resource, collected_at)
+);
+
+COMMENT ON TABLE workspace_agent_resource_samples IS 'CPU and memory usage of workspace agents, sampled by the rollup from the "coder stat cpu" and "coder stat mem" agent metadata. Used to recommend right-sized workspace resources.';
+
+COMMENT ON COLUMN workspace_agent_resource_samples.resource IS 'Either cpu, in cores, or memory, in bytes.';
+
+COMMENT ON COLUMN workspace_agent_resource_samples.collected_at IS 'When the agent collected the metadata value the sample was taken from.';
+
+CREATE INDEX workspace_agent_resource_samples_workspace_id_collected_at_idx ON workspace_agent_resource_samples USING btree (workspace_id, collected_at);
+
+CREATE INDEX workspace_agent_resource_samples_collected_at_idx ON workspace_agent_resource_samples USING btree (collected_at);
@@ -0,0 +1,24 @@
+INSERT INTO scaletest_results (
+    id,
+    created_at,
+    created_by,
+    name,
+    total_runs,
+    total_pass,
+    total_fail,
+    elapsed_ms,
+    summary
+)
+SELECT
+    '2d1f3a5e-8c3b-4f0e-9a6d-7b2e1c4d5f60',
+    '2026-10-01 12:00:00+00',
+    id,
+    'create-workspaces',
+    10,
+    9,
+    1,
+    60000,
+    '{"schema_version":1,"total_runs":10,"total_pass":9,"total_fail":1,"elapsed_ms":60000}'::jsonb
+FROM users
+ORDER BY created_at, id
+LIMIT 1;
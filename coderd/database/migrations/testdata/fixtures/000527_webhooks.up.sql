@@ -0,0 +1,46 @@
+INSERT INTO webhooks (
+    id,
+    created_at,
+    created_by,
+    template_id,
+    url,
+    secret,
+    events
+)
+SELECT
+    '5b0e2c1d-3f4a-4e6b-8c7d-9a1b2c3d4e5f',
+    '2026-10-01 12:00:00+00',
+    id,
+    NULL,
+    'https://example.com/coder-webhook',
+    'secret',
+    ARRAY['workspace_build_succeeded', 'workspace_build_failed']
+FROM users
+ORDER BY created_at, id
+LIMIT 1;
+
+INSERT INTO webhook_deliveries (
+    id,
+    webhook_id,
+    created_at,
+    updated_at,
+    event,
+    payload,
+    status,
+    attempts,
+    next_attempt_at,
+    response_status_code
+)
+SELECT
+    'a3c5e7f9-1b2d-4f6a-8c0e-2d4f6a8c0e1b',
+    id,
+    '2026-10-01 12:05:00+00',
+    '2026-10-01 12:05:01+00',
+    'workspace_build_succeeded',
+    '{"event":"workspace_build_succeeded"}'::jsonb,
+    'succeeded',
+    1,
+    '2026-10-01 12:05:00+00',
+    200
+FROM webhooks
+WHERE id = '5b0e2c1d-3f4a-4e6b-8c7d-9a1b2c3d4e5f';
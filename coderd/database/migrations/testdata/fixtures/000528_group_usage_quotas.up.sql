@@ -0,0 +1,14 @@
+INSERT INTO group_usage_quotas (
+    group_id,
+    metric,
+    monthly_hours,
+    action
+)
+SELECT
+    id,
+    'connection_hours',
+    160,
+    'block_start'
+FROM groups
+ORDER BY name, id
+LIMIT 1;
@@ -0,0 +1,23 @@
+INSERT INTO template_idle_policies (
+    template_id,
+    idle_timeout_ms,
+    action
+)
+SELECT
+    id,
+    86400000,
+    'stop'
+FROM templates
+ORDER BY name, id
+LIMIT 1;
+
+INSERT INTO workspace_idle_flags (
+    workspace_id,
+    flagged_at
+)
+SELECT
+    id,
+    '2024-01-01 00:00:00+00'
+FROM workspaces
+ORDER BY name, id
+LIMIT 1;
@@ -0,0 +1,22 @@
+INSERT INTO workspace_costs (
+    start_time,
+    workspace_id,
+    owner_id,
+    organization_id,
+    template_id,
+    running_mins,
+    daily_cost,
+    cost
+)
+SELECT
+    '2024-01-01 00:00:00+00',
+    id,
+    owner_id,
+    organization_id,
+    template_id,
+    60,
+    24,
+    1
+FROM workspaces
+ORDER BY name, id
+LIMIT 1;
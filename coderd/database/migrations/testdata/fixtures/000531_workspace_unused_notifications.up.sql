@@ -0,0 +1,10 @@
+INSERT INTO workspace_unused_notifications (
+    workspace_id,
+    notified_at
+)
+SELECT
+    id,
+    '2024-01-01 00:00:00+00'
+FROM workspaces
+ORDER BY name, id
+LIMIT 1;
@@ -0,0 +1,17 @@
+INSERT INTO workspace_client_network_stats (
+    id,
+    created_at,
+    user_id,
+    workspace_id,
+    agent_id,
+    client_region,
+    client_asn
+) VALUES (
+    'b7c1e3a2-5f4d-4b8e-9a6c-2d3e4f5a6b7c',
+    '2024-01-01 00:00:00+00',
+    '30095c71-380b-457a-8995-97b8ee6e5307',
+    '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+    '7a1ce5f8-8d00-431c-ad1b-97a846512804',
+    'DE',
+    3320
+);
@@ -0,0 +1,20 @@
+INSERT INTO workspace_stat_baselines (
+    workspace_id,
+    metric,
+    samples,
+    mean,
+    variance,
+    updated_at,
+    flagged_at
+)
+SELECT
+    id,
+    'tx_bytes',
+    24,
+    1048576,
+    262144,
+    '2024-01-01 00:00:00+00',
+    NULL
+FROM workspaces
+ORDER BY name, id
+LIMIT 1;
@@ -0,0 +1,9 @@
+INSERT INTO audit_log_exports (
+    dataset,
+    exported_until,
+    updated_at
+) VALUES (
+    'audit_logs',
+    '2024-01-01 00:00:00+00',
+    '2024-01-01 01:00:00+00'
+);
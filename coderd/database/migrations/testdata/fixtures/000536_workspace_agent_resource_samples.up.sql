@@ -0,0 +1,22 @@
+INSERT INTO workspace_agent_resource_samples (
+    agent_id,
+    workspace_id,
+    resource,
+    collected_at,
+    used,
+    total
+) VALUES (
+    '7a1ce5f8-8d00-431c-ad1b-97a846512804',
+    '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+    'cpu',
+    '2024-01-01 00:00:00+00',
+    1.5,
+    4
+), (
+    '7a1ce5f8-8d00-431c-ad1b-97a846512804',
+    '3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+    'memory',
+    '2024-01-01 00:00:00+00',
+    2684354560,
+    8589934592
+);
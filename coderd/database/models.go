@@ -2066,6 +2066,64 @@ func AllGroupSourceValues() []GroupSource {
 	}
 }
 
+type IdleWorkspaceAction string
+
+const (
+	IdleWorkspaceActionFlag IdleWorkspaceAction = "flag"
+	IdleWorkspaceActionStop IdleWorkspaceAction = "stop"
+)
+
+func (e *IdleWorkspaceAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = IdleWorkspaceAction(s)
+	case string:
+		*e = IdleWorkspaceAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for IdleWorkspaceAction: %T", src)
+	}
+	return nil
+}
+
+type NullIdleWorkspaceAction struct {
+	IdleWorkspaceAction IdleWorkspaceAction `json:"idle_workspace_action"`
+	Valid               bool                `json:"valid"` // Valid is true if IdleWorkspaceAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullIdleWorkspaceAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.IdleWorkspaceAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.IdleWorkspaceAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullIdleWorkspaceAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.IdleWorkspaceAction), nil
+}
+
+func (e IdleWorkspaceAction) Valid() bool {
+	switch e {
+	case IdleWorkspaceActionFlag,
+		IdleWorkspaceActionStop:
+		return true
+	}
+	return false
+}
+
+func AllIdleWorkspaceActionValues() []IdleWorkspaceAction {
+	return []IdleWorkspaceAction{
+		IdleWorkspaceActionFlag,
+		IdleWorkspaceActionStop,
+	}
+}
+
 type InboxNotificationReadStatus string
 
 const (
@@ -3370,6 +3428,7 @@ const (
 	ResourceTypeUserSkill                   ResourceType = "user_skill"
 	ResourceTypeAIGatewayKey                ResourceType = "ai_gateway_key"
 	ResourceTypeUserAIBudgetOverride        ResourceType = "user_ai_budget_override"
+	ResourceTypeWebhook                     ResourceType = "webhook"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -3443,7 +3502,8 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeGroupAIBudget,
 		ResourceTypeUserSkill,
 		ResourceTypeAIGatewayKey,
-		ResourceTypeUserAIBudgetOverride:
+		ResourceTypeUserAIBudgetOverride,
+		ResourceTypeWebhook:
 		return true
 	}
 	return false
@@ -3486,6 +3546,7 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeUserSkill,
 		ResourceTypeAIGatewayKey,
 		ResourceTypeUserAIBudgetOverride,
+		ResourceTypeWebhook,
 	}
 }
 
@@ -3736,6 +3797,122 @@ func AllTaskStatusValues() []TaskStatus {
 	}
 }
 
+type UsageQuotaAction string
+
+const (
+	UsageQuotaActionReport     UsageQuotaAction = "report"
+	UsageQuotaActionBlockStart UsageQuotaAction = "block_start"
+)
+
+func (e *UsageQuotaAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UsageQuotaAction(s)
+	case string:
+		*e = UsageQuotaAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UsageQuotaAction: %T", src)
+	}
+	return nil
+}
+
+type NullUsageQuotaAction struct {
+	UsageQuotaAction UsageQuotaAction `json:"usage_quota_action"`
+	Valid            bool             `json:"valid"` // Valid is true if UsageQuotaAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUsageQuotaAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.UsageQuotaAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UsageQuotaAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUsageQuotaAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UsageQuotaAction), nil
+}
+
+func (e UsageQuotaAction) Valid() bool {
+	switch e {
+	case UsageQuotaActionReport,
+		UsageQuotaActionBlockStart:
+		return true
+	}
+	return false
+}
+
+func AllUsageQuotaActionValues() []UsageQuotaAction {
+	return []UsageQuotaAction{
+		UsageQuotaActionReport,
+		UsageQuotaActionBlockStart,
+	}
+}
+
+type UsageQuotaMetric string
+
+const (
+	UsageQuotaMetricConnectionHours UsageQuotaMetric = "connection_hours"
+	UsageQuotaMetricActiveHours     UsageQuotaMetric = "active_hours"
+)
+
+func (e *UsageQuotaMetric) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UsageQuotaMetric(s)
+	case string:
+		*e = UsageQuotaMetric(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UsageQuotaMetric: %T", src)
+	}
+	return nil
+}
+
+type NullUsageQuotaMetric struct {
+	UsageQuotaMetric UsageQuotaMetric `json:"usage_quota_metric"`
+	Valid            bool             `json:"valid"` // Valid is true if UsageQuotaMetric is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUsageQuotaMetric) Scan(value interface{}) error {
+	if value == nil {
+		ns.UsageQuotaMetric, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UsageQuotaMetric.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUsageQuotaMetric) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UsageQuotaMetric), nil
+}
+
+func (e UsageQuotaMetric) Valid() bool {
+	switch e {
+	case UsageQuotaMetricConnectionHours,
+		UsageQuotaMetricActiveHours:
+		return true
+	}
+	return false
+}
+
+func AllUsageQuotaMetricValues() []UsageQuotaMetric {
+	return []UsageQuotaMetric{
+		UsageQuotaMetricConnectionHours,
+		UsageQuotaMetricActiveHours,
+	}
+}
+
 // Defines the users status: active, dormant, or suspended.
 type UserStatus string
 
@@ -3798,6 +3975,67 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWebhookDeliveryStatusValues() []WebhookDeliveryStatus {
+	return []WebhookDeliveryStatus{
+		WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusFailed,
+	}
+}
+
 type WorkspaceAgentContextBodyKind string
 
 const (
@@ -4707,6 +4945,13 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Tracks up to when each dataset was exported to object storage by the audit log exporter.
+type AuditLogExport struct {
+	Dataset       string    `db:"dataset" json:"dataset"`
+	ExportedUntil time.Time `db:"exported_until" json:"exported_until"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // Persisted boundary audit events. Each row is a single audit event processed by a Boundary proxy.
 type BoundaryLog struct {
 	ID uuid.UUID `db:"id" json:"id"`
@@ -5190,6 +5435,17 @@ type GroupMemberTable struct {
 	GroupID uuid.UUID `db:"group_id" json:"group_id"`
 }
 
+// Monthly usage allowance granted to each member of the group. A member's allowance for a metric is the sum across their groups. No row in any of their groups means the metric is not limited.
+type GroupUsageQuota struct {
+	GroupID      uuid.UUID        `db:"group_id" json:"group_id"`
+	Metric       UsageQuotaMetric `db:"metric" json:"metric"`
+	MonthlyHours int32            `db:"monthly_hours" json:"monthly_hours"`
+	// What happens when a member exceeds their allowance. The strictest action across their groups applies.
+	Action    UsageQuotaAction `db:"action" json:"action"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time        `db:"updated_at" json:"updated_at"`
+}
+
 type InboxNotification struct {
 	ID         uuid.UUID       `db:"id" json:"id"`
 	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
@@ -5572,6 +5828,21 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Summaries of scaletest results uploaded by the scaletest CLI, so that the capacity of a deployment can be tracked over time.
+type ScaletestResult struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+	// Name of the test, defaults to the scaletest command that was run.
+	Name      string `db:"name" json:"name"`
+	TotalRuns int32  `db:"total_runs" json:"total_runs"`
+	TotalPass int32  `db:"total_pass" json:"total_pass"`
+	TotalFail int32  `db:"total_fail" json:"total_fail"`
+	ElapsedMs int64  `db:"elapsed_ms" json:"elapsed_ms"`
+	// The scaletest harness results without the results of individual runs.
+	Summary json.RawMessage `db:"summary" json:"summary"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -5704,6 +5975,7 @@ type Template struct {
 	UseClassicParameterFlow       bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
 	CorsBehavior                  CorsBehavior    `db:"cors_behavior" json:"cors_behavior"`
 	DisableModuleCache            bool            `db:"disable_module_cache" json:"disable_module_cache"`
+	DormancyActivitySignal        string          `db:"dormancy_activity_signal" json:"dormancy_activity_signal"`
 	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 	CreatedByName                 string          `db:"created_by_name" json:"created_by_name"`
@@ -5712,6 +5984,16 @@ type Template struct {
 	OrganizationIcon              string          `db:"organization_icon" json:"organization_icon"`
 }
 
+// Configures the idle workspace reaper for workspaces of the template. Templates without a row are never reaped.
+type TemplateIdlePolicy struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// Time without sessions after which a running workspace is idle.
+	IdleTimeoutMs int64               `db:"idle_timeout_ms" json:"idle_timeout_ms"`
+	Action        IdleWorkspaceAction `db:"action" json:"action"`
+	CreatedAt     time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `db:"updated_at" json:"updated_at"`
+}
+
 type TemplateTable struct {
 	ID              uuid.UUID       `db:"id" json:"id"`
 	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
@@ -5754,6 +6036,8 @@ type TemplateTable struct {
 	UseClassicParameterFlow bool         `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
 	CorsBehavior            CorsBehavior `db:"cors_behavior" json:"cors_behavior"`
 	DisableModuleCache      bool         `db:"disable_module_cache" json:"disable_module_cache"`
+	// The agent activity that counts as usage of workspaces of the template, and keeps them from going dormant. Empty to use the deployment default.
+	DormancyActivitySignal string `db:"dormancy_activity_signal" json:"dormancy_activity_signal"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -6072,6 +6356,39 @@ type VisibleUser struct {
 	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
 }
 
+// Endpoints that workspace events are delivered to.
+type Webhook struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+	// Only events of workspaces of this template are delivered. NULL means events of all workspaces are delivered.
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	Url        string        `db:"url" json:"url"`
+	// Key used to sign the HMAC-SHA256 signature of deliveries. Encrypted at rest via dbcrypt when secret_key_id is set.
+	Secret string `db:"secret" json:"secret"`
+	// The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted.
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	Events      []string       `db:"events" json:"events"`
+}
+
+// Log of the deliveries of workspace events to webhooks.
+type WebhookDelivery struct {
+	ID        uuid.UUID             `db:"id" json:"id"`
+	WebhookID uuid.UUID             `db:"webhook_id" json:"webhook_id"`
+	CreatedAt time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time             `db:"updated_at" json:"updated_at"`
+	Event     string                `db:"event" json:"event"`
+	Payload   json.RawMessage       `db:"payload" json:"payload"`
+	Status    WebhookDeliveryStatus `db:"status" json:"status"`
+	Attempts  int32                 `db:"attempts" json:"attempts"`
+	// When a pending delivery is attempted next. It is pushed forward while an attempt is in flight, so that other replicas do not attempt it concurrently.
+	NextAttemptAt time.Time `db:"next_attempt_at" json:"next_attempt_at"`
+	// HTTP status code of the response to the last attempt, if any.
+	ResponseStatusCode sql.NullInt32 `db:"response_status_code" json:"response_status_code"`
+	// Error of the last attempt, if it failed.
+	Error sql.NullString `db:"error" json:"error"`
+}
+
 type WebpushSubscription struct {
 	ID                uuid.UUID `db:"id" json:"id"`
 	UserID            uuid.UUID `db:"user_id" json:"user_id"`
@@ -6269,6 +6586,18 @@ type WorkspaceAgentPortShare struct {
 	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
 }
 
+// CPU and memory usage of workspace agents, sampled by the rollup from the "coder stat cpu" and "coder stat mem" agent metadata. Used to recommend right-sized workspace resources.
+type WorkspaceAgentResourceSample struct {
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// Either cpu, in cores, or memory, in bytes.
+	Resource string `db:"resource" json:"resource"`
+	// When the agent collected the metadata value the sample was taken from.
+	CollectedAt time.Time `db:"collected_at" json:"collected_at"`
+	Used        float64   `db:"used" json:"used"`
+	Total       float64   `db:"total" json:"total"`
+}
+
 type WorkspaceAgentScript struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
@@ -6460,6 +6789,41 @@ type WorkspaceBuildTable struct {
 	HasExternalAgent        sql.NullBool        `db:"has_external_agent" json:"has_external_agent"`
 }
 
+// Records the coarse network location of clients connecting to workspace agents. Only recorded when the deployment is configured with the request headers to derive it from, and never includes the client IP.
+type WorkspaceClientNetworkStat struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	// Region of the client, e.g. a country code, or empty if unknown.
+	ClientRegion string `db:"client_region" json:"client_region"`
+	// Autonomous system number of the network of the client, or 0 if unknown.
+	ClientAsn int64 `db:"client_asn" json:"client_asn"`
+}
+
+// Records the estimated cost of running workspaces, aggregated per hour. A workspace is considered running during every minute it reported agent stats.
+type WorkspaceCost struct {
+	// Start time of the hour the cost was accrued in.
+	StartTime      time.Time `db:"start_time" json:"start_time"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	// Minutes the workspace was running during the hour.
+	RunningMins int16 `db:"running_mins" json:"running_mins"`
+	// Daily cost of the workspace resources at the end of the hour.
+	DailyCost int32 `db:"daily_cost" json:"daily_cost"`
+	// Cost accrued during the hour, in the same unit as daily_cost.
+	Cost float64 `db:"cost" json:"cost"`
+}
+
+// Records when the idle workspace reaper last flagged a workspace. A flag older than the last activity of the workspace is stale.
+type WorkspaceIdleFlag struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	FlaggedAt   time.Time `db:"flagged_at" json:"flagged_at"`
+}
+
 type WorkspaceLatestBuild struct {
 	ID                      uuid.UUID            `db:"id" json:"id"`
 	WorkspaceID             uuid.UUID            `db:"workspace_id" json:"workspace_id"`
@@ -6545,6 +6909,19 @@ type WorkspaceResourceMetadatum struct {
 	ID                  int64          `db:"id" json:"id"`
 }
 
+// The usual hourly activity of a workspace, kept as exponentially weighted moving statistics by the anomaly detector.
+type WorkspaceStatBaseline struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Metric      string    `db:"metric" json:"metric"`
+	Samples     int32     `db:"samples" json:"samples"`
+	Mean        float64   `db:"mean" json:"mean"`
+	Variance    float64   `db:"variance" json:"variance"`
+	// The end of the last hour folded into the baseline.
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// When the metric of the workspace was last flagged as anomalous.
+	FlaggedAt sql.NullTime `db:"flagged_at" json:"flagged_at"`
+}
+
 type WorkspaceTable struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -6566,3 +6943,9 @@ type WorkspaceTable struct {
 	GroupACL    WorkspaceACL `db:"group_acl" json:"group_acl"`
 	UserACL     WorkspaceACL `db:"user_acl" json:"user_acl"`
 }
+
+// Records when the owner of a workspace was last notified that it is unused. A notification older than the last use of the workspace is stale.
+type WorkspaceUnusedNotification struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	NotifiedAt  time.Time `db:"notified_at" json:"notified_at"`
+}
@@ -42,6 +42,12 @@ type sqlcQuerier interface {
 	// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
 	AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error)
 	AcquireStaleChatDiffStatuses(ctx context.Context, limitVal int32) ([]AcquireStaleChatDiffStatusesRow, error)
+	// Acquires pending deliveries that are due, along with the webhook they are
+	// delivered to. The next attempt of acquired deliveries is pushed forward to
+	// the end of the lease, so that if the replica fails to record the outcome the
+	// delivery is attempted again once the lease expires. SKIP LOCKED prevents
+	// replicas from acquiring the same deliveries.
+	AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]AcquireWebhookDeliveriesRow, error)
 	// Bumps the workspace deadline by the template's configured "activity_bump"
 	// duration (default 1h). If the workspace bump will cross an autostart
 	// threshold, then the bump is autostart + TTL. This is the deadline behavior if
@@ -165,6 +171,7 @@ type sqlcQuerier interface {
 	DeleteGroupAIBudget(ctx context.Context, groupID uuid.UUID) (GroupAIBudget, error)
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
 	DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error
+	DeleteGroupUsageQuota(ctx context.Context, arg DeleteGroupUsageQuotaParams) (GroupUsageQuota, error)
 	DeleteLicense(ctx context.Context, id int32) (int32, error)
 	DeleteMCPServerConfigByID(ctx context.Context, id uuid.UUID) error
 	DeleteMCPServerUserToken(ctx context.Context, arg DeleteMCPServerUserTokenParams) error
@@ -217,11 +224,16 @@ type sqlcQuerier interface {
 	DeleteOldProvisionerDaemons(ctx context.Context) error
 	// Deletes old telemetry locks from the telemetry_locks table.
 	DeleteOldTelemetryLocks(ctx context.Context, periodEndingAtBefore time.Time) error
+	// Delete all webhook deliveries which have not been updated for over 30 days.
+	DeleteOldWebhookDeliveries(ctx context.Context) error
 	// If an agent hasn't connected within the retention period, we purge its logs.
 	// Exception: if the logs are related to the latest build, we keep those around.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
 	DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold time.Time) (int64, error)
-	DeleteOldWorkspaceAgentStats(ctx context.Context) error
+	// Delete all workspace agent resource samples collected over 30 days ago.
+	DeleteOldWorkspaceAgentResourceSamples(ctx context.Context) error
+	DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) (int64, error)
+	DeleteOldWorkspaceClientNetworkStats(ctx context.Context, beforeTime time.Time) (int64, error)
 	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
 	DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
@@ -234,12 +246,14 @@ type sqlcQuerier interface {
 	DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error)
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
 	DeleteTask(ctx context.Context, arg DeleteTaskParams) (uuid.UUID, error)
+	DeleteTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (TemplateIdlePolicy, error)
 	DeleteUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (UserAIBudgetOverride, error)
 	DeleteUserAIProviderKey(ctx context.Context, arg DeleteUserAIProviderKeyParams) error
 	DeleteUserAIProviderKeysByProviderID(ctx context.Context, aiProviderID uuid.UUID) error
 	DeleteUserChatCompactionThreshold(ctx context.Context, arg DeleteUserChatCompactionThresholdParams) error
 	DeleteUserSecretByUserIDAndName(ctx context.Context, arg DeleteUserSecretByUserIDAndNameParams) (UserSecret, error)
 	DeleteUserSkillByUserIDAndName(ctx context.Context, arg DeleteUserSkillByUserIDAndNameParams) (UserSkill, error)
+	DeleteWebhookByID(ctx context.Context, id uuid.UUID) error
 	DeleteWebpushSubscriptionByUserIDAndEndpoint(ctx context.Context, arg DeleteWebpushSubscriptionByUserIDAndEndpointParams) error
 	DeleteWebpushSubscriptions(ctx context.Context, ids []uuid.UUID) error
 	DeleteWorkspaceACLByID(ctx context.Context, id uuid.UUID) error
@@ -350,6 +364,11 @@ type sqlcQuerier interface {
 	GetAndResetBoundaryUsageSummary(ctx context.Context, maxStalenessMs int64) (GetAndResetBoundaryUsageSummaryRow, error)
 	GetAnnouncementBanners(ctx context.Context) (string, error)
 	GetApplicationName(ctx context.Context) (string, error)
+	GetAuditLogExport(ctx context.Context, dataset string) (AuditLogExport, error)
+	// Pages through the audit logs before end_time in the order they are
+	// exported. Pass the time and ID of the last audit log of the previous page
+	// to get the next one.
+	GetAuditLogsForExport(ctx context.Context, arg GetAuditLogsForExportParams) ([]AuditLog, error)
 	// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
 	// ID.
 	GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error)
@@ -524,10 +543,19 @@ type sqlcQuerier interface {
 	// record deadlines or heartbeats rely on a clock that is consistent
 	// with the database rather than the caller's local clock.
 	GetDatabaseNow(ctx context.Context) (time.Time, error)
+	GetDatabasePurgeStatus(ctx context.Context) (string, error)
 	GetDefaultChatModelConfig(ctx context.Context) (ChatModelConfig, error)
 	GetDefaultOrganization(ctx context.Context) (Organization, error)
 	GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error)
 	GetDeploymentID(ctx context.Context) (string, error)
+	// GetDeploymentInsights returns insights aggregated across all templates and
+	// organizations between start and end time: the number of active users, the
+	// connection latency they experienced, the number of completed workspace
+	// builds and how many of them failed, and the bytes sent and received by
+	// workspace agents. Raw agent stats are deleted once they are rolled up into
+	// template usage stats, so the bandwidth only covers the period since
+	// bandwidth_start_time.
+	GetDeploymentInsights(ctx context.Context, arg GetDeploymentInsightsParams) (GetDeploymentInsightsRow, error)
 	GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error)
 	GetDeploymentWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentUsageStatsRow, error)
 	GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploymentWorkspaceStatsRow, error)
@@ -578,6 +606,7 @@ type sqlcQuerier interface {
 	// GetGroupMembersCountByGroupID, the count is returned even when the
 	// caller does not have read access to individual group members.
 	GetGroupMembersCountByGroupIDs(ctx context.Context, arg GetGroupMembersCountByGroupIDsParams) ([]GetGroupMembersCountByGroupIDsRow, error)
+	GetGroupUsageQuotas(ctx context.Context, groupID uuid.UUID) ([]GroupUsageQuota, error)
 	// A limit of 0 means "no limit".
 	GetGroups(ctx context.Context, arg GetGroupsParams) ([]GetGroupsRow, error)
 	GetHealthSettings(ctx context.Context) (string, error)
@@ -588,6 +617,14 @@ type sqlcQuerier interface {
 	// Returns no rows when the user has no budgeted groups; callers should treat
 	// sql.ErrNoRows as "no group budget".
 	GetHighestGroupAIBudgetByUser(ctx context.Context, userID uuid.UUID) (GetHighestGroupAIBudgetByUserRow, error)
+	// Returns the running workspaces of templates with an idle policy that have
+	// not been active within the idle timeout of the policy. A workspace is active
+	// while any of its agents reports a session. The start of the latest build
+	// also counts as activity so that workspaces aren't reaped right after they
+	// start. The last used time of the workspace is ignored, since it is also bumped
+	// by activity that isn't an agent session. Flags older than the last activity
+	// are ignored. A nil template ID returns idle workspaces across all templates.
+	GetIdleWorkspaces(ctx context.Context, arg GetIdleWorkspacesParams) ([]GetIdleWorkspacesRow, error)
 	GetInboxNotificationByID(ctx context.Context, id uuid.UUID) (InboxNotification, error)
 	// Fetches inbox notifications for a user filtered by templates and targets
 	// param user_id: The user ID
@@ -632,9 +669,18 @@ type sqlcQuerier interface {
 	GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (OAuth2ProviderAppToken, error)
 	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]GetOAuth2ProviderAppsByUserIDRow, error)
+	// Returns the creation time of the oldest raw workspace agent stat, or the
+	// current time if there are none.
+	GetOldestWorkspaceAgentStatCreatedAt(ctx context.Context) (time.Time, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, arg GetOrganizationByNameParams) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
+	// GetOrganizationInsights returns the same insights as GetDeploymentInsights
+	// limited to the templates and workspaces of an organization, along with the
+	// current number of workspaces in the organization and how many of them are
+	// running. Unlike the other insights, the workspace counts are not limited to
+	// the period between start and end time.
+	GetOrganizationInsights(ctx context.Context, arg GetOrganizationInsightsParams) (GetOrganizationInsightsRow, error)
 	GetOrganizationResourceCountByID(ctx context.Context, organizationID uuid.UUID) (GetOrganizationResourceCountByIDRow, error)
 	GetOrganizations(ctx context.Context, arg GetOrganizationsParams) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, arg GetOrganizationsByUserIDParams) ([]Organization, error)
@@ -642,6 +688,15 @@ type sqlcQuerier interface {
 	// membership status for the prebuilds system user (org membership, group existence, group membership).
 	GetOrganizationsWithPrebuildStatus(ctx context.Context, arg GetOrganizationsWithPrebuildStatusParams) ([]GetOrganizationsWithPrebuildStatusRow, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// GetPrebuildInsights returns the utilization of prebuilt workspace pools
+	// between start_time and end_time, grouped by template and preset name. A
+	// claim is the first build of a prebuilt workspace that was not initiated by
+	// the prebuilds system user. Its latency is the duration of that build, and
+	// the idle time is the time between the prebuild becoming ready and the claim.
+	// A miss is a workspace created from a preset with prebuilds configured that
+	// was built from scratch instead of claimed. Latencies and idle times are -1
+	// if there were no claims. The result can be filtered on template_ids.
+	GetPrebuildInsights(ctx context.Context, arg GetPrebuildInsightsParams) ([]GetPrebuildInsightsRow, error)
 	GetPrebuildMetrics(ctx context.Context) ([]GetPrebuildMetricsRow, error)
 	GetPrebuildsSettings(ctx context.Context) (string, error)
 	GetPresetByID(ctx context.Context, presetID uuid.UUID) (GetPresetByIDRow, error)
@@ -705,6 +760,8 @@ type sqlcQuerier interface {
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
 	GetRunningPrebuiltWorkspaces(ctx context.Context) ([]GetRunningPrebuiltWorkspacesRow, error)
 	GetRuntimeConfig(ctx context.Context, key string) (string, error)
+	GetScaletestResultByID(ctx context.Context, id uuid.UUID) (ScaletestResult, error)
+	GetScaletestResults(ctx context.Context, arg GetScaletestResultsParams) ([]ScaletestResult, error)
 	// Find chats that appear stuck and need recovery:
 	//   1. Running chats whose heartbeat has expired (worker crash).
 	//   2. requires_action chats past the timeout threshold (client
@@ -739,6 +796,12 @@ type sqlcQuerier interface {
 	//   because each resume cycle provisions a new app ID. This ensures
 	//   pre-pause statuses contribute to idle duration and active duration.
 	GetTelemetryTaskEvents(ctx context.Context, arg GetTelemetryTaskEventsParams) ([]GetTelemetryTaskEventsRow, error)
+	// GetTemplateAccessMethodInsights returns the session time per access method
+	// (VS Code, JetBrains, web terminal, SSH and SFTP) of every template with
+	// usage between start and end time, for each interval. Intervals are generated
+	// like in GetTemplateInsightsByInterval, and intervals without usage are
+	// included with no session time. The result can be filtered on template_ids.
+	GetTemplateAccessMethodInsights(ctx context.Context, arg GetTemplateAccessMethodInsightsParams) ([]GetTemplateAccessMethodInsightsRow, error)
 	// GetTemplateAppInsights returns the aggregate usage of each app in a given
 	// timeframe. The result can be filtered on template_ids, meaning only user data
 	// from workspaces based on those templates will be included.
@@ -749,6 +812,12 @@ type sqlcQuerier interface {
 	GetTemplateAverageBuildTime(ctx context.Context, templateID uuid.NullUUID) (GetTemplateAverageBuildTimeRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
+	// GetTemplateComparisonInsights returns build, usage and latency metrics of
+	// each of the given templates between start and end time, in the order the
+	// templates are given. The median build time only includes successful
+	// workspace starts. Unknown build times and latencies are -1.
+	GetTemplateComparisonInsights(ctx context.Context, arg GetTemplateComparisonInsightsParams) ([]GetTemplateComparisonInsightsRow, error)
+	GetTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (TemplateIdlePolicy, error)
 	// GetTemplateInsights returns the aggregate user-produced usage of all
 	// workspaces in a given timeframe. The template IDs, active users, and
 	// usage_seconds all reflect any usage in the template, including apps.
@@ -763,6 +832,11 @@ type sqlcQuerier interface {
 	// time, if end time is a partial interval, it will be included in the results and
 	// that interval will be shorter than a full one. If there is no data for a selected
 	// interval/template, it will be included in the results with 0 active users.
+	//
+	// Intervals are generated in the local time of the tz timezone, so that they
+	// follow the calendar of the caller across daylight saving time changes.
+	// Monthly intervals start on the first of the month, the first one is cut
+	// short to begin at the start time.
 	GetTemplateInsightsByInterval(ctx context.Context, arg GetTemplateInsightsByIntervalParams) ([]GetTemplateInsightsByIntervalRow, error)
 	// GetTemplateInsightsByTemplate is used for Prometheus metrics. Keep
 	// in sync with GetTemplateInsights and UpsertTemplateUsageStats.
@@ -782,7 +856,15 @@ type sqlcQuerier interface {
 	// score is computed in Go (see listtemplates.go) so the ranking policy and
 	// its confidence thresholds live in one place.
 	GetTemplateRankingSignalsByOwnerID(ctx context.Context, arg GetTemplateRankingSignalsByOwnerIDParams) ([]GetTemplateRankingSignalsByOwnerIDRow, error)
+	// GetTemplateUsageHeatmap returns the average and peak number of active users
+	// for each hour of the week between start and end time. Hours are in the local
+	// time of the tz timezone, day_of_week 0 is Sunday. Hours without activity
+	// count towards the average with 0 active users.
+	GetTemplateUsageHeatmap(ctx context.Context, arg GetTemplateUsageHeatmapParams) ([]GetTemplateUsageHeatmapRow, error)
 	GetTemplateUsageStats(ctx context.Context, arg GetTemplateUsageStatsParams) ([]TemplateUsageStat, error)
+	// Returns the template usage stats starting between start_time and end_time
+	// in the order they are exported. A limit of 0 returns all of them.
+	GetTemplateUsageStatsForExport(ctx context.Context, arg GetTemplateUsageStatsForExportParams) ([]TemplateUsageStat, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
@@ -805,6 +887,21 @@ type sqlcQuerier interface {
 	// inclusive.
 	GetTotalUsageDCManagedAgentsV1(ctx context.Context, arg GetTotalUsageDCManagedAgentsV1Params) (int64, error)
 	GetUnexpiredLicenses(ctx context.Context) ([]License, error)
+	// Returns the workspaces that have not been used since the given time and are
+	// not yet dormant. A workspace is used while any of its agents reports a
+	// session. Workspaces whose owner was already notified after the last use are
+	// omitted.
+	GetUnusedWorkspaces(ctx context.Context, unusedSince time.Time) ([]GetUnusedWorkspacesRow, error)
+	// Returns the monthly allowance of the user for every metric limited by any of
+	// their groups in the organization. Like workspace quota allowances, the
+	// allowances of all their groups are summed. The strictest action applies,
+	// which is the last one in the enum. Metrics without a row are not limited.
+	GetUsageQuotaAllowancesForUser(ctx context.Context, arg GetUsageQuotaAllowancesForUserParams) ([]GetUsageQuotaAllowancesForUserRow, error)
+	// Returns the minutes the user has used workspaces of the organization since
+	// the given time, from the usage stats rolled up from batched agent and app
+	// stats. Connection minutes count every session separately, while active
+	// minutes count the time the user had any session, across all templates.
+	GetUsageQuotaConsumedForUser(ctx context.Context, arg GetUsageQuotaConsumedForUserParams) (GetUsageQuotaConsumedForUserRow, error)
 	GetUserAIBudgetOverride(ctx context.Context, userID uuid.UUID) (UserAIBudgetOverride, error)
 	GetUserAIProviderKeyByProviderID(ctx context.Context, arg GetUserAIProviderKeyByProviderIDParams) (UserAIProviderKey, error)
 	// GetUserAIProviderKeys is used by dbcrypt key rotation. Request paths should use
@@ -825,6 +922,13 @@ type sqlcQuerier interface {
 	GetUserActivityInsights(ctx context.Context, arg GetUserActivityInsightsParams) ([]GetUserActivityInsightsRow, error)
 	GetUserAgentChatSendShortcut(ctx context.Context, userID uuid.UUID) (string, error)
 	GetUserAppearanceSettings(ctx context.Context, userID uuid.UUID) (GetUserAppearanceSettingsRow, error)
+	// GetUserBandwidthInsights returns the bytes received and sent by the
+	// workspace agents of each user, broken down by template. Users are ordered
+	// by their total bytes, heaviest first, and paginated as a whole so all rows
+	// of a user are returned together. user_count is the number of users across
+	// all pages. Raw agent stats are deleted once they are rolled up, so only the
+	// period they are retained for is covered.
+	GetUserBandwidthInsights(ctx context.Context, arg GetUserBandwidthInsightsParams) ([]GetUserBandwidthInsightsRow, error)
 	GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserChatCompactionThreshold(ctx context.Context, arg GetUserChatCompactionThresholdParams) (string, error)
@@ -838,6 +942,10 @@ type sqlcQuerier interface {
 	GetUserChatSpendInPeriod(ctx context.Context, arg GetUserChatSpendInPeriodParams) (int64, error)
 	GetUserCodeDiffDisplayMode(ctx context.Context, userID uuid.UUID) (string, error)
 	GetUserCount(ctx context.Context, includeSystem bool) (int64, error)
+	// Counts active users that used a workspace since the given time. Raw agent
+	// stats are purged after a short retention period, so the rolled up template
+	// usage stats are checked as well.
+	GetUserCountWithUsageSince(ctx context.Context, since time.Time) (int64, error)
 	// Returns the minimum (most restrictive) group limit for a user.
 	// Returns -1 if no group limits match the specified scope.
 	// When organization_id is NULL, groups across all organizations are
@@ -896,21 +1004,48 @@ type sqlcQuerier interface {
 	// to look up references to actions. eg. a user could build a workspace
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error)
+	GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
 	GetWebpushSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]WebpushSubscription, error)
 	GetWebpushVAPIDKeys(ctx context.Context) (GetWebpushVAPIDKeysRow, error)
 	GetWorkspaceACLByID(ctx context.Context, id uuid.UUID) (GetWorkspaceACLByIDRow, error)
+	// Returns the traffic of each workspace with agent stats between start_time
+	// and end_time, and the most sessions its agents had open at once.
+	GetWorkspaceActivityStats(ctx context.Context, arg GetWorkspaceActivityStatsParams) ([]GetWorkspaceActivityStatsRow, error)
 	GetWorkspaceAgentAndWorkspaceByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentAndWorkspaceByIDRow, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
+	// Aggregates the connection latency reported by workspace agents by the region
+	// and network of their clients. Each stat is attributed to the client that most
+	// recently connected to the agent within the hour before the stat was
+	// reported. Stats that cannot be attributed to a client are left out.
+	GetWorkspaceAgentConnectionLatencyByClientRegion(ctx context.Context, arg GetWorkspaceAgentConnectionLatencyByClientRegionParams) ([]GetWorkspaceAgentConnectionLatencyByClientRegionRow, error)
+	// Aggregates the connection latency reported by workspace agents by their
+	// preferred DERP region. Only the tailnet coordinator knows which region an
+	// agent prefers, so the mapping is passed in as parallel arrays. Agents missing
+	// from the mapping are grouped under region 0.
+	GetWorkspaceAgentConnectionLatencyByRegion(ctx context.Context, arg GetWorkspaceAgentConnectionLatencyByRegionParams) ([]GetWorkspaceAgentConnectionLatencyByRegionRow, error)
 	GetWorkspaceAgentDevcontainersByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentDevcontainer, error)
+	GetWorkspaceAgentIDsWithConnectionLatency(ctx context.Context, arg GetWorkspaceAgentIDsWithConnectionLatencyParams) ([]uuid.UUID, error)
 	GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error)
 	GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error)
 	GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error)
 	GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error)
 	GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	// Returns the CPU and memory usage of the agents of a workspace between
+	// start_time and end_time. Agents are grouped by name, so that the samples of
+	// the agents of all builds are included. The total is the latest total of the
+	// resource reported by the agent.
+	GetWorkspaceAgentResourceUsage(ctx context.Context, arg GetWorkspaceAgentResourceUsageParams) ([]GetWorkspaceAgentResourceUsageRow, error)
 	GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context, id uuid.UUID) ([]GetWorkspaceAgentScriptTimingsByBuildIDRow, error)
 	GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspaceAgentScriptsByAgentIDsRow, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
 	GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error)
+	// Downsamples the stats reported by a workspace agent into fixed size buckets
+	// starting at start_time. Counters are summed and gauges are averaged within
+	// each bucket. Buckets without any stats are returned with zero values so
+	// callers don't have to fill the gaps themselves.
+	GetWorkspaceAgentStatsTimeSeries(ctx context.Context, arg GetWorkspaceAgentStatsTimeSeriesParams) ([]GetWorkspaceAgentStatsTimeSeriesRow, error)
 	// `minute_buckets` could return 0 rows if there are no usage stats since `created_at`.
 	GetWorkspaceAgentUsageStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsRow, error)
 	GetWorkspaceAgentUsageStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentUsageStatsAndLabelsRow, error)
@@ -947,6 +1082,21 @@ type sqlcQuerier interface {
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByResourceID(ctx context.Context, resourceID uuid.UUID) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	// Returns how long a workspace was running between start_time and end_time,
+	// and the estimated cost of running it.
+	GetWorkspaceCost(ctx context.Context, arg GetWorkspaceCostParams) (GetWorkspaceCostRow, error)
+	// GetWorkspaceCostInsights returns the estimated cost of running workspaces
+	// between start and end time, grouped by workspace, owner or organization as
+	// chosen by group_by. The name is the workspace name, username or
+	// organization name respectively, and is empty if it no longer exists.
+	GetWorkspaceCostInsights(ctx context.Context, arg GetWorkspaceCostInsightsParams) ([]GetWorkspaceCostInsightsRow, error)
+	// GetWorkspaceHealthInsights returns the build and latency signals used to
+	// score the health of workspaces whose latest build is a start build. The
+	// latency is the median connection latency reported by agents since
+	// latency_since, the baseline latency is the median between baseline_since and
+	// latency_since, and both are -1 if no latency was reported. Builds are counted
+	// since builds_since. The result can be filtered on template_ids.
+	GetWorkspaceHealthInsights(ctx context.Context, arg GetWorkspaceHealthInsightsParams) ([]GetWorkspaceHealthInsightsRow, error)
 	GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceModule, error)
 	GetWorkspaceModulesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceModule, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
@@ -966,6 +1116,7 @@ type sqlcQuerier interface {
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceStatBaselines(ctx context.Context, workspaceIds []uuid.UUID) ([]WorkspaceStatBaseline, error)
 	GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error)
 	// build_params is used to filter by build parameters if present.
 	// It has to be a CTE because the set returning function 'unnest' cannot
@@ -1064,6 +1215,7 @@ type sqlcQuerier interface {
 	InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error)
 	InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertScaletestResult(ctx context.Context, arg InsertScaletestResultParams) (ScaletestResult, error)
 	InsertTask(ctx context.Context, arg InsertTaskParams) (TaskTable, error)
 	InsertTelemetryItemIfNotExists(ctx context.Context, arg InsertTelemetryItemIfNotExistsParams) error
 	// Inserts a new lock row into the telemetry_locks table. Replicas should call
@@ -1088,6 +1240,10 @@ type sqlcQuerier interface {
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
 	InsertUserSkill(ctx context.Context, arg InsertUserSkillParams) (UserSkill, error)
 	InsertVolumeResourceMonitor(ctx context.Context, arg InsertVolumeResourceMonitorParams) (WorkspaceAgentVolumeResourceMonitor, error)
+	InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error)
+	// Enqueues a delivery of the event to every webhook subscribed to it, either
+	// for all workspaces or for the template of the workspace the event is about.
+	InsertWebhookDeliveries(ctx context.Context, arg InsertWebhookDeliveriesParams) (int64, error)
 	// Inserts or updates a webpush subscription. The (user_id, endpoint) pair
 	// is unique; re-subscribing the same endpoint replaces the keys instead of
 	// inserting a duplicate row. This is the recovery path after a PWA reinstall
@@ -1099,6 +1255,12 @@ type sqlcQuerier interface {
 	InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error)
 	InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
+	// Samples the CPU and memory usage agents report through the "coder stat cpu"
+	// and "coder stat mem" metadata scripts, e.g. "1.2/4 cores (30%)" and
+	// "2.5/7.7 GiB (32%)". Host usage (--host) is not sampled, since it is not
+	// what the workspace is sized by. Every collected value is only sampled once,
+	// so the stale metadata of disconnected agents does not skew the samples.
+	InsertWorkspaceAgentResourceSamples(ctx context.Context) error
 	InsertWorkspaceAgentScriptTimings(ctx context.Context, arg InsertWorkspaceAgentScriptTimingsParams) (WorkspaceAgentScriptTiming, error)
 	InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error)
 	InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error
@@ -1106,6 +1268,7 @@ type sqlcQuerier interface {
 	InsertWorkspaceAppStatus(ctx context.Context, arg InsertWorkspaceAppStatusParams) (WorkspaceAppStatus, error)
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceClientNetworkStat(ctx context.Context, arg InsertWorkspaceClientNetworkStatParams) error
 	InsertWorkspaceModule(ctx context.Context, arg InsertWorkspaceModuleParams) (WorkspaceModule, error)
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
@@ -1377,6 +1540,10 @@ type sqlcQuerier interface {
 	// rows in place.
 	UpdateEncryptedAIProviderSettings(ctx context.Context, arg UpdateEncryptedAIProviderSettingsParams) (AIProvider, error)
 	UpdateEncryptedUserAIProviderKey(ctx context.Context, arg UpdateEncryptedUserAIProviderKeyParams) (UserAIProviderKey, error)
+	// Updates only the encrypted columns (secret, secret_key_id) of a webhook.
+	// Used by the dbcrypt key rotation utility to re-encrypt or decrypt rows in
+	// place.
+	UpdateEncryptedWebhookSecret(ctx context.Context, arg UpdateEncryptedWebhookSecretParams) (Webhook, error)
 	UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error)
 	// Optimistic lock: only update the row if the refresh token in the database
 	// still matches the one we read before attempting the refresh. This prevents
@@ -1457,6 +1624,7 @@ type sqlcQuerier interface {
 	UpdateUserThemePreference(ctx context.Context, arg UpdateUserThemePreferenceParams) (UserConfig, error)
 	UpdateUserThinkingDisplayMode(ctx context.Context, arg UpdateUserThinkingDisplayModeParams) (string, error)
 	UpdateVolumeResourceMonitor(ctx context.Context, arg UpdateVolumeResourceMonitorParams) error
+	UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (WorkspaceTable, error)
 	UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
@@ -1491,6 +1659,7 @@ type sqlcQuerier interface {
 	UpsertAISeatState(ctx context.Context, arg UpsertAISeatStateParams) (bool, error)
 	UpsertAnnouncementBanners(ctx context.Context, value string) error
 	UpsertApplicationName(ctx context.Context, value string) error
+	UpsertAuditLogExport(ctx context.Context, arg UpsertAuditLogExportParams) error
 	// Upserts boundary usage statistics for a replica. On INSERT (new period), uses
 	// delta values for unique counts (only data since last flush). On UPDATE, uses
 	// cumulative values for unique counts (accurate period totals). Request counts
@@ -1527,11 +1696,13 @@ type sqlcQuerier interface {
 	UpsertChatUsageLimitGroupOverride(ctx context.Context, arg UpsertChatUsageLimitGroupOverrideParams) (UpsertChatUsageLimitGroupOverrideRow, error)
 	UpsertChatUsageLimitUserOverride(ctx context.Context, arg UpsertChatUsageLimitUserOverrideParams) (UpsertChatUsageLimitUserOverrideRow, error)
 	UpsertChatWorkspaceTTL(ctx context.Context, workspaceTtl string) error
+	UpsertDatabasePurgeStatus(ctx context.Context, value string) error
 	// The default proxy is implied and not actually stored in the database.
 	// So we need to store it's configuration here for display purposes.
 	// The functional values are immutable and controlled implicitly.
 	UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error
 	UpsertGroupAIBudget(ctx context.Context, arg UpsertGroupAIBudgetParams) (GroupAIBudget, error)
+	UpsertGroupUsageQuota(ctx context.Context, arg UpsertGroupUsageQuotaParams) (GroupUsageQuota, error)
 	UpsertHealthSettings(ctx context.Context, value string) error
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
 	UpsertLogoURL(ctx context.Context, value string) error
@@ -1549,6 +1720,7 @@ type sqlcQuerier interface {
 	UpsertTaskSnapshot(ctx context.Context, arg UpsertTaskSnapshotParams) error
 	UpsertTaskWorkspaceApp(ctx context.Context, arg UpsertTaskWorkspaceAppParams) (TaskWorkspaceApp, error)
 	UpsertTelemetryItem(ctx context.Context, arg UpsertTelemetryItemParams) error
+	UpsertTemplateIdlePolicy(ctx context.Context, arg UpsertTemplateIdlePolicyParams) (TemplateIdlePolicy, error)
 	// This query aggregates the workspace_agent_stats and workspace_app_stats data
 	// into a single table for efficient storage and querying. Half-hour buckets are
 	// used to store the data, and the minutes are summed for each user and template
@@ -1571,6 +1743,20 @@ type sqlcQuerier interface {
 	// was started. This means that a new row was inserted (no previous session) or
 	// the updated_at is older than stale interval.
 	UpsertWorkspaceAppAuditSession(ctx context.Context, arg UpsertWorkspaceAppAuditSessionParams) (bool, error)
+	// This query estimates the cost of running workspaces from the
+	// workspace_agent_stats data. A workspace is considered running during every
+	// minute it reported agent stats, each running minute costs 1/1440th of the
+	// daily cost of the latest start build at that time. Hour buckets are used to
+	// store the data, and the result is stored in the workspace_costs table. Each
+	// hour is attributed to the user recorded on the agent stats, which is the
+	// owner at that time, so hours before an ownership transfer stay with the
+	// previous owner.
+	UpsertWorkspaceCosts(ctx context.Context) error
+	UpsertWorkspaceIdleFlag(ctx context.Context, arg UpsertWorkspaceIdleFlagParams) error
+	// Baselines are only moved forward, so that an hour is not folded into a
+	// baseline twice.
+	UpsertWorkspaceStatBaseline(ctx context.Context, arg UpsertWorkspaceStatBaselineParams) error
+	UpsertWorkspaceUnusedNotification(ctx context.Context, arg UpsertWorkspaceUnusedNotificationParams) error
 	UsageEventExistsByID(ctx context.Context, id string) (bool, error)
 	ValidateGroupIDs(ctx context.Context, groupIds []uuid.UUID) (ValidateGroupIDsRow, error)
 	ValidateUserIDs(ctx context.Context, userIds []uuid.UUID) (ValidateUserIDsRow, error)
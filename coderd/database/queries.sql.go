@@ -3312,6 +3312,94 @@ func (q *sqlQuerier) DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditL
 	return result.RowsAffected()
 }
 
+const getAuditLogExport = `-- name: GetAuditLogExport :one
+SELECT
+	dataset, exported_until, updated_at
+FROM
+	audit_log_exports
+WHERE
+	dataset = $1
+`
+
+func (q *sqlQuerier) GetAuditLogExport(ctx context.Context, dataset string) (AuditLogExport, error) {
+	row := q.db.QueryRowContext(ctx, getAuditLogExport, dataset)
+	var i AuditLogExport
+	err := row.Scan(
+		&i.Dataset,
+		&i.ExportedUntil,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAuditLogsForExport = `-- name: GetAuditLogsForExport :many
+SELECT
+	id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon
+FROM
+	audit_logs
+WHERE
+	(audit_logs.time, audit_logs.id) > ($1::timestamptz, $2::uuid)
+	AND audit_logs.time < $3::timestamptz
+ORDER BY
+	audit_logs.time, audit_logs.id
+LIMIT
+	$4::int
+`
+
+type GetAuditLogsForExportParams struct {
+	AfterTime  time.Time `db:"after_time" json:"after_time"`
+	AfterID    uuid.UUID `db:"after_id" json:"after_id"`
+	EndTime    time.Time `db:"end_time" json:"end_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Pages through the audit logs before end_time in the order they are
+// exported. Pass the time and ID of the last audit log of the previous page
+// to get the next one.
+func (q *sqlQuerier) GetAuditLogsForExport(ctx context.Context, arg GetAuditLogsForExportParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsForExport,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.EndTime,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+			&i.AdditionalFields,
+			&i.RequestID,
+			&i.ResourceIcon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
 	-- sqlc.embed(users) would be nice but it does not seem to play well with
@@ -3626,6 +3714,32 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
+const upsertAuditLogExport = `-- name: UpsertAuditLogExport :exec
+INSERT INTO audit_log_exports (
+	dataset,
+	exported_until,
+	updated_at
+) VALUES (
+	$1,
+	$2,
+	$3
+)
+ON CONFLICT (dataset) DO UPDATE SET
+	exported_until = EXCLUDED.exported_until,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertAuditLogExportParams struct {
+	Dataset       string    `db:"dataset" json:"dataset"`
+	ExportedUntil time.Time `db:"exported_until" json:"exported_until"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertAuditLogExport(ctx context.Context, arg UpsertAuditLogExportParams) error {
+	_, err := q.db.ExecContext(ctx, upsertAuditLogExport, arg.Dataset, arg.ExportedUntil, arg.UpdatedAt)
+	return err
+}
+
 const deleteOldBoundaryLogs = `-- name: DeleteOldBoundaryLogs :execrows
 WITH old_logs AS (
     SELECT id
@@ -15207,6 +15321,865 @@ func (q *sqlQuerier) ValidateGroupIDs(ctx context.Context, groupIds []uuid.UUID)
 	return i, err
 }
 
+const deleteTemplateIdlePolicy = `-- name: DeleteTemplateIdlePolicy :one
+DELETE FROM template_idle_policies
+WHERE template_id = $1
+RETURNING template_id, idle_timeout_ms, action, created_at, updated_at
+`
+
+func (q *sqlQuerier) DeleteTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (TemplateIdlePolicy, error) {
+	row := q.db.QueryRowContext(ctx, deleteTemplateIdlePolicy, templateID)
+	var i TemplateIdlePolicy
+	err := row.Scan(
+		&i.TemplateID,
+		&i.IdleTimeoutMs,
+		&i.Action,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getIdleWorkspaces = `-- name: GetIdleWorkspaces :many
+WITH last_sessions AS (
+	SELECT
+		workspace_id,
+		MAX(created_at) AS last_session_at
+	FROM
+		workspace_agent_stats
+	WHERE
+		template_id IN (SELECT template_id FROM template_idle_policies) AND
+		(session_count_vscode + session_count_jetbrains + session_count_reconnecting_pty + session_count_ssh) > 0
+	GROUP BY
+		workspace_id
+), activity AS (
+	SELECT
+		workspaces.id AS workspace_id,
+		GREATEST(workspace_latest_builds.created_at, last_sessions.last_session_at)::timestamptz AS last_active_at
+	FROM
+		workspaces
+	INNER JOIN workspace_latest_builds ON
+		workspace_latest_builds.workspace_id = workspaces.id
+	LEFT JOIN last_sessions ON
+		last_sessions.workspace_id = workspaces.id
+	WHERE
+		workspaces.deleted = false AND
+		workspaces.dormant_at IS NULL AND
+		workspace_latest_builds.transition = 'start'::workspace_transition AND
+		workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status AND
+		-- Prebuilt workspaces are handled by the prebuilds reconciliation loop.
+		workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
+)
+SELECT
+	workspaces_expanded.id AS workspace_id,
+	workspaces_expanded.name AS workspace_name,
+	workspaces_expanded.owner_id,
+	workspaces_expanded.owner_username,
+	workspaces_expanded.organization_id,
+	workspaces_expanded.template_id,
+	workspaces_expanded.template_name,
+	template_idle_policies.idle_timeout_ms,
+	template_idle_policies.action,
+	activity.last_active_at,
+	workspace_idle_flags.flagged_at
+FROM
+	activity
+INNER JOIN workspaces_expanded ON
+	workspaces_expanded.id = activity.workspace_id
+INNER JOIN template_idle_policies ON
+	template_idle_policies.template_id = workspaces_expanded.template_id
+LEFT JOIN workspace_idle_flags ON
+	workspace_idle_flags.workspace_id = activity.workspace_id AND
+	workspace_idle_flags.flagged_at >= activity.last_active_at
+WHERE
+	activity.last_active_at < $1::timestamptz - (INTERVAL '1 millisecond' * template_idle_policies.idle_timeout_ms) AND
+	CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspaces_expanded.template_id = $2
+		ELSE true
+	END
+ORDER BY
+	activity.last_active_at ASC,
+	workspaces_expanded.id ASC
+`
+
+type GetIdleWorkspacesParams struct {
+	Now        time.Time `db:"now" json:"now"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+}
+
+type GetIdleWorkspacesRow struct {
+	WorkspaceID    uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName  string              `db:"workspace_name" json:"workspace_name"`
+	OwnerID        uuid.UUID           `db:"owner_id" json:"owner_id"`
+	OwnerUsername  string              `db:"owner_username" json:"owner_username"`
+	OrganizationID uuid.UUID           `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID           `db:"template_id" json:"template_id"`
+	TemplateName   string              `db:"template_name" json:"template_name"`
+	IdleTimeoutMs  int64               `db:"idle_timeout_ms" json:"idle_timeout_ms"`
+	Action         IdleWorkspaceAction `db:"action" json:"action"`
+	LastActiveAt   time.Time           `db:"last_active_at" json:"last_active_at"`
+	FlaggedAt      sql.NullTime        `db:"flagged_at" json:"flagged_at"`
+}
+
+// Returns the running workspaces of templates with an idle policy that have
+// not been active within the idle timeout of the policy. A workspace is active
+// while any of its agents reports a session. The start of the latest build
+// also counts as activity so that workspaces aren't reaped right after they
+// start. The last used time of the workspace is ignored, since it is also bumped
+// by activity that isn't an agent session. Flags older than the last activity
+// are ignored. A nil template ID returns idle workspaces across all templates.
+func (q *sqlQuerier) GetIdleWorkspaces(ctx context.Context, arg GetIdleWorkspacesParams) ([]GetIdleWorkspacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getIdleWorkspaces, arg.Now, arg.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetIdleWorkspacesRow
+	for rows.Next() {
+		var i GetIdleWorkspacesRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.IdleTimeoutMs,
+			&i.Action,
+			&i.LastActiveAt,
+			&i.FlaggedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateIdlePolicy = `-- name: GetTemplateIdlePolicy :one
+SELECT
+	template_id, idle_timeout_ms, action, created_at, updated_at
+FROM
+	template_idle_policies
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateIdlePolicy(ctx context.Context, templateID uuid.UUID) (TemplateIdlePolicy, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateIdlePolicy, templateID)
+	var i TemplateIdlePolicy
+	err := row.Scan(
+		&i.TemplateID,
+		&i.IdleTimeoutMs,
+		&i.Action,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUnusedWorkspaces = `-- name: GetUnusedWorkspaces :many
+WITH last_sessions AS (
+	SELECT
+		workspace_id,
+		MAX(created_at) AS last_session_at
+	FROM
+		workspace_agent_stats
+	WHERE
+		(session_count_vscode + session_count_jetbrains + session_count_reconnecting_pty + session_count_ssh) > 0
+	GROUP BY
+		workspace_id
+), activity AS (
+	SELECT
+		workspaces.id AS workspace_id,
+		GREATEST(workspaces.last_used_at, last_sessions.last_session_at)::timestamptz AS last_used_at
+	FROM
+		workspaces
+	LEFT JOIN last_sessions ON
+		last_sessions.workspace_id = workspaces.id
+	WHERE
+		workspaces.deleted = false AND
+		workspaces.dormant_at IS NULL AND
+		-- Prebuilt workspaces are handled by the prebuilds reconciliation loop.
+		workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
+)
+SELECT
+	workspaces_expanded.id AS workspace_id,
+	workspaces_expanded.name AS workspace_name,
+	workspaces_expanded.owner_id,
+	workspaces_expanded.owner_username,
+	workspaces_expanded.organization_id,
+	workspaces_expanded.template_id,
+	templates.time_til_dormant,
+	activity.last_used_at
+FROM
+	activity
+INNER JOIN workspaces_expanded ON
+	workspaces_expanded.id = activity.workspace_id
+INNER JOIN templates ON
+	templates.id = workspaces_expanded.template_id
+LEFT JOIN workspace_unused_notifications ON
+	workspace_unused_notifications.workspace_id = activity.workspace_id AND
+	workspace_unused_notifications.notified_at >= activity.last_used_at
+WHERE
+	activity.last_used_at < $1::timestamptz AND
+	workspace_unused_notifications.workspace_id IS NULL
+ORDER BY
+	activity.last_used_at ASC,
+	workspaces_expanded.id ASC
+`
+
+type GetUnusedWorkspacesRow struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName  string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OwnerUsername  string    `db:"owner_username" json:"owner_username"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	TimeTilDormant int64     `db:"time_til_dormant" json:"time_til_dormant"`
+	LastUsedAt     time.Time `db:"last_used_at" json:"last_used_at"`
+}
+
+// Returns the workspaces that have not been used since the given time and are
+// not yet dormant. A workspace is used while any of its agents reports a
+// session. Workspaces whose owner was already notified after the last use are
+// omitted.
+func (q *sqlQuerier) GetUnusedWorkspaces(ctx context.Context, unusedSince time.Time) ([]GetUnusedWorkspacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnusedWorkspaces, unusedSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnusedWorkspacesRow
+	for rows.Next() {
+		var i GetUnusedWorkspacesRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.TimeTilDormant,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateIdlePolicy = `-- name: UpsertTemplateIdlePolicy :one
+INSERT INTO template_idle_policies (template_id, idle_timeout_ms, action)
+VALUES ($1, $2, $3)
+ON CONFLICT (template_id) DO UPDATE SET
+	idle_timeout_ms = EXCLUDED.idle_timeout_ms,
+	action          = EXCLUDED.action,
+	updated_at      = NOW()
+RETURNING template_id, idle_timeout_ms, action, created_at, updated_at
+`
+
+type UpsertTemplateIdlePolicyParams struct {
+	TemplateID    uuid.UUID           `db:"template_id" json:"template_id"`
+	IdleTimeoutMs int64               `db:"idle_timeout_ms" json:"idle_timeout_ms"`
+	Action        IdleWorkspaceAction `db:"action" json:"action"`
+}
+
+func (q *sqlQuerier) UpsertTemplateIdlePolicy(ctx context.Context, arg UpsertTemplateIdlePolicyParams) (TemplateIdlePolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateIdlePolicy, arg.TemplateID, arg.IdleTimeoutMs, arg.Action)
+	var i TemplateIdlePolicy
+	err := row.Scan(
+		&i.TemplateID,
+		&i.IdleTimeoutMs,
+		&i.Action,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertWorkspaceIdleFlag = `-- name: UpsertWorkspaceIdleFlag :exec
+INSERT INTO workspace_idle_flags (workspace_id, flagged_at)
+VALUES ($1, $2)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	flagged_at = EXCLUDED.flagged_at
+`
+
+type UpsertWorkspaceIdleFlagParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	FlaggedAt   time.Time `db:"flagged_at" json:"flagged_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceIdleFlag(ctx context.Context, arg UpsertWorkspaceIdleFlagParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceIdleFlag, arg.WorkspaceID, arg.FlaggedAt)
+	return err
+}
+
+const upsertWorkspaceUnusedNotification = `-- name: UpsertWorkspaceUnusedNotification :exec
+INSERT INTO workspace_unused_notifications (workspace_id, notified_at)
+VALUES ($1, $2)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	notified_at = EXCLUDED.notified_at
+`
+
+type UpsertWorkspaceUnusedNotificationParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	NotifiedAt  time.Time `db:"notified_at" json:"notified_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceUnusedNotification(ctx context.Context, arg UpsertWorkspaceUnusedNotificationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceUnusedNotification, arg.WorkspaceID, arg.NotifiedAt)
+	return err
+}
+
+const getDeploymentInsights = `-- name: GetDeploymentInsights :one
+WITH
+	usage_stats AS (
+		SELECT
+			COUNT(DISTINCT tus.user_id) AS active_users,
+			COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_50,
+			COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_95
+		FROM
+			template_usage_stats AS tus
+		WHERE
+			tus.start_time >= $1::timestamptz
+			AND tus.end_time <= $2::timestamptz
+	),
+	build_stats AS (
+		SELECT
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			wb.created_at >= $1::timestamptz
+			AND wb.created_at < $2::timestamptz
+			AND pj.completed_at IS NOT NULL
+	),
+	agent_stats AS (
+		SELECT
+			COALESCE(MIN(was.created_at), $2::timestamptz)::timestamptz AS bandwidth_start_time,
+			COALESCE(SUM(was.rx_bytes), 0)::bigint AS workspace_rx_bytes,
+			COALESCE(SUM(was.tx_bytes), 0)::bigint AS workspace_tx_bytes
+		FROM
+			workspace_agent_stats AS was
+		WHERE
+			was.created_at >= $1::timestamptz
+			AND was.created_at < $2::timestamptz
+	)
+SELECT
+	usage_stats.active_users,
+	usage_stats.workspace_connection_latency_50,
+	usage_stats.workspace_connection_latency_95,
+	build_stats.total_builds,
+	build_stats.failed_builds,
+	agent_stats.bandwidth_start_time,
+	agent_stats.workspace_rx_bytes,
+	agent_stats.workspace_tx_bytes
+FROM
+	usage_stats, build_stats, agent_stats
+`
+
+type GetDeploymentInsightsParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetDeploymentInsightsRow struct {
+	ActiveUsers                  int64     `db:"active_users" json:"active_users"`
+	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	TotalBuilds                  int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds                 int64     `db:"failed_builds" json:"failed_builds"`
+	BandwidthStartTime           time.Time `db:"bandwidth_start_time" json:"bandwidth_start_time"`
+	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+}
+
+// GetDeploymentInsights returns insights aggregated across all templates and
+// organizations between start and end time: the number of active users, the
+// connection latency they experienced, the number of completed workspace
+// builds and how many of them failed, and the bytes sent and received by
+// workspace agents. Raw agent stats are deleted once they are rolled up into
+// template usage stats, so the bandwidth only covers the period since
+// bandwidth_start_time.
+func (q *sqlQuerier) GetDeploymentInsights(ctx context.Context, arg GetDeploymentInsightsParams) (GetDeploymentInsightsRow, error) {
+	row := q.db.QueryRowContext(ctx, getDeploymentInsights, arg.StartTime, arg.EndTime)
+	var i GetDeploymentInsightsRow
+	err := row.Scan(
+		&i.ActiveUsers,
+		&i.WorkspaceConnectionLatency50,
+		&i.WorkspaceConnectionLatency95,
+		&i.TotalBuilds,
+		&i.FailedBuilds,
+		&i.BandwidthStartTime,
+		&i.WorkspaceRxBytes,
+		&i.WorkspaceTxBytes,
+	)
+	return i, err
+}
+
+const getOrganizationInsights = `-- name: GetOrganizationInsights :one
+WITH
+	usage_stats AS (
+		SELECT
+			COUNT(DISTINCT tus.user_id) AS active_users,
+			COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_50,
+			COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_95
+		FROM
+			template_usage_stats AS tus
+		JOIN
+			templates AS t
+		ON
+			t.id = tus.template_id
+		WHERE
+			t.organization_id = $1
+			AND tus.start_time >= $2::timestamptz
+			AND tus.end_time <= $3::timestamptz
+	),
+	workspace_stats AS (
+		SELECT
+			COUNT(*) AS total_workspaces,
+			COUNT(*) FILTER (WHERE wlb.transition = 'start' AND wlb.job_status = 'succeeded') AS running_workspaces
+		FROM
+			workspaces AS w
+		LEFT JOIN
+			workspace_latest_builds AS wlb
+		ON
+			wlb.workspace_id = w.id
+		WHERE
+			w.organization_id = $1
+			AND w.deleted = false
+	),
+	build_stats AS (
+		SELECT
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			pj.organization_id = $1
+			AND wb.created_at >= $2::timestamptz
+			AND wb.created_at < $3::timestamptz
+			AND pj.completed_at IS NOT NULL
+	),
+	agent_stats AS (
+		SELECT
+			COALESCE(MIN(was.created_at), $3::timestamptz)::timestamptz AS bandwidth_start_time,
+			COALESCE(SUM(was.rx_bytes), 0)::bigint AS workspace_rx_bytes,
+			COALESCE(SUM(was.tx_bytes), 0)::bigint AS workspace_tx_bytes
+		FROM
+			workspace_agent_stats AS was
+		JOIN
+			templates AS t
+		ON
+			t.id = was.template_id
+		WHERE
+			t.organization_id = $1
+			AND was.created_at >= $2::timestamptz
+			AND was.created_at < $3::timestamptz
+	)
+SELECT
+	usage_stats.active_users,
+	usage_stats.workspace_connection_latency_50,
+	usage_stats.workspace_connection_latency_95,
+	workspace_stats.total_workspaces,
+	workspace_stats.running_workspaces,
+	build_stats.total_builds,
+	build_stats.failed_builds,
+	agent_stats.bandwidth_start_time,
+	agent_stats.workspace_rx_bytes,
+	agent_stats.workspace_tx_bytes
+FROM
+	usage_stats, workspace_stats, build_stats, agent_stats
+`
+
+type GetOrganizationInsightsParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	StartTime      time.Time `db:"start_time" json:"start_time"`
+	EndTime        time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetOrganizationInsightsRow struct {
+	ActiveUsers                  int64     `db:"active_users" json:"active_users"`
+	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	TotalWorkspaces              int64     `db:"total_workspaces" json:"total_workspaces"`
+	RunningWorkspaces            int64     `db:"running_workspaces" json:"running_workspaces"`
+	TotalBuilds                  int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds                 int64     `db:"failed_builds" json:"failed_builds"`
+	BandwidthStartTime           time.Time `db:"bandwidth_start_time" json:"bandwidth_start_time"`
+	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+}
+
+// GetOrganizationInsights returns the same insights as GetDeploymentInsights
+// limited to the templates and workspaces of an organization, along with the
+// current number of workspaces in the organization and how many of them are
+// running. Unlike the other insights, the workspace counts are not limited to
+// the period between start and end time.
+func (q *sqlQuerier) GetOrganizationInsights(ctx context.Context, arg GetOrganizationInsightsParams) (GetOrganizationInsightsRow, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationInsights, arg.OrganizationID, arg.StartTime, arg.EndTime)
+	var i GetOrganizationInsightsRow
+	err := row.Scan(
+		&i.ActiveUsers,
+		&i.WorkspaceConnectionLatency50,
+		&i.WorkspaceConnectionLatency95,
+		&i.TotalWorkspaces,
+		&i.RunningWorkspaces,
+		&i.TotalBuilds,
+		&i.FailedBuilds,
+		&i.BandwidthStartTime,
+		&i.WorkspaceRxBytes,
+		&i.WorkspaceTxBytes,
+	)
+	return i, err
+}
+
+const getPrebuildInsights = `-- name: GetPrebuildInsights :many
+WITH
+	claim_builds AS (
+		SELECT DISTINCT ON (wb.workspace_id)
+			wb.workspace_id,
+			wb.build_number,
+			wb.job_id,
+			wb.created_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			workspace_builds AS first_build
+		ON
+			first_build.workspace_id = wb.workspace_id
+			AND first_build.build_number = 1
+			AND first_build.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+		WHERE
+			wb.initiator_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+		ORDER BY
+			wb.workspace_id, wb.build_number
+	),
+	events AS (
+		-- Prebuilds created for the pool.
+		SELECT
+			wb.template_version_preset_id AS preset_id,
+			'created'::text AS kind,
+			NULL::float AS claim_seconds,
+			NULL::float AS idle_seconds
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.build_number = 1
+			AND wb.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+			AND wb.created_at >= $1::timestamptz
+			AND wb.created_at < $2::timestamptz
+
+		UNION ALL
+
+		-- Prebuilds claimed from the pool, attributed to the preset of the
+		-- last prebuild build.
+		SELECT
+			pb.template_version_preset_id AS preset_id,
+			'claim'::text AS kind,
+			EXTRACT(EPOCH FROM claim_job.completed_at - cb.created_at)::float AS claim_seconds,
+			GREATEST(EXTRACT(EPOCH FROM cb.created_at - prebuild_job.completed_at), 0)::float AS idle_seconds
+		FROM
+			claim_builds AS cb
+		JOIN LATERAL (
+			SELECT
+				wb.template_version_preset_id,
+				wb.job_id
+			FROM
+				workspace_builds AS wb
+			WHERE
+				wb.workspace_id = cb.workspace_id
+				AND wb.build_number < cb.build_number
+			ORDER BY
+				wb.build_number DESC
+			LIMIT 1
+		) AS pb ON TRUE
+		JOIN
+			provisioner_jobs AS claim_job
+		ON
+			claim_job.id = cb.job_id
+		JOIN
+			provisioner_jobs AS prebuild_job
+		ON
+			prebuild_job.id = pb.job_id
+		WHERE
+			cb.created_at >= $1::timestamptz
+			AND cb.created_at < $2::timestamptz
+
+		UNION ALL
+
+		-- Workspaces built from scratch although the preset has a pool.
+		SELECT
+			wb.template_version_preset_id AS preset_id,
+			'miss'::text AS kind,
+			NULL::float AS claim_seconds,
+			NULL::float AS idle_seconds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_version_presets AS tvp
+		ON
+			tvp.id = wb.template_version_preset_id
+		WHERE
+			wb.build_number = 1
+			AND wb.initiator_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+			AND tvp.desired_instances > 0
+			AND wb.created_at >= $1::timestamptz
+			AND wb.created_at < $2::timestamptz
+	)
+SELECT
+	t.id AS template_id,
+	t.name AS template_name,
+	tvp.name AS preset_name,
+	COUNT(*) FILTER (WHERE e.kind = 'created') AS prebuilds_created,
+	COUNT(*) FILTER (WHERE e.kind = 'claim') AS claims,
+	COUNT(*) FILTER (WHERE e.kind = 'miss') AS misses,
+	COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY e.claim_seconds)), -1)::float AS claim_latency_seconds_50,
+	COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY e.claim_seconds)), -1)::float AS claim_latency_seconds_95,
+	COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY e.idle_seconds)), -1)::float AS idle_seconds_50,
+	COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY e.idle_seconds)), -1)::float AS idle_seconds_95
+FROM
+	events AS e
+JOIN
+	template_version_presets AS tvp
+ON
+	tvp.id = e.preset_id
+JOIN
+	template_versions AS tv
+ON
+	tv.id = tvp.template_version_id
+JOIN
+	templates AS t
+ON
+	t.id = tv.template_id
+WHERE
+	CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN t.id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY
+	t.id, t.name, tvp.name
+ORDER BY
+	t.name, tvp.name
+`
+
+type GetPrebuildInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetPrebuildInsightsRow struct {
+	TemplateID            uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName          string    `db:"template_name" json:"template_name"`
+	PresetName            string    `db:"preset_name" json:"preset_name"`
+	PrebuildsCreated      int64     `db:"prebuilds_created" json:"prebuilds_created"`
+	Claims                int64     `db:"claims" json:"claims"`
+	Misses                int64     `db:"misses" json:"misses"`
+	ClaimLatencySeconds50 float64   `db:"claim_latency_seconds_50" json:"claim_latency_seconds_50"`
+	ClaimLatencySeconds95 float64   `db:"claim_latency_seconds_95" json:"claim_latency_seconds_95"`
+	IdleSeconds50         float64   `db:"idle_seconds_50" json:"idle_seconds_50"`
+	IdleSeconds95         float64   `db:"idle_seconds_95" json:"idle_seconds_95"`
+}
+
+// GetPrebuildInsights returns the utilization of prebuilt workspace pools
+// between start_time and end_time, grouped by template and preset name. A
+// claim is the first build of a prebuilt workspace that was not initiated by
+// the prebuilds system user. Its latency is the duration of that build, and
+// the idle time is the time between the prebuild becoming ready and the claim.
+// A miss is a workspace created from a preset with prebuilds configured that
+// was built from scratch instead of claimed. Latencies and idle times are -1
+// if there were no claims. The result can be filtered on template_ids.
+func (q *sqlQuerier) GetPrebuildInsights(ctx context.Context, arg GetPrebuildInsightsParams) ([]GetPrebuildInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrebuildInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPrebuildInsightsRow
+	for rows.Next() {
+		var i GetPrebuildInsightsRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.PresetName,
+			&i.PrebuildsCreated,
+			&i.Claims,
+			&i.Misses,
+			&i.ClaimLatencySeconds50,
+			&i.ClaimLatencySeconds95,
+			&i.IdleSeconds50,
+			&i.IdleSeconds95,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateAccessMethodInsights = `-- name: GetTemplateAccessMethodInsights :many
+WITH
+	ts AS (
+		SELECT
+			GREATEST(d AT TIME ZONE $1::text, $2::timestamptz)::timestamptz AS from_,
+			LEAST(
+				(d + make_interval(months => $3::int, days => $4::int)) AT TIME ZONE $1::text,
+				$5::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				CASE
+					WHEN $3::int > 0 THEN date_trunc('month', $2::timestamptz AT TIME ZONE $1::text)
+					ELSE $2::timestamptz AT TIME ZONE $1::text
+				END,
+				-- Subtract 1 μs to avoid creating an extra series.
+				($5::timestamptz AT TIME ZONE $1::text) - '1 microsecond'::interval,
+				make_interval(months => $3::int, days => $4::int)
+			) AS d
+	),
+	usage AS (
+		SELECT
+			ts.from_,
+			tus.template_id,
+			SUM(tus.vscode_mins) AS vscode_mins,
+			SUM(tus.jetbrains_mins) AS jetbrains_mins,
+			SUM(tus.reconnecting_pty_mins) AS reconnecting_pty_mins,
+			SUM(tus.ssh_mins) AS ssh_mins,
+			SUM(tus.sftp_mins) AS sftp_mins
+		FROM
+			ts
+		JOIN
+			template_usage_stats AS tus
+		ON
+			tus.start_time >= ts.from_
+			AND tus.start_time < ts.to_ -- End time exclusion criteria optimization for index.
+			AND tus.end_time <= ts.to_
+			AND CASE WHEN COALESCE(array_length($6::uuid[], 1), 0) > 0 THEN tus.template_id = ANY($6::uuid[]) ELSE TRUE END
+		GROUP BY
+			ts.from_, tus.template_id
+	)
+SELECT
+	ts.from_ AS start_time,
+	ts.to_ AS end_time,
+	t.id AS template_id,
+	t.name AS template_name,
+	(COALESCE(u.vscode_mins, 0) * 60)::bigint AS usage_vscode_seconds,
+	(COALESCE(u.jetbrains_mins, 0) * 60)::bigint AS usage_jetbrains_seconds,
+	(COALESCE(u.reconnecting_pty_mins, 0) * 60)::bigint AS usage_reconnecting_pty_seconds,
+	(COALESCE(u.ssh_mins, 0) * 60)::bigint AS usage_ssh_seconds,
+	(COALESCE(u.sftp_mins, 0) * 60)::bigint AS usage_sftp_seconds
+FROM
+	(SELECT DISTINCT template_id FROM usage) AS tu
+JOIN
+	templates AS t
+ON
+	t.id = tu.template_id
+CROSS JOIN
+	ts
+LEFT JOIN
+	usage AS u
+ON
+	u.from_ = ts.from_
+	AND u.template_id = t.id
+ORDER BY
+	t.name, t.id, ts.from_
+`
+
+type GetTemplateAccessMethodInsightsParams struct {
+	Tz             string      `db:"tz" json:"tz"`
+	StartTime      time.Time   `db:"start_time" json:"start_time"`
+	IntervalMonths int32       `db:"interval_months" json:"interval_months"`
+	IntervalDays   int32       `db:"interval_days" json:"interval_days"`
+	EndTime        time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs    []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateAccessMethodInsightsRow struct {
+	StartTime                   time.Time `db:"start_time" json:"start_time"`
+	EndTime                     time.Time `db:"end_time" json:"end_time"`
+	TemplateID                  uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName                string    `db:"template_name" json:"template_name"`
+	UsageVscodeSeconds          int64     `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
+	UsageJetbrainsSeconds       int64     `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
+	UsageReconnectingPtySeconds int64     `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
+	UsageSshSeconds             int64     `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
+	UsageSftpSeconds            int64     `db:"usage_sftp_seconds" json:"usage_sftp_seconds"`
+}
+
+// GetTemplateAccessMethodInsights returns the session time per access method
+// (VS Code, JetBrains, web terminal, SSH and SFTP) of every template with
+// usage between start and end time, for each interval. Intervals are generated
+// like in GetTemplateInsightsByInterval, and intervals without usage are
+// included with no session time. The result can be filtered on template_ids.
+func (q *sqlQuerier) GetTemplateAccessMethodInsights(ctx context.Context, arg GetTemplateAccessMethodInsightsParams) ([]GetTemplateAccessMethodInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAccessMethodInsights,
+		arg.Tz,
+		arg.StartTime,
+		arg.IntervalMonths,
+		arg.IntervalDays,
+		arg.EndTime,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateAccessMethodInsightsRow
+	for rows.Next() {
+		var i GetTemplateAccessMethodInsightsRow
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.UsageVscodeSeconds,
+			&i.UsageJetbrainsSeconds,
+			&i.UsageReconnectingPtySeconds,
+			&i.UsageSshSeconds,
+			&i.UsageSftpSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
 WITH
 	-- Create a list of all unique apps by template, this is used to
@@ -15525,6 +16498,132 @@ func (q *sqlQuerier) GetTemplateAppInsightsByTemplate(ctx context.Context, arg G
 	return items, nil
 }
 
+const getTemplateComparisonInsights = `-- name: GetTemplateComparisonInsights :many
+WITH
+	build_stats AS (
+		SELECT
+			tv.template_id,
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pj.completed_at - pj.started_at)) FILTER (WHERE wb.transition = 'start' AND pj.job_status = 'succeeded') AS start_build_seconds_50
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			tv.template_id = ANY($1::uuid[])
+			AND wb.created_at >= $2::timestamptz
+			AND wb.created_at < $3::timestamptz
+			AND pj.completed_at IS NOT NULL
+		GROUP BY
+			tv.template_id
+	),
+	usage_stats AS (
+		SELECT
+			tus.template_id,
+			COUNT(DISTINCT tus.user_id) AS active_users,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms) AS workspace_connection_latency_50,
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms) AS workspace_connection_latency_95
+		FROM
+			template_usage_stats AS tus
+		WHERE
+			tus.template_id = ANY($1::uuid[])
+			AND tus.start_time >= $2::timestamptz
+			AND tus.end_time <= $3::timestamptz
+		GROUP BY
+			tus.template_id
+	)
+
+SELECT
+	t.id AS template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	t.organization_id,
+	COALESCE(bs.total_builds, 0)::bigint AS total_builds,
+	COALESCE(bs.failed_builds, 0)::bigint AS failed_builds,
+	COALESCE(bs.start_build_seconds_50, -1)::float AS start_build_seconds_50,
+	COALESCE(us.active_users, 0)::bigint AS active_users,
+	COALESCE(us.workspace_connection_latency_50, -1)::float AS workspace_connection_latency_50,
+	COALESCE(us.workspace_connection_latency_95, -1)::float AS workspace_connection_latency_95
+FROM
+	templates AS t
+LEFT JOIN
+	build_stats AS bs
+ON
+	bs.template_id = t.id
+LEFT JOIN
+	usage_stats AS us
+ON
+	us.template_id = t.id
+WHERE
+	t.id = ANY($1::uuid[])
+ORDER BY
+	array_position($1::uuid[], t.id) ASC
+`
+
+type GetTemplateComparisonInsightsParams struct {
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateComparisonInsightsRow struct {
+	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName                 string    `db:"template_name" json:"template_name"`
+	TemplateDisplayName          string    `db:"template_display_name" json:"template_display_name"`
+	OrganizationID               uuid.UUID `db:"organization_id" json:"organization_id"`
+	TotalBuilds                  int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds                 int64     `db:"failed_builds" json:"failed_builds"`
+	StartBuildSeconds50          float64   `db:"start_build_seconds_50" json:"start_build_seconds_50"`
+	ActiveUsers                  int64     `db:"active_users" json:"active_users"`
+	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+}
+
+// GetTemplateComparisonInsights returns build, usage and latency metrics of
+// each of the given templates between start and end time, in the order the
+// templates are given. The median build time only includes successful
+// workspace starts. Unknown build times and latencies are -1.
+func (q *sqlQuerier) GetTemplateComparisonInsights(ctx context.Context, arg GetTemplateComparisonInsightsParams) ([]GetTemplateComparisonInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateComparisonInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateComparisonInsightsRow
+	for rows.Next() {
+		var i GetTemplateComparisonInsightsRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.TemplateDisplayName,
+			&i.OrganizationID,
+			&i.TotalBuilds,
+			&i.FailedBuilds,
+			&i.StartBuildSeconds50,
+			&i.ActiveUsers,
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency95,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateInsights = `-- name: GetTemplateInsights :one
 WITH
 	insights AS (
@@ -15636,20 +16735,22 @@ const getTemplateInsightsByInterval = `-- name: GetTemplateInsightsByInterval :m
 WITH
 	ts AS (
 		SELECT
-			d::timestamptz AS from_,
+			GREATEST(d AT TIME ZONE $2::text, $3::timestamptz)::timestamptz AS from_,
 			LEAST(
-				(d::timestamptz + ($2::int || ' day')::interval)::timestamptz,
-				$3::timestamptz
+				(d + make_interval(months => $4::int, days => $5::int)) AT TIME ZONE $2::text,
+				$6::timestamptz
 			)::timestamptz AS to_
 		FROM
 			generate_series(
-				$4::timestamptz,
+				CASE
+					WHEN $4::int > 0 THEN date_trunc('month', $3::timestamptz AT TIME ZONE $2::text)
+					ELSE $3::timestamptz AT TIME ZONE $2::text
+				END,
 				-- Subtract 1 μs to avoid creating an extra series.
-				($3::timestamptz) - '1 microsecond'::interval,
-				($2::int || ' day')::interval
+				($6::timestamptz AT TIME ZONE $2::text) - '1 microsecond'::interval,
+				make_interval(months => $4::int, days => $5::int)
 			) AS d
 	)
-
 SELECT
 	ts.from_ AS start_time,
 	ts.to_ AS end_time,
@@ -15669,10 +16770,12 @@ GROUP BY
 `
 
 type GetTemplateInsightsByIntervalParams struct {
-	TemplateIDs  []uuid.UUID `db:"template_ids" json:"template_ids"`
-	IntervalDays int32       `db:"interval_days" json:"interval_days"`
-	EndTime      time.Time   `db:"end_time" json:"end_time"`
-	StartTime    time.Time   `db:"start_time" json:"start_time"`
+	TemplateIDs    []uuid.UUID `db:"template_ids" json:"template_ids"`
+	Tz             string      `db:"tz" json:"tz"`
+	StartTime      time.Time   `db:"start_time" json:"start_time"`
+	IntervalMonths int32       `db:"interval_months" json:"interval_months"`
+	IntervalDays   int32       `db:"interval_days" json:"interval_days"`
+	EndTime        time.Time   `db:"end_time" json:"end_time"`
 }
 
 type GetTemplateInsightsByIntervalRow struct {
@@ -15686,12 +16789,19 @@ type GetTemplateInsightsByIntervalRow struct {
 // time, if end time is a partial interval, it will be included in the results and
 // that interval will be shorter than a full one. If there is no data for a selected
 // interval/template, it will be included in the results with 0 active users.
+//
+// Intervals are generated in the local time of the tz timezone, so that they
+// follow the calendar of the caller across daylight saving time changes.
+// Monthly intervals start on the first of the month, the first one is cut
+// short to begin at the start time.
 func (q *sqlQuerier) GetTemplateInsightsByInterval(ctx context.Context, arg GetTemplateInsightsByIntervalParams) ([]GetTemplateInsightsByIntervalRow, error) {
 	rows, err := q.db.QueryContext(ctx, getTemplateInsightsByInterval,
 		pq.Array(arg.TemplateIDs),
+		arg.Tz,
+		arg.StartTime,
+		arg.IntervalMonths,
 		arg.IntervalDays,
 		arg.EndTime,
-		arg.StartTime,
 	)
 	if err != nil {
 		return nil, err
@@ -15925,6 +17035,102 @@ func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTe
 	return items, nil
 }
 
+const getTemplateUsageHeatmap = `-- name: GetTemplateUsageHeatmap :many
+WITH
+	hours AS (
+		SELECT
+			h AS start_time,
+			EXTRACT(DOW FROM h AT TIME ZONE $1::text)::int AS day_of_week,
+			EXTRACT(HOUR FROM h AT TIME ZONE $1::text)::int AS hour_of_day
+		FROM
+			generate_series(
+				$2::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				$3::timestamptz - '1 microsecond'::interval,
+				'1 hour'::interval
+			) AS h
+	),
+	hourly_active_users AS (
+		SELECT
+			hours.day_of_week,
+			hours.hour_of_day,
+			COUNT(DISTINCT tus.user_id) AS active_users
+		FROM
+			hours
+		LEFT JOIN
+			template_usage_stats AS tus
+		ON
+			tus.start_time >= hours.start_time
+			AND tus.start_time < hours.start_time + '1 hour'::interval
+			AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN tus.template_id = ANY($4::uuid[]) ELSE TRUE END
+		GROUP BY
+			hours.start_time, hours.day_of_week, hours.hour_of_day
+	)
+
+SELECT
+	day_of_week,
+	hour_of_day,
+	AVG(active_users)::float AS avg_active_users,
+	MAX(active_users)::bigint AS max_active_users
+FROM
+	hourly_active_users
+GROUP BY
+	day_of_week, hour_of_day
+ORDER BY
+	day_of_week ASC, hour_of_day ASC
+`
+
+type GetTemplateUsageHeatmapParams struct {
+	Tz          string      `db:"tz" json:"tz"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateUsageHeatmapRow struct {
+	DayOfWeek      int32   `db:"day_of_week" json:"day_of_week"`
+	HourOfDay      int32   `db:"hour_of_day" json:"hour_of_day"`
+	AvgActiveUsers float64 `db:"avg_active_users" json:"avg_active_users"`
+	MaxActiveUsers int64   `db:"max_active_users" json:"max_active_users"`
+}
+
+// GetTemplateUsageHeatmap returns the average and peak number of active users
+// for each hour of the week between start and end time. Hours are in the local
+// time of the tz timezone, day_of_week 0 is Sunday. Hours without activity
+// count towards the average with 0 active users.
+func (q *sqlQuerier) GetTemplateUsageHeatmap(ctx context.Context, arg GetTemplateUsageHeatmapParams) ([]GetTemplateUsageHeatmapRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateUsageHeatmap,
+		arg.Tz,
+		arg.StartTime,
+		arg.EndTime,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateUsageHeatmapRow
+	for rows.Next() {
+		var i GetTemplateUsageHeatmapRow
+		if err := rows.Scan(
+			&i.DayOfWeek,
+			&i.HourOfDay,
+			&i.AvgActiveUsers,
+			&i.MaxActiveUsers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateUsageStats = `-- name: GetTemplateUsageStats :many
 SELECT
 	start_time, end_time, template_id, user_id, median_latency_ms, usage_mins, ssh_mins, sftp_mins, reconnecting_pty_mins, vscode_mins, jetbrains_mins, app_usage_mins
@@ -15978,6 +17184,64 @@ func (q *sqlQuerier) GetTemplateUsageStats(ctx context.Context, arg GetTemplateU
 	return items, nil
 }
 
+const getTemplateUsageStatsForExport = `-- name: GetTemplateUsageStatsForExport :many
+SELECT
+	start_time, end_time, template_id, user_id, median_latency_ms, usage_mins, ssh_mins, sftp_mins, reconnecting_pty_mins, vscode_mins, jetbrains_mins, app_usage_mins
+FROM
+	template_usage_stats
+WHERE
+	start_time >= $1::timestamptz
+	AND start_time < $2::timestamptz
+ORDER BY
+	start_time, template_id, user_id
+LIMIT
+	NULLIF($3::int, 0)
+`
+
+type GetTemplateUsageStatsForExportParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// Returns the template usage stats starting between start_time and end_time
+// in the order they are exported. A limit of 0 returns all of them.
+func (q *sqlQuerier) GetTemplateUsageStatsForExport(ctx context.Context, arg GetTemplateUsageStatsForExportParams) ([]TemplateUsageStat, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateUsageStatsForExport, arg.StartTime, arg.EndTime, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateUsageStat
+	for rows.Next() {
+		var i TemplateUsageStat
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			&i.TemplateID,
+			&i.UserID,
+			&i.MedianLatencyMs,
+			&i.UsageMins,
+			&i.SshMins,
+			&i.SftpMins,
+			&i.ReconnectingPtyMins,
+			&i.VscodeMins,
+			&i.JetbrainsMins,
+			&i.AppUsageMins,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserActivityInsights = `-- name: GetUserActivityInsights :many
 WITH
 	deployment_stats AS (
@@ -16078,6 +17342,123 @@ func (q *sqlQuerier) GetUserActivityInsights(ctx context.Context, arg GetUserAct
 	return items, nil
 }
 
+const getUserBandwidthInsights = `-- name: GetUserBandwidthInsights :many
+WITH
+	template_bandwidth AS (
+		SELECT
+			user_id,
+			template_id,
+			SUM(rx_bytes)::bigint AS rx_bytes,
+			SUM(tx_bytes)::bigint AS tx_bytes
+		FROM
+			workspace_agent_stats
+		WHERE
+			created_at >= $1::timestamptz
+			AND created_at < $2::timestamptz
+			AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN template_id = ANY($3::uuid[]) ELSE TRUE END
+		GROUP BY
+			user_id, template_id
+	),
+	user_bandwidth AS (
+		SELECT
+			user_id,
+			SUM(rx_bytes + tx_bytes) AS total_bytes,
+			COUNT(*) OVER () AS user_count
+		FROM
+			template_bandwidth
+		GROUP BY
+			user_id
+		ORDER BY
+			total_bytes DESC, user_id ASC
+		OFFSET $4
+		LIMIT
+			-- A null limit means "no limit", so 0 means return all
+			NULLIF($5 :: int, 0)
+	)
+
+SELECT
+	tb.user_id,
+	u.username,
+	u.avatar_url,
+	tb.template_id,
+	tb.rx_bytes,
+	tb.tx_bytes,
+	ub.user_count
+FROM
+	user_bandwidth ub
+JOIN
+	template_bandwidth tb
+ON
+	tb.user_id = ub.user_id
+JOIN
+	users u
+ON
+	u.id = tb.user_id
+ORDER BY
+	ub.total_bytes DESC, tb.user_id ASC, tb.rx_bytes + tb.tx_bytes DESC, tb.template_id ASC
+`
+
+type GetUserBandwidthInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	OffsetOpt   int32       `db:"offset_opt" json:"offset_opt"`
+	LimitOpt    int32       `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetUserBandwidthInsightsRow struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Username   string    `db:"username" json:"username"`
+	AvatarURL  string    `db:"avatar_url" json:"avatar_url"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	RxBytes    int64     `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes    int64     `db:"tx_bytes" json:"tx_bytes"`
+	UserCount  int64     `db:"user_count" json:"user_count"`
+}
+
+// GetUserBandwidthInsights returns the bytes received and sent by the
+// workspace agents of each user, broken down by template. Users are ordered
+// by their total bytes, heaviest first, and paginated as a whole so all rows
+// of a user are returned together. user_count is the number of users across
+// all pages. Raw agent stats are deleted once they are rolled up, so only the
+// period they are retained for is covered.
+func (q *sqlQuerier) GetUserBandwidthInsights(ctx context.Context, arg GetUserBandwidthInsightsParams) ([]GetUserBandwidthInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserBandwidthInsights,
+		arg.StartTime,
+		arg.EndTime,
+		pq.Array(arg.TemplateIDs),
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserBandwidthInsightsRow
+	for rows.Next() {
+		var i GetUserBandwidthInsightsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.AvatarURL,
+			&i.TemplateID,
+			&i.RxBytes,
+			&i.TxBytes,
+			&i.UserCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
 SELECT
 	tus.user_id,
@@ -16284,6 +17665,283 @@ func (q *sqlQuerier) GetUserStatusCounts(ctx context.Context, arg GetUserStatusC
 	return items, nil
 }
 
+const getWorkspaceCost = `-- name: GetWorkspaceCost :one
+SELECT
+	(COALESCE(SUM(wc.running_mins), 0) * 60)::bigint AS running_seconds,
+	COALESCE(SUM(wc.cost), 0)::float AS cost
+FROM
+	workspace_costs AS wc
+WHERE
+	wc.workspace_id = $1
+	AND wc.start_time >= $2::timestamptz
+	AND wc.start_time < $3::timestamptz
+`
+
+type GetWorkspaceCostParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	StartTime   time.Time `db:"start_time" json:"start_time"`
+	EndTime     time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceCostRow struct {
+	RunningSeconds int64   `db:"running_seconds" json:"running_seconds"`
+	Cost           float64 `db:"cost" json:"cost"`
+}
+
+// Returns how long a workspace was running between start_time and end_time,
+// and the estimated cost of running it.
+func (q *sqlQuerier) GetWorkspaceCost(ctx context.Context, arg GetWorkspaceCostParams) (GetWorkspaceCostRow, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceCost, arg.WorkspaceID, arg.StartTime, arg.EndTime)
+	var i GetWorkspaceCostRow
+	err := row.Scan(&i.RunningSeconds, &i.Cost)
+	return i, err
+}
+
+const getWorkspaceCostInsights = `-- name: GetWorkspaceCostInsights :many
+WITH
+	grouped_costs AS (
+		SELECT
+			CASE $1::text
+				WHEN 'user' THEN wc.owner_id
+				WHEN 'organization' THEN wc.organization_id
+				ELSE wc.workspace_id
+			END AS id,
+			(SUM(wc.running_mins) * 60)::bigint AS running_seconds,
+			SUM(wc.cost)::float AS cost
+		FROM
+			workspace_costs AS wc
+		WHERE
+			wc.start_time >= $2::timestamptz
+			AND wc.start_time < $3::timestamptz
+		GROUP BY
+			1
+	)
+SELECT
+	gc.id,
+	COALESCE(w.name, u.username, o.name, '')::text AS name,
+	gc.running_seconds,
+	gc.cost
+FROM
+	grouped_costs AS gc
+LEFT JOIN
+	workspaces AS w
+ON
+	$1::text = 'workspace' AND w.id = gc.id
+LEFT JOIN
+	users AS u
+ON
+	$1::text = 'user' AND u.id = gc.id
+LEFT JOIN
+	organizations AS o
+ON
+	$1::text = 'organization' AND o.id = gc.id
+ORDER BY
+	gc.cost DESC, gc.id
+`
+
+type GetWorkspaceCostInsightsParams struct {
+	GroupBy   string    `db:"group_by" json:"group_by"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceCostInsightsRow struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	RunningSeconds int64     `db:"running_seconds" json:"running_seconds"`
+	Cost           float64   `db:"cost" json:"cost"`
+}
+
+// GetWorkspaceCostInsights returns the estimated cost of running workspaces
+// between start and end time, grouped by workspace, owner or organization as
+// chosen by group_by. The name is the workspace name, username or
+// organization name respectively, and is empty if it no longer exists.
+func (q *sqlQuerier) GetWorkspaceCostInsights(ctx context.Context, arg GetWorkspaceCostInsightsParams) ([]GetWorkspaceCostInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCostInsights, arg.GroupBy, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceCostInsightsRow
+	for rows.Next() {
+		var i GetWorkspaceCostInsightsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.RunningSeconds,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceHealthInsights = `-- name: GetWorkspaceHealthInsights :many
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (wb.workspace_id)
+			wb.workspace_id,
+			wb.job_id,
+			wb.transition,
+			pj.job_status
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		ORDER BY
+			wb.workspace_id, wb.build_number DESC
+	),
+	recent_builds AS (
+		SELECT
+			wb.workspace_id,
+			COUNT(*) AS builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed'::provisioner_job_status) AS failed_builds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			wb.created_at >= $1::timestamptz
+		GROUP BY
+			wb.workspace_id
+	),
+	latencies AS (
+		SELECT
+			was.workspace_id,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms) FILTER (WHERE was.created_at >= $2::timestamptz) AS latency_ms,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms) FILTER (WHERE was.created_at < $2::timestamptz) AS baseline_latency_ms
+		FROM
+			workspace_agent_stats AS was
+		WHERE
+			was.created_at >= $3::timestamptz
+			AND was.connection_median_latency_ms > 0
+		GROUP BY
+			was.workspace_id
+	)
+SELECT
+	w.id AS workspace_id,
+	w.name AS workspace_name,
+	w.owner_id,
+	u.username AS owner_username,
+	w.template_id,
+	t.name AS template_name,
+	lb.job_id AS latest_build_job_id,
+	(lb.job_status = 'failed'::provisioner_job_status)::boolean AS latest_build_failed,
+	COALESCE(rb.builds, 0)::bigint AS recent_builds,
+	COALESCE(rb.failed_builds, 0)::bigint AS recent_failed_builds,
+	COALESCE(l.latency_ms, -1)::float AS latency_ms,
+	COALESCE(l.baseline_latency_ms, -1)::float AS baseline_latency_ms
+FROM
+	workspaces AS w
+JOIN
+	users AS u
+ON
+	u.id = w.owner_id
+JOIN
+	templates AS t
+ON
+	t.id = w.template_id
+JOIN
+	latest_builds AS lb
+ON
+	lb.workspace_id = w.id
+LEFT JOIN
+	recent_builds AS rb
+ON
+	rb.workspace_id = w.id
+LEFT JOIN
+	latencies AS l
+ON
+	l.workspace_id = w.id
+WHERE
+	w.deleted = FALSE
+	AND lb.transition = 'start'::workspace_transition
+	AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN w.template_id = ANY($4::uuid[]) ELSE TRUE END
+ORDER BY
+	w.id
+`
+
+type GetWorkspaceHealthInsightsParams struct {
+	BuildsSince   time.Time   `db:"builds_since" json:"builds_since"`
+	LatencySince  time.Time   `db:"latency_since" json:"latency_since"`
+	BaselineSince time.Time   `db:"baseline_since" json:"baseline_since"`
+	TemplateIDs   []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetWorkspaceHealthInsightsRow struct {
+	WorkspaceID        uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName      string    `db:"workspace_name" json:"workspace_name"`
+	OwnerID            uuid.UUID `db:"owner_id" json:"owner_id"`
+	OwnerUsername      string    `db:"owner_username" json:"owner_username"`
+	TemplateID         uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName       string    `db:"template_name" json:"template_name"`
+	LatestBuildJobID   uuid.UUID `db:"latest_build_job_id" json:"latest_build_job_id"`
+	LatestBuildFailed  bool      `db:"latest_build_failed" json:"latest_build_failed"`
+	RecentBuilds       int64     `db:"recent_builds" json:"recent_builds"`
+	RecentFailedBuilds int64     `db:"recent_failed_builds" json:"recent_failed_builds"`
+	LatencyMs          float64   `db:"latency_ms" json:"latency_ms"`
+	BaselineLatencyMs  float64   `db:"baseline_latency_ms" json:"baseline_latency_ms"`
+}
+
+// GetWorkspaceHealthInsights returns the build and latency signals used to
+// score the health of workspaces whose latest build is a start build. The
+// latency is the median connection latency reported by agents since
+// latency_since, the baseline latency is the median between baseline_since and
+// latency_since, and both are -1 if no latency was reported. Builds are counted
+// since builds_since. The result can be filtered on template_ids.
+func (q *sqlQuerier) GetWorkspaceHealthInsights(ctx context.Context, arg GetWorkspaceHealthInsightsParams) ([]GetWorkspaceHealthInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceHealthInsights,
+		arg.BuildsSince,
+		arg.LatencySince,
+		arg.BaselineSince,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceHealthInsightsRow
+	for rows.Next() {
+		var i GetWorkspaceHealthInsightsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.OwnerID,
+			&i.OwnerUsername,
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.LatestBuildJobID,
+			&i.LatestBuildFailed,
+			&i.RecentBuilds,
+			&i.RecentFailedBuilds,
+			&i.LatencyMs,
+			&i.BaselineLatencyMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertTemplateUsageStats = `-- name: UpsertTemplateUsageStats :exec
 WITH
 	latest_start AS (
@@ -16562,6 +18220,110 @@ func (q *sqlQuerier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return err
 }
 
+const upsertWorkspaceCosts = `-- name: UpsertWorkspaceCosts :exec
+WITH
+	latest_start AS (
+		SELECT
+			-- Truncate to hour so that we always look at even ranges of data.
+			date_trunc('hour', COALESCE(
+				MAX(start_time) - '1 hour'::interval,
+				-- Fallback when there are no workspace costs yet.
+				(SELECT MIN(created_at) FROM workspace_agent_stats)
+			)) AS t
+		FROM
+			workspace_costs
+	),
+	running_minutes AS (
+		SELECT DISTINCT ON (was.workspace_id, date_trunc('minute', was.created_at))
+			was.workspace_id,
+			was.user_id,
+			date_trunc('minute', was.created_at) AS minute_bucket
+		FROM
+			workspace_agent_stats AS was
+		WHERE
+			was.created_at >= (SELECT t FROM latest_start)
+			AND was.created_at < NOW()
+		ORDER BY
+			was.workspace_id, date_trunc('minute', was.created_at), was.created_at DESC
+	),
+	minute_costs AS (
+		SELECT
+			rm.workspace_id,
+			rm.user_id,
+			rm.minute_bucket,
+			COALESCE(wb.daily_cost, 0) AS daily_cost
+		FROM
+			running_minutes AS rm
+		LEFT JOIN LATERAL (
+			SELECT
+				wb.daily_cost
+			FROM
+				workspace_builds AS wb
+			WHERE
+				wb.workspace_id = rm.workspace_id
+				AND wb.transition = 'start'::workspace_transition
+				AND wb.created_at < rm.minute_bucket + '1 minute'::interval
+			ORDER BY
+				wb.build_number DESC
+			LIMIT 1
+		) AS wb ON TRUE
+	)
+INSERT INTO workspace_costs AS wc (
+	start_time,
+	workspace_id,
+	owner_id,
+	organization_id,
+	template_id,
+	running_mins,
+	daily_cost,
+	cost
+)
+SELECT
+	date_trunc('hour', mc.minute_bucket) AS start_time,
+	mc.workspace_id,
+	-- An hour that spans an ownership transfer belongs to the owner at the end
+	-- of the hour.
+	(array_agg(mc.user_id ORDER BY mc.minute_bucket DESC))[1] AS owner_id,
+	w.organization_id,
+	w.template_id,
+	COUNT(*) AS running_mins,
+	(array_agg(mc.daily_cost ORDER BY mc.minute_bucket DESC))[1] AS daily_cost,
+	SUM(mc.daily_cost) / 1440.0 AS cost
+FROM
+	minute_costs AS mc
+JOIN
+	workspaces AS w
+ON
+	w.id = mc.workspace_id
+GROUP BY
+	date_trunc('hour', mc.minute_bucket), mc.workspace_id, w.organization_id, w.template_id
+ON CONFLICT
+	(start_time, workspace_id)
+DO UPDATE
+SET
+	owner_id = EXCLUDED.owner_id,
+	organization_id = EXCLUDED.organization_id,
+	template_id = EXCLUDED.template_id,
+	running_mins = EXCLUDED.running_mins,
+	daily_cost = EXCLUDED.daily_cost,
+	cost = EXCLUDED.cost
+WHERE
+	(wc.owner_id, wc.running_mins, wc.daily_cost, wc.cost) IS DISTINCT FROM (EXCLUDED.owner_id, EXCLUDED.running_mins, EXCLUDED.daily_cost, EXCLUDED.cost)
+`
+
+// This query estimates the cost of running workspaces from the
+// workspace_agent_stats data. A workspace is considered running during every
+// minute it reported agent stats, each running minute costs 1/1440th of the
+// daily cost of the latest start build at that time. Hour buckets are used to
+// store the data, and the result is stored in the workspace_costs table. Each
+// hour is attributed to the user recorded on the agent stats, which is the
+// owner at that time, so hours before an ownership transfer stay with the
+// previous owner.
+func (q *sqlQuerier) UpsertWorkspaceCosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceCosts)
+	return err
+}
+
 const deleteLicense = `-- name: DeleteLicense :one
 DELETE
 FROM licenses
@@ -23801,6 +25563,72 @@ func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg Update
 	return err
 }
 
+const deleteGroupUsageQuota = `-- name: DeleteGroupUsageQuota :one
+DELETE FROM group_usage_quotas
+WHERE group_id = $1 AND metric = $2
+RETURNING group_id, metric, monthly_hours, action, created_at, updated_at
+`
+
+type DeleteGroupUsageQuotaParams struct {
+	GroupID uuid.UUID        `db:"group_id" json:"group_id"`
+	Metric  UsageQuotaMetric `db:"metric" json:"metric"`
+}
+
+func (q *sqlQuerier) DeleteGroupUsageQuota(ctx context.Context, arg DeleteGroupUsageQuotaParams) (GroupUsageQuota, error) {
+	row := q.db.QueryRowContext(ctx, deleteGroupUsageQuota, arg.GroupID, arg.Metric)
+	var i GroupUsageQuota
+	err := row.Scan(
+		&i.GroupID,
+		&i.Metric,
+		&i.MonthlyHours,
+		&i.Action,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGroupUsageQuotas = `-- name: GetGroupUsageQuotas :many
+SELECT
+	group_id, metric, monthly_hours, action, created_at, updated_at
+FROM
+	group_usage_quotas
+WHERE
+	group_id = $1
+ORDER BY
+	metric
+`
+
+func (q *sqlQuerier) GetGroupUsageQuotas(ctx context.Context, groupID uuid.UUID) ([]GroupUsageQuota, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupUsageQuotas, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupUsageQuota
+	for rows.Next() {
+		var i GroupUsageQuota
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.Metric,
+			&i.MonthlyHours,
+			&i.Action,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
 SELECT
 	coalesce(SUM(groups.quota_allowance), 0)::BIGINT
@@ -23870,6 +25698,149 @@ func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaCo
 	return column_1, err
 }
 
+const getUsageQuotaAllowancesForUser = `-- name: GetUsageQuotaAllowancesForUser :many
+SELECT
+	group_usage_quotas.metric,
+	SUM(group_usage_quotas.monthly_hours)::BIGINT AS monthly_hours,
+	MAX(group_usage_quotas.action)::usage_quota_action AS action
+FROM
+	group_usage_quotas
+INNER JOIN group_members_expanded ON
+	group_members_expanded.group_id = group_usage_quotas.group_id
+WHERE
+	group_members_expanded.user_id = $1 AND
+	group_members_expanded.organization_id = $2
+GROUP BY
+	group_usage_quotas.metric
+ORDER BY
+	group_usage_quotas.metric
+`
+
+type GetUsageQuotaAllowancesForUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+type GetUsageQuotaAllowancesForUserRow struct {
+	Metric       UsageQuotaMetric `db:"metric" json:"metric"`
+	MonthlyHours int64            `db:"monthly_hours" json:"monthly_hours"`
+	Action       UsageQuotaAction `db:"action" json:"action"`
+}
+
+// Returns the monthly allowance of the user for every metric limited by any of
+// their groups in the organization. Like workspace quota allowances, the
+// allowances of all their groups are summed. The strictest action applies,
+// which is the last one in the enum. Metrics without a row are not limited.
+func (q *sqlQuerier) GetUsageQuotaAllowancesForUser(ctx context.Context, arg GetUsageQuotaAllowancesForUserParams) ([]GetUsageQuotaAllowancesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsageQuotaAllowancesForUser, arg.UserID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsageQuotaAllowancesForUserRow
+	for rows.Next() {
+		var i GetUsageQuotaAllowancesForUserRow
+		if err := rows.Scan(&i.Metric, &i.MonthlyHours, &i.Action); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsageQuotaConsumedForUser = `-- name: GetUsageQuotaConsumedForUser :one
+WITH
+	usage_stats AS (
+		SELECT
+			tus.start_time,
+			-- See motivation in GetTemplateInsights for LEAST(SUM(n), 30).
+			LEAST(SUM(tus.usage_mins), 30) AS active_mins,
+			SUM(
+				tus.ssh_mins + tus.sftp_mins + tus.reconnecting_pty_mins + tus.vscode_mins + tus.jetbrains_mins +
+				COALESCE((SELECT SUM(value::INTEGER) FROM jsonb_each_text(tus.app_usage_mins)), 0)
+			) AS connection_mins
+		FROM
+			template_usage_stats tus
+		INNER JOIN templates ON
+			templates.id = tus.template_id
+		WHERE
+			tus.user_id = $1 AND
+			templates.organization_id = $2 AND
+			tus.start_time >= $3::timestamptz
+		GROUP BY
+			tus.start_time
+	)
+SELECT
+	COALESCE(SUM(connection_mins), 0)::BIGINT AS connection_mins,
+	COALESCE(SUM(active_mins), 0)::BIGINT AS active_mins
+FROM
+	usage_stats
+`
+
+type GetUsageQuotaConsumedForUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	StartTime      time.Time `db:"start_time" json:"start_time"`
+}
+
+type GetUsageQuotaConsumedForUserRow struct {
+	ConnectionMins int64 `db:"connection_mins" json:"connection_mins"`
+	ActiveMins     int64 `db:"active_mins" json:"active_mins"`
+}
+
+// Returns the minutes the user has used workspaces of the organization since
+// the given time, from the usage stats rolled up from batched agent and app
+// stats. Connection minutes count every session separately, while active
+// minutes count the time the user had any session, across all templates.
+func (q *sqlQuerier) GetUsageQuotaConsumedForUser(ctx context.Context, arg GetUsageQuotaConsumedForUserParams) (GetUsageQuotaConsumedForUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getUsageQuotaConsumedForUser, arg.UserID, arg.OrganizationID, arg.StartTime)
+	var i GetUsageQuotaConsumedForUserRow
+	err := row.Scan(&i.ConnectionMins, &i.ActiveMins)
+	return i, err
+}
+
+const upsertGroupUsageQuota = `-- name: UpsertGroupUsageQuota :one
+INSERT INTO group_usage_quotas (group_id, metric, monthly_hours, action)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (group_id, metric) DO UPDATE SET
+	monthly_hours = EXCLUDED.monthly_hours,
+	action        = EXCLUDED.action,
+	updated_at    = NOW()
+RETURNING group_id, metric, monthly_hours, action, created_at, updated_at
+`
+
+type UpsertGroupUsageQuotaParams struct {
+	GroupID      uuid.UUID        `db:"group_id" json:"group_id"`
+	Metric       UsageQuotaMetric `db:"metric" json:"metric"`
+	MonthlyHours int32            `db:"monthly_hours" json:"monthly_hours"`
+	Action       UsageQuotaAction `db:"action" json:"action"`
+}
+
+func (q *sqlQuerier) UpsertGroupUsageQuota(ctx context.Context, arg UpsertGroupUsageQuotaParams) (GroupUsageQuota, error) {
+	row := q.db.QueryRowContext(ctx, upsertGroupUsageQuota,
+		arg.GroupID,
+		arg.Metric,
+		arg.MonthlyHours,
+		arg.Action,
+	)
+	var i GroupUsageQuota
+	err := row.Scan(
+		&i.GroupID,
+		&i.Metric,
+		&i.MonthlyHours,
+		&i.Action,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
 DELETE FROM replicas WHERE updated_at < $1
 `
@@ -24288,6 +26259,140 @@ func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleP
 	return i, err
 }
 
+const getScaletestResultByID = `-- name: GetScaletestResultByID :one
+SELECT id, created_at, created_by, name, total_runs, total_pass, total_fail, elapsed_ms, summary FROM scaletest_results WHERE id = $1
+`
+
+func (q *sqlQuerier) GetScaletestResultByID(ctx context.Context, id uuid.UUID) (ScaletestResult, error) {
+	row := q.db.QueryRowContext(ctx, getScaletestResultByID, id)
+	var i ScaletestResult
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Name,
+		&i.TotalRuns,
+		&i.TotalPass,
+		&i.TotalFail,
+		&i.ElapsedMs,
+		&i.Summary,
+	)
+	return i, err
+}
+
+const getScaletestResults = `-- name: GetScaletestResults :many
+SELECT
+	id, created_at, created_by, name, total_runs, total_pass, total_fail, elapsed_ms, summary
+FROM
+	scaletest_results
+WHERE
+	CASE
+		WHEN $1 :: text != '' THEN name = $1
+		ELSE true
+	END
+ORDER BY
+	created_at DESC, id DESC
+OFFSET $2
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($3 :: int, 0)
+`
+
+type GetScaletestResultsParams struct {
+	Name      string `db:"name" json:"name"`
+	OffsetOpt int32  `db:"offset_opt" json:"offset_opt"`
+	LimitOpt  int32  `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetScaletestResults(ctx context.Context, arg GetScaletestResultsParams) ([]ScaletestResult, error) {
+	rows, err := q.db.QueryContext(ctx, getScaletestResults, arg.Name, arg.OffsetOpt, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScaletestResult
+	for rows.Next() {
+		var i ScaletestResult
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.CreatedBy,
+			&i.Name,
+			&i.TotalRuns,
+			&i.TotalPass,
+			&i.TotalFail,
+			&i.ElapsedMs,
+			&i.Summary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertScaletestResult = `-- name: InsertScaletestResult :one
+INSERT INTO scaletest_results (
+	id,
+	created_at,
+	created_by,
+	name,
+	total_runs,
+	total_pass,
+	total_fail,
+	elapsed_ms,
+	summary
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, created_at, created_by, name, total_runs, total_pass, total_fail, elapsed_ms, summary
+`
+
+type InsertScaletestResultParams struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	CreatedBy uuid.UUID       `db:"created_by" json:"created_by"`
+	Name      string          `db:"name" json:"name"`
+	TotalRuns int32           `db:"total_runs" json:"total_runs"`
+	TotalPass int32           `db:"total_pass" json:"total_pass"`
+	TotalFail int32           `db:"total_fail" json:"total_fail"`
+	ElapsedMs int64           `db:"elapsed_ms" json:"elapsed_ms"`
+	Summary   json.RawMessage `db:"summary" json:"summary"`
+}
+
+func (q *sqlQuerier) InsertScaletestResult(ctx context.Context, arg InsertScaletestResultParams) (ScaletestResult, error) {
+	row := q.db.QueryRowContext(ctx, insertScaletestResult,
+		arg.ID,
+		arg.CreatedAt,
+		arg.CreatedBy,
+		arg.Name,
+		arg.TotalRuns,
+		arg.TotalPass,
+		arg.TotalFail,
+		arg.ElapsedMs,
+		arg.Summary,
+	)
+	var i ScaletestResult
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Name,
+		&i.TotalRuns,
+		&i.TotalPass,
+		&i.TotalFail,
+		&i.ElapsedMs,
+		&i.Summary,
+	)
+	return i, err
+}
+
 const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
 DELETE FROM site_configs
 WHERE site_configs.key = $1
@@ -24597,6 +26702,17 @@ func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const getDatabasePurgeStatus = `-- name: GetDatabasePurgeStatus :one
+SELECT value FROM site_configs WHERE key = 'database_purge_status'
+`
+
+func (q *sqlQuerier) GetDatabasePurgeStatus(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDatabasePurgeStatus)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
 SELECT
 	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
@@ -24992,6 +27108,16 @@ func (q *sqlQuerier) UpsertChatWorkspaceTTL(ctx context.Context, workspaceTtl st
 	return err
 }
 
+const upsertDatabasePurgeStatus = `-- name: UpsertDatabasePurgeStatus :exec
+INSERT INTO site_configs (key, value) VALUES ('database_purge_status', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'database_purge_status'
+`
+
+func (q *sqlQuerier) UpsertDatabasePurgeStatus(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertDatabasePurgeStatus, value)
+	return err
+}
+
 const upsertDefaultProxy = `-- name: UpsertDefaultProxy :exec
 INSERT INTO site_configs (key, value)
 VALUES
@@ -26390,7 +28516,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, templateID
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, dormancy_activity_signal, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names
 WHERE
@@ -26434,6 +28560,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.UseClassicParameterFlow,
 		&i.CorsBehavior,
 		&i.DisableModuleCache,
+		&i.DormancyActivitySignal,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
@@ -26446,7 +28573,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, dormancy_activity_signal, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -26498,6 +28625,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.UseClassicParameterFlow,
 		&i.CorsBehavior,
 		&i.DisableModuleCache,
+		&i.DormancyActivitySignal,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.CreatedByName,
@@ -26509,7 +28637,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, dormancy_activity_signal, created_by_avatar_url, created_by_username, created_by_name, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -26554,6 +28682,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.UseClassicParameterFlow,
 			&i.CorsBehavior,
 			&i.DisableModuleCache,
+			&i.DormancyActivitySignal,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -26576,7 +28705,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.cors_behavior, t.disable_module_cache, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
+	t.id, t.created_at, t.updated_at, t.organization_id, t.deleted, t.name, t.provisioner, t.active_version_id, t.description, t.default_ttl, t.created_by, t.icon, t.user_acl, t.group_acl, t.display_name, t.allow_user_cancel_workspace_jobs, t.allow_user_autostart, t.allow_user_autostop, t.failure_ttl, t.time_til_dormant, t.time_til_dormant_autodelete, t.autostop_requirement_days_of_week, t.autostop_requirement_weeks, t.autostart_block_days_of_week, t.require_active_version, t.deprecated, t.activity_bump, t.max_port_sharing_level, t.use_classic_parameter_flow, t.cors_behavior, t.disable_module_cache, t.dormancy_activity_signal, t.created_by_avatar_url, t.created_by_username, t.created_by_name, t.organization_name, t.organization_display_name, t.organization_icon
 FROM
 	template_with_names AS t
 LEFT JOIN
@@ -26736,6 +28865,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.UseClassicParameterFlow,
 			&i.CorsBehavior,
 			&i.DisableModuleCache,
+			&i.DormancyActivitySignal,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.CreatedByName,
@@ -26974,7 +29104,8 @@ SET
 	autostart_block_days_of_week = $9,
 	failure_ttl = $10,
 	time_til_dormant = $11,
-	time_til_dormant_autodelete = $12
+	time_til_dormant_autodelete = $12,
+	dormancy_activity_signal = $13
 WHERE
 	id = $1
 `
@@ -26992,6 +29123,7 @@ type UpdateTemplateScheduleByIDParams struct {
 	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
 	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
 	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+	DormancyActivitySignal        string    `db:"dormancy_activity_signal" json:"dormancy_activity_signal"`
 }
 
 func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
@@ -27008,6 +29140,7 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 		arg.FailureTTL,
 		arg.TimeTilDormant,
 		arg.TimeTilDormantAutoDelete,
+		arg.DormancyActivitySignal,
 	)
 	return err
 }
@@ -29907,6 +32040,41 @@ func (q *sqlQuerier) GetUserCount(ctx context.Context, includeSystem bool) (int6
 	return count, err
 }
 
+const getUserCountWithUsageSince = `-- name: GetUserCountWithUsageSince :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+	AND is_service_account = false
+	AND is_system = false
+	AND (
+		EXISTS (
+			SELECT 1 FROM workspace_agent_stats
+			WHERE workspace_agent_stats.user_id = users.id
+				AND workspace_agent_stats.usage = true
+				AND workspace_agent_stats.created_at >= $1::timestamptz
+		)
+		OR EXISTS (
+			SELECT 1 FROM template_usage_stats
+			WHERE template_usage_stats.user_id = users.id
+				AND template_usage_stats.start_time >= $1::timestamptz
+				AND template_usage_stats.usage_mins > 0
+		)
+	)
+`
+
+// Counts active users that used a workspace since the given time. Raw agent
+// stats are purged after a short retention period, so the rolled up template
+// usage stats are checked as well.
+func (q *sqlQuerier) GetUserCountWithUsageSince(ctx context.Context, since time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserCountWithUsageSince, since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getUserShellToolDisplayMode = `-- name: GetUserShellToolDisplayMode :one
 SELECT
 	value AS shell_tool_display_mode
@@ -31204,6 +33372,402 @@ func (q *sqlQuerier) ValidateUserIDs(ctx context.Context, userIds []uuid.UUID) (
 	return i, err
 }
 
+const acquireWebhookDeliveries = `-- name: AcquireWebhookDeliveries :many
+WITH acquired AS (
+	UPDATE
+		webhook_deliveries
+	SET
+		attempts = attempts + 1,
+		updated_at = $1 :: timestamptz,
+		next_attempt_at = $2 :: timestamptz
+	WHERE
+		id IN (
+			SELECT
+				wd.id
+			FROM
+				webhook_deliveries AS wd
+			WHERE
+				wd.status = 'pending' :: webhook_delivery_status
+				AND wd.next_attempt_at <= $1 :: timestamptz
+			ORDER BY
+				wd.next_attempt_at ASC
+			LIMIT
+				$3 :: int
+			FOR UPDATE OF wd
+				SKIP LOCKED
+		)
+	RETURNING id, webhook_id, created_at, updated_at, event, payload, status, attempts, next_attempt_at, response_status_code, error
+)
+SELECT
+	acquired.id,
+	acquired.webhook_id,
+	acquired.event,
+	acquired.payload,
+	acquired.attempts,
+	webhooks.url,
+	webhooks.secret,
+	webhooks.secret_key_id
+FROM
+	acquired
+JOIN
+	webhooks ON webhooks.id = acquired.webhook_id
+`
+
+type AcquireWebhookDeliveriesParams struct {
+	Now        time.Time `db:"now" json:"now"`
+	LeaseUntil time.Time `db:"lease_until" json:"lease_until"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type AcquireWebhookDeliveriesRow struct {
+	ID          uuid.UUID       `db:"id" json:"id"`
+	WebhookID   uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event       string          `db:"event" json:"event"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	Attempts    int32           `db:"attempts" json:"attempts"`
+	Url         string          `db:"url" json:"url"`
+	Secret      string          `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString  `db:"secret_key_id" json:"secret_key_id"`
+}
+
+// Acquires pending deliveries that are due, along with the webhook they are
+// delivered to. The next attempt of acquired deliveries is pushed forward to
+// the end of the lease, so that if the replica fails to record the outcome the
+// delivery is attempted again once the lease expires. SKIP LOCKED prevents
+// replicas from acquiring the same deliveries.
+func (q *sqlQuerier) AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]AcquireWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, acquireWebhookDeliveries, arg.Now, arg.LeaseUntil, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AcquireWebhookDeliveriesRow
+	for rows.Next() {
+		var i AcquireWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Attempts,
+			&i.Url,
+			&i.Secret,
+			&i.SecretKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteOldWebhookDeliveries = `-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM webhook_deliveries
+WHERE updated_at < NOW() - INTERVAL '30 days'
+`
+
+// Delete all webhook deliveries which have not been updated for over 30 days.
+func (q *sqlQuerier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWebhookDeliveries)
+	return err
+}
+
+const deleteWebhookByID = `-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookByID, id)
+	return err
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT id, created_at, created_by, template_id, url, secret, secret_key_id, events FROM webhooks WHERE id = $1
+`
+
+func (q *sqlQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.TemplateID,
+		&i.Url,
+		&i.Secret,
+		&i.SecretKeyID,
+		pq.Array(&i.Events),
+	)
+	return i, err
+}
+
+const getWebhookDeliveriesByWebhookID = `-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	id, webhook_id, created_at, updated_at, event, payload, status, attempts, next_attempt_at, response_status_code, error
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = $1
+ORDER BY
+	created_at DESC, id DESC
+OFFSET $2
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($3 :: int, 0)
+`
+
+type GetWebhookDeliveriesByWebhookIDParams struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	OffsetOpt int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveriesByWebhookID, arg.WebhookID, arg.OffsetOpt, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.ResponseStatusCode,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT id, created_at, created_by, template_id, url, secret, secret_key_id, events FROM webhooks ORDER BY created_at, id
+`
+
+func (q *sqlQuerier) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.CreatedBy,
+			&i.TemplateID,
+			&i.Url,
+			&i.Secret,
+			&i.SecretKeyID,
+			pq.Array(&i.Events),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWebhook = `-- name: InsertWebhook :one
+INSERT INTO webhooks (
+	id,
+	created_at,
+	created_by,
+	template_id,
+	url,
+	secret,
+	secret_key_id,
+	events
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8 :: text[])
+RETURNING id, created_at, created_by, template_id, url, secret, secret_key_id, events
+`
+
+type InsertWebhookParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	CreatedBy   uuid.UUID      `db:"created_by" json:"created_by"`
+	TemplateID  uuid.NullUUID  `db:"template_id" json:"template_id"`
+	Url         string         `db:"url" json:"url"`
+	Secret      string         `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	Events      []string       `db:"events" json:"events"`
+}
+
+func (q *sqlQuerier) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
+		arg.ID,
+		arg.CreatedAt,
+		arg.CreatedBy,
+		arg.TemplateID,
+		arg.Url,
+		arg.Secret,
+		arg.SecretKeyID,
+		pq.Array(arg.Events),
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.TemplateID,
+		&i.Url,
+		&i.Secret,
+		&i.SecretKeyID,
+		pq.Array(&i.Events),
+	)
+	return i, err
+}
+
+const insertWebhookDeliveries = `-- name: InsertWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+	id,
+	webhook_id,
+	created_at,
+	updated_at,
+	event,
+	payload,
+	next_attempt_at
+)
+SELECT
+	gen_random_uuid(),
+	webhooks.id,
+	$1 :: timestamptz,
+	$1 :: timestamptz,
+	$2 :: text,
+	$3 :: jsonb,
+	$1 :: timestamptz
+FROM
+	webhooks
+WHERE
+	$2 :: text = ANY(webhooks.events)
+	AND (webhooks.template_id IS NULL OR webhooks.template_id = $4 :: uuid)
+`
+
+type InsertWebhookDeliveriesParams struct {
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	Event      string          `db:"event" json:"event"`
+	Payload    json.RawMessage `db:"payload" json:"payload"`
+	TemplateID uuid.UUID       `db:"template_id" json:"template_id"`
+}
+
+// Enqueues a delivery of the event to every webhook subscribed to it, either
+// for all workspaces or for the template of the workspace the event is about.
+func (q *sqlQuerier) InsertWebhookDeliveries(ctx context.Context, arg InsertWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertWebhookDeliveries,
+		arg.CreatedAt,
+		arg.Event,
+		arg.Payload,
+		arg.TemplateID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateEncryptedWebhookSecret = `-- name: UpdateEncryptedWebhookSecret :one
+UPDATE
+	webhooks
+SET
+	secret = $1 :: text,
+	secret_key_id = $2 :: text
+WHERE
+	id = $3
+RETURNING id, created_at, created_by, template_id, url, secret, secret_key_id, events
+`
+
+type UpdateEncryptedWebhookSecretParams struct {
+	Secret      string         `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	ID          uuid.UUID      `db:"id" json:"id"`
+}
+
+// Updates only the encrypted columns (secret, secret_key_id) of a webhook.
+// Used by the dbcrypt key rotation utility to re-encrypt or decrypt rows in
+// place.
+func (q *sqlQuerier) UpdateEncryptedWebhookSecret(ctx context.Context, arg UpdateEncryptedWebhookSecretParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateEncryptedWebhookSecret, arg.Secret, arg.SecretKeyID, arg.ID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.TemplateID,
+		&i.Url,
+		&i.Secret,
+		&i.SecretKeyID,
+		pq.Array(&i.Events),
+	)
+	return i, err
+}
+
+const updateWebhookDeliveryByID = `-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = $1,
+	updated_at = $2,
+	next_attempt_at = $3,
+	response_status_code = $4,
+	error = $5
+WHERE
+	id = $6
+`
+
+type UpdateWebhookDeliveryByIDParams struct {
+	Status             WebhookDeliveryStatus `db:"status" json:"status"`
+	UpdatedAt          time.Time             `db:"updated_at" json:"updated_at"`
+	NextAttemptAt      time.Time             `db:"next_attempt_at" json:"next_attempt_at"`
+	ResponseStatusCode sql.NullInt32         `db:"response_status_code" json:"response_status_code"`
+	Error              sql.NullString        `db:"error" json:"error"`
+	ID                 uuid.UUID             `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDeliveryByID,
+		arg.Status,
+		arg.UpdatedAt,
+		arg.NextAttemptAt,
+		arg.ResponseStatusCode,
+		arg.Error,
+		arg.ID,
+	)
+	return err
+}
+
 const deleteStaleWorkspaceAgentContextResources = `-- name: DeleteStaleWorkspaceAgentContextResources :exec
 DELETE FROM workspace_agent_context_resources
 WHERE workspace_agent_id = $1
@@ -32002,6 +34566,153 @@ func (q *sqlQuerier) UpdateVolumeResourceMonitor(ctx context.Context, arg Update
 	return err
 }
 
+const deleteOldWorkspaceAgentResourceSamples = `-- name: DeleteOldWorkspaceAgentResourceSamples :exec
+DELETE FROM workspace_agent_resource_samples
+WHERE collected_at < NOW() - INTERVAL '30 days'
+`
+
+// Delete all workspace agent resource samples collected over 30 days ago.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentResourceSamples(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentResourceSamples)
+	return err
+}
+
+const getWorkspaceAgentResourceUsage = `-- name: GetWorkspaceAgentResourceUsage :many
+SELECT
+	wa.name AS agent_name,
+	wars.resource,
+	COUNT(*) AS samples,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY wars.used)::float AS used_p95,
+	MAX(wars.used)::float AS used_max,
+	(array_agg(wars.total ORDER BY wars.collected_at DESC))[1]::float AS total
+FROM
+	workspace_agent_resource_samples AS wars
+JOIN
+	workspace_agents AS wa
+ON
+	wa.id = wars.agent_id
+WHERE
+	wars.workspace_id = $1
+	AND wars.collected_at >= $2::timestamptz
+	AND wars.collected_at < $3::timestamptz
+GROUP BY
+	wa.name, wars.resource
+ORDER BY
+	wa.name, wars.resource
+`
+
+type GetWorkspaceAgentResourceUsageParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	StartTime   time.Time `db:"start_time" json:"start_time"`
+	EndTime     time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceAgentResourceUsageRow struct {
+	AgentName string  `db:"agent_name" json:"agent_name"`
+	Resource  string  `db:"resource" json:"resource"`
+	Samples   int64   `db:"samples" json:"samples"`
+	UsedP95   float64 `db:"used_p95" json:"used_p95"`
+	UsedMax   float64 `db:"used_max" json:"used_max"`
+	Total     float64 `db:"total" json:"total"`
+}
+
+// Returns the CPU and memory usage of the agents of a workspace between
+// start_time and end_time. Agents are grouped by name, so that the samples of
+// the agents of all builds are included. The total is the latest total of the
+// resource reported by the agent.
+func (q *sqlQuerier) GetWorkspaceAgentResourceUsage(ctx context.Context, arg GetWorkspaceAgentResourceUsageParams) ([]GetWorkspaceAgentResourceUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentResourceUsage, arg.WorkspaceID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentResourceUsageRow
+	for rows.Next() {
+		var i GetWorkspaceAgentResourceUsageRow
+		if err := rows.Scan(
+			&i.AgentName,
+			&i.Resource,
+			&i.Samples,
+			&i.UsedP95,
+			&i.UsedMax,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentResourceSamples = `-- name: InsertWorkspaceAgentResourceSamples :exec
+INSERT INTO workspace_agent_resource_samples (
+	agent_id,
+	workspace_id,
+	resource,
+	collected_at,
+	used,
+	total
+)
+SELECT
+	wam.workspace_agent_id,
+	wb.workspace_id,
+	CASE WHEN m.parts[4] = 'cores' THEN 'cpu' ELSE 'memory' END,
+	wam.collected_at,
+	m.parts[1]::float * m.scale,
+	m.parts[2]::float * m.scale
+FROM
+	workspace_agent_metadata AS wam
+JOIN
+	workspace_agents AS wa
+ON
+	wa.id = wam.workspace_agent_id
+JOIN
+	workspace_resources AS wr
+ON
+	wr.id = wa.resource_id
+JOIN
+	workspace_builds AS wb
+ON
+	wb.job_id = wr.job_id
+CROSS JOIN LATERAL (
+	SELECT
+		p.parts,
+		CASE p.parts[3]
+			WHEN 'Ki' THEN 1024::float
+			WHEN 'Mi' THEN 1048576::float
+			WHEN 'Gi' THEN 1073741824::float
+			WHEN 'Ti' THEN 1099511627776::float
+			ELSE 1::float
+		END AS scale
+	FROM
+		(SELECT regexp_match(wam.value, '^([0-9]+(?:\.[0-9]+)?)/([0-9]+(?:\.[0-9]+)?) (Ki|Mi|Gi|Ti)?(cores|B)\M') AS parts) AS p
+) AS m
+WHERE
+	wam.script ~ '^coder stat (cpu|mem)\M'
+	AND wam.script !~ '--host'
+	AND wam.error = ''
+	AND m.parts IS NOT NULL
+ON CONFLICT
+	(agent_id, resource, collected_at)
+DO NOTHING
+`
+
+// Samples the CPU and memory usage agents report through the "coder stat cpu"
+// and "coder stat mem" metadata scripts, e.g. "1.2/4 cores (30%)" and
+// "2.5/7.7 GiB (32%)". Host usage (--host) is not sampled, since it is not
+// what the workspace is sized by. Every collected value is only sampled once,
+// so the stale metadata of disconnected agents does not skew the samples.
+func (q *sqlQuerier) InsertWorkspaceAgentResourceSamples(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentResourceSamples)
+	return err
+}
+
 const batchUpdateWorkspaceAgentMetadata = `-- name: BatchUpdateWorkspaceAgentMetadata :exec
 WITH metadata AS (
 	SELECT
@@ -33970,11 +36681,12 @@ func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg Up
 	return err
 }
 
-const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
+const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :execrows
 DELETE FROM
 	workspace_agent_stats
 WHERE
-	created_at < (
+	created_at < $1::timestamptz
+	AND created_at < (
 		SELECT
 			COALESCE(
 				-- When generating initial template usage stats, all the
@@ -34002,9 +36714,27 @@ WHERE
 	)
 `
 
-func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
-	return err
+func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats, beforeTime)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOldWorkspaceClientNetworkStats = `-- name: DeleteOldWorkspaceClientNetworkStats :execrows
+DELETE FROM
+	workspace_client_network_stats
+WHERE
+	created_at < $1::timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceClientNetworkStats(ctx context.Context, beforeTime time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceClientNetworkStats, beforeTime)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getDeploymentWorkspaceAgentStats = `-- name: GetDeploymentWorkspaceAgentStats :one
@@ -34145,6 +36875,235 @@ func (q *sqlQuerier) GetDeploymentWorkspaceAgentUsageStats(ctx context.Context,
 	return i, err
 }
 
+const getOldestWorkspaceAgentStatCreatedAt = `-- name: GetOldestWorkspaceAgentStatCreatedAt :one
+SELECT
+	COALESCE(MIN(created_at), NOW())::timestamptz AS oldest_created_at
+FROM
+	workspace_agent_stats
+`
+
+// Returns the creation time of the oldest raw workspace agent stat, or the
+// current time if there are none.
+func (q *sqlQuerier) GetOldestWorkspaceAgentStatCreatedAt(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getOldestWorkspaceAgentStatCreatedAt)
+	var oldest_created_at time.Time
+	err := row.Scan(&oldest_created_at)
+	return oldest_created_at, err
+}
+
+const getWorkspaceAgentConnectionLatencyByClientRegion = `-- name: GetWorkspaceAgentConnectionLatencyByClientRegion :many
+SELECT
+	cn.client_region,
+	cn.client_asn,
+	COUNT(DISTINCT cn.user_id) AS user_count,
+	COUNT(DISTINCT was.agent_id) AS agent_count,
+	(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_50,
+	(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_90,
+	(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_95,
+	(PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_99
+FROM
+	workspace_agent_stats AS was
+JOIN LATERAL (
+	SELECT
+		wcns.user_id,
+		wcns.client_region,
+		wcns.client_asn
+	FROM
+		workspace_client_network_stats AS wcns
+	WHERE
+		wcns.agent_id = was.agent_id
+		AND wcns.created_at <= was.created_at
+		AND wcns.created_at > was.created_at - '1 hour'::interval
+	ORDER BY
+		wcns.created_at DESC
+	LIMIT 1
+) AS cn ON TRUE
+WHERE
+	was.created_at >= $1::timestamptz
+	AND was.created_at < $2::timestamptz
+	AND was.connection_median_latency_ms > 0
+GROUP BY
+	cn.client_region, cn.client_asn
+ORDER BY
+	cn.client_region, cn.client_asn
+`
+
+type GetWorkspaceAgentConnectionLatencyByClientRegionParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceAgentConnectionLatencyByClientRegionRow struct {
+	ClientRegion                 string  `db:"client_region" json:"client_region"`
+	ClientAsn                    int64   `db:"client_asn" json:"client_asn"`
+	UserCount                    int64   `db:"user_count" json:"user_count"`
+	AgentCount                   int64   `db:"agent_count" json:"agent_count"`
+	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency90 float64 `db:"workspace_connection_latency_90" json:"workspace_connection_latency_90"`
+	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	WorkspaceConnectionLatency99 float64 `db:"workspace_connection_latency_99" json:"workspace_connection_latency_99"`
+}
+
+// Aggregates the connection latency reported by workspace agents by the region
+// and network of their clients. Each stat is attributed to the client that most
+// recently connected to the agent within the hour before the stat was
+// reported. Stats that cannot be attributed to a client are left out.
+func (q *sqlQuerier) GetWorkspaceAgentConnectionLatencyByClientRegion(ctx context.Context, arg GetWorkspaceAgentConnectionLatencyByClientRegionParams) ([]GetWorkspaceAgentConnectionLatencyByClientRegionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentConnectionLatencyByClientRegion, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentConnectionLatencyByClientRegionRow
+	for rows.Next() {
+		var i GetWorkspaceAgentConnectionLatencyByClientRegionRow
+		if err := rows.Scan(
+			&i.ClientRegion,
+			&i.ClientAsn,
+			&i.UserCount,
+			&i.AgentCount,
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency90,
+			&i.WorkspaceConnectionLatency95,
+			&i.WorkspaceConnectionLatency99,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentConnectionLatencyByRegion = `-- name: GetWorkspaceAgentConnectionLatencyByRegion :many
+WITH agent_regions AS (
+	SELECT
+		unnest($1::uuid[]) AS agent_id,
+		unnest($2::int[]) AS region_id
+)
+SELECT
+	COALESCE(agent_regions.region_id, 0)::int AS region_id,
+	COUNT(DISTINCT was.agent_id) AS agent_count,
+	(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_50,
+	(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_90,
+	(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_95,
+	(PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_99
+FROM
+	workspace_agent_stats AS was
+LEFT JOIN
+	agent_regions
+ON
+	agent_regions.agent_id = was.agent_id
+WHERE
+	was.created_at >= $3::timestamptz
+	AND was.created_at < $4::timestamptz
+	AND was.connection_median_latency_ms > 0
+GROUP BY
+	agent_regions.region_id
+ORDER BY
+	region_id
+`
+
+type GetWorkspaceAgentConnectionLatencyByRegionParams struct {
+	AgentIDs  []uuid.UUID `db:"agent_ids" json:"agent_ids"`
+	RegionIDs []int32     `db:"region_ids" json:"region_ids"`
+	StartTime time.Time   `db:"start_time" json:"start_time"`
+	EndTime   time.Time   `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceAgentConnectionLatencyByRegionRow struct {
+	RegionID                     int32   `db:"region_id" json:"region_id"`
+	AgentCount                   int64   `db:"agent_count" json:"agent_count"`
+	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency90 float64 `db:"workspace_connection_latency_90" json:"workspace_connection_latency_90"`
+	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	WorkspaceConnectionLatency99 float64 `db:"workspace_connection_latency_99" json:"workspace_connection_latency_99"`
+}
+
+// Aggregates the connection latency reported by workspace agents by their
+// preferred DERP region. Only the tailnet coordinator knows which region an
+// agent prefers, so the mapping is passed in as parallel arrays. Agents missing
+// from the mapping are grouped under region 0.
+func (q *sqlQuerier) GetWorkspaceAgentConnectionLatencyByRegion(ctx context.Context, arg GetWorkspaceAgentConnectionLatencyByRegionParams) ([]GetWorkspaceAgentConnectionLatencyByRegionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentConnectionLatencyByRegion,
+		pq.Array(arg.AgentIDs),
+		pq.Array(arg.RegionIDs),
+		arg.StartTime,
+		arg.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentConnectionLatencyByRegionRow
+	for rows.Next() {
+		var i GetWorkspaceAgentConnectionLatencyByRegionRow
+		if err := rows.Scan(
+			&i.RegionID,
+			&i.AgentCount,
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency90,
+			&i.WorkspaceConnectionLatency95,
+			&i.WorkspaceConnectionLatency99,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentIDsWithConnectionLatency = `-- name: GetWorkspaceAgentIDsWithConnectionLatency :many
+SELECT DISTINCT
+	agent_id
+FROM
+	workspace_agent_stats
+WHERE
+	created_at >= $1::timestamptz
+	AND created_at < $2::timestamptz
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	AND connection_median_latency_ms > 0
+`
+
+type GetWorkspaceAgentIDsWithConnectionLatencyParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentIDsWithConnectionLatency(ctx context.Context, arg GetWorkspaceAgentIDsWithConnectionLatencyParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentIDsWithConnectionLatency, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var agent_id uuid.UUID
+		if err := rows.Scan(&agent_id); err != nil {
+			return nil, err
+		}
+		items = append(items, agent_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
 WITH agent_stats AS (
 	SELECT
@@ -34333,6 +37292,110 @@ func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, create
 	return items, nil
 }
 
+const getWorkspaceAgentStatsTimeSeries = `-- name: GetWorkspaceAgentStatsTimeSeries :many
+WITH buckets AS (
+	SELECT
+		generate_series(
+			$1::timestamptz,
+			$2::timestamptz - '1 microsecond'::interval,
+			make_interval(secs => $3::int)
+		) AS bucket_start
+)
+SELECT
+	buckets.bucket_start::timestamptz AS bucket_start,
+	COUNT(was.id) AS report_count,
+	COALESCE(SUM(was.rx_bytes), 0)::bigint AS rx_bytes,
+	COALESCE(SUM(was.tx_bytes), 0)::bigint AS tx_bytes,
+	COALESCE(SUM(was.rx_packets), 0)::bigint AS rx_packets,
+	COALESCE(SUM(was.tx_packets), 0)::bigint AS tx_packets,
+	COALESCE(AVG(was.connection_count), 0)::float AS connection_count,
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	COALESCE(AVG(was.connection_median_latency_ms) FILTER (WHERE was.connection_median_latency_ms > 0), 0)::float AS connection_median_latency_ms,
+	COALESCE(AVG(was.session_count_vscode), 0)::float AS session_count_vscode,
+	COALESCE(AVG(was.session_count_jetbrains), 0)::float AS session_count_jetbrains,
+	COALESCE(AVG(was.session_count_reconnecting_pty), 0)::float AS session_count_reconnecting_pty,
+	COALESCE(AVG(was.session_count_ssh), 0)::float AS session_count_ssh
+FROM
+	buckets
+LEFT JOIN
+	workspace_agent_stats AS was
+ON
+	was.agent_id = $4::uuid
+	AND was.created_at >= buckets.bucket_start
+	AND was.created_at < LEAST(buckets.bucket_start + make_interval(secs => $3::int), $2::timestamptz)
+GROUP BY
+	buckets.bucket_start
+ORDER BY
+	buckets.bucket_start
+`
+
+type GetWorkspaceAgentStatsTimeSeriesParams struct {
+	StartTime     time.Time `db:"start_time" json:"start_time"`
+	EndTime       time.Time `db:"end_time" json:"end_time"`
+	BucketSeconds int32     `db:"bucket_seconds" json:"bucket_seconds"`
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+}
+
+type GetWorkspaceAgentStatsTimeSeriesRow struct {
+	BucketStart                 time.Time `db:"bucket_start" json:"bucket_start"`
+	ReportCount                 int64     `db:"report_count" json:"report_count"`
+	RxBytes                     int64     `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64     `db:"tx_bytes" json:"tx_bytes"`
+	RxPackets                   int64     `db:"rx_packets" json:"rx_packets"`
+	TxPackets                   int64     `db:"tx_packets" json:"tx_packets"`
+	ConnectionCount             float64   `db:"connection_count" json:"connection_count"`
+	ConnectionMedianLatencyMS   float64   `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+	SessionCountVSCode          float64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       float64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY float64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountSSH             float64   `db:"session_count_ssh" json:"session_count_ssh"`
+}
+
+// Downsamples the stats reported by a workspace agent into fixed size buckets
+// starting at start_time. Counters are summed and gauges are averaged within
+// each bucket. Buckets without any stats are returned with zero values so
+// callers don't have to fill the gaps themselves.
+func (q *sqlQuerier) GetWorkspaceAgentStatsTimeSeries(ctx context.Context, arg GetWorkspaceAgentStatsTimeSeriesParams) ([]GetWorkspaceAgentStatsTimeSeriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStatsTimeSeries,
+		arg.StartTime,
+		arg.EndTime,
+		arg.BucketSeconds,
+		arg.AgentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentStatsTimeSeriesRow
+	for rows.Next() {
+		var i GetWorkspaceAgentStatsTimeSeriesRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.ReportCount,
+			&i.RxBytes,
+			&i.TxBytes,
+			&i.RxPackets,
+			&i.TxPackets,
+			&i.ConnectionCount,
+			&i.ConnectionMedianLatencyMS,
+			&i.SessionCountVSCode,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+			&i.SessionCountSSH,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentUsageStats = `-- name: GetWorkspaceAgentUsageStats :many
 WITH agent_stats AS (
 	SELECT
@@ -34650,6 +37713,44 @@ func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWo
 	return err
 }
 
+const insertWorkspaceClientNetworkStat = `-- name: InsertWorkspaceClientNetworkStat :exec
+INSERT INTO
+	workspace_client_network_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		agent_id,
+		client_region,
+		client_asn
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertWorkspaceClientNetworkStatParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	ClientRegion string    `db:"client_region" json:"client_region"`
+	ClientAsn    int64     `db:"client_asn" json:"client_asn"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceClientNetworkStat(ctx context.Context, arg InsertWorkspaceClientNetworkStatParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceClientNetworkStat,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.ClientRegion,
+		arg.ClientAsn,
+	)
+	return err
+}
+
 const upsertWorkspaceAppAuditSession = `-- name: UpsertWorkspaceAppAuditSession :one
 INSERT INTO
 	workspace_app_audit_sessions (
@@ -37544,7 +40645,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, cors_behavior, disable_module_cache, dormancy_activity_signal
 	FROM
 		templates
 	WHERE
@@ -38976,3 +42077,169 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const getWorkspaceActivityStats = `-- name: GetWorkspaceActivityStats :many
+WITH agent_stats AS (
+	SELECT
+		workspace_id,
+		agent_id,
+		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes,
+		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+		coalesce(MAX(session_count_vscode + session_count_jetbrains + session_count_reconnecting_pty + session_count_ssh), 0)::bigint AS sessions
+	FROM
+		workspace_agent_stats
+	WHERE
+		created_at >= $1
+		AND created_at < $2
+	GROUP BY
+		workspace_id, agent_id
+)
+SELECT
+	agent_stats.workspace_id,
+	SUM(agent_stats.tx_bytes)::bigint AS tx_bytes,
+	SUM(agent_stats.rx_bytes)::bigint AS rx_bytes,
+	SUM(agent_stats.sessions)::bigint AS sessions
+FROM
+	agent_stats
+JOIN
+	workspaces ON workspaces.id = agent_stats.workspace_id
+WHERE
+	NOT workspaces.deleted
+GROUP BY
+	agent_stats.workspace_id
+`
+
+type GetWorkspaceActivityStatsParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetWorkspaceActivityStatsRow struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TxBytes     int64     `db:"tx_bytes" json:"tx_bytes"`
+	RxBytes     int64     `db:"rx_bytes" json:"rx_bytes"`
+	Sessions    int64     `db:"sessions" json:"sessions"`
+}
+
+// Returns the traffic of each workspace with agent stats between start_time
+// and end_time, and the most sessions its agents had open at once.
+func (q *sqlQuerier) GetWorkspaceActivityStats(ctx context.Context, arg GetWorkspaceActivityStatsParams) ([]GetWorkspaceActivityStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceActivityStats, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceActivityStatsRow
+	for rows.Next() {
+		var i GetWorkspaceActivityStatsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.TxBytes,
+			&i.RxBytes,
+			&i.Sessions,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceStatBaselines = `-- name: GetWorkspaceStatBaselines :many
+SELECT
+	workspace_id, metric, samples, mean, variance, updated_at, flagged_at
+FROM
+	workspace_stat_baselines
+WHERE
+	workspace_id = ANY($1::uuid[])
+`
+
+func (q *sqlQuerier) GetWorkspaceStatBaselines(ctx context.Context, workspaceIds []uuid.UUID) ([]WorkspaceStatBaseline, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceStatBaselines, pq.Array(workspaceIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceStatBaseline
+	for rows.Next() {
+		var i WorkspaceStatBaseline
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.Metric,
+			&i.Samples,
+			&i.Mean,
+			&i.Variance,
+			&i.UpdatedAt,
+			&i.FlaggedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceStatBaseline = `-- name: UpsertWorkspaceStatBaseline :exec
+INSERT INTO workspace_stat_baselines (
+	workspace_id,
+	metric,
+	samples,
+	mean,
+	variance,
+	updated_at,
+	flagged_at
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7
+)
+ON CONFLICT (workspace_id, metric) DO UPDATE SET
+	samples = EXCLUDED.samples,
+	mean = EXCLUDED.mean,
+	variance = EXCLUDED.variance,
+	updated_at = EXCLUDED.updated_at,
+	flagged_at = EXCLUDED.flagged_at
+WHERE
+	workspace_stat_baselines.updated_at < EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceStatBaselineParams struct {
+	WorkspaceID uuid.UUID    `db:"workspace_id" json:"workspace_id"`
+	Metric      string       `db:"metric" json:"metric"`
+	Samples     int32        `db:"samples" json:"samples"`
+	Mean        float64      `db:"mean" json:"mean"`
+	Variance    float64      `db:"variance" json:"variance"`
+	UpdatedAt   time.Time    `db:"updated_at" json:"updated_at"`
+	FlaggedAt   sql.NullTime `db:"flagged_at" json:"flagged_at"`
+}
+
+// Baselines are only moved forward, so that an hour is not folded into a
+// baseline twice.
+func (q *sqlQuerier) UpsertWorkspaceStatBaseline(ctx context.Context, arg UpsertWorkspaceStatBaselineParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceStatBaseline,
+		arg.WorkspaceID,
+		arg.Metric,
+		arg.Samples,
+		arg.Mean,
+		arg.Variance,
+		arg.UpdatedAt,
+		arg.FlaggedAt,
+	)
+	return err
+}
@@ -281,3 +281,41 @@ WITH old_logs AS (
 DELETE FROM audit_logs
 USING old_logs
 WHERE audit_logs.id = old_logs.id;
+
+-- name: GetAuditLogsForExport :many
+-- Pages through the audit logs before end_time in the order they are
+-- exported. Pass the time and ID of the last audit log of the previous page
+-- to get the next one.
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	(audit_logs.time, audit_logs.id) > (@after_time::timestamptz, @after_id::uuid)
+	AND audit_logs.time < @end_time::timestamptz
+ORDER BY
+	audit_logs.time, audit_logs.id
+LIMIT
+	@limit_count::int;
+
+-- name: GetAuditLogExport :one
+SELECT
+	*
+FROM
+	audit_log_exports
+WHERE
+	dataset = @dataset;
+
+-- name: UpsertAuditLogExport :exec
+INSERT INTO audit_log_exports (
+	dataset,
+	exported_until,
+	updated_at
+) VALUES (
+	@dataset,
+	@exported_until,
+	@updated_at
+)
+ON CONFLICT (dataset) DO UPDATE SET
+	exported_until = EXCLUDED.exported_until,
+	updated_at = EXCLUDED.updated_at;
@@ -0,0 +1,156 @@
+-- name: GetTemplateIdlePolicy :one
+SELECT
+	*
+FROM
+	template_idle_policies
+WHERE
+	template_id = @template_id;
+
+-- name: UpsertTemplateIdlePolicy :one
+INSERT INTO template_idle_policies (template_id, idle_timeout_ms, action)
+VALUES (@template_id, @idle_timeout_ms, @action)
+ON CONFLICT (template_id) DO UPDATE SET
+	idle_timeout_ms = EXCLUDED.idle_timeout_ms,
+	action          = EXCLUDED.action,
+	updated_at      = NOW()
+RETURNING *;
+
+-- name: DeleteTemplateIdlePolicy :one
+DELETE FROM template_idle_policies
+WHERE template_id = @template_id
+RETURNING *;
+
+-- name: GetIdleWorkspaces :many
+-- Returns the running workspaces of templates with an idle policy that have
+-- not been active within the idle timeout of the policy. A workspace is active
+-- while any of its agents reports a session. The start of the latest build
+-- also counts as activity so that workspaces aren't reaped right after they
+-- start. The last used time of the workspace is ignored, since it is also bumped
+-- by activity that isn't an agent session. Flags older than the last activity
+-- are ignored. A nil template ID returns idle workspaces across all templates.
+WITH last_sessions AS (
+	SELECT
+		workspace_id,
+		MAX(created_at) AS last_session_at
+	FROM
+		workspace_agent_stats
+	WHERE
+		template_id IN (SELECT template_id FROM template_idle_policies) AND
+		(session_count_vscode + session_count_jetbrains + session_count_reconnecting_pty + session_count_ssh) > 0
+	GROUP BY
+		workspace_id
+), activity AS (
+	SELECT
+		workspaces.id AS workspace_id,
+		GREATEST(workspace_latest_builds.created_at, last_sessions.last_session_at)::timestamptz AS last_active_at
+	FROM
+		workspaces
+	INNER JOIN workspace_latest_builds ON
+		workspace_latest_builds.workspace_id = workspaces.id
+	LEFT JOIN last_sessions ON
+		last_sessions.workspace_id = workspaces.id
+	WHERE
+		workspaces.deleted = false AND
+		workspaces.dormant_at IS NULL AND
+		workspace_latest_builds.transition = 'start'::workspace_transition AND
+		workspace_latest_builds.job_status = 'succeeded'::provisioner_job_status AND
+		-- Prebuilt workspaces are handled by the prebuilds reconciliation loop.
+		workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
+)
+SELECT
+	workspaces_expanded.id AS workspace_id,
+	workspaces_expanded.name AS workspace_name,
+	workspaces_expanded.owner_id,
+	workspaces_expanded.owner_username,
+	workspaces_expanded.organization_id,
+	workspaces_expanded.template_id,
+	workspaces_expanded.template_name,
+	template_idle_policies.idle_timeout_ms,
+	template_idle_policies.action,
+	activity.last_active_at,
+	workspace_idle_flags.flagged_at
+FROM
+	activity
+INNER JOIN workspaces_expanded ON
+	workspaces_expanded.id = activity.workspace_id
+INNER JOIN template_idle_policies ON
+	template_idle_policies.template_id = workspaces_expanded.template_id
+LEFT JOIN workspace_idle_flags ON
+	workspace_idle_flags.workspace_id = activity.workspace_id AND
+	workspace_idle_flags.flagged_at >= activity.last_active_at
+WHERE
+	activity.last_active_at < @now::timestamptz - (INTERVAL '1 millisecond' * template_idle_policies.idle_timeout_ms) AND
+	CASE
+		WHEN @template_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspaces_expanded.template_id = @template_id
+		ELSE true
+	END
+ORDER BY
+	activity.last_active_at ASC,
+	workspaces_expanded.id ASC;
+
+-- name: UpsertWorkspaceIdleFlag :exec
+INSERT INTO workspace_idle_flags (workspace_id, flagged_at)
+VALUES (@workspace_id, @flagged_at)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	flagged_at = EXCLUDED.flagged_at;
+
+-- name: GetUnusedWorkspaces :many
+-- Returns the workspaces that have not been used since the given time and are
+-- not yet dormant. A workspace is used while any of its agents reports a
+-- session. Workspaces whose owner was already notified after the last use are
+-- omitted.
+WITH last_sessions AS (
+	SELECT
+		workspace_id,
+		MAX(created_at) AS last_session_at
+	FROM
+		workspace_agent_stats
+	WHERE
+		(session_count_vscode + session_count_jetbrains + session_count_reconnecting_pty + session_count_ssh) > 0
+	GROUP BY
+		workspace_id
+), activity AS (
+	SELECT
+		workspaces.id AS workspace_id,
+		GREATEST(workspaces.last_used_at, last_sessions.last_session_at)::timestamptz AS last_used_at
+	FROM
+		workspaces
+	LEFT JOIN last_sessions ON
+		last_sessions.workspace_id = workspaces.id
+	WHERE
+		workspaces.deleted = false AND
+		workspaces.dormant_at IS NULL AND
+		-- Prebuilt workspaces are handled by the prebuilds reconciliation loop.
+		workspaces.owner_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::UUID
+)
+SELECT
+	workspaces_expanded.id AS workspace_id,
+	workspaces_expanded.name AS workspace_name,
+	workspaces_expanded.owner_id,
+	workspaces_expanded.owner_username,
+	workspaces_expanded.organization_id,
+	workspaces_expanded.template_id,
+	templates.time_til_dormant,
+	activity.last_used_at
+FROM
+	activity
+INNER JOIN workspaces_expanded ON
+	workspaces_expanded.id = activity.workspace_id
+INNER JOIN templates ON
+	templates.id = workspaces_expanded.template_id
+LEFT JOIN workspace_unused_notifications ON
+	workspace_unused_notifications.workspace_id = activity.workspace_id AND
+	workspace_unused_notifications.notified_at >= activity.last_used_at
+WHERE
+	activity.last_used_at < @unused_since::timestamptz AND
+	workspace_unused_notifications.workspace_id IS NULL
+ORDER BY
+	activity.last_used_at ASC,
+	workspaces_expanded.id ASC;
+
+-- name: UpsertWorkspaceUnusedNotification :exec
+INSERT INTO workspace_unused_notifications (workspace_id, notified_at)
+VALUES (@workspace_id, @notified_at)
+ON CONFLICT (workspace_id) DO UPDATE SET
+	notified_at = EXCLUDED.notified_at;
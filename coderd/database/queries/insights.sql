@@ -81,6 +81,67 @@ GROUP BY
 ORDER BY
 	ds.user_id ASC;
 
+-- name: GetUserBandwidthInsights :many
+-- GetUserBandwidthInsights returns the bytes received and sent by the
+-- workspace agents of each user, broken down by template. Users are ordered
+-- by their total bytes, heaviest first, and paginated as a whole so all rows
+-- of a user are returned together. user_count is the number of users across
+-- all pages. Raw agent stats are deleted once they are rolled up, so only the
+-- period they are retained for is covered.
+WITH
+	template_bandwidth AS (
+		SELECT
+			user_id,
+			template_id,
+			SUM(rx_bytes)::bigint AS rx_bytes,
+			SUM(tx_bytes)::bigint AS tx_bytes
+		FROM
+			workspace_agent_stats
+		WHERE
+			created_at >= @start_time::timestamptz
+			AND created_at < @end_time::timestamptz
+			AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+		GROUP BY
+			user_id, template_id
+	),
+	user_bandwidth AS (
+		SELECT
+			user_id,
+			SUM(rx_bytes + tx_bytes) AS total_bytes,
+			COUNT(*) OVER () AS user_count
+		FROM
+			template_bandwidth
+		GROUP BY
+			user_id
+		ORDER BY
+			total_bytes DESC, user_id ASC
+		OFFSET @offset_opt
+		LIMIT
+			-- A null limit means "no limit", so 0 means return all
+			NULLIF(@limit_opt :: int, 0)
+	)
+
+SELECT
+	tb.user_id,
+	u.username,
+	u.avatar_url,
+	tb.template_id,
+	tb.rx_bytes,
+	tb.tx_bytes,
+	ub.user_count
+FROM
+	user_bandwidth ub
+JOIN
+	template_bandwidth tb
+ON
+	tb.user_id = ub.user_id
+JOIN
+	users u
+ON
+	u.id = tb.user_id
+ORDER BY
+	ub.total_bytes DESC, tb.user_id ASC, tb.rx_bytes + tb.tx_bytes DESC, tb.template_id ASC;
+
 -- name: GetTemplateInsights :one
 -- GetTemplateInsights returns the aggregate user-produced usage of all
 -- workspaces in a given timeframe. The template IDs, active users, and
@@ -427,25 +488,105 @@ GROUP BY
 	template_id, slug_or_port, display_name;
 
 
+-- name: GetTemplateComparisonInsights :many
+-- GetTemplateComparisonInsights returns build, usage and latency metrics of
+-- each of the given templates between start and end time, in the order the
+-- templates are given. The median build time only includes successful
+-- workspace starts. Unknown build times and latencies are -1.
+WITH
+	build_stats AS (
+		SELECT
+			tv.template_id,
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pj.completed_at - pj.started_at)) FILTER (WHERE wb.transition = 'start' AND pj.job_status = 'succeeded') AS start_build_seconds_50
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_versions AS tv
+		ON
+			tv.id = wb.template_version_id
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			tv.template_id = ANY(@template_ids::uuid[])
+			AND wb.created_at >= @start_time::timestamptz
+			AND wb.created_at < @end_time::timestamptz
+			AND pj.completed_at IS NOT NULL
+		GROUP BY
+			tv.template_id
+	),
+	usage_stats AS (
+		SELECT
+			tus.template_id,
+			COUNT(DISTINCT tus.user_id) AS active_users,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms) AS workspace_connection_latency_50,
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms) AS workspace_connection_latency_95
+		FROM
+			template_usage_stats AS tus
+		WHERE
+			tus.template_id = ANY(@template_ids::uuid[])
+			AND tus.start_time >= @start_time::timestamptz
+			AND tus.end_time <= @end_time::timestamptz
+		GROUP BY
+			tus.template_id
+	)
+
+SELECT
+	t.id AS template_id,
+	t.name AS template_name,
+	t.display_name AS template_display_name,
+	t.organization_id,
+	COALESCE(bs.total_builds, 0)::bigint AS total_builds,
+	COALESCE(bs.failed_builds, 0)::bigint AS failed_builds,
+	COALESCE(bs.start_build_seconds_50, -1)::float AS start_build_seconds_50,
+	COALESCE(us.active_users, 0)::bigint AS active_users,
+	COALESCE(us.workspace_connection_latency_50, -1)::float AS workspace_connection_latency_50,
+	COALESCE(us.workspace_connection_latency_95, -1)::float AS workspace_connection_latency_95
+FROM
+	templates AS t
+LEFT JOIN
+	build_stats AS bs
+ON
+	bs.template_id = t.id
+LEFT JOIN
+	usage_stats AS us
+ON
+	us.template_id = t.id
+WHERE
+	t.id = ANY(@template_ids::uuid[])
+ORDER BY
+	array_position(@template_ids::uuid[], t.id) ASC;
+
 -- name: GetTemplateInsightsByInterval :many
 -- GetTemplateInsightsByInterval returns all intervals between start and end
 -- time, if end time is a partial interval, it will be included in the results and
 -- that interval will be shorter than a full one. If there is no data for a selected
 -- interval/template, it will be included in the results with 0 active users.
+--
+-- Intervals are generated in the local time of the tz timezone, so that they
+-- follow the calendar of the caller across daylight saving time changes.
+-- Monthly intervals start on the first of the month, the first one is cut
+-- short to begin at the start time.
 WITH
 	ts AS (
 		SELECT
-			d::timestamptz AS from_,
+			GREATEST(d AT TIME ZONE @tz::text, @start_time::timestamptz)::timestamptz AS from_,
 			LEAST(
-				(d::timestamptz + (@interval_days::int || ' day')::interval)::timestamptz,
+				(d + make_interval(months => @interval_months::int, days => @interval_days::int)) AT TIME ZONE @tz::text,
 				@end_time::timestamptz
 			)::timestamptz AS to_
 		FROM
 			generate_series(
-				@start_time::timestamptz,
+				CASE
+					WHEN @interval_months::int > 0 THEN date_trunc('month', @start_time::timestamptz AT TIME ZONE @tz::text)
+					ELSE @start_time::timestamptz AT TIME ZONE @tz::text
+				END,
 				-- Subtract 1 μs to avoid creating an extra series.
-				(@end_time::timestamptz) - '1 microsecond'::interval,
-				(@interval_days::int || ' day')::interval
+				(@end_time::timestamptz AT TIME ZONE @tz::text) - '1 microsecond'::interval,
+				make_interval(months => @interval_months::int, days => @interval_days::int)
 			) AS d
 	)
 
@@ -466,6 +607,54 @@ ON
 GROUP BY
 	ts.from_, ts.to_;
 
+-- name: GetTemplateUsageHeatmap :many
+-- GetTemplateUsageHeatmap returns the average and peak number of active users
+-- for each hour of the week between start and end time. Hours are in the local
+-- time of the tz timezone, day_of_week 0 is Sunday. Hours without activity
+-- count towards the average with 0 active users.
+WITH
+	hours AS (
+		SELECT
+			h AS start_time,
+			EXTRACT(DOW FROM h AT TIME ZONE @tz::text)::int AS day_of_week,
+			EXTRACT(HOUR FROM h AT TIME ZONE @tz::text)::int AS hour_of_day
+		FROM
+			generate_series(
+				@start_time::timestamptz,
+				-- Subtract 1 μs to avoid creating an extra series.
+				@end_time::timestamptz - '1 microsecond'::interval,
+				'1 hour'::interval
+			) AS h
+	),
+	hourly_active_users AS (
+		SELECT
+			hours.day_of_week,
+			hours.hour_of_day,
+			COUNT(DISTINCT tus.user_id) AS active_users
+		FROM
+			hours
+		LEFT JOIN
+			template_usage_stats AS tus
+		ON
+			tus.start_time >= hours.start_time
+			AND tus.start_time < hours.start_time + '1 hour'::interval
+			AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN tus.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+		GROUP BY
+			hours.start_time, hours.day_of_week, hours.hour_of_day
+	)
+
+SELECT
+	day_of_week,
+	hour_of_day,
+	AVG(active_users)::float AS avg_active_users,
+	MAX(active_users)::bigint AS max_active_users
+FROM
+	hourly_active_users
+GROUP BY
+	day_of_week, hour_of_day
+ORDER BY
+	day_of_week ASC, hour_of_day ASC;
+
 -- name: GetTemplateUsageStats :many
 SELECT
 	*
@@ -476,6 +665,21 @@ WHERE
 	AND end_time <= @end_time::timestamptz
 	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN template_id = ANY(@template_ids::uuid[]) ELSE TRUE END;
 
+-- name: GetTemplateUsageStatsForExport :many
+-- Returns the template usage stats starting between start_time and end_time
+-- in the order they are exported. A limit of 0 returns all of them.
+SELECT
+	*
+FROM
+	template_usage_stats
+WHERE
+	start_time >= @start_time::timestamptz
+	AND start_time < @end_time::timestamptz
+ORDER BY
+	start_time, template_id, user_id
+LIMIT
+	NULLIF(@limit_opt::int, 0);
+
 -- name: UpsertTemplateUsageStats :exec
 -- This query aggregates the workspace_agent_stats and workspace_app_stats data
 -- into a single table for efficient storage and querying. Half-hour buckets are
@@ -898,3 +1102,599 @@ FROM ranked_status_change_per_user_per_date rscpupd
 CROSS JOIN statuses
 GROUP BY rscpupd.date, statuses.new_status
 ORDER BY rscpupd.date;
+
+-- name: GetDeploymentInsights :one
+-- GetDeploymentInsights returns insights aggregated across all templates and
+-- organizations between start and end time: the number of active users, the
+-- connection latency they experienced, the number of completed workspace
+-- builds and how many of them failed, and the bytes sent and received by
+-- workspace agents. Raw agent stats are deleted once they are rolled up into
+-- template usage stats, so the bandwidth only covers the period since
+-- bandwidth_start_time.
+WITH
+	usage_stats AS (
+		SELECT
+			COUNT(DISTINCT tus.user_id) AS active_users,
+			COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_50,
+			COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_95
+		FROM
+			template_usage_stats AS tus
+		WHERE
+			tus.start_time >= @start_time::timestamptz
+			AND tus.end_time <= @end_time::timestamptz
+	),
+	build_stats AS (
+		SELECT
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			wb.created_at >= @start_time::timestamptz
+			AND wb.created_at < @end_time::timestamptz
+			AND pj.completed_at IS NOT NULL
+	),
+	agent_stats AS (
+		SELECT
+			COALESCE(MIN(was.created_at), @end_time::timestamptz)::timestamptz AS bandwidth_start_time,
+			COALESCE(SUM(was.rx_bytes), 0)::bigint AS workspace_rx_bytes,
+			COALESCE(SUM(was.tx_bytes), 0)::bigint AS workspace_tx_bytes
+		FROM
+			workspace_agent_stats AS was
+		WHERE
+			was.created_at >= @start_time::timestamptz
+			AND was.created_at < @end_time::timestamptz
+	)
+SELECT
+	usage_stats.active_users,
+	usage_stats.workspace_connection_latency_50,
+	usage_stats.workspace_connection_latency_95,
+	build_stats.total_builds,
+	build_stats.failed_builds,
+	agent_stats.bandwidth_start_time,
+	agent_stats.workspace_rx_bytes,
+	agent_stats.workspace_tx_bytes
+FROM
+	usage_stats, build_stats, agent_stats;
+
+-- name: GetOrganizationInsights :one
+-- GetOrganizationInsights returns the same insights as GetDeploymentInsights
+-- limited to the templates and workspaces of an organization, along with the
+-- current number of workspaces in the organization and how many of them are
+-- running. Unlike the other insights, the workspace counts are not limited to
+-- the period between start and end time.
+WITH
+	usage_stats AS (
+		SELECT
+			COUNT(DISTINCT tus.user_id) AS active_users,
+			COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_50,
+			COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY tus.median_latency_ms)), -1)::float AS workspace_connection_latency_95
+		FROM
+			template_usage_stats AS tus
+		JOIN
+			templates AS t
+		ON
+			t.id = tus.template_id
+		WHERE
+			t.organization_id = @organization_id
+			AND tus.start_time >= @start_time::timestamptz
+			AND tus.end_time <= @end_time::timestamptz
+	),
+	workspace_stats AS (
+		SELECT
+			COUNT(*) AS total_workspaces,
+			COUNT(*) FILTER (WHERE wlb.transition = 'start' AND wlb.job_status = 'succeeded') AS running_workspaces
+		FROM
+			workspaces AS w
+		LEFT JOIN
+			workspace_latest_builds AS wlb
+		ON
+			wlb.workspace_id = w.id
+		WHERE
+			w.organization_id = @organization_id
+			AND w.deleted = false
+	),
+	build_stats AS (
+		SELECT
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed') AS failed_builds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			pj.organization_id = @organization_id
+			AND wb.created_at >= @start_time::timestamptz
+			AND wb.created_at < @end_time::timestamptz
+			AND pj.completed_at IS NOT NULL
+	),
+	agent_stats AS (
+		SELECT
+			COALESCE(MIN(was.created_at), @end_time::timestamptz)::timestamptz AS bandwidth_start_time,
+			COALESCE(SUM(was.rx_bytes), 0)::bigint AS workspace_rx_bytes,
+			COALESCE(SUM(was.tx_bytes), 0)::bigint AS workspace_tx_bytes
+		FROM
+			workspace_agent_stats AS was
+		JOIN
+			templates AS t
+		ON
+			t.id = was.template_id
+		WHERE
+			t.organization_id = @organization_id
+			AND was.created_at >= @start_time::timestamptz
+			AND was.created_at < @end_time::timestamptz
+	)
+SELECT
+	usage_stats.active_users,
+	usage_stats.workspace_connection_latency_50,
+	usage_stats.workspace_connection_latency_95,
+	workspace_stats.total_workspaces,
+	workspace_stats.running_workspaces,
+	build_stats.total_builds,
+	build_stats.failed_builds,
+	agent_stats.bandwidth_start_time,
+	agent_stats.workspace_rx_bytes,
+	agent_stats.workspace_tx_bytes
+FROM
+	usage_stats, workspace_stats, build_stats, agent_stats;
+
+-- name: UpsertWorkspaceCosts :exec
+-- This query estimates the cost of running workspaces from the
+-- workspace_agent_stats data. A workspace is considered running during every
+-- minute it reported agent stats, each running minute costs 1/1440th of the
+-- daily cost of the latest start build at that time. Hour buckets are used to
+-- store the data, and the result is stored in the workspace_costs table. Each
+-- hour is attributed to the user recorded on the agent stats, which is the
+-- owner at that time, so hours before an ownership transfer stay with the
+-- previous owner.
+WITH
+	latest_start AS (
+		SELECT
+			-- Truncate to hour so that we always look at even ranges of data.
+			date_trunc('hour', COALESCE(
+				MAX(start_time) - '1 hour'::interval,
+				-- Fallback when there are no workspace costs yet.
+				(SELECT MIN(created_at) FROM workspace_agent_stats)
+			)) AS t
+		FROM
+			workspace_costs
+	),
+	running_minutes AS (
+		SELECT DISTINCT ON (was.workspace_id, date_trunc('minute', was.created_at))
+			was.workspace_id,
+			was.user_id,
+			date_trunc('minute', was.created_at) AS minute_bucket
+		FROM
+			workspace_agent_stats AS was
+		WHERE
+			was.created_at >= (SELECT t FROM latest_start)
+			AND was.created_at < NOW()
+		ORDER BY
+			was.workspace_id, date_trunc('minute', was.created_at), was.created_at DESC
+	),
+	minute_costs AS (
+		SELECT
+			rm.workspace_id,
+			rm.user_id,
+			rm.minute_bucket,
+			COALESCE(wb.daily_cost, 0) AS daily_cost
+		FROM
+			running_minutes AS rm
+		LEFT JOIN LATERAL (
+			SELECT
+				wb.daily_cost
+			FROM
+				workspace_builds AS wb
+			WHERE
+				wb.workspace_id = rm.workspace_id
+				AND wb.transition = 'start'::workspace_transition
+				AND wb.created_at < rm.minute_bucket + '1 minute'::interval
+			ORDER BY
+				wb.build_number DESC
+			LIMIT 1
+		) AS wb ON TRUE
+	)
+INSERT INTO workspace_costs AS wc (
+	start_time,
+	workspace_id,
+	owner_id,
+	organization_id,
+	template_id,
+	running_mins,
+	daily_cost,
+	cost
+)
+SELECT
+	date_trunc('hour', mc.minute_bucket) AS start_time,
+	mc.workspace_id,
+	-- An hour that spans an ownership transfer belongs to the owner at the end
+	-- of the hour.
+	(array_agg(mc.user_id ORDER BY mc.minute_bucket DESC))[1] AS owner_id,
+	w.organization_id,
+	w.template_id,
+	COUNT(*) AS running_mins,
+	(array_agg(mc.daily_cost ORDER BY mc.minute_bucket DESC))[1] AS daily_cost,
+	SUM(mc.daily_cost) / 1440.0 AS cost
+FROM
+	minute_costs AS mc
+JOIN
+	workspaces AS w
+ON
+	w.id = mc.workspace_id
+GROUP BY
+	date_trunc('hour', mc.minute_bucket), mc.workspace_id, w.organization_id, w.template_id
+ON CONFLICT
+	(start_time, workspace_id)
+DO UPDATE
+SET
+	owner_id = EXCLUDED.owner_id,
+	organization_id = EXCLUDED.organization_id,
+	template_id = EXCLUDED.template_id,
+	running_mins = EXCLUDED.running_mins,
+	daily_cost = EXCLUDED.daily_cost,
+	cost = EXCLUDED.cost
+WHERE
+	(wc.owner_id, wc.running_mins, wc.daily_cost, wc.cost) IS DISTINCT FROM (EXCLUDED.owner_id, EXCLUDED.running_mins, EXCLUDED.daily_cost, EXCLUDED.cost);
+
+-- name: GetWorkspaceCost :one
+-- Returns how long a workspace was running between start_time and end_time,
+-- and the estimated cost of running it.
+SELECT
+	(COALESCE(SUM(wc.running_mins), 0) * 60)::bigint AS running_seconds,
+	COALESCE(SUM(wc.cost), 0)::float AS cost
+FROM
+	workspace_costs AS wc
+WHERE
+	wc.workspace_id = @workspace_id
+	AND wc.start_time >= @start_time::timestamptz
+	AND wc.start_time < @end_time::timestamptz;
+
+-- name: GetWorkspaceCostInsights :many
+-- GetWorkspaceCostInsights returns the estimated cost of running workspaces
+-- between start and end time, grouped by workspace, owner or organization as
+-- chosen by group_by. The name is the workspace name, username or
+-- organization name respectively, and is empty if it no longer exists.
+WITH
+	grouped_costs AS (
+		SELECT
+			CASE @group_by::text
+				WHEN 'user' THEN wc.owner_id
+				WHEN 'organization' THEN wc.organization_id
+				ELSE wc.workspace_id
+			END AS id,
+			(SUM(wc.running_mins) * 60)::bigint AS running_seconds,
+			SUM(wc.cost)::float AS cost
+		FROM
+			workspace_costs AS wc
+		WHERE
+			wc.start_time >= @start_time::timestamptz
+			AND wc.start_time < @end_time::timestamptz
+		GROUP BY
+			1
+	)
+SELECT
+	gc.id,
+	COALESCE(w.name, u.username, o.name, '')::text AS name,
+	gc.running_seconds,
+	gc.cost
+FROM
+	grouped_costs AS gc
+LEFT JOIN
+	workspaces AS w
+ON
+	@group_by::text = 'workspace' AND w.id = gc.id
+LEFT JOIN
+	users AS u
+ON
+	@group_by::text = 'user' AND u.id = gc.id
+LEFT JOIN
+	organizations AS o
+ON
+	@group_by::text = 'organization' AND o.id = gc.id
+ORDER BY
+	gc.cost DESC, gc.id;
+
+-- name: GetWorkspaceHealthInsights :many
+-- GetWorkspaceHealthInsights returns the build and latency signals used to
+-- score the health of workspaces whose latest build is a start build. The
+-- latency is the median connection latency reported by agents since
+-- latency_since, the baseline latency is the median between baseline_since and
+-- latency_since, and both are -1 if no latency was reported. Builds are counted
+-- since builds_since. The result can be filtered on template_ids.
+WITH
+	latest_builds AS (
+		SELECT DISTINCT ON (wb.workspace_id)
+			wb.workspace_id,
+			wb.job_id,
+			wb.transition,
+			pj.job_status
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		ORDER BY
+			wb.workspace_id, wb.build_number DESC
+	),
+	recent_builds AS (
+		SELECT
+			wb.workspace_id,
+			COUNT(*) AS builds,
+			COUNT(*) FILTER (WHERE pj.job_status = 'failed'::provisioner_job_status) AS failed_builds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			provisioner_jobs AS pj
+		ON
+			pj.id = wb.job_id
+		WHERE
+			wb.created_at >= @builds_since::timestamptz
+		GROUP BY
+			wb.workspace_id
+	),
+	latencies AS (
+		SELECT
+			was.workspace_id,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms) FILTER (WHERE was.created_at >= @latency_since::timestamptz) AS latency_ms,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms) FILTER (WHERE was.created_at < @latency_since::timestamptz) AS baseline_latency_ms
+		FROM
+			workspace_agent_stats AS was
+		WHERE
+			was.created_at >= @baseline_since::timestamptz
+			AND was.connection_median_latency_ms > 0
+		GROUP BY
+			was.workspace_id
+	)
+SELECT
+	w.id AS workspace_id,
+	w.name AS workspace_name,
+	w.owner_id,
+	u.username AS owner_username,
+	w.template_id,
+	t.name AS template_name,
+	lb.job_id AS latest_build_job_id,
+	(lb.job_status = 'failed'::provisioner_job_status)::boolean AS latest_build_failed,
+	COALESCE(rb.builds, 0)::bigint AS recent_builds,
+	COALESCE(rb.failed_builds, 0)::bigint AS recent_failed_builds,
+	COALESCE(l.latency_ms, -1)::float AS latency_ms,
+	COALESCE(l.baseline_latency_ms, -1)::float AS baseline_latency_ms
+FROM
+	workspaces AS w
+JOIN
+	users AS u
+ON
+	u.id = w.owner_id
+JOIN
+	templates AS t
+ON
+	t.id = w.template_id
+JOIN
+	latest_builds AS lb
+ON
+	lb.workspace_id = w.id
+LEFT JOIN
+	recent_builds AS rb
+ON
+	rb.workspace_id = w.id
+LEFT JOIN
+	latencies AS l
+ON
+	l.workspace_id = w.id
+WHERE
+	w.deleted = FALSE
+	AND lb.transition = 'start'::workspace_transition
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN w.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+ORDER BY
+	w.id;
+
+-- name: GetPrebuildInsights :many
+-- GetPrebuildInsights returns the utilization of prebuilt workspace pools
+-- between start_time and end_time, grouped by template and preset name. A
+-- claim is the first build of a prebuilt workspace that was not initiated by
+-- the prebuilds system user. Its latency is the duration of that build, and
+-- the idle time is the time between the prebuild becoming ready and the claim.
+-- A miss is a workspace created from a preset with prebuilds configured that
+-- was built from scratch instead of claimed. Latencies and idle times are -1
+-- if there were no claims. The result can be filtered on template_ids.
+WITH
+	claim_builds AS (
+		SELECT DISTINCT ON (wb.workspace_id)
+			wb.workspace_id,
+			wb.build_number,
+			wb.job_id,
+			wb.created_at
+		FROM
+			workspace_builds AS wb
+		JOIN
+			workspace_builds AS first_build
+		ON
+			first_build.workspace_id = wb.workspace_id
+			AND first_build.build_number = 1
+			AND first_build.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid -- The system user responsible for prebuilds.
+		WHERE
+			wb.initiator_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+		ORDER BY
+			wb.workspace_id, wb.build_number
+	),
+	events AS (
+		-- Prebuilds created for the pool.
+		SELECT
+			wb.template_version_preset_id AS preset_id,
+			'created'::text AS kind,
+			NULL::float AS claim_seconds,
+			NULL::float AS idle_seconds
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.build_number = 1
+			AND wb.initiator_id = 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+			AND wb.created_at >= @start_time::timestamptz
+			AND wb.created_at < @end_time::timestamptz
+
+		UNION ALL
+
+		-- Prebuilds claimed from the pool, attributed to the preset of the
+		-- last prebuild build.
+		SELECT
+			pb.template_version_preset_id AS preset_id,
+			'claim'::text AS kind,
+			EXTRACT(EPOCH FROM claim_job.completed_at - cb.created_at)::float AS claim_seconds,
+			GREATEST(EXTRACT(EPOCH FROM cb.created_at - prebuild_job.completed_at), 0)::float AS idle_seconds
+		FROM
+			claim_builds AS cb
+		JOIN LATERAL (
+			SELECT
+				wb.template_version_preset_id,
+				wb.job_id
+			FROM
+				workspace_builds AS wb
+			WHERE
+				wb.workspace_id = cb.workspace_id
+				AND wb.build_number < cb.build_number
+			ORDER BY
+				wb.build_number DESC
+			LIMIT 1
+		) AS pb ON TRUE
+		JOIN
+			provisioner_jobs AS claim_job
+		ON
+			claim_job.id = cb.job_id
+		JOIN
+			provisioner_jobs AS prebuild_job
+		ON
+			prebuild_job.id = pb.job_id
+		WHERE
+			cb.created_at >= @start_time::timestamptz
+			AND cb.created_at < @end_time::timestamptz
+
+		UNION ALL
+
+		-- Workspaces built from scratch although the preset has a pool.
+		SELECT
+			wb.template_version_preset_id AS preset_id,
+			'miss'::text AS kind,
+			NULL::float AS claim_seconds,
+			NULL::float AS idle_seconds
+		FROM
+			workspace_builds AS wb
+		JOIN
+			template_version_presets AS tvp
+		ON
+			tvp.id = wb.template_version_preset_id
+		WHERE
+			wb.build_number = 1
+			AND wb.initiator_id != 'c42fdf75-3097-471c-8c33-fb52454d81c0'::uuid
+			AND tvp.desired_instances > 0
+			AND wb.created_at >= @start_time::timestamptz
+			AND wb.created_at < @end_time::timestamptz
+	)
+SELECT
+	t.id AS template_id,
+	t.name AS template_name,
+	tvp.name AS preset_name,
+	COUNT(*) FILTER (WHERE e.kind = 'created') AS prebuilds_created,
+	COUNT(*) FILTER (WHERE e.kind = 'claim') AS claims,
+	COUNT(*) FILTER (WHERE e.kind = 'miss') AS misses,
+	COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY e.claim_seconds)), -1)::float AS claim_latency_seconds_50,
+	COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY e.claim_seconds)), -1)::float AS claim_latency_seconds_95,
+	COALESCE((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY e.idle_seconds)), -1)::float AS idle_seconds_50,
+	COALESCE((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY e.idle_seconds)), -1)::float AS idle_seconds_95
+FROM
+	events AS e
+JOIN
+	template_version_presets AS tvp
+ON
+	tvp.id = e.preset_id
+JOIN
+	template_versions AS tv
+ON
+	tv.id = tvp.template_version_id
+JOIN
+	templates AS t
+ON
+	t.id = tv.template_id
+WHERE
+	CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN t.id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY
+	t.id, t.name, tvp.name
+ORDER BY
+	t.name, tvp.name;
+
+-- name: GetTemplateAccessMethodInsights :many
+-- GetTemplateAccessMethodInsights returns the session time per access method
+-- (VS Code, JetBrains, web terminal, SSH and SFTP) of every template with
+-- usage between start and end time, for each interval. Intervals are generated
+-- like in GetTemplateInsightsByInterval, and intervals without usage are
+-- included with no session time. The result can be filtered on template_ids.
+WITH
+	ts AS (
+		SELECT
+			GREATEST(d AT TIME ZONE @tz::text, @start_time::timestamptz)::timestamptz AS from_,
+			LEAST(
+				(d + make_interval(months => @interval_months::int, days => @interval_days::int)) AT TIME ZONE @tz::text,
+				@end_time::timestamptz
+			)::timestamptz AS to_
+		FROM
+			generate_series(
+				CASE
+					WHEN @interval_months::int > 0 THEN date_trunc('month', @start_time::timestamptz AT TIME ZONE @tz::text)
+					ELSE @start_time::timestamptz AT TIME ZONE @tz::text
+				END,
+				-- Subtract 1 μs to avoid creating an extra series.
+				(@end_time::timestamptz AT TIME ZONE @tz::text) - '1 microsecond'::interval,
+				make_interval(months => @interval_months::int, days => @interval_days::int)
+			) AS d
+	),
+	usage AS (
+		SELECT
+			ts.from_,
+			tus.template_id,
+			SUM(tus.vscode_mins) AS vscode_mins,
+			SUM(tus.jetbrains_mins) AS jetbrains_mins,
+			SUM(tus.reconnecting_pty_mins) AS reconnecting_pty_mins,
+			SUM(tus.ssh_mins) AS ssh_mins,
+			SUM(tus.sftp_mins) AS sftp_mins
+		FROM
+			ts
+		JOIN
+			template_usage_stats AS tus
+		ON
+			tus.start_time >= ts.from_
+			AND tus.start_time < ts.to_ -- End time exclusion criteria optimization for index.
+			AND tus.end_time <= ts.to_
+			AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN tus.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+		GROUP BY
+			ts.from_, tus.template_id
+	)
+SELECT
+	ts.from_ AS start_time,
+	ts.to_ AS end_time,
+	t.id AS template_id,
+	t.name AS template_name,
+	(COALESCE(u.vscode_mins, 0) * 60)::bigint AS usage_vscode_seconds,
+	(COALESCE(u.jetbrains_mins, 0) * 60)::bigint AS usage_jetbrains_seconds,
+	(COALESCE(u.reconnecting_pty_mins, 0) * 60)::bigint AS usage_reconnecting_pty_seconds,
+	(COALESCE(u.ssh_mins, 0) * 60)::bigint AS usage_ssh_seconds,
+	(COALESCE(u.sftp_mins, 0) * 60)::bigint AS usage_sftp_seconds
+FROM
+	(SELECT DISTINCT template_id FROM usage) AS tu
+JOIN
+	templates AS t
+ON
+	t.id = tu.template_id
+CROSS JOIN
+	ts
+LEFT JOIN
+	usage AS u
+ON
+	u.from_ = ts.from_
+	AND u.template_id = t.id
+ORDER BY
+	t.name, t.id, ts.from_;
@@ -42,3 +42,80 @@ SELECT
 FROM
 	latest_builds
 ;
+
+-- name: GetGroupUsageQuotas :many
+SELECT
+	*
+FROM
+	group_usage_quotas
+WHERE
+	group_id = @group_id
+ORDER BY
+	metric;
+
+-- name: UpsertGroupUsageQuota :one
+INSERT INTO group_usage_quotas (group_id, metric, monthly_hours, action)
+VALUES (@group_id, @metric, @monthly_hours, @action)
+ON CONFLICT (group_id, metric) DO UPDATE SET
+	monthly_hours = EXCLUDED.monthly_hours,
+	action        = EXCLUDED.action,
+	updated_at    = NOW()
+RETURNING *;
+
+-- name: DeleteGroupUsageQuota :one
+DELETE FROM group_usage_quotas
+WHERE group_id = @group_id AND metric = @metric
+RETURNING *;
+
+-- name: GetUsageQuotaAllowancesForUser :many
+-- Returns the monthly allowance of the user for every metric limited by any of
+-- their groups in the organization. Like workspace quota allowances, the
+-- allowances of all their groups are summed. The strictest action applies,
+-- which is the last one in the enum. Metrics without a row are not limited.
+SELECT
+	group_usage_quotas.metric,
+	SUM(group_usage_quotas.monthly_hours)::BIGINT AS monthly_hours,
+	MAX(group_usage_quotas.action)::usage_quota_action AS action
+FROM
+	group_usage_quotas
+INNER JOIN group_members_expanded ON
+	group_members_expanded.group_id = group_usage_quotas.group_id
+WHERE
+	group_members_expanded.user_id = @user_id AND
+	group_members_expanded.organization_id = @organization_id
+GROUP BY
+	group_usage_quotas.metric
+ORDER BY
+	group_usage_quotas.metric;
+
+-- name: GetUsageQuotaConsumedForUser :one
+-- Returns the minutes the user has used workspaces of the organization since
+-- the given time, from the usage stats rolled up from batched agent and app
+-- stats. Connection minutes count every session separately, while active
+-- minutes count the time the user had any session, across all templates.
+WITH
+	usage_stats AS (
+		SELECT
+			tus.start_time,
+			-- See motivation in GetTemplateInsights for LEAST(SUM(n), 30).
+			LEAST(SUM(tus.usage_mins), 30) AS active_mins,
+			SUM(
+				tus.ssh_mins + tus.sftp_mins + tus.reconnecting_pty_mins + tus.vscode_mins + tus.jetbrains_mins +
+				COALESCE((SELECT SUM(value::INTEGER) FROM jsonb_each_text(tus.app_usage_mins)), 0)
+			) AS connection_mins
+		FROM
+			template_usage_stats tus
+		INNER JOIN templates ON
+			templates.id = tus.template_id
+		WHERE
+			tus.user_id = @user_id AND
+			templates.organization_id = @organization_id AND
+			tus.start_time >= @start_time::timestamptz
+		GROUP BY
+			tus.start_time
+	)
+SELECT
+	COALESCE(SUM(connection_mins), 0)::BIGINT AS connection_mins,
+	COALESCE(SUM(active_mins), 0)::BIGINT AS active_mins
+FROM
+	usage_stats;
@@ -0,0 +1,35 @@
+-- name: InsertScaletestResult :one
+INSERT INTO scaletest_results (
+	id,
+	created_at,
+	created_by,
+	name,
+	total_runs,
+	total_pass,
+	total_fail,
+	elapsed_ms,
+	summary
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetScaletestResultByID :one
+SELECT * FROM scaletest_results WHERE id = $1;
+
+-- name: GetScaletestResults :many
+SELECT
+	*
+FROM
+	scaletest_results
+WHERE
+	CASE
+		WHEN @name :: text != '' THEN name = @name
+		ELSE true
+	END
+ORDER BY
+	created_at DESC, id DESC
+OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
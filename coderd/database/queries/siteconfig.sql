@@ -36,6 +36,13 @@ ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update
 -- name: GetLastUpdateCheck :one
 SELECT value FROM site_configs WHERE key = 'last_update_check';
 
+-- name: UpsertDatabasePurgeStatus :exec
+INSERT INTO site_configs (key, value) VALUES ('database_purge_status', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'database_purge_status';
+
+-- name: GetDatabasePurgeStatus :one
+SELECT value FROM site_configs WHERE key = 'database_purge_status';
+
 -- name: UpsertAnnouncementBanners :exec
 INSERT INTO site_configs (key, value) VALUES ('announcement_banners', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'announcement_banners';
@@ -193,7 +193,8 @@ SET
 	autostart_block_days_of_week = $9,
 	failure_ttl = $10,
 	time_til_dormant = $11,
-	time_til_dormant_autodelete = $12
+	time_til_dormant_autodelete = $12,
+	dormancy_activity_signal = $13
 WHERE
 	id = $1
 ;
@@ -81,6 +81,33 @@ WHERE
 	AND is_service_account = false
 	AND CASE WHEN @include_system::bool THEN TRUE ELSE is_system = false END;
 
+-- name: GetUserCountWithUsageSince :one
+-- Counts active users that used a workspace since the given time. Raw agent
+-- stats are purged after a short retention period, so the rolled up template
+-- usage stats are checked as well.
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+	AND is_service_account = false
+	AND is_system = false
+	AND (
+		EXISTS (
+			SELECT 1 FROM workspace_agent_stats
+			WHERE workspace_agent_stats.user_id = users.id
+				AND workspace_agent_stats.usage = true
+				AND workspace_agent_stats.created_at >= @since::timestamptz
+		)
+		OR EXISTS (
+			SELECT 1 FROM template_usage_stats
+			WHERE template_usage_stats.user_id = users.id
+				AND template_usage_stats.start_time >= @since::timestamptz
+				AND template_usage_stats.usage_mins > 0
+		)
+	);
+
 -- name: InsertUser :one
 INSERT INTO
 	users (
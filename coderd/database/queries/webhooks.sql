@@ -0,0 +1,138 @@
+-- name: InsertWebhook :one
+INSERT INTO webhooks (
+	id,
+	created_at,
+	created_by,
+	template_id,
+	url,
+	secret,
+	secret_key_id,
+	events
+)
+VALUES
+	(@id, @created_at, @created_by, @template_id, @url, @secret, @secret_key_id, @events :: text[])
+RETURNING *;
+
+-- name: GetWebhooks :many
+SELECT * FROM webhooks ORDER BY created_at, id;
+
+-- name: GetWebhookByID :one
+SELECT * FROM webhooks WHERE id = @id;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks WHERE id = @id;
+
+-- name: UpdateEncryptedWebhookSecret :one
+-- Updates only the encrypted columns (secret, secret_key_id) of a webhook.
+-- Used by the dbcrypt key rotation utility to re-encrypt or decrypt rows in
+-- place.
+UPDATE
+	webhooks
+SET
+	secret = @secret :: text,
+	secret_key_id = sqlc.narg('secret_key_id') :: text
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: InsertWebhookDeliveries :execrows
+-- Enqueues a delivery of the event to every webhook subscribed to it, either
+-- for all workspaces or for the template of the workspace the event is about.
+INSERT INTO webhook_deliveries (
+	id,
+	webhook_id,
+	created_at,
+	updated_at,
+	event,
+	payload,
+	next_attempt_at
+)
+SELECT
+	gen_random_uuid(),
+	webhooks.id,
+	@created_at :: timestamptz,
+	@created_at :: timestamptz,
+	@event :: text,
+	@payload :: jsonb,
+	@created_at :: timestamptz
+FROM
+	webhooks
+WHERE
+	@event :: text = ANY(webhooks.events)
+	AND (webhooks.template_id IS NULL OR webhooks.template_id = @template_id :: uuid);
+
+-- name: AcquireWebhookDeliveries :many
+-- Acquires pending deliveries that are due, along with the webhook they are
+-- delivered to. The next attempt of acquired deliveries is pushed forward to
+-- the end of the lease, so that if the replica fails to record the outcome the
+-- delivery is attempted again once the lease expires. SKIP LOCKED prevents
+-- replicas from acquiring the same deliveries.
+WITH acquired AS (
+	UPDATE
+		webhook_deliveries
+	SET
+		attempts = attempts + 1,
+		updated_at = @now :: timestamptz,
+		next_attempt_at = @lease_until :: timestamptz
+	WHERE
+		id IN (
+			SELECT
+				wd.id
+			FROM
+				webhook_deliveries AS wd
+			WHERE
+				wd.status = 'pending' :: webhook_delivery_status
+				AND wd.next_attempt_at <= @now :: timestamptz
+			ORDER BY
+				wd.next_attempt_at ASC
+			LIMIT
+				@limit_opt :: int
+			FOR UPDATE OF wd
+				SKIP LOCKED
+		)
+	RETURNING *
+)
+SELECT
+	acquired.id,
+	acquired.webhook_id,
+	acquired.event,
+	acquired.payload,
+	acquired.attempts,
+	webhooks.url,
+	webhooks.secret,
+	webhooks.secret_key_id
+FROM
+	acquired
+JOIN
+	webhooks ON webhooks.id = acquired.webhook_id;
+
+-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = @status,
+	updated_at = @updated_at,
+	next_attempt_at = @next_attempt_at,
+	response_status_code = @response_status_code,
+	error = @error
+WHERE
+	id = @id;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC, id DESC
+OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: DeleteOldWebhookDeliveries :exec
+-- Delete all webhook deliveries which have not been updated for over 30 days.
+DELETE FROM webhook_deliveries
+WHERE updated_at < NOW() - INTERVAL '30 days';
@@ -0,0 +1,88 @@
+-- name: DeleteOldWorkspaceAgentResourceSamples :exec
+-- Delete all workspace agent resource samples collected over 30 days ago.
+DELETE FROM workspace_agent_resource_samples
+WHERE collected_at < NOW() - INTERVAL '30 days';
+
+-- name: GetWorkspaceAgentResourceUsage :many
+-- Returns the CPU and memory usage of the agents of a workspace between
+-- start_time and end_time. Agents are grouped by name, so that the samples of
+-- the agents of all builds are included. The total is the latest total of the
+-- resource reported by the agent.
+SELECT
+	wa.name AS agent_name,
+	wars.resource,
+	COUNT(*) AS samples,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY wars.used)::float AS used_p95,
+	MAX(wars.used)::float AS used_max,
+	(array_agg(wars.total ORDER BY wars.collected_at DESC))[1]::float AS total
+FROM
+	workspace_agent_resource_samples AS wars
+JOIN
+	workspace_agents AS wa
+ON
+	wa.id = wars.agent_id
+WHERE
+	wars.workspace_id = @workspace_id
+	AND wars.collected_at >= @start_time::timestamptz
+	AND wars.collected_at < @end_time::timestamptz
+GROUP BY
+	wa.name, wars.resource
+ORDER BY
+	wa.name, wars.resource;
+
+-- name: InsertWorkspaceAgentResourceSamples :exec
+-- Samples the CPU and memory usage agents report through the "coder stat cpu"
+-- and "coder stat mem" metadata scripts, e.g. "1.2/4 cores (30%)" and
+-- "2.5/7.7 GiB (32%)". Host usage (--host) is not sampled, since it is not
+-- what the workspace is sized by. Every collected value is only sampled once,
+-- so the stale metadata of disconnected agents does not skew the samples.
+INSERT INTO workspace_agent_resource_samples (
+	agent_id,
+	workspace_id,
+	resource,
+	collected_at,
+	used,
+	total
+)
+SELECT
+	wam.workspace_agent_id,
+	wb.workspace_id,
+	CASE WHEN m.parts[4] = 'cores' THEN 'cpu' ELSE 'memory' END,
+	wam.collected_at,
+	m.parts[1]::float * m.scale,
+	m.parts[2]::float * m.scale
+FROM
+	workspace_agent_metadata AS wam
+JOIN
+	workspace_agents AS wa
+ON
+	wa.id = wam.workspace_agent_id
+JOIN
+	workspace_resources AS wr
+ON
+	wr.id = wa.resource_id
+JOIN
+	workspace_builds AS wb
+ON
+	wb.job_id = wr.job_id
+CROSS JOIN LATERAL (
+	SELECT
+		p.parts,
+		CASE p.parts[3]
+			WHEN 'Ki' THEN 1024::float
+			WHEN 'Mi' THEN 1048576::float
+			WHEN 'Gi' THEN 1073741824::float
+			WHEN 'Ti' THEN 1099511627776::float
+			ELSE 1::float
+		END AS scale
+	FROM
+		(SELECT regexp_match(wam.value, '^([0-9]+(?:\.[0-9]+)?)/([0-9]+(?:\.[0-9]+)?) (Ki|Mi|Gi|Ti)?(cores|B)\M') AS parts) AS p
+) AS m
+WHERE
+	wam.script ~ '^coder stat (cpu|mem)\M'
+	AND wam.script !~ '--host'
+	AND wam.error = ''
+	AND m.parts IS NOT NULL
+ON CONFLICT
+	(agent_id, resource, collected_at)
+DO NOTHING;
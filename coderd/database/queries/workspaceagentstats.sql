@@ -40,11 +40,12 @@ SELECT
 	unnest(@connection_median_latency_ms :: double precision[]) AS connection_median_latency_ms,
 	unnest(@usage :: boolean[]) AS usage;
 
--- name: DeleteOldWorkspaceAgentStats :exec
+-- name: DeleteOldWorkspaceAgentStats :execrows
 DELETE FROM
 	workspace_agent_stats
 WHERE
-	created_at < (
+	created_at < @before_time::timestamptz
+	AND created_at < (
 		SELECT
 			COALESCE(
 				-- When generating initial template usage stats, all the
@@ -71,6 +72,14 @@ WHERE
 			workspace_agent_stats
 	);
 
+-- name: GetOldestWorkspaceAgentStatCreatedAt :one
+-- Returns the creation time of the oldest raw workspace agent stat, or the
+-- current time if there are none.
+SELECT
+	COALESCE(MIN(created_at), NOW())::timestamptz AS oldest_created_at
+FROM
+	workspace_agent_stats;
+
 -- name: GetDeploymentWorkspaceAgentStats :one
 WITH stats AS (
     SELECT
@@ -153,6 +162,89 @@ latest_agent_stats AS (
 )
 SELECT * FROM agent_stats, latest_agent_stats;
 
+-- name: GetWorkspaceAgentIDsWithConnectionLatency :many
+SELECT DISTINCT
+	agent_id
+FROM
+	workspace_agent_stats
+WHERE
+	created_at >= @start_time::timestamptz
+	AND created_at < @end_time::timestamptz
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	AND connection_median_latency_ms > 0;
+
+-- name: GetWorkspaceAgentConnectionLatencyByRegion :many
+-- Aggregates the connection latency reported by workspace agents by their
+-- preferred DERP region. Only the tailnet coordinator knows which region an
+-- agent prefers, so the mapping is passed in as parallel arrays. Agents missing
+-- from the mapping are grouped under region 0.
+WITH agent_regions AS (
+	SELECT
+		unnest(@agent_ids::uuid[]) AS agent_id,
+		unnest(@region_ids::int[]) AS region_id
+)
+SELECT
+	COALESCE(agent_regions.region_id, 0)::int AS region_id,
+	COUNT(DISTINCT was.agent_id) AS agent_count,
+	(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_50,
+	(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_90,
+	(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_95,
+	(PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_99
+FROM
+	workspace_agent_stats AS was
+LEFT JOIN
+	agent_regions
+ON
+	agent_regions.agent_id = was.agent_id
+WHERE
+	was.created_at >= @start_time::timestamptz
+	AND was.created_at < @end_time::timestamptz
+	AND was.connection_median_latency_ms > 0
+GROUP BY
+	agent_regions.region_id
+ORDER BY
+	region_id;
+
+-- name: GetWorkspaceAgentConnectionLatencyByClientRegion :many
+-- Aggregates the connection latency reported by workspace agents by the region
+-- and network of their clients. Each stat is attributed to the client that most
+-- recently connected to the agent within the hour before the stat was
+-- reported. Stats that cannot be attributed to a client are left out.
+SELECT
+	cn.client_region,
+	cn.client_asn,
+	COUNT(DISTINCT cn.user_id) AS user_count,
+	COUNT(DISTINCT was.agent_id) AS agent_count,
+	(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_50,
+	(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_90,
+	(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_95,
+	(PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY was.connection_median_latency_ms))::FLOAT AS workspace_connection_latency_99
+FROM
+	workspace_agent_stats AS was
+JOIN LATERAL (
+	SELECT
+		wcns.user_id,
+		wcns.client_region,
+		wcns.client_asn
+	FROM
+		workspace_client_network_stats AS wcns
+	WHERE
+		wcns.agent_id = was.agent_id
+		AND wcns.created_at <= was.created_at
+		AND wcns.created_at > was.created_at - '1 hour'::interval
+	ORDER BY
+		wcns.created_at DESC
+	LIMIT 1
+) AS cn ON TRUE
+WHERE
+	was.created_at >= @start_time::timestamptz
+	AND was.created_at < @end_time::timestamptz
+	AND was.connection_median_latency_ms > 0
+GROUP BY
+	cn.client_region, cn.client_asn
+ORDER BY
+	cn.client_region, cn.client_asn;
+
 -- name: GetWorkspaceAgentStats :many
 WITH agent_stats AS (
 	SELECT
@@ -183,6 +275,46 @@ WITH agent_stats AS (
 )
 SELECT * FROM agent_stats JOIN latest_agent_stats ON agent_stats.agent_id = latest_agent_stats.agent_id;
 
+-- name: GetWorkspaceAgentStatsTimeSeries :many
+-- Downsamples the stats reported by a workspace agent into fixed size buckets
+-- starting at start_time. Counters are summed and gauges are averaged within
+-- each bucket. Buckets without any stats are returned with zero values so
+-- callers don't have to fill the gaps themselves.
+WITH buckets AS (
+	SELECT
+		generate_series(
+			@start_time::timestamptz,
+			@end_time::timestamptz - '1 microsecond'::interval,
+			make_interval(secs => @bucket_seconds::int)
+		) AS bucket_start
+)
+SELECT
+	buckets.bucket_start::timestamptz AS bucket_start,
+	COUNT(was.id) AS report_count,
+	COALESCE(SUM(was.rx_bytes), 0)::bigint AS rx_bytes,
+	COALESCE(SUM(was.tx_bytes), 0)::bigint AS tx_bytes,
+	COALESCE(SUM(was.rx_packets), 0)::bigint AS rx_packets,
+	COALESCE(SUM(was.tx_packets), 0)::bigint AS tx_packets,
+	COALESCE(AVG(was.connection_count), 0)::float AS connection_count,
+	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+	COALESCE(AVG(was.connection_median_latency_ms) FILTER (WHERE was.connection_median_latency_ms > 0), 0)::float AS connection_median_latency_ms,
+	COALESCE(AVG(was.session_count_vscode), 0)::float AS session_count_vscode,
+	COALESCE(AVG(was.session_count_jetbrains), 0)::float AS session_count_jetbrains,
+	COALESCE(AVG(was.session_count_reconnecting_pty), 0)::float AS session_count_reconnecting_pty,
+	COALESCE(AVG(was.session_count_ssh), 0)::float AS session_count_ssh
+FROM
+	buckets
+LEFT JOIN
+	workspace_agent_stats AS was
+ON
+	was.agent_id = @agent_id::uuid
+	AND was.created_at >= buckets.bucket_start
+	AND was.created_at < LEAST(buckets.bucket_start + make_interval(secs => @bucket_seconds::int), @end_time::timestamptz)
+GROUP BY
+	buckets.bucket_start
+ORDER BY
+	buckets.bucket_start;
+
 -- name: GetWorkspaceAgentUsageStats :many
 WITH agent_stats AS (
 	SELECT
@@ -357,3 +489,23 @@ JOIN
 	workspaces
 ON
 	workspaces.id = agent_stats.workspace_id;
+
+-- name: InsertWorkspaceClientNetworkStat :exec
+INSERT INTO
+	workspace_client_network_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		agent_id,
+		client_region,
+		client_asn
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7);
+
+-- name: DeleteOldWorkspaceClientNetworkStats :execrows
+DELETE FROM
+	workspace_client_network_stats
+WHERE
+	created_at < @before_time::timestamptz;
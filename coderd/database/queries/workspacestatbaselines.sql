@@ -0,0 +1,68 @@
+-- name: GetWorkspaceActivityStats :many
+-- Returns the traffic of each workspace with agent stats between start_time
+-- and end_time, and the most sessions its agents had open at once.
+WITH agent_stats AS (
+	SELECT
+		workspace_id,
+		agent_id,
+		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes,
+		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+		coalesce(MAX(session_count_vscode + session_count_jetbrains + session_count_reconnecting_pty + session_count_ssh), 0)::bigint AS sessions
+	FROM
+		workspace_agent_stats
+	WHERE
+		created_at >= @start_time
+		AND created_at < @end_time
+	GROUP BY
+		workspace_id, agent_id
+)
+SELECT
+	agent_stats.workspace_id,
+	SUM(agent_stats.tx_bytes)::bigint AS tx_bytes,
+	SUM(agent_stats.rx_bytes)::bigint AS rx_bytes,
+	SUM(agent_stats.sessions)::bigint AS sessions
+FROM
+	agent_stats
+JOIN
+	workspaces ON workspaces.id = agent_stats.workspace_id
+WHERE
+	NOT workspaces.deleted
+GROUP BY
+	agent_stats.workspace_id;
+
+-- name: GetWorkspaceStatBaselines :many
+SELECT
+	*
+FROM
+	workspace_stat_baselines
+WHERE
+	workspace_id = ANY(@workspace_ids::uuid[]);
+
+-- name: UpsertWorkspaceStatBaseline :exec
+-- Baselines are only moved forward, so that an hour is not folded into a
+-- baseline twice.
+INSERT INTO workspace_stat_baselines (
+	workspace_id,
+	metric,
+	samples,
+	mean,
+	variance,
+	updated_at,
+	flagged_at
+) VALUES (
+	@workspace_id,
+	@metric,
+	@samples,
+	@mean,
+	@variance,
+	@updated_at,
+	@flagged_at
+)
+ON CONFLICT (workspace_id, metric) DO UPDATE SET
+	samples = EXCLUDED.samples,
+	mean = EXCLUDED.mean,
+	variance = EXCLUDED.variance,
+	updated_at = EXCLUDED.updated_at,
+	flagged_at = EXCLUDED.flagged_at
+WHERE
+	workspace_stat_baselines.updated_at < EXCLUDED.updated_at;
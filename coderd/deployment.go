@@ -1,7 +1,11 @@
 package coderd
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -61,6 +65,141 @@ func (api *API) deploymentStats(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(r.Context(), rw, http.StatusOK, stats)
 }
 
+// @Summary Get database purge status
+// @ID get-database-purge-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.DatabasePurgeStatus
+// @Router /api/v2/deployment/purge-status [get]
+func (api *API) databasePurgeStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	raw, err := api.Database.GetDatabasePurgeStatus(ctx)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The database purge has not run yet.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	var status codersdk.DatabasePurgeStatus
+	if err := json.Unmarshal([]byte(raw), &status); err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("unmarshal database purge status: %w", err))
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, status)
+}
+
+// @Summary Get API rate limit consumption
+// @ID get-api-rate-limit-consumption
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.APIRateLimitConsumption
+// @Router /api/v2/deployment/rate-limits [get]
+func (api *API) apiRateLimitConsumption(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, api.rateLimitTracker.Consumption())
+}
+
+// deploymentStatsWatchInterval is how often watchers of the deployment stats
+// check the metrics cache for newly aggregated stats.
+const deploymentStatsWatchInterval = 5 * time.Second
+
+// @Summary Watch deployment stats
+// @ID watch-deployment-stats
+// @Security CoderSessionToken
+// @Produce text/event-stream
+// @Tags General
+// @Success 200 {object} codersdk.Response
+// @Router /api/v2/deployment/stats/watch [get]
+func (api *API) watchDeploymentStatsSSE(rw http.ResponseWriter, r *http.Request) {
+	api.watchDeploymentStats(rw, r, httpapi.ServerSentEventSender)
+}
+
+// @Summary Watch deployment stats via WebSockets
+// @ID watch-deployment-stats-via-websockets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.ServerSentEvent
+// @Router /api/v2/deployment/stats/watch-ws [get]
+func (api *API) watchDeploymentStatsWS(rw http.ResponseWriter, r *http.Request) {
+	api.watchDeploymentStats(rw, r, httpapi.OneWayWebSocketEventSender(api.Logger, api.wsWatcher))
+}
+
+// watchDeploymentStats sends the deployment stats whenever the metrics cache
+// aggregates new ones, so that the dashboard doesn't have to poll for them.
+func (api *API) watchDeploymentStats(
+	rw http.ResponseWriter,
+	r *http.Request,
+	connect httpapi.EventSender,
+) {
+	ctx := r.Context()
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	sendEvent, senderClosed, err := connect(rw, r)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting up server-sent events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Prevent handler from returning until the sender is closed.
+	defer func() {
+		<-senderClosed
+	}()
+
+	var lastCollectedAt time.Time
+	sendUpdate := func() {
+		stats, ok := api.metricsCache.DeploymentStats()
+		if !ok || stats.CollectedAt.Equal(lastCollectedAt) {
+			// Either the stats are still processing, or the watcher
+			// already has the latest ones.
+			return
+		}
+		lastCollectedAt = stats.CollectedAt
+		_ = sendEvent(codersdk.ServerSentEvent{
+			Type: codersdk.ServerSentEventTypeData,
+			Data: stats,
+		})
+	}
+
+	ticker := api.Clock.NewTicker(deploymentStatsWatchInterval, "deployment_stats", "watch")
+	defer ticker.Stop()
+
+	// Send the current stats immediately.
+	sendUpdate()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-senderClosed:
+			return
+		case <-ticker.C:
+			sendUpdate()
+		}
+	}
+}
+
 // @Summary Build info
 // @ID build-info
 // @Produce json
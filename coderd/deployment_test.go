@@ -2,12 +2,19 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -62,3 +69,106 @@ func TestDeploymentStats(t *testing.T) {
 		return err == nil
 	}, testutil.IntervalMedium), "failed to get deployment stats in time")
 }
+
+func TestWatchDeploymentStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		stats, err := client.WatchDeploymentStats(ctx)
+		require.NoError(t, err)
+		got := testutil.TryReceive(ctx, t, stats)
+		require.False(t, got.CollectedAt.IsZero())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := member.WatchDeploymentStats(ctx)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestDatabasePurgeStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, _, api := coderdtest.NewWithAPI(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// The purge has not run yet.
+	_, err := client.DatabasePurgeStatus(ctx)
+	cerr := coderdtest.SDKError(t, err)
+	require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+
+	want := codersdk.DatabasePurgeStatus{
+		StartedAt:  dbtime.Now(),
+		FinishedAt: dbtime.Now(),
+		Success:    true,
+		RecordsPurged: map[string]int64{
+			"workspace_agent_stats": 42,
+		},
+		WorkspaceAgentStatsBacklogMillis: time.Hour.Milliseconds(),
+	}
+	raw, err := json.Marshal(want)
+	require.NoError(t, err)
+	//nolint:gocritic // The status is stored by the purge, not by users.
+	err = api.Database.UpsertDatabasePurgeStatus(dbauthz.AsSystemRestricted(ctx), string(raw))
+	require.NoError(t, err)
+
+	got, err := client.DatabasePurgeStatus(ctx)
+	require.NoError(t, err)
+	require.True(t, got.Success)
+	require.Equal(t, want.RecordsPurged, got.RecordsPurged)
+	require.Equal(t, want.WorkspaceAgentStatsBacklogMillis, got.WorkspaceAgentStatsBacklogMillis)
+	require.WithinDuration(t, want.StartedAt, got.StartedAt, time.Second)
+
+	_, err = member.DatabasePurgeStatus(ctx)
+	cerr = coderdtest.SDKError(t, err)
+	require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+}
+
+func TestAPIRateLimitConsumption(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{
+		APIRateLimit: 100,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	for i := 0; i < 3; i++ {
+		_, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	}
+
+	consumption, err := client.APIRateLimitConsumption(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 100, consumption.Limit)
+	require.EqualValues(t, 60, consumption.WindowSeconds)
+	idx := slices.IndexFunc(consumption.Users, func(u codersdk.APIRateLimitUserConsumption) bool {
+		return u.UserID == memberUser.ID
+	})
+	require.NotEqual(t, -1, idx, "member missing from the consumption")
+	require.Equal(t, memberUser.Username, consumption.Users[idx].Username)
+	require.GreaterOrEqual(t, consumption.Users[idx].WindowRequests, 3)
+	require.Greater(t, consumption.Users[idx].Consumption, 0.0)
+	require.Zero(t, consumption.Users[idx].LimitedRequests)
+
+	_, err = member.APIRateLimitConsumption(ctx)
+	cerr := coderdtest.SDKError(t, err)
+	require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-chi/httprate"
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/aibridge"
@@ -18,9 +19,24 @@ import (
 	"github.com/coder/coder/v2/cryptorand"
 )
 
+// RateLimitOption configures RateLimit.
+type RateLimitOption func(*rateLimitOptions)
+
+type rateLimitOptions struct {
+	tracker *RateLimitTracker
+}
+
+// WithRateLimitTracker records the consumption of the rate limit by each user
+// in the given tracker.
+func WithRateLimitTracker(tracker *RateLimitTracker) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.tracker = tracker
+	}
+}
+
 // RateLimit returns a handler that limits requests per-minute based
 // on IP, endpoint, and user ID (if available).
-func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler {
+func RateLimit(count int, window time.Duration, opts ...RateLimitOption) func(http.Handler) http.Handler {
 	// -1 is no rate limit
 	if count <= 0 {
 		return func(handler http.Handler) http.Handler {
@@ -28,37 +44,32 @@ func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler
 		}
 	}
 
+	var options rateLimitOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return httprate.Limit(
 		count,
 		window,
 		httprate.WithKeyFuncs(func(r *http.Request) (string, error) {
-			// Identify the caller. We check two sources:
-			//
-			// 1. apiKeyPrecheckedContextKey — set by PrecheckAPIKey
-			//    at the root of the router. Only fully validated
-			//    keys are used.
-			// 2. apiKeyContextKey — set by ExtractAPIKeyMW if it
-			//    has already run (e.g. unit tests, workspace-app
-			//    routes that don't go through PrecheckAPIKey).
-			//
-			// If neither is present, fall back to IP.
-			var userID string
-			var subject *rbac.Subject
-
-			if pc, ok := r.Context().Value(apiKeyPrecheckedContextKey{}).(APIKeyPrechecked); ok && pc.Result != nil {
-				userID = pc.Result.Key.UserID.String()
-				subject = &pc.Result.Subject
-			} else if ak, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey); ok {
-				userID = ak.UserID.String()
-				if auth, ok := UserAuthorizationOptional(r.Context()); ok {
-					subject = &auth
-				}
-			} else {
+			// Identify the caller, falling back to the IP.
+			id, subject, ok := rateLimitUser(r)
+			if !ok {
 				return httprate.KeyByIP(r)
 			}
+			userID := id.String()
 
 			if ok, _ := strconv.ParseBool(r.Header.Get(codersdk.BypassRatelimitHeader)); !ok {
 				// No bypass attempt, just rate limit by user.
+				if options.tracker != nil {
+					var username string
+					if subject != nil {
+						username = subject.FriendlyName
+					}
+					endpoint, _ := httprate.KeyByEndpoint(r)
+					options.tracker.recordRequest(id, username, endpoint)
+				}
 				return userID, nil
 			}
 
@@ -87,6 +98,11 @@ func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler
 			)
 		}, httprate.KeyByEndpoint),
 		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			if options.tracker != nil {
+				if id, _, ok := rateLimitUser(r); ok {
+					options.tracker.recordLimited(id)
+				}
+			}
 			httpapi.Write(r.Context(), w, http.StatusTooManyRequests, codersdk.Response{
 				Message: fmt.Sprintf("You've been rate limited for sending more than %v requests in %v.", count, window),
 			})
@@ -94,6 +110,27 @@ func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler
 	)
 }
 
+// rateLimitUser identifies the user sending the request. We check two sources:
+//
+//  1. apiKeyPrecheckedContextKey — set by PrecheckAPIKey at the root of the
+//     router. Only fully validated keys are used.
+//  2. apiKeyContextKey — set by ExtractAPIKeyMW if it has already run (e.g.
+//     unit tests, workspace-app routes that don't go through PrecheckAPIKey).
+//
+// The subject is nil when the roles of the user are unknown.
+func rateLimitUser(r *http.Request) (uuid.UUID, *rbac.Subject, bool) {
+	if pc, ok := r.Context().Value(apiKeyPrecheckedContextKey{}).(APIKeyPrechecked); ok && pc.Result != nil {
+		return pc.Result.Key.UserID, &pc.Result.Subject, true
+	}
+	if ak, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey); ok {
+		if auth, ok := UserAuthorizationOptional(r.Context()); ok {
+			return ak.UserID, &auth, true
+		}
+		return ak.UserID, nil, true
+	}
+	return uuid.Nil, nil, false
+}
+
 // RateLimitByAuthToken returns a handler that limits requests based on the
 // authentication token in the request.
 //
@@ -19,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
 )
 
 func randRemoteAddr() string {
@@ -146,6 +147,60 @@ func TestRateLimit(t *testing.T) {
 			require.False(t, resp.StatusCode == http.StatusTooManyRequests)
 		}
 	})
+
+	t.Run("Tracker", func(t *testing.T) {
+		t.Parallel()
+
+		db, _ := dbtestutil.NewDB(t)
+		u := dbgen.User(t, db, database.User{Username: "automation"})
+		_, key := dbgen.APIKey(t, db, database.APIKey{UserID: u.ID})
+
+		clock := quartz.NewMock(t)
+		tracker := httpmw.NewRateLimitTracker(2, time.Minute, clock)
+
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:       db,
+			Optional: false,
+		}))
+		rtr.Use(httpmw.RateLimit(2, time.Minute, httpmw.WithRateLimitTracker(tracker)))
+		rtr.Get("/", func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		})
+
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(codersdk.SessionTokenHeader, key)
+			rec := httptest.NewRecorder()
+			rtr.ServeHTTP(rec, req)
+			resp := rec.Result()
+			_ = resp.Body.Close()
+			require.Equal(t, i == 2, resp.StatusCode == http.StatusTooManyRequests)
+		}
+
+		consumption := tracker.Consumption()
+		require.Equal(t, 2, consumption.Limit)
+		require.EqualValues(t, 60, consumption.WindowSeconds)
+		require.Len(t, consumption.Users, 1)
+		require.Equal(t, u.ID, consumption.Users[0].UserID)
+		require.Equal(t, "automation", consumption.Users[0].Username)
+		require.Equal(t, 3, consumption.Users[0].WindowRequests)
+		require.InDelta(t, 1.5, consumption.Users[0].Consumption, 0.001)
+		require.EqualValues(t, 3, consumption.Users[0].TotalRequests)
+		require.EqualValues(t, 1, consumption.Users[0].LimitedRequests)
+
+		// The consumption resets with the window, the totals do not.
+		clock.Advance(time.Minute)
+		consumption = tracker.Consumption()
+		require.Len(t, consumption.Users, 1)
+		require.Zero(t, consumption.Users[0].WindowRequests)
+		require.Zero(t, consumption.Users[0].Consumption)
+		require.EqualValues(t, 3, consumption.Users[0].TotalRequests)
+
+		// Users that stopped sending requests are forgotten.
+		clock.Advance(time.Hour)
+		require.Empty(t, tracker.Consumption().Users)
+	})
 }
 
 func TestRateLimitByAuthToken(t *testing.T) {
@@ -0,0 +1,201 @@
+package httpmw
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+// rateLimitTrackerRetention is how long a user that stopped sending requests
+// is still reported by the tracker.
+const rateLimitTrackerRetention = time.Hour
+
+// RateLimitTracker records how much of the API rate limit each user consumes,
+// so that operators can spot automation hammering the API before it is
+// limited. Like the rate limit itself, the counters are local to the replica.
+//
+// The tracker is a prometheus.Collector and must be passed to RateLimit with
+// WithRateLimitTracker.
+type RateLimitTracker struct {
+	limit  int
+	window time.Duration
+	clock  quartz.Clock
+
+	mu       sync.Mutex
+	users    map[uuid.UUID]*rateLimitUsage
+	prunedAt time.Time
+
+	requestsDesc    *prometheus.Desc
+	limitedDesc     *prometheus.Desc
+	consumptionDesc *prometheus.Desc
+}
+
+type rateLimitUsage struct {
+	username string
+	// windowStart is the start of the window the endpoint counts belong to.
+	windowStart time.Time
+	// endpoints counts the requests per endpoint in the current window. The
+	// rate limit applies to each endpoint separately.
+	endpoints     map[string]int
+	requests      int64
+	limited       int64
+	lastRequestAt time.Time
+}
+
+// NewRateLimitTracker creates a tracker for a rate limit of limit requests per
+// endpoint in the given window.
+func NewRateLimitTracker(limit int, window time.Duration, clock quartz.Clock) *RateLimitTracker {
+	return &RateLimitTracker{
+		limit:  limit,
+		window: window,
+		clock:  clock,
+		users:  make(map[uuid.UUID]*rateLimitUsage),
+		requestsDesc: prometheus.NewDesc(
+			"coderd_api_rate_limit_requests_total",
+			"The total number of API requests subject to the rate limit sent by a user.",
+			[]string{"username"}, nil,
+		),
+		limitedDesc: prometheus.NewDesc(
+			"coderd_api_rate_limit_limited_requests_total",
+			"The total number of API requests of a user rejected by the rate limit.",
+			[]string{"username"}, nil,
+		),
+		consumptionDesc: prometheus.NewDesc(
+			"coderd_api_rate_limit_consumption_ratio",
+			"The requests a user sent in the current rate limit window to their busiest endpoint, as a fraction of the limit. Values above 1 mean requests are being rejected.",
+			[]string{"username"}, nil,
+		),
+	}
+}
+
+func (t *RateLimitTracker) recordRequest(userID uuid.UUID, username, endpoint string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	usage, ok := t.users[userID]
+	if !ok {
+		usage = &rateLimitUsage{endpoints: make(map[string]int)}
+		t.users[userID] = usage
+	}
+	if now.Sub(usage.windowStart) >= t.window {
+		usage.windowStart = now
+		clear(usage.endpoints)
+	}
+	if username != "" {
+		usage.username = username
+	}
+	usage.endpoints[endpoint]++
+	usage.requests++
+	usage.lastRequestAt = now
+
+	if now.Sub(t.prunedAt) >= t.window {
+		t.pruneLocked(now)
+	}
+}
+
+func (t *RateLimitTracker) recordLimited(userID uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if usage, ok := t.users[userID]; ok {
+		usage.limited++
+	}
+}
+
+// pruneLocked forgets the users that have not sent a request within the
+// retention period. The caller must hold the lock.
+func (t *RateLimitTracker) pruneLocked(now time.Time) {
+	for id, usage := range t.users {
+		if now.Sub(usage.lastRequestAt) > rateLimitTrackerRetention {
+			delete(t.users, id)
+		}
+	}
+	t.prunedAt = now
+}
+
+// windowRequestsLocked returns the number of requests the user sent to their
+// busiest endpoint in the current window. The caller must hold the lock.
+func (t *RateLimitTracker) windowRequestsLocked(usage *rateLimitUsage, now time.Time) int {
+	if now.Sub(usage.windowStart) >= t.window {
+		return 0
+	}
+	var busiest int
+	for _, count := range usage.endpoints {
+		busiest = max(busiest, count)
+	}
+	return busiest
+}
+
+func (t *RateLimitTracker) consumption(windowRequests int) float64 {
+	if t.limit <= 0 {
+		return 0
+	}
+	return float64(windowRequests) / float64(t.limit)
+}
+
+// Consumption returns the rate limit consumption of the users that sent
+// requests recently, ordered by the highest consumption first.
+func (t *RateLimitTracker) Consumption() codersdk.APIRateLimitConsumption {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	t.pruneLocked(now)
+
+	res := codersdk.APIRateLimitConsumption{
+		Limit:         max(t.limit, 0),
+		WindowSeconds: int64(t.window.Seconds()),
+		Users:         make([]codersdk.APIRateLimitUserConsumption, 0, len(t.users)),
+	}
+	for id, usage := range t.users {
+		windowRequests := t.windowRequestsLocked(usage, now)
+		res.Users = append(res.Users, codersdk.APIRateLimitUserConsumption{
+			UserID:          id,
+			Username:        usage.username,
+			WindowRequests:  windowRequests,
+			Consumption:     t.consumption(windowRequests),
+			TotalRequests:   usage.requests,
+			LimitedRequests: usage.limited,
+			LastRequestAt:   usage.lastRequestAt,
+		})
+	}
+	slices.SortFunc(res.Users, func(a, b codersdk.APIRateLimitUserConsumption) int {
+		if c := cmp.Compare(b.WindowRequests, a.WindowRequests); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.LimitedRequests, a.LimitedRequests); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return res
+}
+
+// Describe implements prometheus.Collector.
+func (t *RateLimitTracker) Describe(descCh chan<- *prometheus.Desc) {
+	descCh <- t.requestsDesc
+	descCh <- t.limitedDesc
+	descCh <- t.consumptionDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *RateLimitTracker) Collect(metricsCh chan<- prometheus.Metric) {
+	for _, user := range t.Consumption().Users {
+		username := user.Username
+		if username == "" {
+			username = user.UserID.String()
+		}
+		metricsCh <- prometheus.MustNewConstMetric(t.requestsDesc, prometheus.CounterValue, float64(user.TotalRequests), username)
+		metricsCh <- prometheus.MustNewConstMetric(t.limitedDesc, prometheus.CounterValue, float64(user.LimitedRequests), username)
+		metricsCh <- prometheus.MustNewConstMetric(t.consumptionDesc, prometheus.GaugeValue, user.Consumption, username)
+	}
+}
@@ -0,0 +1,247 @@
+package idlereaper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/files"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+// Reaper stops or flags running workspaces that have had no sessions for
+// longer than the idle policy of their template allows. Templates without an
+// idle policy are left alone.
+type Reaper struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db                database.Store
+	pubsub            pubsub.Pubsub
+	fileCache         *files.Cache
+	buildUsageChecker *atomic.Pointer[wsbuilder.UsageChecker]
+	log               slog.Logger
+	tick              <-chan time.Time
+	stats             chan<- Stats
+}
+
+// Stats contains statistics about the last run of the reaper.
+type Stats struct {
+	// StoppedWorkspaceIDs contains the IDs of the workspaces a stop build was
+	// scheduled for.
+	StoppedWorkspaceIDs []uuid.UUID
+	// FlaggedWorkspaceIDs contains the IDs of the workspaces that were newly
+	// flagged as idle.
+	FlaggedWorkspaceIDs []uuid.UUID
+	// Errors contains the errors that occurred while handling individual
+	// workspaces.
+	Errors map[uuid.UUID]error
+	// Error is the fatal error that occurred during the last run of the
+	// reaper, if any.
+	Error error
+}
+
+// New returns a new idle workspace reaper.
+func New(ctx context.Context, db database.Store, ps pubsub.Pubsub, fc *files.Cache, buildUsageChecker *atomic.Pointer[wsbuilder.UsageChecker], log slog.Logger, tick <-chan time.Time) *Reaper {
+	//nolint:gocritic // The idle reaper stops workspaces just like autostop.
+	ctx, cancel := context.WithCancel(dbauthz.AsAutostart(ctx))
+	return &Reaper{
+		ctx:               ctx,
+		cancel:            cancel,
+		done:              make(chan struct{}),
+		db:                db,
+		pubsub:            ps,
+		fileCache:         fc,
+		buildUsageChecker: buildUsageChecker,
+		log:               log,
+		tick:              tick,
+		stats:             nil,
+	}
+}
+
+// WithStatsChannel will cause the reaper to push Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the reaper will hang.
+// This should only be used in tests.
+func (r *Reaper) WithStatsChannel(ch chan<- Stats) *Reaper {
+	r.stats = ch
+	return r
+}
+
+// Start will cause the reaper to handle idle workspaces on every tick from
+// its channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (r *Reaper) Start() {
+	go func() {
+		defer close(r.done)
+		defer r.cancel()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case t, ok := <-r.tick:
+				if !ok {
+					return
+				}
+				stats := r.run(t)
+				if stats.Error != nil {
+					r.log.Warn(r.ctx, "error running idle workspace reaper once", slog.Error(stats.Error))
+				}
+				if r.stats != nil {
+					select {
+					case <-r.ctx.Done():
+						return
+					case r.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Wait will block until the reaper is stopped.
+func (r *Reaper) Wait() {
+	<-r.done
+}
+
+// Close will stop the reaper.
+func (r *Reaper) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Reaper) run(t time.Time) Stats {
+	ctx, cancel := context.WithTimeout(r.ctx, 5*time.Minute)
+	defer cancel()
+
+	stats := Stats{
+		StoppedWorkspaceIDs: []uuid.UUID{},
+		FlaggedWorkspaceIDs: []uuid.UUID{},
+		Errors:              map[uuid.UUID]error{},
+	}
+
+	workspaces, err := r.db.GetIdleWorkspaces(ctx, database.GetIdleWorkspacesParams{
+		Now: t,
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("get idle workspaces: %w", err)
+		return stats
+	}
+
+	for _, ws := range workspaces {
+		log := r.log.With(
+			slog.F("workspace_id", ws.WorkspaceID),
+			slog.F("workspace_name", ws.WorkspaceName),
+			slog.F("last_active_at", ws.LastActiveAt),
+			slog.F("action", ws.Action),
+		)
+
+		switch ws.Action {
+		case database.IdleWorkspaceActionFlag:
+			// The workspace was already flagged since it was last active.
+			if ws.FlaggedAt.Valid {
+				continue
+			}
+			err := r.db.UpsertWorkspaceIdleFlag(ctx, database.UpsertWorkspaceIdleFlagParams{
+				WorkspaceID: ws.WorkspaceID,
+				FlaggedAt:   dbtime.Time(t),
+			})
+			if err != nil {
+				stats.Errors[ws.WorkspaceID] = xerrors.Errorf("flag workspace: %w", err)
+				log.Error(ctx, "failed to flag idle workspace", slog.Error(err))
+				continue
+			}
+			stats.FlaggedWorkspaceIDs = append(stats.FlaggedWorkspaceIDs, ws.WorkspaceID)
+			log.Info(ctx, "flagged idle workspace")
+		case database.IdleWorkspaceActionStop:
+			stopped, err := r.stop(ctx, ws.WorkspaceID)
+			if err != nil {
+				stats.Errors[ws.WorkspaceID] = xerrors.Errorf("stop workspace: %w", err)
+				log.Error(ctx, "failed to stop idle workspace", slog.Error(err))
+				continue
+			}
+			if stopped {
+				stats.StoppedWorkspaceIDs = append(stats.StoppedWorkspaceIDs, ws.WorkspaceID)
+				log.Info(ctx, "stopped idle workspace")
+			}
+		}
+	}
+
+	return stats
+}
+
+// stop schedules a stop build for the workspace. It returns false if the
+// workspace was no longer running or is being handled by another replica.
+func (r *Reaper) stop(ctx context.Context, workspaceID uuid.UUID) (bool, error) {
+	var job *database.ProvisionerJob
+	err := r.db.InTx(func(tx database.Store) error {
+		// Share the lock of the lifecycle executor so that the two never build
+		// the same workspace at once.
+		ok, err := tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("lifecycle-executor:%s", workspaceID)))
+		if err != nil {
+			return xerrors.Errorf("try acquire lifecycle executor lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		ws, err := tx.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by id: %w", err)
+		}
+		latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, ws.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		latestJob, err := tx.GetProvisionerJobByID(ctx, latestBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get latest provisioner job: %w", err)
+		}
+		// The workspace may have been stopped since it was found to be idle.
+		if latestBuild.Transition != database.WorkspaceTransitionStart ||
+			latestJob.JobStatus != database.ProvisionerJobStatusSucceeded {
+			return nil
+		}
+
+		builder := wsbuilder.New(ws, database.WorkspaceTransitionStop, *r.buildUsageChecker.Load()).
+			SetLastWorkspaceBuildInTx(&latestBuild).
+			SetLastWorkspaceBuildJobInTx(&latestJob).
+			Reason(database.BuildReasonAutostop)
+		_, job, _, err = builder.Build(ctx, tx, r.fileCache, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+		if err != nil {
+			return xerrors.Errorf("build workspace: %w", err)
+		}
+		return nil
+	}, &database.TxOptions{
+		Isolation:    sql.LevelRepeatableRead,
+		TxIdentifier: "idle_reaper",
+	})
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	if err := provisionerjobs.PostJob(r.pubsub, *job); err != nil {
+		// The job is already committed, so a provisioner will eventually
+		// acquire it.
+		r.log.Warn(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	return true, nil
+}
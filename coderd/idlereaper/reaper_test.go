@@ -0,0 +1,144 @@
+package idlereaper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/idlereaper"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, testutil.GoleakOptions...)
+}
+
+func TestReaper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		env := newReaperTestEnv(t)
+		env.runningWorkspace(t)
+
+		stats := env.tick(t, time.Now().Add(365*24*time.Hour))
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.StoppedWorkspaceIDs)
+		require.Empty(t, stats.FlaggedWorkspaceIDs)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+
+		env := newReaperTestEnv(t)
+		workspace := env.runningWorkspace(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := env.client.UpsertTemplateIdlePolicy(ctx, workspace.TemplateID, codersdk.UpsertTemplateIdlePolicyRequest{
+			IdleTimeoutMillis: time.Hour.Milliseconds(),
+			Action:            codersdk.IdleWorkspaceActionStop,
+		})
+		require.NoError(t, err)
+
+		// The workspace just started, so it is not idle yet.
+		stats := env.tick(t, time.Now())
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.StoppedWorkspaceIDs)
+
+		stats = env.tick(t, time.Now().Add(2*time.Hour))
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.Errors)
+		require.Equal(t, []uuid.UUID{workspace.ID}, stats.StoppedWorkspaceIDs)
+
+		workspace = coderdtest.MustWorkspace(t, env.client, workspace.ID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+		require.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, env.client, workspace.LatestBuild.ID)
+
+		// Stopped workspaces are no longer idle.
+		stats = env.tick(t, time.Now().Add(2*time.Hour))
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.StoppedWorkspaceIDs)
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		t.Parallel()
+
+		env := newReaperTestEnv(t)
+		workspace := env.runningWorkspace(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := env.client.UpsertTemplateIdlePolicy(ctx, workspace.TemplateID, codersdk.UpsertTemplateIdlePolicyRequest{
+			IdleTimeoutMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+
+		flaggedAt := time.Now().Add(2 * time.Hour)
+		stats := env.tick(t, flaggedAt)
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.Errors)
+		require.Empty(t, stats.StoppedWorkspaceIDs)
+		require.Equal(t, []uuid.UUID{workspace.ID}, stats.FlaggedWorkspaceIDs)
+
+		// Workspaces are only flagged once while they stay idle.
+		stats = env.tick(t, flaggedAt.Add(time.Hour))
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.FlaggedWorkspaceIDs)
+
+		workspace = coderdtest.MustWorkspace(t, env.client, workspace.ID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	})
+}
+
+type reaperTestEnv struct {
+	client  *codersdk.Client
+	owner   codersdk.CreateFirstUserResponse
+	tickCh  chan time.Time
+	statsCh chan idlereaper.Stats
+}
+
+func newReaperTestEnv(t *testing.T) *reaperTestEnv {
+	t.Helper()
+
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	tickCh := make(chan time.Time)
+	statsCh := make(chan idlereaper.Stats)
+	reaper := idlereaper.New(t.Context(), api.Database, api.Pubsub, api.FileCache, api.BuildUsageChecker, testutil.Logger(t), tickCh).
+		WithStatsChannel(statsCh)
+	reaper.Start()
+	t.Cleanup(reaper.Close)
+
+	return &reaperTestEnv{
+		client:  client,
+		owner:   owner,
+		tickCh:  tickCh,
+		statsCh: statsCh,
+	}
+}
+
+func (e *reaperTestEnv) runningWorkspace(t *testing.T) codersdk.Workspace {
+	t.Helper()
+
+	version := coderdtest.CreateTemplateVersion(t, e.client, e.owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, e.client, version.ID)
+	template := coderdtest.CreateTemplate(t, e.client, e.owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, e.client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, e.client, workspace.LatestBuild.ID)
+	return workspace
+}
+
+func (e *reaperTestEnv) tick(t *testing.T, now time.Time) idlereaper.Stats {
+	t.Helper()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	testutil.RequireSend(ctx, t, e.tickCh, now)
+	return testutil.RequireReceive(ctx, t, e.statsCh)
+}
@@ -0,0 +1,151 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template idle policy
+// @ID get-template-idle-policy
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateIdlePolicy
+// @Router /api/v2/templates/{template}/idle-policy [get]
+func (api *API) templateIdlePolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	idlePolicy, err := api.Database.GetTemplateIdlePolicy(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "get template idle policy", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.TemplateIdlePolicy(idlePolicy))
+}
+
+// @Summary Upsert template idle policy
+// @ID upsert-template-idle-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpsertTemplateIdlePolicyRequest true "Upsert template idle policy request"
+// @Success 200 {object} codersdk.TemplateIdlePolicy
+// @Router /api/v2/templates/{template}/idle-policy [put]
+func (api *API) putTemplateIdlePolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	var req codersdk.UpsertTemplateIdlePolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Action == "" {
+		req.Action = codersdk.IdleWorkspaceActionFlag
+	}
+	action := database.IdleWorkspaceAction(req.Action)
+	if !action.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid idle workspace action %q.", req.Action),
+			Validations: []codersdk.ValidationError{
+				{Field: "action", Detail: fmt.Sprintf("must be one of %v", codersdk.IdleWorkspaceActions)},
+			},
+		})
+		return
+	}
+
+	idlePolicy, err := api.Database.UpsertTemplateIdlePolicy(ctx, database.UpsertTemplateIdlePolicyParams{
+		TemplateID:    template.ID,
+		IdleTimeoutMs: req.IdleTimeoutMillis,
+		Action:        action,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "upsert template idle policy", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.TemplateIdlePolicy(idlePolicy))
+}
+
+// @Summary Delete template idle policy
+// @ID delete-template-idle-policy
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 204
+// @Router /api/v2/templates/{template}/idle-policy [delete]
+func (api *API) deleteTemplateIdlePolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	_, err := api.Database.DeleteTemplateIdlePolicy(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "delete template idle policy", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// templateIdleWorkspaces is a dry run of the idle workspace reaper. It lists
+// the workspaces that the reaper would act on if it ran now.
+//
+// @Summary Get idle workspaces by template
+// @ID get-idle-workspaces-by-template
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.IdleWorkspace
+// @Router /api/v2/templates/{template}/idle-workspaces [get]
+func (api *API) templateIdleWorkspaces(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	rows, err := api.Database.GetIdleWorkspaces(ctx, database.GetIdleWorkspacesParams{
+		Now:        dbtime.Time(api.Clock.Now()),
+		TemplateID: template.ID,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "get idle workspaces", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.IdleWorkspace, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, db2sdk.IdleWorkspace(row))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
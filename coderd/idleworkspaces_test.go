@@ -0,0 +1,121 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateIdlePolicy(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	var apiErr *codersdk.Error
+	_, err := client.TemplateIdlePolicy(ctx, template.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	idlePolicy, err := client.UpsertTemplateIdlePolicy(ctx, template.ID, codersdk.UpsertTemplateIdlePolicyRequest{
+		IdleTimeoutMillis: time.Hour.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, template.ID, idlePolicy.TemplateID)
+	require.Equal(t, time.Hour.Milliseconds(), idlePolicy.IdleTimeoutMillis)
+	require.Equal(t, codersdk.IdleWorkspaceActionFlag, idlePolicy.Action)
+
+	idlePolicy, err = client.UpsertTemplateIdlePolicy(ctx, template.ID, codersdk.UpsertTemplateIdlePolicyRequest{
+		IdleTimeoutMillis: 2 * time.Hour.Milliseconds(),
+		Action:            codersdk.IdleWorkspaceActionStop,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.IdleWorkspaceActionStop, idlePolicy.Action)
+
+	fetched, err := client.TemplateIdlePolicy(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, idlePolicy, fetched)
+
+	_, err = client.UpsertTemplateIdlePolicy(ctx, template.ID, codersdk.UpsertTemplateIdlePolicyRequest{
+		IdleTimeoutMillis: time.Hour.Milliseconds(),
+		Action:            "archive",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.UpsertTemplateIdlePolicy(ctx, template.ID, codersdk.UpsertTemplateIdlePolicyRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Members can use the template but not change its idle policy.
+	_, err = member.UpsertTemplateIdlePolicy(ctx, template.ID, codersdk.UpsertTemplateIdlePolicyRequest{
+		IdleTimeoutMillis: time.Hour.Milliseconds(),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = client.DeleteTemplateIdlePolicy(ctx, template.ID)
+	require.NoError(t, err)
+
+	err = client.DeleteTemplateIdlePolicy(ctx, template.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestTemplateIdleWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+	// Without a policy no workspace is idle.
+	idle, err := client.TemplateIdleWorkspaces(ctx, template.ID)
+	require.NoError(t, err)
+	require.Empty(t, idle)
+
+	// The smallest timeout makes the workspace idle as soon as it started.
+	_, err = client.UpsertTemplateIdlePolicy(ctx, template.ID, codersdk.UpsertTemplateIdlePolicyRequest{
+		IdleTimeoutMillis: 1,
+		Action:            codersdk.IdleWorkspaceActionStop,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		idle, err = client.TemplateIdleWorkspaces(ctx, template.ID)
+		return err == nil && len(idle) == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, workspace.ID, idle[0].WorkspaceID)
+	require.Equal(t, workspace.OwnerName, idle[0].OwnerName)
+	require.Equal(t, codersdk.IdleWorkspaceActionStop, idle[0].Action)
+	require.Nil(t, idle[0].FlaggedAt)
+
+	// Listing idle workspaces is a dry run.
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+
+	// Only template admins can see idle workspaces.
+	_, err = member.TemplateIdleWorkspaces(ctx, template.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -3,9 +3,14 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"math"
+	"mime"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,10 +18,14 @@ import (
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog/v3"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/insightscache"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/util/slice"
@@ -66,9 +75,10 @@ func (api *API) returnDAUsInternal(rw http.ResponseWriter, r *http.Request, temp
 	// Always return 60 days of data (2 months).
 	sixtyDaysAgo := nextHourInLoc.In(loc).Truncate(24*time.Hour).AddDate(0, 0, -60)
 
-	rows, err := api.Database.GetTemplateInsightsByInterval(ctx, database.GetTemplateInsightsByIntervalParams{
+	rows, err := insightscache.Get(ctx, api.insightsCache, "GetTemplateInsightsByInterval", api.Database.GetTemplateInsightsByInterval, database.GetTemplateInsightsByIntervalParams{
 		StartTime:    sixtyDaysAgo,
 		EndTime:      nextHourInLoc,
+		Tz:           utcOffsetTimezone(tzOffset * 3600),
 		IntervalDays: 1,
 		TemplateIDs:  templateIDs,
 	})
@@ -105,6 +115,7 @@ func (api *API) returnDAUsInternal(rw http.ResponseWriter, r *http.Request, temp
 // @Param start_time query string true "Start time" format(date-time)
 // @Param end_time query string true "End time" format(date-time)
 // @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
 // @Success 200 {object} codersdk.UserActivityInsightsResponse
 // @Router /api/v2/insights/user-activity [get]
 func (api *API) insightsUserActivity(rw http.ResponseWriter, r *http.Request) {
@@ -120,6 +131,7 @@ func (api *API) insightsUserActivity(rw http.ResponseWriter, r *http.Request) {
 		startTimeString = p.String(vals, "", "start_time")
 		endTimeString   = p.String(vals, "", "end_time")
 		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		formatString    = p.String(vals, "", "format")
 	)
 	p.ErrorExcessParams(vals)
 	if len(p.Errors) > 0 {
@@ -134,84 +146,1472 @@ func (api *API) insightsUserActivity(rw http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := insightscache.Get(ctx, api.insightsCache, "GetUserActivityInsights", api.Database.GetUserActivityInsights, database.GetUserActivityInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	// No data is not an error.
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		// Check authorization.
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user activity.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "user-activity.csv", []string{"user_id", "username", "template_ids", "seconds"}, len(rows), func(i int) []string {
+			return []string{
+				rows[i].UserID.String(),
+				rows[i].Username,
+				insightsCSVTemplateIDs(rows[i].TemplateIDs),
+				strconv.FormatInt(rows[i].UsageSeconds, 10),
+			}
+		})
+		return
+	}
+
+	templateIDSet := make(map[uuid.UUID]struct{})
+	userActivities := make([]codersdk.UserActivity, 0, len(rows))
+	for _, row := range rows {
+		for _, templateID := range row.TemplateIDs {
+			templateIDSet[templateID] = struct{}{}
+		}
+		userActivities = append(userActivities, codersdk.UserActivity{
+			TemplateIDs: row.TemplateIDs,
+			UserID:      row.UserID,
+			Username:    row.Username,
+			AvatarURL:   row.AvatarURL,
+			Seconds:     row.UsageSeconds,
+		})
+	}
+
+	// TemplateIDs that contributed to the data.
+	seenTemplateIDs := make([]uuid.UUID, 0, len(templateIDSet))
+	for templateID := range templateIDSet {
+		seenTemplateIDs = append(seenTemplateIDs, templateID)
+	}
+	slices.SortFunc(seenTemplateIDs, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+
+	resp := codersdk.UserActivityInsightsResponse{
+		Report: codersdk.UserActivityInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: seenTemplateIDs,
+			Users:       userActivities,
+		},
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get insights about user latency
+// @ID get-insights-about-user-latency
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.UserLatencyInsightsResponse
+// @Router /api/v2/insights/user-latency [get]
+func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		formatString    = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := insightscache.Get(ctx, api.insightsCache, "GetUserLatencyInsights", api.Database.GetUserLatencyInsights, database.GetUserLatencyInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user latency.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "user-latency.csv", []string{"user_id", "username", "template_ids", "latency_p50_ms", "latency_p95_ms"}, len(rows), func(i int) []string {
+			return []string{
+				rows[i].UserID.String(),
+				rows[i].Username,
+				insightsCSVTemplateIDs(rows[i].TemplateIDs),
+				strconv.FormatFloat(rows[i].WorkspaceConnectionLatency50, 'f', -1, 64),
+				strconv.FormatFloat(rows[i].WorkspaceConnectionLatency95, 'f', -1, 64),
+			}
+		})
+		return
+	}
+
+	templateIDSet := make(map[uuid.UUID]struct{})
+	userLatencies := make([]codersdk.UserLatency, 0, len(rows))
+	for _, row := range rows {
+		for _, templateID := range row.TemplateIDs {
+			templateIDSet[templateID] = struct{}{}
+		}
+		userLatencies = append(userLatencies, codersdk.UserLatency{
+			TemplateIDs: row.TemplateIDs,
+			UserID:      row.UserID,
+			Username:    row.Username,
+			AvatarURL:   row.AvatarURL,
+			LatencyMS: codersdk.ConnectionLatency{
+				P50: row.WorkspaceConnectionLatency50,
+				P95: row.WorkspaceConnectionLatency95,
+			},
+		})
+	}
+
+	// TemplateIDs that contributed to the data.
+	seenTemplateIDs := make([]uuid.UUID, 0, len(templateIDSet))
+	for templateID := range templateIDSet {
+		seenTemplateIDs = append(seenTemplateIDs, templateID)
+	}
+	slices.SortFunc(seenTemplateIDs, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+
+	resp := codersdk.UserLatencyInsightsResponse{
+		Report: codersdk.UserLatencyInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: seenTemplateIDs,
+			Users:       userLatencies,
+		},
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get insights about the deployment
+// @ID get-insights-about-the-deployment
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Success 200 {object} codersdk.DeploymentInsightsResponse
+// @Router /api/v2/insights/deployment [get]
+func (api *API) insightsDeployment(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Deployment insights span every organization, so they're limited to
+	// deployment admins rather than everyone who can view template
+	// insights.
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceDeploymentConfig) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	row, err := api.Database.GetDeploymentInsights(ctx, database.GetDeploymentInsightsParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching deployment insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.DeploymentInsightsResponse{
+		Report: codersdk.DeploymentInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			ActiveUsers: row.ActiveUsers,
+			Builds: codersdk.DeploymentBuildInsights{
+				Total:  row.TotalBuilds,
+				Failed: row.FailedBuilds,
+			},
+			Bandwidth: codersdk.DeploymentBandwidthInsights{
+				StartTime: row.BandwidthStartTime.In(startTime.Location()),
+				RxBytes:   row.WorkspaceRxBytes,
+				TxBytes:   row.WorkspaceTxBytes,
+			},
+			LatencyMS: codersdk.ConnectionLatency{
+				P50: row.WorkspaceConnectionLatency50,
+				P95: row.WorkspaceConnectionLatency95,
+			},
+		},
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get connection latency insights by DERP region
+// @ID get-connection-latency-insights-by-derp-region
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Success 200 {object} codersdk.RegionLatencyInsightsResponse
+// @Router /api/v2/insights/region-latency [get]
+func (api *API) insightsRegionLatency(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	agentIDs, err := api.Database.GetWorkspaceAgentIDsWithConnectionLatency(ctx, database.GetWorkspaceAgentIDsWithConnectionLatencyParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Stats don't record which DERP region an agent relays through, so look
+	// up the preferred region of each agent from the tailnet coordinator.
+	// Agents that are no longer connected are left out of the mapping and
+	// reported under region 0.
+	coordinator := *api.TailnetCoordinator.Load()
+	arg := database.GetWorkspaceAgentConnectionLatencyByRegionParams{
+		AgentIDs:  make([]uuid.UUID, 0, len(agentIDs)),
+		RegionIDs: make([]int32, 0, len(agentIDs)),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	for _, agentID := range agentIDs {
+		node := coordinator.Node(agentID)
+		if node == nil || node.PreferredDERP == 0 {
+			continue
+		}
+		arg.AgentIDs = append(arg.AgentIDs, agentID)
+		arg.RegionIDs = append(arg.RegionIDs, int32(node.PreferredDERP)) //nolint:gosec // DERP region IDs fit in an int32.
+	}
+
+	rows, err := api.Database.GetWorkspaceAgentConnectionLatencyByRegion(ctx, arg)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching connection latency by region.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	derpMap := api.DERPMap()
+	regions := make([]codersdk.RegionLatencyInsight, 0, len(rows))
+	for _, row := range rows {
+		regionID := int(row.RegionID)
+		regionName := "Unknown"
+		if regionID != 0 {
+			regionName = fmt.Sprintf("Unnamed %d", regionID)
+			if region, ok := derpMap.Regions[regionID]; ok && region != nil {
+				regionName = region.RegionName
+			}
+		}
+		regions = append(regions, codersdk.RegionLatencyInsight{
+			RegionID:   regionID,
+			RegionName: regionName,
+			Agents:     row.AgentCount,
+			LatencyMS: codersdk.ConnectionLatencyQuantiles{
+				P50: row.WorkspaceConnectionLatency50,
+				P90: row.WorkspaceConnectionLatency90,
+				P95: row.WorkspaceConnectionLatency95,
+				P99: row.WorkspaceConnectionLatency99,
+			},
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RegionLatencyInsightsResponse{
+		Report: codersdk.RegionLatencyInsightsReport{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Regions:   regions,
+		},
+	})
+}
+
+// @Summary Get connection latency insights by client region
+// @ID get-connection-latency-insights-by-client-region
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Success 200 {object} codersdk.ClientRegionLatencyInsightsResponse
+// @Router /api/v2/insights/client-region-latency [get]
+func (api *API) insightsClientRegionLatency(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceAgentConnectionLatencyByClientRegion(ctx, database.GetWorkspaceAgentConnectionLatencyByClientRegionParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching connection latency by client region.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	regions := make([]codersdk.ClientRegionLatencyInsight, 0, len(rows))
+	for _, row := range rows {
+		regions = append(regions, codersdk.ClientRegionLatencyInsight{
+			Region: row.ClientRegion,
+			ASN:    row.ClientAsn,
+			Users:  row.UserCount,
+			Agents: row.AgentCount,
+			LatencyMS: codersdk.ConnectionLatencyQuantiles{
+				P50: row.WorkspaceConnectionLatency50,
+				P90: row.WorkspaceConnectionLatency90,
+				P95: row.WorkspaceConnectionLatency95,
+				P99: row.WorkspaceConnectionLatency99,
+			},
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ClientRegionLatencyInsightsResponse{
+		Report: codersdk.ClientRegionLatencyInsightsReport{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Regions:   regions,
+		},
+	})
+}
+
+// @Summary Get insights about user bandwidth
+// @ID get-insights-about-user-bandwidth
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.UserBandwidthInsightsResponse
+// @Router /api/v2/insights/user-bandwidth [get]
+func (api *API) insightsUserBandwidth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		// A limit of 0 means no limit, see ParsePagination.
+		limit        = p.PositiveInt32(vals, 0, "limit")
+		offset       = p.PositiveInt32(vals, 0, "offset")
+		formatString = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetUserBandwidthInsights(ctx, database.GetUserBandwidthInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+		OffsetOpt:   offset,
+		LimitOpt:    limit,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user bandwidth.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "user-bandwidth.csv", []string{"user_id", "username", "template_id", "rx_bytes", "tx_bytes"}, len(rows), func(i int) []string {
+			return []string{
+				rows[i].UserID.String(),
+				rows[i].Username,
+				rows[i].TemplateID.String(),
+				strconv.FormatInt(rows[i].RxBytes, 10),
+				strconv.FormatInt(rows[i].TxBytes, 10),
+			}
+		})
+		return
+	}
+
+	// Rows are ordered by user, so the templates of a user are adjacent.
+	report := codersdk.UserBandwidthInsightsReport{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Users:     []codersdk.UserBandwidth{},
+	}
+	for _, row := range rows {
+		report.Count = row.UserCount
+		if n := len(report.Users); n == 0 || report.Users[n-1].UserID != row.UserID {
+			report.Users = append(report.Users, codersdk.UserBandwidth{
+				UserID:    row.UserID,
+				Username:  row.Username,
+				AvatarURL: row.AvatarURL,
+				Templates: []codersdk.TemplateBandwidth{},
+			})
+		}
+		user := &report.Users[len(report.Users)-1]
+		user.RxBytes += row.RxBytes
+		user.TxBytes += row.TxBytes
+		user.Templates = append(user.Templates, codersdk.TemplateBandwidth{
+			TemplateID: row.TemplateID,
+			RxBytes:    row.RxBytes,
+			TxBytes:    row.TxBytes,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserBandwidthInsightsResponse{
+		Report: report,
+	})
+}
+
+// @Summary Get insights about hour-of-week usage
+// @ID get-insights-about-hour-of-week-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param timezone query string false "IANA timezone name to bucket hours in (e.g. America/St_Johns), defaults to the UTC offset of the start time"
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.UsageHeatmapInsightsResponse
+// @Router /api/v2/insights/usage-heatmap [get]
+func (api *API) insightsUsageHeatmap(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		timezoneString  = p.String(vals, "", "timezone")
+		formatString    = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	loc, tz, ok := parseInsightsTimezone(ctx, rw, timezoneString, startTime)
+	if !ok {
+		return
+	}
+	startTime, endTime = startTime.In(loc), endTime.In(loc)
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetTemplateUsageHeatmap(ctx, database.GetTemplateUsageHeatmapParams{
+		Tz:          tz,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching usage heatmap.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "usage-heatmap.csv", []string{"day_of_week", "hour_of_day", "avg_active_users", "max_active_users"}, len(rows), func(i int) []string {
+			return []string{
+				strconv.Itoa(int(rows[i].DayOfWeek)),
+				strconv.Itoa(int(rows[i].HourOfDay)),
+				strconv.FormatFloat(rows[i].AvgActiveUsers, 'f', -1, 64),
+				strconv.FormatInt(rows[i].MaxActiveUsers, 10),
+			}
+		})
+		return
+	}
+
+	// Hours that are not part of the time range, e.g. when it is shorter
+	// than a week, are left at zero.
+	report := codersdk.UsageHeatmapInsightsReport{
+		StartTime:      startTime,
+		EndTime:        endTime,
+		TemplateIDs:    templateIDs,
+		AvgActiveUsers: make([][]float64, 7),
+		MaxActiveUsers: make([][]int64, 7),
+	}
+	for day := range report.AvgActiveUsers {
+		report.AvgActiveUsers[day] = make([]float64, 24)
+		report.MaxActiveUsers[day] = make([]int64, 24)
+	}
+	for _, row := range rows {
+		report.AvgActiveUsers[row.DayOfWeek][row.HourOfDay] = row.AvgActiveUsers
+		report.MaxActiveUsers[row.DayOfWeek][row.HourOfDay] = row.MaxActiveUsers
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UsageHeatmapInsightsResponse{
+		Report: report,
+	})
+}
+
+// @Summary Get insights comparing templates
+// @ID get-insights-comparing-templates
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string true "Template IDs" collectionFormat(csv)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.TemplateComparisonInsightsResponse
+// @Router /api/v2/insights/template-comparison [get]
+func (api *API) insightsTemplateComparison(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time").
+		RequiredNotEmpty("template_ids")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		formatString    = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetTemplateComparisonInsights(ctx, database.GetTemplateComparisonInsightsParams{
+		TemplateIDs: templateIDs,
+		StartTime:   startTime,
+		EndTime:     endTime,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template comparison.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	templates := make([]codersdk.TemplateComparison, 0, len(rows))
+	for _, row := range rows {
+		successRate := float64(-1)
+		if row.TotalBuilds > 0 {
+			successRate = float64(row.TotalBuilds-row.FailedBuilds) / float64(row.TotalBuilds)
+		}
+		templates = append(templates, codersdk.TemplateComparison{
+			TemplateID:          row.TemplateID,
+			TemplateName:        row.TemplateName,
+			TemplateDisplayName: row.TemplateDisplayName,
+			OrganizationID:      row.OrganizationID,
+			TotalBuilds:         row.TotalBuilds,
+			FailedBuilds:        row.FailedBuilds,
+			BuildSuccessRate:    successRate,
+			MedianBuildSeconds:  row.StartBuildSeconds50,
+			ActiveUsers:         row.ActiveUsers,
+			WorkspaceConnectionLatencyMS: codersdk.ConnectionLatency{
+				P50: row.WorkspaceConnectionLatency50,
+				P95: row.WorkspaceConnectionLatency95,
+			},
+		})
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "template-comparison.csv", []string{
+			"template_id", "template_name", "total_builds", "failed_builds", "build_success_rate",
+			"median_build_seconds", "active_users", "latency_p50_ms", "latency_p95_ms",
+		}, len(templates), func(i int) []string {
+			return []string{
+				templates[i].TemplateID.String(),
+				templates[i].TemplateName,
+				strconv.FormatInt(templates[i].TotalBuilds, 10),
+				strconv.FormatInt(templates[i].FailedBuilds, 10),
+				strconv.FormatFloat(templates[i].BuildSuccessRate, 'f', -1, 64),
+				strconv.FormatFloat(templates[i].MedianBuildSeconds, 'f', -1, 64),
+				strconv.FormatInt(templates[i].ActiveUsers, 10),
+				strconv.FormatFloat(templates[i].WorkspaceConnectionLatencyMS.P50, 'f', -1, 64),
+				strconv.FormatFloat(templates[i].WorkspaceConnectionLatencyMS.P95, 'f', -1, 64),
+			}
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateComparisonInsightsResponse{
+		Report: codersdk.TemplateComparisonInsightsReport{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Templates: templates,
+		},
+	})
+}
+
+// @Summary Get insights about access method usage
+// @ID get-insights-about-access-method-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param interval query string false "Interval, defaults to day" enums(month,week,day)
+// @Param timezone query string false "IANA timezone name to bucket intervals in (e.g. America/St_Johns), defaults to the UTC offset of the start time"
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.AccessMethodInsightsResponse
+// @Router /api/v2/insights/access-methods [get]
+func (api *API) insightsAccessMethods(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		intervalString  = p.String(vals, "", "interval")
+		timezoneString  = p.String(vals, "", "timezone")
+		formatString    = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	loc, tz, ok := parseInsightsTimezone(ctx, rw, timezoneString, startTime)
+	if !ok {
+		return
+	}
+	startTime, endTime = startTime.In(loc), endTime.In(loc)
+	if intervalString == "" {
+		intervalString = string(codersdk.InsightsReportIntervalDay)
+	}
+	interval, ok := parseInsightsInterval(ctx, rw, intervalString, startTime, endTime)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetTemplateAccessMethodInsights(ctx, database.GetTemplateAccessMethodInsightsParams{
+		Tz:             tz,
+		IntervalMonths: interval.Months(),
+		IntervalDays:   interval.Days(),
+		StartTime:      startTime,
+		EndTime:        endTime,
+		TemplateIDs:    templateIDs,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching access method insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "access-methods.csv", []string{
+			"start_time", "end_time", "template_id", "template_name",
+			"vscode_seconds", "jetbrains_seconds", "reconnecting_pty_seconds", "ssh_seconds", "sftp_seconds",
+		}, len(rows), func(i int) []string {
+			return []string{
+				rows[i].StartTime.Format(time.RFC3339),
+				rows[i].EndTime.Format(time.RFC3339),
+				rows[i].TemplateID.String(),
+				rows[i].TemplateName,
+				strconv.FormatInt(rows[i].UsageVscodeSeconds, 10),
+				strconv.FormatInt(rows[i].UsageJetbrainsSeconds, 10),
+				strconv.FormatInt(rows[i].UsageReconnectingPtySeconds, 10),
+				strconv.FormatInt(rows[i].UsageSshSeconds, 10),
+				strconv.FormatInt(rows[i].UsageSftpSeconds, 10),
+			}
+		})
+		return
+	}
+
+	// Rows are ordered by template, so the intervals of a template are
+	// consecutive.
+	templates := []codersdk.TemplateAccessMethodInsight{}
+	var totals []int64
+	for _, row := range rows {
+		seconds := []int64{
+			row.UsageVscodeSeconds,
+			row.UsageJetbrainsSeconds,
+			row.UsageReconnectingPtySeconds,
+			row.UsageSshSeconds,
+			row.UsageSftpSeconds,
+		}
+		if len(templates) == 0 || templates[len(templates)-1].TemplateID != row.TemplateID {
+			if len(templates) > 0 {
+				templates[len(templates)-1].AccessMethods = accessMethodUsage(totals)
+			}
+			templates = append(templates, codersdk.TemplateAccessMethodInsight{
+				TemplateID:   row.TemplateID,
+				TemplateName: row.TemplateName,
+				Intervals:    []codersdk.AccessMethodIntervalReport{},
+			})
+			totals = make([]int64, len(seconds))
+		}
+		for i, s := range seconds {
+			totals[i] += s
+		}
+		tpl := &templates[len(templates)-1]
+		tpl.Intervals = append(tpl.Intervals, codersdk.AccessMethodIntervalReport{
+			StartTime:     row.StartTime,
+			EndTime:       row.EndTime,
+			AccessMethods: accessMethodUsage(seconds),
+		})
+	}
+	if len(templates) > 0 {
+		templates[len(templates)-1].AccessMethods = accessMethodUsage(totals)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AccessMethodInsightsResponse{
+		Report: codersdk.AccessMethodInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: templateIDs,
+			Interval:    interval,
+			Templates:   templates,
+		},
+	})
+}
+
+// accessMethodUsage converts the session seconds of the builtin access
+// methods, in the order VS Code, JetBrains, web terminal, SSH and SFTP, to
+// their usage shares.
+func accessMethodUsage(seconds []int64) []codersdk.AccessMethodUsage {
+	methods := []codersdk.AccessMethodUsage{
+		{Slug: "vscode", DisplayName: codersdk.TemplateBuiltinAppDisplayNameVSCode},
+		{Slug: "jetbrains", DisplayName: codersdk.TemplateBuiltinAppDisplayNameJetBrains},
+		{Slug: "reconnecting-pty", DisplayName: codersdk.TemplateBuiltinAppDisplayNameWebTerminal},
+		{Slug: "ssh", DisplayName: codersdk.TemplateBuiltinAppDisplayNameSSH},
+		{Slug: "sftp", DisplayName: codersdk.TemplateBuiltinAppDisplayNameSFTP},
+	}
+	var total int64
+	for _, s := range seconds {
+		total += s
+	}
+	for i := range methods {
+		methods[i].Seconds = seconds[i]
+		if total > 0 {
+			methods[i].Share = float64(seconds[i]) / float64(total)
+		}
+	}
+	return methods
+}
+
+// @Summary Get insights about workspace costs
+// @ID get-insights-about-workspace-costs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param group_by query string false "Group costs by" enums(workspace,user,organization)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.CostInsightsResponse
+// @Router /api/v2/insights/costs [get]
+func (api *API) insightsCosts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		groupByString   = p.String(vals, "", "group_by")
+		formatString    = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	groupBy, ok := parseCostInsightsGroupBy(ctx, rw, groupByString)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceCostInsights(ctx, database.GetWorkspaceCostInsightsParams{
+		GroupBy:   string(groupBy),
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace costs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "costs.csv", []string{"id", "name", "running_seconds", "cost"}, len(rows), func(i int) []string {
+			return []string{
+				rows[i].ID.String(),
+				rows[i].Name,
+				strconv.FormatInt(rows[i].RunningSeconds, 10),
+				strconv.FormatFloat(rows[i].Cost, 'f', -1, 64),
+			}
+		})
+		return
+	}
+
+	report := codersdk.CostInsightsReport{
+		StartTime: startTime,
+		EndTime:   endTime,
+		GroupBy:   groupBy,
+		Entries:   make([]codersdk.CostInsight, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.TotalCost += row.Cost
+		report.Entries = append(report.Entries, codersdk.CostInsight{
+			ID:             row.ID,
+			Name:           row.Name,
+			RunningSeconds: row.RunningSeconds,
+			Cost:           row.Cost,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.CostInsightsResponse{
+		Report: report,
+	})
+}
+
+// @Summary Get insights about workspace health
+// @ID get-insights-about-workspace-health
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.WorkspaceHealthInsightsResponse
+// @Router /api/v2/insights/workspace-health [get]
+func (api *API) insightsWorkspaceHealth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	var (
+		templateIDs  = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		formatString = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+
+	now := dbtime.Now()
+	rows, err := api.Database.GetWorkspaceHealthInsights(ctx, database.GetWorkspaceHealthInsightsParams{
+		BuildsSince:   now.Add(-workspaceHealthBuildsWindow),
+		LatencySince:  now.Add(-workspaceHealthLatencyWindow),
+		BaselineSince: now.Add(-workspaceHealthBaselineWindow),
+		TemplateIDs:   templateIDs,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace health.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	agentsByJobID, appsByAgentID, err := api.workspaceHealthAgents(ctx, rows)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	report := codersdk.WorkspaceHealthInsightsReport{
+		GeneratedAt: now,
+		TemplateIDs: templateIDs,
+		Workspaces:  make([]codersdk.WorkspaceHealthInsight, 0, len(rows)),
+	}
+	for _, row := range rows {
+		agents := agentsByJobID[row.LatestBuildJobID]
+		var apps []database.WorkspaceApp
+		for _, agent := range agents {
+			apps = append(apps, appsByAgentID[agent.ID]...)
+		}
+		report.Workspaces = append(report.Workspaces, workspaceHealthInsight(row, agents, apps, now, api.AgentInactiveDisconnectTimeout))
+	}
+	// Show the least healthy workspaces first, they are the ones to triage.
+	slices.SortStableFunc(report.Workspaces, func(a, b codersdk.WorkspaceHealthInsight) int {
+		if a.Score != b.Score {
+			return a.Score - b.Score
+		}
+		return strings.Compare(a.WorkspaceName, b.WorkspaceName)
+	})
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "workspace-health.csv", []string{"workspace_id", "workspace_name", "owner_username", "template_name", "score", "status"}, len(report.Workspaces), func(i int) []string {
+			ws := report.Workspaces[i]
+			return []string{
+				ws.WorkspaceID.String(),
+				ws.WorkspaceName,
+				ws.OwnerUsername,
+				ws.TemplateName,
+				strconv.Itoa(ws.Score),
+				string(ws.Status),
+			}
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceHealthInsightsResponse{
+		Report: report,
+	})
+}
+
+// workspaceHealthAgents fetches the agents in the latest builds of the given
+// workspaces, keyed by build job, and their apps, keyed by agent.
+func (api *API) workspaceHealthAgents(ctx context.Context, rows []database.GetWorkspaceHealthInsightsRow) (map[uuid.UUID][]database.WorkspaceAgent, map[uuid.UUID][]database.WorkspaceApp, error) {
+	jobIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		jobIDs = append(jobIDs, row.LatestBuildJobID)
+	}
+	// nolint:gocritic // Getting workspace resources by job ID is a system function.
+	resources, err := api.Database.GetWorkspaceResourcesByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, xerrors.Errorf("get workspace resources by job: %w", err)
+	}
+	if len(resources) == 0 {
+		return nil, nil, nil
+	}
+
+	jobIDByResourceID := make(map[uuid.UUID]uuid.UUID, len(resources))
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		jobIDByResourceID[resource.ID] = resource.JobID
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	// nolint:gocritic // Getting workspace agents by resource IDs is a system function.
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, xerrors.Errorf("get workspace agents: %w", err)
+	}
+	if len(agents) == 0 {
+		return nil, nil, nil
+	}
+
+	agentsByJobID := make(map[uuid.UUID][]database.WorkspaceAgent)
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		jobID := jobIDByResourceID[agent.ResourceID]
+		agentsByJobID[jobID] = append(agentsByJobID[jobID], agent)
+		agentIDs = append(agentIDs, agent.ID)
+	}
+	// nolint:gocritic // Getting workspace apps by agent IDs is a system function.
+	apps, err := api.Database.GetWorkspaceAppsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, xerrors.Errorf("get workspace apps: %w", err)
+	}
+	appsByAgentID := make(map[uuid.UUID][]database.WorkspaceApp)
+	for _, app := range apps {
+		appsByAgentID[app.AgentID] = append(appsByAgentID[app.AgentID], app)
+	}
+	return agentsByJobID, appsByAgentID, nil
+}
+
+const (
+	// workspaceHealthLatencyWindow is the window of the current latency, the
+	// baseline latency is measured over the rest of the baseline window.
+	workspaceHealthLatencyWindow  = time.Hour
+	workspaceHealthBaselineWindow = 7 * 24 * time.Hour
+	workspaceHealthBuildsWindow   = 7 * 24 * time.Hour
+
+	// Workspaces scoring at least workspaceHealthHealthyScore are healthy,
+	// and at least workspaceHealthDegradedScore are degraded.
+	workspaceHealthHealthyScore  = 80
+	workspaceHealthDegradedScore = 50
+)
+
+// workspaceHealthInsight computes the composite health score of a workspace.
+// Components without any data to judge, like a workspace without apps, score
+// 100 so they don't drag the composite score down.
+func workspaceHealthInsight(row database.GetWorkspaceHealthInsightsRow, agents []database.WorkspaceAgent, apps []database.WorkspaceApp, now time.Time, agentInactiveTimeout time.Duration) codersdk.WorkspaceHealthInsight {
+	insight := codersdk.WorkspaceHealthInsight{
+		WorkspaceID:   row.WorkspaceID,
+		WorkspaceName: row.WorkspaceName,
+		OwnerID:       row.OwnerID,
+		OwnerUsername: row.OwnerUsername,
+		TemplateID:    row.TemplateID,
+		TemplateName:  row.TemplateName,
+		Connectivity: codersdk.WorkspaceHealthConnectivity{
+			Score:       100,
+			AgentsTotal: len(agents),
+		},
+		Latency: codersdk.WorkspaceHealthLatency{
+			Score:             100,
+			LatencyMS:         row.LatencyMs,
+			BaselineLatencyMS: row.BaselineLatencyMs,
+		},
+		Apps: codersdk.WorkspaceHealthApps{
+			Score: 100,
+		},
+		Builds: codersdk.WorkspaceHealthBuilds{
+			Score:             100,
+			LatestBuildFailed: row.LatestBuildFailed,
+			Builds:            row.RecentBuilds,
+			FailedBuilds:      row.RecentFailedBuilds,
+		},
+	}
+
+	for _, agent := range agents {
+		if agent.Status(now, agentInactiveTimeout).Status == database.WorkspaceAgentStatusConnected {
+			insight.Connectivity.AgentsConnected++
+		}
+	}
+	switch {
+	case len(agents) > 0:
+		insight.Connectivity.Score = 100 * insight.Connectivity.AgentsConnected / len(agents)
+	case row.LatestBuildFailed:
+		// The build failed before creating any agent.
+		insight.Connectivity.Score = 0
+	}
+
+	// Every 10% of latency above the baseline costs 5 points, so a workspace
+	// with three times its usual latency scores 0.
+	if row.LatencyMs > 0 && row.BaselineLatencyMs > 0 {
+		ratio := row.LatencyMs / row.BaselineLatencyMs
+		insight.Latency.Score = clampHealthScore(100 - (ratio-1)*50)
+	}
+
+	for _, app := range apps {
+		switch app.Health {
+		case database.WorkspaceAppHealthHealthy:
+			insight.Apps.Healthy++
+		case database.WorkspaceAppHealthInitializing:
+			insight.Apps.Initializing++
+		case database.WorkspaceAppHealthUnhealthy:
+			insight.Apps.Unhealthy++
+		}
+	}
+	if checked := insight.Apps.Healthy + insight.Apps.Initializing + insight.Apps.Unhealthy; checked > 0 {
+		insight.Apps.Score = 100 * insight.Apps.Healthy / checked
+	}
+
+	switch {
+	case row.LatestBuildFailed:
+		insight.Builds.Score = 0
+	case row.RecentBuilds > 0:
+		insight.Builds.Score = int(100 * (row.RecentBuilds - row.RecentFailedBuilds) / row.RecentBuilds)
+	}
+
+	insight.Score = clampHealthScore(0.4*float64(insight.Connectivity.Score) +
+		0.2*float64(insight.Latency.Score) +
+		0.2*float64(insight.Apps.Score) +
+		0.2*float64(insight.Builds.Score))
+	switch {
+	case insight.Score >= workspaceHealthHealthyScore:
+		insight.Status = codersdk.WorkspaceHealthStatusHealthy
+	case insight.Score >= workspaceHealthDegradedScore:
+		insight.Status = codersdk.WorkspaceHealthStatusDegraded
+	default:
+		insight.Status = codersdk.WorkspaceHealthStatusUnhealthy
+	}
+	return insight
+}
+
+func clampHealthScore(score float64) int {
+	return int(math.Round(max(0, min(100, score))))
+}
+
+// @Summary Get insights about prebuilt workspaces
+// @ID get-insights-about-prebuilt-workspaces
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
+// @Success 200 {object} codersdk.PrebuildInsightsResponse
+// @Router /api/v2/insights/prebuilds [get]
+func (api *API) insightsPrebuilds(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceDeploymentStats) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		RequiredNotEmpty("start_time").
+		RequiredNotEmpty("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		formatString    = p.String(vals, "", "format")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
 
-	rows, err := api.Database.GetUserActivityInsights(ctx, database.GetUserActivityInsightsParams{
+	rows, err := api.Database.GetPrebuildInsights(ctx, database.GetPrebuildInsightsParams{
 		StartTime:   startTime,
 		EndTime:     endTime,
 		TemplateIDs: templateIDs,
 	})
 	if err != nil {
-		// No data is not an error.
-		if xerrors.Is(err, sql.ErrNoRows) {
-			httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserActivityInsightsResponse{
-				Report: codersdk.UserActivityInsightsReport{
-					StartTime:   startTime,
-					EndTime:     endTime,
-					TemplateIDs: []uuid.UUID{},
-					Users:       []codersdk.UserActivity{},
-				},
-			})
-			return
-		}
-		// Check authorization.
-		if httpapi.Is404Error(err) {
-			httpapi.ResourceNotFound(rw)
-			return
-		}
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching user activity.",
+			Message: "Internal error fetching prebuild insights.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	templateIDSet := make(map[uuid.UUID]struct{})
-	userActivities := make([]codersdk.UserActivity, 0, len(rows))
-	for _, row := range rows {
-		for _, templateID := range row.TemplateIDs {
-			templateIDSet[templateID] = struct{}{}
-		}
-		userActivities = append(userActivities, codersdk.UserActivity{
-			TemplateIDs: row.TemplateIDs,
-			UserID:      row.UserID,
-			Username:    row.Username,
-			AvatarURL:   row.AvatarURL,
-			Seconds:     row.UsageSeconds,
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "prebuilds.csv", []string{"template_id", "template_name", "preset_name", "prebuilds_created", "claims", "misses", "claim_latency_seconds_50", "claim_latency_seconds_95", "idle_seconds_50", "idle_seconds_95"}, len(rows), func(i int) []string {
+			return []string{
+				rows[i].TemplateID.String(),
+				rows[i].TemplateName,
+				rows[i].PresetName,
+				strconv.FormatInt(rows[i].PrebuildsCreated, 10),
+				strconv.FormatInt(rows[i].Claims, 10),
+				strconv.FormatInt(rows[i].Misses, 10),
+				strconv.FormatFloat(rows[i].ClaimLatencySeconds50, 'f', -1, 64),
+				strconv.FormatFloat(rows[i].ClaimLatencySeconds95, 'f', -1, 64),
+				strconv.FormatFloat(rows[i].IdleSeconds50, 'f', -1, 64),
+				strconv.FormatFloat(rows[i].IdleSeconds95, 'f', -1, 64),
+			}
 		})
+		return
 	}
 
-	// TemplateIDs that contributed to the data.
-	seenTemplateIDs := make([]uuid.UUID, 0, len(templateIDSet))
-	for templateID := range templateIDSet {
-		seenTemplateIDs = append(seenTemplateIDs, templateID)
+	report := codersdk.PrebuildInsightsReport{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+		Presets:     make([]codersdk.PrebuildPresetInsight, 0, len(rows)),
 	}
-	slices.SortFunc(seenTemplateIDs, func(a, b uuid.UUID) int {
-		return slice.Ascending(a.String(), b.String())
+	for _, row := range rows {
+		report.Claims += row.Claims
+		report.Misses += row.Misses
+		report.Presets = append(report.Presets, codersdk.PrebuildPresetInsight{
+			TemplateID:            row.TemplateID,
+			TemplateName:          row.TemplateName,
+			PresetName:            row.PresetName,
+			PrebuildsCreated:      row.PrebuildsCreated,
+			Claims:                row.Claims,
+			Misses:                row.Misses,
+			HitRate:               prebuildHitRate(row.Claims, row.Misses),
+			ClaimLatencySeconds50: row.ClaimLatencySeconds50,
+			ClaimLatencySeconds95: row.ClaimLatencySeconds95,
+			IdleSeconds50:         row.IdleSeconds50,
+			IdleSeconds95:         row.IdleSeconds95,
+		})
+	}
+	report.HitRate = prebuildHitRate(report.Claims, report.Misses)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.PrebuildInsightsResponse{
+		Report: report,
 	})
+}
 
-	resp := codersdk.UserActivityInsightsResponse{
-		Report: codersdk.UserActivityInsightsReport{
-			StartTime:   startTime,
-			EndTime:     endTime,
-			TemplateIDs: seenTemplateIDs,
-			Users:       userActivities,
-		},
+// prebuildHitRate returns the share of workspace creations served from a
+// prebuild pool.
+func prebuildHitRate(claims, misses int64) float64 {
+	if claims+misses == 0 {
+		return 0
 	}
-	httpapi.Write(ctx, rw, http.StatusOK, resp)
+	return float64(claims) / float64(claims+misses)
 }
 
-// @Summary Get insights about user latency
-// @ID get-insights-about-user-latency
+// @Summary Get insights about an organization
+// @ID get-insights-about-an-organization
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Insights
+// @Param organization path string true "Organization ID" format(uuid)
 // @Param start_time query string true "Start time" format(date-time)
 // @Param end_time query string true "End time" format(date-time)
-// @Param template_ids query []string false "Template IDs" collectionFormat(csv)
-// @Success 200 {object} codersdk.UserLatencyInsightsResponse
-// @Router /api/v2/insights/user-latency [get]
-func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
+// @Success 200 {object} codersdk.OrganizationInsightsResponse
+// @Router /api/v2/organizations/{organization}/insights [get]
+func (api *API) insightsOrganization(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+
+	// Organization insights span every template in the organization, so
+	// they're limited to those who can view the insights of all of them,
+	// e.g. organization admins and auditors.
+	if !api.Authorize(r, policy.ActionViewInsights, rbac.ResourceTemplate.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// Active users and latency depend on the usage stats.
+	if !api.DeploymentValues.StatsCollection.UsageStats.Enable.Value() {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Not Found.",
+			Detail:  "Template insights are disabled.",
+		})
+		return
+	}
 
 	p := httpapi.NewQueryParamParser().
 		RequiredNotEmpty("start_time").
@@ -222,7 +1622,6 @@ func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
 		// to parse the time ourselves.
 		startTimeString = p.String(vals, "", "start_time")
 		endTimeString   = p.String(vals, "", "end_time")
-		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
 	)
 	p.ErrorExcessParams(vals)
 	if len(p.Errors) > 0 {
@@ -238,10 +1637,10 @@ func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := api.Database.GetUserLatencyInsights(ctx, database.GetUserLatencyInsightsParams{
-		StartTime:   startTime,
-		EndTime:     endTime,
-		TemplateIDs: templateIDs,
+	row, err := api.Database.GetOrganizationInsights(ctx, database.GetOrganizationInsightsParams{
+		OrganizationID: organization.ID,
+		StartTime:      startTime,
+		EndTime:        endTime,
 	})
 	if err != nil {
 		if httpapi.Is404Error(err) {
@@ -249,45 +1648,35 @@ func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching user latency.",
+			Message: "Internal error fetching organization insights.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	templateIDSet := make(map[uuid.UUID]struct{})
-	userLatencies := make([]codersdk.UserLatency, 0, len(rows))
-	for _, row := range rows {
-		for _, templateID := range row.TemplateIDs {
-			templateIDSet[templateID] = struct{}{}
-		}
-		userLatencies = append(userLatencies, codersdk.UserLatency{
-			TemplateIDs: row.TemplateIDs,
-			UserID:      row.UserID,
-			Username:    row.Username,
-			AvatarURL:   row.AvatarURL,
+	resp := codersdk.OrganizationInsightsResponse{
+		Report: codersdk.OrganizationInsightsReport{
+			OrganizationID: organization.ID,
+			StartTime:      startTime,
+			EndTime:        endTime,
+			ActiveUsers:    row.ActiveUsers,
+			Workspaces: codersdk.OrganizationWorkspaceInsights{
+				Total:   row.TotalWorkspaces,
+				Running: row.RunningWorkspaces,
+			},
+			Builds: codersdk.DeploymentBuildInsights{
+				Total:  row.TotalBuilds,
+				Failed: row.FailedBuilds,
+			},
+			Bandwidth: codersdk.DeploymentBandwidthInsights{
+				StartTime: row.BandwidthStartTime.In(startTime.Location()),
+				RxBytes:   row.WorkspaceRxBytes,
+				TxBytes:   row.WorkspaceTxBytes,
+			},
 			LatencyMS: codersdk.ConnectionLatency{
 				P50: row.WorkspaceConnectionLatency50,
 				P95: row.WorkspaceConnectionLatency95,
 			},
-		})
-	}
-
-	// TemplateIDs that contributed to the data.
-	seenTemplateIDs := make([]uuid.UUID, 0, len(templateIDSet))
-	for templateID := range templateIDSet {
-		seenTemplateIDs = append(seenTemplateIDs, templateID)
-	}
-	slices.SortFunc(seenTemplateIDs, func(a, b uuid.UUID) int {
-		return slice.Ascending(a.String(), b.String())
-	})
-
-	resp := codersdk.UserLatencyInsightsResponse{
-		Report: codersdk.UserLatencyInsightsReport{
-			StartTime:   startTime,
-			EndTime:     endTime,
-			TemplateIDs: seenTemplateIDs,
-			Users:       userLatencies,
 		},
 	}
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
@@ -393,8 +1782,11 @@ func (api *API) insightsUserStatusCounts(rw http.ResponseWriter, r *http.Request
 // @Tags Insights
 // @Param start_time query string true "Start time" format(date-time)
 // @Param end_time query string true "End time" format(date-time)
-// @Param interval query string true "Interval" enums(week,day)
+// @Param interval query string true "Interval" enums(month,week,day)
+// @Param timezone query string false "IANA timezone name to bucket intervals in (e.g. America/St_Johns), defaults to the UTC offset of the start time"
 // @Param template_ids query []string false "Template IDs" collectionFormat(csv)
+// @Param fields query []string false "Fields of the report to include, all of them by default" enums(active_users,apps_usage,parameters_usage) collectionFormat(csv)
+// @Param format query string false "Response format, csv can also be requested with an Accept: text/csv header" enums(json,csv)
 // @Success 200 {object} codersdk.TemplateInsightsResponse
 // @Router /api/v2/insights/templates [get]
 func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
@@ -410,8 +1802,11 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 		startTimeString = p.String(vals, "", "start_time")
 		endTimeString   = p.String(vals, "", "end_time")
 		intervalString  = p.String(vals, "", "interval")
+		timezoneString  = p.String(vals, "", "timezone")
 		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
 		sectionStrings  = p.Strings(vals, templateInsightsSectionAsStrings(codersdk.TemplateInsightsSectionIntervalReports, codersdk.TemplateInsightsSectionReport), "sections")
+		fieldStrings    = p.Strings(vals, templateInsightsFieldsAsStrings(codersdk.TemplateInsightsFields...), "fields")
+		formatString    = p.String(vals, "", "format")
 	)
 	p.ErrorExcessParams(vals)
 	if len(p.Errors) > 0 {
@@ -426,6 +1821,11 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	loc, tz, ok := parseInsightsTimezone(ctx, rw, timezoneString, startTime)
+	if !ok {
+		return
+	}
+	startTime, endTime = startTime.In(loc), endTime.In(loc)
 	interval, ok := parseInsightsInterval(ctx, rw, intervalString, startTime, endTime)
 	if !ok {
 		return
@@ -434,6 +1834,25 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	fields, ok := parseTemplateInsightsFields(ctx, rw, fieldStrings)
+	if !ok {
+		return
+	}
+	format, ok := parseInsightsFormat(ctx, rw, r, formatString)
+	if !ok {
+		return
+	}
+	if format == insightsFormatCSV {
+		// Only the interval reports are tabular, so they are the only
+		// section exported as CSV.
+		if interval == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query parameter \"interval\" is required for the CSV format.",
+			})
+			return
+		}
+		sections = []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionIntervalReports}
+	}
 
 	var usage database.GetTemplateInsightsRow
 	var appUsage []database.GetTemplateAppInsightsRow
@@ -449,11 +1868,13 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	eg.Go(func() error {
 		var err error
 		if interval != "" && slices.Contains(sections, codersdk.TemplateInsightsSectionIntervalReports) {
-			dailyUsage, err = api.Database.GetTemplateInsightsByInterval(egCtx, database.GetTemplateInsightsByIntervalParams{
-				StartTime:    startTime,
-				EndTime:      endTime,
-				TemplateIDs:  templateIDs,
-				IntervalDays: interval.Days(),
+			dailyUsage, err = insightscache.Get(egCtx, api.insightsCache, "GetTemplateInsightsByInterval", api.Database.GetTemplateInsightsByInterval, database.GetTemplateInsightsByIntervalParams{
+				StartTime:      startTime,
+				EndTime:        endTime,
+				TemplateIDs:    templateIDs,
+				Tz:             tz,
+				IntervalMonths: interval.Months(),
+				IntervalDays:   interval.Days(),
 			})
 			if err != nil {
 				return xerrors.Errorf("get template daily insights: %w", err)
@@ -461,13 +1882,18 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+	report := slices.Contains(sections, codersdk.TemplateInsightsSectionReport)
+	includeActiveUsers := report && slices.Contains(fields, codersdk.TemplateInsightsFieldActiveUsers)
+	includeAppsUsage := report && slices.Contains(fields, codersdk.TemplateInsightsFieldAppsUsage)
+	includeParametersUsage := report && slices.Contains(fields, codersdk.TemplateInsightsFieldParametersUsage)
 	eg.Go(func() error {
-		if !slices.Contains(sections, codersdk.TemplateInsightsSectionReport) {
+		// The usage of builtin apps is aggregated with the active users.
+		if !includeActiveUsers && !includeAppsUsage {
 			return nil
 		}
 
 		var err error
-		usage, err = api.Database.GetTemplateInsights(egCtx, database.GetTemplateInsightsParams{
+		usage, err = insightscache.Get(egCtx, api.insightsCache, "GetTemplateInsights", api.Database.GetTemplateInsights, database.GetTemplateInsightsParams{
 			StartTime:   startTime,
 			EndTime:     endTime,
 			TemplateIDs: templateIDs,
@@ -478,12 +1904,12 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 	eg.Go(func() error {
-		if !slices.Contains(sections, codersdk.TemplateInsightsSectionReport) {
+		if !includeAppsUsage {
 			return nil
 		}
 
 		var err error
-		appUsage, err = api.Database.GetTemplateAppInsights(egCtx, database.GetTemplateAppInsightsParams{
+		appUsage, err = insightscache.Get(egCtx, api.insightsCache, "GetTemplateAppInsights", api.Database.GetTemplateAppInsights, database.GetTemplateAppInsightsParams{
 			StartTime:   startTime,
 			EndTime:     endTime,
 			TemplateIDs: templateIDs,
@@ -497,12 +1923,12 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	// Template parameter insights have no risk of inconsistency with the other
 	// insights.
 	eg.Go(func() error {
-		if !slices.Contains(sections, codersdk.TemplateInsightsSectionReport) {
+		if !includeParametersUsage {
 			return nil
 		}
 
 		var err error
-		parameterRows, err = api.Database.GetTemplateParameterInsights(ctx, database.GetTemplateParameterInsightsParams{
+		parameterRows, err = insightscache.Get(ctx, api.insightsCache, "GetTemplateParameterInsights", api.Database.GetTemplateParameterInsights, database.GetTemplateParameterInsightsParams{
 			StartTime:   startTime,
 			EndTime:     endTime,
 			TemplateIDs: templateIDs,
@@ -539,15 +1965,37 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 		IntervalReports: []codersdk.TemplateInsightsIntervalReport{},
 	}
 
-	if slices.Contains(sections, codersdk.TemplateInsightsSectionReport) {
+	if report {
+		if usage.TemplateIDs == nil {
+			usage.TemplateIDs = []uuid.UUID{}
+		}
 		resp.Report = &codersdk.TemplateInsightsReport{
-			StartTime:       startTime,
-			EndTime:         endTime,
-			TemplateIDs:     usage.TemplateIDs,
-			ActiveUsers:     usage.ActiveUsers,
-			AppsUsage:       convertTemplateInsightsApps(usage, appUsage),
-			ParametersUsage: parametersUsage,
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: usage.TemplateIDs,
+		}
+		if includeActiveUsers {
+			resp.Report.ActiveUsers = &usage.ActiveUsers
 		}
+		if includeAppsUsage {
+			resp.Report.AppsUsage = convertTemplateInsightsApps(usage, appUsage)
+		}
+		if includeParametersUsage {
+			resp.Report.ParametersUsage = parametersUsage
+		}
+	}
+
+	if format == insightsFormatCSV {
+		api.writeInsightsCSV(ctx, rw, "templates.csv", []string{"start_time", "end_time", "interval", "template_ids", "active_users"}, len(dailyUsage), func(i int) []string {
+			return []string{
+				dailyUsage[i].StartTime.In(startTime.Location()).Format(insightsTimeLayout),
+				dailyUsage[i].EndTime.In(startTime.Location()).Format(insightsTimeLayout),
+				string(interval),
+				insightsCSVTemplateIDs(dailyUsage[i].TemplateIDs),
+				strconv.FormatInt(dailyUsage[i].ActiveUsers, 10),
+			}
+		})
+		return
 	}
 
 	for _, row := range dailyUsage {
@@ -769,13 +2217,22 @@ func parseInsightsInterval(ctx context.Context, rw http.ResponseWriter, interval
 			return "", false
 		}
 		return v, true
+	case codersdk.InsightsReportIntervalMonth:
+		if !lastReportIntervalHasAtLeastFourWeeks(startTime, endTime) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query parameter has invalid value.",
+				Detail:  "Last report interval should have at least 28 days.",
+			})
+			return "", false
+		}
+		return v, true
 	default:
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Query parameter has invalid value.",
 			Validations: []codersdk.ValidationError{
 				{
 					Field:  "interval",
-					Detail: fmt.Sprintf("must be one of %v", []codersdk.InsightsReportInterval{codersdk.InsightsReportIntervalDay, codersdk.InsightsReportIntervalWeek}),
+					Detail: fmt.Sprintf("must be one of %v", []codersdk.InsightsReportInterval{codersdk.InsightsReportIntervalDay, codersdk.InsightsReportIntervalWeek, codersdk.InsightsReportIntervalMonth}),
 				},
 			},
 		})
@@ -793,6 +2250,69 @@ func lastReportIntervalHasAtLeastSixDays(startTime, endTime time.Time) bool {
 	return lastReportIntervalDays >= 6*24*time.Hour || startTime.AddDate(0, 0, 6).Equal(endTime)
 }
 
+// lastReportIntervalHasAtLeastFourWeeks reports whether the last monthly
+// interval between start and end time is either a full month or at least 28
+// days long. Monthly intervals follow the calendar months of the location of
+// the start time.
+func lastReportIntervalHasAtLeastFourWeeks(startTime, endTime time.Time) bool {
+	endTime = endTime.In(startTime.Location())
+	y, m, _ := endTime.Date()
+	lastStart := time.Date(y, m, 1, 0, 0, 0, 0, startTime.Location())
+	if lastStart.Equal(endTime) {
+		return true // this is a perfectly full month!
+	}
+	if lastStart.Before(startTime) {
+		lastStart = startTime
+	}
+	return !lastStart.AddDate(0, 0, 28).After(endTime)
+}
+
+// parseInsightsTimezone returns the location to bucket report intervals in
+// and its name for the database. Without an IANA timezone name, intervals are
+// bucketed in the UTC offset of the start time, which does not follow
+// daylight saving time changes.
+func parseInsightsTimezone(ctx context.Context, rw http.ResponseWriter, timezoneString string, startTime time.Time) (*time.Location, string, bool) {
+	if timezoneString == "" {
+		_, offset := startTime.Zone()
+		return startTime.Location(), utcOffsetTimezone(offset), true
+	}
+	loc, err := time.LoadLocation(timezoneString)
+	if err != nil || loc == time.Local {
+		detail := fmt.Sprintf("Query param %q must be an IANA timezone name", "timezone")
+		if err != nil {
+			detail += ": " + err.Error()
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "timezone",
+					Detail: detail,
+				},
+			},
+		})
+		return nil, "", false
+	}
+	// loc.String() returns the IANA timezone name, which PostgreSQL also
+	// understands as both use the IANA Time Zone Database.
+	return loc, loc.String(), true
+}
+
+// utcOffsetTimezone returns a POSIX timezone for a fixed UTC offset in
+// seconds. PostgreSQL reads POSIX offsets as west of UTC, so the sign is the
+// opposite of the ISO 8601 offset.
+func utcOffsetTimezone(offset int) string {
+	if offset == 0 {
+		return "UTC"
+	}
+	sign := "-"
+	if offset < 0 {
+		sign = "+"
+		offset = -offset
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d:%02d", sign, offset/3600, offset%3600/60, offset%60)
+}
+
 func templateInsightsSectionAsStrings(sections ...codersdk.TemplateInsightsSection) []string {
 	t := make([]string, len(sections))
 	for i, s := range sections {
@@ -822,3 +2342,127 @@ func parseTemplateInsightsSections(ctx context.Context, rw http.ResponseWriter,
 	}
 	return t, true
 }
+
+func templateInsightsFieldsAsStrings(fields ...codersdk.TemplateInsightsField) []string {
+	t := make([]string, len(fields))
+	for i, f := range fields {
+		t[i] = string(f)
+	}
+	return t
+}
+
+func parseTemplateInsightsFields(ctx context.Context, rw http.ResponseWriter, fields []string) ([]codersdk.TemplateInsightsField, bool) {
+	t := make([]codersdk.TemplateInsightsField, len(fields))
+	for i, f := range fields {
+		v := codersdk.TemplateInsightsField(f)
+		if !slices.Contains(codersdk.TemplateInsightsFields, v) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query parameter has invalid value.",
+				Validations: []codersdk.ValidationError{
+					{
+						Field:  "fields",
+						Detail: fmt.Sprintf("must be one of %v", codersdk.TemplateInsightsFields),
+					},
+				},
+			})
+			return nil, false
+		}
+		t[i] = v
+	}
+	return t, true
+}
+
+func parseCostInsightsGroupBy(ctx context.Context, rw http.ResponseWriter, groupByString string) (codersdk.CostInsightsGroupBy, bool) {
+	switch v := codersdk.CostInsightsGroupBy(groupByString); v {
+	case codersdk.CostInsightsGroupByWorkspace, codersdk.CostInsightsGroupByUser, codersdk.CostInsightsGroupByOrganization:
+		return v, true
+	case "":
+		return codersdk.CostInsightsGroupByWorkspace, true
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "group_by",
+					Detail: fmt.Sprintf("must be one of %v", []codersdk.CostInsightsGroupBy{codersdk.CostInsightsGroupByWorkspace, codersdk.CostInsightsGroupByUser, codersdk.CostInsightsGroupByOrganization}),
+				},
+			},
+		})
+		return "", false
+	}
+}
+
+type insightsFormat string
+
+const (
+	insightsFormatJSON insightsFormat = "json"
+	insightsFormatCSV  insightsFormat = "csv"
+)
+
+// parseInsightsFormat returns the format requested by the format query
+// parameter or, if it's not set, by the Accept header.
+func parseInsightsFormat(ctx context.Context, rw http.ResponseWriter, r *http.Request, formatString string) (insightsFormat, bool) {
+	switch v := insightsFormat(formatString); v {
+	case insightsFormatJSON, insightsFormatCSV:
+		return v, true
+	case "":
+		for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+			if mediaType, _, _ := mime.ParseMediaType(strings.TrimSpace(accept)); mediaType == "text/csv" {
+				return insightsFormatCSV, true
+			}
+		}
+		return insightsFormatJSON, true
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "format",
+					Detail: fmt.Sprintf("must be one of %v", []insightsFormat{insightsFormatJSON, insightsFormatCSV}),
+				},
+			},
+		})
+		return "", false
+	}
+}
+
+// insightsCSVFlushRows is the number of rows after which a CSV export is
+// flushed to the client.
+const insightsCSVFlushRows = 1000
+
+// writeInsightsCSV writes n records as a CSV attachment. Records are flushed
+// to the client while they are written, so large exports are streamed rather
+// than buffered in full.
+func (api *API) writeInsightsCSV(ctx context.Context, rw http.ResponseWriter, filename string, header []string, n int, record func(i int) []string) {
+	rw.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	rw.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	rw.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(rw)
+	w := csv.NewWriter(rw)
+	err := w.Write(header)
+	for i := 0; i < n && err == nil; i++ {
+		err = w.Write(record(i))
+		if (i+1)%insightsCSVFlushRows == 0 {
+			w.Flush()
+			_ = rc.Flush()
+		}
+	}
+	w.Flush()
+	if err == nil {
+		err = w.Error()
+	}
+	if err != nil {
+		// The status has already been written, so the client is left with a
+		// truncated export.
+		api.Logger.Debug(ctx, "failed to write insights csv", slog.F("filename", filename), slog.Error(err))
+	}
+}
+
+func insightsCSVTemplateIDs(templateIDs []uuid.UUID) string {
+	ids := make([]string, len(templateIDs))
+	for i, id := range templateIDs {
+		ids[i] = id.String()
+	}
+	return strings.Join(ids, ";")
+}
@@ -2,13 +2,19 @@ package coderd
 
 import (
 	"context"
+	"database/sql"
+	"encoding/csv"
+	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -334,6 +340,301 @@ func TestLastReportIntervalHasAtLeastSixDays(t *testing.T) {
 	}
 }
 
+func TestLastReportIntervalHasAtLeastFourWeeks(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	newYorkLoc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		startTime time.Time
+		endTime   time.Time
+		expected  bool
+	}{
+		{
+			name:      "perfectly full months",
+			startTime: time.Date(2024, time.January, 1, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.March, 1, 0, 0, 0, 0, loc),
+			expected:  true,
+		},
+		{
+			name:      "full February in a leap year",
+			startTime: time.Date(2024, time.February, 1, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.March, 1, 0, 0, 0, 0, loc),
+			expected:  true,
+		},
+		{
+			name:      "exactly 28 days after the last month",
+			startTime: time.Date(2024, time.January, 1, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.March, 29, 0, 0, 0, 0, loc),
+			expected:  true,
+		},
+		{
+			name:      "less than 28 days after the last month",
+			startTime: time.Date(2024, time.January, 1, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.March, 28, 23, 0, 0, 0, loc),
+			expected:  false,
+		},
+		{
+			name:      "less than a month",
+			startTime: time.Date(2024, time.January, 1, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.January, 15, 0, 0, 0, 0, loc),
+			expected:  false,
+		},
+		{
+			name:      "mid-month start until the end of the month",
+			startTime: time.Date(2024, time.January, 15, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.March, 1, 0, 0, 0, 0, loc),
+			expected:  true,
+		},
+		{
+			name:      "mid-month start into a short last month",
+			startTime: time.Date(2024, time.January, 15, 0, 0, 0, 0, loc),
+			endTime:   time.Date(2024, time.February, 15, 0, 0, 0, 0, loc),
+			expected:  false,
+		},
+		{
+			name:      "full months across a DST change",
+			startTime: time.Date(2024, time.February, 1, 0, 0, 0, 0, newYorkLoc),
+			endTime:   time.Date(2024, time.April, 1, 0, 0, 0, 0, newYorkLoc),
+			expected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := lastReportIntervalHasAtLeastFourWeeks(tc.startTime, tc.endTime)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_parseInsightsTimezone(t *testing.T) {
+	t.Parallel()
+
+	newYorkLoc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		timezone  string
+		startTime time.Time
+		wantLoc   *time.Location
+		wantTz    string
+		wantOk    bool
+	}{
+		{
+			name:      "UTC",
+			startTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			wantLoc:   time.UTC,
+			wantTz:    "UTC",
+			wantOk:    true,
+		},
+		{
+			name:      "OffsetEastOfUTC",
+			startTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.FixedZone("", 5*3600+30*60)),
+			wantTz:    "UTC-05:30:00",
+			wantOk:    true,
+		},
+		{
+			name:      "OffsetWestOfUTC",
+			startTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.FixedZone("", -5*3600)),
+			wantTz:    "UTC+05:00:00",
+			wantOk:    true,
+		},
+		{
+			name:      "IANA",
+			timezone:  "America/New_York",
+			startTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.FixedZone("", -5*3600)),
+			wantLoc:   newYorkLoc,
+			wantTz:    "America/New_York",
+			wantOk:    true,
+		},
+		{
+			name:      "Local",
+			timezone:  "Local",
+			startTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Unknown",
+			timezone:  "Mars/Olympus_Mons",
+			startTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rw := httptest.NewRecorder()
+			loc, tz, ok := parseInsightsTimezone(context.Background(), rw, tc.timezone, tc.startTime)
+			require.Equal(t, tc.wantOk, ok)
+			if !tc.wantOk {
+				require.Equal(t, http.StatusBadRequest, rw.Code)
+				return
+			}
+			require.Equal(t, tc.wantTz, tz)
+			if tc.wantLoc == nil {
+				tc.wantLoc = tc.startTime.Location()
+			}
+			require.Equal(t, tc.wantLoc.String(), loc.String())
+		})
+	}
+}
+
+func Test_parseInsightsFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		format string
+		accept string
+		want   insightsFormat
+		wantOk bool
+	}{
+		{name: "Default", want: insightsFormatJSON, wantOk: true},
+		{name: "JSON", format: "json", want: insightsFormatJSON, wantOk: true},
+		{name: "CSV", format: "csv", want: insightsFormatCSV, wantOk: true},
+		{name: "AcceptCSV", accept: "application/json, text/csv; charset=utf-8", want: insightsFormatCSV, wantOk: true},
+		{name: "FormatOverridesAccept", format: "json", accept: "text/csv", want: insightsFormatJSON, wantOk: true},
+		{name: "Invalid", format: "xml", wantOk: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			rw := httptest.NewRecorder()
+			format, ok := parseInsightsFormat(context.Background(), rw, r, tc.format)
+			require.Equal(t, tc.wantOk, ok)
+			if !tc.wantOk {
+				require.Equal(t, http.StatusBadRequest, rw.Code)
+				return
+			}
+			require.Equal(t, tc.want, format)
+		})
+	}
+}
+
+func TestWriteInsightsCSV(t *testing.T) {
+	t.Parallel()
+
+	api := &API{
+		Options: &Options{Logger: slogtest.Make(t, nil)},
+	}
+	records := make([][]string, insightsCSVFlushRows+1)
+	for i := range records {
+		records[i] = []string{strconv.Itoa(i), "user, with a comma"}
+	}
+
+	rw := httptest.NewRecorder()
+	api.writeInsightsCSV(context.Background(), rw, "users.csv", []string{"id", "name"}, len(records), func(i int) []string {
+		return records[i]
+	})
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "text/csv; charset=utf-8", rw.Header().Get("Content-Type"))
+	require.Equal(t, "attachment; filename=users.csv", rw.Header().Get("Content-Disposition"))
+
+	got, err := csv.NewReader(rw.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, got, len(records)+1)
+	require.Equal(t, []string{"id", "name"}, got[0])
+	require.Equal(t, records, got[1:])
+}
+
+func Test_workspaceHealthInsight(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	connected := database.WorkspaceAgent{
+		FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+		LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+	}
+	disconnected := database.WorkspaceAgent{
+		FirstConnectedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+		LastConnectedAt:  sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+		DisconnectedAt:   sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+	}
+	app := func(health database.WorkspaceAppHealth) database.WorkspaceApp {
+		return database.WorkspaceApp{Health: health}
+	}
+
+	testCases := []struct {
+		name       string
+		row        database.GetWorkspaceHealthInsightsRow
+		agents     []database.WorkspaceAgent
+		apps       []database.WorkspaceApp
+		wantScore  int
+		wantStatus codersdk.WorkspaceHealthStatus
+	}{
+		{
+			name:       "NoData",
+			row:        database.GetWorkspaceHealthInsightsRow{LatencyMs: -1, BaselineLatencyMs: -1},
+			wantScore:  100,
+			wantStatus: codersdk.WorkspaceHealthStatusHealthy,
+		},
+		{
+			name:       "HalfConnected",
+			row:        database.GetWorkspaceHealthInsightsRow{LatencyMs: -1, BaselineLatencyMs: -1},
+			agents:     []database.WorkspaceAgent{connected, disconnected},
+			wantScore:  80,
+			wantStatus: codersdk.WorkspaceHealthStatusHealthy,
+		},
+		{
+			name: "UnhealthyApps",
+			row:  database.GetWorkspaceHealthInsightsRow{LatencyMs: -1, BaselineLatencyMs: -1},
+			// Apps without health checks are not counted.
+			apps:       []database.WorkspaceApp{app(database.WorkspaceAppHealthHealthy), app(database.WorkspaceAppHealthUnhealthy), app(database.WorkspaceAppHealthInitializing), app(database.WorkspaceAppHealthDisabled)},
+			agents:     []database.WorkspaceAgent{connected},
+			wantScore:  87,
+			wantStatus: codersdk.WorkspaceHealthStatusHealthy,
+		},
+		{
+			name:       "LatencyImproved",
+			row:        database.GetWorkspaceHealthInsightsRow{LatencyMs: 10, BaselineLatencyMs: 50},
+			agents:     []database.WorkspaceAgent{connected},
+			wantScore:  100,
+			wantStatus: codersdk.WorkspaceHealthStatusHealthy,
+		},
+		{
+			name:       "LatencyTripled",
+			row:        database.GetWorkspaceHealthInsightsRow{LatencyMs: 150, BaselineLatencyMs: 50},
+			agents:     []database.WorkspaceAgent{connected},
+			wantScore:  80,
+			wantStatus: codersdk.WorkspaceHealthStatusHealthy,
+		},
+		{
+			name:       "RecentFailedBuilds",
+			row:        database.GetWorkspaceHealthInsightsRow{LatencyMs: -1, BaselineLatencyMs: -1, RecentBuilds: 4, RecentFailedBuilds: 3},
+			agents:     []database.WorkspaceAgent{disconnected},
+			wantScore:  45,
+			wantStatus: codersdk.WorkspaceHealthStatusUnhealthy,
+		},
+		{
+			name:       "LatestBuildFailed",
+			row:        database.GetWorkspaceHealthInsightsRow{LatencyMs: -1, BaselineLatencyMs: -1, LatestBuildFailed: true, RecentBuilds: 1, RecentFailedBuilds: 1},
+			wantScore:  40,
+			wantStatus: codersdk.WorkspaceHealthStatusUnhealthy,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := workspaceHealthInsight(tc.row, tc.agents, tc.apps, now, time.Minute)
+			assert.Equal(t, tc.wantScore, got.Score)
+			assert.Equal(t, tc.wantStatus, got.Status)
+		})
+	}
+}
+
 // stripTime strips the time from a time.Time value, but keeps the date and TZ.
 func stripTime(t time.Time) time.Time {
 	y, m, d := t.Date()
@@ -2,12 +2,17 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -25,9 +30,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbrollup"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspacestats"
@@ -36,6 +43,7 @@ import (
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/tailnet"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -142,6 +150,1015 @@ func TestDeploymentInsights(t *testing.T) {
 	}
 }
 
+func TestDeploymentWideInsights(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	y, m, d := time.Now().UTC().Date()
+	req := codersdk.DeploymentInsightsRequest{
+		StartTime: time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("AsOwner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.DeploymentInsights(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), resp.Report.Builds.Total)
+		require.Zero(t, resp.Report.Builds.Failed)
+		require.Zero(t, resp.Report.ActiveUsers)
+	})
+
+	t.Run("AsTemplateAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := templateAdmin.DeploymentInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestOrganizationInsights(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	y, m, d := time.Now().UTC().Date()
+	req := codersdk.OrganizationInsightsRequest{
+		StartTime: time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("AsOrgAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgAdmin(owner.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := orgAdmin.OrganizationInsights(ctx, owner.OrganizationID, req)
+		require.NoError(t, err)
+		require.Equal(t, owner.OrganizationID, resp.Report.OrganizationID)
+		require.Equal(t, int64(1), resp.Report.Workspaces.Total)
+		require.Equal(t, int64(1), resp.Report.Workspaces.Running)
+		require.Equal(t, int64(1), resp.Report.Builds.Total)
+		require.Zero(t, resp.Report.Builds.Failed)
+		require.Zero(t, resp.Report.ActiveUsers)
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.OrganizationInsights(ctx, owner.OrganizationID, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+// regionCoordinator reports a fixed preferred DERP region for agents.
+type regionCoordinator struct {
+	tailnet.Coordinator
+	nodes map[uuid.UUID]*tailnet.Node
+}
+
+func (c *regionCoordinator) Node(id uuid.UUID) *tailnet.Node {
+	return c.nodes[id]
+}
+
+func TestRegionLatencyInsights(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	regionID := int(dv.DERP.Server.RegionID.Value())
+	connectedAgentID := uuid.New()
+	coordinator := &regionCoordinator{
+		Coordinator: tailnet.NewCoordinator(testutil.Logger(t)),
+		nodes: map[uuid.UUID]*tailnet.Node{
+			connectedAgentID: {PreferredDERP: regionID},
+		},
+	}
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		DeploymentValues: dv,
+		Coordinator:      coordinator,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	// The connected agent prefers the embedded relay. The other agent is no
+	// longer connected, so its region is unknown.
+	for _, latency := range []float64{10, 20, 30} {
+		dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+			AgentID:                   connectedAgentID,
+			ConnectionMedianLatencyMS: latency,
+		})
+	}
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{ConnectionMedianLatencyMS: 100})
+	// Legacy agents don't report latency and are ignored.
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{})
+
+	req := codersdk.RegionLatencyInsightsRequest{
+		StartTime: time.Now().UTC().Truncate(time.Hour).Add(-time.Hour),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("AsOwner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.RegionLatencyInsights(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Regions, 2)
+
+		unknown := resp.Report.Regions[0]
+		assert.Equal(t, 0, unknown.RegionID)
+		assert.Equal(t, "Unknown", unknown.RegionName)
+		assert.Equal(t, int64(1), unknown.Agents)
+		assert.InDelta(t, 100, unknown.LatencyMS.P50, 0.001)
+
+		embedded := resp.Report.Regions[1]
+		assert.Equal(t, regionID, embedded.RegionID)
+		assert.Equal(t, dv.DERP.Server.RegionName.String(), embedded.RegionName)
+		assert.Equal(t, int64(1), embedded.Agents)
+		assert.InDelta(t, 20, embedded.LatencyMS.P50, 0.001)
+		assert.InDelta(t, 28, embedded.LatencyMS.P90, 0.001)
+		assert.LessOrEqual(t, embedded.LatencyMS.P99, float64(30))
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.RegionLatencyInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestClientRegionLatencyInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	// Clients in two regions connect to different agents before the agents
+	// report their latency. The latency of the third agent cannot be
+	// attributed to a client.
+	now := dbtime.Now()
+	germanAgentID, usAgentID := uuid.New(), uuid.New()
+	dbgen.WorkspaceClientNetworkStat(t, db, database.WorkspaceClientNetworkStat{
+		CreatedAt:    now.Add(-time.Minute),
+		AgentID:      germanAgentID,
+		ClientRegion: "DE",
+		ClientAsn:    3320,
+	})
+	dbgen.WorkspaceClientNetworkStat(t, db, database.WorkspaceClientNetworkStat{
+		CreatedAt:    now.Add(-time.Minute),
+		AgentID:      usAgentID,
+		ClientRegion: "US",
+	})
+	for _, latency := range []float64{10, 20, 30} {
+		dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+			CreatedAt:                 now,
+			AgentID:                   germanAgentID,
+			ConnectionMedianLatencyMS: latency,
+		})
+	}
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{CreatedAt: now, AgentID: usAgentID, ConnectionMedianLatencyMS: 100})
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{CreatedAt: now, ConnectionMedianLatencyMS: 200})
+
+	req := codersdk.ClientRegionLatencyInsightsRequest{
+		StartTime: time.Now().UTC().Truncate(time.Hour).Add(-time.Hour),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("AsOwner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.ClientRegionLatencyInsights(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Regions, 2)
+
+		germany := resp.Report.Regions[0]
+		assert.Equal(t, "DE", germany.Region)
+		assert.Equal(t, int64(3320), germany.ASN)
+		assert.Equal(t, int64(1), germany.Users)
+		assert.Equal(t, int64(1), germany.Agents)
+		assert.InDelta(t, 20, germany.LatencyMS.P50, 0.001)
+
+		us := resp.Report.Regions[1]
+		assert.Equal(t, "US", us.Region)
+		assert.Zero(t, us.ASN)
+		assert.InDelta(t, 100, us.LatencyMS.P50, 0.001)
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.ClientRegionLatencyInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestUserBandwidthInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	templateA, templateB := uuid.New(), uuid.New()
+	for _, stat := range []database.WorkspaceAgentStat{
+		{UserID: owner.UserID, TemplateID: templateA, RxBytes: 100, TxBytes: 10},
+		{UserID: owner.UserID, TemplateID: templateA, RxBytes: 100, TxBytes: 10},
+		{UserID: memberUser.ID, TemplateID: templateA, RxBytes: 1000, TxBytes: 100},
+		{UserID: memberUser.ID, TemplateID: templateB, RxBytes: 5000, TxBytes: 500},
+		// Outside of the requested time range.
+		{UserID: owner.UserID, TemplateID: templateB, RxBytes: 9999, CreatedAt: time.Now().Add(-48 * time.Hour)},
+	} {
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+
+	req := codersdk.UserBandwidthInsightsRequest{
+		StartTime: time.Now().UTC().Truncate(time.Hour).Add(-time.Hour),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("AsOwner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.UserBandwidthInsights(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), resp.Report.Count)
+		require.Len(t, resp.Report.Users, 2)
+
+		// The heaviest user comes first, and their heaviest template.
+		heaviest := resp.Report.Users[0]
+		assert.Equal(t, memberUser.ID, heaviest.UserID)
+		assert.Equal(t, int64(6000), heaviest.RxBytes)
+		assert.Equal(t, int64(600), heaviest.TxBytes)
+		require.Len(t, heaviest.Templates, 2)
+		assert.Equal(t, templateB, heaviest.Templates[0].TemplateID)
+		assert.Equal(t, templateA, heaviest.Templates[1].TemplateID)
+
+		assert.Equal(t, owner.UserID, resp.Report.Users[1].UserID)
+		assert.Equal(t, int64(200), resp.Report.Users[1].RxBytes)
+		assert.Equal(t, int64(20), resp.Report.Users[1].TxBytes)
+	})
+
+	t.Run("Paginated", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.Limit = 1
+		req.Offset = 1
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.UserBandwidthInsights(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), resp.Report.Count)
+		require.Len(t, resp.Report.Users, 1)
+		assert.Equal(t, owner.UserID, resp.Report.Users[0].UserID)
+	})
+
+	t.Run("TemplateFilter", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.TemplateIDs = []uuid.UUID{templateA}
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.UserBandwidthInsights(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Users, 2)
+		for _, user := range resp.Report.Users {
+			require.Len(t, user.Templates, 1)
+			assert.Equal(t, templateA, user.Templates[0].TemplateID)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		qp := url.Values{}
+		qp.Add("start_time", req.StartTime.Format(time.RFC3339))
+		qp.Add("end_time", req.EndTime.Format(time.RFC3339))
+		qp.Add("format", "csv")
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/insights/user-bandwidth?"+qp.Encode(), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"user_id", "username", "template_id", "rx_bytes", "tx_bytes"},
+			{memberUser.ID.String(), memberUser.Username, templateB.String(), "5000", "500"},
+			{memberUser.ID.String(), memberUser.Username, templateA.String(), "1000", "100"},
+			{owner.UserID.String(), coderdtest.FirstUserParams.Username, templateA.String(), "200", "20"},
+		}, records)
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.UserBandwidthInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestCostInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	now := dbtime.Now()
+	ownerWorkspace := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).Seed(database.WorkspaceBuild{
+		CreatedAt: now.Add(-2 * time.Hour),
+		// One credit per running minute.
+		DailyCost: 1440,
+	}).Do().Workspace
+	memberWorkspace := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).Seed(database.WorkspaceBuild{
+		CreatedAt: now.Add(-2 * time.Hour),
+		DailyCost: 2880,
+	}).Do().Workspace
+
+	// The owner workspace runs for 3 minutes, the member workspace for 1.
+	for _, stat := range []database.WorkspaceAgentStat{
+		{WorkspaceID: ownerWorkspace.ID, UserID: owner.UserID, CreatedAt: now.Add(-3 * time.Minute)},
+		{WorkspaceID: ownerWorkspace.ID, UserID: owner.UserID, CreatedAt: now.Add(-2 * time.Minute)},
+		{WorkspaceID: ownerWorkspace.ID, UserID: owner.UserID, CreatedAt: now.Add(-time.Minute)},
+		{WorkspaceID: memberWorkspace.ID, UserID: memberUser.ID, CreatedAt: now.Add(-time.Minute)},
+	} {
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+	//nolint:gocritic // Rolling up costs is a system function.
+	err := db.UpsertWorkspaceCosts(dbauthz.AsSystemRestricted(context.Background()))
+	require.NoError(t, err)
+
+	req := codersdk.CostInsightsRequest{
+		StartTime: time.Now().UTC().Truncate(time.Hour).Add(-time.Hour),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("ByWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.CostInsights(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.CostInsightsGroupByWorkspace, resp.Report.GroupBy)
+		assert.InDelta(t, 5, resp.Report.TotalCost, 0.0001)
+		require.Len(t, resp.Report.Entries, 2)
+
+		// The most expensive workspace comes first.
+		assert.Equal(t, ownerWorkspace.ID, resp.Report.Entries[0].ID)
+		assert.Equal(t, ownerWorkspace.Name, resp.Report.Entries[0].Name)
+		assert.Equal(t, int64(180), resp.Report.Entries[0].RunningSeconds)
+		assert.InDelta(t, 3, resp.Report.Entries[0].Cost, 0.0001)
+		assert.Equal(t, memberWorkspace.ID, resp.Report.Entries[1].ID)
+		assert.Equal(t, int64(60), resp.Report.Entries[1].RunningSeconds)
+		assert.InDelta(t, 2, resp.Report.Entries[1].Cost, 0.0001)
+	})
+
+	t.Run("ByUser", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.GroupBy = codersdk.CostInsightsGroupByUser
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.CostInsights(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Entries, 2)
+		assert.Equal(t, owner.UserID, resp.Report.Entries[0].ID)
+		assert.Equal(t, coderdtest.FirstUserParams.Username, resp.Report.Entries[0].Name)
+		assert.Equal(t, memberUser.ID, resp.Report.Entries[1].ID)
+		assert.Equal(t, memberUser.Username, resp.Report.Entries[1].Name)
+	})
+
+	t.Run("ByOrganization", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.GroupBy = codersdk.CostInsightsGroupByOrganization
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.CostInsights(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Entries, 1)
+		assert.Equal(t, owner.OrganizationID, resp.Report.Entries[0].ID)
+		assert.Equal(t, int64(240), resp.Report.Entries[0].RunningSeconds)
+		assert.InDelta(t, 5, resp.Report.Entries[0].Cost, 0.0001)
+	})
+
+	t.Run("InvalidGroupBy", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.GroupBy = "template"
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.CostInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		qp := url.Values{}
+		qp.Add("start_time", req.StartTime.Format(time.RFC3339))
+		qp.Add("end_time", req.EndTime.Format(time.RFC3339))
+		qp.Add("group_by", "user")
+		qp.Add("format", "csv")
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/insights/costs?"+qp.Encode(), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"id", "name", "running_seconds", "cost"},
+			{owner.UserID.String(), coderdtest.FirstUserParams.Username, "180", "3"},
+			{memberUser.ID.String(), memberUser.Username, "60", "2"},
+		}, records)
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.CostInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestCostInsightsOwnershipTransfer(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// The workspace is owned by the member now, but was owned by the first
+	// user during the previous hour.
+	hour := dbtime.Now().UTC().Truncate(time.Hour)
+	workspace := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).Seed(database.WorkspaceBuild{
+		CreatedAt: hour.Add(-2 * time.Hour),
+		DailyCost: 1440,
+	}).Do().Workspace
+
+	for _, stat := range []database.WorkspaceAgentStat{
+		{WorkspaceID: workspace.ID, UserID: owner.UserID, CreatedAt: hour.Add(-30 * time.Minute)},
+		{WorkspaceID: workspace.ID, UserID: owner.UserID, CreatedAt: hour.Add(-29 * time.Minute)},
+		{WorkspaceID: workspace.ID, UserID: memberUser.ID, CreatedAt: hour},
+	} {
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+	// Rolling up twice recomputes the previous hour, which must not move it
+	// to the current owner.
+	for range 2 {
+		//nolint:gocritic // Rolling up costs is a system function.
+		err := db.UpsertWorkspaceCosts(dbauthz.AsSystemRestricted(context.Background()))
+		require.NoError(t, err)
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	resp, err := client.CostInsights(ctx, codersdk.CostInsightsRequest{
+		StartTime: hour.Add(-time.Hour),
+		EndTime:   hour.Add(time.Hour),
+		GroupBy:   codersdk.CostInsightsGroupByUser,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Report.Entries, 2)
+	assert.Equal(t, owner.UserID, resp.Report.Entries[0].ID)
+	assert.Equal(t, int64(120), resp.Report.Entries[0].RunningSeconds)
+	assert.Equal(t, memberUser.ID, resp.Report.Entries[1].ID)
+	assert.Equal(t, int64(60), resp.Report.Entries[1].RunningSeconds)
+}
+
+func TestWorkspaceHealthInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	now := dbtime.Now()
+	newWorkspace := func(name string) dbfake.WorkspaceBuildBuilder {
+		return dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			Name:           name,
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		})
+	}
+
+	// A connected workspace with a steady latency.
+	healthy := newWorkspace("healthy").WithAgent().Do()
+	err := db.UpdateWorkspaceAgentConnectionByID(context.Background(), database.UpdateWorkspaceAgentConnectionByIDParams{
+		ID:               healthy.Agents[0].ID,
+		FirstConnectedAt: sql.NullTime{Time: now, Valid: true},
+		LastConnectedAt:  sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:        now,
+	})
+	require.NoError(t, err)
+	// A workspace whose agent never connected, with twice its usual latency.
+	disconnected := newWorkspace("disconnected").WithAgent().Do()
+	// A workspace whose latest build failed.
+	failed := newWorkspace("failed").Failed().Do()
+	// Stopped workspaces are not scored.
+	_ = newWorkspace("stopped").Seed(database.WorkspaceBuild{Transition: database.WorkspaceTransitionStop}).Do()
+
+	for _, stat := range []database.WorkspaceAgentStat{
+		{WorkspaceID: healthy.Workspace.ID, AgentID: healthy.Agents[0].ID, CreatedAt: now.Add(-48 * time.Hour), ConnectionMedianLatencyMS: 20},
+		{WorkspaceID: healthy.Workspace.ID, AgentID: healthy.Agents[0].ID, CreatedAt: now.Add(-10 * time.Minute), ConnectionMedianLatencyMS: 20},
+		{WorkspaceID: disconnected.Workspace.ID, AgentID: disconnected.Agents[0].ID, CreatedAt: now.Add(-48 * time.Hour), ConnectionMedianLatencyMS: 20},
+		{WorkspaceID: disconnected.Workspace.ID, AgentID: disconnected.Agents[0].ID, CreatedAt: now.Add(-10 * time.Minute), ConnectionMedianLatencyMS: 40},
+	} {
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+
+	t.Run("All", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.WorkspaceHealthInsights(ctx, codersdk.WorkspaceHealthInsightsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Workspaces, 3)
+
+		// The least healthy workspace comes first.
+		got := resp.Report.Workspaces[0]
+		assert.Equal(t, failed.Workspace.ID, got.WorkspaceID)
+		assert.Equal(t, 40, got.Score)
+		assert.Equal(t, codersdk.WorkspaceHealthStatusUnhealthy, got.Status)
+		assert.Equal(t, 0, got.Connectivity.Score)
+		assert.True(t, got.Builds.LatestBuildFailed)
+		assert.Equal(t, int64(1), got.Builds.FailedBuilds)
+
+		got = resp.Report.Workspaces[1]
+		assert.Equal(t, disconnected.Workspace.ID, got.WorkspaceID)
+		assert.Equal(t, 50, got.Score)
+		assert.Equal(t, codersdk.WorkspaceHealthStatusDegraded, got.Status)
+		assert.Equal(t, codersdk.WorkspaceHealthConnectivity{Score: 0, AgentsConnected: 0, AgentsTotal: 1}, got.Connectivity)
+		assert.Equal(t, codersdk.WorkspaceHealthLatency{Score: 50, LatencyMS: 40, BaselineLatencyMS: 20}, got.Latency)
+
+		got = resp.Report.Workspaces[2]
+		assert.Equal(t, healthy.Workspace.ID, got.WorkspaceID)
+		assert.Equal(t, "healthy", got.WorkspaceName)
+		assert.Equal(t, coderdtest.FirstUserParams.Username, got.OwnerUsername)
+		assert.Equal(t, 100, got.Score)
+		assert.Equal(t, codersdk.WorkspaceHealthStatusHealthy, got.Status)
+		assert.Equal(t, codersdk.WorkspaceHealthConnectivity{Score: 100, AgentsConnected: 1, AgentsTotal: 1}, got.Connectivity)
+	})
+
+	t.Run("TemplateIDs", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.WorkspaceHealthInsights(ctx, codersdk.WorkspaceHealthInsightsRequest{
+			TemplateIDs: []uuid.UUID{healthy.Workspace.TemplateID},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Report.Workspaces, 1)
+		assert.Equal(t, healthy.Workspace.ID, resp.Report.Workspaces[0].WorkspaceID)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/insights/workspace-health?format=csv", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		require.Equal(t, []string{"workspace_id", "workspace_name", "owner_username", "template_name", "score", "status"}, records[0])
+		require.Equal(t, []string{failed.Workspace.ID.String(), "failed", coderdtest.FirstUserParams.Username, failed.Template.Name, "40", "unhealthy"}, records[1])
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.WorkspaceHealthInsights(ctx, codersdk.WorkspaceHealthInsightsRequest{})
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestPrebuildInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	now := dbtime.Now()
+	tv := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+		OrganizationID: owner.OrganizationID,
+		CreatedBy:      owner.UserID,
+	}).Do()
+	preset := dbgen.Preset(t, db, database.InsertPresetParams{
+		TemplateVersionID: tv.TemplateVersion.ID,
+		Name:              "pool",
+		DesiredInstances:  sql.NullInt32{Int32: 2, Valid: true},
+	})
+	presetID := uuid.NullUUID{UUID: preset.ID, Valid: true}
+	newWorkspace := func() database.WorkspaceTable {
+		return database.WorkspaceTable{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+			TemplateID:     tv.Template.ID,
+		}
+	}
+
+	// A prebuild that was ready an hour before it was claimed, and took 30
+	// seconds to start for its new owner.
+	claimed := dbfake.WorkspaceBuild(t, db, newWorkspace()).Seed(database.WorkspaceBuild{
+		InitiatorID:             database.PrebuildsSystemUserID,
+		TemplateVersionPresetID: presetID,
+		CreatedAt:               now.Add(-2 * time.Hour),
+	}).Succeeded(dbfake.WithJobCompletedAt(now.Add(-2 * time.Hour))).Do()
+	_ = dbfake.WorkspaceBuild(t, db, claimed.Workspace).Seed(database.WorkspaceBuild{
+		BuildNumber:             2,
+		InitiatorID:             owner.UserID,
+		TemplateVersionPresetID: presetID,
+		CreatedAt:               now.Add(-time.Hour),
+	}).Succeeded(dbfake.WithJobCompletedAt(now.Add(-time.Hour + 30*time.Second))).Do()
+	// A prebuild waiting in the pool.
+	_ = dbfake.WorkspaceBuild(t, db, newWorkspace()).Seed(database.WorkspaceBuild{
+		InitiatorID:             database.PrebuildsSystemUserID,
+		TemplateVersionPresetID: presetID,
+	}).Do()
+	// A workspace built from scratch although the preset has a pool.
+	_ = dbfake.WorkspaceBuild(t, db, newWorkspace()).Seed(database.WorkspaceBuild{
+		TemplateVersionPresetID: presetID,
+	}).Do()
+
+	req := codersdk.PrebuildInsightsRequest{
+		StartTime: time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.PrebuildInsights(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), resp.Report.Claims)
+		assert.Equal(t, int64(1), resp.Report.Misses)
+		assert.InDelta(t, 0.5, resp.Report.HitRate, 0.0001)
+		require.Len(t, resp.Report.Presets, 1)
+
+		got := resp.Report.Presets[0]
+		assert.Equal(t, tv.Template.ID, got.TemplateID)
+		assert.Equal(t, tv.Template.Name, got.TemplateName)
+		assert.Equal(t, "pool", got.PresetName)
+		assert.Equal(t, int64(2), got.PrebuildsCreated)
+		assert.InDelta(t, 0.5, got.HitRate, 0.0001)
+		assert.InDelta(t, 30, got.ClaimLatencySeconds50, 0.0001)
+		assert.InDelta(t, 3600, got.IdleSeconds50, 0.0001)
+	})
+
+	t.Run("OtherTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.TemplateIDs = []uuid.UUID{uuid.New()}
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.PrebuildInsights(ctx, req)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Report.Presets)
+		assert.Zero(t, resp.Report.HitRate)
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.PrebuildInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
+
+func TestUsageHeatmapInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// Both users are active in the same hour using different templates.
+	createdAt := dbtime.Now().Add(-time.Second)
+	templateA, templateB := uuid.New(), uuid.New()
+	for _, stat := range []database.WorkspaceAgentStat{
+		{UserID: owner.UserID, TemplateID: templateA, CreatedAt: createdAt, ConnectionCount: 1, SessionCountSSH: 1},
+		{UserID: memberUser.ID, TemplateID: templateB, CreatedAt: createdAt, ConnectionCount: 1, SessionCountSSH: 1},
+	} {
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+	ctx := testutil.Context(t, testutil.WaitShort)
+	err := db.UpsertTemplateUsageStats(dbauthz.AsSystemRestricted(ctx))
+	require.NoError(t, err)
+
+	// Cover the same hour on the previous day, which has no activity.
+	y, m, d := createdAt.UTC().Date()
+	req := codersdk.UsageHeatmapInsightsRequest{
+		StartTime: time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+	day, hour := createdAt.UTC().Weekday(), createdAt.UTC().Hour()
+
+	t.Run("Deployment", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.UsageHeatmapInsights(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Report.AvgActiveUsers, 7)
+		require.Len(t, resp.Report.AvgActiveUsers[day], 24)
+		assert.InDelta(t, 2, resp.Report.AvgActiveUsers[day][hour], 0.001)
+		assert.Equal(t, int64(2), resp.Report.MaxActiveUsers[day][hour])
+		assert.Zero(t, resp.Report.MaxActiveUsers[(day+6)%7][hour])
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.TemplateIDs = []uuid.UUID{templateA}
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.UsageHeatmapInsights(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{templateA}, resp.Report.TemplateIDs)
+		assert.Equal(t, int64(1), resp.Report.MaxActiveUsers[day][hour])
+	})
+
+	t.Run("Timezone", func(t *testing.T) {
+		t.Parallel()
+
+		loc, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		req := req
+		req.Timezone = loc.String()
+		day, hour := createdAt.In(loc).Weekday(), createdAt.In(loc).Hour()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.UsageHeatmapInsights(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Report.MaxActiveUsers[day][hour])
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.UsageHeatmapInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+	})
+}
+
+func TestTemplateComparisonInsights(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	healthyVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, healthyVersion.ID)
+	healthy := coderdtest.CreateTemplate(t, client, owner.OrganizationID, healthyVersion.ID)
+	// Workspaces of the broken template fail to start.
+	brokenVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+			proto.WorkspaceTransition_START: echo.ApplyFailed,
+		},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, brokenVersion.ID)
+	broken := coderdtest.CreateTemplate(t, client, owner.OrganizationID, brokenVersion.ID)
+	for _, template := range []codersdk.Template{healthy, broken} {
+		workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	}
+
+	y, m, d := time.Now().UTC().Date()
+	req := codersdk.TemplateComparisonInsightsRequest{
+		StartTime:   time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		EndTime:     time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+		TemplateIDs: []uuid.UUID{broken.ID, healthy.ID},
+	}
+
+	t.Run("AsOwner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.TemplateComparisonInsights(ctx, req)
+		require.NoError(t, err)
+		// Templates are returned in the requested order.
+		require.Len(t, resp.Report.Templates, 2)
+
+		got := resp.Report.Templates[0]
+		assert.Equal(t, broken.ID, got.TemplateID)
+		assert.Equal(t, broken.Name, got.TemplateName)
+		assert.Equal(t, int64(1), got.TotalBuilds)
+		assert.Equal(t, int64(1), got.FailedBuilds)
+		assert.Zero(t, got.BuildSuccessRate)
+		assert.Equal(t, float64(-1), got.MedianBuildSeconds)
+		assert.Equal(t, float64(-1), got.WorkspaceConnectionLatencyMS.P50)
+
+		got = resp.Report.Templates[1]
+		assert.Equal(t, healthy.ID, got.TemplateID)
+		assert.Equal(t, int64(1), got.TotalBuilds)
+		assert.Zero(t, got.FailedBuilds)
+		assert.Equal(t, float64(1), got.BuildSuccessRate)
+		assert.GreaterOrEqual(t, got.MedianBuildSeconds, float64(0))
+		assert.Zero(t, got.ActiveUsers)
+	})
+
+	t.Run("TemplateIDsRequired", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.TemplateIDs = nil
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.TemplateComparisonInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.TemplateComparisonInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+	})
+}
+
+func TestAccessMethodInsights(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// The owner uses VS Code and the member uses SSH on the same template.
+	template := dbgen.Template(t, db, database.Template{OrganizationID: owner.OrganizationID, CreatedBy: owner.UserID})
+	createdAt := dbtime.Now().Add(-time.Second)
+	for _, stat := range []database.WorkspaceAgentStat{
+		{UserID: owner.UserID, TemplateID: template.ID, CreatedAt: createdAt, ConnectionCount: 1, SessionCountVSCode: 1},
+		{UserID: memberUser.ID, TemplateID: template.ID, CreatedAt: createdAt, ConnectionCount: 1, SessionCountSSH: 1},
+	} {
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+	ctx := testutil.Context(t, testutil.WaitShort)
+	err := db.UpsertTemplateUsageStats(dbauthz.AsSystemRestricted(ctx))
+	require.NoError(t, err)
+
+	// Cover the previous day, which has no usage, and today.
+	y, m, d := createdAt.UTC().Date()
+	req := codersdk.AccessMethodInsightsRequest{
+		StartTime:   time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1),
+		EndTime:     time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+		TemplateIDs: []uuid.UUID{template.ID},
+	}
+
+	t.Run("AsOwner", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		resp, err := client.AccessMethodInsights(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.InsightsReportIntervalDay, resp.Report.Interval)
+		require.Len(t, resp.Report.Templates, 1)
+
+		got := resp.Report.Templates[0]
+		assert.Equal(t, template.ID, got.TemplateID)
+		assert.Equal(t, template.Name, got.TemplateName)
+		require.Len(t, got.AccessMethods, 5)
+		usage := make(map[string]codersdk.AccessMethodUsage)
+		for _, method := range got.AccessMethods {
+			usage[method.Slug] = method
+		}
+		assert.Positive(t, usage["vscode"].Seconds)
+		assert.Equal(t, usage["vscode"].Seconds, usage["ssh"].Seconds)
+		assert.InDelta(t, 0.5, usage["vscode"].Share, 0.001)
+		assert.InDelta(t, 0.5, usage["ssh"].Share, 0.001)
+		assert.Zero(t, usage["jetbrains"].Seconds)
+		assert.Zero(t, usage["jetbrains"].Share)
+
+		// The previous day is included without usage.
+		require.Len(t, got.Intervals, 2)
+		for _, method := range got.Intervals[0].AccessMethods {
+			assert.Zero(t, method.Seconds)
+			assert.Zero(t, method.Share)
+		}
+		assert.Equal(t, got.AccessMethods, got.Intervals[1].AccessMethods)
+	})
+
+	t.Run("InvalidInterval", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.Interval = "hour"
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.AccessMethodInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("InvalidTimezone", func(t *testing.T) {
+		t.Parallel()
+
+		req := req
+		req.Timezone = "Mars/Olympus_Mons"
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.AccessMethodInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("AsMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.AccessMethodInsights(ctx, req)
+		require.Error(t, err)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+	})
+}
+
 func TestUserActivityInsights_SanityCheck(t *testing.T) {
 	t.Parallel()
 
@@ -381,6 +1398,66 @@ func TestUserActivityInsights_BadRequest(t *testing.T) {
 	assert.Error(t, err, "want error for end time before start time")
 }
 
+func TestInsights_CSV(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	y, m, d := time.Now().UTC().Date()
+	qp := url.Values{}
+	qp.Add("start_time", time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -7).Format(time.RFC3339))
+	qp.Add("end_time", time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339))
+
+	t.Run("Format", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		q := maps.Clone(qp)
+		q.Add("format", "csv")
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/insights/user-activity?"+q.Encode(), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"user_id", "username", "template_ids", "seconds"}}, records)
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		q := maps.Clone(qp)
+		q.Add("interval", "day")
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/insights/templates?"+q.Encode(), nil, func(r *http.Request) {
+			r.Header.Set("Accept", "text/csv")
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 8, "header and one row per day")
+		require.Equal(t, []string{"start_time", "end_time", "interval", "template_ids", "active_users"}, records[0])
+	})
+
+	t.Run("TemplatesWithoutInterval", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		q := maps.Clone(qp)
+		q.Add("format", "csv")
+		res, err := client.Request(ctx, http.MethodGet, "/api/v2/insights/templates?"+q.Encode(), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
 func TestTemplateInsights_Golden(t *testing.T) {
 	t.Parallel()
 
@@ -2158,6 +3235,106 @@ func TestTemplateInsights_BadRequest(t *testing.T) {
 		Sections:  []codersdk.TemplateInsightsSection{"invalid"},
 	})
 	assert.Error(t, err, "want error for bad section")
+
+	_, err = client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime: today.AddDate(0, 0, -1),
+		EndTime:   today,
+		Fields:    []codersdk.TemplateInsightsField{"invalid"},
+	})
+	assert.Error(t, err, "want error for bad field")
+}
+
+func TestTemplateInsights_Fields(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// All fields are included by default, and the builtin apps are always
+	// part of the apps usage.
+	resp, err := client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime: today.AddDate(0, 0, -1),
+		EndTime:   today,
+		Sections:  []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionReport},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Report)
+	require.NotNil(t, resp.Report.ActiveUsers)
+	require.NotEmpty(t, resp.Report.AppsUsage)
+	require.NotNil(t, resp.Report.ParametersUsage)
+
+	// The fields that are not included are omitted.
+	resp, err = client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime: today.AddDate(0, 0, -1),
+		EndTime:   today,
+		Sections:  []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionReport},
+		Fields:    []codersdk.TemplateInsightsField{codersdk.TemplateInsightsFieldActiveUsers},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Report)
+	require.NotNil(t, resp.Report.ActiveUsers)
+	require.Nil(t, resp.Report.AppsUsage)
+	require.Nil(t, resp.Report.ParametersUsage)
+	require.NotNil(t, resp.Report.TemplateIDs)
+
+	resp, err = client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime: today.AddDate(0, 0, -1),
+		EndTime:   today,
+		Sections:  []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionReport},
+		Fields:    []codersdk.TemplateInsightsField{codersdk.TemplateInsightsFieldParametersUsage},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Report)
+	require.Nil(t, resp.Report.ActiveUsers)
+	require.Nil(t, resp.Report.AppsUsage)
+	// Included fields are present even when they are empty.
+	require.NotNil(t, resp.Report.ParametersUsage)
+	require.Empty(t, resp.Report.ParametersUsage)
+}
+
+func TestTemplateInsights_MonthlyIntervals(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	// The range spans the start of daylight saving time in New York on
+	// March 10, 2024.
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	boundaries := []time.Time{
+		time.Date(2024, time.February, 15, 0, 0, 0, 0, loc),
+		time.Date(2024, time.March, 1, 0, 0, 0, 0, loc),
+		time.Date(2024, time.April, 1, 0, 0, 0, 0, loc),
+		time.Date(2024, time.May, 1, 0, 0, 0, 0, loc),
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	resp, err := client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime: boundaries[0],
+		EndTime:   boundaries[len(boundaries)-1],
+		Interval:  codersdk.InsightsReportIntervalMonth,
+		Timezone:  loc.String(),
+		Sections:  []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionIntervalReports},
+	})
+	require.NoError(t, err)
+
+	// The first interval starts at the start time, the others at midnight on
+	// the first of the month in New York, before and after the DST change.
+	require.Len(t, resp.IntervalReports, len(boundaries)-1)
+	slices.SortFunc(resp.IntervalReports, func(a, b codersdk.TemplateInsightsIntervalReport) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	for i, report := range resp.IntervalReports {
+		assert.Equal(t, codersdk.InsightsReportIntervalMonth, report.Interval)
+		assert.True(t, boundaries[i].Equal(report.StartTime), "interval %d starts at %s, want %s", i, report.StartTime, boundaries[i])
+		assert.True(t, boundaries[i+1].Equal(report.EndTime), "interval %d ends at %s, want %s", i, report.EndTime, boundaries[i+1])
+	}
 }
 
 func TestTemplateInsights_RBAC(t *testing.T) {
@@ -2439,6 +3616,20 @@ func TestGenericInsights_Disabled(t *testing.T) {
 				return err
 			},
 		},
+		{
+			name: "Deployment",
+			fn: func(ctx context.Context) error {
+				_, err := client.DeploymentInsights(ctx, codersdk.DeploymentInsightsRequest{})
+				return err
+			},
+		},
+		{
+			name: "Organization",
+			fn: func(ctx context.Context) error {
+				_, err := client.OrganizationInsights(ctx, user.OrganizationID, codersdk.OrganizationInsightsRequest{})
+				return err
+			},
+		},
 		{
 			name: "UserStatusCounts",
 			fn: func(ctx context.Context) error {
@@ -0,0 +1,157 @@
+package insightscache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbrollup"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/quartz"
+)
+
+// maxEntries bounds the number of cached results. Expired results are dropped
+// when it is reached, and all results if none expired.
+const maxEntries = 1000
+
+// Cache caches the results of insights queries. The aggregate queries behind
+// insights take seconds on large deployments, while dashboards refresh them
+// every 30 seconds even though the rolled up usage stats they aggregate only
+// change every few minutes.
+//
+// Results are cached per actor, as the queries are authorized, and dropped
+// after the TTL or as soon as usage stats are rolled up on any replica.
+type Cache struct {
+	log   slog.Logger
+	clock quartz.Clock
+	ttl   time.Duration
+
+	mu sync.Mutex
+	// generation is incremented on invalidation so that results fetched
+	// before are not cached.
+	generation uint64
+	entries    map[string]entry
+
+	cancel func()
+}
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// New returns a cache of insights results for the TTL, which is disabled when
+// the TTL is 0. It is the caller's responsibility to call Close.
+func New(log slog.Logger, ps pubsub.Pubsub, clock quartz.Clock, ttl time.Duration) (*Cache, error) {
+	c := &Cache{
+		log:     log,
+		clock:   clock,
+		ttl:     ttl,
+		entries: make(map[string]entry),
+		cancel:  func() {},
+	}
+	if ttl <= 0 {
+		return c, nil
+	}
+	cancel, err := ps.Subscribe(dbrollup.EventChannel, func(ctx context.Context, _ []byte) {
+		c.log.Debug(ctx, "invalidating insights cache after rollup")
+		c.Invalidate()
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to rollup events: %w", err)
+	}
+	c.cancel = cancel
+	return c, nil
+}
+
+func (c *Cache) Close() {
+	c.cancel()
+}
+
+// Invalidate drops all cached results.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	clear(c.entries)
+}
+
+// Get returns the cached result of the query with the given argument for the
+// actor of the context, or fetches and caches it. Errors are not cached.
+func Get[A, T any](ctx context.Context, c *Cache, query string, fetch func(context.Context, A) (T, error), arg A) (T, error) {
+	if c.ttl <= 0 {
+		return fetch(ctx, arg)
+	}
+	key, err := cacheKey(ctx, query, arg)
+	if err != nil {
+		c.log.Warn(ctx, "not caching insights query", slog.F("query", query), slog.Error(err))
+		return fetch(ctx, arg)
+	}
+
+	c.mu.Lock()
+	now := c.clock.Now()
+	generation := c.generation
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if v, isT := e.value.(T); ok && isT && now.Before(e.expiresAt) {
+		return v, nil
+	}
+
+	v, err := fetch(ctx, arg)
+	if err != nil {
+		return v, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		// Usage stats were rolled up while fetching, the result may be stale.
+		return v, nil
+	}
+	if len(c.entries) >= maxEntries {
+		c.pruneLocked(now)
+	}
+	c.entries[key] = entry{value: v, expiresAt: now.Add(c.ttl)}
+	return v, nil
+}
+
+func (c *Cache) pruneLocked(now time.Time) {
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= maxEntries {
+		clear(c.entries)
+	}
+}
+
+// cacheKey identifies the query with the given arguments run by the actor of
+// the context.
+func cacheKey[A any](ctx context.Context, query string, arg A) (string, error) {
+	actor, ok := dbauthz.ActorFromContext(ctx)
+	if !ok {
+		return "", xerrors.New("no actor in context")
+	}
+	// Everything that authorization depends on is part of the key.
+	subject, err := json.Marshal(struct {
+		ID     string
+		Roles  []rbac.RoleIdentifier
+		Groups []string
+		Scope  rbac.ExpandableScope
+	}{actor.ID, actor.SafeRoleNames(), actor.Groups, actor.Scope})
+	if err != nil {
+		return "", xerrors.Errorf("marshal actor: %w", err)
+	}
+	args, err := json.Marshal(arg)
+	if err != nil {
+		return "", xerrors.Errorf("marshal arguments: %w", err)
+	}
+	return query + ":" + string(subject) + ":" + string(args), nil
+}
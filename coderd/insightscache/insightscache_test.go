@@ -0,0 +1,102 @@
+package insightscache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbrollup"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/insightscache"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	const ttl = time.Minute
+	ctx := testutil.Context(t, testutil.WaitShort)
+	ps := pubsub.NewInMemory()
+	clk := quartz.NewMock(t)
+	c, err := insightscache.New(slogtest.Make(t, nil), ps, clk, ttl)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	actor := func() context.Context {
+		return dbauthz.As(ctx, rbac.Subject{
+			ID:    uuid.NewString(),
+			Roles: rbac.RoleIdentifiers{rbac.RoleMember()},
+			Scope: rbac.ScopeAll,
+		})
+	}
+	var calls int
+	fetch := func(_ context.Context, arg string) (string, error) {
+		calls++
+		if arg == "fail" {
+			return "", xerrors.New("fail")
+		}
+		return arg, nil
+	}
+	get := func(ctx context.Context, arg string) {
+		t.Helper()
+		v, err := insightscache.Get(ctx, c, "query", fetch, arg)
+		if arg == "fail" {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, arg, v)
+	}
+	ctx1, ctx2 := actor(), actor()
+
+	// Results are cached per actor and argument.
+	get(ctx1, "a")
+	get(ctx1, "a")
+	require.Equal(t, 1, calls)
+	get(ctx1, "b")
+	get(ctx2, "a")
+	require.Equal(t, 3, calls)
+
+	// Errors are not cached.
+	get(ctx1, "fail")
+	get(ctx1, "fail")
+	require.Equal(t, 5, calls)
+
+	// Results expire after the TTL.
+	clk.Advance(ttl)
+	get(ctx1, "a")
+	require.Equal(t, 6, calls)
+
+	// Results are invalidated when usage stats are rolled up.
+	require.NoError(t, ps.Publish(dbrollup.EventChannel, []byte(`{"template_usage_stats":true}`)))
+	get(ctx1, "a")
+	require.Equal(t, 7, calls)
+}
+
+func TestCacheDisabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort))
+	c, err := insightscache.New(slogtest.Make(t, nil), pubsub.NewInMemory(), quartz.NewMock(t), 0)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	var calls int
+	fetch := func(context.Context, struct{}) (int, error) {
+		calls++
+		return calls, nil
+	}
+	for range 2 {
+		_, err := insightscache.Get(ctx, c, "query", fetch, struct{}{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, calls)
+}
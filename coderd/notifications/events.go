@@ -18,6 +18,8 @@ var (
 	TemplateWorkspaceManualBuildFailed = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
 	TemplateWorkspaceOutOfMemory       = uuid.MustParse("a9d027b4-ac49-4fb1-9f6d-45af15f64e7a")
 	TemplateWorkspaceOutOfDisk         = uuid.MustParse("f047f6a3-5713-40f7-85aa-0394cce9fa3a")
+	TemplateWorkspaceUnused            = uuid.MustParse("501fc4a3-2c42-4a27-896d-4c14c1cac708")
+	TemplateWorkspaceAnomalousActivity = uuid.MustParse("c89dc1e6-4d01-4bba-8eed-c18d31e5249b")
 )
 
 // Account-related events.
@@ -1182,6 +1182,37 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceUnused",
+			id:   notifications.TemplateWorkspaceUnused,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"name":           "bobby-workspace",
+					"owner":          "bobby",
+					"lastUsed":       "2 weeks ago",
+					"timeTilDormant": "1 week from now",
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceAnomalousActivity",
+			id:   notifications.TemplateWorkspaceAnomalousActivity,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"name":     "bobby-workspace",
+					"owner":    "bobby",
+					"metric":   "egress",
+					"observed": "12 GB",
+					"usual":    "120 MB",
+				},
+			},
+		},
 		{
 			name: "TemplateTestNotification",
 			id:   notifications.TemplateTestNotification,
@@ -25,10 +25,31 @@ const (
 	delay = 15 * time.Minute
 )
 
-func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) io.Closer {
+type Option func(*reportGenerator)
+
+// WithUnusedWorkspaceNotifications enables notifying the owners of workspaces
+// that have not been used for the given duration. Template admins of the
+// organization are notified as well when notifyAdmins is set. A zero duration
+// disables the notifications.
+func WithUnusedWorkspaceNotifications(after time.Duration, notifyAdmins bool) Option {
+	return func(g *reportGenerator) {
+		g.unusedWorkspaceAfter = after
+		g.unusedWorkspaceNotifyAdmins = notifyAdmins
+	}
+}
+
+func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock, opts ...Option) io.Closer {
 	closed := make(chan struct{})
 
 	ctx, cancelFunc := context.WithCancel(ctx)
+	g := &reportGenerator{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
 	//nolint:gocritic // The system generates periodic reports without direct user input.
 	ctx = dbauthz.AsSystemRestricted(ctx)
 
@@ -54,6 +75,13 @@ func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Sto
 				return xerrors.Errorf("unable to generate reports with failed workspace builds: %w", err)
 			}
 
+			if g.unusedWorkspaceAfter > 0 {
+				err = reportUnusedWorkspaces(ctx, logger, tx, enqueuer, clk, g.unusedWorkspaceAfter, g.unusedWorkspaceNotifyAdmins)
+				if err != nil {
+					return xerrors.Errorf("unable to notify about unused workspaces: %w", err)
+				}
+			}
+
 			logger.Info(ctx, "report generator finished", slog.F("duration", clk.Since(start)))
 
 			return nil
@@ -80,15 +108,15 @@ func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Sto
 			}
 		}
 	}()
-	return &reportGenerator{
-		cancel: cancelFunc,
-		closed: closed,
-	}
+	return g
 }
 
 type reportGenerator struct {
 	cancel context.CancelFunc
 	closed chan struct{}
+
+	unusedWorkspaceAfter        time.Duration
+	unusedWorkspaceNotifyAdmins bool
 }
 
 func (i *reportGenerator) Close() error {
@@ -157,7 +185,7 @@ func reportFailedWorkspaceBuilds(ctx context.Context, logger slog.Logger, db dat
 		}
 
 		// Fetch template admins with org access to the templates
-		templateAdmins, err := findTemplateAdmins(ctx, db, stats)
+		templateAdmins, err := findTemplateAdmins(ctx, db, stats.TemplateOrganizationID)
 		if err != nil {
 			logger.Error(ctx, "unable to find template admins for template", slog.F("template_id", stats.TemplateID), slog.Error(err))
 			continue
@@ -295,7 +323,7 @@ func buildDataForReportFailedWorkspaceBuilds(reports []adminReport) map[string]a
 	}
 }
 
-func findTemplateAdmins(ctx context.Context, db database.Store, stats database.GetWorkspaceBuildStatsByTemplatesRow) ([]database.GetUsersRow, error) {
+func findTemplateAdmins(ctx context.Context, db database.Store, organizationID uuid.UUID) ([]database.GetUsersRow, error) {
 	users, err := db.GetUsers(ctx, database.GetUsersParams{
 		RbacRole: []string{codersdk.RoleTemplateAdmin},
 	})
@@ -321,7 +349,7 @@ func findTemplateAdmins(ctx context.Context, db database.Store, stats database.G
 	}
 
 	for _, entry := range orgIDsByMemberIDs {
-		if slices.Contains(entry.OrganizationIDs, stats.TemplateOrganizationID) {
+		if slices.Contains(entry.OrganizationIDs, organizationID) {
 			templateAdmins = append(templateAdmins, usersByIDs[entry.UserID])
 		}
 	}
@@ -0,0 +1,84 @@
+package reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/quartz"
+)
+
+// reportUnusedWorkspaces notifies the owners of workspaces that have not been
+// used for longer than unusedAfter. Owners are notified once per period of
+// inactivity: using the workspace again makes it eligible for a new
+// notification.
+func reportUnusedWorkspaces(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock, unusedAfter time.Duration, notifyAdmins bool) error {
+	now := clk.Now()
+
+	workspaces, err := db.GetUnusedWorkspaces(ctx, dbtime.Time(now.Add(-unusedAfter)).UTC())
+	if err != nil {
+		return xerrors.Errorf("unable to fetch unused workspaces: %w", err)
+	}
+
+	adminsByOrganization := make(map[uuid.UUID][]database.GetUsersRow)
+	for _, ws := range workspaces {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		labels := map[string]string{
+			"name":     ws.WorkspaceName,
+			"owner":    ws.OwnerUsername,
+			"lastUsed": humanize.RelTime(ws.LastUsedAt, now, "ago", "from now"),
+		}
+		if ws.TimeTilDormant > 0 {
+			dormantAt := ws.LastUsedAt.Add(time.Duration(ws.TimeTilDormant))
+			if dormantAt.After(now) {
+				labels["timeTilDormant"] = humanize.RelTime(dormantAt, now, "ago", "from now")
+			}
+		}
+
+		recipients := []uuid.UUID{ws.OwnerID}
+		if notifyAdmins {
+			admins, ok := adminsByOrganization[ws.OrganizationID]
+			if !ok {
+				admins, err = findTemplateAdmins(ctx, db, ws.OrganizationID)
+				if err != nil {
+					logger.Error(ctx, "unable to find template admins for organization", slog.F("organization_id", ws.OrganizationID), slog.Error(err))
+				}
+				adminsByOrganization[ws.OrganizationID] = admins
+			}
+			for _, admin := range admins {
+				if admin.ID != ws.OwnerID {
+					recipients = append(recipients, admin.ID)
+				}
+			}
+		}
+
+		for _, recipient := range recipients {
+			if _, err := enqueuer.Enqueue(ctx, recipient, notifications.TemplateWorkspaceUnused,
+				labels,
+				"report_generator",
+				ws.WorkspaceID, ws.OwnerID, ws.TemplateID, ws.OrganizationID,
+			); err != nil {
+				logger.Warn(ctx, "failed to notify about unused workspace", slog.F("workspace_id", ws.WorkspaceID), slog.F("user_id", recipient), slog.Error(err))
+			}
+		}
+
+		err = db.UpsertWorkspaceUnusedNotification(ctx, database.UpsertWorkspaceUnusedNotificationParams{
+			WorkspaceID: ws.WorkspaceID,
+			NotifiedAt:  dbtime.Time(now).UTC(),
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to record unused workspace notification: %w", err)
+		}
+	}
+	return nil
+}
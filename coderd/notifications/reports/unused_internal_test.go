@@ -0,0 +1,127 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+func TestReportUnusedWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	const unusedAfter = 7 * dayDuration
+
+	t.Run("NotifyOwner", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+		now := dbtime.Time(clk.Now()).UTC()
+
+		// Given: a template with a dormancy threshold
+		org := dbgen.Organization(t, db, database.Organization{})
+		owner := dbgen.User(t, db, database.User{Username: "owner"})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: owner.ID, OrganizationID: org.ID})
+		tpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: owner.ID})
+		err := db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
+			ID:             tpl.ID,
+			UpdatedAt:      now,
+			TimeTilDormant: int64(30 * dayDuration),
+		})
+		require.NoError(t, err)
+
+		// Given: one unused and one recently used workspace
+		unused := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "unused", TemplateID: tpl.ID, OwnerID: owner.ID, OrganizationID: org.ID, LastUsedAt: now.Add(-10 * dayDuration)})
+		used := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "used", TemplateID: tpl.ID, OwnerID: owner.ID, OrganizationID: org.ID, LastUsedAt: now.Add(-dayDuration)})
+
+		// When
+		notifEnq.Clear()
+		err = reportUnusedWorkspaces(ctx, logger, db, notifEnq, clk, unusedAfter, false)
+
+		// Then: only the owner of the unused workspace is notified
+		require.NoError(t, err)
+		sent := notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceUnused))
+		require.Len(t, sent, 1)
+		require.Equal(t, owner.ID, sent[0].UserID)
+		require.Equal(t, map[string]string{
+			"name":           "unused",
+			"owner":          "owner",
+			"lastUsed":       "1 week ago",
+			"timeTilDormant": "2 weeks from now",
+		}, sent[0].Labels)
+		require.Contains(t, sent[0].Targets, unused.ID)
+
+		// When: run again without the workspace being used
+		notifEnq.Clear()
+		err = reportUnusedWorkspaces(ctx, logger, db, notifEnq, clk, unusedAfter, false)
+
+		// Then: the owner is not notified twice
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+
+		// Given: the unused workspace is used again, and both are left unused
+		err = db.BatchUpdateWorkspaceLastUsedAt(ctx, database.BatchUpdateWorkspaceLastUsedAtParams{
+			LastUsedAt: now,
+			IDs:        []uuid.UUID{unused.ID},
+		})
+		require.NoError(t, err)
+		clk.Advance(unusedAfter + time.Hour)
+
+		// When
+		notifEnq.Clear()
+		err = reportUnusedWorkspaces(ctx, logger, db, notifEnq, clk, unusedAfter, false)
+
+		// Then: both workspaces are reported
+		require.NoError(t, err)
+		sent = notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceUnused))
+		require.Len(t, sent, 2)
+		require.Equal(t, "used", sent[0].Labels["name"])
+		require.Equal(t, "unused", sent[1].Labels["name"])
+		require.Contains(t, sent[0].Targets, used.ID)
+	})
+
+	t.Run("NotifyAdmins", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+		now := dbtime.Time(clk.Now()).UTC()
+
+		// Given: a template admin in the organization of the workspace
+		org := dbgen.Organization(t, db, database.Organization{})
+		templateAdmin := dbgen.User(t, db, database.User{Username: "template-admin", RBACRoles: []string{rbac.RoleTemplateAdmin().Name}})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: templateAdmin.ID, OrganizationID: org.ID})
+		owner := dbgen.User(t, db, database.User{Username: "owner"})
+		_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: owner.ID, OrganizationID: org.ID})
+		tpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: templateAdmin.ID})
+		_ = dbgen.Workspace(t, db, database.WorkspaceTable{Name: "unused", TemplateID: tpl.ID, OwnerID: owner.ID, OrganizationID: org.ID, LastUsedAt: now.Add(-14 * dayDuration)})
+
+		// When
+		notifEnq.Clear()
+		err := reportUnusedWorkspaces(ctx, logger, db, notifEnq, clk, unusedAfter, true)
+
+		// Then: the owner and the template admin are notified
+		require.NoError(t, err)
+		sent := notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceUnused))
+		require.Len(t, sent, 2)
+		require.Equal(t, owner.ID, sent[0].UserID)
+		require.Equal(t, templateAdmin.ID, sent[1].UserID)
+		for _, notif := range sent {
+			// The template has no dormancy threshold.
+			require.Equal(t, map[string]string{
+				"name":     "unused",
+				"owner":    "owner",
+				"lastUsed": "2 weeks ago",
+			}, notif.Labels)
+		}
+	})
+}
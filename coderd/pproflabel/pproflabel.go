@@ -35,6 +35,8 @@ const (
 	// ServiceTallymanPublisher publishes usage events to coder/tallyman.
 	ServiceTallymanPublisher = "tallyman-publisher"
 	ServiceUsageEventCron    = "usage-event-cron"
+	// ServiceWebhooks delivers workspace events to outbound webhooks.
+	ServiceWebhooks = "webhooks"
 
 	RequestTypeTag = "coder_request_type"
 )
@@ -45,6 +45,7 @@ import (
 	"github.com/coder/coder/v2/coderd/usage"
 	"github.com/coder/coder/v2/coderd/usage/usagetypes"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -78,6 +79,7 @@ type Options struct {
 	OIDCConfig          promoauth.OAuth2Config
 	ExternalAuthConfigs []*externalauth.Config
 	AISeatTracker       aiseats.SeatTracker
+	WebhookEnqueuer     webhooks.Enqueuer
 
 	// Clock for testing
 	Clock quartz.Clock
@@ -120,6 +122,7 @@ type server struct {
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	DeploymentValues            *codersdk.DeploymentValues
 	NotificationsEnqueuer       notifications.Enqueuer
+	WebhookEnqueuer             webhooks.Enqueuer
 	PrebuildsOrchestrator       *atomic.Pointer[prebuilds.ReconciliationOrchestrator]
 	UsageInserter               *atomic.Pointer[usage.Inserter]
 	AISeatTracker               aiseats.SeatTracker
@@ -221,6 +224,9 @@ func NewServer(
 	if options.AISeatTracker == nil {
 		options.AISeatTracker = aiseats.Noop{}
 	}
+	if options.WebhookEnqueuer == nil {
+		options.WebhookEnqueuer = webhooks.NewNoopEnqueuer()
+	}
 	if options.AcquireJobLongPollDur == 0 {
 		options.AcquireJobLongPollDur = DefaultAcquireJobLongPollDur
 	}
@@ -245,6 +251,7 @@ func NewServer(
 		Pubsub:                      ps,
 		Acquirer:                    acquirer,
 		NotificationsEnqueuer:       enqueuer,
+		WebhookEnqueuer:             options.WebhookEnqueuer,
 		Telemetry:                   tel,
 		Tracer:                      tracer,
 		QuotaCommitter:              quotaCommitter,
@@ -1284,6 +1291,7 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		}
 
 		s.notifyWorkspaceBuildFailed(ctx, workspace, build)
+		s.enqueueWorkspaceBuildWebhook(ctx, codersdk.WebhookEventWorkspaceBuildFailed, workspace, build, job.Error.String)
 
 		msg, err := json.Marshal(wspubsub.WorkspaceEvent{
 			Kind:        wspubsub.WorkspaceEventKindStateChange,
@@ -2427,6 +2435,7 @@ func (s *server) completeWorkspaceBuildJob(ctx context.Context, job database.Pro
 		if workspaceBuild.Transition == database.WorkspaceTransitionDelete {
 			s.notifyWorkspaceDeleted(ctx, workspace, workspaceBuild)
 		}
+		s.enqueueWorkspaceBuildWebhook(ctx, codersdk.WebhookEventWorkspaceBuildSucceeded, workspace, workspaceBuild, "")
 
 		auditor := s.Auditor.Load()
 		auditAction := auditActionFromTransition(workspaceBuild.Transition)
@@ -2666,6 +2675,31 @@ func (s *server) notifyWorkspaceDeleted(ctx context.Context, workspace database.
 	}
 }
 
+// enqueueWorkspaceBuildWebhook delivers the outcome of a workspace build to the
+// webhooks subscribed to it.
+func (s *server) enqueueWorkspaceBuildWebhook(ctx context.Context, event codersdk.WebhookEvent, workspace database.Workspace, build database.WorkspaceBuild, jobError string) {
+	status := codersdk.ProvisionerJobSucceeded
+	if event == codersdk.WebhookEventWorkspaceBuildFailed {
+		status = codersdk.ProvisionerJobFailed
+	}
+	err := s.WebhookEnqueuer.Enqueue(ctx, codersdk.WebhookPayload{
+		Event:     event,
+		Timestamp: s.timeNow(),
+		Workspace: webhooks.Workspace(workspace),
+		Build: &codersdk.WebhookWorkspaceBuild{
+			ID:          build.ID,
+			BuildNumber: build.BuildNumber,
+			Transition:  codersdk.WorkspaceTransition(build.Transition),
+			Reason:      codersdk.BuildReason(build.Reason),
+			Status:      status,
+			Error:       jobError,
+		},
+	})
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to enqueue workspace build webhook", slog.F("event", event), slog.Error(err))
+	}
+}
+
 func (s *server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	return s.Tracer.Start(ctx, name, append(opts, trace.WithAttributes(
 		semconv.ServiceNameKey.String("coderd.provisionerd"),
@@ -0,0 +1,144 @@
+package coderd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Upload scaletest result
+// @ID upload-scaletest-result
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.UploadScaletestResultRequest true "Scaletest result"
+// @Success 201 {object} codersdk.ScaletestResult
+// @Router /api/v2/scaletest/results [post]
+func (api *API) postScaletestResult(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	var req codersdk.UploadScaletestResultRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !json.Valid(req.Summary) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Summary must be valid JSON.",
+		})
+		return
+	}
+
+	result, err := api.Database.InsertScaletestResult(ctx, database.InsertScaletestResultParams{
+		ID:        uuid.New(),
+		CreatedAt: dbtime.Now(),
+		CreatedBy: apiKey.UserID,
+		Name:      req.Name,
+		// #nosec G115 - Run counts are bounded by the scaletest harness.
+		TotalRuns: int32(req.TotalRuns),
+		// #nosec G115 - Run counts are bounded by the scaletest harness.
+		TotalPass: int32(req.TotalPass),
+		// #nosec G115 - Run counts are bounded by the scaletest harness.
+		TotalFail: int32(req.TotalFail),
+		ElapsedMs: req.ElapsedMS,
+		Summary:   req.Summary,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error storing scaletest result.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.ScaletestResult(result))
+}
+
+// @Summary List scaletest results
+// @ID list-scaletest-results
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Param name query string false "Filter by test name"
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.ScaletestResult
+// @Router /api/v2/scaletest/results [get]
+func (api *API) scaletestResults(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, ok := ParsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	results, err := api.Database.GetScaletestResults(ctx, database.GetScaletestResultsParams{
+		Name: r.URL.Query().Get("name"),
+		// #nosec G115 - Pagination offsets are small and fit in int32
+		OffsetOpt: int32(page.Offset),
+		// #nosec G115 - Pagination limits are small and fit in int32
+		LimitOpt: int32(page.Limit),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching scaletest results.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, slice.List(results, db2sdk.ScaletestResult))
+}
+
+// @Summary Get scaletest result by ID
+// @ID get-scaletest-result-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Param scaletestresult path string true "Scaletest result ID" format(uuid)
+// @Success 200 {object} codersdk.ScaletestResult
+// @Router /api/v2/scaletest/results/{scaletestresult} [get]
+func (api *API) scaletestResult(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := httpmw.ParseUUIDParam(rw, r, "scaletestresult")
+	if !ok {
+		return
+	}
+
+	result, err := api.Database.GetScaletestResultByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching scaletest result.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.ScaletestResult(result))
+}
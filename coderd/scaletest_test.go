@@ -0,0 +1,106 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestScaletestResults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		first, err := client.UploadScaletestResult(ctx, codersdk.UploadScaletestResultRequest{
+			Name:      "create-workspaces",
+			TotalRuns: 10,
+			TotalPass: 9,
+			TotalFail: 1,
+			ElapsedMS: 60000,
+			Summary:   json.RawMessage(`{"schema_version":1,"total_runs":10}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, owner.UserID, first.CreatedBy)
+		require.Equal(t, 9, first.TotalPass)
+		require.JSONEq(t, `{"schema_version":1,"total_runs":10}`, string(first.Summary))
+
+		second, err := client.UploadScaletestResult(ctx, codersdk.UploadScaletestResultRequest{
+			Name:      "workspace-traffic",
+			TotalRuns: 5,
+			TotalPass: 5,
+			Summary:   json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+
+		results, err := client.ScaletestResults(ctx, codersdk.ScaletestResultsRequest{})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		// Newest results come first.
+		require.Equal(t, second.ID, results[0].ID)
+		require.Equal(t, first.ID, results[1].ID)
+
+		results, err = client.ScaletestResults(ctx, codersdk.ScaletestResultsRequest{Name: "create-workspaces"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, first.ID, results[0].ID)
+
+		results, err = client.ScaletestResults(ctx, codersdk.ScaletestResultsRequest{
+			Pagination: codersdk.Pagination{Limit: 1, Offset: 1},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, first.ID, results[0].ID)
+
+		got, err := client.ScaletestResult(ctx, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, first.ID, got.ID)
+		require.Equal(t, first.Name, got.Name)
+		require.JSONEq(t, string(first.Summary), string(got.Summary))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.ScaletestResult(ctx, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.UploadScaletestResult(ctx, codersdk.UploadScaletestResultRequest{
+			Name:    "create-workspaces",
+			Summary: json.RawMessage(`{}`),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.ScaletestResults(ctx, codersdk.ScaletestResultsRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 const MaxTemplateAutostopRequirementWeeks = 16
@@ -137,6 +138,9 @@ type TemplateScheduleOptions struct {
 	// TimeTilDormantAutoDelete dictates the duration after which dormant workspaces will be
 	// permanently deleted.
 	TimeTilDormantAutoDelete time.Duration
+	// DormancyActivitySignal dictates the agent activity that counts towards
+	// TimeTilDormant. Empty means the deployment default.
+	DormancyActivitySignal codersdk.DormancyActivitySignal
 	// UpdateWorkspaceLastUsedAt updates the template's workspaces'
 	// last_used_at field. This is useful for preventing updates to the
 	// templates inactivity_ttl immediately triggering a dormant action against
@@ -183,7 +187,8 @@ func (*agplTemplateScheduleStore) Get(ctx context.Context, db database.Store, te
 		DefaultTTL:           time.Duration(tpl.DefaultTTL),
 		ActivityBump:         time.Duration(tpl.ActivityBump),
 		// Disregard the values in the database, since AutostopRequirement,
-		// FailureTTL, TimeTilDormant, and TimeTilDormantAutoDelete are enterprise features.
+		// FailureTTL, TimeTilDormant, TimeTilDormantAutoDelete and
+		// DormancyActivitySignal are enterprise features.
 		AutostartRequirement: TemplateAutostartRequirement{
 			// Default to allowing all days for AGPL
 			DaysOfWeek: 0b01111111,
@@ -226,6 +231,7 @@ func (*agplTemplateScheduleStore) Set(ctx context.Context, db database.Store, tp
 			FailureTTL:                    tpl.FailureTTL,
 			TimeTilDormant:                tpl.TimeTilDormant,
 			TimeTilDormantAutoDelete:      tpl.TimeTilDormantAutoDelete,
+			DormancyActivitySignal:        tpl.DormancyActivitySignal,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template schedule: %w", err)
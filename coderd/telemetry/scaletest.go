@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/buildinfo"
+)
+
+// ScaletestRun summarizes the outcome of a scaletest run with
+// `coder exp scaletest` against a deployment. It is only reported if the
+// operator opted in, and contains no identifiers of the deployment or its
+// users, only its size class, to help tune the sizing guidance.
+type ScaletestRun struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Command is the name of the scaletest command, e.g. create-workspaces.
+	Command string `json:"command"`
+	// CoderVersion is the version of the deployment under test.
+	CoderVersion string `json:"coder_version"`
+	// SizeClass is the size class of the deployment under test, see
+	// ScaletestSizeClass.
+	SizeClass  string  `json:"size_class"`
+	TotalRuns  int     `json:"total_runs"`
+	TotalFail  int     `json:"total_fail"`
+	ElapsedSec float64 `json:"elapsed_sec"`
+	// Throughput is the number of successful runs per second.
+	Throughput float64 `json:"throughput"`
+	// ErrorRate is the fraction of failed runs.
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// ScaletestSizeClass returns the size class of a deployment with the given
+// number of users, matching the validated reference architectures.
+func ScaletestSizeClass(users int) string {
+	switch {
+	case users <= 1000:
+		return "up_to_1000_users"
+	case users <= 2000:
+		return "up_to_2000_users"
+	case users <= 3000:
+		return "up_to_3000_users"
+	default:
+		return "over_3000_users"
+	}
+}
+
+// Submit sends a single snapshot to the telemetry server at the given URL and
+// waits for it to be accepted. Unlike a Reporter, it doesn't require a
+// database, so it can be used outside of coderd, e.g. by the CLI.
+func Submit(ctx context.Context, client *http.Client, telemetryURL *url.URL, snapshot *Snapshot) error {
+	snapshotURL, err := telemetryURL.Parse("/snapshot")
+	if err != nil {
+		return xerrors.Errorf("parse snapshot url: %w", err)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return xerrors.Errorf("marshal snapshot: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, snapshotURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("create snapshot request: %w", err)
+	}
+	req.Header.Set(VersionHeader, buildinfo.Version())
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("submit snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return xerrors.Errorf("unexpected status code %d from telemetry server", resp.StatusCode)
+	}
+	return nil
+}
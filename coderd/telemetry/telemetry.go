@@ -1611,6 +1611,7 @@ type Snapshot struct {
 	ChatDiffStatusSummary                *ChatDiffStatusSummary                `json:"chat_diff_status_summary"`
 	UserSecretsSummary                   *UserSecretsSummary                   `json:"user_secrets_summary"`
 	TemplateBuilderSessions              []TemplateBuilderSession              `json:"template_builder_sessions"`
+	ScaletestRuns                        []ScaletestRun                        `json:"scaletest_runs"`
 }
 
 // Deployment contains information about the host running Coder.
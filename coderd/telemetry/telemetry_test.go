@@ -2243,3 +2243,38 @@ func TestUserSecretsTelemetry(t *testing.T) {
 		require.Nil(t, snap2.UserSecretsSummary)
 	})
 }
+
+func TestSubmitScaletestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	serverURL, _, snapshots := mockTelemetryServer(ctx, t)
+	run := telemetry.ScaletestRun{
+		ID:         uuid.New(),
+		CreatedAt:  dbtime.Now(),
+		Command:    "create-workspaces",
+		SizeClass:  telemetry.ScaletestSizeClass(1500),
+		TotalRuns:  10,
+		TotalFail:  1,
+		ElapsedSec: 20,
+		Throughput: 0.45,
+		ErrorRate:  0.1,
+	}
+	err := telemetry.Submit(ctx, http.DefaultClient, serverURL, &telemetry.Snapshot{
+		ScaletestRuns: []telemetry.ScaletestRun{run},
+	})
+	require.NoError(t, err)
+
+	snapshot := testutil.TryReceive(ctx, t, snapshots)
+	require.Len(t, snapshot.ScaletestRuns, 1)
+	require.Equal(t, "up_to_2000_users", snapshot.ScaletestRuns[0].SizeClass)
+	require.Equal(t, run.ID, snapshot.ScaletestRuns[0].ID)
+	require.Equal(t, 0.1, snapshot.ScaletestRuns[0].ErrorRate)
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	badURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	err = telemetry.Submit(ctx, http.DefaultClient, badURL, &telemetry.Snapshot{})
+	require.ErrorContains(t, err, "unexpected status code 404")
+}
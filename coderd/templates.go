@@ -822,6 +822,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			FailureTTL:                failureTTL,
 			TimeTilDormant:            inactivityTTL,
 			TimeTilDormantAutoDelete:  timeTilDormantAutoDelete,
+			DormancyActivitySignal:    resolved.dormancyActivitySignal,
 			UpdateWorkspaceLastUsedAt: updateWorkspaceLastUsedAt,
 			UpdateWorkspaceDormantAt:  resolved.updateWorkspaceDormantAtIntent,
 		})
@@ -1044,6 +1045,7 @@ func (api *API) convertTemplate(
 		UseClassicParameterFlow: template.UseClassicParameterFlow,
 		CORSBehavior:            codersdk.CORSBehavior(template.CorsBehavior),
 		DisableModuleCache:      template.DisableModuleCache,
+		DormancyActivitySignal:  codersdk.DormancyActivitySignal(template.DormancyActivitySignal),
 	}
 }
 
@@ -32,6 +32,7 @@ type templateMetaUpdate struct {
 	deprecationMessage                   string
 	useClassicTemplateFlow               bool
 	disableModuleCache                   bool
+	dormancyActivitySignal               codersdk.DormancyActivitySignal
 	corsBehavior                         database.CorsBehavior
 	autostopRequirementDaysOfWeekParsed  uint8
 	autostartRequirementDaysOfWeekParsed uint8
@@ -51,7 +52,7 @@ type templateMetaUpdate struct {
 //
 // This function validates shape, not contents: it parses the
 // autostop/autostart day-of-week strings into bitmaps and ensures any
-// non-empty CORS behavior is a recognized enum. Errors it returns are
+// non-empty CORS behavior or dormancy activity signal is a recognized enum. Errors it returns are
 // user-facing validation errors the caller must surface as 400 Bad
 // Request.
 //
@@ -83,6 +84,7 @@ func resolveTemplateMetaUpdate(
 		deprecationMessage:             ptr.NilToDefault(req.DeprecationMessage, template.Deprecated),
 		useClassicTemplateFlow:         ptr.NilToDefault(req.UseClassicParameterFlow, template.UseClassicParameterFlow),
 		disableModuleCache:             ptr.NilToDefault(req.DisableModuleCache, template.DisableModuleCache),
+		dormancyActivitySignal:         ptr.NilToDefault(req.DormancyActivitySignal, codersdk.DormancyActivitySignal(template.DormancyActivitySignal)),
 		groupACL:                       template.GroupACL,
 
 		// Default to the original values
@@ -149,6 +151,17 @@ func resolveTemplateMetaUpdate(
 		}
 	}
 
+	// An empty dormancy activity signal resets the template to the
+	// deployment default.
+	if out.dormancyActivitySignal != "" && !out.dormancyActivitySignal.Valid() {
+		validErrs = append(validErrs, codersdk.ValidationError{
+			Field: "dormancy_activity_signal",
+			Detail: "Invalid dormancy activity signal \"" + string(out.dormancyActivitySignal) +
+				"\". Must be empty or one of [" + strings.Join(codersdk.DormancyActivitySignals, ", ") + "]",
+		})
+		out.dormancyActivitySignal = codersdk.DormancyActivitySignal(template.DormancyActivitySignal)
+	}
+
 	if req.DisableEveryoneGroupAccess != nil && *req.DisableEveryoneGroupAccess {
 		// Remove the "everyone" group from the template. If this is set to false, the
 		// user needs to explicitly add the "everyone" group back to the ACL via the
@@ -42,6 +42,7 @@ func baselineTemplate() database.Template {
 		UseClassicParameterFlow:       true,
 		CorsBehavior:                  database.CorsBehaviorPassthru,
 		DisableModuleCache:            true,
+		DormancyActivitySignal:        string(codersdk.DormancyActivitySignalSession),
 		GroupACL: database.TemplateACL{
 			orgID.String(): {"read"},
 		},
@@ -83,6 +84,7 @@ func baselineResolved() templateMetaUpdate {
 		deprecationMessage:                   tpl.Deprecated,
 		useClassicTemplateFlow:               tpl.UseClassicParameterFlow,
 		disableModuleCache:                   tpl.DisableModuleCache,
+		dormancyActivitySignal:               codersdk.DormancyActivitySignal(tpl.DormancyActivitySignal),
 		corsBehavior:                         tpl.CorsBehavior,
 		autostopRequirementDaysOfWeekParsed:  0b0000001,
 		autostartRequirementDaysOfWeekParsed: 0b1000000,
@@ -265,6 +267,28 @@ func TestResolveTemplateMetaUpdate(t *testing.T) {
 				r.disableModuleCache = false
 			}},
 		},
+		{
+			name: "DormancyActivitySignal",
+			req:  codersdk.UpdateTemplateMeta{DormancyActivitySignal: ptr.Ref(codersdk.DormancyActivitySignalTraffic)},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.dormancyActivitySignal = codersdk.DormancyActivitySignalTraffic
+			}},
+		},
+		{
+			name: "DormancyActivitySignalEmptyStringResetsToDefault",
+			req:  codersdk.UpdateTemplateMeta{DormancyActivitySignal: ptr.Ref(codersdk.DormancyActivitySignal(""))},
+			expected: expected{override: func(r *templateMetaUpdate) {
+				r.dormancyActivitySignal = ""
+			}},
+		},
+		{
+			name: "DormancyActivitySignalInvalid",
+			req:  codersdk.UpdateTemplateMeta{DormancyActivitySignal: ptr.Ref(codersdk.DormancyActivitySignal("keystrokes"))},
+			expected: expected{
+				override:       func(*templateMetaUpdate) {},
+				validErrFields: []string{"dormancy_activity_signal"},
+			},
+		},
 
 		// CORS behavior.
 		{
@@ -0,0 +1,285 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// @Summary List webhooks
+// @ID list-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Success 200 {array} codersdk.Webhook
+// @Router /api/v2/webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	webhooks, err := api.Database.GetWebhooks(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, slice.List(webhooks, db2sdk.Webhook))
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param request body codersdk.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} codersdk.Webhook
+// @Router /api/v2/webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if len(req.Events) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "At least one webhook event is required.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "events",
+				Detail: fmt.Sprintf("Must contain at least one of %v.", codersdk.WebhookEvents),
+			}},
+		})
+		return
+	}
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		if !event.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid webhook event %q.", event),
+				Validations: []codersdk.ValidationError{{
+					Field:  "events",
+					Detail: fmt.Sprintf("Must be one of %v.", codersdk.WebhookEvents),
+				}},
+			})
+			return
+		}
+		if !slices.Contains(events, string(event)) {
+			events = append(events, string(event))
+		}
+	}
+
+	var templateID uuid.NullUUID
+	if req.TemplateID != nil {
+		_, err := api.Database.GetTemplateByID(ctx, *req.TemplateID)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Template %q not found.", req.TemplateID),
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_id",
+					Detail: "Template not found.",
+				}},
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		templateID = uuid.NullUUID{UUID: *req.TemplateID, Valid: true}
+	}
+
+	secret, err := cryptorand.String(32)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating webhook secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	webhook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:         uuid.New(),
+		CreatedAt:  dbtime.Now(),
+		CreatedBy:  apiKey.UserID,
+		TemplateID: templateID,
+		Url:        req.URL,
+		Secret:     secret,
+		Events:     events,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = webhook
+
+	res := db2sdk.Webhook(webhook)
+	res.Secret = webhook.Secret
+	httpapi.Write(ctx, rw, http.StatusCreated, res)
+}
+
+// @Summary Get webhook by ID
+// @ID get-webhook-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Webhook
+// @Router /api/v2/webhooks/{webhook} [get]
+func (api *API) webhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	webhook, ok := api.webhookParam(rw, r)
+	if !ok {
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Webhook(webhook))
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Tags Notifications
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 204
+// @Router /api/v2/webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	webhook, ok := api.webhookParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = webhook
+
+	err := api.Database.DeleteWebhookByID(ctx, webhook.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List webhook deliveries
+// @ID list-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /api/v2/webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	webhook, ok := api.webhookParam(rw, r)
+	if !ok {
+		return
+	}
+	page, ok := ParsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhook.ID,
+		// #nosec G115 - Pagination offsets are small and fit in int32
+		OffsetOpt: int32(page.Offset),
+		// #nosec G115 - Pagination limits are small and fit in int32
+		LimitOpt: int32(page.Limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook deliveries.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, slice.List(deliveries, db2sdk.WebhookDelivery))
+}
+
+func (api *API) webhookParam(rw http.ResponseWriter, r *http.Request) (database.Webhook, bool) {
+	ctx := r.Context()
+
+	id, ok := httpmw.ParseUUIDParam(rw, r, "webhook")
+	if !ok {
+		return database.Webhook{}, false
+	}
+
+	webhook, err := api.Database.GetWebhookByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.Webhook{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook.",
+			Detail:  err.Error(),
+		})
+		return database.Webhook{}, false
+	}
+	return webhook, true
+}
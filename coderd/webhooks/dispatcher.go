@@ -0,0 +1,255 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/pproflabel"
+	"github.com/coder/quartz"
+)
+
+const (
+	// Headers set on every delivery.
+	HeaderEvent     = "X-Coder-Webhook-Event"
+	HeaderDelivery  = "X-Coder-Webhook-Delivery"
+	HeaderTimestamp = "X-Coder-Webhook-Timestamp"
+	HeaderSignature = "X-Coder-Webhook-Signature"
+
+	// MaxAttempts is the number of times a delivery is attempted before it's
+	// marked as failed.
+	MaxAttempts = 8
+
+	interval     = 10 * time.Second
+	batchSize    = 50
+	timeout      = 10 * time.Second
+	backoffBase  = 30 * time.Second
+	backoffLimit = time.Hour
+	// The lease must outlive the attempts of a whole batch, otherwise another
+	// replica could acquire a delivery that is still being attempted.
+	leaseDuration = batchSize * timeout
+)
+
+type Option func(*dispatcher)
+
+// WithClock overrides the clock used by the dispatcher. Defaults to
+// quartz.NewReal().
+func WithClock(clk quartz.Clock) Option {
+	return func(d *dispatcher) { d.clk = clk }
+}
+
+// WithHTTPClient overrides the client deliveries are sent with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *dispatcher) { d.client = client }
+}
+
+type dispatcher struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+	logger slog.Logger
+	db     database.Store
+	clk    quartz.Clock
+	client *http.Client
+}
+
+// NewDispatcher starts periodically delivering pending webhook deliveries.
+// Callers must Close the returned dispatcher.
+func NewDispatcher(ctx context.Context, logger slog.Logger, db database.Store, opts ...Option) io.Closer {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // Delivering webhooks is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	d := &dispatcher{
+		cancel: cancelFunc,
+		closed: make(chan struct{}),
+		logger: logger,
+		db:     db,
+		clk:    quartz.NewReal(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.client == nil {
+		d.client = newHTTPClient(ctx, logger)
+	}
+
+	ticker := d.clk.NewTicker(interval)
+	doTick := func(ctx context.Context) {
+		defer ticker.Reset(interval)
+		if err := d.dispatch(ctx); err != nil && !xerrors.Is(err, context.Canceled) {
+			logger.Error(ctx, "failed to dispatch webhook deliveries", slog.Error(err))
+		}
+	}
+
+	pproflabel.Go(ctx, pproflabel.Service(pproflabel.ServiceWebhooks), func(ctx context.Context) {
+		defer close(d.closed)
+		defer ticker.Stop()
+		// Force an initial tick.
+		doTick(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ticker.Stop()
+				doTick(ctx)
+			}
+		}
+	})
+	return d
+}
+
+func (d *dispatcher) Close() error {
+	d.cancel()
+	<-d.closed
+	return nil
+}
+
+// dispatch attempts the deliveries that are due, until none are left.
+func (d *dispatcher) dispatch(ctx context.Context) error {
+	for {
+		now := dbtime.Time(d.clk.Now())
+		deliveries, err := d.db.AcquireWebhookDeliveries(ctx, database.AcquireWebhookDeliveriesParams{
+			Now:        now,
+			LeaseUntil: now.Add(leaseDuration),
+			LimitOpt:   batchSize,
+		})
+		if err != nil {
+			return xerrors.Errorf("acquire webhook deliveries: %w", err)
+		}
+		for _, delivery := range deliveries {
+			if err := d.deliver(ctx, delivery); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < batchSize {
+			return nil
+		}
+	}
+}
+
+// deliver attempts a delivery and records its outcome.
+func (d *dispatcher) deliver(ctx context.Context, delivery database.AcquireWebhookDeliveriesRow) error {
+	statusCode, attemptErr := d.send(ctx, delivery)
+	if ctx.Err() != nil {
+		// The delivery is attempted again once its lease expires.
+		return ctx.Err()
+	}
+
+	now := dbtime.Time(d.clk.Now())
+	arg := database.UpdateWebhookDeliveryByIDParams{
+		ID:            delivery.ID,
+		Status:        database.WebhookDeliveryStatusSucceeded,
+		UpdatedAt:     now,
+		NextAttemptAt: now,
+	}
+	if statusCode != 0 {
+		// #nosec G115 - HTTP status codes fit in int32.
+		arg.ResponseStatusCode = sql.NullInt32{Int32: int32(statusCode), Valid: true}
+	}
+	if attemptErr != nil {
+		arg.Error = sql.NullString{String: attemptErr.Error(), Valid: true}
+		if delivery.Attempts >= MaxAttempts {
+			arg.Status = database.WebhookDeliveryStatusFailed
+		} else {
+			arg.Status = database.WebhookDeliveryStatusPending
+			arg.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+		}
+		d.logger.Warn(ctx, "webhook delivery attempt failed",
+			slog.F("delivery_id", delivery.ID),
+			slog.F("webhook_id", delivery.WebhookID),
+			slog.F("attempts", delivery.Attempts),
+			slog.Error(attemptErr),
+		)
+	}
+
+	if err := d.db.UpdateWebhookDeliveryByID(ctx, arg); err != nil {
+		return xerrors.Errorf("update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// send POSTs the payload of the delivery to its webhook, and returns the
+// status code of the response if there was one.
+func (d *dispatcher) send(ctx context.Context, delivery database.AcquireWebhookDeliveriesRow) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, xerrors.Errorf("create HTTP request: %w", err)
+	}
+	timestamp := d.clk.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Signature(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, xerrors.Errorf("request timeout: %w", err)
+		}
+		return 0, xerrors.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain some of the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, xerrors.Errorf("non-2xx response (%d)", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Signature returns the value of the signature header of a delivery: the hex
+// encoded HMAC-SHA256 of the timestamp, a dot and the body, keyed with the
+// secret of the webhook. Receivers should compute the same value and compare
+// it in constant time, and reject deliveries with old timestamps to prevent
+// replays.
+func Signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.", timestamp)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns how long to wait before attempting a delivery again after
+// the given number of failed attempts.
+func Backoff(attempts int32) time.Duration {
+	backoff := backoffBase
+	for i := int32(1); i < attempts && backoff < backoffLimit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, backoffLimit)
+}
+
+func newHTTPClient(ctx context.Context, logger slog.Logger) *http.Client {
+	// Create a new transport in favor of reusing the default, since other http clients may interfere.
+	var rt http.RoundTripper
+	def := http.DefaultTransport
+	t, ok := def.(*http.Transport)
+	if !ok {
+		logger.Warn(ctx, "failed to clone default HTTP transport, unexpected type", slog.F("type", fmt.Sprintf("%T", def)))
+		rt = def
+	} else {
+		rt = t.Clone()
+	}
+	return &http.Client{Transport: rt}
+}
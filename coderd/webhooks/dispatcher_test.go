@@ -0,0 +1,154 @@
+package webhooks_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"event":"workspace_build_succeeded"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write([]byte("1700000000."))
+	_, _ = mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	require.Equal(t, want, webhooks.Signature("secret", 1700000000, body))
+	require.NotEqual(t, want, webhooks.Signature("other", 1700000000, body))
+	require.NotEqual(t, want, webhooks.Signature("secret", 1700000001, body))
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 30*time.Second, webhooks.Backoff(1))
+	require.Equal(t, time.Minute, webhooks.Backoff(2))
+	require.Equal(t, 2*time.Minute, webhooks.Backoff(3))
+	require.Equal(t, time.Hour, webhooks.Backoff(webhooks.MaxAttempts))
+	require.Equal(t, time.Hour, webhooks.Backoff(100))
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	payload, err := json.Marshal(codersdk.WebhookPayload{
+		Event: codersdk.WebhookEventWorkspaceBuildSucceeded,
+		Workspace: codersdk.WebhookWorkspace{
+			ID:   uuid.New(),
+			Name: "dev",
+		},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name       string
+		statusCode int
+		attempts   int32
+		wantStatus database.WebhookDeliveryStatus
+		wantRetry  time.Duration
+	}{
+		{
+			name:       "Succeeded",
+			statusCode: http.StatusOK,
+			attempts:   1,
+			wantStatus: database.WebhookDeliveryStatusSucceeded,
+		},
+		{
+			name:       "Retried",
+			statusCode: http.StatusInternalServerError,
+			attempts:   2,
+			wantStatus: database.WebhookDeliveryStatusPending,
+			wantRetry:  time.Minute,
+		},
+		{
+			name:       "Failed",
+			statusCode: http.StatusInternalServerError,
+			attempts:   webhooks.MaxAttempts,
+			wantStatus: database.WebhookDeliveryStatusFailed,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitShort)
+			clk := quartz.NewMock(t)
+			now := dbtime.Time(clk.Now())
+
+			delivery := database.AcquireWebhookDeliveriesRow{
+				ID:        uuid.New(),
+				WebhookID: uuid.New(),
+				Event:     string(codersdk.WebhookEventWorkspaceBuildSucceeded),
+				Payload:   payload,
+				Attempts:  tc.attempts,
+				Secret:    "secret",
+			}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.JSONEq(t, string(payload), string(body))
+				assert.Equal(t, delivery.Event, r.Header.Get(webhooks.HeaderEvent))
+				assert.Equal(t, delivery.ID.String(), r.Header.Get(webhooks.HeaderDelivery))
+				timestamp, err := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+				assert.NoError(t, err)
+				assert.Equal(t, webhooks.Signature("secret", timestamp, body), r.Header.Get(webhooks.HeaderSignature))
+				rw.WriteHeader(tc.statusCode)
+			}))
+			defer srv.Close()
+			delivery.Url = srv.URL
+
+			ctrl := gomock.NewController(t)
+			db := dbmock.NewMockStore(ctrl)
+			db.EXPECT().AcquireWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Return([]database.AcquireWebhookDeliveriesRow{delivery}, nil).Times(1)
+			updated := make(chan database.UpdateWebhookDeliveryByIDParams, 1)
+			db.EXPECT().UpdateWebhookDeliveryByID(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ any, arg database.UpdateWebhookDeliveryByIDParams) error {
+					updated <- arg
+					return nil
+				}).Times(1)
+
+			logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+			dispatcher := webhooks.NewDispatcher(ctx, logger, db, webhooks.WithClock(clk))
+			defer dispatcher.Close()
+
+			arg := testutil.TryReceive(ctx, t, updated)
+			require.Equal(t, delivery.ID, arg.ID)
+			require.Equal(t, tc.wantStatus, arg.Status)
+			require.True(t, arg.ResponseStatusCode.Valid)
+			require.EqualValues(t, tc.statusCode, arg.ResponseStatusCode.Int32)
+			require.Equal(t, now.Add(tc.wantRetry), arg.NextAttemptAt)
+			if tc.wantStatus == database.WebhookDeliveryStatusSucceeded {
+				require.False(t, arg.Error.Valid)
+			} else {
+				require.Equal(t, fmt.Sprintf("non-2xx response (%d)", tc.statusCode), arg.Error.String)
+			}
+		})
+	}
+}
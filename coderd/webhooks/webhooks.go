@@ -0,0 +1,88 @@
+// Package webhooks delivers workspace events to the outbound webhooks
+// configured by deployment admins.
+//
+// Events are enqueued as one delivery per subscribed webhook in the database,
+// in the same way notification messages are, so that they survive restarts
+// and can be retried by any replica. The Dispatcher acquires pending
+// deliveries, POSTs them to the webhook and records the outcome.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Enqueuer enqueues the delivery of workspace events to the webhooks
+// subscribed to them.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, payload codersdk.WebhookPayload) error
+}
+
+// StoreEnqueuer enqueues deliveries in the database, where they are picked up
+// by a Dispatcher.
+type StoreEnqueuer struct {
+	db database.Store
+}
+
+var _ Enqueuer = &StoreEnqueuer{}
+
+func NewStoreEnqueuer(db database.Store) *StoreEnqueuer {
+	return &StoreEnqueuer{db: db}
+}
+
+// Enqueue enqueues a delivery of the payload to every webhook subscribed to
+// its event, either for all workspaces or for the template of its workspace.
+func (s *StoreEnqueuer) Enqueue(ctx context.Context, payload codersdk.WebhookPayload) error {
+	if payload.Timestamp.IsZero() {
+		payload.Timestamp = dbtime.Now()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	//nolint:gocritic // Enqueueing webhook deliveries is a system function.
+	_, err = s.db.InsertWebhookDeliveries(dbauthz.AsSystemRestricted(ctx), database.InsertWebhookDeliveriesParams{
+		CreatedAt:  dbtime.Time(payload.Timestamp),
+		Event:      string(payload.Event),
+		Payload:    data,
+		TemplateID: payload.Workspace.TemplateID,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// NoopEnqueuer discards all events.
+type NoopEnqueuer struct{}
+
+var _ Enqueuer = NoopEnqueuer{}
+
+func NewNoopEnqueuer() NoopEnqueuer {
+	return NoopEnqueuer{}
+}
+
+func (NoopEnqueuer) Enqueue(context.Context, codersdk.WebhookPayload) error {
+	return nil
+}
+
+// Workspace converts a workspace to the form it is sent to webhooks in.
+func Workspace(workspace database.Workspace) codersdk.WebhookWorkspace {
+	return codersdk.WebhookWorkspace{
+		ID:             workspace.ID,
+		Name:           workspace.Name,
+		OwnerID:        workspace.OwnerID,
+		OwnerName:      workspace.OwnerUsername,
+		OrganizationID: workspace.OrganizationID,
+		TemplateID:     workspace.TemplateID,
+		TemplateName:   workspace.TemplateName,
+	}
+}
@@ -0,0 +1,146 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL: "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{
+				codersdk.WebhookEventWorkspaceBuildFailed,
+				codersdk.WebhookEventWorkspaceBuildFailed,
+				codersdk.WebhookEventWorkspaceResourceThresholdExceeded,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, owner.UserID, created.CreatedBy)
+		require.Nil(t, created.TemplateID)
+		require.NotEmpty(t, created.Secret)
+		// Duplicate events are only stored once.
+		require.Equal(t, []codersdk.WebhookEvent{
+			codersdk.WebhookEventWorkspaceBuildFailed,
+			codersdk.WebhookEventWorkspaceResourceThresholdExceeded,
+		}, created.Events)
+
+		// The secret is only returned when the webhook is created.
+		got, err := client.Webhook(ctx, created.ID)
+		require.NoError(t, err)
+		require.Empty(t, got.Secret)
+		require.Equal(t, created.URL, got.URL)
+
+		webhooks, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, created.ID, webhooks[0].ID)
+
+		deliveries, err := client.WebhookDeliveries(ctx, created.ID, codersdk.WebhookDeliveriesRequest{})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+
+		err = client.DeleteWebhook(ctx, created.ID)
+		require.NoError(t, err)
+		_, err = client.Webhook(ctx, created.ID)
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeWebhook,
+			ResourceID:   created.ID,
+		}))
+
+		err = client.DeleteWebhook(ctx, created.ID)
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeWebhook,
+			ResourceID:   created.ID,
+		}))
+	})
+
+	t.Run("InvalidEvent", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{"workspace_exploded"},
+		})
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("TemplateNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		templateID := uuid.New()
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:        "https://example.com/hook",
+			TemplateID: &templateID,
+			Events:     []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildSucceeded},
+		})
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildSucceeded},
+		})
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+
+		_, err = member.Webhooks(ctx)
+		cerr = coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
+}
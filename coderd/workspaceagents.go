@@ -41,6 +41,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	maputil "github.com/coder/coder/v2/coderd/util/maps"
+	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 	"github.com/coder/coder/v2/coderd/x/chatd"
 	"github.com/coder/coder/v2/coderd/x/chatd/chatprompt"
@@ -365,6 +366,138 @@ func (api *API) patchWorkspaceAgentAppStatus(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, nil)
 }
 
+const (
+	// maxAgentStatsBatchSize is the number of stats an agent can report in a
+	// single request.
+	maxAgentStatsBatchSize = 100
+	// maxAgentStatsBatchAge is how long ago reported stats can have been
+	// collected. Older stats would not be rolled up into the template usage
+	// stats anymore, so they are dropped.
+	maxAgentStatsBatchAge = time.Hour
+	// agentStatsClockSkew is how far in the future reported stats can have
+	// been collected, to allow for the agent's clock to be ahead.
+	agentStatsClockSkew = time.Minute
+)
+
+// @Summary Submit workspace agent stats batch
+// @ID submit-workspace-agent-stats-batch
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostStatsRequest true "Stats batch"
+// @Success 200 {object} agentsdk.StatsResponse
+// @Router /api/v2/workspaceagents/me/stats [post]
+func (api *API) postWorkspaceAgentStats(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostStatsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.Stats) == 0 || len(req.Stats) > maxAgentStatsBatchSize {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Between 1 and %d stats must be reported at once.", maxAgentStatsBatchSize),
+			Validations: []codersdk.ValidationError{
+				{Field: "stats", Detail: fmt.Sprintf("Must contain between 1 and %d items", maxAgentStatsBatchSize)},
+			},
+		})
+		return
+	}
+
+	now := dbtime.Time(api.Clock.Now())
+	var (
+		validations []codersdk.ValidationError
+		dropped     int
+	)
+	reports := make([]workspacestats.TimestampedStats, 0, len(req.Stats))
+	for i, st := range req.Stats {
+		field := fmt.Sprintf("stats[%d]", i)
+		if !st.CollectedAt.IsZero() && st.CollectedAt.Before(now.Add(-maxAgentStatsBatchAge)) {
+			// An agent that was disconnected for a long time can't help
+			// having stale stats, so the rest of the batch is still recorded.
+			dropped++
+			continue
+		}
+		switch {
+		case st.CollectedAt.IsZero():
+			validations = append(validations, codersdk.ValidationError{Field: field + ".collected_at", Detail: "Required"})
+		case st.CollectedAt.After(now.Add(agentStatsClockSkew)):
+			validations = append(validations, codersdk.ValidationError{Field: field + ".collected_at", Detail: "Must not be in the future"})
+		}
+		if st.Stats.ConnectionCount < 0 || st.Stats.RxBytes < 0 || st.Stats.TxBytes < 0 ||
+			st.Stats.RxPackets < 0 || st.Stats.TxPackets < 0 ||
+			st.Stats.SessionCountVSCode < 0 || st.Stats.SessionCountJetBrains < 0 ||
+			st.Stats.SessionCountReconnectingPTY < 0 || st.Stats.SessionCountSSH < 0 {
+			validations = append(validations, codersdk.ValidationError{Field: field + ".stats", Detail: "Counts must not be negative"})
+		}
+		protoStats, err := agentsdk.ProtoFromStats(st.Stats)
+		if err != nil {
+			validations = append(validations, codersdk.ValidationError{Field: field + ".stats.metrics", Detail: err.Error()})
+			continue
+		}
+		reports = append(reports, workspacestats.TimestampedStats{
+			CollectedAt: dbtime.Time(st.CollectedAt),
+			Stats:       protoStats,
+		})
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid stats.",
+			Validations: validations,
+		})
+		return
+	}
+	if len(reports) == 0 {
+		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.StatsResponse{
+			ReportInterval: api.AgentStatsRefreshInterval,
+			Dropped:        dropped,
+		})
+		return
+	}
+	slices.SortStableFunc(reports, func(a, b workspacestats.TimestampedStats) int {
+		return a.CollectedAt.Compare(b.CollectedAt)
+	})
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Stats are reported through the same implementation as UpdateStats on
+	// the Agent API.
+	cachedWs := &agentapi.CachedWorkspaceFields{}
+	cachedWs.UpdateValues(workspace)
+	statsAPI := &agentapi.StatsAPI{
+		AgentID:                   workspaceAgent.ID,
+		AgentName:                 workspaceAgent.Name,
+		Workspace:                 cachedWs,
+		Database:                  api.Database,
+		Log:                       api.Logger,
+		StatsReporter:             api.statsReporter,
+		StatsLeases:               api.statsLeases,
+		AgentStatsRefreshInterval: api.AgentStatsRefreshInterval,
+		Experiments:               api.Experiments,
+	}
+	err = statsAPI.ReportStatsBatch(ctx, reports)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to report stats.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.StatsResponse{
+		ReportInterval: api.AgentStatsRefreshInterval,
+		Dropped:        dropped,
+	})
+}
+
 // workspaceAgentLogs returns the logs associated with a workspace agent
 //
 // @Summary Get logs by workspace agent
@@ -790,6 +923,251 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// @Summary Get top processes for workspace agent
+// @ID get-top-processes-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param sort_by query string false "Resource to sort processes by" Enums(cpu,memory)
+// @Param limit query int false "Number of processes to return"
+// @Success 200 {object} codersdk.WorkspaceAgentTopProcessesResponse
+// @Router /api/v2/workspaceagents/{workspaceagent}/top-processes [get]
+func (api *API) workspaceAgentTopProcesses(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	sortBy := httpapi.ParseCustom(p, vals, codersdk.WorkspaceAgentProcessSortByCPU, "sort_by", httpapi.ParseEnum[codersdk.WorkspaceAgentProcessSortBy])
+	limit := p.PositiveInt32(vals, codersdk.WorkspaceAgentTopProcessesDefaultLimit, "limit")
+	p.ErrorExcessParams(vals)
+	if limit > codersdk.WorkspaceAgentTopProcessesMaxLimit {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "limit",
+			Detail: fmt.Sprintf("Query param %q must be at most %d.", "limit", codersdk.WorkspaceAgentTopProcessesMaxLimit),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	// If the agent is unreachable, the request will hang. Assume that if we
+	// don't get a response after 30s that the agent is unreachable.
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), waws.WorkspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, waws.WorkspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	processes, err := agentConn.TopProcesses(ctx, codersdk.WorkspaceAgentTopProcessesRequest{
+		SortBy: sortBy,
+		Limit:  int(limit),
+	})
+	if err != nil {
+		// Agents that predate this endpoint respond with a 404.
+		if cerr, ok := codersdk.AsError(err); ok && cerr.StatusCode() == http.StatusNotFound {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The workspace agent does not support reporting top processes.",
+				Detail:  "Update the workspace agent to a newer version to use this feature.",
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching top processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, processes)
+}
+
+// agentStatsTimeSeriesMinBucketSize is the smallest bucket size that can be
+// requested. Agents report stats every 30 seconds by default, so smaller
+// buckets would mostly be empty.
+const agentStatsTimeSeriesMinBucketSize = time.Minute
+
+// @Summary Get workspace agent stats time series
+// @ID get-workspace-agent-stats-time-series
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param start_time query string false "Start time, defaults to 24 hours before end time" format(date-time)
+// @Param end_time query string false "End time, defaults to now" format(date-time)
+// @Param bucket_size query string false "Bucket size as a duration, e.g. 5m"
+// @Param metrics query string false "Comma-separated list of metrics, defaults to all metrics"
+// @Success 200 {object} codersdk.WorkspaceAgentStatsTimeSeries
+// @Router /api/v2/workspaceagents/{workspaceagent}/stats/timeseries [get]
+func (api *API) workspaceAgentStatsTimeSeries(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	waws := httpmw.WorkspaceAgentAndWorkspaceParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	var (
+		startTime  = p.Time(vals, time.Time{}, "start_time", time.RFC3339)
+		endTime    = p.Time(vals, time.Time{}, "end_time", time.RFC3339)
+		bucketSize = p.Duration(vals, 0, "bucket_size")
+		metrics    = httpapi.ParseCustomList(p, vals, codersdk.AllWorkspaceAgentStatsMetrics, "metrics", httpapi.ParseEnum[codersdk.WorkspaceAgentStatsMetric])
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	if endTime.IsZero() {
+		endTime = dbtime.Now()
+	}
+	if startTime.IsZero() {
+		startTime = endTime.Add(-24 * time.Hour)
+	}
+	if !startTime.Before(endTime) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{Field: "start_time", Detail: "Start time must be before end time."},
+			},
+		})
+		return
+	}
+
+	timeRange := endTime.Sub(startTime)
+	if bucketSize == 0 {
+		// Use the smallest whole number of minutes that keeps the series
+		// within the bucket limit.
+		perBucket := (timeRange + codersdk.WorkspaceAgentStatsTimeSeriesMaxBuckets - 1) / codersdk.WorkspaceAgentStatsTimeSeriesMaxBuckets
+		bucketSize = max(
+			(perBucket+time.Minute-1)/time.Minute*time.Minute,
+			agentStatsTimeSeriesMinBucketSize,
+		)
+	}
+	if bucketSize < agentStatsTimeSeriesMinBucketSize || bucketSize%time.Second != 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{Field: "bucket_size", Detail: fmt.Sprintf("Bucket size must be a whole number of seconds and at least %s.", agentStatsTimeSeriesMinBucketSize)},
+			},
+		})
+		return
+	}
+	if buckets := (timeRange + bucketSize - 1) / bucketSize; buckets > codersdk.WorkspaceAgentStatsTimeSeriesMaxBuckets {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{Field: "bucket_size", Detail: fmt.Sprintf("The time range would be split into %d buckets, the maximum is %d. Use a larger bucket size or a shorter time range.", buckets, codersdk.WorkspaceAgentStatsTimeSeriesMaxBuckets)},
+			},
+		})
+		return
+	}
+
+	// Drop duplicate metrics while preserving the requested order.
+	seen := make(map[codersdk.WorkspaceAgentStatsMetric]struct{}, len(metrics))
+	metrics = slices.DeleteFunc(slices.Clone(metrics), func(m codersdk.WorkspaceAgentStatsMetric) bool {
+		if _, ok := seen[m]; ok {
+			return true
+		}
+		seen[m] = struct{}{}
+		return false
+	})
+
+	rows, err := api.Database.GetWorkspaceAgentStatsTimeSeries(ctx, database.GetWorkspaceAgentStatsTimeSeriesParams{
+		StartTime:     startTime,
+		EndTime:       endTime,
+		BucketSeconds: int32(bucketSize / time.Second), // #nosec G115 - Bounded by the bucket limit above.
+		AgentID:       waws.WorkspaceAgent.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent stats.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.WorkspaceAgentStatsTimeSeries{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		BucketSizeMS: bucketSize.Milliseconds(),
+		Metrics:      metrics,
+		Buckets:      make([]codersdk.WorkspaceAgentStatsBucket, 0, len(rows)),
+	}
+	for _, row := range rows {
+		values := make(map[codersdk.WorkspaceAgentStatsMetric]float64, len(metrics))
+		for _, m := range metrics {
+			values[m] = workspaceAgentStatsMetricValue(row, m)
+		}
+		resp.Buckets = append(resp.Buckets, codersdk.WorkspaceAgentStatsBucket{
+			StartTime: row.BucketStart,
+			Reports:   row.ReportCount,
+			Values:    values,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+func workspaceAgentStatsMetricValue(row database.GetWorkspaceAgentStatsTimeSeriesRow, metric codersdk.WorkspaceAgentStatsMetric) float64 {
+	switch metric {
+	case codersdk.WorkspaceAgentStatsMetricRxBytes:
+		return float64(row.RxBytes)
+	case codersdk.WorkspaceAgentStatsMetricTxBytes:
+		return float64(row.TxBytes)
+	case codersdk.WorkspaceAgentStatsMetricRxPackets:
+		return float64(row.RxPackets)
+	case codersdk.WorkspaceAgentStatsMetricTxPackets:
+		return float64(row.TxPackets)
+	case codersdk.WorkspaceAgentStatsMetricConnectionCount:
+		return row.ConnectionCount
+	case codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS:
+		return row.ConnectionMedianLatencyMS
+	case codersdk.WorkspaceAgentStatsMetricSessionCountVSCode:
+		return row.SessionCountVSCode
+	case codersdk.WorkspaceAgentStatsMetricSessionCountJetBrains:
+		return row.SessionCountJetBrains
+	case codersdk.WorkspaceAgentStatsMetricSessionCountReconnectingPTY:
+		return row.SessionCountReconnectingPTY
+	case codersdk.WorkspaceAgentStatsMetricSessionCountSSH:
+		return row.SessionCountSSH
+	default:
+		return 0
+	}
+}
+
 // @Summary Watch workspace agent for container updates.
 // @ID watch-workspace-agent-for-container-updates
 // @Security CoderSessionToken
@@ -1356,6 +1734,8 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 		return
 	}
 
+	api.recordClientNetwork(ctx, r, waws)
+
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
 	api.WebsocketWaitMutex.Unlock()
@@ -1388,6 +1768,68 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	}
 }
 
+// recordClientNetwork records the coarse network location of a client
+// connecting to a workspace agent, as reported by the request headers of a
+// trusted edge proxy. Nothing is recorded unless the headers are configured, or
+// when a workspace proxy connects on behalf of a client.
+func (api *API) recordClientNetwork(ctx context.Context, r *http.Request, waws database.GetWorkspaceAgentAndWorkspaceByIDRow) {
+	cfg := api.DeploymentValues.StatsCollection
+	if !cfg.UsageStats.Enable.Value() {
+		return
+	}
+	apiKey, ok := httpmw.APIKeyOptional(r)
+	if !ok {
+		return
+	}
+	region, asn := clientNetworkFromHeaders(r.Header, cfg.ClientGeography.RegionHeader.Value(), cfg.ClientGeography.ASNHeader.Value())
+	if region == "" && asn == 0 {
+		return
+	}
+
+	//nolint:gocritic // Client network stats are system data recorded on behalf of the user.
+	err := api.Database.InsertWorkspaceClientNetworkStat(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceClientNetworkStatParams{
+		ID:           uuid.New(),
+		CreatedAt:    dbtime.Now(),
+		UserID:       apiKey.UserID,
+		WorkspaceID:  waws.WorkspaceTable.ID,
+		AgentID:      waws.WorkspaceAgent.ID,
+		ClientRegion: region,
+		ClientAsn:    asn,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to record client network stat", slog.F("agent_id", waws.WorkspaceAgent.ID), slog.Error(err))
+	}
+}
+
+// maxClientRegionLength bounds the client region taken from a request header,
+// region codes are much shorter.
+const maxClientRegionLength = 32
+
+// clientNetworkFromHeaders returns the region and autonomous system number of
+// a client from the given request headers. Values that don't look like a
+// region or an ASN are ignored, and the region is upper-cased so that it
+// aggregates regardless of the proxy.
+func clientNetworkFromHeaders(h http.Header, regionHeader, asnHeader string) (region string, asn int64) {
+	if regionHeader != "" {
+		region = strings.ToUpper(strings.TrimSpace(h.Get(regionHeader)))
+		valid := len(region) <= maxClientRegionLength && !strings.ContainsFunc(region, func(r rune) bool {
+			return (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_'
+		})
+		if !valid {
+			region = ""
+		}
+	}
+	if asnHeader != "" {
+		v := strings.TrimSpace(h.Get(asnHeader))
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "AS"), "as")
+		// ASNs are 32-bit unsigned integers, 0 is reserved.
+		if n, err := strconv.ParseUint(v, 10, 32); err == nil {
+			asn = int64(n)
+		}
+	}
+	return region, asn
+}
+
 // handleResumeToken accepts a resume_token query parameter to use the same peer ID
 func (api *API) handleResumeToken(ctx context.Context, rw http.ResponseWriter, r *http.Request) (peerID uuid.UUID, err error) {
 	peerID = uuid.New()
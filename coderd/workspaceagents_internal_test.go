@@ -979,3 +979,51 @@ func TestWatchAgentContainers(t *testing.T) {
 		}
 	})
 }
+
+func TestClientNetworkFromHeaders(t *testing.T) {
+	t.Parallel()
+
+	const regionHeader, asnHeader = "Viewer-Region", "Viewer-ASN"
+	for _, tc := range []struct {
+		name       string
+		headers    map[string]string
+		wantRegion string
+		wantASN    int64
+	}{
+		{
+			name:    "NotConfigured",
+			headers: map[string]string{"CF-IPCountry": "DE"},
+		},
+		{
+			name:       "Region",
+			headers:    map[string]string{regionHeader: " de "},
+			wantRegion: "DE",
+		},
+		{
+			name:       "RegionAndASN",
+			headers:    map[string]string{regionHeader: "US-CA", asnHeader: "AS7018"},
+			wantRegion: "US-CA",
+			wantASN:    7018,
+		},
+		{
+			name:    "InvalidRegion",
+			headers: map[string]string{regionHeader: "<script>"},
+		},
+		{
+			name:    "InvalidASN",
+			headers: map[string]string{asnHeader: "99999999999"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.Header{}
+			for k, v := range tc.headers {
+				h.Set(k, v)
+			}
+			region, asn := clientNetworkFromHeaders(h, regionHeader, asnHeader)
+			require.Equal(t, tc.wantRegion, region)
+			require.Equal(t, tc.wantASN, asn)
+		})
+	}
+}
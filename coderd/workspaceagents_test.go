@@ -54,6 +54,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/workspacestats/workspacestatstest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
@@ -564,6 +565,84 @@ func TestWorkspaceAgentAppStatus(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentPostStats(t *testing.T) {
+	t.Parallel()
+
+	batcher := &workspacestatstest.StatsBatcher{}
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		StatsBatcher: batcher,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	agentClient := agentsdk.New(client.URL, agentsdk.WithFixedToken(r.AgentToken))
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		now := dbtime.Now()
+		res, err := agentClient.PostStats(ctx, agentsdk.PostStatsRequest{
+			Stats: []agentsdk.TimestampedStats{
+				{CollectedAt: now.Add(-time.Minute), Stats: agentsdk.Stats{TxBytes: 2}},
+				{CollectedAt: now.Add(-10 * time.Minute), Stats: agentsdk.Stats{TxBytes: 1}},
+				{CollectedAt: now.Add(-2 * time.Hour), Stats: agentsdk.Stats{TxBytes: 3}},
+			},
+		})
+		require.NoError(t, err)
+		require.NotZero(t, res.ReportInterval)
+		require.Equal(t, 1, res.Dropped)
+
+		// The recent ones are stored with the time they were collected, in
+		// order.
+		batcher.Mu.Lock()
+		defer batcher.Mu.Unlock()
+		require.EqualValues(t, 2, batcher.Called)
+		require.Equal(t, r.Agents[0].ID, batcher.LastAgentID)
+		require.Equal(t, r.Workspace.ID, batcher.LastWorkspaceID)
+		require.WithinDuration(t, now.Add(-time.Minute), batcher.LastTime, time.Millisecond)
+		require.EqualValues(t, 2, batcher.LastStats.TxBytes)
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		res, err := agentClient.PostStats(ctx, agentsdk.PostStatsRequest{
+			Stats: []agentsdk.TimestampedStats{
+				{CollectedAt: dbtime.Now().Add(-2 * time.Hour)},
+				{CollectedAt: dbtime.Now().Add(-3 * time.Hour)},
+			},
+		})
+		require.NoError(t, err)
+		require.NotZero(t, res.ReportInterval)
+		require.Equal(t, 2, res.Dropped)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := agentClient.PostStats(ctx, agentsdk.PostStatsRequest{
+			Stats: []agentsdk.TimestampedStats{
+				{Stats: agentsdk.Stats{TxBytes: 1}},
+				{CollectedAt: dbtime.Now().Add(-2 * time.Hour)},
+				{CollectedAt: dbtime.Now(), Stats: agentsdk.Stats{RxBytes: -1}},
+			},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Len(t, sdkErr.Validations, 2)
+
+		_, err = agentClient.PostStats(ctx, agentsdk.PostStatsRequest{})
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+}
+
 func TestWorkspaceAgentAppStatus_ActivityBump(t *testing.T) {
 	t.Parallel()
 
@@ -1356,6 +1435,151 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentTopProcesses(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.NewWorkspaceAgentWaiter(t, client, r.Workspace.ID).Wait()
+	agentID := resources[0].Agents[0].ID
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.WorkspaceAgentTopProcesses(ctx, agentID, codersdk.WorkspaceAgentTopProcessesRequest{
+			SortBy: codersdk.WorkspaceAgentProcessSortByMemory,
+			Limit:  5,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentProcessSortByMemory, res.SortBy)
+		require.NotEmpty(t, res.Processes)
+		require.LessOrEqual(t, len(res.Processes), 5)
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceAgentTopProcesses(ctx, agentID, codersdk.WorkspaceAgentTopProcessesRequest{
+			Limit: codersdk.WorkspaceAgentTopProcessesMaxLimit + 1,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+}
+
+func TestWorkspaceAgentStatsTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	agentID := r.Agents[0].ID
+
+	endTime := dbtime.Now().Truncate(time.Hour)
+	startTime := endTime.Add(-time.Hour)
+	for _, stat := range []database.WorkspaceAgentStat{
+		// Two reports in the first bucket.
+		{CreatedAt: startTime.Add(time.Minute), RxBytes: 100, ConnectionCount: 1, SessionCountSSH: 1},
+		{CreatedAt: startTime.Add(2 * time.Minute), RxBytes: 50, ConnectionCount: 3, SessionCountSSH: 0},
+		// One report in the last bucket.
+		{CreatedAt: endTime.Add(-time.Minute), RxBytes: 10, ConnectionMedianLatencyMS: 25},
+		// Reports outside of the range, or for another agent, are ignored.
+		{CreatedAt: endTime.Add(time.Minute), RxBytes: 1000},
+		{CreatedAt: startTime.Add(time.Minute), RxBytes: 1000, AgentID: uuid.New()},
+	} {
+		if stat.AgentID == uuid.Nil {
+			stat.AgentID = agentID
+		}
+		stat.WorkspaceID = r.Workspace.ID
+		stat.UserID = user.UserID
+		dbgen.WorkspaceAgentStat(t, db, stat)
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		series, err := client.WorkspaceAgentStatsTimeSeries(ctx, agentID, codersdk.WorkspaceAgentStatsTimeSeriesRequest{
+			StartTime:  startTime,
+			EndTime:    endTime,
+			BucketSize: 15 * time.Minute,
+			Metrics: []codersdk.WorkspaceAgentStatsMetric{
+				codersdk.WorkspaceAgentStatsMetricRxBytes,
+				codersdk.WorkspaceAgentStatsMetricConnectionCount,
+				codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, (15 * time.Minute).Milliseconds(), series.BucketSizeMS)
+		require.Len(t, series.Buckets, 4)
+
+		first := series.Buckets[0]
+		require.True(t, first.StartTime.Equal(startTime))
+		require.EqualValues(t, 2, first.Reports)
+		require.Equal(t, map[codersdk.WorkspaceAgentStatsMetric]float64{
+			codersdk.WorkspaceAgentStatsMetricRxBytes:                   150,
+			codersdk.WorkspaceAgentStatsMetricConnectionCount:           2,
+			codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS: 0,
+		}, first.Values)
+
+		// Empty buckets are included with zero values.
+		require.Zero(t, series.Buckets[1].Reports)
+		require.Zero(t, series.Buckets[1].Values[codersdk.WorkspaceAgentStatsMetricRxBytes])
+
+		last := series.Buckets[3]
+		require.EqualValues(t, 1, last.Reports)
+		require.EqualValues(t, 10, last.Values[codersdk.WorkspaceAgentStatsMetricRxBytes])
+		require.EqualValues(t, 25, last.Values[codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS])
+	})
+
+	t.Run("DefaultMetrics", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		series, err := client.WorkspaceAgentStatsTimeSeries(ctx, agentID, codersdk.WorkspaceAgentStatsTimeSeriesRequest{})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.AllWorkspaceAgentStatsMetrics, series.Metrics)
+		require.LessOrEqual(t, len(series.Buckets), codersdk.WorkspaceAgentStatsTimeSeriesMaxBuckets)
+	})
+
+	t.Run("TooManyBuckets", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceAgentStatsTimeSeries(ctx, agentID, codersdk.WorkspaceAgentStatsTimeSeriesRequest{
+			StartTime:  endTime.Add(-30 * 24 * time.Hour),
+			EndTime:    endTime,
+			BucketSize: time.Minute,
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("InvalidMetric", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceAgentStatsTimeSeries(ctx, agentID, codersdk.WorkspaceAgentStatsTimeSeriesRequest{
+			Metrics: []codersdk.WorkspaceAgentStatsMetric{"cpu"},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+}
+
 func TestWorkspaceAgentContainers(t *testing.T) {
 	t.Parallel()
 
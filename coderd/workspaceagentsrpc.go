@@ -155,12 +155,14 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		Clock:                             api.Clock,
 		Database:                          api.Database,
 		NotificationsEnqueuer:             api.NotificationsEnqueuer,
+		WebhookEnqueuer:                   api.WebhookEnqueuer,
 		Pubsub:                            api.Pubsub,
 		ConnectionLogger:                  &api.ConnectionLogger,
 		DerpMapFn:                         api.DERPMap,
 		TailnetCoordinator:                &api.TailnetCoordinator,
 		AppearanceFetcher:                 &api.AppearanceFetcher,
 		StatsReporter:                     api.statsReporter,
+		StatsLeases:                       api.statsLeases,
 		MetadataBatcher:                   api.metadataBatcher,
 		PublishWorkspaceUpdateFn:          api.publishWorkspaceUpdate,
 		PublishWorkspaceAgentLogsUpdateFn: api.publishWorkspaceAgentLogsUpdate,
@@ -0,0 +1,145 @@
+package coderd
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	workspaceRightsizingDefaultDays = 14
+	// Resource samples are purged after 30 days.
+	workspaceRightsizingMaxDays = 30
+	// Samples are taken by the rollup every 5 minutes, so recommendations are
+	// based on at least an hour of usage.
+	workspaceRightsizingMinSamples = 12
+	// The recommended size leaves room for the usage to grow past the 95th
+	// percentile.
+	workspaceRightsizingHeadroom = 1.3
+)
+
+// Recommended sizes are rounded up to these steps, which are also the
+// smallest sizes recommended.
+var workspaceRightsizingSteps = map[string]float64{
+	string(codersdk.WorkspaceRightsizingResourceCPU):    0.5,
+	string(codersdk.WorkspaceRightsizingResourceMemory): 512 << 20,
+}
+
+// @Summary Get workspace resource right-sizing recommendations
+// @ID get-workspace-resource-right-sizing-recommendations
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param days query int false "Number of days of usage to base the recommendations on, defaults to 14"
+// @Success 200 {object} codersdk.WorkspaceRightsizing
+// @Router /api/v2/workspaces/{workspace}/rightsizing [get]
+func (api *API) workspaceRightsizing(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	days := p.PositiveInt32(vals, workspaceRightsizingDefaultDays, "days")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if days == 0 || days > workspaceRightsizingMaxDays {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Query parameter \"days\" must be between 1 and %d.", workspaceRightsizingMaxDays),
+		})
+		return
+	}
+
+	endTime := dbtime.Now()
+	startTime := endTime.AddDate(0, 0, -int(days))
+
+	usage, err := api.Database.GetWorkspaceAgentResourceUsage(ctx, database.GetWorkspaceAgentResourceUsageParams{
+		WorkspaceID: workspace.ID,
+		StartTime:   startTime,
+		EndTime:     endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent resource usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	cost, err := api.Database.GetWorkspaceCost(ctx, database.GetWorkspaceCostParams{
+		WorkspaceID: workspace.ID,
+		StartTime:   startTime,
+		EndTime:     endTime,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace cost.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, recommendWorkspaceResources(startTime, endTime, usage, cost))
+}
+
+// recommendWorkspaceResources recommends a size for every resource of an agent
+// with enough samples. The potential savings assume the cost of the workspace
+// is split evenly between CPU and memory, and scale each half by how much the
+// recommendations would change the total of the resource across all agents.
+func recommendWorkspaceResources(startTime, endTime time.Time, usage []database.GetWorkspaceAgentResourceUsageRow, cost database.GetWorkspaceCostRow) codersdk.WorkspaceRightsizing {
+	rightsizing := codersdk.WorkspaceRightsizing{
+		StartTime:       startTime,
+		EndTime:         endTime,
+		RunningSeconds:  cost.RunningSeconds,
+		Cost:            cost.Cost,
+		Recommendations: []codersdk.WorkspaceRightsizingRecommendation{},
+	}
+
+	var (
+		current     = map[string]float64{}
+		recommended = map[string]float64{}
+	)
+	for _, row := range usage {
+		step, ok := workspaceRightsizingSteps[row.Resource]
+		if !ok || row.Samples < workspaceRightsizingMinSamples {
+			continue
+		}
+		size := math.Max(math.Ceil(row.UsedP95*workspaceRightsizingHeadroom/step)*step, step)
+		current[row.Resource] += row.Total
+		recommended[row.Resource] += size
+		rightsizing.Recommendations = append(rightsizing.Recommendations, codersdk.WorkspaceRightsizingRecommendation{
+			AgentName:   row.AgentName,
+			Resource:    codersdk.WorkspaceRightsizingResource(row.Resource),
+			Samples:     row.Samples,
+			Current:     row.Total,
+			UsedP95:     row.UsedP95,
+			UsedMax:     row.UsedMax,
+			Recommended: size,
+		})
+	}
+
+	var savings float64
+	for resource, total := range current {
+		if total > 0 {
+			savings += cost.Cost / float64(len(workspaceRightsizingSteps)) * (1 - recommended[resource]/total)
+		}
+	}
+	// Undersized agents make the workspace more expensive, which is not a
+	// saving.
+	rightsizing.PotentialSavings = math.Max(savings, 0)
+	return rightsizing
+}
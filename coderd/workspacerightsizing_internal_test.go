@@ -0,0 +1,70 @@
+package coderd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func Test_recommendWorkspaceResources(t *testing.T) {
+	t.Parallel()
+
+	endTime := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	startTime := endTime.AddDate(0, 0, -14)
+	cost := database.GetWorkspaceCostRow{RunningSeconds: 14 * 8 * 3600, Cost: 100}
+
+	t.Run("Oversized", func(t *testing.T) {
+		t.Parallel()
+
+		rightsizing := recommendWorkspaceResources(startTime, endTime, []database.GetWorkspaceAgentResourceUsageRow{
+			{AgentName: "dev", Resource: "cpu", Samples: 100, UsedP95: 1.4, UsedMax: 3, Total: 4},
+			{AgentName: "dev", Resource: "memory", Samples: 100, UsedP95: 3 << 30, UsedMax: 6 << 30, Total: 16 << 30},
+		}, cost)
+		require.Equal(t, startTime, rightsizing.StartTime)
+		require.Equal(t, endTime, rightsizing.EndTime)
+		require.EqualValues(t, cost.RunningSeconds, rightsizing.RunningSeconds)
+		require.Len(t, rightsizing.Recommendations, 2)
+		// 1.4 cores with 30% headroom, rounded up to half a core.
+		require.Equal(t, 2.0, rightsizing.Recommendations[0].Recommended)
+		// 3 GiB with 30% headroom, rounded up to 512 MiB.
+		require.Equal(t, float64(4<<30), rightsizing.Recommendations[1].Recommended)
+		// Half the cost scaled by 1-2/4, half by 1-4/16.
+		require.InDelta(t, 50*0.5+50*0.75, rightsizing.PotentialSavings, 0.0001)
+	})
+
+	t.Run("Undersized", func(t *testing.T) {
+		t.Parallel()
+
+		rightsizing := recommendWorkspaceResources(startTime, endTime, []database.GetWorkspaceAgentResourceUsageRow{
+			{AgentName: "dev", Resource: "cpu", Samples: 100, UsedP95: 2, UsedMax: 2, Total: 2},
+		}, cost)
+		require.Len(t, rightsizing.Recommendations, 1)
+		require.Equal(t, 3.0, rightsizing.Recommendations[0].Recommended)
+		require.Zero(t, rightsizing.PotentialSavings)
+	})
+
+	t.Run("Idle", func(t *testing.T) {
+		t.Parallel()
+
+		rightsizing := recommendWorkspaceResources(startTime, endTime, []database.GetWorkspaceAgentResourceUsageRow{
+			{AgentName: "dev", Resource: "cpu", Samples: 100, UsedP95: 0, UsedMax: 0.1, Total: 4},
+		}, cost)
+		require.Len(t, rightsizing.Recommendations, 1)
+		// Never recommend less than the smallest step.
+		require.Equal(t, 0.5, rightsizing.Recommendations[0].Recommended)
+	})
+
+	t.Run("TooFewSamples", func(t *testing.T) {
+		t.Parallel()
+
+		rightsizing := recommendWorkspaceResources(startTime, endTime, []database.GetWorkspaceAgentResourceUsageRow{
+			{AgentName: "dev", Resource: "cpu", Samples: 3, UsedP95: 0.2, UsedMax: 0.2, Total: 4},
+		}, cost)
+		require.Equal(t, []codersdk.WorkspaceRightsizingRecommendation{}, rightsizing.Recommendations)
+		require.Zero(t, rightsizing.PotentialSavings)
+	})
+}
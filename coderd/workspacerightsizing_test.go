@@ -0,0 +1,84 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceRightsizing(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   pubsub,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).WithAgent().Do()
+	agent := r.Agents[0]
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	for _, md := range []database.InsertWorkspaceAgentMetadataParams{
+		{Key: "cpu", Script: "coder stat cpu"},
+		{Key: "mem", Script: "coder stat mem"},
+	} {
+		md.WorkspaceAgentID = agent.ID
+		md.DisplayName = md.Key
+		md.Timeout = 1
+		md.Interval = 10
+		require.NoError(t, db.InsertWorkspaceAgentMetadata(ctx, md))
+	}
+	// Sample an hour of usage, as the rollup would every 5 minutes.
+	now := dbtime.Now()
+	for i := range 12 {
+		collectedAt := now.Add(-time.Duration(i) * 5 * time.Minute)
+		err := db.BatchUpdateWorkspaceAgentMetadata(ctx, database.BatchUpdateWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: []uuid.UUID{agent.ID, agent.ID},
+			Key:              []string{"cpu", "mem"},
+			Value:            []string{"1/4 cores (25%)", "1/8 GiB (13%)"},
+			Error:            []string{"", ""},
+			CollectedAt:      []time.Time{collectedAt, collectedAt},
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.InsertWorkspaceAgentResourceSamples(ctx))
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		rightsizing, err := client.WorkspaceRightsizing(ctx, r.Workspace.ID, codersdk.WorkspaceRightsizingRequest{})
+		require.NoError(t, err)
+		require.WithinDuration(t, rightsizing.EndTime.AddDate(0, 0, -14), rightsizing.StartTime, time.Second)
+		require.Equal(t, []codersdk.WorkspaceRightsizingRecommendation{
+			{AgentName: "dev", Resource: codersdk.WorkspaceRightsizingResourceCPU, Samples: 12, Current: 4, UsedP95: 1, UsedMax: 1, Recommended: 1.5},
+			{AgentName: "dev", Resource: codersdk.WorkspaceRightsizingResourceMemory, Samples: 12, Current: 8 << 30, UsedP95: 1 << 30, UsedMax: 1 << 30, Recommended: 1.5 * (1 << 30)},
+		}, rightsizing.Recommendations)
+	})
+
+	t.Run("InvalidDays", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.WorkspaceRightsizing(ctx, r.Workspace.ID, codersdk.WorkspaceRightsizingRequest{Days: 31})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,343 @@
+// Package anomaly flags workspaces whose hourly activity is far above their
+// usual activity, e.g. a workspace suddenly sending a hundred times its usual
+// egress.
+package anomaly
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+const (
+	// window is the period of activity that is compared to the baseline of a
+	// workspace.
+	window = time.Hour
+	// delay leaves time for the stats of the last window to be flushed to the
+	// database before it is analyzed.
+	delay = 5 * time.Minute
+	// warmupSamples is the number of active hours a baseline needs before the
+	// workspace can be flagged.
+	warmupSamples = 24
+	// maxSamples bounds the weight of the history in a baseline, so that it
+	// follows lasting changes in the usage of a workspace within a week.
+	maxSamples = 7 * 24
+	// flagCooldown is how long a flagged metric of a workspace is not flagged
+	// again, so that owners are not notified every hour.
+	flagCooldown = 24 * time.Hour
+)
+
+// threshold is how far a value must exceed the baseline to be anomalous.
+type threshold struct {
+	// factor is the minimum ratio of the value to the mean.
+	factor float64
+	// zScore is the minimum number of standard deviations above the mean.
+	zScore float64
+}
+
+var thresholds = map[codersdk.AnomalyDetectionSensitivity]threshold{
+	codersdk.AnomalyDetectionSensitivityLow:    {factor: 100, zScore: 6},
+	codersdk.AnomalyDetectionSensitivityMedium: {factor: 20, zScore: 4},
+	codersdk.AnomalyDetectionSensitivityHigh:   {factor: 5, zScore: 3},
+}
+
+type metric struct {
+	// name is stored in workspace_stat_baselines.
+	name string
+	// label describes the metric in notifications.
+	label string
+	// minimum is the smallest value that is flagged, so that an almost idle
+	// workspace becoming slightly less idle is not.
+	minimum float64
+	value   func(database.GetWorkspaceActivityStatsRow) float64
+	format  func(float64) string
+}
+
+func formatBytes(v float64) string {
+	return humanize.Bytes(uint64(math.Max(v, 0)))
+}
+
+func formatCount(v float64) string {
+	return humanize.Ftoa(math.Round(v*10) / 10)
+}
+
+var metrics = []metric{
+	{
+		name:    "tx_bytes",
+		label:   "egress",
+		minimum: 100 << 20,
+		value:   func(r database.GetWorkspaceActivityStatsRow) float64 { return float64(r.TxBytes) },
+		format:  formatBytes,
+	},
+	{
+		name:    "rx_bytes",
+		label:   "ingress",
+		minimum: 100 << 20,
+		value:   func(r database.GetWorkspaceActivityStatsRow) float64 { return float64(r.RxBytes) },
+		format:  formatBytes,
+	},
+	{
+		name:    "sessions",
+		label:   "concurrent sessions",
+		minimum: 10,
+		value:   func(r database.GetWorkspaceActivityStatsRow) float64 { return float64(r.Sessions) },
+		format:  formatCount,
+	},
+}
+
+// New starts a detector that compares the activity of every workspace to its
+// baseline once an hour. Anomalies are recorded in the audit log and reported
+// to the owners of the deployment. It is the caller's responsibility to call
+// Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, auditor *atomic.Pointer[audit.Auditor], enqueuer notifications.Enqueuer, clk quartz.Clock, sensitivity codersdk.AnomalyDetectionSensitivity) (io.Closer, error) {
+	th, ok := thresholds[sensitivity]
+	if !ok {
+		return nil, xerrors.Errorf("unsupported anomaly detection sensitivity %q", sensitivity)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	d := &detector{
+		cancel: cancel,
+		closed: make(chan struct{}),
+	}
+
+	//nolint:gocritic // The system analyzes workspace stats without direct user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	doTick := func(windowEnd time.Time) {
+		err := db.InTx(func(tx database.Store) error {
+			// Only one replica analyzes a window, the others skip it.
+			ok, err := tx.TryAcquireLock(ctx, database.LockIDAnomalyDetector)
+			if err != nil {
+				return xerrors.Errorf("acquire anomaly detector lock: %w", err)
+			}
+			if !ok {
+				logger.Debug(ctx, "unable to acquire lock for detecting anomalies, skipping")
+				return nil
+			}
+			return detect(ctx, logger, tx, *auditor.Load(), enqueuer, windowEnd, th)
+		}, database.DefaultTXOptions().WithID("anomaly_detector"))
+		if err != nil && ctx.Err() == nil {
+			logger.Error(ctx, "failed to detect anomalies", slog.Error(err))
+		}
+	}
+
+	go func() {
+		defer close(d.closed)
+		for {
+			// Windows are aligned with the hour, so that every replica
+			// analyzes the same ones.
+			now := clk.Now()
+			windowEnd := now.Add(-delay).Truncate(window).Add(window)
+			timer := clk.NewTimer(windowEnd.Add(delay).Sub(now), "anomaly", "detector")
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				logger.Debug(ctx, "closing anomaly detector")
+				return
+			case <-timer.C:
+				doTick(windowEnd)
+			}
+		}
+	}()
+	return d, nil
+}
+
+type detector struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (d *detector) Close() error {
+	d.cancel()
+	<-d.closed
+	return nil
+}
+
+type anomaly struct {
+	workspaceID uuid.UUID
+	metric      metric
+	value       float64
+	mean        float64
+}
+
+// detect compares the activity of the workspaces in the window ending at
+// windowEnd to their baselines, folds it into the baselines and reports the
+// anomalies.
+func detect(ctx context.Context, logger slog.Logger, db database.Store, auditor audit.Auditor, enqueuer notifications.Enqueuer, windowEnd time.Time, th threshold) error {
+	windowEnd = dbtime.Time(windowEnd).UTC()
+	rows, err := db.GetWorkspaceActivityStats(ctx, database.GetWorkspaceActivityStatsParams{
+		StartTime: windowEnd.Add(-window),
+		EndTime:   windowEnd,
+	})
+	if err != nil {
+		return xerrors.Errorf("get workspace activity stats: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		workspaceIDs = append(workspaceIDs, row.WorkspaceID)
+	}
+	baselineRows, err := db.GetWorkspaceStatBaselines(ctx, workspaceIDs)
+	if err != nil {
+		return xerrors.Errorf("get workspace stat baselines: %w", err)
+	}
+	baselines := make(map[uuid.UUID]map[string]database.WorkspaceStatBaseline, len(baselineRows))
+	for _, b := range baselineRows {
+		if baselines[b.WorkspaceID] == nil {
+			baselines[b.WorkspaceID] = make(map[string]database.WorkspaceStatBaseline)
+		}
+		baselines[b.WorkspaceID][b.Metric] = b
+	}
+
+	var anomalies []anomaly
+	for _, row := range rows {
+		for _, m := range metrics {
+			b, ok := baselines[row.WorkspaceID][m.name]
+			if ok && !b.UpdatedAt.Before(windowEnd) {
+				// The window is already folded into the baseline.
+				continue
+			}
+			value := m.value(row)
+			flagged := isAnomalous(b, value, m.minimum, th) &&
+				(!b.FlaggedAt.Valid || windowEnd.Sub(b.FlaggedAt.Time) >= flagCooldown)
+			if flagged {
+				anomalies = append(anomalies, anomaly{
+					workspaceID: row.WorkspaceID,
+					metric:      m,
+					value:       value,
+					mean:        b.Mean,
+				})
+				b.FlaggedAt = sql.NullTime{Time: windowEnd, Valid: true}
+			}
+
+			samples, mean, variance := fold(b, value)
+			err = db.UpsertWorkspaceStatBaseline(ctx, database.UpsertWorkspaceStatBaselineParams{
+				WorkspaceID: row.WorkspaceID,
+				Metric:      m.name,
+				Samples:     samples,
+				Mean:        mean,
+				Variance:    variance,
+				UpdatedAt:   windowEnd,
+				FlaggedAt:   b.FlaggedAt,
+			})
+			if err != nil {
+				return xerrors.Errorf("upsert workspace stat baseline: %w", err)
+			}
+		}
+	}
+	if len(anomalies) == 0 {
+		return nil
+	}
+
+	owners, err := db.GetUsers(ctx, database.GetUsersParams{
+		RbacRole: []string{codersdk.RoleOwner},
+	})
+	if err != nil {
+		return xerrors.Errorf("get owners: %w", err)
+	}
+	for _, a := range anomalies {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		report(ctx, logger, db, auditor, enqueuer, owners, a, windowEnd)
+	}
+	return nil
+}
+
+// isAnomalous reports whether value is far enough above the baseline. Values
+// are not flagged until the baseline has seen enough active hours.
+func isAnomalous(b database.WorkspaceStatBaseline, value, minimum float64, th threshold) bool {
+	if b.Samples < warmupSamples || value < minimum {
+		return false
+	}
+	if value < b.Mean*th.factor {
+		return false
+	}
+	return value > b.Mean+th.zScore*math.Sqrt(b.Variance)
+}
+
+// fold adds value to the exponentially weighted moving mean and variance of
+// the baseline. Until maxSamples is reached, they are exactly the mean and
+// variance of all values.
+func fold(b database.WorkspaceStatBaseline, value float64) (samples int32, mean float64, variance float64) {
+	samples = b.Samples + 1
+	alpha := 1 / float64(min(samples, maxSamples))
+	diff := value - b.Mean
+	incr := alpha * diff
+	mean = b.Mean + incr
+	variance = (1 - alpha) * (b.Variance + diff*incr)
+	return samples, mean, variance
+}
+
+func report(ctx context.Context, logger slog.Logger, db database.Store, auditor audit.Auditor, enqueuer notifications.Enqueuer, owners []database.GetUsersRow, a anomaly, windowEnd time.Time) {
+	logger = logger.With(slog.F("workspace_id", a.workspaceID), slog.F("metric", a.metric.name))
+	logger.Info(ctx, "workspace has anomalous activity", slog.F("value", a.value), slog.F("mean", a.mean))
+
+	ws, err := db.GetWorkspaceByID(ctx, a.workspaceID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch workspace with anomalous activity", slog.Error(err))
+		return
+	}
+
+	fields := audit.BackgroundTaskFields(audit.BackgroundSubsystemAnomalyDetection)
+	fields["anomaly_metric"] = a.metric.name
+	fields["anomaly_value"] = strconv.FormatFloat(a.value, 'f', -1, 64)
+	fields["anomaly_mean"] = strconv.FormatFloat(a.mean, 'f', -1, 64)
+	fields["anomaly_window_end"] = windowEnd.Format(time.RFC3339)
+	rawFields, err := json.Marshal(fields)
+	if err != nil {
+		logger.Error(ctx, "marshal additional fields for anomaly audit", slog.Error(err))
+		rawFields = []byte("{}")
+	}
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceTable]{
+		Audit:            auditor,
+		Log:              logger,
+		UserID:           ws.OwnerID,
+		OrganizationID:   ws.OrganizationID,
+		Action:           database.AuditActionWrite,
+		Old:              ws.WorkspaceTable(),
+		New:              ws.WorkspaceTable(),
+		Status:           http.StatusOK,
+		AdditionalFields: rawFields,
+	})
+
+	labels := map[string]string{
+		"name":     ws.Name,
+		"owner":    ws.OwnerUsername,
+		"metric":   a.metric.label,
+		"observed": a.metric.format(a.value),
+		"usual":    a.metric.format(a.mean),
+	}
+	for _, owner := range owners {
+		if _, err := enqueuer.Enqueue(ctx, owner.ID, notifications.TemplateWorkspaceAnomalousActivity,
+			labels,
+			"anomaly_detector",
+			ws.ID, ws.OwnerID, ws.TemplateID, ws.OrganizationID,
+		); err != nil {
+			logger.Warn(ctx, "failed to notify about anomalous workspace activity", slog.F("user_id", owner.ID), slog.Error(err))
+		}
+	}
+}
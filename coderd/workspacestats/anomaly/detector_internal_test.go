@@ -0,0 +1,161 @@
+package anomaly
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	ctx := dbauthz.AsSystemRestricted(context.Background())
+	logger := slogtest.Make(t, nil)
+	db, _ := dbtestutil.NewDB(t)
+	auditor := audit.NewMock()
+	notifEnq := &notificationstest.FakeEnqueuer{}
+	th := thresholds[codersdk.AnomalyDetectionSensitivityMedium]
+	windowEnd := dbtime.Now().Truncate(window)
+
+	// Given: a deployment owner, and a workspace that usually sends about
+	// 10 MB an hour.
+	org := dbgen.Organization(t, db, database.Organization{})
+	admin := dbgen.User(t, db, database.User{Username: "admin", RBACRoles: []string{rbac.RoleOwner().Name}})
+	member := dbgen.User(t, db, database.User{Username: "member"})
+	_ = dbgen.OrganizationMember(t, db, database.OrganizationMember{UserID: member.ID, OrganizationID: org.ID})
+	tpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: admin.ID})
+	ws := dbgen.Workspace(t, db, database.WorkspaceTable{Name: "busy", TemplateID: tpl.ID, OwnerID: member.ID, OrganizationID: org.ID})
+	for _, m := range metrics {
+		err := db.UpsertWorkspaceStatBaseline(ctx, database.UpsertWorkspaceStatBaselineParams{
+			WorkspaceID: ws.ID,
+			Metric:      m.name,
+			Samples:     48,
+			Mean:        10 << 20,
+			Variance:    1 << 40,
+			UpdatedAt:   windowEnd.Add(-window),
+		})
+		require.NoError(t, err)
+	}
+
+	// Given: it sent 2 GB in the last hour.
+	insertStat := func(end time.Time, txBytes int64) {
+		_ = dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+			CreatedAt:   end.Add(-30 * time.Minute),
+			WorkspaceID: ws.ID,
+			TemplateID:  tpl.ID,
+			UserID:      member.ID,
+			TxBytes:     txBytes,
+			RxBytes:     1 << 20,
+		})
+	}
+	insertStat(windowEnd, 2<<30)
+
+	// When
+	err := detect(ctx, logger, db, auditor, notifEnq, windowEnd, th)
+	require.NoError(t, err)
+
+	// Then: only the egress is flagged, and the deployment owner is notified.
+	sent := notifEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceAnomalousActivity))
+	require.Len(t, sent, 1)
+	require.Equal(t, admin.ID, sent[0].UserID)
+	require.Equal(t, map[string]string{
+		"name":     "busy",
+		"owner":    "member",
+		"metric":   "egress",
+		"observed": "2.1 GB",
+		"usual":    "10 MB",
+	}, sent[0].Labels)
+	require.Contains(t, sent[0].Targets, ws.ID)
+
+	logs := auditor.AuditLogs()
+	require.Len(t, logs, 1)
+	require.Equal(t, database.ResourceTypeWorkspace, logs[0].ResourceType)
+	require.Equal(t, ws.ID, logs[0].ResourceID)
+	require.Contains(t, string(logs[0].AdditionalFields), `"anomaly_metric":"tx_bytes"`)
+
+	// Then: the hour is folded into the baselines.
+	baselines, err := db.GetWorkspaceStatBaselines(ctx, []uuid.UUID{ws.ID})
+	require.NoError(t, err)
+	require.Len(t, baselines, len(metrics))
+	for _, b := range baselines {
+		require.EqualValues(t, 49, b.Samples)
+		require.Equal(t, windowEnd, b.UpdatedAt.UTC())
+		require.Equal(t, b.Metric == "tx_bytes", b.FlaggedAt.Valid, b.Metric)
+	}
+
+	// When: the same hour is analyzed again, e.g. by another replica.
+	notifEnq.Clear()
+	err = detect(ctx, logger, db, auditor, notifEnq, windowEnd, th)
+	require.NoError(t, err)
+
+	// Then: it is not reported twice.
+	require.Empty(t, notifEnq.Sent())
+	require.Len(t, auditor.AuditLogs(), 1)
+
+	// When: the workspace keeps sending a lot within the cooldown.
+	insertStat(windowEnd.Add(window), 2<<30)
+	err = detect(ctx, logger, db, auditor, notifEnq, windowEnd.Add(window), th)
+	require.NoError(t, err)
+
+	// Then: it is not reported again.
+	require.Empty(t, notifEnq.Sent())
+	require.Len(t, auditor.AuditLogs(), 1)
+}
+
+func TestIsAnomalous(t *testing.T) {
+	t.Parallel()
+
+	th := threshold{factor: 10, zScore: 3}
+	baseline := database.WorkspaceStatBaseline{Samples: warmupSamples, Mean: 100, Variance: 100}
+
+	require.True(t, isAnomalous(baseline, 1000, 0, th))
+	// Below the factor.
+	require.False(t, isAnomalous(baseline, 999, 0, th))
+	// Below the minimum.
+	require.False(t, isAnomalous(baseline, 1000, 1001, th))
+	// Within the usual variation.
+	noisy := baseline
+	noisy.Variance = 400 * 400
+	require.False(t, isAnomalous(noisy, 1000, 0, th))
+	// Not enough history.
+	fresh := baseline
+	fresh.Samples = warmupSamples - 1
+	require.False(t, isAnomalous(fresh, 1000, 0, th))
+	// Nothing is usually sent at all.
+	require.True(t, isAnomalous(database.WorkspaceStatBaseline{Samples: warmupSamples}, 1, 0, th))
+}
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+
+	// Until maxSamples is reached, the baseline is the mean and the
+	// population variance of all values.
+	var b database.WorkspaceStatBaseline
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		b.Samples, b.Mean, b.Variance = fold(b, v)
+	}
+	require.EqualValues(t, 8, b.Samples)
+	require.InDelta(t, 5, b.Mean, 1e-9)
+	require.InDelta(t, 4, b.Variance, 1e-9)
+
+	// Afterwards, recent values weigh more.
+	b = database.WorkspaceStatBaseline{Samples: maxSamples, Mean: 10}
+	samples, mean, _ := fold(b, 10+maxSamples)
+	require.EqualValues(t, maxSamples+1, samples)
+	require.InDelta(t, 11, mean, 1e-9)
+}
@@ -51,6 +51,8 @@ type DBBatcher struct {
 	flushForced atomic.Bool
 	// flushed is used during testing to signal that a flush has completed.
 	flushed chan<- int
+	// exporter receives every batch after it has been stored.
+	exporter StatsExporter
 }
 
 // Option is a functional option for configuring a Batcher.
@@ -84,6 +86,14 @@ func BatcherWithLogger(log slog.Logger) BatcherOption {
 	}
 }
 
+// BatcherWithExporter sets an exporter that receives every batch of stats
+// after it has been stored.
+func BatcherWithExporter(exporter StatsExporter) BatcherOption {
+	return func(b *DBBatcher) {
+		b.exporter = exporter
+	}
+}
+
 // NewBatcher creates a new Batcher and starts it.
 func NewBatcher(ctx context.Context, opts ...BatcherOption) (*DBBatcher, func(), error) {
 	b := &DBBatcher{}
@@ -256,6 +266,9 @@ func (b *DBBatcher) flush(ctx context.Context, forced bool, reason string) {
 		b.log.Error(ctx, "error inserting workspace agent stats", slog.Error(err), slog.F("elapsed", elapsed))
 		return
 	}
+	if b.exporter != nil {
+		b.exporter.Export(ctx, *b.buf)
+	}
 
 	b.resetBuf()
 }
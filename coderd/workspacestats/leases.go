@@ -0,0 +1,281 @@
+package workspacestats
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/quartz"
+)
+
+// StatsLeaseChannel is the pubsub channel replicas announce the stats
+// ingestion leases they hold on.
+const StatsLeaseChannel = "workspace_agent_stats_lease"
+
+// statsLeaseRetention is how long the state of an agent that stopped
+// reporting stats is kept.
+const statsLeaseRetention = time.Hour
+
+// statsLeaseEvent announces that a replica holds the stats ingestion lease of
+// an agent. It is published when the lease is acquired by a new connection, or
+// taken back by a previous one. It is also published without a lease once
+// stats reported outside of a connection were ingested.
+type statsLeaseEvent struct {
+	AgentID   uuid.UUID `json:"agent_id"`
+	ReplicaID uuid.UUID `json:"replica_id"`
+	// LeaseID is nil when the event only announces ingested stats.
+	LeaseID uuid.UUID `json:"lease_id"`
+	// AcquiredAt is when the lease was acquired, on the clock of the replica.
+	AcquiredAt time.Time `json:"acquired_at"`
+	// CollectedAt is when the latest stats reported outside of a connection
+	// were collected, on the clock of the agent.
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// StatsLeases coordinates the ingestion of agent stats between replicas, so
+// that stats are not counted twice when an agent reconnects to another
+// replica while its previous connection lingers, or retries a report that
+// was already ingested.
+//
+// Every connection of an agent acquires a lease, which revokes the leases of
+// its previous connections on every replica. Stats reported on a revoked lease
+// are dropped. A revoked lease is taken back once the newer lease has not been
+// used for the TTL, so that an agent is not left without a lease. Replicas only
+// announce acquiring leases, not using them, so a replica considers a lease of
+// another replica used when it was acquired.
+//
+// Stats reported outside of a connection carry the time the agent collected
+// them, and are dropped when they were collected before the latest such stats
+// that were ingested by any replica. As these times come from the agent's
+// clock, they are not compared with the server's clock the leases are ordered
+// by.
+type StatsLeases struct {
+	logger    slog.Logger
+	ps        pubsub.Pubsub
+	replicaID uuid.UUID
+	clock     quartz.Clock
+	ttl       time.Duration
+
+	mu       sync.Mutex
+	agents   map[uuid.UUID]*agentStatsLease
+	prunedAt time.Time
+
+	cancel func()
+}
+
+type agentStatsLease struct {
+	// leaseID is the lease stats of the agent are ingested under, on any
+	// replica.
+	leaseID    uuid.UUID
+	acquiredAt time.Time
+	// usedAt is when stats of the agent were last ingested by this replica, or
+	// the lease was last acquired.
+	usedAt time.Time
+	// collectedAt is when the latest ingested stats reported outside of a
+	// connection were collected. Such stats collected before are dropped.
+	collectedAt time.Time
+}
+
+// NewStatsLeases starts listening to the leases announced by other replicas.
+// Leases expire after ttl without stats, which should be a few stats report
+// intervals. It is the caller's responsibility to call Close.
+func NewStatsLeases(logger slog.Logger, ps pubsub.Pubsub, replicaID uuid.UUID, clock quartz.Clock, ttl time.Duration) (*StatsLeases, error) {
+	l := &StatsLeases{
+		logger:    logger,
+		ps:        ps,
+		replicaID: replicaID,
+		clock:     clock,
+		ttl:       ttl,
+		agents:    make(map[uuid.UUID]*agentStatsLease),
+	}
+	cancel, err := ps.Subscribe(StatsLeaseChannel, l.handleEvent)
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to stats leases: %w", err)
+	}
+	l.cancel = cancel
+	return l, nil
+}
+
+func (l *StatsLeases) Close() error {
+	l.cancel()
+	return nil
+}
+
+// StatsLease allows a connection of an agent to ingest its stats.
+type StatsLease struct {
+	leases  *StatsLeases
+	agentID uuid.UUID
+	id      uuid.UUID
+}
+
+// Acquire acquires a lease for a new connection of the agent.
+func (l *StatsLeases) Acquire(ctx context.Context, agentID uuid.UUID) *StatsLease {
+	lease := &StatsLease{leases: l, agentID: agentID, id: uuid.New()}
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	state := l.stateLocked(agentID)
+	state.leaseID = lease.id
+	state.acquiredAt = now
+	state.usedAt = now
+	event := l.eventLocked(agentID, state)
+	l.mu.Unlock()
+
+	l.publish(ctx, event)
+	return lease
+}
+
+// Admit reports whether stats reported on the connection should be ingested.
+// Stats are dropped when the lease was revoked by a newer connection of the
+// agent.
+func (s *StatsLease) Admit(ctx context.Context) bool {
+	l := s.leases
+	l.mu.Lock()
+	now := l.clock.Now()
+	state := l.stateLocked(s.agentID)
+	if state.leaseID == s.id {
+		l.useLocked(state, now)
+		l.mu.Unlock()
+		return true
+	}
+	if now.Sub(state.usedAt) < l.ttl {
+		l.mu.Unlock()
+		l.logger.Debug(ctx, "dropping stats reported on a revoked lease",
+			slog.F("agent_id", s.agentID),
+		)
+		return false
+	}
+	// The newer connection went away, take the lease back.
+	state.leaseID = s.id
+	state.acquiredAt = now
+	l.useLocked(state, now)
+	event := l.eventLocked(s.agentID, state)
+	l.mu.Unlock()
+
+	l.publish(ctx, event)
+	return true
+}
+
+// Admit reports whether stats of the agent collected at collectedAt, that are
+// reported outside of a connection, should be ingested. Stats are dropped when
+// stats collected at the same time or later were already ingested. Admitting
+// stats doesn't record them, Ingested must be called once they are stored.
+func (l *StatsLeases) Admit(ctx context.Context, agentID uuid.UUID, collectedAt time.Time) bool {
+	l.mu.Lock()
+	ingestedUntil := l.stateLocked(agentID).collectedAt
+	l.mu.Unlock()
+	if !collectedAt.After(ingestedUntil) {
+		l.logger.Debug(ctx, "dropping stats overlapping ingested stats",
+			slog.F("agent_id", agentID),
+			slog.F("collected_at", collectedAt),
+			slog.F("ingested_until", ingestedUntil),
+		)
+		return false
+	}
+	return true
+}
+
+// Ingested records that stats of the agent collected until collectedAt, that
+// were reported outside of a connection, were stored, and announces it to the
+// other replicas so that they drop them if they are reported again.
+func (l *StatsLeases) Ingested(ctx context.Context, agentID uuid.UUID, collectedAt time.Time) {
+	l.mu.Lock()
+	state := l.stateLocked(agentID)
+	if !collectedAt.After(state.collectedAt) {
+		l.mu.Unlock()
+		return
+	}
+	state.collectedAt = collectedAt
+	l.useLocked(state, l.clock.Now())
+	l.mu.Unlock()
+
+	l.publish(ctx, statsLeaseEvent{
+		AgentID:     agentID,
+		ReplicaID:   l.replicaID,
+		CollectedAt: collectedAt,
+	})
+}
+
+// stateLocked returns the state of the agent, creating it if it is unknown or
+// was pruned while its connections were idle. The caller must hold the lock.
+func (l *StatsLeases) stateLocked(agentID uuid.UUID) *agentStatsLease {
+	state, ok := l.agents[agentID]
+	if !ok {
+		state = &agentStatsLease{}
+		l.agents[agentID] = state
+	}
+	return state
+}
+
+// useLocked records that stats of the agent were ingested, and prunes the
+// agents that stopped reporting stats once in a while. The caller must hold
+// the lock.
+func (l *StatsLeases) useLocked(state *agentStatsLease, now time.Time) {
+	state.usedAt = now
+	if now.Sub(l.prunedAt) >= statsLeaseRetention {
+		l.pruneLocked(now)
+	}
+}
+
+func (l *StatsLeases) eventLocked(agentID uuid.UUID, state *agentStatsLease) statsLeaseEvent {
+	return statsLeaseEvent{
+		AgentID:     agentID,
+		ReplicaID:   l.replicaID,
+		LeaseID:     state.leaseID,
+		AcquiredAt:  state.acquiredAt,
+		CollectedAt: state.collectedAt,
+	}
+}
+
+func (l *StatsLeases) publish(ctx context.Context, event statsLeaseEvent) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		l.logger.Error(ctx, "failed to marshal stats lease event", slog.Error(err))
+		return
+	}
+	if err := l.ps.Publish(StatsLeaseChannel, msg); err != nil {
+		l.logger.Warn(ctx, "failed to publish stats lease",
+			slog.F("agent_id", event.AgentID), slog.Error(err))
+	}
+}
+
+func (l *StatsLeases) handleEvent(ctx context.Context, msg []byte) {
+	var event statsLeaseEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		l.logger.Warn(ctx, "failed to unmarshal stats lease event", slog.Error(err))
+		return
+	}
+	if event.ReplicaID == l.replicaID {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	state := l.stateLocked(event.AgentID)
+	if event.LeaseID != uuid.Nil && (event.LeaseID == state.leaseID || event.AcquiredAt.After(state.acquiredAt)) {
+		// The lease of the other replica is the current one.
+		state.leaseID = event.LeaseID
+		state.acquiredAt = event.AcquiredAt
+		state.usedAt = l.clock.Now()
+	}
+	if event.CollectedAt.After(state.collectedAt) {
+		state.collectedAt = event.CollectedAt
+	}
+}
+
+// pruneLocked forgets the agents that have not reported stats within the
+// retention period. The caller must hold the lock.
+func (l *StatsLeases) pruneLocked(now time.Time) {
+	for id, state := range l.agents {
+		if now.Sub(state.usedAt) > statsLeaseRetention {
+			delete(l.agents, id)
+		}
+	}
+	l.prunedAt = now
+}
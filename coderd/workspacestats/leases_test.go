@@ -0,0 +1,113 @@
+package workspacestats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/workspacestats"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestStatsLeases(t *testing.T) {
+	t.Parallel()
+
+	const ttl = time.Minute
+	ctx := testutil.Context(t, testutil.WaitShort)
+	ps := pubsub.NewInMemory()
+	clk := quartz.NewMock(t)
+	newLeases := func() *workspacestats.StatsLeases {
+		l, err := workspacestats.NewStatsLeases(slogtest.Make(t, nil), ps, uuid.New(), clk, ttl)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+		return l
+	}
+	replica1 := newLeases()
+	replica2 := newLeases()
+	agentID := uuid.New()
+
+	// Given: the agent is connected to the first replica.
+	first := replica1.Acquire(ctx, agentID)
+	require.True(t, first.Admit(ctx))
+
+	// When: the agent reconnects to the second replica.
+	clk.Advance(time.Second)
+	second := replica2.Acquire(ctx, agentID)
+
+	// Then: stats reported on the previous connection are dropped.
+	clk.Advance(time.Second)
+	require.False(t, first.Admit(ctx))
+	require.True(t, second.Admit(ctx))
+
+	// When: the second connection stops reporting stats.
+	clk.Advance(ttl)
+
+	// Then: the previous connection takes the lease back.
+	require.True(t, first.Admit(ctx))
+	require.False(t, second.Admit(ctx))
+}
+
+func TestStatsLeasesCollectedAt(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	ps := pubsub.NewInMemory()
+	clk := quartz.NewMock(t)
+	l, err := workspacestats.NewStatsLeases(slogtest.Make(t, nil), ps, uuid.New(), clk, time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	agentID := uuid.New()
+	lease := l.Acquire(ctx, agentID)
+
+	// Given: stats collected by an agent whose clock is ahead of the server.
+	collectedAt := clk.Now().Add(time.Minute)
+	require.True(t, l.Admit(ctx, agentID, collectedAt))
+
+	// Then: the stats are admitted again until they were ingested, so that
+	// a retry of a report that failed is not dropped.
+	require.True(t, l.Admit(ctx, agentID, collectedAt))
+	l.Ingested(ctx, agentID, collectedAt)
+
+	// Then: retries of the stats are dropped.
+	require.False(t, l.Admit(ctx, agentID, collectedAt))
+	require.False(t, l.Admit(ctx, agentID, collectedAt.Add(-time.Second)))
+
+	// Then: stats reported on the connection are still ingested.
+	clk.Advance(time.Second)
+	require.True(t, lease.Admit(ctx))
+	require.True(t, l.Admit(ctx, agentID, collectedAt.Add(time.Second)))
+}
+
+func TestStatsLeasesCollectedAtReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	ps := pubsub.NewInMemory()
+	clk := quartz.NewMock(t)
+	newLeases := func() *workspacestats.StatsLeases {
+		l, err := workspacestats.NewStatsLeases(slogtest.Make(t, nil), ps, uuid.New(), clk, time.Minute)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+		return l
+	}
+	replica1 := newLeases()
+	replica2 := newLeases()
+	agentID := uuid.New()
+
+	// Given: a batch of stats ingested by the first replica.
+	collectedAt := clk.Now().Add(-time.Minute)
+	require.True(t, replica1.Admit(ctx, agentID, collectedAt))
+	replica1.Ingested(ctx, agentID, collectedAt)
+
+	// Then: the same batch posted to the second replica is dropped.
+	require.False(t, replica2.Admit(ctx, agentID, collectedAt))
+
+	// Then: newer stats are admitted by both replicas.
+	require.True(t, replica1.Admit(ctx, agentID, collectedAt.Add(time.Second)))
+	require.True(t, replica2.Admit(ctx, agentID, collectedAt.Add(time.Second)))
+}
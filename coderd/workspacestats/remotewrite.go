@@ -0,0 +1,423 @@
+package workspacestats
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// remoteWriteMetricPrefix is prepended to the name of every pushed
+	// series. It differs from the scraped coderd_agentstats_* metrics so both
+	// can live in the same TSDB.
+	remoteWriteMetricPrefix = "coderd_workspace_agent_stats_"
+
+	remoteWriteQueueSize = 16
+	remoteWriteTimeout   = 10 * time.Second
+)
+
+// StatsExporter receives every batch of workspace agent stats the batcher
+// flushes to the database. Exporters must not retain the batch, as the
+// batcher reuses its buffers.
+type StatsExporter interface {
+	Export(ctx context.Context, batch database.InsertWorkspaceAgentStatsParams)
+}
+
+type RemoteWriteOptions struct {
+	Logger      slog.Logger
+	URL         string
+	BearerToken string
+	// Metrics are the stats that are pushed. Defaults to all stats.
+	Metrics        []codersdk.WorkspaceAgentStatsMetric
+	RelabelConfigs []codersdk.PrometheusRelabelConfig
+	// HTTPClient defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// RemoteWriteExporter pushes workspace agent stats to a Prometheus
+// remote_write endpoint. Each flush of the batcher results in one sample per
+// series, timestamped at the time of the flush. Series that have the same
+// labels after relabeling are merged: latency is averaged, everything else is
+// summed.
+type RemoteWriteExporter struct {
+	log         slog.Logger
+	url         string
+	bearerToken string
+	client      *http.Client
+	metrics     []codersdk.WorkspaceAgentStatsMetric
+	relabel     []relabelRule
+
+	queue     chan []byte
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ StatsExporter = (*RemoteWriteExporter)(nil)
+
+// NewRemoteWriteExporter validates the options and starts sending pushed
+// stats in the background. Close must be called to stop it.
+func NewRemoteWriteExporter(opts RemoteWriteOptions) (*RemoteWriteExporter, error) {
+	if opts.URL == "" {
+		return nil, xerrors.New("remote write url is required")
+	}
+	metrics := opts.Metrics
+	if len(metrics) == 0 {
+		metrics = codersdk.AllWorkspaceAgentStatsMetrics
+	}
+	for _, m := range metrics {
+		if !m.Valid() {
+			return nil, xerrors.Errorf("invalid metric %q", m)
+		}
+	}
+	relabel := make([]relabelRule, 0, len(opts.RelabelConfigs))
+	for i, cfg := range opts.RelabelConfigs {
+		rule, err := newRelabelRule(cfg)
+		if err != nil {
+			return nil, xerrors.Errorf("relabel config %d: %w", i, err)
+		}
+		relabel = append(relabel, rule)
+	}
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: remoteWriteTimeout}
+	}
+
+	e := &RemoteWriteExporter{
+		log:         opts.Logger,
+		url:         opts.URL,
+		bearerToken: opts.BearerToken,
+		client:      client,
+		metrics:     metrics,
+		relabel:     relabel,
+		queue:       make(chan []byte, remoteWriteQueueSize),
+		done:        make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// Export converts the batch into remote_write series and queues them to be
+// pushed. If the endpoint can't keep up the batch is dropped rather than
+// holding up the batcher.
+func (e *RemoteWriteExporter) Export(ctx context.Context, batch database.InsertWorkspaceAgentStatsParams) {
+	series := e.series(batch, dbtime.Now())
+	if len(series) == 0 {
+		return
+	}
+	payload := snappy.Encode(nil, encodeWriteRequest(series))
+
+	select {
+	case e.queue <- payload:
+	default:
+		e.log.Warn(ctx, "remote write queue is full, dropping workspace agent stats", slog.F("series", len(series)))
+	}
+}
+
+// Close sends any queued stats and stops the exporter.
+func (e *RemoteWriteExporter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.queue)
+	})
+	<-e.done
+	return nil
+}
+
+func (e *RemoteWriteExporter) run() {
+	defer close(e.done)
+	for payload := range e.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteWriteTimeout)
+		err := e.send(ctx, payload)
+		cancel()
+		if err != nil {
+			e.log.Error(ctx, "push workspace agent stats to remote write endpoint", slog.Error(err))
+		}
+	}
+}
+
+func (e *RemoteWriteExporter) send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "coderd")
+	if e.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+e.bearerToken)
+	}
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+type remoteWriteLabel struct {
+	name  string
+	value string
+}
+
+type remoteWriteSeries struct {
+	labels []remoteWriteLabel
+	value  float64
+	// count is the number of stats merged into the series, used to average
+	// latency.
+	count     int
+	average   bool
+	timestamp int64
+}
+
+// series converts the batch into one sample per series after relabeling.
+func (e *RemoteWriteExporter) series(batch database.InsertWorkspaceAgentStatsParams, now time.Time) []*remoteWriteSeries {
+	var (
+		merged = make(map[string]*remoteWriteSeries)
+		order  []string
+	)
+	for i := range batch.ID {
+		for _, m := range e.metrics {
+			value, ok := batchStatValue(batch, i, m)
+			if !ok {
+				continue
+			}
+			labels := map[string]string{
+				"__name__":     remoteWriteMetricPrefix + string(m),
+				"agent_id":     batch.AgentID[i].String(),
+				"workspace_id": batch.WorkspaceID[i].String(),
+				"template_id":  batch.TemplateID[i].String(),
+				"user_id":      batch.UserID[i].String(),
+			}
+			keep := true
+			for _, rule := range e.relabel {
+				if labels, keep = rule.apply(labels); !keep {
+					break
+				}
+			}
+			if !keep || labels["__name__"] == "" {
+				continue
+			}
+
+			sorted := make([]remoteWriteLabel, 0, len(labels))
+			for name, value := range labels {
+				if value == "" {
+					continue
+				}
+				sorted = append(sorted, remoteWriteLabel{name: name, value: value})
+			}
+			slices.SortFunc(sorted, func(a, b remoteWriteLabel) int {
+				return strings.Compare(a.name, b.name)
+			})
+			var key strings.Builder
+			for _, l := range sorted {
+				_, _ = fmt.Fprintf(&key, "%s\xff%s\xff", l.name, l.value)
+			}
+
+			s, ok := merged[key.String()]
+			if !ok {
+				s = &remoteWriteSeries{
+					labels:    sorted,
+					average:   m == codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS,
+					timestamp: now.UnixMilli(),
+				}
+				merged[key.String()] = s
+				order = append(order, key.String())
+			}
+			s.value += value
+			s.count++
+		}
+	}
+
+	series := make([]*remoteWriteSeries, 0, len(order))
+	for _, key := range order {
+		s := merged[key]
+		if s.average {
+			s.value /= float64(s.count)
+		}
+		series = append(series, s)
+	}
+	return series
+}
+
+// batchStatValue returns the value of the metric for the i-th stat in the
+// batch. Latency is only reported by agents that have connections, so it's
+// skipped otherwise.
+func batchStatValue(batch database.InsertWorkspaceAgentStatsParams, i int, metric codersdk.WorkspaceAgentStatsMetric) (float64, bool) {
+	switch metric {
+	case codersdk.WorkspaceAgentStatsMetricRxBytes:
+		return float64(batch.RxBytes[i]), true
+	case codersdk.WorkspaceAgentStatsMetricTxBytes:
+		return float64(batch.TxBytes[i]), true
+	case codersdk.WorkspaceAgentStatsMetricRxPackets:
+		return float64(batch.RxPackets[i]), true
+	case codersdk.WorkspaceAgentStatsMetricTxPackets:
+		return float64(batch.TxPackets[i]), true
+	case codersdk.WorkspaceAgentStatsMetricConnectionCount:
+		return float64(batch.ConnectionCount[i]), true
+	case codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS:
+		latency := batch.ConnectionMedianLatencyMS[i]
+		return latency, latency > 0
+	case codersdk.WorkspaceAgentStatsMetricSessionCountVSCode:
+		return float64(batch.SessionCountVSCode[i]), true
+	case codersdk.WorkspaceAgentStatsMetricSessionCountJetBrains:
+		return float64(batch.SessionCountJetBrains[i]), true
+	case codersdk.WorkspaceAgentStatsMetricSessionCountReconnectingPTY:
+		return float64(batch.SessionCountReconnectingPTY[i]), true
+	case codersdk.WorkspaceAgentStatsMetricSessionCountSSH:
+		return float64(batch.SessionCountSSH[i]), true
+	default:
+		return 0, false
+	}
+}
+
+// encodeWriteRequest encodes the series as a prometheus.WriteRequest
+// protobuf message.
+func encodeWriteRequest(series []*remoteWriteSeries) []byte {
+	var b []byte
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l.name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(s.timestamp)) // #nosec G115 - Timestamps are positive.
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, ts)
+	}
+	return b
+}
+
+// relabelRule is a compiled codersdk.PrometheusRelabelConfig.
+type relabelRule struct {
+	sourceLabels []string
+	separator    string
+	regex        *regexp.Regexp
+	targetLabel  string
+	replacement  string
+	action       codersdk.PrometheusRelabelAction
+}
+
+func newRelabelRule(cfg codersdk.PrometheusRelabelConfig) (relabelRule, error) {
+	rule := relabelRule{
+		sourceLabels: cfg.SourceLabels,
+		separator:    cfg.Separator,
+		targetLabel:  cfg.TargetLabel,
+		replacement:  cfg.Replacement,
+		action:       cfg.Action,
+	}
+	// These defaults match Prometheus.
+	if rule.separator == "" {
+		rule.separator = ";"
+	}
+	if rule.replacement == "" {
+		rule.replacement = "$1"
+	}
+	if rule.action == "" {
+		rule.action = codersdk.PrometheusRelabelActionReplace
+	}
+	expr := cfg.Regex
+	if expr == "" {
+		expr = "(.*)"
+	}
+	// Like Prometheus, the regex must match the whole value.
+	regex, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return relabelRule{}, xerrors.Errorf("compile regex: %w", err)
+	}
+	rule.regex = regex
+
+	switch rule.action {
+	case codersdk.PrometheusRelabelActionReplace:
+		if rule.targetLabel == "" {
+			return relabelRule{}, xerrors.New("target_label is required for the replace action")
+		}
+	case codersdk.PrometheusRelabelActionKeep, codersdk.PrometheusRelabelActionDrop:
+		if len(rule.sourceLabels) == 0 {
+			return relabelRule{}, xerrors.Errorf("source_labels are required for the %s action", rule.action)
+		}
+	case codersdk.PrometheusRelabelActionLabelDrop, codersdk.PrometheusRelabelActionLabelKeep:
+	default:
+		return relabelRule{}, xerrors.Errorf("unsupported action %q", rule.action)
+	}
+	return rule, nil
+}
+
+// apply returns the relabeled labels, or false if the series should be
+// dropped. The labels are modified in place.
+func (r relabelRule) apply(labels map[string]string) (map[string]string, bool) {
+	values := make([]string, 0, len(r.sourceLabels))
+	for _, name := range r.sourceLabels {
+		values = append(values, labels[name])
+	}
+	value := strings.Join(values, r.separator)
+
+	switch r.action {
+	case codersdk.PrometheusRelabelActionReplace:
+		match := r.regex.FindStringSubmatchIndex(value)
+		if match == nil {
+			return labels, true
+		}
+		target := string(r.regex.ExpandString(nil, r.targetLabel, value, match))
+		replaced := string(r.regex.ExpandString(nil, r.replacement, value, match))
+		if replaced == "" {
+			delete(labels, target)
+		} else {
+			labels[target] = replaced
+		}
+	case codersdk.PrometheusRelabelActionKeep:
+		if !r.regex.MatchString(value) {
+			return nil, false
+		}
+	case codersdk.PrometheusRelabelActionDrop:
+		if r.regex.MatchString(value) {
+			return nil, false
+		}
+	case codersdk.PrometheusRelabelActionLabelDrop:
+		for name := range labels {
+			if r.regex.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	case codersdk.PrometheusRelabelActionLabelKeep:
+		for name := range labels {
+			if !r.regex.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	}
+	return labels, true
+}
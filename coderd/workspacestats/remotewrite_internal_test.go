@@ -0,0 +1,221 @@
+package workspacestats
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRemoteWriteExporter(t *testing.T) {
+	t.Parallel()
+
+	templateID := uuid.New()
+	otherTemplateID := uuid.New()
+	batch := database.InsertWorkspaceAgentStatsParams{}
+	for _, st := range []struct {
+		templateID uuid.UUID
+		rxBytes    int64
+		latency    float64
+	}{
+		{templateID: templateID, rxBytes: 100, latency: 10},
+		{templateID: templateID, rxBytes: 50, latency: 30},
+		{templateID: otherTemplateID, rxBytes: 7, latency: 0},
+	} {
+		batch.ID = append(batch.ID, uuid.New())
+		batch.AgentID = append(batch.AgentID, uuid.New())
+		batch.WorkspaceID = append(batch.WorkspaceID, uuid.New())
+		batch.UserID = append(batch.UserID, uuid.New())
+		batch.TemplateID = append(batch.TemplateID, st.templateID)
+		batch.RxBytes = append(batch.RxBytes, st.rxBytes)
+		batch.TxBytes = append(batch.TxBytes, st.rxBytes)
+		batch.ConnectionMedianLatencyMS = append(batch.ConnectionMedianLatencyMS, st.latency)
+	}
+
+	t.Run("PerTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		requests := make(chan *http.Request, 1)
+		payloads := make(chan []byte, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			decoded, err := snappy.Decode(nil, body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			requests <- r
+			payloads <- decoded
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		exporter, err := NewRemoteWriteExporter(RemoteWriteOptions{
+			Logger:      slogtest.Make(t, nil),
+			URL:         srv.URL,
+			BearerToken: "secret",
+			Metrics: []codersdk.WorkspaceAgentStatsMetric{
+				codersdk.WorkspaceAgentStatsMetricRxBytes,
+				codersdk.WorkspaceAgentStatsMetricConnectionMedianLatencyMS,
+			},
+			RelabelConfigs: []codersdk.PrometheusRelabelConfig{
+				{Action: codersdk.PrometheusRelabelActionLabelDrop, Regex: "agent_id|workspace_id|user_id"},
+				{SourceLabels: []string{"template_id"}, TargetLabel: "template", Regex: "(.{8}).*"},
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = exporter.Close() })
+
+		exporter.Export(ctx, batch)
+
+		req := testutil.RequireReceive(ctx, t, requests)
+		require.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		require.Equal(t, "snappy", req.Header.Get("Content-Encoding"))
+		require.Equal(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+		require.Equal(t, "0.1.0", req.Header.Get("X-Prometheus-Remote-Write-Version"))
+
+		series := decodeWriteRequest(t, testutil.RequireReceive(ctx, t, payloads))
+		values := make(map[string]float64)
+		for _, s := range series {
+			require.Len(t, s.labels, 3, "agent, workspace and user labels should be dropped")
+			require.Equal(t, s.labels["template_id"][:8], s.labels["template"])
+			values[s.labels["__name__"]+"/"+s.labels["template_id"]] = s.value
+		}
+		require.Equal(t, map[string]float64{
+			"coderd_workspace_agent_stats_rx_bytes/" + templateID.String():                     150,
+			"coderd_workspace_agent_stats_rx_bytes/" + otherTemplateID.String():                7,
+			"coderd_workspace_agent_stats_connection_median_latency_ms/" + templateID.String(): 20,
+		}, values)
+	})
+
+	t.Run("KeepDrop", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := NewRemoteWriteExporter(RemoteWriteOptions{
+			Logger: slogtest.Make(t, nil),
+			URL:    "http://localhost",
+			Metrics: []codersdk.WorkspaceAgentStatsMetric{
+				codersdk.WorkspaceAgentStatsMetricRxBytes,
+				codersdk.WorkspaceAgentStatsMetricTxBytes,
+			},
+			RelabelConfigs: []codersdk.PrometheusRelabelConfig{
+				{Action: codersdk.PrometheusRelabelActionKeep, SourceLabels: []string{"template_id"}, Regex: templateID.String()},
+				{Action: codersdk.PrometheusRelabelActionDrop, SourceLabels: []string{"__name__"}, Regex: ".*_tx_bytes"},
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = exporter.Close() })
+
+		series := exporter.series(batch, time.Now())
+		require.Len(t, series, 2)
+		for _, s := range series {
+			labels := make(map[string]string)
+			for _, l := range s.labels {
+				labels[l.name] = l.value
+			}
+			require.Equal(t, "coderd_workspace_agent_stats_rx_bytes", labels["__name__"])
+			require.Equal(t, templateID.String(), labels["template_id"])
+		}
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		t.Parallel()
+
+		for _, cfg := range []codersdk.PrometheusRelabelConfig{
+			{Action: codersdk.PrometheusRelabelActionReplace, SourceLabels: []string{"agent_id"}},
+			{Action: codersdk.PrometheusRelabelActionKeep, Regex: ".*"},
+			{Action: codersdk.PrometheusRelabelActionLabelDrop, Regex: "("},
+			{Action: "hashmod"},
+		} {
+			_, err := NewRemoteWriteExporter(RemoteWriteOptions{
+				URL:            "http://localhost",
+				RelabelConfigs: []codersdk.PrometheusRelabelConfig{cfg},
+			})
+			require.Error(t, err, "action %q", cfg.Action)
+		}
+	})
+}
+
+type testSeries struct {
+	labels map[string]string
+	value  float64
+}
+
+// decodeWriteRequest decodes a prometheus.WriteRequest encoded by
+// encodeWriteRequest.
+func decodeWriteRequest(t *testing.T, b []byte) []testSeries {
+	t.Helper()
+
+	var series []testSeries
+	forEachField(t, b, func(num protowire.Number, v []byte, _ uint64) {
+		require.Equal(t, protowire.Number(1), num)
+		s := testSeries{labels: make(map[string]string)}
+		forEachField(t, v, func(num protowire.Number, v []byte, _ uint64) {
+			switch num {
+			case 1:
+				var name, value string
+				forEachField(t, v, func(num protowire.Number, v []byte, _ uint64) {
+					if num == 1 {
+						name = string(v)
+					} else {
+						value = string(v)
+					}
+				})
+				s.labels[name] = value
+			case 2:
+				forEachField(t, v, func(num protowire.Number, _ []byte, n uint64) {
+					if num == 1 {
+						s.value = math.Float64frombits(n)
+					}
+				})
+			}
+		})
+		series = append(series, s)
+	})
+	return series
+}
+
+func forEachField(t *testing.T, b []byte, fn func(num protowire.Number, v []byte, n uint64)) {
+	t.Helper()
+
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			fn(num, v, 0)
+			b = b[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			require.GreaterOrEqual(t, n, 0)
+			fn(num, nil, v)
+			b = b[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			fn(num, nil, v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package workspacestats
 import (
 	"context"
 	"encoding/json"
+	"maps"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 // TODO: There are currently two paths for reporting activity, both of which are
@@ -52,14 +55,25 @@ type ReporterOptions struct {
 	DisableDatabaseInserts bool
 
 	AppStatBatchSize int
+
+	// DormancyActivitySignal is the activity that keeps workspaces from going
+	// dormant, unless their template overrides it. Defaults to
+	// codersdk.DormancyActivitySignalConnection.
+	DormancyActivitySignal codersdk.DormancyActivitySignal
 }
 
 type Reporter struct {
 	opts ReporterOptions
+
+	dormancySignalsMu sync.Mutex
+	dormancySignals   map[uuid.UUID]cachedDormancySignal
 }
 
 func NewReporter(opts ReporterOptions) *Reporter {
-	return &Reporter{opts: opts}
+	return &Reporter{
+		opts:            opts,
+		dormancySignals: make(map[uuid.UUID]cachedDormancySignal),
+	}
 }
 
 func (r *Reporter) ReportAppStats(ctx context.Context, stats []workspaceapps.StatsReport) error {
@@ -142,7 +156,14 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 	if !r.opts.DisableDatabaseInserts {
 		r.opts.StatsBatcher.Add(now, agentID, workspace.TemplateID, workspace.OwnerID, workspace.ID, stats, usage)
 	}
+	return r.reportAgentActivity(ctx, now, workspace, agentName, stats, usage)
+}
 
+// reportAgentActivity updates the metrics and the activity of the workspace
+// from agent stats reported at now.
+//
+// nolint:revive // usage is a control flag while we have the experiment
+func (r *Reporter) reportAgentActivity(ctx context.Context, now time.Time, workspace database.WorkspaceIdentity, agentName string, stats *agentproto.Stats, usage bool) error {
 	// update prometheus metrics (even if template insights are disabled)
 	if r.opts.UpdateAgentMetricsFn != nil {
 		r.opts.UpdateAgentMetricsFn(ctx, prometheusmetrics.AgentMetricLabels{
@@ -166,12 +187,17 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 		return nil
 	}
 
+	// The template schedule is loaded at most once, and only when needed.
+	loadTemplateSchedule := sync.OnceValues(func() (schedule.TemplateScheduleOptions, error) {
+		return (*(r.opts.TemplateScheduleStore.Load())).Get(ctx, r.opts.Database, workspace.TemplateID)
+	})
+
 	// Prebuilds are not subject to activity-based deadline bumps
 	if !workspace.IsPrebuild() {
 		// check next autostart
 		var nextAutostart time.Time
 		if workspace.AutostartSchedule.String != "" {
-			templateSchedule, err := (*(r.opts.TemplateScheduleStore.Load())).Get(ctx, r.opts.Database, workspace.TemplateID)
+			templateSchedule, err := loadTemplateSchedule()
 			// If the template schedule fails to load, just default to bumping
 			// without the next transition and log it.
 			switch {
@@ -197,8 +223,11 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 		ActivityBumpWorkspace(ctx, r.opts.Logger.Named("activity_bump"), r.opts.Database, workspace.ID, nextAutostart, ActivityBumpReasonWorkspaceStats)
 	}
 
-	// bump workspace last_used_at
-	r.opts.UsageTracker.Add(workspace.ID)
+	// bump workspace last_used_at, which workspaces go dormant based on, if
+	// the activity counts as usage of the workspace
+	if r.isDormancyActivity(ctx, now, workspace, stats, loadTemplateSchedule) {
+		r.opts.UsageTracker.Add(workspace.ID)
+	}
 
 	// notify workspace update
 	msg, err := json.Marshal(wspubsub.WorkspaceEvent{
@@ -217,6 +246,119 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 	return nil
 }
 
+// TimestampedStats are agent stats with the time the agent collected them.
+type TimestampedStats struct {
+	CollectedAt time.Time
+	Stats       *agentproto.Stats
+}
+
+// ReportAgentStatsBatch reports stats an agent collected at different times,
+// e.g. while it was unable to reach coderd. Every report is stored with the
+// time it was collected, while metrics and workspace activity are only updated
+// from the most recent one. Since the times are supplied by the agent, the
+// activity is bumped relative to the current time instead. Reports must be
+// ordered by the time they were collected.
+//
+// nolint:revive // usage is a control flag while we have the experiment
+func (r *Reporter) ReportAgentStatsBatch(ctx context.Context, workspace database.WorkspaceIdentity, agentID uuid.UUID, agentName string, reports []TimestampedStats, usage bool) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	if !r.opts.DisableDatabaseInserts {
+		for _, report := range reports {
+			r.opts.StatsBatcher.Add(report.CollectedAt, agentID, workspace.TemplateID, workspace.OwnerID, workspace.ID, report.Stats, usage)
+		}
+	}
+	latest := reports[len(reports)-1]
+	return r.reportAgentActivity(ctx, dbtime.Now(), workspace, agentName, latest.Stats, usage)
+}
+
+// dormancyTrafficThreshold is the amount of data, in bytes, a report must
+// include for connections to count as usage with
+// codersdk.DormancyActivitySignalTraffic. Idle connections that are left open
+// only exchange keepalives, far below it.
+const dormancyTrafficThreshold = 64 * 1024
+
+// dormancySignalTTL is how long the dormancy activity signal of a template is
+// cached for. Most reports of idle connections depend on it, so loading the
+// template schedule for each of them would be wasteful. Changes to the signal
+// of a template take effect once it expires.
+const dormancySignalTTL = time.Minute
+
+type cachedDormancySignal struct {
+	signal    codersdk.DormancyActivitySignal
+	expiresAt time.Time
+}
+
+// isDormancyActivity returns whether the stats count as usage of the workspace
+// for the dormancy activity signal of its template. Stats without connections
+// must have been skipped already.
+func (r *Reporter) isDormancyActivity(ctx context.Context, now time.Time, workspace database.WorkspaceIdentity, stats *agentproto.Stats, loadTemplateSchedule func() (schedule.TemplateScheduleOptions, error)) bool {
+	hasSessions := stats.SessionCountVscode > 0 ||
+		stats.SessionCountJetbrains > 0 ||
+		stats.SessionCountReconnectingPty > 0 ||
+		stats.SessionCountSsh > 0
+	hasTraffic := stats.RxBytes+stats.TxBytes >= dormancyTrafficThreshold
+	if hasSessions && hasTraffic {
+		// This is activity for every signal.
+		return true
+	}
+
+	signal, err := r.dormancyActivitySignal(now, workspace.TemplateID, loadTemplateSchedule)
+	if err != nil {
+		// Rather keep the workspace alive than let it go dormant while in use.
+		if !database.IsQueryCanceledError(err) {
+			r.opts.Logger.Warn(ctx, "failed to load template schedule, counting activity towards dormancy",
+				slog.F("workspace_id", workspace.ID),
+				slog.F("template_id", workspace.TemplateID),
+				slog.Error(err),
+			)
+		}
+		return true
+	}
+
+	switch signal {
+	case codersdk.DormancyActivitySignalSession:
+		return hasSessions
+	case codersdk.DormancyActivitySignalTraffic:
+		return hasTraffic
+	default:
+		return true
+	}
+}
+
+// dormancyActivitySignal returns the dormancy activity signal of the template,
+// from the cache if it was loaded in the last dormancySignalTTL.
+func (r *Reporter) dormancyActivitySignal(now time.Time, templateID uuid.UUID, loadTemplateSchedule func() (schedule.TemplateScheduleOptions, error)) (codersdk.DormancyActivitySignal, error) {
+	r.dormancySignalsMu.Lock()
+	cached, ok := r.dormancySignals[templateID]
+	r.dormancySignalsMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.signal, nil
+	}
+
+	templateSchedule, err := loadTemplateSchedule()
+	if err != nil {
+		return "", err
+	}
+	signal := r.opts.DormancyActivitySignal
+	if templateSchedule.DormancyActivitySignal != "" {
+		signal = templateSchedule.DormancyActivitySignal
+	}
+
+	r.dormancySignalsMu.Lock()
+	defer r.dormancySignalsMu.Unlock()
+	// Forget deleted templates.
+	maps.DeleteFunc(r.dormancySignals, func(_ uuid.UUID, c cachedDormancySignal) bool {
+		return !now.Before(c.expiresAt)
+	})
+	r.dormancySignals[templateID] = cachedDormancySignal{
+		signal:    signal,
+		expiresAt: now.Add(dormancySignalTTL),
+	}
+	return signal, nil
+}
+
 type UpdateTemplateWorkspacesLastUsedAtFunc func(ctx context.Context, db database.Store, templateID uuid.UUID, lastUsedAt time.Time) error
 
 func UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, db database.Store, templateID uuid.UUID, lastUsedAt time.Time) error {
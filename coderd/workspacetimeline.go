@@ -0,0 +1,285 @@
+package coderd
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	workspaceTimelineDefaultLimit = 25
+	workspaceTimelineMaxLimit     = 100
+)
+
+// @Summary Get workspace timeline by ID
+// @ID get-workspace-timeline-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WorkspaceTimelineEvent
+// @Router /api/v2/workspaces/{workspace}/timeline [get]
+func (api *API) workspaceTimeline(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	page, ok := ParsePagination(rw, r)
+	if !ok {
+		return
+	}
+	if page.AfterID != uuid.Nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter \"after_id\" is not supported by the workspace timeline, use \"offset\" instead.",
+		})
+		return
+	}
+	if page.Limit == 0 {
+		page.Limit = workspaceTimelineDefaultLimit
+	}
+	if page.Limit > workspaceTimelineMaxLimit {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Query parameter \"limit\" must be at most %d.", workspaceTimelineMaxLimit),
+		})
+		return
+	}
+
+	events, err := api.workspaceTimelineEvents(ctx, workspace, page.Offset+page.Limit)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace timeline.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if page.Offset >= len(events) {
+		httpapi.Write(ctx, rw, http.StatusOK, []codersdk.WorkspaceTimelineEvent{})
+		return
+	}
+	events = events[page.Offset:]
+	if len(events) > page.Limit {
+		events = events[:page.Limit]
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, events)
+}
+
+// workspaceTimelineEvents returns the n most recent events of the workspace,
+// most recent first. Since every source is ordered by time, the n most recent
+// events of the merged timeline are among the n most recent events of each
+// source. Connection and audit logs are filtered by the permissions of the
+// caller, so the timeline only includes those the caller is allowed to read.
+func (api *API) workspaceTimelineEvents(ctx context.Context, workspace database.Workspace, n int) ([]codersdk.WorkspaceTimelineEvent, error) {
+	// #nosec G115 - Pagination limits are small and fit in int32
+	limit := int32(n)
+
+	builds, err := api.Database.GetWorkspaceBuildsByWorkspaceID(ctx, database.GetWorkspaceBuildsByWorkspaceIDParams{
+		WorkspaceID: workspace.ID,
+		LimitOpt:    limit,
+	})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get workspace builds: %w", err)
+	}
+	buildEvents, err := api.workspaceTimelineBuildEvents(ctx, builds)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionLogs, err := api.Database.GetConnectionLogsOffset(ctx, database.GetConnectionLogsOffsetParams{
+		WorkspaceID: workspace.ID,
+		LimitOpt:    limit,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get connection logs: %w", err)
+	}
+
+	auditLogs, err := api.Database.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{
+		ResourceID: workspace.ID,
+		LimitOpt:   limit,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get audit logs: %w", err)
+	}
+
+	events := make([]codersdk.WorkspaceTimelineEvent, 0, len(buildEvents)+len(connectionLogs)+len(auditLogs))
+	events = append(events, buildEvents...)
+	for _, row := range connectionLogs {
+		events = append(events, workspaceTimelineConnectionEvent(row.ConnectionLog))
+	}
+	for _, row := range auditLogs {
+		description := strings.NewReplacer(
+			"{user}", cmp.Or(row.UserUsername.String, "Unknown user"),
+			"{target}", row.AuditLog.ResourceTarget,
+		).Replace(auditLogDescription(row))
+		events = append(events, codersdk.WorkspaceTimelineEvent{
+			ID:          row.AuditLog.ID,
+			Type:        codersdk.WorkspaceTimelineEventTypeAudit,
+			Time:        row.AuditLog.Time,
+			Description: description,
+			UserID:      nonNilUUID(row.AuditLog.UserID),
+		})
+	}
+
+	slices.SortStableFunc(events, func(a, b codersdk.WorkspaceTimelineEvent) int {
+		return b.Time.Compare(a.Time)
+	})
+	if len(events) > n {
+		events = events[:n]
+	}
+	return events, nil
+}
+
+// workspaceTimelineBuildEvents returns the events of the given builds and of
+// the agents they created. Agents only record when they last reached each
+// lifecycle state, so restarts within a build appear only once.
+func (api *API) workspaceTimelineBuildEvents(ctx context.Context, builds []database.WorkspaceBuild) ([]codersdk.WorkspaceTimelineEvent, error) {
+	if len(builds) == 0 {
+		return nil, nil
+	}
+
+	jobIDs := make([]uuid.UUID, 0, len(builds))
+	buildsByJobID := make(map[uuid.UUID]database.WorkspaceBuild, len(builds))
+	for _, build := range builds {
+		jobIDs = append(jobIDs, build.JobID)
+		buildsByJobID[build.JobID] = build
+	}
+	jobs, err := api.Database.GetProvisionerJobsByIDsWithQueuePosition(ctx, database.GetProvisionerJobsByIDsWithQueuePositionParams{
+		IDs:             jobIDs,
+		StaleIntervalMS: provisionerdserver.StaleInterval.Milliseconds(),
+	})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get provisioner jobs: %w", err)
+	}
+	jobsByID := make(map[uuid.UUID]database.ProvisionerJob, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ProvisionerJob.ID] = job.ProvisionerJob
+	}
+
+	events := make([]codersdk.WorkspaceTimelineEvent, 0, len(builds))
+	for _, build := range builds {
+		description := fmt.Sprintf("Build #%d: %s", build.BuildNumber, build.Transition)
+		event := codersdk.WorkspaceTimelineEvent{
+			ID:          build.ID,
+			Type:        codersdk.WorkspaceTimelineEventTypeBuild,
+			Time:        build.CreatedAt,
+			UserID:      nonNilUUID(build.InitiatorID),
+			BuildNumber: build.BuildNumber,
+		}
+		if job, ok := jobsByID[build.JobID]; ok {
+			description = fmt.Sprintf("%s %s", description, job.JobStatus)
+			if job.CompletedAt.Valid {
+				event.EndTime = &job.CompletedAt.Time
+			}
+		}
+		if build.Reason != database.BuildReasonInitiator {
+			description = fmt.Sprintf("%s (%s)", description, build.Reason)
+		}
+		event.Description = description
+		events = append(events, event)
+	}
+
+	// nolint:gocritic // Getting workspace resources by job ID is a system function.
+	resources, err := api.Database.GetWorkspaceResourcesByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get workspace resources by job: %w", err)
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	buildNumbersByResourceID := make(map[uuid.UUID]int32, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+		buildNumbersByResourceID[resource.ID] = buildsByJobID[resource.JobID].BuildNumber
+	}
+	// nolint:gocritic // Getting workspace agents by resource IDs is a system function.
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get workspace agents: %w", err)
+	}
+	for _, agent := range agents {
+		for _, state := range []struct {
+			at          sql.NullTime
+			description string
+		}{
+			{agent.FirstConnectedAt, "connected"},
+			{agent.StartedAt, "started"},
+			{agent.ReadyAt, "ready"},
+			{agent.DisconnectedAt, "disconnected"},
+		} {
+			if !state.at.Valid {
+				continue
+			}
+			events = append(events, codersdk.WorkspaceTimelineEvent{
+				ID:          agent.ID,
+				Type:        codersdk.WorkspaceTimelineEventTypeAgentLifecycle,
+				Time:        state.at.Time,
+				Description: fmt.Sprintf("Agent %q %s", agent.Name, state.description),
+				BuildNumber: buildNumbersByResourceID[agent.ResourceID],
+			})
+		}
+	}
+	return events, nil
+}
+
+func workspaceTimelineConnectionEvent(log database.ConnectionLog) codersdk.WorkspaceTimelineEvent {
+	event := codersdk.WorkspaceTimelineEvent{
+		ID:   log.ID,
+		Type: codersdk.WorkspaceTimelineEventTypeConnection,
+		Time: log.ConnectTime,
+	}
+	if log.UserID.Valid {
+		event.UserID = &log.UserID.UUID
+	}
+	if log.DisconnectTime.Valid {
+		event.EndTime = &log.DisconnectTime.Time
+	}
+
+	switch log.Type {
+	case database.ConnectionTypeWorkspaceApp:
+		event.Type = codersdk.WorkspaceTimelineEventTypeAppUsage
+		event.Description = fmt.Sprintf("App %q opened on agent %q", log.SlugOrPort.String, log.AgentName)
+	case database.ConnectionTypePortForwarding:
+		event.Type = codersdk.WorkspaceTimelineEventTypeAppUsage
+		event.Description = fmt.Sprintf("Port %s forwarded on agent %q", log.SlugOrPort.String, log.AgentName)
+	default:
+		event.Description = fmt.Sprintf("%s connection to agent %q", connectionTypeName(log.Type), log.AgentName)
+	}
+	return event
+}
+
+func connectionTypeName(t database.ConnectionType) string {
+	switch t {
+	case database.ConnectionTypeSsh:
+		return "SSH"
+	case database.ConnectionTypeVscode:
+		return "VS Code"
+	case database.ConnectionTypeJetbrains:
+		return "JetBrains"
+	case database.ConnectionTypeReconnectingPty:
+		return "Terminal"
+	default:
+		return string(t)
+	}
+}
+
+func nonNilUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
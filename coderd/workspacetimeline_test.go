@@ -0,0 +1,109 @@
+package coderd_test
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceTimeline(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   pubsub,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        owner.UserID,
+	}).WithAgent().Do()
+
+	now := dbtime.Now()
+	dbgen.ConnectionLog(t, db, database.UpsertConnectionLogParams{
+		Time:             now.Add(time.Minute),
+		OrganizationID:   owner.OrganizationID,
+		WorkspaceOwnerID: owner.UserID,
+		WorkspaceID:      r.Workspace.ID,
+		WorkspaceName:    r.Workspace.Name,
+		AgentName:        "dev",
+		Type:             database.ConnectionTypeSsh,
+	})
+	dbgen.ConnectionLog(t, db, database.UpsertConnectionLogParams{
+		Time:             now.Add(2 * time.Minute),
+		OrganizationID:   owner.OrganizationID,
+		WorkspaceOwnerID: owner.UserID,
+		WorkspaceID:      r.Workspace.ID,
+		WorkspaceName:    r.Workspace.Name,
+		AgentName:        "dev",
+		Type:             database.ConnectionTypeWorkspaceApp,
+		SlugOrPort:       sql.NullString{String: "code-server", Valid: true},
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		events, err := client.WorkspaceTimeline(ctx, r.Workspace.ID, codersdk.WorkspaceTimelineRequest{})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(events), 3)
+		for i := 1; i < len(events); i++ {
+			require.False(t, events[i].Time.After(events[i-1].Time), "events must be ordered by time, most recent first")
+		}
+
+		require.Equal(t, codersdk.WorkspaceTimelineEventTypeAppUsage, events[0].Type)
+		require.Contains(t, events[0].Description, "code-server")
+		require.Equal(t, codersdk.WorkspaceTimelineEventTypeConnection, events[1].Type)
+		require.Contains(t, events[1].Description, "SSH")
+
+		var build *codersdk.WorkspaceTimelineEvent
+		for _, event := range events {
+			if event.Type == codersdk.WorkspaceTimelineEventTypeBuild {
+				build = &event
+			}
+		}
+		require.NotNil(t, build)
+		require.Equal(t, r.Build.ID, build.ID)
+		require.Equal(t, r.Build.BuildNumber, build.BuildNumber)
+	})
+
+	t.Run("Paginated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		all, err := client.WorkspaceTimeline(ctx, r.Workspace.ID, codersdk.WorkspaceTimelineRequest{})
+		require.NoError(t, err)
+
+		page, err := client.WorkspaceTimeline(ctx, r.Workspace.ID, codersdk.WorkspaceTimelineRequest{
+			Pagination: codersdk.Pagination{Offset: 1, Limit: 1},
+		})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, all[1], page[0])
+	})
+
+	t.Run("AfterID", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := client.WorkspaceTimeline(ctx, r.Workspace.ID, codersdk.WorkspaceTimelineRequest{
+			Pagination: codersdk.Pagination{AfterID: r.Build.ID},
+		})
+		cerr := coderdtest.SDKError(t, err)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+}
@@ -630,6 +630,37 @@ type StatsResponse struct {
 	// ReportInterval is the duration after which the agent should send stats
 	// again.
 	ReportInterval time.Duration `json:"report_interval"`
+	// Dropped is the number of stats in a PostStatsRequest that were not
+	// recorded because they were collected too long ago.
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// PostStatsRequest reports stats the agent collected at different times in a
+// single request. It is an alternative to UpdateStats on the Agent API for
+// agents behind proxies that break long-lived connections, which buffer their
+// stats until they can be delivered.
+type PostStatsRequest struct {
+	Stats []TimestampedStats `json:"stats"`
+}
+
+// TimestampedStats are stats with the time the agent collected them.
+type TimestampedStats struct {
+	CollectedAt time.Time `json:"collected_at" format:"date-time"`
+	Stats       Stats     `json:"stats"`
+}
+
+// PostStats reports a batch of stats over HTTP.
+func (c *Client) PostStats(ctx context.Context, req PostStatsRequest) (StatsResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/stats", req)
+	if err != nil {
+		return StatsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return StatsResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp StatsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
 type PostLifecycleRequest struct {
@@ -389,6 +389,70 @@ func ProtoFromLog(log Log) (*proto.Log, error) {
 	}, nil
 }
 
+func ProtoFromStats(s Stats) (*proto.Stats, error) {
+	metrics := make([]*proto.Stats_Metric, 0, len(s.Metrics))
+	for _, m := range s.Metrics {
+		t, ok := proto.Stats_Metric_Type_value[strings.ToUpper(string(m.Type))]
+		if !ok {
+			return nil, xerrors.Errorf("unknown metric type: %s", m.Type)
+		}
+		labels := make([]*proto.Stats_Metric_Label, 0, len(m.Labels))
+		for _, l := range m.Labels {
+			labels = append(labels, &proto.Stats_Metric_Label{Name: l.Name, Value: l.Value})
+		}
+		metrics = append(metrics, &proto.Stats_Metric{
+			Name:   m.Name,
+			Type:   proto.Stats_Metric_Type(t),
+			Value:  m.Value,
+			Labels: labels,
+		})
+	}
+	return &proto.Stats{
+		ConnectionsByProto:          s.ConnectionsByProto,
+		ConnectionCount:             s.ConnectionCount,
+		ConnectionMedianLatencyMs:   s.ConnectionMedianLatencyMS,
+		RxPackets:                   s.RxPackets,
+		RxBytes:                     s.RxBytes,
+		TxPackets:                   s.TxPackets,
+		TxBytes:                     s.TxBytes,
+		SessionCountVscode:          s.SessionCountVSCode,
+		SessionCountJetbrains:       s.SessionCountJetBrains,
+		SessionCountReconnectingPty: s.SessionCountReconnectingPTY,
+		SessionCountSsh:             s.SessionCountSSH,
+		Metrics:                     metrics,
+	}, nil
+}
+
+func StatsFromProto(s *proto.Stats) Stats {
+	metrics := make([]AgentMetric, 0, len(s.GetMetrics()))
+	for _, m := range s.GetMetrics() {
+		labels := make([]AgentMetricLabel, 0, len(m.GetLabels()))
+		for _, l := range m.GetLabels() {
+			labels = append(labels, AgentMetricLabel{Name: l.GetName(), Value: l.GetValue()})
+		}
+		metrics = append(metrics, AgentMetric{
+			Name:   m.GetName(),
+			Type:   AgentMetricType(strings.ToLower(m.GetType().String())),
+			Value:  m.GetValue(),
+			Labels: labels,
+		})
+	}
+	return Stats{
+		ConnectionsByProto:          s.GetConnectionsByProto(),
+		ConnectionCount:             s.GetConnectionCount(),
+		ConnectionMedianLatencyMS:   s.GetConnectionMedianLatencyMs(),
+		RxPackets:                   s.GetRxPackets(),
+		RxBytes:                     s.GetRxBytes(),
+		TxPackets:                   s.GetTxPackets(),
+		TxBytes:                     s.GetTxBytes(),
+		SessionCountVSCode:          s.GetSessionCountVscode(),
+		SessionCountJetBrains:       s.GetSessionCountJetbrains(),
+		SessionCountReconnectingPTY: s.GetSessionCountReconnectingPty(),
+		SessionCountSSH:             s.GetSessionCountSsh(),
+		Metrics:                     metrics,
+	}
+}
+
 func ProtoFromLifecycle(req PostLifecycleRequest) (*proto.Lifecycle, error) {
 	s, ok := proto.Lifecycle_State_value[strings.ToUpper(string(req.State))]
 	if !ok {
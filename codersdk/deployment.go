@@ -612,6 +612,26 @@ var AIBudgetPeriods = []string{
 	string(AIBudgetPeriodMonth),
 }
 
+// AnomalyDetectionSensitivity determines how far the hourly activity of a
+// workspace must exceed its usual activity to be flagged as anomalous.
+type AnomalyDetectionSensitivity string
+
+const (
+	AnomalyDetectionSensitivityOff    AnomalyDetectionSensitivity = "off"
+	AnomalyDetectionSensitivityLow    AnomalyDetectionSensitivity = "low"
+	AnomalyDetectionSensitivityMedium AnomalyDetectionSensitivity = "medium"
+	AnomalyDetectionSensitivityHigh   AnomalyDetectionSensitivity = "high"
+)
+
+// AnomalyDetectionSensitivities lists the supported AnomalyDetectionSensitivity
+// values.
+var AnomalyDetectionSensitivities = []string{
+	string(AnomalyDetectionSensitivityOff),
+	string(AnomalyDetectionSensitivityLow),
+	string(AnomalyDetectionSensitivityMedium),
+	string(AnomalyDetectionSensitivityHigh),
+}
+
 // DeploymentValues is the central configuration values the coder server.
 type DeploymentValues struct {
 	Verbose             serpent.Bool   `json:"verbose,omitempty"`
@@ -669,13 +689,16 @@ type DeploymentValues struct {
 	DisableOwnerWorkspaceExec               serpent.Bool                         `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
 	DisableWorkspaceSharing                 serpent.Bool                         `json:"disable_workspace_sharing,omitempty" typescript:",notnull"`
 	DisableChatSharing                      serpent.Bool                         `json:"disable_chat_sharing,omitempty" typescript:",notnull"`
+	LicenseSeatUsageWindow                  serpent.Duration                     `json:"license_seat_usage_window,omitempty" typescript:",notnull"`
 	ProxyHealthStatusInterval               serpent.Duration                     `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
 	EnableTerraformDebugMode                serpent.Bool                         `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
 	UserQuietHoursSchedule                  UserQuietHoursScheduleConfig         `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
 	WebTerminalRenderer                     serpent.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
 	AllowWorkspaceRenames                   serpent.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
+	DormancyActivitySignal                  string                               `json:"dormancy_activity_signal,omitempty" typescript:",notnull"`
 	Healthcheck                             HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
 	Retention                               RetentionConfig                      `json:"retention,omitempty" typescript:",notnull"`
+	AuditLogExport                          AuditLogExportConfig                 `json:"audit_log_export,omitempty" typescript:",notnull"`
 	CLIUpgradeMessage                       serpent.String                       `json:"cli_upgrade_message,omitempty" typescript:",notnull"`
 	TermsOfServiceURL                       serpent.String                       `json:"terms_of_service_url,omitempty" typescript:",notnull"`
 	Notifications                           NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
@@ -883,19 +906,68 @@ type DERPConfig struct {
 }
 
 type UsageStatsConfig struct {
-	Enable serpent.Bool `json:"enable" typescript:",notnull"`
+	Enable           serpent.Bool     `json:"enable" typescript:",notnull"`
+	InsightsCacheTTL serpent.Duration `json:"insights_cache_ttl" typescript:",notnull"`
+}
+
+// ClientGeographyConfig configures the request headers the coarse network
+// location of clients is derived from. Both are empty by default, in which case
+// it is not recorded.
+type ClientGeographyConfig struct {
+	RegionHeader serpent.String `json:"region_header" typescript:",notnull"`
+	ASNHeader    serpent.String `json:"asn_header" typescript:",notnull"`
+}
+
+// AnomalyDetectionConfig configures flagging workspaces whose traffic or
+// sessions are far above their usual activity.
+type AnomalyDetectionConfig struct {
+	Sensitivity string `json:"sensitivity" typescript:",notnull"`
 }
 
 type StatsCollectionConfig struct {
-	UsageStats UsageStatsConfig `json:"usage_stats" tyescript:",notnull"`
+	UsageStats       UsageStatsConfig       `json:"usage_stats" tyescript:",notnull"`
+	ClientGeography  ClientGeographyConfig  `json:"client_geography" typescript:",notnull"`
+	AnomalyDetection AnomalyDetectionConfig `json:"anomaly_detection" typescript:",notnull"`
 }
 
 type PrometheusConfig struct {
-	Enable                serpent.Bool        `json:"enable" typescript:",notnull"`
-	Address               serpent.HostPort    `json:"address" typescript:",notnull"`
-	CollectAgentStats     serpent.Bool        `json:"collect_agent_stats" typescript:",notnull"`
-	CollectDBMetrics      serpent.Bool        `json:"collect_db_metrics" typescript:",notnull"`
-	AggregateAgentStatsBy serpent.StringArray `json:"aggregate_agent_stats_by" typescript:",notnull"`
+	Enable                serpent.Bool                `json:"enable" typescript:",notnull"`
+	Address               serpent.HostPort            `json:"address" typescript:",notnull"`
+	CollectAgentStats     serpent.Bool                `json:"collect_agent_stats" typescript:",notnull"`
+	CollectDBMetrics      serpent.Bool                `json:"collect_db_metrics" typescript:",notnull"`
+	AggregateAgentStatsBy serpent.StringArray         `json:"aggregate_agent_stats_by" typescript:",notnull"`
+	RemoteWrite           PrometheusRemoteWriteConfig `json:"remote_write" typescript:",notnull"`
+}
+
+// PrometheusRemoteWriteConfig configures pushing workspace agent stats to a
+// Prometheus remote_write endpoint.
+type PrometheusRemoteWriteConfig struct {
+	URL            serpent.URL                               `json:"url" typescript:",notnull"`
+	BearerToken    serpent.String                            `json:"bearer_token" typescript:",notnull"`
+	Metrics        serpent.StringArray                       `json:"metrics" typescript:",notnull"`
+	RelabelConfigs serpent.Struct[[]PrometheusRelabelConfig] `json:"relabel_configs" typescript:",notnull"`
+}
+
+type PrometheusRelabelAction string
+
+const (
+	PrometheusRelabelActionReplace   PrometheusRelabelAction = "replace"
+	PrometheusRelabelActionKeep      PrometheusRelabelAction = "keep"
+	PrometheusRelabelActionDrop      PrometheusRelabelAction = "drop"
+	PrometheusRelabelActionLabelDrop PrometheusRelabelAction = "labeldrop"
+	PrometheusRelabelActionLabelKeep PrometheusRelabelAction = "labelkeep"
+)
+
+// PrometheusRelabelConfig is the subset of a Prometheus relabel_config that is
+// applied to series before they are pushed to a remote_write endpoint. Empty
+// fields use the same defaults as Prometheus.
+type PrometheusRelabelConfig struct {
+	SourceLabels []string                `json:"source_labels,omitempty" yaml:"source_labels,omitempty"`
+	Separator    string                  `json:"separator,omitempty" yaml:"separator,omitempty"`
+	Regex        string                  `json:"regex,omitempty" yaml:"regex,omitempty"`
+	TargetLabel  string                  `json:"target_label,omitempty" yaml:"target_label,omitempty"`
+	Replacement  string                  `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	Action       PrometheusRelabelAction `json:"action,omitempty" yaml:"action,omitempty" enums:"replace,keep,drop,labeldrop,labelkeep"`
 }
 
 type PprofConfig struct {
@@ -1211,6 +1283,27 @@ type RetentionConfig struct {
 	// deletion (keep indefinitely). Adjust to match your
 	// organization's regulatory requirements.
 	BoundaryLogs serpent.Duration `json:"boundary_logs" typescript:",notnull"`
+	// WorkspaceAgentStats controls how long raw workspace agent stats are
+	// retained. Stats are only deleted once they have been rolled up into
+	// template usage stats. Defaults to 1 day to preserve existing behavior.
+	WorkspaceAgentStats serpent.Duration `json:"workspace_agent_stats" typescript:",notnull"`
+}
+
+// AuditLogExportConfig contains configuration for exporting audit logs to
+// object storage.
+type AuditLogExportConfig struct {
+	// Bucket is the S3 bucket the audit logs are exported to. The export is
+	// disabled when empty.
+	Bucket serpent.String `json:"bucket" typescript:",notnull"`
+	// Prefix is prepended to the keys of the exported objects.
+	Prefix serpent.String `json:"prefix" typescript:",notnull"`
+	// Region is the region of the bucket.
+	Region serpent.String `json:"region" typescript:",notnull"`
+	// Endpoint is the endpoint of an S3 compatible service, e.g. Google
+	// Cloud Storage.
+	Endpoint serpent.String `json:"endpoint" typescript:",notnull"`
+	// IncludeStats also exports the template usage stats.
+	IncludeStats serpent.Bool `json:"include_stats" typescript:",notnull"`
 }
 
 type NotificationsConfig struct {
@@ -1241,6 +1334,11 @@ type NotificationsConfig struct {
 	// How often to query the database for queued notifications.
 	FetchInterval serpent.Duration `json:"fetch_interval"`
 
+	// How long a workspace must go unused before its owner is notified. A value of 0 disables the notification.
+	UnusedWorkspaceAfter serpent.Duration `json:"unused_workspace_after" typescript:",notnull"`
+	// Whether template admins are notified about unused workspaces in their organizations as well.
+	UnusedWorkspaceNotifyAdmins serpent.Bool `json:"unused_workspace_notify_admins" typescript:",notnull"`
+
 	// Which delivery method to use (available options: 'smtp', 'webhook').
 	Method serpent.String `json:"method"`
 	// How long to wait while a notification is being sent before giving up.
@@ -1470,6 +1568,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Name:   "Usage Stats",
 			YAML:   "usageStats",
 		}
+		deploymentGroupIntrospectionStatsCollectionClientGeography = serpent.Group{
+			Parent: &deploymentGroupIntrospectionStatsCollection,
+			Name:   "Client Geography",
+			YAML:   "clientGeography",
+		}
+		deploymentGroupIntrospectionStatsCollectionAnomalyDetection = serpent.Group{
+			Parent: &deploymentGroupIntrospectionStatsCollection,
+			Name:   "Anomaly Detection",
+			YAML:   "anomalyDetection",
+		}
 		deploymentGroupIntrospectionPrometheus = serpent.Group{
 			Parent: &deploymentGroupIntrospection,
 			Name:   "Prometheus",
@@ -1616,6 +1724,11 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Description: "Configure data retention policies for various database tables. Retention policies automatically purge old data to reduce database size and improve performance. Setting a retention duration to 0 disables automatic purging for that data type.",
 			YAML:        "retention",
 		}
+		deploymentGroupAuditLogExport = serpent.Group{
+			Name:        "Audit Log Export",
+			Description: "Export audit logs to an S3 compatible bucket as hourly objects of gzipped JSON lines, to retain them beyond the audit logs retention. Credentials are read from the standard AWS environment variables or the instance role.",
+			YAML:        "auditLogExport",
+		}
 		deploymentGroupTemplateBuilder = serpent.Group{
 			Name: "Template Builder",
 			YAML: "templateBuilder",
@@ -2505,6 +2618,50 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Group:       &deploymentGroupIntrospectionStatsCollectionUsageStats,
 			YAML:        "enable",
 		},
+		{
+			Name: "Stats Collection Usage Stats Insights Cache TTL",
+			Description: "How long insights responses are cached, so that dashboards refreshing insights do not re-run the aggregate queries behind them. " +
+				"Cached responses are invalidated whenever usage stats are rolled up, every 5 minutes. Set to 0 to disable caching.",
+			Flag:    "stats-collection-usage-stats-insights-cache-ttl",
+			Env:     "CODER_STATS_COLLECTION_USAGE_STATS_INSIGHTS_CACHE_TTL",
+			Default: "0",
+			Value:   &c.StatsCollection.UsageStats.InsightsCacheTTL,
+			Group:   &deploymentGroupIntrospectionStatsCollectionUsageStats,
+			YAML:    "insightsCacheTTL",
+		},
+		{
+			Name: "Stats Collection Client Geography Region Header",
+			Description: "The request header a trusted edge proxy sets to the region of the client, e.g. CF-IPCountry or CloudFront-Viewer-Country. " +
+				"When set, the region of clients connecting to workspaces is recorded to aggregate connection latency by client region. " +
+				"Only set this if clients cannot reach Coder without passing through the proxy, as they could otherwise forge the header. Leave empty to not record it.",
+			Flag:  "stats-collection-client-geography-region-header",
+			Env:   "CODER_STATS_COLLECTION_CLIENT_GEOGRAPHY_REGION_HEADER",
+			Value: &c.StatsCollection.ClientGeography.RegionHeader,
+			Group: &deploymentGroupIntrospectionStatsCollectionClientGeography,
+			YAML:  "regionHeader",
+		},
+		{
+			Name: "Stats Collection Client Geography ASN Header",
+			Description: "The request header a trusted edge proxy sets to the autonomous system number of the network of the client, e.g. CloudFront-Viewer-ASN. " +
+				"When set, the network of clients connecting to workspaces is recorded to aggregate connection latency by client network. Leave empty to not record it.",
+			Flag:  "stats-collection-client-geography-asn-header",
+			Env:   "CODER_STATS_COLLECTION_CLIENT_GEOGRAPHY_ASN_HEADER",
+			Value: &c.StatsCollection.ClientGeography.ASNHeader,
+			Group: &deploymentGroupIntrospectionStatsCollectionClientGeography,
+			YAML:  "asnHeader",
+		},
+		{
+			Name: "Stats Collection Anomaly Detection Sensitivity",
+			Description: "How sensitive the detection of workspaces with anomalous activity is. Every hour, the egress, ingress and concurrent sessions of each workspace are compared to its usual activity, " +
+				"and workspaces far above it are recorded in the audit log and reported to the owners of the deployment. " +
+				"\"high\" flags smaller deviations than \"low\", at the cost of more false positives. Set to \"off\" to disable.",
+			Flag:    "stats-collection-anomaly-detection-sensitivity",
+			Env:     "CODER_STATS_COLLECTION_ANOMALY_DETECTION_SENSITIVITY",
+			Value:   serpent.EnumOf(&c.StatsCollection.AnomalyDetection.Sensitivity, AnomalyDetectionSensitivities...),
+			Default: string(AnomalyDetectionSensitivityOff),
+			Group:   &deploymentGroupIntrospectionStatsCollectionAnomalyDetection,
+			YAML:    "sensitivity",
+		},
 		// TODO: support Git Auth settings.
 		// Prometheus settings
 		{
@@ -2567,6 +2724,51 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			YAML:    "collect_db_metrics",
 			Default: "false",
 		},
+		{
+			Name:        "Prometheus Remote Write URL",
+			Description: "Push workspace agent stats to a Prometheus remote_write endpoint (such as Mimir, Thanos or VictoriaMetrics) every time they are flushed to the database. Leave empty to disable.",
+			Flag:        "prometheus-remote-write-url",
+			Env:         "CODER_PROMETHEUS_REMOTE_WRITE_URL",
+			Value:       &c.Prometheus.RemoteWrite.URL,
+			Group:       &deploymentGroupIntrospectionPrometheus,
+			YAML:        "remote_write_url",
+		},
+		{
+			Name:        "Prometheus Remote Write Bearer Token",
+			Description: "Bearer token sent in the Authorization header of remote_write requests.",
+			Flag:        "prometheus-remote-write-bearer-token",
+			Env:         "CODER_PROMETHEUS_REMOTE_WRITE_BEARER_TOKEN",
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Prometheus.RemoteWrite.BearerToken,
+			Group:       &deploymentGroupIntrospectionPrometheus,
+		},
+		{
+			Name:        "Prometheus Remote Write Metrics",
+			Description: fmt.Sprintf("The comma-separated workspace agent stats to push to the remote_write endpoint. Accepted values are %s.", strings.Join(workspaceAgentStatsMetricStrings(), ", ")),
+			Flag:        "prometheus-remote-write-metrics",
+			Env:         "CODER_PROMETHEUS_REMOTE_WRITE_METRICS",
+			Value: serpent.Validate(&c.Prometheus.RemoteWrite.Metrics, func(value *serpent.StringArray) error {
+				if value == nil {
+					return nil
+				}
+				for _, m := range value.Value() {
+					if !WorkspaceAgentStatsMetric(m).Valid() {
+						return xerrors.Errorf("invalid metric %q, accepted values are %s", m, strings.Join(workspaceAgentStatsMetricStrings(), ", "))
+					}
+				}
+				return nil
+			}),
+			Group:   &deploymentGroupIntrospectionPrometheus,
+			YAML:    "remote_write_metrics",
+			Default: strings.Join(workspaceAgentStatsMetricStrings(), ","),
+		},
+		{
+			Name:        "Prometheus Remote Write Relabel Configs",
+			Description: "Relabeling rules applied to workspace agent stats series before they are pushed to the remote_write endpoint. Supports the replace, keep, drop, labeldrop and labelkeep actions. Series that end up with the same labels are merged, so dropping the agent, workspace and user labels produces per-template series.",
+			Value:       &c.Prometheus.RemoteWrite.RelabelConfigs,
+			Group:       &deploymentGroupIntrospectionPrometheus,
+			YAML:        "remote_write_relabel_configs",
+		},
 		// Pprof settings
 		{
 			Name:        "pprof Enable",
@@ -3637,6 +3839,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Value: &c.DisableChatSharing,
 			YAML:  "disableChatSharing",
 		},
+		{
+			Name:        "License Seat Usage Window",
+			Description: "Count license seats from workspace usage instead of user status. When set, only users that actively used a workspace within the window consume a seat. Set to 0 to count every active user.",
+			Flag:        "license-seat-usage-window",
+			Env:         "CODER_LICENSE_SEAT_USAGE_WINDOW",
+			Default:     "0",
+			Value:       &c.LicenseSeatUsageWindow,
+			YAML:        "licenseSeatUsageWindow",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Session Duration",
 			Description: "The token expiry duration for browser sessions. Sessions may last longer if they are actively making requests, but this functionality can be disabled via --disable-session-expiry-refresh.",
@@ -3818,6 +4030,17 @@ Write out the current server config as YAML to stdout.`,
 			Value:   &c.AllowWorkspaceRenames,
 			YAML:    "allowWorkspaceRenames",
 		},
+		{
+			Name: "Dormancy Activity Signal",
+			Description: "The agent activity that counts as usage of a workspace, and so keeps it from going dormant. " +
+				"\"connection\" counts any connection, even idle ones left open. \"session\" only counts SSH, terminal and IDE sessions. " +
+				"\"traffic\" only counts connections that transfer a meaningful amount of data. Templates can override it.",
+			Flag:    "dormancy-activity-signal",
+			Env:     "CODER_DORMANCY_ACTIVITY_SIGNAL",
+			Default: string(DormancyActivitySignalConnection),
+			Value:   serpent.EnumOf(&c.DormancyActivitySignal, DormancyActivitySignals...),
+			YAML:    "dormancyActivitySignal",
+		},
 		// Healthcheck Options
 		{
 			Name:        "Health Check Refresh",
@@ -4127,6 +4350,28 @@ Write out the current server config as YAML to stdout.`,
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 			Hidden:      true, // Hidden because most operators should not need to modify this.
 		},
+		{
+			Name: "Notifications: Unused Workspace After",
+			Description: "Notify the owner of a workspace once it has not been used for this long, before it is marked as " +
+				"dormant. A workspace is used while any of its agents reports a session. Set to 0 to disable.",
+			Flag:        "notifications-unused-workspace-after",
+			Env:         "CODER_NOTIFICATIONS_UNUSED_WORKSPACE_AFTER",
+			Value:       &c.Notifications.UnusedWorkspaceAfter,
+			Default:     "0",
+			Group:       &deploymentGroupNotifications,
+			YAML:        "unusedWorkspaceAfter",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Unused Workspace Notify Admins",
+			Description: "Also notify the template admins of the organization about unused workspaces.",
+			Flag:        "notifications-unused-workspace-notify-admins",
+			Env:         "CODER_NOTIFICATIONS_UNUSED_WORKSPACE_NOTIFY_ADMINS",
+			Value:       &c.Notifications.UnusedWorkspaceNotifyAdmins,
+			Default:     "false",
+			Group:       &deploymentGroupNotifications,
+			YAML:        "unusedWorkspaceNotifyAdmins",
+		},
 
 		// Workspace Prebuilds Options
 		{
@@ -4748,6 +4993,64 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "boundary_logs",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Workspace Agent Stats Retention",
+			Description: "How long raw workspace agent stats are retained. Stats are only deleted once they have been rolled up into template usage stats, so insights are not affected. Set to 0 to disable automatic deletion.",
+			Flag:        "workspace-agent-stats-retention",
+			Env:         "CODER_WORKSPACE_AGENT_STATS_RETENTION",
+			Value:       &c.Retention.WorkspaceAgentStats,
+			Default:     "1d",
+			Group:       &deploymentGroupRetention,
+			YAML:        "workspace_agent_stats",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Audit Log Export Bucket",
+			Description: "The S3 bucket to export audit logs to. Leave empty to disable the export.",
+			Flag:        "audit-log-export-bucket",
+			Env:         "CODER_AUDIT_LOG_EXPORT_BUCKET",
+			Value:       &c.AuditLogExport.Bucket,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "bucket",
+		},
+		{
+			Name:        "Audit Log Export Prefix",
+			Description: "The prefix of the keys of the exported objects. Objects are partitioned by dataset and date below it, e.g. coder/audit_logs/year=2024/month=01/day=31/hour=13.jsonl.gz, so lifecycle rules can target each dataset.",
+			Flag:        "audit-log-export-prefix",
+			Env:         "CODER_AUDIT_LOG_EXPORT_PREFIX",
+			Value:       &c.AuditLogExport.Prefix,
+			Default:     "coder",
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "prefix",
+		},
+		{
+			Name:        "Audit Log Export Region",
+			Description: "The region of the bucket. Defaults to the region of the AWS environment.",
+			Flag:        "audit-log-export-region",
+			Env:         "CODER_AUDIT_LOG_EXPORT_REGION",
+			Value:       &c.AuditLogExport.Region,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "region",
+		},
+		{
+			Name:        "Audit Log Export Endpoint",
+			Description: "The endpoint of an S3 compatible service to export to instead of Amazon S3, e.g. https://storage.googleapis.com for Google Cloud Storage with HMAC keys.",
+			Flag:        "audit-log-export-endpoint",
+			Env:         "CODER_AUDIT_LOG_EXPORT_ENDPOINT",
+			Value:       &c.AuditLogExport.Endpoint,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "endpoint",
+		},
+		{
+			Name:        "Audit Log Export Include Stats",
+			Description: "Also export the template usage stats that back the template insights.",
+			Flag:        "audit-log-export-include-stats",
+			Env:         "CODER_AUDIT_LOG_EXPORT_INCLUDE_STATS",
+			Value:       &c.AuditLogExport.IncludeStats,
+			Default:     "false",
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "include_stats",
+		},
 		{
 			Name: "Enable Authorization Recordings",
 			Description: "All api requests will have a header including all authorization calls made during the request. " +
@@ -5021,6 +5324,127 @@ func (c *Client) DeploymentStats(ctx context.Context) (DeploymentStats, error) {
 	return df, json.NewDecoder(res.Body).Decode(&df)
 }
 
+// DatabasePurgeStatus is the outcome of the last run of the background job
+// that purges data past its retention period.
+type DatabasePurgeStatus struct {
+	StartedAt  time.Time `json:"started_at" format:"date-time"`
+	FinishedAt time.Time `json:"finished_at" format:"date-time"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	// RecordsPurged is the number of records deleted by the run, keyed by
+	// record type.
+	RecordsPurged map[string]int64 `json:"records_purged"`
+	// WorkspaceAgentStatsBacklogMillis is how far the oldest raw workspace
+	// agent stat is past its retention period. Stats are purged in batches,
+	// so this shrinks to zero over multiple runs once the purge catches up.
+	WorkspaceAgentStatsBacklogMillis int64 `json:"workspace_agent_stats_backlog_ms"`
+}
+
+// DatabasePurgeStatus returns the status of the last database purge run.
+func (c *Client) DatabasePurgeStatus(ctx context.Context) (DatabasePurgeStatus, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/purge-status", nil)
+	if err != nil {
+		return DatabasePurgeStatus{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return DatabasePurgeStatus{}, ReadBodyAsError(res)
+	}
+
+	var status DatabasePurgeStatus
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
+
+// APIRateLimitConsumption reports how much of the API rate limit the users
+// that sent requests recently have consumed. The counters are local to the
+// replica serving the request.
+type APIRateLimitConsumption struct {
+	// Limit is the number of requests a user may send to an endpoint per
+	// window. Zero means the API rate limit is disabled.
+	Limit         int                           `json:"limit"`
+	WindowSeconds int64                         `json:"window_seconds"`
+	Users         []APIRateLimitUserConsumption `json:"users"`
+}
+
+type APIRateLimitUserConsumption struct {
+	UserID   uuid.UUID `json:"user_id" format:"uuid"`
+	Username string    `json:"username"`
+	// WindowRequests is the number of requests the user sent to their
+	// busiest endpoint in the current window.
+	WindowRequests int `json:"window_requests"`
+	// Consumption is WindowRequests as a fraction of the limit. Values above
+	// 1 mean requests are being rejected.
+	Consumption     float64   `json:"consumption"`
+	TotalRequests   int64     `json:"total_requests"`
+	LimitedRequests int64     `json:"limited_requests"`
+	LastRequestAt   time.Time `json:"last_request_at" format:"date-time"`
+}
+
+// APIRateLimitConsumption returns the consumption of the API rate limit per
+// user.
+func (c *Client) APIRateLimitConsumption(ctx context.Context) (APIRateLimitConsumption, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/rate-limits", nil)
+	if err != nil {
+		return APIRateLimitConsumption{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return APIRateLimitConsumption{}, ReadBodyAsError(res)
+	}
+
+	var consumption APIRateLimitConsumption
+	return consumption, json.NewDecoder(res.Body).Decode(&consumption)
+}
+
+// WatchDeploymentStats streams the deployment stats, starting with the current
+// ones, whenever they are aggregated again. The channel is closed when the
+// context is canceled or the stream ends.
+func (c *Client) WatchDeploymentStats(ctx context.Context) (<-chan DeploymentStats, error) {
+	//nolint:bodyclose
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/stats/watch", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	nextEvent := ServerSentEventReader(ctx, res.Body)
+
+	statsChan := make(chan DeploymentStats, 8)
+	go func() {
+		defer close(statsChan)
+		defer res.Body.Close()
+
+		for {
+			sse, err := nextEvent()
+			if err != nil {
+				return
+			}
+			if sse.Type != ServerSentEventTypeData {
+				continue
+			}
+			b, ok := sse.Data.([]byte)
+			if !ok {
+				return
+			}
+			var stats DeploymentStats
+			if err := json.Unmarshal(b, &stats); err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case statsChan <- stats:
+			}
+		}
+	}()
+
+	return statsChan, nil
+}
+
 type AppearanceConfig struct {
 	ApplicationName string `json:"application_name"`
 	LogoURL         string `json:"logo_url"`
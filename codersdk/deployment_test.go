@@ -67,6 +67,9 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"SCIM API Key": {
 			yaml: true,
 		},
+		"Prometheus Remote Write Bearer Token": {
+			yaml: true,
+		},
 		"External Token Encryption Keys": {
 			yaml: true,
 		},
@@ -76,6 +79,11 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 			flag: true,
 			env:  true,
 		},
+		// Relabel configs are a list of structs, which only YAML can express.
+		"Prometheus Remote Write Relabel Configs": {
+			flag: true,
+			env:  true,
+		},
 		"Provisioner Daemon Pre-shared Key (PSK)": {
 			yaml: true,
 		},
@@ -0,0 +1,134 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// IdleWorkspaceAction is what the idle workspace reaper does with workspaces
+// that have been idle for longer than their template allows.
+type IdleWorkspaceAction string
+
+const (
+	// IdleWorkspaceActionFlag only records the workspace as idle.
+	IdleWorkspaceActionFlag IdleWorkspaceAction = "flag"
+	// IdleWorkspaceActionStop stops the workspace.
+	IdleWorkspaceActionStop IdleWorkspaceAction = "stop"
+)
+
+var IdleWorkspaceActions = []IdleWorkspaceAction{
+	IdleWorkspaceActionFlag,
+	IdleWorkspaceActionStop,
+}
+
+// TemplateIdlePolicy configures the idle workspace reaper for the workspaces
+// of a template. A workspace is idle once none of its agents have reported a
+// session for the idle timeout.
+type TemplateIdlePolicy struct {
+	TemplateID        uuid.UUID           `json:"template_id" format:"uuid"`
+	IdleTimeoutMillis int64               `json:"idle_timeout_ms"`
+	Action            IdleWorkspaceAction `json:"action" enums:"flag,stop"`
+	CreatedAt         time.Time           `json:"created_at" format:"date-time"`
+	UpdatedAt         time.Time           `json:"updated_at" format:"date-time"`
+}
+
+type UpsertTemplateIdlePolicyRequest struct {
+	IdleTimeoutMillis int64 `json:"idle_timeout_ms" validate:"gt=0"`
+	// Action defaults to "flag" when empty.
+	Action IdleWorkspaceAction `json:"action,omitempty" enums:"flag,stop"`
+}
+
+// IdleWorkspace is a running workspace that the idle workspace reaper acts on
+// during its next run.
+type IdleWorkspace struct {
+	WorkspaceID   uuid.UUID           `json:"workspace_id" format:"uuid"`
+	WorkspaceName string              `json:"workspace_name"`
+	OwnerID       uuid.UUID           `json:"owner_id" format:"uuid"`
+	OwnerName     string              `json:"owner_name"`
+	TemplateID    uuid.UUID           `json:"template_id" format:"uuid"`
+	LastActiveAt  time.Time           `json:"last_active_at" format:"date-time"`
+	Action        IdleWorkspaceAction `json:"action" enums:"flag,stop"`
+	// FlaggedAt is set once the reaper has flagged the workspace since it was
+	// last active.
+	FlaggedAt *time.Time `json:"flagged_at,omitempty" format:"date-time"`
+}
+
+// TemplateIdlePolicy returns the idle policy of the template.
+func (c *Client) TemplateIdlePolicy(ctx context.Context, template uuid.UUID) (TemplateIdlePolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/templates/%s/idle-policy", template),
+		nil,
+	)
+	if err != nil {
+		return TemplateIdlePolicy{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return TemplateIdlePolicy{}, ReadBodyAsError(res)
+	}
+	var resp TemplateIdlePolicy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpsertTemplateIdlePolicy creates or updates the idle policy of the template.
+func (c *Client) UpsertTemplateIdlePolicy(ctx context.Context, template uuid.UUID, req UpsertTemplateIdlePolicyRequest) (TemplateIdlePolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/templates/%s/idle-policy", template),
+		req,
+	)
+	if err != nil {
+		return TemplateIdlePolicy{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return TemplateIdlePolicy{}, ReadBodyAsError(res)
+	}
+	var resp TemplateIdlePolicy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteTemplateIdlePolicy removes the idle policy of the template, which
+// stops the reaper from acting on its workspaces.
+func (c *Client) DeleteTemplateIdlePolicy(ctx context.Context, template uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/templates/%s/idle-policy", template),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplateIdleWorkspaces is a dry run of the idle workspace reaper. It lists
+// the workspaces of the template that are idle according to its idle policy,
+// without acting on them.
+func (c *Client) TemplateIdleWorkspaces(ctx context.Context, template uuid.UUID) ([]IdleWorkspace, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/templates/%s/idle-workspaces", template),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []IdleWorkspace
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -21,13 +21,29 @@ const insightsTimeLayout = time.RFC3339
 // smaller insights report within a time range.
 type InsightsReportInterval string
 
-// Days returns the duration of the interval in days.
+// Days returns the duration of the interval in days, or zero for intervals
+// measured in months.
 func (interval InsightsReportInterval) Days() int32 {
 	switch interval {
 	case InsightsReportIntervalDay:
 		return 1
 	case InsightsReportIntervalWeek:
 		return 7
+	case InsightsReportIntervalMonth:
+		return 0
+	default:
+		panic("developer error: unsupported report interval")
+	}
+}
+
+// Months returns the duration of the interval in months, or zero for
+// intervals measured in days.
+func (interval InsightsReportInterval) Months() int32 {
+	switch interval {
+	case InsightsReportIntervalDay, InsightsReportIntervalWeek:
+		return 0
+	case InsightsReportIntervalMonth:
+		return 1
 	default:
 		panic("developer error: unsupported report interval")
 	}
@@ -35,8 +51,9 @@ func (interval InsightsReportInterval) Days() int32 {
 
 // InsightsReportInterval enums.
 const (
-	InsightsReportIntervalDay  InsightsReportInterval = "day"
-	InsightsReportIntervalWeek InsightsReportInterval = "week"
+	InsightsReportIntervalDay   InsightsReportInterval = "day"
+	InsightsReportIntervalWeek  InsightsReportInterval = "week"
+	InsightsReportIntervalMonth InsightsReportInterval = "month"
 )
 
 // TemplateInsightsSection defines the section to be included in the template insights response.
@@ -48,6 +65,24 @@ const (
 	TemplateInsightsSectionReport          TemplateInsightsSection = "report"
 )
 
+// TemplateInsightsField defines a field of the template insights report, so
+// that the aggregates behind the fields that are not needed can be skipped.
+type TemplateInsightsField string
+
+// TemplateInsightsField enums.
+const (
+	TemplateInsightsFieldActiveUsers     TemplateInsightsField = "active_users"
+	TemplateInsightsFieldAppsUsage       TemplateInsightsField = "apps_usage"
+	TemplateInsightsFieldParametersUsage TemplateInsightsField = "parameters_usage"
+)
+
+// TemplateInsightsFields are the fields of the template insights report.
+var TemplateInsightsFields = []TemplateInsightsField{
+	TemplateInsightsFieldActiveUsers,
+	TemplateInsightsFieldAppsUsage,
+	TemplateInsightsFieldParametersUsage,
+}
+
 // UserLatencyInsightsResponse is the response from the user latency insights
 // endpoint.
 type UserLatencyInsightsResponse struct {
@@ -166,6 +201,751 @@ func (c *Client) UserActivityInsights(ctx context.Context, req UserActivityInsig
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
 
+// DeploymentInsightsResponse is the response from the deployment insights
+// endpoint.
+type DeploymentInsightsResponse struct {
+	Report DeploymentInsightsReport `json:"report"`
+}
+
+// DeploymentInsightsReport is the report from the deployment insights
+// endpoint, aggregated across all templates and organizations.
+type DeploymentInsightsReport struct {
+	StartTime   time.Time                   `json:"start_time" format:"date-time"`
+	EndTime     time.Time                   `json:"end_time" format:"date-time"`
+	ActiveUsers int64                       `json:"active_users" example:"14"`
+	Builds      DeploymentBuildInsights     `json:"builds"`
+	Bandwidth   DeploymentBandwidthInsights `json:"bandwidth"`
+	LatencyMS   ConnectionLatency           `json:"latency_ms"`
+}
+
+// DeploymentBuildInsights shows the number of completed workspace builds.
+type DeploymentBuildInsights struct {
+	Total  int64 `json:"total" example:"120"`
+	Failed int64 `json:"failed" example:"3"`
+}
+
+// DeploymentBandwidthInsights shows the bytes sent and received by workspace
+// agents. Raw agent stats are only retained until they are rolled up, so the
+// bandwidth only covers the period since StartTime, which may be later than
+// the start of the report.
+type DeploymentBandwidthInsights struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	RxBytes   int64     `json:"rx_bytes" example:"1048576"`
+	TxBytes   int64     `json:"tx_bytes" example:"524288"`
+}
+
+type DeploymentInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+}
+
+func (c *Client) DeploymentInsights(ctx context.Context, req DeploymentInsightsRequest) (DeploymentInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+
+	reqURL := fmt.Sprintf("/api/v2/insights/deployment?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return DeploymentInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return DeploymentInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result DeploymentInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// RegionLatencyInsightsResponse is the response from the region latency
+// insights endpoint.
+type RegionLatencyInsightsResponse struct {
+	Report RegionLatencyInsightsReport `json:"report"`
+}
+
+// RegionLatencyInsightsReport shows the connection latency reported by
+// workspace agents, grouped by the DERP region they prefer to relay through.
+// It is computed from raw agent stats, so it only covers the period those
+// stats are retained for.
+type RegionLatencyInsightsReport struct {
+	StartTime time.Time              `json:"start_time" format:"date-time"`
+	EndTime   time.Time              `json:"end_time" format:"date-time"`
+	Regions   []RegionLatencyInsight `json:"regions"`
+}
+
+// RegionLatencyInsight shows the connection latency of the workspace agents
+// that prefer a DERP region. Agents whose preferred region is unknown, for
+// example because they have since disconnected, are reported under region 0.
+type RegionLatencyInsight struct {
+	RegionID   int                        `json:"region_id" example:"999"`
+	RegionName string                     `json:"region_name" example:"Coder"`
+	Agents     int64                      `json:"agents" example:"12"`
+	LatencyMS  ConnectionLatencyQuantiles `json:"latency_ms"`
+}
+
+// ConnectionLatencyQuantiles shows the distribution of connection latencies.
+type ConnectionLatencyQuantiles struct {
+	P50 float64 `json:"p50" example:"31.312"`
+	P90 float64 `json:"p90" example:"84.127"`
+	P95 float64 `json:"p95" example:"119.832"`
+	P99 float64 `json:"p99" example:"203.511"`
+}
+
+type RegionLatencyInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+}
+
+func (c *Client) RegionLatencyInsights(ctx context.Context, req RegionLatencyInsightsRequest) (RegionLatencyInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+
+	reqURL := fmt.Sprintf("/api/v2/insights/region-latency?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return RegionLatencyInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RegionLatencyInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result RegionLatencyInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// ClientRegionLatencyInsightsResponse is the response from the client region
+// latency insights endpoint.
+type ClientRegionLatencyInsightsResponse struct {
+	Report ClientRegionLatencyInsightsReport `json:"report"`
+}
+
+// ClientRegionLatencyInsightsReport shows the connection latency reported by
+// workspace agents, grouped by the region and network of the clients
+// connecting to them. Client networks are only recorded when the deployment is
+// configured with the request headers to derive them from. Like the region
+// latency report, it only covers the period raw agent stats are retained for.
+type ClientRegionLatencyInsightsReport struct {
+	StartTime time.Time                    `json:"start_time" format:"date-time"`
+	EndTime   time.Time                    `json:"end_time" format:"date-time"`
+	Regions   []ClientRegionLatencyInsight `json:"regions"`
+}
+
+// ClientRegionLatencyInsight shows the connection latency of the workspace
+// agents that clients in a region and network connected to. The region is
+// empty and the ASN is 0 if they are unknown.
+type ClientRegionLatencyInsight struct {
+	Region    string                     `json:"region" example:"DE"`
+	ASN       int64                      `json:"asn" example:"3320"`
+	Users     int64                      `json:"users" example:"4"`
+	Agents    int64                      `json:"agents" example:"6"`
+	LatencyMS ConnectionLatencyQuantiles `json:"latency_ms"`
+}
+
+type ClientRegionLatencyInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+}
+
+func (c *Client) ClientRegionLatencyInsights(ctx context.Context, req ClientRegionLatencyInsightsRequest) (ClientRegionLatencyInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+
+	reqURL := fmt.Sprintf("/api/v2/insights/client-region-latency?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return ClientRegionLatencyInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ClientRegionLatencyInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result ClientRegionLatencyInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// UserBandwidthInsightsResponse is the response from the user bandwidth
+// insights endpoint.
+type UserBandwidthInsightsResponse struct {
+	Report UserBandwidthInsightsReport `json:"report"`
+}
+
+// UserBandwidthInsightsReport shows the bytes transferred by the workspace
+// agents of each user, heaviest users first. It is computed from raw agent
+// stats, so it only covers the period those stats are retained for.
+type UserBandwidthInsightsReport struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+	// Count is the total number of users with bandwidth in the time range,
+	// across all pages.
+	Count int64           `json:"count" example:"42"`
+	Users []UserBandwidth `json:"users"`
+}
+
+// UserBandwidth shows the bytes received and sent by the workspace agents of
+// a user, in total and per template.
+type UserBandwidth struct {
+	UserID    uuid.UUID           `json:"user_id" format:"uuid"`
+	Username  string              `json:"username"`
+	AvatarURL string              `json:"avatar_url" format:"uri"`
+	RxBytes   int64               `json:"rx_bytes" example:"4182593536"`
+	TxBytes   int64               `json:"tx_bytes" example:"817889280"`
+	Templates []TemplateBandwidth `json:"templates"`
+}
+
+// TemplateBandwidth shows the bytes received and sent by the workspace agents
+// of a user in workspaces based on a template.
+type TemplateBandwidth struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	RxBytes    int64     `json:"rx_bytes" example:"4182593536"`
+	TxBytes    int64     `json:"tx_bytes" example:"817889280"`
+}
+
+type UserBandwidthInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+	// Pagination pages through users, AfterID is not supported.
+	Pagination
+}
+
+func (c *Client) UserBandwidthInsights(ctx context.Context, req UserBandwidthInsightsRequest) (UserBandwidthInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/user-bandwidth?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil, req.Pagination.asRequestOption())
+	if err != nil {
+		return UserBandwidthInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return UserBandwidthInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result UserBandwidthInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// UsageHeatmapInsightsResponse is the response from the usage heatmap
+// insights endpoint.
+type UsageHeatmapInsightsResponse struct {
+	Report UsageHeatmapInsightsReport `json:"report"`
+}
+
+// UsageHeatmapInsightsReport shows the number of active users in each hour of
+// the week, in the time zone of the start time. The heatmaps are indexed by
+// day of the week, starting on Sunday, and then by hour of the day.
+type UsageHeatmapInsightsReport struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+	// AvgActiveUsers is the average number of active users in each hour of
+	// the week across the time range.
+	AvgActiveUsers [][]float64 `json:"avg_active_users"`
+	// MaxActiveUsers is the highest number of active users seen in each
+	// hour of the week.
+	MaxActiveUsers [][]int64 `json:"max_active_users"`
+}
+
+type UsageHeatmapInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+	// TemplateIDs limits the heatmap to the given templates, the heatmap is
+	// deployment-wide when empty.
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+	// Timezone is the IANA timezone name to bucket hours in, so that they
+	// follow daylight saving time changes. Defaults to the UTC offset of the
+	// start time.
+	Timezone string `json:"timezone,omitempty" example:"America/St_Johns"`
+}
+
+func (c *Client) UsageHeatmapInsights(ctx context.Context, req UsageHeatmapInsightsRequest) (UsageHeatmapInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+	if req.Timezone != "" {
+		qp.Add("timezone", req.Timezone)
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/usage-heatmap?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return UsageHeatmapInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return UsageHeatmapInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result UsageHeatmapInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// TemplateComparisonInsightsResponse is the response from the template
+// comparison insights endpoint.
+type TemplateComparisonInsightsResponse struct {
+	Report TemplateComparisonInsightsReport `json:"report"`
+}
+
+// TemplateComparisonInsightsReport shows the health of a set of templates side
+// by side, in the order they were requested.
+type TemplateComparisonInsightsReport struct {
+	StartTime time.Time            `json:"start_time" format:"date-time"`
+	EndTime   time.Time            `json:"end_time" format:"date-time"`
+	Templates []TemplateComparison `json:"templates"`
+}
+
+// TemplateComparison shows the build, usage and latency metrics of a
+// template. Metrics that can't be computed, for example the build success
+// rate of a template without builds, are -1.
+type TemplateComparison struct {
+	TemplateID          uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName        string    `json:"template_name"`
+	TemplateDisplayName string    `json:"template_display_name"`
+	OrganizationID      uuid.UUID `json:"organization_id" format:"uuid"`
+	TotalBuilds         int64     `json:"total_builds" example:"212"`
+	FailedBuilds        int64     `json:"failed_builds" example:"7"`
+	// BuildSuccessRate is the share of completed builds that didn't fail,
+	// between 0 and 1. Canceled builds are not counted as failures.
+	BuildSuccessRate float64 `json:"build_success_rate" example:"0.967"`
+	// MedianBuildSeconds is the median duration of successful workspace
+	// starts.
+	MedianBuildSeconds           float64           `json:"median_build_seconds" example:"48.5"`
+	ActiveUsers                  int64             `json:"active_users" example:"18"`
+	WorkspaceConnectionLatencyMS ConnectionLatency `json:"workspace_connection_latency_ms"`
+}
+
+type TemplateComparisonInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) TemplateComparisonInsights(ctx context.Context, req TemplateComparisonInsightsRequest) (TemplateComparisonInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	var templateIDs []string
+	for _, id := range req.TemplateIDs {
+		templateIDs = append(templateIDs, id.String())
+	}
+	qp.Add("template_ids", strings.Join(templateIDs, ","))
+
+	reqURL := fmt.Sprintf("/api/v2/insights/template-comparison?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return TemplateComparisonInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TemplateComparisonInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result TemplateComparisonInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// CostInsightsGroupBy defines how the entries of the cost insights report are
+// grouped.
+type CostInsightsGroupBy string
+
+// CostInsightsGroupBy enums.
+const (
+	CostInsightsGroupByWorkspace    CostInsightsGroupBy = "workspace"
+	CostInsightsGroupByUser         CostInsightsGroupBy = "user"
+	CostInsightsGroupByOrganization CostInsightsGroupBy = "organization"
+)
+
+// CostInsightsResponse is the response from the cost insights endpoint.
+type CostInsightsResponse struct {
+	Report CostInsightsReport `json:"report"`
+}
+
+// CostInsightsReport shows the estimated cost of running workspaces. Costs
+// are in the same unit as the daily cost of template resources, and accrue
+// for every minute a workspace reported agent stats.
+type CostInsightsReport struct {
+	StartTime time.Time           `json:"start_time" format:"date-time"`
+	EndTime   time.Time           `json:"end_time" format:"date-time"`
+	GroupBy   CostInsightsGroupBy `json:"group_by" enums:"workspace,user,organization"`
+	TotalCost float64             `json:"total_cost" example:"184.5"`
+	Entries   []CostInsight       `json:"entries"`
+}
+
+// CostInsight is the estimated cost of a workspace, user or organization,
+// depending on how the report is grouped. The name is empty if the workspace,
+// user or organization no longer exists.
+type CostInsight struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	RunningSeconds int64     `json:"running_seconds" example:"86400"`
+	Cost           float64   `json:"cost" example:"24"`
+}
+
+type CostInsightsRequest struct {
+	StartTime time.Time           `json:"start_time" format:"date-time"`
+	EndTime   time.Time           `json:"end_time" format:"date-time"`
+	GroupBy   CostInsightsGroupBy `json:"group_by"`
+}
+
+func (c *Client) CostInsights(ctx context.Context, req CostInsightsRequest) (CostInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if req.GroupBy != "" {
+		qp.Add("group_by", string(req.GroupBy))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/costs?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return CostInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return CostInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result CostInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// WorkspaceHealthStatus summarizes the health score of a workspace.
+type WorkspaceHealthStatus string
+
+// WorkspaceHealthStatus enums.
+const (
+	WorkspaceHealthStatusHealthy   WorkspaceHealthStatus = "healthy"
+	WorkspaceHealthStatusDegraded  WorkspaceHealthStatus = "degraded"
+	WorkspaceHealthStatusUnhealthy WorkspaceHealthStatus = "unhealthy"
+)
+
+// WorkspaceHealthInsightsResponse is the response from the workspace health
+// insights endpoint.
+type WorkspaceHealthInsightsResponse struct {
+	Report WorkspaceHealthInsightsReport `json:"report"`
+}
+
+// WorkspaceHealthInsightsReport scores the health of running workspaces,
+// least healthy first.
+type WorkspaceHealthInsightsReport struct {
+	GeneratedAt time.Time                `json:"generated_at" format:"date-time"`
+	TemplateIDs []uuid.UUID              `json:"template_ids" format:"uuid"`
+	Workspaces  []WorkspaceHealthInsight `json:"workspaces"`
+}
+
+// WorkspaceHealthInsight is the composite health score of a workspace, from 0
+// to 100. The score is a weighted average of the component scores: agent
+// connectivity weighs 40%, and latency, app health and builds weigh 20% each.
+type WorkspaceHealthInsight struct {
+	WorkspaceID   uuid.UUID             `json:"workspace_id" format:"uuid"`
+	WorkspaceName string                `json:"workspace_name"`
+	OwnerID       uuid.UUID             `json:"owner_id" format:"uuid"`
+	OwnerUsername string                `json:"owner_username"`
+	TemplateID    uuid.UUID             `json:"template_id" format:"uuid"`
+	TemplateName  string                `json:"template_name"`
+	Score         int                   `json:"score" example:"85"`
+	Status        WorkspaceHealthStatus `json:"status" enums:"healthy,degraded,unhealthy"`
+
+	Connectivity WorkspaceHealthConnectivity `json:"connectivity"`
+	Latency      WorkspaceHealthLatency      `json:"latency"`
+	Apps         WorkspaceHealthApps         `json:"apps"`
+	Builds       WorkspaceHealthBuilds       `json:"builds"`
+}
+
+// WorkspaceHealthConnectivity scores the share of agents in the latest build
+// that are connected. A workspace without agents scores 100, unless its latest
+// build failed.
+type WorkspaceHealthConnectivity struct {
+	Score           int `json:"score" example:"100"`
+	AgentsConnected int `json:"agents_connected" example:"1"`
+	AgentsTotal     int `json:"agents_total" example:"1"`
+}
+
+// WorkspaceHealthLatency scores the connection latency of the last hour
+// against the baseline of the preceding week. Latencies are -1 if no latency
+// was reported, and the score is 100 if there is nothing to compare.
+type WorkspaceHealthLatency struct {
+	Score             int     `json:"score" example:"100"`
+	LatencyMS         float64 `json:"latency_ms" example:"25"`
+	BaselineLatencyMS float64 `json:"baseline_latency_ms" example:"22"`
+}
+
+// WorkspaceHealthApps scores the share of apps with health checks that are
+// healthy. Initializing apps count as not healthy yet.
+type WorkspaceHealthApps struct {
+	Score        int `json:"score" example:"100"`
+	Healthy      int `json:"healthy" example:"2"`
+	Initializing int `json:"initializing" example:"0"`
+	Unhealthy    int `json:"unhealthy" example:"0"`
+}
+
+// WorkspaceHealthBuilds scores the share of builds of the last week that did
+// not fail. A failed latest build always scores 0.
+type WorkspaceHealthBuilds struct {
+	Score             int   `json:"score" example:"100"`
+	LatestBuildFailed bool  `json:"latest_build_failed"`
+	Builds            int64 `json:"builds" example:"3"`
+	FailedBuilds      int64 `json:"failed_builds" example:"0"`
+}
+
+type WorkspaceHealthInsightsRequest struct {
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) WorkspaceHealthInsights(ctx context.Context, req WorkspaceHealthInsightsRequest) (WorkspaceHealthInsightsResponse, error) {
+	qp := url.Values{}
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/workspace-health?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return WorkspaceHealthInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return WorkspaceHealthInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result WorkspaceHealthInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// PrebuildInsightsResponse is the response from the prebuild insights
+// endpoint.
+type PrebuildInsightsResponse struct {
+	Report PrebuildInsightsReport `json:"report"`
+}
+
+// PrebuildInsightsReport shows how well the prebuilt workspace pools serve
+// workspace creations, so that pool sizes can be tuned. The hit rate is the
+// share of workspaces created from a preset with prebuilds that were claimed
+// from the pool, and is 0 if no such workspace was created.
+type PrebuildInsightsReport struct {
+	StartTime   time.Time               `json:"start_time" format:"date-time"`
+	EndTime     time.Time               `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID             `json:"template_ids" format:"uuid"`
+	Claims      int64                   `json:"claims" example:"42"`
+	Misses      int64                   `json:"misses" example:"8"`
+	HitRate     float64                 `json:"hit_rate" example:"0.84"`
+	Presets     []PrebuildPresetInsight `json:"presets"`
+}
+
+// PrebuildPresetInsight shows the utilization of the prebuilt workspace pool
+// of a template preset. The claim latency is how long users waited for a
+// claimed prebuild to be ready, and the idle time how long a prebuild waited
+// in the pool before being claimed. Both are -1 if there were no claims.
+type PrebuildPresetInsight struct {
+	TemplateID            uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName          string    `json:"template_name"`
+	PresetName            string    `json:"preset_name"`
+	PrebuildsCreated      int64     `json:"prebuilds_created" example:"50"`
+	Claims                int64     `json:"claims" example:"42"`
+	Misses                int64     `json:"misses" example:"8"`
+	HitRate               float64   `json:"hit_rate" example:"0.84"`
+	ClaimLatencySeconds50 float64   `json:"claim_latency_seconds_50" example:"12.5"`
+	ClaimLatencySeconds95 float64   `json:"claim_latency_seconds_95" example:"30"`
+	IdleSeconds50         float64   `json:"idle_seconds_50" example:"3600"`
+	IdleSeconds95         float64   `json:"idle_seconds_95" example:"43200"`
+}
+
+type PrebuildInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) PrebuildInsights(ctx context.Context, req PrebuildInsightsRequest) (PrebuildInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/prebuilds?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return PrebuildInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return PrebuildInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result PrebuildInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// AccessMethodInsightsResponse is the response from the access method
+// insights endpoint.
+type AccessMethodInsightsResponse struct {
+	Report AccessMethodInsightsReport `json:"report"`
+}
+
+// AccessMethodInsightsReport shows how session time is shared between the
+// access methods of workspaces, per template and over time.
+type AccessMethodInsightsReport struct {
+	StartTime   time.Time                     `json:"start_time" format:"date-time"`
+	EndTime     time.Time                     `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID                   `json:"template_ids" format:"uuid"`
+	Interval    InsightsReportInterval        `json:"interval" example:"week"`
+	Templates   []TemplateAccessMethodInsight `json:"templates"`
+}
+
+// TemplateAccessMethodInsight shows the session time per access method of a
+// template, over the whole report and per interval.
+type TemplateAccessMethodInsight struct {
+	TemplateID    uuid.UUID                    `json:"template_id" format:"uuid"`
+	TemplateName  string                       `json:"template_name"`
+	AccessMethods []AccessMethodUsage          `json:"access_methods"`
+	Intervals     []AccessMethodIntervalReport `json:"intervals"`
+}
+
+// AccessMethodIntervalReport shows the session time per access method of a
+// template in an interval.
+type AccessMethodIntervalReport struct {
+	StartTime     time.Time           `json:"start_time" format:"date-time"`
+	EndTime       time.Time           `json:"end_time" format:"date-time"`
+	AccessMethods []AccessMethodUsage `json:"access_methods"`
+}
+
+// AccessMethodUsage is the session time of an access method. The slug and
+// display name match the builtin apps of the template insights. The share is
+// the fraction of the session time of all access methods, and is 0 if there
+// was no session time.
+type AccessMethodUsage struct {
+	Slug        string  `json:"slug" example:"vscode"`
+	DisplayName string  `json:"display_name" example:"Visual Studio Code"`
+	Seconds     int64   `json:"seconds" example:"80500"`
+	Share       float64 `json:"share" example:"0.65"`
+}
+
+type AccessMethodInsightsRequest struct {
+	StartTime   time.Time              `json:"start_time" format:"date-time"`
+	EndTime     time.Time              `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID            `json:"template_ids" format:"uuid"`
+	Interval    InsightsReportInterval `json:"interval" example:"day"`
+	// Timezone is the IANA timezone name to bucket intervals in, so that they
+	// follow daylight saving time changes. Defaults to the UTC offset of the
+	// start time.
+	Timezone string `json:"timezone,omitempty" example:"America/St_Johns"`
+}
+
+func (c *Client) AccessMethodInsights(ctx context.Context, req AccessMethodInsightsRequest) (AccessMethodInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp.Add("template_ids", strings.Join(templateIDs, ","))
+	}
+	if req.Interval != "" {
+		qp.Add("interval", string(req.Interval))
+	}
+	if req.Timezone != "" {
+		qp.Add("timezone", req.Timezone)
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/access-methods?%s", qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return AccessMethodInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AccessMethodInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result AccessMethodInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// OrganizationInsightsResponse is the response from the organization insights
+// endpoint.
+type OrganizationInsightsResponse struct {
+	Report OrganizationInsightsReport `json:"report"`
+}
+
+// OrganizationInsightsReport is the report from the organization insights
+// endpoint, aggregated across the templates and workspaces of an
+// organization.
+type OrganizationInsightsReport struct {
+	OrganizationID uuid.UUID                     `json:"organization_id" format:"uuid"`
+	StartTime      time.Time                     `json:"start_time" format:"date-time"`
+	EndTime        time.Time                     `json:"end_time" format:"date-time"`
+	ActiveUsers    int64                         `json:"active_users" example:"14"`
+	Workspaces     OrganizationWorkspaceInsights `json:"workspaces"`
+	Builds         DeploymentBuildInsights       `json:"builds"`
+	Bandwidth      DeploymentBandwidthInsights   `json:"bandwidth"`
+	LatencyMS      ConnectionLatency             `json:"latency_ms"`
+}
+
+// OrganizationWorkspaceInsights shows the current number of workspaces in the
+// organization. Unlike the rest of the report, it is not limited to the
+// period of the report.
+type OrganizationWorkspaceInsights struct {
+	Total   int64 `json:"total" example:"42"`
+	Running int64 `json:"running" example:"17"`
+}
+
+type OrganizationInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+}
+
+func (c *Client) OrganizationInsights(ctx context.Context, organizationID uuid.UUID, req OrganizationInsightsRequest) (OrganizationInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+
+	reqURL := fmt.Sprintf("/api/v2/organizations/%s/insights?%s", organizationID, qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return OrganizationInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return OrganizationInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result OrganizationInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
 // TemplateInsightsResponse is the response from the template insights endpoint.
 type TemplateInsightsResponse struct {
 	Report          *TemplateInsightsReport          `json:"report,omitempty"`
@@ -173,13 +953,15 @@ type TemplateInsightsResponse struct {
 }
 
 // TemplateInsightsReport is the report from the template insights endpoint.
+//
+// The fields of the report that were not requested are omitted.
 type TemplateInsightsReport struct {
 	StartTime       time.Time                `json:"start_time" format:"date-time"`
 	EndTime         time.Time                `json:"end_time" format:"date-time"`
 	TemplateIDs     []uuid.UUID              `json:"template_ids" format:"uuid"`
-	ActiveUsers     int64                    `json:"active_users" example:"22"`
-	AppsUsage       []TemplateAppUsage       `json:"apps_usage"`
-	ParametersUsage []TemplateParameterUsage `json:"parameters_usage"`
+	ActiveUsers     *int64                   `json:"active_users,omitempty" example:"22"`
+	AppsUsage       []TemplateAppUsage       `json:"apps_usage,omitzero"`
+	ParametersUsage []TemplateParameterUsage `json:"parameters_usage,omitzero"`
 }
 
 // TemplateInsightsIntervalReport is the report from the template insights
@@ -246,6 +1028,13 @@ type TemplateInsightsRequest struct {
 	TemplateIDs []uuid.UUID               `json:"template_ids" format:"uuid"`
 	Interval    InsightsReportInterval    `json:"interval" example:"day"`
 	Sections    []TemplateInsightsSection `json:"sections" example:"report"`
+	// Fields of the report to include, all of them when empty. Fields that
+	// are not included are omitted from the report.
+	Fields []TemplateInsightsField `json:"fields" example:"active_users"`
+	// Timezone is the IANA timezone name to bucket intervals in, so that they
+	// follow daylight saving time changes. Defaults to the UTC offset of the
+	// start time.
+	Timezone string `json:"timezone,omitempty" example:"America/St_Johns"`
 }
 
 func (c *Client) TemplateInsights(ctx context.Context, req TemplateInsightsRequest) (TemplateInsightsResponse, error) {
@@ -262,6 +1051,9 @@ func (c *Client) TemplateInsights(ctx context.Context, req TemplateInsightsReque
 	if req.Interval != "" {
 		qp.Add("interval", string(req.Interval))
 	}
+	if req.Timezone != "" {
+		qp.Add("timezone", req.Timezone)
+	}
 	if len(req.Sections) > 0 {
 		var sections []string
 		for _, sec := range req.Sections {
@@ -269,6 +1061,13 @@ func (c *Client) TemplateInsights(ctx context.Context, req TemplateInsightsReque
 		}
 		qp.Add("sections", strings.Join(sections, ","))
 	}
+	if len(req.Fields) > 0 {
+		var fields []string
+		for _, field := range req.Fields {
+			fields = append(fields, string(field))
+		}
+		qp.Add("fields", strings.Join(fields, ","))
+	}
 
 	reqURL := fmt.Sprintf("/api/v2/insights/templates?%s", qp.Encode())
 	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
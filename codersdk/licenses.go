@@ -23,6 +23,22 @@ type AddLicenseRequest struct {
 	License string `json:"license" validate:"required"`
 }
 
+// LicenseSeatUsage describes how the user limit of the license is consumed.
+type LicenseSeatUsage struct {
+	// UsageWindowMillis is the window of workspace usage used to count seats.
+	// Zero means every active user consumes a seat.
+	UsageWindowMillis int64 `json:"usage_window_ms"`
+	// ActiveUsers is the number of active users in the deployment.
+	ActiveUsers int64 `json:"active_users"`
+	// UsageUsers is the number of active users that used a workspace within
+	// the usage window. It is only set when the usage window is non-zero.
+	UsageUsers *int64 `json:"usage_users,omitempty"`
+	// Seats is the number of seats counted against the user limit.
+	Seats int64 `json:"seats"`
+	// Limit is the user limit of the license, if there is one.
+	Limit *int64 `json:"limit,omitempty"`
+}
+
 type License struct {
 	ID         int32     `json:"id"`
 	UUID       uuid.UUID `json:"uuid" format:"uuid"`
@@ -137,3 +153,16 @@ func (c *Client) DeleteLicense(ctx context.Context, id int32) error {
 	}
 	return nil
 }
+
+func (c *Client) LicenseSeatUsage(ctx context.Context) (LicenseSeatUsage, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/licenses/seat-usage", nil)
+	if err != nil {
+		return LicenseSeatUsage{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return LicenseSeatUsage{}, ReadBodyAsError(res)
+	}
+	var usage LicenseSeatUsage
+	return usage, json.NewDecoder(res.Body).Decode(&usage)
+}
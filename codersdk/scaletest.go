@@ -0,0 +1,96 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// ScaletestResult is the summary of a scaletest run against the deployment.
+type ScaletestResult struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// CreatedBy is the user that uploaded the result.
+	CreatedBy uuid.UUID `json:"created_by" format:"uuid"`
+	// Name is the name of the test, e.g. the scaletest command that was run.
+	Name      string `json:"name"`
+	TotalRuns int    `json:"total_runs"`
+	TotalPass int    `json:"total_pass"`
+	TotalFail int    `json:"total_fail"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+	// Summary is the result of the scaletest harness without the results of
+	// individual runs. Its format is defined by the scaletest harness.
+	Summary json.RawMessage `json:"summary" swaggertype:"object"`
+}
+
+type UploadScaletestResultRequest struct {
+	Name      string          `json:"name" validate:"required"`
+	TotalRuns int             `json:"total_runs" validate:"min=0"`
+	TotalPass int             `json:"total_pass" validate:"min=0"`
+	TotalFail int             `json:"total_fail" validate:"min=0"`
+	ElapsedMS int64           `json:"elapsed_ms" validate:"min=0"`
+	Summary   json.RawMessage `json:"summary" validate:"required" swaggertype:"object"`
+}
+
+type ScaletestResultsRequest struct {
+	Pagination
+	// Name filters the results by the name of the test.
+	Name string `json:"name,omitempty"`
+}
+
+// UploadScaletestResult stores the summary of a scaletest run, so that the
+// results of past runs can be compared with each other.
+func (c *Client) UploadScaletestResult(ctx context.Context, req UploadScaletestResultRequest) (ScaletestResult, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/scaletest/results", req)
+	if err != nil {
+		return ScaletestResult{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return ScaletestResult{}, ReadBodyAsError(res)
+	}
+
+	var result ScaletestResult
+	return result, json.NewDecoder(res.Body).Decode(&result)
+}
+
+// ScaletestResults lists the stored scaletest results, newest first.
+func (c *Client) ScaletestResults(ctx context.Context, req ScaletestResultsRequest) ([]ScaletestResult, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/scaletest/results", nil,
+		req.Pagination.asRequestOption(),
+		WithQueryParam("name", req.Name),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var results []ScaletestResult
+	return results, json.NewDecoder(res.Body).Decode(&results)
+}
+
+// ScaletestResult returns a stored scaletest result by ID.
+func (c *Client) ScaletestResult(ctx context.Context, id uuid.UUID) (ScaletestResult, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/scaletest/results/%s", id), nil)
+	if err != nil {
+		return ScaletestResult{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ScaletestResult{}, ReadBodyAsError(res)
+	}
+
+	var result ScaletestResult
+	return result, json.NewDecoder(res.Body).Decode(&result)
+}
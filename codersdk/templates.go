@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -69,6 +70,40 @@ type Template struct {
 	// DisableModuleCache disables the use of cached Terraform modules during
 	// provisioning.
 	DisableModuleCache bool `json:"disable_module_cache"`
+
+	// DormancyActivitySignal is the agent activity that keeps workspaces of
+	// the template from going dormant. Empty if the deployment default is
+	// used.
+	DormancyActivitySignal DormancyActivitySignal `json:"dormancy_activity_signal" enums:",connection,session,traffic"`
+}
+
+// DormancyActivitySignal determines which agent activity counts as usage of a
+// workspace, and so keeps it from going dormant.
+type DormancyActivitySignal string
+
+const (
+	// DormancyActivitySignalConnection counts any connection to the
+	// workspace, even idle ones.
+	DormancyActivitySignalConnection DormancyActivitySignal = "connection"
+	// DormancyActivitySignalSession counts SSH, terminal, IDE and JetBrains
+	// sessions.
+	DormancyActivitySignalSession DormancyActivitySignal = "session"
+	// DormancyActivitySignalTraffic counts connections that transfer a
+	// meaningful amount of data, so that idle connections left open do not
+	// keep workspaces alive.
+	DormancyActivitySignalTraffic DormancyActivitySignal = "traffic"
+)
+
+// DormancyActivitySignals lists the supported DormancyActivitySignal values.
+var DormancyActivitySignals = []string{
+	string(DormancyActivitySignalConnection),
+	string(DormancyActivitySignalSession),
+	string(DormancyActivitySignalTraffic),
+}
+
+// Valid returns whether the signal is supported. An empty signal is not.
+func (s DormancyActivitySignal) Valid() bool {
+	return slices.Contains(DormancyActivitySignals, string(s))
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -274,6 +309,11 @@ type UpdateTemplateMeta struct {
 	// DisableModuleCache disables the using of cached Terraform modules during
 	// provisioning. It is recommended not to disable this.
 	DisableModuleCache *bool `json:"disable_module_cache,omitempty"`
+	// DormancyActivitySignal overrides the deployment wide agent activity
+	// that keeps workspaces of the template from going dormant. Pass an
+	// empty string to use the deployment default. This is an enterprise
+	// feature, like TimeTilDormantMillis.
+	DormancyActivitySignal *DormancyActivitySignal `json:"dormancy_activity_signal,omitempty" enums:",connection,session,traffic"`
 }
 
 type TemplateExample struct {
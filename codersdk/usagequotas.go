@@ -0,0 +1,152 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// UsageQuotaMetric is the unit a usage quota is expressed in.
+type UsageQuotaMetric string
+
+const (
+	// UsageQuotaMetricConnectionHours counts every session separately, so
+	// two concurrent SSH sessions for an hour consume two hours.
+	UsageQuotaMetricConnectionHours UsageQuotaMetric = "connection_hours"
+	// UsageQuotaMetricActiveHours counts the time the user had at least one
+	// session to any of their workspaces.
+	UsageQuotaMetricActiveHours UsageQuotaMetric = "active_hours"
+)
+
+var UsageQuotaMetrics = []UsageQuotaMetric{
+	UsageQuotaMetricConnectionHours,
+	UsageQuotaMetricActiveHours,
+}
+
+// UsageQuotaAction is what happens once a user exceeds their allowance.
+type UsageQuotaAction string
+
+const (
+	// UsageQuotaActionReport only reports the quota as exceeded.
+	UsageQuotaActionReport UsageQuotaAction = "report"
+	// UsageQuotaActionBlockStart additionally refuses to start workspaces
+	// until the next period.
+	UsageQuotaActionBlockStart UsageQuotaAction = "block_start"
+)
+
+var UsageQuotaActions = []UsageQuotaAction{
+	UsageQuotaActionReport,
+	UsageQuotaActionBlockStart,
+}
+
+// GroupUsageQuota is the monthly allowance granted to each member of a group
+// for one metric.
+type GroupUsageQuota struct {
+	GroupID      uuid.UUID        `json:"group_id" format:"uuid"`
+	Metric       UsageQuotaMetric `json:"metric" enums:"connection_hours,active_hours"`
+	MonthlyHours int32            `json:"monthly_hours"`
+	Action       UsageQuotaAction `json:"action" enums:"report,block_start"`
+	CreatedAt    time.Time        `json:"created_at" format:"date-time"`
+	UpdatedAt    time.Time        `json:"updated_at" format:"date-time"`
+}
+
+type UpsertGroupUsageQuotaRequest struct {
+	MonthlyHours int32 `json:"monthly_hours" validate:"gte=0"`
+	// Action defaults to "report" when empty.
+	Action UsageQuotaAction `json:"action,omitempty" enums:"report,block_start"`
+}
+
+// UsageQuota is the consumption of a user in an organization during the
+// current period, which is the calendar month in UTC.
+type UsageQuota struct {
+	PeriodStart time.Time          `json:"period_start" format:"date-time"`
+	PeriodEnd   time.Time          `json:"period_end" format:"date-time"`
+	Metrics     []UsageQuotaStatus `json:"metrics"`
+}
+
+type UsageQuotaStatus struct {
+	Metric        UsageQuotaMetric `json:"metric" enums:"connection_hours,active_hours"`
+	ConsumedHours float64          `json:"consumed_hours"`
+	// AllowanceHours is the sum of the allowances of the user's groups. It is
+	// omitted when none of their groups limit the metric.
+	AllowanceHours *int64 `json:"allowance_hours,omitempty"`
+	// Action is the strictest action across the user's groups. It is omitted
+	// when the metric is not limited.
+	Action   UsageQuotaAction `json:"action,omitempty" enums:"report,block_start"`
+	Exceeded bool             `json:"exceeded"`
+}
+
+// GroupUsageQuotas returns the usage quotas configured for the given group.
+func (c *Client) GroupUsageQuotas(ctx context.Context, group uuid.UUID) ([]GroupUsageQuota, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/groups/%s/usage-quotas", group.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []GroupUsageQuota
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpsertGroupUsageQuota creates or updates the usage quota of the given group
+// for a metric.
+func (c *Client) UpsertGroupUsageQuota(ctx context.Context, group uuid.UUID, metric UsageQuotaMetric, req UpsertGroupUsageQuotaRequest) (GroupUsageQuota, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/groups/%s/usage-quotas/%s", group.String(), metric),
+		req,
+	)
+	if err != nil {
+		return GroupUsageQuota{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return GroupUsageQuota{}, ReadBodyAsError(res)
+	}
+	var resp GroupUsageQuota
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteGroupUsageQuota removes the usage quota of the given group for a
+// metric.
+func (c *Client) DeleteGroupUsageQuota(ctx context.Context, group uuid.UUID, metric UsageQuotaMetric) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/groups/%s/usage-quotas/%s", group.String(), metric),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UsageQuota returns the usage consumed by the user in the organization during
+// the current period, against the allowances granted by their groups.
+func (c *Client) UsageQuota(ctx context.Context, organizationID string, userID string) (UsageQuota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/members/%s/usage-quota", organizationID, userID), nil)
+	if err != nil {
+		return UsageQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UsageQuota{}, ReadBodyAsError(res)
+	}
+	var quota UsageQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
@@ -0,0 +1,221 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WebhookEvent is an event that outbound webhooks can subscribe to.
+type WebhookEvent string
+
+// WebhookEvent enums.
+const (
+	WebhookEventWorkspaceBuildSucceeded            WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed               WebhookEvent = "workspace_build_failed"
+	WebhookEventWorkspaceResourceThresholdExceeded WebhookEvent = "workspace_resource_threshold_exceeded"
+)
+
+// WebhookEvents lists all events that outbound webhooks can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEventWorkspaceBuildSucceeded,
+	WebhookEventWorkspaceBuildFailed,
+	WebhookEventWorkspaceResourceThresholdExceeded,
+}
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventWorkspaceResourceThresholdExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Webhook is an outbound webhook that receives workspace events, either for
+// all workspaces of the deployment or for the workspaces of a template.
+type Webhook struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	CreatedBy uuid.UUID `json:"created_by" format:"uuid"`
+	// TemplateID restricts the webhook to workspaces of the template. Events
+	// of all workspaces are delivered if it is not set.
+	TemplateID *uuid.UUID     `json:"template_id,omitempty" format:"uuid"`
+	URL        string         `json:"url"`
+	Events     []WebhookEvent `json:"events"`
+	// Secret is used to sign the deliveries of the webhook. It is only
+	// returned when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string         `json:"url" validate:"required,http_url"`
+	TemplateID *uuid.UUID     `json:"template_id,omitempty" format:"uuid"`
+	Events     []WebhookEvent `json:"events" validate:"required"`
+}
+
+// WebhookDeliveryStatus is the status of the delivery of an event to a
+// webhook.
+type WebhookDeliveryStatus string
+
+// WebhookDeliveryStatus enums.
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook. Pending
+// deliveries are retried with exponential backoff until they succeed or run
+// out of attempts.
+type WebhookDelivery struct {
+	ID            uuid.UUID             `json:"id" format:"uuid"`
+	WebhookID     uuid.UUID             `json:"webhook_id" format:"uuid"`
+	CreatedAt     time.Time             `json:"created_at" format:"date-time"`
+	UpdatedAt     time.Time             `json:"updated_at" format:"date-time"`
+	Event         WebhookEvent          `json:"event"`
+	Payload       WebhookPayload        `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status" enums:"pending,succeeded,failed"`
+	Attempts      int32                 `json:"attempts"`
+	NextAttemptAt *time.Time            `json:"next_attempt_at,omitempty" format:"date-time"`
+	// ResponseStatusCode is the status code returned by the last attempt, if
+	// the endpoint responded.
+	ResponseStatusCode *int32 `json:"response_status_code,omitempty"`
+	// Error is the reason the last attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+type WebhookDeliveriesRequest struct {
+	Pagination
+}
+
+// WebhookPayload is the JSON body POSTed to webhooks. Deliveries are signed
+// with the secret of the webhook: the X-Coder-Webhook-Signature header is
+// "sha256=" followed by the hex encoded HMAC-SHA256 of the
+// X-Coder-Webhook-Timestamp header, a dot and the body.
+type WebhookPayload struct {
+	Event     WebhookEvent     `json:"event"`
+	Timestamp time.Time        `json:"timestamp" format:"date-time"`
+	Workspace WebhookWorkspace `json:"workspace"`
+	// Build is set for workspace build events.
+	Build *WebhookWorkspaceBuild `json:"build,omitempty"`
+	// Thresholds is set for resource threshold events, and lists the
+	// resources of the workspace that exceeded their threshold.
+	Thresholds []WebhookResourceThreshold `json:"thresholds,omitempty"`
+}
+
+type WebhookWorkspace struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	OwnerID        uuid.UUID `json:"owner_id" format:"uuid"`
+	OwnerName      string    `json:"owner_name"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName   string    `json:"template_name"`
+}
+
+type WebhookWorkspaceBuild struct {
+	ID          uuid.UUID            `json:"id" format:"uuid"`
+	BuildNumber int32                `json:"build_number"`
+	Transition  WorkspaceTransition  `json:"transition" enums:"start,stop,delete"`
+	Reason      BuildReason          `json:"reason"`
+	Status      ProvisionerJobStatus `json:"status"`
+	Error       string               `json:"error,omitempty"`
+}
+
+type WebhookResourceThreshold struct {
+	// Resource is either "memory" or "volume".
+	Resource string `json:"resource" enums:"memory,volume"`
+	// Path is the path of the volume, for volume thresholds.
+	Path             string `json:"path,omitempty"`
+	ThresholdPercent int32  `json:"threshold_percent"`
+}
+
+// Webhooks lists the outbound webhooks of the deployment.
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var webhooks []Webhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+// CreateWebhook creates an outbound webhook. The returned webhook includes
+// the secret deliveries are signed with, which can't be retrieved later.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// Webhook returns an outbound webhook by ID.
+func (c *Client) Webhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// DeleteWebhook deletes an outbound webhook along with its deliveries.
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebhookDeliveries lists the deliveries of an outbound webhook, newest
+// first.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID, req WebhookDeliveriesRequest) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id), nil,
+		req.Pagination.asRequestOption(),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var deliveries []WebhookDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
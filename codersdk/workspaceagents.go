@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -424,6 +427,208 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentProcessSortBy is the resource used to rank processes running
+// inside a workspace.
+type WorkspaceAgentProcessSortBy string
+
+const (
+	WorkspaceAgentProcessSortByCPU    WorkspaceAgentProcessSortBy = "cpu"
+	WorkspaceAgentProcessSortByMemory WorkspaceAgentProcessSortBy = "memory"
+)
+
+func (s WorkspaceAgentProcessSortBy) Valid() bool {
+	switch s {
+	case WorkspaceAgentProcessSortByCPU, WorkspaceAgentProcessSortByMemory:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// WorkspaceAgentTopProcessesDefaultLimit is the number of processes
+	// returned when no limit is requested.
+	WorkspaceAgentTopProcessesDefaultLimit = 10
+	// WorkspaceAgentTopProcessesMaxLimit is the maximum number of processes
+	// that can be requested at once.
+	WorkspaceAgentTopProcessesMaxLimit = 100
+)
+
+type WorkspaceAgentTopProcessesRequest struct {
+	// SortBy is the resource to rank processes by. Defaults to CPU.
+	SortBy WorkspaceAgentProcessSortBy `json:"sort_by,omitempty"`
+	// Limit is the number of processes to return. Defaults to
+	// WorkspaceAgentTopProcessesDefaultLimit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// asRequestOption returns a function that can be used in (*Client).Request.
+// It modifies the request query parameters.
+func (r WorkspaceAgentTopProcessesRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if r.SortBy != "" {
+			q.Set("sort_by", string(r.SortBy))
+		}
+		if r.Limit > 0 {
+			q.Set("limit", strconv.Itoa(r.Limit))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+type WorkspaceAgentTopProcessesResponse struct {
+	SortBy WorkspaceAgentProcessSortBy `json:"sort_by" enums:"cpu,memory"`
+	// Processes are ordered by the resource they are sorted by, highest
+	// first.
+	Processes []WorkspaceAgentProcess `json:"processes"`
+}
+
+// WorkspaceAgentProcess is a process running inside a workspace, along with
+// its resource usage.
+type WorkspaceAgentProcess struct {
+	PID  int32  `json:"pid"`
+	Name string `json:"name"`
+	// Username is empty if the owner of the process can't be resolved.
+	Username string `json:"username"`
+	// CPUPercent is the CPU usage of the process, sampled over a short
+	// interval. It can exceed 100 for processes using multiple cores.
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryBytes   uint64  `json:"memory_bytes"`
+	MemoryPercent float32 `json:"memory_percent"`
+}
+
+// WorkspaceAgentTopProcesses returns the processes using the most CPU or
+// memory inside the workspace.
+func (c *Client) WorkspaceAgentTopProcesses(ctx context.Context, agentID uuid.UUID, req WorkspaceAgentTopProcessesRequest) (WorkspaceAgentTopProcessesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/top-processes", agentID), nil, req.asRequestOption())
+	if err != nil {
+		return WorkspaceAgentTopProcessesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentTopProcessesResponse{}, ReadBodyAsError(res)
+	}
+	var topProcesses WorkspaceAgentTopProcessesResponse
+	return topProcesses, json.NewDecoder(res.Body).Decode(&topProcesses)
+}
+
+// WorkspaceAgentStatsMetric is a metric reported by workspace agents that can
+// be charted over time.
+type WorkspaceAgentStatsMetric string
+
+const (
+	WorkspaceAgentStatsMetricRxBytes                     WorkspaceAgentStatsMetric = "rx_bytes"
+	WorkspaceAgentStatsMetricTxBytes                     WorkspaceAgentStatsMetric = "tx_bytes"
+	WorkspaceAgentStatsMetricRxPackets                   WorkspaceAgentStatsMetric = "rx_packets"
+	WorkspaceAgentStatsMetricTxPackets                   WorkspaceAgentStatsMetric = "tx_packets"
+	WorkspaceAgentStatsMetricConnectionCount             WorkspaceAgentStatsMetric = "connection_count"
+	WorkspaceAgentStatsMetricConnectionMedianLatencyMS   WorkspaceAgentStatsMetric = "connection_median_latency_ms"
+	WorkspaceAgentStatsMetricSessionCountVSCode          WorkspaceAgentStatsMetric = "session_count_vscode"
+	WorkspaceAgentStatsMetricSessionCountJetBrains       WorkspaceAgentStatsMetric = "session_count_jetbrains"
+	WorkspaceAgentStatsMetricSessionCountReconnectingPTY WorkspaceAgentStatsMetric = "session_count_reconnecting_pty"
+	WorkspaceAgentStatsMetricSessionCountSSH             WorkspaceAgentStatsMetric = "session_count_ssh"
+)
+
+// AllWorkspaceAgentStatsMetrics lists every metric that can be requested from
+// the stats time series endpoint.
+var AllWorkspaceAgentStatsMetrics = []WorkspaceAgentStatsMetric{
+	WorkspaceAgentStatsMetricRxBytes,
+	WorkspaceAgentStatsMetricTxBytes,
+	WorkspaceAgentStatsMetricRxPackets,
+	WorkspaceAgentStatsMetricTxPackets,
+	WorkspaceAgentStatsMetricConnectionCount,
+	WorkspaceAgentStatsMetricConnectionMedianLatencyMS,
+	WorkspaceAgentStatsMetricSessionCountVSCode,
+	WorkspaceAgentStatsMetricSessionCountJetBrains,
+	WorkspaceAgentStatsMetricSessionCountReconnectingPTY,
+	WorkspaceAgentStatsMetricSessionCountSSH,
+}
+
+func (m WorkspaceAgentStatsMetric) Valid() bool {
+	return slices.Contains(AllWorkspaceAgentStatsMetrics, m)
+}
+
+func workspaceAgentStatsMetricStrings() []string {
+	metrics := make([]string, 0, len(AllWorkspaceAgentStatsMetrics))
+	for _, m := range AllWorkspaceAgentStatsMetrics {
+		metrics = append(metrics, string(m))
+	}
+	return metrics
+}
+
+// WorkspaceAgentStatsTimeSeriesMaxBuckets is the maximum number of buckets a
+// stats time series can be downsampled into.
+const WorkspaceAgentStatsTimeSeriesMaxBuckets = 1000
+
+type WorkspaceAgentStatsTimeSeriesRequest struct {
+	// StartTime defaults to 24 hours before EndTime.
+	StartTime time.Time `json:"start_time,omitempty" format:"date-time"`
+	// EndTime defaults to now.
+	EndTime time.Time `json:"end_time,omitempty" format:"date-time"`
+	// BucketSize must be at least a minute. It defaults to the smallest
+	// multiple of a minute that fits the time range into
+	// WorkspaceAgentStatsTimeSeriesMaxBuckets buckets.
+	BucketSize time.Duration `json:"bucket_size,omitempty"`
+	// Metrics defaults to all metrics.
+	Metrics []WorkspaceAgentStatsMetric `json:"metrics,omitempty"`
+}
+
+// WorkspaceAgentStatsTimeSeries is the stats reported by a workspace agent,
+// downsampled into fixed size buckets.
+type WorkspaceAgentStatsTimeSeries struct {
+	StartTime    time.Time                   `json:"start_time" format:"date-time"`
+	EndTime      time.Time                   `json:"end_time" format:"date-time"`
+	BucketSizeMS int64                       `json:"bucket_size_ms"`
+	Metrics      []WorkspaceAgentStatsMetric `json:"metrics"`
+	Buckets      []WorkspaceAgentStatsBucket `json:"buckets"`
+}
+
+// WorkspaceAgentStatsBucket holds the value of each requested metric within a
+// bucket. Byte and packet counts are summed over the bucket, while connection
+// counts, session counts and latency are averaged.
+type WorkspaceAgentStatsBucket struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	// Reports is the number of stats reports the agent sent within the
+	// bucket. Buckets without reports have all values set to zero.
+	Reports int64                                 `json:"reports"`
+	Values  map[WorkspaceAgentStatsMetric]float64 `json:"values"`
+}
+
+// WorkspaceAgentStatsTimeSeries returns the stats reported by a workspace
+// agent, downsampled server-side into buckets suitable for charting.
+func (c *Client) WorkspaceAgentStatsTimeSeries(ctx context.Context, agentID uuid.UUID, req WorkspaceAgentStatsTimeSeriesRequest) (WorkspaceAgentStatsTimeSeries, error) {
+	qp := url.Values{}
+	if !req.StartTime.IsZero() {
+		qp.Add("start_time", req.StartTime.Format(time.RFC3339))
+	}
+	if !req.EndTime.IsZero() {
+		qp.Add("end_time", req.EndTime.Format(time.RFC3339))
+	}
+	if req.BucketSize > 0 {
+		qp.Add("bucket_size", req.BucketSize.String())
+	}
+	if len(req.Metrics) > 0 {
+		metrics := make([]string, 0, len(req.Metrics))
+		for _, m := range req.Metrics {
+			metrics = append(metrics, string(m))
+		}
+		qp.Add("metrics", strings.Join(metrics, ","))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/workspaceagents/%s/stats/timeseries?%s", agentID, qp.Encode())
+	res, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return WorkspaceAgentStatsTimeSeries{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentStatsTimeSeries{}, ReadBodyAsError(res)
+	}
+	var series WorkspaceAgentStatsTimeSeries
+	return series, json.NewDecoder(res.Body).Decode(&series)
+}
+
 // WorkspaceAgentDevcontainerStatus is the status of a devcontainer.
 type WorkspaceAgentDevcontainerStatus string
 
@@ -0,0 +1,83 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceRightsizingResource is the resource of a workspace agent that a
+// right-sizing recommendation is for.
+type WorkspaceRightsizingResource string
+
+// WorkspaceRightsizingResource enums.
+const (
+	WorkspaceRightsizingResourceCPU    WorkspaceRightsizingResource = "cpu"
+	WorkspaceRightsizingResourceMemory WorkspaceRightsizingResource = "memory"
+)
+
+// WorkspaceRightsizing recommends resources for the agents of a workspace
+// based on their CPU and memory usage, as sampled from the "coder stat cpu"
+// and "coder stat mem" agent metadata. Agents that don't report these, or
+// haven't reported them for long enough, have no recommendations.
+type WorkspaceRightsizing struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+	// RunningSeconds is how long the workspace was running during the period.
+	RunningSeconds int64 `json:"running_seconds" example:"86400"`
+	// Cost is the estimated cost of running the workspace during the period,
+	// in the same unit as the daily cost of template resources.
+	Cost float64 `json:"cost" example:"24"`
+	// PotentialSavings is the part of the cost that would have been saved by
+	// applying the recommendations, assuming the cost is split evenly between
+	// CPU and memory.
+	PotentialSavings float64                              `json:"potential_savings" example:"6"`
+	Recommendations  []WorkspaceRightsizingRecommendation `json:"recommendations"`
+}
+
+// WorkspaceRightsizingRecommendation is the recommended size of a resource of
+// a workspace agent. CPU is in cores and memory is in bytes.
+type WorkspaceRightsizingRecommendation struct {
+	AgentName string                       `json:"agent_name"`
+	Resource  WorkspaceRightsizingResource `json:"resource" enums:"cpu,memory"`
+	// Samples is the number of usage samples the recommendation is based on.
+	Samples int64 `json:"samples"`
+	// Current is the latest total of the resource reported by the agent.
+	Current float64 `json:"current"`
+	UsedP95 float64 `json:"used_p95"`
+	UsedMax float64 `json:"used_max"`
+	// Recommended is the 95th percentile of the usage with headroom, rounded
+	// up. It is higher than the current total if the agent is undersized.
+	Recommended float64 `json:"recommended"`
+}
+
+type WorkspaceRightsizingRequest struct {
+	// Days is the number of days of usage to base the recommendations on. The
+	// server picks a default if it's zero.
+	Days int `json:"days"`
+}
+
+// WorkspaceRightsizing returns resource recommendations for the agents of a
+// workspace, based on their recent CPU and memory usage.
+func (c *Client) WorkspaceRightsizing(ctx context.Context, id uuid.UUID, req WorkspaceRightsizingRequest) (WorkspaceRightsizing, error) {
+	qp := url.Values{}
+	if req.Days != 0 {
+		qp.Add("days", strconv.Itoa(req.Days))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/rightsizing?%s", id, qp.Encode()), nil)
+	if err != nil {
+		return WorkspaceRightsizing{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceRightsizing{}, ReadBodyAsError(res)
+	}
+	var rightsizing WorkspaceRightsizing
+	return rightsizing, json.NewDecoder(res.Body).Decode(&rightsizing)
+}
@@ -127,6 +127,7 @@ type AgentConn interface {
 	SSHClientOnPort(ctx context.Context, port uint16) (*ssh.Client, error)
 	SSHOnPort(ctx context.Context, port uint16) (*gonet.TCPConn, error)
 	Speedtest(ctx context.Context, direction speedtest.Direction, duration time.Duration) ([]speedtest.Result, error)
+	TopProcesses(ctx context.Context, req codersdk.WorkspaceAgentTopProcessesRequest) (codersdk.WorkspaceAgentTopProcessesResponse, error)
 	WatchContainers(ctx context.Context, logger slog.Logger) (<-chan codersdk.WorkspaceAgentListContainersResponse, io.Closer, error)
 	WatchGit(ctx context.Context, logger slog.Logger, chatID uuid.UUID) (*wsjson.Stream[codersdk.WorkspaceAgentGitServerMessage, codersdk.WorkspaceAgentGitClientMessage], error)
 	ConnectDesktopVNC(ctx context.Context) (net.Conn, error)
@@ -387,6 +388,35 @@ func (c *agentConn) ListeningPorts(ctx context.Context) (codersdk.WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// TopProcesses lists the processes using the most CPU or memory in the
+// workspace.
+func (c *agentConn) TopProcesses(ctx context.Context, req codersdk.WorkspaceAgentTopProcessesRequest) (codersdk.WorkspaceAgentTopProcessesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	q := neturl.Values{}
+	if req.SortBy != "" {
+		q.Set("sort_by", string(req.SortBy))
+	}
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	path := "/api/v0/top-processes"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	res, err := c.apiRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return codersdk.WorkspaceAgentTopProcessesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.WorkspaceAgentTopProcessesResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp codersdk.WorkspaceAgentTopProcessesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // Netcheck returns a network check report from the workspace agent.
 func (c *agentConn) Netcheck(ctx context.Context) (healthsdk.AgentNetcheckReport, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -624,6 +624,21 @@ func (mr *MockAgentConnMockRecorder) TailnetConn() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TailnetConn", reflect.TypeOf((*MockAgentConn)(nil).TailnetConn))
 }
 
+// TopProcesses mocks base method.
+func (m *MockAgentConn) TopProcesses(ctx context.Context, req codersdk.WorkspaceAgentTopProcessesRequest) (codersdk.WorkspaceAgentTopProcessesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopProcesses", ctx, req)
+	ret0, _ := ret[0].(codersdk.WorkspaceAgentTopProcessesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopProcesses indicates an expected call of TopProcesses.
+func (mr *MockAgentConnMockRecorder) TopProcesses(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopProcesses", reflect.TypeOf((*MockAgentConn)(nil).TopProcesses), ctx, req)
+}
+
 // WatchContainers mocks base method.
 func (m *MockAgentConn) WatchContainers(ctx context.Context, logger slog.Logger) (<-chan codersdk.WorkspaceAgentListContainersResponse, io.Closer, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,65 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceTimelineEventType is the kind of event in the timeline of a
+// workspace.
+type WorkspaceTimelineEventType string
+
+// WorkspaceTimelineEventType enums.
+const (
+	WorkspaceTimelineEventTypeBuild          WorkspaceTimelineEventType = "build"
+	WorkspaceTimelineEventTypeConnection     WorkspaceTimelineEventType = "connection"
+	WorkspaceTimelineEventTypeAppUsage       WorkspaceTimelineEventType = "app_usage"
+	WorkspaceTimelineEventTypeAgentLifecycle WorkspaceTimelineEventType = "agent_lifecycle"
+	WorkspaceTimelineEventTypeAudit          WorkspaceTimelineEventType = "audit"
+)
+
+// WorkspaceTimelineEvent is an event in the history of a workspace.
+type WorkspaceTimelineEvent struct {
+	// ID is the ID of the record the event is derived from, i.e. the
+	// workspace build, connection log, workspace agent or audit log.
+	ID   uuid.UUID                  `json:"id" format:"uuid"`
+	Type WorkspaceTimelineEventType `json:"type" enums:"build,connection,app_usage,agent_lifecycle,audit"`
+	Time time.Time                  `json:"time" format:"date-time"`
+	// EndTime is set for events that span a period of time once it's over,
+	// e.g. for completed builds and closed connections.
+	EndTime     *time.Time `json:"end_time,omitempty" format:"date-time"`
+	Description string     `json:"description"`
+	// UserID is the user that caused the event, if known.
+	UserID *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	// BuildNumber is the number of the workspace build the event belongs to,
+	// if any.
+	BuildNumber int32 `json:"build_number,omitempty"`
+}
+
+type WorkspaceTimelineRequest struct {
+	Pagination
+}
+
+// WorkspaceTimeline returns the events in the history of a workspace, most
+// recent first.
+func (c *Client) WorkspaceTimeline(ctx context.Context, id uuid.UUID, req WorkspaceTimelineRequest) ([]WorkspaceTimelineEvent, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/workspaces/%s/timeline", id),
+		nil,
+		req.Pagination.asRequestOption(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var events []WorkspaceTimelineEvent
+	return events, json.NewDecoder(res.Body).Decode(&events)
+}
@@ -37,6 +37,7 @@ var AuditActionMap = map[string][]codersdk.AuditAction{
 	"Chat":                          {codersdk.AuditActionCreate, codersdk.AuditActionWrite}, // chats get 'archived' by users, not deleted.
 	"UserSecret":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 	"UserSkill":                     {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Webhook":                       {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -130,6 +131,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"use_classic_parameter_flow":        ActionTrack,
 		"cors_behavior":                     ActionTrack,
 		"disable_module_cache":              ActionTrack,
+		"dormancy_activity_signal":          ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -504,6 +506,16 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"created_at":   ActionIgnore,
 		"updated_at":   ActionIgnore,
 	},
+	&database.Webhook{}: {
+		"id":            ActionTrack,
+		"created_at":    ActionIgnore,
+		"created_by":    ActionTrack,
+		"template_id":   ActionTrack,
+		"url":           ActionTrack,
+		"secret":        ActionSecret,
+		"secret_key_id": ActionIgnore,
+		"events":        ActionTrack,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
@@ -17,10 +17,12 @@ import (
 
 	agplcoderd "github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/audit/backends"
 	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/enterprise/coderd/auditexport"
 	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
 	"github.com/coder/coder/v2/enterprise/coderd/usage"
 	"github.com/coder/coder/v2/enterprise/dbcrypt"
@@ -162,6 +164,29 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 		usageCron.Start(ctx)
 		closers.Add(usageCron)
 
+		if exportCfg := options.DeploymentValues.AuditLogExport; exportCfg.Bucket.Value() != "" {
+			uploader, err := auditexport.NewS3Uploader(ctx, auditexport.S3Options{
+				Bucket:   exportCfg.Bucket.Value(),
+				Region:   exportCfg.Region.Value(),
+				Endpoint: exportCfg.Endpoint.Value(),
+			})
+			if err != nil {
+				_ = closers.Close()
+				return nil, nil, xerrors.Errorf("create audit log export uploader: %w", err)
+			}
+			closers.Add(auditexport.New(ctx, auditexport.Options{
+				Logger:       options.Logger,
+				Database:     options.Database,
+				Uploader:     uploader,
+				Clock:        quartz.NewReal(),
+				Prefix:       exportCfg.Prefix.Value(),
+				IncludeStats: exportCfg.IncludeStats.Value(),
+				Entitled: func() bool {
+					return api.Entitlements.Enabled(codersdk.FeatureAuditLog)
+				},
+			}))
+		}
+
 		// In-memory AI Bridge Proxy daemon. The bridge daemon itself is
 		// started unconditionally by AGPL cli/server.go (chatd uses its
 		// in-memory roundtripper regardless of license); only the proxy
@@ -195,6 +195,11 @@ func TestServerDBCrypt(t *testing.T) {
 		require.False(t, sshKey.PrivateKeyKeyID.Valid, "expected private_key_key_id to be cleared for user %s", usr.ID)
 	}
 
+	// Assert that no webhooks remain.
+	webhooks, err := db.GetWebhooks(ctx)
+	require.NoError(t, err, "failed to get webhooks")
+	require.Empty(t, webhooks)
+
 	// Validate that the key has been revoked in the database.
 	keys, err = db.GetDBCryptKeys(ctx)
 	require.NoError(t, err, "failed to get db crypt keys")
@@ -251,6 +256,15 @@ func genData(t *testing.T, db database.Store) []database.User {
 					UpdatedAt:    now,
 				})
 				require.NoError(t, err)
+				_, err = db.InsertWebhook(context.Background(), database.InsertWebhookParams{
+					ID:        uuid.New(),
+					CreatedAt: now,
+					CreatedBy: usr.ID,
+					Url:       "https://example.com/webhook",
+					Secret:    "webhook-secret-" + usr.ID.String(),
+					Events:    []string{"workspace_build_succeeded"},
+				})
+				require.NoError(t, err)
 
 				// Deleted users cannot have user_links or user_secrets.
 				if !deleted {
@@ -357,6 +371,19 @@ func requireEncryptedWithCipher(ctx context.Context, t *testing.T, db database.S
 	require.Len(t, userAIProviderKeys, 1)
 	requireEncryptedEquals(t, c, "user-ai-provider-key-"+userID.String(), userAIProviderKeys[0].APIKey)
 	require.Equal(t, c.HexDigest(), userAIProviderKeys[0].ApiKeyKeyID.String)
+
+	webhooks, err := db.GetWebhooks(ctx)
+	require.NoError(t, err, "failed to get webhooks")
+	var found bool
+	for _, w := range webhooks {
+		if w.CreatedBy != userID {
+			continue
+		}
+		found = true
+		requireEncryptedEquals(t, c, "webhook-secret-"+userID.String(), w.Secret)
+		require.Equal(t, c.HexDigest(), w.SecretKeyID.String)
+	}
+	require.True(t, found, "expected webhook for user %s", userID)
 }
 
 // TestServerAIProviderKeysEncryptedWithDBCrypt starts a real enterprise server
@@ -0,0 +1,422 @@
+// Package auditexport copies the audit logs, and optionally the template usage
+// stats, to object storage, so that they can be retained for compliance long
+// after they are purged from the database.
+//
+// Every dataset is exported in hourly objects of gzipped JSON lines, keyed by
+// date so that lifecycle rules and query engines can select them by prefix:
+//
+//	<prefix>/<dataset>/year=2024/month=01/day=31/hour=13.jsonl.gz
+package auditexport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/quartz"
+)
+
+const (
+	DatasetAuditLogs          = "audit_logs"
+	DatasetTemplateUsageStats = "template_usage_stats"
+
+	// interval is the time between consecutive exports.
+	interval = 15 * time.Minute
+	// window is the period of time covered by an exported object.
+	window = time.Hour
+	// pageSize is the number of audit logs read from the database at once.
+	pageSize = 1000
+	// maxObjectsPerRun bounds the work of a single export, so that catching
+	// up on a large history is spread over several exports.
+	maxObjectsPerRun = 100
+)
+
+// Uploader writes objects to a bucket. Uploading an object with an existing
+// key replaces it.
+type Uploader interface {
+	Upload(ctx context.Context, key string, body []byte) error
+}
+
+type Options struct {
+	Logger   slog.Logger
+	Database database.Store
+	Uploader Uploader
+	Clock    quartz.Clock
+	// Prefix is prepended to the keys of the exported objects.
+	Prefix string
+	// IncludeStats also exports the template usage stats.
+	IncludeStats bool
+	// Entitled reports whether the deployment is licensed for the audit
+	// log. Nothing is exported while it is not.
+	Entitled func() bool
+}
+
+// Exporter periodically exports the hours that ended since its last export.
+// Replicas share their progress through the database, so an hour is not
+// exported again once any replica exported it.
+type Exporter struct {
+	logger   slog.Logger
+	db       database.Store
+	uploader Uploader
+	clock    quartz.Clock
+	prefix   string
+	entitled func() bool
+	datasets []dataset
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ io.Closer = (*Exporter)(nil)
+
+// New starts an exporter. It is the caller's responsibility to call Close on
+// the returned instance.
+func New(ctx context.Context, opts Options) *Exporter {
+	e := newExporter(opts)
+
+	//nolint:gocritic // The system exports the audit logs without user input.
+	ctx, e.cancel = context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	tf := e.clock.TickerFunc(ctx, interval, func() error {
+		err := e.run(ctx)
+		if err != nil && ctx.Err() == nil {
+			e.logger.Error(ctx, "failed to export audit logs", slog.Error(err))
+		}
+		return nil
+	}, "auditexport")
+	go func() {
+		defer close(e.done)
+		_ = tf.Wait()
+	}()
+	return e
+}
+
+func newExporter(opts Options) *Exporter {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	if opts.Entitled == nil {
+		opts.Entitled = func() bool { return true }
+	}
+	datasets := []dataset{auditLogs}
+	if opts.IncludeStats {
+		datasets = append(datasets, templateUsageStats)
+	}
+	return &Exporter{
+		logger:   opts.Logger.Named("auditexport"),
+		db:       opts.Database,
+		uploader: opts.Uploader,
+		clock:    opts.Clock,
+		prefix:   opts.Prefix,
+		entitled: opts.Entitled,
+		datasets: datasets,
+		cancel:   func() {},
+		done:     make(chan struct{}),
+	}
+}
+
+func (e *Exporter) Close() error {
+	e.cancel()
+	<-e.done
+	return nil
+}
+
+// run exports the hours of every dataset that ended since the last export.
+func (e *Exporter) run(ctx context.Context) error {
+	if !e.entitled() {
+		e.logger.Debug(ctx, "audit log is not entitled, skipping export")
+		return nil
+	}
+	now := dbtime.Time(e.clock.Now()).UTC()
+	uploaded := 0
+	for _, ds := range e.datasets {
+		n, err := e.exportDataset(ctx, ds, now, maxObjectsPerRun-uploaded)
+		uploaded += n
+		if err != nil {
+			return xerrors.Errorf("export %s: %w", ds.name, err)
+		}
+	}
+	return nil
+}
+
+// exportDataset uploads an object for every hour with rows since the cursor
+// of the dataset, up to limit objects, and returns how many it uploaded. The
+// cursor is advanced after every upload, so that an export that fails midway
+// doesn't upload the same objects again.
+func (e *Exporter) exportDataset(ctx context.Context, ds dataset, now time.Time, limit int) (int, error) {
+	var cursor time.Time
+	export, err := e.db.GetAuditLogExport(ctx, ds.name)
+	switch {
+	case err == nil:
+		cursor = export.ExportedUntil.UTC()
+	case xerrors.Is(err, sql.ErrNoRows):
+		// Nothing was exported yet, start with the oldest row.
+	default:
+		return 0, xerrors.Errorf("get cursor: %w", err)
+	}
+
+	end := now.Add(-ds.delay).Truncate(window)
+	uploaded := 0
+	for uploaded < limit && cursor.Before(end) {
+		start, ok, err := ds.next(ctx, e.db, cursor, end)
+		if err != nil {
+			return uploaded, xerrors.Errorf("find next rows: %w", err)
+		}
+		if !ok {
+			return uploaded, e.advance(ctx, ds.name, end, now)
+		}
+		start = start.UTC().Truncate(window)
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		count, err := ds.write(ctx, e.db, start, json.NewEncoder(zw))
+		if err != nil {
+			return uploaded, xerrors.Errorf("write rows from %s: %w", start, err)
+		}
+		if err := zw.Close(); err != nil {
+			return uploaded, xerrors.Errorf("compress rows: %w", err)
+		}
+		key := e.key(ds.name, start)
+		if err := e.uploader.Upload(ctx, key, buf.Bytes()); err != nil {
+			return uploaded, xerrors.Errorf("upload %q: %w", key, err)
+		}
+		e.logger.Debug(ctx, "exported object", slog.F("key", key), slog.F("rows", count))
+		uploaded++
+		cursor = start.Add(window)
+		if err := e.advance(ctx, ds.name, cursor, now); err != nil {
+			return uploaded, err
+		}
+	}
+	return uploaded, nil
+}
+
+// advance moves the cursor of the dataset forward to exportedUntil. Replicas
+// may export the same hour concurrently, which is harmless as uploading an
+// object replaces it, so the cursor is never moved backwards.
+func (e *Exporter) advance(ctx context.Context, dataset string, exportedUntil, now time.Time) error {
+	return e.db.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.LockIDAuditLogExport)
+		if err != nil {
+			return xerrors.Errorf("acquire audit log export lock: %w", err)
+		}
+		export, err := tx.GetAuditLogExport(ctx, dataset)
+		switch {
+		case err == nil:
+			if !exportedUntil.After(export.ExportedUntil) {
+				return nil
+			}
+		case xerrors.Is(err, sql.ErrNoRows):
+			// This is the first export of the dataset.
+		default:
+			return xerrors.Errorf("get cursor: %w", err)
+		}
+		err = tx.UpsertAuditLogExport(ctx, database.UpsertAuditLogExportParams{
+			Dataset:       dataset,
+			ExportedUntil: exportedUntil,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update cursor: %w", err)
+		}
+		return nil
+	}, database.DefaultTXOptions().WithID("audit_log_export"))
+}
+
+func (e *Exporter) key(dataset string, start time.Time) string {
+	return path.Join(
+		e.prefix,
+		dataset,
+		fmt.Sprintf("year=%04d", start.Year()),
+		fmt.Sprintf("month=%02d", start.Month()),
+		fmt.Sprintf("day=%02d", start.Day()),
+		fmt.Sprintf("hour=%02d.jsonl.gz", start.Hour()),
+	)
+}
+
+type dataset struct {
+	name string
+	// delay is how long after an hour ends its rows are final.
+	delay time.Duration
+	// next returns the time of the first row at or after start and before
+	// end, if any.
+	next func(ctx context.Context, db database.Store, start, end time.Time) (time.Time, bool, error)
+	// write encodes the rows of the hour starting at start, and returns how
+	// many there were.
+	write func(ctx context.Context, db database.Store, start time.Time, enc *json.Encoder) (int, error)
+}
+
+var auditLogs = dataset{
+	name: DatasetAuditLogs,
+	// Leave time for the requests that started before the end of the hour to
+	// commit their audit logs.
+	delay: 5 * time.Minute,
+	next: func(ctx context.Context, db database.Store, start, end time.Time) (time.Time, bool, error) {
+		logs, err := db.GetAuditLogsForExport(ctx, database.GetAuditLogsForExportParams{
+			AfterTime:  start,
+			AfterID:    uuid.Nil,
+			EndTime:    end,
+			LimitCount: 1,
+		})
+		if err != nil || len(logs) == 0 {
+			return time.Time{}, false, err
+		}
+		return logs[0].Time, true, nil
+	},
+	write: func(ctx context.Context, db database.Store, start time.Time, enc *json.Encoder) (int, error) {
+		afterTime, afterID := start, uuid.Nil
+		count := 0
+		for {
+			logs, err := db.GetAuditLogsForExport(ctx, database.GetAuditLogsForExportParams{
+				AfterTime:  afterTime,
+				AfterID:    afterID,
+				EndTime:    start.Add(window),
+				LimitCount: pageSize,
+			})
+			if err != nil {
+				return count, err
+			}
+			for _, log := range logs {
+				if err := enc.Encode(newAuditLogRecord(log)); err != nil {
+					return count, err
+				}
+			}
+			count += len(logs)
+			if len(logs) < pageSize {
+				return count, nil
+			}
+			afterTime, afterID = logs[len(logs)-1].Time, logs[len(logs)-1].ID
+		}
+	},
+}
+
+var templateUsageStats = dataset{
+	name: DatasetTemplateUsageStats,
+	// The rollup keeps updating the stats of the last hour.
+	delay: 2 * time.Hour,
+	next: func(ctx context.Context, db database.Store, start, end time.Time) (time.Time, bool, error) {
+		stats, err := db.GetTemplateUsageStatsForExport(ctx, database.GetTemplateUsageStatsForExportParams{
+			StartTime: start,
+			EndTime:   end,
+			LimitOpt:  1,
+		})
+		if err != nil || len(stats) == 0 {
+			return time.Time{}, false, err
+		}
+		return stats[0].StartTime, true, nil
+	},
+	write: func(ctx context.Context, db database.Store, start time.Time, enc *json.Encoder) (int, error) {
+		// An hour has at most two buckets per template and user, so it is
+		// read at once.
+		stats, err := db.GetTemplateUsageStatsForExport(ctx, database.GetTemplateUsageStatsForExportParams{
+			StartTime: start,
+			EndTime:   start.Add(window),
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, stat := range stats {
+			if err := enc.Encode(newTemplateUsageStatRecord(stat)); err != nil {
+				return 0, err
+			}
+		}
+		return len(stats), nil
+	},
+}
+
+// auditLogRecord is an exported audit log. Its fields must not be renamed,
+// as consumers of the export rely on them.
+type auditLogRecord struct {
+	ID               uuid.UUID       `json:"id"`
+	Time             time.Time       `json:"time"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	UserID           uuid.UUID       `json:"user_id"`
+	IP               string          `json:"ip"`
+	UserAgent        string          `json:"user_agent"`
+	ResourceType     string          `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id"`
+	ResourceTarget   string          `json:"resource_target"`
+	Action           string          `json:"action"`
+	Diff             json.RawMessage `json:"diff"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields"`
+	RequestID        uuid.UUID       `json:"request_id"`
+}
+
+func newAuditLogRecord(log database.AuditLog) auditLogRecord {
+	r := auditLogRecord{
+		ID:               log.ID,
+		Time:             log.Time.UTC(),
+		OrganizationID:   log.OrganizationID,
+		UserID:           log.UserID,
+		UserAgent:        log.UserAgent.String,
+		ResourceType:     string(log.ResourceType),
+		ResourceID:       log.ResourceID,
+		ResourceTarget:   log.ResourceTarget,
+		Action:           string(log.Action),
+		Diff:             rawJSON(log.Diff),
+		StatusCode:       log.StatusCode,
+		AdditionalFields: rawJSON(log.AdditionalFields),
+		RequestID:        log.RequestID,
+	}
+	if log.Ip.Valid {
+		r.IP = log.Ip.IPNet.IP.String()
+	}
+	return r
+}
+
+// templateUsageStatRecord is an exported template usage stat. Its fields
+// must not be renamed, as consumers of the export rely on them.
+type templateUsageStatRecord struct {
+	StartTime           time.Time        `json:"start_time"`
+	EndTime             time.Time        `json:"end_time"`
+	TemplateID          uuid.UUID        `json:"template_id"`
+	UserID              uuid.UUID        `json:"user_id"`
+	MedianLatencyMs     *float64         `json:"median_latency_ms"`
+	UsageMins           int16            `json:"usage_mins"`
+	SSHMins             int16            `json:"ssh_mins"`
+	SFTPMins            int16            `json:"sftp_mins"`
+	ReconnectingPTYMins int16            `json:"reconnecting_pty_mins"`
+	VSCodeMins          int16            `json:"vscode_mins"`
+	JetBrainsMins       int16            `json:"jetbrains_mins"`
+	AppUsageMins        map[string]int64 `json:"app_usage_mins"`
+}
+
+func newTemplateUsageStatRecord(stat database.TemplateUsageStat) templateUsageStatRecord {
+	r := templateUsageStatRecord{
+		StartTime:           stat.StartTime.UTC(),
+		EndTime:             stat.EndTime.UTC(),
+		TemplateID:          stat.TemplateID,
+		UserID:              stat.UserID,
+		UsageMins:           stat.UsageMins,
+		SSHMins:             stat.SshMins,
+		SFTPMins:            stat.SftpMins,
+		ReconnectingPTYMins: stat.ReconnectingPtyMins,
+		VSCodeMins:          stat.VscodeMins,
+		JetBrainsMins:       stat.JetbrainsMins,
+		AppUsageMins:        stat.AppUsageMins,
+	}
+	if stat.MedianLatencyMs.Valid {
+		r.MedianLatencyMs = &stat.MedianLatencyMs.Float64
+	}
+	return r
+}
+
+// rawJSON avoids encoding an empty, invalid, document.
+func rawJSON(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
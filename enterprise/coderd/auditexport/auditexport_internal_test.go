@@ -0,0 +1,176 @@
+package auditexport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/quartz"
+)
+
+type fakeUploader struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// failKey is a key whose uploads fail.
+	failKey string
+}
+
+func (u *fakeUploader) Upload(_ context.Context, key string, body []byte) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if key == u.failKey {
+		return xerrors.New("upload failed")
+	}
+	u.objects[key] = body
+	return nil
+}
+
+// ids returns the IDs of the audit logs in the object, in order.
+func (u *fakeUploader) ids(t *testing.T, key string) []uuid.UUID {
+	t.Helper()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	body, ok := u.objects[key]
+	require.True(t, ok, "object %q not uploaded", key)
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var record auditLogRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		ids = append(ids, record.ID)
+	}
+	require.NoError(t, scanner.Err())
+	return ids
+}
+
+func TestExportAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	ctx := dbauthz.AsSystemRestricted(context.Background())
+	db, _ := dbtestutil.NewDB(t)
+	clk := quartz.NewMock(t)
+	uploader := &fakeUploader{objects: map[string][]byte{}}
+	e := newExporter(Options{
+		Logger:   slogtest.Make(t, nil),
+		Database: db,
+		Uploader: uploader,
+		Clock:    clk,
+		Prefix:   "coder",
+	})
+
+	// Given: audit logs in two hours a day apart, and in the current hour.
+	day1 := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	first := dbgen.AuditLog(t, db, database.AuditLog{Time: day1.Add(10 * time.Minute)})
+	second := dbgen.AuditLog(t, db, database.AuditLog{Time: day1.Add(50 * time.Minute)})
+	third := dbgen.AuditLog(t, db, database.AuditLog{Time: day2.Add(20 * time.Minute)})
+	current := dbgen.AuditLog(t, db, database.AuditLog{Time: day2.Add(70 * time.Minute)})
+	clk.Set(day2.Add(90 * time.Minute))
+
+	// When
+	require.NoError(t, e.run(ctx))
+
+	// Then: an object is uploaded for each complete hour with audit logs.
+	require.Len(t, uploader.objects, 2)
+	require.Equal(t, []uuid.UUID{first.ID, second.ID}, uploader.ids(t, "coder/audit_logs/year=2024/month=01/day=01/hour=08.jsonl.gz"))
+	require.Equal(t, []uuid.UUID{third.ID}, uploader.ids(t, "coder/audit_logs/year=2024/month=01/day=02/hour=09.jsonl.gz"))
+
+	export, err := db.GetAuditLogExport(ctx, DatasetAuditLogs)
+	require.NoError(t, err)
+	require.Equal(t, day2.Add(time.Hour), export.ExportedUntil.UTC())
+
+	// When: the export runs again within the hour.
+	clear(uploader.objects)
+	require.NoError(t, e.run(ctx))
+
+	// Then: nothing is exported twice.
+	require.Empty(t, uploader.objects)
+
+	// When: the current hour ends.
+	clk.Set(day2.Add(2*time.Hour + 10*time.Minute))
+	require.NoError(t, e.run(ctx))
+
+	// Then: it is exported.
+	require.Len(t, uploader.objects, 1)
+	require.Equal(t, []uuid.UUID{current.ID}, uploader.ids(t, "coder/audit_logs/year=2024/month=01/day=02/hour=10.jsonl.gz"))
+}
+
+func TestExportUploadFails(t *testing.T) {
+	t.Parallel()
+
+	ctx := dbauthz.AsSystemRestricted(context.Background())
+	db, _ := dbtestutil.NewDB(t)
+	clk := quartz.NewMock(t)
+	uploader := &fakeUploader{
+		objects: map[string][]byte{},
+		failKey: "audit_logs/year=2024/month=01/day=01/hour=09.jsonl.gz",
+	}
+	e := newExporter(Options{
+		Logger:   slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		Database: db,
+		Uploader: uploader,
+		Clock:    clk,
+	})
+
+	// Given: audit logs in two hours, the second of which fails to upload.
+	hour := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	first := dbgen.AuditLog(t, db, database.AuditLog{Time: hour.Add(10 * time.Minute)})
+	second := dbgen.AuditLog(t, db, database.AuditLog{Time: hour.Add(70 * time.Minute)})
+	clk.Set(hour.Add(3 * time.Hour))
+
+	// When
+	require.ErrorContains(t, e.run(ctx), "upload failed")
+
+	// Then: the cursor is advanced past the uploaded hour only.
+	require.Equal(t, []uuid.UUID{first.ID}, uploader.ids(t, "audit_logs/year=2024/month=01/day=01/hour=08.jsonl.gz"))
+	export, err := db.GetAuditLogExport(ctx, DatasetAuditLogs)
+	require.NoError(t, err)
+	require.Equal(t, hour.Add(time.Hour), export.ExportedUntil.UTC())
+
+	// When: the upload succeeds again.
+	clear(uploader.objects)
+	uploader.failKey = ""
+	require.NoError(t, e.run(ctx))
+
+	// Then: only the failed hour is exported.
+	require.Len(t, uploader.objects, 1)
+	require.Equal(t, []uuid.UUID{second.ID}, uploader.ids(t, "audit_logs/year=2024/month=01/day=01/hour=09.jsonl.gz"))
+}
+
+func TestExportNotEntitled(t *testing.T) {
+	t.Parallel()
+
+	ctx := dbauthz.AsSystemRestricted(context.Background())
+	db, _ := dbtestutil.NewDB(t)
+	clk := quartz.NewMock(t)
+	uploader := &fakeUploader{objects: map[string][]byte{}}
+	e := newExporter(Options{
+		Logger:   slogtest.Make(t, nil),
+		Database: db,
+		Uploader: uploader,
+		Clock:    clk,
+		Entitled: func() bool { return false },
+	})
+
+	_ = dbgen.AuditLog(t, db, database.AuditLog{Time: clk.Now().Add(-2 * time.Hour)})
+
+	require.NoError(t, e.run(ctx))
+	require.Empty(t, uploader.objects)
+}
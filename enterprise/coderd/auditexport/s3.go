@@ -0,0 +1,65 @@
+package auditexport
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/xerrors"
+)
+
+type S3Options struct {
+	Bucket string
+	// Region of the bucket. The region of the environment is used when
+	// empty.
+	Region string
+	// Endpoint of an S3 compatible service, e.g.
+	// https://storage.googleapis.com for Google Cloud Storage. Amazon S3 is
+	// used when empty.
+	Endpoint string
+}
+
+type s3Uploader struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3Uploader returns an uploader to an S3 bucket. Credentials are read from
+// the environment like the AWS CLI does, e.g. from AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY or the instance role.
+func NewS3Uploader(ctx context.Context, opts S3Options) (Uploader, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.Region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, xerrors.Errorf("load aws config: %w", err)
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if opts.Endpoint == "" {
+			return
+		}
+		o.BaseEndpoint = aws.String(opts.Endpoint)
+		// Most S3 compatible services do not support virtual hosted buckets
+		// nor the checksums Amazon S3 added over time.
+		o.UsePathStyle = true
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+	})
+	return &s3Uploader{client: client, bucket: opts.Bucket}, nil
+}
+
+func (u *s3Uploader) Upload(ctx context.Context, key string, body []byte) error {
+	_, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(u.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/gzip"),
+	})
+	if err != nil {
+		return xerrors.Errorf("put object: %w", err)
+	}
+	return nil
+}
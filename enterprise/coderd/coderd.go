@@ -334,6 +334,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Post("/refresh-entitlements", api.postRefreshEntitlements)
 			r.Post("/", api.postLicense)
 			r.Get("/", api.licenses)
+			r.Get("/seat-usage", api.licenseSeatUsage)
 			r.Delete("/{id}", api.deleteLicense)
 		})
 		r.Route("/applications/reconnecting-pty-signed-token", func(r chi.Router) {
@@ -460,6 +461,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				httpmw.ExtractUserParam(api.Database),
 			)
 			r.Get("/organizations/{organization}/members/{user}/workspace-quota", api.workspaceQuota)
+			r.Get("/organizations/{organization}/members/{user}/usage-quota", api.usageQuota)
 		})
 
 		r.Route("/organizations/{organization}/groups", func(r chi.Router) {
@@ -569,6 +571,11 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 					r.Put("/", api.upsertGroupAIBudget)
 					r.Delete("/", api.deleteGroupAIBudget)
 				})
+				r.Route("/usage-quotas", func(r chi.Router) {
+					r.Get("/", api.groupUsageQuotas)
+					r.Put("/{metric}", api.upsertGroupUsageQuota)
+					r.Delete("/{metric}", api.deleteGroupUsageQuota)
+				})
 			})
 		})
 		r.Route("/workspace-quota", func(r chi.Router) {
@@ -865,7 +872,9 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 		reloadedEntitlements, err := license.Entitlements(
 			ctx, api.Database,
-			len(agedReplicas), len(api.ExternalAuthConfigs), api.LicenseKeys, map[codersdk.FeatureName]bool{
+			len(agedReplicas), len(api.ExternalAuthConfigs),
+			api.DeploymentValues.LicenseSeatUsageWindow.Value(),
+			api.LicenseKeys, map[codersdk.FeatureName]bool{
 				codersdk.FeatureAuditLog:                   api.AuditLogging,
 				codersdk.FeatureConnectionLog:              api.ConnectionLogging,
 				codersdk.FeatureBrowserOnly:                api.BrowserOnly,
@@ -1098,8 +1107,9 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 var _ wsbuilder.UsageChecker = &API{}
 
 func (api *API) CheckBuildUsage(
-	_ context.Context,
-	_ database.Store,
+	ctx context.Context,
+	store database.Store,
+	workspace database.Workspace,
 	templateVersion *database.TemplateVersion,
 	task *database.Task,
 	transition database.WorkspaceTransition,
@@ -1116,10 +1126,19 @@ func (api *API) CheckBuildUsage(
 		}
 	}
 
+	if transition != database.WorkspaceTransitionStart {
+		return wsbuilder.UsageCheckResponse{Permitted: true}, nil
+	}
+
+	resp, err := api.checkUsageQuota(ctx, store, workspace)
+	if err != nil || !resp.Permitted {
+		return resp, err
+	}
+
 	// Verify managed agent entitlement for AI task builds.
 	// The count/limit check is intentionally omitted — breaching the
 	// limit is advisory only and surfaced as a warning via entitlements.
-	if transition != database.WorkspaceTransitionStart || task == nil {
+	if task == nil {
 		return wsbuilder.UsageCheckResponse{Permitted: true}, nil
 	}
 
@@ -29,6 +29,7 @@ func Entitlements(
 	db database.Store,
 	replicaCount int,
 	externalAuthCount int,
+	seatUsageWindow time.Duration,
 	keys map[string]ed25519.PublicKey,
 	enablements map[codersdk.FeatureName]bool,
 ) (codersdk.Entitlements, error) {
@@ -40,10 +41,9 @@ func Entitlements(
 		return codersdk.Entitlements{}, err
 	}
 
-	// nolint:gocritic // Getting active user count is a system function.
-	activeUserCount, err := db.GetActiveUserCount(dbauthz.AsSystemRestricted(ctx), false) // Don't include system user in license count.
+	seatUsage, err := SeatUsage(ctx, db, now, seatUsageWindow)
 	if err != nil {
-		return codersdk.Entitlements{}, xerrors.Errorf("query active user count: %w", err)
+		return codersdk.Entitlements{}, err
 	}
 
 	// nolint:gocritic // Getting active AI seat count is a system function.
@@ -64,7 +64,7 @@ func Entitlements(
 	}
 
 	entitlements, err := LicensesEntitlements(ctx, now, licenses, enablements, keys, FeatureArguments{
-		ActiveUserCount:       activeUserCount,
+		ActiveUserCount:       seatUsage.Seats,
 		ActiveAISeatCount:     activeAISeatCount,
 		ReplicaCount:          replicaCount,
 		ExternalAuthCount:     externalAuthCount,
@@ -93,6 +93,35 @@ func Entitlements(
 	return entitlements, nil
 }
 
+// SeatUsage counts the license seats consumed by the deployment. With a zero
+// usage window every active user consumes a seat, otherwise only users that
+// used a workspace within the window do.
+func SeatUsage(ctx context.Context, db database.Store, now time.Time, usageWindow time.Duration) (codersdk.LicenseSeatUsage, error) {
+	// nolint:gocritic // Getting active user count is a system function.
+	activeUserCount, err := db.GetActiveUserCount(dbauthz.AsSystemRestricted(ctx), false) // Don't include system user in license count.
+	if err != nil {
+		return codersdk.LicenseSeatUsage{}, xerrors.Errorf("query active user count: %w", err)
+	}
+
+	usage := codersdk.LicenseSeatUsage{
+		UsageWindowMillis: usageWindow.Milliseconds(),
+		ActiveUsers:       activeUserCount,
+		Seats:             activeUserCount,
+	}
+	if usageWindow <= 0 {
+		return usage, nil
+	}
+
+	// nolint:gocritic // Counting users with workspace usage is a system function.
+	usageUserCount, err := db.GetUserCountWithUsageSince(dbauthz.AsSystemRestricted(ctx), now.Add(-usageWindow))
+	if err != nil {
+		return codersdk.LicenseSeatUsage{}, xerrors.Errorf("query user count with usage: %w", err)
+	}
+	usage.UsageUsers = &usageUserCount
+	usage.Seats = usageUserCount
+	return usage, nil
+}
+
 type FeatureArguments struct {
 	ActiveUserCount       int64
 	ActiveAISeatCount     int64
@@ -266,6 +266,35 @@ func (api *API) licenses(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, sdkLicenses)
 }
 
+// @Summary Get license seat usage
+// @ID get-license-seat-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {object} codersdk.LicenseSeatUsage
+// @Router /api/v2/licenses/seat-usage [get]
+func (api *API) licenseSeatUsage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, policy.ActionRead, rbac.ResourceLicense) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	usage, err := license.SeatUsage(ctx, api.Database, dbtime.Now(), api.DeploymentValues.LicenseSeatUsageWindow.Value())
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching license seat usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if userLimit, ok := api.Entitlements.Feature(codersdk.FeatureUserLimit); ok && userLimit.Limit != nil {
+		limit := *userLimit.Limit
+		usage.Limit = &limit
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, usage)
+}
+
 // @Summary Delete license
 // @ID delete-license
 // @Security CoderSessionToken
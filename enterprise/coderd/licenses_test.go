@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
@@ -303,3 +304,45 @@ func TestDeleteLicense(t *testing.T) {
 		assert.Len(t, licenses, 0)
 	})
 }
+
+func TestLicenseSeatUsage(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	require.NoError(t, dv.LicenseSeatUsageWindow.Set("720h"))
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{DeploymentValues: dv},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserLimit: 10,
+			},
+		},
+	})
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		usage, err := client.LicenseSeatUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, (720 * time.Hour).Milliseconds(), usage.UsageWindowMillis)
+		assert.EqualValues(t, 2, usage.ActiveUsers)
+		// Neither user has used a workspace, so no seats are consumed.
+		require.NotNil(t, usage.UsageUsers)
+		assert.EqualValues(t, 0, *usage.UsageUsers)
+		assert.EqualValues(t, 0, usage.Seats)
+		require.NotNil(t, usage.Limit)
+		assert.EqualValues(t, 10, *usage.Limit)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.LicenseSeatUsage(ctx)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		assert.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+}
@@ -357,6 +357,7 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
 			OIDCConfig:          api.OIDCConfig,
 			AISeatTracker:       api.AGPL.AISeatTracker,
+			WebhookEnqueuer:     api.AGPL.WebhookEnqueuer,
 			Clock:               api.Clock,
 		},
 		api.NotificationsEnqueuer,
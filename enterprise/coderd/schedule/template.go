@@ -99,6 +99,7 @@ func (*EnterpriseTemplateScheduleStore) Get(ctx context.Context, db database.Sto
 		FailureTTL:               time.Duration(tpl.FailureTTL),
 		TimeTilDormant:           time.Duration(tpl.TimeTilDormant),
 		TimeTilDormantAutoDelete: time.Duration(tpl.TimeTilDormantAutoDelete),
+		DormancyActivitySignal:   codersdk.DormancyActivitySignal(tpl.DormancyActivitySignal),
 	}, nil
 }
 
@@ -122,6 +123,7 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 		int64(opts.FailureTTL) == tpl.FailureTTL &&
 		int64(opts.TimeTilDormant) == tpl.TimeTilDormant &&
 		int64(opts.TimeTilDormantAutoDelete) == tpl.TimeTilDormantAutoDelete &&
+		string(opts.DormancyActivitySignal) == tpl.DormancyActivitySignal &&
 		opts.UserAutostartEnabled == tpl.AllowUserAutostart &&
 		opts.UserAutostopEnabled == tpl.AllowUserAutostop {
 		// Avoid updating the UpdatedAt timestamp if nothing will be changed.
@@ -161,6 +163,7 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 			FailureTTL:               int64(opts.FailureTTL),
 			TimeTilDormant:           int64(opts.TimeTilDormant),
 			TimeTilDormantAutoDelete: int64(opts.TimeTilDormantAutoDelete),
+			DormancyActivitySignal:   string(opts.DormancyActivitySignal),
 		})
 		if err != nil {
 			return xerrors.Errorf("update template schedule: %w", err)
@@ -682,6 +682,50 @@ func TestTemplates(t *testing.T) {
 		require.True(t, updatedDormantWS.LastUsedAt.After(dormantWorkspace.LastUsedAt))
 	})
 
+	t.Run("DormancyActivitySignal", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAdvancedTemplateScheduling: 1,
+				},
+			},
+		})
+		anotherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.DormancyActivitySignal)
+
+		updated, err := anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DormancyActivitySignal: ptr.Ref(codersdk.DormancyActivitySignalTraffic),
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.DormancyActivitySignalTraffic, updated.DormancyActivitySignal)
+
+		// Other updates leave it as is.
+		updated, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: ptr.Ref("updated"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.DormancyActivitySignalTraffic, updated.DormancyActivitySignal)
+
+		// An empty signal resets it to the deployment default.
+		updated, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DormancyActivitySignal: ptr.Ref(codersdk.DormancyActivitySignal("")),
+		})
+		require.NoError(t, err)
+		require.Empty(t, updated.DormancyActivitySignal)
+
+		_, err = anotherClient.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DormancyActivitySignal: ptr.Ref(codersdk.DormancyActivitySignal("keystrokes")),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("RequireActiveVersion", func(t *testing.T) {
 		t.Parallel()
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
@@ -0,0 +1,278 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get group usage quotas
+// @ID get-group-usage-quotas
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group ID" format(uuid)
+// @Success 200 {array} codersdk.GroupUsageQuota
+// @Router /api/v2/groups/{group}/usage-quotas [get]
+func (api *API) groupUsageQuotas(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	group := httpmw.GroupParam(r)
+
+	quotas, err := api.Database.GetGroupUsageQuotas(ctx, group.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "get group usage quotas", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.GroupUsageQuota, 0, len(quotas))
+	for _, quota := range quotas {
+		resp = append(resp, db2sdk.GroupUsageQuota(quota))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Upsert group usage quota
+// @ID upsert-group-usage-quota
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group ID" format(uuid)
+// @Param metric path string true "Usage quota metric" Enums(connection_hours,active_hours)
+// @Param request body codersdk.UpsertGroupUsageQuotaRequest true "Upsert group usage quota request"
+// @Success 200 {object} codersdk.GroupUsageQuota
+// @Router /api/v2/groups/{group}/usage-quotas/{metric} [put]
+func (api *API) upsertGroupUsageQuota(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	group := httpmw.GroupParam(r)
+
+	metric, ok := parseUsageQuotaMetric(rw, r)
+	if !ok {
+		return
+	}
+
+	var req codersdk.UpsertGroupUsageQuotaRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Action == "" {
+		req.Action = codersdk.UsageQuotaActionReport
+	}
+	action := database.UsageQuotaAction(req.Action)
+	if !action.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid usage quota action %q.", req.Action),
+			Validations: []codersdk.ValidationError{
+				{Field: "action", Detail: fmt.Sprintf("must be one of %v", codersdk.UsageQuotaActions)},
+			},
+		})
+		return
+	}
+
+	quota, err := api.Database.UpsertGroupUsageQuota(ctx, database.UpsertGroupUsageQuotaParams{
+		GroupID:      group.ID,
+		Metric:       metric,
+		MonthlyHours: req.MonthlyHours,
+		Action:       action,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "upsert group usage quota", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.GroupUsageQuota(quota))
+}
+
+// @Summary Delete group usage quota
+// @ID delete-group-usage-quota
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param group path string true "Group ID" format(uuid)
+// @Param metric path string true "Usage quota metric" Enums(connection_hours,active_hours)
+// @Success 204
+// @Router /api/v2/groups/{group}/usage-quotas/{metric} [delete]
+func (api *API) deleteGroupUsageQuota(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	group := httpmw.GroupParam(r)
+
+	metric, ok := parseUsageQuotaMetric(rw, r)
+	if !ok {
+		return
+	}
+
+	_, err := api.Database.DeleteGroupUsageQuota(ctx, database.DeleteGroupUsageQuotaParams{
+		GroupID: group.ID,
+		Metric:  metric,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "delete group usage quota", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get usage quota by user
+// @ID get-usage-quota-by-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param user path string true "User ID, name, or me"
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.UsageQuota
+// @Router /api/v2/organizations/{organization}/members/{user}/usage-quota [get]
+func (api *API) usageQuota(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+		user         = httpmw.UserParam(r)
+	)
+
+	periodStart, periodEnd := usageQuotaPeriod(api.Clock.Now())
+
+	// There are no groups and thus no allowances if RBAC isn't licensed.
+	licensed := api.Entitlements.Enabled(codersdk.FeatureTemplateRBAC)
+	statuses, err := usageQuotaStatuses(ctx, api.Database, user.ID, organization.ID, periodStart, licensed)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get usage quota",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UsageQuota{
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Metrics:     statuses,
+	})
+}
+
+// checkUsageQuota refuses to start the workspace if its owner has exceeded a
+// usage quota whose action is to block starts.
+func (api *API) checkUsageQuota(ctx context.Context, store database.Store, workspace database.Workspace) (wsbuilder.UsageCheckResponse, error) {
+	// Usage quotas are granted through groups.
+	if !api.Entitlements.Enabled(codersdk.FeatureTemplateRBAC) {
+		return wsbuilder.UsageCheckResponse{Permitted: true}, nil
+	}
+
+	// The initiator of the build, e.g. an admin or the autostart executor,
+	// may not be allowed to read the usage of the owner.
+	//nolint:gocritic // Enforcing quotas is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	periodStart, _ := usageQuotaPeriod(api.Clock.Now())
+	statuses, err := usageQuotaStatuses(ctx, store, workspace.OwnerID, workspace.OrganizationID, periodStart, true)
+	if err != nil {
+		return wsbuilder.UsageCheckResponse{}, xerrors.Errorf("get usage quota: %w", err)
+	}
+	for _, status := range statuses {
+		if status.Exceeded && status.Action == codersdk.UsageQuotaActionBlockStart {
+			return wsbuilder.UsageCheckResponse{
+				Permitted: false,
+				Message:   fmt.Sprintf("the workspace owner has used %.1f of their %d %s this month.", status.ConsumedHours, *status.AllowanceHours, strings.ReplaceAll(string(status.Metric), "_", " ")),
+			}, nil
+		}
+	}
+	return wsbuilder.UsageCheckResponse{Permitted: true}, nil
+}
+
+// usageQuotaStatuses returns the consumption of the user since the start of
+// the period for every metric, along with their allowance if any.
+func usageQuotaStatuses(ctx context.Context, db database.Store, userID, organizationID uuid.UUID, periodStart time.Time, withAllowances bool) ([]codersdk.UsageQuotaStatus, error) {
+	allowances := make(map[database.UsageQuotaMetric]database.GetUsageQuotaAllowancesForUserRow)
+	if withAllowances {
+		rows, err := db.GetUsageQuotaAllowancesForUser(ctx, database.GetUsageQuotaAllowancesForUserParams{
+			UserID:         userID,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get allowances: %w", err)
+		}
+		for _, row := range rows {
+			allowances[row.Metric] = row
+		}
+	}
+
+	consumed, err := db.GetUsageQuotaConsumedForUser(ctx, database.GetUsageQuotaConsumedForUserParams{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		StartTime:      periodStart,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get consumed: %w", err)
+	}
+
+	statuses := make([]codersdk.UsageQuotaStatus, 0, len(database.AllUsageQuotaMetricValues()))
+	for _, metric := range database.AllUsageQuotaMetricValues() {
+		var mins int64
+		switch metric {
+		case database.UsageQuotaMetricConnectionHours:
+			mins = consumed.ConnectionMins
+		case database.UsageQuotaMetricActiveHours:
+			mins = consumed.ActiveMins
+		}
+
+		status := codersdk.UsageQuotaStatus{
+			Metric:        codersdk.UsageQuotaMetric(metric),
+			ConsumedHours: float64(mins) / 60,
+		}
+		if allowance, ok := allowances[metric]; ok {
+			status.AllowanceHours = ptr.Ref(allowance.MonthlyHours)
+			status.Action = codersdk.UsageQuotaAction(allowance.Action)
+			status.Exceeded = mins >= allowance.MonthlyHours*60
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// usageQuotaPeriod returns the bounds of the calendar month, in UTC, that
+// contains now.
+func usageQuotaPeriod(now time.Time) (start time.Time, end time.Time) {
+	now = now.UTC()
+	start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func parseUsageQuotaMetric(rw http.ResponseWriter, r *http.Request) (database.UsageQuotaMetric, bool) {
+	metric := database.UsageQuotaMetric(chi.URLParam(r, "metric"))
+	if !metric.Valid() {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid usage quota metric %q.", metric),
+			Detail:  fmt.Sprintf("Must be one of %v.", codersdk.UsageQuotaMetrics),
+		})
+		return "", false
+	}
+	return metric, true
+}
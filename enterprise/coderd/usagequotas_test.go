@@ -0,0 +1,159 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGroupUsageQuotas(t *testing.T) {
+	t.Parallel()
+
+	client, user := coderdenttest.New(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+		Name: "limited",
+	})
+	require.NoError(t, err)
+
+	quotas, err := client.GroupUsageQuotas(ctx, group.ID)
+	require.NoError(t, err)
+	require.Empty(t, quotas)
+
+	quota, err := client.UpsertGroupUsageQuota(ctx, group.ID, codersdk.UsageQuotaMetricConnectionHours, codersdk.UpsertGroupUsageQuotaRequest{
+		MonthlyHours: 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, group.ID, quota.GroupID)
+	require.Equal(t, codersdk.UsageQuotaMetricConnectionHours, quota.Metric)
+	require.EqualValues(t, 100, quota.MonthlyHours)
+	require.Equal(t, codersdk.UsageQuotaActionReport, quota.Action)
+
+	quota, err = client.UpsertGroupUsageQuota(ctx, group.ID, codersdk.UsageQuotaMetricConnectionHours, codersdk.UpsertGroupUsageQuotaRequest{
+		MonthlyHours: 120,
+		Action:       codersdk.UsageQuotaActionBlockStart,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 120, quota.MonthlyHours)
+	require.Equal(t, codersdk.UsageQuotaActionBlockStart, quota.Action)
+
+	quotas, err = client.GroupUsageQuotas(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, quotas, 1)
+
+	_, err = client.UpsertGroupUsageQuota(ctx, group.ID, "sandwiches", codersdk.UpsertGroupUsageQuotaRequest{
+		MonthlyHours: 1,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.UpsertGroupUsageQuota(ctx, group.ID, codersdk.UsageQuotaMetricActiveHours, codersdk.UpsertGroupUsageQuotaRequest{
+		MonthlyHours: 1,
+		Action:       "shrug",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = client.DeleteGroupUsageQuota(ctx, group.ID, codersdk.UsageQuotaMetricConnectionHours)
+	require.NoError(t, err)
+
+	err = client.DeleteGroupUsageQuota(ctx, group.ID, codersdk.UsageQuotaMetricConnectionHours)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestUsageQuota(t *testing.T) {
+	t.Parallel()
+
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	usage, err := member.UsageQuota(ctx, owner.OrganizationID.String(), codersdk.Me)
+	require.NoError(t, err)
+	require.True(t, usage.PeriodStart.Before(usage.PeriodEnd))
+	require.Len(t, usage.Metrics, 2)
+	for _, status := range usage.Metrics {
+		require.Zero(t, status.ConsumedHours)
+		require.Nil(t, status.AllowanceHours)
+		require.False(t, status.Exceeded)
+	}
+
+	// An allowance of zero hours is exceeded from the start. The Everyone
+	// group shares the organization's ID.
+	_, err = client.UpsertGroupUsageQuota(ctx, owner.OrganizationID, codersdk.UsageQuotaMetricActiveHours, codersdk.UpsertGroupUsageQuotaRequest{
+		MonthlyHours: 0,
+		Action:       codersdk.UsageQuotaActionReport,
+	})
+	require.NoError(t, err)
+
+	usage, err = member.UsageQuota(ctx, owner.OrganizationID.String(), memberUser.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, codersdk.UsageQuotaMetricActiveHours, usage.Metrics[1].Metric)
+	require.Equal(t, ptr.Ref(int64(0)), usage.Metrics[1].AllowanceHours)
+	require.Equal(t, codersdk.UsageQuotaActionReport, usage.Metrics[1].Action)
+	require.True(t, usage.Metrics[1].Exceeded)
+
+	// Reporting does not prevent starting workspaces.
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+	_, err = client.UpsertGroupUsageQuota(ctx, owner.OrganizationID, codersdk.UsageQuotaMetricActiveHours, codersdk.UpsertGroupUsageQuotaRequest{
+		MonthlyHours: 0,
+		Action:       codersdk.UsageQuotaActionBlockStart,
+	})
+	require.NoError(t, err)
+
+	_, err = member.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "blocked",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Stopping is still allowed.
+	build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+	// Lifting the allowance allows starting again.
+	err = client.DeleteGroupUsageQuota(ctx, owner.OrganizationID, codersdk.UsageQuotaMetricActiveHours)
+	require.NoError(t, err)
+
+	_, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+	})
+	require.NoError(t, err)
+}
@@ -205,6 +205,26 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		log.Debug(ctx, "encrypted user ai provider key", slog.F("user_ai_provider_key_id", key.ID), slog.F("ai_provider_id", key.AIProviderID), slog.F("user_id", key.UserID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	webhooks, err := cryptDB.GetWebhooks(ctx)
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	log.Info(ctx, "encrypting webhook secrets", slog.F("webhook_count", len(webhooks)))
+	for idx, webhook := range webhooks {
+		if webhook.SecretKeyID.Valid && webhook.SecretKeyID.String == ciphers[0].HexDigest() {
+			log.Debug(ctx, "skipping webhook", slog.F("webhook_id", webhook.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+			continue
+		}
+		if _, err := cryptDB.UpdateEncryptedWebhookSecret(ctx, database.UpdateEncryptedWebhookSecretParams{
+			ID:          webhook.ID,
+			Secret:      webhook.Secret,
+			SecretKeyID: sql.NullString{}, // dbcrypt will update as required
+		}); err != nil {
+			return xerrors.Errorf("update webhook id=%s: %w", webhook.ID, err)
+		}
+		log.Debug(ctx, "encrypted webhook secret", slog.F("webhook_id", webhook.ID), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
+	}
+
 	// Revoke old keys
 	for _, c := range ciphers[1:] {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -400,6 +420,26 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 		log.Debug(ctx, "decrypted user ai provider key", slog.F("user_ai_provider_key_id", key.ID), slog.F("ai_provider_id", key.AIProviderID), slog.F("user_id", key.UserID), slog.F("current", idx+1))
 	}
 
+	webhooks, err := cryptDB.GetWebhooks(ctx)
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	log.Info(ctx, "decrypting webhook secrets", slog.F("webhook_count", len(webhooks)))
+	for idx, webhook := range webhooks {
+		if !webhook.SecretKeyID.Valid {
+			log.Debug(ctx, "skipping webhook", slog.F("webhook_id", webhook.ID), slog.F("current", idx+1))
+			continue
+		}
+		if _, err := cryptDB.UpdateEncryptedWebhookSecret(ctx, database.UpdateEncryptedWebhookSecretParams{
+			ID:          webhook.ID,
+			Secret:      webhook.Secret,
+			SecretKeyID: sql.NullString{}, // explicitly clear the key id
+		}); err != nil {
+			return xerrors.Errorf("decrypt webhook id=%s: %w", webhook.ID, err)
+		}
+		log.Debug(ctx, "decrypted webhook secret", slog.F("webhook_id", webhook.ID), slog.F("current", idx+1))
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -439,6 +479,8 @@ UPDATE ai_providers
 	WHERE settings_key_id IS NOT NULL;
 DELETE FROM ai_provider_keys
 	WHERE api_key_key_id IS NOT NULL;
+DELETE FROM webhooks
+	WHERE secret_key_id IS NOT NULL;
 COMMIT;
 `
 
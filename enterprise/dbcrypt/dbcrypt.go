@@ -969,6 +969,74 @@ func (db *dbCrypt) UpdateGitSSHKey(ctx context.Context, params database.UpdateGi
 	return key, nil
 }
 
+func (db *dbCrypt) InsertWebhook(ctx context.Context, params database.InsertWebhookParams) (database.Webhook, error) {
+	if err := db.encryptField(&params.Secret, &params.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	webhook, err := db.Store.InsertWebhook(ctx, params)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+	if err := db.decryptField(&webhook.Secret, webhook.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (db *dbCrypt) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	webhook, err := db.Store.GetWebhookByID(ctx, id)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+	if err := db.decryptField(&webhook.Secret, webhook.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (db *dbCrypt) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	webhooks, err := db.Store.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		if err := db.decryptField(&webhooks[i].Secret, webhooks[i].SecretKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return webhooks, nil
+}
+
+// UpdateEncryptedWebhookSecret re-encrypts the secret column of a webhook,
+// so that dbcrypt key rotation can move every FK reference to a new key
+// digest before old keys are revoked.
+func (db *dbCrypt) UpdateEncryptedWebhookSecret(ctx context.Context, params database.UpdateEncryptedWebhookSecretParams) (database.Webhook, error) {
+	if err := db.encryptField(&params.Secret, &params.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	webhook, err := db.Store.UpdateEncryptedWebhookSecret(ctx, params)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+	if err := db.decryptField(&webhook.Secret, webhook.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (db *dbCrypt) AcquireWebhookDeliveries(ctx context.Context, params database.AcquireWebhookDeliveriesParams) ([]database.AcquireWebhookDeliveriesRow, error) {
+	deliveries, err := db.Store.AcquireWebhookDeliveries(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deliveries {
+		if err := db.decryptField(&deliveries[i].Secret, deliveries[i].SecretKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
 	if db.ciphers == nil || db.primaryCipherDigest == "" {
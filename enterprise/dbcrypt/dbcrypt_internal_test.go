@@ -1939,3 +1939,146 @@ func TestGitSSHKey(t *testing.T) {
 		require.False(t, rawKey.PrivateKeyKeyID.Valid)
 	})
 }
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	const (
+		initialSecret = "webhook-secret-initial"
+		rotatedSecret = "webhook-secret-rotated"
+	)
+
+	insertWebhook := func(t *testing.T, store database.Store, ciphers []Cipher) database.Webhook {
+		t.Helper()
+		user := dbgen.User(t, store, database.User{})
+		webhook, err := store.InsertWebhook(ctx, database.InsertWebhookParams{
+			ID:        uuid.New(),
+			CreatedAt: dbtime.Now(),
+			CreatedBy: user.ID,
+			Url:       "https://example.com/webhook",
+			Secret:    initialSecret,
+			Events:    []string{"workspace_build_succeeded"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, initialSecret, webhook.Secret)
+		if len(ciphers) > 0 {
+			require.True(t, webhook.SecretKeyID.Valid)
+			require.Equal(t, ciphers[0].HexDigest(), webhook.SecretKeyID.String)
+		}
+		return webhook
+	}
+
+	requireRawEncrypted := func(t *testing.T, db database.Store, id uuid.UUID, ciphers []Cipher, expected string) {
+		t.Helper()
+		raw, err := db.GetWebhookByID(ctx, id)
+		require.NoError(t, err)
+		require.NotEqual(t, expected, raw.Secret)
+		requireEncryptedEquals(t, ciphers[0], raw.Secret, expected)
+		require.Equal(t, ciphers[0].HexDigest(), raw.SecretKeyID.String)
+	}
+
+	t.Run("InsertWebhook", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		webhook := insertWebhook(t, crypt, ciphers)
+		requireRawEncrypted(t, db, webhook.ID, ciphers, initialSecret)
+	})
+
+	t.Run("GetWebhookByID", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, ciphers := setup(t)
+		webhook := insertWebhook(t, crypt, ciphers)
+
+		got, err := crypt.GetWebhookByID(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, initialSecret, got.Secret)
+		require.Equal(t, ciphers[0].HexDigest(), got.SecretKeyID.String)
+	})
+
+	t.Run("GetWebhooks", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, ciphers := setup(t)
+		webhook := insertWebhook(t, crypt, ciphers)
+
+		got, err := crypt.GetWebhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, webhook.ID, got[0].ID)
+		require.Equal(t, initialSecret, got[0].Secret)
+	})
+
+	t.Run("GetWebhookByIDReadsPlaintextRow", func(t *testing.T) {
+		// Webhooks created before encryption was enabled must remain readable.
+		t.Parallel()
+		db, crypt, _ := setup(t)
+		webhook := insertWebhook(t, db, nil)
+		require.False(t, webhook.SecretKeyID.Valid)
+
+		got, err := crypt.GetWebhookByID(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, initialSecret, got.Secret)
+		require.False(t, got.SecretKeyID.Valid)
+	})
+
+	t.Run("UpdateEncryptedWebhookSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		webhook := insertWebhook(t, db, nil)
+
+		updated, err := crypt.UpdateEncryptedWebhookSecret(ctx, database.UpdateEncryptedWebhookSecretParams{
+			ID:     webhook.ID,
+			Secret: rotatedSecret,
+		})
+		require.NoError(t, err)
+		require.Equal(t, rotatedSecret, updated.Secret)
+		require.Equal(t, ciphers[0].HexDigest(), updated.SecretKeyID.String)
+		requireRawEncrypted(t, db, webhook.ID, ciphers, rotatedSecret)
+	})
+
+	t.Run("AcquireWebhookDeliveries", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, ciphers := setup(t)
+		webhook := insertWebhook(t, crypt, ciphers)
+
+		now := dbtime.Now()
+		_, err := crypt.InsertWebhookDeliveries(ctx, database.InsertWebhookDeliveriesParams{
+			CreatedAt:  now,
+			Event:      "workspace_build_succeeded",
+			Payload:    []byte("{}"),
+			TemplateID: uuid.New(),
+		})
+		require.NoError(t, err)
+
+		deliveries, err := crypt.AcquireWebhookDeliveries(ctx, database.AcquireWebhookDeliveriesParams{
+			Now:        now,
+			LeaseUntil: now.Add(time.Minute),
+			LimitOpt:   10,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, webhook.ID, deliveries[0].WebhookID)
+		require.Equal(t, initialSecret, deliveries[0].Secret)
+	})
+
+	t.Run("GetWebhookByIDDecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		user := dbgen.User(t, db, database.User{})
+		webhook, err := db.InsertWebhook(ctx, database.InsertWebhookParams{
+			ID:          uuid.New(),
+			CreatedAt:   dbtime.Now(),
+			CreatedBy:   user.ID,
+			Url:         "https://example.com/webhook",
+			Secret:      fakeBase64RandomData(t, 32),
+			SecretKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+			Events:      []string{"workspace_build_succeeded"},
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetWebhookByID(ctx, webhook.ID)
+		require.Error(t, err)
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr)
+	})
+}
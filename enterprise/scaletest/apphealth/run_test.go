@@ -0,0 +1,128 @@
+package apphealth_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/scaletest/apphealth"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// The runner is tested here as it needs the enterprise external agent
+// credentials endpoint.
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) (*codersdk.Client, apphealth.Config) {
+		t.Helper()
+
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspaceExternalAgent: 1,
+				},
+			},
+		})
+		return client, apphealth.Config{
+			OrganizationID:            owner.OrganizationID,
+			ProvisionerType:           codersdk.ProvisionerTypeEcho,
+			Apps:                      2,
+			TemplateVersionJobTimeout: testutil.WaitLong,
+			WorkspaceBuildTimeout:     testutil.WaitLong,
+			PropagationTimeout:        testutil.WaitMedium,
+		}
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		t.Parallel()
+
+		client, cfg := newClient(t)
+		require.NoError(t, cfg.Validate())
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		runner := apphealth.NewRunner(client, cfg)
+		logs := bytes.NewBuffer(nil)
+		err := runner.Run(ctx, "1", logs)
+		require.NoError(t, err, logs.String())
+
+		// Only the initial report is sent, marking every app healthy.
+		metrics := runner.GetNumericMetrics()
+		require.EqualValues(t, 1, metrics[apphealth.ReportsMetric])
+		require.EqualValues(t, 2, metrics[apphealth.AppUpdatesMetric])
+		require.Contains(t, metrics, apphealth.PropagationMaxMetric)
+
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.ElementsMatch(t, []codersdk.WorkspaceAppHealth{
+			codersdk.WorkspaceAppHealthHealthy,
+			codersdk.WorkspaceAppHealthHealthy,
+		}, appHealths(workspaces.Workspaces[0]))
+
+		plan, err := runner.CleanupPlan(ctx, "1")
+		require.NoError(t, err)
+		require.Len(t, plan, 2)
+		err = runner.Cleanup(ctx, "1", logs)
+		require.NoError(t, err, logs.String())
+		templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+		require.NoError(t, err)
+		require.Empty(t, templates)
+		workspaces, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+
+	t.Run("Unhealthy", func(t *testing.T) {
+		t.Parallel()
+
+		client, cfg := newClient(t)
+		cfg.Reports = 1
+		cfg.Interval = time.Millisecond
+		cfg.FlipRatio = 1
+		require.NoError(t, cfg.Validate())
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		runner := apphealth.NewRunner(client, cfg)
+		logs := bytes.NewBuffer(nil)
+		err := runner.Run(ctx, "1", logs)
+		require.NoError(t, err, logs.String())
+
+		// The second report flips every app to unhealthy.
+		metrics := runner.GetNumericMetrics()
+		require.EqualValues(t, 2, metrics[apphealth.ReportsMetric])
+		require.EqualValues(t, 4, metrics[apphealth.AppUpdatesMetric])
+
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.ElementsMatch(t, []codersdk.WorkspaceAppHealth{
+			codersdk.WorkspaceAppHealthUnhealthy,
+			codersdk.WorkspaceAppHealthUnhealthy,
+		}, appHealths(workspaces.Workspaces[0]))
+
+		err = runner.Cleanup(ctx, "1", logs)
+		require.NoError(t, err, logs.String())
+	})
+}
+
+func appHealths(workspace codersdk.Workspace) []codersdk.WorkspaceAppHealth {
+	var health []codersdk.WorkspaceAppHealth
+	for _, res := range workspace.LatestBuild.Resources {
+		for _, agent := range res.Agents {
+			for _, app := range agent.Apps {
+				health = append(health, app.Health)
+			}
+		}
+	}
+	return health
+}
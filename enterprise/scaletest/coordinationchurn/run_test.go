@@ -0,0 +1,96 @@
+package coordinationchurn_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/scaletest/coordinationchurn"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// The runner is tested here as it needs the enterprise external agent
+// credentials endpoint.
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureWorkspaceExternalAgent: 1,
+			},
+		},
+	})
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Resources: []*proto.Resource{{
+						Type: "coder_external_agent",
+						Name: "main",
+						Agents: []*proto.Agent{{
+							Name:            "external-agent",
+							OperatingSystem: "linux",
+							Architecture:    "amd64",
+						}},
+					}},
+					HasExternalAgents: true,
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	cfg := coordinationchurn.Config{
+		Workspace: workspacebuild.Config{
+			OrganizationID: owner.OrganizationID,
+			UserID:         codersdk.Me,
+			Request: codersdk.CreateWorkspaceRequest{
+				TemplateID: template.ID,
+			},
+		},
+		Clients:             2,
+		Cycles:              2,
+		CoordinationTimeout: testutil.WaitMedium,
+	}
+	require.NoError(t, cfg.Validate())
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	runner := coordinationchurn.NewRunner(client, cfg)
+	logs := bytes.NewBuffer(nil)
+	err := runner.Run(ctx, "1", logs)
+	t.Log("Runner logs:\n\n" + logs.String())
+	require.NoError(t, err)
+	require.Contains(t, logs.String(), "Using external agent")
+
+	// Every cycle has one agent session and a session per client.
+	metrics := runner.GetNumericMetrics()
+	require.EqualValues(t, 2, metrics[coordinationchurn.CyclesCompletedMetric])
+	require.EqualValues(t, 6, metrics[coordinationchurn.SessionsMetric])
+	for _, name := range []string{
+		coordinationchurn.ClientCoordinationMeanMetric,
+		coordinationchurn.AgentFanoutMeanMetric,
+		coordinationchurn.DisconnectMeanMetric,
+	} {
+		require.Contains(t, metrics, name)
+	}
+
+	plan, err := runner.CleanupPlan(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	err = runner.Cleanup(ctx, "1", bytes.NewBuffer(nil))
+	require.NoError(t, err)
+}
@@ -0,0 +1,95 @@
+// Package annotate implements harness.Annotator for external monitoring
+// systems, so that the phases of a scaletest are visible on the dashboards of
+// the deployment under test.
+package annotate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// Multi posts every annotation to all of the given annotators. Annotating only
+// fails if it fails for every annotator, so wrap the annotators with LogErrors
+// to report individual failures.
+type Multi []harness.Annotator
+
+var _ harness.Annotator = Multi{}
+
+// Annotate implements harness.Annotator. The returned ID is only meaningful to
+// EndAnnotation of the same Multi.
+func (m Multi) Annotate(ctx context.Context, annotation harness.Annotation) (string, error) {
+	ids := make([]string, len(m))
+	var (
+		posted  bool
+		lastErr error
+	)
+	for i, a := range m {
+		id, err := a.Annotate(ctx, annotation)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ids[i] = id
+		posted = true
+	}
+	if !posted && lastErr != nil {
+		return "", lastErr
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return "", xerrors.Errorf("encode IDs: %w", err)
+	}
+	return string(data), nil
+}
+
+// EndAnnotation implements harness.Annotator.
+func (m Multi) EndAnnotation(ctx context.Context, id string, end time.Time) error {
+	var ids []string
+	if err := json.Unmarshal([]byte(id), &ids); err != nil || len(ids) != len(m) {
+		return xerrors.Errorf("invalid annotation ID %q", id)
+	}
+	var firstErr error
+	for i, a := range m {
+		if ids[i] == "" {
+			continue
+		}
+		if err := a.EndAnnotation(ctx, ids[i], end); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// LogErrors wraps an annotator and writes its errors to w, since the harness
+// ignores them.
+func LogErrors(a harness.Annotator, w io.Writer) harness.Annotator {
+	return logErrors{Annotator: a, w: w}
+}
+
+type logErrors struct {
+	harness.Annotator
+	w io.Writer
+}
+
+func (l logErrors) Annotate(ctx context.Context, annotation harness.Annotation) (string, error) {
+	id, err := l.Annotator.Annotate(ctx, annotation)
+	if err != nil {
+		_, _ = fmt.Fprintf(l.w, "Failed to post annotation %q: %v\n", annotation.Text, err)
+	}
+	return id, err
+}
+
+func (l logErrors) EndAnnotation(ctx context.Context, id string, end time.Time) error {
+	err := l.Annotator.EndAnnotation(ctx, id, end)
+	if err != nil {
+		_, _ = fmt.Fprintf(l.w, "Failed to end annotation %s: %v\n", id, err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package annotate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/annotate"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeAnnotator struct {
+	err   error
+	ended []string
+}
+
+func (f *fakeAnnotator) Annotate(_ context.Context, annotation harness.Annotation) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return annotation.Text, nil
+}
+
+func (f *fakeAnnotator) EndAnnotation(_ context.Context, id string, _ time.Time) error {
+	f.ended = append(f.ended, id)
+	return nil
+}
+
+func TestMulti(t *testing.T) {
+	t.Parallel()
+
+	ok := &fakeAnnotator{}
+	failing := &fakeAnnotator{err: xerrors.New("connection refused")}
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	// Annotating succeeds as long as one annotator succeeds.
+	m := annotate.Multi{failing, ok}
+	id, err := m.Annotate(ctx, harness.Annotation{Text: "run"})
+	require.NoError(t, err)
+	err = m.EndAnnotation(ctx, id, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []string{"run"}, ok.ended)
+	require.Empty(t, failing.ended)
+
+	_, err = annotate.Multi{failing}.Annotate(ctx, harness.Annotation{Text: "run"})
+	require.ErrorContains(t, err, "connection refused")
+}
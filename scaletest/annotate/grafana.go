@@ -0,0 +1,113 @@
+package annotate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// Grafana posts annotations to the HTTP API of a Grafana instance. The
+// annotations are organization wide, so they show up on every dashboard that
+// displays annotations matching their tags.
+type Grafana struct {
+	// URL is the base URL of the Grafana instance.
+	URL *url.URL
+	// Token is a Grafana service account token with permission to write
+	// annotations.
+	Token string
+	// Tags are added to every annotation, e.g. to distinguish tests against
+	// different deployments.
+	Tags []string
+	// HTTPClient is used to make requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ harness.Annotator = &Grafana{}
+
+type grafanaAnnotation struct {
+	Time    int64    `json:"time,omitempty"`
+	TimeEnd int64    `json:"timeEnd,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Text    string   `json:"text,omitempty"`
+}
+
+// Annotate implements harness.Annotator.
+func (g *Grafana) Annotate(ctx context.Context, annotation harness.Annotation) (string, error) {
+	body := grafanaAnnotation{
+		Time: annotation.Time.UnixMilli(),
+		Tags: append(annotation.Tags, g.Tags...),
+		Text: annotation.Text,
+	}
+	if !annotation.TimeEnd.IsZero() {
+		body.TimeEnd = annotation.TimeEnd.UnixMilli()
+	}
+
+	var resp struct {
+		ID      int64  `json:"id"`
+		Message string `json:"message"`
+	}
+	err := g.do(ctx, http.MethodPost, "/api/annotations", body, &resp)
+	if err != nil {
+		return "", xerrors.Errorf("add annotation: %w", err)
+	}
+	return strconv.FormatInt(resp.ID, 10), nil
+}
+
+// EndAnnotation implements harness.Annotator.
+func (g *Grafana) EndAnnotation(ctx context.Context, id string, end time.Time) error {
+	err := g.do(ctx, http.MethodPatch, "/api/annotations/"+url.PathEscape(id), grafanaAnnotation{
+		TimeEnd: end.UnixMilli(),
+	}, nil)
+	if err != nil {
+		return xerrors.Errorf("update annotation %s: %w", id, err)
+	}
+	return nil
+}
+
+func (g *Grafana) do(ctx context.Context, method, path string, body, res any) error {
+	if g.URL == nil {
+		return xerrors.New("no Grafana URL configured")
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return xerrors.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.URL.JoinPath(path).String(), bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	client := g.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return xerrors.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	if res == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return xerrors.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package annotate_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/annotate"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGrafana(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.UnixMilli(1700000000000)
+		end := start.Add(time.Minute)
+		var patched bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/grafana/api/annotations":
+				assert.Equal(t, map[string]any{
+					"time": float64(start.UnixMilli()),
+					"tags": []any{"scaletest", "run", "dogfood"},
+					"text": "Scaletest run",
+				}, body)
+				_, _ = w.Write([]byte(`{"id": 42, "message": "Annotation added"}`))
+			case r.Method == http.MethodPatch && r.URL.Path == "/grafana/api/annotations/42":
+				assert.Equal(t, map[string]any{"timeEnd": float64(end.UnixMilli())}, body)
+				patched = true
+				_, _ = w.Write([]byte(`{"message": "Annotation patched"}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL + "/grafana")
+		require.NoError(t, err)
+		g := &annotate.Grafana{
+			URL:   u,
+			Token: "secret",
+			Tags:  []string{"dogfood"},
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		id, err := g.Annotate(ctx, harness.Annotation{
+			Time: start,
+			Text: "Scaletest run",
+			Tags: []string{"scaletest", "run"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "42", id)
+
+		err = g.EndAnnotation(ctx, id, end)
+		require.NoError(t, err)
+		require.True(t, patched)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "invalid API key"}`))
+		}))
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		g := &annotate.Grafana{URL: u}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err = g.Annotate(ctx, harness.Annotation{Time: time.Now(), Text: "Scaletest run"})
+		require.ErrorContains(t, err, "unexpected status 401")
+		require.ErrorContains(t, err, "invalid API key")
+	})
+}
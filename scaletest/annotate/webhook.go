@@ -0,0 +1,103 @@
+package annotate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// WebhookEvent is the JSON body posted by Webhook.
+type WebhookEvent struct {
+	// ID identifies the annotation. The event ending an annotation has the
+	// same ID as the event starting it.
+	ID string `json:"id"`
+	// Type is either "annotation" or "annotation_end".
+	Type       string             `json:"type"`
+	Annotation harness.Annotation `json:"annotation"`
+}
+
+// Webhook event types.
+const (
+	WebhookEventAnnotation    = "annotation"
+	WebhookEventAnnotationEnd = "annotation_end"
+)
+
+// Webhook posts annotations as JSON encoded WebhookEvents to an arbitrary HTTP
+// endpoint, for monitoring systems other than Grafana.
+type Webhook struct {
+	URL string
+	// Headers are added to every request, e.g. for authentication.
+	Headers http.Header
+	// Tags are added to every annotation.
+	Tags []string
+	// HTTPClient is used to make requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ harness.Annotator = &Webhook{}
+
+// Annotate implements harness.Annotator.
+func (w *Webhook) Annotate(ctx context.Context, annotation harness.Annotation) (string, error) {
+	id := uuid.NewString()
+	annotation.Tags = append(annotation.Tags, w.Tags...)
+	err := w.post(ctx, WebhookEvent{
+		ID:         id,
+		Type:       WebhookEventAnnotation,
+		Annotation: annotation,
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// EndAnnotation implements harness.Annotator.
+func (w *Webhook) EndAnnotation(ctx context.Context, id string, end time.Time) error {
+	return w.post(ctx, WebhookEvent{
+		ID:   id,
+		Type: WebhookEventAnnotationEnd,
+		Annotation: harness.Annotation{
+			TimeEnd: end,
+		},
+	})
+}
+
+func (w *Webhook) post(ctx context.Context, event WebhookEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return xerrors.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	for name, values := range w.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("post %s event: %w", event.Type, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return xerrors.Errorf("post %s event: unexpected status %d: %s", event.Type, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
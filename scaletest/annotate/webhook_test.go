@@ -0,0 +1,66 @@
+package annotate_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/annotate"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mut    sync.Mutex
+		events []annotate.WebhookEvent
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("X-Api-Key"))
+		var event annotate.WebhookEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		mut.Lock()
+		events = append(events, event)
+		mut.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	wh := &annotate.Webhook{
+		URL:     srv.URL,
+		Headers: http.Header{"X-Api-Key": []string{"token"}},
+		Tags:    []string{"dogfood"},
+	}
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	end := start.Add(time.Minute)
+	ctx := testutil.Context(t, testutil.WaitShort)
+	id, err := wh.Annotate(ctx, harness.Annotation{
+		Time: start,
+		Text: "Scaletest cleanup",
+		Tags: []string{"scaletest", "cleanup"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	err = wh.EndAnnotation(ctx, id, end)
+	require.NoError(t, err)
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Len(t, events, 2)
+	require.Equal(t, annotate.WebhookEventAnnotation, events[0].Type)
+	require.Equal(t, id, events[0].ID)
+	require.Equal(t, "Scaletest cleanup", events[0].Annotation.Text)
+	require.True(t, start.Equal(events[0].Annotation.Time))
+	require.Equal(t, []string{"scaletest", "cleanup", "dogfood"}, events[0].Annotation.Tags)
+	require.Equal(t, annotate.WebhookEventAnnotationEnd, events[1].Type)
+	require.Equal(t, id, events[1].ID)
+	require.True(t, end.Equal(events[1].Annotation.TimeEnd))
+}
@@ -0,0 +1,41 @@
+package apireplay
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type Config struct {
+	// Routes are the API routes to request. Each request picks a route at
+	// random according to the route weights.
+	Routes []Route `json:"routes"`
+	// Interval is the average interval between requests.
+	Interval time.Duration `json:"interval"`
+	// Jitter is the maximum random amount the interval is shortened or
+	// lengthened by.
+	Jitter time.Duration `json:"jitter"`
+	// Metrics records the duration and errors of each request, if set.
+	Metrics Metrics `json:"-"`
+}
+
+func (c Config) Validate() error {
+	if len(c.Routes) == 0 {
+		return xerrors.New("validate routes: must not be empty")
+	}
+	for i, route := range c.Routes {
+		if err := route.Validate(); err != nil {
+			return xerrors.Errorf("validate routes[%d]: %w", i, err)
+		}
+	}
+
+	if !(c.Interval > 0) {
+		return xerrors.Errorf("validate interval: must be greater than zero")
+	}
+
+	if c.Jitter < 0 || !(c.Jitter < c.Interval) {
+		return xerrors.Errorf("validate jitter: must be between zero and interval")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package apireplay
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics interface {
+	ObserveDuration(route string, d time.Duration)
+	IncErrors(route string)
+}
+
+type PromMetrics struct {
+	durationSeconds *prometheus.HistogramVec
+	errors          *prometheus.CounterVec
+}
+
+func NewMetrics(reg prometheus.Registerer) *PromMetrics {
+	m := &PromMetrics{
+		durationSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "scaletest_api_replay",
+			Name:      "duration_seconds",
+		}, []string{"route"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "scaletest_api_replay",
+			Name:      "errors_total",
+		}, []string{"route"}),
+	}
+
+	reg.MustRegister(m.durationSeconds)
+	reg.MustRegister(m.errors)
+	return m
+}
+
+func (p *PromMetrics) ObserveDuration(route string, d time.Duration) {
+	p.durationSeconds.WithLabelValues(route).Observe(d.Seconds())
+}
+
+func (p *PromMetrics) IncErrors(route string) {
+	p.errors.WithLabelValues(route).Inc()
+}
+
+// WithRoutePrefix returns metrics that record routes with the given prefix
+// added to their names, e.g. to distinguish the variants of an A/B test.
+func WithRoutePrefix(m Metrics, prefix string) Metrics {
+	return prefixedMetrics{m: m, prefix: prefix}
+}
+
+type prefixedMetrics struct {
+	m      Metrics
+	prefix string
+}
+
+func (p prefixedMetrics) ObserveDuration(route string, d time.Duration) {
+	p.m.ObserveDuration(p.prefix+route, d)
+}
+
+func (p prefixedMetrics) IncErrors(route string) {
+	p.m.IncErrors(p.prefix + route)
+}
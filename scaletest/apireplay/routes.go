@@ -0,0 +1,154 @@
+package apireplay
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Route is an API route to replay against coderd.
+type Route struct {
+	// Name identifies the route in logs and metrics. Defaults to the method
+	// and path.
+	Name string `json:"name,omitempty"`
+	// Method is the HTTP method of the request. Only read-only methods are
+	// allowed, as replaying writes would modify the deployment.
+	Method string `json:"method"`
+	// Path is the path and query of the request, e.g.
+	// "/api/v2/workspaces?q=owner:me".
+	Path string `json:"path"`
+	// Weight is how often the route is requested relative to the other
+	// routes.
+	Weight int `json:"weight"`
+}
+
+func (r Route) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Method + " " + r.Path
+}
+
+func (r Route) Validate() error {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return xerrors.Errorf("method %q is not allowed, only GET and HEAD requests are replayed", r.Method)
+	}
+	if !strings.HasPrefix(r.Path, "/api/") {
+		return xerrors.Errorf("path %q must start with /api/", r.Path)
+	}
+	if r.Weight <= 0 {
+		return xerrors.New("weight must be greater than zero")
+	}
+	return nil
+}
+
+// ParseRoutes parses a JSON list of routes.
+func ParseRoutes(r io.Reader) ([]Route, error) {
+	var routes []Route
+	err := json.NewDecoder(r).Decode(&routes)
+	if err != nil {
+		return nil, xerrors.Errorf("decode routes: %w", err)
+	}
+	for i, route := range routes {
+		if route.Weight == 0 {
+			routes[i].Weight = 1
+		}
+		routes[i].Method = strings.ToUpper(route.Method)
+	}
+	return routes, nil
+}
+
+// harFile is the subset of the HAR 1.2 format needed to extract routes.
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method string `json:"method"`
+				URL    string `json:"url"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// ParseHAR extracts the coderd API routes from a HAR recording, e.g. one
+// exported from the browser's developer tools while using the dashboard.
+// Requests that aren't read-only or aren't to the API are ignored. Each
+// distinct route is weighted by how many times it was requested in the
+// recording, so replaying it approximates the recorded traffic mix.
+func ParseHAR(r io.Reader) ([]Route, error) {
+	var har harFile
+	err := json.NewDecoder(r).Decode(&har)
+	if err != nil {
+		return nil, xerrors.Errorf("decode HAR: %w", err)
+	}
+
+	weights := map[Route]int{}
+	for _, entry := range har.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, xerrors.Errorf("parse request URL %q: %w", entry.Request.URL, err)
+		}
+		route := Route{
+			Method: strings.ToUpper(entry.Request.Method),
+			Path:   u.RequestURI(),
+			Weight: 1,
+		}
+		if route.Validate() != nil {
+			continue
+		}
+		route.Weight = 0
+		weights[route]++
+	}
+
+	routes := make([]Route, 0, len(weights))
+	for route, weight := range weights {
+		route.Weight = weight
+		routes = append(routes, route)
+	}
+	slices.SortFunc(routes, func(a, b Route) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return routes, nil
+}
+
+// PathRewrite replaces the Old prefix of route paths with New, e.g. to replay
+// the same traffic against an experimental version of an API route.
+type PathRewrite struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ParsePathRewrite parses a path rewrite in the format OLD=NEW.
+func ParsePathRewrite(s string) (PathRewrite, error) {
+	oldPrefix, newPrefix, ok := strings.Cut(s, "=")
+	if !ok || oldPrefix == "" || newPrefix == "" {
+		return PathRewrite{}, xerrors.Errorf("path rewrite %q must be in the format OLD=NEW", s)
+	}
+	if !strings.HasPrefix(newPrefix, "/api/") {
+		return PathRewrite{}, xerrors.Errorf("path rewrite %q must rewrite to a path starting with /api/", s)
+	}
+	return PathRewrite{Old: oldPrefix, New: newPrefix}, nil
+}
+
+// RewriteRoutes returns a copy of the routes with the first matching rewrite
+// applied to each path. Rewritten routes keep the name of the original route,
+// so that the requests of both versions can be matched up.
+func RewriteRoutes(routes []Route, rewrites []PathRewrite) []Route {
+	rewritten := make([]Route, len(routes))
+	for i, route := range routes {
+		for _, rewrite := range rewrites {
+			if rest, ok := strings.CutPrefix(route.Path, rewrite.Old); ok {
+				route.Name = route.String()
+				route.Path = rewrite.New + rest
+				break
+			}
+		}
+		rewritten[i] = route
+	}
+	return rewritten
+}
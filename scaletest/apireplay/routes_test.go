@@ -0,0 +1,142 @@
+package apireplay_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/apireplay"
+)
+
+func Test_ParseHAR(t *testing.T) {
+	t.Parallel()
+
+	har := `{"log": {"entries": [
+		{"request": {"method": "GET", "url": "https://coder.example.com/api/v2/workspaces?q=owner:me"}},
+		{"request": {"method": "GET", "url": "https://coder.example.com/api/v2/users/me"}},
+		{"request": {"method": "GET", "url": "https://coder.example.com/api/v2/workspaces?q=owner:me"}},
+		{"request": {"method": "POST", "url": "https://coder.example.com/api/v2/users/me/workspaces"}},
+		{"request": {"method": "GET", "url": "https://coder.example.com/assets/index.js"}}
+	]}}`
+
+	routes, err := apireplay.ParseHAR(strings.NewReader(har))
+	require.NoError(t, err)
+	require.Equal(t, []apireplay.Route{
+		{Method: "GET", Path: "/api/v2/users/me", Weight: 1},
+		{Method: "GET", Path: "/api/v2/workspaces?q=owner:me", Weight: 2},
+	}, routes)
+}
+
+func Test_ParseRoutes(t *testing.T) {
+	t.Parallel()
+
+	routes, err := apireplay.ParseRoutes(strings.NewReader(`[
+		{"name": "workspaces", "method": "get", "path": "/api/v2/workspaces", "weight": 5},
+		{"method": "GET", "path": "/api/v2/templates"}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, []apireplay.Route{
+		{Name: "workspaces", Method: "GET", Path: "/api/v2/workspaces", Weight: 5},
+		{Method: "GET", Path: "/api/v2/templates", Weight: 1},
+	}, routes)
+	require.Equal(t, "workspaces", routes[0].String())
+	require.Equal(t, "GET /api/v2/templates", routes[1].String())
+
+	_, err = apireplay.ParseRoutes(strings.NewReader(`{}`))
+	require.Error(t, err)
+}
+
+func Test_RewriteRoutes(t *testing.T) {
+	t.Parallel()
+
+	_, err := apireplay.ParsePathRewrite("/api/v2/workspaces")
+	require.ErrorContains(t, err, "must be in the format OLD=NEW")
+	_, err = apireplay.ParsePathRewrite("/api/v2/workspaces=/workspaces")
+	require.ErrorContains(t, err, "must rewrite to a path starting with /api/")
+	rewrite, err := apireplay.ParsePathRewrite("/api/v2/workspaces=/api/experimental/workspaces")
+	require.NoError(t, err)
+
+	routes := []apireplay.Route{
+		{Method: "GET", Path: "/api/v2/workspaces?q=owner:me", Weight: 2},
+		{Name: "me", Method: "GET", Path: "/api/v2/users/me", Weight: 1},
+	}
+	rewritten := apireplay.RewriteRoutes(routes, []apireplay.PathRewrite{rewrite})
+	require.Equal(t, []apireplay.Route{
+		{Name: "GET /api/v2/workspaces?q=owner:me", Method: "GET", Path: "/api/experimental/workspaces?q=owner:me", Weight: 2},
+		{Name: "me", Method: "GET", Path: "/api/v2/users/me", Weight: 1},
+	}, rewritten)
+	// The original routes are left untouched.
+	require.Equal(t, "/api/v2/workspaces?q=owner:me", routes[0].Path)
+}
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		config      apireplay.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: apireplay.Config{
+				Routes:   []apireplay.Route{{Method: "GET", Path: "/api/v2/users/me", Weight: 1}},
+				Interval: 1,
+			},
+		},
+		{
+			name: "NoRoutes",
+			config: apireplay.Config{
+				Interval: 1,
+			},
+			errContains: "routes: must not be empty",
+		},
+		{
+			name: "WriteRoute",
+			config: apireplay.Config{
+				Routes:   []apireplay.Route{{Method: "DELETE", Path: "/api/v2/users/me", Weight: 1}},
+				Interval: 1,
+			},
+			errContains: `method "DELETE" is not allowed`,
+		},
+		{
+			name: "NotAPI",
+			config: apireplay.Config{
+				Routes:   []apireplay.Route{{Method: "GET", Path: "/workspaces", Weight: 1}},
+				Interval: 1,
+			},
+			errContains: "must start with /api/",
+		},
+		{
+			name: "NoWeight",
+			config: apireplay.Config{
+				Routes:   []apireplay.Route{{Method: "GET", Path: "/api/v2/users/me"}},
+				Interval: 1,
+			},
+			errContains: "weight must be greater than zero",
+		},
+		{
+			name: "JitterTooLarge",
+			config: apireplay.Config{
+				Routes:   []apireplay.Route{{Method: "GET", Path: "/api/v2/users/me", Weight: 1}},
+				Interval: 1,
+				Jitter:   1,
+			},
+			errContains: "jitter",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.ErrorContains(t, err, c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
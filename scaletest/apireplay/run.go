@@ -0,0 +1,168 @@
+package apireplay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+// Numeric metrics reported by the runner.
+const (
+	RequestsMetric         = "requests"
+	RequestErrorsMetric    = "request_errors"
+	RequestErrorRateMetric = "request_error_rate"
+	RequestMeanMetric      = "request_seconds_mean"
+	RequestMaxMetric       = "request_seconds_max"
+)
+
+// Runner requests API routes as an authenticated user until its context is
+// done, to simulate the read-heavy load of the dashboard.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	requests      atomic.Int64
+	errors        atomic.Int64
+	totalDuration atomic.Int64
+	maxDuration   atomic.Int64
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable. Failed requests are logged and counted, the run
+// only fails if no request succeeded.
+func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) error {
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+
+	me, err := r.client.User(ctx, codersdk.Me)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	//nolint:gocritic
+	logger.Info(ctx, "replaying api routes", slog.F("username", me.Username), slog.F("routes", len(r.cfg.Routes)))
+
+	var totalWeight int
+	for _, route := range r.cfg.Routes {
+		totalWeight += route.Weight
+	}
+	rng := harness.Rand(ctx)
+
+	t := time.NewTimer(0) // First one should be immediate.
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			//nolint:gocritic
+			logger.Info(ctx, "replay summary",
+				slog.F("requests", r.requests.Load()),
+				slog.F("errors", r.errors.Load()),
+			)
+			if r.requests.Load() > 0 && r.requests.Load() == r.errors.Load() {
+				return xerrors.Errorf("all %d requests failed, see logs for details", r.requests.Load())
+			}
+			return nil
+		case <-t.C:
+		}
+
+		wait := r.cfg.Interval
+		if r.cfg.Jitter > 0 {
+			wait += time.Duration(rng.Int63n(int64(2*r.cfg.Jitter))) - r.cfg.Jitter
+		}
+		t.Reset(wait)
+
+		route := pickRoute(r.cfg.Routes, rng.Intn(totalWeight))
+		took, err := r.request(ctx, route)
+		if err != nil && ctx.Err() != nil {
+			// The request was interrupted because the test is over.
+			continue
+		}
+
+		r.requests.Add(1)
+		r.totalDuration.Add(int64(took))
+		for {
+			prev := r.maxDuration.Load()
+			if int64(took) <= prev || r.maxDuration.CompareAndSwap(prev, int64(took)) {
+				break
+			}
+		}
+		if r.cfg.Metrics != nil {
+			r.cfg.Metrics.ObserveDuration(route.String(), took)
+		}
+		if err != nil {
+			r.errors.Add(1)
+			if r.cfg.Metrics != nil {
+				r.cfg.Metrics.IncErrors(route.String())
+			}
+			logger.Error(ctx, "request failed", slog.F("route", route.String()), slog.Error(err))
+			continue
+		}
+		logger.Debug(ctx, "request succeeded", slog.F("route", route.String()), slog.F("took", took))
+	}
+}
+
+// pickRoute returns the route that n falls into, where n is in
+// [0, total weight).
+func pickRoute(routes []Route, n int) Route {
+	for _, route := range routes {
+		if n < route.Weight {
+			return route
+		}
+		n -= route.Weight
+	}
+	return routes[len(routes)-1]
+}
+
+func (r *Runner) request(ctx context.Context, route Route) (time.Duration, error) {
+	start := time.Now()
+	res, err := r.client.Request(ctx, route.Method, route.Path, nil)
+	if err != nil {
+		return time.Since(start), xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return time.Since(start), codersdk.ReadBodyAsError(res)
+	}
+	_, err = io.Copy(io.Discard, res.Body)
+	took := time.Since(start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return took, xerrors.Errorf("read response body: %w", err)
+	}
+	return took, nil
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	requests := r.requests.Load()
+	metrics := map[string]float64{
+		RequestsMetric:      float64(requests),
+		RequestErrorsMetric: float64(r.errors.Load()),
+		RequestMaxMetric:    time.Duration(r.maxDuration.Load()).Seconds(),
+	}
+	if requests > 0 {
+		metrics[RequestErrorRateMetric] = float64(r.errors.Load()) / float64(requests)
+		metrics[RequestMeanMetric] = (time.Duration(r.totalDuration.Load()) / time.Duration(requests)).Seconds()
+	}
+	return metrics
+}
@@ -0,0 +1,95 @@
+package apireplay_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/apireplay"
+)
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		hits = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v2/users/me":
+			httpapi.Write(r.Context(), w, http.StatusOK, codersdk.User{})
+		case "/api/v2/workspaces":
+			if r.URL.Query().Get("q") != "owner:me" {
+				httpapi.Write(r.Context(), w, http.StatusBadRequest, codersdk.Response{Message: "missing query"})
+				return
+			}
+			httpapi.Write(r.Context(), w, http.StatusOK, codersdk.WorkspacesResponse{})
+		default:
+			httpapi.Write(r.Context(), w, http.StatusNotFound, codersdk.Response{Message: "not found"})
+		}
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	metrics := &fakeMetrics{errors: map[string]int{}}
+	runner := apireplay.NewRunner(codersdk.New(u), apireplay.Config{
+		Routes: []apireplay.Route{
+			{Method: "GET", Path: "/api/v2/workspaces?q=owner:me", Weight: 3},
+			{Name: "missing", Method: "GET", Path: "/api/v2/missing", Weight: 1},
+		},
+		Interval: time.Millisecond,
+		Metrics:  metrics,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	err = runner.Run(ctx, "1", io.Discard)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Positive(t, hits["/api/v2/workspaces"])
+	require.Positive(t, hits["/api/v2/missing"])
+	require.Greater(t, hits["/api/v2/workspaces"], hits["/api/v2/missing"])
+
+	numeric := runner.GetNumericMetrics()
+	require.Positive(t, numeric[apireplay.RequestsMetric])
+	require.Positive(t, numeric[apireplay.RequestErrorsMetric])
+	require.Less(t, numeric[apireplay.RequestErrorRateMetric], float64(1))
+	require.Positive(t, metrics.errorCount("missing"))
+	require.Zero(t, metrics.errorCount("GET /api/v2/workspaces?q=owner:me"))
+}
+
+type fakeMetrics struct {
+	mu     sync.Mutex
+	errors map[string]int
+}
+
+func (*fakeMetrics) ObserveDuration(string, time.Duration) {}
+
+func (m *fakeMetrics) IncErrors(route string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[route]++
+}
+
+func (m *fakeMetrics) errorCount(route string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.errors[route]
+}
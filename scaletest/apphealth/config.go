@@ -0,0 +1,76 @@
+package apphealth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type Config struct {
+	// OrganizationID is the ID of the organization to create the template and
+	// workspace in.
+	OrganizationID uuid.UUID `json:"organization_id"`
+	// ProvisionerType is the provisioner that imports the template version
+	// and builds the workspace. Defaults to Terraform. The echo provisioner
+	// skips Terraform entirely.
+	ProvisionerType codersdk.ProvisionerType `json:"provisioner_type"`
+	// ProvisionerTags are optional tags used to route the template version
+	// import and workspace build jobs to specific provisioner daemons.
+	ProvisionerTags map[string]string `json:"provisioner_tags"`
+	// Apps is the number of coder_apps with health checks defined on the
+	// workspace agent.
+	Apps int `json:"apps"`
+	// Reports is the number of app health reports sent by the agent after the
+	// initial report that marks every app as healthy.
+	Reports int `json:"reports"`
+	// Interval is the time to wait between reports.
+	Interval time.Duration `json:"interval"`
+	// FlipRatio is the fraction of apps, between 0 and 1, whose health
+	// changes with every report. At least one app changes per report.
+	FlipRatio float64 `json:"flip_ratio"`
+	// TemplateVersionJobTimeout is how long to wait for the template version
+	// import job to complete.
+	TemplateVersionJobTimeout time.Duration `json:"template_version_job_timeout"`
+	// WorkspaceBuildTimeout is how long to wait for the workspace build to
+	// complete.
+	WorkspaceBuildTimeout time.Duration `json:"workspace_build_timeout"`
+	// PropagationTimeout is how long to wait for a report to be visible on
+	// the workspace through the API.
+	PropagationTimeout time.Duration `json:"propagation_timeout"`
+}
+
+func (c Config) Validate() error {
+	if c.OrganizationID == uuid.Nil {
+		return xerrors.New("organization_id must be set")
+	}
+	switch c.ProvisionerType {
+	case "", codersdk.ProvisionerTypeTerraform, codersdk.ProvisionerTypeEcho:
+	default:
+		return xerrors.Errorf("unsupported provisioner_type %q", c.ProvisionerType)
+	}
+	if c.Apps <= 0 {
+		return xerrors.New("apps must be greater than 0")
+	}
+	if c.Reports < 0 {
+		return xerrors.New("reports must be at least 0")
+	}
+	if c.Interval < 0 {
+		return xerrors.New("interval must be at least 0")
+	}
+	if c.FlipRatio < 0 || c.FlipRatio > 1 {
+		return xerrors.New("flip_ratio must be between 0 and 1")
+	}
+	if c.TemplateVersionJobTimeout <= 0 {
+		return xerrors.New("template_version_job_timeout must be greater than 0")
+	}
+	if c.WorkspaceBuildTimeout <= 0 {
+		return xerrors.New("workspace_build_timeout must be greater than 0")
+	}
+	if c.PropagationTimeout <= 0 {
+		return xerrors.New("propagation_timeout must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package apphealth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/apphealth"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	cases := []struct {
+		name        string
+		config      apphealth.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+		},
+		{
+			name: "NoReports",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+		},
+		{
+			name: "EchoProvisioner",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				ProvisionerType:           codersdk.ProvisionerTypeEcho,
+				Apps:                      10,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+		},
+		{
+			name: "UnsupportedProvisioner",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				ProvisionerType:           "pulumi",
+				Apps:                      10,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: `unsupported provisioner_type "pulumi"`,
+		},
+		{
+			name: "NoOrganizationID",
+			config: apphealth.Config{
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "organization_id must be set",
+		},
+		{
+			name: "NoApps",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "apps must be greater than 0",
+		},
+		{
+			name: "NegativeReports",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   -1,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "reports must be at least 0",
+		},
+		{
+			name: "NegativeInterval",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  -time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "interval must be at least 0",
+		},
+		{
+			name: "NegativeFlipRatio",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 -0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "flip_ratio must be between 0 and 1",
+		},
+		{
+			name: "FlipRatioTooLarge",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 1.5,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "flip_ratio must be between 0 and 1",
+		},
+		{
+			name: "NoTemplateVersionJobTimeout",
+			config: apphealth.Config{
+				OrganizationID:        id,
+				Apps:                  10,
+				Reports:               5,
+				Interval:              time.Second,
+				FlipRatio:             0.1,
+				WorkspaceBuildTimeout: time.Minute,
+				PropagationTimeout:    time.Minute,
+			},
+			errContains: "template_version_job_timeout must be greater than 0",
+		},
+		{
+			name: "NoWorkspaceBuildTimeout",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				PropagationTimeout:        time.Minute,
+			},
+			errContains: "workspace_build_timeout must be greater than 0",
+		},
+		{
+			name: "NoPropagationTimeout",
+			config: apphealth.Config{
+				OrganizationID:            id,
+				Apps:                      10,
+				Reports:                   5,
+				Interval:                  time.Second,
+				FlipRatio:                 0.1,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+			},
+			errContains: "propagation_timeout must be greater than 0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,488 @@
+package apphealth
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+// TemplatePrefix is the name prefix applied to all templates created by the
+// app health runner.
+const TemplatePrefix = "scaletest-app-health-"
+
+// Numeric metrics reported by the runner.
+const (
+	ReportsMetric          = "app_health_reports"
+	AppUpdatesMetric       = "app_health_updates"
+	UpdatesPerSecondMetric = "app_health_updates_per_second"
+	ReportMeanMetric       = "report_seconds_mean"
+	ReportMaxMetric        = "report_seconds_max"
+	PropagationMeanMetric  = "propagation_seconds_mean"
+	PropagationMaxMetric   = "propagation_seconds_max"
+)
+
+const (
+	externalAgentResourceType = "coder_external_agent"
+	jobPollInterval           = time.Second
+)
+
+// Runner creates a template whose agent defines a number of coder_apps with
+// health checks, builds a workspace from it and then acts as the workspace's
+// external agent, reporting app health changes. For every report it records
+// how long coderd took to accept it and how long it took for the new health
+// of all apps to be visible through the API.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	template             codersdk.Template
+	workspacebuildRunner *workspacebuild.Runner
+
+	reports         int
+	appUpdates      int
+	reportingTime   time.Duration
+	reportDurations []time.Duration
+	propagations    []time.Duration
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.CleanupPlanner     = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	version, err := r.pushTemplateVersion(ctx, logs)
+	if err != nil {
+		return xerrors.Errorf("push template version: %w", err)
+	}
+	r.template, err = r.client.CreateTemplate(ctx, r.cfg.OrganizationID, codersdk.CreateTemplateRequest{
+		Name:        TemplatePrefix + id,
+		Description: "`coder exp scaletest app-health` template",
+		VersionID:   version.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("create template: %w", err)
+	}
+	_, _ = fmt.Fprintf(logs, "Created template %q (%s)\n", r.template.Name, r.template.ID)
+
+	buildCtx, cancel := context.WithTimeout(ctx, r.cfg.WorkspaceBuildTimeout)
+	defer cancel()
+	r.workspacebuildRunner = workspacebuild.NewRunner(r.client, workspacebuild.Config{
+		OrganizationID: r.cfg.OrganizationID,
+		UserID:         codersdk.Me,
+		Request: codersdk.CreateWorkspaceRequest{
+			TemplateID: r.template.ID,
+		},
+		// The agent is never started, this runner acts as the agent instead.
+		NoWaitForAgents: true,
+	})
+	slim, err := r.workspacebuildRunner.RunReturningWorkspace(buildCtx, id, logs)
+	if err != nil {
+		return xerrors.Errorf("create workspace: %w", err)
+	}
+
+	agentName, appIDs, err := r.externalAgent(ctx, slim.ID)
+	if err != nil {
+		return err
+	}
+	if len(appIDs) != r.cfg.Apps {
+		return xerrors.Errorf("expected %d apps with health checks, found %d", r.cfg.Apps, len(appIDs))
+	}
+	creds, err := r.client.WorkspaceExternalAgentCredentials(ctx, slim.ID, agentName)
+	if err != nil {
+		return xerrors.Errorf("get external agent credentials: %w", err)
+	}
+
+	agentClient := agentsdk.New(r.client.URL, agentsdk.WithFixedToken(creds.AgentToken),
+		codersdk.WithHTTPClient(r.client.HTTPClient),
+		codersdk.WithLogger(logger))
+	drpcClient, _, err := agentClient.ConnectRPC29WithRole(ctx, "")
+	if err != nil {
+		return xerrors.Errorf("connect agent: %w", err)
+	}
+	defer drpcClient.DRPCConn().Close()
+
+	updates, err := r.client.WatchWorkspace(ctx, slim.ID)
+	if err != nil {
+		return xerrors.Errorf("watch workspace: %w", err)
+	}
+
+	health := make(map[uuid.UUID]agentproto.AppHealth, len(appIDs))
+	changed := make([]uuid.UUID, 0, len(appIDs))
+	for _, appID := range appIDs {
+		health[appID] = agentproto.AppHealth_HEALTHY
+		changed = append(changed, appID)
+	}
+
+	start := time.Now()
+	defer func() {
+		r.reportingTime = time.Since(start)
+	}()
+	_, _ = fmt.Fprintf(logs, "Reporting %d apps as healthy...\n", len(appIDs))
+	err = r.report(ctx, drpcClient, updates, health, changed)
+	if err != nil {
+		return xerrors.Errorf("initial report: %w", err)
+	}
+
+	flips := max(1, int(math.Round(r.cfg.FlipRatio*float64(len(appIDs)))))
+	rng := harness.Rand(ctx)
+	for i := 1; i <= r.cfg.Reports; i++ {
+		if r.cfg.Interval > 0 {
+			t := time.NewTimer(r.cfg.Interval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+
+		changed = changed[:0]
+		for _, idx := range rng.Perm(len(appIDs))[:flips] {
+			appID := appIDs[idx]
+			if health[appID] == agentproto.AppHealth_HEALTHY {
+				health[appID] = agentproto.AppHealth_UNHEALTHY
+			} else {
+				health[appID] = agentproto.AppHealth_HEALTHY
+			}
+			changed = append(changed, appID)
+		}
+		err = r.report(ctx, drpcClient, updates, health, changed)
+		if err != nil {
+			return xerrors.Errorf("report %d: %w", i, err)
+		}
+		logger.Debug(ctx, "sent app health report", slog.F("report", i), slog.F("changed", len(changed)))
+	}
+
+	return nil
+}
+
+// externalAgent returns the name of the first external agent of the
+// workspace and the IDs of its apps that have health checks.
+func (r *Runner) externalAgent(ctx context.Context, workspaceID uuid.UUID) (string, []uuid.UUID, error) {
+	workspace, err := r.client.Workspace(ctx, workspaceID)
+	if err != nil {
+		return "", nil, xerrors.Errorf("fetch workspace: %w", err)
+	}
+	for _, res := range workspace.LatestBuild.Resources {
+		if res.Type != externalAgentResourceType || len(res.Agents) == 0 {
+			continue
+		}
+		agent := res.Agents[0]
+		var appIDs []uuid.UUID
+		for _, app := range agent.Apps {
+			if app.Healthcheck.URL != "" {
+				appIDs = append(appIDs, app.ID)
+			}
+		}
+		return agent.Name, appIDs, nil
+	}
+	return "", nil, xerrors.Errorf("workspace %q has no external agent", workspace.Name)
+}
+
+// report sends the health of the changed apps and waits until the health of
+// all apps is visible on the workspace.
+func (r *Runner) report(ctx context.Context, drpcClient agentproto.DRPCAgentClient28, updates <-chan codersdk.Workspace, health map[uuid.UUID]agentproto.AppHealth, changed []uuid.UUID) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	req := &agentproto.BatchUpdateAppHealthRequest{
+		Updates: make([]*agentproto.BatchUpdateAppHealthRequest_HealthUpdate, 0, len(changed)),
+	}
+	for _, appID := range changed {
+		req.Updates = append(req.Updates, &agentproto.BatchUpdateAppHealthRequest_HealthUpdate{
+			Id:     appID[:],
+			Health: health[appID],
+		})
+	}
+
+	start := time.Now()
+	_, err := drpcClient.BatchUpdateAppHealths(ctx, req)
+	if err != nil {
+		return xerrors.Errorf("batch update app healths: %w", err)
+	}
+	r.reportDurations = append(r.reportDurations, time.Since(start))
+	r.reports++
+	r.appUpdates += len(changed)
+
+	propagationCtx, cancel := context.WithTimeout(ctx, r.cfg.PropagationTimeout)
+	defer cancel()
+	for {
+		select {
+		case <-propagationCtx.Done():
+			return xerrors.Errorf("wait for app health to propagate: %w", propagationCtx.Err())
+		case workspace, ok := <-updates:
+			if !ok {
+				return xerrors.New("workspace watch closed")
+			}
+			if healthMatches(workspace, health) {
+				r.propagations = append(r.propagations, time.Since(start))
+				return nil
+			}
+		}
+	}
+}
+
+// healthMatches returns whether the health of every app in the map matches
+// the health reported for it on the workspace.
+func healthMatches(workspace codersdk.Workspace, health map[uuid.UUID]agentproto.AppHealth) bool {
+	matched := 0
+	for _, res := range workspace.LatestBuild.Resources {
+		for _, agent := range res.Agents {
+			for _, app := range agent.Apps {
+				want, ok := health[app.ID]
+				if !ok {
+					continue
+				}
+				if app.Health != sdkAppHealth(want) {
+					return false
+				}
+				matched++
+			}
+		}
+	}
+	return matched == len(health)
+}
+
+func sdkAppHealth(health agentproto.AppHealth) codersdk.WorkspaceAppHealth {
+	switch health {
+	case agentproto.AppHealth_INITIALIZING:
+		return codersdk.WorkspaceAppHealthInitializing
+	case agentproto.AppHealth_HEALTHY:
+		return codersdk.WorkspaceAppHealthHealthy
+	case agentproto.AppHealth_UNHEALTHY:
+		return codersdk.WorkspaceAppHealthUnhealthy
+	default:
+		return codersdk.WorkspaceAppHealthDisabled
+	}
+}
+
+// pushTemplateVersion uploads and imports the template version and waits for
+// the import job to complete.
+func (r *Runner) pushTemplateVersion(ctx context.Context, logs io.Writer) (codersdk.TemplateVersion, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	tarData, err := r.templateTarData()
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("create template tar: %w", err)
+	}
+	upload, err := r.client.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(tarData))
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("upload template tar: %w", err)
+	}
+	version, err := r.client.CreateTemplateVersion(ctx, r.cfg.OrganizationID, codersdk.CreateTemplateVersionRequest{
+		FileID:          upload.ID,
+		Message:         fmt.Sprintf("Template version with %d apps for scaletest app health", r.cfg.Apps),
+		StorageMethod:   codersdk.ProvisionerStorageMethodFile,
+		Provisioner:     r.provisionerType(),
+		ProvisionerTags: r.cfg.ProvisionerTags,
+	})
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("create template version: %w", err)
+	}
+	if version.MatchedProvisioners != nil && version.MatchedProvisioners.Count == 0 {
+		return codersdk.TemplateVersion{}, xerrors.New("no provisioners matched for template version")
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, r.cfg.TemplateVersionJobTimeout)
+	defer cancel()
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		version, err = r.client.TemplateVersion(jobCtx, version.ID)
+		if err != nil {
+			return codersdk.TemplateVersion{}, xerrors.Errorf("fetch template version: %w", err)
+		}
+
+		job := version.Job
+		if job.CompletedAt != nil {
+			if job.Status != codersdk.ProvisionerJobSucceeded {
+				return codersdk.TemplateVersion{}, xerrors.Errorf("import failed with status %q: %s", job.Status, job.Error)
+			}
+			_, _ = fmt.Fprintf(logs, "Imported template version %q\n", version.Name)
+			return version, nil
+		}
+
+		select {
+		case <-jobCtx.Done():
+			return codersdk.TemplateVersion{}, xerrors.Errorf("wait for import job: %w", jobCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	metrics := map[string]float64{
+		ReportsMetric:    float64(r.reports),
+		AppUpdatesMetric: float64(r.appUpdates),
+	}
+	if r.reportingTime > 0 {
+		metrics[UpdatesPerSecondMetric] = float64(r.appUpdates) / r.reportingTime.Seconds()
+	}
+	if len(r.reportDurations) > 0 {
+		reportMean, reportMax := loadtestutil.MeanMax(r.reportDurations)
+		metrics[ReportMeanMetric] = reportMean.Seconds()
+		metrics[ReportMaxMetric] = reportMax.Seconds()
+	}
+	if len(r.propagations) > 0 {
+		propagationMean, propagationMax := loadtestutil.MeanMax(r.propagations)
+		metrics[PropagationMeanMetric] = propagationMean.Seconds()
+		metrics[PropagationMaxMetric] = propagationMax.Seconds()
+	}
+	return metrics
+}
+
+// Cleanup implements Cleanable by deleting the workspace and then the
+// template.
+func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
+	if r.workspacebuildRunner != nil {
+		_, _ = fmt.Fprintln(logs, "Cleaning up workspace...")
+		if err := r.workspacebuildRunner.Cleanup(ctx, id, logs); err != nil {
+			return xerrors.Errorf("cleanup workspace: %w", err)
+		}
+		r.workspacebuildRunner = nil
+	}
+
+	if r.template.ID == uuid.Nil {
+		return nil
+	}
+	_, _ = fmt.Fprintf(logs, "Deleting template %q...\n", r.template.Name)
+	if err := r.client.DeleteTemplate(ctx, r.template.ID); err != nil {
+		return xerrors.Errorf("delete template: %w", err)
+	}
+	r.template = codersdk.Template{}
+	return nil
+}
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(ctx context.Context, id string) ([]harness.CleanupResource, error) {
+	var resources []harness.CleanupResource
+	if r.workspacebuildRunner != nil {
+		workspaceResources, err := r.workspacebuildRunner.CleanupPlan(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, workspaceResources...)
+	}
+	if r.template.ID != uuid.Nil {
+		resources = append(resources, harness.CleanupResource{
+			Kind: "template",
+			ID:   r.template.ID.String(),
+			Name: r.template.Name,
+		})
+	}
+	return resources, nil
+}
+
+func (r *Runner) provisionerType() codersdk.ProvisionerType {
+	if r.cfg.ProvisionerType == "" {
+		return codersdk.ProvisionerTypeTerraform
+	}
+	return r.cfg.ProvisionerType
+}
+
+// templateTarData returns a tar archive of the template for the configured
+// provisioner.
+func (r *Runner) templateTarData() ([]byte, error) {
+	if r.provisionerType() != codersdk.ProvisionerTypeEcho {
+		return TemplateTarData(r.cfg.Apps)
+	}
+	// The echo provisioner replays the resources the Terraform template
+	// would produce.
+	apps := make([]*sdkproto.App, 0, r.cfg.Apps)
+	for i := range r.cfg.Apps {
+		url := fmt.Sprintf("http://localhost:%d", 8000+i)
+		apps = append(apps, &sdkproto.App{
+			Slug:        fmt.Sprintf("app-%d", i),
+			DisplayName: fmt.Sprintf("App %d", i),
+			Url:         url,
+			Healthcheck: &sdkproto.Healthcheck{
+				Url:       url + "/healthz",
+				Interval:  5,
+				Threshold: 6,
+			},
+		})
+	}
+	return echo.Tar(&echo.Responses{
+		ProvisionGraph: []*sdkproto.Response{{
+			Type: &sdkproto.Response_Graph{
+				Graph: &sdkproto.GraphComplete{
+					Resources: []*sdkproto.Resource{{
+						Type: externalAgentResourceType,
+						Name: "main",
+						Agents: []*sdkproto.Agent{{
+							Name:            "main",
+							OperatingSystem: "linux",
+							Architecture:    "amd64",
+							Apps:            apps,
+						}},
+					}},
+					HasExternalAgents: true,
+				},
+			},
+		}},
+	})
+}
+
+// TemplateTarData returns a tar archive of a Terraform template with an
+// external agent that defines the given number of coder_apps with health
+// checks.
+func TemplateTarData(apps int) ([]byte, error) {
+	tmpl, err := template.New("app-health").Parse(templateContent)
+	if err != nil {
+		return nil, err
+	}
+	result := bytes.Buffer{}
+	err = tmpl.Execute(&result, map[string]int{
+		"Apps": apps,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loadtestutil.CreateTarFromFiles(map[string][]byte{
+		"main.tf": result.Bytes(),
+	})
+}
+
+//go:embed tf/main.tf.tpl
+var templateContent string
@@ -0,0 +1,32 @@
+package apphealth_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/apphealth"
+)
+
+func TestTemplateTarData(t *testing.T) {
+	t.Parallel()
+
+	data, err := apphealth.TemplateTarData(25)
+	require.NoError(t, err)
+
+	tr := tar.NewReader(bytes.NewReader(data))
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "main.tf", hdr.Name)
+	content, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	_, err = tr.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	require.Contains(t, string(content), `resource "coder_external_agent" "main"`)
+	require.Contains(t, string(content), "count        = 25")
+	require.Contains(t, string(content), "healthcheck {")
+}
@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    coder = {
+      source  = "coder/coder"
+      version = ">= 2.10.0"
+    }
+  }
+}
+
+data "coder_provisioner" "me" {}
+
+resource "coder_agent" "main" {
+  os   = data.coder_provisioner.me.os
+  arch = data.coder_provisioner.me.arch
+}
+
+resource "coder_external_agent" "main" {
+  agent_id = coder_agent.main.id
+}
+
+resource "coder_app" "app" {
+  count        = {{.Apps}}
+  agent_id     = coder_agent.main.id
+  slug         = "app-${count.index}"
+  display_name = "App ${count.index}"
+  url          = "http://localhost:${8000 + count.index}"
+
+  healthcheck {
+    url       = "http://localhost:${8000 + count.index}/healthz"
+    interval  = 5
+    threshold = 6
+  }
+}
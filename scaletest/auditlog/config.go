@@ -0,0 +1,53 @@
+package auditlog
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// MaxPageSize is the largest page size accepted by the audit log API.
+const MaxPageSize = 100
+
+type Config struct {
+	// Filters are the audit log search queries to run, e.g.
+	// "resource_type:workspace action:create". An empty filter lists all
+	// audit logs. Each query picks a filter at random.
+	Filters []string `json:"filters"`
+	// PageSize is the number of audit logs requested per page.
+	PageSize int `json:"page_size"`
+	// Pages is the maximum number of pages walked per query. Fewer pages are
+	// fetched if the results run out.
+	Pages int `json:"pages"`
+	// Interval is the average interval between queries.
+	Interval time.Duration `json:"interval"`
+	// Jitter is the maximum random amount the interval is shortened or
+	// lengthened by.
+	Jitter time.Duration `json:"jitter"`
+	// Metrics records the duration and errors of each page request, if set.
+	Metrics Metrics `json:"-"`
+}
+
+func (c Config) Validate() error {
+	if len(c.Filters) == 0 {
+		return xerrors.New("validate filters: must not be empty")
+	}
+
+	if c.PageSize <= 0 || c.PageSize > MaxPageSize {
+		return xerrors.Errorf("validate page_size: must be between 1 and %d", MaxPageSize)
+	}
+
+	if c.Pages <= 0 {
+		return xerrors.New("validate pages: must be greater than zero")
+	}
+
+	if !(c.Interval > 0) {
+		return xerrors.New("validate interval: must be greater than zero")
+	}
+
+	if c.Jitter < 0 || !(c.Jitter < c.Interval) {
+		return xerrors.New("validate jitter: must be between zero and interval")
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package auditlog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/auditlog"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		config      auditlog.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: auditlog.Config{
+				Filters:  []string{"", "resource_type:workspace"},
+				PageSize: 25,
+				Pages:    4,
+				Interval: time.Second,
+				Jitter:   500 * time.Millisecond,
+			},
+		},
+		{
+			name: "NoFilters",
+			config: auditlog.Config{
+				PageSize: 25,
+				Pages:    4,
+				Interval: time.Second,
+			},
+			errContains: "validate filters",
+		},
+		{
+			name: "PageSizeTooLarge",
+			config: auditlog.Config{
+				Filters:  []string{""},
+				PageSize: auditlog.MaxPageSize + 1,
+				Pages:    4,
+				Interval: time.Second,
+			},
+			errContains: "validate page_size",
+		},
+		{
+			name: "NoPages",
+			config: auditlog.Config{
+				Filters:  []string{""},
+				PageSize: 25,
+				Interval: time.Second,
+			},
+			errContains: "validate pages",
+		},
+		{
+			name: "NoInterval",
+			config: auditlog.Config{
+				Filters:  []string{""},
+				PageSize: 25,
+				Pages:    4,
+			},
+			errContains: "validate interval",
+		},
+		{
+			name: "JitterTooLarge",
+			config: auditlog.Config{
+				Filters:  []string{""},
+				PageSize: 25,
+				Pages:    4,
+				Interval: time.Second,
+				Jitter:   time.Second,
+			},
+			errContains: "validate jitter",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
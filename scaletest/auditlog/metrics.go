@@ -0,0 +1,44 @@
+package auditlog
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics interface {
+	ObserveDuration(filter string, d time.Duration)
+	IncErrors(filter string)
+}
+
+type PromMetrics struct {
+	durationSeconds *prometheus.HistogramVec
+	errors          *prometheus.CounterVec
+}
+
+func NewMetrics(reg prometheus.Registerer) *PromMetrics {
+	m := &PromMetrics{
+		durationSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "scaletest_audit_log",
+			Name:      "page_duration_seconds",
+		}, []string{"filter"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "scaletest_audit_log",
+			Name:      "errors_total",
+		}, []string{"filter"}),
+	}
+
+	reg.MustRegister(m.durationSeconds)
+	reg.MustRegister(m.errors)
+	return m
+}
+
+func (p *PromMetrics) ObserveDuration(filter string, d time.Duration) {
+	p.durationSeconds.WithLabelValues(filter).Observe(d.Seconds())
+}
+
+func (p *PromMetrics) IncErrors(filter string) {
+	p.errors.WithLabelValues(filter).Inc()
+}
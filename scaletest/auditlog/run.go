@@ -0,0 +1,154 @@
+package auditlog
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+// Numeric metrics reported by the runner.
+const (
+	QueriesMetric          = "queries"
+	PagesMetric            = "pages"
+	AuditLogsMetric        = "audit_logs"
+	RequestErrorsMetric    = "request_errors"
+	RequestErrorRateMetric = "request_error_rate"
+	PageMeanMetric         = "page_seconds_mean"
+	PageMaxMetric          = "page_seconds_max"
+)
+
+// Runner queries the audit log with filters and walks the result pages until
+// its context is done, to simulate the load of a compliance dashboard.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	queries       atomic.Int64
+	pages         atomic.Int64
+	auditLogs     atomic.Int64
+	errors        atomic.Int64
+	totalDuration atomic.Int64
+	maxDuration   atomic.Int64
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable. Failed requests are logged and counted, the run
+// only fails if no request succeeded.
+func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) error {
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	logger.Info(ctx, "querying audit logs", slog.F("filters", len(r.cfg.Filters)), slog.F("page_size", r.cfg.PageSize), slog.F("pages", r.cfg.Pages))
+
+	rng := harness.Rand(ctx)
+
+	t := time.NewTimer(0) // First one should be immediate.
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "audit log query summary",
+				slog.F("queries", r.queries.Load()),
+				slog.F("pages", r.pages.Load()),
+				slog.F("errors", r.errors.Load()),
+			)
+			if r.pages.Load() > 0 && r.pages.Load() == r.errors.Load() {
+				return xerrors.Errorf("all %d requests failed, see logs for details", r.pages.Load())
+			}
+			return nil
+		case <-t.C:
+		}
+
+		wait := r.cfg.Interval
+		if r.cfg.Jitter > 0 {
+			wait += time.Duration(rng.Int63n(int64(2*r.cfg.Jitter))) - r.cfg.Jitter
+		}
+		t.Reset(wait)
+
+		filter := r.cfg.Filters[rng.Intn(len(r.cfg.Filters))]
+		r.queries.Add(1)
+		r.query(ctx, logger, filter)
+	}
+}
+
+// query walks the pages of the audit logs matching filter, stopping early when
+// the results run out or a request fails.
+func (r *Runner) query(ctx context.Context, logger slog.Logger, filter string) {
+	for page := range r.cfg.Pages {
+		start := time.Now()
+		res, err := r.client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			SearchQuery: filter,
+			Pagination: codersdk.Pagination{
+				Offset: page * r.cfg.PageSize,
+				Limit:  r.cfg.PageSize,
+			},
+		})
+		took := time.Since(start)
+		if err != nil && ctx.Err() != nil {
+			// The request was interrupted because the test is over.
+			return
+		}
+
+		r.pages.Add(1)
+		r.totalDuration.Add(int64(took))
+		for {
+			prev := r.maxDuration.Load()
+			if int64(took) <= prev || r.maxDuration.CompareAndSwap(prev, int64(took)) {
+				break
+			}
+		}
+		if r.cfg.Metrics != nil {
+			r.cfg.Metrics.ObserveDuration(filter, took)
+		}
+		if err != nil {
+			r.errors.Add(1)
+			if r.cfg.Metrics != nil {
+				r.cfg.Metrics.IncErrors(filter)
+			}
+			logger.Error(ctx, "audit log request failed", slog.F("filter", filter), slog.F("page", page), slog.Error(err))
+			return
+		}
+
+		r.auditLogs.Add(int64(len(res.AuditLogs)))
+		logger.Debug(ctx, "audit log request succeeded", slog.F("filter", filter), slog.F("page", page), slog.F("results", len(res.AuditLogs)), slog.F("took", took))
+		if len(res.AuditLogs) < r.cfg.PageSize {
+			return
+		}
+	}
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	pages := r.pages.Load()
+	metrics := map[string]float64{
+		QueriesMetric:       float64(r.queries.Load()),
+		PagesMetric:         float64(pages),
+		AuditLogsMetric:     float64(r.auditLogs.Load()),
+		RequestErrorsMetric: float64(r.errors.Load()),
+		PageMaxMetric:       time.Duration(r.maxDuration.Load()).Seconds(),
+	}
+	if pages > 0 {
+		metrics[RequestErrorRateMetric] = float64(r.errors.Load()) / float64(pages)
+		metrics[PageMeanMetric] = (time.Duration(r.totalDuration.Load()) / time.Duration(pages)).Seconds()
+	}
+	return metrics
+}
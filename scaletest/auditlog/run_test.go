@@ -0,0 +1,107 @@
+package auditlog_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/auditlog"
+)
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+
+	const totalLogs = 250
+
+	var (
+		mu      sync.Mutex
+		offsets = map[string][]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/audit" {
+			httpapi.Write(r.Context(), w, http.StatusNotFound, codersdk.Response{Message: "not found"})
+			return
+		}
+		query := r.URL.Query()
+		filter := query.Get("q")
+		if filter == "action:bogus" {
+			httpapi.Write(r.Context(), w, http.StatusBadRequest, codersdk.Response{Message: "invalid query"})
+			return
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		mu.Lock()
+		offsets[filter] = append(offsets[filter], offset)
+		mu.Unlock()
+
+		n := max(0, min(limit, totalLogs-offset))
+		httpapi.Write(r.Context(), w, http.StatusOK, codersdk.AuditLogResponse{
+			AuditLogs: make([]codersdk.AuditLog, n),
+			Count:     totalLogs,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	metrics := &fakeMetrics{errors: map[string]int{}}
+	runner := auditlog.NewRunner(codersdk.New(u), auditlog.Config{
+		Filters:  []string{"resource_type:workspace", "action:bogus"},
+		PageSize: 100,
+		Pages:    5,
+		Interval: time.Millisecond,
+		Metrics:  metrics,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	err = runner.Run(ctx, "1", io.Discard)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	walked := offsets["resource_type:workspace"]
+	require.NotEmpty(t, walked)
+	// Every query walks the pages in order and stops at the partial page.
+	for i, offset := range walked {
+		require.Equal(t, (i%3)*100, offset)
+	}
+
+	numeric := runner.GetNumericMetrics()
+	require.Positive(t, numeric[auditlog.QueriesMetric])
+	require.Positive(t, numeric[auditlog.PagesMetric])
+	require.Positive(t, numeric[auditlog.AuditLogsMetric])
+	require.Positive(t, numeric[auditlog.RequestErrorsMetric])
+	require.Less(t, numeric[auditlog.RequestErrorRateMetric], float64(1))
+	require.Positive(t, metrics.errorCount("action:bogus"))
+	require.Zero(t, metrics.errorCount("resource_type:workspace"))
+}
+
+type fakeMetrics struct {
+	mu     sync.Mutex
+	errors map[string]int
+}
+
+func (*fakeMetrics) ObserveDuration(string, time.Duration) {}
+
+func (m *fakeMetrics) IncErrors(filter string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[filter]++
+}
+
+func (m *fakeMetrics) errorCount(filter string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.errors[filter]
+}
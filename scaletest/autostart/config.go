@@ -29,6 +29,12 @@ type Config struct {
 	// to schedule them to be started again.
 	AutostartDelay time.Duration `json:"autostart_delay"`
 
+	// ScheduleOffset is added to AutostartDelay for this workspace. Giving
+	// runners different offsets spreads the autostart schedules across
+	// several overlapping minutes instead of a single one. It must be a whole
+	// number of minutes, as autostart schedules have minute granularity.
+	ScheduleOffset time.Duration `json:"schedule_offset"`
+
 	// AutostartBuildTimeout is how long to wait for the autostart build to
 	// complete after it has been triggered. This should be longer than
 	// WorkspaceJobTimeout to account for potential queueing time in high-load
@@ -76,13 +82,17 @@ func (c Config) Validate() error {
 		return xerrors.New("autostart_delay must be at least 2 minutes")
 	}
 
+	if c.ScheduleOffset < 0 || c.ScheduleOffset%time.Minute != 0 {
+		return xerrors.New("schedule_offset must be a non-negative whole number of minutes")
+	}
+
 	if c.AutostartBuildTimeout <= 0 {
 		return xerrors.New("autostart_build_timeout must be greater than 0")
 	}
 
-	if c.AutostartBuildTimeout <= c.WorkspaceJobTimeout {
-		return xerrors.Errorf("autostart_build_timeout (%s) must be greater than workspace_job_timeout (%s) to account for scheduling delay and queueing time",
-			c.AutostartBuildTimeout, c.WorkspaceJobTimeout)
+	if c.AutostartBuildTimeout <= c.WorkspaceJobTimeout+c.ScheduleOffset {
+		return xerrors.Errorf("autostart_build_timeout (%s) must be greater than workspace_job_timeout (%s) plus schedule_offset (%s) to account for scheduling delay and queueing time",
+			c.AutostartBuildTimeout, c.WorkspaceJobTimeout, c.ScheduleOffset)
 	}
 
 	return nil
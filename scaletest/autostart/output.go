@@ -26,6 +26,11 @@ type RunResults struct {
 	EndToEndLatencyP95 time.Duration
 	EndToEndLatencyP99 time.Duration
 
+	// Aggregate latency statistics (scheduled time to autostart build).
+	StartLatencyP50 time.Duration
+	StartLatencyP95 time.Duration
+	StartLatencyP99 time.Duration
+
 	// Aggregate latency statistics (trigger to completion).
 	TriggerToCompletionP50 time.Duration
 	TriggerToCompletionP95 time.Duration
@@ -41,6 +46,7 @@ func NewRunResults(runs []RunResult) RunResults {
 
 	var (
 		endToEndLatencies            []time.Duration
+		startLatencies               []time.Duration
 		triggerToCompletionLatencies []time.Duration
 	)
 
@@ -48,6 +54,7 @@ func NewRunResults(runs []RunResult) RunResults {
 		if run.Success {
 			results.SuccessfulRuns++
 			endToEndLatencies = append(endToEndLatencies, run.EndToEndLatency())
+			startLatencies = append(startLatencies, run.StartLatency())
 			triggerToCompletionLatencies = append(triggerToCompletionLatencies, run.TriggerToCompletionLatency())
 		} else {
 			results.FailedRuns++
@@ -64,6 +71,16 @@ func NewRunResults(runs []RunResult) RunResults {
 		results.EndToEndLatencyP99 = percentile(endToEndLatencies, 0.99)
 	}
 
+	// Calculate percentiles for start latency.
+	if len(startLatencies) > 0 {
+		sort.Slice(startLatencies, func(i, j int) bool {
+			return startLatencies[i] < startLatencies[j]
+		})
+		results.StartLatencyP50 = percentile(startLatencies, 0.50)
+		results.StartLatencyP95 = percentile(startLatencies, 0.95)
+		results.StartLatencyP99 = percentile(startLatencies, 0.99)
+	}
+
 	// Calculate percentiles for trigger to completion latency.
 	if len(triggerToCompletionLatencies) > 0 {
 		sort.Slice(triggerToCompletionLatencies, func(i, j int) bool {
@@ -108,6 +125,12 @@ func (r RunResults) PrintText(w io.Writer) {
 		_, _ = fmt.Fprintf(w, "P95: %v\n", r.EndToEndLatencyP95.Round(time.Millisecond))
 		_, _ = fmt.Fprintf(w, "P99: %v\n\n", r.EndToEndLatencyP99.Round(time.Millisecond))
 
+		_, _ = fmt.Fprintf(w, "Start Latency (Scheduled Time → Autostart Build)\n")
+		_, _ = fmt.Fprintf(w, "-------------------------------------------------\n")
+		_, _ = fmt.Fprintf(w, "P50: %v\n", r.StartLatencyP50.Round(time.Millisecond))
+		_, _ = fmt.Fprintf(w, "P95: %v\n", r.StartLatencyP95.Round(time.Millisecond))
+		_, _ = fmt.Fprintf(w, "P99: %v\n\n", r.StartLatencyP99.Round(time.Millisecond))
+
 		_, _ = fmt.Fprintf(w, "Trigger to Completion Latency (Scheduled Time → Completion)\n")
 		_, _ = fmt.Fprintf(w, "------------------------------------------------------------\n")
 		_, _ = fmt.Fprintf(w, "P50: %v\n", r.TriggerToCompletionP50.Round(time.Millisecond))
@@ -138,6 +161,10 @@ func (r RunResults) MarshalJSON() ([]byte, error) {
 		EndToEndLatencyP95MS int64 `json:"end_to_end_latency_p95_ms"`
 		EndToEndLatencyP99MS int64 `json:"end_to_end_latency_p99_ms"`
 
+		StartLatencyP50MS int64 `json:"start_latency_p50_ms"`
+		StartLatencyP95MS int64 `json:"start_latency_p95_ms"`
+		StartLatencyP99MS int64 `json:"start_latency_p99_ms"`
+
 		TriggerToCompletionP50MS int64 `json:"trigger_to_completion_p50_ms"`
 		TriggerToCompletionP95MS int64 `json:"trigger_to_completion_p95_ms"`
 		TriggerToCompletionP99MS int64 `json:"trigger_to_completion_p99_ms"`
@@ -148,8 +175,10 @@ func (r RunResults) MarshalJSON() ([]byte, error) {
 			Success       bool   `json:"success"`
 			Error         string `json:"error,omitempty"`
 
-			EndToEndLatencyMS     int64 `json:"end_to_end_latency_ms"`
-			TriggerToCompletionMS int64 `json:"trigger_to_completion_ms"`
+			ScheduledTime         time.Time `json:"scheduled_time"`
+			EndToEndLatencyMS     int64     `json:"end_to_end_latency_ms"`
+			StartLatencyMS        int64     `json:"start_latency_ms"`
+			TriggerToCompletionMS int64     `json:"trigger_to_completion_ms"`
 		} `json:"runs"`
 	}
 
@@ -162,6 +191,10 @@ func (r RunResults) MarshalJSON() ([]byte, error) {
 		EndToEndLatencyP95MS: r.EndToEndLatencyP95.Milliseconds(),
 		EndToEndLatencyP99MS: r.EndToEndLatencyP99.Milliseconds(),
 
+		StartLatencyP50MS: r.StartLatencyP50.Milliseconds(),
+		StartLatencyP95MS: r.StartLatencyP95.Milliseconds(),
+		StartLatencyP99MS: r.StartLatencyP99.Milliseconds(),
+
 		TriggerToCompletionP50MS: r.TriggerToCompletionP50.Milliseconds(),
 		TriggerToCompletionP95MS: r.TriggerToCompletionP95.Milliseconds(),
 		TriggerToCompletionP99MS: r.TriggerToCompletionP99.Milliseconds(),
@@ -174,15 +207,19 @@ func (r RunResults) MarshalJSON() ([]byte, error) {
 			Success       bool   `json:"success"`
 			Error         string `json:"error,omitempty"`
 
-			EndToEndLatencyMS     int64 `json:"end_to_end_latency_ms"`
-			TriggerToCompletionMS int64 `json:"trigger_to_completion_ms"`
+			ScheduledTime         time.Time `json:"scheduled_time"`
+			EndToEndLatencyMS     int64     `json:"end_to_end_latency_ms"`
+			StartLatencyMS        int64     `json:"start_latency_ms"`
+			TriggerToCompletionMS int64     `json:"trigger_to_completion_ms"`
 		}{
 			WorkspaceID:   run.WorkspaceID.String(),
 			WorkspaceName: run.WorkspaceName,
 			Success:       run.Success,
 			Error:         run.Error,
 
+			ScheduledTime:         run.ScheduledTime,
 			EndToEndLatencyMS:     run.EndToEndLatency().Milliseconds(),
+			StartLatencyMS:        run.StartLatency().Milliseconds(),
 			TriggerToCompletionMS: run.TriggerToCompletionLatency().Milliseconds(),
 		})
 	}
@@ -209,6 +246,8 @@ func (r RunResults) ToHarnessResults() harness.Results {
 			Error:    err,
 			Metrics: map[string]any{
 				"end_to_end_latency_seconds":    run.EndToEndLatency().Seconds(),
+				"start_latency_seconds":         run.StartLatency().Seconds(),
+				"scheduled_time":                run.ScheduledTime,
 				"trigger_to_completion_seconds": run.TriggerToCompletionLatency().Seconds(),
 				"workspace_id":                  run.WorkspaceID.String(),
 				"workspace_name":                run.WorkspaceName,
@@ -217,9 +256,10 @@ func (r RunResults) ToHarnessResults() harness.Results {
 	}
 
 	return harness.Results{
-		TotalRuns: r.TotalRuns,
-		TotalPass: r.SuccessfulRuns,
-		TotalFail: r.FailedRuns,
-		Runs:      harnessRuns,
+		SchemaVersion: harness.ResultsSchemaVersion,
+		TotalRuns:     r.TotalRuns,
+		TotalPass:     r.SuccessfulRuns,
+		TotalFail:     r.FailedRuns,
+		Runs:          harnessRuns,
 	}
 }
@@ -15,6 +15,7 @@ func TestRunResult(t *testing.T) {
 
 	configTime := time.Now().UTC()
 	scheduledTime := configTime.Add(2 * time.Minute)
+	triggerTime := scheduledTime.Add(5 * time.Second)
 	completionTime := scheduledTime.Add(30 * time.Second)
 
 	result := autostart.RunResult{
@@ -22,6 +23,7 @@ func TestRunResult(t *testing.T) {
 		WorkspaceName:  "test-workspace",
 		ConfigTime:     configTime,
 		ScheduledTime:  scheduledTime,
+		TriggerTime:    triggerTime,
 		CompletionTime: completionTime,
 		Success:        true,
 	}
@@ -31,6 +33,9 @@ func TestRunResult(t *testing.T) {
 	expectedEndToEnd := 2*time.Minute + 30*time.Second
 	require.Equal(t, expectedEndToEnd, endToEnd)
 
+	// Test start latency.
+	require.Equal(t, 5*time.Second, result.StartLatency())
+
 	// Test trigger to completion latency.
 	triggerToCompletion := result.TriggerToCompletionLatency()
 	expectedTriggerToCompletion := 30 * time.Second
@@ -47,6 +52,7 @@ func TestRunResults(t *testing.T) {
 			WorkspaceName:  "workspace-1",
 			ConfigTime:     now,
 			ScheduledTime:  now.Add(1 * time.Minute),
+			TriggerTime:    now.Add(1*time.Minute + 2*time.Second),
 			CompletionTime: now.Add(1*time.Minute + 10*time.Second),
 			Success:        true,
 		},
@@ -55,6 +61,7 @@ func TestRunResults(t *testing.T) {
 			WorkspaceName:  "workspace-2",
 			ConfigTime:     now,
 			ScheduledTime:  now.Add(1 * time.Minute),
+			TriggerTime:    now.Add(1*time.Minute + 4*time.Second),
 			CompletionTime: now.Add(1*time.Minute + 20*time.Second),
 			Success:        true,
 		},
@@ -63,6 +70,7 @@ func TestRunResults(t *testing.T) {
 			WorkspaceName:  "workspace-3",
 			ConfigTime:     now,
 			ScheduledTime:  now.Add(1 * time.Minute),
+			TriggerTime:    now.Add(1*time.Minute + 6*time.Second),
 			CompletionTime: now.Add(1*time.Minute + 30*time.Second),
 			Success:        true,
 		},
@@ -88,6 +96,10 @@ func TestRunResults(t *testing.T) {
 	// P99 is also at index int((3-1)*0.99) = 1, which is 20s.
 	require.Equal(t, 20*time.Second, results.TriggerToCompletionP99)
 
+	// Start latencies are measured from the scheduled time.
+	require.Equal(t, 4*time.Second, results.StartLatencyP50)
+	require.Equal(t, 4*time.Second, results.StartLatencyP95)
+
 	// End-to-end latencies should include the 1 minute delay.
 	require.Equal(t, 1*time.Minute+20*time.Second, results.EndToEndLatencyP50)
 	require.Equal(t, 1*time.Minute+20*time.Second, results.EndToEndLatencyP95)
@@ -19,6 +19,9 @@ type RunResult struct {
 	ConfigTime time.Time
 	// ScheduledTime is the time the workspace was scheduled to autostart.
 	ScheduledTime time.Time
+	// TriggerTime is when the first update for the autostart build was
+	// received, i.e. when the lifecycle executor started the workspace.
+	TriggerTime time.Time
 	// CompletionTime is when the autostart build completed successfully.
 	CompletionTime time.Time
 
@@ -37,6 +40,15 @@ func (r RunResult) EndToEndLatency() time.Duration {
 	return r.CompletionTime.Sub(r.ConfigTime)
 }
 
+// StartLatency returns the time from the scheduled autostart time until the
+// lifecycle executor created the autostart build.
+func (r RunResult) StartLatency() time.Duration {
+	if r.ScheduledTime.IsZero() || r.TriggerTime.IsZero() {
+		return 0
+	}
+	return r.TriggerTime.Sub(r.ScheduledTime)
+}
+
 // TriggerToCompletionLatency returns the time from the scheduled autostart
 // time to completion. This includes queueing time plus build execution time.
 func (r RunResult) TriggerToCompletionLatency() time.Duration {
@@ -98,7 +98,7 @@ func (r *Runner) RunReturningResult(ctx context.Context, id string, logs io.Writ
 	defer cancel()
 
 	logger.Info(ctx, "waiting for initial workspace build", slog.F("workspace_name", workspace.Name), slog.F("workspace_id", workspace.ID.String()))
-	err = waitForBuild(createWorkspaceCtx, logger, buildUpdates, codersdk.WorkspaceTransitionStart)
+	_, err = waitForBuild(createWorkspaceCtx, logger, buildUpdates, codersdk.WorkspaceTransitionStart)
 	if err != nil {
 		return result, xerrors.Errorf("wait for initial workspace build (workspace=%s, id=%s): %w", workspace.Name, workspace.ID, err)
 	}
@@ -117,7 +117,7 @@ func (r *Runner) RunReturningResult(ctx context.Context, id string, logs io.Writ
 	stopBuildCtx, cancel := context.WithTimeout(ctx, r.cfg.WorkspaceJobTimeout)
 	defer cancel()
 
-	err = waitForBuild(stopBuildCtx, logger, buildUpdates, codersdk.WorkspaceTransitionStop)
+	_, err = waitForBuild(stopBuildCtx, logger, buildUpdates, codersdk.WorkspaceTransitionStop)
 	if err != nil {
 		return result, xerrors.Errorf("wait for stop build: %w", err)
 	}
@@ -132,7 +132,7 @@ func (r *Runner) RunReturningResult(ctx context.Context, id string, logs io.Writ
 
 	// Schedule the workspace to autostart.
 	testStartTime := time.Now().UTC()
-	autostartTime := testStartTime.Add(r.cfg.AutostartDelay).Round(time.Minute)
+	autostartTime := testStartTime.Add(r.cfg.AutostartDelay + r.cfg.ScheduleOffset).Round(time.Minute)
 	schedule := fmt.Sprintf("CRON_TZ=UTC %d %d * * *", autostartTime.Minute(), autostartTime.Hour())
 
 	logger.Info(ctx, "setting autostart schedule for workspace", slog.F("workspace_name", workspace.Name), slog.F("schedule", schedule))
@@ -164,7 +164,7 @@ func (r *Runner) RunReturningResult(ctx context.Context, id string, logs io.Writ
 		slog.F("workspace_name", workspace.Name),
 		slog.F("timeout", r.cfg.AutostartBuildTimeout))
 
-	err = waitForBuild(autostartBuildCtx, logger, buildUpdates, codersdk.WorkspaceTransitionStart)
+	result.TriggerTime, err = waitForBuild(autostartBuildCtx, logger, buildUpdates, codersdk.WorkspaceTransitionStart)
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
@@ -181,7 +181,10 @@ func (r *Runner) RunReturningResult(ctx context.Context, id string, logs io.Writ
 	result.CompletionTime = time.Now().UTC()
 	result.Success = true
 
-	logger.Info(ctx, "autostart build completed successfully", slog.F("workspace_name", workspace.Name))
+	logger.Info(ctx, "autostart build completed successfully",
+		slog.F("workspace_name", workspace.Name),
+		slog.F("start_latency", result.StartLatency()),
+		slog.F("trigger_to_completion", result.TriggerToCompletionLatency()))
 
 	if r.cfg.ResultSink != nil {
 		select {
@@ -195,17 +198,22 @@ func (r *Runner) RunReturningResult(ctx context.Context, id string, logs io.Writ
 }
 
 // waitForBuild waits for a build with the given transition to reach a
-// terminal state. It returns nil on success, or an error if the build
-// fails, is canceled, or the context expires. If an unexpected transition
-// is received, it returns an error immediately.
-func waitForBuild(ctx context.Context, logger slog.Logger, updates <-chan codersdk.WorkspaceBuildUpdate, transition codersdk.WorkspaceTransition) error {
+// terminal state. It returns the time the first update for the build was
+// received, and nil on success, or an error if the build fails, is canceled,
+// or the context expires. If an unexpected transition is received, it returns
+// an error immediately.
+func waitForBuild(ctx context.Context, logger slog.Logger, updates <-chan codersdk.WorkspaceBuildUpdate, transition codersdk.WorkspaceTransition) (time.Time, error) {
+	var firstUpdate time.Time
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return firstUpdate, ctx.Err()
 		case update, ok := <-updates:
 			if !ok {
-				return xerrors.New("build updates channel closed")
+				return firstUpdate, xerrors.New("build updates channel closed")
+			}
+			if firstUpdate.IsZero() {
+				firstUpdate = time.Now().UTC()
 			}
 			logger.Debug(ctx, "received build update",
 				slog.F("transition", update.Transition),
@@ -213,15 +221,15 @@ func waitForBuild(ctx context.Context, logger slog.Logger, updates <-chan coders
 				slog.F("build_number", update.BuildNumber))
 
 			if update.Transition != string(transition) {
-				return xerrors.Errorf("unexpected transition: expected %s, got %s (build_number=%d)", transition, update.Transition, update.BuildNumber)
+				return firstUpdate, xerrors.Errorf("unexpected transition: expected %s, got %s (build_number=%d)", transition, update.Transition, update.BuildNumber)
 			}
 			switch codersdk.ProvisionerJobStatus(update.JobStatus) {
 			case codersdk.ProvisionerJobSucceeded:
-				return nil
+				return firstUpdate, nil
 			case codersdk.ProvisionerJobFailed:
-				return xerrors.Errorf("workspace build failed (transition=%s, build_number=%d)", update.Transition, update.BuildNumber)
+				return firstUpdate, xerrors.Errorf("workspace build failed (transition=%s, build_number=%d)", update.Transition, update.BuildNumber)
 			case codersdk.ProvisionerJobCanceled:
-				return xerrors.Errorf("workspace build canceled (transition=%s, build_number=%d)", update.Transition, update.BuildNumber)
+				return firstUpdate, xerrors.Errorf("workspace build canceled (transition=%s, build_number=%d)", update.Transition, update.BuildNumber)
 			default:
 				// Intermediate states (pending, running, canceling)
 				// are expected; keep waiting.
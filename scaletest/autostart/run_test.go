@@ -83,7 +83,7 @@ func TestRun(t *testing.T) {
 	workspaceChannels := make(map[string]chan codersdk.WorkspaceBuildUpdate)
 	for i := range numUsers {
 		id := strconv.Itoa(i)
-		workspaceName := loadtestutil.GenerateDeterministicWorkspaceName(id)
+		workspaceName := loadtestutil.GenerateDeterministicWorkspaceName(ctx, id)
 		workspaceChannels[workspaceName] = make(chan codersdk.WorkspaceBuildUpdate, 16)
 	}
 
@@ -113,7 +113,7 @@ func TestRun(t *testing.T) {
 	runners := make([]*autostart.Runner, 0, numUsers)
 	for i := range numUsers {
 		id := strconv.Itoa(i)
-		workspaceName := loadtestutil.GenerateDeterministicWorkspaceName(id)
+		workspaceName := loadtestutil.GenerateDeterministicWorkspaceName(ctx, id)
 		cfg := autostart.Config{
 			User: createusers.Config{
 				OrganizationID: user.OrganizationID,
@@ -112,9 +112,10 @@ func (r *Runner) WithClock(clock quartz.Clock) *Runner {
 }
 
 var (
-	_ harness.Runnable    = &Runner{}
-	_ harness.Cleanable   = &Runner{}
-	_ harness.Collectable = &Runner{}
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.Collectable        = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
 )
 
 func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
@@ -452,3 +453,18 @@ func (r *Runner) GetMetrics() map[string]any {
 		"total_tokens":   r.totalTokens,
 	}
 }
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	avgDuration := time.Duration(0)
+	if r.requestCount > 0 {
+		avgDuration = r.totalDuration / time.Duration(r.requestCount)
+	}
+
+	return map[string]float64{
+		"request_count":        float64(r.requestCount),
+		"failure_count":        float64(r.failureCount),
+		"avg_duration_seconds": avgDuration.Seconds(),
+		"total_tokens":         float64(r.totalTokens),
+	}
+}
@@ -0,0 +1,104 @@
+package chaos
+
+import (
+	"context"
+	"math"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+const workspacesPageSize = 100
+
+// AgentKiller kills a single workspace agent, e.g. by deleting the pod it
+// runs in.
+type AgentKiller interface {
+	KillAgent(ctx context.Context, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent) error
+}
+
+// CommandAgentKiller kills agents by running a command. The workspace and
+// agent are passed to the command in the CODER_WORKSPACE_ID,
+// CODER_WORKSPACE_NAME, CODER_WORKSPACE_OWNER_NAME, CODER_AGENT_ID and
+// CODER_AGENT_NAME environment variables.
+type CommandAgentKiller struct {
+	Command Command
+}
+
+var _ AgentKiller = CommandAgentKiller{}
+
+// KillAgent implements AgentKiller.
+func (k CommandAgentKiller) KillAgent(ctx context.Context, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent) error {
+	return k.Command.run(ctx,
+		"CODER_WORKSPACE_ID="+workspace.ID.String(),
+		"CODER_WORKSPACE_NAME="+workspace.Name,
+		"CODER_WORKSPACE_OWNER_NAME="+workspace.OwnerName,
+		"CODER_AGENT_ID="+agent.ID.String(),
+		"CODER_AGENT_NAME="+agent.Name,
+	)
+}
+
+// KillAgents kills a percentage of the connected agents of the workspaces
+// matching a filter every time it's injected. The agents are picked at random
+// using the random number generator of the chaos hook, so the same agents are
+// picked when the test is run again with the same seed and workspaces.
+type KillAgents struct {
+	Client *codersdk.Client
+	// Filter selects the workspaces whose agents may be killed, e.g. the
+	// workspaces owned by the scaletest users.
+	Filter codersdk.WorkspaceFilter
+	// Percent is the percentage of connected agents to kill, between 0 and
+	// 100. At least one agent is killed if any are connected.
+	Percent float64
+	Killer  AgentKiller
+}
+
+var _ harness.ChaosInjector = KillAgents{}
+
+// Inject implements harness.ChaosInjector.
+func (k KillAgents) Inject(ctx context.Context) error {
+	if k.Percent < 0 || k.Percent > 100 {
+		return xerrors.Errorf("percent must be between 0 and 100, got %v", k.Percent)
+	}
+
+	type target struct {
+		workspace codersdk.Workspace
+		agent     codersdk.WorkspaceAgent
+	}
+	var targets []target
+	filter := k.Filter
+	filter.Limit = workspacesPageSize
+	for filter.Offset = 0; ; filter.Offset += workspacesPageSize {
+		res, err := k.Client.Workspaces(ctx, filter)
+		if err != nil {
+			return xerrors.Errorf("list workspaces: %w", err)
+		}
+		for _, workspace := range res.Workspaces {
+			for _, resource := range workspace.LatestBuild.Resources {
+				for _, agent := range resource.Agents {
+					if agent.Status == codersdk.WorkspaceAgentConnected {
+						targets = append(targets, target{workspace: workspace, agent: agent})
+					}
+				}
+			}
+		}
+		if len(res.Workspaces) < workspacesPageSize {
+			break
+		}
+	}
+	if len(targets) == 0 || k.Percent == 0 {
+		return nil
+	}
+
+	n := max(1, int(math.Round(float64(len(targets))*k.Percent/100)))
+	var merr error
+	for _, i := range harness.Rand(ctx).Perm(len(targets))[:n] {
+		t := targets[i]
+		if err := k.Killer.KillAgent(ctx, t.workspace, t.agent); err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("kill agent %s of workspace %s/%s: %w", t.agent.Name, t.workspace.OwnerName, t.workspace.Name, err))
+		}
+	}
+	return merr
+}
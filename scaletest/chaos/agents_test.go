@@ -0,0 +1,128 @@
+package chaos_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/chaos"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type agentKillerFn func(ctx context.Context, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent) error
+
+func (fn agentKillerFn) KillAgent(ctx context.Context, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent) error {
+	return fn(ctx, workspace, agent)
+}
+
+func TestKillAgents(t *testing.T) {
+	t.Parallel()
+
+	workspaces := make([]codersdk.Workspace, 0, 4)
+	for i := range 4 {
+		status := codersdk.WorkspaceAgentConnected
+		if i == 3 {
+			status = codersdk.WorkspaceAgentDisconnected
+		}
+		workspaces = append(workspaces, codersdk.Workspace{
+			ID:        uuid.New(),
+			Name:      "scaletest-" + string(rune('a'+i)),
+			OwnerName: "scaletest-user",
+			LatestBuild: codersdk.WorkspaceBuild{
+				Resources: []codersdk.WorkspaceResource{{
+					Agents: []codersdk.WorkspaceAgent{{
+						ID:     uuid.New(),
+						Name:   "main",
+						Status: status,
+					}},
+				}},
+			},
+		})
+	}
+
+	var (
+		queriesMu sync.Mutex
+		queries   []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queriesMu.Lock()
+		queries = append(queries, r.URL.Query().Get("q"))
+		queriesMu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(codersdk.WorkspacesResponse{
+			Workspaces: workspaces,
+			Count:      len(workspaces),
+		})
+	}))
+	t.Cleanup(srv.Close)
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	client := codersdk.New(serverURL)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu     sync.Mutex
+			killed = map[uuid.UUID]bool{}
+		)
+		injector := chaos.KillAgents{
+			Client:  client,
+			Filter:  codersdk.WorkspaceFilter{Owner: "scaletest-user"},
+			Percent: 50,
+			Killer: agentKillerFn(func(_ context.Context, _ codersdk.Workspace, agent codersdk.WorkspaceAgent) error {
+				mu.Lock()
+				defer mu.Unlock()
+				killed[agent.ID] = true
+				return nil
+			}),
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := injector.Inject(ctx)
+		require.NoError(t, err)
+
+		// Half of the three connected agents, rounded, are killed.
+		require.Len(t, killed, 2)
+		require.False(t, killed[workspaces[3].LatestBuild.Resources[0].Agents[0].ID], "disconnected agent was killed")
+		queriesMu.Lock()
+		defer queriesMu.Unlock()
+		require.Contains(t, queries, `owner:"scaletest-user"`)
+	})
+
+	t.Run("KillError", func(t *testing.T) {
+		t.Parallel()
+
+		injector := chaos.KillAgents{
+			Client:  client,
+			Percent: 100,
+			Killer: agentKillerFn(func(context.Context, codersdk.Workspace, codersdk.WorkspaceAgent) error {
+				return xerrors.New("pod not found")
+			}),
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := injector.Inject(ctx)
+		require.ErrorContains(t, err, "pod not found")
+		require.ErrorContains(t, err, "scaletest-user/scaletest-a")
+	})
+
+	t.Run("InvalidPercent", func(t *testing.T) {
+		t.Parallel()
+
+		injector := chaos.KillAgents{
+			Client:  client,
+			Percent: 150,
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := injector.Inject(ctx)
+		require.ErrorContains(t, err, "percent must be between 0 and 100")
+	})
+}
@@ -0,0 +1,34 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+
+	"golang.org/x/xerrors"
+)
+
+// Command is a command and its arguments run by the command based injectors,
+// e.g. a kubectl or tc invocation. The command inherits the environment of
+// the process, extended with variables describing the fault.
+type Command []string
+
+// run runs the command with the given additional environment variables and
+// returns an error including its output if it fails.
+func (c Command) run(ctx context.Context, env ...string) error {
+	if len(c) == 0 {
+		return xerrors.New("no command configured")
+	}
+
+	//nolint:gosec // The command is provided by the operator of the test.
+	cmd := exec.CommandContext(ctx, c[0], c[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return xerrors.Errorf("run %q: %w: %s", c[0], err, bytes.TrimSpace(out.Bytes()))
+	}
+	return nil
+}
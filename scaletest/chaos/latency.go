@@ -0,0 +1,55 @@
+package chaos
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// LatencyInjector adds latency to the network of the system under test, e.g.
+// using tc netem or a proxy between coderd and its database.
+type LatencyInjector interface {
+	AddLatency(ctx context.Context, latency time.Duration) error
+	RemoveLatency(ctx context.Context) error
+}
+
+// CommandLatencyInjector adds and removes latency by running commands. The
+// latency is passed to the add command in milliseconds in the
+// CHAOS_LATENCY_MS environment variable.
+type CommandLatencyInjector struct {
+	Add    Command
+	Remove Command
+}
+
+var _ LatencyInjector = CommandLatencyInjector{}
+
+// AddLatency implements LatencyInjector.
+func (i CommandLatencyInjector) AddLatency(ctx context.Context, latency time.Duration) error {
+	return i.Add.run(ctx, "CHAOS_LATENCY_MS="+strconv.FormatInt(latency.Milliseconds(), 10))
+}
+
+// RemoveLatency implements LatencyInjector.
+func (i CommandLatencyInjector) RemoveLatency(ctx context.Context) error {
+	return i.Remove.run(ctx)
+}
+
+// NetworkLatency adds network latency using a pluggable LatencyInjector for
+// as long as the fault is in effect.
+type NetworkLatency struct {
+	Injector LatencyInjector
+	Latency  time.Duration
+}
+
+var _ harness.ChaosReverter = NetworkLatency{}
+
+// Inject implements harness.ChaosInjector.
+func (l NetworkLatency) Inject(ctx context.Context) error {
+	return l.Injector.AddLatency(ctx, l.Latency)
+}
+
+// Revert implements harness.ChaosReverter.
+func (l NetworkLatency) Revert(ctx context.Context) error {
+	return l.Injector.RemoveLatency(ctx)
+}
@@ -0,0 +1,50 @@
+package chaos_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/chaos"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNetworkLatency(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses sh")
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		fault := chaos.NetworkLatency{
+			Injector: chaos.CommandLatencyInjector{
+				Add:    chaos.Command{"sh", "-c", `test "$CHAOS_LATENCY_MS" = 250`},
+				Remove: chaos.Command{"true"},
+			},
+			Latency: 250 * time.Millisecond,
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.NoError(t, fault.Inject(ctx))
+		require.NoError(t, fault.Revert(ctx))
+	})
+
+	t.Run("CommandError", func(t *testing.T) {
+		t.Parallel()
+
+		fault := chaos.NetworkLatency{
+			Injector: chaos.CommandLatencyInjector{
+				Add: chaos.Command{"sh", "-c", "echo 'RTNETLINK answers: Operation not permitted' >&2; exit 2"},
+			},
+			Latency: time.Second,
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := fault.Inject(ctx)
+		require.ErrorContains(t, err, "Operation not permitted")
+		err = fault.Revert(ctx)
+		require.ErrorContains(t, err, "no command configured")
+	})
+}
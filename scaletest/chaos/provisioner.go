@@ -0,0 +1,22 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// RestartProvisioner restarts provisioner daemons by running a command, e.g.
+// `kubectl rollout restart deployment/coder-provisioner`. Jobs that are in
+// progress while the daemons restart are expected to fail or be requeued, which
+// shows up in the results of the runs that depend on them.
+type RestartProvisioner struct {
+	Command Command
+}
+
+var _ harness.ChaosInjector = RestartProvisioner{}
+
+// Inject implements harness.ChaosInjector.
+func (p RestartProvisioner) Inject(ctx context.Context) error {
+	return p.Command.run(ctx)
+}
@@ -47,9 +47,10 @@ type runnerResult struct {
 }
 
 var (
-	_ harness.Runnable    = &Runner{}
-	_ harness.Cleanable   = &Runner{}
-	_ harness.Collectable = &Runner{}
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.Collectable        = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
 )
 
 func NewRunner(client *codersdk.Client, cfg Config) *Runner {
@@ -414,3 +415,13 @@ func (r *Runner) GetMetrics() map[string]any {
 		"turns_completed":        r.result.turnsCompleted,
 	}
 }
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	return map[string]float64{
+		"total_duration_seconds": r.result.totalDuration.Seconds(),
+		"retry_count":            float64(r.result.retryCount),
+		"event_count":            float64(r.result.eventCount),
+		"turns_completed":        float64(r.result.turnsCompleted),
+	}
+}
@@ -0,0 +1,47 @@
+package coordinationchurn
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+type Config struct {
+	// Workspace is the configuration for the workspace to create. The
+	// template must define a coder_external_agent, whose token is used to
+	// open the agent's coordinator sessions. The runner never waits for the
+	// agent to connect.
+	Workspace workspacebuild.Config `json:"workspace"`
+	// Clients is the number of client coordinator sessions opened to the
+	// agent in every cycle.
+	Clients int `json:"clients"`
+	// Cycles is the number of times the agent and client sessions are
+	// connected and disconnected.
+	Cycles int `json:"cycles"`
+	// Interval is the time to wait between cycles.
+	Interval time.Duration `json:"interval"`
+	// CoordinationTimeout is how long a single cycle may take to coordinate
+	// all sessions and propagate their disconnection.
+	CoordinationTimeout time.Duration `json:"coordination_timeout"`
+}
+
+func (c Config) Validate() error {
+	if err := c.Workspace.Validate(); err != nil {
+		return xerrors.Errorf("workspace config: %w", err)
+	}
+	if c.Clients <= 0 {
+		return xerrors.New("clients must be greater than 0")
+	}
+	if c.Cycles <= 0 {
+		return xerrors.New("cycles must be greater than 0")
+	}
+	if c.Interval < 0 {
+		return xerrors.New("interval must be at least 0")
+	}
+	if c.CoordinationTimeout <= 0 {
+		return xerrors.New("coordination_timeout must be greater than 0")
+	}
+	return nil
+}
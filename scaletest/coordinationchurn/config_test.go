@@ -0,0 +1,103 @@
+package coordinationchurn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/coordinationchurn"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	workspace := workspacebuild.Config{
+		OrganizationID: id,
+		UserID:         codersdk.Me,
+		Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+	}
+
+	cases := []struct {
+		name        string
+		config      coordinationchurn.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: coordinationchurn.Config{
+				Workspace:           workspace,
+				Clients:             10,
+				Cycles:              5,
+				Interval:            time.Second,
+				CoordinationTimeout: time.Minute,
+			},
+		},
+		{
+			name: "InvalidWorkspace",
+			config: coordinationchurn.Config{
+				Workspace:           workspacebuild.Config{UserID: codersdk.Me},
+				Clients:             1,
+				Cycles:              1,
+				CoordinationTimeout: time.Minute,
+			},
+			errContains: "workspace config",
+		},
+		{
+			name: "NoClients",
+			config: coordinationchurn.Config{
+				Workspace:           workspace,
+				Cycles:              1,
+				CoordinationTimeout: time.Minute,
+			},
+			errContains: "clients must be greater than 0",
+		},
+		{
+			name: "NoCycles",
+			config: coordinationchurn.Config{
+				Workspace:           workspace,
+				Clients:             1,
+				CoordinationTimeout: time.Minute,
+			},
+			errContains: "cycles must be greater than 0",
+		},
+		{
+			name: "NegativeInterval",
+			config: coordinationchurn.Config{
+				Workspace:           workspace,
+				Clients:             1,
+				Cycles:              1,
+				Interval:            -time.Second,
+				CoordinationTimeout: time.Minute,
+			},
+			errContains: "interval must be at least 0",
+		},
+		{
+			name: "NoCoordinationTimeout",
+			config: coordinationchurn.Config{
+				Workspace: workspace,
+				Clients:   1,
+				Cycles:    1,
+			},
+			errContains: "coordination_timeout must be greater than 0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package coordinationchurn
+
+import (
+	"net/netip"
+	"time"
+
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+// fakeNode returns a node with fresh keys advertising addr. The node is only
+// exchanged through the coordinator, so it doesn't need a working WireGuard
+// engine behind it.
+func fakeNode(addr netip.Addr) (*proto.Node, error) {
+	prefix := netip.PrefixFrom(addr, 128)
+	return tailnet.NodeToProto(&tailnet.Node{
+		ID:            tailcfg.NodeID(time.Now().UnixNano()),
+		AsOf:          time.Now(),
+		Key:           key.NewNode().Public(),
+		DiscoKey:      key.NewDisco().Public(),
+		PreferredDERP: 1,
+		Addresses:     []netip.Prefix{prefix},
+		AllowedIPs:    []netip.Prefix{prefix},
+	})
+}
+
+// peerEvent is a peer update received by the agent's coordinator session.
+type peerEvent struct {
+	id   string
+	kind proto.CoordinateResponse_PeerUpdate_Kind
+}
+
+// peerTracker follows the peers the agent's coordinator session knows about.
+type peerTracker struct {
+	connected map[string]struct{}
+	gone      map[string]struct{}
+}
+
+func newPeerTracker() *peerTracker {
+	return &peerTracker{
+		connected: make(map[string]struct{}),
+		gone:      make(map[string]struct{}),
+	}
+}
+
+// update applies the event and returns the number of peers that have sent a
+// node, and the number of those that have since disconnected.
+func (t *peerTracker) update(ev peerEvent) (connected, gone int) {
+	switch ev.kind {
+	case proto.CoordinateResponse_PeerUpdate_NODE:
+		t.connected[ev.id] = struct{}{}
+	case proto.CoordinateResponse_PeerUpdate_DISCONNECTED, proto.CoordinateResponse_PeerUpdate_LOST:
+		if _, ok := t.connected[ev.id]; ok {
+			t.gone[ev.id] = struct{}{}
+		}
+	}
+	return len(t.connected), len(t.gone)
+}
@@ -0,0 +1,60 @@
+package coordinationchurn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+func TestFakeNode(t *testing.T) {
+	t.Parallel()
+
+	// The coordinator only accepts agent nodes advertising the agent's own
+	// address, and client nodes advertising single addresses.
+	agentID := uuid.New()
+	agentNode, err := fakeNode(tailnet.TailscaleServicePrefix.AddrFromUUID(agentID))
+	require.NoError(t, err)
+	err = tailnet.AgentCoordinateeAuth{ID: agentID}.Authorize(context.Background(), &proto.CoordinateRequest{
+		UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: agentNode},
+	})
+	require.NoError(t, err)
+
+	clientNode, err := fakeNode(tailnet.TailscaleServicePrefix.RandomAddr())
+	require.NoError(t, err)
+	err = tailnet.ClientCoordinateeAuth{AgentID: agentID}.Authorize(context.Background(), &proto.CoordinateRequest{
+		UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: clientNode},
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, agentNode.Key, clientNode.Key)
+}
+
+func TestPeerTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newPeerTracker()
+	connected, gone := tracker.update(peerEvent{id: "a", kind: proto.CoordinateResponse_PeerUpdate_NODE})
+	require.Equal(t, 1, connected)
+	require.Zero(t, gone)
+
+	// Repeated node updates don't count twice.
+	connected, _ = tracker.update(peerEvent{id: "a", kind: proto.CoordinateResponse_PeerUpdate_NODE})
+	require.Equal(t, 1, connected)
+
+	connected, _ = tracker.update(peerEvent{id: "b", kind: proto.CoordinateResponse_PeerUpdate_NODE})
+	require.Equal(t, 2, connected)
+
+	// Disconnects of unknown peers are ignored.
+	_, gone = tracker.update(peerEvent{id: "c", kind: proto.CoordinateResponse_PeerUpdate_DISCONNECTED})
+	require.Zero(t, gone)
+
+	_, gone = tracker.update(peerEvent{id: "a", kind: proto.CoordinateResponse_PeerUpdate_DISCONNECTED})
+	require.Equal(t, 1, gone)
+	connected, gone = tracker.update(peerEvent{id: "b", kind: proto.CoordinateResponse_PeerUpdate_LOST})
+	require.Equal(t, 2, connected)
+	require.Equal(t, 2, gone)
+}
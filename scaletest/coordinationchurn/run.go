@@ -0,0 +1,372 @@
+package coordinationchurn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
+	"github.com/coder/websocket"
+)
+
+// Numeric metrics reported by the runner.
+const (
+	CyclesCompletedMetric        = "cycles_completed"
+	SessionsMetric               = "sessions"
+	ClientCoordinationMeanMetric = "client_coordination_seconds_mean"
+	ClientCoordinationMaxMetric  = "client_coordination_seconds_max"
+	AgentFanoutMeanMetric        = "agent_fanout_seconds_mean"
+	AgentFanoutMaxMetric         = "agent_fanout_seconds_max"
+	DisconnectMeanMetric         = "disconnect_seconds_mean"
+	DisconnectMaxMetric          = "disconnect_seconds_max"
+)
+
+const externalAgentResourceType = "coder_external_agent"
+
+// Runner repeatedly connects and disconnects an agent coordinator session
+// and a number of client coordinator sessions to it, without any traffic
+// between them. For every cycle it records how long each client took to
+// receive the agent's node, how long the agent took to receive the nodes of
+// all clients, and how long it took for the agent to learn that all clients
+// disconnected.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	workspacebuildRunner *workspacebuild.Runner
+
+	mu                 sync.Mutex
+	cycles             int
+	sessions           int
+	clientCoordination []time.Duration
+	agentFanout        []time.Duration
+	disconnects        []time.Duration
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.CleanupPlanner     = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	workspaceConfig := r.cfg.Workspace
+	workspaceConfig.NoWaitForAgents = true
+	r.workspacebuildRunner = workspacebuild.NewRunner(r.client, workspaceConfig)
+	slim, err := r.workspacebuildRunner.RunReturningWorkspace(ctx, id, logs)
+	if err != nil {
+		return xerrors.Errorf("create workspace: %w", err)
+	}
+
+	agentID, agentToken, err := r.externalAgent(ctx, slim.ID)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(logs, "Using external agent %s of workspace %q\n", agentID, slim.Name)
+
+	for cycle := 1; cycle <= r.cfg.Cycles; cycle++ {
+		if cycle > 1 && r.cfg.Interval > 0 {
+			t := time.NewTimer(r.cfg.Interval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+
+		err := r.runCycle(ctx, logger, agentID, agentToken)
+		if err != nil {
+			return xerrors.Errorf("cycle %d: %w", cycle, err)
+		}
+		r.mu.Lock()
+		r.cycles++
+		r.mu.Unlock()
+		logger.Debug(ctx, "completed cycle", slog.F("cycle", cycle))
+	}
+
+	return nil
+}
+
+// externalAgent returns the ID and token of the first external agent of the
+// workspace.
+func (r *Runner) externalAgent(ctx context.Context, workspaceID uuid.UUID) (uuid.UUID, string, error) {
+	workspace, err := r.client.Workspace(ctx, workspaceID)
+	if err != nil {
+		return uuid.Nil, "", xerrors.Errorf("fetch workspace: %w", err)
+	}
+	for _, res := range workspace.LatestBuild.Resources {
+		if res.Type != externalAgentResourceType || len(res.Agents) == 0 {
+			continue
+		}
+		agent := res.Agents[0]
+		creds, err := r.client.WorkspaceExternalAgentCredentials(ctx, workspace.ID, agent.Name)
+		if err != nil {
+			return uuid.Nil, "", xerrors.Errorf("get external agent credentials: %w", err)
+		}
+		return agent.ID, creds.AgentToken, nil
+	}
+	return uuid.Nil, "", xerrors.Errorf("workspace %q has no external agent", workspace.Name)
+}
+
+// runCycle connects the agent session, then all client sessions, waits for
+// them to be coordinated, and disconnects them again.
+func (r *Runner) runCycle(ctx context.Context, logger slog.Logger, agentID uuid.UUID, agentToken string) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.CoordinationTimeout)
+	defer cancel()
+
+	agentClient := agentsdk.New(r.client.URL, agentsdk.WithFixedToken(agentToken),
+		codersdk.WithHTTPClient(r.client.HTTPClient),
+		codersdk.WithLogger(logger))
+	_, tailnetClient, err := agentClient.ConnectRPC29WithRole(ctx, "agent")
+	if err != nil {
+		return xerrors.Errorf("connect agent: %w", err)
+	}
+	defer tailnetClient.DRPCConn().Close()
+	agentCoord, err := tailnetClient.Coordinate(ctx)
+	if err != nil {
+		return xerrors.Errorf("open agent coordinator session: %w", err)
+	}
+	agentNode, err := fakeNode(tailnet.TailscaleServicePrefix.AddrFromUUID(agentID))
+	if err != nil {
+		return xerrors.Errorf("create agent node: %w", err)
+	}
+	err = agentCoord.Send(&proto.CoordinateRequest{UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: agentNode}})
+	if err != nil {
+		return xerrors.Errorf("send agent node: %w", err)
+	}
+
+	events := make(chan peerEvent, r.cfg.Clients*2)
+	go func() {
+		for {
+			resp, err := agentCoord.Recv()
+			if err != nil {
+				return
+			}
+			for _, update := range resp.PeerUpdates {
+				select {
+				case events <- peerEvent{id: string(update.Id), kind: update.Kind}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	releaseClients := func() { releaseOnce.Do(func() { close(release) }) }
+	defer releaseClients()
+
+	start := time.Now()
+	eg, egCtx := errgroup.WithContext(ctx)
+	for range r.cfg.Clients {
+		eg.Go(func() error {
+			return r.runClient(egCtx, logger, agentID, release)
+		})
+	}
+
+	peers := newPeerTracker()
+	waitForPeers := func(done func(connected, gone int) bool) error {
+		for {
+			select {
+			case <-egCtx.Done():
+				return egCtx.Err()
+			case ev := <-events:
+				if done(peers.update(ev)) {
+					return nil
+				}
+			}
+		}
+	}
+
+	err = waitForPeers(func(connected, _ int) bool { return connected >= r.cfg.Clients })
+	if err != nil {
+		releaseClients()
+		if egErr := eg.Wait(); egErr != nil {
+			return xerrors.Errorf("client session: %w", egErr)
+		}
+		return xerrors.Errorf("wait for agent to receive %d client nodes: %w", r.cfg.Clients, err)
+	}
+	fanout := time.Since(start)
+
+	releaseClients()
+	disconnectStart := time.Now()
+	err = waitForPeers(func(_, gone int) bool { return gone >= r.cfg.Clients })
+	if egErr := eg.Wait(); egErr != nil {
+		return xerrors.Errorf("client session: %w", egErr)
+	}
+	if err != nil {
+		return xerrors.Errorf("wait for agent to see %d client disconnects: %w", r.cfg.Clients, err)
+	}
+	disconnect := time.Since(disconnectStart)
+
+	_ = agentCoord.Send(&proto.CoordinateRequest{Disconnect: &proto.CoordinateRequest_Disconnect{}})
+
+	r.mu.Lock()
+	r.agentFanout = append(r.agentFanout, fanout)
+	r.disconnects = append(r.disconnects, disconnect)
+	r.sessions++
+	r.mu.Unlock()
+	return nil
+}
+
+// runClient opens a client coordinator session to the agent, waits to
+// receive the agent's node and then disconnects once released.
+func (r *Runner) runClient(ctx context.Context, logger slog.Logger, agentID uuid.UUID, release <-chan struct{}) error {
+	coordinateURL, err := r.client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	if err != nil {
+		return xerrors.Errorf("parse url: %w", err)
+	}
+	wsOptions := &websocket.DialOptions{
+		HTTPClient:      r.client.HTTPClient,
+		CompressionMode: websocket.CompressionDisabled,
+	}
+	r.client.SessionTokenProvider.SetDialOption(wsOptions)
+
+	start := time.Now()
+	protocol, err := workspacesdk.NewWebsocketDialer(logger, coordinateURL, wsOptions).Dial(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("dial coordinator: %w", err)
+	}
+	defer protocol.Closer.Close()
+	// The coordinator session isn't bound to ctx, so close it to unblock
+	// reads once ctx is done.
+	stop := context.AfterFunc(ctx, func() { _ = protocol.Closer.Close() })
+	defer stop()
+	coord := protocol.Coordinator
+
+	node, err := fakeNode(tailnet.TailscaleServicePrefix.RandomAddr())
+	if err != nil {
+		return xerrors.Errorf("create client node: %w", err)
+	}
+	err = coord.Send(&proto.CoordinateRequest{UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: node}})
+	if err != nil {
+		return xerrors.Errorf("send client node: %w", err)
+	}
+	err = coord.Send(&proto.CoordinateRequest{AddTunnel: &proto.CoordinateRequest_Tunnel{Id: tailnet.UUIDToByteSlice(agentID)}})
+	if err != nil {
+		return xerrors.Errorf("add tunnel: %w", err)
+	}
+
+	agentIDBytes := tailnet.UUIDToByteSlice(agentID)
+	for received := false; !received; {
+		resp, err := coord.Recv()
+		if err != nil {
+			return xerrors.Errorf("receive agent node: %w", err)
+		}
+		if resp.Error != "" {
+			return xerrors.Errorf("coordinator error: %s", resp.Error)
+		}
+		for _, update := range resp.PeerUpdates {
+			if update.Kind == proto.CoordinateResponse_PeerUpdate_NODE && bytes.Equal(update.Id, agentIDBytes) {
+				received = true
+			}
+		}
+	}
+	took := time.Since(start)
+
+	r.mu.Lock()
+	r.clientCoordination = append(r.clientCoordination, took)
+	r.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-release:
+	}
+	err = coord.Send(&proto.CoordinateRequest{Disconnect: &proto.CoordinateRequest_Disconnect{}})
+	if err != nil {
+		return xerrors.Errorf("send disconnect: %w", err)
+	}
+
+	r.mu.Lock()
+	r.sessions++
+	r.mu.Unlock()
+	return nil
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := map[string]float64{
+		CyclesCompletedMetric: float64(r.cycles),
+		SessionsMetric:        float64(r.sessions),
+	}
+	for _, m := range []struct {
+		durations []time.Duration
+		mean, max string
+	}{
+		{r.clientCoordination, ClientCoordinationMeanMetric, ClientCoordinationMaxMetric},
+		{r.agentFanout, AgentFanoutMeanMetric, AgentFanoutMaxMetric},
+		{r.disconnects, DisconnectMeanMetric, DisconnectMaxMetric},
+	} {
+		if len(m.durations) == 0 {
+			continue
+		}
+		mean, maximum := loadtestutil.MeanMax(m.durations)
+		metrics[m.mean] = mean.Seconds()
+		metrics[m.max] = maximum.Seconds()
+	}
+	return metrics
+}
+
+// Cleanup implements Cleanable.
+func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
+	if r.workspacebuildRunner == nil {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(logs, "Cleaning up workspace...")
+	if err := r.workspacebuildRunner.Cleanup(ctx, id, logs); err != nil {
+		return xerrors.Errorf("cleanup workspace: %w", err)
+	}
+	return nil
+}
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(ctx context.Context, id string) ([]harness.CleanupResource, error) {
+	if r.workspacebuildRunner == nil {
+		return nil, nil
+	}
+	return r.workspacebuildRunner.CleanupPlan(ctx, id)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/scaletest/harness"
 	"github.com/coder/coder/v2/scaletest/loadtestutil"
 )
 
@@ -45,7 +46,7 @@ func (r *Runner) RunReturningUser(ctx context.Context, id string, logs io.Writer
 	r.client.SetLogBodies(true)
 
 	if r.cfg.Username == "" || r.cfg.Email == "" {
-		genUsername, genEmail, err := loadtestutil.GenerateUserIdentifier(id)
+		genUsername, genEmail, err := loadtestutil.GenerateUserIdentifier(ctx, id)
 		if err != nil {
 			return User{}, xerrors.Errorf("generate user identifier: %w", err)
 		}
@@ -99,6 +100,18 @@ func (r *Runner) RunReturningUser(ctx context.Context, id string, logs io.Writer
 	return User{User: user, SessionToken: loginRes.SessionToken}, nil
 }
 
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(_ context.Context, _ string) ([]harness.CleanupResource, error) {
+	if r.user.ID == uuid.Nil {
+		return nil, nil
+	}
+	return []harness.CleanupResource{{
+		Kind: "user",
+		ID:   r.user.ID.String(),
+		Name: r.user.Username,
+	}}, nil
+}
+
 func (r *Runner) Cleanup(ctx context.Context, _ string, logs io.Writer) error {
 	if r.user.ID != uuid.Nil {
 		err := r.client.DeleteUser(ctx, r.user.ID)
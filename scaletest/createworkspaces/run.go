@@ -30,8 +30,9 @@ type Runner struct {
 }
 
 var (
-	_ harness.Runnable  = &Runner{}
-	_ harness.Cleanable = &Runner{}
+	_ harness.Runnable       = &Runner{}
+	_ harness.Cleanable      = &Runner{}
+	_ harness.CleanupPlanner = &Runner{}
 )
 
 func NewRunner(client *codersdk.Client, cfg Config) *Runner {
@@ -169,6 +170,9 @@ func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
 		if err != nil {
 			return xerrors.Errorf("cleanup workspace: %w", err)
 		}
+		// Forget the workspace so that it isn't cleaned up again if the run
+		// is retried and the next attempt fails before creating one.
+		r.workspacebuildRunner = nil
 	}
 
 	if r.createUserRunner != nil {
@@ -176,7 +180,34 @@ func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
 		if err != nil {
 			return xerrors.Errorf("cleanup user: %w", err)
 		}
+		r.createUserRunner = nil
 	}
 
 	return nil
 }
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(ctx context.Context, id string) ([]harness.CleanupResource, error) {
+	if r.cfg.NoCleanup {
+		return nil, nil
+	}
+
+	var resources []harness.CleanupResource
+	if r.workspacebuildRunner != nil {
+		res, err := r.workspacebuildRunner.CleanupPlan(ctx, id)
+		if err != nil {
+			return nil, xerrors.Errorf("plan workspace cleanup: %w", err)
+		}
+		resources = append(resources, res...)
+	}
+
+	if r.createUserRunner != nil {
+		res, err := r.createUserRunner.CleanupPlan(ctx, id)
+		if err != nil {
+			return nil, xerrors.Errorf("plan user cleanup: %w", err)
+		}
+		resources = append(resources, res...)
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,41 @@
+package devcontainer
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+type Config struct {
+	// Workspace is the configuration for the workspace to create. The
+	// template must define at least one devcontainer. The runner always
+	// waits for the build and the agents.
+	Workspace workspacebuild.Config `json:"workspace"`
+	// Devcontainers is the number of devcontainers the runner waits for
+	// across all agents of the workspace.
+	Devcontainers int `json:"devcontainers"`
+	// DevcontainerTimeout is how long to wait for the devcontainers to be
+	// running and their sub-agents to be ready after the workspace agents
+	// connected. This includes cloning the repository and building the image.
+	DevcontainerTimeout time.Duration `json:"devcontainer_timeout"`
+	// PollInterval is how often the devcontainer status is polled.
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+func (c Config) Validate() error {
+	if err := c.Workspace.Validate(); err != nil {
+		return xerrors.Errorf("workspace config: %w", err)
+	}
+	if c.Devcontainers <= 0 {
+		return xerrors.New("devcontainers must be greater than 0")
+	}
+	if c.DevcontainerTimeout <= 0 {
+		return xerrors.New("devcontainer_timeout must be greater than 0")
+	}
+	if c.PollInterval <= 0 {
+		return xerrors.New("poll_interval must be greater than 0")
+	}
+	return nil
+}
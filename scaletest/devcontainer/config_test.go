@@ -0,0 +1,91 @@
+package devcontainer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/devcontainer"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	workspace := workspacebuild.Config{
+		OrganizationID: id,
+		UserID:         codersdk.Me,
+		Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+	}
+
+	cases := []struct {
+		name        string
+		config      devcontainer.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: devcontainer.Config{
+				Workspace:           workspace,
+				Devcontainers:       2,
+				DevcontainerTimeout: time.Minute,
+				PollInterval:        time.Second,
+			},
+		},
+		{
+			name: "InvalidWorkspace",
+			config: devcontainer.Config{
+				Workspace:           workspacebuild.Config{UserID: codersdk.Me},
+				Devcontainers:       1,
+				DevcontainerTimeout: time.Minute,
+				PollInterval:        time.Second,
+			},
+			errContains: "workspace config",
+		},
+		{
+			name: "NoDevcontainers",
+			config: devcontainer.Config{
+				Workspace:           workspace,
+				DevcontainerTimeout: time.Minute,
+				PollInterval:        time.Second,
+			},
+			errContains: "devcontainers must be greater than 0",
+		},
+		{
+			name: "NoTimeout",
+			config: devcontainer.Config{
+				Workspace:     workspace,
+				Devcontainers: 1,
+				PollInterval:  time.Second,
+			},
+			errContains: "devcontainer_timeout must be greater than 0",
+		},
+		{
+			name: "NoPollInterval",
+			config: devcontainer.Config{
+				Workspace:           workspace,
+				Devcontainers:       1,
+				DevcontainerTimeout: time.Minute,
+			},
+			errContains: "poll_interval must be greater than 0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package devcontainer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+// Numeric metrics reported by the runner.
+const (
+	WorkspaceReadyMetric        = "workspace_ready_seconds"
+	DevcontainerStartMeanMetric = "devcontainer_start_seconds_mean"
+	DevcontainerStartMaxMetric  = "devcontainer_start_seconds_max"
+	SubagentReadyMeanMetric     = "subagent_ready_seconds_mean"
+	SubagentReadyMaxMetric      = "subagent_ready_seconds_max"
+)
+
+// Runner creates a workspace from a devcontainer template and waits for its
+// devcontainers to be built and started and their sub-agents to be ready.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	workspacebuildRunner *workspacebuild.Runner
+
+	// workspaceReady is the time from creating the workspace until its
+	// agents connected.
+	workspaceReady time.Duration
+	// devcontainerStarts is the time from the agents connecting until each
+	// devcontainer was running.
+	devcontainerStarts []time.Duration
+	// subagentsReady is the time from the agents connecting until the
+	// sub-agent of each devcontainer was ready.
+	subagentsReady []time.Duration
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.CleanupPlanner     = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	workspaceConfig := r.cfg.Workspace
+	workspaceConfig.NoWaitForBuild = false
+	workspaceConfig.NoWaitForAgents = false
+	r.workspacebuildRunner = workspacebuild.NewRunner(r.client, workspaceConfig)
+
+	start := time.Now()
+	slim, err := r.workspacebuildRunner.RunReturningWorkspace(ctx, id, logs)
+	if err != nil {
+		return xerrors.Errorf("create workspace: %w", err)
+	}
+	agentsReady := time.Now()
+	r.workspaceReady = agentsReady.Sub(start)
+	_, _ = fmt.Fprintf(logs, "Workspace %q ready after %s, waiting for devcontainers...\n", slim.Name, r.workspaceReady)
+
+	waitCtx, cancel := context.WithTimeout(ctx, r.cfg.DevcontainerTimeout)
+	defer cancel()
+	err = r.waitForDevcontainers(waitCtx, logs, slim.ID, agentsReady)
+	if err != nil {
+		return xerrors.Errorf("wait for devcontainers: %w", err)
+	}
+
+	return nil
+}
+
+// waitForDevcontainers polls the devcontainers of every agent in the
+// workspace until the expected number of devcontainers are running and their
+// sub-agents are ready, recording when each got there.
+func (r *Runner) waitForDevcontainers(ctx context.Context, logs io.Writer, workspaceID uuid.UUID, since time.Time) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	workspace, err := r.client.Workspace(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("fetch workspace: %w", err)
+	}
+	var agentIDs []uuid.UUID
+	for _, res := range workspace.LatestBuild.Resources {
+		for _, agent := range res.Agents {
+			if !agent.ParentID.Valid {
+				agentIDs = append(agentIDs, agent.ID)
+			}
+		}
+	}
+	if len(agentIDs) == 0 {
+		return xerrors.New("workspace has no agents")
+	}
+
+	started := map[uuid.UUID]bool{}
+	ready := map[uuid.UUID]bool{}
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		for _, agentID := range agentIDs {
+			res, err := r.client.WorkspaceAgentListContainers(ctx, agentID, nil)
+			if err != nil {
+				return xerrors.Errorf("list containers of agent %s: %w", agentID, err)
+			}
+
+			for _, dc := range res.Devcontainers {
+				switch {
+				case dc.Status == codersdk.WorkspaceAgentDevcontainerStatusError:
+					return xerrors.Errorf("devcontainer %q failed: %s", dc.Name, dc.Error)
+				case dc.Status != codersdk.WorkspaceAgentDevcontainerStatusRunning:
+					continue
+				}
+
+				if !started[dc.ID] {
+					started[dc.ID] = true
+					took := time.Since(since)
+					r.devcontainerStarts = append(r.devcontainerStarts, took)
+					_, _ = fmt.Fprintf(logs, "\tDevcontainer %q running after %s\n", dc.Name, took)
+				}
+				if ready[dc.ID] || dc.Agent == nil {
+					continue
+				}
+
+				subagent, err := r.client.WorkspaceAgent(ctx, dc.Agent.ID)
+				if err != nil {
+					return xerrors.Errorf("fetch sub-agent of devcontainer %q: %w", dc.Name, err)
+				}
+				if subagent.Status != codersdk.WorkspaceAgentConnected || subagent.LifecycleState != codersdk.WorkspaceAgentLifecycleReady {
+					continue
+				}
+				ready[dc.ID] = true
+				took := time.Since(since)
+				r.subagentsReady = append(r.subagentsReady, took)
+				_, _ = fmt.Fprintf(logs, "\tSub-agent %q of devcontainer %q ready after %s\n", subagent.Name, dc.Name, took)
+			}
+		}
+
+		if len(ready) >= r.cfg.Devcontainers {
+			_, _ = fmt.Fprintf(logs, "\n%d devcontainers ready!\n", len(ready))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("%d of %d devcontainers running, %d ready: %w", len(started), r.cfg.Devcontainers, len(ready), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	metrics := map[string]float64{}
+	if r.workspaceReady > 0 {
+		metrics[WorkspaceReadyMetric] = r.workspaceReady.Seconds()
+	}
+	if len(r.devcontainerStarts) > 0 {
+		mean, maximum := loadtestutil.MeanMax(r.devcontainerStarts)
+		metrics[DevcontainerStartMeanMetric] = mean.Seconds()
+		metrics[DevcontainerStartMaxMetric] = maximum.Seconds()
+	}
+	if len(r.subagentsReady) > 0 {
+		mean, maximum := loadtestutil.MeanMax(r.subagentsReady)
+		metrics[SubagentReadyMeanMetric] = mean.Seconds()
+		metrics[SubagentReadyMaxMetric] = maximum.Seconds()
+	}
+	return metrics
+}
+
+// Cleanup implements Cleanable.
+func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
+	if r.workspacebuildRunner == nil {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(logs, "Cleaning up workspace...")
+	if err := r.workspacebuildRunner.Cleanup(ctx, id, logs); err != nil {
+		return xerrors.Errorf("cleanup workspace: %w", err)
+	}
+	return nil
+}
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(ctx context.Context, id string) ([]harness.CleanupResource, error) {
+	if r.workspacebuildRunner == nil {
+		return nil, nil
+	}
+	return r.workspacebuildRunner.CleanupPlan(ctx, id)
+}
@@ -0,0 +1,150 @@
+package devcontainer_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/agent/agentcontainers/acmock"
+	"github.com/coder/coder/v2/agent/agentcontainers/watcher"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/scaletest/devcontainer"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+	if testutil.RaceEnabled() {
+		t.Skip("Race detector enabled, skipping time-sensitive test.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionGraph: echo.ProvisionGraphWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	// The agent reports a running devcontainer, but never injects a sub-agent
+	// into it.
+	var (
+		workspaceFolder = t.TempDir()
+		configFile      = filepath.Join(workspaceFolder, ".devcontainer", "devcontainer.json")
+		container       = codersdk.WorkspaceAgentContainer{
+			ID:           uuid.NewString(),
+			CreatedAt:    dbtime.Now(),
+			FriendlyName: "devcontainer",
+			Image:        "busybox:latest",
+			Labels: map[string]string{
+				agentcontainers.DevcontainerLocalFolderLabel: workspaceFolder,
+				agentcontainers.DevcontainerConfigFileLabel:  configFile,
+			},
+			Running: true,
+			Status:  "running",
+		}
+		mCtrl  = gomock.NewController(t)
+		mCCLI  = acmock.NewMockContainerCLI(mCtrl)
+		mDCCLI = acmock.NewMockDevcontainerCLI(mCtrl)
+	)
+	mCCLI.EXPECT().List(gomock.Any()).Return(codersdk.WorkspaceAgentListContainersResponse{
+		Containers: []codersdk.WorkspaceAgentContainer{container},
+	}, nil).AnyTimes()
+	// "<none>" disables injecting the sub-agent.
+	mCCLI.EXPECT().DetectArchitecture(gomock.Any(), container.ID).Return("<none>", nil).AnyTimes()
+	mDCCLI.EXPECT().ReadConfig(gomock.Any(), workspaceFolder, configFile, gomock.Any()).Return(agentcontainers.DevcontainerConfig{}, nil).AnyTimes()
+
+	// The runner creates the workspace on its own, so the agent is started
+	// once the workspace has been built.
+	go func() {
+		var workspace codersdk.Workspace
+		if !assert.Eventually(t, func() bool {
+			res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+				Owner: codersdk.Me,
+			})
+			if err != nil || len(res.Workspaces) != 1 {
+				return false
+			}
+			workspace = res.Workspaces[0]
+			return true
+		}, testutil.WaitShort, testutil.IntervalMedium) {
+			return
+		}
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		agentCloser := agent.New(agent.Options{
+			Client: agentsdk.New(client.URL, agentsdk.WithFixedToken(authToken)),
+			Logger: slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).
+				Named("agent").
+				Leveled(slog.LevelWarn),
+			Devcontainers: true,
+			DevcontainerAPIOptions: []agentcontainers.Option{
+				agentcontainers.WithContainerCLI(mCCLI),
+				agentcontainers.WithDevcontainerCLI(mDCCLI),
+				agentcontainers.WithWatcher(watcher.NewNoop()),
+				agentcontainers.WithDevcontainers([]codersdk.WorkspaceAgentDevcontainer{{
+					ID:              uuid.New(),
+					Name:            "test-devcontainer",
+					WorkspaceFolder: workspaceFolder,
+					ConfigPath:      configFile,
+				}}, nil),
+			},
+		})
+		t.Cleanup(func() {
+			_ = agentCloser.Close()
+		})
+	}()
+
+	runner := devcontainer.NewRunner(client, devcontainer.Config{
+		Workspace: workspacebuild.Config{
+			OrganizationID: user.OrganizationID,
+			UserID:         codersdk.Me,
+			Request: codersdk.CreateWorkspaceRequest{
+				TemplateID: template.ID,
+			},
+		},
+		Devcontainers:       1,
+		DevcontainerTimeout: 3 * time.Second,
+		PollInterval:        testutil.IntervalFast,
+	})
+
+	logs := bytes.NewBuffer(nil)
+	err := runner.Run(ctx, "1", logs)
+	t.Log("Runner logs:\n\n" + logs.String())
+	require.ErrorContains(t, err, "1 of 1 devcontainers running, 0 ready")
+	require.Regexp(t, `Devcontainer ".+" running after`, logs.String())
+
+	metrics := runner.GetNumericMetrics()
+	require.Positive(t, metrics[devcontainer.WorkspaceReadyMetric])
+	require.Contains(t, metrics, devcontainer.DevcontainerStartMeanMetric)
+	require.Contains(t, metrics, devcontainer.DevcontainerStartMaxMetric)
+	require.NotContains(t, metrics, devcontainer.SubagentReadyMeanMetric)
+
+	plan, err := runner.CleanupPlan(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	err = runner.Cleanup(ctx, "1", bytes.NewBuffer(nil))
+	require.NoError(t, err)
+}
@@ -0,0 +1,233 @@
+package harness
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+// metricSampleSize is the number of values per numeric metric that are sampled
+// to compute quantiles when results are aggregated while the test runs.
+const metricSampleSize = 10000
+
+// ResultRetention bounds the number of runs whose individual results, including
+// their logs, are kept in memory. See WithResultRetention.
+type ResultRetention struct {
+	// Passed is the number of passed runs whose results are retained.
+	Passed int
+	// Failed is the number of failed runs whose results are retained, to
+	// have examples of the errors to debug.
+	Failed int
+}
+
+// WithResultRetention aggregates the results of the runs while the test runs,
+// and only retains the individual results of the first runs that pass or fail
+// up to the given limits. The logs and metrics of every other run are released
+// once the run has finished, so tests with hundreds of thousands of runs don't
+// need gigabytes of memory to produce their results.
+//
+// Totals, latency and tag breakdowns and bandwidth summaries cover every run
+// regardless. Metric quantiles are computed from a uniform random sample of
+// the values once a metric has more than 10,000 values, while their count,
+// minimum, maximum and mean remain exact. Skipped runs are always retained, and
+// Cleanup still cleans up every run. Results.TotalDiscarded is the number of
+// runs that are missing from Results.Runs.
+func WithResultRetention(r ResultRetention) Option {
+	return func(h *TestHarness) {
+		h.aggregate = &resultAggregator{
+			maxSamples: metricSampleSize,
+			retention:  &r,
+		}
+	}
+}
+
+// resultAggregator accumulates the totals and summaries of the results one run
+// at a time.
+type resultAggregator struct {
+	mu sync.Mutex
+	// maxSamples bounds the number of values sampled per metric. Zero keeps
+	// every value.
+	maxSamples int
+	rng        *rand.Rand
+	// retention limits the runs whose results are retained. Every run is
+	// retained if it is nil.
+	retention      *ResultRetention
+	retainedPassed int
+	retainedFailed int
+
+	totalRuns           int
+	totalPass           int
+	totalFail           int
+	totalTimedOut       int
+	totalTestTimedOut   int
+	totalPassAfterRetry int
+	totalWarmup         int
+	totalSkipped        int
+	totalDiscarded      int
+	// totalDuration is the sum of the durations of the measured runs,
+	// including those whose results are not retained.
+	totalDuration time.Duration
+	tags          map[string]map[string]TagSummary
+	metrics       map[string]*metricAggregate
+	bandwidth     map[string]BandwidthSummary
+	// windows holds the time range of every measured run if trackWindows is
+	// set, to find the runs that were affected by chaos hooks.
+	trackWindows bool
+	windows      [][2]time.Time
+}
+
+// add adds the result of a finished or skipped run to the aggregate, and
+// returns whether the individual result should be retained.
+func (a *resultAggregator) add(run RunResult) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if run.Skipped {
+		a.totalSkipped++
+		return true
+	}
+	retain := a.retain(run)
+	if !retain {
+		a.totalDiscarded++
+	}
+	if run.Warmup {
+		a.totalWarmup++
+		return retain
+	}
+
+	a.totalRuns++
+	a.totalDuration += time.Duration(run.Duration)
+	if run.Error == nil {
+		a.totalPass++
+		if run.Attempts > 1 {
+			a.totalPassAfterRetry++
+		}
+	} else {
+		a.totalFail++
+	}
+	if run.TimedOut {
+		a.totalTimedOut++
+	}
+	if run.TestTimedOut {
+		a.totalTestTimedOut++
+	}
+	if a.trackWindows {
+		a.windows = append(a.windows, [2]time.Time{run.StartedAt, run.StartedAt.Add(time.Duration(run.Duration))})
+	}
+
+	for key, value := range run.Tags {
+		if a.tags == nil {
+			a.tags = map[string]map[string]TagSummary{}
+		}
+		values, ok := a.tags[key]
+		if !ok {
+			values = map[string]TagSummary{}
+			a.tags[key] = values
+		}
+		summary := values[value]
+		summary.TotalRuns++
+		if run.Error == nil {
+			summary.TotalPass++
+		} else {
+			summary.TotalFail++
+		}
+		values[value] = summary
+	}
+
+	if a.metrics == nil {
+		a.metrics = map[string]*metricAggregate{}
+	}
+	addMetrics(a.metrics, run.NumericMetrics, a.maxSamples, a.rng)
+
+	for channel, count := range run.Bytes {
+		if a.bandwidth == nil {
+			a.bandwidth = map[string]BandwidthSummary{}
+		}
+		s := a.bandwidth[channel]
+		s.Runs++
+		s.Read += count.Read
+		s.Written += count.Written
+		a.bandwidth[channel] = s
+	}
+	return retain
+}
+
+// retain returns whether the result of the given finished run fits within the
+// retention limits, and counts it against them if it does.
+func (a *resultAggregator) retain(run RunResult) bool {
+	switch {
+	case a.retention == nil:
+		return true
+	case run.Error == nil && a.retainedPassed < a.retention.Passed:
+		a.retainedPassed++
+		return true
+	case run.Error != nil && a.retainedFailed < a.retention.Failed:
+		a.retainedFailed++
+		return true
+	default:
+		return false
+	}
+}
+
+// apply sets the totals and summaries of the results from the aggregate.
+// tagLatencies are the latency histograms of the harness by tag key and value.
+func (a *resultAggregator) apply(r *Results, tagLatencies map[string]map[string]*Histogram) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r.TotalRuns = a.totalRuns
+	r.TotalPass = a.totalPass
+	r.TotalFail = a.totalFail
+	r.TotalTimedOut = a.totalTimedOut
+	r.TotalTestTimedOut = a.totalTestTimedOut
+	r.TotalPassAfterRetry = a.totalPassAfterRetry
+	r.TotalWarmup = a.totalWarmup
+	r.TotalSkipped = a.totalSkipped
+	r.TotalDiscarded = a.totalDiscarded
+	if a.totalRuns > 0 {
+		r.AvgDuration = httpapi.Duration(a.totalDuration / time.Duration(a.totalRuns))
+	}
+	for _, window := range a.windows {
+		if len(activeChaosHooks(r.Chaos, window[0], window[1])) > 0 {
+			r.TotalChaosAffected++
+		}
+	}
+
+	r.Tags = map[string]map[string]TagSummary{}
+	for key, values := range a.tags {
+		r.Tags[key] = make(map[string]TagSummary, len(values))
+		for value, summary := range values {
+			if hist, ok := tagLatencies[key][value]; ok {
+				summary.Latency = hist.Summary()
+			}
+			r.Tags[key][value] = summary
+		}
+	}
+	r.Metrics = summarizeMetrics(a.metrics)
+
+	if len(a.bandwidth) > 0 {
+		elapsed := time.Duration(r.Elapsed)
+		r.Bandwidth = make(map[string]BandwidthSummary, len(a.bandwidth))
+		for channel, s := range a.bandwidth {
+			if elapsed > 0 {
+				s.ReadPerSecond = float64(s.Read) / elapsed.Seconds()
+				s.WrittenPerSecond = float64(s.Written) / elapsed.Seconds()
+			}
+			r.Bandwidth[channel] = s
+		}
+	}
+}
+
+// recordResult adds the result of the given finished or skipped run to the
+// aggregate of the harness, if results are aggregated while the test runs, and
+// releases the result if it is not retained.
+func (h *TestHarness) recordResult(run *TestRun) {
+	if h.aggregate == nil {
+		return
+	}
+	if !h.aggregate.add(run.Result()) {
+		run.discard()
+	}
+}
@@ -0,0 +1,143 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_ResultRetention(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var cleanups atomic.Int64
+		h := harness.NewTestHarness(harness.ConcurrentExecutionStrategy{}, harness.ConcurrentExecutionStrategy{},
+			harness.WithResultRetention(harness.ResultRetention{Passed: 2, Failed: 3}),
+		)
+		for i := range 100 {
+			run := h.AddRun("test", strconv.Itoa(i), numericTestFns{
+				testFns: testFns{
+					RunFn: func(_ context.Context, _ string, logs io.Writer) error {
+						_, _ = io.WriteString(logs, "some logs\n")
+						if i%2 == 1 {
+							return xerrors.New("odd run")
+						}
+						return nil
+					},
+					CleanupFn: func(context.Context, string, io.Writer) error {
+						cleanups.Add(1)
+						return nil
+					},
+				},
+				metrics: map[string]float64{"value": float64(i)},
+			})
+			run.SetTag("parity", strconv.Itoa(i%2))
+		}
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		require.Equal(t, 100, res.TotalRuns)
+		require.Equal(t, 50, res.TotalPass)
+		require.Equal(t, 50, res.TotalFail)
+		require.Equal(t, 95, res.TotalDiscarded)
+		require.Len(t, res.Runs, 5)
+		var passed, failed int
+		for _, run := range res.Runs {
+			require.Equal(t, "some logs\n", run.Logs)
+			if run.Error == nil {
+				passed++
+			} else {
+				failed++
+			}
+		}
+		require.Equal(t, 2, passed)
+		require.Equal(t, 3, failed)
+
+		// Summaries cover every run.
+		require.Equal(t, int64(100), res.Latency["test"].Count)
+		require.Equal(t, 50, res.Tags["parity"]["1"].TotalFail)
+		require.Equal(t, int64(50), res.Tags["parity"]["1"].Latency.Count)
+		require.Equal(t, 100, res.Metrics["value"].Count)
+		require.Equal(t, 0.0, res.Metrics["value"].Min)
+		require.Equal(t, 99.0, res.Metrics["value"].Max)
+		require.Equal(t, 49.5, res.Metrics["value"].Mean)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "95 run results not retained")
+
+		// Every run is still cleaned up.
+		require.NoError(t, h.Cleanup(context.Background()))
+		require.Equal(t, int64(100), cleanups.Load())
+	})
+
+	t.Run("AvgDuration", func(t *testing.T) {
+		t.Parallel()
+
+		const sleep = 20 * time.Millisecond
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithResultRetention(harness.ResultRetention{Passed: 1}),
+		)
+		for i := range 5 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					// Only the first, quick run is retained.
+					if i > 0 {
+						time.Sleep(sleep)
+					}
+					return nil
+				},
+			})
+		}
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		require.Len(t, res.Runs, 1)
+		require.Equal(t, 4, res.TotalDiscarded)
+		// The average covers the discarded runs too.
+		require.GreaterOrEqual(t, time.Duration(res.AvgDuration), 4*sleep/5)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "Avg. duration:  "+time.Duration(res.AvgDuration).String())
+	})
+
+	t.Run("SampledMetrics", func(t *testing.T) {
+		t.Parallel()
+
+		const count = 20000
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithResultRetention(harness.ResultRetention{}),
+		)
+		for i := range count {
+			h.AddRun("test", strconv.Itoa(i), numericTestFns{
+				testFns: fakeTestFns(nil, nil),
+				metrics: map[string]float64{"value": float64(i)},
+			})
+		}
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		require.Empty(t, res.Runs)
+		require.Equal(t, count, res.TotalDiscarded)
+		m := res.Metrics["value"]
+		require.Equal(t, count, m.Count)
+		require.Equal(t, 0.0, m.Min)
+		require.Equal(t, float64(count-1), m.Max)
+		require.Equal(t, float64(count-1)/2, m.Mean)
+		// Quantiles are estimated from a sample.
+		require.InDelta(t, count/2, m.P50, count/20)
+		require.InDelta(t, count*0.95, m.P95, count/20)
+	})
+}
@@ -0,0 +1,76 @@
+package harness
+
+import (
+	"context"
+	"time"
+)
+
+// annotationTimeout is how long posting a single annotation may take.
+const annotationTimeout = 10 * time.Second
+
+// Tags applied to the annotations posted by the harness.
+const (
+	AnnotationTagScaletest = "scaletest"
+	AnnotationTagRun       = "run"
+	AnnotationTagWarmup    = "warmup"
+	AnnotationTagCleanup   = "cleanup"
+	AnnotationTagChaos     = "chaos"
+	// AnnotationTagCircuitBreaker marks the time the circuit breaker aborted
+	// the test.
+	AnnotationTagCircuitBreaker = "circuit_breaker"
+)
+
+// Annotation marks a point in time, or a time range if TimeEnd is set, on the
+// dashboards of the deployment under test.
+type Annotation struct {
+	Time    time.Time `json:"time"`
+	TimeEnd time.Time `json:"time_end,omitzero"`
+	Text    string    `json:"text"`
+	Tags    []string  `json:"tags"`
+}
+
+// Annotator posts annotations to an external system, e.g. Grafana, so that the
+// phases of a test are visible alongside the metrics of the deployment.
+type Annotator interface {
+	// Annotate posts the annotation and returns an ID that can be passed to
+	// EndAnnotation to turn it into a time range once the phase it marks has
+	// finished.
+	Annotate(ctx context.Context, annotation Annotation) (string, error)
+	// EndAnnotation sets the end time of an annotation posted by Annotate.
+	EndAnnotation(ctx context.Context, id string, end time.Time) error
+}
+
+// WithAnnotator posts annotations marking the run phase, the end of the warmup
+// phase, injected chaos faults, circuit breaker trips and the cleanup phase of
+// the test. Posting
+// annotations is best effort: errors are ignored so that an unavailable
+// dashboard never affects the test, and annotators should report them
+// themselves.
+func WithAnnotator(a Annotator) Option {
+	return func(h *TestHarness) {
+		h.annotator = a
+	}
+}
+
+// annotate posts an annotation if an annotator is configured and returns a
+// function that ends it. The annotation is posted even if the context is
+// canceled, so that the end of an interrupted phase is still marked.
+func (h *TestHarness) annotate(ctx context.Context, annotation Annotation) (end func()) {
+	if h.annotator == nil {
+		return func() {}
+	}
+
+	annotation.Tags = append([]string{AnnotationTagScaletest}, annotation.Tags...)
+	ctx = context.WithoutCancel(ctx)
+	postCtx, cancel := context.WithTimeout(ctx, annotationTimeout)
+	defer cancel()
+	id, err := h.annotator.Annotate(postCtx, annotation)
+	if err != nil || !annotation.TimeEnd.IsZero() {
+		return func() {}
+	}
+	return func() {
+		endCtx, cancel := context.WithTimeout(ctx, annotationTimeout)
+		defer cancel()
+		_ = h.annotator.EndAnnotation(endCtx, id, time.Now())
+	}
+}
@@ -0,0 +1,141 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeAnnotator struct {
+	mut         sync.Mutex
+	annotations []harness.Annotation
+	err         error
+}
+
+func (a *fakeAnnotator) Annotate(_ context.Context, annotation harness.Annotation) (string, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	if a.err != nil {
+		return "", a.err
+	}
+	a.annotations = append(a.annotations, annotation)
+	return strconv.Itoa(len(a.annotations) - 1), nil
+}
+
+func (a *fakeAnnotator) EndAnnotation(_ context.Context, id string, end time.Time) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	i, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+	a.annotations[i].TimeEnd = end
+	return nil
+}
+
+func (a *fakeAnnotator) find(t *testing.T, tag string) harness.Annotation {
+	t.Helper()
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	for _, annotation := range a.annotations {
+		if annotation.Tags[1] == tag {
+			require.Equal(t, harness.AnnotationTagScaletest, annotation.Tags[0])
+			return annotation
+		}
+	}
+	t.Fatalf("no annotation with tag %q", tag)
+	return harness.Annotation{}
+}
+
+func Test_Annotations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Phases", func(t *testing.T) {
+		t.Parallel()
+
+		annotator := &fakeAnnotator{}
+		injected := make(chan struct{})
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithAnnotator(annotator),
+			harness.WithWarmup(harness.Warmup{Runs: 1}),
+			harness.WithChaos(harness.ChaosHook{
+				Name: "latency",
+				Injector: chaosReverter{
+					injectFn: func(context.Context) error {
+						close(injected)
+						return nil
+					},
+					revertFn: func(context.Context) error {
+						return nil
+					},
+				},
+			}),
+		)
+		for i := range 2 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-injected:
+					}
+					return nil
+				},
+				CleanupFn: func(context.Context, string, io.Writer) error {
+					return nil
+				},
+			})
+		}
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+		err = h.Cleanup(ctx)
+		require.NoError(t, err)
+
+		run := annotator.find(t, harness.AnnotationTagRun)
+		require.Equal(t, "Scaletest run (2 runs)", run.Text)
+		require.False(t, run.TimeEnd.Before(run.Time))
+
+		warmup := annotator.find(t, harness.AnnotationTagWarmup)
+		require.True(t, warmup.TimeEnd.IsZero())
+		require.False(t, warmup.Time.Before(run.Time))
+
+		chaos := annotator.find(t, harness.AnnotationTagChaos)
+		require.Equal(t, "Chaos: latency", chaos.Text)
+		require.Contains(t, chaos.Tags, "latency")
+		require.False(t, chaos.TimeEnd.IsZero())
+
+		cleanup := annotator.find(t, harness.AnnotationTagCleanup)
+		require.False(t, cleanup.Time.Before(run.TimeEnd))
+		require.False(t, cleanup.TimeEnd.IsZero())
+	})
+
+	t.Run("ErrorsIgnored", func(t *testing.T) {
+		t.Parallel()
+
+		annotator := &fakeAnnotator{err: xerrors.New("grafana unavailable")}
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithAnnotator(annotator),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				return nil
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, h.Results().TotalPass)
+	})
+}
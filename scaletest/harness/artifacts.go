@@ -0,0 +1,105 @@
+package harness
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// artifactLogsFile is the name of the file in a failed run's artifact
+// directory that its logs are written to.
+const artifactLogsFile = "logs.txt"
+
+// WithArtifactDir gives every test run its own artifact directory under the
+// given directory, at the path of the run's full ID. Runners can retrieve it
+// with ArtifactDir and drop any files that help with analyzing the run
+// afterwards, e.g. full logs, HAR captures or profiles. The logs of failed runs
+// are also written there. Directories of runs that produced no artifacts are
+// removed.
+func WithArtifactDir(dir string) Option {
+	return func(h *TestHarness) {
+		h.artifactDir = dir
+	}
+}
+
+type artifactDirKey struct{}
+
+// ArtifactDir returns the artifact directory of the test run that the context
+// belongs to. The returned bool is false if the harness wasn't configured with
+// an artifact directory.
+func ArtifactDir(ctx context.Context) (string, bool) {
+	dir, ok := ctx.Value(artifactDirKey{}).(string)
+	return dir, ok
+}
+
+// prepareArtifactDir creates the artifact directory of the run, if any, and
+// returns a context carrying it.
+func (r *TestRun) prepareArtifactDir(ctx context.Context) context.Context {
+	if r.artifactDir == "" {
+		return ctx
+	}
+
+	err := os.MkdirAll(r.artifactDir, 0o755)
+	if err != nil {
+		_, _ = fmt.Fprintf(r.logs, "Failed to create artifact directory %q: %v\n\n", r.artifactDir, err)
+		r.artifactDir = ""
+		return ctx
+	}
+	return context.WithValue(ctx, artifactDirKey{}, r.artifactDir)
+}
+
+// finalizeArtifactDir writes the logs of a failed run to its artifact
+// directory and removes the directory if it's empty.
+func (r *TestRun) finalizeArtifactDir(runErr error) {
+	if r.artifactDir == "" {
+		return
+	}
+
+	if runErr != nil {
+		err := os.WriteFile(filepath.Join(r.artifactDir, artifactLogsFile), []byte(r.logs.String()), 0o600)
+		if err != nil {
+			_, _ = fmt.Fprintf(r.logs, "\nFailed to write logs to artifact directory: %v\n", err)
+		}
+	}
+
+	// Remove only succeeds if the directory is empty.
+	if os.Remove(r.artifactDir) == nil {
+		r.artifactDir = ""
+	}
+}
+
+// WriteArtifactArchive writes a gzipped tarball of the artifact directory,
+// containing the artifacts of all runs keyed by their full IDs, to the given
+// writer. Panics if the harness has not finished.
+func (h *TestHarness) WriteArtifactArchive(w io.Writer) error {
+	select {
+	case <-h.done:
+	default:
+		panic("harness has not finished")
+	}
+	if h.artifactDir == "" {
+		return xerrors.New("harness has no artifact directory")
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	err := tw.AddFS(os.DirFS(h.artifactDir))
+	if err != nil {
+		return xerrors.Errorf("add artifacts to archive: %w", err)
+	}
+	err = tw.Close()
+	if err != nil {
+		return xerrors.Errorf("close tar writer: %w", err)
+	}
+	err = gw.Close()
+	if err != nil {
+		return xerrors.Errorf("close gzip writer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package harness_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Artifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	h := harness.NewTestHarness(
+		harness.LinearExecutionStrategy{},
+		harness.LinearExecutionStrategy{},
+		harness.WithArtifactDir(dir),
+	)
+
+	profile := h.AddRun("test", "profile", testFns{
+		RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+			dir, ok := harness.ArtifactDir(ctx)
+			if !ok {
+				return xerrors.New("no artifact dir")
+			}
+			return os.WriteFile(filepath.Join(dir, "cpu.pprof"), []byte("profile"), 0o600)
+		},
+	})
+	fail := h.AddRun("test", "fail", testFns{
+		RunFn: func(_ context.Context, _ string, logs io.Writer) error {
+			_, _ = io.WriteString(logs, "something went wrong\n")
+			return xerrors.New("fail")
+		},
+	})
+	empty := h.AddRun("test", "empty", fakeTestFns(nil, nil))
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+
+	profileDir := filepath.Join(dir, "test", "profile")
+	require.Equal(t, profileDir, profile.Result().ArtifactDir)
+	require.FileExists(t, filepath.Join(profileDir, "cpu.pprof"))
+
+	failDir := filepath.Join(dir, "test", "fail")
+	require.Equal(t, failDir, fail.Result().ArtifactDir)
+	logs, err := os.ReadFile(filepath.Join(failDir, "logs.txt"))
+	require.NoError(t, err)
+	require.Contains(t, string(logs), "something went wrong")
+
+	require.Empty(t, empty.Result().ArtifactDir)
+	require.NoDirExists(t, filepath.Join(dir, "test", "empty"))
+
+	var buf bytes.Buffer
+	err = h.WriteArtifactArchive(&buf)
+	require.NoError(t, err)
+
+	gr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if xerrors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(b)
+	}
+	require.Len(t, files, 2)
+	require.Equal(t, "profile", files["test/profile/cpu.pprof"])
+	require.Contains(t, files["test/fail/logs.txt"], "something went wrong")
+}
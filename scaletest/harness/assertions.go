@@ -0,0 +1,106 @@
+package harness
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+)
+
+// Assertions are service level objectives that the results of a test must
+// satisfy. Zero values disable the corresponding assertion.
+type Assertions struct {
+	// MaxErrorRate is the maximum fraction of runs (between 0 and 1) that may
+	// fail.
+	MaxErrorRate float64 `json:"max_error_rate"`
+	// MaxP95Duration is the maximum 95th percentile run duration of each
+	// test.
+	MaxP95Duration time.Duration `json:"max_p95_duration"`
+	// MinThroughput is the minimum number of successful runs per second over
+	// the whole test.
+	MinThroughput float64 `json:"min_throughput"`
+}
+
+// Validate returns an error if the assertions are invalid.
+func (a Assertions) Validate() error {
+	if a.MaxErrorRate < 0 || a.MaxErrorRate > 1 {
+		return xerrors.Errorf("max error rate must be between 0 and 1, got %v", a.MaxErrorRate)
+	}
+	if a.MaxP95Duration < 0 {
+		return xerrors.Errorf("max p95 duration must not be negative, got %s", a.MaxP95Duration)
+	}
+	if a.MinThroughput < 0 {
+		return xerrors.Errorf("min throughput must not be negative, got %v", a.MinThroughput)
+	}
+	return nil
+}
+
+// AssertionViolation describes an assertion that was not satisfied by the
+// results of a test.
+type AssertionViolation struct {
+	Assertion string `json:"assertion"`
+	Limit     string `json:"limit"`
+	Actual    string `json:"actual"`
+}
+
+func (v AssertionViolation) String() string {
+	return fmt.Sprintf("%s: limit %s, actual %s", v.Assertion, v.Limit, v.Actual)
+}
+
+// Evaluate checks the given results against the assertions and returns all
+// violations, if any.
+func (a Assertions) Evaluate(r Results) []AssertionViolation {
+	var violations []AssertionViolation
+
+	if a.MaxErrorRate > 0 && r.TotalRuns > 0 {
+		errorRate := float64(r.TotalFail) / float64(r.TotalRuns)
+		if errorRate > a.MaxErrorRate {
+			violations = append(violations, AssertionViolation{
+				Assertion: "max error rate",
+				Limit:     formatPercent(a.MaxErrorRate),
+				Actual:    fmt.Sprintf("%s (%d/%d runs failed)", formatPercent(errorRate), r.TotalFail, r.TotalRuns),
+			})
+		}
+	}
+
+	if a.MaxP95Duration > 0 {
+		testNames := maps.Keys(r.Latency)
+		slices.Sort(testNames)
+		for _, testName := range testNames {
+			p95 := time.Duration(r.Latency[testName].P95)
+			if p95 > a.MaxP95Duration {
+				violations = append(violations, AssertionViolation{
+					Assertion: fmt.Sprintf("max p95 duration (%s)", testName),
+					Limit:     a.MaxP95Duration.String(),
+					Actual:    p95.String(),
+				})
+			}
+		}
+	}
+
+	if a.MinThroughput > 0 {
+		var throughput float64
+		if elapsed := time.Duration(r.Elapsed); elapsed > 0 {
+			throughput = float64(r.TotalPass) / elapsed.Seconds()
+		}
+		if throughput < a.MinThroughput {
+			violations = append(violations, AssertionViolation{
+				Assertion: "min throughput",
+				Limit:     formatThroughput(a.MinThroughput),
+				Actual:    formatThroughput(throughput),
+			})
+		}
+	}
+
+	return violations
+}
+
+func formatPercent(f float64) string {
+	return fmt.Sprintf("%.2f%%", f*100)
+}
+
+func formatThroughput(f float64) string {
+	return fmt.Sprintf("%.3f runs/s", f)
+}
@@ -0,0 +1,67 @@
+package harness_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Assertions(t *testing.T) {
+	t.Parallel()
+
+	results := harness.Results{
+		TotalRuns: 10,
+		TotalPass: 8,
+		TotalFail: 2,
+		Elapsed:   httpapi.Duration(4 * time.Second),
+		Latency: map[string]harness.LatencySummary{
+			"fast": {P95: httpapi.Duration(100 * time.Millisecond)},
+			"slow": {P95: httpapi.Duration(3 * time.Second)},
+		},
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, harness.Assertions{}.Validate())
+		require.NoError(t, harness.Assertions{MaxErrorRate: 1, MaxP95Duration: time.Second, MinThroughput: 1}.Validate())
+		require.Error(t, harness.Assertions{MaxErrorRate: 1.5}.Validate())
+		require.Error(t, harness.Assertions{MaxP95Duration: -time.Second}.Validate())
+		require.Error(t, harness.Assertions{MinThroughput: -1}.Validate())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, harness.Assertions{}.Evaluate(results))
+	})
+
+	t.Run("Satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, harness.Assertions{
+			MaxErrorRate:   0.2,
+			MaxP95Duration: 5 * time.Second,
+			MinThroughput:  2,
+		}.Evaluate(results))
+	})
+
+	t.Run("Violated", func(t *testing.T) {
+		t.Parallel()
+
+		violations := harness.Assertions{
+			MaxErrorRate:   0.1,
+			MaxP95Duration: time.Second,
+			MinThroughput:  2.5,
+		}.Evaluate(results)
+		require.Equal(t, []harness.AssertionViolation{
+			{Assertion: "max error rate", Limit: "10.00%", Actual: "20.00% (2/10 runs failed)"},
+			{Assertion: "max p95 duration (slow)", Limit: "1s", Actual: "3s"},
+			{Assertion: "min throughput", Limit: "2.500 runs/s", Actual: "2.000 runs/s"},
+		}, violations)
+	})
+}
@@ -0,0 +1,98 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/exp/maps"
+)
+
+// Well-known channels that runners implementing ByteCollectable can report
+// the bytes they transferred over, so that the results of different runners
+// can be compared.
+const (
+	// ByteChannelSSH is the stdin (written) and stdout (read) of SSH
+	// sessions.
+	ByteChannelSSH = "ssh"
+	// ByteChannelPTY is the input (written) and output (read) of reconnecting
+	// PTYs.
+	ByteChannelPTY = "pty"
+	// ByteChannelAppProxy is traffic to and from workspace apps through the
+	// app proxy.
+	ByteChannelAppProxy = "app_proxy"
+	// ByteChannelPortForward is traffic to and from forwarded workspace
+	// ports.
+	ByteChannelPortForward = "port_forward"
+)
+
+// ByteCount is the number of bytes a runner transferred over a channel in
+// each direction.
+type ByteCount struct {
+	// Read is the number of bytes the runner received, e.g. the stdout of an
+	// SSH session.
+	Read int64 `json:"read"`
+	// Written is the number of bytes the runner sent, e.g. the stdin of an
+	// SSH session.
+	Written int64 `json:"written"`
+}
+
+// ByteCollectable is an optional extension to Collectable for runners that
+// transfer data over one or more channels. The bytes of every channel are
+// summarized across runs in the results, giving a bandwidth breakdown by
+// protocol and direction.
+type ByteCollectable interface {
+	Collectable
+	// GetBytes returns the bytes transferred by the run keyed by channel, e.g.
+	// ByteChannelSSH.
+	GetBytes() map[string]ByteCount
+}
+
+// BandwidthSummary summarizes the bytes transferred over a channel across
+// runs.
+type BandwidthSummary struct {
+	// Runs is the number of runs that reported bytes for the channel.
+	Runs    int   `json:"runs"`
+	Read    int64 `json:"read"`
+	Written int64 `json:"written"`
+	// ReadPerSecond and WrittenPerSecond are the average bandwidth over the
+	// elapsed time of the test.
+	ReadPerSecond    float64 `json:"read_per_second"`
+	WrittenPerSecond float64 `json:"written_per_second"`
+}
+
+// totalBytes returns the bytes transferred by the run across all channels.
+func (r RunResult) totalBytes() (ByteCount, bool) {
+	var total ByteCount
+	for _, count := range r.Bytes {
+		total.Read += count.Read
+		total.Written += count.Written
+	}
+	return total, len(r.Bytes) > 0
+}
+
+// byteChannels returns the sorted names of all channels reported by any of
+// the runs.
+func (r *Results) byteChannels() []string {
+	channels := map[string]struct{}{}
+	for _, run := range r.Runs {
+		for channel := range run.Bytes {
+			channels[channel] = struct{}{}
+		}
+	}
+	keys := maps.Keys(channels)
+	slices.Sort(keys)
+	return keys
+}
+
+func (r *Results) printBandwidth(w io.Writer) {
+	channels := maps.Keys(r.Bandwidth)
+	slices.Sort(channels)
+	for _, channel := range channels {
+		b := r.Bandwidth[channel]
+		_, _ = fmt.Fprintf(w, "\n\tBandwidth (%s, %d runs):\n", channel, b.Runs)
+		_, _ = fmt.Fprintf(w, "\t\tRead:    %s (%s/s)\n", humanize.IBytes(uint64(max(b.Read, 0))), humanize.IBytes(uint64(max(b.ReadPerSecond, 0))))
+		_, _ = fmt.Fprintf(w, "\t\tWritten: %s (%s/s)\n", humanize.IBytes(uint64(max(b.Written, 0))), humanize.IBytes(uint64(max(b.WrittenPerSecond, 0))))
+	}
+}
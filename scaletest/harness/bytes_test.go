@@ -0,0 +1,95 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// byteTestFns implements ByteCollectable.
+type byteTestFns struct {
+	testFns
+	bytes map[string]harness.ByteCount
+}
+
+var _ harness.ByteCollectable = byteTestFns{}
+
+// GetMetrics implements Collectable.
+func (byteTestFns) GetMetrics() map[string]any {
+	return nil
+}
+
+// GetBytes implements ByteCollectable.
+func (fns byteTestFns) GetBytes() map[string]harness.ByteCount {
+	return fns.bytes
+}
+
+func Test_ByteCollectable(t *testing.T) {
+	t.Parallel()
+
+	h := harness.NewTestHarness(
+		harness.LinearExecutionStrategy{},
+		harness.LinearExecutionStrategy{},
+		harness.WithWarmup(harness.Warmup{Runs: 1}),
+	)
+	// The warmup run is excluded from the summary.
+	h.AddRun("test", "warmup", byteTestFns{
+		testFns: fakeTestFns(nil, nil),
+		bytes:   map[string]harness.ByteCount{harness.ByteChannelSSH: {Read: 1000, Written: 1000}},
+	})
+	h.AddRun("test", "ssh", byteTestFns{
+		testFns: fakeTestFns(nil, nil),
+		bytes:   map[string]harness.ByteCount{harness.ByteChannelSSH: {Read: 100, Written: 10}},
+	})
+	h.AddRun("test", "both", byteTestFns{
+		testFns: fakeTestFns(nil, nil),
+		bytes: map[string]harness.ByteCount{
+			harness.ByteChannelSSH: {Read: 200, Written: 20},
+			harness.ByteChannelPTY: {Read: 2048, Written: 1},
+		},
+	})
+	h.AddRun("test", "none", fakeTestFns(nil, nil))
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+
+	res := h.Results()
+	require.Len(t, res.Bandwidth, 2)
+	ssh := res.Bandwidth[harness.ByteChannelSSH]
+	require.Equal(t, 2, ssh.Runs)
+	require.EqualValues(t, 300, ssh.Read)
+	require.EqualValues(t, 30, ssh.Written)
+	require.Positive(t, ssh.ReadPerSecond)
+	pty := res.Bandwidth[harness.ByteChannelPTY]
+	require.Equal(t, 1, pty.Runs)
+	require.EqualValues(t, 2048, pty.Read)
+	require.Equal(t, map[string]harness.ByteCount{harness.ByteChannelSSH: {Read: 100, Written: 10}}, res.Runs["test/ssh"].Bytes)
+
+	var buf bytes.Buffer
+	res.PrintText(&buf)
+	require.Contains(t, buf.String(), "Bandwidth (pty, 1 runs):\n\t\tRead:    2.0 KiB")
+	require.Contains(t, buf.String(), "Bandwidth (ssh, 2 runs):\n\t\tRead:    300 B")
+
+	buf.Reset()
+	err = res.WriteCSV(&buf)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	require.True(t, strings.HasSuffix(lines[0], ",bytes_read,bytes_written,bytes_read_pty,bytes_written_pty,bytes_read_ssh,bytes_written_ssh"), lines[0])
+	for _, line := range lines[1:] {
+		switch {
+		case strings.HasPrefix(line, "test/both,"):
+			// The totals are summed across channels.
+			require.True(t, strings.HasSuffix(line, ",2248,21,2048,1,200,20"), line)
+		case strings.HasPrefix(line, "test/ssh,"):
+			require.True(t, strings.HasSuffix(line, ",100,10,,,100,10"), line)
+		case strings.HasPrefix(line, "test/none,"):
+			require.True(t, strings.HasSuffix(line, ",,,,,,"), line)
+		}
+	}
+}
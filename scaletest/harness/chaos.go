@@ -0,0 +1,205 @@
+package harness
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+)
+
+// chaosRevertTimeout is how long reverting a fault may take once the run phase
+// of the test has finished.
+const chaosRevertTimeout = time.Minute
+
+// ChaosInjector injects a fault into the system under test, e.g. killing a
+// percentage of workspace agents, adding network latency or restarting a
+// provisioner. Injectors can obtain a reproducible random number generator
+// for the hook they belong to with Rand.
+type ChaosInjector interface {
+	Inject(ctx context.Context) error
+}
+
+// ChaosReverter is an optional extension to ChaosInjector for faults that
+// persist until they are reverted, e.g. added network latency.
+type ChaosReverter interface {
+	ChaosInjector
+	Revert(ctx context.Context) error
+}
+
+// ChaosHook schedules a ChaosInjector during the run phase of a test.
+type ChaosHook struct {
+	// Name identifies the hook in the results. It must be unique.
+	Name     string
+	Injector ChaosInjector
+	// Start is the time from the start of the run phase until the fault is
+	// first injected. If the harness has a warmup duration, Start is counted
+	// from the end of the warmup phase instead so that warmup runs are never
+	// affected.
+	Start time.Duration
+	// Every is the interval at which the fault is injected again. Zero
+	// injects the fault only once.
+	Every time.Duration
+	// Duration is how long the fault lasts before it's reverted, if the
+	// injector implements ChaosReverter. Zero keeps the fault until the run
+	// phase finishes.
+	Duration time.Duration
+}
+
+// ChaosEvent records a single injection of a fault.
+type ChaosEvent struct {
+	Hook       string    `json:"hook"`
+	InjectedAt time.Time `json:"injected_at"`
+	// RevertedAt is when the fault was reverted. It is the zero time if the
+	// injector doesn't implement ChaosReverter or reverting the fault failed.
+	RevertedAt time.Time `json:"reverted_at,omitzero"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// active returns whether the fault was in effect at any point in the given
+// time range. Faults that are never reverted are considered to be in effect
+// only at the time they were injected.
+func (e ChaosEvent) active(start, end time.Time) bool {
+	if e.Error != "" {
+		return false
+	}
+	revertedAt := e.RevertedAt
+	if revertedAt.IsZero() {
+		revertedAt = e.InjectedAt
+	}
+	return !e.InjectedAt.After(end) && !revertedAt.Before(start)
+}
+
+// WithChaos injects faults into the system under test on the schedule of the
+// given hooks while the test runs, so that the resilience of the system can be
+// measured in the same test as its performance. No faults are injected during
+// cleanup, and faults that are still in effect when the run phase finishes are
+// reverted before Run returns. Every injection is recorded in the results, and
+// every run records the hooks whose faults were in effect while it ran.
+func WithChaos(hooks ...ChaosHook) Option {
+	return func(h *TestHarness) {
+		h.chaosHooks = append(h.chaosHooks, hooks...)
+	}
+}
+
+// runChaos runs all chaos hooks until the context is canceled and then
+// reverts any faults that are still in effect.
+func (h *TestHarness) runChaos(ctx context.Context, start time.Time) {
+	var wg sync.WaitGroup
+	for _, hook := range h.chaosHooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.runChaosHook(ctx, start, hook)
+		}()
+	}
+	wg.Wait()
+}
+
+func (h *TestHarness) runChaosHook(ctx context.Context, start time.Time, hook ChaosHook) {
+	//nolint:gosec // gosec thinks we're using an insecure RNG, but we're not.
+	ctx = context.WithValue(ctx, randKey{}, rand.New(rand.NewSource(runSeed(h.seed, "chaos/"+hook.Name))))
+	reverter, canRevert := hook.Injector.(ChaosReverter)
+
+	next := start.Add(h.warmup.Duration + hook.Start)
+	for {
+		if !sleepUntil(ctx, next) {
+			return
+		}
+
+		event := ChaosEvent{
+			Hook:       hook.Name,
+			InjectedAt: time.Now(),
+		}
+		err := hook.Injector.Inject(ctx)
+		if err != nil {
+			event.Error = err.Error()
+		} else if canRevert {
+			// Keep the fault until the hook's duration elapses or the run
+			// phase finishes, whichever comes first.
+			if hook.Duration > 0 {
+				_ = sleepUntil(ctx, event.InjectedAt.Add(hook.Duration))
+			} else {
+				<-ctx.Done()
+			}
+			revertCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), chaosRevertTimeout)
+			err = reverter.Revert(revertCtx)
+			cancel()
+			if err != nil {
+				event.Error = err.Error()
+			} else {
+				event.RevertedAt = time.Now()
+			}
+		}
+		h.recordChaosEvent(event)
+		h.annotateChaosEvent(ctx, event)
+
+		if hook.Every <= 0 {
+			return
+		}
+		next = next.Add(hook.Every)
+	}
+}
+
+func (h *TestHarness) recordChaosEvent(event ChaosEvent) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.chaosEvents = append(h.chaosEvents, event)
+}
+
+// annotateChaosEvent marks the time the fault of the event was in effect.
+func (h *TestHarness) annotateChaosEvent(ctx context.Context, event ChaosEvent) {
+	text := "Chaos: " + event.Hook
+	if event.Error != "" {
+		text += " (failed: " + event.Error + ")"
+	}
+	timeEnd := event.RevertedAt
+	if timeEnd.IsZero() {
+		// Mark faults that aren't reverted as a single point in time.
+		timeEnd = event.InjectedAt
+	}
+	h.annotate(ctx, Annotation{
+		Time:    event.InjectedAt,
+		TimeEnd: timeEnd,
+		Text:    text,
+		Tags:    []string{AnnotationTagChaos, event.Hook},
+	})
+}
+
+// chaosResults returns the recorded chaos events sorted by the time they were
+// injected.
+func (h *TestHarness) chaosResults() []ChaosEvent {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	events := slices.Clone(h.chaosEvents)
+	slices.SortFunc(events, func(a, b ChaosEvent) int {
+		return a.InjectedAt.Compare(b.InjectedAt)
+	})
+	return events
+}
+
+// activeChaosHooks returns the sorted names of the hooks whose faults were in
+// effect at any point in the given time range.
+func activeChaosHooks(events []ChaosEvent, start, end time.Time) []string {
+	var hooks []string
+	for _, event := range events {
+		if event.active(start, end) && !slices.Contains(hooks, event.Hook) {
+			hooks = append(hooks, event.Hook)
+		}
+	}
+	slices.Sort(hooks)
+	return hooks
+}
+
+// sleepUntil waits until the given time and returns false if the context is
+// canceled first.
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
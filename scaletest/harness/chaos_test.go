@@ -0,0 +1,197 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type chaosInjectorFn func(ctx context.Context) error
+
+func (fn chaosInjectorFn) Inject(ctx context.Context) error {
+	return fn(ctx)
+}
+
+type chaosReverter struct {
+	injectFn func(ctx context.Context) error
+	revertFn func(ctx context.Context) error
+}
+
+func (r chaosReverter) Inject(ctx context.Context) error {
+	return r.injectFn(ctx)
+}
+
+func (r chaosReverter) Revert(ctx context.Context) error {
+	return r.revertFn(ctx)
+}
+
+func Test_Chaos(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RevertedAfterRun", func(t *testing.T) {
+		t.Parallel()
+
+		injected := make(chan struct{})
+		var reverted atomic.Bool
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithChaos(harness.ChaosHook{
+				Name: "latency",
+				Injector: chaosReverter{
+					injectFn: func(context.Context) error {
+						close(injected)
+						return nil
+					},
+					revertFn: func(ctx context.Context) error {
+						// The revert context must still be usable after the
+						// run phase finished.
+						assert.NoError(t, ctx.Err())
+						reverted.Store(true)
+						return nil
+					},
+				},
+			}),
+		)
+		h.AddRun("test", "affected", testFns{
+			RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-injected:
+				}
+				if reverted.Load() {
+					return xerrors.New("fault reverted while the run was in progress")
+				}
+				return nil
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+		require.True(t, reverted.Load())
+
+		res := h.Results()
+		require.Len(t, res.Chaos, 1)
+		require.Equal(t, "latency", res.Chaos[0].Hook)
+		require.Empty(t, res.Chaos[0].Error)
+		require.False(t, res.Chaos[0].RevertedAt.IsZero())
+		require.NoError(t, res.Runs["test/affected"].Error)
+		require.Equal(t, []string{"latency"}, res.Runs["test/affected"].ChaosHooks)
+		require.Equal(t, 1, res.TotalChaosAffected)
+	})
+
+	t.Run("Repeated", func(t *testing.T) {
+		t.Parallel()
+
+		var injections atomic.Int64
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithChaos(harness.ChaosHook{
+				Name: "kill-agents",
+				Injector: chaosInjectorFn(func(context.Context) error {
+					injections.Add(1)
+					return nil
+				}),
+				Every: time.Millisecond,
+			}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				ticker := time.NewTicker(testutil.IntervalFast)
+				defer ticker.Stop()
+				for injections.Load() < 3 {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-ticker.C:
+					}
+				}
+				return nil
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+
+		// No more faults are injected once Run returns.
+		after := injections.Load()
+		res := h.Results()
+		require.Len(t, res.Chaos, int(after))
+		time.Sleep(10 * time.Millisecond)
+		require.Equal(t, after, injections.Load())
+		for i := 1; i < len(res.Chaos); i++ {
+			require.False(t, res.Chaos[i].InjectedAt.Before(res.Chaos[i-1].InjectedAt))
+		}
+	})
+
+	t.Run("InjectError", func(t *testing.T) {
+		t.Parallel()
+
+		injected := make(chan struct{})
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithChaos(harness.ChaosHook{
+				Name: "restart-provisioner",
+				Injector: chaosInjectorFn(func(context.Context) error {
+					defer close(injected)
+					return xerrors.New("kubectl not found")
+				}),
+			}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-injected:
+				}
+				return nil
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+
+		res := h.Results()
+		require.Len(t, res.Chaos, 1)
+		require.Equal(t, "kubectl not found", res.Chaos[0].Error)
+		require.Empty(t, res.Runs["test/0"].ChaosHooks)
+		require.Zero(t, res.TotalChaosAffected)
+	})
+
+	t.Run("NotStartedBeforeSchedule", func(t *testing.T) {
+		t.Parallel()
+
+		var injections atomic.Int64
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithChaos(harness.ChaosHook{
+				Name: "late",
+				Injector: chaosInjectorFn(func(context.Context) error {
+					injections.Add(1)
+					return nil
+				}),
+				Start: time.Hour,
+			}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				return nil
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+		require.Zero(t, injections.Load())
+		require.Empty(t, h.Results().Chaos)
+	})
+}
@@ -0,0 +1,135 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CircuitBreaker aborts a test early when the deployment under test is clearly
+// unhealthy, instead of spending a long time starting runs that are bound to
+// fail.
+type CircuitBreaker struct {
+	// MaxErrorRate is the fraction of failed runs, between 0 and 1, among the
+	// most recently finished runs above which the test is aborted.
+	MaxErrorRate float64
+	// Window is the number of most recently finished runs the error rate is
+	// computed over. The breaker doesn't trip before Window runs have
+	// finished.
+	Window int
+}
+
+// Validate returns an error if the circuit breaker is invalid.
+func (c CircuitBreaker) Validate() error {
+	if c.MaxErrorRate < 0 || c.MaxErrorRate >= 1 {
+		return xerrors.Errorf("max error rate must be at least 0 and less than 1, got %v", c.MaxErrorRate)
+	}
+	if c.Window <= 0 {
+		return xerrors.Errorf("window must be positive, got %d", c.Window)
+	}
+	return nil
+}
+
+// CircuitBreakerTrip records why the circuit breaker aborted a test.
+type CircuitBreakerTrip struct {
+	TrippedAt time.Time `json:"tripped_at"`
+	// ErrorRate is the fraction of failed runs in the window when the breaker
+	// tripped.
+	ErrorRate    float64 `json:"error_rate"`
+	MaxErrorRate float64 `json:"max_error_rate"`
+	Window       int     `json:"window"`
+}
+
+func (t CircuitBreakerTrip) String() string {
+	return fmt.Sprintf("%.1f%% of the last %d runs failed, exceeding the maximum error rate of %.1f%%", t.ErrorRate*100, t.Window, t.MaxErrorRate*100)
+}
+
+// WithCircuitBreaker drains the harness once the error rate of the most
+// recently finished runs exceeds the maximum error rate of the breaker. Runs
+// in progress are allowed to finish, runs that haven't started yet are
+// skipped, and the trip is recorded in the results. Warmup runs are not
+// counted.
+func WithCircuitBreaker(c CircuitBreaker) Option {
+	return func(h *TestHarness) {
+		h.circuitBreaker = &circuitBreakerState{
+			CircuitBreaker: c,
+			failed:         make([]bool, c.Window),
+		}
+	}
+}
+
+// circuitBreakerState tracks the outcome of the most recently finished runs in
+// a ring buffer. It's protected by the harness mutex.
+type circuitBreakerState struct {
+	CircuitBreaker
+	failed   []bool
+	next     int
+	count    int
+	failures int
+	trip     *CircuitBreakerTrip
+}
+
+// record adds the outcome of a finished run and returns the trip if the
+// breaker tripped because of it.
+func (s *circuitBreakerState) record(failed bool) *CircuitBreakerTrip {
+	if s.trip != nil || len(s.failed) == 0 {
+		return nil
+	}
+	if s.count == len(s.failed) && s.failed[s.next] {
+		s.failures--
+	}
+	s.failed[s.next] = failed
+	if failed {
+		s.failures++
+	}
+	s.next = (s.next + 1) % len(s.failed)
+	s.count = min(s.count+1, len(s.failed))
+	if s.count < len(s.failed) {
+		return nil
+	}
+
+	errorRate := float64(s.failures) / float64(s.count)
+	if errorRate <= s.MaxErrorRate {
+		return nil
+	}
+	s.trip = &CircuitBreakerTrip{
+		TrippedAt:    time.Now(),
+		ErrorRate:    errorRate,
+		MaxErrorRate: s.MaxErrorRate,
+		Window:       s.count,
+	}
+	return s.trip
+}
+
+// recordCircuitBreaker records the outcome of a finished run with the circuit
+// breaker, if configured, and drains the harness if it trips.
+func (h *TestHarness) recordCircuitBreaker(ctx context.Context, err error) {
+	if h.circuitBreaker == nil {
+		return
+	}
+	h.mut.Lock()
+	trip := h.circuitBreaker.record(err != nil)
+	h.mut.Unlock()
+	if trip == nil {
+		return
+	}
+
+	h.drain("test was aborted by the circuit breaker")
+	h.annotate(ctx, Annotation{
+		Time: trip.TrippedAt,
+		Text: "Scaletest aborted: " + trip.String(),
+		Tags: []string{AnnotationTagCircuitBreaker},
+	})
+}
+
+// circuitBreakerTrip returns the trip of the circuit breaker, if it tripped.
+func (h *TestHarness) circuitBreakerTrip() *CircuitBreakerTrip {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	if h.circuitBreaker == nil {
+		return nil
+	}
+	return h.circuitBreaker.trip
+}
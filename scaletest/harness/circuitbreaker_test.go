@@ -0,0 +1,96 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	// newHarness returns a harness whose runs fail according to failed.
+	newHarness := func(t *testing.T, breaker harness.CircuitBreaker, failed ...bool) *harness.TestHarness {
+		t.Helper()
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithCircuitBreaker(breaker),
+		)
+		for i, fail := range failed {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					if fail {
+						return xerrors.New("workspace build failed")
+					}
+					return nil
+				},
+			})
+		}
+		return h
+	}
+
+	t.Run("Trips", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHarness(t, harness.CircuitBreaker{MaxErrorRate: 0.5, Window: 4},
+			false, true, true, false, true, false, false, false, false, false,
+		)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+
+		// The fifth run brings the error rate of the last four runs to 75%.
+		res := h.Results()
+		require.NotNil(t, res.CircuitBreaker)
+		require.Equal(t, 0.75, res.CircuitBreaker.ErrorRate)
+		require.Equal(t, 0.5, res.CircuitBreaker.MaxErrorRate)
+		require.Equal(t, 4, res.CircuitBreaker.Window)
+		require.Equal(t, 5, res.TotalRuns)
+		require.Equal(t, 5, res.TotalSkipped)
+		require.True(t, res.Runs["test/5"].Skipped)
+		require.Equal(t, "test was aborted by the circuit breaker", res.Runs["test/5"].SkipReason)
+	})
+
+	t.Run("BelowThreshold", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHarness(t, harness.CircuitBreaker{MaxErrorRate: 0.5, Window: 4},
+			true, true, false, false, true, false, true, false,
+		)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+
+		res := h.Results()
+		require.Nil(t, res.CircuitBreaker)
+		require.Equal(t, 8, res.TotalRuns)
+		require.Zero(t, res.TotalSkipped)
+	})
+
+	t.Run("WindowNotFull", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHarness(t, harness.CircuitBreaker{MaxErrorRate: 0.1, Window: 10},
+			true, true, true,
+		)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+		require.Nil(t, h.Results().CircuitBreaker)
+	})
+}
+
+func TestCircuitBreaker_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, harness.CircuitBreaker{MaxErrorRate: 0.5, Window: 100}.Validate())
+	require.Error(t, harness.CircuitBreaker{MaxErrorRate: 1, Window: 100}.Validate())
+	require.Error(t, harness.CircuitBreaker{MaxErrorRate: -0.1, Window: 100}.Validate())
+	require.Error(t, harness.CircuitBreaker{MaxErrorRate: 0.5}.Validate())
+}
@@ -0,0 +1,204 @@
+package harness
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+)
+
+// CleanupResource is a resource that would be deleted by cleaning up a test
+// run.
+type CleanupResource struct {
+	// Kind is the type of the resource, e.g. "workspace", "user" or "token".
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// CleanupPlanner is an optional extension to Cleanable that allows listing the
+// resources that Cleanup would delete without deleting them.
+type CleanupPlanner interface {
+	Cleanable
+	// CleanupPlan should return the resources that Cleanup would delete. It
+	// must not modify any resources.
+	CleanupPlan(ctx context.Context, id string) ([]CleanupResource, error)
+}
+
+// CleanupPlan lists the resources that cleaning up the harness would delete.
+type CleanupPlan struct {
+	// Runs contains the plan of every test run that needs cleaning up, keyed
+	// by full ID.
+	Runs map[string]RunCleanupPlan `json:"runs"`
+}
+
+// RunCleanupPlan lists the resources that cleaning up a single test run would
+// delete.
+type RunCleanupPlan struct {
+	FullID    string            `json:"full_id"`
+	Resources []CleanupResource `json:"resources"`
+	// Unknown is true if the runner doesn't implement CleanupPlanner, so the
+	// resources it would delete are unknown.
+	Unknown bool  `json:"unknown"`
+	Error   error `json:"-"`
+}
+
+// CleanupPlan returns the resources that Cleanup would delete, without
+// deleting anything. Runs that don't need cleaning up or whose resources are
+// retained are omitted. Panics if
+// the harness has not finished.
+func (h *TestHarness) CleanupPlan(ctx context.Context) CleanupPlan {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	if !h.started {
+		panic("harness has not started")
+	}
+	select {
+	case <-h.done:
+	default:
+		panic("harness has not finished")
+	}
+
+	plan := CleanupPlan{
+		Runs: map[string]RunCleanupPlan{},
+	}
+	for _, run := range h.runs {
+		if _, ok := run.runner.(Cleanable); !ok || run.retained {
+			continue
+		}
+		plan.Runs[run.FullID()] = run.cleanupPlan(ctx)
+	}
+	return plan
+}
+
+func (r *TestRun) cleanupPlan(ctx context.Context) (plan RunCleanupPlan) {
+	plan.FullID = r.FullID()
+	p, ok := r.runner.(CleanupPlanner)
+	if !ok {
+		plan.Unknown = true
+		return plan
+	}
+
+	defer func() {
+		e := recover()
+		if e != nil {
+			plan.Error = xerrors.Errorf("panic: %v", e)
+		}
+	}()
+	plan.Resources, plan.Error = p.CleanupPlan(ctx, r.id)
+	return plan
+}
+
+// PrintText prints the cleanup plan as human-readable text to the given writer.
+func (p CleanupPlan) PrintText(w io.Writer) {
+	var total int
+	keys := maps.Keys(p.Runs)
+	slices.Sort(keys)
+	for _, key := range keys {
+		run := p.Runs[key]
+		switch {
+		case run.Error != nil:
+			_, _ = fmt.Fprintf(w, "%s: failed to list resources: %v\n", run.FullID, run.Error)
+		case run.Unknown:
+			_, _ = fmt.Fprintf(w, "%s: unknown resources\n", run.FullID)
+		case len(run.Resources) == 0:
+			_, _ = fmt.Fprintf(w, "%s: nothing to delete\n", run.FullID)
+		default:
+			_, _ = fmt.Fprintf(w, "%s:\n", run.FullID)
+			for _, res := range run.Resources {
+				if res.Name != "" {
+					_, _ = fmt.Fprintf(w, "\t%s %s (%s)\n", res.Kind, res.Name, res.ID)
+				} else {
+					_, _ = fmt.Fprintf(w, "\t%s %s\n", res.Kind, res.ID)
+				}
+			}
+			total += len(run.Resources)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\nTotal: %d resources in %d runs would be deleted\n", total, len(p.Runs))
+}
+
+// retainPlanTimeout is how long listing the resources of the retained runs may
+// take.
+const retainPlanTimeout = time.Minute
+
+// WithRetainFailedRuns retains the resources of failed runs rather than
+// cleaning them up, so that they can be inspected after the test, e.g. to
+// debug a workspace that failed to build. The resources of runs that passed
+// are still cleaned up. The resources that were retained are listed in the
+// results as soon as the run phase is over, and have to be deleted manually.
+func WithRetainFailedRuns() Option {
+	return func(h *TestHarness) {
+		h.retainFailed = true
+	}
+}
+
+// RetainedRun lists the resources of a failed run that were retained rather
+// than cleaned up. See WithRetainFailedRuns.
+type RetainedRun struct {
+	FullID    string            `json:"full_id"`
+	Resources []CleanupResource `json:"resources,omitempty"`
+	// Unknown is true if the runner doesn't implement CleanupPlanner, so the
+	// retained resources are unknown.
+	Unknown bool   `json:"unknown,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// retainFailedRuns marks the failed runs that need cleaning up as retained,
+// and lists their resources.
+func (h *TestHarness) retainFailedRuns(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, retainPlanTimeout)
+	defer cancel()
+
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	for _, run := range h.runs {
+		if run.err == nil || !run.cleanable() {
+			continue
+		}
+		plan := run.cleanupPlan(ctx)
+		retained := RetainedRun{
+			FullID:    plan.FullID,
+			Resources: plan.Resources,
+			Unknown:   plan.Unknown,
+		}
+		if plan.Error != nil {
+			retained.Error = plan.Error.Error()
+		}
+		run.retained = true
+		h.retained = append(h.retained, retained)
+	}
+	slices.SortFunc(h.retained, func(a, b RetainedRun) int {
+		return cmp.Compare(a.FullID, b.FullID)
+	})
+}
+
+// printRetained prints the resources of the failed runs that were retained.
+func (r *Results) printRetained(w io.Writer) {
+	if len(r.Retained) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\n\tRetained resources of %d failed runs:\n", len(r.Retained))
+	for _, run := range r.Retained {
+		switch {
+		case run.Error != "":
+			_, _ = fmt.Fprintf(w, "\t\t%s: failed to list resources: %s\n", run.FullID, run.Error)
+		case run.Unknown:
+			_, _ = fmt.Fprintf(w, "\t\t%s: unknown resources\n", run.FullID)
+		default:
+			for _, res := range run.Resources {
+				if res.Name != "" {
+					_, _ = fmt.Fprintf(w, "\t\t%s: %s %s (%s)\n", run.FullID, res.Kind, res.Name, res.ID)
+				} else {
+					_, _ = fmt.Fprintf(w, "\t\t%s: %s %s\n", run.FullID, res.Kind, res.ID)
+				}
+			}
+		}
+	}
+}
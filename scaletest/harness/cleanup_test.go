@@ -0,0 +1,124 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// plannerTestFns implements CleanupPlanner.
+type plannerTestFns struct {
+	testFns
+	resources []harness.CleanupResource
+}
+
+var _ harness.CleanupPlanner = plannerTestFns{}
+
+// CleanupPlan implements CleanupPlanner.
+func (fns plannerTestFns) CleanupPlan(_ context.Context, _ string) ([]harness.CleanupResource, error) {
+	return fns.resources, nil
+}
+
+func Test_CleanupPlan(t *testing.T) {
+	t.Parallel()
+
+	var cleanupCalled atomic.Int64
+	fns := testFns{
+		RunFn: func(_ context.Context, _ string, _ io.Writer) error {
+			return nil
+		},
+		CleanupFn: func(_ context.Context, _ string, _ io.Writer) error {
+			cleanupCalled.Add(1)
+			return nil
+		},
+	}
+
+	h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+	h.AddRun("test", "workspace", plannerTestFns{
+		testFns: fns,
+		resources: []harness.CleanupResource{
+			{Kind: "workspace", ID: "ws-id"},
+			{Kind: "user", ID: "user-id", Name: "scaletest-user"},
+		},
+	})
+	h.AddRun("test", "empty", plannerTestFns{testFns: fns})
+	h.AddRun("test", "unknown", fns)
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+
+	plan := h.CleanupPlan(context.Background())
+	require.EqualValues(t, 0, cleanupCalled.Load())
+	require.Len(t, plan.Runs, 3)
+	require.Len(t, plan.Runs["test/workspace"].Resources, 2)
+	require.False(t, plan.Runs["test/workspace"].Unknown)
+	require.Empty(t, plan.Runs["test/empty"].Resources)
+	require.True(t, plan.Runs["test/unknown"].Unknown)
+
+	var buf bytes.Buffer
+	plan.PrintText(&buf)
+	out := buf.String()
+	require.Contains(t, out, "\tworkspace ws-id\n")
+	require.Contains(t, out, "\tuser scaletest-user (user-id)\n")
+	require.Contains(t, out, "test/empty: nothing to delete")
+	require.Contains(t, out, "test/unknown: unknown resources")
+	require.Contains(t, out, "Total: 2 resources in 3 runs would be deleted")
+}
+
+func Test_RetainFailedRuns(t *testing.T) {
+	t.Parallel()
+
+	var cleanedUp sync.Map
+	fns := func(err error) testFns {
+		return testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				return err
+			},
+			CleanupFn: func(_ context.Context, id string, _ io.Writer) error {
+				cleanedUp.Store(id, true)
+				return nil
+			},
+		}
+	}
+
+	h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+		harness.WithRetainFailedRuns(),
+	)
+	h.AddRun("test", "passed", fns(nil))
+	h.AddRun("test", "failed", plannerTestFns{
+		testFns:   fns(xerrors.New("build failed")),
+		resources: []harness.CleanupResource{{Kind: "workspace", ID: "ws-id", Name: "scaletest-ws"}},
+	})
+	h.AddRun("test", "unknown", fns(xerrors.New("agent not connected")))
+	require.NoError(t, h.Run(context.Background()))
+
+	res := h.Results()
+	require.Equal(t, []harness.RetainedRun{
+		{FullID: "test/failed", Resources: []harness.CleanupResource{{Kind: "workspace", ID: "ws-id", Name: "scaletest-ws"}}},
+		{FullID: "test/unknown", Unknown: true},
+	}, res.Retained)
+
+	// Retained runs are neither planned for nor cleaned up.
+	plan := h.CleanupPlan(context.Background())
+	require.Len(t, plan.Runs, 1)
+	require.Contains(t, plan.Runs, "test/passed")
+	require.NoError(t, h.Cleanup(context.Background()))
+	_, ok := cleanedUp.Load("passed")
+	require.True(t, ok)
+	_, ok = cleanedUp.Load("failed")
+	require.False(t, ok)
+	_, ok = cleanedUp.Load("unknown")
+	require.False(t, ok)
+
+	var out bytes.Buffer
+	res.PrintText(&out)
+	require.Contains(t, out.String(), "Retained resources of 2 failed runs:\n\t\ttest/failed: workspace scaletest-ws (ws-id)\n\t\ttest/unknown: unknown resources\n")
+}
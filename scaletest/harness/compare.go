@@ -0,0 +1,229 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+)
+
+// ReadResults decodes results previously written with WriteJSON. Results
+// written with a newer schema version than ResultsSchemaVersion are rejected.
+func ReadResults(r io.Reader) (Results, error) {
+	var res Results
+	err := json.NewDecoder(r).Decode(&res)
+	if err != nil {
+		return Results{}, xerrors.Errorf("decode JSON: %w", err)
+	}
+	if res.SchemaVersion > ResultsSchemaVersion {
+		return Results{}, xerrors.Errorf("unsupported results schema version %d, at most %d is supported", res.SchemaVersion, ResultsSchemaVersion)
+	}
+	return res, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for RunResult.
+func (r *RunResult) UnmarshalJSON(data []byte) error {
+	type alias RunResult
+	aux := struct {
+		*alias
+		Error string `json:"error"`
+	}{
+		alias: (*alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Error = nil
+	// MarshalJSON formats nil errors as "<nil>".
+	if aux.Error != "" && aux.Error != "<nil>" {
+		r.Error = xerrors.New(aux.Error)
+	}
+	return nil
+}
+
+// CompareThresholds are the maximum regressions of a candidate test compared
+// to a baseline before the comparison reports a regression. Zero values
+// disable the corresponding threshold.
+type CompareThresholds struct {
+	// MaxThroughputDecrease is the maximum decrease of the number of
+	// successful runs per second, in percent of the baseline.
+	MaxThroughputDecrease float64 `json:"max_throughput_decrease"`
+	// MaxErrorRateIncrease is the maximum increase of the fraction of failed
+	// runs, in percentage points.
+	MaxErrorRateIncrease float64 `json:"max_error_rate_increase"`
+	// MaxLatencyIncrease is the maximum increase of any latency percentile of
+	// any test, in percent of the baseline.
+	MaxLatencyIncrease float64 `json:"max_latency_increase"`
+}
+
+// Validate returns an error if the thresholds are invalid.
+func (t CompareThresholds) Validate() error {
+	if t.MaxThroughputDecrease < 0 {
+		return xerrors.Errorf("max throughput decrease must not be negative, got %v", t.MaxThroughputDecrease)
+	}
+	if t.MaxErrorRateIncrease < 0 || t.MaxErrorRateIncrease > 100 {
+		return xerrors.Errorf("max error rate increase must be between 0 and 100, got %v", t.MaxErrorRateIncrease)
+	}
+	if t.MaxLatencyIncrease < 0 {
+		return xerrors.Errorf("max latency increase must not be negative, got %v", t.MaxLatencyIncrease)
+	}
+	return nil
+}
+
+// ComparisonRow compares a single measurement of a baseline and a candidate
+// test.
+type ComparisonRow struct {
+	Metric    string  `json:"metric"`
+	Baseline  float64 `json:"baseline"`
+	Candidate float64 `json:"candidate"`
+	// DeltaPercent is the change from the baseline to the candidate in
+	// percent of the baseline. It is null if the baseline is zero.
+	DeltaPercent *float64 `json:"delta_percent"`
+	// Regression is set if the change exceeds its threshold.
+	Regression bool `json:"regression"`
+
+	// format formats values of the measurement for display.
+	format func(float64) string
+}
+
+// Comparison is the result of comparing a candidate test to a baseline.
+type Comparison struct {
+	Thresholds CompareThresholds `json:"thresholds"`
+	Rows       []ComparisonRow   `json:"rows"`
+}
+
+// Regressions returns the rows whose change exceeds its threshold.
+func (c Comparison) Regressions() []ComparisonRow {
+	var rows []ComparisonRow
+	for _, row := range c.Rows {
+		if row.Regression {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Compare compares the throughput, error rate and latency percentiles of a
+// candidate test to a baseline and flags changes that exceed the thresholds as
+// regressions. Latencies are only compared for tests present in both results.
+func Compare(baseline, candidate Results, thresholds CompareThresholds) Comparison {
+	c := Comparison{Thresholds: thresholds}
+
+	throughput := newComparisonRow("throughput (runs/s)", resultsThroughput(baseline), resultsThroughput(candidate), formatFloat)
+	if thresholds.MaxThroughputDecrease > 0 && throughput.DeltaPercent != nil {
+		throughput.Regression = -*throughput.DeltaPercent > thresholds.MaxThroughputDecrease
+	}
+	c.Rows = append(c.Rows, throughput)
+
+	errorRate := newComparisonRow("error rate", resultsErrorRate(baseline), resultsErrorRate(candidate), formatPercent)
+	if thresholds.MaxErrorRateIncrease > 0 {
+		errorRate.Regression = (errorRate.Candidate-errorRate.Baseline)*100 > thresholds.MaxErrorRateIncrease
+	}
+	c.Rows = append(c.Rows, errorRate)
+
+	testNames := maps.Keys(baseline.Latency)
+	slices.Sort(testNames)
+	for _, testName := range testNames {
+		candidateLatency, ok := candidate.Latency[testName]
+		if !ok {
+			continue
+		}
+		baselineLatency := baseline.Latency[testName]
+		for _, p := range []struct {
+			name                string
+			baseline, candidate time.Duration
+		}{
+			{"p50", time.Duration(baselineLatency.P50), time.Duration(candidateLatency.P50)},
+			{"p90", time.Duration(baselineLatency.P90), time.Duration(candidateLatency.P90)},
+			{"p95", time.Duration(baselineLatency.P95), time.Duration(candidateLatency.P95)},
+			{"p99", time.Duration(baselineLatency.P99), time.Duration(candidateLatency.P99)},
+			{"p99.9", time.Duration(baselineLatency.P999), time.Duration(candidateLatency.P999)},
+		} {
+			row := newComparisonRow(fmt.Sprintf("latency %s (%s)", p.name, testName), p.baseline.Seconds(), p.candidate.Seconds(), formatSeconds)
+			if thresholds.MaxLatencyIncrease > 0 && row.DeltaPercent != nil {
+				row.Regression = *row.DeltaPercent > thresholds.MaxLatencyIncrease
+			}
+			c.Rows = append(c.Rows, row)
+		}
+	}
+
+	return c
+}
+
+func newComparisonRow(metric string, baseline, candidate float64, format func(float64) string) ComparisonRow {
+	row := ComparisonRow{
+		Metric:    metric,
+		Baseline:  baseline,
+		Candidate: candidate,
+		format:    format,
+	}
+	if baseline != 0 {
+		delta := (candidate - baseline) / math.Abs(baseline) * 100
+		row.DeltaPercent = &delta
+	}
+	return row
+}
+
+func resultsThroughput(r Results) float64 {
+	elapsed := time.Duration(r.Elapsed)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(r.TotalPass) / elapsed.Seconds()
+}
+
+func resultsErrorRate(r Results) float64 {
+	if r.TotalRuns == 0 {
+		return 0
+	}
+	return float64(r.TotalFail) / float64(r.TotalRuns)
+}
+
+// PrintText prints the comparison as a table, followed by a summary of the
+// regressions, if any.
+func (c Comparison) PrintText(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "METRIC\tBASELINE\tCANDIDATE\tDELTA\t")
+	for _, row := range c.Rows {
+		format := row.format
+		if format == nil {
+			format = formatFloat
+		}
+		delta := "n/a"
+		if row.DeltaPercent != nil {
+			delta = fmt.Sprintf("%+.2f%%", *row.DeltaPercent)
+		}
+		var flag string
+		if row.Regression {
+			flag = "REGRESSION"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", row.Metric, format(row.Baseline), format(row.Candidate), delta, flag)
+	}
+	_ = tw.Flush()
+
+	regressions := c.Regressions()
+	if len(regressions) == 0 {
+		_, _ = fmt.Fprintln(w, "\nNo regressions.")
+		return
+	}
+	names := make([]string, 0, len(regressions))
+	for _, row := range regressions {
+		names = append(names, row.Metric)
+	}
+	_, _ = fmt.Fprintf(w, "\n%d regression(s): %s\n", len(regressions), strings.Join(names, ", "))
+}
+
+func formatFloat(f float64) string {
+	return fmt.Sprintf("%.3f", f)
+}
+
+func formatSeconds(f float64) string {
+	return time.Duration(f * float64(time.Second)).String()
+}
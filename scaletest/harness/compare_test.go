@@ -0,0 +1,142 @@
+package harness_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_ReadResults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		res := harness.Results{
+			SchemaVersion: harness.ResultsSchemaVersion,
+			TotalRuns:     2,
+			TotalPass:     1,
+			TotalFail:     1,
+			Elapsed:       httpapi.Duration(time.Second),
+			Runs: map[string]harness.RunResult{
+				"test/0": {FullID: "test/0", TestName: "test", ID: "0", Duration: httpapi.Duration(time.Millisecond)},
+				"test/1": {FullID: "test/1", TestName: "test", ID: "1", Error: xerrors.New("boom")},
+			},
+			Latency: map[string]harness.LatencySummary{
+				"test": {Count: 1, P95: httpapi.Duration(time.Millisecond)},
+			},
+		}
+		var buf bytes.Buffer
+		require.NoError(t, res.WriteJSON(&buf))
+
+		got, err := harness.ReadResults(&buf)
+		require.NoError(t, err)
+		require.Equal(t, res.TotalFail, got.TotalFail)
+		require.Equal(t, res.Elapsed, got.Elapsed)
+		require.Equal(t, res.Latency, got.Latency)
+		require.NoError(t, got.Runs["test/0"].Error)
+		require.ErrorContains(t, got.Runs["test/1"].Error, "boom")
+		require.Equal(t, res.Runs["test/0"].Duration, got.Runs["test/0"].Duration)
+	})
+
+	t.Run("NewerSchemaVersion", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := harness.ReadResults(strings.NewReader(`{"schema_version": 999}`))
+		require.ErrorContains(t, err, "unsupported results schema version 999")
+	})
+}
+
+func Test_Compare(t *testing.T) {
+	t.Parallel()
+
+	results := func(pass, fail int, p95 time.Duration) harness.Results {
+		return harness.Results{
+			TotalRuns: pass + fail,
+			TotalPass: pass,
+			TotalFail: fail,
+			Elapsed:   httpapi.Duration(10 * time.Second),
+			Latency: map[string]harness.LatencySummary{
+				"test": {
+					P50:  httpapi.Duration(p95 / 2),
+					P90:  httpapi.Duration(p95),
+					P95:  httpapi.Duration(p95),
+					P99:  httpapi.Duration(p95),
+					P999: httpapi.Duration(p95),
+				},
+			},
+		}
+	}
+	thresholds := harness.CompareThresholds{
+		MaxThroughputDecrease: 10,
+		MaxErrorRateIncrease:  1,
+		MaxLatencyIncrease:    20,
+	}
+
+	t.Run("NoRegression", func(t *testing.T) {
+		t.Parallel()
+
+		c := harness.Compare(results(100, 0, time.Second), results(95, 0, 1100*time.Millisecond), thresholds)
+		require.Empty(t, c.Regressions())
+		// Throughput, error rate and five latency percentiles.
+		require.Len(t, c.Rows, 7)
+		require.NotNil(t, c.Rows[0].DeltaPercent)
+		require.InDelta(t, -5, *c.Rows[0].DeltaPercent, 0.001)
+		// The baseline error rate is zero, so there is no relative delta.
+		require.Nil(t, c.Rows[1].DeltaPercent)
+
+		var out bytes.Buffer
+		c.PrintText(&out)
+		require.Contains(t, out.String(), "No regressions.")
+	})
+
+	t.Run("Regressions", func(t *testing.T) {
+		t.Parallel()
+
+		c := harness.Compare(results(100, 0, time.Second), results(80, 5, 2*time.Second), thresholds)
+		var metrics []string
+		for _, row := range c.Regressions() {
+			metrics = append(metrics, row.Metric)
+		}
+		require.Contains(t, metrics, "throughput (runs/s)")
+		require.Contains(t, metrics, "error rate")
+		require.Contains(t, metrics, "latency p95 (test)")
+
+		var out bytes.Buffer
+		c.PrintText(&out)
+		require.Contains(t, out.String(), "REGRESSION")
+		require.Contains(t, out.String(), "regression(s)")
+	})
+
+	t.Run("DisabledThresholds", func(t *testing.T) {
+		t.Parallel()
+
+		c := harness.Compare(results(100, 0, time.Second), results(10, 50, 10*time.Second), harness.CompareThresholds{})
+		require.Empty(t, c.Regressions())
+	})
+
+	t.Run("MissingTest", func(t *testing.T) {
+		t.Parallel()
+
+		candidate := results(100, 0, time.Second)
+		candidate.Latency = nil
+		c := harness.Compare(results(100, 0, time.Second), candidate, thresholds)
+		require.Len(t, c.Rows, 2)
+	})
+}
+
+func Test_CompareThresholds_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, harness.CompareThresholds{MaxThroughputDecrease: 10, MaxErrorRateIncrease: 1, MaxLatencyIncrease: 20}.Validate())
+	require.Error(t, harness.CompareThresholds{MaxThroughputDecrease: -1}.Validate())
+	require.Error(t, harness.CompareThresholds{MaxErrorRateIncrease: 101}.Validate())
+	require.Error(t, harness.CompareThresholds{MaxLatencyIncrease: -1}.Validate())
+}
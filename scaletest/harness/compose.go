@@ -0,0 +1,82 @@
+package harness
+
+import "time"
+
+// StrategyWrapper adds a behavior, e.g. a rate limit, to an ExecutionStrategy
+// by wrapping it. Wrappers are composed around a base strategy with Compose.
+type StrategyWrapper func(inner ExecutionStrategy) ExecutionStrategy
+
+// Compose wraps the base strategy with the given wrappers and returns the
+// resulting strategy. The first wrapper is the outermost one, so the per-run
+// behavior of later wrappers happens first. For example:
+//
+//	Compose(ParallelExecutionStrategy{Limit: 10}, WithTimeout(time.Minute, 0), WithRateLimit(time.Second))
+//
+// waits for the rate limit before starting the timeout of each run. Nil
+// wrappers are ignored.
+func Compose(base ExecutionStrategy, wrappers ...StrategyWrapper) ExecutionStrategy {
+	strategy := base
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		if wrappers[i] == nil {
+			continue
+		}
+		strategy = wrappers[i](strategy)
+	}
+	return strategy
+}
+
+// WithRateLimit returns a StrategyWrapper that starts test runs at least
+// interval apart. See RateLimitExecutionStrategyWrapper. A non-positive
+// interval leaves the strategy unchanged.
+func WithRateLimit(interval time.Duration) StrategyWrapper {
+	return func(inner ExecutionStrategy) ExecutionStrategy {
+		if interval <= 0 {
+			return inner
+		}
+		return RateLimitExecutionStrategyWrapper{Interval: interval, Inner: inner}
+	}
+}
+
+// WithTimeout returns a StrategyWrapper that applies a timeout to each test
+// run. See TimeoutExecutionStrategyWrapper. A non-positive timeout leaves the
+// strategy unchanged.
+func WithTimeout(timeout, abandonAfter time.Duration) StrategyWrapper {
+	return func(inner ExecutionStrategy) ExecutionStrategy {
+		if timeout <= 0 {
+			return inner
+		}
+		return TimeoutExecutionStrategyWrapper{Timeout: timeout, AbandonAfter: abandonAfter, Inner: inner}
+	}
+}
+
+// WithJitter returns a StrategyWrapper that delays the start of each test run
+// by a random duration up to maxDelay. See JitterExecutionStrategyWrapper. A
+// non-positive maxDelay leaves the strategy unchanged.
+func WithJitter(maxDelay time.Duration) StrategyWrapper {
+	return func(inner ExecutionStrategy) ExecutionStrategy {
+		if maxDelay <= 0 {
+			return inner
+		}
+		return JitterExecutionStrategyWrapper{Max: maxDelay, Inner: inner}
+	}
+}
+
+// WithMaxInFlight returns a StrategyWrapper that limits the number of test
+// runs in progress at the same time. See MaxInFlightExecutionStrategyWrapper.
+// A non-positive limit leaves the strategy unchanged.
+func WithMaxInFlight(limit int) StrategyWrapper {
+	return func(inner ExecutionStrategy) ExecutionStrategy {
+		if limit <= 0 {
+			return inner
+		}
+		return MaxInFlightExecutionStrategyWrapper{Limit: limit, Inner: inner}
+	}
+}
+
+// WithShuffle returns a StrategyWrapper that shuffles the order of the test
+// runs. See ShuffleExecutionStrategyWrapper.
+func WithShuffle() StrategyWrapper {
+	return func(inner ExecutionStrategy) ExecutionStrategy {
+		return ShuffleExecutionStrategyWrapper{Inner: inner}
+	}
+}
@@ -0,0 +1,136 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Compose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Order", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+		record := func(name string) harness.StrategyWrapper {
+			return func(inner harness.ExecutionStrategy) harness.ExecutionStrategy {
+				return recordingStrategy{name: name, inner: inner, record: func(s string) {
+					mu.Lock()
+					defer mu.Unlock()
+					order = append(order, s)
+				}}
+			}
+		}
+
+		_, fns := strategyTestData(1, nil)
+		strategy := harness.Compose(harness.LinearExecutionStrategy{}, record("outer"), nil, record("inner"))
+		runErrs, err := strategy.Run(context.Background(), fns)
+		require.NoError(t, err)
+		require.Empty(t, runErrs)
+		// The per-run behavior of the inner wrapper happens first.
+		require.Equal(t, []string{"inner", "outer"}, order)
+	})
+
+	t.Run("ZeroValuesUnchanged", func(t *testing.T) {
+		t.Parallel()
+
+		base := harness.LinearExecutionStrategy{}
+		strategy := harness.Compose(base,
+			harness.WithRateLimit(0),
+			harness.WithTimeout(0, time.Second),
+			harness.WithJitter(0),
+			harness.WithMaxInFlight(0),
+		)
+		require.Equal(t, base, strategy)
+
+		strategy = harness.Compose(base, harness.WithTimeout(time.Minute, 0), harness.WithRateLimit(time.Second))
+		require.Equal(t, harness.TimeoutExecutionStrategyWrapper{
+			Timeout: time.Minute,
+			Inner: harness.RateLimitExecutionStrategyWrapper{
+				Interval: time.Second,
+				Inner:    base,
+			},
+		}, strategy)
+	})
+}
+
+// recordingStrategy records its name whenever one of its runs starts.
+type recordingStrategy struct {
+	name   string
+	inner  harness.ExecutionStrategy
+	record func(string)
+}
+
+func (r recordingStrategy) Run(ctx context.Context, fns []harness.TestFn) ([]error, error) {
+	newFns := make([]harness.TestFn, len(fns))
+	for i, fn := range fns {
+		newFns[i] = func(ctx context.Context) error {
+			r.record(r.name)
+			return fn(ctx)
+		}
+	}
+	return r.inner.Run(ctx, newFns)
+}
+
+func Test_MaxInFlightExecutionStrategyWrapper(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight atomic.Int64
+	_, fns := strategyTestData(20, func(_ context.Context, _ int, _ io.Writer) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	strategy := harness.Compose(harness.ConcurrentExecutionStrategy{}, harness.WithMaxInFlight(3))
+
+	runErrs, err := strategy.Run(context.Background(), fns)
+	require.NoError(t, err)
+	require.Empty(t, runErrs)
+	require.LessOrEqual(t, maxInFlight.Load(), int64(3))
+	require.Positive(t, maxInFlight.Load())
+}
+
+func Test_JitterExecutionStrategyWrapper(t *testing.T) {
+	t.Parallel()
+
+	const maxDelay = 200 * time.Millisecond
+	runs, fns := strategyTestData(10, nil)
+	strategy := harness.Compose(harness.ConcurrentExecutionStrategy{}, harness.WithJitter(maxDelay))
+
+	startTime := time.Now()
+	runErrs, err := strategy.Run(context.Background(), fns)
+	require.NoError(t, err)
+	require.Empty(t, runErrs)
+
+	// The runs were started concurrently, but not all at once.
+	var earliest, latest time.Time
+	for _, run := range runs {
+		started := run.Result().StartedAt
+		require.Less(t, started.Sub(startTime), maxDelay+time.Second)
+		if earliest.IsZero() || started.Before(earliest) {
+			earliest = started
+		}
+		if started.After(latest) {
+			latest = started
+		}
+	}
+	require.Greater(t, latest.Sub(earliest), maxDelay/10)
+}
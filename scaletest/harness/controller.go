@@ -0,0 +1,256 @@
+package harness
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+// LatencyTargetExecutionStrategy executes test runs concurrently and adjusts
+// the concurrency limit to find the maximum load the system under test can
+// sustain while the 95th percentile run duration stays under a target. The
+// limit is adjusted at every interval: it's increased by Step if the runs
+// that finished during the interval met the target and halved otherwise.
+// Intervals in which every run failed never meet the target.
+//
+// When run by a TestHarness, the discovered capacity is included in the
+// results.
+type LatencyTargetExecutionStrategy struct {
+	// Target is the maximum 95th percentile run duration.
+	Target time.Duration
+	// Interval is how often the concurrency limit is adjusted. Defaults to
+	// 30 seconds. Runs should finish well within the interval for the
+	// controller to be able to measure their duration.
+	Interval time.Duration
+	// InitialLimit is the concurrency limit to start with. Defaults to
+	// MinLimit.
+	InitialLimit int
+	// MinLimit is the lowest the concurrency limit is decreased to. Defaults
+	// to 1.
+	MinLimit int
+	// MaxLimit is the highest the concurrency limit is increased to. Zero
+	// means the number of test runs.
+	MaxLimit int
+	// Step is how much the concurrency limit is increased after an interval
+	// that met the target. Defaults to 1.
+	Step int
+}
+
+var _ ExecutionStrategy = LatencyTargetExecutionStrategy{}
+
+// Validate returns an error if the strategy is invalid.
+func (s LatencyTargetExecutionStrategy) Validate() error {
+	if s.Target <= 0 {
+		return xerrors.Errorf("target must be positive, got %s", s.Target)
+	}
+	if s.Interval < 0 {
+		return xerrors.Errorf("interval must not be negative, got %s", s.Interval)
+	}
+	if s.InitialLimit < 0 || s.MinLimit < 0 || s.MaxLimit < 0 || s.Step < 0 {
+		return xerrors.New("limits and step must not be negative")
+	}
+	if s.MaxLimit > 0 && max(s.MinLimit, s.InitialLimit) > s.MaxLimit {
+		return xerrors.Errorf("max limit %d must not be lower than the min and initial limits", s.MaxLimit)
+	}
+	return nil
+}
+
+// CapacityResult is the outcome of a LatencyTargetExecutionStrategy.
+type CapacityResult struct {
+	Target httpapi.Duration `json:"target"`
+	// Capacity is the highest number of concurrent runs during an interval
+	// whose 95th percentile run duration met the target. It's zero if no
+	// interval met the target.
+	Capacity int `json:"capacity"`
+	// Throughput is the number of runs per second finished during the
+	// interval Capacity was measured in.
+	Throughput float64 `json:"throughput"`
+	// Steps contains the measurements of every interval.
+	Steps []CapacityStep `json:"steps"`
+}
+
+// CapacityStep is the measurement of a single interval of a
+// LatencyTargetExecutionStrategy.
+type CapacityStep struct {
+	Time  time.Time `json:"time"`
+	Limit int       `json:"limit"`
+	// Concurrency is the highest number of runs that were in progress at the
+	// same time during the interval.
+	Concurrency int              `json:"concurrency"`
+	Runs        int              `json:"runs"`
+	Failures    int              `json:"failures"`
+	P95         httpapi.Duration `json:"p95"`
+	MetTarget   bool             `json:"met_target"`
+}
+
+type capacityKey struct{}
+
+// withCapacityRecorder returns a context that strategies report their
+// capacity result to.
+func withCapacityRecorder(ctx context.Context, record func(CapacityResult)) context.Context {
+	return context.WithValue(ctx, capacityKey{}, record)
+}
+
+func recordCapacity(ctx context.Context, res CapacityResult) {
+	if record, ok := ctx.Value(capacityKey{}).(func(CapacityResult)); ok {
+		record(res)
+	}
+}
+
+// latencyController tracks the runs of a LatencyTargetExecutionStrategy.
+type latencyController struct {
+	mut         sync.Mutex
+	limit       int
+	inFlight    int
+	concurrency int
+	// unlimited is set once the context is canceled, at which point the
+	// remaining runs are started immediately so they can handle the
+	// cancellation.
+	unlimited bool
+	hist      *Histogram
+	runs      int
+	failures  int
+	// wake is signaled whenever a run finishes or the limit changes.
+	wake chan struct{}
+}
+
+func (c *latencyController) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// acquire waits until another run may be started.
+func (c *latencyController) acquire(ctx context.Context) {
+	for {
+		c.mut.Lock()
+		if c.unlimited || c.inFlight < c.limit {
+			c.inFlight++
+			c.concurrency = max(c.concurrency, c.inFlight)
+			c.mut.Unlock()
+			return
+		}
+		c.mut.Unlock()
+
+		select {
+		case <-ctx.Done():
+			c.mut.Lock()
+			c.unlimited = true
+			c.mut.Unlock()
+		case <-c.wake:
+		}
+	}
+}
+
+func (c *latencyController) release(duration time.Duration, err error) {
+	c.mut.Lock()
+	c.inFlight--
+	c.runs++
+	if err != nil {
+		// Failed runs don't count towards the run duration, since they may
+		// fail fast.
+		c.failures++
+	} else {
+		c.hist.Record(duration)
+	}
+	c.mut.Unlock()
+	c.signal()
+}
+
+// Run implements ExecutionStrategy.
+func (s LatencyTargetExecutionStrategy) Run(ctx context.Context, fns []TestFn) ([]error, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	interval := s.Interval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+	minLimit := max(s.MinLimit, 1)
+	maxLimit := s.MaxLimit
+	if maxLimit == 0 {
+		maxLimit = max(len(fns), minLimit)
+	}
+	step := max(s.Step, 1)
+
+	c := &latencyController{
+		limit: min(max(s.InitialLimit, minLimit), maxLimit),
+		hist:  NewHistogram(),
+		wake:  make(chan struct{}, 1),
+	}
+	res := CapacityResult{Target: httpapi.Duration(s.Target)}
+
+	controlCtx, cancelControl := context.WithCancel(ctx)
+	controlDone := make(chan struct{})
+	go func() {
+		defer close(controlDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-controlCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			c.mut.Lock()
+			stepRes := CapacityStep{
+				Time:        time.Now(),
+				Limit:       c.limit,
+				Concurrency: c.concurrency,
+				Runs:        c.runs,
+				Failures:    c.failures,
+			}
+			if c.runs > 0 {
+				p95 := c.hist.Quantile(0.95)
+				stepRes.P95 = httpapi.Duration(p95)
+				stepRes.MetTarget = c.hist.Count() > 0 && p95 <= s.Target
+				if stepRes.MetTarget {
+					c.limit = min(c.limit+step, maxLimit)
+				} else {
+					c.limit = max(c.limit/2, minLimit)
+				}
+			}
+			c.hist = NewHistogram()
+			c.runs, c.failures = 0, 0
+			c.concurrency = c.inFlight
+			c.mut.Unlock()
+			c.signal()
+
+			res.Steps = append(res.Steps, stepRes)
+			if stepRes.MetTarget && stepRes.Concurrency >= res.Capacity {
+				res.Capacity = stepRes.Concurrency
+				res.Throughput = float64(stepRes.Runs) / interval.Seconds()
+			}
+		}
+	}()
+
+	var (
+		wg   sync.WaitGroup
+		errs = newErrorsList()
+	)
+	for i, fn := range fns {
+		c.acquire(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := fn(ctx)
+			c.release(time.Since(start), err)
+			if err != nil {
+				errs.add(xerrors.Errorf("run %d: %w", i, err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	cancelControl()
+	<-controlDone
+	recordCapacity(ctx, res)
+	return errs.errs, nil
+}
@@ -0,0 +1,75 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_LatencyTargetExecutionStrategy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FindsCapacity", func(t *testing.T) {
+		t.Parallel()
+
+		// Runs are fast as long as at most 3 run concurrently, and slow once
+		// the system is overloaded.
+		var inFlight atomic.Int64
+		strategy := harness.LatencyTargetExecutionStrategy{
+			Target:   20 * time.Millisecond,
+			Interval: 50 * time.Millisecond,
+			MaxLimit: 10,
+		}
+		h := harness.NewTestHarness(strategy, harness.LinearExecutionStrategy{})
+		for i := range 300 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+					n := inFlight.Add(1)
+					defer inFlight.Add(-1)
+					d := time.Millisecond
+					if n > 3 {
+						d = 40 * time.Millisecond
+					}
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(d):
+					}
+					return nil
+				},
+			})
+		}
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := h.Run(ctx)
+		require.NoError(t, err)
+
+		res := h.Results()
+		require.Equal(t, 300, res.TotalPass)
+		require.NotNil(t, res.Capacity)
+		require.NotEmpty(t, res.Capacity.Steps)
+		require.Equal(t, 20*time.Millisecond, time.Duration(res.Capacity.Target))
+		require.Positive(t, res.Capacity.Capacity)
+		require.LessOrEqual(t, res.Capacity.Capacity, 3)
+		require.Positive(t, res.Capacity.Throughput)
+		for _, step := range res.Capacity.Steps {
+			require.LessOrEqual(t, step.Limit, 10)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, harness.LatencyTargetExecutionStrategy{}.Validate())
+		require.Error(t, harness.LatencyTargetExecutionStrategy{Target: time.Second, MinLimit: 5, MaxLimit: 2}.Validate())
+		require.NoError(t, harness.LatencyTargetExecutionStrategy{Target: time.Second, MaxLimit: 2}.Validate())
+	})
+}
@@ -0,0 +1,91 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// DependsOn makes the test run start only after the given runs have finished,
+// e.g. to measure creating a workspace, generating traffic to it and deleting
+// it as separate runs. If any of the given runs fails or is skipped, the run is
+// skipped as well. The runs must be registered with the same harness. Panics if
+// the run has already started.
+//
+// The harness passes runs to the ExecutionStrategy in dependency order, so
+// strategies that start runs in the order they are given never block on a
+// dependency that hasn't started. Strategies that reorder runs, like
+// ShuffleExecutionStrategyWrapper, should not be used with dependencies. Time
+// spent waiting for dependencies doesn't count towards the duration of a run,
+// but it does count towards the timeout of TimeoutExecutionStrategyWrapper.
+func (r *TestRun) DependsOn(runs ...*TestRun) {
+	if r.done != nil {
+		panic("cannot add dependencies to a test run after it has started")
+	}
+	r.dependencies = append(r.dependencies, runs...)
+}
+
+// orderRuns returns the runs of the harness ordered so that every run comes
+// after its dependencies, keeping the order the runs were registered in
+// otherwise. An error is returned if the dependencies contain a cycle or a run
+// that is not registered with the harness.
+func (h *TestHarness) orderRuns() ([]*TestRun, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*TestRun]int, len(h.runs))
+	for _, run := range h.runs {
+		state[run] = unvisited
+	}
+
+	ordered := make([]*TestRun, 0, len(h.runs))
+	var visit func(run *TestRun) error
+	visit = func(run *TestRun) error {
+		switch state[run] {
+		case visiting:
+			return xerrors.Errorf("dependency cycle through test run %q", run.FullID())
+		case visited:
+			return nil
+		}
+		state[run] = visiting
+		for _, dep := range run.dependencies {
+			if _, ok := state[dep]; !ok {
+				return xerrors.Errorf("test run %q depends on %q, which is not registered with the harness", run.FullID(), dep.FullID())
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[run] = visited
+		ordered = append(ordered, run)
+		return nil
+	}
+	for _, run := range h.runs {
+		if err := visit(run); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// waitForDependencies blocks until every dependency of the run has finished,
+// and returns the reason the run should be skipped if any of them didn't pass.
+func (r *TestRun) waitForDependencies(ctx context.Context) (string, bool) {
+	for _, dep := range r.dependencies {
+		select {
+		case <-ctx.Done():
+			return fmt.Sprintf("test was canceled while waiting for dependency %q", dep.FullID()), true
+		case <-dep.finished:
+		}
+		switch {
+		case dep.skipped:
+			return fmt.Sprintf("dependency %q was skipped", dep.FullID()), true
+		case dep.err != nil:
+			return fmt.Sprintf("dependency %q failed", dep.FullID()), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,117 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_DependsOn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Order", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+		record := func(name string) testFns {
+			return testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					mu.Lock()
+					defer mu.Unlock()
+					order = append(order, name)
+					return nil
+				},
+			}
+		}
+
+		// Register the runs in reverse order to make sure the linear
+		// strategy doesn't block on a dependency that hasn't started.
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		deleteRun := h.AddRun("delete", "0", record("delete"))
+		trafficRun := h.AddRun("traffic", "0", record("traffic"))
+		createRun := h.AddRun("create", "0", record("create"))
+		trafficRun.DependsOn(createRun)
+		deleteRun.DependsOn(trafficRun)
+		require.NoError(t, h.Run(context.Background()))
+
+		require.Equal(t, []string{"create", "traffic", "delete"}, order)
+		res := h.Results()
+		require.Equal(t, 3, res.TotalPass)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		createDone := make(chan struct{})
+		h := harness.NewTestHarness(harness.ConcurrentExecutionStrategy{}, harness.ConcurrentExecutionStrategy{})
+		createRun := h.AddRun("create", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				close(createDone)
+				return nil
+			},
+		})
+		trafficRun := h.AddRun("traffic", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				select {
+				case <-createDone:
+					return nil
+				default:
+					return xerrors.New("started before its dependency finished")
+				}
+			},
+		})
+		trafficRun.DependsOn(createRun)
+		require.NoError(t, h.Run(context.Background()))
+		require.Equal(t, 2, h.Results().TotalPass)
+	})
+
+	t.Run("FailedDependency", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.ParallelExecutionStrategy{Limit: 2}, harness.LinearExecutionStrategy{})
+		createRun := h.AddRun("create", "0", fakeTestFns(xerrors.New("no capacity"), nil))
+		trafficRun := h.AddRun("traffic", "0", fakeTestFns(nil, nil))
+		deleteRun := h.AddRun("delete", "0", fakeTestFns(nil, nil))
+		h.AddRun("other", "0", fakeTestFns(nil, nil))
+		trafficRun.DependsOn(createRun)
+		deleteRun.DependsOn(trafficRun)
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		require.Equal(t, 1, res.TotalFail)
+		require.Equal(t, 1, res.TotalPass)
+		require.Equal(t, 2, res.TotalSkipped)
+		require.Equal(t, `dependency "create/0" failed`, res.Runs["traffic/0"].SkipReason)
+		require.Equal(t, `dependency "traffic/0" was skipped`, res.Runs["delete/0"].SkipReason)
+		require.False(t, res.Runs["other/0"].Skipped)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		a := h.AddRun("test", "a", fakeTestFns(nil, nil))
+		b := h.AddRun("test", "b", fakeTestFns(nil, nil))
+		a.DependsOn(b)
+		b.DependsOn(a)
+		require.ErrorContains(t, h.Run(context.Background()), "dependency cycle")
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		run := h.AddRun("test", "0", fakeTestFns(nil, nil))
+		run.DependsOn(harness.NewTestRun("other", "0", fakeTestFns(nil, nil)))
+		require.ErrorContains(t, h.Run(context.Background()), "not registered")
+	})
+}
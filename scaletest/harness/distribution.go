@@ -0,0 +1,58 @@
+package harness
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// Weighted is a value with a relative weight, e.g. an organization that should
+// receive a given share of the users created by a test.
+type Weighted[T any] struct {
+	Value  T
+	Weight int
+}
+
+// Distribution deterministically spreads runs across weighted values, so that
+// the share of runs assigned to each value matches its weight as closely as
+// possible at every point of the test, not just at the end. This keeps the
+// ratios intact for tests that are interrupted or use a duration instead of a
+// count. Distribution is not safe for concurrent use; runs are typically
+// assigned while they're added to the harness.
+type Distribution[T any] struct {
+	values  []Weighted[T]
+	current []int
+	total   int
+}
+
+// NewDistribution returns a distribution across the given values. Weights must
+// be positive.
+func NewDistribution[T any](values ...Weighted[T]) (*Distribution[T], error) {
+	if len(values) == 0 {
+		return nil, xerrors.New("at least one value is required")
+	}
+	d := &Distribution[T]{
+		values:  values,
+		current: make([]int, len(values)),
+	}
+	for i, v := range values {
+		if v.Weight <= 0 {
+			return nil, xerrors.Errorf("weight of value %d must be positive, got %d", i, v.Weight)
+		}
+		d.total += v.Weight
+	}
+	return d, nil
+}
+
+// Next returns the value to assign the next run to.
+func (d *Distribution[T]) Next() T {
+	// Smooth weighted round-robin: every value gains its weight, and the
+	// value that gained the most is picked and set back by the total.
+	best := 0
+	for i, v := range d.values {
+		d.current[i] += v.Weight
+		if d.current[i] > d.current[best] {
+			best = i
+		}
+	}
+	d.current[best] -= d.total
+	return d.values[best].Value
+}
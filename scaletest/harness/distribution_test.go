@@ -0,0 +1,45 @@
+package harness_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func TestDistribution(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ratios", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := harness.NewDistribution(
+			harness.Weighted[string]{Value: "a", Weight: 3},
+			harness.Weighted[string]{Value: "b", Weight: 1},
+			harness.Weighted[string]{Value: "c", Weight: 1},
+		)
+		require.NoError(t, err)
+
+		counts := map[string]int{}
+		var got []string
+		for range 10 {
+			v := d.Next()
+			got = append(got, v)
+			counts[v]++
+		}
+		require.Equal(t, map[string]int{"a": 6, "b": 2, "c": 2}, counts)
+		// Values are interleaved rather than assigned in blocks.
+		require.Equal(t, []string{"a", "b", "a", "c", "a"}, got[:5])
+		require.Equal(t, got[:5], got[5:])
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := harness.NewDistribution[string]()
+		require.Error(t, err)
+		_, err = harness.NewDistribution(harness.Weighted[string]{Value: "a", Weight: 0})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,133 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+)
+
+// DurationTest configures a test that runs for a wall-clock duration instead of
+// a fixed number of runs. See TestHarness.RunFor.
+type DurationTest struct {
+	// TestName is the test name of every run.
+	TestName string
+	// Duration is how long new runs are started for.
+	Duration time.Duration
+	// Concurrency is the number of runs in progress at the same time.
+	Concurrency int
+	// RunTimeout is applied to every run like a
+	// TimeoutExecutionStrategyWrapper would. Zero disables the timeout.
+	RunTimeout time.Duration
+	// NewRunner returns the runner of the run with the given ID. IDs start at
+	// 0 and increase in the order runs are started.
+	NewRunner func(id int) (Runnable, error)
+}
+
+// Validate returns an error if the test is invalid.
+func (t DurationTest) Validate() error {
+	if t.TestName == "" {
+		return xerrors.New("test name must be set")
+	}
+	if t.Duration <= 0 {
+		return xerrors.New("duration must be greater than 0")
+	}
+	if t.Concurrency <= 0 {
+		return xerrors.New("concurrency must be greater than 0")
+	}
+	if t.RunTimeout < 0 {
+		return xerrors.New("run timeout must not be negative")
+	}
+	if t.NewRunner == nil {
+		return xerrors.New("NewRunner must be set")
+	}
+	return nil
+}
+
+// RunFor runs the given test for its duration, starting a new run whenever a
+// run finishes so that the configured number of runs is in progress until the
+// duration elapses. Runs in progress at that point are allowed to finish. This
+// is what soak tests usually need, since the number of runs that fit in the
+// test duration is not known upfront.
+//
+// RunFor is used instead of Run. The run strategy of the harness is not used,
+// and no runs may be registered with AddRun or RegisterRun. Results and Cleanup
+// cover every run that was started. Blocks until the test has finished and
+// returns the test execution error (not individual run errors), including any
+// error returned by NewRunner, which stops the test.
+//
+// Panics if the harness has already been started.
+func (h *TestHarness) RunFor(ctx context.Context, test DurationTest) (err error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	if err := test.Validate(); err != nil {
+		return xerrors.Errorf("validate duration test: %w", err)
+	}
+	h.mut.Lock()
+	registered := len(h.runs)
+	h.mut.Unlock()
+	if registered > 0 {
+		return xerrors.Errorf("duration tests cannot have registered runs, got %d", registered)
+	}
+	if h.rerun != nil {
+		return xerrors.New("duration tests cannot be rerun")
+	}
+
+	err = h.execute(ctx, fmt.Sprintf("Scaletest run (%s)", test.Duration), func(ctx context.Context, p *runPhase) error {
+		var (
+			deadline = p.start.Add(test.Duration)
+			nextID   atomic.Int64
+			wg       sync.WaitGroup
+			errOnce  sync.Once
+			firstErr error
+		)
+		for range test.Concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for time.Now().Before(deadline) && ctx.Err() == nil && !h.draining.Load() {
+					id := int(nextID.Add(1) - 1)
+					runner, err := test.NewRunner(id)
+					if err != nil {
+						errOnce.Do(func() {
+							firstErr = xerrors.Errorf("create runner %d: %w", id, err)
+						})
+						h.drain("failed to create runner")
+						return
+					}
+
+					run := NewTestRun(test.TestName, strconv.Itoa(id), runner)
+					h.mut.Lock()
+					h.runIDs[run.FullID()] = struct{}{}
+					h.configureRun(run)
+					h.runs = append(h.runs, run)
+					h.mut.Unlock()
+
+					// The total number of runs is not known upfront.
+					fn := p.testFn(run, id, 0)
+					h.emit(run, Event{Type: EventRunQueued})
+					runCtx, cancel := ctx, func() {}
+					if test.RunTimeout > 0 {
+						runCtx, cancel = context.WithTimeoutCause(ctx, test.RunTimeout, ErrRunTimeout)
+					}
+					// Run errors are recorded by the *TestRun.
+					_ = fn(runCtx)
+					cancel()
+				}
+			}()
+		}
+		wg.Wait()
+		return firstErr
+	})
+	if reportErr := h.report(ctx); err == nil {
+		err = reportErr
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_RunFor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var inFlight, maxInFlight atomic.Int64
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		err := h.RunFor(context.Background(), harness.DurationTest{
+			TestName:    "soak",
+			Duration:    200 * time.Millisecond,
+			Concurrency: 3,
+			NewRunner: func(int) (harness.Runnable, error) {
+				return testFns{
+					RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+						n := inFlight.Add(1)
+						defer inFlight.Add(-1)
+						for {
+							m := maxInFlight.Load()
+							if n <= m || maxInFlight.CompareAndSwap(m, n) {
+								break
+							}
+						}
+						md, ok := harness.Metadata(ctx)
+						if !ok || md.Total != 0 {
+							return xerrors.New("unexpected metadata")
+						}
+						time.Sleep(20 * time.Millisecond)
+						return nil
+					},
+				}, nil
+			},
+		})
+		require.NoError(t, err)
+
+		res := h.Results()
+		// Three runs at a time for roughly 200ms with 20ms each.
+		require.Greater(t, res.TotalRuns, 10)
+		require.Equal(t, res.TotalRuns, res.TotalPass)
+		require.Equal(t, int64(3), maxInFlight.Load())
+		require.GreaterOrEqual(t, time.Duration(res.Elapsed), 200*time.Millisecond)
+		require.Contains(t, res.Runs, "soak/0")
+	})
+
+	t.Run("RunTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		err := h.RunFor(context.Background(), harness.DurationTest{
+			TestName:    "soak",
+			Duration:    50 * time.Millisecond,
+			Concurrency: 1,
+			RunTimeout:  10 * time.Millisecond,
+			NewRunner: func(int) (harness.Runnable, error) {
+				return testFns{
+					RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				}, nil
+			},
+		})
+		require.NoError(t, err)
+
+		res := h.Results()
+		require.Positive(t, res.TotalRuns)
+		require.Equal(t, res.TotalRuns, res.TotalTimedOut)
+	})
+
+	t.Run("NewRunnerError", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		err := h.RunFor(context.Background(), harness.DurationTest{
+			TestName:    "soak",
+			Duration:    time.Minute,
+			Concurrency: 2,
+			NewRunner: func(id int) (harness.Runnable, error) {
+				if id >= 5 {
+					return nil, xerrors.New("out of workspaces")
+				}
+				return fakeTestFns(nil, nil), nil
+			},
+		})
+		require.ErrorContains(t, err, "out of workspaces")
+		// Runs that were about to start when the test stopped are skipped.
+		res := h.Results()
+		require.Equal(t, 5, res.TotalRuns+res.TotalSkipped)
+	})
+
+	t.Run("RegisteredRuns", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		h.AddRun("test", "0", fakeTestFns(nil, nil))
+		err := h.RunFor(context.Background(), harness.DurationTest{
+			TestName:    "soak",
+			Duration:    time.Second,
+			Concurrency: 1,
+			NewRunner: func(int) (harness.Runnable, error) {
+				return fakeTestFns(nil, nil), nil
+			},
+		})
+		require.ErrorContains(t, err, "cannot have registered runs")
+	})
+}
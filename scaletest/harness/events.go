@@ -0,0 +1,90 @@
+package harness
+
+import "time"
+
+// EventType is the type of an Event emitted by a TestHarness.
+type EventType string
+
+const (
+	// EventRunQueued is emitted for every run when the harness hands its runs
+	// to the run execution strategy.
+	EventRunQueued EventType = "run_queued"
+	// EventRunStarted is emitted when the execution strategy starts a run.
+	EventRunStarted EventType = "run_started"
+	// EventRunSkipped is emitted when a run is skipped instead of started,
+	// e.g. because the harness was drained.
+	EventRunSkipped EventType = "run_skipped"
+	// EventRunCompleted is emitted when a run returns, including all of its
+	// retries.
+	EventRunCompleted EventType = "run_completed"
+	// EventCleanupStarted is emitted when the cleanup of a run starts. Runs
+	// that don't need to be cleaned up emit no cleanup events.
+	EventCleanupStarted EventType = "cleanup_started"
+	// EventCleanupCompleted is emitted when the cleanup of a run returns.
+	EventCleanupCompleted EventType = "cleanup_completed"
+)
+
+// Event describes something that happened to a test run of a harness.
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	TestName string
+	ID       string
+	// Warmup is whether the run is part of the warmup phase. It is only known
+	// once the run has started.
+	Warmup bool
+	// Duration is how long the run or its cleanup took, for completed events.
+	Duration time.Duration
+	// Error is the error returned by the run or its cleanup, for completed
+	// events.
+	Error error
+	// SkipReason is why the run was skipped, for EventRunSkipped.
+	SkipReason string
+}
+
+// FullID returns the full ID of the run the event belongs to.
+func (e Event) FullID() string {
+	return e.TestName + "/" + e.ID
+}
+
+// Observer receives the events emitted by a TestHarness, e.g. to log them,
+// export them as metrics or render the progress of a test. Events are
+// delivered synchronously from the goroutine executing the run, so observers
+// must be safe for concurrent use and return quickly.
+type Observer interface {
+	Observe(event Event)
+}
+
+// ObserverFunc is an Observer implemented by a function.
+type ObserverFunc func(event Event)
+
+var _ Observer = ObserverFunc(nil)
+
+// Observe implements Observer.
+func (f ObserverFunc) Observe(event Event) {
+	f(event)
+}
+
+// WithObserver subscribes the observer to the events of the harness. It can be
+// given multiple times to subscribe multiple observers, which receive every
+// event in the order they were given.
+func WithObserver(o Observer) Option {
+	return func(h *TestHarness) {
+		h.observers = append(h.observers, o)
+	}
+}
+
+// emit delivers the event to every observer of the harness.
+func (h *TestHarness) emit(run *TestRun, event Event) {
+	if len(h.observers) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.TestName = run.testName
+	event.ID = run.id
+	for _, o := range h.observers {
+		o.Observe(event)
+	}
+}
@@ -0,0 +1,115 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// eventRecorder is an Observer that records every event it receives.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []harness.Event
+}
+
+func (r *eventRecorder) Observe(event harness.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// types returns the types of the events of the given run, in order.
+func (r *eventRecorder) types(fullID string) []harness.EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []harness.EventType
+	for _, e := range r.events {
+		if e.FullID() == fullID {
+			types = append(types, e.Type)
+		}
+	}
+	return types
+}
+
+func Test_Observers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		first  = &eventRecorder{}
+		second = &eventRecorder{}
+	)
+	h := harness.NewTestHarness(
+		skipOddStrategy{},
+		harness.LinearExecutionStrategy{},
+		harness.WithWarmup(harness.Warmup{Runs: 1}),
+		harness.WithObserver(first),
+		harness.WithObserver(second),
+	)
+	h.AddRun("test", "0", testFns{
+		RunFn: func(context.Context, string, io.Writer) error {
+			return nil
+		},
+		CleanupFn: func(context.Context, string, io.Writer) error {
+			return nil
+		},
+	})
+	h.AddRun("test", "1", testFns{
+		RunFn: func(context.Context, string, io.Writer) error {
+			return xerrors.New("should be skipped")
+		},
+		CleanupFn: func(context.Context, string, io.Writer) error {
+			return xerrors.New("should not be cleaned up")
+		},
+	})
+	h.AddRun("test", "2", testFns{
+		RunFn: func(context.Context, string, io.Writer) error {
+			return xerrors.New("test error")
+		},
+		CleanupFn: func(context.Context, string, io.Writer) error {
+			return xerrors.New("cleanup error")
+		},
+	})
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+	err = h.Cleanup(context.Background())
+	require.ErrorContains(t, err, "cleanup error")
+
+	require.Equal(t, []harness.EventType{
+		harness.EventRunQueued,
+		harness.EventRunStarted,
+		harness.EventRunCompleted,
+		harness.EventCleanupStarted,
+		harness.EventCleanupCompleted,
+	}, first.types("test/0"))
+	require.Equal(t, []harness.EventType{
+		harness.EventRunQueued,
+		harness.EventRunSkipped,
+	}, first.types("test/1"))
+	require.Equal(t, first.types("test/2"), first.types("test/0"))
+	// Every observer receives every event.
+	require.Equal(t, first.events, second.events)
+
+	for _, e := range first.events {
+		require.False(t, e.Time.IsZero())
+		switch {
+		case e.Type == harness.EventRunSkipped:
+			require.Equal(t, "odd run", e.SkipReason)
+		case e.Type == harness.EventRunCompleted && e.ID == "0":
+			require.True(t, e.Warmup)
+			require.NoError(t, e.Error)
+			require.Positive(t, e.Duration)
+		case e.Type == harness.EventRunCompleted && e.ID == "2":
+			require.False(t, e.Warmup)
+			require.ErrorContains(t, e.Error, "test error")
+		case e.Type == harness.EventCleanupCompleted && e.ID == "2":
+			require.ErrorContains(t, e.Error, "cleanup error")
+		}
+	}
+}
@@ -2,20 +2,57 @@ package harness
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/tracing"
 )
 
+// TracerName is the name of the tracer used for test run spans.
+const TracerName = "coder_scaletest_harness"
+
 // TestHarness runs a bunch of registered test runs using the given execution
 // strategies.
 type TestHarness struct {
 	runStrategy     ExecutionStrategy
 	cleanupStrategy ExecutionStrategy
+	tracer          trace.Tracer
+	retry           RetryPolicy
+	warmup          Warmup
+	artifactDir     string
+	logStream       io.Writer
+	logDir          string
+	seed            int64
+	shard           Shard
+	chaosHooks      []ChaosHook
+	annotator       Annotator
+	observers       []Observer
+	reporters       []Reporter
+	circuitBreaker  *circuitBreakerState
+	capacity        *CapacityResult
+	// resourceInterval is the interval at which the resource usage of the
+	// process is sampled. Zero disables sampling.
+	resourceInterval time.Duration
+	// snapshotInterval is the interval at which snapshots are taken, and
+	// snapshotFn receives them. Zero disables snapshots.
+	snapshotInterval time.Duration
+	snapshotFn       func(Snapshot)
+	profiles         ProfileConfig
+	progress         *progressTracker
+	retainFailed     bool
+	rerun            *rerunState
+	phaseTimeouts    PhaseTimeouts
+	// aggregate accumulates the results while the test runs, if set by
+	// WithResultRetention. Otherwise, results are aggregated by Results.
+	aggregate *resultAggregator
 
 	mut     *sync.Mutex
 	runIDs  map[string]struct{}
@@ -23,18 +60,110 @@ type TestHarness struct {
 	started bool
 	done    chan struct{}
 	elapsed time.Duration
+	// cancelRun cancels the context of the run phase once it has started.
+	cancelRun context.CancelCauseFunc
+	// reportErr holds the errors of reporting snapshots.
+	reportErr error
+	// latencies holds a histogram of run durations per test name.
+	latencies map[string]*Histogram
+	// tagLatencies holds a histogram of run durations per tag key and value.
+	tagLatencies map[string]map[string]*Histogram
+	// phaseLatencies holds a histogram of phase durations per test name and
+	// phase.
+	phaseLatencies map[string]map[string]*Histogram
+	// startedRuns is the number of runs that have been started.
+	startedRuns atomic.Int64
+	// completedRuns is the number of started runs that have returned.
+	completedRuns atomic.Int64
+	// draining is set once no more runs should be started, and drainReason
+	// is why.
+	draining    atomic.Bool
+	drainReason atomic.Pointer[string]
+	// chaosEvents holds every fault injected by the chaos hooks.
+	chaosEvents []ChaosEvent
+	// resourceUsage holds the resource usage of the process during the run
+	// phase, if it was sampled.
+	resourceUsage *ResourceUsage
+	snapshots     snapshotter
+	// profileCaptures holds every profile captured by WithProfiles.
+	profileCaptures []ProfileCapture
+	// retained holds the failed runs whose resources are retained by
+	// WithRetainFailedRuns.
+	retained []RetainedRun
+}
+
+// Option configures optional behavior of a TestHarness.
+type Option func(*TestHarness)
+
+// WithTracerProvider records an OpenTelemetry trace for every test run
+// registered with the harness, with child spans for the setup, run and cleanup
+// phases of the run.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(h *TestHarness) {
+		h.tracer = tp.Tracer(TracerName)
+	}
+}
+
+// WithRetryPolicy retries failed test runs according to the given policy. The
+// runners of all registered runs must support being run more than once. Failed
+// attempts of Cleanable runners are cleaned up before they are retried. The
+// number of attempts of each run is recorded in its result, and its duration
+// is that of the last attempt.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(h *TestHarness) {
+		h.retry = p
+	}
+}
+
+// Warmup configures which test runs are considered part of the warmup phase of
+// a test. Warmup runs execute normally but are excluded from the totals and
+// latency percentiles of the results, so that effects like connection pools
+// warming up don't skew them. A run is part of the warmup phase if it satisfies
+// either condition.
+type Warmup struct {
+	// Runs is the number of runs, in the order they are started, that are
+	// part of the warmup phase.
+	Runs int
+	// Duration is the length of the warmup phase from the start of the test.
+	// Runs that start within this duration are part of the warmup phase.
+	Duration time.Duration
+}
+
+// WithWarmup excludes runs in the given warmup phase from the results summary.
+func WithWarmup(w Warmup) Option {
+	return func(h *TestHarness) {
+		h.warmup = w
+	}
 }
 
 // NewTestHarness creates a new TestHarness with the given execution strategies.
-func NewTestHarness(runStrategy, cleanupStrategy ExecutionStrategy) *TestHarness {
-	return &TestHarness{
+func NewTestHarness(runStrategy, cleanupStrategy ExecutionStrategy, opts ...Option) *TestHarness {
+	h := &TestHarness{
 		runStrategy:     runStrategy,
 		cleanupStrategy: cleanupStrategy,
 		mut:             new(sync.Mutex),
 		runIDs:          map[string]struct{}{},
 		runs:            []*TestRun{},
 		done:            make(chan struct{}),
+		latencies:       map[string]*Histogram{},
+		tagLatencies:    map[string]map[string]*Histogram{},
+		phaseLatencies:  map[string]map[string]*Histogram{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.seed == 0 && h.rerun != nil {
+		h.seed = h.rerun.seed
+	}
+	if h.seed == 0 {
+		h.seed = cryptoRandSource{}.Int63()
 	}
+	if h.aggregate != nil {
+		//nolint:gosec // not used for cryptographic purposes
+		h.aggregate.rng = rand.New(rand.NewSource(h.seed))
+		h.aggregate.trackWindows = len(h.chaosHooks) > 0
+	}
+	return h
 }
 
 // Run runs the registered tests using the given ExecutionStrategy. The provided
@@ -47,18 +176,73 @@ func (h *TestHarness) Run(ctx context.Context) (err error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
+	runs, err := h.orderRuns()
+	if err != nil {
+		return xerrors.Errorf("order test runs: %w", err)
+	}
+	total := len(runs)
+	indexes := make([]int, len(runs))
+	for i := range runs {
+		indexes[i] = i
+	}
+	if h.rerun != nil {
+		runs, indexes = h.rerunRuns(runs)
+	}
+	err = h.execute(ctx, fmt.Sprintf("Scaletest run (%d runs)", len(runs)), func(ctx context.Context, p *runPhase) error {
+		runFns := make([]TestFn, len(runs))
+		for i, run := range runs {
+			runFns[i] = p.testFn(run, indexes[i], total)
+		}
+		for _, run := range runs {
+			h.emit(run, Event{Type: EventRunQueued, Time: p.start})
+		}
+		// We don't care about test failures here since they already get
+		// recorded by the *TestRun.
+		_, err := h.runStrategy.Run(ctx, runFns)
+		return err
+	})
+	if reportErr := h.report(ctx); err == nil {
+		err = reportErr
+	}
+	return err
+}
+
+// runPhase is the state of the run phase of a harness shared by its test
+// runs.
+type runPhase struct {
+	h          *TestHarness
+	start      time.Time
+	warmupDone sync.Once
+	// warmupOver is set once the first run outside of the warmup phase has
+	// started.
+	warmupOver atomic.Bool
+}
+
+// execute runs the run phase of the harness, calling fn to execute the test
+// runs while the resource sampler, chaos hooks, profiler and progress renderer
+// are active.
+func (h *TestHarness) execute(ctx context.Context, annotation string, fn func(ctx context.Context, p *runPhase) error) (err error) {
+	ctx, cancelTimeout := h.runPhaseContext(ctx)
+	defer cancelTimeout()
+	ctx, cancelRun := context.WithCancelCause(ctx)
+	defer cancelRun(nil)
+
 	h.mut.Lock()
 	if h.started {
 		h.mut.Unlock()
 		panic("harness is already started")
 	}
 	h.started = true
+	h.cancelRun = cancelRun
 	h.mut.Unlock()
 
-	runFns := make([]TestFn, len(h.runs))
-	for i, run := range h.runs {
-		runFns[i] = run.Run
-	}
+	ctx = context.WithValue(ctx, seedKey{}, h.seed)
+	ctx = withCapacityRecorder(ctx, func(res CapacityResult) {
+		h.mut.Lock()
+		defer h.mut.Unlock()
+		h.capacity = &res
+	})
+	p := &runPhase{h: h, start: time.Now()}
 
 	defer close(h.done)
 	defer func() {
@@ -68,23 +252,232 @@ func (h *TestHarness) Run(ctx context.Context) (err error) {
 		}
 	}()
 
-	start := time.Now()
 	defer func() {
 		h.mut.Lock()
 		defer h.mut.Unlock()
-		h.elapsed = time.Since(start)
+		h.elapsed = time.Since(p.start)
 	}()
 
-	// We don't care about test failures here since they already get recorded
-	// by the *TestRun.
-	_, err = h.runStrategy.Run(ctx, runFns)
+	endRunAnnotation := h.annotate(ctx, Annotation{
+		Time: p.start,
+		Text: annotation,
+		Tags: []string{AnnotationTagRun},
+	})
+	defer endRunAnnotation()
+
+	if h.resourceInterval > 0 {
+		sampleCtx, cancelSample := context.WithCancel(ctx)
+		sampleDone := make(chan struct{})
+		go func() {
+			defer close(sampleDone)
+			h.sampleResources(sampleCtx)
+		}()
+		defer func() {
+			cancelSample()
+			<-sampleDone
+		}()
+	}
+
+	if len(h.chaosHooks) > 0 {
+		chaosCtx, cancelChaos := context.WithCancel(ctx)
+		chaosDone := make(chan struct{})
+		go func() {
+			defer close(chaosDone)
+			h.runChaos(chaosCtx, p.start)
+		}()
+		// Stop injecting faults and revert them once the run phase is over.
+		defer func() {
+			cancelChaos()
+			<-chaosDone
+		}()
+	}
+
+	if h.snapshotInterval > 0 {
+		snapshotCtx, cancelSnapshots := context.WithCancel(ctx)
+		snapshotsDone := make(chan struct{})
+		go func() {
+			defer close(snapshotsDone)
+			h.takeSnapshots(snapshotCtx, p.start)
+		}()
+		// The final snapshot is taken once the run phase is over.
+		defer func() {
+			cancelSnapshots()
+			<-snapshotsDone
+		}()
+	}
+
+	if h.profiles.Interval > 0 && h.profiles.Profiler != nil {
+		profileCtx, cancelProfiles := context.WithCancel(ctx)
+		profilesDone := make(chan struct{})
+		go func() {
+			defer close(profilesDone)
+			h.captureProfiles(profileCtx, p)
+		}()
+		defer func() {
+			cancelProfiles()
+			<-profilesDone
+		}()
+	}
+
+	if h.progress != nil {
+		progressCtx, cancelProgress := context.WithCancel(ctx)
+		progressDone := make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			h.progress.render(progressCtx, p.start)
+		}()
+		// The final update is rendered once the run phase is over.
+		defer func() {
+			cancelProgress()
+			<-progressDone
+		}()
+	}
+
+	err = fn(ctx, p)
+	if h.retainFailed {
+		// The resources are listed even if the run phase was canceled.
+		h.retainFailedRuns(context.WithoutCancel(ctx))
+	}
 	//nolint:revive // we use named returns because we mutate it in a defer
 	return
 }
 
+// testFn returns the function that executes the given run, which has the given
+// index among the total number of runs, as part of the run phase.
+func (p *runPhase) testFn(run *TestRun, index, total int) TestFn {
+	h := p.h
+	//nolint:gosec // gosec thinks we're using an insecure RNG, but we're not.
+	run.rng = rand.New(rand.NewSource(runSeed(h.seed, run.FullID())))
+	run.metadata = &RunMetadata{
+		TestName: run.testName,
+		ID:       run.id,
+		Index:    index,
+		Total:    total,
+		Shard:    h.shard,
+		Seed:     h.seed,
+	}
+	run.finished = make(chan struct{})
+	return func(ctx context.Context) error {
+		defer close(run.finished)
+		if reason, ok := run.waitForDependencies(ctx); ok {
+			run.skip(reason)
+			h.emit(run, Event{Type: EventRunSkipped, SkipReason: reason})
+			h.recordResult(run)
+			return nil
+		}
+		if h.draining.Load() {
+			reason := *h.drainReason.Load()
+			run.skip(reason)
+			h.emit(run, Event{Type: EventRunSkipped, SkipReason: reason})
+			h.recordResult(run)
+			return nil
+		}
+		if reason, ok := skipReason(ctx); ok {
+			run.skip(reason)
+			h.emit(run, Event{Type: EventRunSkipped, SkipReason: reason})
+			h.recordResult(run)
+			return nil
+		}
+		started := h.startedRuns.Add(1)
+		run.warmup = started <= int64(h.warmup.Runs) || time.Since(p.start) < h.warmup.Duration
+		if !run.warmup && (h.warmup.Runs > 0 || h.warmup.Duration > 0) {
+			p.warmupDone.Do(func() {
+				p.warmupOver.Store(true)
+				h.annotate(ctx, Annotation{
+					Time: time.Now(),
+					Text: "Scaletest warmup finished",
+					Tags: []string{AnnotationTagWarmup},
+				})
+			})
+		}
+		h.emit(run, Event{Type: EventRunStarted, Warmup: run.warmup})
+		err := run.Run(ctx)
+		h.completedRuns.Add(1)
+		h.emit(run, Event{Type: EventRunCompleted, Warmup: run.warmup, Duration: run.duration, Error: err})
+		if !run.warmup {
+			h.recordLatency(run)
+			h.recordCircuitBreaker(ctx, err)
+			h.recordSnapshotRun(run)
+		}
+		h.recordResult(run)
+		return err
+	}
+}
+
+// Drain stops the harness from starting any more test runs, e.g. because the
+// test was interrupted. Runs in progress are allowed to finish, and runs that
+// haven't started yet are marked as skipped in the results. Drain is safe to
+// call concurrently with Run.
+func (h *TestHarness) Drain() {
+	h.drain("test was interrupted")
+}
+
+// ErrStopped is the cause of the context of the runs that were still in
+// progress when the deadline of TestHarness.Stop was reached.
+var ErrStopped = xerrors.New("test was stopped")
+
+// Stop gracefully stops a running harness and returns the partial results. Like
+// Drain, it prevents any more test runs from starting, and then waits for the
+// runs in progress to finish. If ctx is done before they have, their context is
+// canceled with ErrStopped as the cause, and Stop returns the results and an
+// error once they have returned, so runners must honor cancellation. Runs that
+// never started are marked as skipped in the results.
+//
+// Stop is safe to call concurrently with Run or RunFor. It returns an error if
+// the harness has not been started, in which case all runs are skipped if it
+// is started later.
+func (h *TestHarness) Stop(ctx context.Context) (Results, error) {
+	h.drain("test was stopped")
+
+	h.mut.Lock()
+	started, cancelRun := h.started, h.cancelRun
+	h.mut.Unlock()
+	if !started {
+		return Results{}, xerrors.New("harness has not started")
+	}
+
+	select {
+	case <-h.done:
+		return h.Results(), nil
+	case <-ctx.Done():
+	}
+
+	cancelRun(ErrStopped)
+	<-h.done
+	return h.Results(), xerrors.Errorf("canceled runs in progress: %w", context.Cause(ctx))
+}
+
+// drain stops the harness from starting any more test runs for the given
+// reason. Only the first reason is recorded.
+func (h *TestHarness) drain(reason string) {
+	h.drainReason.CompareAndSwap(nil, &reason)
+	h.draining.Store(true)
+}
+
+// recordLatency adds the duration of the given finished run to the latency
+// histograms for its test name and tags.
+func (h *TestHarness) recordLatency(run *TestRun) {
+	h.mut.Lock()
+	hist, ok := h.latencies[run.testName]
+	if !ok {
+		hist = NewHistogram()
+		h.latencies[run.testName] = hist
+	}
+	hists := append(h.recordTagLatency(run), hist)
+	h.mut.Unlock()
+
+	for _, hist := range hists {
+		hist.Record(run.duration)
+	}
+	h.recordPhaseLatency(run)
+}
+
 // Cleanup should be called after the test run has finished and results have
 // been collected.
 func (h *TestHarness) Cleanup(ctx context.Context) (err error) {
+	ctx, cancel := h.cleanupPhaseContext(ctx)
+	defer cancel()
+
 	h.mut.Lock()
 	defer h.mut.Unlock()
 	if !h.started {
@@ -96,9 +489,25 @@ func (h *TestHarness) Cleanup(ctx context.Context) (err error) {
 		panic("harness has not finished")
 	}
 
+	endCleanupAnnotation := h.annotate(ctx, Annotation{
+		Time: time.Now(),
+		Text: "Scaletest cleanup",
+		Tags: []string{AnnotationTagCleanup},
+	})
+	defer endCleanupAnnotation()
+
 	cleanupFns := make([]TestFn, len(h.runs))
 	for i, run := range h.runs {
-		cleanupFns[i] = run.Cleanup
+		cleanupFns[i] = func(ctx context.Context) error {
+			if !run.cleanable() {
+				return nil
+			}
+			started := time.Now()
+			h.emit(run, Event{Type: EventCleanupStarted, Time: started})
+			err := run.Cleanup(ctx)
+			h.emit(run, Event{Type: EventCleanupCompleted, Duration: time.Since(started), Error: err})
+			return err
+		}
 	}
 
 	defer func() {
@@ -1,12 +1,17 @@
 package harness_test
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/scaletest/harness"
@@ -55,6 +60,8 @@ func Test_TestHarness(t *testing.T) {
 			r1.FullID(): r1.Result(),
 			r2.FullID(): r2.Result(),
 		}, res.Runs)
+		require.Len(t, res.Latency, 1)
+		require.EqualValues(t, 2, res.Latency["test"].Count)
 
 		err = h.Cleanup(context.Background())
 		require.NoError(t, err)
@@ -165,6 +172,173 @@ func Test_TestHarness(t *testing.T) {
 		})
 	})
 
+	t.Run("Warmup", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := xerrors.New("expected error")
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{}, harness.WithWarmup(harness.Warmup{Runs: 2}))
+		w1 := h.AddRun("test", "1", fakeTestFns(expectedErr, nil))
+		w2 := h.AddRun("test", "2", fakeTestFns(nil, nil))
+		r3 := h.AddRun("test", "3", fakeTestFns(nil, nil))
+		r4 := h.AddRun("test", "4", fakeTestFns(expectedErr, nil))
+
+		err := h.Run(context.Background())
+		require.NoError(t, err)
+
+		require.True(t, w1.Result().Warmup)
+		require.True(t, w2.Result().Warmup)
+		require.False(t, r3.Result().Warmup)
+		require.False(t, r4.Result().Warmup)
+
+		res := h.Results()
+		require.Len(t, res.Runs, 4)
+		require.Equal(t, 2, res.TotalWarmup)
+		require.Equal(t, 2, res.TotalRuns)
+		require.Equal(t, 1, res.TotalPass)
+		require.Equal(t, 1, res.TotalFail)
+		require.EqualValues(t, 2, res.Latency["test"].Count)
+	})
+
+	t.Run("Drain", func(t *testing.T) {
+		t.Parallel()
+
+		var cleanupCalls atomic.Int64
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		r1 := h.AddRun("test", "1", testFns{
+			RunFn: func(_ context.Context, _ string, _ io.Writer) error {
+				// The run in progress is allowed to finish.
+				h.Drain()
+				return nil
+			},
+			CleanupFn: func(_ context.Context, _ string, _ io.Writer) error {
+				cleanupCalls.Add(1)
+				return nil
+			},
+		})
+		r2 := h.AddRun("test", "2", testFns{
+			RunFn: func(_ context.Context, _ string, _ io.Writer) error {
+				return xerrors.New("should not run")
+			},
+			CleanupFn: func(_ context.Context, _ string, _ io.Writer) error {
+				cleanupCalls.Add(1)
+				return nil
+			},
+		})
+
+		err := h.Run(context.Background())
+		require.NoError(t, err)
+
+		require.False(t, r1.Result().Skipped)
+		require.NoError(t, r1.Result().Error)
+		require.True(t, r2.Result().Skipped)
+		require.Equal(t, "test was interrupted", r2.Result().SkipReason)
+		require.NoError(t, r2.Result().Error)
+
+		res := h.Results()
+		require.Len(t, res.Runs, 2)
+		require.Equal(t, 1, res.TotalRuns)
+		require.Equal(t, 1, res.TotalPass)
+		require.Equal(t, 1, res.TotalSkipped)
+
+		err = h.Cleanup(context.Background())
+		require.NoError(t, err)
+		require.EqualValues(t, 1, cleanupCalls.Load())
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := xerrors.New("expected error")
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		r1 := h.AddRun("test", "1", fakeTestFns(nil, nil))
+		r1.SetTag("template", "a")
+		r1.SetTag("region", "eu")
+		r2 := h.AddRun("test", "2", fakeTestFns(expectedErr, nil))
+		r2.SetTag("template", "a")
+		r3 := h.AddRun("test", "3", fakeTestFns(nil, nil))
+		r3.SetTag("template", "b")
+		_ = h.AddRun("test", "4", fakeTestFns(nil, nil))
+
+		err := h.Run(context.Background())
+		require.NoError(t, err)
+		require.Panics(t, func() {
+			r1.SetTag("late", "tag")
+		})
+
+		res := h.Results()
+		require.Equal(t, map[string]string{"template": "a", "region": "eu"}, res.Runs["test/1"].Tags)
+		require.Nil(t, res.Runs["test/4"].Tags)
+
+		require.Len(t, res.Tags, 2)
+		a := res.Tags["template"]["a"]
+		require.Equal(t, 2, a.TotalRuns)
+		require.Equal(t, 1, a.TotalPass)
+		require.Equal(t, 1, a.TotalFail)
+		require.EqualValues(t, 2, a.Latency.Count)
+		b := res.Tags["template"]["b"]
+		require.Equal(t, 1, b.TotalRuns)
+		require.EqualValues(t, 1, b.Latency.Count)
+		require.Equal(t, 1, res.Tags["region"]["eu"].TotalRuns)
+
+		out := bytes.NewBuffer(nil)
+		res.PrintText(out)
+		require.Contains(t, out.String(), "By template:")
+		require.Contains(t, out.String(), "a: pass 1, fail 1")
+	})
+
+	t.Run("Tracing", func(t *testing.T) {
+		t.Parallel()
+
+		sr := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+		expectedErr := xerrors.New("expected error")
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{}, harness.WithTracerProvider(tp))
+		r1 := h.AddRun("test", "1", fakeTestFns(nil, nil))
+		_ = h.AddRun("test", "2", testFns{
+			RunFn: func(_ context.Context, _ string, _ io.Writer) error {
+				t.Error("run should not be called after setup fails")
+				return nil
+			},
+			SetupFn: func(_ context.Context, _ string, _ io.Writer) error {
+				return expectedErr
+			},
+		})
+
+		err := h.Run(context.Background())
+		require.NoError(t, err)
+		err = h.Cleanup(context.Background())
+		require.NoError(t, err)
+
+		res := h.Results()
+		require.Equal(t, 1, res.TotalFail)
+		require.ErrorIs(t, res.Runs["test/2"].Error, expectedErr)
+		require.Contains(t, r1.Result().Logs, "Trace ID: ")
+
+		spans := map[string]sdktrace.ReadOnlySpan{}
+		for _, span := range sr.Ended() {
+			spans[span.Name()] = span
+		}
+		require.Len(t, spans, 7)
+
+		root := spans["test/1"]
+		require.NotNil(t, root)
+		assert.Equal(t, codes.Unset, root.Status().Code)
+		for _, phase := range []string{"setup", "run", "cleanup"} {
+			span := spans["test/1 "+phase]
+			require.NotNil(t, span, phase)
+			assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID(), phase)
+			assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID(), phase)
+		}
+
+		assert.Equal(t, codes.Error, spans["test/2"].Status().Code)
+		assert.Equal(t, codes.Error, spans["test/2 setup"].Status().Code)
+		assert.NotContains(t, spans, "test/2 run")
+		assert.NotEqual(t, root.SpanContext().TraceID(), spans["test/2"].SpanContext().TraceID())
+	})
+
 	t.Run("Panics", func(t *testing.T) {
 		t.Parallel()
 
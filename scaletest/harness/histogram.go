@@ -0,0 +1,163 @@
+package harness
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+const (
+	// histogramSubBuckets is the number of linear sub-buckets per power of
+	// two. Values are recorded with a relative error of at most
+	// 1/(histogramSubBuckets/2), i.e. ~1.6%.
+	histogramSubBuckets     = 128
+	histogramHalfSubBuckets = histogramSubBuckets / 2
+	histogramSubBucketBits  = 7
+	// Enough buckets to cover every non-negative int64.
+	histogramBuckets = histogramSubBuckets + (63-histogramSubBucketBits+1)*histogramHalfSubBuckets
+)
+
+// Histogram records durations into log-linear buckets in the style of an HDR
+// histogram. Memory usage is constant regardless of how many values are
+// recorded, and quantiles are accurate to within ~1.6%. Values are recorded
+// with microsecond resolution.
+//
+// Histogram is safe for concurrent use.
+type Histogram struct {
+	mut    sync.Mutex
+	counts [histogramBuckets]int64
+	count  int64
+	max    time.Duration
+}
+
+// NewHistogram creates an empty Histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// Record adds the given duration to the histogram. Negative durations are
+// recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.counts[histogramIndex(d.Microseconds())]++
+	h.count++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() int64 {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	return h.count
+}
+
+// Max returns the exact largest recorded value.
+func (h *Histogram) Max() time.Duration {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	return h.max
+}
+
+// Quantile returns the value at the given quantile (0 < q <= 1). The returned
+// value is never larger than Max.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	return h.quantile(q)
+}
+
+func (h *Histogram) quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	target := int64(math.Ceil(q * float64(h.count)))
+	target = max(target, 1)
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			return min(time.Duration(histogramValue(i))*time.Microsecond, h.max)
+		}
+	}
+	return h.max
+}
+
+// LatencySummary is a summary of the latencies recorded in a Histogram.
+type LatencySummary struct {
+	Count  int64            `json:"count"`
+	P50    httpapi.Duration `json:"p50"`
+	P90    httpapi.Duration `json:"p90"`
+	P95    httpapi.Duration `json:"p95"`
+	P99    httpapi.Duration `json:"p99"`
+	P999   httpapi.Duration `json:"p99_9"`
+	Max    httpapi.Duration `json:"max"`
+	P50MS  int64            `json:"p50_ms"`
+	P90MS  int64            `json:"p90_ms"`
+	P95MS  int64            `json:"p95_ms"`
+	P99MS  int64            `json:"p99_ms"`
+	P999MS int64            `json:"p99_9_ms"`
+	MaxMS  int64            `json:"max_ms"`
+}
+
+// Summary returns the commonly reported quantiles of the histogram.
+func (h *Histogram) Summary() LatencySummary {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	var (
+		p50  = h.quantile(0.5)
+		p90  = h.quantile(0.9)
+		p95  = h.quantile(0.95)
+		p99  = h.quantile(0.99)
+		p999 = h.quantile(0.999)
+	)
+	return LatencySummary{
+		Count:  h.count,
+		P50:    httpapi.Duration(p50),
+		P90:    httpapi.Duration(p90),
+		P95:    httpapi.Duration(p95),
+		P99:    httpapi.Duration(p99),
+		P999:   httpapi.Duration(p999),
+		Max:    httpapi.Duration(h.max),
+		P50MS:  p50.Milliseconds(),
+		P90MS:  p90.Milliseconds(),
+		P95MS:  p95.Milliseconds(),
+		P99MS:  p99.Milliseconds(),
+		P999MS: p999.Milliseconds(),
+		MaxMS:  h.max.Milliseconds(),
+	}
+}
+
+// histogramIndex returns the bucket index for the given non-negative value.
+// Values below histogramSubBuckets get a bucket each, after which every power
+// of two is split into histogramHalfSubBuckets linear buckets.
+func histogramIndex(v int64) int {
+	if v < histogramSubBuckets {
+		return int(v)
+	}
+	// #nosec G115 - v is non-negative.
+	shift := bits.Len64(uint64(v)) - histogramSubBucketBits
+	return histogramSubBuckets + (shift-1)*histogramHalfSubBuckets + int(v>>shift) - histogramHalfSubBuckets
+}
+
+// histogramValue returns the highest value that maps to the given bucket index.
+func histogramValue(i int) int64 {
+	if i < histogramSubBuckets {
+		return int64(i)
+	}
+	i -= histogramSubBuckets
+	shift := i/histogramHalfSubBuckets + 1
+	sub := int64(i%histogramHalfSubBuckets + histogramHalfSubBuckets)
+	return (sub+1)<<shift - 1
+}
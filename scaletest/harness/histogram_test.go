@@ -0,0 +1,71 @@
+package harness_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Histogram(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewHistogram()
+		require.Zero(t, h.Count())
+		require.Zero(t, h.Max())
+		require.Zero(t, h.Quantile(0.5))
+	})
+
+	t.Run("Quantiles", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewHistogram()
+		var wg sync.WaitGroup
+		for i := 1; i <= 10000; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				h.Record(time.Duration(i) * time.Millisecond)
+			}()
+		}
+		wg.Wait()
+
+		require.EqualValues(t, 10000, h.Count())
+		require.Equal(t, 10*time.Second, h.Max())
+
+		// Quantiles are accurate to within ~1.6%.
+		for q, want := range map[float64]time.Duration{
+			0.5:   5 * time.Second,
+			0.9:   9 * time.Second,
+			0.99:  9900 * time.Millisecond,
+			0.999: 9990 * time.Millisecond,
+		} {
+			got := h.Quantile(q)
+			assert.InEpsilon(t, want, got, 0.016, "quantile %v", q)
+			assert.LessOrEqual(t, got, h.Max(), "quantile %v", q)
+		}
+
+		summary := h.Summary()
+		require.EqualValues(t, 10000, summary.Count)
+		require.Equal(t, h.Quantile(0.99).Milliseconds(), summary.P99MS)
+		require.EqualValues(t, 10000, summary.MaxMS)
+	})
+
+	t.Run("SmallValuesExact", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewHistogram()
+		for i := 1; i <= 100; i++ {
+			h.Record(time.Duration(i) * time.Microsecond)
+		}
+		require.Equal(t, 50*time.Microsecond, h.Quantile(0.5))
+		require.Equal(t, 99*time.Microsecond, h.Quantile(0.99))
+	})
+}
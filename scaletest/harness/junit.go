@@ -0,0 +1,153 @@
+package harness
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+)
+
+// junitTestSuites is the root element of a JUnit XML report. The format isn't
+// formally specified, so we stick to the subset of elements and attributes
+// that is understood by the common CI report parsers.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr,omitempty"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr,omitempty"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr,omitempty"`
+	Contents string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results as a JUnit XML report to the given writer.
+// Each test name becomes a test suite and each run becomes a test case within
+// it, so that CI systems can display scaletest results in their native test
+// report UIs.
+func (r *Results) WriteJUnit(w io.Writer) error {
+	suitesByName := map[string]*junitTestSuite{}
+	suiteStart := map[string]time.Time{}
+	suiteDuration := map[string]time.Duration{}
+
+	keys := maps.Keys(r.Runs)
+	slices.Sort(keys)
+	for _, key := range keys {
+		run := r.Runs[key]
+		suite, ok := suitesByName[run.TestName]
+		if !ok {
+			suite = &junitTestSuite{Name: run.TestName}
+			suitesByName[run.TestName] = suite
+		}
+
+		tc := junitTestCase{
+			ClassName: run.TestName,
+			Name:      run.ID,
+			Time:      junitSeconds(time.Duration(run.Duration)),
+			SystemOut: run.Logs,
+		}
+		switch {
+		case run.Skipped:
+			reason := run.SkipReason
+			if reason == "" {
+				reason = "test was interrupted"
+			}
+			tc.Skipped = &junitSkipped{Message: "not started, " + reason}
+			suite.Skipped++
+		case run.Warmup:
+			// Warmup runs aren't part of the results, so report them as
+			// skipped rather than hiding them entirely.
+			tc.Skipped = &junitSkipped{Message: "warmup run"}
+			suite.Skipped++
+		case run.Error != nil:
+			tc.Failure = &junitFailure{
+				Message:  run.Error.Error(),
+				Contents: fmt.Sprintf("%+v", run.Error),
+			}
+			switch {
+			case run.TimedOut:
+				tc.Failure.Type = "timeout"
+			case run.TestTimedOut:
+				tc.Failure.Type = "test_timeout"
+			}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+
+		if start, ok := suiteStart[run.TestName]; !run.StartedAt.IsZero() && (!ok || run.StartedAt.Before(start)) {
+			suiteStart[run.TestName] = run.StartedAt
+		}
+		suiteDuration[run.TestName] += time.Duration(run.Duration)
+	}
+
+	report := junitTestSuites{
+		Name:     "scaletest",
+		Tests:    r.TotalRuns + r.TotalWarmup + r.TotalSkipped,
+		Failures: r.TotalFail,
+		Skipped:  r.TotalWarmup + r.TotalSkipped,
+		Time:     junitSeconds(time.Duration(r.Elapsed)),
+	}
+	names := maps.Keys(suitesByName)
+	slices.Sort(names)
+	for _, name := range names {
+		suite := suitesByName[name]
+		suite.Time = junitSeconds(suiteDuration[name])
+		if start, ok := suiteStart[name]; ok {
+			suite.Timestamp = start.UTC().Format("2006-01-02T15:04:05")
+		}
+		report.Suites = append(report.Suites, *suite)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return xerrors.Errorf("write XML header: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	err = enc.Encode(report)
+	if err != nil {
+		return xerrors.Errorf("encode JUnit XML: %w", err)
+	}
+	_, err = io.WriteString(w, "\n")
+	if err != nil {
+		return xerrors.Errorf("write trailing newline: %w", err)
+	}
+	return nil
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,76 @@
+package harness_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Results_WriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 10, 5, 12, 3, 56, 0, time.UTC)
+	results := harness.Results{
+		TotalRuns: 3,
+		TotalPass: 2,
+		TotalFail: 1,
+		Elapsed:   httpapi.Duration(2500 * time.Millisecond),
+		Runs: map[string]harness.RunResult{
+			"test-0/0": {
+				FullID:    "test-0/0",
+				TestName:  "test-0",
+				ID:        "0",
+				Logs:      "ok",
+				StartedAt: now,
+				Duration:  httpapi.Duration(time.Second),
+			},
+			"test-0/1": {
+				FullID:    "test-0/1",
+				TestName:  "test-0",
+				ID:        "1",
+				Logs:      "<failed>",
+				Error:     testError{hidden: xerrors.New("boom")},
+				StartedAt: now.Add(-time.Second),
+				Duration:  httpapi.Duration(1500 * time.Millisecond),
+			},
+			"test-1/0": {
+				FullID:    "test-1/0",
+				TestName:  "test-1",
+				ID:        "0",
+				StartedAt: now,
+				Duration:  httpapi.Duration(250 * time.Millisecond),
+			},
+		},
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="scaletest" tests="3" failures="1" time="2.500">
+	<testsuite name="test-0" tests="2" failures="1" time="2.500" timestamp="2023-10-05T12:03:55">
+		<testcase classname="test-0" name="0" time="1.000">
+			<system-out>ok</system-out>
+		</testcase>
+		<testcase classname="test-0" name="1" time="1.500">
+			<failure message="boom">boom</failure>
+			<system-out>&lt;failed&gt;</system-out>
+		</testcase>
+	</testsuite>
+	<testsuite name="test-1" tests="1" failures="0" time="0.250" timestamp="2023-10-05T12:03:56">
+		<testcase classname="test-1" name="0" time="0.250"></testcase>
+	</testsuite>
+</testsuites>
+`
+
+	out := bytes.NewBuffer(nil)
+	err := results.WriteJUnit(out)
+	require.NoError(t, err)
+
+	assert.Empty(t, cmp.Diff(want, out.String()), "JUnit result does not match (-want +got)")
+}
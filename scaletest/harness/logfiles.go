@@ -0,0 +1,186 @@
+package harness
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// logFileExt is the extension of the log files written by WithLogDir.
+const logFileExt = ".jsonl"
+
+// Phases of a test run recorded in its log file.
+const (
+	LogPhaseRun     = "run"
+	LogPhaseCleanup = "cleanup"
+)
+
+// WithLogDir writes the logs of every test run to its own file under the given
+// directory, at the path of the run's full ID with a .jsonl extension, in
+// addition to collecting them for the results. Every line of the logs is
+// written as a LogEntry, so that the logs of large tests can be analyzed
+// afterwards without loading all of them at once.
+func WithLogDir(dir string) Option {
+	return func(h *TestHarness) {
+		h.logDir = dir
+	}
+}
+
+// LogEntry is a line of the logs of a test run, as written to its log file.
+type LogEntry struct {
+	Time time.Time `json:"time"`
+	// Level is the level of the line if it was logged by a slog human sink,
+	// e.g. "info" or "error", and "info" otherwise.
+	Level string `json:"level"`
+	// Run is the full ID of the test run.
+	Run   string `json:"run"`
+	Phase string `json:"phase"`
+	Msg   string `json:"msg"`
+}
+
+// humanLevels maps the levels printed by slog human sinks to the level of a
+// LogEntry.
+var humanLevels = []struct {
+	bracketed []byte
+	level     string
+}{
+	{[]byte("[debu]"), "debug"},
+	{[]byte("[info]"), "info"},
+	{[]byte("[warn]"), "warn"},
+	{[]byte("[erro]"), "error"},
+	{[]byte("[crit]"), "critical"},
+	{[]byte("[fata]"), "fatal"},
+}
+
+// logLevel returns the level of a log line.
+func logLevel(line []byte) string {
+	// The level follows the timestamp at the start of the line.
+	head := line[:min(len(line), 64)]
+	for _, l := range humanLevels {
+		if bytes.Contains(head, l.bracketed) {
+			return l.level
+		}
+	}
+	return "info"
+}
+
+// startLogFile starts writing the logs of the run to its log file for the
+// given phase, if it has one, and returns a function that stops it.
+func (r *TestRun) startLogFile(phase string) (stop func()) {
+	if r.logFilePath == "" {
+		return func() {}
+	}
+	f, err := openLogFile(r.logFilePath, r.FullID(), phase)
+	if err != nil {
+		_, _ = fmt.Fprintf(r.logs, "Failed to open log file: %v\n\n", err)
+		return func() {}
+	}
+	r.logs.setFile(f)
+	return func() {
+		r.logs.setFile(nil)
+		_ = f.Close()
+	}
+}
+
+// logFile writes complete lines written to it as JSON log entries to a file.
+// Incomplete lines are buffered until they are completed or the file is
+// closed.
+type logFile struct {
+	run   string
+	phase string
+
+	mut     sync.Mutex
+	f       *os.File
+	bw      *bufio.Writer
+	enc     *json.Encoder
+	partial []byte
+}
+
+// openLogFile opens the log file at the given path for the given phase of a
+// test run. The file is truncated for the run phase and appended to for
+// later phases.
+func openLogFile(path, run, phase string) (*logFile, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, xerrors.Errorf("create log directory: %w", err)
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if phase == LogPhaseRun {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return nil, xerrors.Errorf("open log file: %w", err)
+	}
+	bw := bufio.NewWriter(f)
+	return &logFile{
+		run:   run,
+		phase: phase,
+		f:     f,
+		bw:    bw,
+		enc:   json.NewEncoder(bw),
+	}, nil
+}
+
+func (l *logFile) Write(p []byte) (int, error) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	if l.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	l.partial = append(l.partial, p...)
+	now := time.Now()
+	for {
+		i := bytes.IndexByte(l.partial, '\n')
+		if i < 0 {
+			break
+		}
+		err := l.writeEntry(now, l.partial[:i])
+		l.partial = l.partial[i+1:]
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (l *logFile) writeEntry(t time.Time, line []byte) error {
+	return l.enc.Encode(LogEntry{
+		Time:  t,
+		Level: logLevel(line),
+		Run:   l.run,
+		Phase: l.phase,
+		Msg:   string(line),
+	})
+}
+
+// Close writes any buffered incomplete line and closes the file.
+func (l *logFile) Close() error {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	if l.f == nil {
+		return nil
+	}
+
+	var err error
+	if len(l.partial) > 0 {
+		err = l.writeEntry(time.Now(), l.partial)
+		l.partial = nil
+	}
+	if flushErr := l.bw.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := l.f.Close(); err == nil {
+		err = closeErr
+	}
+	l.f = nil
+	return err
+}
@@ -0,0 +1,81 @@
+package harness_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_LogDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	h := harness.NewTestHarness(
+		skipOddStrategy{},
+		harness.LinearExecutionStrategy{},
+		harness.WithLogDir(dir),
+	)
+	for _, id := range []string{"0", "1"} {
+		h.AddRun("test", id, testFns{
+			RunFn: func(ctx context.Context, id string, logs io.Writer) error {
+				logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+				logger.Warn(ctx, "warning from "+id)
+				_, _ = io.WriteString(logs, "plain line\nunterminated")
+				return nil
+			},
+			CleanupFn: func(_ context.Context, id string, logs io.Writer) error {
+				_, _ = io.WriteString(logs, "cleaning up "+id+"\n")
+				return nil
+			},
+		})
+	}
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+	err = h.Cleanup(context.Background())
+	require.NoError(t, err)
+
+	f, err := os.Open(filepath.Join(dir, "test", "0.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+	var entries []harness.LogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry harness.LogEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		require.Equal(t, "test/0", entry.Run)
+		require.False(t, entry.Time.IsZero())
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, entries, 4)
+	require.Equal(t, "warn", entries[0].Level)
+	require.Contains(t, entries[0].Msg, "warning from 0")
+	require.Equal(t, harness.LogPhaseRun, entries[0].Phase)
+	require.Equal(t, "info", entries[1].Level)
+	require.Equal(t, "plain line", entries[1].Msg)
+	// Incomplete lines are written when the phase ends.
+	require.Equal(t, "unterminated", entries[2].Msg)
+	require.Equal(t, harness.LogPhaseRun, entries[2].Phase)
+	require.Equal(t, "cleaning up 0", entries[3].Msg)
+	require.Equal(t, harness.LogPhaseCleanup, entries[3].Phase)
+
+	// Logs are still collected for the results.
+	res := h.Results()
+	require.Contains(t, res.Runs["test/0"].Logs, "plain line")
+
+	// Skipped runs have no log file.
+	_, err = os.Stat(filepath.Join(dir, "test", "1.jsonl"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
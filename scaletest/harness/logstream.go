@@ -0,0 +1,85 @@
+package harness
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// WithLogStream streams the logs of every test run to the given writer in real
+// time, in addition to collecting them for the results. Every line is prefixed
+// with the full ID of the run it belongs to, and lines from concurrent runs are
+// never interleaved. This is useful to follow long-running tests, e.g. soak
+// tests, while they are in progress.
+func WithLogStream(w io.Writer) Option {
+	return func(h *TestHarness) {
+		h.logStream = &lockedWriter{w: w}
+	}
+}
+
+// lockedWriter serializes writes to the underlying writer.
+type lockedWriter struct {
+	mut sync.Mutex
+	w   io.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mut.Lock()
+	defer lw.mut.Unlock()
+	return lw.w.Write(p)
+}
+
+// linePrefixWriter writes complete lines to the underlying writer, each
+// prefixed with a fixed string. Incomplete lines are buffered until they are
+// completed or flushed.
+type linePrefixWriter struct {
+	prefix []byte
+	w      io.Writer
+
+	mut     sync.Mutex
+	partial []byte
+}
+
+func newLinePrefixWriter(w io.Writer, prefix string) *linePrefixWriter {
+	return &linePrefixWriter{
+		prefix: []byte(prefix),
+		w:      w,
+	}
+}
+
+func (pw *linePrefixWriter) Write(p []byte) (int, error) {
+	pw.mut.Lock()
+	defer pw.mut.Unlock()
+
+	pw.partial = append(pw.partial, p...)
+	var out []byte
+	for {
+		i := bytes.IndexByte(pw.partial, '\n')
+		if i < 0 {
+			break
+		}
+		out = append(out, pw.prefix...)
+		out = append(out, pw.partial[:i+1]...)
+		pw.partial = pw.partial[i+1:]
+	}
+	if len(out) > 0 {
+		// Write all complete lines at once so they stay together.
+		if _, err := pw.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered incomplete line, terminated by a newline.
+func (pw *linePrefixWriter) Flush() {
+	pw.mut.Lock()
+	defer pw.mut.Unlock()
+	if len(pw.partial) == 0 {
+		return
+	}
+
+	out := append(append([]byte{}, pw.prefix...), pw.partial...)
+	_, _ = pw.w.Write(append(out, '\n'))
+	pw.partial = nil
+}
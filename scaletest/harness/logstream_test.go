@@ -0,0 +1,54 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_LogStream(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	h := harness.NewTestHarness(
+		harness.ConcurrentExecutionStrategy{},
+		harness.LinearExecutionStrategy{},
+		harness.WithLogStream(&out),
+	)
+	for _, id := range []string{"1", "2"} {
+		h.AddRun("test", id, testFns{
+			RunFn: func(_ context.Context, id string, logs io.Writer) error {
+				_, _ = io.WriteString(logs, "first line from "+id+"\nsecond ")
+				_, _ = io.WriteString(logs, "line from "+id+"\nunterminated")
+				return nil
+			},
+			CleanupFn: func(_ context.Context, id string, logs io.Writer) error {
+				_, _ = io.WriteString(logs, "cleaning up "+id+"\n")
+				return nil
+			},
+		})
+	}
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+	err = h.Cleanup(context.Background())
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.ElementsMatch(t, []string{
+		"[test/1] first line from 1",
+		"[test/1] second line from 1",
+		"[test/1] unterminated",
+		"[test/1] cleaning up 1",
+		"[test/2] first line from 2",
+		"[test/2] second line from 2",
+		"[test/2] unterminated",
+		"[test/2] cleaning up 2",
+	}, lines)
+}
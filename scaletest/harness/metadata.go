@@ -0,0 +1,70 @@
+package harness
+
+import "context"
+
+// Shard identifies the part of a test that a harness runs when the same test is
+// split across multiple load generators, each running its own harness.
+type Shard struct {
+	// Index is the index of the shard, from 0 to Count-1.
+	Index int `json:"index"`
+	// Count is the total number of shards.
+	Count int `json:"count"`
+}
+
+// WithShard records that the harness runs the given shard of a test. Runners
+// can use it through Metadata, e.g. to pick distinct resources on every load
+// generator.
+func WithShard(shard Shard) Option {
+	return func(h *TestHarness) {
+		h.shard = shard
+	}
+}
+
+// RunMetadata describes a test run of a harness and its position in the test.
+type RunMetadata struct {
+	TestName string
+	ID       string
+	// Index is the index of the run in the order runs were registered with
+	// the harness, from 0 to Total-1. It doesn't depend on the order the
+	// execution strategy starts runs in. For duration tests, it is the order
+	// the runs were started in.
+	Index int
+	// Total is the number of runs registered with the harness. It is zero for
+	// duration tests, where the number of runs is not known upfront.
+	Total int
+	// Shard is the shard of the test that the harness runs. It is the zero
+	// value if the test is not sharded.
+	Shard Shard
+	// Seed is the seed of the harness. See WithSeed.
+	Seed int64
+}
+
+// shardResult returns the shard to record in the results, if the test is
+// sharded.
+func (h *TestHarness) shardResult() *Shard {
+	if h.shard.Count == 0 {
+		return nil
+	}
+	shard := h.shard
+	return &shard
+}
+
+type runMetadataKey struct{}
+
+// Metadata returns the metadata of the test run that the context belongs to,
+// so that runners can vary their behavior deterministically, e.g. by doing a
+// heavier operation in every 10th run. It is available to the setup, run and
+// cleanup phases of runs executed by a TestHarness.
+func Metadata(ctx context.Context) (RunMetadata, bool) {
+	md, ok := ctx.Value(runMetadataKey{}).(RunMetadata)
+	return md, ok
+}
+
+// withMetadata returns a context carrying the metadata of the test run, if it
+// was set by a harness.
+func (r *TestRun) withMetadata(ctx context.Context) context.Context {
+	if r.metadata == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, runMetadataKey{}, *r.metadata)
+}
@@ -0,0 +1,78 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Metadata(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mut        sync.Mutex
+		runMeta    = map[string]harness.RunMetadata{}
+		cleanupIDs = map[string]int{}
+	)
+	h := harness.NewTestHarness(
+		harness.ShuffleExecutionStrategyWrapper{Inner: harness.ConcurrentExecutionStrategy{}},
+		harness.LinearExecutionStrategy{},
+		harness.WithSeed(42),
+		harness.WithShard(harness.Shard{Index: 1, Count: 3}),
+	)
+	for i := range 5 {
+		h.AddRun("test", strconv.Itoa(i), testFns{
+			RunFn: func(ctx context.Context, id string, _ io.Writer) error {
+				md, ok := harness.Metadata(ctx)
+				assert.True(t, ok)
+				mut.Lock()
+				defer mut.Unlock()
+				runMeta[id] = md
+				return nil
+			},
+			CleanupFn: func(ctx context.Context, id string, _ io.Writer) error {
+				md, ok := harness.Metadata(ctx)
+				assert.True(t, ok)
+				mut.Lock()
+				defer mut.Unlock()
+				cleanupIDs[id] = md.Index
+				return nil
+			},
+		})
+	}
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+	err = h.Cleanup(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, runMeta, 5)
+	for i := range 5 {
+		id := strconv.Itoa(i)
+		// The index is the registration order, even though the runs were
+		// shuffled.
+		require.Equal(t, harness.RunMetadata{
+			TestName: "test",
+			ID:       id,
+			Index:    i,
+			Total:    5,
+			Shard:    harness.Shard{Index: 1, Count: 3},
+			Seed:     42,
+		}, runMeta[id])
+		require.Equal(t, i, cleanupIDs[id])
+	}
+
+	res := h.Results()
+	require.Equal(t, &harness.Shard{Index: 1, Count: 3}, res.Shard)
+
+	// Runs outside of a harness have no metadata.
+	_, ok := harness.Metadata(context.Background())
+	require.False(t, ok)
+}
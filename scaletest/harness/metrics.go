@@ -0,0 +1,149 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+
+	"golang.org/x/exp/maps"
+)
+
+// NumericCollectable is an optional extension to Runnable that exposes numeric
+// metrics from the runner, e.g. time to first byte, build queue wait or
+// reconnect count. Unlike the metrics exposed by Collectable, these are
+// summarized across runs in the results and included as columns in exports.
+type NumericCollectable interface {
+	Runnable
+	GetNumericMetrics() map[string]float64
+}
+
+// MetricSummary summarizes the values a numeric metric took across runs.
+type MetricSummary struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+}
+
+// metricSummaries summarizes the numeric metrics of the given (non-warmup) run
+// results, keyed by metric name.
+func metricSummaries(runs []RunResult) map[string]MetricSummary {
+	metrics := map[string]*metricAggregate{}
+	for _, run := range runs {
+		addMetrics(metrics, run.NumericMetrics, 0, nil)
+	}
+	return summarizeMetrics(metrics)
+}
+
+// metricAggregate accumulates the values of a numeric metric. Count, Min, Max
+// and Mean are exact. Quantiles are computed from a uniform random sample of
+// the values if their number exceeds the sample size.
+type metricAggregate struct {
+	count    int
+	min, max float64
+	sum      float64
+	samples  []float64
+}
+
+// addMetrics adds the given metric values to the aggregates keyed by metric
+// name. maxSamples bounds the number of values sampled per metric, where zero
+// keeps every value, and rng picks the samples once the bound is reached.
+func addMetrics(metrics map[string]*metricAggregate, values map[string]float64, maxSamples int, rng *rand.Rand) {
+	for name, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		m, ok := metrics[name]
+		if !ok {
+			m = &metricAggregate{min: v, max: v}
+			metrics[name] = m
+		}
+		m.count++
+		m.sum += v
+		m.min = min(m.min, v)
+		m.max = max(m.max, v)
+		if maxSamples <= 0 || len(m.samples) < maxSamples {
+			m.samples = append(m.samples, v)
+			continue
+		}
+		// Reservoir sampling keeps every value with equal probability.
+		if i := rng.Intn(m.count); i < maxSamples {
+			m.samples[i] = v
+		}
+	}
+}
+
+// summarizeMetrics returns the summaries of the given aggregates, or nil if
+// there are none.
+func summarizeMetrics(metrics map[string]*metricAggregate) map[string]MetricSummary {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	summaries := make(map[string]MetricSummary, len(metrics))
+	for name, m := range metrics {
+		vs := slices.Clone(m.samples)
+		slices.Sort(vs)
+		mean := m.sum / float64(m.count)
+		if len(vs) == m.count {
+			// Sum the sorted values for a stable result.
+			var sum float64
+			for _, v := range vs {
+				sum += v
+			}
+			mean = sum / float64(len(vs))
+		}
+		summaries[name] = MetricSummary{
+			Count: m.count,
+			Min:   m.min,
+			Max:   m.max,
+			Mean:  mean,
+			P50:   metricQuantile(vs, 0.5),
+			P95:   metricQuantile(vs, 0.95),
+			P99:   metricQuantile(vs, 0.99),
+		}
+	}
+	return summaries
+}
+
+// metricQuantile returns the value at the given quantile (0 < q <= 1) of the
+// sorted, non-empty values using the nearest-rank method.
+func metricQuantile(sorted []float64, q float64) float64 {
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	rank = max(rank, 1)
+	return sorted[rank-1]
+}
+
+// metricNames returns the sorted names of all numeric metrics reported by any
+// of the runs.
+func (r *Results) metricNames() []string {
+	names := map[string]struct{}{}
+	for _, run := range r.Runs {
+		for name := range run.NumericMetrics {
+			names[name] = struct{}{}
+		}
+	}
+	keys := maps.Keys(names)
+	slices.Sort(keys)
+	return keys
+}
+
+func (r *Results) printMetrics(w io.Writer) {
+	names := maps.Keys(r.Metrics)
+	slices.Sort(names)
+	for _, name := range names {
+		m := r.Metrics[name]
+		_, _ = fmt.Fprintf(w, "\n\tMetric (%s, %d runs):\n", name, m.Count)
+		_, _ = fmt.Fprintf(w, "\t\tMean: %g\n", m.Mean)
+		_, _ = fmt.Fprintf(w, "\t\tMin:  %g\n", m.Min)
+		_, _ = fmt.Fprintf(w, "\t\tP50:  %g\n", m.P50)
+		_, _ = fmt.Fprintf(w, "\t\tP95:  %g\n", m.P95)
+		_, _ = fmt.Fprintf(w, "\t\tP99:  %g\n", m.P99)
+		_, _ = fmt.Fprintf(w, "\t\tMax:  %g\n", m.Max)
+	}
+}
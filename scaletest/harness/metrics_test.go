@@ -0,0 +1,99 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// numericTestFns implements NumericCollectable.
+type numericTestFns struct {
+	testFns
+	metrics map[string]float64
+}
+
+var _ harness.NumericCollectable = numericTestFns{}
+
+// GetNumericMetrics implements NumericCollectable.
+func (fns numericTestFns) GetNumericMetrics() map[string]float64 {
+	return fns.metrics
+}
+
+func Test_NumericMetrics(t *testing.T) {
+	t.Parallel()
+
+	h := harness.NewTestHarness(
+		harness.LinearExecutionStrategy{},
+		harness.LinearExecutionStrategy{},
+		harness.WithWarmup(harness.Warmup{Runs: 1}),
+	)
+	// The warmup run is excluded from the summary.
+	h.AddRun("test", "warmup", numericTestFns{
+		testFns: fakeTestFns(nil, nil),
+		metrics: map[string]float64{"ttfb_seconds": 1000},
+	})
+	for i := 1; i <= 4; i++ {
+		h.AddRun("test", strconv.Itoa(i), numericTestFns{
+			testFns: fakeTestFns(nil, nil),
+			metrics: map[string]float64{"ttfb_seconds": float64(i)},
+		})
+	}
+	h.AddRun("test", "reconnects", numericTestFns{
+		testFns: fakeTestFns(nil, nil),
+		metrics: map[string]float64{"reconnect_count": 3},
+	})
+	h.AddRun("test", "none", fakeTestFns(nil, nil))
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+
+	res := h.Results()
+	require.Equal(t, map[string]harness.MetricSummary{
+		"ttfb_seconds": {
+			Count: 4,
+			Min:   1,
+			Max:   4,
+			Mean:  2.5,
+			P50:   2,
+			P95:   4,
+			P99:   4,
+		},
+		"reconnect_count": {
+			Count: 1,
+			Min:   3,
+			Max:   3,
+			Mean:  3,
+			P50:   3,
+			P95:   3,
+			P99:   3,
+		},
+	}, res.Metrics)
+	require.Equal(t, map[string]float64{"ttfb_seconds": 2}, res.Runs["test/2"].NumericMetrics)
+
+	var buf bytes.Buffer
+	res.PrintText(&buf)
+	require.Contains(t, buf.String(), "Metric (ttfb_seconds, 4 runs):\n\t\tMean: 2.5\n")
+
+	buf.Reset()
+	err = res.WriteCSV(&buf)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 8)
+	require.True(t, strings.HasSuffix(lines[0], ",bytes_read,bytes_written,reconnect_count,ttfb_seconds"))
+	for _, line := range lines[1:] {
+		switch {
+		case strings.HasPrefix(line, "test/2,"):
+			require.True(t, strings.HasSuffix(line, ",,,,2"), line)
+		case strings.HasPrefix(line, "test/reconnects,"):
+			require.True(t, strings.HasSuffix(line, ",,,3,"), line)
+		case strings.HasPrefix(line, "test/none,"):
+			require.True(t, strings.HasSuffix(line, ",,,,"), line)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package harness
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+// phaseOrder is the order the phases of the harness are executed and printed
+// in. Phases marked by runners with StartPhase are printed afterwards.
+var phaseOrder = []string{"setup", "verify", "run"}
+
+type testRunKey struct{}
+
+// StartPhase marks the start of a named phase of the test run that the context
+// belongs to, e.g. "connect" or "traffic", and returns a function that marks
+// its end. The durations of phases are recorded in the results alongside the
+// setup, verify and run phases of the harness, and summarized per test, so that
+// it's visible where the time of runs is spent. If a phase is marked more than
+// once during an attempt, e.g. once per request, its durations are summed.
+//
+// StartPhase is safe to call concurrently. The returned function is a no-op
+// if the context doesn't belong to a test run.
+func StartPhase(ctx context.Context, name string) (end func()) {
+	r, ok := ctx.Value(testRunKey{}).(*TestRun)
+	if !ok {
+		return func() {}
+	}
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.addPhase(name, time.Since(start))
+		})
+	}
+}
+
+// addPhase adds the given duration to the phase of the current attempt.
+func (r *TestRun) addPhase(phase string, d time.Duration) {
+	r.phasesMu.Lock()
+	defer r.phasesMu.Unlock()
+	if r.phases == nil {
+		r.phases = map[string]time.Duration{}
+	}
+	r.phases[phase] += d
+}
+
+// sortPhases sorts the phases of the harness in the order they are executed,
+// followed by the phases marked by runners sorted by name.
+func sortPhases(phases []string) {
+	slices.SortFunc(phases, func(a, b string) int {
+		ai, bi := slices.Index(phaseOrder, a), slices.Index(phaseOrder, b)
+		switch {
+		case ai >= 0 && bi >= 0:
+			return cmp.Compare(ai, bi)
+		case ai >= 0:
+			return -1
+		case bi >= 0:
+			return 1
+		default:
+			return cmp.Compare(a, b)
+		}
+	})
+}
+
+// recordPhaseLatency adds the durations of the phases of the given finished
+// run to the phase latency histograms for its test name.
+func (h *TestHarness) recordPhaseLatency(run *TestRun) {
+	durations := run.phaseDurations()
+	if len(durations) == 0 {
+		return
+	}
+
+	h.mut.Lock()
+	phases, ok := h.phaseLatencies[run.testName]
+	if !ok {
+		phases = map[string]*Histogram{}
+		h.phaseLatencies[run.testName] = phases
+	}
+	hists := make(map[string]*Histogram, len(durations))
+	for phase := range durations {
+		hist, ok := phases[phase]
+		if !ok {
+			hist = NewHistogram()
+			phases[phase] = hist
+		}
+		hists[phase] = hist
+	}
+	h.mut.Unlock()
+
+	for phase, hist := range hists {
+		hist.Record(time.Duration(durations[phase]))
+	}
+}
+
+// phaseDurations returns the durations of the phases of the run, if they were
+// recorded.
+func (r *TestRun) phaseDurations() map[string]httpapi.Duration {
+	r.phasesMu.Lock()
+	defer r.phasesMu.Unlock()
+	if len(r.phases) == 0 {
+		return nil
+	}
+	durations := make(map[string]httpapi.Duration, len(r.phases))
+	for phase, d := range r.phases {
+		durations[phase] = httpapi.Duration(d)
+	}
+	return durations
+}
+
+// printPhaseLatency prints the duration percentiles of the phases of every
+// test as human-readable text.
+func (r *Results) printPhaseLatency(w io.Writer) {
+	testNames := maps.Keys(r.PhaseLatency)
+	slices.Sort(testNames)
+	for _, testName := range testNames {
+		phases := r.PhaseLatency[testName]
+		_, _ = fmt.Fprintf(w, "\n\tPhases (%s):\n", testName)
+		names := maps.Keys(phases)
+		sortPhases(names)
+		for _, phase := range names {
+			l := phases[phase]
+			_, _ = fmt.Fprintf(w, "\t\t%s: p50 %s, p95 %s, max %s (%d runs)\n",
+				phase, time.Duration(l.P50), time.Duration(l.P95), time.Duration(l.Max), l.Count,
+			)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// verifyTestFns is a runner with setup and verify phases.
+type verifyTestFns struct {
+	testFns
+	SetupFn  func(ctx context.Context, id string, logs io.Writer) error
+	VerifyFn func(ctx context.Context, id string, logs io.Writer) error
+}
+
+var (
+	_ harness.Setupable  = verifyTestFns{}
+	_ harness.Verifiable = verifyTestFns{}
+)
+
+func (fns verifyTestFns) Setup(ctx context.Context, id string, logs io.Writer) error {
+	return fns.SetupFn(ctx, id, logs)
+}
+
+func (fns verifyTestFns) Verify(ctx context.Context, id string, logs io.Writer) error {
+	return fns.VerifyFn(ctx, id, logs)
+}
+
+// runOnlyFn is a runner without any optional extensions.
+type runOnlyFn func(ctx context.Context, id string, logs io.Writer) error
+
+func (fn runOnlyFn) Run(ctx context.Context, id string, logs io.Writer) error {
+	return fn(ctx, id, logs)
+}
+
+func Test_Verifiable(t *testing.T) {
+	t.Parallel()
+
+	sleep := func(d time.Duration) func(context.Context, string, io.Writer) error {
+		return func(context.Context, string, io.Writer) error {
+			time.Sleep(d)
+			return nil
+		}
+	}
+
+	t.Run("Phases", func(t *testing.T) {
+		t.Parallel()
+
+		var order []string
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		h.AddRun("traffic", "0", verifyTestFns{
+			testFns: testFns{RunFn: func(context.Context, string, io.Writer) error {
+				order = append(order, "run")
+				time.Sleep(30 * time.Millisecond)
+				return nil
+			}},
+			SetupFn: func(context.Context, string, io.Writer) error {
+				order = append(order, "setup")
+				return nil
+			},
+			VerifyFn: func(context.Context, string, io.Writer) error {
+				order = append(order, "verify")
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			},
+		})
+		// Runners without setup or verify phases don't record phases.
+		h.AddRun("other", "0", runOnlyFn(func(context.Context, string, io.Writer) error {
+			return nil
+		}))
+		require.NoError(t, h.Run(context.Background()))
+		require.Equal(t, []string{"setup", "verify", "run"}, order)
+
+		res := h.Results()
+		run := res.Runs["traffic/0"]
+		require.Len(t, run.Phases, 3)
+		require.GreaterOrEqual(t, time.Duration(run.Phases["verify"]), 10*time.Millisecond)
+		require.GreaterOrEqual(t, time.Duration(run.Phases["run"]), 30*time.Millisecond)
+		require.Less(t, time.Duration(run.Phases["run"]), time.Duration(run.Duration))
+		require.Empty(t, res.Runs["other/0"].Phases)
+
+		require.Len(t, res.PhaseLatency, 1)
+		require.Equal(t, int64(1), res.PhaseLatency["traffic"]["verify"].Count)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "Phases (traffic):")
+		require.Contains(t, out.String(), "verify: p50")
+	})
+
+	t.Run("VerifyFails", func(t *testing.T) {
+		t.Parallel()
+
+		ran := false
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		h.AddRun("traffic", "0", verifyTestFns{
+			testFns: testFns{RunFn: func(context.Context, string, io.Writer) error {
+				ran = true
+				return nil
+			}},
+			SetupFn: sleep(0),
+			VerifyFn: func(context.Context, string, io.Writer) error {
+				return xerrors.New("agent not connected")
+			},
+		})
+		require.NoError(t, h.Run(context.Background()))
+		require.False(t, ran)
+
+		res := h.Results()
+		require.Equal(t, 1, res.TotalFail)
+		require.ErrorContains(t, res.Runs["traffic/0"].Error, "verify: agent not connected")
+		require.Contains(t, res.Runs["traffic/0"].Phases, "verify")
+		require.NotContains(t, res.Runs["traffic/0"].Phases, "run")
+	})
+}
+
+func Test_StartPhase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		h.AddRun("traffic", "0", runOnlyFn(func(ctx context.Context, _ string, _ io.Writer) error {
+			end := harness.StartPhase(ctx, "connect")
+			time.Sleep(10 * time.Millisecond)
+			end()
+			// Ending a phase twice doesn't record it twice.
+			end()
+
+			var wg sync.WaitGroup
+			for range 2 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer harness.StartPhase(ctx, "traffic")()
+					time.Sleep(20 * time.Millisecond)
+				}()
+			}
+			wg.Wait()
+			return nil
+		}))
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		run := res.Runs["traffic/0"]
+		require.Len(t, run.Phases, 3)
+		require.GreaterOrEqual(t, time.Duration(run.Phases["connect"]), 10*time.Millisecond)
+		// The durations of concurrent phases are summed.
+		require.GreaterOrEqual(t, time.Duration(run.Phases["traffic"]), 40*time.Millisecond)
+		require.GreaterOrEqual(t, time.Duration(run.Phases["run"]), 30*time.Millisecond)
+		require.Equal(t, int64(1), res.PhaseLatency["traffic"]["connect"].Count)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		text := out.String()
+		require.Regexp(t, `(?s)Phases \(traffic\):\n\t\trun: p50 .*\n\t\tconnect: p50 .*\n\t\ttraffic: p50 `, text)
+	})
+
+	t.Run("NoTestRun", func(t *testing.T) {
+		t.Parallel()
+
+		// Marking a phase outside of a test run is a no-op.
+		harness.StartPhase(context.Background(), "connect")()
+	})
+}
@@ -0,0 +1,192 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// AnnotationTagProfile marks the time range a profile of the deployment under
+// test was captured in.
+const AnnotationTagProfile = "profile"
+
+// Load phases that profiles are captured in.
+const (
+	LoadPhaseWarmup = "warmup"
+	LoadPhaseRun    = "run"
+)
+
+// Profiler captures profiles of the deployment under test, e.g. by fetching
+// them from the pprof endpoints of coderd.
+type Profiler interface {
+	// Profile writes a profile captured at the time of the call to w.
+	Profile(ctx context.Context, w io.Writer) error
+}
+
+// ProfilerFunc is a function that implements Profiler.
+type ProfilerFunc func(ctx context.Context, w io.Writer) error
+
+// Profile implements Profiler.
+func (fn ProfilerFunc) Profile(ctx context.Context, w io.Writer) error {
+	return fn(ctx, w)
+}
+
+// ProfileConfig configures the profiles captured by WithProfiles.
+type ProfileConfig struct {
+	Profiler Profiler
+	// Interval is the time between the start of consecutive captures. If a
+	// capture takes longer than the interval, the next one starts as soon as
+	// it has finished.
+	Interval time.Duration
+	// Dir is the directory the profiles are written to. If empty, they are
+	// written to the profiles directory of the artifact directory.
+	Dir string
+	// Extension is the file extension of the profiles, e.g. ".tar.gz".
+	Extension string
+}
+
+// WithProfiles periodically captures profiles of the deployment under test
+// during the run phase, starting as soon as it starts, and writes them to
+// files named after the time they were captured at and the load phase they
+// were captured in. The captures are listed in the results and annotated, so
+// that a profile can be matched to the load the deployment was under while it
+// was captured. A capture still in progress when the run phase finishes is
+// discarded. A zero interval disables profiling.
+func WithProfiles(cfg ProfileConfig) Option {
+	return func(h *TestHarness) {
+		h.profiles = cfg
+	}
+}
+
+// ProfileCapture is a single profile captured by WithProfiles.
+type ProfileCapture struct {
+	Time    time.Time `json:"time"`
+	TimeEnd time.Time `json:"time_end"`
+	// Phase is the load phase of the test when the capture started, either
+	// LoadPhaseWarmup or LoadPhaseRun.
+	Phase string `json:"phase"`
+	// InFlight is the number of runs in progress when the capture started.
+	InFlight int64  `json:"in_flight"`
+	Path     string `json:"path,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// captureProfiles captures a profile at every interval until the context is
+// canceled.
+func (h *TestHarness) captureProfiles(ctx context.Context, p *runPhase) {
+	dir := h.profiles.Dir
+	if dir == "" && h.artifactDir != "" {
+		dir = filepath.Join(h.artifactDir, "profiles")
+	}
+
+	next := p.start
+	for {
+		if !sleepUntil(ctx, next) {
+			return
+		}
+		capture := h.captureProfile(ctx, p, dir)
+		// Captures interrupted by the end of the run phase are incomplete.
+		if ctx.Err() != nil {
+			if capture.Path != "" {
+				_ = os.Remove(capture.Path)
+			}
+			return
+		}
+		h.mut.Lock()
+		h.profileCaptures = append(h.profileCaptures, capture)
+		h.mut.Unlock()
+		h.annotate(ctx, Annotation{
+			Time:    capture.Time,
+			TimeEnd: capture.TimeEnd,
+			Text:    fmt.Sprintf("Scaletest profile (%s phase, %d runs in flight)", capture.Phase, capture.InFlight),
+			Tags:    []string{AnnotationTagProfile},
+		})
+		next = next.Add(h.profiles.Interval)
+		if now := time.Now(); next.Before(now) {
+			next = now
+		}
+	}
+}
+
+func (h *TestHarness) captureProfile(ctx context.Context, p *runPhase, dir string) ProfileCapture {
+	capture := ProfileCapture{
+		Time:     time.Now(),
+		Phase:    p.loadPhase(),
+		InFlight: h.startedRuns.Load() - h.completedRuns.Load(),
+	}
+	err := func() error {
+		if dir == "" {
+			return xerrors.New("no profile or artifact directory configured")
+		}
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return xerrors.Errorf("create profile directory: %w", err)
+		}
+		name := fmt.Sprintf("%s-%s%s", capture.Time.UTC().Format("20060102T150405.000Z"), capture.Phase, h.profiles.Extension)
+		capture.Path = filepath.Join(dir, name)
+		f, err := os.Create(capture.Path)
+		if err != nil {
+			return xerrors.Errorf("create profile file: %w", err)
+		}
+		defer f.Close()
+		err = h.profiles.Profiler.Profile(ctx, f)
+		if err != nil {
+			return xerrors.Errorf("capture profile: %w", err)
+		}
+		return f.Close()
+	}()
+	capture.TimeEnd = time.Now()
+	if err != nil {
+		capture.Error = err.Error()
+		if capture.Path != "" {
+			_ = os.Remove(capture.Path)
+			capture.Path = ""
+		}
+	}
+	return capture
+}
+
+// loadPhase returns the load phase the test is currently in.
+func (p *runPhase) loadPhase() string {
+	w := p.h.warmup
+	if (w.Runs > 0 || w.Duration > 0) && !p.warmupOver.Load() {
+		return LoadPhaseWarmup
+	}
+	return LoadPhaseRun
+}
+
+// printProfiles prints where the captured profiles were written to, and the
+// first error if any captures failed.
+func (r *Results) printProfiles(w io.Writer) {
+	if len(r.Profiles) == 0 {
+		return
+	}
+
+	var (
+		dir      string
+		failed   int
+		firstErr string
+	)
+	for _, capture := range r.Profiles {
+		if capture.Error == "" {
+			dir = filepath.Dir(capture.Path)
+			continue
+		}
+		if failed == 0 {
+			firstErr = capture.Error
+		}
+		failed++
+	}
+	_, _ = fmt.Fprintf(w, "\n\tProfiles (%d captured, %d failed):\n", len(r.Profiles)-failed, failed)
+	if dir != "" {
+		_, _ = fmt.Fprintf(w, "\t\tDirectory: %s\n", dir)
+	}
+	if failed > 0 {
+		_, _ = fmt.Fprintf(w, "\t\tFirst error: %s\n", firstErr)
+	}
+}
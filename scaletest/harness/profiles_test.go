@@ -0,0 +1,136 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_Profiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		annotator := &fakeAnnotator{}
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithWarmup(harness.Warmup{Runs: 1}),
+			harness.WithAnnotator(annotator),
+			harness.WithProfiles(harness.ProfileConfig{
+				Profiler: harness.ProfilerFunc(func(_ context.Context, w io.Writer) error {
+					_, err := w.Write([]byte("profile"))
+					return err
+				}),
+				Interval:  10 * time.Millisecond,
+				Dir:       dir,
+				Extension: ".pprof",
+			}),
+		)
+		for i := range 3 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				},
+			})
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.NoError(t, h.Run(ctx))
+
+		res := h.Results()
+		require.NotEmpty(t, res.Profiles)
+		phases := map[string]int{}
+		for _, capture := range res.Profiles {
+			require.Empty(t, capture.Error)
+			require.Equal(t, dir, filepath.Dir(capture.Path))
+			require.Contains(t, filepath.Base(capture.Path), "-"+capture.Phase+".pprof")
+			require.False(t, capture.TimeEnd.Before(capture.Time))
+			data, err := os.ReadFile(capture.Path)
+			require.NoError(t, err)
+			require.Equal(t, "profile", string(data))
+			phases[capture.Phase]++
+		}
+		require.Positive(t, phases[harness.LoadPhaseWarmup])
+		require.Positive(t, phases[harness.LoadPhaseRun])
+		annotation := annotator.find(t, harness.AnnotationTagProfile)
+		require.Equal(t, res.Profiles[0].Time, annotation.Time)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "failed):\n\t\tDirectory: "+dir+"\n")
+	})
+
+	t.Run("ArtifactDir", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithArtifactDir(dir),
+			harness.WithProfiles(harness.ProfileConfig{
+				Profiler: harness.ProfilerFunc(func(context.Context, io.Writer) error {
+					return nil
+				}),
+				Interval: time.Hour,
+			}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+		})
+		require.NoError(t, h.Run(testutil.Context(t, testutil.WaitShort)))
+
+		res := h.Results()
+		require.Len(t, res.Profiles, 1)
+		require.Equal(t, filepath.Join(dir, "profiles"), filepath.Dir(res.Profiles[0].Path))
+		require.FileExists(t, res.Profiles[0].Path)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithProfiles(harness.ProfileConfig{
+				Profiler: harness.ProfilerFunc(func(context.Context, io.Writer) error {
+					return xerrors.New("forbidden")
+				}),
+				Interval: time.Hour,
+				Dir:      dir,
+			}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+		})
+		require.NoError(t, h.Run(testutil.Context(t, testutil.WaitShort)))
+
+		res := h.Results()
+		require.Len(t, res.Profiles, 1)
+		require.Empty(t, res.Profiles[0].Path)
+		require.Contains(t, res.Profiles[0].Error, "forbidden")
+		require.Equal(t, harness.LoadPhaseRun, res.Profiles[0].Phase)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "Profiles (0 captured, 1 failed):\n\t\tFirst error: capture profile: forbidden\n")
+	})
+}
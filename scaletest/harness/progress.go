@@ -0,0 +1,175 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProgressTTYInterval   = 500 * time.Millisecond
+	defaultProgressPlainInterval = 30 * time.Second
+	defaultProgressWindow        = 100
+	progressBarWidth             = 30
+)
+
+// ProgressConfig configures the progress rendered by WithProgress.
+type ProgressConfig struct {
+	// TTY redraws a single status line with a progress bar in place, for
+	// writers that are terminals. Otherwise, a plain line is written at every
+	// interval, which is suitable for log files and CI output.
+	TTY bool
+	// Interval is the time between updates. Defaults to 500ms for terminals
+	// and 30s otherwise.
+	Interval time.Duration
+	// Window is the number of most recently completed runs that the error
+	// rate and latency percentiles are computed over, so that they reflect
+	// the current state of the test rather than its whole history. Defaults
+	// to 100.
+	Window int
+}
+
+// WithProgress renders the progress of the run phase to w while it's running:
+// the number of finished runs out of the runs queued so far, the number of
+// runs in progress, and the error rate and latency percentiles of the most
+// recently completed runs. Warmup runs count towards the progress, but not
+// towards the error rate or latency percentiles. A final update is rendered
+// once the run phase is over.
+func WithProgress(w io.Writer, cfg ProgressConfig) Option {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultProgressPlainInterval
+		if cfg.TTY {
+			cfg.Interval = defaultProgressTTYInterval
+		}
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultProgressWindow
+	}
+	return func(h *TestHarness) {
+		h.progress = &progressTracker{w: w, cfg: cfg}
+		h.observers = append(h.observers, h.progress)
+	}
+}
+
+// progressSample is a completed run in the window of a progressTracker.
+type progressSample struct {
+	duration time.Duration
+	failed   bool
+}
+
+// progressTracker tracks the progress of the run phase from the events of the
+// harness and renders it.
+type progressTracker struct {
+	w   io.Writer
+	cfg ProgressConfig
+
+	mu       sync.Mutex
+	queued   int
+	finished int
+	inFlight int
+	// window is a ring buffer of the most recently completed measured runs,
+	// and next is the index the next run is written to.
+	window []progressSample
+	next   int
+}
+
+var _ Observer = &progressTracker{}
+
+// Observe implements Observer.
+func (p *progressTracker) Observe(event Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch event.Type {
+	case EventRunQueued:
+		p.queued++
+	case EventRunStarted:
+		p.inFlight++
+	case EventRunSkipped:
+		p.finished++
+	case EventRunCompleted:
+		p.inFlight--
+		p.finished++
+		if event.Warmup {
+			return
+		}
+		sample := progressSample{duration: event.Duration, failed: event.Error != nil}
+		if len(p.window) < p.cfg.Window {
+			p.window = append(p.window, sample)
+			return
+		}
+		p.window[p.next] = sample
+		p.next = (p.next + 1) % len(p.window)
+	}
+}
+
+// render renders the progress every interval until the context is done, and a
+// final update afterwards.
+func (p *progressTracker) render(ctx context.Context, start time.Time) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.write(p.line(time.Since(start)))
+			if p.cfg.TTY {
+				_, _ = fmt.Fprintln(p.w)
+			}
+			return
+		case <-ticker.C:
+			p.write(p.line(time.Since(start)))
+		}
+	}
+}
+
+func (p *progressTracker) write(line string) {
+	if p.cfg.TTY {
+		// Return to the start of the line and clear what's left of the
+		// previous update.
+		_, _ = fmt.Fprintf(p.w, "\r%s\x1b[K", line)
+		return
+	}
+	_, _ = fmt.Fprintf(p.w, "Progress: %s\n", line)
+}
+
+// line returns the current progress as a single line.
+func (p *progressTracker) line(elapsed time.Duration) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+	if p.cfg.TTY {
+		filled := 0
+		if p.queued > 0 {
+			filled = progressBarWidth * p.finished / p.queued
+		}
+		_, _ = fmt.Fprintf(&b, "[%s%s] ", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled))
+	}
+	_, _ = fmt.Fprintf(&b, "%d/%d runs", p.finished, p.queued)
+	if p.queued > 0 {
+		_, _ = fmt.Fprintf(&b, " (%d%%)", 100*p.finished/p.queued)
+	}
+	_, _ = fmt.Fprintf(&b, ", %s elapsed, %d in flight", elapsed.Round(time.Second), p.inFlight)
+	if len(p.window) == 0 {
+		return b.String()
+	}
+
+	var failed int
+	hist := NewHistogram()
+	for _, sample := range p.window {
+		if sample.failed {
+			failed++
+		}
+		hist.Record(sample.duration)
+	}
+	_, _ = fmt.Fprintf(&b, ", %.1f%% errors, p50 %s, p95 %s, p99 %s (last %d runs)",
+		100*float64(failed)/float64(len(p.window)),
+		hist.Quantile(0.5).Round(time.Millisecond),
+		hist.Quantile(0.95).Round(time.Millisecond),
+		hist.Quantile(0.99).Round(time.Millisecond),
+		len(p.window),
+	)
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Progress(t *testing.T) {
+	t.Parallel()
+
+	addRuns := func(h *harness.TestHarness) {
+		for i := range 4 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					time.Sleep(20 * time.Millisecond)
+					if i == 3 {
+						return xerrors.New("boom")
+					}
+					return nil
+				},
+			})
+		}
+	}
+
+	t.Run("Plain", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithWarmup(harness.Warmup{Runs: 1}),
+			harness.WithProgress(&out, harness.ProgressConfig{Interval: time.Hour}),
+		)
+		addRuns(h)
+		require.NoError(t, h.Run(context.Background()))
+
+		// Only the final update is rendered within the interval, and the
+		// warmup run doesn't count towards the error rate.
+		require.Equal(t, 1, strings.Count(out.String(), "\n"))
+		require.Regexp(t, `^Progress: 4/4 runs \(100%\), \d+s elapsed, 0 in flight, 33\.3% errors, p50 \d+ms, p95 \d+ms, p99 \d+ms \(last 3 runs\)\n$`, out.String())
+	})
+
+	t.Run("TTY", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithProgress(&out, harness.ProgressConfig{TTY: true, Interval: 10 * time.Millisecond, Window: 2}),
+		)
+		addRuns(h)
+		require.NoError(t, h.Run(context.Background()))
+
+		updates := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\r")[1:]
+		require.Greater(t, len(updates), 1)
+		for _, update := range updates {
+			require.True(t, strings.HasSuffix(update, "\x1b[K"), update)
+			require.NotContains(t, update, "\n")
+		}
+		final := updates[len(updates)-1]
+		require.Contains(t, final, "["+strings.Repeat("=", 30)+"] 4/4 runs (100%)")
+		require.Contains(t, final, "50.0% errors")
+		require.Contains(t, final, "(last 2 runs)")
+	})
+
+	t.Run("NoRuns", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithProgress(&out, harness.ProgressConfig{}),
+		)
+		require.NoError(t, h.Run(context.Background()))
+		require.Regexp(t, `^Progress: 0/0 runs, \d+s elapsed, 0 in flight\n$`, out.String())
+	})
+}
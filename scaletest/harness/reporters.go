@@ -0,0 +1,225 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"golang.org/x/xerrors"
+)
+
+// Reporter reports the results of a test, e.g. by writing them to a file or
+// pushing them to a metrics backend. See WithReporter.
+type Reporter interface {
+	// Report is called with the results once the run phase has finished.
+	Report(ctx context.Context, results Results) error
+}
+
+// SnapshotReporter is an optional extension to Reporter that also reports the
+// snapshots taken while the test runs. See WithSnapshots.
+type SnapshotReporter interface {
+	Reporter
+	// ReportSnapshot is called with every snapshot as soon as it is taken.
+	// It is called from a single goroutine and should not block for long.
+	ReportSnapshot(ctx context.Context, snapshot Snapshot) error
+}
+
+// WithReporter reports the results to the given reporter once Run or RunFor has
+// finished, and the snapshots of the test if the reporter is a
+// SnapshotReporter. The option can be passed more than once to report to
+// multiple reporters. Errors returned by reporters are returned by Run or
+// RunFor unless the test itself failed to execute.
+func WithReporter(r Reporter) Option {
+	return func(h *TestHarness) {
+		h.reporters = append(h.reporters, r)
+	}
+}
+
+// report reports the results of the harness to every reporter. The results are
+// reported even if ctx is done, e.g. because the test was interrupted.
+func (h *TestHarness) report(ctx context.Context) error {
+	if len(h.reporters) == 0 {
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	res := h.Results()
+	h.mut.Lock()
+	merr := h.reportErr
+	h.mut.Unlock()
+	for _, r := range h.reporters {
+		if err := r.Report(ctx, res); err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("report results: %w", err))
+		}
+	}
+	return merr
+}
+
+// reportSnapshot reports the snapshot to every SnapshotReporter.
+func (h *TestHarness) reportSnapshot(ctx context.Context, snapshot Snapshot) {
+	for _, r := range h.reporters {
+		sr, ok := r.(SnapshotReporter)
+		if !ok {
+			continue
+		}
+		if err := sr.ReportSnapshot(ctx, snapshot); err != nil {
+			h.mut.Lock()
+			h.reportErr = multierror.Append(h.reportErr, xerrors.Errorf("report snapshot %d: %w", snapshot.Index, err))
+			h.mut.Unlock()
+		}
+	}
+}
+
+// TextReporter writes the results, and a line for every snapshot, to W in a
+// human-readable format.
+type TextReporter struct {
+	W io.Writer
+}
+
+var _ SnapshotReporter = TextReporter{}
+
+// Report implements Reporter.
+func (r TextReporter) Report(_ context.Context, results Results) error {
+	results.PrintText(r.W)
+	return nil
+}
+
+// ReportSnapshot implements SnapshotReporter.
+func (r TextReporter) ReportSnapshot(_ context.Context, snapshot Snapshot) error {
+	_, err := fmt.Fprintf(r.W, "Snapshot %d: %s\n", snapshot.Index, snapshot.summaryLine())
+	return err
+}
+
+// JSONReporter writes the results to W as JSON. See Results.WriteJSON.
+type JSONReporter struct {
+	W io.Writer
+}
+
+// Report implements Reporter.
+func (r JSONReporter) Report(_ context.Context, results Results) error {
+	return results.WriteJSON(r.W)
+}
+
+// CSVReporter writes the results to W as CSV. See Results.WriteCSV.
+type CSVReporter struct {
+	W io.Writer
+}
+
+// Report implements Reporter.
+func (r CSVReporter) Report(_ context.Context, results Results) error {
+	return results.WriteCSV(r.W)
+}
+
+// JUnitReporter writes the results to W as JUnit XML. See Results.WriteJUnit.
+type JUnitReporter struct {
+	W io.Writer
+}
+
+// Report implements Reporter.
+func (r JUnitReporter) Report(_ context.Context, results Results) error {
+	return results.WriteJUnit(r.W)
+}
+
+// PrometheusPushReporter pushes a summary of the results, and of every
+// snapshot, to a Prometheus Pushgateway. The summary of the results replaces
+// the metrics of the previous test with the same job, while snapshots only
+// replace the snapshot metrics.
+type PrometheusPushReporter struct {
+	// URL is the URL of the Pushgateway.
+	URL string
+	// Job is the job label of the pushed metrics. Defaults to
+	// "coder_scaletest".
+	Job string
+	// Client is the HTTP client to push with. Defaults to
+	// http.DefaultClient.
+	Client push.HTTPDoer
+}
+
+var _ SnapshotReporter = PrometheusPushReporter{}
+
+// Report implements Reporter.
+func (r PrometheusPushReporter) Report(ctx context.Context, results Results) error {
+	reg := prometheus.NewRegistry()
+	runs := newScaletestGaugeVec(reg, "runs", "Number of measured test runs by result.", "result")
+	runs.WithLabelValues("pass").Set(float64(results.TotalPass))
+	runs.WithLabelValues("fail").Set(float64(results.TotalFail))
+	runs.WithLabelValues("timed_out").Set(float64(results.TotalTimedOut))
+	runs.WithLabelValues("test_timed_out").Set(float64(results.TotalTestTimedOut))
+	runs.WithLabelValues("skipped").Set(float64(results.TotalSkipped))
+	elapsed := newScaletestGaugeVec(reg, "elapsed_seconds", "Duration of the run phase of the test.")
+	elapsed.WithLabelValues().Set(time.Duration(results.Elapsed).Seconds())
+	latency := newScaletestGaugeVec(reg, "latency_seconds", "Run duration percentiles by test name.", "test_name", "quantile")
+	for testName, l := range results.Latency {
+		setLatencyGauges(latency, testName, l)
+	}
+	return r.push(ctx, reg, true)
+}
+
+// ReportSnapshot implements SnapshotReporter.
+func (r PrometheusPushReporter) ReportSnapshot(ctx context.Context, snapshot Snapshot) error {
+	reg := prometheus.NewRegistry()
+	runs := newScaletestGaugeVec(reg, "snapshot_runs", "Number of test runs that completed during the latest snapshot interval by result.", "result")
+	runs.WithLabelValues("pass").Set(float64(snapshot.TotalPass))
+	runs.WithLabelValues("fail").Set(float64(snapshot.TotalFail))
+	runs.WithLabelValues("timed_out").Set(float64(snapshot.TotalTimedOut))
+	inFlight := newScaletestGaugeVec(reg, "snapshot_in_flight", "Number of test runs in progress at the end of the latest snapshot interval.")
+	inFlight.WithLabelValues().Set(float64(snapshot.InFlight))
+	elapsed := newScaletestGaugeVec(reg, "snapshot_elapsed_seconds", "Time since the start of the test at the end of the latest snapshot interval.")
+	elapsed.WithLabelValues().Set(time.Duration(snapshot.Elapsed).Seconds())
+	latency := newScaletestGaugeVec(reg, "snapshot_latency_seconds", "Run duration percentiles during the latest snapshot interval by test name.", "test_name", "quantile")
+	for testName, l := range snapshot.Latency {
+		setLatencyGauges(latency, testName, l)
+	}
+	return r.push(ctx, reg, false)
+}
+
+// push pushes the metrics of the registry. If replace is set, every metric of
+// the job is replaced rather than only the pushed ones.
+func (r PrometheusPushReporter) push(ctx context.Context, reg *prometheus.Registry, replace bool) error {
+	job := r.Job
+	if job == "" {
+		job = "coder_scaletest"
+	}
+	pusher := push.New(r.URL, job).Gatherer(reg)
+	if r.Client != nil {
+		pusher = pusher.Client(r.Client)
+	}
+	var err error
+	if replace {
+		err = pusher.PushContext(ctx)
+	} else {
+		err = pusher.AddContext(ctx)
+	}
+	if err != nil {
+		return xerrors.Errorf("push metrics to %q: %w", r.URL, err)
+	}
+	return nil
+}
+
+func newScaletestGaugeVec(reg *prometheus.Registry, name, help string, labels ...string) *prometheus.GaugeVec {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coder",
+		Subsystem: "scaletest",
+		Name:      name,
+		Help:      help,
+	}, labels)
+	reg.MustRegister(g)
+	return g
+}
+
+func setLatencyGauges(g *prometheus.GaugeVec, testName string, l LatencySummary) {
+	for quantile, d := range map[string]time.Duration{
+		"0.5":   time.Duration(l.P50),
+		"0.9":   time.Duration(l.P90),
+		"0.95":  time.Duration(l.P95),
+		"0.99":  time.Duration(l.P99),
+		"0.999": time.Duration(l.P999),
+		"1":     time.Duration(l.Max),
+	} {
+		g.WithLabelValues(testName, quantile).Set(d.Seconds())
+	}
+}
@@ -0,0 +1,132 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+type fakeReporter struct {
+	mu        sync.Mutex
+	results   []harness.Results
+	snapshots []harness.Snapshot
+	err       error
+}
+
+var _ harness.SnapshotReporter = &fakeReporter{}
+
+func (r *fakeReporter) Report(_ context.Context, results harness.Results) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, results)
+	return r.err
+}
+
+func (r *fakeReporter) ReportSnapshot(_ context.Context, snapshot harness.Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshots = append(r.snapshots, snapshot)
+	return nil
+}
+
+func Test_Reporters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := &fakeReporter{}
+		var text bytes.Buffer
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithSnapshots(20*time.Millisecond, nil),
+			harness.WithReporter(reporter),
+			harness.WithReporter(harness.TextReporter{W: &text}),
+		)
+		err := h.RunFor(context.Background(), harness.DurationTest{
+			TestName:    "soak",
+			Duration:    70 * time.Millisecond,
+			Concurrency: 1,
+			NewRunner: func(int) (harness.Runnable, error) {
+				return testFns{
+					RunFn: func(context.Context, string, io.Writer) error {
+						time.Sleep(5 * time.Millisecond)
+						return nil
+					},
+				}, nil
+			},
+		})
+		require.NoError(t, err)
+
+		res := h.Results()
+		require.Len(t, reporter.results, 1)
+		require.Equal(t, res.TotalRuns, reporter.results[0].TotalRuns)
+		require.Equal(t, res.Snapshots, reporter.snapshots)
+		require.Contains(t, text.String(), "Snapshot 0: ")
+		require.Contains(t, text.String(), "Test results:")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := &fakeReporter{err: xerrors.New("disk full")}
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithReporter(reporter),
+		)
+		h.AddRun("test", "0", fakeTestFns(nil, nil))
+		err := h.Run(context.Background())
+		require.ErrorContains(t, err, "disk full")
+		// The results are still available.
+		require.Equal(t, 1, h.Results().TotalPass)
+	})
+
+	t.Run("PrometheusPush", func(t *testing.T) {
+		t.Parallel()
+
+		type request struct {
+			method string
+			path   string
+			body   string
+		}
+		var (
+			mu       sync.Mutex
+			requests []request
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			requests = append(requests, request{method: r.Method, path: r.URL.Path, body: string(body)})
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+
+		reporter := harness.PrometheusPushReporter{URL: srv.URL}
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithReporter(reporter),
+		)
+		h.AddRun("test", "0", fakeTestFns(nil, nil))
+		require.NoError(t, h.Run(context.Background()))
+		require.NoError(t, reporter.ReportSnapshot(context.Background(), harness.Snapshot{TotalRuns: 1, TotalPass: 1}))
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, requests, 2)
+		// Results replace every metric of the job, snapshots only add theirs.
+		require.Equal(t, http.MethodPut, requests[0].method)
+		require.Equal(t, "/metrics/job/coder_scaletest", requests[0].path)
+		require.Contains(t, requests[0].body, "coder_scaletest_runs")
+		require.Contains(t, requests[0].body, "coder_scaletest_latency_seconds")
+		require.Equal(t, http.MethodPost, requests[1].method)
+		require.Contains(t, requests[1].body, "coder_scaletest_snapshot_runs")
+	})
+}
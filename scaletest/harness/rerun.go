@@ -0,0 +1,129 @@
+package harness
+
+import (
+	"slices"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+// WithRerunFailed only executes the runs that failed or were skipped in the
+// previous results of the same test, along with the runs they depend on, so
+// that a test in which a few runs flaked doesn't have to be repeated in full.
+// Runs must be registered exactly as they were for the previous test, and the
+// other runs are dropped from the harness once it starts. Unless a seed is
+// given, the seed of the previous test is used, so that reruns get the same
+// randomness as before. Reruns are measured even if they were part of the
+// warmup phase before, so the warmup phase is disabled.
+//
+// Use MergeRerun to merge the results of the rerun into the previous results.
+// Duration tests started with RunFor can't be rerun.
+func WithRerunFailed(previous Results) Option {
+	return func(h *TestHarness) {
+		failed := map[string]struct{}{}
+		for id, run := range previous.Runs {
+			if run.Skipped || (run.Error != nil && !run.Warmup) {
+				failed[id] = struct{}{}
+			}
+		}
+		h.rerun = &rerunState{failed: failed, seed: previous.Seed}
+	}
+}
+
+// rerunState holds the runs to rerun, see WithRerunFailed.
+type rerunState struct {
+	// failed holds the full IDs of the runs that failed or were skipped.
+	failed map[string]struct{}
+	seed   int64
+}
+
+// rerunRuns returns the given runs that need to be rerun in the same order,
+// along with their indexes among the given runs, and drops the others from the
+// harness.
+func (h *TestHarness) rerunRuns(runs []*TestRun) ([]*TestRun, []int) {
+	selected := map[*TestRun]bool{}
+	var visit func(run *TestRun)
+	visit = func(run *TestRun) {
+		if selected[run] {
+			return
+		}
+		selected[run] = true
+		for _, dep := range run.dependencies {
+			visit(dep)
+		}
+	}
+	for _, run := range runs {
+		if _, ok := h.rerun.failed[run.FullID()]; ok {
+			visit(run)
+		}
+	}
+
+	var (
+		rerun   = make([]*TestRun, 0, len(selected))
+		indexes = make([]int, 0, len(selected))
+	)
+	for i, run := range runs {
+		if selected[run] {
+			rerun = append(rerun, run)
+			indexes = append(indexes, i)
+		}
+	}
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.runs = rerun
+	h.warmup = Warmup{}
+	return rerun, indexes
+}
+
+// MergeRerun merges the results of a test run with WithRerunFailed into the
+// results of the previous test. The results of the reruns replace the previous
+// results of the same runs and are marked as reruns, and the totals and
+// latency percentiles are computed again from the merged runs. The elapsed
+// time is the sum of both tests. Summaries that describe the test as a whole
+// rather than individual runs, e.g. resource usage and snapshots, are those of
+// the previous test.
+//
+// Returns an error if the results of some runs of either test were not
+// retained, since the totals of the merged results could not be computed, or if
+// the previous results were written with an older schema version, since their
+// totals have a different meaning.
+func MergeRerun(previous, rerun Results) (Results, error) {
+	if previous.SchemaVersion != ResultsSchemaVersion {
+		return Results{}, xerrors.Errorf("the previous results have schema version %d, but only version %d can be merged", previous.SchemaVersion, ResultsSchemaVersion)
+	}
+	if previous.TotalDiscarded > 0 {
+		return Results{}, xerrors.Errorf("the results of %d previous runs were not retained", previous.TotalDiscarded)
+	}
+	if rerun.TotalDiscarded > 0 {
+		return Results{}, xerrors.Errorf("the results of %d reruns were not retained", rerun.TotalDiscarded)
+	}
+
+	runs := make(map[string]RunResult, len(previous.Runs))
+	for id, run := range previous.Runs {
+		runs[id] = run
+	}
+	for id, run := range rerun.Runs {
+		if _, ok := runs[id]; !ok {
+			return Results{}, xerrors.Errorf("rerun %q is not part of the previous results", id)
+		}
+		run.Rerun = true
+		runs[id] = run
+	}
+
+	elapsed := time.Duration(previous.Elapsed) + time.Duration(rerun.Elapsed)
+	base := previous
+	base.SchemaVersion = ResultsSchemaVersion
+	base.Elapsed = httpapi.Duration(elapsed)
+	base.ElapsedMS = elapsed.Milliseconds()
+	merged := aggregateRuns(base, runs)
+	merged.Chaos = previous.Chaos
+	merged.Profiles = previous.Profiles
+	merged.ResourceUsage = previous.ResourceUsage
+	merged.CircuitBreaker = previous.CircuitBreaker
+	merged.Capacity = previous.Capacity
+	merged.Snapshots = previous.Snapshots
+	merged.Retained = slices.Concat(previous.Retained, rerun.Retained)
+	return merged, nil
+}
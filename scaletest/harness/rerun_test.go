@@ -0,0 +1,117 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_RerunFailed(t *testing.T) {
+	t.Parallel()
+
+	// addRuns registers the same four runs with the harness, where the last
+	// run depends on the third.
+	addRuns := func(h *harness.TestHarness, fail map[string]bool, ran *sync.Map) {
+		runs := make([]*harness.TestRun, 4)
+		for i := range runs {
+			id := strconv.Itoa(i)
+			runs[i] = h.AddRun("test", id, testFns{
+				RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+					md, _ := harness.Metadata(ctx)
+					ran.Store(id, md)
+					if fail[id] {
+						return xerrors.New("flaked")
+					}
+					return nil
+				},
+			})
+		}
+		runs[3].DependsOn(runs[2])
+	}
+
+	var ran sync.Map
+	h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+		harness.WithWarmup(harness.Warmup{Runs: 1}),
+	)
+	addRuns(h, map[string]bool{"1": true, "3": true}, &ran)
+	require.NoError(t, h.Run(context.Background()))
+	previous := h.Results()
+	require.Equal(t, 1, previous.TotalPass)
+	require.Equal(t, 2, previous.TotalFail)
+
+	// Rerun the failed runs, along with the run the failed last run depends
+	// on, with the same seed.
+	ran = sync.Map{}
+	h = harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+		harness.WithWarmup(harness.Warmup{Runs: 1}),
+		harness.WithRerunFailed(previous),
+	)
+	addRuns(h, nil, &ran)
+	require.NoError(t, h.Run(context.Background()))
+	rerun := h.Results()
+	require.Len(t, rerun.Runs, 3)
+	require.Equal(t, previous.Seed, rerun.Seed)
+	// Reruns are measured, and keep their position in the test.
+	require.Equal(t, 3, rerun.TotalPass)
+	require.Zero(t, rerun.TotalWarmup)
+	_, ok := ran.Load("0")
+	require.False(t, ok)
+	md, ok := ran.Load("3")
+	require.True(t, ok)
+	require.Equal(t, 3, md.(harness.RunMetadata).Index)
+	require.Equal(t, 4, md.(harness.RunMetadata).Total)
+
+	merged, err := harness.MergeRerun(previous, rerun)
+	require.NoError(t, err)
+	require.Len(t, merged.Runs, 4)
+	require.Equal(t, 3, merged.TotalPass)
+	require.Zero(t, merged.TotalFail)
+	require.Equal(t, 1, merged.TotalWarmup)
+	require.Equal(t, int64(3), merged.Latency["test"].Count)
+	require.Equal(t, time.Duration(previous.Elapsed)+time.Duration(rerun.Elapsed), time.Duration(merged.Elapsed))
+	require.False(t, merged.Runs["test/0"].Rerun)
+	require.True(t, merged.Runs["test/1"].Rerun)
+	require.True(t, merged.Runs["test/2"].Rerun)
+	require.NoError(t, merged.Runs["test/3"].Error)
+
+	t.Run("UnknownRun", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := harness.MergeRerun(harness.Results{SchemaVersion: harness.ResultsSchemaVersion}, rerun)
+		require.ErrorContains(t, err, "is not part of the previous results")
+	})
+
+	t.Run("OldSchemaVersion", func(t *testing.T) {
+		t.Parallel()
+
+		previous := previous
+		previous.SchemaVersion = 1
+		_, err := harness.MergeRerun(previous, rerun)
+		require.ErrorContains(t, err, "schema version 1")
+	})
+
+	t.Run("Duration", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithRerunFailed(previous),
+		)
+		err := h.RunFor(context.Background(), harness.DurationTest{
+			TestName:    "test",
+			Duration:    time.Millisecond,
+			Concurrency: 1,
+			NewRunner: func(int) (harness.Runnable, error) {
+				return fakeTestFns(nil, nil), nil
+			},
+		})
+		require.ErrorContains(t, err, "duration tests cannot be rerun")
+	})
+}
@@ -0,0 +1,176 @@
+package harness
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// resourceSaturation is the fraction of a resource limit of the load generator
+// above which the results warn that the load generator may have been the
+// bottleneck of the test.
+const resourceSaturation = 0.9
+
+// WithResourceUsage samples the CPU, memory and file descriptor usage of the
+// process running the harness at the given interval while the test runs, and
+// includes a summary of the samples in the results. This shows whether the
+// machine generating the load, rather than the deployment under test, was the
+// bottleneck. A zero interval disables sampling.
+func WithResourceUsage(interval time.Duration) Option {
+	return func(h *TestHarness) {
+		h.resourceInterval = interval
+	}
+}
+
+// ResourceSample is a single sample of the resource usage of the load
+// generator process.
+type ResourceSample struct {
+	Time time.Time `json:"time"`
+	// CPUPercent is the CPU usage since the previous sample as a percentage
+	// of a single CPU.
+	CPUPercent float64 `json:"cpu_percent"`
+	RSSBytes   uint64  `json:"rss_bytes"`
+	// OpenFDs is the number of open file descriptors. It is zero on
+	// platforms where it can't be determined.
+	OpenFDs    int32 `json:"open_fds"`
+	Goroutines int   `json:"goroutines"`
+}
+
+// ResourceUsage summarizes the resource usage of the load generator process
+// during the run phase of a test.
+type ResourceUsage struct {
+	// NumCPU is the number of CPUs usable by the process. The process is
+	// saturated when its CPU usage reaches NumCPU * 100 percent.
+	NumCPU int `json:"num_cpu"`
+	// FDLimit is the soft limit on open file descriptors. It is zero if
+	// unknown.
+	FDLimit        uint64           `json:"fd_limit,omitempty"`
+	CPUPercentMean float64          `json:"cpu_percent_mean"`
+	CPUPercentMax  float64          `json:"cpu_percent_max"`
+	RSSBytesMax    uint64           `json:"rss_bytes_max"`
+	OpenFDsMax     int32            `json:"open_fds_max"`
+	GoroutinesMax  int              `json:"goroutines_max"`
+	Samples        []ResourceSample `json:"samples"`
+}
+
+// CPUSaturated returns whether the CPU usage of the load generator came close
+// to the capacity of the CPUs available to it.
+func (u ResourceUsage) CPUSaturated() bool {
+	return u.NumCPU > 0 && u.CPUPercentMax >= resourceSaturation*float64(u.NumCPU)*100
+}
+
+// FDsSaturated returns whether the number of open file descriptors of the load
+// generator came close to its limit.
+func (u ResourceUsage) FDsSaturated() bool {
+	return u.FDLimit > 0 && float64(u.OpenFDsMax) >= resourceSaturation*float64(u.FDLimit)
+}
+
+// resourceSampler samples the resource usage of the current process.
+type resourceSampler struct {
+	proc     *process.Process
+	lastTime time.Time
+	lastCPU  float64
+	usage    ResourceUsage
+}
+
+func newResourceSampler(ctx context.Context) (*resourceSampler, error) {
+	//nolint:gosec // PIDs fit in an int32.
+	proc, err := process.NewProcessWithContext(ctx, int32(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	s := &resourceSampler{
+		proc: proc,
+		usage: ResourceUsage{
+			NumCPU: runtime.GOMAXPROCS(0),
+		},
+	}
+	if limits, err := proc.RlimitWithContext(ctx); err == nil {
+		for _, limit := range limits {
+			if limit.Resource == process.RLIMIT_NOFILE {
+				s.usage.FDLimit = limit.Soft
+			}
+		}
+	}
+	s.lastTime = time.Now()
+	s.lastCPU, _ = s.cpuSeconds(ctx)
+	return s, nil
+}
+
+func (s *resourceSampler) cpuSeconds(ctx context.Context) (float64, error) {
+	times, err := s.proc.TimesWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return times.User + times.System, nil
+}
+
+// sample records a single sample. Values that can't be determined on the
+// current platform are left at zero.
+func (s *resourceSampler) sample(ctx context.Context) {
+	now := time.Now()
+	sample := ResourceSample{
+		Time:       now,
+		Goroutines: runtime.NumGoroutine(),
+	}
+	if cpuSeconds, err := s.cpuSeconds(ctx); err == nil {
+		if elapsed := now.Sub(s.lastTime).Seconds(); elapsed > 0 {
+			sample.CPUPercent = (cpuSeconds - s.lastCPU) / elapsed * 100
+		}
+		s.lastCPU = cpuSeconds
+	}
+	s.lastTime = now
+	if mem, err := s.proc.MemoryInfoWithContext(ctx); err == nil {
+		sample.RSSBytes = mem.RSS
+	}
+	if fds, err := s.proc.NumFDsWithContext(ctx); err == nil {
+		sample.OpenFDs = fds
+	}
+
+	s.usage.Samples = append(s.usage.Samples, sample)
+	s.usage.CPUPercentMax = max(s.usage.CPUPercentMax, sample.CPUPercent)
+	s.usage.RSSBytesMax = max(s.usage.RSSBytesMax, sample.RSSBytes)
+	s.usage.OpenFDsMax = max(s.usage.OpenFDsMax, sample.OpenFDs)
+	s.usage.GoroutinesMax = max(s.usage.GoroutinesMax, sample.Goroutines)
+}
+
+// summary returns the resource usage recorded so far.
+func (s *resourceSampler) summary() ResourceUsage {
+	usage := s.usage
+	if len(usage.Samples) > 0 {
+		var total float64
+		for _, sample := range usage.Samples {
+			total += sample.CPUPercent
+		}
+		usage.CPUPercentMean = total / float64(len(usage.Samples))
+	}
+	return usage
+}
+
+// sampleResources samples the resource usage of the process until the context
+// is canceled, takes a final sample and stores the summary in the harness.
+func (h *TestHarness) sampleResources(ctx context.Context) {
+	sampler, err := newResourceSampler(ctx)
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.resourceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			sampler.sample(context.WithoutCancel(ctx))
+			usage := sampler.summary()
+			h.mut.Lock()
+			h.resourceUsage = &usage
+			h.mut.Unlock()
+			return
+		case <-ticker.C:
+			sampler.sample(ctx)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_ResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, opts ...harness.Option) harness.Results {
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{}, opts...)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				t := time.NewTimer(50 * time.Millisecond)
+				defer t.Stop()
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-t.C:
+					return nil
+				}
+			},
+		})
+		err := h.Run(testutil.Context(t, testutil.WaitShort))
+		require.NoError(t, err)
+		return h.Results()
+	}
+
+	t.Run("Sampled", func(t *testing.T) {
+		t.Parallel()
+
+		res := run(t, harness.WithResourceUsage(10*time.Millisecond))
+		require.NotNil(t, res.ResourceUsage)
+		u := res.ResourceUsage
+		require.NotEmpty(t, u.Samples)
+		require.Positive(t, u.NumCPU)
+		require.Positive(t, u.RSSBytesMax)
+		require.Positive(t, u.GoroutinesMax)
+		require.GreaterOrEqual(t, u.CPUPercentMax, u.CPUPercentMean)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "Load generator resource usage")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		res := run(t)
+		require.Nil(t, res.ResourceUsage)
+	})
+
+	t.Run("Saturation", func(t *testing.T) {
+		t.Parallel()
+
+		u := harness.ResourceUsage{
+			NumCPU:        2,
+			CPUPercentMax: 150,
+			FDLimit:       1024,
+			OpenFDsMax:    100,
+		}
+		require.False(t, u.CPUSaturated())
+		require.False(t, u.FDsSaturated())
+
+		u.CPUPercentMax = 195
+		u.OpenFDsMax = 1000
+		require.True(t, u.CPUSaturated())
+		require.True(t, u.FDsSaturated())
+
+		res := harness.Results{TotalRuns: 1, TotalPass: 1, ResourceUsage: &u}
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "close to saturating its CPUs")
+		require.Contains(t, out.String(), "close to its file descriptor limit")
+	})
+}
@@ -2,40 +2,161 @@ package harness
 
 import (
 	"bufio"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/httpapi"
 )
 
+// ResultsSchemaVersion is the version of the JSON schema produced when encoding
+// Results. It must be incremented whenever a field is removed or changes
+// meaning so that consumers can detect files they don't understand.
+//
+// Version 2 excludes warmup runs from TotalRuns, and Runs no longer contains
+// every run if results were discarded, see TotalDiscarded.
+const ResultsSchemaVersion = 2
+
+// Well-known metric names that runners implementing Collectable can use to
+// report the amount of data they transferred. These are surfaced as dedicated
+// columns in exported results.
+const (
+	BytesReadMetric    = "bytes_read"
+	BytesWrittenMetric = "bytes_written"
+)
+
 // Results is the full compiled results for a set of test runs.
 type Results struct {
-	TotalRuns int              `json:"total_runs"`
-	TotalPass int              `json:"total_pass"`
-	TotalFail int              `json:"total_fail"`
+	SchemaVersion int `json:"schema_version"`
+	// Seed is the seed of the harness random number generators. Running the
+	// test again with the same seed reproduces its randomness.
+	Seed int64 `json:"seed,omitempty"`
+	// Shard is the shard of the test that the harness ran, if the test was
+	// sharded with WithShard.
+	Shard *Shard `json:"shard,omitempty"`
+
+	TotalRuns int `json:"total_runs"`
+	TotalPass int `json:"total_pass"`
+	TotalFail int `json:"total_fail"`
+	// TotalTimedOut is the number of failed runs that exceeded their
+	// individual timeout.
+	TotalTimedOut int `json:"total_timed_out"`
+	// TotalTestTimedOut is the number of failed runs that were still in
+	// progress when the overall timeout of the test was reached. Since these
+	// runs didn't necessarily fail on their own, they are counted separately
+	// from the runs that exceeded their individual timeout.
+	TotalTestTimedOut int `json:"total_test_timed_out"`
+	// TotalPassAfterRetry is the number of passed runs that failed at least
+	// once before passing.
+	TotalPassAfterRetry int `json:"total_pass_after_retry"`
+	// TotalWarmup is the number of runs that were part of the warmup phase.
+	// These runs are included in Runs, but not in any of the other totals or
+	// in Latency.
+	TotalWarmup int `json:"total_warmup"`
+	// TotalSkipped is the number of runs that were never started because the
+	// harness was drained. Like warmup runs, these are included in Runs only.
+	TotalSkipped int `json:"total_skipped"`
+	// TotalChaosAffected is the number of measured runs that were in
+	// progress while a fault injected by a chaos hook was in effect.
+	TotalChaosAffected int `json:"total_chaos_affected"`
+	// TotalDiscarded is the number of finished runs whose individual results
+	// were not retained because of WithResultRetention. These runs are
+	// included in all other totals, but not in Runs.
+	TotalDiscarded int `json:"total_discarded,omitempty"`
+
 	Elapsed   httpapi.Duration `json:"elapsed"`
 	ElapsedMS int64            `json:"elapsed_ms"`
+	// AvgDuration is the mean duration of the measured runs, including those
+	// that are not in Runs because their results were discarded.
+	AvgDuration httpapi.Duration `json:"avg_duration"`
 
 	Runs map[string]RunResult `json:"runs"`
+	// Latency contains run duration percentiles keyed by test name.
+	Latency map[string]LatencySummary `json:"latency,omitempty"`
+	// PhaseLatency contains the duration percentiles of the setup, verify and
+	// run phases keyed by test name and phase, for tests whose runners
+	// implement Setupable or Verifiable, along with the phases marked with
+	// StartPhase.
+	PhaseLatency map[string]map[string]LatencySummary `json:"phase_latency,omitempty"`
+	// Tags contains a breakdown of the results keyed by tag key and value.
+	Tags map[string]map[string]TagSummary `json:"tags,omitempty"`
+	// Metrics contains a summary of the numeric metrics reported by runners
+	// keyed by metric name.
+	Metrics map[string]MetricSummary `json:"metrics,omitempty"`
+	// Bandwidth contains a summary of the bytes transferred by runners
+	// implementing ByteCollectable keyed by channel.
+	Bandwidth map[string]BandwidthSummary `json:"bandwidth,omitempty"`
+	// Chaos contains every fault injected by chaos hooks, sorted by the time
+	// it was injected.
+	Chaos []ChaosEvent `json:"chaos,omitempty"`
+	// Profiles contains every profile of the deployment captured during the
+	// test, sorted by the time it was captured.
+	Profiles []ProfileCapture `json:"profiles,omitempty"`
+	// Retained contains the failed runs whose resources were retained rather
+	// than cleaned up, sorted by full ID. See WithRetainFailedRuns.
+	Retained []RetainedRun `json:"retained,omitempty"`
+	// ResourceUsage contains the resource usage of the load generator during
+	// the test, if it was sampled.
+	ResourceUsage *ResourceUsage `json:"resource_usage,omitempty"`
+	// CircuitBreaker is set if the circuit breaker aborted the test.
+	CircuitBreaker *CircuitBreakerTrip `json:"circuit_breaker,omitempty"`
+	// Capacity is set if the test was run with a
+	// LatencyTargetExecutionStrategy.
+	Capacity *CapacityResult `json:"capacity,omitempty"`
+	// Snapshots summarize the runs that completed during every interval of
+	// the test, if it was run with WithSnapshots.
+	Snapshots []Snapshot `json:"snapshots,omitempty"`
 }
 
 // RunResult is the result of a single test run.
 type RunResult struct {
-	FullID     string           `json:"full_id"`
-	TestName   string           `json:"test_name"`
-	ID         string           `json:"id"`
-	Logs       string           `json:"logs"`
-	Error      error            `json:"error"`
+	FullID   string `json:"full_id"`
+	TestName string `json:"test_name"`
+	ID       string `json:"id"`
+	Logs     string `json:"logs"`
+	Error    error  `json:"error"`
+	TimedOut bool   `json:"timed_out"`
+	// TestTimedOut is set if the run failed because the overall timeout of
+	// the test was reached while it was in progress, rather than its own.
+	TestTimedOut bool `json:"test_timed_out,omitempty"`
+	Attempts     int  `json:"attempts"`
+	Warmup       bool `json:"warmup"`
+	Skipped      bool `json:"skipped"`
+	// SkipReason is why the run was skipped, if it was.
+	SkipReason string            `json:"skip_reason,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	// ArtifactDir is the directory containing the artifacts of the run, if
+	// any were collected.
+	ArtifactDir string `json:"artifact_dir,omitempty"`
+	// StartedAt and Duration are those of the last attempt of the run.
 	StartedAt  time.Time        `json:"started_at"`
 	Duration   httpapi.Duration `json:"duration"`
 	DurationMS int64            `json:"duration_ms"`
 	Metrics    map[string]any   `json:"metrics,omitempty"`
+	// NumericMetrics are the metrics reported by runners implementing
+	// NumericCollectable.
+	NumericMetrics map[string]float64 `json:"numeric_metrics,omitempty"`
+	// Bytes are the bytes transferred by runners implementing
+	// ByteCollectable keyed by channel.
+	Bytes map[string]ByteCount `json:"bytes,omitempty"`
+	// ChaosHooks are the names of the chaos hooks whose faults were in effect
+	// while the run was in progress.
+	ChaosHooks []string `json:"chaos_hooks,omitempty"`
+	// Rerun is set if the result is that of a rerun merged into the results
+	// of a previous test with MergeRerun.
+	Rerun bool `json:"rerun,omitempty"`
+	// Phases contains the duration of each phase of the last attempt, if the
+	// runner implements Setupable or Verifiable or marked phases with
+	// StartPhase.
+	Phases map[string]httpapi.Duration `json:"phases,omitempty"`
 }
 
 // MarshalJSON implements json.Marhshaler for RunResult.
@@ -60,15 +181,26 @@ func (r *TestRun) Result() RunResult {
 	}
 
 	return RunResult{
-		FullID:     r.FullID(),
-		TestName:   r.testName,
-		ID:         r.id,
-		Logs:       r.logs.String(),
-		Error:      r.err,
-		StartedAt:  r.started,
-		Duration:   httpapi.Duration(r.duration),
-		DurationMS: r.duration.Milliseconds(),
-		Metrics:    r.metrics,
+		FullID:         r.FullID(),
+		TestName:       r.testName,
+		ID:             r.id,
+		Logs:           r.logs.String(),
+		Error:          r.err,
+		TimedOut:       r.timedOut,
+		TestTimedOut:   r.testTimedOut,
+		Attempts:       r.attempts,
+		Warmup:         r.warmup,
+		Skipped:        r.skipped,
+		SkipReason:     r.skipReason,
+		Tags:           maps.Clone(r.tags),
+		ArtifactDir:    r.artifactDir,
+		StartedAt:      r.started,
+		Duration:       httpapi.Duration(r.duration),
+		DurationMS:     r.duration.Milliseconds(),
+		Metrics:        r.metrics,
+		NumericMetrics: r.numericMetrics,
+		Bytes:          r.byteCounts,
+		Phases:         r.phaseDurations(),
 	}
 }
 
@@ -83,39 +215,186 @@ func (h *TestHarness) Results() Results {
 		panic("harness has not finished")
 	}
 
+	chaos := h.chaosResults()
 	results := Results{
-		TotalRuns: len(h.runs),
-		Runs:      make(map[string]RunResult, len(h.runs)),
-		Elapsed:   httpapi.Duration(h.elapsed),
-		ElapsedMS: h.elapsed.Milliseconds(),
+		SchemaVersion:  ResultsSchemaVersion,
+		Seed:           h.seed,
+		Shard:          h.shardResult(),
+		Runs:           make(map[string]RunResult, len(h.runs)),
+		Elapsed:        httpapi.Duration(h.elapsed),
+		ElapsedMS:      h.elapsed.Milliseconds(),
+		Latency:        make(map[string]LatencySummary, len(h.latencies)),
+		Chaos:          chaos,
+		Profiles:       h.profileCaptures,
+		Retained:       h.retained,
+		ResourceUsage:  h.resourceUsage,
+		CircuitBreaker: h.circuitBreakerTrip(),
+		Capacity:       h.capacity,
+		Snapshots:      h.snapshots.snapshots,
+	}
+	for testName, hist := range h.latencies {
+		results.Latency[testName] = hist.Summary()
+	}
+	for testName, phases := range h.phaseLatencies {
+		if results.PhaseLatency == nil {
+			results.PhaseLatency = make(map[string]map[string]LatencySummary, len(h.phaseLatencies))
+		}
+		results.PhaseLatency[testName] = make(map[string]LatencySummary, len(phases))
+		for phase, hist := range phases {
+			results.PhaseLatency[testName][phase] = hist.Summary()
+		}
+	}
+	agg := h.aggregate
+	if agg == nil {
+		agg = &resultAggregator{trackWindows: len(chaos) > 0}
 	}
 	for _, run := range h.runs {
+		if run.discarded {
+			continue
+		}
 		runRes := run.Result()
+		if !runRes.Skipped {
+			runRes.ChaosHooks = activeChaosHooks(chaos, runRes.StartedAt, runRes.StartedAt.Add(time.Duration(runRes.Duration)))
+		}
 		results.Runs[runRes.FullID] = runRes
-
-		if runRes.Error == nil {
-			results.TotalPass++
-		} else {
-			results.TotalFail++
+		if h.aggregate == nil {
+			agg.add(runRes)
 		}
 	}
+	agg.apply(&results, h.tagLatencies)
 
 	return results
 }
 
+// WriteJSON writes the results as JSON to the given writer. The output follows
+// the schema identified by ResultsSchemaVersion.
+func (r *Results) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	err := enc.Encode(r)
+	if err != nil {
+		return xerrors.Errorf("encode JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the results as CSV to the given writer, with a header row
+// followed by one row per test run sorted by full ID.
+func (r *Results) WriteCSV(w io.Writer) error {
+	metricNames := r.metricNames()
+	byteChannels := r.byteChannels()
+	byteColumns := make([]string, 0, len(byteChannels)*2)
+	for _, channel := range byteChannels {
+		byteColumns = append(byteColumns, BytesReadMetric+"_"+channel, BytesWrittenMetric+"_"+channel)
+	}
+	cw := csv.NewWriter(w)
+	err := cw.Write(append([]string{
+		"full_id",
+		"test_name",
+		"id",
+		"started_at",
+		"duration_ms",
+		"error",
+		"timed_out",
+		"attempts",
+		"warmup",
+		"skipped",
+		"tags",
+		BytesReadMetric,
+		BytesWrittenMetric,
+	}, append(byteColumns, metricNames...)...))
+	if err != nil {
+		return xerrors.Errorf("write CSV header: %w", err)
+	}
+
+	keys := maps.Keys(r.Runs)
+	slices.Sort(keys)
+	for _, key := range keys {
+		run := r.Runs[key]
+		var errStr string
+		if run.Error != nil {
+			errStr = run.Error.Error()
+		}
+		bytesRead := csvMetric(run.Metrics, BytesReadMetric)
+		bytesWritten := csvMetric(run.Metrics, BytesWrittenMetric)
+		// Runners that only report bytes by channel still get totals.
+		if total, ok := run.totalBytes(); ok {
+			if bytesRead == "" {
+				bytesRead = strconv.FormatInt(total.Read, 10)
+			}
+			if bytesWritten == "" {
+				bytesWritten = strconv.FormatInt(total.Written, 10)
+			}
+		}
+		row := []string{
+			run.FullID,
+			run.TestName,
+			run.ID,
+			run.StartedAt.Format(time.RFC3339Nano),
+			strconv.FormatInt(run.DurationMS, 10),
+			errStr,
+			strconv.FormatBool(run.TimedOut),
+			strconv.Itoa(run.Attempts),
+			strconv.FormatBool(run.Warmup),
+			strconv.FormatBool(run.Skipped),
+			formatTags(run.Tags),
+			bytesRead,
+			bytesWritten,
+		}
+		for _, channel := range byteChannels {
+			var read, written string
+			if count, ok := run.Bytes[channel]; ok {
+				read = strconv.FormatInt(count.Read, 10)
+				written = strconv.FormatInt(count.Written, 10)
+			}
+			row = append(row, read, written)
+		}
+		for _, name := range metricNames {
+			var value string
+			if v, ok := run.NumericMetrics[name]; ok {
+				value = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+			row = append(row, value)
+		}
+		err = cw.Write(row)
+		if err != nil {
+			return xerrors.Errorf("write CSV row for %q: %w", run.FullID, err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return xerrors.Errorf("flush CSV: %w", err)
+	}
+	return nil
+}
+
+func csvMetric(metrics map[string]any, name string) string {
+	v, ok := metrics[name]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
 // PrintText prints the results as human-readable text to the given writer.
 func (r *Results) PrintText(w io.Writer) {
-	var totalDuration time.Duration
 	keys := maps.Keys(r.Runs)
 	slices.Sort(keys)
 	for _, key := range keys {
 		run := r.Runs[key]
-		totalDuration += time.Duration(run.Duration)
-		if run.Error == nil {
+		if run.Warmup || run.Skipped || run.Error == nil {
 			continue
 		}
 
-		_, _ = fmt.Fprintf(w, "\n== FAIL: %s\n\n", run.FullID)
+		switch {
+		case run.TimedOut:
+			_, _ = fmt.Fprintf(w, "\n== TIMEOUT: %s\n\n", run.FullID)
+		case run.TestTimedOut:
+			_, _ = fmt.Fprintf(w, "\n== TEST TIMEOUT: %s\n\n", run.FullID)
+		default:
+			_, _ = fmt.Fprintf(w, "\n== FAIL: %s\n\n", run.FullID)
+		}
 		_, _ = fmt.Fprintf(w, "\tError: %s\n\n", run.Error)
 
 		// Print log lines indented.
@@ -140,9 +419,117 @@ func (r *Results) PrintText(w io.Writer) {
 		return
 	}
 	_, _ = fmt.Fprintf(w, "\tPass:  %d\n", r.TotalPass)
+	if r.TotalPassAfterRetry > 0 {
+		_, _ = fmt.Fprintf(w, "\t       (%d after retrying)\n", r.TotalPassAfterRetry)
+	}
 	_, _ = fmt.Fprintf(w, "\tFail:  %d\n", r.TotalFail)
+	if r.TotalTimedOut > 0 {
+		_, _ = fmt.Fprintf(w, "\t       (%d timed out)\n", r.TotalTimedOut)
+	}
+	if r.TotalTestTimedOut > 0 {
+		_, _ = fmt.Fprintf(w, "\t       (%d killed by the test timeout)\n", r.TotalTestTimedOut)
+	}
 	_, _ = fmt.Fprintf(w, "\tTotal: %d\n", r.TotalRuns)
+	if r.TotalDiscarded > 0 {
+		_, _ = fmt.Fprintf(w, "\t       (%d run results not retained)\n", r.TotalDiscarded)
+	}
+	if r.TotalWarmup > 0 {
+		_, _ = fmt.Fprintf(w, "\t       (%d warmup runs excluded)\n", r.TotalWarmup)
+	}
+	if r.TotalSkipped > 0 {
+		skipped := map[string]int{}
+		for _, run := range r.Runs {
+			if run.Skipped {
+				skipped[run.SkipReason]++
+			}
+		}
+		reasons := maps.Keys(skipped)
+		slices.Sort(reasons)
+		for _, reason := range reasons {
+			count := skipped[reason]
+			if reason == "" {
+				reason = "test was interrupted"
+			}
+			_, _ = fmt.Fprintf(w, "\t       (%d runs skipped, %s)\n", count, reason)
+		}
+	}
+	if r.CircuitBreaker != nil {
+		_, _ = fmt.Fprintf(w, "\n\tAborted by circuit breaker: %s\n", r.CircuitBreaker)
+	}
+	r.printErrorBreakdown(w)
 	_, _ = fmt.Fprintln(w, "")
 	_, _ = fmt.Fprintf(w, "\tTotal duration: %s\n", time.Duration(r.Elapsed))
-	_, _ = fmt.Fprintf(w, "\tAvg. duration:  %s\n", totalDuration/time.Duration(r.TotalRuns))
+	_, _ = fmt.Fprintf(w, "\tAvg. duration:  %s\n", time.Duration(r.AvgDuration))
+	if r.Seed != 0 {
+		_, _ = fmt.Fprintf(w, "\tSeed:           %d\n", r.Seed)
+	}
+	if r.Shard != nil {
+		_, _ = fmt.Fprintf(w, "\tShard:          %d/%d\n", r.Shard.Index+1, r.Shard.Count)
+	}
+
+	testNames := maps.Keys(r.Latency)
+	slices.Sort(testNames)
+	for _, testName := range testNames {
+		l := r.Latency[testName]
+		_, _ = fmt.Fprintf(w, "\n\tLatency (%s, %d runs):\n", testName, l.Count)
+		_, _ = fmt.Fprintf(w, "\t\tP50:   %s\n", time.Duration(l.P50))
+		_, _ = fmt.Fprintf(w, "\t\tP90:   %s\n", time.Duration(l.P90))
+		_, _ = fmt.Fprintf(w, "\t\tP95:   %s\n", time.Duration(l.P95))
+		_, _ = fmt.Fprintf(w, "\t\tP99:   %s\n", time.Duration(l.P99))
+		_, _ = fmt.Fprintf(w, "\t\tP99.9: %s\n", time.Duration(l.P999))
+		_, _ = fmt.Fprintf(w, "\t\tMax:   %s\n", time.Duration(l.Max))
+	}
+
+	r.printSlowestRuns(w)
+	r.printPhaseLatency(w)
+	r.printCapacity(w)
+	r.printResourceUsage(w)
+	r.printProfiles(w)
+	r.printMetrics(w)
+	r.printBandwidth(w)
+	r.printSnapshots(w)
+	r.printTags(w)
+	r.printRetained(w)
+}
+
+// printCapacity prints the capacity discovered by a
+// LatencyTargetExecutionStrategy.
+func (r *Results) printCapacity(w io.Writer) {
+	c := r.Capacity
+	if c == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\n\tCapacity (P95 target %s, %d intervals):\n", time.Duration(c.Target), len(c.Steps))
+	if c.Capacity == 0 {
+		_, _ = fmt.Fprintln(w, "\t\tNo interval met the target.")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\t\tConcurrency: %d\n", c.Capacity)
+	_, _ = fmt.Fprintf(w, "\t\tThroughput:  %.3f runs/s\n", c.Throughput)
+}
+
+// printResourceUsage prints the resource usage of the load generator, warning
+// if it may have been the bottleneck of the test.
+func (r *Results) printResourceUsage(w io.Writer) {
+	u := r.ResourceUsage
+	if u == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\n\tLoad generator resource usage (%d samples):\n", len(u.Samples))
+	_, _ = fmt.Fprintf(w, "\t\tCPU:        %.1f%% mean, %.1f%% max (%d CPUs)\n", u.CPUPercentMean, u.CPUPercentMax, u.NumCPU)
+	_, _ = fmt.Fprintf(w, "\t\tMemory:     %d MiB max RSS\n", u.RSSBytesMax/(1<<20))
+	if u.FDLimit > 0 {
+		_, _ = fmt.Fprintf(w, "\t\tFDs:        %d max (limit %d)\n", u.OpenFDsMax, u.FDLimit)
+	} else {
+		_, _ = fmt.Fprintf(w, "\t\tFDs:        %d max\n", u.OpenFDsMax)
+	}
+	_, _ = fmt.Fprintf(w, "\t\tGoroutines: %d max\n", u.GoroutinesMax)
+	if u.CPUSaturated() {
+		_, _ = fmt.Fprintln(w, "\t\tWarning: the load generator was close to saturating its CPUs, results may be limited by the load generator rather than the deployment.")
+	}
+	if u.FDsSaturated() {
+		_, _ = fmt.Fprintln(w, "\t\tWarning: the load generator was close to its file descriptor limit, results may be limited by the load generator rather than the deployment.")
+	}
 }
@@ -2,7 +2,6 @@ package harness_test
 
 import (
 	"bytes"
-	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,9 +31,10 @@ func Test_Results(t *testing.T) {
 
 	now := time.Date(2023, 10, 5, 12, 3, 56, 395813665, time.UTC)
 	results := harness.Results{
-		TotalRuns: 10,
-		TotalPass: 8,
-		TotalFail: 2,
+		SchemaVersion: harness.ResultsSchemaVersion,
+		TotalRuns:     10,
+		TotalPass:     8,
+		TotalFail:     2,
 		Runs: map[string]harness.RunResult{
 			"test-0/0": {
 				FullID:     "test-0/0",
@@ -42,6 +42,7 @@ func Test_Results(t *testing.T) {
 				ID:         "0",
 				Logs:       "test-0/0 log line 1\ntest-0/0 log line 2",
 				Error:      xerrors.New("test-0/0 error"),
+				Attempts:   1,
 				StartedAt:  now,
 				Duration:   httpapi.Duration(time.Second),
 				DurationMS: 1000,
@@ -56,6 +57,7 @@ func Test_Results(t *testing.T) {
 				ID:         "1",
 				Logs:       "test-0/1 log line 1\ntest-0/1 log line 2",
 				Error:      nil,
+				Attempts:   1,
 				StartedAt:  now.Add(333 * time.Millisecond),
 				Duration:   httpapi.Duration(time.Second),
 				DurationMS: 1000,
@@ -70,6 +72,7 @@ func Test_Results(t *testing.T) {
 				ID:         "2",
 				Logs:       "test-0/2 log line 1\ntest-0/2 log line 2",
 				Error:      testError{hidden: xerrors.New("test-0/2 error")},
+				Attempts:   1,
 				StartedAt:  now.Add(666 * time.Millisecond),
 				Duration:   httpapi.Duration(time.Second),
 				DurationMS: 1000,
@@ -79,8 +82,9 @@ func Test_Results(t *testing.T) {
 				},
 			},
 		},
-		Elapsed:   httpapi.Duration(time.Second),
-		ElapsedMS: 1000,
+		Elapsed:     httpapi.Duration(time.Second),
+		ElapsedMS:   1000,
+		AvgDuration: httpapi.Duration(300 * time.Millisecond),
 	}
 
 	wantText := `
@@ -104,21 +108,41 @@ Test results:
 	Fail:  2
 	Total: 10
 
+	Errors (2 failed runs):
+		2x test-<n>/<n> error (e.g. test-0/0)
+
 	Total duration: 1s
 	Avg. duration:  300ms
+
+	Slowest runs:
+		test-0/0: 1s (failed)
+		test-0/1: 1s (passed)
+		test-0/2: 1s (failed)
 `
 	wantJSON := `{
+	"schema_version": 2,
 	"total_runs": 10,
 	"total_pass": 8,
 	"total_fail": 2,
+	"total_timed_out": 0,
+	"total_test_timed_out": 0,
+	"total_pass_after_retry": 0,
+	"total_warmup": 0,
+	"total_skipped": 0,
+	"total_chaos_affected": 0,
 	"elapsed": "1s",
 	"elapsed_ms": 1000,
+	"avg_duration": "300ms",
 	"runs": {
 		"test-0/0": {
 			"full_id": "test-0/0",
 			"test_name": "test-0",
 			"id": "0",
 			"logs": "test-0/0 log line 1\ntest-0/0 log line 2",
+			"timed_out": false,
+			"attempts": 1,
+			"warmup": false,
+			"skipped": false,
 			"started_at": "2023-10-05T12:03:56.395813665Z",
 			"duration": "1s",
 			"duration_ms": 1000,
@@ -133,6 +157,10 @@ Test results:
 			"test_name": "test-0",
 			"id": "1",
 			"logs": "test-0/1 log line 1\ntest-0/1 log line 2",
+			"timed_out": false,
+			"attempts": 1,
+			"warmup": false,
+			"skipped": false,
 			"started_at": "2023-10-05T12:03:56.728813665Z",
 			"duration": "1s",
 			"duration_ms": 1000,
@@ -147,6 +175,10 @@ Test results:
 			"test_name": "test-0",
 			"id": "2",
 			"logs": "test-0/2 log line 1\ntest-0/2 log line 2",
+			"timed_out": false,
+			"attempts": 1,
+			"warmup": false,
+			"skipped": false,
 			"started_at": "2023-10-05T12:03:57.061813665Z",
 			"duration": "1s",
 			"duration_ms": 1000,
@@ -158,6 +190,11 @@ Test results:
 		}
 	}
 }
+`
+	wantCSV := `full_id,test_name,id,started_at,duration_ms,error,timed_out,attempts,warmup,skipped,tags,bytes_read,bytes_written
+test-0/0,test-0,0,2023-10-05T12:03:56.395813665Z,1000,test-0/0 error,false,1,false,false,,1024,2048
+test-0/1,test-0,1,2023-10-05T12:03:56.728813665Z,1000,,false,1,false,false,,512,1024
+test-0/2,test-0,2,2023-10-05T12:03:57.061813665Z,1000,test-0/2 error,false,1,false,false,,2048,4096
 `
 	wd, err := os.Getwd()
 	require.NoError(t, err)
@@ -170,10 +207,14 @@ Test results:
 	assert.Empty(t, cmp.Diff(wantText, out.String()), "text result does not match (-want +got)")
 
 	out.Reset()
-	enc := json.NewEncoder(out)
-	enc.SetIndent("", "\t")
-	err = enc.Encode(results)
+	err = results.WriteJSON(out)
 	require.NoError(t, err)
 
 	assert.Empty(t, cmp.Diff(wantJSON, out.String()), "JSON result does not match (-want +got)")
+
+	out.Reset()
+	err = results.WriteCSV(out)
+	require.NoError(t, err)
+
+	assert.Empty(t, cmp.Diff(wantCSV, out.String()), "CSV result does not match (-want +got)")
 }
@@ -3,10 +3,17 @@ package harness
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"math/rand"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"golang.org/x/xerrors"
 )
 
@@ -31,6 +38,30 @@ type Cleanable interface {
 	Cleanup(ctx context.Context, id string, logs io.Writer) error
 }
 
+// Setupable is an optional extension to Runnable that allows for pre-test setup
+// that is traced separately from the test itself. If Setup returns an error,
+// Run is not called and the test run fails.
+type Setupable interface {
+	Runnable
+	// Setup should prepare anything the test requires before it's run.
+	Setup(ctx context.Context, id string, logs io.Writer) error
+}
+
+// Verifiable is an optional extension to Runnable that allows for verifying
+// that the resources the test requires are reachable, e.g. that the agent of a
+// workspace is connected and its apps are healthy, between setup and the test
+// itself. If Verify returns an error, Run is not called and the test run fails.
+//
+// The durations of the setup, verify and run phases of runs whose runners
+// implement Setupable or Verifiable are recorded separately in the results,
+// along with any phases marked with StartPhase.
+type Verifiable interface {
+	Runnable
+	// Verify should return once the resources the test requires are
+	// reachable, or an error if they don't become reachable.
+	Verify(ctx context.Context, id string, logs io.Writer) error
+}
+
 // Collectable is an optional extension to Runnable that exposes additional
 // metrics from the runner.
 type Collectable interface {
@@ -64,21 +95,105 @@ func (h *TestHarness) RegisterRun(run *TestRun) {
 		panic("cannot add test with duplicate full ID: " + run.FullID())
 	}
 	h.runIDs[run.FullID()] = struct{}{}
+	h.configureRun(run)
 	h.runs = append(h.runs, run)
 }
 
+// configureRun applies the configuration of the harness to the given run.
+func (h *TestHarness) configureRun(run *TestRun) {
+	if h.tracer != nil {
+		run.tracer = h.tracer
+	}
+	run.retry = h.retry
+	if h.logStream != nil {
+		run.logStream = newLinePrefixWriter(h.logStream, "["+run.FullID()+"] ")
+	}
+	if h.artifactDir != "" {
+		run.artifactDir = filepath.Join(h.artifactDir, filepath.FromSlash(run.FullID()))
+	}
+	if h.logDir != "" {
+		run.logFilePath = filepath.Join(h.logDir, filepath.FromSlash(run.FullID())+logFileExt)
+	}
+}
+
+// RetryPolicy configures how failed test runs are retried.
+type RetryPolicy struct {
+	// Retries is the number of times a failed run is retried. Zero disables
+	// retries.
+	Retries int
+	// Backoff is the delay before the first retry. It doubles on every
+	// subsequent retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries. Zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// backoff returns the delay before retrying after the given attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
 // TestRun is a single test run and it's accompanying state.
 type TestRun struct {
 	testName string
 	id       string
 	runner   Runnable
+	tracer   trace.Tracer
 
 	logs     *syncBuffer
+	span     trace.SpanContext
 	done     chan struct{}
 	started  time.Time
 	duration time.Duration
 	err      error
-	metrics  map[string]any
+	timedOut bool
+	// testTimedOut is set if the run failed because the overall timeout of
+	// the test was reached while it was in progress.
+	testTimedOut bool
+	attempts     int
+	retry        RetryPolicy
+	warmup       bool
+	skipped      bool
+	// retained is set if the resources of the run are retained rather than
+	// cleaned up, see WithRetainFailedRuns.
+	retained bool
+	// skipReason is why the run was skipped.
+	skipReason string
+	tags       map[string]string
+	// artifactDir is cleared after the run if no artifacts were written.
+	artifactDir    string
+	metrics        map[string]any
+	numericMetrics map[string]float64
+	byteCounts     map[string]ByteCount
+	// logStream receives a copy of everything written to logs, if set.
+	logStream *linePrefixWriter
+	// logFilePath is the path of the file the logs are written to as JSON
+	// lines, if set.
+	logFilePath string
+	// rng is the random number generator returned by Rand, seeded by the
+	// harness.
+	rng *rand.Rand
+	// metadata is made available to the runner through Metadata, if set by
+	// the harness.
+	metadata *RunMetadata
+	// discarded is set once the result of the run has been released.
+	discarded bool
+	// dependencies are the runs that must pass before the run starts.
+	dependencies []*TestRun
+	// finished is closed by the harness once the run has finished or has
+	// been skipped, for runs that depend on it.
+	finished chan struct{}
+	// phases holds the duration of each phase of the last attempt, if the
+	// runner has phases other than run or marked phases with StartPhase.
+	phasesMu sync.Mutex
+	phases   map[string]time.Duration
 }
 
 func NewTestRun(testName string, id string, runner Runnable) *TestRun {
@@ -86,6 +201,7 @@ func NewTestRun(testName string, id string, runner Runnable) *TestRun {
 		testName: testName,
 		id:       id,
 		runner:   runner,
+		tracer:   noop.NewTracerProvider().Tracer(TracerName),
 	}
 }
 
@@ -95,23 +211,117 @@ func (r *TestRun) FullID() string {
 
 // Run executes the Run function with a self-managed log writer, panic handler,
 // error recording and duration recording. The test error is returned.
+//
+// Each test run is recorded as its own trace, linked to the trace in the given
+// context, with child spans for the setup, verify and run phases.
 func (r *TestRun) Run(ctx context.Context) (err error) {
 	r.logs = &syncBuffer{
 		buf: new(bytes.Buffer),
 	}
+	if r.logStream != nil {
+		r.logs.tee = r.logStream
+		defer r.logStream.Flush()
+	}
+	defer r.startLogFile(LogPhaseRun)()
 	r.done = make(chan struct{})
 	defer close(r.done)
 
-	r.started = time.Now()
+	ctx, span := r.tracer.Start(ctx, r.FullID(),
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(
+			attribute.String("scaletest.test_name", r.testName),
+			attribute.String("scaletest.id", r.id),
+		),
+	)
+	r.span = span.SpanContext()
+	defer func() {
+		endSpan(span, err)
+	}()
+	if r.span.HasTraceID() {
+		_, _ = fmt.Fprintf(r.logs, "Trace ID: %s\n", r.span.TraceID())
+		_, _ = fmt.Fprintf(r.logs, "Span ID: %s\n\n", r.span.SpanID())
+	}
+
+	if r.rng != nil {
+		ctx = context.WithValue(ctx, randKey{}, r.rng)
+	}
+	ctx = r.withMetadata(ctx)
+	ctx = r.prepareArtifactDir(ctx)
+	defer func() {
+		r.finalizeArtifactDir(err)
+	}()
+
 	defer func() {
 		r.duration = time.Since(r.started)
 		r.err = err
-		c, ok := r.runner.(Collectable)
-		if !ok {
-			return
+		r.timedOut = err != nil && xerrors.Is(context.Cause(ctx), ErrRunTimeout)
+		r.testTimedOut = err != nil && testTimedOut(ctx)
+		if c, ok := r.runner.(Collectable); ok {
+			r.metrics = c.GetMetrics()
+		}
+		if c, ok := r.runner.(NumericCollectable); ok {
+			r.numericMetrics = c.GetNumericMetrics()
+		}
+		if c, ok := r.runner.(ByteCollectable); ok {
+			r.byteCounts = c.GetBytes()
 		}
-		r.metrics = c.GetMetrics()
 	}()
+	for {
+		// Only the last attempt is timed, so that retries and backoff delays
+		// don't skew the recorded latencies.
+		r.started = time.Now()
+		r.attempts++
+		err = r.attempt(ctx)
+		if err == nil || r.attempts > r.retry.Retries || ctx.Err() != nil {
+			break
+		}
+
+		// The resources of the failed attempt are cleaned up before retrying,
+		// as the runner replaces its state on the next attempt.
+		if cerr := r.cleanupAttempt(ctx); cerr != nil {
+			_, _ = fmt.Fprintf(r.logs, "\nAttempt %d failed, not retrying as its cleanup failed: %+v\n", r.attempts, cerr)
+			break
+		}
+
+		backoff := r.retry.backoff(r.attempts)
+		_, _ = fmt.Fprintf(r.logs, "\nAttempt %d failed, retrying in %s: %+v\n\n", r.attempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			//nolint:revive // we use named returns because we mutate it in a defer
+			return
+		case <-time.After(backoff):
+		}
+	}
+
+	//nolint:revive // we use named returns because we mutate it in a defer
+	return
+}
+
+// skip marks the test run as done without running it.
+func (r *TestRun) skip(reason string) {
+	r.logs = &syncBuffer{
+		buf: new(bytes.Buffer),
+	}
+	r.skipped = true
+	r.skipReason = reason
+	r.done = make(chan struct{})
+	close(r.done)
+}
+
+// discard releases the logs and metrics of the finished test run once its
+// result has been aggregated. Logs written during cleanup are still streamed
+// and written to the log file of the run, if configured.
+func (r *TestRun) discard() {
+	r.discarded = true
+	r.logs.discard()
+	r.metrics = nil
+	r.numericMetrics = nil
+	r.byteCounts = nil
+}
+
+// attempt runs the setup, verify and run phases of the test once.
+func (r *TestRun) attempt(ctx context.Context) (err error) {
 	defer func() {
 		e := recover()
 		if e != nil {
@@ -119,24 +329,95 @@ func (r *TestRun) Run(ctx context.Context) (err error) {
 		}
 	}()
 
-	err = r.runner.Run(ctx, r.id, r.logs)
+	s, setupable := r.runner.(Setupable)
+	v, verifiable := r.runner.(Verifiable)
+	r.phasesMu.Lock()
+	r.phases = nil
+	if setupable || verifiable {
+		r.phases = map[string]time.Duration{}
+	}
+	r.phasesMu.Unlock()
+	ctx = context.WithValue(ctx, testRunKey{}, r)
 
-	//nolint:revive // we use named returns because we mutate it in a defer
-	return
+	if setupable {
+		err = r.timePhase(ctx, "setup", func(ctx context.Context) error {
+			return s.Setup(ctx, r.id, r.logs)
+		})
+		if err != nil {
+			return xerrors.Errorf("setup: %w", err)
+		}
+	}
+
+	if verifiable {
+		err = r.timePhase(ctx, "verify", func(ctx context.Context) error {
+			return v.Verify(ctx, r.id, r.logs)
+		})
+		if err != nil {
+			return xerrors.Errorf("verify: %w", err)
+		}
+	}
+
+	return r.timePhase(ctx, "run", func(ctx context.Context) error {
+		return runAbandonable(ctx, func(ctx context.Context) error {
+			return r.runner.Run(ctx, r.id, r.logs)
+		})
+	})
 }
 
-func (r *TestRun) Cleanup(ctx context.Context) (err error) {
+// cleanupAttempt cleans up the resources of a failed attempt, if the runner is
+// Cleanable.
+func (r *TestRun) cleanupAttempt(ctx context.Context) (err error) {
 	c, ok := r.runner.(Cleanable)
 	if !ok {
 		return nil
 	}
 
+	defer func() {
+		e := recover()
+		if e != nil {
+			err = xerrors.Errorf("panic: %v", e)
+		}
+	}()
+
+	return r.tracePhase(ctx, "cleanup", func(ctx context.Context) error {
+		return c.Cleanup(ctx, r.id, r.logs)
+	})
+}
+
+// timePhase traces the given phase like tracePhase, and records its duration
+// if the durations of the phases of the run are recorded, i.e. if the runner
+// has phases other than run or marked any phases with StartPhase.
+func (r *TestRun) timePhase(ctx context.Context, phase string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := r.tracePhase(ctx, phase, fn)
+	r.phasesMu.Lock()
+	defer r.phasesMu.Unlock()
+	if r.phases != nil {
+		r.phases[phase] = time.Since(start)
+	}
+	return err
+}
+
+// cleanable returns whether the test run needs to be cleaned up, i.e. its
+// runner is Cleanable and it was executed.
+func (r *TestRun) cleanable() bool {
+	if _, ok := r.runner.(Cleanable); !ok {
+		return false
+	}
 	select {
 	case <-r.done:
 	default:
 		// Test wasn't executed, so we don't need to clean up.
+		return false
+	}
+	return !r.skipped && !r.retained
+}
+
+func (r *TestRun) Cleanup(ctx context.Context) (err error) {
+	if !r.cleanable() {
 		return nil
 	}
+	c, _ := r.runner.(Cleanable)
 
 	defer func() {
 		e := recover()
@@ -145,24 +426,123 @@ func (r *TestRun) Cleanup(ctx context.Context) (err error) {
 		}
 	}()
 
-	err = c.Cleanup(ctx, r.id, r.logs)
+	// The cleanup span belongs to the same trace as the run itself.
+	if r.span.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, r.span)
+	}
+	ctx = r.withMetadata(ctx)
+	if r.logStream != nil {
+		defer r.logStream.Flush()
+	}
+	defer r.startLogFile(LogPhaseCleanup)()
+	err = r.tracePhase(ctx, "cleanup", func(ctx context.Context) error {
+		return c.Cleanup(ctx, r.id, r.logs)
+	})
 	//nolint:revive // we use named returns because we mutate it in a defer
 	return
 }
 
+// tracePhase runs fn in a child span named after the test run and the given
+// phase.
+func (r *TestRun) tracePhase(ctx context.Context, phase string, fn func(ctx context.Context) error) (err error) {
+	ctx, span := r.tracer.Start(ctx, r.FullID()+" "+phase)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return fn(ctx)
+}
+
+// runAbandonable calls fn. If the context was configured by a
+// TimeoutExecutionStrategyWrapper to abandon runs, and fn doesn't return within
+// the configured duration after the context is done, an error is returned
+// without waiting for fn any longer.
+func runAbandonable(ctx context.Context, fn func(ctx context.Context) error) error {
+	abandonAfter, ok := ctx.Value(abandonAfterKey{}).(time.Duration)
+	if !ok {
+		return fn(ctx)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			e := recover()
+			if e != nil {
+				errCh <- xerrors.Errorf("panic: %v", e)
+			}
+		}()
+		errCh <- fn(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	timer := time.NewTimer(abandonAfter)
+	defer timer.Stop()
+	select {
+	case err := <-errCh:
+		return err
+	case <-timer.C:
+		return xerrors.Errorf("run did not return within %s of its context being canceled, abandoning it: %w", abandonAfter, context.Cause(ctx))
+	}
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 type syncBuffer struct {
 	buf *bytes.Buffer
-	mut sync.Mutex
+	// tee receives a copy of every write, if set. Errors writing to it are
+	// ignored.
+	tee io.Writer
+	// file receives a copy of every write while the log file of the run is
+	// open. Errors writing to it are ignored.
+	file io.Writer
+	// discarding is set once writes should no longer be buffered.
+	discarding bool
+	mut        sync.Mutex
 }
 
 func (sb *syncBuffer) Write(p []byte) (n int, err error) {
 	sb.mut.Lock()
 	defer sb.mut.Unlock()
+	if sb.tee != nil {
+		_, _ = sb.tee.Write(p)
+	}
+	if sb.file != nil {
+		_, _ = sb.file.Write(p)
+	}
+	if sb.discarding {
+		return len(p), nil
+	}
 	return sb.buf.Write(p)
 }
 
+func (sb *syncBuffer) setFile(w io.Writer) {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+	sb.file = w
+}
+
 func (sb *syncBuffer) String() string {
 	sb.mut.Lock()
 	defer sb.mut.Unlock()
 	return sb.buf.String()
 }
+
+// discard releases the buffered logs. Subsequent writes are only copied to tee
+// and file.
+func (sb *syncBuffer) discard() {
+	sb.mut.Lock()
+	defer sb.mut.Unlock()
+	sb.buf = new(bytes.Buffer)
+	sb.discarding = true
+}
@@ -6,8 +6,10 @@ import (
 	"io"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/scaletest/harness"
 )
@@ -15,6 +17,8 @@ import (
 // testFns implements Runnable and Cleanable.
 type testFns struct {
 	RunFn func(ctx context.Context, id string, logs io.Writer) error
+	// SetupFn is optional if no setup is required.
+	SetupFn func(ctx context.Context, id string, logs io.Writer) error
 	// CleanupFn is optional if no cleanup is required.
 	CleanupFn func(ctx context.Context, id string, logs io.Writer) error
 	// GetMetricsFn is optional if no metric collection is required.
@@ -23,6 +27,7 @@ type testFns struct {
 
 var (
 	_ harness.Runnable    = &testFns{}
+	_ harness.Setupable   = &testFns{}
 	_ harness.Cleanable   = &testFns{}
 	_ harness.Collectable = &testFns{}
 )
@@ -32,6 +37,15 @@ func (fns testFns) Run(ctx context.Context, id string, logs io.Writer) error {
 	return fns.RunFn(ctx, id, logs)
 }
 
+// Setup implements Setupable.
+func (fns testFns) Setup(ctx context.Context, id string, logs io.Writer) error {
+	if fns.SetupFn == nil {
+		return nil
+	}
+
+	return fns.SetupFn(ctx, id, logs)
+}
+
 // GetBytesTransferred implements Collectable.
 func (fns testFns) GetMetrics() map[string]any {
 	if fns.GetMetricsFn == nil {
@@ -91,6 +105,64 @@ func Test_TestRun(t *testing.T) {
 		require.EqualValues(t, 1, cleanupCalled.Load())
 	})
 
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			flakyCalls    atomic.Int64
+			flakyCleanups atomic.Int64
+			brokenCalls   atomic.Int64
+			h             = harness.NewTestHarness(
+				harness.LinearExecutionStrategy{},
+				harness.LinearExecutionStrategy{},
+				harness.WithRetryPolicy(harness.RetryPolicy{
+					Retries: 2,
+					Backoff: time.Millisecond,
+				}),
+			)
+		)
+		flaky := h.AddRun("test", "flaky", testFns{
+			RunFn: func(_ context.Context, _ string, _ io.Writer) error {
+				if flakyCalls.Add(1) < 3 {
+					time.Sleep(100 * time.Millisecond)
+					return xerrors.New("flake")
+				}
+				return nil
+			},
+			CleanupFn: func(_ context.Context, _ string, _ io.Writer) error {
+				flakyCleanups.Add(1)
+				return nil
+			},
+		})
+		broken := h.AddRun("test", "broken", testFns{
+			RunFn: func(_ context.Context, _ string, _ io.Writer) error {
+				brokenCalls.Add(1)
+				return xerrors.New("broken")
+			},
+		})
+		ok := h.AddRun("test", "ok", fakeTestFns(nil, nil))
+
+		err := h.Run(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, flaky.Result().Error)
+		require.Equal(t, 3, flaky.Result().Attempts)
+		require.Contains(t, flaky.Result().Logs, "Attempt 2 failed, retrying")
+		// Failed attempts are cleaned up before retrying, and only the last
+		// attempt is timed.
+		require.EqualValues(t, 2, flakyCleanups.Load())
+		require.Less(t, time.Duration(flaky.Result().Duration), 100*time.Millisecond)
+		require.Error(t, broken.Result().Error)
+		require.Equal(t, 3, broken.Result().Attempts)
+		require.EqualValues(t, 3, brokenCalls.Load())
+		require.Equal(t, 1, ok.Result().Attempts)
+
+		res := h.Results()
+		require.Equal(t, 2, res.TotalPass)
+		require.Equal(t, 1, res.TotalPassAfterRetry)
+		require.Equal(t, 1, res.TotalFail)
+	})
+
 	t.Run("Cleanup", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,54 @@
+package harness
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+)
+
+// WithSeed sets the seed of the random number generators used by the harness,
+// its execution strategies and its runners. Running the same test with the same
+// seed makes all randomness obtained through Rand, e.g. jitter, generated names
+// and workload mixes, reproducible. If no seed is given, a random seed is
+// picked and reported in the results so that the test can be reproduced
+// afterwards. A seed of zero also picks a random seed.
+func WithSeed(seed int64) Option {
+	return func(h *TestHarness) {
+		h.seed = seed
+	}
+}
+
+type seedKey struct{}
+
+type randKey struct{}
+
+// Seed returns the seed of the harness that the context belongs to.
+func Seed(ctx context.Context) (int64, bool) {
+	seed, ok := ctx.Value(seedKey{}).(int64)
+	return seed, ok
+}
+
+// Rand returns the random number generator of the test run that the context
+// belongs to. It is seeded from the harness seed and the full ID of the run, so
+// it produces the same sequence for the same run every time the test is run
+// with the same seed, regardless of the order runs are executed in. If the
+// context doesn't belong to a test run of a harness, a randomly seeded
+// generator is returned.
+//
+// The returned generator is not safe for concurrent use.
+func Rand(ctx context.Context) *rand.Rand {
+	rng, ok := ctx.Value(randKey{}).(*rand.Rand)
+	if !ok {
+		//nolint:gosec // gosec thinks we're using an insecure RNG, but we're not.
+		return rand.New(cryptoRandSource{})
+	}
+	return rng
+}
+
+// runSeed derives the seed for the given test run from the harness seed.
+func runSeed(seed int64, fullID string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(fullID))
+	// #nosec G115 - overflow is fine, we only need the bits.
+	return seed ^ int64(h.Sum64())
+}
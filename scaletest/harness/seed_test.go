@@ -0,0 +1,60 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Seed(t *testing.T) {
+	t.Parallel()
+
+	// run executes a shuffled harness with the given seed and returns the
+	// random value drawn by each run and the order the runs executed in.
+	run := func(t *testing.T, seed int64) (map[string]int64, []string, harness.Results) {
+		var (
+			mut    sync.Mutex
+			values = map[string]int64{}
+			order  []string
+		)
+		h := harness.NewTestHarness(
+			harness.ShuffleExecutionStrategyWrapper{Inner: harness.LinearExecutionStrategy{}},
+			harness.LinearExecutionStrategy{},
+			harness.WithSeed(seed),
+		)
+		for i := range 20 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(ctx context.Context, id string, _ io.Writer) error {
+					mut.Lock()
+					defer mut.Unlock()
+					values[id] = harness.Rand(ctx).Int63()
+					order = append(order, id)
+					return nil
+				},
+			})
+		}
+
+		err := h.Run(context.Background())
+		require.NoError(t, err)
+		return values, order, h.Results()
+	}
+
+	values1, order1, res := run(t, 42)
+	require.EqualValues(t, 42, res.Seed)
+	values2, order2, _ := run(t, 42)
+	require.Equal(t, values1, values2)
+	require.Equal(t, order1, order2)
+
+	values3, _, _ := run(t, 1337)
+	require.NotEqual(t, values1, values3)
+
+	// Without a seed, a random one is picked and reported.
+	_, _, res = run(t, 0)
+	require.NotZero(t, res.Seed)
+}
@@ -0,0 +1,170 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+)
+
+// WithSnapshots takes a snapshot of the runs that completed during every
+// interval of the run phase, e.g. every 10 minutes of a soak test, and passes
+// it to fn as soon as it is taken. This makes trends such as latency creeping
+// up over time visible while the test is running, rather than only in the
+// aggregate of the final results, which also include every snapshot. A final
+// snapshot covers the remainder of the run phase. A zero interval disables
+// snapshots.
+//
+// fn is called from a single goroutine and should not block for long.
+func WithSnapshots(interval time.Duration, fn func(Snapshot)) Option {
+	return func(h *TestHarness) {
+		h.snapshotInterval = interval
+		h.snapshotFn = fn
+	}
+}
+
+// Snapshot summarizes the runs that completed during an interval of the run
+// phase. Warmup and skipped runs are excluded.
+type Snapshot struct {
+	// Index is the index of the snapshot, starting at 0.
+	Index int       `json:"index"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Elapsed is the time since the start of the run phase at the end of the
+	// interval.
+	Elapsed       httpapi.Duration `json:"elapsed"`
+	TotalRuns     int              `json:"total_runs"`
+	TotalPass     int              `json:"total_pass"`
+	TotalFail     int              `json:"total_fail"`
+	TotalTimedOut int              `json:"total_timed_out"`
+	// InFlight is the number of runs in progress at the end of the interval.
+	InFlight int `json:"in_flight"`
+	// Latency contains the duration percentiles of the runs that completed
+	// during the interval keyed by test name.
+	Latency map[string]LatencySummary `json:"latency,omitempty"`
+	// Metrics contains a summary of the numeric metrics of the runs that
+	// completed during the interval keyed by metric name.
+	Metrics map[string]MetricSummary `json:"metrics,omitempty"`
+}
+
+// snapshotter collects the runs that complete between snapshots.
+type snapshotter struct {
+	mu        sync.Mutex
+	completed []RunResult
+	snapshots []Snapshot
+}
+
+// recordSnapshotRun adds the result of a finished run to the next snapshot.
+func (h *TestHarness) recordSnapshotRun(run *TestRun) {
+	if h.snapshotInterval <= 0 {
+		return
+	}
+	res := run.Result()
+	// Only the summary of the run is needed.
+	res.Logs = ""
+	h.snapshots.mu.Lock()
+	defer h.snapshots.mu.Unlock()
+	h.snapshots.completed = append(h.snapshots.completed, res)
+}
+
+// takeSnapshots takes a snapshot at every interval until the context is done,
+// and a final one afterwards.
+func (h *TestHarness) takeSnapshots(ctx context.Context, start time.Time) {
+	ticker := time.NewTicker(h.snapshotInterval)
+	defer ticker.Stop()
+
+	last := start
+	for {
+		select {
+		case <-ctx.Done():
+			h.takeSnapshot(context.WithoutCancel(ctx), start, last, time.Now())
+			return
+		case now := <-ticker.C:
+			h.takeSnapshot(ctx, start, last, now)
+			last = now
+		}
+	}
+}
+
+func (h *TestHarness) takeSnapshot(ctx context.Context, phaseStart, start, end time.Time) {
+	h.snapshots.mu.Lock()
+	completed := h.snapshots.completed
+	h.snapshots.completed = nil
+	index := len(h.snapshots.snapshots)
+	h.snapshots.mu.Unlock()
+
+	snapshot := Snapshot{
+		Index:    index,
+		Start:    start,
+		End:      end,
+		Elapsed:  httpapi.Duration(end.Sub(phaseStart)),
+		InFlight: int(h.startedRuns.Load() - h.completedRuns.Load()),
+		Latency:  map[string]LatencySummary{},
+	}
+	hists := map[string]*Histogram{}
+	for _, run := range completed {
+		snapshot.TotalRuns++
+		if run.Error == nil {
+			snapshot.TotalPass++
+		} else {
+			snapshot.TotalFail++
+		}
+		if run.TimedOut {
+			snapshot.TotalTimedOut++
+		}
+		hist, ok := hists[run.TestName]
+		if !ok {
+			hist = NewHistogram()
+			hists[run.TestName] = hist
+		}
+		hist.Record(time.Duration(run.Duration))
+	}
+	for testName, hist := range hists {
+		snapshot.Latency[testName] = hist.Summary()
+	}
+	snapshot.Metrics = metricSummaries(completed)
+
+	h.snapshots.mu.Lock()
+	h.snapshots.snapshots = append(h.snapshots.snapshots, snapshot)
+	h.snapshots.mu.Unlock()
+	if h.snapshotFn != nil {
+		h.snapshotFn(snapshot)
+	}
+	h.reportSnapshot(ctx, snapshot)
+}
+
+// printSnapshots prints a line per snapshot with the P95 latency of every test,
+// so trends over the course of the test are visible at a glance.
+func (r *Results) printSnapshots(w io.Writer) {
+	if len(r.Snapshots) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\n\tSnapshots (%d):\n", len(r.Snapshots))
+	for _, s := range r.Snapshots {
+		_, _ = fmt.Fprintf(w, "\t\t%s\n", s.summaryLine())
+	}
+}
+
+// summaryLine summarizes the snapshot in a single line with the P95 latency of
+// every test.
+func (s Snapshot) summaryLine() string {
+	testNames := maps.Keys(s.Latency)
+	slices.Sort(testNames)
+	p95s := make([]string, 0, len(testNames))
+	for _, testName := range testNames {
+		p95s = append(p95s, fmt.Sprintf("%s P95 %s", testName, time.Duration(s.Latency[testName].P95)))
+	}
+	line := fmt.Sprintf("%s: %d runs, %d failed, %d in flight", time.Duration(s.Elapsed).Round(time.Second), s.TotalRuns, s.TotalFail, s.InFlight)
+	if len(p95s) > 0 {
+		line += ", " + strings.Join(p95s, ", ")
+	}
+	return line
+}
@@ -0,0 +1,83 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Snapshots(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []harness.Snapshot
+	)
+	h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+		harness.WithSnapshots(50*time.Millisecond, func(s harness.Snapshot) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, s)
+		}),
+	)
+	err := h.RunFor(context.Background(), harness.DurationTest{
+		TestName:    "soak",
+		Duration:    220 * time.Millisecond,
+		Concurrency: 2,
+		NewRunner: func(int) (harness.Runnable, error) {
+			return testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					time.Sleep(10 * time.Millisecond)
+					return nil
+				},
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	res := h.Results()
+	mu.Lock()
+	defer mu.Unlock()
+	// Four full intervals and a final snapshot for the remainder.
+	require.GreaterOrEqual(t, len(received), 4)
+	require.Equal(t, received, res.Snapshots)
+
+	total := 0
+	for i, s := range res.Snapshots {
+		require.Equal(t, i, s.Index)
+		require.False(t, s.End.Before(s.Start))
+		if i > 0 {
+			require.Equal(t, res.Snapshots[i-1].End, s.Start)
+			require.Greater(t, s.Elapsed, res.Snapshots[i-1].Elapsed)
+		}
+		require.Equal(t, s.TotalRuns, s.TotalPass)
+		if s.TotalRuns > 0 {
+			require.Equal(t, int64(s.TotalRuns), s.Latency["soak"].Count)
+		}
+		total += s.TotalRuns
+	}
+	// Every run is included in exactly one snapshot.
+	require.Equal(t, res.TotalRuns, total)
+	require.Zero(t, res.Snapshots[len(res.Snapshots)-1].InFlight)
+
+	var out bytes.Buffer
+	res.PrintText(&out)
+	require.Contains(t, out.String(), "Snapshots (")
+	require.Contains(t, out.String(), "soak P95")
+}
+
+func Test_Snapshots_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+	h.AddRun("test", "0", fakeTestFns(nil, nil))
+	require.NoError(t, h.Run(context.Background()))
+	require.Empty(t, h.Results().Snapshots)
+}
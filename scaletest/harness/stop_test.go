@@ -0,0 +1,118 @@
+package harness_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_Stop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Graceful", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		for i := range 5 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(context.Context, string, io.Writer) error {
+					select {
+					case started <- struct{}{}:
+					default:
+					}
+					<-release
+					return nil
+				},
+			})
+		}
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- h.Run(context.Background())
+		}()
+		<-started
+
+		stopped := make(chan harness.Results, 1)
+		go func() {
+			defer close(stopped)
+			res, err := h.Stop(context.Background())
+			if err == nil {
+				stopped <- res
+			}
+		}()
+		// Stop waits for the run in progress to finish.
+		time.Sleep(50 * time.Millisecond)
+		select {
+		case <-stopped:
+			t.Fatal("stop returned before the run in progress finished")
+		default:
+		}
+		close(release)
+
+		res, ok := <-stopped
+		require.True(t, ok, "stop failed")
+		require.NoError(t, <-runErr)
+		require.Equal(t, 1, res.TotalRuns)
+		require.Equal(t, 1, res.TotalPass)
+		require.Equal(t, 4, res.TotalSkipped)
+		require.Equal(t, "test was stopped", res.Runs["test/4"].SkipReason)
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		h := harness.NewTestHarness(harness.ConcurrentExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		for i := range 3 {
+			h.AddRun("test", strconv.Itoa(i), testFns{
+				RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+					started <- struct{}{}
+					<-ctx.Done()
+					return context.Cause(ctx)
+				},
+			})
+		}
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- h.Run(context.Background())
+		}()
+		for range 3 {
+			<-started
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		res, err := h.Stop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		<-runErr
+
+		require.Equal(t, 3, res.TotalRuns)
+		require.Equal(t, 3, res.TotalFail)
+		for _, run := range res.Runs {
+			require.ErrorIs(t, run.Error, harness.ErrStopped)
+		}
+	})
+
+	t.Run("NotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		h.AddRun("test", "0", fakeTestFns(nil, nil))
+		_, err := h.Stop(context.Background())
+		require.ErrorContains(t, err, "has not started")
+
+		require.NoError(t, h.Run(context.Background()))
+		res := h.Results()
+		require.Zero(t, res.TotalRuns)
+		require.Equal(t, 1, res.TotalSkipped)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -23,6 +24,21 @@ type ExecutionStrategy interface {
 	Run(ctx context.Context, fns []TestFn) ([]error, error)
 }
 
+type skipKey struct{}
+
+// SkipRun returns a context that makes a TestFn of a TestHarness mark its run
+// as skipped with the given reason instead of running it. Strategies use it
+// to skip runs they can't schedule, since every TestFn must be called.
+// Skipped runs are excluded from the totals and latencies in the results.
+func SkipRun(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, skipKey{}, reason)
+}
+
+func skipReason(ctx context.Context) (string, bool) {
+	reason, ok := ctx.Value(skipKey{}).(string)
+	return reason, ok
+}
+
 // LinearExecutionStrategy executes all test runs in a linear fashion, one after
 // the other.
 type LinearExecutionStrategy struct{}
@@ -72,7 +88,13 @@ func (ConcurrentExecutionStrategy) Run(ctx context.Context, fns []TestFn) ([]err
 }
 
 // ParallelExecutionStrategy executes all test runs concurrently, but limits the
-// number of concurrent runs to the given limit.
+// number of concurrent runs to the given limit. A limit of zero or less runs
+// all test runs concurrently.
+//
+// Runs are executed in order by a fixed pool of Limit workers, which each take
+// the next run as soon as they finish their previous one, so that no
+// concurrency slot sits idle while runs are still queued, however much the
+// durations of runs vary.
 type ParallelExecutionStrategy struct {
 	Limit int
 }
@@ -81,24 +103,29 @@ var _ ExecutionStrategy = ParallelExecutionStrategy{}
 
 // Run implements ExecutionStrategy.
 func (p ParallelExecutionStrategy) Run(ctx context.Context, fns []TestFn) ([]error, error) {
+	workers := len(fns)
+	if p.Limit > 0 && p.Limit < workers {
+		workers = p.Limit
+	}
+
 	var (
 		wg   sync.WaitGroup
+		next atomic.Int64
 		errs = newErrorsList()
-		sem  = make(chan struct{}, p.Limit)
 	)
-	defer close(sem)
-
-	for i, fn := range fns {
+	for range workers {
 		wg.Add(1)
 		go func() {
-			defer func() {
-				<-sem
-				wg.Done()
-			}()
-			sem <- struct{}{}
-			err := fn(ctx)
-			if err != nil {
-				errs.add(xerrors.Errorf("run %d: %w", i, err))
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(fns) {
+					return
+				}
+				err := fns[i](ctx)
+				if err != nil {
+					errs.add(xerrors.Errorf("run %d: %w", i, err))
+				}
 			}
 		}()
 	}
@@ -107,11 +134,68 @@ func (p ParallelExecutionStrategy) Run(ctx context.Context, fns []TestFn) ([]err
 	return errs.errs, nil
 }
 
+// RateLimitExecutionStrategyWrapper is an ExecutionStrategy that wraps another
+// ExecutionStrategy and limits the rate at which test runs are started. This is
+// useful in combination with a high concurrency limit, e.g. to tear down
+// thousands of workspaces in parallel without overwhelming the server.
+type RateLimitExecutionStrategyWrapper struct {
+	// Interval is the minimum duration between the start of two test runs.
+	Interval time.Duration
+	Inner    ExecutionStrategy
+}
+
+var _ ExecutionStrategy = RateLimitExecutionStrategyWrapper{}
+
+// Run implements ExecutionStrategy.
+func (r RateLimitExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn) ([]error, error) {
+	var (
+		mut  sync.Mutex
+		next time.Time
+	)
+	newFns := make([]TestFn, len(fns))
+	for i, fn := range fns {
+		newFns[i] = func(ctx context.Context) error {
+			mut.Lock()
+			now := time.Now()
+			if next.Before(now) {
+				next = now
+			}
+			wait := next.Sub(now)
+			next = next.Add(r.Interval)
+			mut.Unlock()
+
+			// All functions must be executed, so if the context is done we
+			// run the function immediately and let it handle the
+			// cancellation.
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+			case <-timer.C:
+			}
+			return fn(ctx)
+		}
+	}
+
+	return r.Inner.Run(ctx, newFns)
+}
+
+// ErrRunTimeout is the cause of a test run's context being canceled when the
+// run exceeds the timeout of a TimeoutExecutionStrategyWrapper. Runs that fail
+// after their timeout elapsed are marked as timed out in the results.
+var ErrRunTimeout = xerrors.New("test run timed out")
+
 // TimeoutExecutionStrategyWrapper is an ExecutionStrategy that wraps another
 // ExecutionStrategy and applies a timeout to each test run's context.
 type TimeoutExecutionStrategyWrapper struct {
 	Timeout time.Duration
-	Inner   ExecutionStrategy
+	// AbandonAfter is how long to wait for a test run to return after its
+	// timeout elapsed before giving up on it and freeing up its slot in the
+	// inner strategy. Abandoned runs are marked as failed and timed out, and
+	// their goroutine is left running. Zero waits for the run to return
+	// indefinitely.
+	AbandonAfter time.Duration
+	Inner        ExecutionStrategy
 }
 
 var _ ExecutionStrategy = TimeoutExecutionStrategyWrapper{}
@@ -121,8 +205,11 @@ func (t TimeoutExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn)
 	newFns := make([]TestFn, len(fns))
 	for i, fn := range fns {
 		newFns[i] = func(ctx context.Context) error {
-			ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+			ctx, cancel := context.WithTimeoutCause(ctx, t.Timeout, ErrRunTimeout)
 			defer cancel()
+			if t.AbandonAfter > 0 {
+				ctx = context.WithValue(ctx, abandonAfterKey{}, t.AbandonAfter)
+			}
 			return fn(ctx)
 		}
 	}
@@ -130,8 +217,11 @@ func (t TimeoutExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn)
 	return t.Inner.Run(ctx, newFns)
 }
 
+type abandonAfterKey struct{}
+
 // ShuffleExecutionStrategyWrapper is an ExecutionStrategy that wraps another
 // ExecutionStrategy and shuffles the order of the test runs before executing.
+// When run by a TestHarness, the order is determined by the harness seed.
 type ShuffleExecutionStrategyWrapper struct {
 	Inner ExecutionStrategy
 }
@@ -163,6 +253,11 @@ func (s ShuffleExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn)
 
 	//nolint:gosec // gosec thinks we're using an insecure RNG, but we're not.
 	src := rand.New(cryptoRandSource{})
+	if seed, ok := Seed(ctx); ok {
+		// Shuffle deterministically when the harness is seeded.
+		//nolint:gosec // not used for crypto
+		src = rand.New(rand.NewSource(seed))
+	}
 	for i := range shuffledFns {
 		j := src.Intn(i + 1)
 		shuffledFns[i], shuffledFns[j] = shuffledFns[j], shuffledFns[i]
@@ -171,6 +266,88 @@ func (s ShuffleExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn)
 	return s.Inner.Run(ctx, shuffledFns)
 }
 
+// JitterExecutionStrategyWrapper is an ExecutionStrategy that wraps another
+// ExecutionStrategy and delays the start of each test run by a random duration,
+// so that runs started at the same time don't hit the server in lockstep. When
+// run by a TestHarness, the delays are determined by the harness seed.
+type JitterExecutionStrategyWrapper struct {
+	// Max is the maximum delay before a test run starts.
+	Max   time.Duration
+	Inner ExecutionStrategy
+}
+
+var _ ExecutionStrategy = JitterExecutionStrategyWrapper{}
+
+// Run implements ExecutionStrategy.
+func (j JitterExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn) ([]error, error) {
+	//nolint:gosec // gosec thinks we're using an insecure RNG, but we're not.
+	src := rand.New(cryptoRandSource{})
+	if seed, ok := Seed(ctx); ok {
+		//nolint:gosec // not used for crypto
+		src = rand.New(rand.NewSource(seed))
+	}
+	// Pick the delays upfront so that they don't depend on the order the
+	// inner strategy starts the runs in.
+	delays := make([]time.Duration, len(fns))
+	if j.Max > 0 {
+		for i := range delays {
+			delays[i] = time.Duration(src.Int63n(int64(j.Max)))
+		}
+	}
+
+	newFns := make([]TestFn, len(fns))
+	for i, fn := range fns {
+		newFns[i] = func(ctx context.Context) error {
+			// All functions must be executed, so if the context is done we
+			// run the function immediately and let it handle the
+			// cancellation.
+			timer := time.NewTimer(delays[i])
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+			case <-timer.C:
+			}
+			return fn(ctx)
+		}
+	}
+
+	return j.Inner.Run(ctx, newFns)
+}
+
+// MaxInFlightExecutionStrategyWrapper is an ExecutionStrategy that wraps
+// another ExecutionStrategy and limits the number of test runs that are in
+// progress at the same time, regardless of how many the inner strategy starts
+// concurrently.
+type MaxInFlightExecutionStrategyWrapper struct {
+	Limit int
+	Inner ExecutionStrategy
+}
+
+var _ ExecutionStrategy = MaxInFlightExecutionStrategyWrapper{}
+
+// Run implements ExecutionStrategy.
+func (m MaxInFlightExecutionStrategyWrapper) Run(ctx context.Context, fns []TestFn) ([]error, error) {
+	if m.Limit <= 0 {
+		return m.Inner.Run(ctx, fns)
+	}
+
+	sem := make(chan struct{}, m.Limit)
+	newFns := make([]TestFn, len(fns))
+	for i, fn := range fns {
+		newFns[i] = func(ctx context.Context) error {
+			// Unlike the other wrappers, runs wait for a slot even if the
+			// context is done, so that the limit is never exceeded.
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			return fn(ctx)
+		}
+	}
+
+	return m.Inner.Run(ctx, newFns)
+}
+
 type errorsList struct {
 	mut  *sync.Mutex
 	errs []error
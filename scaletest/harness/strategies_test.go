@@ -5,6 +5,7 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
 )
 
 //nolint:paralleltest // this tests uses timings to determine if it's working
@@ -125,6 +127,82 @@ func Test_ParallelExecutionStrategy(t *testing.T) {
 	require.Equal(t, 5, withinRange)
 }
 
+//nolint:paralleltest // this tests uses timings to determine if it's working
+func Test_ParallelExecutionStrategyScheduling(t *testing.T) {
+	t.Run("Heterogeneous", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			inFlight int
+			maxSeen  int
+			calls    = make([]int, 8)
+		)
+		fns := make([]harness.TestFn, len(calls))
+		for i := range fns {
+			fns[i] = func(context.Context) error {
+				mu.Lock()
+				calls[i]++
+				inFlight++
+				maxSeen = max(maxSeen, inFlight)
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+				}()
+				// The first worker is stuck on a long run, so the other
+				// worker must take all of the remaining runs.
+				if i == 0 {
+					time.Sleep(500 * time.Millisecond)
+				} else {
+					time.Sleep(20 * time.Millisecond)
+				}
+				return nil
+			}
+		}
+
+		start := time.Now()
+		errs, err := harness.ParallelExecutionStrategy{Limit: 2}.Run(context.Background(), fns)
+		require.NoError(t, err)
+		require.Empty(t, errs)
+		require.Less(t, time.Since(start), 650*time.Millisecond)
+		require.Equal(t, 2, maxSeen)
+		for i, n := range calls {
+			require.Equal(t, 1, n, "run %d", i)
+		}
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		var order []int
+		fns := make([]harness.TestFn, 5)
+		for i := range fns {
+			fns[i] = func(context.Context) error {
+				order = append(order, i)
+				return nil
+			}
+		}
+		_, err := harness.ParallelExecutionStrategy{Limit: 1}.Run(context.Background(), fns)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3, 4}, order)
+	})
+
+	t.Run("NoLimit", func(t *testing.T) {
+		var started sync.WaitGroup
+		started.Add(10)
+		fns := make([]harness.TestFn, 10)
+		for i := range fns {
+			fns[i] = func(context.Context) error {
+				// Every run must be in flight at the same time for all of
+				// them to return.
+				started.Done()
+				started.Wait()
+				return nil
+			}
+		}
+		_, err := harness.ParallelExecutionStrategy{}.Run(testutil.Context(t, testutil.WaitShort), fns)
+		require.NoError(t, err)
+	})
+}
+
 //nolint:paralleltest // this tests uses timings to determine if it's working
 func Test_TimeoutExecutionStrategy(t *testing.T) {
 	runs, fns := strategyTestData(1, func(ctx context.Context, _ int, _ io.Writer) error {
@@ -152,6 +230,76 @@ func Test_TimeoutExecutionStrategy(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest // this tests uses timings to determine if it's working
+func Test_TimeoutExecutionStrategy_TimedOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	runs, fns := strategyTestData(3, func(ctx context.Context, i int, _ io.Writer) error {
+		switch i {
+		case 0:
+			// Respects the context.
+			<-ctx.Done()
+			return ctx.Err()
+		case 1:
+			// Ignores the context and hangs.
+			<-release
+			return nil
+		default:
+			return nil
+		}
+	})
+	strategy := harness.TimeoutExecutionStrategyWrapper{
+		Timeout:      100 * time.Millisecond,
+		AbandonAfter: 100 * time.Millisecond,
+		Inner:        harness.LinearExecutionStrategy{},
+	}
+
+	runErrs, err := strategy.Run(context.Background(), fns)
+	require.NoError(t, err)
+	require.Len(t, runErrs, 2)
+
+	res := runs[0].Result()
+	require.ErrorIs(t, res.Error, context.DeadlineExceeded)
+	require.True(t, res.TimedOut)
+
+	res = runs[1].Result()
+	require.ErrorIs(t, res.Error, harness.ErrRunTimeout)
+	require.ErrorContains(t, res.Error, "abandoning")
+	require.True(t, res.TimedOut)
+
+	res = runs[2].Result()
+	require.NoError(t, res.Error)
+	require.False(t, res.TimedOut)
+}
+
+//nolint:paralleltest // this tests uses timings to determine if it's working
+func Test_RateLimitExecutionStrategyWrapper(t *testing.T) {
+	runs, fns := strategyTestData(5, nil)
+	strategy := harness.RateLimitExecutionStrategyWrapper{
+		Interval: 100 * time.Millisecond,
+		Inner:    harness.ConcurrentExecutionStrategy{},
+	}
+
+	startTime := time.Now()
+	runErrs, err := strategy.Run(context.Background(), fns)
+	require.NoError(t, err)
+	require.Len(t, runErrs, 0)
+
+	// Even though all runs are executed concurrently, they should've been
+	// started at least 100ms apart.
+	startTimes := make([]time.Time, len(runs))
+	for i, run := range runs {
+		startTimes[i] = run.Result().StartedAt
+	}
+	sort.Slice(startTimes, func(i, j int) bool {
+		return startTimes[i].Before(startTimes[j])
+	})
+	for i := 1; i < len(startTimes); i++ {
+		require.GreaterOrEqual(t, startTimes[i].Sub(startTimes[i-1]), 90*time.Millisecond)
+	}
+	require.GreaterOrEqual(t, time.Since(startTime), 400*time.Millisecond)
+}
+
 //nolint:paralleltest // this tests uses timings to determine if it's working
 func Test_ShuffleExecutionStrategyWrapper(t *testing.T) {
 	runs, fns := strategyTestData(100000, func(_ context.Context, i int, _ io.Writer) error {
@@ -200,3 +348,43 @@ func strategyTestData(count int, runFn func(ctx context.Context, i int, logs io.
 
 	return runs, fns
 }
+
+// skipOddStrategy skips every other run.
+type skipOddStrategy struct{}
+
+func (skipOddStrategy) Run(ctx context.Context, fns []harness.TestFn) ([]error, error) {
+	for i, fn := range fns {
+		runCtx := ctx
+		if i%2 == 1 {
+			runCtx = harness.SkipRun(ctx, "odd run")
+		}
+		_ = fn(runCtx)
+	}
+	return nil, nil
+}
+
+func Test_SkipRun(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	h := harness.NewTestHarness(skipOddStrategy{}, harness.LinearExecutionStrategy{})
+	for i := range 4 {
+		h.AddRun("test", strconv.Itoa(i), testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				calls.Add(1)
+				return nil
+			},
+		})
+	}
+
+	err := h.Run(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, calls.Load())
+
+	res := h.Results()
+	require.Equal(t, 2, res.TotalRuns)
+	require.Equal(t, 2, res.TotalSkipped)
+	require.True(t, res.Runs["test/1"].Skipped)
+	require.Equal(t, "odd run", res.Runs["test/1"].SkipReason)
+	require.False(t, res.Runs["test/2"].Skipped)
+}
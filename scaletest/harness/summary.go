@@ -0,0 +1,136 @@
+package harness
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// slowestRunsCount is the number of slowest runs printed in the summary.
+	slowestRunsCount = 5
+	// maxErrorGroupLength is the maximum length of the error messages printed
+	// in the error breakdown.
+	maxErrorGroupLength = 200
+)
+
+var (
+	errorGroupUUIDRegex   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	errorGroupNumberRegex = regexp.MustCompile(`[0-9]+`)
+)
+
+// errorGroup returns the key that the error is grouped by in the error
+// breakdown. IDs and numbers are masked so that errors that only differ by the
+// resource or run they happened in are grouped together.
+func errorGroup(err error) string {
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	msg = errorGroupUUIDRegex.ReplaceAllString(msg, "<uuid>")
+	msg = errorGroupNumberRegex.ReplaceAllString(msg, "<n>")
+	if len(msg) > maxErrorGroupLength {
+		cut := maxErrorGroupLength
+		for cut > 0 && !utf8.RuneStart(msg[cut]) {
+			cut--
+		}
+		msg = msg[:cut] + "..."
+	}
+	return msg
+}
+
+// printErrorBreakdown prints the errors of the failed runs grouped by message,
+// most common first.
+func (r *Results) printErrorBreakdown(w io.Writer) {
+	type group struct {
+		message string
+		count   int
+		example string
+	}
+	var (
+		groups = map[string]*group{}
+		failed int
+	)
+	for _, run := range r.Runs {
+		if run.Warmup || run.Skipped || run.Error == nil {
+			continue
+		}
+		failed++
+		key := errorGroup(run.Error)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{message: key, example: run.FullID}
+			groups[key] = g
+		}
+		g.count++
+		if run.FullID < g.example {
+			g.example = run.FullID
+		}
+	}
+	if failed == 0 {
+		return
+	}
+
+	sorted := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	slices.SortFunc(sorted, func(a, b *group) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.message, b.message)
+	})
+
+	_, _ = fmt.Fprintf(w, "\n\tErrors (%d failed runs):\n", failed)
+	for _, g := range sorted {
+		_, _ = fmt.Fprintf(w, "\t\t%dx %s (e.g. %s)\n", g.count, g.message, g.example)
+	}
+}
+
+// printSlowestRuns prints the measured runs that took the longest, along with
+// the durations of their phases.
+func (r *Results) printSlowestRuns(w io.Writer) {
+	runs := make([]RunResult, 0, len(r.Runs))
+	for _, run := range r.Runs {
+		if run.Warmup || run.Skipped {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if len(runs) == 0 {
+		return
+	}
+	slices.SortFunc(runs, func(a, b RunResult) int {
+		if c := cmp.Compare(b.Duration, a.Duration); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.FullID, b.FullID)
+	})
+	if len(runs) > slowestRunsCount {
+		runs = runs[:slowestRunsCount]
+	}
+
+	_, _ = fmt.Fprintf(w, "\n\tSlowest runs:\n")
+	for _, run := range runs {
+		status := "passed"
+		switch {
+		case run.TimedOut:
+			status = "timed out"
+		case run.TestTimedOut:
+			status = "killed by the test timeout"
+		case run.Error != nil:
+			status = "failed"
+		}
+		phases := maps.Keys(run.Phases)
+		sortPhases(phases)
+		for _, phase := range phases {
+			status += fmt.Sprintf(", %s %s", phase, time.Duration(run.Phases[phase]))
+		}
+		_, _ = fmt.Fprintf(w, "\t\t%s: %s (%s)\n", run.FullID, time.Duration(run.Duration), status)
+	}
+}
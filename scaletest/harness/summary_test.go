@@ -0,0 +1,74 @@
+package harness_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_ResultsSummary(t *testing.T) {
+	t.Parallel()
+
+	results := harness.Results{
+		TotalRuns: 8,
+		TotalPass: 3,
+		TotalFail: 5,
+		Runs:      map[string]harness.RunResult{},
+	}
+	add := func(id string, d time.Duration, err error) {
+		results.Runs["test/"+id] = harness.RunResult{
+			FullID:   "test/" + id,
+			TestName: "test",
+			ID:       id,
+			Error:    err,
+			Duration: httpapi.Duration(d),
+		}
+	}
+	for i := range 3 {
+		add(fmt.Sprint(i), time.Duration(i+1)*time.Second, xerrors.Errorf("get workspace 6bb7ba1b-9ac9-4cf1-8cbb-7ac1d1e5d70f: status %d", 500+i))
+	}
+	add("3", 10*time.Second, xerrors.New("dial agent: connection refused"))
+	add("4", 20*time.Second, xerrors.New("dial agent: connection refused"))
+	for i := 5; i < 8; i++ {
+		add(fmt.Sprint(i), time.Duration(i)*time.Millisecond, nil)
+	}
+	results.Runs["test/4"] = func(run harness.RunResult) harness.RunResult {
+		run.TimedOut = true
+		run.Phases = map[string]httpapi.Duration{
+			"setup":  httpapi.Duration(5 * time.Second),
+			"verify": httpapi.Duration(15 * time.Second),
+		}
+		return run
+	}(results.Runs["test/4"])
+	// Warmup runs are excluded from the summary.
+	results.Runs["test/warmup"] = harness.RunResult{
+		FullID:   "test/warmup",
+		Warmup:   true,
+		Error:    xerrors.New("warmup failed"),
+		Duration: httpapi.Duration(time.Hour),
+	}
+
+	var out bytes.Buffer
+	results.PrintText(&out)
+	require.Contains(t, out.String(), `
+	Errors (5 failed runs):
+		3x get workspace <uuid>: status <n> (e.g. test/0)
+		2x dial agent: connection refused (e.g. test/3)
+`)
+	require.Contains(t, out.String(), `
+	Slowest runs:
+		test/4: 20s (timed out, setup 5s, verify 15s)
+		test/3: 10s (failed)
+		test/2: 3s (failed)
+		test/1: 2s (failed)
+		test/0: 1s (failed)
+`)
+	require.NotContains(t, out.String(), "warmup failed")
+}
@@ -0,0 +1,83 @@
+package harness
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+)
+
+// SetTag attaches a key/value tag to the test run, e.g. the template or region
+// a run targets. Results are broken down by every tag value in addition to the
+// per-test totals. Panics if the run has already started.
+func (r *TestRun) SetTag(key, value string) {
+	if r.done != nil {
+		panic("cannot tag a test run after it has started")
+	}
+	if r.tags == nil {
+		r.tags = map[string]string{}
+	}
+	r.tags[key] = value
+}
+
+// TagSummary summarizes the results of all runs with a given tag value.
+type TagSummary struct {
+	TotalRuns int            `json:"total_runs"`
+	TotalPass int            `json:"total_pass"`
+	TotalFail int            `json:"total_fail"`
+	Latency   LatencySummary `json:"latency"`
+}
+
+// recordTagLatency adds the duration of the given finished run to the latency
+// histograms for each of its tags. The caller must hold h.mut.
+func (h *TestHarness) recordTagLatency(run *TestRun) []*Histogram {
+	hists := make([]*Histogram, 0, len(run.tags))
+	for key, value := range run.tags {
+		values, ok := h.tagLatencies[key]
+		if !ok {
+			values = map[string]*Histogram{}
+			h.tagLatencies[key] = values
+		}
+		hist, ok := values[value]
+		if !ok {
+			hist = NewHistogram()
+			values[value] = hist
+		}
+		hists = append(hists, hist)
+	}
+	return hists
+}
+
+// printTags prints the per-tag breakdown of the results as human-readable
+// text.
+func (r *Results) printTags(w io.Writer) {
+	keys := maps.Keys(r.Tags)
+	slices.Sort(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "\n\tBy %s:\n", key)
+		values := maps.Keys(r.Tags[key])
+		slices.Sort(values)
+		for _, value := range values {
+			s := r.Tags[key][value]
+			_, _ = fmt.Fprintf(w, "\t\t%s: pass %d, fail %d, p50 %s, p95 %s, max %s\n",
+				value, s.TotalPass, s.TotalFail,
+				time.Duration(s.Latency.P50), time.Duration(s.Latency.P95), time.Duration(s.Latency.Max),
+			)
+		}
+	}
+}
+
+// formatTags formats tags as a sorted, semicolon separated list of key=value
+// pairs.
+func formatTags(tags map[string]string) string {
+	keys := maps.Keys(tags)
+	slices.Sort(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,77 @@
+package harness
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrPhaseTimeout is the cause of the context of the run or cleanup phase being
+// canceled when the phase exceeds its budget. See WithPhaseTimeouts. Callers
+// that enforce the overall timeout of a test themselves should cancel the
+// context passed to Run with the same cause, e.g. with
+// context.WithTimeoutCause, so that the runs it kills are attributed to it.
+var ErrPhaseTimeout = xerrors.New("test phase timed out")
+
+// PhaseTimeouts configures separate time budgets for the run and cleanup phases
+// of a harness. Zero values disable the respective budget.
+type PhaseTimeouts struct {
+	// Run is the budget of Run or RunFor.
+	Run time.Duration
+	// Cleanup is the budget of Cleanup. Cleaning up thousands of workspaces
+	// usually takes far longer than the test itself, so the cleanup budget
+	// replaces any deadline of the context passed to Cleanup, e.g. a deadline
+	// that was shared with the run phase. Cancellation of that context is still
+	// honored.
+	Cleanup time.Duration
+}
+
+// WithPhaseTimeouts applies separate time budgets to the run and cleanup
+// phases of the harness.
+func WithPhaseTimeouts(t PhaseTimeouts) Option {
+	return func(h *TestHarness) {
+		h.phaseTimeouts = t
+	}
+}
+
+// testTimedOut returns whether the context of a run was canceled by the overall
+// timeout of the test rather than by the timeout of the run itself, i.e. by
+// the run budget of WithPhaseTimeouts or a deadline of the context the test
+// was run with.
+func testTimedOut(ctx context.Context) bool {
+	cause := context.Cause(ctx)
+	if cause == nil || xerrors.Is(cause, ErrRunTimeout) {
+		return false
+	}
+	return xerrors.Is(cause, ErrPhaseTimeout) || xerrors.Is(cause, context.DeadlineExceeded)
+}
+
+// runPhaseContext applies the run budget to ctx.
+func (h *TestHarness) runPhaseContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.phaseTimeouts.Run <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, h.phaseTimeouts.Run, ErrPhaseTimeout)
+}
+
+// cleanupPhaseContext replaces the deadline of ctx with the cleanup budget.
+func (h *TestHarness) cleanupPhaseContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.phaseTimeouts.Cleanup <= 0 {
+		return ctx, func() {}
+	}
+
+	cleanupCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		// Only propagate cancellation, not the deadline of the parent.
+		if xerrors.Is(ctx.Err(), context.Canceled) {
+			cancel(context.Cause(ctx))
+		}
+	})
+	cleanupCtx, cancelTimeout := context.WithTimeoutCause(cleanupCtx, h.phaseTimeouts.Cleanup, ErrPhaseTimeout)
+	return cleanupCtx, func() {
+		stop()
+		cancelTimeout()
+		cancel(nil)
+	}
+}
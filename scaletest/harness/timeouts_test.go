@@ -0,0 +1,126 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_PhaseTimeouts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Run", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithPhaseTimeouts(harness.PhaseTimeouts{Run: 10 * time.Millisecond}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				<-ctx.Done()
+				return context.Cause(ctx)
+			},
+		})
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		require.Equal(t, 1, res.TotalFail)
+		require.ErrorIs(t, res.Runs["test/0"].Error, harness.ErrPhaseTimeout)
+		require.True(t, res.Runs["test/0"].TestTimedOut)
+		require.False(t, res.Runs["test/0"].TimedOut)
+		require.Equal(t, 1, res.TotalTestTimedOut)
+	})
+
+	t.Run("Attribution", func(t *testing.T) {
+		t.Parallel()
+
+		// The first run exceeds its own timeout, while the second is still
+		// within its own timeout when the test times out.
+		h := harness.NewTestHarness(
+			harness.Compose(harness.LinearExecutionStrategy{}, harness.WithTimeout(100*time.Millisecond, 0)),
+			harness.LinearExecutionStrategy{},
+			harness.WithPhaseTimeouts(harness.PhaseTimeouts{Run: 150 * time.Millisecond}),
+		)
+		wait := func(ctx context.Context, _ string, _ io.Writer) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		h.AddRun("test", "done", testFns{RunFn: func(context.Context, string, io.Writer) error {
+			return nil
+		}})
+		h.AddRun("test", "stuck", testFns{RunFn: wait})
+		h.AddRun("test", "healthy", testFns{RunFn: wait})
+		require.NoError(t, h.Run(context.Background()))
+
+		res := h.Results()
+		require.Equal(t, 2, res.TotalFail)
+		require.Equal(t, 1, res.TotalTimedOut)
+		require.Equal(t, 1, res.TotalTestTimedOut)
+		require.True(t, res.Runs["test/stuck"].TimedOut)
+		require.False(t, res.Runs["test/stuck"].TestTimedOut)
+		require.False(t, res.Runs["test/healthy"].TimedOut)
+		require.True(t, res.Runs["test/healthy"].TestTimedOut)
+		require.False(t, res.Runs["test/done"].TestTimedOut)
+
+		var out bytes.Buffer
+		res.PrintText(&out)
+		require.Contains(t, out.String(), "== TEST TIMEOUT: test/healthy")
+		require.Contains(t, out.String(), "(1 killed by the test timeout)")
+	})
+
+	t.Run("CleanupOutlivesDeadline", func(t *testing.T) {
+		t.Parallel()
+
+		// The deadline of the context is shared by both phases and has
+		// passed by the time cleanup starts.
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		var cleanupErr error
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithPhaseTimeouts(harness.PhaseTimeouts{Cleanup: time.Minute}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				<-ctx.Done()
+				return nil
+			},
+			CleanupFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				cleanupErr = ctx.Err()
+				return cleanupErr
+			},
+		})
+		require.NoError(t, h.Run(ctx))
+		require.Error(t, ctx.Err())
+		require.NoError(t, h.Cleanup(ctx))
+		require.NoError(t, cleanupErr)
+	})
+
+	t.Run("CleanupCanceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{},
+			harness.WithPhaseTimeouts(harness.PhaseTimeouts{Cleanup: time.Minute}),
+		)
+		h.AddRun("test", "0", testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				return nil
+			},
+			CleanupFn: func(ctx context.Context, _ string, _ io.Writer) error {
+				cancel()
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		})
+		require.NoError(t, h.Run(ctx))
+		// Cancellation is still propagated to cleanup.
+		require.ErrorIs(t, h.Cleanup(ctx), context.Canceled)
+	})
+}
@@ -0,0 +1,40 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Summary returns a copy of the results without the results of individual
+// runs, which include their logs and can be too large to store.
+func (r *Results) Summary() Results {
+	summary := *r
+	summary.Runs = nil
+	return summary
+}
+
+// UploadResults stores the summary of the results in the deployment under
+// test, so that the results of past tests can be compared with each other.
+func UploadResults(ctx context.Context, client *codersdk.Client, name string, res Results) (codersdk.ScaletestResult, error) {
+	summary, err := json.Marshal(res.Summary())
+	if err != nil {
+		return codersdk.ScaletestResult{}, xerrors.Errorf("marshal results summary: %w", err)
+	}
+
+	uploaded, err := client.UploadScaletestResult(ctx, codersdk.UploadScaletestResultRequest{
+		Name:      name,
+		TotalRuns: res.TotalRuns,
+		TotalPass: res.TotalPass,
+		TotalFail: res.TotalFail,
+		ElapsedMS: res.ElapsedMS,
+		Summary:   summary,
+	})
+	if err != nil {
+		return codersdk.ScaletestResult{}, xerrors.Errorf("upload results: %w", err)
+	}
+	return uploaded, nil
+}
@@ -0,0 +1,86 @@
+package harness_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_UploadResults(t *testing.T) {
+	t.Parallel()
+
+	res := harness.Results{
+		SchemaVersion: harness.ResultsSchemaVersion,
+		TotalRuns:     2,
+		TotalPass:     1,
+		TotalFail:     1,
+		Elapsed:       httpapi.Duration(time.Minute),
+		ElapsedMS:     time.Minute.Milliseconds(),
+		Runs: map[string]harness.RunResult{
+			"test/0": {FullID: "test/0", TestName: "test", ID: "0", Logs: "lots of logs"},
+			"test/1": {FullID: "test/1", TestName: "test", ID: "1", Logs: "lots of logs"},
+		},
+		Latency: map[string]harness.LatencySummary{
+			"test": {Count: 1},
+		},
+	}
+
+	id := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, http.MethodPost, r.Method) || !assert.Equal(t, "/api/v2/scaletest/results", r.URL.Path) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req codersdk.UploadScaletestResultRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, "create-workspaces", req.Name)
+		assert.Equal(t, 2, req.TotalRuns)
+		assert.Equal(t, 1, req.TotalPass)
+		assert.Equal(t, 1, req.TotalFail)
+		assert.Equal(t, time.Minute.Milliseconds(), req.ElapsedMS)
+
+		// The summary is valid results, without the individual runs.
+		summary, err := harness.ReadResults(bytes.NewReader(req.Summary))
+		if assert.NoError(t, err) {
+			assert.Empty(t, summary.Runs)
+			assert.Equal(t, res.Latency, summary.Latency)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(codersdk.ScaletestResult{
+			ID:        id,
+			Name:      req.Name,
+			TotalRuns: req.TotalRuns,
+			Summary:   req.Summary,
+		})
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	uploaded, err := harness.UploadResults(ctx, codersdk.New(u), "create-workspaces", res)
+	require.NoError(t, err)
+	require.Equal(t, id, uploaded.ID)
+	require.Equal(t, "create-workspaces", uploaded.Name)
+
+	// The results themselves are left untouched.
+	require.Len(t, res.Runs, 2)
+}
@@ -0,0 +1,99 @@
+package harness
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+)
+
+// VariantResults returns the results of the runs tagged with the given value of
+// the given tag key, as if they had been run on their own. This splits a test
+// that ran several variants of the same workload simultaneously, e.g. against
+// a stable and an experimental API route, into the results of each variant.
+// The variant shares the elapsed time of the test, so that throughputs of
+// variants are comparable.
+//
+// Returns an error if the results of some runs were not retained, since the
+// totals of the variant could not be computed.
+func VariantResults(r Results, key, value string) (Results, error) {
+	if r.TotalDiscarded > 0 {
+		return Results{}, xerrors.Errorf("the results of %d runs were not retained", r.TotalDiscarded)
+	}
+
+	runs := map[string]RunResult{}
+	for id, run := range r.Runs {
+		if v, ok := run.Tags[key]; ok && v == value {
+			runs[id] = run
+		}
+	}
+	if len(runs) == 0 {
+		return Results{}, xerrors.Errorf("no runs are tagged with %s=%s", key, value)
+	}
+	return aggregateRuns(r, runs), nil
+}
+
+// aggregateRuns returns the results of the given runs, with the totals and
+// latency percentiles computed from them. The schema version, seed, shard and
+// elapsed time are copied from base.
+func aggregateRuns(base Results, runs map[string]RunResult) Results {
+	res := Results{
+		SchemaVersion: base.SchemaVersion,
+		Seed:          base.Seed,
+		Shard:         base.Shard,
+		Elapsed:       base.Elapsed,
+		ElapsedMS:     base.ElapsedMS,
+		Runs:          runs,
+		Latency:       map[string]LatencySummary{},
+	}
+	agg := &resultAggregator{}
+	hists := map[string]*Histogram{}
+	for _, run := range runs {
+		agg.add(run)
+		if run.Warmup || run.Skipped {
+			continue
+		}
+		hist, ok := hists[run.TestName]
+		if !ok {
+			hist = NewHistogram()
+			hists[run.TestName] = hist
+		}
+		hist.Record(time.Duration(run.Duration))
+	}
+	agg.apply(&res, nil)
+	for testName, hist := range hists {
+		res.Latency[testName] = hist.Summary()
+	}
+	return res
+}
+
+// CompareVariants compares the runs of the candidate variant of a test to the
+// runs of the baseline variant, where runs are assigned to variants by the
+// value of the given tag key. See VariantResults. In addition to the rows of
+// Compare, the means of the numeric metrics reported by the runners of both
+// variants are compared. Since whether an increase of a metric is a regression
+// depends on the metric, metric rows are never flagged as regressions.
+func CompareVariants(r Results, key, baseline, candidate string, thresholds CompareThresholds) (Comparison, error) {
+	baselineRes, err := VariantResults(r, key, baseline)
+	if err != nil {
+		return Comparison{}, xerrors.Errorf("baseline variant %q: %w", baseline, err)
+	}
+	candidateRes, err := VariantResults(r, key, candidate)
+	if err != nil {
+		return Comparison{}, xerrors.Errorf("candidate variant %q: %w", candidate, err)
+	}
+
+	c := Compare(baselineRes, candidateRes, thresholds)
+	names := maps.Keys(baselineRes.Metrics)
+	slices.Sort(names)
+	for _, name := range names {
+		candidateMetric, ok := candidateRes.Metrics[name]
+		if !ok {
+			continue
+		}
+		c.Rows = append(c.Rows, newComparisonRow(fmt.Sprintf("%s mean", name), baselineRes.Metrics[name].Mean, candidateMetric.Mean, formatFloat))
+	}
+	return c, nil
+}
@@ -0,0 +1,97 @@
+package harness_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+func Test_CompareVariants(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.ConcurrentExecutionStrategy{}, harness.ConcurrentExecutionStrategy{})
+		for i := range 4 {
+			for _, variant := range []string{"stable", "experimental"} {
+				var (
+					wait    = 10 * time.Millisecond
+					err     error
+					metrics = map[string]float64{"requests": 10}
+				)
+				if variant == "experimental" {
+					wait = 30 * time.Millisecond
+					metrics["requests"] = 20
+					if i == 0 {
+						err = xerrors.New("not found")
+					}
+				}
+				run := h.AddRun("replay", variant+"/"+strconv.Itoa(i), numericTestFns{
+					testFns: testFns{RunFn: func(context.Context, string, io.Writer) error {
+						time.Sleep(wait)
+						return err
+					}},
+					metrics: metrics,
+				})
+				run.SetTag("variant", variant)
+			}
+		}
+		require.NoError(t, h.Run(context.Background()))
+		res := h.Results()
+
+		stable, err := harness.VariantResults(res, "variant", "stable")
+		require.NoError(t, err)
+		require.Equal(t, 4, stable.TotalRuns)
+		require.Equal(t, 4, stable.TotalPass)
+		require.Len(t, stable.Runs, 4)
+		require.Equal(t, res.Elapsed, stable.Elapsed)
+		require.Equal(t, int64(4), stable.Latency["replay"].Count)
+
+		c, err := harness.CompareVariants(res, "variant", "stable", "experimental", harness.CompareThresholds{
+			MaxErrorRateIncrease: 10,
+			MaxLatencyIncrease:   50,
+		})
+		require.NoError(t, err)
+		rows := map[string]harness.ComparisonRow{}
+		for _, row := range c.Rows {
+			rows[row.Metric] = row
+		}
+		require.InDelta(t, 0.25, rows["error rate"].Candidate, 0.001)
+		require.True(t, rows["error rate"].Regression)
+		require.True(t, rows["latency p50 (replay)"].Regression)
+		require.Equal(t, 10.0, rows["requests mean"].Baseline)
+		require.Equal(t, 20.0, rows["requests mean"].Candidate)
+		require.False(t, rows["requests mean"].Regression)
+
+		var out bytes.Buffer
+		c.PrintText(&out)
+		require.Contains(t, out.String(), "REGRESSION")
+	})
+
+	t.Run("UnknownVariant", func(t *testing.T) {
+		t.Parallel()
+
+		h := harness.NewTestHarness(harness.LinearExecutionStrategy{}, harness.LinearExecutionStrategy{})
+		h.AddRun("replay", "0", fakeTestFns(nil, nil)).SetTag("variant", "stable")
+		require.NoError(t, h.Run(context.Background()))
+
+		_, err := harness.CompareVariants(h.Results(), "variant", "stable", "experimental", harness.CompareThresholds{})
+		require.ErrorContains(t, err, `candidate variant "experimental": no runs are tagged with variant=experimental`)
+	})
+
+	t.Run("Discarded", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := harness.VariantResults(harness.Results{TotalDiscarded: 1}, "variant", "stable")
+		require.ErrorContains(t, err, "the results of 1 runs were not retained")
+	})
+}
@@ -0,0 +1,230 @@
+// Package loadprofile records the load of a deployment from its historical
+// stats, and replays an equivalent pattern of scaletest runs against another
+// deployment, compressed in time.
+package loadprofile
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Metric is the stat of a profile that runs are scheduled by.
+type Metric string
+
+const (
+	// MetricBuilds schedules a run for every workspace start build, e.g. to
+	// replay the build pattern with create-workspaces.
+	MetricBuilds Metric = "builds"
+	// MetricActiveUsers schedules a run for every active user, e.g. to replay
+	// the traffic pattern with workspace-traffic.
+	MetricActiveUsers Metric = "active_users"
+)
+
+// Profile is the load of a deployment over time.
+type Profile struct {
+	Start time.Time `json:"start" format:"date-time"`
+	End   time.Time `json:"end" format:"date-time"`
+	// Interval is the duration covered by each sample.
+	Interval httpapi.Duration `json:"interval"`
+	// Samples are ordered by start time.
+	Samples []Sample `json:"samples"`
+}
+
+// Sample is the load of a deployment during an interval.
+type Sample struct {
+	Start time.Time `json:"start" format:"date-time"`
+	// ActiveUsers is the number of users that used a workspace.
+	ActiveUsers int64 `json:"active_users"`
+	// Builds is the number of workspace start builds.
+	Builds int64 `json:"builds"`
+}
+
+func (s Sample) count(m Metric) int64 {
+	if m == MetricActiveUsers {
+		return s.ActiveUsers
+	}
+	return s.Builds
+}
+
+// Validate returns an error if the profile is invalid.
+func (p Profile) Validate() error {
+	if !p.Start.Before(p.End) {
+		return xerrors.New("validate end: must be after start")
+	}
+	if p.Interval <= 0 {
+		return xerrors.New("validate interval: must be greater than zero")
+	}
+	for i, s := range p.Samples {
+		if s.Start.Before(p.Start) || !s.Start.Before(p.End) {
+			return xerrors.Errorf("validate samples[%d]: start must be between the start and end of the profile", i)
+		}
+		if i > 0 && !p.Samples[i-1].Start.Before(s.Start) {
+			return xerrors.Errorf("validate samples[%d]: samples must be ordered by start", i)
+		}
+		if s.ActiveUsers < 0 || s.Builds < 0 {
+			return xerrors.Errorf("validate samples[%d]: counts must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// Parse parses and validates a JSON profile, e.g. one written by Write.
+func Parse(r io.Reader) (Profile, error) {
+	var p Profile
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return Profile{}, xerrors.Errorf("decode profile: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// Write writes the profile to w as JSON.
+func (p Profile) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(p); err != nil {
+		return xerrors.Errorf("encode profile: %w", err)
+	}
+	return nil
+}
+
+// Schedule returns the offset from the start of the test at which to start a
+// run for every occurrence of the metric in the profile, with the profile
+// compressed in time by the given factor, e.g. a factor of 1440 replays a day
+// in a minute. The runs of each sample are spread evenly over its interval.
+func (p Profile) Schedule(m Metric, compression float64) []time.Duration {
+	if compression <= 0 {
+		compression = 1
+	}
+	window := float64(p.Interval) / compression
+	var schedule []time.Duration
+	for _, s := range p.Samples {
+		n := s.count(m)
+		start := float64(s.Start.Sub(p.Start)) / compression
+		for k := range n {
+			schedule = append(schedule, time.Duration(start+window*(float64(k)+0.5)/float64(n)))
+		}
+	}
+	return schedule
+}
+
+// Client is the subset of *codersdk.Client used to record profiles.
+type Client interface {
+	TemplateInsights(ctx context.Context, req codersdk.TemplateInsightsRequest) (codersdk.TemplateInsightsResponse, error)
+	Workspaces(ctx context.Context, filter codersdk.WorkspaceFilter) (codersdk.WorkspacesResponse, error)
+	WorkspaceBuilds(ctx context.Context, req codersdk.WorkspaceBuildsRequest) ([]codersdk.WorkspaceBuild, error)
+}
+
+var _ Client = &codersdk.Client{}
+
+// RecordConfig configures the stats that are recorded into a profile.
+type RecordConfig struct {
+	// Start and End bound the recorded stats. The insights API requires
+	// them to be at the start of an hour.
+	Start time.Time
+	End   time.Time
+	// Interval is the interval of the samples.
+	Interval codersdk.InsightsReportInterval
+	// TemplateIDs limits the stats to workspaces of the given templates, if
+	// set.
+	TemplateIDs []uuid.UUID
+}
+
+// Record records a profile from the stats of a deployment. Active users are
+// taken from the template insights, and builds from the build history of the
+// existing workspaces, so builds of workspaces that have since been deleted
+// are not counted.
+func Record(ctx context.Context, client Client, cfg RecordConfig) (Profile, error) {
+	insights, err := client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime:   cfg.Start,
+		EndTime:     cfg.End,
+		TemplateIDs: cfg.TemplateIDs,
+		Interval:    cfg.Interval,
+		Sections:    []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionIntervalReports},
+	})
+	if err != nil {
+		return Profile{}, xerrors.Errorf("get template insights: %w", err)
+	}
+
+	p := Profile{
+		Start:    cfg.Start,
+		End:      cfg.End,
+		Interval: httpapi.Duration(time.Duration(cfg.Interval.Days()) * 24 * time.Hour),
+	}
+	for _, r := range insights.IntervalReports {
+		p.Samples = append(p.Samples, Sample{
+			Start:       r.StartTime,
+			ActiveUsers: r.ActiveUsers,
+		})
+	}
+	slices.SortFunc(p.Samples, func(a, b Sample) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	const limit = 100
+	for offset := 0; ; offset += limit {
+		page, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Offset: offset,
+			Limit:  limit,
+		})
+		if err != nil {
+			return Profile{}, xerrors.Errorf("list workspaces: %w", err)
+		}
+		for _, w := range page.Workspaces {
+			if len(cfg.TemplateIDs) > 0 && !slices.Contains(cfg.TemplateIDs, w.TemplateID) {
+				continue
+			}
+			if err := p.recordBuilds(ctx, client, w.ID); err != nil {
+				return Profile{}, xerrors.Errorf("record builds of workspace %s: %w", w.ID, err)
+			}
+		}
+		if len(page.Workspaces) < limit {
+			break
+		}
+	}
+	return p, nil
+}
+
+// recordBuilds counts the start builds of the workspace in the samples they
+// were created in.
+func (p *Profile) recordBuilds(ctx context.Context, client Client, workspaceID uuid.UUID) error {
+	const limit = 100
+	for offset := 0; ; offset += limit {
+		builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspaceID,
+			Since:       p.Start,
+			Pagination:  codersdk.Pagination{Offset: offset, Limit: limit},
+		})
+		if err != nil {
+			return err
+		}
+		for _, b := range builds {
+			if b.Transition != codersdk.WorkspaceTransitionStart {
+				continue
+			}
+			for i := len(p.Samples) - 1; i >= 0; i-- {
+				s := &p.Samples[i]
+				if !b.CreatedAt.Before(s.Start) {
+					if b.CreatedAt.Before(s.Start.Add(time.Duration(p.Interval))) && b.CreatedAt.Before(p.End) {
+						s.Builds++
+					}
+					break
+				}
+			}
+		}
+		if len(builds) < limit {
+			return nil
+		}
+	}
+}
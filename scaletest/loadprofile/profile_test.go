@@ -0,0 +1,145 @@
+package loadprofile_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/loadprofile"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeClient struct {
+	insights   codersdk.TemplateInsightsResponse
+	workspaces []codersdk.Workspace
+	builds     map[uuid.UUID][]codersdk.WorkspaceBuild
+}
+
+func (c *fakeClient) TemplateInsights(context.Context, codersdk.TemplateInsightsRequest) (codersdk.TemplateInsightsResponse, error) {
+	return c.insights, nil
+}
+
+func (c *fakeClient) Workspaces(_ context.Context, filter codersdk.WorkspaceFilter) (codersdk.WorkspacesResponse, error) {
+	return codersdk.WorkspacesResponse{Workspaces: page(c.workspaces, filter.Offset, filter.Limit)}, nil
+}
+
+func (c *fakeClient) WorkspaceBuilds(_ context.Context, req codersdk.WorkspaceBuildsRequest) ([]codersdk.WorkspaceBuild, error) {
+	return page(c.builds[req.WorkspaceID], req.Offset, req.Limit), nil
+}
+
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	templateID := uuid.New()
+	client := &fakeClient{
+		insights: codersdk.TemplateInsightsResponse{
+			IntervalReports: []codersdk.TemplateInsightsIntervalReport{
+				{StartTime: start.Add(day), ActiveUsers: 7},
+				{StartTime: start, ActiveUsers: 3},
+			},
+		},
+		builds: map[uuid.UUID][]codersdk.WorkspaceBuild{},
+	}
+	// Enough workspaces and builds to need more than one page.
+	for i := range 150 {
+		ws := codersdk.Workspace{ID: uuid.New(), TemplateID: templateID}
+		if i%2 == 1 {
+			ws.TemplateID = uuid.New()
+		}
+		client.workspaces = append(client.workspaces, ws)
+		client.builds[ws.ID] = []codersdk.WorkspaceBuild{
+			{Transition: codersdk.WorkspaceTransitionStart, CreatedAt: start.Add(time.Hour)},
+			{Transition: codersdk.WorkspaceTransitionStop, CreatedAt: start.Add(2 * time.Hour)},
+			{Transition: codersdk.WorkspaceTransitionStart, CreatedAt: start.Add(day + time.Hour)},
+			// After the end of the profile.
+			{Transition: codersdk.WorkspaceTransitionStart, CreatedAt: start.Add(2*day + time.Hour)},
+		}
+	}
+	for range 120 {
+		client.builds[client.workspaces[0].ID] = append(client.builds[client.workspaces[0].ID], codersdk.WorkspaceBuild{
+			Transition: codersdk.WorkspaceTransitionStart,
+			CreatedAt:  start.Add(3 * time.Hour),
+		})
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	p, err := loadprofile.Record(ctx, client, loadprofile.RecordConfig{
+		Start:       start,
+		End:         start.Add(2 * day),
+		Interval:    codersdk.InsightsReportIntervalDay,
+		TemplateIDs: []uuid.UUID{templateID},
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Validate())
+	require.Equal(t, httpapi.Duration(day), p.Interval)
+	require.Equal(t, []loadprofile.Sample{
+		{Start: start, ActiveUsers: 3, Builds: 75 + 120},
+		{Start: start.Add(day), ActiveUsers: 7, Builds: 75},
+	}, p.Samples)
+}
+
+func TestProfile(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := loadprofile.Profile{
+		Start:    start,
+		End:      start.Add(3 * time.Hour),
+		Interval: httpapi.Duration(time.Hour),
+		Samples: []loadprofile.Sample{
+			{Start: start, ActiveUsers: 1, Builds: 2},
+			{Start: start.Add(2 * time.Hour), ActiveUsers: 4},
+		},
+	}
+
+	t.Run("Schedule", func(t *testing.T) {
+		t.Parallel()
+
+		// An hour is replayed in a minute.
+		require.Equal(t, []time.Duration{15 * time.Second, 45 * time.Second}, p.Schedule(loadprofile.MetricBuilds, 60))
+		require.Equal(t, []time.Duration{
+			30 * time.Second,
+			2*time.Minute + 7500*time.Millisecond,
+			2*time.Minute + 22500*time.Millisecond,
+			2*time.Minute + 37500*time.Millisecond,
+			2*time.Minute + 52500*time.Millisecond,
+		}, p.Schedule(loadprofile.MetricActiveUsers, 60))
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, p.Write(&buf))
+		parsed, err := loadprofile.Parse(&buf)
+		require.NoError(t, err)
+		require.Equal(t, p, parsed)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := p
+		invalid.Samples = []loadprofile.Sample{p.Samples[1], p.Samples[0]}
+		require.ErrorContains(t, invalid.Validate(), "ordered by start")
+		invalid.Samples = []loadprofile.Sample{{Start: start.Add(-time.Hour)}}
+		require.ErrorContains(t, invalid.Validate(), "between the start and end")
+		invalid.Interval = 0
+		require.ErrorContains(t, invalid.Validate(), "interval")
+	})
+}
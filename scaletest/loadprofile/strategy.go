@@ -0,0 +1,53 @@
+package loadprofile
+
+import (
+	"context"
+	"time"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// SkipReason is the reason runs skipped by ExecutionStrategyWrapper are
+// marked with in the results.
+const SkipReason = "not part of the load profile"
+
+// ExecutionStrategyWrapper is a harness.ExecutionStrategy that wraps another
+// strategy and starts every run at its offset in a schedule, e.g. one returned
+// by Profile.Schedule. Runs beyond the end of the schedule are skipped.
+//
+// Runs wait for their offset within the inner strategy, so the inner strategy
+// should not limit the number of concurrent runs, or runs may start later than
+// scheduled.
+type ExecutionStrategyWrapper struct {
+	// Schedule contains the offset from the start of the test at which each
+	// run is started.
+	Schedule []time.Duration
+	Inner    harness.ExecutionStrategy
+}
+
+var _ harness.ExecutionStrategy = ExecutionStrategyWrapper{}
+
+// Run implements harness.ExecutionStrategy.
+func (w ExecutionStrategyWrapper) Run(ctx context.Context, fns []harness.TestFn) ([]error, error) {
+	start := time.Now()
+	newFns := make([]harness.TestFn, len(fns))
+	for i, fn := range fns {
+		newFns[i] = func(ctx context.Context) error {
+			if i >= len(w.Schedule) {
+				return fn(harness.SkipRun(ctx, SkipReason))
+			}
+
+			// All functions must be executed, so if the context is done we
+			// run the function immediately and let it handle the
+			// cancellation.
+			timer := time.NewTimer(time.Until(start.Add(w.Schedule[i])))
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+			case <-timer.C:
+			}
+			return fn(ctx)
+		}
+	}
+	return w.Inner.Run(ctx, newFns)
+}
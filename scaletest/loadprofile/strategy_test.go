@@ -0,0 +1,60 @@
+package loadprofile_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadprofile"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExecutionStrategyWrapper(t *testing.T) {
+	t.Parallel()
+
+	schedule := []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond}
+	h := harness.NewTestHarness(loadprofile.ExecutionStrategyWrapper{
+		Schedule: schedule,
+		Inner:    harness.ConcurrentExecutionStrategy{},
+	}, harness.ConcurrentExecutionStrategy{})
+
+	var (
+		mu     sync.Mutex
+		starts = map[string]time.Time{}
+	)
+	for i := range 5 {
+		h.AddRun("test", strconv.Itoa(i), testFns{
+			RunFn: func(_ context.Context, id string, _ io.Writer) error {
+				mu.Lock()
+				defer mu.Unlock()
+				starts[id] = time.Now()
+				return nil
+			},
+		})
+	}
+	start := time.Now()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	require.NoError(t, h.Run(ctx))
+
+	res := h.Results()
+	require.Equal(t, 3, res.TotalPass)
+	require.Equal(t, 2, res.TotalSkipped)
+	require.Equal(t, loadprofile.SkipReason, res.Runs["test/4"].SkipReason)
+	for i, offset := range schedule {
+		require.GreaterOrEqual(t, starts[strconv.Itoa(i)].Sub(start), offset)
+	}
+}
+
+type testFns struct {
+	RunFn func(ctx context.Context, id string, logs io.Writer) error
+}
+
+func (fns testFns) Run(ctx context.Context, id string, logs io.Writer) error {
+	return fns.RunFn(ctx, id, logs)
+}
@@ -0,0 +1,18 @@
+package loadtestutil
+
+import "time"
+
+// MeanMax returns the mean and maximum of the given durations, or zero for
+// both if there are none.
+func MeanMax(ds []time.Duration) (mean, maximum time.Duration) {
+	if len(ds) == 0 {
+		return 0, 0
+	}
+
+	var total time.Duration
+	for _, d := range ds {
+		total += d
+		maximum = max(maximum, d)
+	}
+	return total / time.Duration(len(ds)), maximum
+}
@@ -0,0 +1,22 @@
+package loadtestutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+func TestMeanMax(t *testing.T) {
+	t.Parallel()
+
+	mean, maximum := loadtestutil.MeanMax(nil)
+	require.Zero(t, mean)
+	require.Zero(t, maximum)
+
+	mean, maximum = loadtestutil.MeanMax([]time.Duration{time.Second, 3 * time.Second, 2 * time.Second})
+	require.Equal(t, 2*time.Second, mean)
+	require.Equal(t, 3*time.Second, maximum)
+}
@@ -1,64 +1,148 @@
 package loadtestutil
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
+	"golang.org/x/xerrors"
+
 	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/scaletest/harness"
 )
 
 const (
-	// Prefix for all scaletest resources (users and workspaces)
+	// Default prefix for all scaletest resources (users and workspaces). See
+	// WithPrefix.
 	ScaleTestPrefix = "scaletest"
 
-	// Email domain for scaletest users
+	// Email domain for scaletest users with the default prefix
 	EmailDomain = "@scaletest.local"
 
 	DefaultRandLength = 8
 )
 
+var prefixRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type prefixKey struct{}
+
+// WithPrefix returns a context that makes the functions of this package name
+// and recognize scaletest resources by the given prefix instead of
+// ScaleTestPrefix. Scaletests with different prefixes can run against the same
+// deployment concurrently without cleaning up each other's resources, as long
+// as neither prefix is a prefix of the other, e.g. "scaletest" and
+// "scaletest-team-a" overlap but "team-a" and "team-b" do not.
+func WithPrefix(ctx context.Context, prefix string) context.Context {
+	return context.WithValue(ctx, prefixKey{}, prefix)
+}
+
+// Prefix returns the scaletest resource prefix of the context, or
+// ScaleTestPrefix if none was set with WithPrefix.
+func Prefix(ctx context.Context) string {
+	if prefix, ok := ctx.Value(prefixKey{}).(string); ok && prefix != "" {
+		return prefix
+	}
+	return ScaleTestPrefix
+}
+
+// ValidatePrefix returns an error if the prefix cannot be used in usernames,
+// workspace names and email domains.
+func ValidatePrefix(prefix string) error {
+	if !prefixRegex.MatchString(prefix) {
+		return xerrors.Errorf("prefix %q must consist of lowercase letters and digits separated by single hyphens", prefix)
+	}
+	// Leave room for the random part and ID of names, which are limited to
+	// 32 characters.
+	if len(prefix) > 16 {
+		return xerrors.Errorf("prefix %q must be at most 16 characters long", prefix)
+	}
+	return nil
+}
+
+// emailDomain returns the email domain of scaletest users with the given
+// prefix.
+func emailDomain(prefix string) string {
+	if prefix == ScaleTestPrefix {
+		return EmailDomain
+	}
+	return "@" + prefix + "." + ScaleTestPrefix + ".local"
+}
+
 // GenerateUserIdentifier generates a username and email for scale testing.
-// The username follows the pattern: scaletest-<random>-<id>
-// The email follows the pattern: <random>-<id>@scaletest.local
-func GenerateUserIdentifier(id string) (username, email string, err error) {
-	randStr, err := cryptorand.String(DefaultRandLength)
+// The username follows the pattern: <prefix>-<random>-<id>
+// The email follows the pattern: <random>-<id>@scaletest.local, or
+// <random>-<id>@<prefix>.scaletest.local if a prefix was set with WithPrefix.
+// The random part is reproducible if ctx belongs to a seeded test run.
+func GenerateUserIdentifier(ctx context.Context, id string) (username, email string, err error) {
+	randStr, err := randString(ctx, DefaultRandLength)
 	if err != nil {
 		return "", "", err
 	}
 
-	username = fmt.Sprintf("%s-%s-%s", ScaleTestPrefix, randStr, id)
-	email = fmt.Sprintf("%s-%s%s", randStr, id, EmailDomain)
+	prefix := Prefix(ctx)
+	username = fmt.Sprintf("%s-%s-%s", prefix, randStr, id)
+	email = fmt.Sprintf("%s-%s%s", randStr, id, emailDomain(prefix))
 	return username, email, nil
 }
 
 // GenerateWorkspaceName generates a workspace name for scale testing.
-// The workspace name follows the pattern: scaletest-<random>-<id>
-func GenerateWorkspaceName(id string) (name string, err error) {
-	randStr, err := cryptorand.String(DefaultRandLength)
+// The workspace name follows the pattern: <prefix>-<random>-<id>
+// The random part is reproducible if ctx belongs to a seeded test run.
+func GenerateWorkspaceName(ctx context.Context, id string) (name string, err error) {
+	randStr, err := randString(ctx, DefaultRandLength)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("%s-%s-%s", ScaleTestPrefix, randStr, id), nil
+	return fmt.Sprintf("%s-%s-%s", Prefix(ctx), randStr, id), nil
+}
+
+// randString returns a random string of the given size. If ctx belongs to a
+// test run of a harness, the run's seeded random number generator is used so
+// the string can be reproduced.
+func randString(ctx context.Context, size int) (string, error) {
+	if _, ok := harness.Seed(ctx); !ok {
+		return cryptorand.String(size)
+	}
+
+	rng := harness.Rand(ctx)
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = cryptorand.Default[rng.Intn(len(cryptorand.Default))]
+	}
+	return string(b), nil
 }
 
 // GenerateDeterministicWorkspaceName generates a deterministic workspace name
 // for scale testing without a random component. This is useful when the
 // workspace name needs to be known before the workspace is created, such as
 // for pre-creating channels keyed by workspace name.
-// The workspace name follows the pattern: scaletest-<id>
-func GenerateDeterministicWorkspaceName(id string) string {
-	return fmt.Sprintf("%s-%s", ScaleTestPrefix, id)
+// The workspace name follows the pattern: <prefix>-<id>
+func GenerateDeterministicWorkspaceName(ctx context.Context, id string) string {
+	return fmt.Sprintf("%s-%s", Prefix(ctx), id)
+}
+
+// IsScaleTestUser checks if a username indicates it was created for scale
+// testing with the prefix of the context.
+func IsScaleTestUser(ctx context.Context, username, email string) bool {
+	prefix := Prefix(ctx)
+	return strings.HasPrefix(username, prefix+"-") ||
+		strings.HasSuffix(email, emailDomain(prefix))
 }
 
-// IsScaleTestUser checks if a username indicates it was created for scale testing.
-func IsScaleTestUser(username, email string) bool {
-	return strings.HasPrefix(username, ScaleTestPrefix+"-") ||
-		strings.HasSuffix(email, EmailDomain)
+// IsScaleTestWorkspace checks if a workspace name indicates it was created for
+// scale testing with the prefix of the context.
+func IsScaleTestWorkspace(ctx context.Context, workspaceName, ownerName string) bool {
+	prefix := Prefix(ctx)
+	return strings.HasPrefix(workspaceName, prefix+"-") ||
+		strings.HasPrefix(ownerName, prefix+"-")
 }
 
-// IsScaleTestWorkspace checks if a workspace name indicates it was created for scale testing.
-func IsScaleTestWorkspace(workspaceName, ownerName string) bool {
-	return strings.HasPrefix(workspaceName, ScaleTestPrefix+"-") ||
-		strings.HasPrefix(ownerName, ScaleTestPrefix+"-")
+// IsScaleTestToken checks if an API token name or the username of its owner
+// indicates it was created for scale testing with the prefix of the context.
+func IsScaleTestToken(ctx context.Context, tokenName, ownerName string) bool {
+	prefix := Prefix(ctx)
+	return strings.HasPrefix(tokenName, prefix+"-") ||
+		strings.HasPrefix(ownerName, prefix+"-")
 }
@@ -0,0 +1,64 @@
+package loadtestutil_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+func TestPrefix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		require.Equal(t, loadtestutil.ScaleTestPrefix, loadtestutil.Prefix(ctx))
+
+		username, email, err := loadtestutil.GenerateUserIdentifier(ctx, "1")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(username, "scaletest-"))
+		require.True(t, strings.HasSuffix(email, loadtestutil.EmailDomain))
+		require.True(t, loadtestutil.IsScaleTestUser(ctx, username, email))
+		require.Equal(t, "scaletest-1", loadtestutil.GenerateDeterministicWorkspaceName(ctx, "1"))
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := loadtestutil.WithPrefix(context.Background(), "team-a")
+		otherCtx := loadtestutil.WithPrefix(context.Background(), "team-b")
+
+		username, email, err := loadtestutil.GenerateUserIdentifier(ctx, "1")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(username, "team-a-"))
+		require.True(t, strings.HasSuffix(email, "@team-a.scaletest.local"))
+		require.True(t, loadtestutil.IsScaleTestUser(ctx, username, email))
+		// Neither other prefixes nor the default prefix match the user.
+		require.False(t, loadtestutil.IsScaleTestUser(otherCtx, username, email))
+		require.False(t, loadtestutil.IsScaleTestUser(context.Background(), username, email))
+
+		name, err := loadtestutil.GenerateWorkspaceName(ctx, "1")
+		require.NoError(t, err)
+		require.True(t, loadtestutil.IsScaleTestWorkspace(ctx, name, "admin"))
+		require.False(t, loadtestutil.IsScaleTestWorkspace(otherCtx, name, "admin"))
+		require.True(t, loadtestutil.IsScaleTestWorkspace(ctx, "dev", username))
+
+		require.True(t, loadtestutil.IsScaleTestToken(ctx, "team-a-123", "admin"))
+		require.False(t, loadtestutil.IsScaleTestToken(otherCtx, "team-a-123", "admin"))
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, loadtestutil.ValidatePrefix(loadtestutil.ScaleTestPrefix))
+		require.NoError(t, loadtestutil.ValidatePrefix("team-a"))
+		for _, prefix := range []string{"", "Team", "team_a", "-team", "team-", "team--a", "a-very-long-team-prefix"} {
+			require.Error(t, loadtestutil.ValidatePrefix(prefix), prefix)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package notificationdelivery
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/scaletest/createusers"
+	"github.com/coder/coder/v2/scaletest/notifications"
+)
+
+type Config struct {
+	// User is the configuration for the user to create. The user sends the
+	// notifications to themselves.
+	User createusers.Config `json:"user"`
+
+	// Notifications is the number of notifications to send.
+	Notifications int `json:"notifications"`
+
+	// Interval is the time to wait between sending notifications.
+	Interval time.Duration `json:"interval"`
+
+	// DialTimeout is how long to wait for the inbox websocket connection.
+	DialTimeout time.Duration `json:"dial_timeout"`
+
+	// DeliveryTimeout is how long to wait for all notifications to be
+	// delivered after the last one was sent.
+	DeliveryTimeout time.Duration `json:"delivery_timeout"`
+
+	// Webhook receives the notifications dispatched by the webhook method. If
+	// set, every notification is expected to be delivered to it as well.
+	Webhook *WebhookReceiver `json:"-"`
+
+	// SMTPApiURL is the URL of the SMTP mock HTTP API. If set, every
+	// notification is expected to be delivered by email as well.
+	SMTPApiURL string `json:"smtp_api_url"`
+
+	// SMTPRequestTimeout is the timeout for SMTP requests.
+	SMTPRequestTimeout time.Duration `json:"smtp_request_timeout"`
+
+	// SMTPHttpClient is the HTTP client for SMTP requests.
+	SMTPHttpClient *http.Client `json:"-"`
+
+	Metrics *notifications.Metrics `json:"-"`
+}
+
+func (c Config) Validate() error {
+	if c.User.OrganizationID == uuid.Nil {
+		return xerrors.New("user organization_id must be set")
+	}
+
+	if err := c.User.Validate(); err != nil {
+		return xerrors.Errorf("user config: %w", err)
+	}
+
+	if c.Notifications <= 0 {
+		return xerrors.New("notifications must be greater than 0")
+	}
+
+	if c.Interval < 0 {
+		return xerrors.New("interval must be at least 0")
+	}
+
+	if c.DialTimeout <= 0 {
+		return xerrors.New("dial_timeout must be greater than 0")
+	}
+
+	if c.DeliveryTimeout <= 0 {
+		return xerrors.New("delivery_timeout must be greater than 0")
+	}
+
+	if c.SMTPApiURL != "" && c.SMTPRequestTimeout <= 0 {
+		return xerrors.New("smtp_request_timeout must be set if smtp_api_url is set")
+	}
+
+	if c.SMTPApiURL != "" && c.SMTPHttpClient == nil {
+		return xerrors.New("smtp_http_client must be set if smtp_api_url is set")
+	}
+
+	if c.Metrics == nil {
+		return xerrors.New("metrics must be set")
+	}
+
+	return nil
+}
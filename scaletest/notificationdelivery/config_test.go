@@ -0,0 +1,125 @@
+package notificationdelivery_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/createusers"
+	"github.com/coder/coder/v2/scaletest/notificationdelivery"
+	"github.com/coder/coder/v2/scaletest/notifications"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	metrics := notifications.NewMetrics(prometheus.NewRegistry())
+	user := createusers.Config{OrganizationID: uuid.New()}
+	valid := func() notificationdelivery.Config {
+		return notificationdelivery.Config{
+			User:            user,
+			Notifications:   10,
+			Interval:        time.Second,
+			DialTimeout:     time.Minute,
+			DeliveryTimeout: time.Minute,
+			Metrics:         metrics,
+		}
+	}
+
+	cases := []struct {
+		name        string
+		mutate      func(*notificationdelivery.Config)
+		errContains string
+	}{
+		{
+			name:   "OK",
+			mutate: func(*notificationdelivery.Config) {},
+		},
+		{
+			name: "SMTP",
+			mutate: func(c *notificationdelivery.Config) {
+				c.SMTPApiURL = "http://localhost:8080"
+				c.SMTPRequestTimeout = time.Minute
+				c.SMTPHttpClient = http.DefaultClient
+			},
+		},
+		{
+			name: "NoOrganizationID",
+			mutate: func(c *notificationdelivery.Config) {
+				c.User.OrganizationID = uuid.Nil
+			},
+			errContains: "user organization_id must be set",
+		},
+		{
+			name: "NoNotifications",
+			mutate: func(c *notificationdelivery.Config) {
+				c.Notifications = 0
+			},
+			errContains: "notifications must be greater than 0",
+		},
+		{
+			name: "NegativeInterval",
+			mutate: func(c *notificationdelivery.Config) {
+				c.Interval = -time.Second
+			},
+			errContains: "interval must be at least 0",
+		},
+		{
+			name: "NoDialTimeout",
+			mutate: func(c *notificationdelivery.Config) {
+				c.DialTimeout = 0
+			},
+			errContains: "dial_timeout must be greater than 0",
+		},
+		{
+			name: "NoDeliveryTimeout",
+			mutate: func(c *notificationdelivery.Config) {
+				c.DeliveryTimeout = 0
+			},
+			errContains: "delivery_timeout must be greater than 0",
+		},
+		{
+			name: "SMTPNoRequestTimeout",
+			mutate: func(c *notificationdelivery.Config) {
+				c.SMTPApiURL = "http://localhost:8080"
+				c.SMTPHttpClient = http.DefaultClient
+			},
+			errContains: "smtp_request_timeout must be set",
+		},
+		{
+			name: "SMTPNoHTTPClient",
+			mutate: func(c *notificationdelivery.Config) {
+				c.SMTPApiURL = "http://localhost:8080"
+				c.SMTPRequestTimeout = time.Minute
+			},
+			errContains: "smtp_http_client must be set",
+		},
+		{
+			name: "NoMetrics",
+			mutate: func(c *notificationdelivery.Config) {
+				c.Metrics = nil
+			},
+			errContains: "metrics must be set",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := valid()
+			c.mutate(&config)
+			err := config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
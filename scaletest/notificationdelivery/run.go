@@ -0,0 +1,376 @@
+package notificationdelivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	notificationsLib "github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/createusers"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/notifications"
+	"github.com/coder/coder/v2/scaletest/smtpmock"
+	"github.com/coder/websocket"
+)
+
+// NotificationTypeWebhook is the delivery type of notifications dispatched by
+// the webhook method.
+const NotificationTypeWebhook notifications.NotificationType = "webhook"
+
+// Numeric metrics reported by the runner. The delivery metrics are prefixed
+// with the delivery type, e.g. "websocket_delivery_seconds_mean".
+const (
+	NotificationsSentMetric  = "notifications_sent"
+	EnqueueMeanMetric        = "enqueue_seconds_mean"
+	EnqueueMaxMetric         = "enqueue_seconds_max"
+	deliveredMetricSuffix    = "_delivered"
+	deliveryMeanMetricSuffix = "_delivery_seconds_mean"
+	deliveryMaxMetricSuffix  = "_delivery_seconds_max"
+)
+
+const deliveryPollInterval = time.Second
+
+// Runner creates a user that sends itself a number of custom notifications,
+// and measures the time from each notification being sent until it was
+// delivered to the inbox websocket and, if configured, the webhook receiver
+// and the SMTP mock.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	createUserRunner *createusers.Runner
+
+	mu sync.Mutex
+	// sent maps the title of each sent notification to the time the request
+	// to enqueue it was sent.
+	sent             map[string]time.Time
+	enqueueDurations []time.Duration
+	// received maps the delivery type to the time each notification title
+	// was first received. Notifications can be received before the request
+	// to enqueue them returned, so receipts are recorded for any title.
+	received map[notifications.NotificationType]map[string]time.Time
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.CleanupPlanner     = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client:   client,
+		cfg:      cfg,
+		sent:     make(map[string]time.Time),
+		received: make(map[notifications.NotificationType]map[string]time.Time),
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	r.createUserRunner = createusers.NewRunner(r.client, r.cfg.User)
+	newUserAndToken, err := r.createUserRunner.RunReturningUser(ctx, id, logs)
+	if err != nil {
+		r.cfg.Metrics.AddError("create_user")
+		return xerrors.Errorf("create user: %w", err)
+	}
+	newUser := newUserAndToken.User
+	newUserClient := codersdk.New(r.client.URL,
+		codersdk.WithSessionToken(newUserAndToken.SessionToken),
+		codersdk.WithLogger(logger))
+
+	dialCtx, cancel := context.WithTimeout(ctx, r.cfg.DialTimeout)
+	defer cancel()
+	conn, err := dialInboxWebsocket(dialCtx, newUserClient)
+	if err != nil {
+		r.cfg.Metrics.AddError("dial")
+		return xerrors.Errorf("dial inbox websocket: %w", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "done")
+	go r.watchInbox(ctx, conn, logger)
+
+	for i := range r.cfg.Notifications {
+		if i > 0 && r.cfg.Interval > 0 {
+			t := time.NewTimer(r.cfg.Interval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+
+		// The username is random, so the title is unique across runners
+		// and runs.
+		title := fmt.Sprintf("scaletest %s #%d", newUser.Username, i)
+		start := time.Now()
+		err := newUserClient.PostCustomNotification(ctx, codersdk.CustomNotificationRequest{
+			Content: &codersdk.CustomNotificationContent{
+				Title:   title,
+				Message: "Notification sent by the notification delivery scaletest.",
+			},
+		})
+		if err != nil {
+			r.cfg.Metrics.AddError("enqueue")
+			return xerrors.Errorf("send notification %d: %w", i, err)
+		}
+		took := time.Since(start)
+
+		r.mu.Lock()
+		r.sent[title] = start
+		r.enqueueDurations = append(r.enqueueDurations, took)
+		r.mu.Unlock()
+	}
+	logger.Info(ctx, "sent all notifications, waiting for delivery", slog.F("count", r.cfg.Notifications), slog.F("timeout", r.cfg.DeliveryTimeout))
+
+	deliveryCtx, cancel := context.WithTimeout(ctx, r.cfg.DeliveryTimeout)
+	defer cancel()
+	err = r.waitForDelivery(deliveryCtx, newUser.Email, logger)
+	r.recordLatencies()
+	if err != nil {
+		return xerrors.Errorf("wait for delivery: %w", err)
+	}
+
+	return nil
+}
+
+// watchInbox records the receipt time of every notification sent by the
+// runner that is delivered to the inbox websocket, until the connection is
+// closed.
+func (r *Runner) watchInbox(ctx context.Context, conn *websocket.Conn, logger slog.Logger) {
+	for {
+		_, message, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		now := time.Now()
+
+		var notif codersdk.GetInboxNotificationResponse
+		if err := json.Unmarshal(message, &notif); err != nil {
+			r.cfg.Metrics.AddError("read_notification_websocket")
+			logger.Error(ctx, "unmarshal notification", slog.Error(err))
+			continue
+		}
+		if notif.Notification.TemplateID != notificationsLib.TemplateCustomNotification {
+			continue
+		}
+		r.record(notifications.NotificationTypeWebsocket, notif.Notification.Title, now)
+	}
+}
+
+// record records the first receipt time of a notification for a delivery
+// type.
+func (r *Runner) record(typ notifications.NotificationType, title string, receivedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	received, ok := r.received[typ]
+	if !ok {
+		received = make(map[string]time.Time)
+		r.received[typ] = received
+	}
+	if _, ok := received[title]; ok {
+		return
+	}
+	received[title] = receivedAt
+}
+
+// latencies returns the delivery latency of each sent notification that was
+// received by the delivery type. The caller must hold mu.
+func (r *Runner) latencies(typ notifications.NotificationType) []time.Duration {
+	var latencies []time.Duration
+	for title, receivedAt := range r.received[typ] {
+		if sent, ok := r.sent[title]; ok {
+			latencies = append(latencies, receivedAt.Sub(sent))
+		}
+	}
+	return latencies
+}
+
+// recordLatencies records the delivery latencies in the Prometheus metrics.
+func (r *Runner) recordLatencies() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for typ := range r.received {
+		for _, latency := range r.latencies(typ) {
+			r.cfg.Metrics.RecordLatency(latency, notificationsLib.TemplateCustomNotification.String(), typ)
+		}
+	}
+}
+
+// waitForDelivery waits until every sent notification has been received by
+// every configured delivery type.
+func (r *Runner) waitForDelivery(ctx context.Context, email string, logger slog.Logger) error {
+	types := []notifications.NotificationType{notifications.NotificationTypeWebsocket}
+	if r.cfg.Webhook != nil {
+		types = append(types, NotificationTypeWebhook)
+	}
+	if r.cfg.SMTPApiURL != "" {
+		types = append(types, notifications.NotificationTypeSMTP)
+	}
+
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+	for {
+		if r.cfg.Webhook != nil {
+			r.mu.Lock()
+			titles := make([]string, 0, len(r.sent))
+			for title := range r.sent {
+				titles = append(titles, title)
+			}
+			r.mu.Unlock()
+			for _, title := range titles {
+				if receivedAt, ok := r.cfg.Webhook.ReceivedAt(title); ok {
+					r.record(NotificationTypeWebhook, title, receivedAt)
+				}
+			}
+		}
+		if r.cfg.SMTPApiURL != "" {
+			if err := r.pollSMTP(ctx, email); err != nil {
+				r.cfg.Metrics.AddError("smtp_poll")
+				logger.Error(ctx, "poll smtp api for notifications", slog.Error(err))
+			}
+		}
+
+		var pending []string
+		r.mu.Lock()
+		for _, typ := range types {
+			if missing := len(r.sent) - len(r.latencies(typ)); missing > 0 {
+				pending = append(pending, fmt.Sprintf("%d via %s", missing, typ))
+			}
+		}
+		r.mu.Unlock()
+		if len(pending) == 0 {
+			logger.Info(ctx, "all notifications delivered")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("notifications not delivered (%s): %w", strings.Join(pending, ", "), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollSMTP records the notifications received by the SMTP mock for the user.
+func (r *Runner) pollSMTP(ctx context.Context, email string) error {
+	reqCtx, cancel := context.WithTimeout(ctx, r.cfg.SMTPRequestTimeout)
+	defer cancel()
+
+	apiURL := fmt.Sprintf("%s/messages?email=%s", r.cfg.SMTPApiURL, url.QueryEscape(email))
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	resp, err := r.cfg.SMTPHttpClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return xerrors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var summaries []smtpmock.EmailSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summaries); err != nil {
+		return xerrors.Errorf("decode response: %w", err)
+	}
+	for _, summary := range summaries {
+		receivedAt := summary.Date
+		if receivedAt.IsZero() {
+			receivedAt = time.Now()
+		}
+		r.record(notifications.NotificationTypeSMTP, summary.Subject, receivedAt)
+	}
+	return nil
+}
+
+func dialInboxWebsocket(ctx context.Context, client *codersdk.Client) (*websocket.Conn, error) {
+	u, err := client.URL.Parse("/api/v2/notifications/inbox/watch")
+	if err != nil {
+		return nil, xerrors.Errorf("parse notification URL: %w", err)
+	}
+
+	conn, resp, err := websocket.Dial(ctx, u.String(), &websocket.DialOptions{
+		HTTPHeader: http.Header{
+			codersdk.SessionTokenHeader: []string{client.SessionToken()},
+		},
+	})
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusSwitchingProtocols {
+				err = codersdk.ReadBodyAsError(resp)
+			}
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	enqueueMean, enqueueMax := loadtestutil.MeanMax(r.enqueueDurations)
+	metrics := map[string]float64{
+		NotificationsSentMetric: float64(len(r.sent)),
+		EnqueueMeanMetric:       enqueueMean.Seconds(),
+		EnqueueMaxMetric:        enqueueMax.Seconds(),
+	}
+	for typ := range r.received {
+		latencies := r.latencies(typ)
+		mean, maximum := loadtestutil.MeanMax(latencies)
+		metrics[string(typ)+deliveredMetricSuffix] = float64(len(latencies))
+		metrics[string(typ)+deliveryMeanMetricSuffix] = mean.Seconds()
+		metrics[string(typ)+deliveryMaxMetricSuffix] = maximum.Seconds()
+	}
+	return metrics
+}
+
+// Cleanup implements Cleanable.
+func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
+	if r.createUserRunner == nil {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(logs, "Cleaning up user...")
+	if err := r.createUserRunner.Cleanup(ctx, id, logs); err != nil {
+		return xerrors.Errorf("cleanup user: %w", err)
+	}
+	return nil
+}
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(ctx context.Context, id string) ([]harness.CleanupResource, error) {
+	if r.createUserRunner == nil {
+		return nil, nil
+	}
+	return r.createUserRunner.CleanupPlan(ctx, id)
+}
@@ -0,0 +1,93 @@
+package notificationdelivery_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/createusers"
+	"github.com/coder/coder/v2/scaletest/notificationdelivery"
+	"github.com/coder/coder/v2/scaletest/notifications"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// inboxEnqueuer delivers every enqueued notification to the inbox right away,
+// standing in for the notification manager.
+type inboxEnqueuer struct {
+	handler *dispatch.InboxHandler
+}
+
+func (e inboxEnqueuer) Enqueue(ctx context.Context, userID, templateID uuid.UUID, labels map[string]string, createdBy string, targets ...uuid.UUID) ([]uuid.UUID, error) {
+	return e.EnqueueWithData(ctx, userID, templateID, labels, nil, createdBy, targets...)
+}
+
+func (e inboxEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID uuid.UUID, labels map[string]string, _ map[string]any, _ string, targets ...uuid.UUID) ([]uuid.UUID, error) {
+	deliver, err := e.handler.Dispatcher(types.MessagePayload{
+		UserID:                 userID.String(),
+		NotificationTemplateID: templateID.String(),
+		Targets:                targets,
+	}, labels["custom_title"], labels["custom_message"], nil)
+	if err != nil {
+		return nil, err
+	}
+	id := uuid.New()
+	if _, err := deliver(ctx, id); err != nil {
+		return nil, err
+	}
+	return []uuid.UUID{id}, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database:              db,
+		Pubsub:                ps,
+		NotificationsEnqueuer: inboxEnqueuer{handler: dispatch.NewInboxHandler(testutil.Logger(t).Named("inbox"), db, ps)},
+	})
+	firstUser := coderdtest.CreateFirstUser(t, client)
+
+	cfg := notificationdelivery.Config{
+		User: createusers.Config{
+			OrganizationID: firstUser.OrganizationID,
+		},
+		Notifications:   3,
+		Interval:        10 * time.Millisecond,
+		DialTimeout:     testutil.WaitShort,
+		DeliveryTimeout: testutil.WaitShort,
+		Metrics:         notifications.NewMetrics(prometheus.NewRegistry()),
+	}
+	require.NoError(t, cfg.Validate())
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	runner := notificationdelivery.NewRunner(client, cfg)
+	logs := bytes.NewBuffer(nil)
+	err := runner.Run(ctx, "1", logs)
+	t.Log("Runner logs:\n\n" + logs.String())
+	require.NoError(t, err)
+
+	metrics := runner.GetNumericMetrics()
+	require.EqualValues(t, 3, metrics[notificationdelivery.NotificationsSentMetric])
+	require.EqualValues(t, 3, metrics[string(notifications.NotificationTypeWebsocket)+"_delivered"])
+	require.GreaterOrEqual(t, metrics[notificationdelivery.EnqueueMaxMetric], metrics[notificationdelivery.EnqueueMeanMetric])
+
+	plan, err := runner.CleanupPlan(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	err = runner.Cleanup(ctx, "1", bytes.NewBuffer(nil))
+	require.NoError(t, err)
+	users, err := client.Users(ctx, codersdk.UsersRequest{})
+	require.NoError(t, err)
+	require.Len(t, users.Users, 1)
+}
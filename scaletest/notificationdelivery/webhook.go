@@ -0,0 +1,52 @@
+package notificationdelivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+)
+
+// WebhookReceiver is an HTTP handler that receives the notifications
+// dispatched by the webhook method and records when each was received. A
+// single receiver is shared by all runners, and the deployment's webhook
+// endpoint must point at it.
+type WebhookReceiver struct {
+	mu       sync.Mutex
+	received map[string]time.Time
+}
+
+func NewWebhookReceiver() *WebhookReceiver {
+	return &WebhookReceiver{
+		received: make(map[string]time.Time),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (w *WebhookReceiver) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+
+	var payload dispatch.WebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.mu.Lock()
+	if _, ok := w.received[payload.Title]; !ok {
+		w.received[payload.Title] = now
+	}
+	w.mu.Unlock()
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// ReceivedAt returns when the notification with the given title was first
+// received.
+func (w *WebhookReceiver) ReceivedAt(title string) (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t, ok := w.received[title]
+	return t, ok
+}
@@ -0,0 +1,46 @@
+package notificationdelivery_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/scaletest/notificationdelivery"
+)
+
+func TestWebhookReceiver(t *testing.T) {
+	t.Parallel()
+
+	receiver := notificationdelivery.NewWebhookReceiver()
+	post := func(t *testing.T, body []byte) int {
+		t.Helper()
+		rw := httptest.NewRecorder()
+		receiver.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+		return rw.Code
+	}
+
+	_, ok := receiver.ReceivedAt("scaletest foo #0")
+	require.False(t, ok)
+
+	body, err := json.Marshal(dispatch.WebhookPayload{Title: "scaletest foo #0"})
+	require.NoError(t, err)
+	before := time.Now()
+	require.Equal(t, http.StatusNoContent, post(t, body))
+	first, ok := receiver.ReceivedAt("scaletest foo #0")
+	require.True(t, ok)
+	require.False(t, first.Before(before))
+
+	// Retried deliveries keep the first receipt time.
+	require.Equal(t, http.StatusNoContent, post(t, body))
+	again, ok := receiver.ReceivedAt("scaletest foo #0")
+	require.True(t, ok)
+	require.Equal(t, first, again)
+
+	require.Equal(t, http.StatusBadRequest, post(t, []byte("not json")))
+}
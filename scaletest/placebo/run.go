@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"math/rand"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -31,8 +30,7 @@ func NewRunner(cfg Config) *Runner {
 func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) error {
 	sleepDur := time.Duration(r.cfg.Sleep)
 	if r.cfg.Jitter > 0 {
-		//nolint:gosec // not used for crypto
-		sleepDur += time.Duration(rand.Int63n(int64(r.cfg.Jitter)))
+		sleepDur += time.Duration(harness.Rand(ctx).Int63n(int64(r.cfg.Jitter)))
 		// This makes it easier to tell if jitter was applied in tests.
 		sleepDur += time.Millisecond
 	}
@@ -52,8 +50,7 @@ func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) error {
 	if r.cfg.FailureChance > 0 {
 		_, _ = fmt.Fprintf(logs, "failure chance is %f\n", r.cfg.FailureChance)
 		_, _ = fmt.Fprintln(logs, "rolling the dice of fate...")
-		//nolint:gosec // not used for crypto
-		roll := rand.Float64()
+		roll := harness.Rand(ctx).Float64()
 		_, _ = fmt.Fprintf(logs, "rolled: %f\n", roll)
 
 		if roll < r.cfg.FailureChance {
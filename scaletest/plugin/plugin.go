@@ -0,0 +1,150 @@
+// Package plugin lets Go packages that are compiled into the coder binary
+// contribute execution strategies and workloads to the scaletest CLI, without
+// changing its command wiring.
+//
+// Plugins register themselves from an init function:
+//
+//	func init() {
+//		plugin.RegisterWorkload(plugin.Workload{
+//			Name:  "my-workload",
+//			Short: "Load test my workload",
+//			NewRunner: func(ctx context.Context, client *codersdk.Client, _ int) (harness.Runnable, error) {
+//				return newRunner(client), nil
+//			},
+//		})
+//	}
+//
+// and are enabled by importing their package for its side effects from the main
+// package of a custom build:
+//
+//	import _ "example.com/scaletest/myworkload"
+//
+// Registered workloads are available as `coder exp scaletest <name>`, and
+// registered strategies can be selected with the --strategy flag of every
+// scaletest command that runs its jobs concurrently.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/serpent"
+)
+
+// Strategy is an execution strategy contributed by a plugin.
+type Strategy struct {
+	// Name is the value of the --strategy flag that selects the strategy.
+	Name string
+	// Description is shown in the help of the --strategy flag.
+	Description string
+	// New returns the strategy to run the jobs of a test with. Concurrency is
+	// the value of the --concurrency flag, where 0 means unlimited.
+	New func(concurrency int) harness.ExecutionStrategy
+}
+
+// Workload is a scaletest command contributed by a plugin. The command has the
+// same flags as the built-in scaletest commands for the execution strategy,
+// timeouts, outputs, tracing and cleanup, in addition to the workload's own
+// options.
+type Workload struct {
+	// Name is the name of the scaletest subcommand, and the test name of its
+	// runs in the results.
+	Name  string
+	Short string
+	Long  string
+	// Options are additional flags of the command. Their values are set
+	// before Validate and NewRunner are called.
+	Options serpent.OptionSet
+	// Validate is called once before any runner is created, if set.
+	Validate func() error
+	// NewRunner returns the runner of the run with the given ID. IDs start at
+	// 0 and go up to the value of the --count flag, or keep increasing until
+	// the --duration of the test elapses. Every runner gets its own client.
+	NewRunner func(ctx context.Context, client *codersdk.Client, id int) (harness.Runnable, error)
+}
+
+var (
+	mu         sync.Mutex
+	strategies = map[string]Strategy{}
+	workloads  = map[string]Workload{}
+)
+
+// RegisterStrategy makes an execution strategy available to the scaletest CLI.
+// It panics if the strategy is invalid or its name is already registered, and
+// is meant to be called from an init function.
+func RegisterStrategy(s Strategy) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if s.Name == "" || s.New == nil {
+		panic("plugin: strategy must have a name and a New function")
+	}
+	if _, ok := strategies[s.Name]; ok {
+		panic(fmt.Sprintf("plugin: strategy %q is already registered", s.Name))
+	}
+	strategies[s.Name] = s
+}
+
+// RegisterWorkload makes a workload available to the scaletest CLI. It panics
+// if the workload is invalid or its name is already registered, and is meant to
+// be called from an init function. Names must not conflict with built-in
+// scaletest commands.
+func RegisterWorkload(w Workload) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if w.Name == "" || w.NewRunner == nil {
+		panic("plugin: workload must have a name and a NewRunner function")
+	}
+	if strings.ContainsAny(w.Name, " \t\n") {
+		panic(fmt.Sprintf("plugin: workload name %q must not contain whitespace", w.Name))
+	}
+	if _, ok := workloads[w.Name]; ok {
+		panic(fmt.Sprintf("plugin: workload %q is already registered", w.Name))
+	}
+	workloads[w.Name] = w
+}
+
+// Strategies returns the registered strategies sorted by name.
+func Strategies() []Strategy {
+	mu.Lock()
+	defer mu.Unlock()
+
+	list := make([]Strategy, 0, len(strategies))
+	for _, s := range strategies {
+		list = append(list, s)
+	}
+	slices.SortFunc(list, func(a, b Strategy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
+
+// LookupStrategy returns the registered strategy with the given name.
+func LookupStrategy(name string) (Strategy, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	s, ok := strategies[name]
+	return s, ok
+}
+
+// Workloads returns the registered workloads sorted by name.
+func Workloads() []Workload {
+	mu.Lock()
+	defer mu.Unlock()
+
+	list := make([]Workload, 0, len(workloads))
+	for _, w := range workloads {
+		list = append(list, w)
+	}
+	slices.SortFunc(list, func(a, b Workload) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
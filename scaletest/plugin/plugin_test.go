@@ -0,0 +1,65 @@
+package plugin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/plugin"
+)
+
+func newRunner(context.Context, *codersdk.Client, int) (harness.Runnable, error) {
+	return nil, nil
+}
+
+//nolint:paralleltest // the registry is global
+func TestRegister(t *testing.T) {
+	plugin.RegisterStrategy(plugin.Strategy{
+		Name: "zeta",
+		New: func(int) harness.ExecutionStrategy {
+			return harness.LinearExecutionStrategy{}
+		},
+	})
+	plugin.RegisterStrategy(plugin.Strategy{
+		Name: "alpha",
+		New: func(concurrency int) harness.ExecutionStrategy {
+			return harness.ParallelExecutionStrategy{Limit: concurrency}
+		},
+	})
+	plugin.RegisterWorkload(plugin.Workload{Name: "workload-b", NewRunner: newRunner})
+	plugin.RegisterWorkload(plugin.Workload{Name: "workload-a", NewRunner: newRunner})
+
+	strategies := plugin.Strategies()
+	require.Len(t, strategies, 2)
+	require.Equal(t, "alpha", strategies[0].Name)
+	require.Equal(t, "zeta", strategies[1].Name)
+
+	s, ok := plugin.LookupStrategy("alpha")
+	require.True(t, ok)
+	require.Equal(t, harness.ParallelExecutionStrategy{Limit: 3}, s.New(3))
+	_, ok = plugin.LookupStrategy("")
+	require.False(t, ok)
+
+	workloads := plugin.Workloads()
+	require.Len(t, workloads, 2)
+	require.Equal(t, "workload-a", workloads[0].Name)
+	require.Equal(t, "workload-b", workloads[1].Name)
+
+	// Duplicate and invalid registrations are programming errors.
+	require.Panics(t, func() {
+		plugin.RegisterStrategy(plugin.Strategy{Name: "alpha", New: strategies[0].New})
+	})
+	require.Panics(t, func() {
+		plugin.RegisterStrategy(plugin.Strategy{Name: "no-new"})
+	})
+	require.Panics(t, func() {
+		plugin.RegisterWorkload(plugin.Workload{Name: "workload-a", NewRunner: newRunner})
+	})
+	require.Panics(t, func() {
+		plugin.RegisterWorkload(plugin.Workload{Name: "has space", NewRunner: newRunner})
+	})
+	require.Len(t, plugin.Workloads(), 2)
+}
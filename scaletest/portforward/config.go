@@ -0,0 +1,85 @@
+package portforward
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// Pattern is the shape of the traffic sent through each forwarded connection.
+type Pattern string
+
+const (
+	// PatternStream writes BytesPerTick to the connection every tick while
+	// concurrently reading whatever is sent back, without waiting for it.
+	PatternStream Pattern = "stream"
+	// PatternPingPong writes BytesPerTick to the connection every tick and
+	// waits for the same bytes to be echoed back before the next tick,
+	// recording the round trip time.
+	PatternPingPong Pattern = "ping-pong"
+)
+
+type Config struct {
+	// AgentID is the workspace agent ID to which to connect.
+	AgentID uuid.UUID `json:"agent_id"`
+	// WorkspaceName is the workspace name, used for logging.
+	WorkspaceName string `json:"workspace_name"`
+	// AgentName is the agent name, used for logging.
+	AgentName string `json:"agent_name"`
+
+	// Port is the TCP port inside the workspace to forward connections to.
+	// A service that echoes back everything it receives (e.g.
+	// `socat TCP-LISTEN:<port>,fork EXEC:cat`) must be listening on it.
+	Port uint16 `json:"port"`
+	// Connections is the number of concurrent connections to forward to the
+	// port. All connections share a single tunnel to the agent, like
+	// `coder port-forward` does.
+	Connections int `json:"connections"`
+	// DisableDirect forces the tunnel to be relayed through DERP.
+	DisableDirect bool `json:"disable_direct"`
+
+	// Pattern is the traffic pattern to send through each connection.
+	Pattern Pattern `json:"pattern"`
+	// BytesPerTick is the number of bytes to send through each connection per
+	// tick.
+	BytesPerTick int64 `json:"bytes_per_tick"`
+	// TickInterval is the interval between ticks.
+	TickInterval time.Duration `json:"tick_interval"`
+	// Duration is the total duration for which to send traffic.
+	Duration time.Duration `json:"duration"`
+}
+
+func (c Config) Validate() error {
+	if c.AgentID == uuid.Nil {
+		return xerrors.Errorf("validate agent_id: must not be nil")
+	}
+
+	if c.Port == 0 {
+		return xerrors.Errorf("validate port: must be set")
+	}
+
+	if c.Connections <= 0 {
+		return xerrors.Errorf("validate connections: must be greater than zero")
+	}
+
+	switch c.Pattern {
+	case PatternStream, PatternPingPong:
+	default:
+		return xerrors.Errorf("validate pattern: unknown pattern %q", c.Pattern)
+	}
+
+	if c.BytesPerTick <= 0 {
+		return xerrors.Errorf("validate bytes_per_tick: must be greater than zero")
+	}
+
+	if c.TickInterval <= 0 {
+		return xerrors.Errorf("validate tick_interval: must be greater than zero")
+	}
+
+	if c.Duration <= 0 {
+		return xerrors.Errorf("validate duration: must be greater than zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package portforward_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/portforward"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	valid := func() portforward.Config {
+		return portforward.Config{
+			AgentID:      uuid.New(),
+			Port:         8080,
+			Connections:  2,
+			Pattern:      portforward.PatternStream,
+			BytesPerTick: 1024,
+			TickInterval: 100 * time.Millisecond,
+			Duration:     time.Minute,
+		}
+	}
+
+	cases := []struct {
+		name        string
+		mutate      func(c *portforward.Config)
+		errContains string
+	}{
+		{
+			name:   "OK",
+			mutate: func(*portforward.Config) {},
+		},
+		{
+			name: "PingPong",
+			mutate: func(c *portforward.Config) {
+				c.Pattern = portforward.PatternPingPong
+			},
+		},
+		{
+			name: "NoAgentID",
+			mutate: func(c *portforward.Config) {
+				c.AgentID = uuid.Nil
+			},
+			errContains: "agent_id",
+		},
+		{
+			name: "NoPort",
+			mutate: func(c *portforward.Config) {
+				c.Port = 0
+			},
+			errContains: "port",
+		},
+		{
+			name: "NoConnections",
+			mutate: func(c *portforward.Config) {
+				c.Connections = 0
+			},
+			errContains: "connections",
+		},
+		{
+			name: "UnknownPattern",
+			mutate: func(c *portforward.Config) {
+				c.Pattern = "bursty"
+			},
+			errContains: `unknown pattern "bursty"`,
+		},
+		{
+			name: "NoBytesPerTick",
+			mutate: func(c *portforward.Config) {
+				c.BytesPerTick = 0
+			},
+			errContains: "bytes_per_tick",
+		},
+		{
+			name: "NoTickInterval",
+			mutate: func(c *portforward.Config) {
+				c.TickInterval = 0
+			},
+			errContains: "tick_interval",
+		},
+		{
+			name: "NoDuration",
+			mutate: func(c *portforward.Config) {
+				c.Duration = 0
+			},
+			errContains: "duration",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := valid()
+			c.mutate(&cfg)
+			err := cfg.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.ErrorContains(t, err, c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package portforward
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+// Numeric metrics reported by the runner, in addition to the bytes read and
+// written.
+const (
+	ReadThroughputMetric      = "read_bytes_per_second"
+	WriteThroughputMetric     = "write_bytes_per_second"
+	ConnectionErrorsMetric    = "connection_errors"
+	ConnectionErrorRateMetric = "connection_error_rate"
+	RoundTripMeanMetric       = "round_trip_seconds_mean"
+	RoundTripMaxMetric        = "round_trip_seconds_max"
+)
+
+// Runner forwards connections to a port inside a workspace through a tunnel
+// to its agent, the same way `coder port-forward` does, and pushes traffic
+// through them.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
+	connErrors   atomic.Int64
+	elapsed      time.Duration
+
+	mu         sync.Mutex
+	roundTrips []time.Duration
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Collectable        = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+	_ harness.ByteCollectable    = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable. The run fails only if every forwarded connection
+// failed, individual connection failures are reported through the
+// connection_errors and connection_error_rate metrics.
+func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug).With(
+		slog.F("agent_id", r.cfg.AgentID),
+		slog.F("workspace_name", r.cfg.WorkspaceName),
+		slog.F("agent_name", r.cfg.AgentName),
+	)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	logger.Info(ctx, "connecting to workspace agent", slog.F("disable_direct", r.cfg.DisableDirect))
+	conn, err := workspacesdk.New(r.client).DialAgent(ctx, r.cfg.AgentID, &workspacesdk.DialAgentOptions{
+		Logger:         logger.Named("agentconn"),
+		BlockEndpoints: r.cfg.DisableDirect,
+	})
+	if err != nil {
+		return xerrors.Errorf("dial workspace agent: %w", err)
+	}
+	defer conn.Close()
+	if !conn.AwaitReachable(ctx) {
+		return xerrors.Errorf("await agent reachable: %w", ctx.Err())
+	}
+
+	logger.Info(ctx, "forwarding connections",
+		slog.F("port", r.cfg.Port),
+		slog.F("connections", r.cfg.Connections),
+		slog.F("pattern", r.cfg.Pattern),
+		slog.F("bytes_per_tick", r.cfg.BytesPerTick),
+		slog.F("tick_interval", r.cfg.TickInterval),
+	)
+
+	start := time.Now()
+	deadline := start.Add(r.cfg.Duration)
+	rng := harness.Rand(ctx)
+	var eg errgroup.Group
+	for i := range r.cfg.Connections {
+		// The run's random source isn't safe for concurrent use, so every
+		// connection gets its own source derived from it.
+		//nolint:gosec // We want pseudorandomness here to avoid entropy issues.
+		connRand := rand.New(rand.NewSource(rng.Int63()))
+		eg.Go(func() error {
+			err := r.forward(ctx, conn, connRand, deadline)
+			if err != nil {
+				r.connErrors.Add(1)
+				logger.Error(ctx, "forwarded connection failed", slog.F("connection", i), slog.Error(err))
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	r.elapsed = time.Since(start)
+
+	//nolint:gocritic
+	logger.Info(ctx, "traffic summary",
+		slog.F("bytes_read", r.bytesRead.Load()),
+		slog.F("bytes_written", r.bytesWritten.Load()),
+		slog.F("connection_errors", r.connErrors.Load()),
+	)
+
+	if ctx.Err() != nil {
+		return xerrors.Errorf("test did not complete: %w", ctx.Err())
+	}
+	if r.connErrors.Load() == int64(r.cfg.Connections) {
+		return xerrors.Errorf("all %d forwarded connections failed, see logs for details", r.cfg.Connections)
+	}
+	return nil
+}
+
+// forward opens a single forwarded connection and sends traffic through it
+// until the deadline.
+func (r *Runner) forward(ctx context.Context, conn workspacesdk.AgentConn, rng *rand.Rand, deadline time.Time) error {
+	remote, err := conn.DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", r.cfg.Port))
+	if err != nil {
+		return xerrors.Errorf("dial port %d: %w", r.cfg.Port, err)
+	}
+	defer remote.Close()
+
+	// Interrupt any blocked reads and writes once the test is over or the
+	// context is canceled.
+	err = remote.SetDeadline(deadline)
+	if err != nil {
+		return xerrors.Errorf("set deadline: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = remote.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	switch r.cfg.Pattern {
+	case PatternPingPong:
+		err = r.pingPong(ctx, remote, rng)
+	default:
+		err = r.stream(ctx, remote, rng)
+	}
+	if err != nil && !isDeadline(err) {
+		return err
+	}
+	return nil
+}
+
+// stream writes every tick and reads concurrently.
+func (r *Runner) stream(ctx context.Context, remote net.Conn, rng *rand.Rand) error {
+	readErr := make(chan error, 1)
+	go func() {
+		n, err := io.Copy(io.Discard, remote)
+		r.bytesRead.Add(n)
+		readErr <- err
+	}()
+
+	tick := time.NewTicker(r.cfg.TickInterval)
+	defer tick.Stop()
+	p := make([]byte, r.cfg.BytesPerTick)
+	for {
+		select {
+		case <-ctx.Done():
+			<-readErr
+			return ctx.Err()
+		case err := <-readErr:
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			return xerrors.Errorf("read: %w", err)
+		case <-tick.C:
+		}
+
+		_, _ = rng.Read(p)
+		n, err := remote.Write(p)
+		r.bytesWritten.Add(int64(n))
+		if err != nil {
+			<-readErr
+			return xerrors.Errorf("write: %w", err)
+		}
+	}
+}
+
+// pingPong writes every tick and waits for the data to be echoed back.
+func (r *Runner) pingPong(ctx context.Context, remote net.Conn, rng *rand.Rand) error {
+	tick := time.NewTicker(r.cfg.TickInterval)
+	defer tick.Stop()
+	p := make([]byte, r.cfg.BytesPerTick)
+	echo := make([]byte, r.cfg.BytesPerTick)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+		}
+
+		_, _ = rng.Read(p)
+		sent := time.Now()
+		n, err := remote.Write(p)
+		r.bytesWritten.Add(int64(n))
+		if err != nil {
+			return xerrors.Errorf("write: %w", err)
+		}
+		n, err = io.ReadFull(remote, echo)
+		r.bytesRead.Add(int64(n))
+		if err != nil {
+			return xerrors.Errorf("read echo: %w", err)
+		}
+		rtt := time.Since(sent)
+		if !bytes.Equal(p, echo) {
+			return xerrors.New("echoed data does not match the data sent")
+		}
+
+		r.mu.Lock()
+		r.roundTrips = append(r.roundTrips, rtt)
+		r.mu.Unlock()
+	}
+}
+
+func isDeadline(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+const (
+	BytesReadMetric    = harness.BytesReadMetric
+	BytesWrittenMetric = harness.BytesWrittenMetric
+)
+
+// GetMetrics implements Collectable.
+func (r *Runner) GetMetrics() map[string]any {
+	return map[string]any{
+		BytesReadMetric:    r.bytesRead.Load(),
+		BytesWrittenMetric: r.bytesWritten.Load(),
+	}
+}
+
+// GetBytes implements ByteCollectable.
+func (r *Runner) GetBytes() map[string]harness.ByteCount {
+	return map[string]harness.ByteCount{
+		harness.ByteChannelPortForward: {
+			Read:    r.bytesRead.Load(),
+			Written: r.bytesWritten.Load(),
+		},
+	}
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	metrics := map[string]float64{
+		ConnectionErrorsMetric: float64(r.connErrors.Load()),
+	}
+	if r.cfg.Connections > 0 {
+		metrics[ConnectionErrorRateMetric] = float64(r.connErrors.Load()) / float64(r.cfg.Connections)
+	}
+	if seconds := r.elapsed.Seconds(); seconds > 0 {
+		metrics[ReadThroughputMetric] = float64(r.bytesRead.Load()) / seconds
+		metrics[WriteThroughputMetric] = float64(r.bytesWritten.Load()) / seconds
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.roundTrips) > 0 {
+		var total, maximum time.Duration
+		for _, rtt := range r.roundTrips {
+			total += rtt
+			maximum = max(maximum, rtt)
+		}
+		metrics[RoundTripMeanMetric] = (total / time.Duration(len(r.roundTrips))).Seconds()
+		metrics[RoundTripMaxMetric] = maximum.Seconds()
+	}
+	return metrics
+}
@@ -0,0 +1,167 @@
+package portforward_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/portforward"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []portforward.Pattern{portforward.PatternStream, portforward.PatternPingPong} {
+		t.Run(string(pattern), func(t *testing.T) {
+			t.Parallel()
+
+			client, agentID := setupRunnerTest(t)
+			port := echoServer(t)
+
+			runner := portforward.NewRunner(client, portforward.Config{
+				AgentID:      agentID,
+				Port:         port,
+				Connections:  2,
+				Pattern:      pattern,
+				BytesPerTick: 128,
+				TickInterval: 10 * time.Millisecond,
+				Duration:     time.Second,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			logs := bytes.NewBuffer(nil)
+			err := runner.Run(ctx, "1", logs)
+			t.Log("Runner logs:\n\n" + logs.String())
+			require.NoError(t, err)
+
+			metrics := runner.GetMetrics()
+			require.Positive(t, metrics[portforward.BytesReadMetric])
+			require.Positive(t, metrics[portforward.BytesWrittenMetric])
+			byteCounts := runner.GetBytes()[harness.ByteChannelPortForward]
+			require.Equal(t, metrics[portforward.BytesReadMetric], byteCounts.Read)
+			require.Equal(t, metrics[portforward.BytesWrittenMetric], byteCounts.Written)
+			numeric := runner.GetNumericMetrics()
+			require.Zero(t, numeric[portforward.ConnectionErrorsMetric])
+			require.Positive(t, numeric[portforward.ReadThroughputMetric])
+			if pattern == portforward.PatternPingPong {
+				require.Equal(t, metrics[portforward.BytesWrittenMetric], metrics[portforward.BytesReadMetric])
+				require.Positive(t, numeric[portforward.RoundTripMeanMetric])
+			}
+		})
+	}
+
+	t.Run("AllConnectionsFail", func(t *testing.T) {
+		t.Parallel()
+
+		client, agentID := setupRunnerTest(t)
+		// Nothing is listening on the port.
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := uint16(l.Addr().(*net.TCPAddr).Port) //nolint:gosec // Port is always in range.
+		_ = l.Close()
+
+		runner := portforward.NewRunner(client, portforward.Config{
+			AgentID:      agentID,
+			Port:         port,
+			Connections:  2,
+			Pattern:      portforward.PatternStream,
+			BytesPerTick: 128,
+			TickInterval: 10 * time.Millisecond,
+			Duration:     time.Second,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err = runner.Run(ctx, "1", io.Discard)
+		require.ErrorContains(t, err, "all 2 forwarded connections failed")
+		numeric := runner.GetNumericMetrics()
+		require.EqualValues(t, 2, numeric[portforward.ConnectionErrorsMetric])
+		require.EqualValues(t, 1, numeric[portforward.ConnectionErrorRateMetric])
+	})
+}
+
+// echoServer starts a TCP server that echoes back everything it receives and
+// returns its port. The agent runs in the same process, so forwarded
+// connections reach it.
+func echoServer(t *testing.T) uint16 {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return uint16(l.Addr().(*net.TCPAddr).Port) //nolint:gosec // Port is always in range.
+}
+
+func setupRunnerTest(t *testing.T) (client *codersdk.Client, agentID uuid.UUID) {
+	t.Helper()
+
+	client = coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.PlanComplete,
+		ProvisionGraph: []*proto.Response{{
+			Type: &proto.Response_Graph{
+				Graph: &proto.GraphComplete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: "agent",
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Apps: []*proto.App{},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	_ = agenttest.New(t, client.URL, authToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	return client, resources[0].Agents[0].ID
+}
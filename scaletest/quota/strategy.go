@@ -0,0 +1,176 @@
+// Package quota schedules scaletest runs around the workspace quotas of the
+// deployment under test.
+package quota
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// SkipReason is the reason runs skipped by ExecutionStrategyWrapper are
+// marked with in the results.
+const SkipReason = "workspace quota exhausted"
+
+// Client is the subset of *codersdk.Client used to query quotas.
+type Client interface {
+	WorkspaceQuota(ctx context.Context, organizationID string, userID string) (codersdk.WorkspaceQuota, error)
+}
+
+var _ Client = &codersdk.Client{}
+
+// ExecutionStrategyWrapper is a harness.ExecutionStrategy that wraps another
+// strategy and only starts runs while the workspace quota of a user in an
+// organization has enough credits left for the workspace each run creates.
+// Runs that would exceed the quota wait for runs in progress to finish, since
+// those may free up credits, and are skipped once no runs are in progress, so
+// that quota rejections don't count as failures.
+//
+// The quota is queried with the SDK. Deployments without workspace quotas
+// don't limit any runs.
+type ExecutionStrategyWrapper struct {
+	Client         Client
+	OrganizationID uuid.UUID
+	// User is the ID or name of the user that owns the workspaces created by
+	// the runs. Defaults to codersdk.Me.
+	User string
+	// Cost is the number of quota credits the workspace of each run
+	// consumes, i.e. the daily cost of the template. Defaults to 1.
+	Cost int
+	// RunOverQuota runs the runs that would exceed the quota anyway instead
+	// of skipping them, e.g. to measure how quota rejections are handled.
+	// Runs are still delayed until no other runs are in progress.
+	RunOverQuota bool
+	Inner        harness.ExecutionStrategy
+}
+
+var _ harness.ExecutionStrategy = ExecutionStrategyWrapper{}
+
+// Run implements harness.ExecutionStrategy.
+func (w ExecutionStrategyWrapper) Run(ctx context.Context, fns []harness.TestFn) ([]error, error) {
+	t := &tracker{
+		w:        w,
+		released: make(chan struct{}),
+	}
+	if t.w.User == "" {
+		t.w.User = codersdk.Me
+	}
+	t.w.Cost = max(t.w.Cost, 1)
+
+	newFns := make([]harness.TestFn, len(fns))
+	for i, fn := range fns {
+		newFns[i] = func(ctx context.Context) error {
+			proceed, reserved := t.reserve(ctx)
+			if reserved {
+				defer t.release()
+			}
+			if !proceed && !w.RunOverQuota {
+				return fn(harness.SkipRun(ctx, SkipReason))
+			}
+			return fn(ctx)
+		}
+	}
+	return w.Inner.Run(ctx, newFns)
+}
+
+// tracker tracks the quota credits available to runs.
+type tracker struct {
+	w ExecutionStrategyWrapper
+
+	mut sync.Mutex
+	// fetched is set once the quota has been queried.
+	fetched bool
+	// unlimited is set if the deployment doesn't limit the workspaces of the
+	// user.
+	unlimited bool
+	// available is an estimate of the number of credits that haven't been
+	// reserved by a run yet.
+	available int
+	// inFlight is the number of runs in progress that reserved credits.
+	inFlight int
+	// released is closed and replaced whenever a run releases its
+	// reservation.
+	released chan struct{}
+}
+
+// reserve reserves the credits for a run if there are enough left. If there
+// aren't, it waits for runs in progress to finish and gives up once none are
+// left. The run may proceed if credits were reserved, the deployment doesn't
+// limit workspaces, querying the quota failed or the context was canceled, so
+// that the run reports any failure itself.
+func (t *tracker) reserve(ctx context.Context) (proceed, reserved bool) {
+	for {
+		t.mut.Lock()
+		reserved, err := t.tryReserve(ctx)
+		inFlight, released := t.inFlight, t.released
+		t.mut.Unlock()
+		if reserved {
+			return true, true
+		}
+		if err != nil {
+			return true, false
+		}
+		if inFlight == 0 {
+			return false, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return true, false
+		case <-released:
+		}
+	}
+}
+
+// tryReserve must be called with the mutex held.
+func (t *tracker) tryReserve(ctx context.Context) (bool, error) {
+	if t.unlimited {
+		t.inFlight++
+		return true, nil
+	}
+	if !t.fetched || t.available < t.w.Cost {
+		// The estimate doesn't account for runs that released their
+		// reservation without consuming credits, e.g. because they failed or
+		// deleted their workspace, so query the quota again. Credits of runs
+		// in progress may or may not be consumed already, so they're
+		// subtracted to be safe.
+		quota, err := t.w.Client.WorkspaceQuota(ctx, t.w.OrganizationID.String(), t.w.User)
+		if err != nil {
+			var sdkErr *codersdk.Error
+			if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+				// Quotas are an enterprise feature.
+				t.unlimited = true
+				t.inFlight++
+				return true, nil
+			}
+			return false, xerrors.Errorf("get workspace quota: %w", err)
+		}
+		t.fetched = true
+		if quota.Budget < 0 {
+			t.unlimited = true
+			t.inFlight++
+			return true, nil
+		}
+		t.available = quota.Budget - quota.CreditsConsumed - t.inFlight*t.w.Cost
+	}
+	if t.available < t.w.Cost {
+		return false, nil
+	}
+	t.available -= t.w.Cost
+	t.inFlight++
+	return true, nil
+}
+
+func (t *tracker) release() {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	t.inFlight--
+	close(t.released)
+	t.released = make(chan struct{})
+}
@@ -0,0 +1,173 @@
+package quota_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/quota"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// fakeClient simulates a workspace quota that runs consume credits of.
+type fakeClient struct {
+	mut      sync.Mutex
+	budget   int
+	consumed int
+	err      error
+	queries  int
+}
+
+func (c *fakeClient) WorkspaceQuota(context.Context, string, string) (codersdk.WorkspaceQuota, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.queries++
+	if c.err != nil {
+		return codersdk.WorkspaceQuota{}, c.err
+	}
+	return codersdk.WorkspaceQuota{
+		CreditsConsumed: c.consumed,
+		Budget:          c.budget,
+	}, nil
+}
+
+// consume consumes the given credits and fails if that exceeds the budget,
+// like building a workspace does.
+func (c *fakeClient) consume(credits int) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.budget >= 0 && c.consumed+credits > c.budget {
+		return codersdk.NewTestError(http.StatusForbidden, http.MethodPost, "/api/v2/workspaces")
+	}
+	c.consumed += credits
+	return nil
+}
+
+func runHarness(t *testing.T, strategy harness.ExecutionStrategy, count int, runFn func() error) harness.Results {
+	t.Helper()
+	h := harness.NewTestHarness(strategy, harness.ConcurrentExecutionStrategy{})
+	for i := range count {
+		h.AddRun("test", strconv.Itoa(i), testFns{
+			RunFn: func(context.Context, string, io.Writer) error {
+				return runFn()
+			},
+		})
+	}
+	ctx := testutil.Context(t, testutil.WaitShort)
+	err := h.Run(ctx)
+	require.NoError(t, err)
+	return h.Results()
+}
+
+func TestExecutionStrategyWrapper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SkipsOverQuota", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeClient{budget: 10, consumed: 4}
+		strategy := quota.ExecutionStrategyWrapper{
+			Client:         client,
+			OrganizationID: uuid.New(),
+			Cost:           2,
+			Inner:          harness.ParallelExecutionStrategy{Limit: 3},
+		}
+		res := runHarness(t, strategy, 10, func() error {
+			return client.consume(2)
+		})
+
+		require.Equal(t, 3, res.TotalPass)
+		require.Zero(t, res.TotalFail)
+		require.Equal(t, 7, res.TotalSkipped)
+		for _, run := range res.Runs {
+			if run.Skipped {
+				require.Equal(t, quota.SkipReason, run.SkipReason)
+			}
+		}
+	})
+
+	t.Run("CreditsFreed", func(t *testing.T) {
+		t.Parallel()
+
+		// Runs that delete their workspace again free up their credits, so
+		// all of them run.
+		client := &fakeClient{budget: 2}
+		strategy := quota.ExecutionStrategyWrapper{
+			Client:         client,
+			OrganizationID: uuid.New(),
+			Inner:          harness.ConcurrentExecutionStrategy{},
+		}
+		var maxConsumed atomic.Int64
+		res := runHarness(t, strategy, 10, func() error {
+			if err := client.consume(1); err != nil {
+				return err
+			}
+			client.mut.Lock()
+			maxConsumed.Store(max(maxConsumed.Load(), int64(client.consumed)))
+			client.consumed--
+			client.mut.Unlock()
+			return nil
+		})
+
+		require.Equal(t, 10, res.TotalPass)
+		require.Zero(t, res.TotalSkipped)
+		require.LessOrEqual(t, maxConsumed.Load(), int64(2))
+	})
+
+	t.Run("RunOverQuota", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeClient{budget: 2}
+		strategy := quota.ExecutionStrategyWrapper{
+			Client:         client,
+			OrganizationID: uuid.New(),
+			RunOverQuota:   true,
+			Inner:          harness.LinearExecutionStrategy{},
+		}
+		res := runHarness(t, strategy, 4, func() error {
+			return client.consume(1)
+		})
+
+		require.Equal(t, 2, res.TotalPass)
+		require.Equal(t, 2, res.TotalFail)
+		require.Zero(t, res.TotalSkipped)
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		for _, client := range []*fakeClient{
+			{budget: -1},
+			{budget: -1, err: codersdk.NewTestError(http.StatusNotFound, http.MethodGet, "/api/v2/organizations/default/members/me/workspace-quota")},
+		} {
+			strategy := quota.ExecutionStrategyWrapper{
+				Client:         client,
+				OrganizationID: uuid.New(),
+				Inner:          harness.ConcurrentExecutionStrategy{},
+			}
+			res := runHarness(t, strategy, 5, func() error {
+				return client.consume(1)
+			})
+			require.Equal(t, 5, res.TotalPass)
+			// The quota is only queried once if it's unlimited.
+			require.Equal(t, 1, client.queries)
+		}
+	})
+}
+
+type testFns struct {
+	RunFn func(ctx context.Context, id string, logs io.Writer) error
+}
+
+func (fns testFns) Run(ctx context.Context, id string, logs io.Writer) error {
+	return fns.RunFn(ctx, id, logs)
+}
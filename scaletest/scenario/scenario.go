@@ -0,0 +1,294 @@
+// Package scenario defines a file format that describes a scaletest made up of
+// multiple phases, each running a mix of scaletest workloads, so that complex
+// capacity tests can be versioned as configuration rather than scripted as
+// command lines.
+package scenario
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/scaletest/harness"
+)
+
+// namePattern is the pattern that phase and workload names must match. Names
+// are used in paths of output files, so they may not contain path separators.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Scenario is a scaletest made up of phases that are run one after the other.
+type Scenario struct {
+	// Name describes the scenario.
+	Name string `yaml:"name"`
+	// Env contains environment variables that are set for every workload.
+	Env map[string]string `yaml:"env"`
+	// OutputDir is the directory the JSON results of every workload are
+	// written to, as <phase>/<workload>.json, if set.
+	OutputDir string `yaml:"output_dir"`
+	// Phases are run in order.
+	Phases []Phase `yaml:"phases"`
+}
+
+// Phase runs a mix of workloads concurrently.
+type Phase struct {
+	Name string `yaml:"name"`
+	// Duration is the maximum duration of the phase. Workloads that are still
+	// running when it elapses are interrupted, so they stop starting new runs
+	// and clean up once the runs in progress have finished. Interrupted
+	// workloads don't fail the phase. Zero means unlimited.
+	Duration time.Duration `yaml:"duration"`
+	// Strategy is applied to every workload of the phase.
+	Strategy Strategy `yaml:"strategy"`
+	// SLO is asserted on the results of every workload of the phase.
+	SLO SLO `yaml:"slo"`
+	// ContinueOnFailure runs the next phases even if a workload of the phase
+	// fails.
+	ContinueOnFailure bool `yaml:"continue_on_failure"`
+	// Workloads are run concurrently.
+	Workloads []Workload `yaml:"workloads"`
+}
+
+// Strategy configures how the runs of a workload are executed. Zero values
+// leave the defaults of the workload unchanged.
+type Strategy struct {
+	// Concurrency is the number of concurrent runs. Zero means unlimited.
+	Concurrency *int64 `yaml:"concurrency"`
+	// Timeout is the timeout of the whole workload.
+	Timeout time.Duration `yaml:"timeout"`
+	// JobTimeout is the timeout of each run.
+	JobTimeout time.Duration `yaml:"job_timeout"`
+}
+
+// SLO contains the service level objectives that the results of a workload
+// must satisfy. Zero values disable the corresponding objective. See
+// harness.Assertions.
+type SLO struct {
+	MaxErrorRate   float64       `yaml:"max_error_rate"`
+	MaxP95Duration time.Duration `yaml:"max_p95_duration"`
+	MinThroughput  float64       `yaml:"min_throughput"`
+}
+
+// Assertions returns the harness assertions of the objectives.
+func (s SLO) Assertions() harness.Assertions {
+	return harness.Assertions{
+		MaxErrorRate:   s.MaxErrorRate,
+		MaxP95Duration: s.MaxP95Duration,
+		MinThroughput:  s.MinThroughput,
+	}
+}
+
+// Workload is a scaletest command run as part of a phase.
+type Workload struct {
+	// Name identifies the workload within the phase. Defaults to the
+	// command.
+	Name string `yaml:"name"`
+	// Command is the name of the scaletest command, e.g.
+	// "workspace-traffic".
+	Command string `yaml:"command"`
+	// Flags are passed to the command, keyed by flag name without leading
+	// dashes. Lists are passed by repeating the flag. Flags override the
+	// strategy and SLO of the phase.
+	Flags map[string]any `yaml:"flags"`
+	// Env contains environment variables that are set for the workload, in
+	// addition to the ones of the scenario.
+	Env map[string]string `yaml:"env"`
+}
+
+// Parse parses a scenario in YAML, or JSON since it is a subset of YAML, and
+// validates it. Unknown fields are rejected to catch typos.
+func Parse(r io.Reader) (Scenario, error) {
+	var s Scenario
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return Scenario{}, xerrors.Errorf("decode scenario: %w", err)
+	}
+	for i := range s.Phases {
+		for j := range s.Phases[i].Workloads {
+			w := &s.Phases[i].Workloads[j]
+			if w.Name == "" {
+				w.Name = w.Command
+			}
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return Scenario{}, err
+	}
+	return s, nil
+}
+
+// Validate returns an error if the scenario is invalid.
+func (s Scenario) Validate() error {
+	if len(s.Phases) == 0 {
+		return xerrors.New("validate phases: must not be empty")
+	}
+	phases := make(map[string]struct{}, len(s.Phases))
+	for i, p := range s.Phases {
+		if err := p.Validate(); err != nil {
+			return xerrors.Errorf("validate phases[%d]: %w", i, err)
+		}
+		if _, ok := phases[p.Name]; ok {
+			return xerrors.Errorf("validate phases[%d]: duplicate name %q", i, p.Name)
+		}
+		phases[p.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Validate returns an error if the phase is invalid.
+func (p Phase) Validate() error {
+	if !namePattern.MatchString(p.Name) {
+		return xerrors.Errorf("validate name: %q must match %s", p.Name, namePattern)
+	}
+	if p.Duration < 0 {
+		return xerrors.New("validate duration: must not be negative")
+	}
+	if p.Strategy.Concurrency != nil && *p.Strategy.Concurrency < 0 {
+		return xerrors.New("validate strategy: concurrency must not be negative")
+	}
+	if p.Strategy.Timeout < 0 || p.Strategy.JobTimeout < 0 {
+		return xerrors.New("validate strategy: timeouts must not be negative")
+	}
+	if err := p.SLO.Assertions().Validate(); err != nil {
+		return xerrors.Errorf("validate slo: %w", err)
+	}
+	if len(p.Workloads) == 0 {
+		return xerrors.New("validate workloads: must not be empty")
+	}
+	workloads := make(map[string]struct{}, len(p.Workloads))
+	for i, w := range p.Workloads {
+		if w.Command == "" {
+			return xerrors.Errorf("validate workloads[%d]: command must not be empty", i)
+		}
+		if !namePattern.MatchString(w.Name) {
+			return xerrors.Errorf("validate workloads[%d]: name %q must match %s", i, w.Name, namePattern)
+		}
+		if _, ok := workloads[w.Name]; ok {
+			return xerrors.Errorf("validate workloads[%d]: duplicate name %q, set a name to run the same command more than once", i, w.Name)
+		}
+		workloads[w.Name] = struct{}{}
+		if _, err := p.Args(w); err != nil {
+			return xerrors.Errorf("validate workloads[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Args returns the arguments of the scaletest command of the given workload of
+// the phase, starting with the name of the command.
+func (p Phase) Args(w Workload) ([]string, error) {
+	flags := make(map[string]any, len(w.Flags))
+	setDefault := func(name string, value any) {
+		if _, ok := w.Flags[name]; !ok {
+			flags[name] = value
+		}
+	}
+	if p.Strategy.Concurrency != nil {
+		setDefault("concurrency", *p.Strategy.Concurrency)
+	}
+	if p.Strategy.Timeout > 0 {
+		setDefault("timeout", p.Strategy.Timeout)
+	}
+	if p.Strategy.JobTimeout > 0 {
+		setDefault("job-timeout", p.Strategy.JobTimeout)
+	}
+	if p.SLO.MaxErrorRate > 0 {
+		setDefault("assert-max-error-rate", p.SLO.MaxErrorRate)
+	}
+	if p.SLO.MaxP95Duration > 0 {
+		setDefault("assert-max-p95-duration", p.SLO.MaxP95Duration)
+	}
+	if p.SLO.MinThroughput > 0 {
+		setDefault("assert-min-throughput", p.SLO.MinThroughput)
+	}
+	maps.Copy(flags, w.Flags)
+
+	args := []string{w.Command}
+	names := maps.Keys(flags)
+	slices.Sort(names)
+	for _, name := range names {
+		if name == "" || strings.HasPrefix(name, "-") {
+			return nil, xerrors.Errorf("flag %q must be a name without leading dashes", name)
+		}
+		values, err := flagValues(flags[name])
+		if err != nil {
+			return nil, xerrors.Errorf("flag %q: %w", name, err)
+		}
+		for _, v := range values {
+			args = append(args, fmt.Sprintf("--%s=%s", name, v))
+		}
+	}
+	return args, nil
+}
+
+// FlagNames returns the names of the flags passed to the command of the given
+// workload of the phase.
+func (p Phase) FlagNames(w Workload) ([]string, error) {
+	args, err := p.Args(w)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, arg := range args[1:] {
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Environ returns the environment variables of the given workload, in the
+// "key=value" format, overriding the ones of the scenario.
+func (s Scenario) Environ(w Workload) []string {
+	env := make(map[string]string, len(s.Env)+len(w.Env))
+	maps.Copy(env, s.Env)
+	maps.Copy(env, w.Env)
+	keys := maps.Keys(env)
+	slices.Sort(keys)
+	environ := make([]string, 0, len(keys))
+	for _, k := range keys {
+		environ = append(environ, k+"="+env[k])
+	}
+	return environ
+}
+
+// flagValues formats the value of a flag. Lists return one value per element.
+func flagValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, xerrors.New("value must not be empty")
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			if _, ok := elem.([]any); ok {
+				return nil, xerrors.New("lists must not be nested")
+			}
+			elemValues, err := flagValues(elem)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elemValues...)
+		}
+		return values, nil
+	case map[string]any:
+		return nil, xerrors.New("value must not be a map")
+	case string:
+		return []string{v}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case time.Time:
+		// Unquoted dates are decoded as timestamps.
+		return []string{v.Format(time.RFC3339)}, nil
+	default:
+		return []string{fmt.Sprint(v)}, nil
+	}
+}
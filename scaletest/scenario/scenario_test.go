@@ -0,0 +1,155 @@
+package scenario_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/scenario"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("YAML", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := scenario.Parse(strings.NewReader(`
+name: capacity
+env:
+  CODER_SCALETEST_PREFIX: capacity
+output_dir: results
+phases:
+  - name: create
+    strategy:
+      concurrency: 10
+      job_timeout: 10m
+    slo:
+      max_error_rate: 0.01
+    workloads:
+      - command: create-workspaces
+        flags:
+          count: 100
+          template: docker
+  - name: traffic
+    duration: 30m
+    workloads:
+      - command: workspace-traffic
+        flags:
+          bytes-per-tick: 1024
+          tick-interval: 100ms
+          concurrency: 0
+      - name: dashboard
+        command: dashboard
+        env:
+          CODER_SCALETEST_DASHBOARD_HEADLESS: "true"
+        flags:
+          target-user: [alice, bob]
+`))
+		require.NoError(t, err)
+		require.Equal(t, "capacity", s.Name)
+		require.Len(t, s.Phases, 2)
+		require.Equal(t, 30*time.Minute, s.Phases[1].Duration)
+
+		create := s.Phases[0]
+		args, err := create.Args(create.Workloads[0])
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"create-workspaces",
+			"--assert-max-error-rate=0.01",
+			"--concurrency=10",
+			"--count=100",
+			"--job-timeout=10m0s",
+			"--template=docker",
+		}, args)
+
+		traffic := s.Phases[1]
+		require.Equal(t, "workspace-traffic", traffic.Workloads[0].Name)
+		args, err = traffic.Args(traffic.Workloads[0])
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"workspace-traffic",
+			"--bytes-per-tick=1024",
+			"--concurrency=0",
+			"--tick-interval=100ms",
+		}, args)
+		args, err = traffic.Args(traffic.Workloads[1])
+		require.NoError(t, err)
+		require.Equal(t, []string{"dashboard", "--target-user=alice", "--target-user=bob"}, args)
+		require.Equal(t, []string{
+			"CODER_SCALETEST_DASHBOARD_HEADLESS=true",
+			"CODER_SCALETEST_PREFIX=capacity",
+		}, s.Environ(traffic.Workloads[1]))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := scenario.Parse(strings.NewReader(`{
+			"phases": [{
+				"name": "churn",
+				"strategy": {"concurrency": 5, "timeout": "1h"},
+				"workloads": [{"command": "workspace-churn", "flags": {"count": 20}}]
+			}]
+		}`))
+		require.NoError(t, err)
+		args, err := s.Phases[0].Args(s.Phases[0].Workloads[0])
+		require.NoError(t, err)
+		require.Equal(t, []string{"workspace-churn", "--concurrency=5", "--count=20", "--timeout=1h0m0s"}, args)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for name, tc := range map[string]struct {
+			scenario string
+			err      string
+		}{
+			"NoPhases": {
+				scenario: `name: empty`,
+				err:      "phases: must not be empty",
+			},
+			"UnknownField": {
+				scenario: `phases: [{name: a, workload: []}]`,
+				err:      "field workload not found",
+			},
+			"NoWorkloads": {
+				scenario: `phases: [{name: a}]`,
+				err:      "workloads: must not be empty",
+			},
+			"DuplicatePhase": {
+				scenario: `phases: [{name: a, workloads: [{command: x}]}, {name: a, workloads: [{command: x}]}]`,
+				err:      `duplicate name "a"`,
+			},
+			"DuplicateWorkload": {
+				scenario: `phases: [{name: a, workloads: [{command: x}, {command: x}]}]`,
+				err:      `duplicate name "x"`,
+			},
+			"InvalidName": {
+				scenario: `phases: [{name: ../a, workloads: [{command: x}]}]`,
+				err:      "must match",
+			},
+			"InvalidSLO": {
+				scenario: `phases: [{name: a, slo: {max_error_rate: 2}, workloads: [{command: x}]}]`,
+				err:      "max error rate must be between 0 and 1",
+			},
+			"MapFlag": {
+				scenario: `phases: [{name: a, workloads: [{command: x, flags: {header: {a: b}}}]}]`,
+				err:      "must not be a map",
+			},
+			"DashedFlag": {
+				scenario: `phases: [{name: a, workloads: [{command: x, flags: {--count: 1}}]}]`,
+				err:      "without leading dashes",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := scenario.Parse(strings.NewReader(tc.scenario))
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package sshcli
+
+import (
+	"net/url"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type Config struct {
+	// BinaryPath is the path to the coder binary to run. Defaults to the
+	// currently running executable.
+	BinaryPath string `json:"binary_path"`
+	// URL is the URL of the Coder deployment.
+	URL *url.URL `json:"url"`
+	// SessionToken is the session token the CLI authenticates with.
+	SessionToken string `json:"-"`
+	// Workspace is the workspace to connect to, in the format accepted by
+	// `coder ssh`, e.g. "owner/workspace.agent".
+	Workspace string `json:"workspace"`
+	// Command is the command to run in the workspace. It should print
+	// something, as the time until the first output is the time to shell.
+	Command string `json:"command"`
+	// Iterations is the number of times to run `coder ssh`.
+	Iterations int `json:"iterations"`
+	// Interval is the time to wait between iterations.
+	Interval time.Duration `json:"interval"`
+	// DisableDirect forces the connection to be relayed through DERP.
+	DisableDirect bool `json:"disable_direct"`
+	// ExtraArgs are additional flags passed to `coder ssh`.
+	ExtraArgs []string `json:"extra_args"`
+}
+
+func (c Config) Validate() error {
+	if c.URL == nil {
+		return xerrors.New("validate url: must be set")
+	}
+	if c.SessionToken == "" {
+		return xerrors.New("validate session_token: must be set")
+	}
+	if c.Workspace == "" {
+		return xerrors.New("validate workspace: must be set")
+	}
+	if c.Command == "" {
+		return xerrors.New("validate command: must be set")
+	}
+	if c.Iterations <= 0 {
+		return xerrors.New("validate iterations: must be greater than zero")
+	}
+	if c.Interval < 0 {
+		return xerrors.New("validate interval: must not be negative")
+	}
+	return nil
+}
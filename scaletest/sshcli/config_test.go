@@ -0,0 +1,111 @@
+package sshcli_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/sshcli"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		config      sshcli.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: sshcli.Config{
+				URL:          u,
+				SessionToken: "token",
+				Workspace:    "ws",
+				Command:      "echo ok",
+				Iterations:   1,
+				Interval:     time.Second,
+			},
+		},
+		{
+			name: "NoURL",
+			config: sshcli.Config{
+				SessionToken: "token",
+				Workspace:    "ws",
+				Command:      "echo ok",
+				Iterations:   1,
+			},
+			errContains: "url",
+		},
+		{
+			name: "NoSessionToken",
+			config: sshcli.Config{
+				URL:        u,
+				Workspace:  "ws",
+				Command:    "echo ok",
+				Iterations: 1,
+			},
+			errContains: "session_token",
+		},
+		{
+			name: "NoWorkspace",
+			config: sshcli.Config{
+				URL:          u,
+				SessionToken: "token",
+				Command:      "echo ok",
+				Iterations:   1,
+			},
+			errContains: "workspace",
+		},
+		{
+			name: "NoCommand",
+			config: sshcli.Config{
+				URL:          u,
+				SessionToken: "token",
+				Workspace:    "ws",
+				Iterations:   1,
+			},
+			errContains: "command",
+		},
+		{
+			name: "NoIterations",
+			config: sshcli.Config{
+				URL:          u,
+				SessionToken: "token",
+				Workspace:    "ws",
+				Command:      "echo ok",
+			},
+			errContains: "iterations",
+		},
+		{
+			name: "NegativeInterval",
+			config: sshcli.Config{
+				URL:          u,
+				SessionToken: "token",
+				Workspace:    "ws",
+				Command:      "echo ok",
+				Iterations:   1,
+				Interval:     -time.Second,
+			},
+			errContains: "interval",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.ErrorContains(t, err, c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
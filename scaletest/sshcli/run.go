@@ -0,0 +1,165 @@
+package sshcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+)
+
+// Numeric metrics reported by the runner.
+const (
+	TimeToShellMeanMetric = "time_to_shell_seconds_mean"
+	TimeToShellMaxMetric  = "time_to_shell_seconds_max"
+	SessionMeanMetric     = "session_seconds_mean"
+	SessionMaxMetric      = "session_seconds_max"
+)
+
+// Runner runs the real `coder ssh` command as a subprocess, so the whole flow
+// is measured: CLI startup, token authentication, workspace lookup,
+// coordinator negotiation and establishing the DERP or direct connection.
+type Runner struct {
+	cfg Config
+
+	timesToShell []time.Duration
+	sessions     []time.Duration
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(cfg Config) *Runner {
+	return &Runner{
+		cfg: cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+
+	binaryPath := r.cfg.BinaryPath
+	if binaryPath == "" {
+		var err error
+		binaryPath, err = os.Executable()
+		if err != nil {
+			return xerrors.Errorf("get coder binary path: %w", err)
+		}
+	}
+
+	// Use a fresh config directory so the CLI authenticates with the
+	// configured token rather than any stored session.
+	configDir, err := os.MkdirTemp("", "coder-scaletest-ssh-*")
+	if err != nil {
+		return xerrors.Errorf("create config dir: %w", err)
+	}
+	defer os.RemoveAll(configDir)
+
+	for i := 1; i <= r.cfg.Iterations; i++ {
+		_, _ = fmt.Fprintf(logs, "Running coder ssh %d/%d...\n", i, r.cfg.Iterations)
+		timeToShell, session, err := r.ssh(ctx, binaryPath, configDir, logs)
+		if err != nil {
+			return xerrors.Errorf("coder ssh %d/%d: %w", i, r.cfg.Iterations, err)
+		}
+		_, _ = fmt.Fprintf(logs, "\tTime to shell: %s, session: %s\n", timeToShell, session)
+		r.timesToShell = append(r.timesToShell, timeToShell)
+		r.sessions = append(r.sessions, session)
+
+		if i < r.cfg.Iterations && r.cfg.Interval > 0 {
+			t := time.NewTimer(r.cfg.Interval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+	}
+
+	return nil
+}
+
+// ssh runs `coder ssh` once and returns the time until the command printed
+// its first output, and the time until the process exited.
+func (r *Runner) ssh(ctx context.Context, binaryPath, configDir string, logs io.Writer) (timeToShell, session time.Duration, err error) {
+	args := append([]string{"ssh"}, r.cfg.ExtraArgs...)
+	args = append(args, r.cfg.Workspace, r.cfg.Command)
+	//nolint:gosec // The binary and arguments are provided by the operator.
+	cmd := exec.CommandContext(ctx, binaryPath, args...)
+	cmd.Env = append(os.Environ(),
+		"CODER_URL="+r.cfg.URL.String(),
+		"CODER_SESSION_TOKEN="+r.cfg.SessionToken,
+		"CODER_CONFIG_DIR="+configDir,
+		"CODER_DISABLE_DIRECT_CONNECTIONS="+strconv.FormatBool(r.cfg.DisableDirect),
+	)
+	stdout := &firstWriteWriter{w: logs}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = io.MultiWriter(logs, stderr)
+
+	start := time.Now()
+	err = cmd.Run()
+	session = time.Since(start)
+	if err != nil {
+		return 0, session, xerrors.Errorf("run %q: %w: %s", cmd.String(), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	first, ok := stdout.first()
+	if !ok {
+		return 0, session, xerrors.New("command exited without printing any output")
+	}
+	return first.Sub(start), session, nil
+}
+
+// firstWriteWriter records the time of the first non-empty write.
+type firstWriteWriter struct {
+	w io.Writer
+
+	mu        sync.Mutex
+	firstTime time.Time
+}
+
+func (f *firstWriteWriter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	if f.firstTime.IsZero() && len(p) > 0 {
+		f.firstTime = time.Now()
+	}
+	f.mu.Unlock()
+	return f.w.Write(p)
+}
+
+func (f *firstWriteWriter) first() (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.firstTime, !f.firstTime.IsZero()
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	if len(r.timesToShell) == 0 {
+		return nil
+	}
+	timeToShellMean, timeToShellMax := loadtestutil.MeanMax(r.timesToShell)
+	sessionMean, sessionMax := loadtestutil.MeanMax(r.sessions)
+	return map[string]float64{
+		TimeToShellMeanMetric: timeToShellMean.Seconds(),
+		TimeToShellMaxMetric:  timeToShellMax.Seconds(),
+		SessionMeanMetric:     sessionMean.Seconds(),
+		SessionMaxMetric:      sessionMax.Seconds(),
+	}
+}
@@ -0,0 +1,97 @@
+package sshcli_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/scaletest/sshcli"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// fakeCoder writes a script that stands in for the coder binary. It prints the
+// arguments and the authentication environment it was run with.
+func fakeCoder(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the coder binary")
+	}
+
+	path := filepath.Join(t.TempDir(), "coder")
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700) //nolint:gosec // Needs to be executable.
+	require.NoError(t, err)
+	return path
+}
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		runner := sshcli.NewRunner(sshcli.Config{
+			BinaryPath:    fakeCoder(t, `echo "args: $*"; echo "url: $CODER_URL token: $CODER_SESSION_TOKEN direct: $CODER_DISABLE_DIRECT_CONNECTIONS"`),
+			URL:           u,
+			SessionToken:  "token",
+			Workspace:     "admin/ws.main",
+			Command:       "echo ok",
+			Iterations:    2,
+			DisableDirect: true,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		logs := bytes.NewBuffer(nil)
+		err := runner.Run(ctx, "1", logs)
+		require.NoError(t, err)
+
+		require.Contains(t, logs.String(), "args: ssh admin/ws.main echo ok")
+		require.Contains(t, logs.String(), "url: https://coder.example.com token: token direct: true")
+		require.Contains(t, logs.String(), "Running coder ssh 2/2")
+
+		metrics := runner.GetNumericMetrics()
+		require.Positive(t, metrics[sshcli.TimeToShellMeanMetric])
+		require.GreaterOrEqual(t, metrics[sshcli.SessionMaxMetric], metrics[sshcli.TimeToShellMaxMetric])
+	})
+
+	t.Run("Fails", func(t *testing.T) {
+		t.Parallel()
+
+		runner := sshcli.NewRunner(sshcli.Config{
+			BinaryPath:   fakeCoder(t, `echo "workspace not found" >&2; exit 1`),
+			URL:          u,
+			SessionToken: "token",
+			Workspace:    "admin/ws",
+			Command:      "echo ok",
+			Iterations:   1,
+		})
+
+		err := runner.Run(context.Background(), "1", bytes.NewBuffer(nil))
+		require.ErrorContains(t, err, "workspace not found")
+		require.Nil(t, runner.GetNumericMetrics())
+	})
+
+	t.Run("NoOutput", func(t *testing.T) {
+		t.Parallel()
+
+		runner := sshcli.NewRunner(sshcli.Config{
+			BinaryPath:   fakeCoder(t, `exit 0`),
+			URL:          u,
+			SessionToken: "token",
+			Workspace:    "admin/ws",
+			Command:      "true",
+			Iterations:   1,
+		})
+
+		err := runner.Run(context.Background(), "1", bytes.NewBuffer(nil))
+		require.ErrorContains(t, err, "without printing any output")
+	})
+}
@@ -0,0 +1,64 @@
+package templatechurn
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type Config struct {
+	// OrganizationID is the ID of the organization to create the template in.
+	OrganizationID uuid.UUID `json:"organization_id"`
+	// ProvisionerType is the provisioner that imports the template versions.
+	// Defaults to Terraform. The echo provisioner skips Terraform entirely,
+	// to measure the overhead of coderd itself.
+	ProvisionerType codersdk.ProvisionerType `json:"provisioner_type"`
+	// ProvisionerTags are optional tags used to route template version import
+	// and workspace build jobs to specific provisioner daemons.
+	ProvisionerTags map[string]string `json:"provisioner_tags"`
+	// Workspaces is the number of workspaces created from the template. Every
+	// workspace is updated to each new template version once it has been
+	// promoted.
+	Workspaces int `json:"workspaces"`
+	// Versions is the number of new template versions to push after the
+	// initial one.
+	Versions int `json:"versions"`
+	// Interval is the time to wait between pushing template versions.
+	Interval time.Duration `json:"interval"`
+	// TemplateVersionJobTimeout is how long to wait for a template version
+	// import job to complete.
+	TemplateVersionJobTimeout time.Duration `json:"template_version_job_timeout"`
+	// WorkspaceBuildTimeout is how long to wait for all workspaces to be
+	// built initially, and to be updated to each new template version.
+	WorkspaceBuildTimeout time.Duration `json:"workspace_build_timeout"`
+}
+
+func (c Config) Validate() error {
+	if c.OrganizationID == uuid.Nil {
+		return xerrors.New("organization_id must be set")
+	}
+	switch c.ProvisionerType {
+	case "", codersdk.ProvisionerTypeTerraform, codersdk.ProvisionerTypeEcho:
+	default:
+		return xerrors.Errorf("unsupported provisioner_type %q", c.ProvisionerType)
+	}
+	if c.Workspaces < 0 {
+		return xerrors.New("workspaces must be at least 0")
+	}
+	if c.Versions <= 0 {
+		return xerrors.New("versions must be greater than 0")
+	}
+	if c.Interval < 0 {
+		return xerrors.New("interval must be at least 0")
+	}
+	if c.TemplateVersionJobTimeout <= 0 {
+		return xerrors.New("template_version_job_timeout must be greater than 0")
+	}
+	if c.Workspaces > 0 && c.WorkspaceBuildTimeout <= 0 {
+		return xerrors.New("workspace_build_timeout must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package templatechurn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/templatechurn"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	cases := []struct {
+		name        string
+		config      templatechurn.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				Workspaces:                5,
+				Versions:                  3,
+				Interval:                  time.Second,
+				TemplateVersionJobTimeout: time.Minute,
+				WorkspaceBuildTimeout:     time.Minute,
+			},
+		},
+		{
+			name: "NoWorkspaces",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				Versions:                  1,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+		},
+		{
+			name: "EchoProvisioner",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				ProvisionerType:           codersdk.ProvisionerTypeEcho,
+				Versions:                  1,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+		},
+		{
+			name: "UnsupportedProvisioner",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				ProvisionerType:           "pulumi",
+				Versions:                  1,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+			errContains: `unsupported provisioner_type "pulumi"`,
+		},
+		{
+			name: "NoOrganizationID",
+			config: templatechurn.Config{
+				Versions:                  1,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+			errContains: "organization_id must be set",
+		},
+		{
+			name: "NegativeWorkspaces",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				Workspaces:                -1,
+				Versions:                  1,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+			errContains: "workspaces must be at least 0",
+		},
+		{
+			name: "NoVersions",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+			errContains: "versions must be greater than 0",
+		},
+		{
+			name: "NegativeInterval",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				Versions:                  1,
+				Interval:                  -time.Second,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+			errContains: "interval must be at least 0",
+		},
+		{
+			name: "NoTemplateVersionJobTimeout",
+			config: templatechurn.Config{
+				OrganizationID: id,
+				Versions:       1,
+			},
+			errContains: "template_version_job_timeout must be greater than 0",
+		},
+		{
+			name: "NoWorkspaceBuildTimeout",
+			config: templatechurn.Config{
+				OrganizationID:            id,
+				Workspaces:                1,
+				Versions:                  1,
+				TemplateVersionJobTimeout: time.Minute,
+			},
+			errContains: "workspace_build_timeout must be greater than 0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
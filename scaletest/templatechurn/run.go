@@ -0,0 +1,423 @@
+package templatechurn
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+// TemplatePrefix is the name prefix applied to all templates created by the
+// template churn runner.
+const TemplatePrefix = "scaletest-template-churn-"
+
+// Numeric metrics reported by the runner.
+const (
+	VersionsPushedMetric     = "template_versions_pushed"
+	ImportQueueMeanMetric    = "template_import_queue_seconds_mean"
+	ImportQueueMaxMetric     = "template_import_queue_seconds_max"
+	ImportDurationMeanMetric = "template_import_seconds_mean"
+	ImportDurationMaxMetric  = "template_import_seconds_max"
+	UpdateFanoutMeanMetric   = "update_fanout_seconds_mean"
+	UpdateFanoutMaxMetric    = "update_fanout_seconds_max"
+	WorkspaceUpdatesMetric   = "workspace_updates"
+)
+
+const jobPollInterval = time.Second
+
+// Runner creates a template with a set of workspaces, then repeatedly pushes
+// new template versions, promotes them and updates every workspace to them.
+// The time each import job spent queued and running, and the time from
+// promoting a version until all workspaces were updated to it, are recorded.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	template   codersdk.Template
+	workspaces []uuid.UUID
+
+	importQueueTimes []time.Duration
+	importDurations  []time.Duration
+	fanoutDurations  []time.Duration
+	workspaceUpdates int
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.CleanupPlanner     = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	version, err := r.pushTemplateVersion(ctx, logs, uuid.Nil, 0)
+	if err != nil {
+		return xerrors.Errorf("push initial template version: %w", err)
+	}
+	r.template, err = r.client.CreateTemplate(ctx, r.cfg.OrganizationID, codersdk.CreateTemplateRequest{
+		Name:        TemplatePrefix + id,
+		Description: "`coder exp scaletest template-churn` template",
+		VersionID:   version.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("create template: %w", err)
+	}
+	_, _ = fmt.Fprintf(logs, "Created template %q (%s)\n", r.template.Name, r.template.ID)
+
+	if r.cfg.Workspaces > 0 {
+		err = r.createWorkspaces(ctx, logs, id)
+		if err != nil {
+			return xerrors.Errorf("create workspaces: %w", err)
+		}
+	}
+
+	for v := 1; v <= r.cfg.Versions; v++ {
+		if err := r.wait(ctx); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(logs, "Pushing template version %d/%d...\n", v, r.cfg.Versions)
+		version, err := r.pushTemplateVersion(ctx, logs, r.template.ID, v)
+		if err != nil {
+			return xerrors.Errorf("push template version %d: %w", v, err)
+		}
+		err = r.client.UpdateActiveTemplateVersion(ctx, r.template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("promote template version %d: %w", v, err)
+		}
+
+		if len(r.workspaces) == 0 {
+			continue
+		}
+		err = r.updateWorkspaces(ctx, logs, version.ID)
+		if err != nil {
+			return xerrors.Errorf("update workspaces to template version %d: %w", v, err)
+		}
+	}
+
+	return nil
+}
+
+// createWorkspaces creates the workspaces from the template and waits for all
+// of their builds to complete.
+func (r *Runner) createWorkspaces(ctx context.Context, logs io.Writer, id string) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.WorkspaceBuildTimeout)
+	defer cancel()
+
+	builds := make([]uuid.UUID, 0, r.cfg.Workspaces)
+	for i := range r.cfg.Workspaces {
+		name, err := loadtestutil.GenerateWorkspaceName(ctx, fmt.Sprintf("%s-%d", id, i))
+		if err != nil {
+			return xerrors.Errorf("generate random name for workspace: %w", err)
+		}
+		workspace, err := r.client.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: r.template.ID,
+			Name:       name,
+		})
+		if err != nil {
+			return xerrors.Errorf("create workspace: %w", err)
+		}
+		r.workspaces = append(r.workspaces, workspace.ID)
+		builds = append(builds, workspace.LatestBuild.ID)
+		_, _ = fmt.Fprintf(logs, "Created workspace %q (%s)\n", workspace.Name, workspace.ID)
+	}
+	for _, buildID := range builds {
+		if _, err := r.waitForBuild(ctx, buildID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushTemplateVersion uploads and imports a new template version and waits
+// for the import job to complete. A nil templateID creates a version that is
+// not yet attached to a template.
+func (r *Runner) pushTemplateVersion(ctx context.Context, logs io.Writer, templateID uuid.UUID, v int) (codersdk.TemplateVersion, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	tarData, err := r.templateTarData(v)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("create template tar: %w", err)
+	}
+	upload, err := r.client.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(tarData))
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("upload template tar: %w", err)
+	}
+	version, err := r.client.CreateTemplateVersion(ctx, r.cfg.OrganizationID, codersdk.CreateTemplateVersionRequest{
+		TemplateID:      templateID,
+		FileID:          upload.ID,
+		Message:         fmt.Sprintf("Template version %d for scaletest template churn", v),
+		StorageMethod:   codersdk.ProvisionerStorageMethodFile,
+		Provisioner:     r.provisionerType(),
+		ProvisionerTags: r.cfg.ProvisionerTags,
+	})
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("create template version: %w", err)
+	}
+	if version.MatchedProvisioners != nil && version.MatchedProvisioners.Count == 0 {
+		return codersdk.TemplateVersion{}, xerrors.New("no provisioners matched for template version")
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, r.cfg.TemplateVersionJobTimeout)
+	defer cancel()
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		version, err = r.client.TemplateVersion(jobCtx, version.ID)
+		if err != nil {
+			return codersdk.TemplateVersion{}, xerrors.Errorf("fetch template version: %w", err)
+		}
+
+		job := version.Job
+		if job.CompletedAt != nil {
+			if job.Status != codersdk.ProvisionerJobSucceeded {
+				return codersdk.TemplateVersion{}, xerrors.Errorf("import failed with status %q: %s", job.Status, job.Error)
+			}
+			var queued, running time.Duration
+			if job.StartedAt != nil {
+				queued = job.StartedAt.Sub(job.CreatedAt)
+				running = job.CompletedAt.Sub(*job.StartedAt)
+			}
+			r.importQueueTimes = append(r.importQueueTimes, queued)
+			r.importDurations = append(r.importDurations, running)
+			_, _ = fmt.Fprintf(logs, "\tImported template version %q: queued for %s, ran for %s\n", version.Name, queued, running)
+			return version, nil
+		}
+
+		select {
+		case <-jobCtx.Done():
+			return codersdk.TemplateVersion{}, xerrors.Errorf("wait for import job: %w", jobCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateWorkspaces starts a build on the given template version for every
+// workspace and waits for all of them to complete, recording the time it took
+// for the update to fan out to all workspaces.
+func (r *Runner) updateWorkspaces(ctx context.Context, logs io.Writer, versionID uuid.UUID) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.WorkspaceBuildTimeout)
+	defer cancel()
+
+	start := time.Now()
+	builds := make([]uuid.UUID, 0, len(r.workspaces))
+	for _, workspaceID := range r.workspaces {
+		build, err := r.client.CreateWorkspaceBuild(ctx, workspaceID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: versionID,
+			Transition:        codersdk.WorkspaceTransitionStart,
+		})
+		if err != nil {
+			return xerrors.Errorf("create build for workspace %s: %w", workspaceID, err)
+		}
+		builds = append(builds, build.ID)
+	}
+	for _, buildID := range builds {
+		if _, err := r.waitForBuild(ctx, buildID); err != nil {
+			return err
+		}
+		r.workspaceUpdates++
+	}
+
+	took := time.Since(start)
+	r.fanoutDurations = append(r.fanoutDurations, took)
+	_, _ = fmt.Fprintf(logs, "\tUpdated %d workspaces in %s\n", len(builds), took)
+	return nil
+}
+
+// waitForBuild polls the build until its job has completed.
+func (r *Runner) waitForBuild(ctx context.Context, buildID uuid.UUID) (codersdk.WorkspaceBuild, error) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		build, err := r.client.WorkspaceBuild(ctx, buildID)
+		if err != nil {
+			return codersdk.WorkspaceBuild{}, xerrors.Errorf("fetch build: %w", err)
+		}
+
+		job := build.Job
+		if job.CompletedAt != nil {
+			if job.Status != codersdk.ProvisionerJobSucceeded {
+				return codersdk.WorkspaceBuild{}, xerrors.Errorf("build %s of workspace %q failed with status %q: %s", build.ID, build.WorkspaceName, job.Status, job.Error)
+			}
+			return build, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return codersdk.WorkspaceBuild{}, xerrors.Errorf("wait for build %s: %w", buildID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// wait waits for the configured interval between template versions.
+func (r *Runner) wait(ctx context.Context) error {
+	if r.cfg.Interval <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(r.cfg.Interval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	queueMean, queueMax := loadtestutil.MeanMax(r.importQueueTimes)
+	importMean, importMax := loadtestutil.MeanMax(r.importDurations)
+	metrics := map[string]float64{
+		VersionsPushedMetric:     float64(len(r.importDurations)),
+		ImportQueueMeanMetric:    queueMean.Seconds(),
+		ImportQueueMaxMetric:     queueMax.Seconds(),
+		ImportDurationMeanMetric: importMean.Seconds(),
+		ImportDurationMaxMetric:  importMax.Seconds(),
+		WorkspaceUpdatesMetric:   float64(r.workspaceUpdates),
+	}
+	if len(r.fanoutDurations) > 0 {
+		fanoutMean, fanoutMax := loadtestutil.MeanMax(r.fanoutDurations)
+		metrics[UpdateFanoutMeanMetric] = fanoutMean.Seconds()
+		metrics[UpdateFanoutMaxMetric] = fanoutMax.Seconds()
+	}
+	return metrics
+}
+
+// Cleanup implements Cleanable by deleting the workspaces and then the
+// template.
+func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
+	for len(r.workspaces) > 0 {
+		workspaceID := r.workspaces[0]
+		err := workspacebuild.NewCleanupRunner(r.client, workspaceID).Run(ctx, id, logs)
+		if err != nil {
+			return xerrors.Errorf("delete workspace %s: %w", workspaceID, err)
+		}
+		r.workspaces = r.workspaces[1:]
+	}
+
+	if r.template.ID == uuid.Nil {
+		return nil
+	}
+	_, _ = fmt.Fprintf(logs, "Deleting template %q...\n", r.template.Name)
+	if err := r.client.DeleteTemplate(ctx, r.template.ID); err != nil {
+		return xerrors.Errorf("delete template: %w", err)
+	}
+	r.template = codersdk.Template{}
+	return nil
+}
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(_ context.Context, _ string) ([]harness.CleanupResource, error) {
+	resources := make([]harness.CleanupResource, 0, len(r.workspaces)+1)
+	for _, workspaceID := range r.workspaces {
+		resources = append(resources, harness.CleanupResource{
+			Kind: "workspace",
+			ID:   workspaceID.String(),
+		})
+	}
+	if r.template.ID != uuid.Nil {
+		resources = append(resources, harness.CleanupResource{
+			Kind: "template",
+			ID:   r.template.ID.String(),
+			Name: r.template.Name,
+		})
+	}
+	return resources, nil
+}
+
+func (r *Runner) provisionerType() codersdk.ProvisionerType {
+	if r.cfg.ProvisionerType == "" {
+		return codersdk.ProvisionerTypeTerraform
+	}
+	return r.cfg.ProvisionerType
+}
+
+// templateTarData returns a tar archive of the given template version for the
+// configured provisioner.
+func (r *Runner) templateTarData(version int) ([]byte, error) {
+	if r.provisionerType() != codersdk.ProvisionerTypeEcho {
+		return TemplateTarData(version)
+	}
+	mainTF, err := templateMainTF(version)
+	if err != nil {
+		return nil, err
+	}
+	// The echo provisioner replays canned responses instead of running the
+	// Terraform template, which is only included to keep versions distinct.
+	return echo.Tar(echo.WithExtraFiles(map[string][]byte{
+		"main.tf": mainTF,
+	}))
+}
+
+// TemplateTarData returns a tar archive of a minimal Terraform template. The
+// version is embedded in the template so that every version is distinct.
+func TemplateTarData(version int) ([]byte, error) {
+	mainTF, err := templateMainTF(version)
+	if err != nil {
+		return nil, err
+	}
+	return loadtestutil.CreateTarFromFiles(map[string][]byte{
+		"main.tf": mainTF,
+	})
+}
+
+func templateMainTF(version int) ([]byte, error) {
+	tmpl, err := template.New("template-churn").Parse(templateContent)
+	if err != nil {
+		return nil, err
+	}
+	result := bytes.Buffer{}
+	err = tmpl.Execute(&result, map[string]int{
+		"Version": version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+//go:embed tf/main.tf.tpl
+var templateContent string
@@ -0,0 +1,96 @@
+package templatechurn_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/templatechurn"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	runner := templatechurn.NewRunner(client, templatechurn.Config{
+		OrganizationID:            user.OrganizationID,
+		ProvisionerType:           codersdk.ProvisionerTypeEcho,
+		Workspaces:                2,
+		Versions:                  2,
+		TemplateVersionJobTimeout: testutil.WaitLong,
+		WorkspaceBuildTimeout:     testutil.WaitLong,
+	})
+	logs := bytes.NewBuffer(nil)
+	err := runner.Run(ctx, "1", logs)
+	require.NoError(t, err, logs.String())
+
+	metrics := runner.GetNumericMetrics()
+	require.EqualValues(t, 3, metrics[templatechurn.VersionsPushedMetric])
+	require.EqualValues(t, 4, metrics[templatechurn.WorkspaceUpdatesMetric])
+	require.Contains(t, metrics, templatechurn.UpdateFanoutMaxMetric)
+
+	// Every workspace was updated to the last version.
+	templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, templatechurn.TemplatePrefix+"1", templates[0].Name)
+	versions, err := client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+		TemplateID: templates[0].ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	require.Len(t, workspaces.Workspaces, 2)
+	for _, workspace := range workspaces.Workspaces {
+		require.Equal(t, templates[0].ActiveVersionID, workspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, workspace.LatestBuild.Status)
+	}
+
+	err = runner.Cleanup(ctx, "1", logs)
+	require.NoError(t, err, logs.String())
+	templates, err = client.Templates(ctx, codersdk.TemplateFilter{})
+	require.NoError(t, err)
+	require.Empty(t, templates)
+	workspaces, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	require.Empty(t, workspaces.Workspaces)
+}
+
+func TestTemplateTarData(t *testing.T) {
+	t.Parallel()
+
+	mainTF := func(t *testing.T, version int) string {
+		t.Helper()
+
+		data, err := templatechurn.TemplateTarData(version)
+		require.NoError(t, err)
+
+		tr := tar.NewReader(bytes.NewReader(data))
+		hdr, err := tr.Next()
+		require.NoError(t, err)
+		require.Equal(t, "main.tf", hdr.Name)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		_, err = tr.Next()
+		require.ErrorIs(t, err, io.EOF)
+		return string(content)
+	}
+
+	first := mainTF(t, 1)
+	second := mainTF(t, 2)
+	require.Contains(t, first, `version = "1"`)
+	require.Contains(t, second, `version = "2"`)
+	require.NotEqual(t, first, second)
+}
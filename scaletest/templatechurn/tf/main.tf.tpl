@@ -0,0 +1,14 @@
+terraform {
+  required_providers {
+    coder = {
+      source  = "coder/coder"
+      version = "2.5.3"
+    }
+  }
+}
+
+resource "null_resource" "workspace" {
+  triggers = {
+    version = "{{.Version}}"
+  }
+}
@@ -49,7 +49,7 @@ func (r *Runner) RunReturningWorkspace(ctx context.Context, id string, logs io.W
 
 	req := r.cfg.Request
 	if req.Name == "" {
-		randName, err := loadtestutil.GenerateWorkspaceName(id)
+		randName, err := loadtestutil.GenerateWorkspaceName(ctx, id)
 		if err != nil {
 			return SlimWorkspace{}, xerrors.Errorf("generate random name for workspace: %w", err)
 		}
@@ -179,6 +179,17 @@ func (r *Runner) Cleanup(ctx context.Context, id string, w io.Writer) error {
 	}).Run(ctx, id, w)
 }
 
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(_ context.Context, _ string) ([]harness.CleanupResource, error) {
+	if r.workspaceID == uuid.Nil {
+		return nil, nil
+	}
+	return []harness.CleanupResource{{
+		Kind: "workspace",
+		ID:   r.workspaceID.String(),
+	}}, nil
+}
+
 func waitForBuild(ctx context.Context, w io.Writer, client *codersdk.Client, buildID uuid.UUID) error {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
@@ -0,0 +1,56 @@
+package workspacechurn
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type Config struct {
+	// OrganizationID is the ID of the organization to create the workspaces
+	// in.
+	OrganizationID uuid.UUID `json:"organization_id"`
+	// UserID is the ID of the user to create the workspaces for.
+	UserID string `json:"user_id"`
+	// Request is the request to send to the Coder API to create each
+	// workspace. request.template_id must be set. A name will be generated
+	// for every workspace.
+	Request codersdk.CreateWorkspaceRequest `json:"request"`
+	// Cycles is the number of times a workspace is created, stopped, started
+	// and deleted.
+	Cycles int `json:"cycles"`
+	// Interval is the time to wait between consecutive builds.
+	Interval time.Duration `json:"interval"`
+}
+
+func (c Config) Validate() error {
+	if c.OrganizationID == uuid.Nil {
+		return xerrors.New("organization_id must be set")
+	}
+	if c.UserID == "" {
+		return xerrors.New("user_id must be set")
+	}
+	if c.UserID != codersdk.Me {
+		_, err := uuid.Parse(c.UserID)
+		if err != nil {
+			return xerrors.Errorf("user_id must be %q or a valid UUID: %w", codersdk.Me, err)
+		}
+	}
+	if c.Request.TemplateID == uuid.Nil {
+		return xerrors.New("request.template_id must be set")
+	}
+	if c.Request.Name != "" {
+		return xerrors.New("request.name must be empty, names are generated")
+	}
+	if c.Cycles <= 0 {
+		return xerrors.New("cycles must be greater than 0")
+	}
+	if c.Interval < 0 {
+		return xerrors.New("interval must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package workspacechurn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/workspacechurn"
+)
+
+func Test_Config(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	cases := []struct {
+		name        string
+		config      workspacechurn.Config
+		errContains string
+	}{
+		{
+			name: "OK",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         codersdk.Me,
+				Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+				Cycles:         3,
+				Interval:       time.Second,
+			},
+		},
+		{
+			name: "UserIDUUID",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         id.String(),
+				Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+				Cycles:         1,
+			},
+		},
+		{
+			name: "NoOrganizationID",
+			config: workspacechurn.Config{
+				UserID:  codersdk.Me,
+				Request: codersdk.CreateWorkspaceRequest{TemplateID: id},
+				Cycles:  1,
+			},
+			errContains: "organization_id must be set",
+		},
+		{
+			name: "InvalidUserID",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         "blah",
+				Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+				Cycles:         1,
+			},
+			errContains: "user_id must be",
+		},
+		{
+			name: "NoTemplateID",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         codersdk.Me,
+				Cycles:         1,
+			},
+			errContains: "request.template_id must be set",
+		},
+		{
+			name: "Name",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         codersdk.Me,
+				Request:        codersdk.CreateWorkspaceRequest{TemplateID: id, Name: "test"},
+				Cycles:         1,
+			},
+			errContains: "request.name must be empty",
+		},
+		{
+			name: "NoCycles",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         codersdk.Me,
+				Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+			},
+			errContains: "cycles must be greater than 0",
+		},
+		{
+			name: "NegativeInterval",
+			config: workspacechurn.Config{
+				OrganizationID: id,
+				UserID:         codersdk.Me,
+				Request:        codersdk.CreateWorkspaceRequest{TemplateID: id},
+				Cycles:         1,
+				Interval:       -time.Second,
+			},
+			errContains: "interval must not be negative",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.config.Validate()
+			if c.errContains != "" {
+				require.Error(t, err)
+				require.ErrorContains(t, err, c.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package workspacechurn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/v3"
+	"cdr.dev/slog/v3/sloggers/sloghuman"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/harness"
+	"github.com/coder/coder/v2/scaletest/loadtestutil"
+	"github.com/coder/coder/v2/scaletest/workspacebuild"
+)
+
+// Numeric metrics reported by the runner.
+const (
+	BuildsCompletedMetric   = "builds_completed"
+	BuildsPerMinuteMetric   = "builds_per_minute"
+	BuildQueueMeanMetric    = "build_queue_seconds_mean"
+	BuildQueueMaxMetric     = "build_queue_seconds_max"
+	BuildDurationMeanMetric = "build_duration_seconds_mean"
+	BuildDurationMaxMetric  = "build_duration_seconds_max"
+)
+
+const buildPollInterval = 500 * time.Millisecond
+
+// Runner repeatedly creates, stops, starts and deletes a workspace to stress
+// the provisioning path. For every build, the time the job spent queued
+// waiting for a provisioner and the time it took to run are recorded.
+type Runner struct {
+	client *codersdk.Client
+	cfg    Config
+
+	// workspaceID is the workspace of the current cycle, if it hasn't been
+	// deleted yet.
+	workspaceID uuid.UUID
+
+	started        time.Time
+	finished       time.Time
+	queueTimes     []time.Duration
+	buildDurations []time.Duration
+}
+
+var (
+	_ harness.Runnable           = &Runner{}
+	_ harness.Cleanable          = &Runner{}
+	_ harness.CleanupPlanner     = &Runner{}
+	_ harness.NumericCollectable = &Runner{}
+)
+
+func NewRunner(client *codersdk.Client, cfg Config) *Runner {
+	return &Runner{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Run implements Runnable.
+func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	logs = loadtestutil.NewSyncWriter(logs)
+	logger := slog.Make(sloghuman.Sink(logs)).Leveled(slog.LevelDebug)
+	r.client.SetLogger(logger)
+	r.client.SetLogBodies(true)
+
+	r.started = time.Now()
+	defer func() {
+		r.finished = time.Now()
+	}()
+
+	for cycle := 1; cycle <= r.cfg.Cycles; cycle++ {
+		_, _ = fmt.Fprintf(logs, "Starting cycle %d/%d...\n", cycle, r.cfg.Cycles)
+		err := r.runCycle(ctx, id, logs)
+		if err != nil {
+			return xerrors.Errorf("cycle %d: %w", cycle, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) runCycle(ctx context.Context, id string, logs io.Writer) error {
+	req := r.cfg.Request
+	name, err := loadtestutil.GenerateWorkspaceName(ctx, id)
+	if err != nil {
+		return xerrors.Errorf("generate random name for workspace: %w", err)
+	}
+	req.Name = name
+
+	workspace, err := r.client.CreateWorkspace(ctx, r.cfg.OrganizationID, r.cfg.UserID, req)
+	if err != nil {
+		return xerrors.Errorf("create workspace: %w", err)
+	}
+	r.workspaceID = workspace.ID
+	_, _ = fmt.Fprintf(logs, "Created workspace %q (%s)\n", workspace.Name, workspace.ID)
+	err = r.waitForBuild(ctx, logs, workspace.LatestBuild.ID)
+	if err != nil {
+		return xerrors.Errorf("wait for start build: %w", err)
+	}
+
+	for _, transition := range []codersdk.WorkspaceTransition{
+		codersdk.WorkspaceTransitionStop,
+		codersdk.WorkspaceTransitionStart,
+		codersdk.WorkspaceTransitionDelete,
+	} {
+		err = r.wait(ctx)
+		if err != nil {
+			return err
+		}
+
+		build, err := r.client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: transition,
+		})
+		if err != nil {
+			return xerrors.Errorf("create %s build: %w", transition, err)
+		}
+		err = r.waitForBuild(ctx, logs, build.ID)
+		if err != nil {
+			return xerrors.Errorf("wait for %s build: %w", transition, err)
+		}
+	}
+	r.workspaceID = uuid.Nil
+
+	return r.wait(ctx)
+}
+
+// wait waits for the configured interval between builds.
+func (r *Runner) wait(ctx context.Context) error {
+	if r.cfg.Interval <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(r.cfg.Interval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// waitForBuild polls the build until its job has completed and records how
+// long the job was queued and running for.
+func (r *Runner) waitForBuild(ctx context.Context, logs io.Writer, buildID uuid.UUID) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	ticker := time.NewTicker(buildPollInterval)
+	defer ticker.Stop()
+	for {
+		build, err := r.client.WorkspaceBuild(ctx, buildID)
+		if err != nil {
+			return xerrors.Errorf("fetch build: %w", err)
+		}
+
+		job := build.Job
+		if job.CompletedAt != nil {
+			var queued, running time.Duration
+			if job.StartedAt != nil {
+				queued = job.StartedAt.Sub(job.CreatedAt)
+				running = job.CompletedAt.Sub(*job.StartedAt)
+			}
+			_, _ = fmt.Fprintf(logs, "\t%s build %s: queued for %s, ran for %s\n", build.Transition, job.Status, queued, running)
+
+			switch job.Status {
+			case codersdk.ProvisionerJobSucceeded:
+				r.queueTimes = append(r.queueTimes, queued)
+				r.buildDurations = append(r.buildDurations, running)
+				return nil
+			case codersdk.ProvisionerJobCanceled:
+				return xerrors.New("build canceled")
+			default:
+				return xerrors.Errorf("build failed with status %q: %s", job.Status, job.Error)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetNumericMetrics implements NumericCollectable.
+func (r *Runner) GetNumericMetrics() map[string]float64 {
+	queueMean, queueMax := loadtestutil.MeanMax(r.queueTimes)
+	durationMean, durationMax := loadtestutil.MeanMax(r.buildDurations)
+	metrics := map[string]float64{
+		BuildsCompletedMetric:   float64(len(r.buildDurations)),
+		BuildQueueMeanMetric:    queueMean.Seconds(),
+		BuildQueueMaxMetric:     queueMax.Seconds(),
+		BuildDurationMeanMetric: durationMean.Seconds(),
+		BuildDurationMaxMetric:  durationMax.Seconds(),
+	}
+	if elapsed := r.finished.Sub(r.started); elapsed > 0 {
+		metrics[BuildsPerMinuteMetric] = float64(len(r.buildDurations)) / elapsed.Minutes()
+	}
+	return metrics
+}
+
+// Cleanup implements Cleanable by deleting the workspace of an unfinished
+// cycle.
+func (r *Runner) Cleanup(ctx context.Context, id string, logs io.Writer) error {
+	if r.workspaceID == uuid.Nil {
+		return nil
+	}
+
+	err := workspacebuild.NewCleanupRunner(r.client, r.workspaceID).Run(ctx, id, logs)
+	if err != nil {
+		return xerrors.Errorf("delete workspace: %w", err)
+	}
+	r.workspaceID = uuid.Nil
+	return nil
+}
+
+// CleanupPlan implements CleanupPlanner.
+func (r *Runner) CleanupPlan(_ context.Context, _ string) ([]harness.CleanupResource, error) {
+	if r.workspaceID == uuid.Nil {
+		return nil, nil
+	}
+	return []harness.CleanupResource{{
+		Kind: "workspace",
+		ID:   r.workspaceID.String(),
+	}}, nil
+}
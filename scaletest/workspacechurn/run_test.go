@@ -0,0 +1,80 @@
+package workspacechurn_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/scaletest/workspacechurn"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func Test_Runner(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		runner := workspacechurn.NewRunner(client, workspacechurn.Config{
+			OrganizationID: user.OrganizationID,
+			UserID:         codersdk.Me,
+			Request: codersdk.CreateWorkspaceRequest{
+				TemplateID: template.ID,
+			},
+			Cycles: 2,
+		})
+
+		logs := bytes.NewBuffer(nil)
+		err := runner.Run(ctx, "1", logs)
+		t.Log("Runner logs:\n\n" + logs.String())
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), "Starting cycle 2/2")
+
+		// Every cycle creates, stops, starts and deletes the workspace.
+		metrics := runner.GetNumericMetrics()
+		require.EqualValues(t, 8, metrics[workspacechurn.BuildsCompletedMetric])
+		require.Positive(t, metrics[workspacechurn.BuildsPerMinuteMetric])
+		require.GreaterOrEqual(t, metrics[workspacechurn.BuildDurationMaxMetric], metrics[workspacechurn.BuildDurationMeanMetric])
+
+		// The workspaces of finished cycles are deleted, so there is nothing
+		// left to clean up.
+		plan, err := runner.CleanupPlan(ctx, "1")
+		require.NoError(t, err)
+		require.Empty(t, plan)
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		runner := workspacechurn.NewRunner(client, workspacechurn.Config{
+			OrganizationID: user.OrganizationID,
+			UserID:         codersdk.Me,
+			Request: codersdk.CreateWorkspaceRequest{
+				TemplateID: uuid.New(),
+			},
+			Cycles: 1,
+		})
+
+		err := runner.Run(ctx, "1", io.Discard)
+		require.ErrorContains(t, err, "create workspace")
+		require.Zero(t, runner.GetNumericMetrics()[workspacechurn.BuildsCompletedMetric])
+	})
+}
@@ -45,6 +45,14 @@ type Config struct {
 
 	App AppConfig `json:"app"`
 
+	// Verify waits for the agent to be connected and ready, and for its apps
+	// to be healthy, before traffic is generated. The time it takes is
+	// recorded as the verify phase of the run.
+	Verify bool `json:"verify"`
+	// VerifyTimeout is how long to wait for the agent to be reachable.
+	// Defaults to 5 minutes.
+	VerifyTimeout time.Duration `json:"verify_timeout"`
+
 	WebClient *codersdk.Client
 }
 
@@ -65,6 +73,10 @@ func (c Config) Validate() error {
 		return xerrors.Errorf("validate tick_interval: must be greater than zero")
 	}
 
+	if c.VerifyTimeout < 0 {
+		return xerrors.Errorf("validate verify_timeout: must not be negative")
+	}
+
 	if c.SSH && c.App.Name != "" {
 		return xerrors.Errorf("validate ssh: must be false when app is used")
 	}
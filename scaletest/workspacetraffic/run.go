@@ -29,9 +29,11 @@ type Runner struct {
 }
 
 var (
-	_ harness.Runnable    = &Runner{}
-	_ harness.Cleanable   = &Runner{}
-	_ harness.Collectable = &Runner{}
+	_ harness.Runnable        = &Runner{}
+	_ harness.Cleanable       = &Runner{}
+	_ harness.Collectable     = &Runner{}
+	_ harness.ByteCollectable = &Runner{}
+	_ harness.Verifiable      = &Runner{}
 )
 
 // func NewRunner(client *codersdk.Client, cfg Config, metrics *Metrics) *Runner {
@@ -103,6 +105,8 @@ func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) (err error)
 	}
 	command := fmt.Sprintf("dd if=/dev/stdin of=%s bs=%d status=none", output, bytesPerTick)
 
+	endConnect := harness.StartPhase(ctx, "connect")
+	defer endConnect()
 	var conn *countReadWriteCloser
 	switch {
 	case r.cfg.App.Name != "":
@@ -132,6 +136,9 @@ func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) (err error)
 			return xerrors.Errorf("connect to workspace via reconnectingpty: %w", err)
 		}
 	}
+	endConnect()
+	defer harness.StartPhase(ctx, "traffic")()
+
 	var closeErr error
 	closeOnce := sync.Once{}
 	closeConn := func() error {
@@ -248,8 +255,8 @@ func (r *Runner) Run(ctx context.Context, _ string, logs io.Writer) (err error)
 }
 
 const (
-	BytesReadMetric    = "bytes_read"
-	BytesWrittenMetric = "bytes_written"
+	BytesReadMetric    = harness.BytesReadMetric
+	BytesWrittenMetric = harness.BytesWrittenMetric
 )
 
 func (r *Runner) GetMetrics() map[string]any {
@@ -259,6 +266,24 @@ func (r *Runner) GetMetrics() map[string]any {
 	}
 }
 
+// GetBytes implements ByteCollectable, reporting the traffic under the
+// channel it was sent over.
+func (r *Runner) GetBytes() map[string]harness.ByteCount {
+	channel := harness.ByteChannelPTY
+	switch {
+	case r.cfg.App.Name != "":
+		channel = harness.ByteChannelAppProxy
+	case r.cfg.SSH:
+		channel = harness.ByteChannelSSH
+	}
+	return map[string]harness.ByteCount{
+		channel: {
+			Read:    r.cfg.ReadMetrics.GetTotalBytes(),
+			Written: r.cfg.WriteMetrics.GetTotalBytes(),
+		},
+	}
+}
+
 // Cleanup does nothing, successfully.
 func (*Runner) Cleanup(context.Context, string, io.Writer) error {
 	return nil
@@ -0,0 +1,74 @@
+package workspacetraffic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultVerifyTimeout = 5 * time.Minute
+	// verifyInterval is the interval the agent is polled at while verifying
+	// it is reachable.
+	verifyInterval = 2 * time.Second
+)
+
+// Verify implements harness.Verifiable. If verification is enabled, it waits
+// for the agent to be connected and ready, and for its apps to be healthy.
+func (r *Runner) Verify(ctx context.Context, _ string, logs io.Writer) error {
+	if !r.cfg.Verify {
+		return nil
+	}
+
+	timeout := r.cfg.VerifyTimeout
+	if timeout == 0 {
+		timeout = defaultVerifyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(verifyInterval)
+	defer ticker.Stop()
+	for {
+		var reason string
+		agent, err := r.client.WorkspaceAgent(ctx, r.cfg.AgentID)
+		if err != nil {
+			reason = fmt.Sprintf("get agent: %v", err)
+		} else {
+			reason = unreachableReason(agent)
+		}
+		if reason == "" {
+			_, _ = fmt.Fprintf(logs, "Agent %s is reachable\n", r.cfg.AgentName)
+			return nil
+		}
+		_, _ = fmt.Fprintf(logs, "Waiting for agent %s to be reachable: %s\n", r.cfg.AgentName, reason)
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("agent %s is not reachable after %s: %s", r.cfg.AgentName, timeout, reason)
+		case <-ticker.C:
+		}
+	}
+}
+
+// unreachableReason returns why traffic can't be generated to the agent yet, or
+// an empty string if it is connected, ready and all its apps are healthy.
+func unreachableReason(agent codersdk.WorkspaceAgent) string {
+	if agent.Status != codersdk.WorkspaceAgentConnected {
+		return fmt.Sprintf("agent is %s", agent.Status)
+	}
+	if agent.LifecycleState != codersdk.WorkspaceAgentLifecycleReady {
+		return fmt.Sprintf("agent lifecycle state is %s", agent.LifecycleState)
+	}
+	for _, app := range agent.Apps {
+		if app.Health == codersdk.WorkspaceAppHealthInitializing || app.Health == codersdk.WorkspaceAppHealthUnhealthy {
+			return fmt.Sprintf("app %s is %s", app.Slug, app.Health)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package workspacetraffic
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUnreachableReason(t *testing.T) {
+	t.Parallel()
+
+	ready := codersdk.WorkspaceAgent{
+		Status:         codersdk.WorkspaceAgentConnected,
+		LifecycleState: codersdk.WorkspaceAgentLifecycleReady,
+		Apps: []codersdk.WorkspaceApp{
+			{Slug: "code-server", Health: codersdk.WorkspaceAppHealthHealthy},
+			{Slug: "docs", Health: codersdk.WorkspaceAppHealthDisabled},
+		},
+	}
+	require.Empty(t, unreachableReason(ready))
+
+	connecting := ready
+	connecting.Status = codersdk.WorkspaceAgentConnecting
+	require.Equal(t, "agent is connecting", unreachableReason(connecting))
+
+	starting := ready
+	starting.LifecycleState = codersdk.WorkspaceAgentLifecycleStarting
+	require.Equal(t, "agent lifecycle state is starting", unreachableReason(starting))
+
+	unhealthy := ready
+	unhealthy.Apps = []codersdk.WorkspaceApp{{Slug: "code-server", Health: codersdk.WorkspaceAppHealthUnhealthy}}
+	require.Equal(t, "app code-server is unhealthy", unreachableReason(unhealthy))
+}
+
+func TestRunner_Verify(t *testing.T) {
+	t.Parallel()
+
+	newRunner := func(t *testing.T, agent codersdk.WorkspaceAgent, cfg Config) *Runner {
+		t.Helper()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(agent)
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		return NewRunner(codersdk.New(u), cfg)
+	}
+
+	t.Run("Reachable", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRunner(t, codersdk.WorkspaceAgent{
+			Status:         codersdk.WorkspaceAgentConnected,
+			LifecycleState: codersdk.WorkspaceAgentLifecycleReady,
+		}, Config{AgentID: uuid.New(), AgentName: "main", Verify: true})
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.NoError(t, r.Verify(ctx, "0", io.Discard))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRunner(t, codersdk.WorkspaceAgent{
+			Status: codersdk.WorkspaceAgentDisconnected,
+		}, Config{AgentID: uuid.New(), AgentName: "main", Verify: true, VerifyTimeout: 100 * time.Millisecond})
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := r.Verify(ctx, "0", io.Discard)
+		require.ErrorContains(t, err, "agent main is not reachable after 100ms: agent is disconnected")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// The agent is never requested.
+		r := NewRunner(codersdk.New(&url.URL{Scheme: "http", Host: "127.0.0.1:1"}), Config{AgentID: uuid.New()})
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.NoError(t, r.Verify(ctx, "0", io.Discard))
+	})
+}
@@ -115,7 +115,7 @@ func (r *Runner) Run(ctx context.Context, id string, logs io.Writer) error {
 
 	r.workspacebuildRunners = make([]*workspacebuild.Runner, 0, r.cfg.WorkspaceCount)
 	for i := range r.cfg.WorkspaceCount {
-		workspaceName, err := loadtestutil.GenerateWorkspaceName(id)
+		workspaceName, err := loadtestutil.GenerateWorkspaceName(ctx, id)
 		if err != nil {
 			return xerrors.Errorf("generate random name for workspace: %w", err)
 		}
@@ -144,6 +144,16 @@ export const watchWorkspace = (
 	});
 };
 
+/**
+ * @returns {OneWayWebSocket} A OneWayWebSocket that emits Server-Sent Events.
+ */
+export const watchDeploymentStats =
+	(): OneWayWebSocket<TypesGen.ServerSentEvent> => {
+		return new OneWayWebSocket({
+			apiRoute: "/api/v2/deployment/stats/watch-ws",
+		});
+	};
+
 export const watchChat = (
 	chatId: string,
 	afterMessageId?: number,
@@ -456,7 +466,8 @@ export type InsightsParams = {
 };
 
 export type InsightsTemplateParams = InsightsParams & {
-	interval: "day" | "week";
+	interval: TypesGen.InsightsReportInterval;
+	timezone?: string;
 };
 
 export class MissingBuildParameters extends Error {
@@ -2730,7 +2741,12 @@ class ApiMethods {
 	getInsightsTemplate = async (
 		params: InsightsTemplateParams,
 	): Promise<TypesGen.TemplateInsightsResponse> => {
-		const searchParams = new URLSearchParams(params);
+		// Intervals are bucketed in the user's IANA timezone so that they
+		// follow daylight saving time changes.
+		const searchParams = new URLSearchParams({
+			timezone: Intl.DateTimeFormat().resolvedOptions().timeZone,
+			...params,
+		});
 		const response = await this.axios.get(
 			`/api/v2/insights/templates?${searchParams}`,
 		);
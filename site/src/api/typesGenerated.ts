@@ -940,6 +940,102 @@ export interface APIKeyWithOwner extends APIKey {
 	readonly username: string;
 }
 
+// From codersdk/deployment.go
+/**
+ * APIRateLimitConsumption reports how much of the API rate limit the users
+ * that sent requests recently have consumed. The counters are local to the
+ * replica serving the request.
+ */
+export interface APIRateLimitConsumption {
+	/**
+	 * Limit is the number of requests a user may send to an endpoint per
+	 * window. Zero means the API rate limit is disabled.
+	 */
+	readonly limit: number;
+	readonly window_seconds: number;
+	readonly users: readonly APIRateLimitUserConsumption[];
+}
+
+// From codersdk/deployment.go
+export interface APIRateLimitUserConsumption {
+	readonly user_id: string;
+	readonly username: string;
+	/**
+	 * WindowRequests is the number of requests the user sent to their
+	 * busiest endpoint in the current window.
+	 */
+	readonly window_requests: number;
+	/**
+	 * Consumption is WindowRequests as a fraction of the limit. Values above
+	 * 1 mean requests are being rejected.
+	 */
+	readonly consumption: number;
+	readonly total_requests: number;
+	readonly limited_requests: number;
+	readonly last_request_at: string;
+}
+
+// From codersdk/insights.go
+/**
+ * AccessMethodInsightsReport shows how session time is shared between the
+ * access methods of workspaces, per template and over time.
+ */
+export interface AccessMethodInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly interval: InsightsReportInterval;
+	readonly templates: readonly TemplateAccessMethodInsight[];
+}
+
+// From codersdk/insights.go
+export interface AccessMethodInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly interval: InsightsReportInterval;
+	/**
+	 * Timezone is the IANA timezone name to bucket intervals in, so that they
+	 * follow daylight saving time changes. Defaults to the UTC offset of the
+	 * start time.
+	 */
+	readonly timezone?: string;
+}
+
+// From codersdk/insights.go
+/**
+ * AccessMethodInsightsResponse is the response from the access method
+ * insights endpoint.
+ */
+export interface AccessMethodInsightsResponse {
+	readonly report: AccessMethodInsightsReport;
+}
+
+// From codersdk/insights.go
+/**
+ * AccessMethodIntervalReport shows the session time per access method of a
+ * template in an interval.
+ */
+export interface AccessMethodIntervalReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly access_methods: readonly AccessMethodUsage[];
+}
+
+// From codersdk/insights.go
+/**
+ * AccessMethodUsage is the session time of an access method. The slug and
+ * display name match the builtin apps of the template insights. The share is
+ * the fraction of the session time of all access methods, and is 0 if there
+ * was no session time.
+ */
+export interface AccessMethodUsage {
+	readonly slug: string;
+	readonly display_name: string;
+	readonly seconds: number;
+	readonly share: number;
+}
+
 // From healthsdk/healthsdk.go
 /**
  * AccessURLReport shows the results of performing a HTTP_GET to the /healthz endpoint through the configured access URL.
@@ -1062,6 +1158,25 @@ export const AgentSubsystems: AgentSubsystem[] = [
 	"exectrace",
 ];
 
+// From codersdk/deployment.go
+/**
+ * AnomalyDetectionConfig configures flagging workspaces whose traffic or
+ * sessions are far above their usual activity.
+ */
+export interface AnomalyDetectionConfig {
+	readonly sensitivity: string;
+}
+
+export const AnomalyDetectionSensitivities: AnomalyDetectionSensitivity[] = [
+	"high",
+	"low",
+	"medium",
+	"off",
+];
+
+// From codersdk/deployment.go
+export type AnomalyDetectionSensitivity = "high" | "low" | "medium" | "off";
+
 // From codersdk/chats.go
 /**
  * AnthropicInlineImageCapBytes is Anthropic's documented per-image
@@ -1188,6 +1303,36 @@ export interface AuditLog {
 	readonly user: User | null;
 }
 
+// From codersdk/deployment.go
+/**
+ * AuditLogExportConfig contains configuration for exporting audit logs to
+ * object storage.
+ */
+export interface AuditLogExportConfig {
+	/**
+	 * Bucket is the S3 bucket the audit logs are exported to. The export is
+	 * disabled when empty.
+	 */
+	readonly bucket: string;
+	/**
+	 * Prefix is prepended to the keys of the exported objects.
+	 */
+	readonly prefix: string;
+	/**
+	 * Region is the region of the bucket.
+	 */
+	readonly region: string;
+	/**
+	 * Endpoint is the endpoint of an S3 compatible service, e.g. Google
+	 * Cloud Storage.
+	 */
+	readonly endpoint: string;
+	/**
+	 * IncludeStats also exports the template usage stats.
+	 */
+	readonly include_stats: boolean;
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
 	readonly audit_logs: readonly AuditLog[];
@@ -3098,6 +3243,60 @@ export interface ChatWorkspaceTTLResponse {
 	readonly workspace_ttl_ms: number;
 }
 
+// From codersdk/deployment.go
+/**
+ * ClientGeographyConfig configures the request headers the coarse network
+ * location of clients is derived from. Both are empty by default, in which case
+ * it is not recorded.
+ */
+export interface ClientGeographyConfig {
+	readonly region_header: string;
+	readonly asn_header: string;
+}
+
+// From codersdk/insights.go
+/**
+ * ClientRegionLatencyInsight shows the connection latency of the workspace
+ * agents that clients in a region and network connected to. The region is
+ * empty and the ASN is 0 if they are unknown.
+ */
+export interface ClientRegionLatencyInsight {
+	readonly region: string;
+	readonly asn: number;
+	readonly users: number;
+	readonly agents: number;
+	readonly latency_ms: ConnectionLatencyQuantiles;
+}
+
+// From codersdk/insights.go
+/**
+ * ClientRegionLatencyInsightsReport shows the connection latency reported by
+ * workspace agents, grouped by the region and network of the clients
+ * connecting to them. Client networks are only recorded when the deployment is
+ * configured with the request headers to derive them from. Like the region
+ * latency report, it only covers the period raw agent stats are retained for.
+ */
+export interface ClientRegionLatencyInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly regions: readonly ClientRegionLatencyInsight[];
+}
+
+// From codersdk/insights.go
+export interface ClientRegionLatencyInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+}
+
+// From codersdk/insights.go
+/**
+ * ClientRegionLatencyInsightsResponse is the response from the client region
+ * latency insights endpoint.
+ */
+export interface ClientRegionLatencyInsightsResponse {
+	readonly report: ClientRegionLatencyInsightsReport;
+}
+
 // From codersdk/client.go
 /**
  * CoderDesktopTelemetryHeader contains a JSON-encoded representation of Desktop telemetry
@@ -3126,6 +3325,17 @@ export interface ConnectionLatency {
 	readonly p95: number;
 }
 
+// From codersdk/insights.go
+/**
+ * ConnectionLatencyQuantiles shows the distribution of connection latencies.
+ */
+export interface ConnectionLatencyQuantiles {
+	readonly p50: number;
+	readonly p90: number;
+	readonly p95: number;
+	readonly p99: number;
+}
+
 // From codersdk/connectionlog.go
 export interface ConnectionLog {
 	readonly id: string;
@@ -3246,6 +3456,57 @@ export interface ConvertLoginRequest {
 	readonly password: string;
 }
 
+// From codersdk/insights.go
+/**
+ * CostInsight is the estimated cost of a workspace, user or organization,
+ * depending on how the report is grouped. The name is empty if the workspace,
+ * user or organization no longer exists.
+ */
+export interface CostInsight {
+	readonly id: string;
+	readonly name: string;
+	readonly running_seconds: number;
+	readonly cost: number;
+}
+
+// From codersdk/insights.go
+export type CostInsightsGroupBy = "organization" | "user" | "workspace";
+
+export const CostInsightsGroupBys: CostInsightsGroupBy[] = [
+	"organization",
+	"user",
+	"workspace",
+];
+
+// From codersdk/insights.go
+/**
+ * CostInsightsReport shows the estimated cost of running workspaces. Costs
+ * are in the same unit as the daily cost of template resources, and accrue
+ * for every minute a workspace reported agent stats.
+ */
+export interface CostInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly group_by: CostInsightsGroupBy;
+	readonly total_cost: number;
+	readonly entries: readonly CostInsight[];
+}
+
+// From codersdk/insights.go
+export interface CostInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly group_by: CostInsightsGroupBy;
+}
+
+// From codersdk/insights.go
+/**
+ * CostInsightsResponse is the response from the cost insights endpoint.
+ */
+export interface CostInsightsResponse {
+	readonly report: CostInsightsReport;
+}
+
 // From codersdk/aigatewaykeys.go
 /**
  * CreateAIGatewayKeyRequest requests a new AI Gateway key.
@@ -3722,6 +3983,13 @@ export interface CreateUserSkillRequest {
 	readonly content: string;
 }
 
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+	readonly url: string;
+	readonly template_id?: string;
+	readonly events: readonly WebhookEvent[];
+}
+
 // From codersdk/workspaces.go
 export type CreateWorkspaceBuildReason =
 	| "cli"
@@ -3986,6 +4254,29 @@ export interface DangerousConfig {
 // From codersdk/database.go
 export const DatabaseNotReachable = "database not reachable";
 
+// From codersdk/deployment.go
+/**
+ * DatabasePurgeStatus is the outcome of the last run of the background job
+ * that purges data past its retention period.
+ */
+export interface DatabasePurgeStatus {
+	readonly started_at: string;
+	readonly finished_at: string;
+	readonly success: boolean;
+	readonly error?: string;
+	/**
+	 * RecordsPurged is the number of records deleted by the run, keyed by
+	 * record type.
+	 */
+	readonly records_purged: Record<string, number>;
+	/**
+	 * WorkspaceAgentStatsBacklogMillis is how far the oldest raw workspace
+	 * agent stat is past its retention period. Stats are purged in batches,
+	 * so this shrinks to zero over multiple runs once the purge catches up.
+	 */
+	readonly workspace_agent_stats_backlog_ms: number;
+}
+
 // From healthsdk/healthsdk.go
 /**
  * DatabaseReport shows the results of pinging the configured database.Conn.
@@ -4062,6 +4353,28 @@ export interface DeleteWorkspaceAgentPortShareRequest {
 	readonly port: number;
 }
 
+// From codersdk/insights.go
+/**
+ * DeploymentBandwidthInsights shows the bytes sent and received by workspace
+ * agents. Raw agent stats are only retained until they are rolled up, so the
+ * bandwidth only covers the period since StartTime, which may be later than
+ * the start of the report.
+ */
+export interface DeploymentBandwidthInsights {
+	readonly start_time: string;
+	readonly rx_bytes: number;
+	readonly tx_bytes: number;
+}
+
+// From codersdk/insights.go
+/**
+ * DeploymentBuildInsights shows the number of completed workspace builds.
+ */
+export interface DeploymentBuildInsights {
+	readonly total: number;
+	readonly failed: number;
+}
+
 // From codersdk/deployment.go
 /**
  * DeploymentConfig contains both the deployment values and how they're set.
@@ -4071,6 +4384,35 @@ export interface DeploymentConfig {
 	readonly options?: SerpentOptionSet;
 }
 
+// From codersdk/insights.go
+/**
+ * DeploymentInsightsReport is the report from the deployment insights
+ * endpoint, aggregated across all templates and organizations.
+ */
+export interface DeploymentInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly active_users: number;
+	readonly builds: DeploymentBuildInsights;
+	readonly bandwidth: DeploymentBandwidthInsights;
+	readonly latency_ms: ConnectionLatency;
+}
+
+// From codersdk/insights.go
+export interface DeploymentInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+}
+
+// From codersdk/insights.go
+/**
+ * DeploymentInsightsResponse is the response from the deployment insights
+ * endpoint.
+ */
+export interface DeploymentInsightsResponse {
+	readonly report: DeploymentInsightsReport;
+}
+
 // From codersdk/deployment.go
 export interface DeploymentStats {
 	/**
@@ -4153,13 +4495,16 @@ export interface DeploymentValues {
 	readonly disable_owner_workspace_exec?: boolean;
 	readonly disable_workspace_sharing?: boolean;
 	readonly disable_chat_sharing?: boolean;
+	readonly license_seat_usage_window?: number;
 	readonly proxy_health_status_interval?: number;
 	readonly enable_terraform_debug_mode?: boolean;
 	readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig;
 	readonly web_terminal_renderer?: string;
 	readonly allow_workspace_renames?: boolean;
+	readonly dormancy_activity_signal?: string;
 	readonly healthcheck?: HealthcheckConfig;
 	readonly retention?: RetentionConfig;
+	readonly audit_log_export?: AuditLogExportConfig;
 	readonly cli_upgrade_message?: string;
 	readonly terms_of_service_url?: string;
 	readonly notifications?: NotificationsConfig;
@@ -4245,6 +4590,15 @@ export const DisplayApps: DisplayApp[] = [
 	"web_terminal",
 ];
 
+// From codersdk/templates.go
+export type DormancyActivitySignal = "connection" | "session" | "traffic";
+
+export const DormancyActivitySignals: DormancyActivitySignal[] = [
+	"connection",
+	"session",
+	"traffic",
+];
+
 // From codersdk/parameters.go
 export interface DynamicParametersRequest {
 	/**
@@ -4818,6 +5172,20 @@ export interface GroupSyncSettings {
 	readonly legacy_group_name_mapping?: Record<string, string>;
 }
 
+// From codersdk/usagequotas.go
+/**
+ * GroupUsageQuota is the monthly allowance granted to each member of a group
+ * for one metric.
+ */
+export interface GroupUsageQuota {
+	readonly group_id: string;
+	readonly metric: UsageQuotaMetric;
+	readonly monthly_hours: number;
+	readonly action: UsageQuotaAction;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
 // From codersdk/deployment.go
 export interface HTTPCookieConfig {
 	readonly secure_auth_cookie?: boolean;
@@ -4982,6 +5350,31 @@ export interface IDPSyncMapping<ResourceIdType extends string> {
 	readonly Gets: ResourceIdType;
 }
 
+// From codersdk/idleworkspaces.go
+/**
+ * IdleWorkspace is a running workspace that the idle workspace reaper acts on
+ * during its next run.
+ */
+export interface IdleWorkspace {
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_name: string;
+	readonly template_id: string;
+	readonly last_active_at: string;
+	readonly action: IdleWorkspaceAction;
+	/**
+	 * FlaggedAt is set once the reaper has flagged the workspace since it was
+	 * last active.
+	 */
+	readonly flagged_at?: string;
+}
+
+// From codersdk/idleworkspaces.go
+export type IdleWorkspaceAction = "flag" | "stop";
+
+export const IdleWorkspaceActions: IdleWorkspaceAction[] = ["flag", "stop"];
+
 // From codersdk/inboxnotification.go
 export interface InboxNotification {
 	readonly id: string;
@@ -5015,10 +5408,11 @@ export const InboxNotificationFallbackIconTemplate = "DEFAULT_ICON_TEMPLATE";
 export const InboxNotificationFallbackIconWorkspace = "DEFAULT_ICON_WORKSPACE";
 
 // From codersdk/insights.go
-export type InsightsReportInterval = "day" | "week";
+export type InsightsReportInterval = "day" | "month" | "week";
 
 export const InsightsReportIntervals: InsightsReportInterval[] = [
 	"day",
+	"month",
 	"week",
 ];
 
@@ -5086,6 +5480,35 @@ export const LicenseExpiryClaim = "license_expires";
 export const LicenseManagedAgentLimitExceededWarningText =
 	"You have built more workspaces with managed agents than your license allows.";
 
+// From codersdk/licenses.go
+/**
+ * LicenseSeatUsage describes how the user limit of the license is consumed.
+ */
+export interface LicenseSeatUsage {
+	/**
+	 * UsageWindowMillis is the window of workspace usage used to count seats.
+	 * Zero means every active user consumes a seat.
+	 */
+	readonly usage_window_ms: number;
+	/**
+	 * ActiveUsers is the number of active users in the deployment.
+	 */
+	readonly active_users: number;
+	/**
+	 * UsageUsers is the number of active users that used a workspace within
+	 * the usage window. It is only set when the usage window is non-zero.
+	 */
+	readonly usage_users?: number;
+	/**
+	 * Seats is the number of seats counted against the user limit.
+	 */
+	readonly seats: number;
+	/**
+	 * Limit is the user limit of the license, if there is one.
+	 */
+	readonly limit?: number;
+}
+
 // From codersdk/licenses.go
 export const LicenseTelemetryRequiredErrorText =
 	"License requires telemetry but telemetry is disabled";
@@ -5539,6 +5962,14 @@ export interface NotificationsConfig {
 	 * How often to query the database for queued notifications.
 	 */
 	readonly fetch_interval: number;
+	/**
+	 * How long a workspace must go unused before its owner is notified. A value of 0 disables the notification.
+	 */
+	readonly unused_workspace_after: number;
+	/**
+	 * Whether template admins are notified about unused workspaces in their organizations as well.
+	 */
+	readonly unused_workspace_notify_admins: boolean;
 	/**
 	 * Which delivery method to use (available options: 'smtp', 'webhook').
 	 */
@@ -6110,16 +6541,48 @@ export interface Organization extends MinimalOrganization {
 	readonly default_org_member_roles: readonly string[];
 }
 
-// From codersdk/organizations.go
-export interface OrganizationMember {
-	readonly user_id: string;
+// From codersdk/insights.go
+/**
+ * OrganizationInsightsReport is the report from the organization insights
+ * endpoint, aggregated across the templates and workspaces of an
+ * organization.
+ */
+export interface OrganizationInsightsReport {
 	readonly organization_id: string;
-	readonly created_at: string;
-	readonly updated_at: string;
-	readonly roles: readonly SlimRole[];
-}
-
-// From codersdk/organizations.go
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly active_users: number;
+	readonly workspaces: OrganizationWorkspaceInsights;
+	readonly builds: DeploymentBuildInsights;
+	readonly bandwidth: DeploymentBandwidthInsights;
+	readonly latency_ms: ConnectionLatency;
+}
+
+// From codersdk/insights.go
+export interface OrganizationInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+}
+
+// From codersdk/insights.go
+/**
+ * OrganizationInsightsResponse is the response from the organization insights
+ * endpoint.
+ */
+export interface OrganizationInsightsResponse {
+	readonly report: OrganizationInsightsReport;
+}
+
+// From codersdk/organizations.go
+export interface OrganizationMember {
+	readonly user_id: string;
+	readonly organization_id: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly roles: readonly SlimRole[];
+}
+
+// From codersdk/organizations.go
 export interface OrganizationMemberWithUserData extends OrganizationMember {
 	readonly username: string;
 	readonly name?: string;
@@ -6184,6 +6647,17 @@ export interface OrganizationSyncSettings {
 	readonly organization_assign_default: boolean;
 }
 
+// From codersdk/insights.go
+/**
+ * OrganizationWorkspaceInsights shows the current number of workspaces in the
+ * organization. Unlike the rest of the report, it is not limited to the
+ * period of the report.
+ */
+export interface OrganizationWorkspaceInsights {
+	readonly total: number;
+	readonly running: number;
+}
+
 // From codersdk/organizations.go
 export interface PaginatedMembersRequest {
 	readonly limit?: number;
@@ -6382,6 +6856,60 @@ export interface PprofConfig {
 	readonly address: string;
 }
 
+// From codersdk/insights.go
+/**
+ * PrebuildInsightsReport shows how well the prebuilt workspace pools serve
+ * workspace creations, so that pool sizes can be tuned. The hit rate is the
+ * share of workspaces created from a preset with prebuilds that were claimed
+ * from the pool, and is 0 if no such workspace was created.
+ */
+export interface PrebuildInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	readonly claims: number;
+	readonly misses: number;
+	readonly hit_rate: number;
+	readonly presets: readonly PrebuildPresetInsight[];
+}
+
+// From codersdk/insights.go
+export interface PrebuildInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * PrebuildInsightsResponse is the response from the prebuild insights
+ * endpoint.
+ */
+export interface PrebuildInsightsResponse {
+	readonly report: PrebuildInsightsReport;
+}
+
+// From codersdk/insights.go
+/**
+ * PrebuildPresetInsight shows the utilization of the prebuilt workspace pool
+ * of a template preset. The claim latency is how long users waited for a
+ * claimed prebuild to be ready, and the idle time how long a prebuild waited
+ * in the pool before being claimed. Both are -1 if there were no claims.
+ */
+export interface PrebuildPresetInsight {
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly preset_name: string;
+	readonly prebuilds_created: number;
+	readonly claims: number;
+	readonly misses: number;
+	readonly hit_rate: number;
+	readonly claim_latency_seconds_50: number;
+	readonly claim_latency_seconds_95: number;
+	readonly idle_seconds_50: number;
+	readonly idle_seconds_95: number;
+}
+
 // From codersdk/deployment.go
 export interface PrebuildsConfig {
 	/**
@@ -6501,6 +7029,50 @@ export interface PrometheusConfig {
 	readonly collect_agent_stats: boolean;
 	readonly collect_db_metrics: boolean;
 	readonly aggregate_agent_stats_by: string;
+	readonly remote_write: PrometheusRemoteWriteConfig;
+}
+
+// From codersdk/deployment.go
+export type PrometheusRelabelAction =
+	| "drop"
+	| "keep"
+	| "labeldrop"
+	| "labelkeep"
+	| "replace";
+
+export const PrometheusRelabelActions: PrometheusRelabelAction[] = [
+	"drop",
+	"keep",
+	"labeldrop",
+	"labelkeep",
+	"replace",
+];
+
+// From codersdk/deployment.go
+/**
+ * PrometheusRelabelConfig is the subset of a Prometheus relabel_config that is
+ * applied to series before they are pushed to a remote_write endpoint. Empty
+ * fields use the same defaults as Prometheus.
+ */
+export interface PrometheusRelabelConfig {
+	readonly source_labels?: readonly string[];
+	readonly separator?: string;
+	readonly regex?: string;
+	readonly target_label?: string;
+	readonly replacement?: string;
+	readonly action?: PrometheusRelabelAction;
+}
+
+// From codersdk/deployment.go
+/**
+ * PrometheusRemoteWriteConfig configures pushing workspace agent stats to a
+ * Prometheus remote_write endpoint.
+ */
+export interface PrometheusRemoteWriteConfig {
+	readonly url: string;
+	readonly bearer_token: string;
+	readonly metrics: string;
+	readonly relabel_configs: SerpentStruct<PrometheusRelabelConfig[]>;
 }
 
 // From codersdk/chats.go
@@ -6994,6 +7566,47 @@ export interface Region {
 	readonly wildcard_hostname: string;
 }
 
+// From codersdk/insights.go
+/**
+ * RegionLatencyInsight shows the connection latency of the workspace agents
+ * that prefer a DERP region. Agents whose preferred region is unknown, for
+ * example because they have since disconnected, are reported under region 0.
+ */
+export interface RegionLatencyInsight {
+	readonly region_id: number;
+	readonly region_name: string;
+	readonly agents: number;
+	readonly latency_ms: ConnectionLatencyQuantiles;
+}
+
+// From codersdk/insights.go
+/**
+ * RegionLatencyInsightsReport shows the connection latency reported by
+ * workspace agents, grouped by the DERP region they prefer to relay through.
+ * It is computed from raw agent stats, so it only covers the period those
+ * stats are retained for.
+ */
+export interface RegionLatencyInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly regions: readonly RegionLatencyInsight[];
+}
+
+// From codersdk/insights.go
+export interface RegionLatencyInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+}
+
+// From codersdk/insights.go
+/**
+ * RegionLatencyInsightsResponse is the response from the region latency
+ * insights endpoint.
+ */
+export interface RegionLatencyInsightsResponse {
+	readonly report: RegionLatencyInsightsReport;
+}
+
 // From codersdk/workspaceproxy.go
 export type RegionTypes = Region | WorkspaceProxy;
 
@@ -7079,6 +7692,7 @@ export type ResourceType =
 	| "user_ai_budget_override"
 	| "user_secret"
 	| "user_skill"
+	| "webhook"
 	| "workspace"
 	| "workspace_agent"
 	| "workspace_app"
@@ -7116,6 +7730,7 @@ export const ResourceTypes: ResourceType[] = [
 	"user_ai_budget_override",
 	"user_secret",
 	"user_skill",
+	"webhook",
 	"workspace",
 	"workspace_agent",
 	"workspace_app",
@@ -7199,6 +7814,12 @@ export interface RetentionConfig {
 	 * organization's regulatory requirements.
 	 */
 	readonly boundary_logs: number;
+	/**
+	 * WorkspaceAgentStats controls how long raw workspace agent stats are
+	 * retained. Stats are only deleted once they have been rolled up into
+	 * template usage stats. Defaults to 1 day to preserve existing behavior.
+	 */
+	readonly workspace_agent_stats: number;
 }
 
 // From codersdk/roles.go
@@ -7354,6 +7975,40 @@ export interface STUNReport {
 	readonly Error: string | null;
 }
 
+// From codersdk/scaletest.go
+/**
+ * ScaletestResult is the summary of a scaletest run against the deployment.
+ */
+export interface ScaletestResult {
+	readonly id: string;
+	readonly created_at: string;
+	/**
+	 * CreatedBy is the user that uploaded the result.
+	 */
+	readonly created_by: string;
+	/**
+	 * Name is the name of the test, e.g. the scaletest command that was run.
+	 */
+	readonly name: string;
+	readonly total_runs: number;
+	readonly total_pass: number;
+	readonly total_fail: number;
+	readonly elapsed_ms: number;
+	/**
+	 * Summary is the result of the scaletest harness without the results of
+	 * individual runs. Its format is defined by the scaletest harness.
+	 */
+	readonly summary: Record<string, string>;
+}
+
+// From codersdk/scaletest.go
+export interface ScaletestResultsRequest extends Pagination {
+	/**
+	 * Name filters the results by the name of the test.
+	 */
+	readonly name?: string;
+}
+
 // From serpent/serpent.go
 /**
  * Annotations is an arbitrary key-mapping used to extend the Option and Command types.
@@ -7614,6 +8269,8 @@ export interface SlimRole {
 // From codersdk/deployment.go
 export interface StatsCollectionConfig {
 	readonly usage_stats: UsageStatsConfig;
+	readonly client_geography: ClientGeographyConfig;
+	readonly anomaly_detection: AnomalyDetectionConfig;
 }
 
 // From codersdk/chats.go
@@ -8044,6 +8701,12 @@ export interface Template {
 	 * provisioning.
 	 */
 	readonly disable_module_cache: boolean;
+	/**
+	 * DormancyActivitySignal is the agent activity that keeps workspaces of
+	 * the template from going dormant. Empty if the deployment default is
+	 * used.
+	 */
+	readonly dormancy_activity_signal: DormancyActivitySignal;
 }
 
 // From codersdk/templates.go
@@ -8052,6 +8715,18 @@ export interface TemplateACL {
 	readonly group: readonly TemplateGroup[];
 }
 
+// From codersdk/insights.go
+/**
+ * TemplateAccessMethodInsight shows the session time per access method of a
+ * template, over the whole report and per interval.
+ */
+export interface TemplateAccessMethodInsight {
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly access_methods: readonly AccessMethodUsage[];
+	readonly intervals: readonly AccessMethodIntervalReport[];
+}
+
 // From codersdk/insights.go
 /**
  * TemplateAppUsage shows the usage of an app for one or more templates.
@@ -8102,6 +8777,17 @@ export interface TemplateAutostopRequirement {
 	readonly weeks: number;
 }
 
+// From codersdk/insights.go
+/**
+ * TemplateBandwidth shows the bytes received and sent by the workspace agents
+ * of a user in workspaces based on a template.
+ */
+export interface TemplateBandwidth {
+	readonly template_id: string;
+	readonly rx_bytes: number;
+	readonly tx_bytes: number;
+}
+
 // From codersdk/templates.go
 export type TemplateBuildTimeStats = Record<
 	WorkspaceTransition,
@@ -8258,6 +8944,60 @@ export const TemplateBuiltinAppDisplayNameVSCode = "Visual Studio Code";
  */
 export const TemplateBuiltinAppDisplayNameWebTerminal = "Web Terminal";
 
+// From codersdk/insights.go
+/**
+ * TemplateComparison shows the build, usage and latency metrics of a
+ * template. Metrics that can't be computed, for example the build success
+ * rate of a template without builds, are -1.
+ */
+export interface TemplateComparison {
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly template_display_name: string;
+	readonly organization_id: string;
+	readonly total_builds: number;
+	readonly failed_builds: number;
+	/**
+	 * BuildSuccessRate is the share of completed builds that didn't fail,
+	 * between 0 and 1. Canceled builds are not counted as failures.
+	 */
+	readonly build_success_rate: number;
+	/**
+	 * MedianBuildSeconds is the median duration of successful workspace
+	 * starts.
+	 */
+	readonly median_build_seconds: number;
+	readonly active_users: number;
+	readonly workspace_connection_latency_ms: ConnectionLatency;
+}
+
+// From codersdk/insights.go
+/**
+ * TemplateComparisonInsightsReport shows the health of a set of templates side
+ * by side, in the order they were requested.
+ */
+export interface TemplateComparisonInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly templates: readonly TemplateComparison[];
+}
+
+// From codersdk/insights.go
+export interface TemplateComparisonInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * TemplateComparisonInsightsResponse is the response from the template
+ * comparison insights endpoint.
+ */
+export interface TemplateComparisonInsightsResponse {
+	readonly report: TemplateComparisonInsightsReport;
+}
+
 // From codersdk/templates.go
 export interface TemplateExample {
 	readonly id: string;
@@ -8279,6 +9019,32 @@ export interface TemplateGroup extends Group {
 	readonly role: TemplateRole;
 }
 
+// From codersdk/idleworkspaces.go
+/**
+ * TemplateIdlePolicy configures the idle workspace reaper for the workspaces
+ * of a template. A workspace is idle once none of its agents have reported a
+ * session for the idle timeout.
+ */
+export interface TemplateIdlePolicy {
+	readonly template_id: string;
+	readonly idle_timeout_ms: number;
+	readonly action: IdleWorkspaceAction;
+	readonly created_at: string;
+	readonly updated_at: string;
+}
+
+// From codersdk/insights.go
+export type TemplateInsightsField =
+	| "active_users"
+	| "apps_usage"
+	| "parameters_usage";
+
+export const TemplateInsightsFields: TemplateInsightsField[] = [
+	"active_users",
+	"apps_usage",
+	"parameters_usage",
+];
+
 // From codersdk/insights.go
 /**
  * TemplateInsightsIntervalReport is the report from the template insights
@@ -8295,14 +9061,16 @@ export interface TemplateInsightsIntervalReport {
 // From codersdk/insights.go
 /**
  * TemplateInsightsReport is the report from the template insights endpoint.
+ *
+ * The fields of the report that were not requested are omitted.
  */
 export interface TemplateInsightsReport {
 	readonly start_time: string;
 	readonly end_time: string;
 	readonly template_ids: readonly string[];
-	readonly active_users: number;
-	readonly apps_usage: readonly TemplateAppUsage[];
-	readonly parameters_usage: readonly TemplateParameterUsage[];
+	readonly active_users?: number;
+	readonly apps_usage?: readonly TemplateAppUsage[];
+	readonly parameters_usage?: readonly TemplateParameterUsage[];
 }
 
 // From codersdk/insights.go
@@ -8312,6 +9080,17 @@ export interface TemplateInsightsRequest {
 	readonly template_ids: readonly string[];
 	readonly interval: InsightsReportInterval;
 	readonly sections: readonly TemplateInsightsSection[];
+	/**
+	 * Fields of the report to include, all of them when empty. Fields that
+	 * are not included are omitted from the report.
+	 */
+	readonly fields: readonly TemplateInsightsField[];
+	/**
+	 * Timezone is the IANA timezone name to bucket intervals in, so that they
+	 * follow daylight saving time changes. Defaults to the UTC offset of the
+	 * start time.
+	 */
+	readonly timezone?: string;
 }
 
 // From codersdk/insights.go
@@ -9006,6 +9785,13 @@ export interface UpdateTemplateMeta {
 	 * provisioning. It is recommended not to disable this.
 	 */
 	readonly disable_module_cache?: boolean;
+	/**
+	 * DormancyActivitySignal overrides the deployment wide agent activity
+	 * that keeps workspaces of the template from going dormant. Pass an
+	 * empty string to use the deployment default. This is an enterprise
+	 * feature, like TimeTilDormantMillis.
+	 */
+	readonly dormancy_activity_signal?: DormancyActivitySignal;
 }
 
 // From codersdk/users.go
@@ -9244,6 +10030,16 @@ export interface UploadResponse {
 	readonly hash: string;
 }
 
+// From codersdk/scaletest.go
+export interface UploadScaletestResultRequest {
+	readonly name: string;
+	readonly total_runs: number;
+	readonly total_pass: number;
+	readonly total_fail: number;
+	readonly elapsed_ms: number;
+	readonly summary: Record<string, string>;
+}
+
 // From codersdk/chats.go
 /**
  * UpsertChatUsageLimitGroupOverrideRequest is the request to create or update
@@ -9267,6 +10063,24 @@ export interface UpsertGroupAIBudgetRequest {
 	readonly spend_limit_micros: number;
 }
 
+// From codersdk/usagequotas.go
+export interface UpsertGroupUsageQuotaRequest {
+	readonly monthly_hours: number;
+	/**
+	 * Action defaults to "report" when empty.
+	 */
+	readonly action?: UsageQuotaAction;
+}
+
+// From codersdk/idleworkspaces.go
+export interface UpsertTemplateIdlePolicyRequest {
+	readonly idle_timeout_ms: number;
+	/**
+	 * Action defaults to "flag" when empty.
+	 */
+	readonly action?: IdleWorkspaceAction;
+}
+
 // From codersdk/aibridge.go
 export interface UpsertUserAIBudgetOverrideRequest {
 	/**
@@ -9295,6 +10109,54 @@ export const UsageAppNames: UsageAppName[] = [
 	"vscode",
 ];
 
+// From codersdk/insights.go
+/**
+ * UsageHeatmapInsightsReport shows the number of active users in each hour of
+ * the week, in the time zone of the start time. The heatmaps are indexed by
+ * day of the week, starting on Sunday, and then by hour of the day.
+ */
+export interface UsageHeatmapInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+	/**
+	 * AvgActiveUsers is the average number of active users in each hour of
+	 * the week across the time range.
+	 */
+	readonly avg_active_users: readonly number[][];
+	/**
+	 * MaxActiveUsers is the highest number of active users seen in each
+	 * hour of the week.
+	 */
+	readonly max_active_users: readonly number[][];
+}
+
+// From codersdk/insights.go
+export interface UsageHeatmapInsightsRequest {
+	readonly start_time: string;
+	readonly end_time: string;
+	/**
+	 * TemplateIDs limits the heatmap to the given templates, the heatmap is
+	 * deployment-wide when empty.
+	 */
+	readonly template_ids: readonly string[];
+	/**
+	 * Timezone is the IANA timezone name to bucket hours in, so that they
+	 * follow daylight saving time changes. Defaults to the UTC offset of the
+	 * start time.
+	 */
+	readonly timezone?: string;
+}
+
+// From codersdk/insights.go
+/**
+ * UsageHeatmapInsightsResponse is the response from the usage heatmap
+ * insights endpoint.
+ */
+export interface UsageHeatmapInsightsResponse {
+	readonly report: UsageHeatmapInsightsReport;
+}
+
 // From codersdk/deployment.go
 export interface UsagePeriod {
 	readonly issued_at: string;
@@ -9302,9 +10164,51 @@ export interface UsagePeriod {
 	readonly end: string;
 }
 
+// From codersdk/usagequotas.go
+/**
+ * UsageQuota is the consumption of a user in an organization during the
+ * current period, which is the calendar month in UTC.
+ */
+export interface UsageQuota {
+	readonly period_start: string;
+	readonly period_end: string;
+	readonly metrics: readonly UsageQuotaStatus[];
+}
+
+// From codersdk/usagequotas.go
+export type UsageQuotaAction = "block_start" | "report";
+
+export const UsageQuotaActions: UsageQuotaAction[] = ["block_start", "report"];
+
+// From codersdk/usagequotas.go
+export type UsageQuotaMetric = "active_hours" | "connection_hours";
+
+export const UsageQuotaMetrics: UsageQuotaMetric[] = [
+	"active_hours",
+	"connection_hours",
+];
+
+// From codersdk/usagequotas.go
+export interface UsageQuotaStatus {
+	readonly metric: UsageQuotaMetric;
+	readonly consumed_hours: number;
+	/**
+	 * AllowanceHours is the sum of the allowances of the user's groups. It is
+	 * omitted when none of their groups limit the metric.
+	 */
+	readonly allowance_hours?: number;
+	/**
+	 * Action is the strictest action across the user's groups. It is omitted
+	 * when the metric is not limited.
+	 */
+	readonly action?: UsageQuotaAction;
+	readonly exceeded: boolean;
+}
+
 // From codersdk/deployment.go
 export interface UsageStatsConfig {
 	readonly enable: boolean;
+	readonly insights_cache_ttl: number;
 }
 
 // From codersdk/users.go
@@ -9405,6 +10309,53 @@ export interface UserAppearanceSettings {
 	readonly terminal_font: TerminalFontName;
 }
 
+// From codersdk/insights.go
+/**
+ * UserBandwidth shows the bytes received and sent by the workspace agents of
+ * a user, in total and per template.
+ */
+export interface UserBandwidth {
+	readonly user_id: string;
+	readonly username: string;
+	readonly avatar_url: string;
+	readonly rx_bytes: number;
+	readonly tx_bytes: number;
+	readonly templates: readonly TemplateBandwidth[];
+}
+
+// From codersdk/insights.go
+/**
+ * UserBandwidthInsightsReport shows the bytes transferred by the workspace
+ * agents of each user, heaviest users first. It is computed from raw agent
+ * stats, so it only covers the period those stats are retained for.
+ */
+export interface UserBandwidthInsightsReport {
+	readonly start_time: string;
+	readonly end_time: string;
+	/**
+	 * Count is the total number of users with bandwidth in the time range,
+	 * across all pages.
+	 */
+	readonly count: number;
+	readonly users: readonly UserBandwidth[];
+}
+
+// From codersdk/insights.go
+export interface UserBandwidthInsightsRequest extends Pagination {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * UserBandwidthInsightsResponse is the response from the user bandwidth
+ * insights endpoint.
+ */
+export interface UserBandwidthInsightsResponse {
+	readonly report: UserBandwidthInsightsReport;
+}
+
 // From codersdk/chats.go
 /**
  * UserChatCompactionThreshold is a user's per-model chat compaction
@@ -9654,6 +10605,136 @@ export interface VariableValue {
 	readonly value: string;
 }
 
+// From codersdk/webhooks.go
+/**
+ * Webhook is an outbound webhook that receives workspace events, either for
+ * all workspaces of the deployment or for the workspaces of a template.
+ */
+export interface Webhook {
+	readonly id: string;
+	readonly created_at: string;
+	readonly created_by: string;
+	/**
+	 * TemplateID restricts the webhook to workspaces of the template. Events
+	 * of all workspaces are delivered if it is not set.
+	 */
+	readonly template_id?: string;
+	readonly url: string;
+	readonly events: readonly WebhookEvent[];
+	/**
+	 * Secret is used to sign the deliveries of the webhook. It is only
+	 * returned when the webhook is created.
+	 */
+	readonly secret?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDeliveriesRequest extends Pagination {}
+
+// From codersdk/webhooks.go
+/**
+ * WebhookDelivery is the delivery of an event to a webhook. Pending
+ * deliveries are retried with exponential backoff until they succeed or run
+ * out of attempts.
+ */
+export interface WebhookDelivery {
+	readonly id: string;
+	readonly webhook_id: string;
+	readonly created_at: string;
+	readonly updated_at: string;
+	readonly event: WebhookEvent;
+	readonly payload: WebhookPayload;
+	readonly status: WebhookDeliveryStatus;
+	readonly attempts: number;
+	readonly next_attempt_at?: string;
+	/**
+	 * ResponseStatusCode is the status code returned by the last attempt, if
+	 * the endpoint responded.
+	 */
+	readonly response_status_code?: number;
+	/**
+	 * Error is the reason the last attempt failed, if it did.
+	 */
+	readonly error?: string;
+}
+
+// From codersdk/webhooks.go
+export type WebhookDeliveryStatus = "failed" | "pending" | "succeeded";
+
+export const WebhookDeliveryStatuses: WebhookDeliveryStatus[] = [
+	"failed",
+	"pending",
+	"succeeded",
+];
+
+// From codersdk/webhooks.go
+export type WebhookEvent =
+	| "workspace_build_failed"
+	| "workspace_build_succeeded"
+	| "workspace_resource_threshold_exceeded";
+
+export const WebhookEvents: WebhookEvent[] = [
+	"workspace_build_failed",
+	"workspace_build_succeeded",
+	"workspace_resource_threshold_exceeded",
+];
+
+// From codersdk/webhooks.go
+/**
+ * WebhookPayload is the JSON body POSTed to webhooks. Deliveries are signed
+ * with the secret of the webhook: the X-Coder-Webhook-Signature header is
+ * "sha256=" followed by the hex encoded HMAC-SHA256 of the
+ * X-Coder-Webhook-Timestamp header, a dot and the body.
+ */
+export interface WebhookPayload {
+	readonly event: WebhookEvent;
+	readonly timestamp: string;
+	readonly workspace: WebhookWorkspace;
+	/**
+	 * Build is set for workspace build events.
+	 */
+	readonly build?: WebhookWorkspaceBuild;
+	/**
+	 * Thresholds is set for resource threshold events, and lists the
+	 * resources of the workspace that exceeded their threshold.
+	 */
+	readonly thresholds?: readonly WebhookResourceThreshold[];
+}
+
+// From codersdk/webhooks.go
+export interface WebhookResourceThreshold {
+	/**
+	 * Resource is either "memory" or "volume".
+	 */
+	readonly resource: string;
+	/**
+	 * Path is the path of the volume, for volume thresholds.
+	 */
+	readonly path?: string;
+	readonly threshold_percent: number;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspace {
+	readonly id: string;
+	readonly name: string;
+	readonly owner_id: string;
+	readonly owner_name: string;
+	readonly organization_id: string;
+	readonly template_id: string;
+	readonly template_name: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceBuild {
+	readonly id: string;
+	readonly build_number: number;
+	readonly transition: WorkspaceTransition;
+	readonly reason: BuildReason;
+	readonly status: ProvisionerJobStatus;
+	readonly error?: string;
+}
+
 // From codersdk/notifications.go
 export interface WebpushMessage {
 	readonly icon: string;
@@ -10113,6 +11194,35 @@ export interface WorkspaceAgentPortShares {
 	readonly shares: readonly WorkspaceAgentPortShare[];
 }
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentProcess is a process running inside a workspace, along with
+ * its resource usage.
+ */
+export interface WorkspaceAgentProcess {
+	readonly pid: number;
+	readonly name: string;
+	/**
+	 * Username is empty if the owner of the process can't be resolved.
+	 */
+	readonly username: string;
+	/**
+	 * CPUPercent is the CPU usage of the process, sampled over a short
+	 * interval. It can exceed 100 for processes using multiple cores.
+	 */
+	readonly cpu_percent: number;
+	readonly memory_bytes: number;
+	readonly memory_percent: number;
+}
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentProcessSortBy = "cpu" | "memory";
+
+export const WorkspaceAgentProcessSortBys: WorkspaceAgentProcessSortBy[] = [
+	"cpu",
+	"memory",
+];
+
 // From codersdk/workspaceagents.go
 /**
  * WorkspaceAgentRepoChanges describes the current state of a single
@@ -10164,6 +11274,90 @@ export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking";
 export const WorkspaceAgentStartupScriptBehaviors: WorkspaceAgentStartupScriptBehavior[] =
 	["blocking", "non-blocking"];
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentStatsBucket holds the value of each requested metric within a
+ * bucket. Byte and packet counts are summed over the bucket, while connection
+ * counts, session counts and latency are averaged.
+ */
+export interface WorkspaceAgentStatsBucket {
+	readonly start_time: string;
+	/**
+	 * Reports is the number of stats reports the agent sent within the
+	 * bucket. Buckets without reports have all values set to zero.
+	 */
+	readonly reports: number;
+	readonly values: Record<WorkspaceAgentStatsMetric, number>;
+}
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentStatsMetric =
+	| "connection_count"
+	| "connection_median_latency_ms"
+	| "rx_bytes"
+	| "rx_packets"
+	| "session_count_jetbrains"
+	| "session_count_reconnecting_pty"
+	| "session_count_ssh"
+	| "session_count_vscode"
+	| "tx_bytes"
+	| "tx_packets";
+
+export const WorkspaceAgentStatsMetrics: WorkspaceAgentStatsMetric[] = [
+	"connection_count",
+	"connection_median_latency_ms",
+	"rx_bytes",
+	"rx_packets",
+	"session_count_jetbrains",
+	"session_count_reconnecting_pty",
+	"session_count_ssh",
+	"session_count_vscode",
+	"tx_bytes",
+	"tx_packets",
+];
+
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentStatsTimeSeries is the stats reported by a workspace agent,
+ * downsampled into fixed size buckets.
+ */
+export interface WorkspaceAgentStatsTimeSeries {
+	readonly start_time: string;
+	readonly end_time: string;
+	readonly bucket_size_ms: number;
+	readonly metrics: readonly WorkspaceAgentStatsMetric[];
+	readonly buckets: readonly WorkspaceAgentStatsBucket[];
+}
+
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentStatsTimeSeriesMaxBuckets is the maximum number of buckets a
+ * stats time series can be downsampled into.
+ */
+export const WorkspaceAgentStatsTimeSeriesMaxBuckets = 1000;
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentStatsTimeSeriesRequest {
+	/**
+	 * StartTime defaults to 24 hours before EndTime.
+	 */
+	readonly start_time?: string;
+	/**
+	 * EndTime defaults to now.
+	 */
+	readonly end_time?: string;
+	/**
+	 * BucketSize must be at least a minute. It defaults to the smallest
+	 * multiple of a minute that fits the time range into
+	 * WorkspaceAgentStatsTimeSeriesMaxBuckets buckets.
+	 */
+	readonly bucket_size?: number;
+	/**
+	 * Metrics defaults to all metrics.
+	 */
+	readonly metrics?: readonly WorkspaceAgentStatsMetric[];
+}
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStatus =
 	| "connected"
@@ -10178,6 +11372,43 @@ export const WorkspaceAgentStatuses: WorkspaceAgentStatus[] = [
 	"timeout",
 ];
 
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentTopProcessesDefaultLimit is the number of processes
+ * returned when no limit is requested.
+ */
+export const WorkspaceAgentTopProcessesDefaultLimit = 10;
+
+// From codersdk/workspaceagents.go
+/**
+ * WorkspaceAgentTopProcessesMaxLimit is the maximum number of processes
+ * that can be requested at once.
+ */
+export const WorkspaceAgentTopProcessesMaxLimit = 100;
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentTopProcessesRequest {
+	/**
+	 * SortBy is the resource to rank processes by. Defaults to CPU.
+	 */
+	readonly sort_by?: WorkspaceAgentProcessSortBy;
+	/**
+	 * Limit is the number of processes to return. Defaults to
+	 * WorkspaceAgentTopProcessesDefaultLimit.
+	 */
+	readonly limit?: number;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentTopProcessesResponse {
+	readonly sort_by: WorkspaceAgentProcessSortBy;
+	/**
+	 * Processes are ordered by the resource they are sorted by, highest
+	 * first.
+	 */
+	readonly processes: readonly WorkspaceAgentProcess[];
+}
+
 // From codersdk/workspaceapps.go
 export interface WorkspaceApp {
 	readonly id: string;
@@ -10433,6 +11664,109 @@ export interface WorkspaceHealth {
 	readonly failing_agents: readonly string[]; // FailingAgents lists the IDs of the agents that are failing, if any.
 }
 
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthApps scores the share of apps with health checks that are
+ * healthy. Initializing apps count as not healthy yet.
+ */
+export interface WorkspaceHealthApps {
+	readonly score: number;
+	readonly healthy: number;
+	readonly initializing: number;
+	readonly unhealthy: number;
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthBuilds scores the share of builds of the last week that did
+ * not fail. A failed latest build always scores 0.
+ */
+export interface WorkspaceHealthBuilds {
+	readonly score: number;
+	readonly latest_build_failed: boolean;
+	readonly builds: number;
+	readonly failed_builds: number;
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthConnectivity scores the share of agents in the latest build
+ * that are connected. A workspace without agents scores 100, unless its latest
+ * build failed.
+ */
+export interface WorkspaceHealthConnectivity {
+	readonly score: number;
+	readonly agents_connected: number;
+	readonly agents_total: number;
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthInsight is the composite health score of a workspace, from 0
+ * to 100. The score is a weighted average of the component scores: agent
+ * connectivity weighs 40%, and latency, app health and builds weigh 20% each.
+ */
+export interface WorkspaceHealthInsight {
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly owner_id: string;
+	readonly owner_username: string;
+	readonly template_id: string;
+	readonly template_name: string;
+	readonly score: number;
+	readonly status: WorkspaceHealthStatus;
+	readonly connectivity: WorkspaceHealthConnectivity;
+	readonly latency: WorkspaceHealthLatency;
+	readonly apps: WorkspaceHealthApps;
+	readonly builds: WorkspaceHealthBuilds;
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthInsightsReport scores the health of running workspaces,
+ * least healthy first.
+ */
+export interface WorkspaceHealthInsightsReport {
+	readonly generated_at: string;
+	readonly template_ids: readonly string[];
+	readonly workspaces: readonly WorkspaceHealthInsight[];
+}
+
+// From codersdk/insights.go
+export interface WorkspaceHealthInsightsRequest {
+	readonly template_ids: readonly string[];
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthInsightsResponse is the response from the workspace health
+ * insights endpoint.
+ */
+export interface WorkspaceHealthInsightsResponse {
+	readonly report: WorkspaceHealthInsightsReport;
+}
+
+// From codersdk/insights.go
+/**
+ * WorkspaceHealthLatency scores the connection latency of the last hour
+ * against the baseline of the preceding week. Latencies are -1 if no latency
+ * was reported, and the score is 100 if there is nothing to compare.
+ */
+export interface WorkspaceHealthLatency {
+	readonly score: number;
+	readonly latency_ms: number;
+	readonly baseline_latency_ms: number;
+}
+
+// From codersdk/insights.go
+export type WorkspaceHealthStatus = "degraded" | "healthy" | "unhealthy";
+
+export const WorkspaceHealthStatuses: WorkspaceHealthStatus[] = [
+	"degraded",
+	"healthy",
+	"unhealthy",
+];
+
 // From codersdk/workspaces.go
 export interface WorkspaceOptions {
 	readonly include_deleted?: boolean;
@@ -10523,6 +11857,76 @@ export interface WorkspaceResourceMetadata {
 	readonly sensitive: boolean;
 }
 
+// From codersdk/workspacerightsizing.go
+/**
+ * WorkspaceRightsizing recommends resources for the agents of a workspace
+ * based on their CPU and memory usage, as sampled from the "coder stat cpu"
+ * and "coder stat mem" agent metadata. Agents that don't report these, or
+ * haven't reported them for long enough, have no recommendations.
+ */
+export interface WorkspaceRightsizing {
+	readonly start_time: string;
+	readonly end_time: string;
+	/**
+	 * RunningSeconds is how long the workspace was running during the period.
+	 */
+	readonly running_seconds: number;
+	/**
+	 * Cost is the estimated cost of running the workspace during the period,
+	 * in the same unit as the daily cost of template resources.
+	 */
+	readonly cost: number;
+	/**
+	 * PotentialSavings is the part of the cost that would have been saved by
+	 * applying the recommendations, assuming the cost is split evenly between
+	 * CPU and memory.
+	 */
+	readonly potential_savings: number;
+	readonly recommendations: readonly WorkspaceRightsizingRecommendation[];
+}
+
+// From codersdk/workspacerightsizing.go
+/**
+ * WorkspaceRightsizingRecommendation is the recommended size of a resource of
+ * a workspace agent. CPU is in cores and memory is in bytes.
+ */
+export interface WorkspaceRightsizingRecommendation {
+	readonly agent_name: string;
+	readonly resource: WorkspaceRightsizingResource;
+	/**
+	 * Samples is the number of usage samples the recommendation is based on.
+	 */
+	readonly samples: number;
+	/**
+	 * Current is the latest total of the resource reported by the agent.
+	 */
+	readonly current: number;
+	readonly used_p95: number;
+	readonly used_max: number;
+	/**
+	 * Recommended is the 95th percentile of the usage with headroom, rounded
+	 * up. It is higher than the current total if the agent is undersized.
+	 */
+	readonly recommended: number;
+}
+
+// From codersdk/workspacerightsizing.go
+export interface WorkspaceRightsizingRequest {
+	/**
+	 * Days is the number of days of usage to base the recommendations on. The
+	 * server picks a default if it's zero.
+	 */
+	readonly days: number;
+}
+
+// From codersdk/workspacerightsizing.go
+export type WorkspaceRightsizingResource = "cpu" | "memory";
+
+export const WorkspaceRightsizingResources: WorkspaceRightsizingResource[] = [
+	"cpu",
+	"memory",
+];
+
 // From codersdk/workspaces.go
 export type WorkspaceRole = "admin" | "" | "use";
 
@@ -10578,6 +11982,54 @@ export const WorkspaceStatuses: WorkspaceStatus[] = [
 	"stopping",
 ];
 
+// From codersdk/workspacetimeline.go
+/**
+ * WorkspaceTimelineEvent is an event in the history of a workspace.
+ */
+export interface WorkspaceTimelineEvent {
+	/**
+	 * ID is the ID of the record the event is derived from, i.e. the
+	 * workspace build, connection log, workspace agent or audit log.
+	 */
+	readonly id: string;
+	readonly type: WorkspaceTimelineEventType;
+	readonly time: string;
+	/**
+	 * EndTime is set for events that span a period of time once it's over,
+	 * e.g. for completed builds and closed connections.
+	 */
+	readonly end_time?: string;
+	readonly description: string;
+	/**
+	 * UserID is the user that caused the event, if known.
+	 */
+	readonly user_id?: string;
+	/**
+	 * BuildNumber is the number of the workspace build the event belongs to,
+	 * if any.
+	 */
+	readonly build_number?: number;
+}
+
+// From codersdk/workspacetimeline.go
+export type WorkspaceTimelineEventType =
+	| "agent_lifecycle"
+	| "app_usage"
+	| "audit"
+	| "build"
+	| "connection";
+
+export const WorkspaceTimelineEventTypes: WorkspaceTimelineEventType[] = [
+	"agent_lifecycle",
+	"app_usage",
+	"audit",
+	"build",
+	"connection",
+];
+
+// From codersdk/workspacetimeline.go
+export interface WorkspaceTimelineRequest extends Pagination {}
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceTransition = "delete" | "start" | "stop";
 
@@ -1,8 +1,10 @@
-import type { FC } from "react";
-import { useQuery } from "react-query";
+import { type FC, useEffect, useState } from "react";
+import { useQuery, useQueryClient } from "react-query";
 import { useLocation } from "react-router";
+import { watchDeploymentStats } from "#/api/api";
 import { health } from "#/api/queries/debug";
 import { deploymentStats } from "#/api/queries/deployment";
+import type { DeploymentStats } from "#/api/typesGenerated";
 import { useAuthenticated } from "#/hooks/useAuthenticated";
 import { DeploymentBannerView } from "./DeploymentBannerView";
 
@@ -17,6 +19,7 @@ const HIDE_DEPLOYMENT_BANNER_PATHS = [
 ];
 
 export const DeploymentBanner: FC = () => {
+	const queryClient = useQueryClient();
 	const { permissions } = useAuthenticated();
 	const deploymentStatsQuery = useQuery({
 		...deploymentStats(),
@@ -31,6 +34,38 @@ export const DeploymentBanner: FC = () => {
 		regex.test(location.pathname),
 	);
 
+	// Stats are pushed by the server as soon as they are aggregated. The view
+	// only polls for them if the stream is unavailable.
+	const [streaming, setStreaming] = useState(false);
+	const canViewStats = permissions.viewDeploymentStats;
+	useEffect(() => {
+		if (!canViewStats) {
+			return;
+		}
+
+		const socket = watchDeploymentStats();
+		socket.addEventListener("message", (event) => {
+			if (event.parseError) {
+				return;
+			}
+			if (event.parsedMessage.type === "data") {
+				setStreaming(true);
+				queryClient.setQueryData(
+					deploymentStats().queryKey,
+					event.parsedMessage.data as DeploymentStats,
+				);
+			}
+		});
+		socket.addEventListener("error", () => {
+			setStreaming(false);
+		});
+		socket.addEventListener("close", () => {
+			setStreaming(false);
+		});
+
+		return () => socket.close();
+	}, [canViewStats, queryClient]);
+
 	if (
 		isHidden ||
 		!permissions.viewDeploymentConfig ||
@@ -44,6 +79,7 @@ export const DeploymentBanner: FC = () => {
 			health={healthQuery.data}
 			stats={deploymentStatsQuery.data}
 			fetchStats={() => deploymentStatsQuery.refetch()}
+			streaming={streaming}
 		/>
 	);
 };
@@ -42,12 +42,18 @@ interface DeploymentBannerViewProps {
 	health?: HealthcheckReport;
 	stats?: DeploymentStats;
 	fetchStats?: () => void;
+	/**
+	 * Whether stats are pushed by the server as they're aggregated, in which
+	 * case they don't have to be fetched again when the countdown ends.
+	 */
+	streaming?: boolean;
 }
 
 export const DeploymentBannerView: FC<DeploymentBannerViewProps> = ({
 	health,
 	stats,
 	fetchStats,
+	streaming,
 }) => {
 	const aggregatedMinutes = useMemo(() => {
 		if (!stats) {
@@ -76,14 +82,16 @@ export const DeploymentBannerView: FC<DeploymentBannerViewProps> = ({
 			if (timeUntilRefresh > 0) {
 				return window.setTimeout(loop, 1000);
 			}
-			fetchStats();
+			if (!streaming) {
+				fetchStats();
+			}
 		};
 		const timeout = setTimeout(loop, 1000);
 		return () => {
 			canceled = true;
 			clearTimeout(timeout);
 		};
-	}, [fetchStats, stats]);
+	}, [fetchStats, stats, streaming]);
 
 	// biome-ignore lint/correctness/useExhaustiveDependencies(timeUntilRefresh): periodic refresh
 	const lastAggregated = useMemo(() => {
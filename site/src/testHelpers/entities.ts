@@ -931,6 +931,7 @@ export const MockTemplate: TypesGen.Template = {
 	use_classic_parameter_flow: false,
 	cors_behavior: "simple",
 	disable_module_cache: false,
+	dormancy_activity_signal: "connection",
 };
 
 const _MockTemplateVersionFiles: TemplateVersionFiles = {